@@ -18,8 +18,11 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	volumesnapshot "github.com/kubernetes-csi/external-snapshotter/client/v7/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +30,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -79,6 +83,25 @@ const (
 	BackupMethodPlugin BackupMethod = "plugin"
 )
 
+// BackupDeletionPolicy defines what happens to the content of the object
+// store when a Backup is deleted
+type BackupDeletionPolicy string
+
+const (
+	// BackupDeletionPolicyRetain keeps the base backup in the object
+	// store when the Backup is deleted
+	BackupDeletionPolicyRetain BackupDeletionPolicy = "retain"
+
+	// BackupDeletionPolicyDelete removes the base backup, and the WAL
+	// files not needed anymore, from the object store when the Backup
+	// is deleted
+	BackupDeletionPolicyDelete BackupDeletionPolicy = "delete"
+)
+
+// BackupFinalizerName is the name of the finalizer used to remove the base
+// backup from the object store before the Backup is deleted
+const BackupFinalizerName = utils.MetadataNamespace + "/deleteBackup"
+
 // BackupSpec defines the desired state of Backup
 type BackupSpec struct {
 	// The cluster to backup
@@ -115,6 +138,156 @@ type BackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// The name of the object store, among the ones defined in
+	// `cluster.spec.backup.additionalBarmanObjectStores`, where the
+	// backup is taken. If empty, it defaults to the one defined in
+	// `cluster.spec.backup.barmanObjectStore`. It can be specified
+	// only when the backup method is `barmanObjectStore`
+	// +optional
+	ObjectStoreName string `json:"objectStoreName,omitempty"`
+
+	// When set, the backup is verified once completed, by restoring it
+	// in a scratch volume and running the configured sanity queries.
+	// It can be specified only when the backup method is `barmanObjectStore`
+	// +optional
+	Verify *BackupVerificationConfiguration `json:"verify,omitempty"`
+
+	// What happens to the base backup in the object store when this Backup
+	// is deleted: `retain` (default) keeps it, while `delete` removes it
+	// together with the WAL files not needed anymore, provided that
+	// `cluster.spec.backup.allowBackupDeletion` is set. It can be specified
+	// only when the backup method is `barmanObjectStore`
+	// +kubebuilder:validation:Enum=retain;delete
+	// +optional
+	DeletionPolicy BackupDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// BackupVerificationConfiguration configures the verification of a backup
+type BackupVerificationConfiguration struct {
+	// The database where the sanity queries are run. Defaults to `postgres`
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The sanity queries to be run against the restored instance once
+	// it has reached a consistent state. Each of them must complete
+	// without errors for the verification to succeed
+	// +optional
+	Queries []string `json:"queries,omitempty"`
+
+	// The resources of the job running the verification. If not set,
+	// the resources of the cluster are used
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// GetDatabase returns the database where the sanity queries are run
+func (verification *BackupVerificationConfiguration) GetDatabase() string {
+	if verification.Database == "" {
+		return "postgres"
+	}
+	return verification.Database
+}
+
+// BackupHooks configures the actions run before and after a backup
+type BackupHooks struct {
+	// The actions run, in order, before the backup is started. If any
+	// of them fails, the backup is not taken and is marked as failed
+	// +optional
+	Pre []BackupHook `json:"pre,omitempty"`
+
+	// The actions run, in order, once the backup is completed or has
+	// failed, provided that the pre-backup actions have been started.
+	// Their failures are reported but don't change the outcome of the
+	// backup
+	// +optional
+	Post []BackupHook `json:"post,omitempty"`
+}
+
+// GetPre returns the actions run before the backup
+func (hooks *BackupHooks) GetPre() []BackupHook {
+	if hooks == nil {
+		return nil
+	}
+	return hooks.Pre
+}
+
+// GetPost returns the actions run after the backup
+func (hooks *BackupHooks) GetPost() []BackupHook {
+	if hooks == nil {
+		return nil
+	}
+	return hooks.Post
+}
+
+// BackupHook is an action run before or after a backup. Exactly one
+// between `http` and `sql` must be specified
+type BackupHook struct {
+	// The name of the hook, used in logs and events
+	Name string `json:"name"`
+
+	// The HTTP request to be sent
+	// +optional
+	HTTP *BackupHookHTTPAction `json:"http,omitempty"`
+
+	// The SQL statement to be executed as superuser
+	// +optional
+	SQL string `json:"sql,omitempty"`
+
+	// The database where the SQL statement is executed. Defaults to `postgres`
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The number of seconds after which the hook is considered failed.
+	// Defaults to 30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// BackupHookHTTPAction is an HTTP request sent by a backup hook
+type BackupHookHTTPAction struct {
+	// The URL the request is sent to
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// The HTTP method of the request. Defaults to `POST`
+	// +kubebuilder:validation:Enum=GET;POST;PUT
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// The secret whose entries are added to the request as headers,
+	// using the keys as the names of the headers
+	// +optional
+	HeadersSecret *LocalObjectReference `json:"headersSecret,omitempty"`
+}
+
+// DefaultBackupHookTimeout is the default number of seconds after which
+// a backup hook is considered failed
+const DefaultBackupHookTimeout = 30
+
+// GetDatabase returns the database where the SQL statement is executed
+func (hook *BackupHook) GetDatabase() string {
+	if hook.Database == "" {
+		return "postgres"
+	}
+	return hook.Database
+}
+
+// GetTimeout returns the time after which the hook is considered failed
+func (hook *BackupHook) GetTimeout() time.Duration {
+	if hook.Timeout <= 0 {
+		return DefaultBackupHookTimeout * time.Second
+	}
+	return time.Duration(hook.Timeout) * time.Second
+}
+
+// GetMethod returns the HTTP method of the request
+func (action *BackupHookHTTPAction) GetMethod() string {
+	if action.Method == "" {
+		return http.MethodPost
+	}
+	return action.Method
 }
 
 // BackupPluginConfiguration contains the backup configuration used by
@@ -180,6 +353,16 @@ type BackupStatus struct {
 	// +optional
 	Encryption string `json:"encryption,omitempty"`
 
+	// The compression algorithm of the WAL files archived by the cluster
+	// when the backup was taken, needed to restore them
+	// +optional
+	WALCompression CompressionType `json:"walCompression,omitempty"`
+
+	// The client-side encryption of the WAL files archived by the cluster
+	// when the backup was taken, needed to restore them
+	// +optional
+	WALClientSideEncryption *WALClientSideEncryption `json:"walClientSideEncryption,omitempty"`
+
 	// The ID of the Barman backup
 	// +optional
 	BackupID string `json:"backupId,omitempty"`
@@ -200,6 +383,14 @@ type BackupStatus struct {
 	// +optional
 	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
 
+	// The progress of the backup, reported while it is running
+	// +optional
+	Progress *BackupProgress `json:"progress,omitempty"`
+
+	// The outcome of the verification of the backup, if requested
+	// +optional
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
+
 	// The starting WAL
 	// +optional
 	BeginWal string `json:"beginWal,omitempty"`
@@ -252,6 +443,79 @@ type BackupStatus struct {
 	Online *bool `json:"online,omitempty"`
 }
 
+// BackupVerificationPhase is the phase of the verification of a backup
+type BackupVerificationPhase string
+
+const (
+	// BackupVerificationPhaseRunning means that the verification job is running
+	BackupVerificationPhaseRunning BackupVerificationPhase = "running"
+
+	// BackupVerificationPhaseSucceeded means that the backup has been
+	// restored and the sanity queries have been run successfully
+	BackupVerificationPhaseSucceeded BackupVerificationPhase = "succeeded"
+
+	// BackupVerificationPhaseFailed means that the backup couldn't be
+	// restored, or that a sanity query failed
+	BackupVerificationPhaseFailed BackupVerificationPhase = "failed"
+)
+
+// BackupVerificationStatus reports the outcome of the verification of a backup
+type BackupVerificationStatus struct {
+	// The phase of the verification
+	Phase BackupVerificationPhase `json:"phase"`
+
+	// The name of the job running the verification
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// When the verification was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the verification was completed
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The detected error
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// IsDone checks whether the verification has been completed,
+// successfully or not
+func (status *BackupVerificationStatus) IsDone() bool {
+	return status != nil &&
+		(status.Phase == BackupVerificationPhaseSucceeded || status.Phase == BackupVerificationPhaseFailed)
+}
+
+// BackupProgress reports the progress of a running backup
+type BackupProgress struct {
+	// The estimated amount of data to be backed up, in bytes
+	// +optional
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+
+	// The amount of data that has been copied so far, in bytes
+	// +optional
+	CopiedBytes int64 `json:"copiedBytes,omitempty"`
+
+	// The estimated percentage of completion, e.g. "42%"
+	// +optional
+	Completion string `json:"completion,omitempty"`
+
+	// The number of WAL files archived by the instance since the
+	// backup started
+	// +optional
+	ArchivedWALs int64 `json:"archivedWALs,omitempty"`
+
+	// The estimated time when the copy of the data will be completed
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+
+	// When the progress was last updated
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
+
 // InstanceID contains the information to identify an instance
 type InstanceID struct {
 	// The pod name
@@ -270,6 +534,7 @@ type InstanceID struct {
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
 // +kubebuilder:printcolumn:name="Method",type="string",JSONPath=".spec.method"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=".status.progress.completion"
 // +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.error"
 
 // Backup is the Schema for the backups API
@@ -372,6 +637,73 @@ func (backup *Backup) IsCompletedVolumeSnapshot() bool {
 		backup.Status.Phase == BackupPhaseCompleted
 }
 
+// ValidateRecoveryTarget checks if the passed recovery target can be reached
+// when recovering from this backup, which is only possible when the target
+// comes after the moment the backup became consistent
+func (backup *Backup) ValidateRecoveryTarget(recoveryTarget *RecoveryTarget) error {
+	if backup == nil || recoveryTarget == nil {
+		return nil
+	}
+
+	// The stop time of volume snapshot backups is recorded when the
+	// snapshots are ready, which may be way after the backup has become
+	// consistent
+	if recoveryTarget.TargetTime != "" && backup.Status.StoppedAt != nil &&
+		!backup.IsCompletedVolumeSnapshot() {
+		targetTime, err := utils.ParseTargetTime(nil, recoveryTarget.TargetTime)
+		if err != nil {
+			return fmt.Errorf("while parsing recovery target targetTime: %w", err)
+		}
+		if targetTime.Before(backup.Status.StoppedAt.Time) {
+			return fmt.Errorf("recovery target time %s is before the end of backup %s (%s)",
+				recoveryTarget.TargetTime, backup.Status.BackupID,
+				backup.Status.StoppedAt.Format(time.RFC3339))
+		}
+	}
+
+	if recoveryTarget.TargetLSN != "" && backup.Status.EndLSN != "" {
+		targetLSN := postgres.LSN(recoveryTarget.TargetLSN)
+		if _, err := targetLSN.Parse(); err != nil {
+			return fmt.Errorf("while parsing recovery target targetLSN: %w", err)
+		}
+		if targetLSN.Less(postgres.LSN(backup.Status.EndLSN)) {
+			return fmt.Errorf("recovery target LSN %s is before the end of backup %s (%s)",
+				recoveryTarget.TargetLSN, backup.Status.BackupID, backup.Status.EndLSN)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRecoveryTargetArchived checks if the WAL files needed to reach the
+// targetTime of the passed recovery target have been archived by the
+// cluster which took this backup. The last recoverability point of the
+// cluster is the time of the last WAL file it archived
+func (backup *Backup) ValidateRecoveryTargetArchived(recoveryTarget *RecoveryTarget, sourceCluster *Cluster) error {
+	if backup == nil || recoveryTarget == nil || recoveryTarget.TargetTime == "" ||
+		sourceCluster == nil || sourceCluster.Status.LastRecoverabilityPoint == "" {
+		return nil
+	}
+
+	targetTime, err := utils.ParseTargetTime(nil, recoveryTarget.TargetTime)
+	if err != nil {
+		return fmt.Errorf("while parsing recovery target targetTime: %w", err)
+	}
+
+	lastRecoverabilityPoint, err := time.Parse(time.RFC3339, sourceCluster.Status.LastRecoverabilityPoint)
+	if err != nil {
+		return fmt.Errorf("while parsing the last recoverability point of cluster %s: %w",
+			sourceCluster.Name, err)
+	}
+
+	if targetTime.After(lastRecoverabilityPoint) {
+		return fmt.Errorf("recovery target time %s is after the last WAL file archived by cluster %s (%s)",
+			recoveryTarget.TargetTime, sourceCluster.Name, sourceCluster.Status.LastRecoverabilityPoint)
+	}
+
+	return nil
+}
+
 // IsInProgress check if a certain backup is in progress or not
 func (backupStatus *BackupStatus) IsInProgress() bool {
 	return backupStatus.Phase == BackupPhasePending ||
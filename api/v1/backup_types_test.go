@@ -124,6 +124,71 @@ var _ = Describe("BackupStatus structure", func() {
 	})
 })
 
+var _ = Describe("recovery target validation", func() {
+	backup := &Backup{
+		Spec: BackupSpec{
+			Method: BackupMethodBarmanObjectStore,
+		},
+		Status: BackupStatus{
+			Phase:     BackupPhaseCompleted,
+			BackupID:  "20240101T100000",
+			StoppedAt: &metav1.Time{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+			EndLSN:    "0/3000100",
+		},
+	}
+
+	It("accepts an empty recovery target", func() {
+		Expect(backup.ValidateRecoveryTarget(nil)).To(Succeed())
+	})
+
+	It("accepts a target time after the end of the backup", func() {
+		Expect(backup.ValidateRecoveryTarget(&RecoveryTarget{TargetTime: "2024-01-01 10:30:00Z"})).To(Succeed())
+	})
+
+	It("refuses a target time before the end of the backup", func() {
+		err := backup.ValidateRecoveryTarget(&RecoveryTarget{TargetTime: "2024-01-01 09:30:00Z"})
+		Expect(err).To(MatchError(ContainSubstring("is before the end of backup 20240101T100000")))
+	})
+
+	It("accepts a target LSN after the end of the backup", func() {
+		Expect(backup.ValidateRecoveryTarget(&RecoveryTarget{TargetLSN: "0/4000000"})).To(Succeed())
+	})
+
+	It("refuses a target LSN before the end of the backup", func() {
+		err := backup.ValidateRecoveryTarget(&RecoveryTarget{TargetLSN: "0/2000000"})
+		Expect(err).To(MatchError(ContainSubstring("is before the end of backup 20240101T100000")))
+	})
+
+	It("accepts a target time before the last WAL file archived by the source cluster", func() {
+		sourceCluster := &Cluster{Status: ClusterStatus{LastRecoverabilityPoint: "2024-01-01T11:00:00Z"}}
+		Expect(backup.ValidateRecoveryTargetArchived(
+			&RecoveryTarget{TargetTime: "2024-01-01 10:30:00Z"}, sourceCluster)).To(Succeed())
+	})
+
+	It("refuses a target time after the last WAL file archived by the source cluster", func() {
+		sourceCluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "source"},
+			Status:     ClusterStatus{LastRecoverabilityPoint: "2024-01-01T10:15:00Z"},
+		}
+		err := backup.ValidateRecoveryTargetArchived(&RecoveryTarget{TargetTime: "2024-01-01 10:30:00Z"}, sourceCluster)
+		Expect(err).To(MatchError(ContainSubstring("is after the last WAL file archived by cluster source")))
+	})
+
+	It("skips the archive check when the source cluster is unknown", func() {
+		Expect(backup.ValidateRecoveryTargetArchived(
+			&RecoveryTarget{TargetTime: "2024-01-01 10:30:00Z"}, nil)).To(Succeed())
+		Expect(backup.ValidateRecoveryTargetArchived(
+			&RecoveryTarget{TargetTime: "2024-01-01 10:30:00Z"}, &Cluster{})).To(Succeed())
+	})
+
+	It("ignores the stop time of volume snapshot backups", func() {
+		snapshotBackup := backup.DeepCopy()
+		snapshotBackup.Spec.Method = BackupMethodVolumeSnapshot
+		Expect(snapshotBackup.ValidateRecoveryTarget(&RecoveryTarget{TargetTime: "2024-01-01 09:30:00Z"})).
+			To(Succeed())
+	})
+})
+
 var _ = Describe("BackupList structure", func() {
 	It("can be sorted by name", func() {
 		backupList := BackupList{
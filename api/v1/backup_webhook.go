@@ -108,6 +108,30 @@ func (r *Backup) validate() field.ErrorList {
 		))
 	}
 
+	if r.Spec.ObjectStoreName != "" && r.Spec.Method != BackupMethodBarmanObjectStore {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "objectStoreName"),
+			r.Spec.ObjectStoreName,
+			"ObjectStoreName parameter can be specified only if the backup method is barmanObjectStore",
+		))
+	}
+
+	if r.Spec.Verify != nil && r.Spec.Method != BackupMethodBarmanObjectStore {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "verify"),
+			r.Spec.Method,
+			"Verify parameter can be specified only if the backup method is barmanObjectStore",
+		))
+	}
+
+	if r.Spec.DeletionPolicy == BackupDeletionPolicyDelete && r.Spec.Method != BackupMethodBarmanObjectStore {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "deletionPolicy"),
+			r.Spec.DeletionPolicy,
+			"DeletionPolicy delete can be specified only if the backup method is barmanObjectStore",
+		))
+	}
+
 	if r.Spec.Method == BackupMethodPlugin && r.Spec.PluginConfiguration.IsEmpty() {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "pluginConfiguration"),
@@ -72,4 +72,67 @@ var _ = Describe("Backup webhook validate", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("doesn't complain if objectStoreName is set on a barman backup", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:          BackupMethodBarmanObjectStore,
+				ObjectStoreName: "dr",
+			},
+		}
+		Expect(backup.validate()).To(BeEmpty())
+	})
+
+	It("complains if objectStoreName is set on a plugin backup", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method: BackupMethodPlugin,
+				PluginConfiguration: &BackupPluginConfiguration{
+					Name: "test",
+				},
+				ObjectStoreName: "dr",
+			},
+		}
+		result := backup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.objectStoreName"))
+	})
+
+	It("complains if the verification is requested on a plugin backup", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method: BackupMethodPlugin,
+				PluginConfiguration: &BackupPluginConfiguration{
+					Name: "test",
+				},
+				Verify: &BackupVerificationConfiguration{},
+			},
+		}
+		result := backup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.verify"))
+	})
+
+	It("complains if the deletion policy is delete on a volume snapshot backup", func() {
+		utils.SetVolumeSnapshot(true)
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:         BackupMethodVolumeSnapshot,
+				DeletionPolicy: BackupDeletionPolicyDelete,
+			},
+		}
+		result := backup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.deletionPolicy"))
+	})
+
+	It("doesn't complain if the deletion policy is delete on a barman backup", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:         BackupMethodBarmanObjectStore,
+				DeletionPolicy: BackupDeletionPolicyDelete,
+			},
+		}
+		Expect(backup.validate()).To(BeEmpty())
+	})
 })
@@ -0,0 +1,228 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BenchmarkPhase is the phase of a benchmark
+type BenchmarkPhase string
+
+const (
+	// BenchmarkPhaseRunning means that the benchmark job is running
+	BenchmarkPhaseRunning BenchmarkPhase = "running"
+
+	// BenchmarkPhaseCompleted means that the benchmark job succeeded
+	// and its results have been collected
+	BenchmarkPhaseCompleted BenchmarkPhase = "completed"
+
+	// BenchmarkPhaseFailed means that the benchmark could not be started,
+	// or that the benchmark job failed
+	BenchmarkPhaseFailed BenchmarkPhase = "failed"
+)
+
+// BenchmarkSpec defines the desired state of Benchmark
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="the spec of a benchmark is immutable"
+// +kubebuilder:validation:XValidation:rule="has(self.pgbench) != has(self.fio)",message="exactly one of pgbench and fio must be set"
+type BenchmarkSpec struct {
+	// Runs pgbench against a cluster
+	// +optional
+	Pgbench *PgbenchConfiguration `json:"pgbench,omitempty"`
+
+	// Runs fio against a volume provisioned for the benchmark
+	// +optional
+	Fio *FioConfiguration `json:"fio,omitempty"`
+
+	// The node selector of the benchmark pod
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// The resources of the benchmark container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// PgbenchConfiguration contains the configuration of a pgbench run
+type PgbenchConfiguration struct {
+	// The cluster to run pgbench against, through its read-write service
+	// and the credentials of the application user
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The database used by pgbench
+	// +kubebuilder:default:=app
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// When set, the database is initialized with the pgbench tables,
+	// using this scale factor, before running the benchmark
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	InitializationScale int32 `json:"initializationScale,omitempty"`
+
+	// The arguments passed to pgbench, like `--time 60 --client 8`
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
+
+// FioConfiguration contains the configuration of a fio run
+type FioConfiguration struct {
+	// The storage class of the volume used by fio. When omitted, the
+	// default storage class is used
+	// +optional
+	StorageClass *string `json:"storageClass,omitempty"`
+
+	// The size of the volume used by fio
+	// +kubebuilder:default:="2Gi"
+	// +optional
+	Size resource.Quantity `json:"size,omitempty"`
+
+	// The image containing fio
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The arguments passed to fio. By default, a random read and write
+	// test on 8kB blocks, lasting one minute, is run
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
+
+// BenchmarkStatus defines the observed state of Benchmark
+type BenchmarkStatus struct {
+	// The current phase of the benchmark
+	// +optional
+	Phase BenchmarkPhase `json:"phase,omitempty"`
+
+	// A human-readable description of the reason of the failure
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The name of the job running the benchmark
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// When the benchmark job was created
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the benchmark completed, or failed
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The results of pgbench
+	// +optional
+	Pgbench *PgbenchResults `json:"pgbench,omitempty"`
+
+	// The results of fio
+	// +optional
+	Fio *FioResults `json:"fio,omitempty"`
+
+	// The summary printed by the benchmark tool, truncated to its last
+	// 4kB
+	// +optional
+	Output string `json:"output,omitempty"`
+}
+
+// PgbenchResults contains the results of a pgbench run
+type PgbenchResults struct {
+	// The number of transactions processed
+	// +optional
+	Transactions int64 `json:"transactions,omitempty"`
+
+	// The number of failed transactions
+	// +optional
+	FailedTransactions int64 `json:"failedTransactions,omitempty"`
+
+	// The average latency of the transactions, in milliseconds
+	// +optional
+	LatencyAverage string `json:"latencyAverage,omitempty"`
+
+	// The number of transactions per second, without the
+	// initial connection time
+	// +optional
+	TPS string `json:"tps,omitempty"`
+}
+
+// FioResults contains the results of a fio run, summed across its jobs
+type FioResults struct {
+	// The number of read operations per second
+	// +optional
+	ReadIOPS int64 `json:"readIOPS,omitempty"`
+
+	// The read bandwidth, in KiB per second
+	// +optional
+	ReadBandwidth int64 `json:"readBandwidth,omitempty"`
+
+	// The number of write operations per second
+	// +optional
+	WriteIOPS int64 `json:"writeIOPS,omitempty"`
+
+	// The write bandwidth, in KiB per second
+	// +optional
+	WriteBandwidth int64 `json:"writeBandwidth,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.pgbench.cluster.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="TPS",type="string",JSONPath=".status.pgbench.tps"
+// +kubebuilder:printcolumn:name="Read IOPS",type="integer",JSONPath=".status.fio.readIOPS"
+// +kubebuilder:printcolumn:name="Write IOPS",type="integer",JSONPath=".status.fio.writeIOPS"
+
+// Benchmark runs pgbench against a cluster, or fio against a storage
+// class, in a job managed by the operator and reports the results
+type Benchmark struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Benchmark.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec BenchmarkSpec `json:"spec"`
+	// Most recently observed status of the Benchmark. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status BenchmarkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BenchmarkList contains a list of Benchmark
+type BenchmarkList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of benchmarks
+	Items []Benchmark `json:"items"`
+}
+
+// IsDone checks whether the benchmark reached a final phase
+func (benchmark *Benchmark) IsDone() bool {
+	return benchmark.Status.Phase == BenchmarkPhaseCompleted ||
+		benchmark.Status.Phase == BenchmarkPhaseFailed
+}
+
+func init() {
+	SchemeBuilder.Register(&Benchmark{}, &BenchmarkList{})
+}
@@ -0,0 +1,338 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// ChangeDataCaptureSlotReclaimPolicy defines a policy for end-of-life
+// maintenance of the replication slot of a ChangeDataCapture
+// +enum
+type ChangeDataCaptureSlotReclaimPolicy string
+
+const (
+	// ChangeDataCaptureSlotReclaimDelete means the replication slot will be dropped
+	// when the ChangeDataCapture object is removed
+	ChangeDataCaptureSlotReclaimDelete ChangeDataCaptureSlotReclaimPolicy = "delete"
+
+	// ChangeDataCaptureSlotReclaimRetain means the replication slot will be left
+	// in PostgreSQL when the ChangeDataCapture object is removed
+	ChangeDataCaptureSlotReclaimRetain ChangeDataCaptureSlotReclaimPolicy = "retain"
+)
+
+// ChangeDataCaptureSnapshotMode defines which changes are published
+// when the bridge starts without a stored position
+// +enum
+type ChangeDataCaptureSnapshotMode string
+
+const (
+	// ChangeDataCaptureSnapshotInitial means the existing content of the published
+	// tables is published before streaming the changes
+	ChangeDataCaptureSnapshotInitial ChangeDataCaptureSnapshotMode = "initial"
+
+	// ChangeDataCaptureSnapshotNoData means only the changes are published
+	ChangeDataCaptureSnapshotNoData ChangeDataCaptureSnapshotMode = "no_data"
+)
+
+// ChangeDataCaptureFinalizerName is the name of the finalizer used by the instance
+// manager to drop the replication slot when the ChangeDataCapture object is removed
+const ChangeDataCaptureFinalizerName = utils.MetadataNamespace + "/deleteChangeDataCaptureSlot"
+
+// ChangeDataCaptureConditionChangesLost is the type of the condition reporting
+// that some changes may not have been published, because the replication slot
+// went missing and has been created again
+const ChangeDataCaptureConditionChangesLost = "ChangesLost"
+
+// ChangeDataCaptureSlotRecreatedReason is the reason of the ChangesLost
+// condition when the replication slot has been created again
+const ChangeDataCaptureSlotRecreatedReason = "SlotRecreated"
+
+// DefaultChangeDataCaptureOffsetStorageSize is the size of the volume storing
+// the offsets of the bridge, when the ChangeDataCapture doesn't specify one
+const DefaultChangeDataCaptureOffsetStorageSize = "1Gi"
+
+// DefaultChangeDataCaptureImage is the image of the bridge publishing
+// the changes, when the ChangeDataCapture doesn't specify one
+const DefaultChangeDataCaptureImage = "quay.io/debezium/server:2.7"
+
+// ChangeDataCaptureSpec defines the desired state of ChangeDataCapture
+type ChangeDataCaptureSpec struct {
+	// The corresponding cluster
+	ClusterRef LocalObjectReference `json:"cluster"`
+
+	// The name of the database whose changes are captured
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="dbname is immutable"
+	DBName string `json:"dbname"`
+
+	// The name of the logical replication slot, using the `pgoutput`
+	// plugin, that is created in the primary and consumed by the bridge
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="slotName is immutable"
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]{1,63}$`
+	SlotName string `json:"slotName"`
+
+	// The name of the PostgreSQL publication defining the published tables,
+	// for example managed through a Publication object
+	PublicationName string `json:"publicationName"`
+
+	// The secret, of type `kubernetes.io/basic-auth`, containing the
+	// credentials of the role used by the bridge, which needs the
+	// `REPLICATION` privilege
+	Credentials LocalObjectReference `json:"credentials"`
+
+	// Where the changes are published
+	Sink ChangeDataCaptureSink `json:"sink"`
+
+	// Which changes are published when the bridge starts without a
+	// stored position, like the first time it runs. `initial` publishes
+	// the existing content of the tables too, `no_data` only the changes
+	// +kubebuilder:validation:Enum=initial;no_data
+	// +kubebuilder:default:=no_data
+	// +optional
+	SnapshotMode ChangeDataCaptureSnapshotMode `json:"snapshotMode,omitempty"`
+
+	// The policy for end-of-life maintenance of the replication slot
+	// +kubebuilder:validation:Enum=delete;retain
+	// +kubebuilder:default:=delete
+	// +optional
+	SlotReclaimPolicy ChangeDataCaptureSlotReclaimPolicy `json:"slotReclaimPolicy,omitempty"`
+
+	// The image of the bridge, a Debezium Server distribution.
+	// Defaults to the one set in `DefaultChangeDataCaptureImage`
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The resources of the bridge container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The node selector of the bridge pod
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+// ChangeDataCaptureSink is where the changes are published
+// +kubebuilder:validation:XValidation:rule="has(self.kafka) != has(self.nats)",message="exactly one of kafka and nats must be set"
+type ChangeDataCaptureSink struct {
+	// The prefix of the name of the topics, or of the subjects, where the
+	// changes are published. The name of each topic is made of the prefix,
+	// the name of the schema and the name of the table, separated by dots.
+	// Defaults to the name of the cluster
+	// +optional
+	TopicPrefix string `json:"topicPrefix,omitempty"`
+
+	// Publishes the changes to Kafka
+	// +optional
+	Kafka *ChangeDataCaptureKafkaSink `json:"kafka,omitempty"`
+
+	// Publishes the changes to NATS JetStream
+	// +optional
+	NATS *ChangeDataCaptureNATSSink `json:"nats,omitempty"`
+}
+
+// ChangeDataCaptureKafkaSink contains the configuration of a Kafka sink
+type ChangeDataCaptureKafkaSink struct {
+	// The list of the Kafka brokers, in the `host:port` format
+	// +kubebuilder:validation:MinItems=1
+	BootstrapServers []string `json:"bootstrapServers"`
+
+	// Additional Kafka producer properties, like `acks` or `compression.type`
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// ChangeDataCaptureNATSSink contains the configuration of a NATS JetStream sink
+type ChangeDataCaptureNATSSink struct {
+	// The URL of the NATS server, like `nats://nats:4222`
+	URL string `json:"url"`
+
+	// Whether the bridge creates the stream receiving the changes,
+	// when it doesn't exist
+	// +optional
+	CreateStream bool `json:"createStream,omitempty"`
+
+	// The volume where the bridge stores the position of the last
+	// published change, which survives the restarts of the bridge
+	// +optional
+	OffsetStorage *ChangeDataCaptureOffsetStorage `json:"offsetStorage,omitempty"`
+}
+
+// ChangeDataCaptureOffsetStorage contains the configuration of the volume
+// where the bridge stores its offsets
+type ChangeDataCaptureOffsetStorage struct {
+	// The storage class of the volume. When omitted, the default
+	// storage class is used
+	// +optional
+	StorageClass *string `json:"storageClass,omitempty"`
+
+	// The size of the volume
+	// +kubebuilder:default:="1Gi"
+	// +optional
+	Size resource.Quantity `json:"size,omitempty"`
+}
+
+// ChangeDataCaptureStatus defines the observed state of ChangeDataCapture
+type ChangeDataCaptureStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Applied is true if the replication slot was reconciled correctly
+	// +optional
+	Applied *bool `json:"applied,omitempty"`
+
+	// Message is the reconciliation output message
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The instance where the replication slot lives
+	// +optional
+	SlotInstance string `json:"slotInstance,omitempty"`
+
+	// The last position confirmed by the bridge
+	// +optional
+	ConfirmedFlushLSN string `json:"confirmedFlushLSN,omitempty"`
+
+	// Whether the bridge is consuming the replication slot
+	// +optional
+	SlotActive bool `json:"slotActive,omitempty"`
+
+	// When the replication slot was last created again because it was
+	// missing in the primary, for example after a failover. The changes
+	// done between the last confirmed position and that moment may not
+	// have been published
+	// +optional
+	SlotRecreatedAt *metav1.Time `json:"slotRecreatedAt,omitempty"`
+
+	// The number of ready replicas of the bridge deployment
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Conditions for the ChangeDataCapture object
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=cdc
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Slot",type="string",JSONPath=".spec.slotName"
+// +kubebuilder:printcolumn:name="Active",type="boolean",JSONPath=".status.slotActive"
+// +kubebuilder:printcolumn:name="Confirmed LSN",type="string",JSONPath=".status.confirmedFlushLSN"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",description="Latest reconciliation message"
+
+// ChangeDataCapture publishes the changes of a database to Kafka or NATS,
+// through a bridge consuming a logical replication slot managed by the
+// operator
+type ChangeDataCapture struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired ChangeDataCapture.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ChangeDataCaptureSpec `json:"spec"`
+	// Most recently observed status of the ChangeDataCapture. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status ChangeDataCaptureStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChangeDataCaptureList contains a list of ChangeDataCapture
+type ChangeDataCaptureList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of change data captures
+	Items []ChangeDataCapture `json:"items"`
+}
+
+// GetSlotReclaimPolicy returns the reclaim policy of the replication slot,
+// defaulting to "delete"
+func (cdc *ChangeDataCapture) GetSlotReclaimPolicy() ChangeDataCaptureSlotReclaimPolicy {
+	if cdc.Spec.SlotReclaimPolicy == "" {
+		return ChangeDataCaptureSlotReclaimDelete
+	}
+	return cdc.Spec.SlotReclaimPolicy
+}
+
+// GetSnapshotMode returns the snapshot mode of the bridge, defaulting to "no_data"
+func (cdc *ChangeDataCapture) GetSnapshotMode() ChangeDataCaptureSnapshotMode {
+	if cdc.Spec.SnapshotMode == "" {
+		return ChangeDataCaptureSnapshotNoData
+	}
+	return cdc.Spec.SnapshotMode
+}
+
+// GetImage returns the image of the bridge
+func (cdc *ChangeDataCapture) GetImage() string {
+	if cdc.Spec.Image == "" {
+		return DefaultChangeDataCaptureImage
+	}
+	return cdc.Spec.Image
+}
+
+// GetOffsetStorageSize returns the size of the volume storing the offsets
+// of the bridge, when the changes are published to NATS
+func (cdc *ChangeDataCapture) GetOffsetStorageSize() resource.Quantity {
+	if cdc.Spec.Sink.NATS == nil || cdc.Spec.Sink.NATS.OffsetStorage == nil ||
+		cdc.Spec.Sink.NATS.OffsetStorage.Size.IsZero() {
+		return resource.MustParse(DefaultChangeDataCaptureOffsetStorageSize)
+	}
+	return cdc.Spec.Sink.NATS.OffsetStorage.Size
+}
+
+// GetOffsetStorageClass returns the storage class of the volume storing
+// the offsets of the bridge, nil for the default one
+func (cdc *ChangeDataCapture) GetOffsetStorageClass() *string {
+	if cdc.Spec.Sink.NATS == nil || cdc.Spec.Sink.NATS.OffsetStorage == nil {
+		return nil
+	}
+	return cdc.Spec.Sink.NATS.OffsetStorage.StorageClass
+}
+
+// GetKafkaOffsetsTopic returns the name of the Kafka topic where the bridge
+// stores its offsets, when the changes are published to Kafka. It can't
+// be confused with the topics of the tables, whose names contain dots
+func (cdc *ChangeDataCapture) GetKafkaOffsetsTopic() string {
+	return cdc.GetTopicPrefix() + "-" + cdc.Name + "-offsets"
+}
+
+// GetTopicPrefix returns the prefix of the topics where the changes are
+// published, defaulting to the name of the cluster
+func (cdc *ChangeDataCapture) GetTopicPrefix() string {
+	if cdc.Spec.Sink.TopicPrefix == "" {
+		return cdc.Spec.ClusterRef.Name
+	}
+	return cdc.Spec.Sink.TopicPrefix
+}
+
+func init() {
+	SchemeBuilder.Register(&ChangeDataCapture{}, &ChangeDataCaptureList{})
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -63,7 +65,8 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	return syncReplicas, electableSyncReplicas
 }
 
-// getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
+// getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas.
+// The list is ordered by election priority, and sorted by name within the same priority
 func (cluster *Cluster) getElectableSyncReplicas() []string {
 	var nonPrimaryInstances []string
 	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
@@ -71,6 +74,7 @@ func (cluster *Cluster) getElectableSyncReplicas() []string {
 			nonPrimaryInstances = append(nonPrimaryInstances, instance)
 		}
 	}
+	sort.Strings(nonPrimaryInstances)
 
 	topology := cluster.Status.Topology
 	// We need to include every replica inside the list of possible synchronous standbys if we have no constraints
@@ -102,6 +106,7 @@ func (cluster *Cluster) getElectableSyncReplicas() []string {
 	}
 
 	electableReplicas := make([]string, 0, len(nonPrimaryInstances))
+	var sameTopologyReplicas []string
 	for _, name := range nonPrimaryInstances {
 		name := PodName(name)
 
@@ -114,8 +119,16 @@ func (cluster *Cluster) getElectableSyncReplicas() []string {
 
 		if !currentPrimaryTopology.matchesTopology(instanceTopology) {
 			electableReplicas = append(electableReplicas, string(name))
+		} else {
+			sameTopologyReplicas = append(sameTopologyReplicas, string(name))
 		}
 	}
 
+	// When the constraints are preferred, the replicas sharing the topology of
+	// the primary are still electable, with the lowest priority
+	if cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.GetMode() == SyncReplicaElectionModePreferred {
+		electableReplicas = append(electableReplicas, sameTopologyReplicas...)
+	}
+
 	return electableReplicas
 }
@@ -64,6 +64,41 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(Equal([]string{differentAZPod}))
 	})
 
+	It("should return the pods in the same AZ with a lower priority when the constraints are preferred", func() {
+		const (
+			primaryPod     = "example-1"
+			sameZonePod    = "example-2"
+			differentAZPod = "example-3"
+		)
+
+		cluster := createFakeCluster("example")
+		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint = SyncReplicaElectionConstraints{
+			Enabled:                true,
+			NodeLabelsAntiAffinity: []string{"az"},
+			Method:                 SyncReplicaElectionMethodFirst,
+			Mode:                   SyncReplicaElectionModePreferred,
+		}
+		cluster.Status.Topology = Topology{
+			SuccessfullyExtracted: true,
+			Instances: map[PodName]PodTopologyLabels{
+				primaryPod: map[string]string{
+					"az": "one",
+				},
+				sameZonePod: map[string]string{
+					"az": "one",
+				},
+				differentAZPod: map[string]string{
+					"az": "three",
+				},
+			},
+		}
+
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{differentAZPod, sameZonePod}))
+	})
+
 	It("should lower the synchronous replica number to enforce self-healing", func() {
 		cluster := createFakeCluster("example")
 		cluster.Status = ClusterStatus{
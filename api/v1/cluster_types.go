@@ -19,6 +19,8 @@ package v1
 import (
 	"context"
 	"fmt"
+	"maps"
+	"path"
 	"regexp"
 	"slices"
 	"strconv"
@@ -26,9 +28,11 @@ import (
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -48,6 +52,11 @@ const (
 	// get the name of the generated replication secret for PostgreSQL
 	ReplicationSecretSuffix = "-replication" // #nosec
 
+	// ClientCertificateSecretSuffix is the suffix appended to the cluster and
+	// role names to get the name of the secret containing the client
+	// certificate of a managed role
+	ClientCertificateSecretSuffix = "-client" // #nosec
+
 	// SuperUserSecretSuffix is the suffix appended to the cluster name to
 	// get the name of the PostgreSQL superuser secret
 	SuperUserSecretSuffix = "-superuser"
@@ -85,10 +94,23 @@ const (
 	// data
 	ServiceReadWriteSuffix = "-rw"
 
+	// ConnectionConfigMapSuffix is the suffix appended to the cluster name
+	// to get the name of the ConfigMap with the multi-host connection strings
+	ConnectionConfigMapSuffix = "-connection"
+
+	// GrafanaDashboardConfigMapSuffix is the suffix appended to the cluster
+	// name to get the name of the ConfigMap with the Grafana dashboard
+	GrafanaDashboardConfigMapSuffix = "-grafana-dashboard"
+
 	// ClusterSecretSuffix is the suffix appended to the cluster name to
 	// get the name of the pull secret
 	ClusterSecretSuffix = "-pull-secret"
 
+	// TDEPassphrasesSecretSuffix is the suffix appended to the cluster name
+	// to get the name of the secret where the operator keeps the TDE
+	// passphrases that may still be wrapping the data encryption keys
+	TDEPassphrasesSecretSuffix = "-tde-passphrases" // #nosec
+
 	// WalArchiveVolumeSuffix is the suffix appended to the instance name to
 	// get the name of the PVC dedicated to WAL files.
 	WalArchiveVolumeSuffix = "-wal"
@@ -121,6 +143,10 @@ const (
 
 	// PGBouncerPoolerUserName is the name of the role to be used for
 	PGBouncerPoolerUserName = "cnpg_pooler_pgbouncer"
+
+	// DiagnosticsUserName is the name of the role running the read-only
+	// diagnostic queries requested through the instance manager
+	DiagnosticsUserName = "cnpg_diagnostics"
 )
 
 // SnapshotOwnerReference defines the reference type for the owner of the snapshot.
@@ -236,6 +262,23 @@ type ImageCatalogRef struct {
 	Major int `json:"major"`
 }
 
+// UpdatePolicy controls the automated update of a cluster to the new minor
+// version images published in its image catalog, which acts as the update
+// channel. The rollout of the new image follows the maintenance windows
+// of the cluster, if any
+type UpdatePolicy struct {
+	// A regular expression that the tag of a new image must match to be
+	// rolled out, like `^16\.[0-9]+$` to skip the images having a suffix
+	// in the tag. If empty, any tag is accepted
+	// +optional
+	TagPattern string `json:"tagPattern,omitempty"`
+
+	// The clusters, in the same namespace, acting as canaries. A new image
+	// is rolled out only when every canary runs it and is healthy
+	// +optional
+	Canaries []LocalObjectReference `json:"canaries,omitempty"`
+}
+
 // +kubebuilder:validation:XValidation:rule="!(has(self.imageCatalogRef) && has(self.imageName))",message="imageName and imageCatalogRef are mutually exclusive"
 
 // ClusterSpec defines the desired state of Cluster
@@ -258,6 +301,13 @@ type ClusterSpec struct {
 	// +optional
 	ImageCatalogRef *ImageCatalogRef `json:"imageCatalogRef,omitempty"`
 
+	// Controls when a new image, published in the image catalog for the
+	// major version of the cluster, is rolled out. Without it, the new
+	// images are rolled out as soon as they are published. Requires
+	// `imageCatalogRef`
+	// +optional
+	UpdatePolicy *UpdatePolicy `json:"updatePolicy,omitempty"`
+
 	// Image pull policy.
 	// One of `Always`, `Never` or `IfNotPresent`.
 	// If not defined, it defaults to `IfNotPresent`.
@@ -313,6 +363,34 @@ type ClusterSpec struct {
 	// +optional
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// Options of the clone of the primary used to create new replicas
+	// +optional
+	ReplicaClone *ReplicaCloneConfiguration `json:"replicaClone,omitempty"`
+
+	// The automatic re-creation of the replicas whose data is damaged
+	// +optional
+	ReplicaAutoReclone *ReplicaAutoRecloneConfiguration `json:"replicaAutoReclone,omitempty"`
+
+	// The consistency checks of the data directory executed before
+	// starting PostgreSQL after an unclean shutdown
+	// +optional
+	StartupCheck *StartupCheckConfiguration `json:"startupCheck,omitempty"`
+
+	// How the instance manager restarts PostgreSQL when the postmaster
+	// exits unexpectedly, for example after running out of memory
+	// +optional
+	PostmasterRestart *PostmasterRestartConfiguration `json:"postmasterRestart,omitempty"`
+
+	// The SQL scripts and shell scripts executed by the instance manager
+	// at well-defined points of the lifecycle of each instance
+	// +optional
+	LifecycleHooks *LifecycleHooksConfiguration `json:"lifecycleHooks,omitempty"`
+
+	// The endpoint of the instance manager running the read-only
+	// diagnostic queries of the `sql` command of the kubectl plugin
+	// +optional
+	DiagnosticQueries *DiagnosticQueriesConfiguration `json:"diagnosticQueries,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -336,6 +414,12 @@ type ClusterSpec struct {
 	// +optional
 	EnableSuperuserAccess *bool `json:"enableSuperuserAccess,omitempty"`
 
+	// The passwords of the superuser and of the application user mounted
+	// in the instance pods by the Secrets Store CSI driver, for example
+	// from HashiCorp Vault, instead of being read from Kubernetes secrets
+	// +optional
+	SecretsStore *SecretsStoreConfiguration `json:"secretsStore,omitempty"`
+
 	// The configuration for the CA and related certificates
 	// +optional
 	Certificates *CertificatesConfiguration `json:"certificates,omitempty"`
@@ -381,6 +465,13 @@ type ClusterSpec struct {
 	// +optional
 	SmartShutdownTimeout int32 `json:"smartShutdownTimeout,omitempty"`
 
+	// The time in seconds that controls the window of time reserved for the fast shutdown of Postgres
+	// to complete, after the smart one timed out. When it expires, an immediate shutdown is requested,
+	// requiring crash recovery at the next start. Make sure that `smartShutdownTimeout` and
+	// `fastShutdownTimeout` fit in `stopDelay` (default 60)
+	// +optional
+	FastShutdownTimeout int32 `json:"fastShutdownTimeout,omitempty"`
+
 	// The time in seconds that is allowed for a primary PostgreSQL instance
 	// to gracefully shutdown during a switchover.
 	// Default value is 3600 seconds (1 hour).
@@ -395,6 +486,41 @@ type ClusterSpec struct {
 	// +optional
 	FailoverDelay int32 `json:"failoverDelay,omitempty"`
 
+	// The amount of time (in seconds) a Kubernetes node has to be reported
+	// as not ready, or under disk pressure, before the instances running on
+	// it are moved away, to ignore transient conditions. Cordoned nodes and
+	// nodes being removed are considered unavailable immediately
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NodeConditionsGracePeriod int32 `json:"nodeConditionsGracePeriod,omitempty"`
+
+	// The conditions under which the primary is considered failed, and
+	// the cooldown preventing repeated failovers in a short time
+	// +optional
+	FailoverDetection *FailoverDetectionConfiguration `json:"failoverDetection,omitempty"`
+
+	// The external arbiter the operator consults before promoting a new
+	// primary in case of failover, to prevent split-brain scenarios in
+	// topologies spanning multiple Kubernetes clusters
+	// +optional
+	FailoverArbiter *FailoverArbiterConfiguration `json:"failoverArbiter,omitempty"`
+
+	// The configuration of the probes to be injected
+	// in the PostgreSQL Pods.
+	// +optional
+	Probes *ProbesConfiguration `json:"probes,omitempty"`
+
+	// The strategy used by a former primary to rejoin the cluster as a
+	// replica when pg_rewind fails. `rewind` (default) requires a manual
+	// intervention, `pgBaseBackup` clones the data from the new primary,
+	// while `objectStore` restores it from the latest backup available in
+	// the object store, replaying the WAL files from the archive
+	// +kubebuilder:validation:Enum:=rewind;pgBaseBackup;objectStore
+	// +kubebuilder:default:=rewind
+	// +optional
+	RejoinStrategy RejoinStrategy `json:"rejoinStrategy,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
@@ -438,6 +564,24 @@ type ClusterSpec struct {
 	// +optional
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// Method to follow to upgrade the replicas during a rolling update
+	// procedure: they can be restarted or re-created in-place (`restart` -
+	// default), or replaced by a new replica that is created and catches up
+	// with the primary before the outdated one is removed (`surge`),
+	// preserving the number of available replicas during the update
+	// +kubebuilder:default:=restart
+	// +kubebuilder:validation:Enum:=restart;surge
+	// +optional
+	ReplicaUpdateMethod ReplicaUpdateMethod `json:"replicaUpdateMethod,omitempty"`
+
+	// The time in seconds that is allowed for the new replica created by the
+	// `surge` update method to catch up with the primary. When it expires,
+	// the new replica is removed and the outdated one is restarted in-place.
+	// Default value is 3600 seconds (1 hour).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicaSurgeTimeout int32 `json:"replicaSurgeTimeout,omitempty"`
+
 	// The configuration to be used for backups
 	// +optional
 	Backup *BackupConfiguration `json:"backup,omitempty"`
@@ -446,6 +590,20 @@ type ClusterSpec struct {
 	// +optional
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
+	// The recurring periods of time during which the operator can perform
+	// the disruptive operations that are not urgent, like the rolling
+	// restart of the instances and the switchover needed to restart the
+	// primary, and the Kubernetes nodes can be drained. Outside of them,
+	// those operations are deferred to the next window, while failovers
+	// are always allowed. If empty, there is no restriction
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// The scheduled VACUUM, ANALYZE and REINDEX runs, executed by the
+	// instance manager of the primary instance
+	// +optional
+	DatabaseMaintenance *DatabaseMaintenanceConfiguration `json:"databaseMaintenance,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this cluster
 	// +optional
 	Monitoring *MonitoringConfiguration `json:"monitoring,omitempty"`
@@ -460,6 +618,12 @@ type ClusterSpec struct {
 	// +optional
 	LogLevel string `json:"logLevel,omitempty"`
 
+	// The configuration of the rotating files where the instance manager
+	// writes a copy of the PostgreSQL logs, in addition to the standard
+	// output
+	// +optional
+	LogFiles *LogFilesConfiguration `json:"logFiles,omitempty"`
+
 	// Template to be used to define projected volumes, projected volumes will be mounted
 	// under `/projected` base folder
 	// +optional
@@ -486,6 +650,13 @@ type ClusterSpec struct {
 	// +optional
 	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
 
+	// The AppArmor profile applied to every container of the instance
+	// pods and jobs, through the `container.apparmor.security.beta.kubernetes.io`
+	// annotations. An annotation set in the cluster for a container takes
+	// precedence over it
+	// +optional
+	AppArmorProfile *AppArmorProfile `json:"appArmorProfile,omitempty"`
+
 	// The tablespaces configuration
 	// +optional
 	Tablespaces []TablespaceConfiguration `json:"tablespaces,omitempty"`
@@ -505,6 +676,12 @@ type ClusterSpec struct {
 	// The plugins configuration, containing
 	// any plugin to be loaded with the corresponding configuration
 	Plugins PluginConfigurationList `json:"plugins,omitempty"`
+
+	// The garbage collection of the resources of the cluster that are
+	// not used anymore. When not specified, the orphaned resources are
+	// retained.
+	// +optional
+	GarbageCollection *GarbageCollectionConfiguration `json:"garbageCollection,omitempty"`
 }
 
 // PluginConfigurationList represent a set of plugin with their
@@ -530,6 +707,10 @@ const (
 	// PhaseWaitingForUser set the status to wait for an action from the user
 	PhaseWaitingForUser = "Waiting for user action"
 
+	// PhaseWaitingForMaintenanceWindow set the status to wait for the next
+	// maintenance window to perform a rolling update
+	PhaseWaitingForMaintenanceWindow = "Waiting for the maintenance window"
+
 	// PhaseInplacePrimaryRestart for a cluster restarting the primary instance in-place
 	PhaseInplacePrimaryRestart = "Primary instance is being restarted in-place"
 
@@ -558,8 +739,81 @@ const (
 	// PhaseApplyingConfiguration is set by the instance manager when a configuration
 	// change is being detected
 	PhaseApplyingConfiguration = "Applying configuration"
+
+	// PhaseMajorUpgrade is set when the data directory of the cluster is being
+	// upgraded to a new PostgreSQL major version
+	PhaseMajorUpgrade = "Upgrading Postgres major version"
+
+	// PhaseMajorUpgradeFailed is set when the major version upgrade failed and
+	// the data directory has been restored to the previous major version
+	PhaseMajorUpgradeFailed = "Postgres major version upgrade failed"
+
+	// PhaseMajorUpgradeIncomplete is set when the major version upgrade failed
+	// after the data directory had been upgraded, which can't be restored
+	// to the previous major version anymore
+	PhaseMajorUpgradeIncomplete = "Postgres major version upgrade incomplete"
+
+	// PhaseInPlaceRestore is set when the cluster is being restored in
+	// place from one of its backups, as requested by a Restore object
+	PhaseInPlaceRestore = "Restoring the cluster in place"
+
+	// PhaseInPlaceRestoreFailed is set when the in-place restore of the
+	// cluster failed, leaving its primary instance without a usable
+	// data directory
+	PhaseInPlaceRestoreFailed = "In-place restore failed"
+)
+
+// ImageInfo contains the information about a PostgreSQL image
+type ImageInfo struct {
+	// Image is the image name
+	Image string `json:"image"`
+
+	// MajorVersion is the major version of the image
+	MajorVersion int `json:"majorVersion"`
+}
+
+// AppArmorProfileType is the kind of AppArmor profile applied to a container
+// +enum
+type AppArmorProfileType string
+
+const (
+	// AppArmorProfileTypeRuntimeDefault means the default profile
+	// of the container runtime is applied
+	AppArmorProfileTypeRuntimeDefault AppArmorProfileType = "RuntimeDefault"
+
+	// AppArmorProfileTypeLocalhost means a profile loaded on the node is applied
+	AppArmorProfileTypeLocalhost AppArmorProfileType = "Localhost"
+
+	// AppArmorProfileTypeUnconfined means no profile is applied
+	AppArmorProfileTypeUnconfined AppArmorProfileType = "Unconfined"
 )
 
+// AppArmorProfile defines the AppArmor profile applied to a container
+// +kubebuilder:validation:XValidation:rule="self.type == 'Localhost' ? has(self.localhostProfile) : !has(self.localhostProfile)",message="localhostProfile must be set if and only if type is Localhost"
+type AppArmorProfile struct {
+	// The kind of profile: `RuntimeDefault`, `Localhost` or `Unconfined`
+	// +kubebuilder:validation:Enum=RuntimeDefault;Localhost;Unconfined
+	Type AppArmorProfileType `json:"type"`
+
+	// The name of the profile loaded on the node, required
+	// when the type is `Localhost`
+	// +optional
+	LocalhostProfile string `json:"localhostProfile,omitempty"`
+}
+
+// GetAnnotationValue gets the value of the AppArmor annotation
+// applying this profile to a container
+func (p *AppArmorProfile) GetAnnotationValue() string {
+	switch p.Type {
+	case AppArmorProfileTypeLocalhost:
+		return "localhost/" + p.LocalhostProfile
+	case AppArmorProfileTypeUnconfined:
+		return "unconfined"
+	default:
+		return "runtime/default"
+	}
+}
+
 // EphemeralVolumesSizeLimitConfiguration contains the configuration of the ephemeral
 // storage
 type EphemeralVolumesSizeLimitConfiguration struct {
@@ -588,6 +842,158 @@ func (e *EphemeralVolumesSizeLimitConfiguration) GetTemporaryDataLimit() *resour
 	return e.TemporaryData
 }
 
+// LogFilesCompression is the compression applied to the rotated log files
+// +kubebuilder:validation:Enum=none;gzip
+type LogFilesCompression string
+
+const (
+	// LogFilesCompressionNone means that the rotated log files are not compressed
+	LogFilesCompressionNone LogFilesCompression = "none"
+
+	// LogFilesCompressionGzip means that the rotated log files are compressed with gzip
+	LogFilesCompressionGzip LogFilesCompression = "gzip"
+)
+
+// LogFilesConfiguration contains the configuration of the files where the
+// instance manager writes a copy of the PostgreSQL logs, in JSON format.
+// The files are stored in a dedicated ephemeral volume mounted in
+// `/var/log/postgresql`, and are rotated when they reach the maximum size
+// or age
+type LogFilesConfiguration struct {
+	// When enabled, the PostgreSQL logs are also written to the log files.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The size a log file reaches before being rotated.
+	// Default: 100Mi.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// The time after which a log file is rotated, regardless of its size,
+	// like `24h`. By default, the log files are rotated only by size
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// The number of rotated log files to retain, the oldest ones being
+	// deleted first.
+	// Default: 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxFiles *int32 `json:"maxFiles,omitempty"`
+
+	// The compression applied to the rotated log files, `none` or `gzip`.
+	// Default: gzip.
+	// +kubebuilder:default:=gzip
+	// +optional
+	Compression LogFilesCompression `json:"compression,omitempty"`
+
+	// The size limit of the `emptyDir` volume storing the log files
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// The source of the volume storing the log files, to use a dedicated
+	// generic ephemeral volume instead of an `emptyDir`
+	// +optional
+	EphemeralVolumeSource *corev1.EphemeralVolumeSource `json:"ephemeralVolumeSource,omitempty"`
+}
+
+// IsLogFilesEnabled checks whether the PostgreSQL logs are written to the
+// log files
+func (cluster *Cluster) IsLogFilesEnabled() bool {
+	return cluster.Spec.LogFiles != nil && cluster.Spec.LogFiles.Enabled
+}
+
+// GetMaxSize gets the size a log file reaches before being rotated
+func (configuration *LogFilesConfiguration) GetMaxSize() int64 {
+	if configuration.MaxSize == nil {
+		return 100 * 1024 * 1024
+	}
+	return configuration.MaxSize.Value()
+}
+
+// GetMaxAge gets the time after which a log file is rotated, zero
+// meaning that log files are rotated only by size
+func (configuration *LogFilesConfiguration) GetMaxAge() time.Duration {
+	if configuration.MaxAge == nil {
+		return 0
+	}
+	return configuration.MaxAge.Duration
+}
+
+// GetMaxFiles gets the number of rotated log files to retain
+func (configuration *LogFilesConfiguration) GetMaxFiles() int {
+	if configuration.MaxFiles == nil {
+		return 10
+	}
+	return int(*configuration.MaxFiles)
+}
+
+// GetCompression gets the compression applied to the rotated log files
+func (configuration *LogFilesConfiguration) GetCompression() LogFilesCompression {
+	if configuration.Compression == "" {
+		return LogFilesCompressionGzip
+	}
+	return configuration.Compression
+}
+
+// GarbageCollectionPolicy is the policy applied to the orphaned resources
+// +kubebuilder:validation:Enum=delete;retain
+type GarbageCollectionPolicy string
+
+const (
+	// GarbageCollectionPolicyDelete means that the orphaned resources are
+	// deleted when their retention period expires
+	GarbageCollectionPolicyDelete = GarbageCollectionPolicy("delete")
+
+	// GarbageCollectionPolicyRetain means that the orphaned resources are
+	// only reported
+	GarbageCollectionPolicyRetain = GarbageCollectionPolicy("retain")
+)
+
+// DefaultGarbageCollectionRetentionPeriod is the time an orphaned resource
+// is retained, when not specified otherwise
+const DefaultGarbageCollectionRetentionPeriod = 24 * time.Hour
+
+// GarbageCollectionConfiguration contains the configuration of the
+// garbage collection of the resources of the cluster. The failed jobs
+// initializing an instance, the PVCs whose initialization never
+// completed, and the certificate secrets generated by the operator that
+// have been replaced by user-provided ones are considered orphaned.
+type GarbageCollectionConfiguration struct {
+	// The policy applied to the orphaned resources: `delete` (default)
+	// removes them when the retention period expires, while `retain`
+	// just reports them with an event
+	// +kubebuilder:default:=delete
+	// +optional
+	Policy GarbageCollectionPolicy `json:"policy,omitempty"`
+
+	// How long an orphaned resource is retained before being deleted,
+	// to allow inspecting it. Default: `24h`
+	// +optional
+	RetentionPeriod *metav1.Duration `json:"retentionPeriod,omitempty"`
+}
+
+// GetPolicy gets the policy applied to the orphaned resources
+func (configuration *GarbageCollectionConfiguration) GetPolicy() GarbageCollectionPolicy {
+	if configuration == nil {
+		return GarbageCollectionPolicyRetain
+	}
+	if configuration.Policy == "" {
+		return GarbageCollectionPolicyDelete
+	}
+	return configuration.Policy
+}
+
+// GetRetentionPeriod gets how long an orphaned resource is retained
+func (configuration *GarbageCollectionConfiguration) GetRetentionPeriod() time.Duration {
+	if configuration == nil || configuration.RetentionPeriod == nil {
+		return DefaultGarbageCollectionRetentionPeriod
+	}
+	return configuration.RetentionPeriod.Duration
+}
+
 // ServiceAccountTemplate contains the template needed to generate the service accounts
 type ServiceAccountTemplate struct {
 	// Metadata are the metadata to be used for the generated
@@ -762,10 +1168,25 @@ type ClusterStatus struct {
 	// +optional
 	TablespacesStatus []TablespaceState `json:"tablespacesStatus,omitempty"`
 
+	// ExtensionsStatus reports the versions of the extensions declared in
+	// `.spec.postgresql.extensions` installed in each database
+	// +optional
+	ExtensionsStatus []ExtensionState `json:"extensionsStatus,omitempty"`
+
 	// The timeline of the Postgres cluster
 	// +optional
 	TimelineID int `json:"timelineID,omitempty"`
 
+	// The configuration parameters that have been changed but need a
+	// restart of PostgreSQL to be applied, as computed by the primary
+	// +optional
+	PendingRestartParameters []string `json:"pendingRestartParameters,omitempty"`
+
+	// The roles whose password is still stored as an MD5 hash, as
+	// reported by the primary instance
+	// +optional
+	RolesWithMD5Password []string `json:"rolesWithMD5Password,omitempty"`
+
 	// Instances topology.
 	// +optional
 	Topology Topology `json:"topology,omitempty"`
@@ -868,6 +1289,37 @@ type ClusterStatus struct {
 	// +optional
 	LastFailedBackup string `json:"lastFailedBackup,omitempty"`
 
+	// The outcome of the latest enforcement of the backup retention policy
+	// on the object store
+	// +optional
+	LastRetentionPolicyEnforcement *RetentionPolicyEnforcementStatus `json:"lastRetentionPolicyEnforcement,omitempty"`
+
+	// The last automatic re-creation of a damaged replica
+	// +optional
+	LastReplicaReclone *ReplicaRecloneStatus `json:"lastReplicaReclone,omitempty"`
+
+	// The time of the last WAL file archived by the primary, stored as a
+	// date in RFC3339 format. Together with the first recoverability point,
+	// it delimits the targets available for a point in time recovery
+	// +optional
+	LastRecoverabilityPoint string `json:"lastRecoverabilityPoint,omitempty"`
+
+	// The most recent completed base backups available in the main object
+	// store, up to 50, refreshed periodically by the primary instance
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	BackupCatalog []BackupCatalogEntry `json:"backupCatalog,omitempty"`
+
+	// The latest runs of the scheduled database maintenance tasks,
+	// starting from the most recent one
+	// +optional
+	MaintenanceHistory []MaintenanceTaskRun `json:"maintenanceHistory,omitempty"`
+
+	// The replacement of an outdated replica in progress, when
+	// upgrading the replicas with the `surge` method
+	// +optional
+	ReplicaSurge *ReplicaSurgeStatus `json:"replicaSurge,omitempty"`
+
 	// The commit hash number of which this operator running
 	// +optional
 	CommitHash string `json:"cloudNativePGCommitHash,omitempty"`
@@ -877,10 +1329,20 @@ type ClusterStatus struct {
 	CurrentPrimaryTimestamp string `json:"currentPrimaryTimestamp,omitempty"`
 
 	// The timestamp when the primary was detected to be unhealthy
-	// This field is reported when `.spec.failoverDelay` is populated or during online upgrades
+	// This field is reported when `.spec.failoverDelay` or
+	// `.spec.failoverDetection.consecutiveFailures` are populated, or during online upgrades
 	// +optional
 	CurrentPrimaryFailingSinceTimestamp string `json:"currentPrimaryFailingSinceTimestamp,omitempty"`
 
+	// The number of consecutive times the primary was detected to be unhealthy
+	// This field is reported when `.spec.failoverDetection.consecutiveFailures` is populated
+	// +optional
+	CurrentPrimaryFailureCount int32 `json:"currentPrimaryFailureCount,omitempty"`
+
+	// The timestamp of the last observation counted in `currentPrimaryFailureCount`
+	// +optional
+	CurrentPrimaryLastFailureTimestamp string `json:"currentPrimaryLastFailureTimestamp,omitempty"`
+
 	// The timestamp when the last request for a new primary has occurred
 	// +optional
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
@@ -917,12 +1379,93 @@ type ClusterStatus struct {
 	// +optional
 	Image string `json:"image,omitempty"`
 
+	// The image published in the image catalog that is waiting for
+	// the update policy to allow its rollout
+	// +optional
+	PendingImage string `json:"pendingImage,omitempty"`
+
+	// PGDataImageInfo contains the details of the latest image that
+	// has run on the current data directory
+	// +optional
+	PGDataImageInfo *ImageInfo `json:"pgDataImageInfo,omitempty"`
+
 	// PluginStatus is the status of the loaded plugins
 	PluginStatus []PluginStatus `json:"pluginStatus,omitempty"`
 
 	// SwitchReplicaClusterStatus is the status of the switch to replica cluster
 	// +optional
 	SwitchReplicaClusterStatus SwitchReplicaClusterStatus `json:"switchReplicaClusterStatus,omitempty"`
+
+	// DemotionToken is generated by the designated primary when this
+	// cluster is demoted to a replica cluster, and is meant to be used
+	// as the `promotionToken` of the new primary cluster
+	// +optional
+	DemotionToken string `json:"demotionToken,omitempty"`
+
+	// PromotionToken is the demotion token of the former primary cluster
+	// found by the operator in the same namespace, which is used when
+	// `.spec.replica.promotionToken` is not set
+	// +optional
+	PromotionToken string `json:"promotionToken,omitempty"`
+
+	// The progress of the logical import of the databases, set
+	// while bootstrapping the cluster with `initdb.import`
+	// +optional
+	LogicalImport *LogicalImportStatus `json:"logicalImport,omitempty"`
+}
+
+// LogicalImportStep is a step of the logical import of the databases
+type LogicalImportStep string
+
+const (
+	// LogicalImportStepRoles means that the roles are being imported
+	LogicalImportStepRoles LogicalImportStep = "roles"
+
+	// LogicalImportStepExport means that the databases are being exported
+	// with pg_dump
+	LogicalImportStepExport LogicalImportStep = "export"
+
+	// LogicalImportStepImport means that the databases are being imported
+	// with pg_restore
+	LogicalImportStepImport LogicalImportStep = "import"
+
+	// LogicalImportStepAnalyze means that the statistics of the imported
+	// databases are being collected
+	LogicalImportStepAnalyze LogicalImportStep = "analyze"
+
+	// LogicalImportStepCompleted means that the logical import is completed
+	LogicalImportStepCompleted LogicalImportStep = "completed"
+)
+
+// LogicalImportStatus is the progress of the logical import of the databases
+type LogicalImportStatus struct {
+	// The step being executed
+	// +optional
+	Step LogicalImportStep `json:"step,omitempty"`
+
+	// The database being processed in the current step
+	// +optional
+	CurrentDatabase string `json:"currentDatabase,omitempty"`
+
+	// The number of databases to be imported
+	// +optional
+	TotalDatabases int `json:"totalDatabases,omitempty"`
+
+	// The number of databases imported so far
+	// +optional
+	ImportedDatabases int `json:"importedDatabases,omitempty"`
+
+	// When the logical import started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the progress was last updated
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// When the logical import was completed
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
 }
 
 // SwitchReplicaClusterStatus contains all the statuses regarding the switch of a cluster to a replica cluster
@@ -939,6 +1482,56 @@ type InstanceReportedState struct {
 	// indicates on which TimelineId the instance is
 	// +optional
 	TimeLineID int `json:"timeLineID,omitempty"`
+	// the results of the last execution of the lifecycle hooks
+	// +optional
+	LifecycleHooks []LifecycleHookStatus `json:"lifecycleHooks,omitempty"`
+	// the result of the last consistency checks of the data directory
+	// +optional
+	StartupCheck *StartupCheckStatus `json:"startupCheck,omitempty"`
+	// the unexpected exits of the postmaster and the restarts that followed
+	// +optional
+	PostmasterRestart *PostmasterRestartStatus `json:"postmasterRestart,omitempty"`
+	// the role of the instance as reported by PostgreSQL, either `primary`
+	// or `replica`, empty when the status of the instance can't be read
+	// +optional
+	Role string `json:"role,omitempty"`
+	// indicates if the pod of the instance is ready
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+	// indicates if the instance needs to be restarted to apply its configuration
+	// +optional
+	PendingRestart bool `json:"pendingRestart,omitempty"`
+	// the reason why the status of the instance couldn't be read.
+	// The details of the error are logged by the operator
+	// +optional
+	Error string `json:"error,omitempty"`
+	// the position of the instance in the WAL stream. As it changes
+	// continuously, it is refreshed at most once per minute
+	// +optional
+	WAL *InstanceWALStatus `json:"wal,omitempty"`
+}
+
+// InstanceWALStatus describes the position of an instance in the WAL stream
+type InstanceWALStatus struct {
+	// the current WAL write location, reported by the primary
+	// +optional
+	CurrentLSN string `json:"currentLSN,omitempty"`
+	// the last WAL location received by a replica
+	// +optional
+	ReceivedLSN string `json:"receivedLSN,omitempty"`
+	// the last WAL location replayed by a replica
+	// +optional
+	ReplayLSN string `json:"replayLSN,omitempty"`
+	// the amount of WAL, in bytes, a replica has still to replay to reach
+	// the current WAL write location of the primary
+	// +optional
+	ReplayLagBytes *int64 `json:"replayLagBytes,omitempty"`
+	// the time elapsed between the flush of recent WAL on the primary and
+	// its replay on a replica, as reported by the primary
+	// +optional
+	ReplayLag string `json:"replayLag,omitempty"`
+	// when the position has been read, in RFC 3339 format
+	ObservedAt string `json:"observedAt"`
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -953,6 +1546,30 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionRecoveryTargetReachable represents whether the recovery target
+	// of a cluster bootstrapped from a backup can be reached
+	ConditionRecoveryTargetReachable ClusterConditionType = "RecoveryTargetReachable"
+	// ConditionReplication represents whether every standby instance is
+	// streaming from its upstream
+	ConditionReplication ClusterConditionType = "Replication"
+	// ConditionConfigApplied represents whether every instance is running
+	// with the current PostgreSQL configuration
+	ConditionConfigApplied ClusterConditionType = "ConfigApplied"
+	// ConditionCertificatesValid represents whether the certificates used
+	// by the cluster are valid
+	ConditionCertificatesValid ClusterConditionType = "CertificatesValid"
+	// ConditionReplicaIntegrity represents whether the data of every replica
+	// is intact, when the damaged replicas are automatically re-created
+	ConditionReplicaIntegrity ClusterConditionType = "ReplicaIntegrity"
+	// ConditionPostmasterRestarts represents whether every instance is
+	// allowed to restart PostgreSQL after an unexpected exit
+	ConditionPostmasterRestarts ClusterConditionType = "PostmasterRestarts"
+	// ConditionFailoverDampened represents whether the failover from an
+	// unhealthy primary is postponed by the flap damping cooldown
+	ConditionFailoverDampened ClusterConditionType = "FailoverDampened"
+	// ConditionFailoverArbiterLease represents whether the cluster holds
+	// the failover arbiter lease, which is required to promote a primary
+	ConditionFailoverArbiterLease ClusterConditionType = "FailoverArbiterLease"
 )
 
 // A Condition that can be used to communicate the Backup progress
@@ -985,6 +1602,26 @@ var (
 			Message: err.Error(),
 		}
 	}
+
+	// RecoveryTargetReachableCondition is added to a cluster being
+	// bootstrapped from a backup when its recovery target can be reached
+	RecoveryTargetReachableCondition = &metav1.Condition{
+		Type:    string(ConditionRecoveryTargetReachable),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonRecoveryTargetReachable),
+		Message: "The recovery target can be reached",
+	}
+
+	// BuildRecoveryTargetUnreachableCondition builds
+	// ConditionReasonRecoveryTargetUnreachable condition
+	BuildRecoveryTargetUnreachableCondition = func(err error) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionRecoveryTargetReachable),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonRecoveryTargetUnreachable),
+			Message: err.Error(),
+		}
+	}
 )
 
 // ConditionStatus defines conditions of resources
@@ -1025,6 +1662,74 @@ const (
 	// the WAL archiving is not working correctly
 	ConditionReasonContinuousArchivingFailing ConditionReason = "ContinuousArchivingFailing"
 
+	// ConditionReasonRecoveryTargetReachable means that the recovery target can be reached
+	// from the backup chosen for the recovery
+	ConditionReasonRecoveryTargetReachable ConditionReason = "RecoveryTargetReachable"
+
+	// ConditionReasonRecoveryTargetUnreachable means that the recovery target can't be
+	// reached from the backups that are available
+	ConditionReasonRecoveryTargetUnreachable ConditionReason = "RecoveryTargetUnreachable"
+
+	// ConditionReasonStandbysStreaming means that every standby instance is streaming
+	// from its upstream
+	ConditionReasonStandbysStreaming ConditionReason = "StandbysStreaming"
+
+	// ConditionReasonStandbysNotStreaming means that at least one of the standby instances
+	// is missing or isn't streaming from its upstream
+	ConditionReasonStandbysNotStreaming ConditionReason = "StandbysNotStreaming"
+
+	// ConditionReasonConfigurationApplied means that every instance is running with the
+	// current PostgreSQL configuration
+	ConditionReasonConfigurationApplied ConditionReason = "ConfigurationApplied"
+
+	// ConditionReasonPendingRestart means that at least one instance needs to be restarted
+	// to apply the current PostgreSQL configuration
+	ConditionReasonPendingRestart ConditionReason = "PendingRestart"
+
+	// ConditionReasonInstanceStatusUnavailable means that the status of at least one
+	// instance couldn't be retrieved
+	ConditionReasonInstanceStatusUnavailable ConditionReason = "InstanceStatusUnavailable"
+
+	// ConditionReasonCertificatesValid means that none of the certificates used by the
+	// cluster is expired
+	ConditionReasonCertificatesValid ConditionReason = "CertificatesValid"
+
+	// ConditionReasonCertificateExpired means that at least one of the certificates used
+	// by the cluster is expired
+	ConditionReasonCertificateExpired ConditionReason = "CertificateExpired"
+
+	// ConditionReasonReplicasIntact means that no replica is reporting
+	// checksum failures or repeatedly failing
+	ConditionReasonReplicasIntact ConditionReason = "ReplicasIntact"
+
+	// ConditionReasonReplicaDamaged means that at least one replica is reporting
+	// checksum failures or repeatedly failing, and will be re-created
+	ConditionReasonReplicaDamaged ConditionReason = "ReplicaDamaged"
+
+	// ConditionReasonPostmasterRestartsAllowed means that no instance has
+	// paused the restarts of PostgreSQL
+	ConditionReasonPostmasterRestartsAllowed ConditionReason = "PostmasterRestartsAllowed"
+
+	// ConditionReasonPostmasterRestartsPaused means that at least one instance
+	// stopped restarting PostgreSQL after repeated unexpected exits
+	ConditionReasonPostmasterRestartsPaused ConditionReason = "PostmasterRestartsPaused"
+
+	// ConditionReasonFlapDampingCooldown means that the primary was promoted
+	// within the flap damping cooldown, and the failover is postponed
+	ConditionReasonFlapDampingCooldown ConditionReason = "FlapDampingCooldown"
+
+	// ConditionReasonFailoverArbiterLeaseHeld means that the cluster
+	// holds the failover arbiter lease
+	ConditionReasonFailoverArbiterLeaseHeld ConditionReason = "LeaseHeld"
+
+	// ConditionReasonFailoverArbiterLeaseNotAcquired means that the lease
+	// is held by another cluster, and no primary is running in this one
+	ConditionReasonFailoverArbiterLeaseNotAcquired ConditionReason = "LeaseNotAcquired"
+
+	// ConditionReasonFailoverArbiterLeaseLost means that the lease has
+	// been lost while running a primary, which has been fenced
+	ConditionReasonFailoverArbiterLeaseLost ConditionReason = "LeaseLost"
+
 	// ClusterReady means that the condition changed because the cluster is ready and working properly
 	ClusterReady ConditionReason = "ClusterIsReady"
 
@@ -1054,6 +1759,29 @@ type PoolerIntegrations struct {
 type PgBouncerIntegrationStatus struct {
 	// +optional
 	Secrets []string `json:"secrets,omitempty"`
+
+	// The users of the auth queries of the poolers which let the
+	// operator provision them
+	// +optional
+	AuthQueryUsers []PgBouncerAuthQueryUser `json:"authQueryUsers,omitempty"`
+}
+
+// PgBouncerAuthQueryUser is the user of the auth query of a pooler, which
+// is created in the cluster together with the function it calls
+type PgBouncerAuthQueryUser struct {
+	// The name of the user
+	Name string `json:"name"`
+
+	// The secret containing the password of the user, not set when
+	// the user authenticates with a TLS client certificate
+	// +optional
+	PasswordSecret *SecretVersion `json:"passwordSecret,omitempty"`
+
+	// The schema of the function looking up the password hashes
+	FunctionSchema string `json:"functionSchema"`
+
+	// The name of the function looking up the password hashes
+	FunctionName string `json:"functionName"`
 }
 
 // ReplicaClusterConfiguration encapsulates the configuration of a replica
@@ -1067,27 +1795,648 @@ type ReplicaClusterConfiguration struct {
 	// existing cluster. Replica cluster can be created from a recovery
 	// object store or via streaming through pg_basebackup.
 	// Refer to the Replica clusters page of the documentation for more information.
-	Enabled bool `json:"enabled"`
-}
-
-// DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
-const DefaultReplicationSlotsUpdateInterval = 30
-
-// DefaultReplicationSlotsHASlotPrefix is the default prefix for names of replication slots used for HA.
-const DefaultReplicationSlotsHASlotPrefix = "_cnpg_"
+	// Ignored when `primary` is set.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 
-// SynchronizeReplicasConfiguration contains the configuration for the synchronization of user defined
-// physical replication slots
-type SynchronizeReplicasConfiguration struct {
-	// When set to true, every replication slot that is on the primary is synchronized on each standby
-	// +kubebuilder:default:=true
-	Enabled *bool `json:"enabled"`
+	// The name of the cluster which is the primary in a distributed
+	// topology. When set, this cluster is a replica unless it is named
+	// after `self`: changing this field in every cluster of the topology
+	// demotes the current primary and promotes the new one
+	// +optional
+	Primary string `json:"primary,omitempty"`
 
-	// List of regular expression patterns to match the names of replication slots to be excluded (by default empty)
+	// The name of this cluster in a distributed topology,
+	// defaults to the name of the Cluster resource
 	// +optional
-	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+	Self string `json:"self,omitempty"`
 
-	synchronizeReplicasCache `json:"-"`
+	// The demotion token generated by the former primary cluster. When set,
+	// the promotion of this cluster waits until the designated primary has
+	// replayed the WAL up to the shutdown checkpoint of the former primary
+	// +optional
+	PromotionToken string `json:"promotionToken,omitempty"`
+}
+
+// DefaultReplicaCloneRetryDelay is the default number of seconds
+// between two attempts to clone the primary
+const DefaultReplicaCloneRetryDelay = 10
+
+// ReplicaCloneConfiguration contains the options of the `pg_basebackup`
+// clone of the primary, used to create new replicas
+type ReplicaCloneConfiguration struct {
+	// The maximum rate at which the data directory is transferred, as
+	// accepted by the `--max-rate` option of `pg_basebackup`: kilobytes
+	// per second, or a value with the `k` or `M` suffix. It must be
+	// between 32k and 1024M. Default: no limit.
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kM]?$`
+	// +optional
+	MaxRate string `json:"maxRate,omitempty"`
+
+	// The maximum number of attempts to clone the primary. After a failed
+	// attempt, the partially transferred data directory is removed and
+	// the clone starts again.
+	// Default: 1.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// The number of seconds to wait between two attempts.
+	// Default: 10.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetryDelay int `json:"retryDelay,omitempty"`
+}
+
+// GetMaxRate gets the maximum transfer rate of the clone, or an
+// empty string if it is not limited
+func (r *ReplicaCloneConfiguration) GetMaxRate() string {
+	if r == nil {
+		return ""
+	}
+	return r.MaxRate
+}
+
+// GetMaxAttempts gets the maximum number of attempts to clone the primary
+func (r *ReplicaCloneConfiguration) GetMaxAttempts() int {
+	if r == nil || r.MaxAttempts < 1 {
+		return 1
+	}
+	return r.MaxAttempts
+}
+
+// GetRetryDelay gets the time to wait between two attempts
+func (r *ReplicaCloneConfiguration) GetRetryDelay() time.Duration {
+	if r == nil || r.RetryDelay < 0 {
+		return DefaultReplicaCloneRetryDelay * time.Second
+	}
+	return time.Duration(r.RetryDelay) * time.Second
+}
+
+// DefaultReplicaAutoRecloneMaxRestarts is the default number of restarts
+// of the PostgreSQL container after which a replica is re-created
+const DefaultReplicaAutoRecloneMaxRestarts = 5
+
+// DefaultReplicaAutoRecloneMinimumInterval is the default number of seconds
+// between two automatic re-creations of a replica
+const DefaultReplicaAutoRecloneMinimumInterval = 3600
+
+// ReplicaAutoRecloneConfiguration defines when a replica is considered
+// damaged and is re-created from scratch, cloning the primary
+type ReplicaAutoRecloneConfiguration struct {
+	// Enables the automatic re-creation of the damaged replicas.
+	// Default: false.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The number of data checksum failures, as reported by
+	// `pg_stat_database`, after which a replica is re-created.
+	// Default: 1.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxChecksumFailures int64 `json:"maxChecksumFailures,omitempty"`
+
+	// The number of restarts of the PostgreSQL container since the pod
+	// was last seen ready, for example because the replay of the WAL files
+	// keeps failing, after which a replica that is not ready is re-created.
+	// Default: 5.
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRestarts int32 `json:"maxRestarts,omitempty"`
+
+	// The minimum number of seconds between two automatic re-creations
+	// of a replica in the cluster.
+	// Default: 3600.
+	// +kubebuilder:default:=3600
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinimumInterval int32 `json:"minimumInterval,omitempty"`
+}
+
+// IsEnabled checks whether the damaged replicas are automatically re-created
+func (r *ReplicaAutoRecloneConfiguration) IsEnabled() bool {
+	return r != nil && r.Enabled
+}
+
+// GetMaxChecksumFailures gets the number of checksum failures after which
+// a replica is re-created
+func (r *ReplicaAutoRecloneConfiguration) GetMaxChecksumFailures() int64 {
+	if r == nil || r.MaxChecksumFailures < 1 {
+		return 1
+	}
+	return r.MaxChecksumFailures
+}
+
+// GetMaxRestarts gets the number of restarts of the PostgreSQL container
+// after which a replica is re-created
+func (r *ReplicaAutoRecloneConfiguration) GetMaxRestarts() int32 {
+	if r == nil || r.MaxRestarts < 1 {
+		return DefaultReplicaAutoRecloneMaxRestarts
+	}
+	return r.MaxRestarts
+}
+
+// GetMinimumInterval gets the minimum time between two re-creations
+func (r *ReplicaAutoRecloneConfiguration) GetMinimumInterval() time.Duration {
+	if r == nil || r.MinimumInterval < 0 {
+		return DefaultReplicaAutoRecloneMinimumInterval * time.Second
+	}
+	return time.Duration(r.MinimumInterval) * time.Second
+}
+
+// DefaultDiagnosticQueriesStatementTimeout is the default number of seconds
+// after which a diagnostic query is cancelled
+const DefaultDiagnosticQueriesStatementTimeout = 30
+
+// DefaultDiagnosticQueriesMaxRows is the default maximum number of rows
+// returned by a diagnostic query
+const DefaultDiagnosticQueriesMaxRows = 1000
+
+// DiagnosticQueriesConfiguration defines the endpoint of the instance
+// manager running read-only diagnostic queries. The endpoint is bound to
+// the loopback interface of the pod, and is reached with a port-forward
+type DiagnosticQueriesConfiguration struct {
+	// Enables the endpoint. The queries are executed in read-only
+	// transactions by the `cnpg_diagnostics` role, a member of `pg_monitor`.
+	// Default: false.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The number of seconds after which a query is cancelled.
+	// Default: 30.
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StatementTimeout int32 `json:"statementTimeout,omitempty"`
+
+	// The maximum number of rows returned by a query, the other ones
+	// being discarded.
+	// Default: 1000.
+	// +kubebuilder:default:=1000
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRows int32 `json:"maxRows,omitempty"`
+}
+
+// IsEnabled checks whether the diagnostic queries are accepted
+func (d *DiagnosticQueriesConfiguration) IsEnabled() bool {
+	return d != nil && d.Enabled
+}
+
+// GetStatementTimeout gets the time after which a query is cancelled
+func (d *DiagnosticQueriesConfiguration) GetStatementTimeout() time.Duration {
+	if d == nil || d.StatementTimeout < 1 {
+		return DefaultDiagnosticQueriesStatementTimeout * time.Second
+	}
+	return time.Duration(d.StatementTimeout) * time.Second
+}
+
+// GetMaxRows gets the maximum number of rows returned by a query
+func (d *DiagnosticQueriesConfiguration) GetMaxRows() int {
+	if d == nil || d.MaxRows < 1 {
+		return DefaultDiagnosticQueriesMaxRows
+	}
+	return int(d.MaxRows)
+}
+
+// StartupChecksumsMode defines how the data checksums are verified
+// by the startup consistency checks
+type StartupChecksumsMode string
+
+const (
+	// StartupChecksumsDisabled means that the data checksums are not verified
+	StartupChecksumsDisabled StartupChecksumsMode = "Disabled"
+
+	// StartupChecksumsSampled means that the data checksums of a random
+	// sample of the relations are verified
+	StartupChecksumsSampled StartupChecksumsMode = "Sampled"
+
+	// StartupChecksumsFull means that the data checksums of every
+	// relation are verified
+	StartupChecksumsFull StartupChecksumsMode = "Full"
+)
+
+// DefaultStartupCheckSampleSize is the default number of relations whose
+// data checksums are verified in the sampled mode
+const DefaultStartupCheckSampleSize = 100
+
+// StartupCheckConfiguration defines the consistency checks of the data
+// directory executed before starting PostgreSQL after an unclean shutdown
+type StartupCheckConfiguration struct {
+	// Enables the consistency checks. The control file is always
+	// validated, while the data checksums are verified as set in
+	// `checksums`.
+	// Default: false.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// How the data checksums are verified with `pg_checksums`: `Disabled`,
+	// `Sampled` or `Full`. The verification requires the data checksums
+	// to be enabled, and the crash recovery to be completed first.
+	// Default: `Disabled`.
+	// +kubebuilder:validation:Enum=Disabled;Sampled;Full
+	// +kubebuilder:default:=Disabled
+	// +optional
+	Checksums StartupChecksumsMode `json:"checksums,omitempty"`
+
+	// The number of relations whose data checksums are verified in the
+	// `Sampled` mode.
+	// Default: 100.
+	// +kubebuilder:default:=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SampleSize int32 `json:"sampleSize,omitempty"`
+
+	// Quarantines a replica failing the checks: PostgreSQL is not started,
+	// and the replica is re-created cloning the primary when
+	// `replicaAutoReclone` is enabled. A primary is never quarantined.
+	// Default: false.
+	// +optional
+	Quarantine bool `json:"quarantine,omitempty"`
+}
+
+// IsEnabled checks whether the startup consistency checks are executed
+func (s *StartupCheckConfiguration) IsEnabled() bool {
+	return s != nil && s.Enabled
+}
+
+// GetChecksumsMode gets how the data checksums are verified
+func (s *StartupCheckConfiguration) GetChecksumsMode() StartupChecksumsMode {
+	if s == nil || s.Checksums == "" {
+		return StartupChecksumsDisabled
+	}
+	return s.Checksums
+}
+
+// GetSampleSize gets the number of relations verified in the sampled mode
+func (s *StartupCheckConfiguration) GetSampleSize() int {
+	if s == nil || s.SampleSize < 1 {
+		return DefaultStartupCheckSampleSize
+	}
+	return int(s.SampleSize)
+}
+
+// StartupCheckStatus is the result of the last consistency checks of the
+// data directory of an instance
+type StartupCheckStatus struct {
+	// When the checks have been executed, stored as a date in RFC3339 format
+	Time string `json:"time"`
+
+	// Whether the data directory passed the checks
+	Passed bool `json:"passed"`
+
+	// The number of relation files whose data checksums have been verified
+	// +optional
+	VerifiedFiles int64 `json:"verifiedFiles,omitempty"`
+
+	// The description of the failure, or of the skipped checks
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Whether the instance has been quarantined, and PostgreSQL not started
+	// +optional
+	Quarantined bool `json:"quarantined,omitempty"`
+}
+
+// PostmasterExitReason is the reason why the postmaster exited unexpectedly
+type PostmasterExitReason string
+
+const (
+	// PostmasterExitOOMKilled means that the postmaster, or one of its
+	// children, has been killed by the OOM killer
+	PostmasterExitOOMKilled PostmasterExitReason = "OOMKilled"
+
+	// PostmasterExitCrashed means that the postmaster, or one of its
+	// children, terminated abnormally after PostgreSQL was started
+	PostmasterExitCrashed PostmasterExitReason = "Crashed"
+
+	// PostmasterExitStartupFailed means that the postmaster exited before
+	// accepting connections, as happens with an invalid configuration
+	PostmasterExitStartupFailed PostmasterExitReason = "StartupFailed"
+)
+
+const (
+	// DefaultPostmasterRestartInitialBackoff is the default time in seconds
+	// waited before the first restart of the postmaster
+	DefaultPostmasterRestartInitialBackoff = 5
+
+	// DefaultPostmasterRestartMaxBackoff is the default maximum time in
+	// seconds waited before a restart of the postmaster
+	DefaultPostmasterRestartMaxBackoff = 300
+
+	// DefaultPostmasterRestartMaxAttempts is the default number of consecutive
+	// crashes after which the restarts of the postmaster are paused
+	DefaultPostmasterRestartMaxAttempts = 5
+)
+
+// PostmasterRestartConfiguration defines how the instance manager restarts
+// PostgreSQL when the postmaster exits unexpectedly
+type PostmasterRestartConfiguration struct {
+	// Restarts PostgreSQL inside the instance manager, with an exponential
+	// backoff, instead of terminating the instance manager and letting the
+	// kubelet restart the container. When the postmaster exits before
+	// accepting connections, it is restarted only after the configuration
+	// changes.
+	// Default: false.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The time in seconds waited before the first restart, doubled after
+	// every consecutive crash.
+	// Default: 5.
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	InitialBackoff int32 `json:"initialBackoff,omitempty"`
+
+	// The maximum time in seconds waited before a restart.
+	// Default: 300.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBackoff int32 `json:"maxBackoff,omitempty"`
+
+	// The number of consecutive crashes after which the restarts are
+	// paused, until the configuration changes or the pod is restarted.
+	// The count is reset when PostgreSQL keeps running for 10 minutes.
+	// Default: 5.
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+}
+
+// IsEnabled checks whether PostgreSQL is restarted by the instance manager
+func (p *PostmasterRestartConfiguration) IsEnabled() bool {
+	return p != nil && p.Enabled
+}
+
+// GetInitialBackoff gets the time waited before the first restart
+func (p *PostmasterRestartConfiguration) GetInitialBackoff() time.Duration {
+	if p == nil || p.InitialBackoff < 1 {
+		return DefaultPostmasterRestartInitialBackoff * time.Second
+	}
+	return time.Duration(p.InitialBackoff) * time.Second
+}
+
+// GetMaxBackoff gets the maximum time waited before a restart
+func (p *PostmasterRestartConfiguration) GetMaxBackoff() time.Duration {
+	if p == nil || p.MaxBackoff < 1 {
+		return DefaultPostmasterRestartMaxBackoff * time.Second
+	}
+	return time.Duration(p.MaxBackoff) * time.Second
+}
+
+// GetMaxAttempts gets the number of consecutive crashes after which
+// the restarts are paused
+func (p *PostmasterRestartConfiguration) GetMaxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return DefaultPostmasterRestartMaxAttempts
+	}
+	return int(p.MaxAttempts)
+}
+
+// PostmasterRestartStatus reports the unexpected exits of the postmaster
+// of an instance, and the restarts that followed
+type PostmasterRestartStatus struct {
+	// The number of consecutive unexpected exits of the postmaster
+	Attempts int32 `json:"attempts"`
+
+	// Why the postmaster exited the last time
+	LastExitReason PostmasterExitReason `json:"lastExitReason"`
+
+	// When the postmaster exited the last time, stored as a date in RFC3339 format
+	LastExitTime string `json:"lastExitTime"`
+
+	// The description of the last exit of the postmaster
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// When PostgreSQL will be restarted, stored as a date in RFC3339 format
+	// +optional
+	NextRestartTime string `json:"nextRestartTime,omitempty"`
+
+	// Whether the restarts of PostgreSQL are paused
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// LifecycleHookPoint is a point in the lifecycle of an instance where
+// the lifecycle hooks are executed
+type LifecycleHookPoint string
+
+const (
+	// LifecycleHookPreStart is executed before the postmaster is started.
+	// PostgreSQL is not running, so only scripts are allowed
+	LifecycleHookPreStart LifecycleHookPoint = "preStart"
+
+	// LifecycleHookPostStart is executed as soon as PostgreSQL
+	// accepts connections
+	LifecycleHookPostStart LifecycleHookPoint = "postStart"
+
+	// LifecycleHookPreStop is executed when the instance has been asked
+	// to shut down, before PostgreSQL is stopped
+	LifecycleHookPreStop LifecycleHookPoint = "preStop"
+
+	// LifecycleHookPostPromotion is executed after an instance has been
+	// promoted to primary
+	LifecycleHookPostPromotion LifecycleHookPoint = "postPromotion"
+
+	// LifecycleHookPreDemotion is executed on the primary before it is
+	// demoted, for example during a switchover
+	LifecycleHookPreDemotion LifecycleHookPoint = "preDemotion"
+)
+
+// DefaultLifecycleHookTimeout is the default number of seconds after
+// which a lifecycle hook is cancelled
+const DefaultLifecycleHookTimeout = 30
+
+// LifecycleHooksConfiguration contains the hooks executed by the instance
+// manager at each point of the lifecycle of an instance. The hooks of each
+// point are executed sequentially, in the specified order. A failing hook
+// is reported in the status but never blocks the lifecycle of the instance
+type LifecycleHooksConfiguration struct {
+	// The hooks executed before the postmaster is started. Only
+	// scripts are allowed here
+	// +optional
+	PreStart []LifecycleHook `json:"preStart,omitempty"`
+
+	// The hooks executed as soon as PostgreSQL accepts connections
+	// +optional
+	PostStart []LifecycleHook `json:"postStart,omitempty"`
+
+	// The hooks executed before PostgreSQL is shut down. Their execution
+	// time counts towards the termination grace period of the Pod
+	// +optional
+	PreStop []LifecycleHook `json:"preStop,omitempty"`
+
+	// The hooks executed on the new primary after its promotion
+	// +optional
+	PostPromotion []LifecycleHook `json:"postPromotion,omitempty"`
+
+	// The hooks executed on the primary before it is demoted
+	// +optional
+	PreDemotion []LifecycleHook `json:"preDemotion,omitempty"`
+}
+
+// GetHooks gets the hooks to be executed at the passed point
+func (configuration *LifecycleHooksConfiguration) GetHooks(point LifecycleHookPoint) []LifecycleHook {
+	if configuration == nil {
+		return nil
+	}
+
+	switch point {
+	case LifecycleHookPreStart:
+		return configuration.PreStart
+	case LifecycleHookPostStart:
+		return configuration.PostStart
+	case LifecycleHookPreStop:
+		return configuration.PreStop
+	case LifecycleHookPostPromotion:
+		return configuration.PostPromotion
+	case LifecycleHookPreDemotion:
+		return configuration.PreDemotion
+	default:
+		return nil
+	}
+}
+
+// GetLifecycleHookPoints gets every point of the lifecycle of an
+// instance where hooks can be executed, in lifecycle order
+func GetLifecycleHookPoints() []LifecycleHookPoint {
+	return []LifecycleHookPoint{
+		LifecycleHookPreStart,
+		LifecycleHookPostStart,
+		LifecycleHookPostPromotion,
+		LifecycleHookPreDemotion,
+		LifecycleHookPreStop,
+	}
+}
+
+// LifecycleHook is a SQL script or a shell script, stored in a ConfigMap,
+// which is executed by the instance manager
+type LifecycleHook struct {
+	// The name of the hook, unique among the hooks of the same point
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// The key of a ConfigMap containing the SQL script to be executed
+	// by the superuser
+	// +optional
+	SQL *ConfigMapKeySelector `json:"sql,omitempty"`
+
+	// The key of a ConfigMap containing the shell script to be executed
+	// by `/bin/sh` in the PostgreSQL container
+	// +optional
+	Script *ConfigMapKeySelector `json:"script,omitempty"`
+
+	// The SHA-256 checksum of the content of the hook, in hexadecimal.
+	// The instance manager refuses to execute a content not matching it,
+	// so that changing the ConfigMap is not enough to change what is executed
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{64}$`
+	SHA256 string `json:"sha256"`
+
+	// The database where the SQL script is executed.
+	// Default: `postgres`.
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The number of seconds after which the hook is cancelled.
+	// Default: 30.
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// GetDatabase gets the database where the SQL script of the hook is executed
+func (hook LifecycleHook) GetDatabase() string {
+	if hook.Database == "" {
+		return "postgres"
+	}
+	return hook.Database
+}
+
+// GetTimeout gets the time after which the hook is cancelled
+func (hook LifecycleHook) GetTimeout() time.Duration {
+	if hook.Timeout < 1 {
+		return DefaultLifecycleHookTimeout * time.Second
+	}
+	return time.Duration(hook.Timeout) * time.Second
+}
+
+// GetConfigMapKeySelector gets the reference to the ConfigMap entry
+// containing the content of the hook
+func (hook LifecycleHook) GetConfigMapKeySelector() *ConfigMapKeySelector {
+	if hook.SQL != nil {
+		return hook.SQL
+	}
+	return hook.Script
+}
+
+// LifecycleHookStatus is the result of the last execution of a lifecycle
+// hook on an instance
+type LifecycleHookStatus struct {
+	// The name of the hook
+	Name string `json:"name"`
+
+	// The point of the lifecycle where the hook has been executed
+	Point LifecycleHookPoint `json:"point"`
+
+	// When the hook has been started, stored as a date in RFC3339 format
+	StartedAt string `json:"startedAt"`
+
+	// The time in seconds taken by the hook
+	// +optional
+	Duration int32 `json:"duration,omitempty"`
+
+	// Whether the hook completed successfully
+	Succeeded bool `json:"succeeded"`
+
+	// The error raised by the hook, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ReplicaRecloneStatus describes the last automatic re-creation of a replica
+type ReplicaRecloneStatus struct {
+	// The name of the instance that has been re-created
+	InstanceName string `json:"instanceName"`
+
+	// When the instance has been re-created, stored as a date in RFC3339 format
+	Time string `json:"time"`
+
+	// Why the instance has been re-created
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
+const DefaultReplicationSlotsUpdateInterval = 30
+
+// DefaultInactiveSlotsRetentionTime is the default in seconds for the time
+// a replication slot should be inactive before being reported as leaked
+const DefaultInactiveSlotsRetentionTime = 3600
+
+// DefaultReplicationSlotsHASlotPrefix is the default prefix for names of replication slots used for HA.
+const DefaultReplicationSlotsHASlotPrefix = "_cnpg_"
+
+// SynchronizeReplicasConfiguration contains the configuration for the synchronization of user defined
+// physical replication slots
+type SynchronizeReplicasConfiguration struct {
+	// When set to true, every replication slot that is on the primary is synchronized on each standby
+	// +kubebuilder:default:=true
+	Enabled *bool `json:"enabled"`
+
+	// List of regular expression patterns to match the names of replication slots to be excluded (by default empty)
+	// +optional
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+
+	synchronizeReplicasCache `json:"-"`
 }
 
 // synchronizeReplicasCache contains the result of the regex compilation
@@ -1174,6 +2523,12 @@ type ReplicationSlotsConfiguration struct {
 	// Configures the synchronization of the user defined physical replication slots
 	// +optional
 	SynchronizeReplicas *SynchronizeReplicasConfiguration `json:"synchronizeReplicas,omitempty"`
+
+	// Configures the detection, and optionally the removal, of the
+	// replication slots that are inactive on the primary, which may
+	// retain WAL files indefinitely
+	// +optional
+	InactiveSlots *InactiveSlotsConfiguration `json:"inactiveSlots,omitempty"`
 }
 
 // GetEnabled returns false if replication slots are disabled, default is true
@@ -1184,11 +2539,82 @@ func (r *ReplicationSlotsConfiguration) GetEnabled() bool {
 // GetUpdateInterval returns the update interval, defaulting to DefaultReplicationSlotsUpdateInterval if empty
 func (r *ReplicationSlotsConfiguration) GetUpdateInterval() time.Duration {
 	if r == nil || r.UpdateInterval <= 0 {
-		return DefaultReplicationSlotsUpdateInterval
+		return DefaultReplicationSlotsUpdateInterval * time.Second
 	}
 	return time.Duration(r.UpdateInterval) * time.Second
 }
 
+// GetInactiveSlots returns the configuration of the inactive replication
+// slots detection, nil if not specified
+func (r *ReplicationSlotsConfiguration) GetInactiveSlots() *InactiveSlotsConfiguration {
+	if r == nil {
+		return nil
+	}
+	return r.InactiveSlots
+}
+
+// InactiveSlotsConfiguration contains the configuration of the detection of
+// the replication slots that are inactive on the primary, like the ones
+// left behind by removed standbys or by logical replication consumers that
+// are not running anymore. Such slots prevent PostgreSQL from recycling
+// WAL files, that may end up filling the volume.
+// Replication slots for High Availability are managed by the operator and
+// are never considered.
+type InactiveSlotsConfiguration struct {
+	// The time in seconds a replication slot should be inactive before
+	// being reported as leaked (default 3600)
+	// +kubebuilder:default:=3600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RetentionTime int `json:"retentionTime,omitempty"`
+
+	// When true, the replication slots that have been inactive for more
+	// than the retention time are dropped (by default false, meaning that
+	// they are only reported)
+	// +optional
+	Drop bool `json:"drop,omitempty"`
+
+	// List of regular expression patterns to match the names of
+	// replication slots to be ignored (by default empty)
+	// +optional
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+}
+
+// GetRetentionTime returns the time a replication slot should be inactive
+// before being reported, defaulting to DefaultInactiveSlotsRetentionTime
+func (r *InactiveSlotsConfiguration) GetRetentionTime() time.Duration {
+	if r == nil || r.RetentionTime <= 0 {
+		return DefaultInactiveSlotsRetentionTime * time.Second
+	}
+	return time.Duration(r.RetentionTime) * time.Second
+}
+
+// GetDrop returns true if the leaked replication slots should be dropped
+func (r *InactiveSlotsConfiguration) GetDrop() bool {
+	return r != nil && r.Drop
+}
+
+// IsExcluded returns true if the passed replication slot should be
+// ignored, as it matches one of the exclude patterns
+func (r *InactiveSlotsConfiguration) IsExcluded(slotName string) (bool, error) {
+	if r == nil {
+		return false, nil
+	}
+
+	for _, pattern := range r.ExcludePatterns {
+		// The patterns are validated by the webhook
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		if re.MatchString(slotName) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // ReplicationSlotsHAConfiguration encapsulates the configuration
 // of the replication slots that are automatically managed by
 // the operator to control the streaming replication connections
@@ -1268,22 +2694,195 @@ const (
 	KubernetesUpgradeStrategyWaitForNode = "waitForNode"
 )
 
-// NodeMaintenanceWindow contains information that the operator
-// will use while upgrading the underlying node.
-//
-// This option is only useful when the chosen storage prevents the Pods
-// from being freely moved across nodes.
-type NodeMaintenanceWindow struct {
-	// Reuse the existing PVC (wait for the node to come
-	// up again) or not (recreate it elsewhere - when `instances` >1)
+// NodeMaintenanceWindow contains information that the operator
+// will use while upgrading the underlying node.
+//
+// This option is only useful when the chosen storage prevents the Pods
+// from being freely moved across nodes.
+type NodeMaintenanceWindow struct {
+	// Reuse the existing PVC (wait for the node to come
+	// up again) or not (recreate it elsewhere - when `instances` >1)
+	// +optional
+	// +kubebuilder:default:=true
+	ReusePVC *bool `json:"reusePVC,omitempty"`
+
+	// Is there a node maintenance activity in progress?
+	// +optional
+	// +kubebuilder:default:=false
+	InProgress bool `json:"inProgress,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time during which the
+// operator can perform disruptive operations on the cluster
+type MaintenanceWindow struct {
+	// The schedule of the beginning of the window, in UTC. It follows the
+	// same format of the scheduled backups, which has a leading field
+	// for the seconds,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The duration of the window
+	Duration metav1.Duration `json:"duration"`
+}
+
+// MaintenanceTaskType is the operation executed by a database
+// maintenance task
+type MaintenanceTaskType string
+
+const (
+	// MaintenanceTaskVacuum runs `vacuumdb`
+	MaintenanceTaskVacuum MaintenanceTaskType = "vacuum"
+
+	// MaintenanceTaskAnalyze runs `vacuumdb --analyze-only`
+	MaintenanceTaskAnalyze MaintenanceTaskType = "analyze"
+
+	// MaintenanceTaskVacuumAnalyze runs `vacuumdb --analyze`
+	MaintenanceTaskVacuumAnalyze MaintenanceTaskType = "vacuumAnalyze"
+
+	// MaintenanceTaskReindex runs `reindexdb --concurrently`
+	MaintenanceTaskReindex MaintenanceTaskType = "reindex"
+)
+
+// MaintenanceTaskResult is the outcome of a run of a database
+// maintenance task
+type MaintenanceTaskResult string
+
+const (
+	// MaintenanceTaskSucceeded means that the task completed successfully
+	MaintenanceTaskSucceeded MaintenanceTaskResult = "Succeeded"
+
+	// MaintenanceTaskFailed means that the task failed on at least
+	// one database
+	MaintenanceTaskFailed MaintenanceTaskResult = "Failed"
+
+	// MaintenanceTaskSkipped means that the task has not been executed
+	// because the primary instance was too busy
+	MaintenanceTaskSkipped MaintenanceTaskResult = "Skipped"
+)
+
+// MaxBackupCatalogEntries is the number of the most recent base
+// backups reported in the backup catalog of the cluster status
+const MaxBackupCatalogEntries = 50
+
+// MaxMaintenanceHistory is the number of runs of the database
+// maintenance tasks kept in the cluster status
+const MaxMaintenanceHistory = 20
+
+// DatabaseMaintenanceConfiguration contains the database maintenance
+// tasks scheduled on the primary instance
+type DatabaseMaintenanceConfiguration struct {
+	// The scheduled maintenance tasks
+	// +optional
+	Tasks []MaintenanceTask `json:"tasks,omitempty"`
+
+	// The number of active sessions on the primary above which the
+	// scheduled tasks are skipped, as the instance is considered under
+	// high load. Zero means that the tasks are never skipped.
+	// Default: 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxActiveSessions int32 `json:"maxActiveSessions,omitempty"`
+}
+
+// GetTasks gets the scheduled maintenance tasks
+func (configuration *DatabaseMaintenanceConfiguration) GetTasks() []MaintenanceTask {
+	if configuration == nil {
+		return nil
+	}
+	return configuration.Tasks
+}
+
+// GetMaxActiveSessions gets the number of active sessions above which
+// the maintenance tasks are skipped, zero if they are never skipped
+func (configuration *DatabaseMaintenanceConfiguration) GetMaxActiveSessions() int32 {
+	if configuration == nil {
+		return 0
+	}
+	return configuration.MaxActiveSessions
+}
+
+// MaintenanceTask is a database maintenance operation executed according
+// to a schedule
+type MaintenanceTask struct {
+	// The name of the task, unique in the cluster
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// The operation to execute
+	// +kubebuilder:validation:Enum=vacuum;analyze;vacuumAnalyze;reindex
+	Type MaintenanceTaskType `json:"type"`
+
+	// The schedule of the task, in UTC. It follows the same format of the
+	// scheduled backups, which has a leading field for the seconds,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The databases where the task is executed. If empty, the task is
+	// executed in every database accepting connections
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// The number of concurrent connections used to process each database.
+	// Default: 1.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Jobs int32 `json:"jobs,omitempty"`
+}
+
+// GetJobs gets the number of concurrent connections used by the task
+func (task MaintenanceTask) GetJobs() int32 {
+	if task.Jobs < 1 {
+		return 1
+	}
+	return task.Jobs
+}
+
+// MaintenanceTaskRun is a run of a database maintenance task
+type MaintenanceTaskRun struct {
+	// The name of the task
+	Name string `json:"name"`
+
+	// The operation executed by the task
+	Type MaintenanceTaskType `json:"type"`
+
+	// The instance where the task has been executed
+	InstanceName string `json:"instanceName"`
+
+	// When the run has been started, stored as a date in RFC3339 format
+	StartedAt string `json:"startedAt"`
+
+	// The time in seconds taken by the run
+	// +optional
+	Duration int32 `json:"duration,omitempty"`
+
+	// The outcome of the run
+	Result MaintenanceTaskResult `json:"result"`
+
+	// The details of the outcome, like the error raised or the reason
+	// why the task has been skipped
 	// +optional
-	// +kubebuilder:default:=true
-	ReusePVC *bool `json:"reusePVC,omitempty"`
+	Message string `json:"message,omitempty"`
+}
 
-	// Is there a node maintenance activity in progress?
+// ReplicaSurgeStatus describes the replacement of an outdated replica with
+// a new one, during a rolling update using the `surge` method
+type ReplicaSurgeStatus struct {
+	// The outdated replica, removed as soon as the new one is streaming
+	// from the primary
+	OutdatedInstance string `json:"outdatedInstance"`
+
+	// The new replica, created to replace the outdated one
+	NewInstance string `json:"newInstance"`
+
+	// Why the outdated replica needs to be replaced
 	// +optional
-	// +kubebuilder:default:=false
-	InProgress bool `json:"inProgress,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	// When the replacement started, stored as a date in RFC3339 format
+	StartedAt string `json:"startedAt"`
 }
 
 // PrimaryUpdateStrategy contains the strategy to follow when upgrading
@@ -1294,6 +2893,10 @@ type PrimaryUpdateStrategy string
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateMethod string
 
+// ReplicaUpdateMethod contains the method to use when upgrading
+// the replicas of the cluster as part of rolling updates
+type ReplicaUpdateMethod string
+
 const (
 	// PrimaryUpdateStrategySupervised means that the operator need to wait for the
 	// user to manually issue a switchover request before updating the primary
@@ -1313,6 +2916,15 @@ const (
 	// when it needs to upgrade it
 	PrimaryUpdateMethodRestart PrimaryUpdateMethod = "restart"
 
+	// ReplicaUpdateMethodRestart means that the operator will restart or
+	// re-create the replicas in-place when it needs to upgrade them
+	ReplicaUpdateMethodRestart ReplicaUpdateMethod = "restart"
+
+	// ReplicaUpdateMethodSurge means that the operator will replace each
+	// replica it needs to upgrade with a new one, removing the outdated
+	// replica only after the new one is streaming from the primary
+	ReplicaUpdateMethodSurge ReplicaUpdateMethod = "surge"
+
 	// DefaultPgCtlTimeoutForPromotion is the default for the pg_ctl timeout when a promotion is performed.
 	// It is greater than one year in seconds, big enough to simulate an infinite timeout
 	DefaultPgCtlTimeoutForPromotion = 40000000
@@ -1322,6 +2934,10 @@ const (
 	// It is greater than one year in seconds, big enough to simulate an infinite timeout
 	DefaultMaxSwitchoverDelay = 3600
 
+	// DefaultReplicaSurgeTimeout is the default time in seconds allowed for
+	// the new replica created by the `surge` update method to catch up
+	DefaultReplicaSurgeTimeout = 3600
+
 	// DefaultStartupDelay is the default value for startupDelay, startupDelay will be used to calculate the
 	// FailureThreshold of startupProbe, the formula is `FailureThreshold = ceiling(startDelay / periodSeconds)`,
 	// the minimum value is 1
@@ -1334,11 +2950,38 @@ type PostgresConfiguration struct {
 	// +optional
 	Parameters map[string]string `json:"parameters,omitempty"`
 
+	// The kind of workload the instances are tuned for, either `oltp`,
+	// `analytics` or `mixed`. When set, the operator computes the default
+	// values of the memory, parallelism, checkpoint, background writer,
+	// autovacuum and standby delay parameters from the resources assigned
+	// to the pods and from the size of the WAL volume. The values in
+	// `parameters` take precedence over the computed ones.
+	// By default, no tuning is applied
+	// +kubebuilder:validation:Enum=oltp;analytics;mixed
+	// +optional
+	WorkloadType WorkloadType `json:"workloadType,omitempty"`
+
+	// PostgreSQL configuration options applied on top of `parameters` only
+	// by the instances running as standby, such as `hot_standby_feedback`
+	// or `max_standby_streaming_delay`. They are removed from an instance
+	// when it is promoted, and applied again when it is demoted.
+	// Parameters requiring a restart are better kept out of this section,
+	// as a switchover would cause the new primary to be restarted
+	// +optional
+	ReplicaParameters map[string]string `json:"replicaParameters,omitempty"`
+
 	// PostgreSQL Host Based Authentication rules (lines to be appended
-	// to the pg_hba.conf file)
+	// to the pg_hba.conf file). Deprecated in favor of `pg_hba_rules`,
+	// the lines are added after the rules defined there
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// PostgreSQL Host Based Authentication rules, validated by the operator
+	// and added to the pg_hba.conf file in the given order, after the
+	// rules required by the operator
+	// +optional
+	PgHBARules []PgHBARule `json:"pg_hba_rules,omitempty"`
+
 	// PostgreSQL User Name Maps rules (lines to be appended
 	// to the pg_ident.conf file)
 	// +optional
@@ -1357,6 +3000,15 @@ type PostgresConfiguration struct {
 	// +optional
 	LDAP *LDAPConfig `json:"ldap,omitempty"`
 
+	// Options to enable the GSSAPI (Kerberos) authentication
+	// +optional
+	GSSAPI *GSSAPIConfiguration `json:"gssapi,omitempty"`
+
+	// Options to migrate the passwords from MD5 to SCRAM-SHA-256
+	// and to restrict the password authentication to SCRAM-SHA-256
+	// +optional
+	PasswordAuthentication *PasswordAuthenticationConfiguration `json:"passwordAuthentication,omitempty"`
+
 	// Specifies the maximum number of seconds to wait when promoting an instance to primary.
 	// Default value is 40000000, greater than one year in seconds,
 	// big enough to simulate an infinite timeout
@@ -1369,6 +3021,304 @@ type PostgresConfiguration struct {
 	// Defaults to false.
 	// +optional
 	EnableAlterSystem bool `json:"enableAlterSystem,omitempty"`
+
+	// Options to enable the transparent data encryption (TDE) of the
+	// data at rest, for the PostgreSQL builds supporting it
+	// +optional
+	TDE *TDEConfiguration `json:"tde,omitempty"`
+
+	// Options to enable and configure the `pgaudit` extension, and to
+	// route the audit records it generates
+	// +optional
+	PgAudit *PgAuditConfiguration `json:"pgaudit,omitempty"`
+
+	// The list of extensions the instance manager installs, and keeps
+	// at the requested version, in the databases of the cluster
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Extensions []ExtensionConfiguration `json:"extensions,omitempty"`
+}
+
+// WorkloadType is the kind of workload the instances are tuned for
+type WorkloadType string
+
+const (
+	// WorkloadTypeOLTP tunes the instances for many short transactions
+	WorkloadTypeOLTP WorkloadType = postgres.WorkloadTypeOLTP
+
+	// WorkloadTypeAnalytics tunes the instances for few long-running
+	// queries scanning large amounts of data
+	WorkloadTypeAnalytics WorkloadType = postgres.WorkloadTypeAnalytics
+
+	// WorkloadTypeMixed tunes the instances for a mix of short
+	// transactions and reporting queries
+	WorkloadTypeMixed WorkloadType = postgres.WorkloadTypeMixed
+)
+
+// ExtensionState reports the state of a declared extension in a database
+type ExtensionState struct {
+	// The name of the extension
+	Name string `json:"name"`
+
+	// The database where the extension is installed
+	Database string `json:"database"`
+
+	// The installed version of the extension, empty if the extension
+	// is not installed
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The error raised while reconciling the extension, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ExtensionConfiguration describes an extension to be installed in some
+// databases of the cluster
+type ExtensionConfiguration struct {
+	// The name of the extension, as found in `pg_available_extensions`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The version of the extension. When not set, the extension is kept at
+	// the default version shipped with the operand image, and it is updated
+	// when an image with a newer default version is used
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema where the objects of the extension are created. This is
+	// only used when the extension is created
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The databases where the extension is installed. When not set, the
+	// extension is installed in the application database, or in the
+	// `postgres` database if the cluster has none
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+}
+
+// GetDatabases returns the databases where the extension should be installed,
+// given the name of the application database of the cluster
+func (ext ExtensionConfiguration) GetDatabases(applicationDatabase string) []string {
+	if len(ext.Databases) > 0 {
+		return ext.Databases
+	}
+	if applicationDatabase != "" {
+		return []string{applicationDatabase}
+	}
+	return []string{"postgres"}
+}
+
+// PgAuditOutput is the output where the instance manager emits the
+// audit records generated by `pgaudit`
+// +kubebuilder:validation:Enum=combined;dedicated
+type PgAuditOutput string
+
+const (
+	// PgAuditOutputCombined means that the audit records are emitted
+	// together with the rest of the instance manager logs
+	PgAuditOutputCombined PgAuditOutput = "combined"
+
+	// PgAuditOutputDedicated means that the audit records are emitted
+	// on the standard output of the container, while the rest of the
+	// instance manager logs stays on the standard error
+	PgAuditOutputDedicated PgAuditOutput = "dedicated"
+)
+
+// DefaultPgAuditLogClasses are the classes of statements logged by
+// the session audit logging when none is specified
+var DefaultPgAuditLogClasses = []string{"ddl", "role"}
+
+// PgAuditConfiguration contains the configuration of the `pgaudit`
+// extension. The operator translates it into the corresponding `pgaudit.*`
+// parameters, which take precedence over the ones in `parameters`
+type PgAuditConfiguration struct {
+	// When enabled, the operator loads `pgaudit` and creates the extension
+	// in every database.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The classes of statements logged by the session audit logging,
+	// as in `pgaudit.log`. A class can be excluded by prefixing it with `-`.
+	// Default: [ddl, role].
+	// +kubebuilder:default:={ddl,role}
+	// +kubebuilder:validation:items:Pattern=`^-?(read|write|function|role|ddl|misc|misc_set|all|none)$`
+	// +optional
+	Log []string `json:"log,omitempty"`
+
+	// Log statements where all the relations are in `pg_catalog`,
+	// as in `pgaudit.log_catalog`.
+	// Default: true.
+	// +optional
+	LogCatalog *bool `json:"logCatalog,omitempty"`
+
+	// Include the parameters passed with the statement,
+	// as in `pgaudit.log_parameter`
+	// +optional
+	LogParameter bool `json:"logParameter,omitempty"`
+
+	// Create a separate log entry for each relation referenced in a
+	// `SELECT` or DML statement, as in `pgaudit.log_relation`
+	// +optional
+	LogRelation bool `json:"logRelation,omitempty"`
+
+	// Log the statement text and parameters only with the first log entry
+	// of a statement or sub-statement, as in `pgaudit.log_statement_once`
+	// +optional
+	LogStatementOnce bool `json:"logStatementOnce,omitempty"`
+
+	// The role used by the object audit logging, as in `pgaudit.role`
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// Where the instance manager emits the audit records: `combined`
+	// with the rest of the logs or on a `dedicated` output, to be
+	// collected separately by a SIEM. Audit records are always tagged
+	// with the `pgaudit` logger.
+	// Default: combined.
+	// +kubebuilder:default:=combined
+	// +optional
+	Output PgAuditOutput `json:"output,omitempty"`
+}
+
+// IsPgAuditEnabled checks whether the `pgaudit` stanza is enabled
+func (p *PostgresConfiguration) IsPgAuditEnabled() bool {
+	return p != nil && p.PgAudit != nil && p.PgAudit.Enabled
+}
+
+// GetParameters gets the PostgreSQL parameters to be applied, which are
+// the ones in `parameters` together with the ones derived from the
+// `pgaudit` stanza, taking precedence. The specification is not changed
+func (p *PostgresConfiguration) GetParameters() map[string]string {
+	if !p.IsPgAuditEnabled() {
+		return p.Parameters
+	}
+
+	parameters := make(map[string]string, len(p.Parameters)+6)
+	maps.Copy(parameters, p.Parameters)
+
+	pgAudit := p.PgAudit
+	classes := pgAudit.Log
+	if len(classes) == 0 {
+		classes = DefaultPgAuditLogClasses
+	}
+	parameters[postgres.ParameterPgAuditLog] = strings.Join(classes, ", ")
+	parameters[postgres.ParameterPgAuditLogCatalog] = boolToOnOff(ptr.Deref(pgAudit.LogCatalog, true))
+	parameters[postgres.ParameterPgAuditLogParameter] = boolToOnOff(pgAudit.LogParameter)
+	parameters[postgres.ParameterPgAuditLogRelation] = boolToOnOff(pgAudit.LogRelation)
+	parameters[postgres.ParameterPgAuditLogStatementOnce] = boolToOnOff(pgAudit.LogStatementOnce)
+	if pgAudit.Role != "" {
+		parameters[postgres.ParameterPgAuditRole] = pgAudit.Role
+	}
+
+	return parameters
+}
+
+// GetPostgresParameters gets the PostgreSQL parameters to be applied to the
+// instances. Together with the ones returned by the `postgresql` stanza,
+// `pg_stat_statements.track` is set to `top` when the query statistics are
+// enabled and no `pg_stat_statements` parameter is already present, so that
+// the extension is managed by the operator only as long as it's needed.
+// The specification is not changed
+func (cluster *Cluster) GetPostgresParameters() map[string]string {
+	parameters := cluster.Spec.PostgresConfiguration.GetParameters()
+	if !cluster.Spec.Monitoring.IsQueryStatisticsEnabled() {
+		return parameters
+	}
+
+	for name := range parameters {
+		if strings.HasPrefix(name, "pg_stat_statements.") {
+			return parameters
+		}
+	}
+
+	result := make(map[string]string, len(parameters)+1)
+	maps.Copy(result, parameters)
+	result[postgres.ParameterPgStatStatementsTrack] = "top"
+	return result
+}
+
+// boolToOnOff converts a boolean into the corresponding PostgreSQL
+// configuration value
+func boolToOnOff(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
+
+// GetPgAuditOutput gets the output where the audit records are emitted
+func (p *PostgresConfiguration) GetPgAuditOutput() PgAuditOutput {
+	if !p.IsPgAuditEnabled() || p.PgAudit.Output == "" {
+		return PgAuditOutputCombined
+	}
+	return p.PgAudit.Output
+}
+
+// TDEConfiguration contains the parameters of the transparent data
+// encryption (TDE), for the PostgreSQL builds supporting it through
+// a data encryption key wrapped by a passphrase
+type TDEConfiguration struct {
+	// True if we want the data at rest to be encrypted. This can only
+	// be set when the cluster is created
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Reference to the secret containing the passphrase used to wrap
+	// and unwrap the data encryption key. The passphrase is made available
+	// to PostgreSQL through the `PGPASSPHRASE` environment variable.
+	// When the content of the secret changes, each instance wraps its
+	// data encryption key again with the new passphrase
+	// +optional
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// The shell command used to wrap the data encryption key, which
+	// is read from the standard input. `%p` is replaced by the path of
+	// the wrapped key file. Defaults to an OpenSSL command using the
+	// passphrase
+	// +optional
+	WrapCommand string `json:"wrapCommand,omitempty"`
+
+	// The shell command used to unwrap the data encryption key, which
+	// must be written to the standard output. `%p` is replaced by the
+	// path of the wrapped key file. Defaults to an OpenSSL command using
+	// the passphrase
+	// +optional
+	UnwrapCommand string `json:"unwrapCommand,omitempty"`
+}
+
+const (
+	// TDEPassphraseEnvVar is the environment variable containing
+	// the passphrase used to wrap the data encryption key
+	TDEPassphraseEnvVar = "PGPASSPHRASE"
+
+	// DefaultTDEWrapCommand is the default command used to wrap
+	// the data encryption key
+	DefaultTDEWrapCommand = `openssl enc -e -aes-256-cbc -pbkdf2 -pass env:PGPASSPHRASE -out "%p"`
+
+	// DefaultTDEUnwrapCommand is the default command used to unwrap
+	// the data encryption key
+	DefaultTDEUnwrapCommand = `openssl enc -d -aes-256-cbc -pbkdf2 -pass env:PGPASSPHRASE -in "%p"`
+)
+
+// GetWrapCommand gets the command used to wrap the data encryption key
+func (tde *TDEConfiguration) GetWrapCommand() string {
+	if tde == nil || tde.WrapCommand == "" {
+		return DefaultTDEWrapCommand
+	}
+	return tde.WrapCommand
+}
+
+// GetUnwrapCommand gets the command used to unwrap the data encryption key
+func (tde *TDEConfiguration) GetUnwrapCommand() string {
+	if tde == nil || tde.UnwrapCommand == "" {
+		return DefaultTDEUnwrapCommand
+	}
+	return tde.UnwrapCommand
 }
 
 // BootstrapConfiguration contains information about how to create the PostgreSQL
@@ -1391,6 +3341,73 @@ type BootstrapConfiguration struct {
 	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
 }
 
+// PgHBARuleType is the type of connection matched by a pg_hba rule
+// +kubebuilder:validation:Enum=local;host;hostssl;hostnossl;hostgssenc;hostnogssenc
+type PgHBARuleType string
+
+// These are the valid types of pg_hba rules
+const (
+	PgHBARuleTypeLocal        PgHBARuleType = "local"
+	PgHBARuleTypeHost         PgHBARuleType = "host"
+	PgHBARuleTypeHostSSL      PgHBARuleType = "hostssl"
+	PgHBARuleTypeHostNoSSL    PgHBARuleType = "hostnossl"
+	PgHBARuleTypeHostGSSEnc   PgHBARuleType = "hostgssenc"
+	PgHBARuleTypeHostNoGSSEnc PgHBARuleType = "hostnogssenc"
+)
+
+// PgHBAMethod is the authentication method used by a pg_hba rule
+// +kubebuilder:validation:Enum=trust;reject;scram-sha-256;md5;password;gss;sspi;ident;peer;pam;ldap;radius;cert
+type PgHBAMethod string
+
+// These are the valid authentication methods of pg_hba rules
+const (
+	PgHBAMethodTrust       PgHBAMethod = "trust"
+	PgHBAMethodReject      PgHBAMethod = "reject"
+	PgHBAMethodScramSHA256 PgHBAMethod = "scram-sha-256"
+	PgHBAMethodMD5         PgHBAMethod = "md5"
+	PgHBAMethodPassword    PgHBAMethod = "password"
+	PgHBAMethodGSS         PgHBAMethod = "gss"
+	PgHBAMethodSSPI        PgHBAMethod = "sspi"
+	PgHBAMethodIdent       PgHBAMethod = "ident"
+	PgHBAMethodPeer        PgHBAMethod = "peer"
+	PgHBAMethodPAM         PgHBAMethod = "pam"
+	PgHBAMethodLDAP        PgHBAMethod = "ldap"
+	PgHBAMethodRadius      PgHBAMethod = "radius"
+	PgHBAMethodCert        PgHBAMethod = "cert"
+)
+
+// PgHBARule is a PostgreSQL Host Based Authentication rule
+type PgHBARule struct {
+	// The type of connection matched by the rule
+	Type PgHBARuleType `json:"type"`
+
+	// The database names matched by the rule, separated by commas.
+	// Defaults to `all`
+	// +kubebuilder:default:=all
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The user names matched by the rule, separated by commas.
+	// Defaults to `all`
+	// +kubebuilder:default:=all
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// The client addresses matched by the rule, as a CIDR, a host name
+	// or one of the `all`, `samehost` and `samenet` keywords.
+	// Required unless the type is `local`, where it is not allowed
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// The authentication method used when the rule matches
+	Method PgHBAMethod `json:"method"`
+
+	// The options of the authentication method, rendered in
+	// alphabetical order
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
 // LDAPScheme defines the possible schemes for LDAP
 type LDAPScheme string
 
@@ -1459,6 +3476,130 @@ type LDAPBindSearchAuth struct {
 	SearchFilter string `json:"searchFilter,omitempty"`
 }
 
+// SecretsStoreConfiguration contains the passwords mounted in the
+// instance pods by the Secrets Store CSI driver. The instance manager of
+// the primary applies them to the PostgreSQL users whenever the mounted
+// files change, as happens when the provider rotates them
+type SecretsStoreConfiguration struct {
+	// The name of the SecretProviderClass, in the namespace of the
+	// cluster, defining the objects to be mounted
+	// +kubebuilder:validation:MinLength=1
+	SecretProviderClass string `json:"secretProviderClass"`
+
+	// The name of the mounted file containing the password of the
+	// `postgres` superuser. When set, it takes precedence over the
+	// superuser secret. It's only used when `enableSuperuserAccess`
+	// is true
+	// +optional
+	SuperuserPasswordFile string `json:"superuserPasswordFile,omitempty"`
+
+	// The name of the mounted file containing the password of the
+	// owner of the application database. When set, it takes precedence
+	// over the application secret
+	// +optional
+	ApplicationPasswordFile string `json:"applicationPasswordFile,omitempty"`
+}
+
+const (
+	// SecretsStoreCSIDriver is the name of the Secrets Store CSI driver
+	SecretsStoreCSIDriver = "secrets-store.csi.k8s.io"
+
+	// SecretsStoreVolumeName is the name of the volume mounted by the
+	// Secrets Store CSI driver
+	SecretsStoreVolumeName = "secrets-store"
+
+	// SecretsStoreMountPath is where the volume mounted by the Secrets
+	// Store CSI driver is available in the PostgreSQL container
+	SecretsStoreMountPath = "/etc/secrets-store"
+)
+
+// PasswordAuthenticationConfiguration controls how the passwords of the
+// roles are stored and checked
+type PasswordAuthenticationConfiguration struct {
+	// Migrate the passwords from MD5 to SCRAM-SHA-256. The
+	// `password_encryption` parameter is set to `scram-sha-256`, so that
+	// every password set from now on is hashed with SCRAM, and the
+	// passwords of the superuser, of the application owner and of the
+	// managed roles still stored as MD5 hashes are set again from their
+	// secrets
+	// +optional
+	MigrateMD5 bool `json:"migrateMD5,omitempty"`
+
+	// Only accept the password authentication through SCRAM-SHA-256 over
+	// TLS, where the clients can require channel binding. The default
+	// rule of `pg_hba.conf` becomes `hostssl all all all scram-sha-256`,
+	// and `password_encryption` is set to `scram-sha-256`
+	// +optional
+	RequireChannelBinding bool `json:"requireChannelBinding,omitempty"`
+}
+
+// GSSAPIConfiguration contains the parameters of the GSSAPI (Kerberos)
+// authentication
+type GSSAPIConfiguration struct {
+	// Reference to the secret containing the keytab of the PostgreSQL
+	// service principal. The keytab is mounted in the instance pods and
+	// used as `krb_server_keyfile`
+	Keytab SecretKeySelector `json:"keytab"`
+
+	// The realm of the users allowed to authenticate. Users from
+	// other realms are rejected. If empty, every realm is accepted
+	// +optional
+	Realm string `json:"realm,omitempty"`
+
+	// The network addresses the rule applies to, in the form accepted
+	// by the `address` field of `pg_hba.conf`. As the rule precedes the
+	// default rules, the clients connecting from these addresses can
+	// only authenticate through GSSAPI
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+
+	// Whether to require GSSAPI encryption for the connections
+	// authenticated with Kerberos, using a `hostgssenc` rule
+	// +optional
+	RequireEncryption bool `json:"requireEncryption,omitempty"`
+
+	// The mappings between the Kerberos principals, including their
+	// realm, and the PostgreSQL users. When empty, the principal
+	// without the realm must match the PostgreSQL user
+	// +optional
+	UserMappings []GSSAPIUserMapping `json:"userMappings,omitempty"`
+}
+
+// GSSAPIUserMapping maps a Kerberos principal to a PostgreSQL user
+type GSSAPIUserMapping struct {
+	// The Kerberos principal, including the realm. A value starting
+	// with a slash is a regular expression, as in `pg_ident.conf`
+	// +kubebuilder:validation:MinLength=1
+	Principal string `json:"principal"`
+
+	// The PostgreSQL user the principal is allowed to log in as
+	// +kubebuilder:validation:MinLength=1
+	User string `json:"user"`
+}
+
+const (
+	// GSSAPIIdentMapName is the name of the pg_ident.conf map holding the
+	// user mappings of the GSSAPI authentication
+	GSSAPIIdentMapName = "gssapi"
+
+	// GSSAPIKeytabVolumeName is the name of the volume containing the
+	// keytab of the GSSAPI authentication
+	GSSAPIKeytabVolumeName = "gssapi-keytab"
+
+	// GSSAPIKeytabMountPath is where the volume containing the keytab
+	// of the GSSAPI authentication is mounted
+	GSSAPIKeytabMountPath = "/etc/gssapi-keytab"
+
+	// GSSAPIKeytabFileName is the name of the keytab file of the
+	// GSSAPI authentication
+	GSSAPIKeytabFileName = "keytab"
+)
+
+// GetKeytabPath gets the path where the keytab is mounted in the instance pods
+func (configuration *GSSAPIConfiguration) GetKeytabPath() string {
+	return GSSAPIKeytabMountPath + "/" + GSSAPIKeytabFileName
+}
+
 // CertificatesConfiguration contains the needed configurations to handle server certificates.
 type CertificatesConfiguration struct {
 	// The secret containing the Server CA certificate. If not defined, a new secret will be created
@@ -1497,21 +3638,148 @@ type CertificatesConfiguration struct {
 	// - `ca.key`: key used to generate client certificates, if ReplicationTLSSecret is provided,
 	// this can be omitted.<br />
 	// +optional
-	ClientCASecret string `json:"clientCASecret,omitempty"`
+	ClientCASecret string `json:"clientCASecret,omitempty"`
+
+	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
+	// +optional
+	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
+
+	// The lifetime of the certificates generated by the operator for
+	// this cluster, and when they are renewed. By default, the
+	// `CERTIFICATE_DURATION` and `EXPIRING_CHECK_THRESHOLD` settings
+	// of the operator apply
+	// +optional
+	Rotation *CertificatesRotationConfiguration `json:"rotation,omitempty"`
+
+	// Delegate the server certificate of the cluster to cert-manager:
+	// instead of signing it with its own CA, the operator creates a
+	// cert-manager Certificate referring to the given issuer. The client
+	// certificates are still signed by the CA of the cluster, so that
+	// only the clients of this cluster can authenticate with it
+	// +optional
+	CertManager *CertManagerConfiguration `json:"certManager,omitempty"`
+}
+
+// CertManagerConfiguration contains the settings used to issue the
+// certificates of the cluster through cert-manager
+type CertManagerConfiguration struct {
+	// The issuer signing the certificates of the cluster
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+}
+
+// CertManagerIssuerReference is a reference to a cert-manager issuer
+type CertManagerIssuerReference struct {
+	// The name of the issuer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The kind of the issuer, `Issuer` by default. `ClusterIssuer`
+	// refers to a cluster-wide issuer
+	// +kubebuilder:default:=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// The API group of the issuer, `cert-manager.io` by default
+	// +kubebuilder:default:=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// CertificatesRotationConfiguration defines the lifetime of the
+// certificates generated by the operator and their renewal window
+type CertificatesRotationConfiguration struct {
+	// The lifetime, in days, of the CA certificates generated by the
+	// operator
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CADurationDays int `json:"caDurationDays,omitempty"`
+
+	// The lifetime, in days, of the server and client certificates
+	// generated by the operator
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LeafDurationDays int `json:"leafDurationDays,omitempty"`
+
+	// How many days before their expiration the certificates are renewed.
+	// The renewed certificate is issued while the previous one is still
+	// valid, giving the clients this much time to trust it
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RenewBeforeDays int `json:"renewBeforeDays,omitempty"`
+}
+
+// CertificatesStatus contains configuration certificates and related expiration dates.
+type CertificatesStatus struct {
+	// Needed configurations to handle server certificates, initialized with default values, if needed.
+	CertificatesConfiguration `json:",inline"`
+
+	// Expiration dates for all certificates.
+	// +optional
+	Expirations map[string]string `json:"expirations,omitempty"`
+}
+
+// RetentionPolicyEnforcementStatus is the outcome of the enforcement of the
+// backup retention policy on the object store
+type RetentionPolicyEnforcementStatus struct {
+	// When the retention policy was enforced, stored as a date in RFC3339 format
+	// +optional
+	Time string `json:"time,omitempty"`
+
+	// The retention policy that has been enforced
+	// +optional
+	RetentionPolicy string `json:"retentionPolicy,omitempty"`
+
+	// The IDs of the backups removed from the object store, among the
+	// ones having a Backup object
+	// +optional
+	RemovedBackups []string `json:"removedBackups,omitempty"`
+
+	// The error raised while enforcing the retention policy, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// BackupCatalogEntry describes a base backup available in the object store
+type BackupCatalogEntry struct {
+	// The ID of the backup, which can be used as `backupID` in a
+	// recovery target
+	ID string `json:"id"`
+
+	// The name of the backup, if any
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// The timeline of the instance when the backup was taken
+	// +optional
+	TimeLineID int `json:"timeLineID,omitempty"`
+
+	// When the backup started
+	// +optional
+	BeginTime *metav1.Time `json:"beginTime,omitempty"`
+
+	// When the backup ended
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
 
-	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
+	// The starting WAL
 	// +optional
-	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
-}
+	BeginWal string `json:"beginWal,omitempty"`
 
-// CertificatesStatus contains configuration certificates and related expiration dates.
-type CertificatesStatus struct {
-	// Needed configurations to handle server certificates, initialized with default values, if needed.
-	CertificatesConfiguration `json:",inline"`
+	// The ending WAL
+	// +optional
+	EndWal string `json:"endWal,omitempty"`
 
-	// Expiration dates for all certificates.
+	// The starting xlog
 	// +optional
-	Expirations map[string]string `json:"expirations,omitempty"`
+	BeginLSN string `json:"beginLSN,omitempty"`
+
+	// The ending xlog
+	// +optional
+	EndLSN string `json:"endLSN,omitempty"`
+
+	// The size of the backup in bytes, as reported by Barman
+	// +optional
+	Size int64 `json:"size,omitempty"`
 }
 
 // BootstrapInitDB is the configuration of the bootstrap process when
@@ -1633,6 +3901,21 @@ type Import struct {
 	// `pg_restore` are invoked, avoiding data import. Default: `false`.
 	// +optional
 	SchemaOnly bool `json:"schemaOnly,omitempty"`
+
+	// When set to true, the global objects of the source are exported
+	// with `pg_dumpall --globals-only` and applied in place of the role
+	// import, keeping the role settings and memberships and the ownership
+	// and privileges of the declared tablespaces. Only available in
+	// monolith type. Default: `false`.
+	// +optional
+	Globals bool `json:"globals,omitempty"`
+
+	// The number of parallel jobs used by `pg_dump` and `pg_restore` for
+	// each database. When greater than 1, the databases are exported in
+	// the directory format, which supports parallel dumps. Default: `1`.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Jobs int `json:"jobs,omitempty"`
 }
 
 // ImportSource describes the source for the logical snapshot
@@ -1689,6 +3972,15 @@ type BootstrapRecovery struct {
 	// +optional
 	VolumeSnapshots *DataSource `json:"volumeSnapshots,omitempty"`
 
+	// The existing PVC(s) whose content is adopted by the first instance of
+	// the cluster, like the ones left behind by a deleted cluster or
+	// provisioned from a restored snapshot. The PVCs of the instance are
+	// cloned from them, and the data directory is validated before
+	// starting PostgreSQL.
+	// Mutually exclusive with `backup`, `source` and `volumeSnapshots`.
+	// +optional
+	VolumeSource *VolumeSourceRecovery `json:"volumeSource,omitempty"`
+
 	// By default, the recovery process applies all the available
 	// WAL files in the archive (full recovery). However, you can also
 	// end the recovery as soon as a consistent state is reached or
@@ -1714,6 +4006,28 @@ type BootstrapRecovery struct {
 	Secret *LocalObjectReference `json:"secret,omitempty"`
 }
 
+// VolumeSourceRecovery contains the configuration required to bootstrap a
+// PostgreSQL cluster adopting the content of existing PVCs
+type VolumeSourceRecovery struct {
+	// The PVC containing the data directory
+	Storage LocalObjectReference `json:"storage"`
+
+	// The PVC containing the WAL files, when they are stored
+	// in a separate volume
+	// +optional
+	WalStorage *LocalObjectReference `json:"walStorage,omitempty"`
+
+	// The PVCs containing the tablespaces, by tablespace name
+	// +optional
+	TablespaceStorage map[string]LocalObjectReference `json:"tablespaceStorage,omitempty"`
+
+	// The system identifier of the PostgreSQL instance the data directory
+	// belongs to. When set, the bootstrap fails if the adopted data
+	// directory has a different one
+	// +optional
+	SystemID string `json:"systemID,omitempty"`
+}
+
 // DataSource contains the configuration required to bootstrap a
 // PostgreSQL cluster from an existing storage
 type DataSource struct {
@@ -1820,14 +4134,89 @@ type StorageConfiguration struct {
 	// +optional
 	Size string `json:"size,omitempty"`
 
-	// Resize existent PVCs, defaults to true
+	// Resize existent PVCs. Defaults to true in `storage`, while the other
+	// storage sections default to the value set in `storage`
 	// +optional
-	// +kubebuilder:default:=true
 	ResizeInUseVolumes *bool `json:"resizeInUseVolumes,omitempty"`
 
 	// Template to be used to generate the Persistent Volume Claim
 	// +optional
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
+
+	// The actions taken by the instance manager when the volume is about
+	// to be full. It can be specified only in the `walStorage` section
+	// +optional
+	EmergencyCleanup *StorageEmergencyCleanupConfiguration `json:"emergencyCleanup,omitempty"`
+}
+
+// DefaultStorageEmergencyCleanupThreshold is the default percentage of
+// used space triggering the emergency cleanup of a volume
+const DefaultStorageEmergencyCleanupThreshold = 90
+
+// StorageEmergencyCleanupConfiguration configures the actions taken by
+// the instance manager to free space in the WAL volume when it is about
+// to be full
+type StorageEmergencyCleanupConfiguration struct {
+	// When enabled, the instance manager checks the used space of the
+	// volume and, when the threshold is exceeded, requests a checkpoint
+	// to remove the WAL files not needed anymore.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The percentage of used space of the volume triggering the cleanup.
+	// Default: 90.
+	// +kubebuilder:default:=90
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	Threshold int `json:"threshold,omitempty"`
+
+	// When enabled, the primary also drops the inactive replication slots
+	// used for high availability, starting from the ones retaining the most
+	// WAL files, until the used space goes below the threshold. These slots
+	// are recreated by the operator, and their replicas resume streaming
+	// using the WAL archive. The slots of other consumers are never dropped.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	DropInactiveSlots bool `json:"dropInactiveSlots,omitempty"`
+
+	// The percentage of used space of the volume above which the primary
+	// throttles the connections, terminating the idle sessions of the
+	// applications. It must be lower than the threshold.
+	// When not set, the connections are never throttled.
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	ThrottleConnectionsThreshold int `json:"throttleConnectionsThreshold,omitempty"`
+
+	// The percentage of used space of the volume above which the primary
+	// fences the writes, turning the databases read-only until the used
+	// space goes below it. It must be higher than the threshold.
+	// When not set, the writes are never fenced.
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	FenceWritesThreshold int `json:"fenceWritesThreshold,omitempty"`
+}
+
+// GetThreshold gets the percentage of used space triggering the cleanup
+func (configuration *StorageEmergencyCleanupConfiguration) GetThreshold() int {
+	if configuration == nil || configuration.Threshold <= 0 {
+		return DefaultStorageEmergencyCleanupThreshold
+	}
+	return configuration.Threshold
+}
+
+// ShouldResizeInUseVolumes is true when the existing PVCs created from this
+// storage configuration should be resized
+func (s *StorageConfiguration) ShouldResizeInUseVolumes() bool {
+	if s == nil || s.ResizeInUseVolumes == nil {
+		return true
+	}
+	return *s.ResizeInUseVolumes
 }
 
 // GetSizeOrNil returns the requests storage size
@@ -1893,6 +4282,73 @@ type SyncReplicaElectionConstraints struct {
 
 	// This flag enables the constraints for sync replicas
 	Enabled bool `json:"enabled"`
+
+	// The method used to generate the "synchronous_standby_names" parameter:
+	// "any" for quorum-based synchronous replication (default), "first" for
+	// priority-based synchronous replication. This setting is applied even
+	// when the constraints are not enabled
+	// +kubebuilder:validation:Enum=any;first
+	// +optional
+	Method SyncReplicaElectionMethod `json:"method,omitempty"`
+
+	// How the anti-affinity constraints are enforced. With "required" (default)
+	// the replicas sharing the topology of the primary are never elected as
+	// synchronous replicas. With "preferred" they are electable, but with a
+	// lower priority than the replicas in a different topology. The "preferred"
+	// mode requires the "first" method
+	// +kubebuilder:validation:Enum=required;preferred
+	// +optional
+	Mode SyncReplicaElectionMode `json:"mode,omitempty"`
+}
+
+// SyncReplicaElectionMethod is the method used to generate
+// the "synchronous_standby_names" parameter
+// +enum
+type SyncReplicaElectionMethod string
+
+const (
+	// SyncReplicaElectionMethodAny means quorum-based synchronous replication,
+	// where a commit waits for any of the electable replicas
+	SyncReplicaElectionMethodAny SyncReplicaElectionMethod = "any"
+
+	// SyncReplicaElectionMethodFirst means priority-based synchronous replication,
+	// where the synchronous replicas are chosen following the order of the
+	// electable replicas
+	SyncReplicaElectionMethodFirst SyncReplicaElectionMethod = "first"
+)
+
+// SyncReplicaElectionMode is the way the anti-affinity constraints
+// for synchronous replicas are enforced
+// +enum
+type SyncReplicaElectionMode string
+
+const (
+	// SyncReplicaElectionModeRequired means that the replicas sharing the
+	// topology of the primary are never elected as synchronous replicas
+	SyncReplicaElectionModeRequired SyncReplicaElectionMode = "required"
+
+	// SyncReplicaElectionModePreferred means that the replicas sharing the
+	// topology of the primary are elected as synchronous replicas only
+	// after the ones in a different topology
+	SyncReplicaElectionModePreferred SyncReplicaElectionMode = "preferred"
+)
+
+// GetMethod returns the method used to generate the
+// "synchronous_standby_names" parameter, defaulting to "any"
+func (constraints SyncReplicaElectionConstraints) GetMethod() SyncReplicaElectionMethod {
+	if constraints.Method == "" {
+		return SyncReplicaElectionMethodAny
+	}
+	return constraints.Method
+}
+
+// GetMode returns how the anti-affinity constraints are enforced,
+// defaulting to "required"
+func (constraints SyncReplicaElectionConstraints) GetMode() SyncReplicaElectionMode {
+	if constraints.Mode == "" {
+		return SyncReplicaElectionModeRequired
+	}
+	return constraints.Mode
 }
 
 // AffinityConfiguration contains the info we need to create the
@@ -1984,6 +4440,12 @@ const (
 
 	// CompressionTypeSnappy means snappy compression is performed
 	CompressionTypeSnappy = CompressionType("snappy")
+
+	// CompressionTypeZstd means zstd compression is performed
+	CompressionTypeZstd = CompressionType("zstd")
+
+	// CompressionTypeLZ4 means lz4 compression is performed
+	CompressionTypeLZ4 = CompressionType("lz4")
 )
 
 // EncryptionType encapsulated the available types of encryption
@@ -2086,6 +4548,16 @@ type BackupConfiguration struct {
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// Additional object stores where the WAL files are shipped together
+	// with the one defined in `barmanObjectStore`, i.e. a bucket in a
+	// different region for disaster recovery. Base backups are taken
+	// on an additional object store by referencing it by name in the
+	// Backup and ScheduledBackup objects
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdditionalBarmanObjectStores []NamedBarmanObjectStoreConfiguration `json:"additionalBarmanObjectStores,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
@@ -2103,14 +4575,86 @@ type BackupConfiguration struct {
 	// +kubebuilder:default:=prefer-standby
 	// +optional
 	Target BackupTarget `json:"target,omitempty"`
+
+	// AllowBackupDeletion is the safety switch enabling the removal of the
+	// base backups from the object store when the Backups having the
+	// `delete` deletion policy are deleted. When false, the base backups
+	// are always retained. Default: `false`.
+	// +optional
+	AllowBackupDeletion bool `json:"allowBackupDeletion,omitempty"`
+
+	// The actions run around every backup taken with the
+	// `barmanObjectStore` method, allowing the applications to flush
+	// and quiesce their activity while it is taken
+	// +optional
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+}
+
+// NamedBarmanObjectStoreConfiguration is the configuration of an
+// additional object store, identified by its name
+type NamedBarmanObjectStoreConfiguration struct {
+	// The name of the object store, used to reference it in the
+	// backups and in the conditions reporting the archiving status
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The configuration of the object store
+	BarmanObjectStoreConfiguration `json:",inline"`
+}
+
+// GetHooks returns the actions run around the backups, if any
+func (backupConfiguration *BackupConfiguration) GetHooks() *BackupHooks {
+	if backupConfiguration == nil {
+		return nil
+	}
+	return backupConfiguration.Hooks
+}
+
+// GetBarmanObjectStore returns the configuration of the object store with
+// the passed name, or the one in `barmanObjectStore` if the name is empty.
+// It returns nil if no such object store is defined
+func (backupConfiguration *BackupConfiguration) GetBarmanObjectStore(
+	name string,
+) *BarmanObjectStoreConfiguration {
+	if backupConfiguration == nil {
+		return nil
+	}
+
+	if name == "" {
+		return backupConfiguration.BarmanObjectStore
+	}
+
+	for i := range backupConfiguration.AdditionalBarmanObjectStores {
+		if backupConfiguration.AdditionalBarmanObjectStores[i].Name == name {
+			return &backupConfiguration.AdditionalBarmanObjectStores[i].BarmanObjectStoreConfiguration
+		}
+	}
+
+	return nil
+}
+
+// GetContinuousArchivingConditionType returns the type of the condition
+// reporting the status of the WAL archiving on the object store with the
+// passed name. The empty name refers to the one in `barmanObjectStore`
+func GetContinuousArchivingConditionType(objectStoreName string) string {
+	if objectStoreName == "" {
+		return string(ConditionContinuousArchiving)
+	}
+
+	return fmt.Sprintf("%s-%s", ConditionContinuousArchiving, objectStoreName)
 }
 
 // WalBackupConfiguration is the configuration of the backup of the
 // WAL stream
 type WalBackupConfiguration struct {
 	// Compress a WAL file before sending it to the object store. Available
-	// options are empty string (no compression, default), `gzip`, `bzip2` or `snappy`.
-	// +kubebuilder:validation:Enum=gzip;bzip2;snappy
+	// options are empty string (no compression, default), `gzip`, `bzip2`,
+	// `snappy`, `zstd` or `lz4`. The `zstd` and `lz4` algorithms require
+	// Barman 3.12 or later in the operand image.
+	// The WAL files are decompressed automatically while being restored,
+	// whatever the algorithm used to archive them.
+	// +kubebuilder:validation:Enum=gzip;bzip2;snappy;zstd;lz4
 	// +optional
 	Compression CompressionType `json:"compression,omitempty"`
 
@@ -2131,6 +4675,60 @@ type WalBackupConfiguration struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	MaxParallel int `json:"maxParallel,omitempty"`
+
+	// Maximum number of WAL files to be archived in parallel when the
+	// archiving is falling behind, that is when more than twice `maxParallel`
+	// WAL files are waiting to be archived. In that case, the number of WAL
+	// files archived in parallel grows with the backlog, up to this value.
+	// If not specified, the archiving parallelism never exceeds `maxParallel`.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxParallelBurst int `json:"maxParallelBurst,omitempty"`
+
+	// Encrypt the WAL files before uploading them to the object store, with
+	// keys that are never sent to it. The WAL files are decrypted while being
+	// restored. The base backups are uploaded by Barman Cloud directly from
+	// the data files, so this option requires them to be encrypted by the
+	// object store, through the `encryption` option of the `data` stanza
+	// +optional
+	ClientSideEncryption *WALClientSideEncryption `json:"clientSideEncryption,omitempty"`
+}
+
+// WALClientSideEncryption is the configuration of the encryption of the WAL
+// files done by the instance manager before uploading them
+type WALClientSideEncryption struct {
+	// The secret containing the encryption keys. Every key of the secret is an
+	// encryption key made of 32 random bytes, or of their base64 encoding as
+	// generated by `openssl rand -base64 32`
+	KeysSecret LocalObjectReference `json:"keysSecret"`
+
+	// The name of the key, in the secret, used to encrypt the new WAL files.
+	// The other keys of the secret are used to decrypt the WAL files archived
+	// before the active key was changed, and must be kept as long as those
+	// files are needed
+	// +kubebuilder:validation:MinLength=1
+	ActiveKey string `json:"activeKey"`
+
+	// Restore the WAL files which are not encrypted, like the ones archived
+	// before enabling the encryption. By default, they are refused, so that
+	// WAL files can't be injected by whoever can write in the object store
+	// +optional
+	AllowUnencryptedFiles bool `json:"allowUnencryptedFiles,omitempty"`
+}
+
+// GetArchiveParallelism gets the number of WAL files to be archived in
+// parallel, given the number of WAL files waiting to be archived
+func (walConfig *WalBackupConfiguration) GetArchiveParallelism(backlog int) int {
+	if walConfig == nil {
+		return 1
+	}
+
+	maxParallel := max(walConfig.MaxParallel, 1)
+	if walConfig.MaxParallelBurst <= maxParallel || backlog <= 2*maxParallel {
+		return maxParallel
+	}
+
+	return min(backlog, walConfig.MaxParallelBurst)
 }
 
 // DataBackupConfiguration is the configuration of the backup of
@@ -2182,110 +4780,563 @@ type DataBackupConfiguration struct {
 	AdditionalCommandArgs []string `json:"additionalCommandArgs,omitempty"`
 }
 
-// S3Credentials is the type for the credentials to be used to upload
-// files to S3. It can be provided in two alternative ways:
-//
-// - explicitly passing accessKeyId and secretAccessKey
-//
-// - inheriting the role from the pod environment by setting inheritFromIAMRole to true
-type S3Credentials struct {
-	// The reference to the access key id
+// S3Credentials is the type for the credentials to be used to upload
+// files to S3. It can be provided in two alternative ways:
+//
+// - explicitly passing accessKeyId and secretAccessKey
+//
+// - inheriting the role from the pod environment by setting inheritFromIAMRole to true
+type S3Credentials struct {
+	// The reference to the access key id
+	// +optional
+	AccessKeyIDReference *SecretKeySelector `json:"accessKeyId,omitempty"`
+
+	// The reference to the secret access key
+	// +optional
+	SecretAccessKeyReference *SecretKeySelector `json:"secretAccessKey,omitempty"`
+
+	// The reference to the secret containing the region name
+	// +optional
+	RegionReference *SecretKeySelector `json:"region,omitempty"`
+
+	// The references to the session key
+	// +optional
+	SessionToken *SecretKeySelector `json:"sessionToken,omitempty"`
+
+	// Use the role based authentication without providing explicitly the keys.
+	// +optional
+	InheritFromIAMRole bool `json:"inheritFromIAMRole,omitempty"`
+}
+
+// AzureCredentials is the type for the credentials to be used to upload
+// files to Azure Blob Storage. The connection string contains every needed
+// information. If the connection string is not specified, we'll need the
+// storage account name and also one (and only one) of:
+//
+// - storageKey
+// - storageSasToken
+//
+// - inheriting the credentials from the pod environment by setting inheritFromAzureAD to true
+type AzureCredentials struct {
+	// The connection string to be used
+	// +optional
+	ConnectionString *SecretKeySelector `json:"connectionString,omitempty"`
+
+	// The storage account where to upload data
+	// +optional
+	StorageAccount *SecretKeySelector `json:"storageAccount,omitempty"`
+
+	// The storage account key to be used in conjunction
+	// with the storage account name
+	// +optional
+	StorageKey *SecretKeySelector `json:"storageKey,omitempty"`
+
+	// A shared-access-signature to be used in conjunction with
+	// the storage account name
+	// +optional
+	StorageSasToken *SecretKeySelector `json:"storageSasToken,omitempty"`
+
+	// Use the Azure AD based authentication without providing explicitly the keys.
+	// +optional
+	InheritFromAzureAD bool `json:"inheritFromAzureAD,omitempty"`
+}
+
+// GoogleCredentials is the type for the Google Cloud Storage credentials.
+// This needs to be specified even if we run inside a GKE environment.
+type GoogleCredentials struct {
+	// The secret containing the Google Cloud Storage JSON file with the credentials
+	// +optional
+	ApplicationCredentials *SecretKeySelector `json:"applicationCredentials,omitempty"`
+
+	// If set to true, will presume that it's running inside a GKE environment,
+	// default to false.
+	// +optional
+	GKEEnvironment bool `json:"gkeEnvironment,omitempty"`
+}
+
+// DefaultFailoverArbiterLeaseDuration is the default duration in seconds
+// of the lease used as failover arbiter
+const DefaultFailoverArbiterLeaseDuration = 30
+
+// FailoverArbiterConfiguration contains the configuration of the external
+// arbiter that must grant the promotion of a new primary in case of failover
+type FailoverArbiterConfiguration struct {
+	// A lease, stored in a Kubernetes cluster different from the one hosting
+	// this cluster, whose holder is the only one allowed to run a primary
+	// +optional
+	Lease *FailoverArbiterLease `json:"lease,omitempty"`
+}
+
+// FailoverArbiterLease is a `coordination.k8s.io/v1` Lease used as
+// failover arbiter. While the primary is healthy the operator keeps renewing
+// the lease and, in case of failover, a new primary is promoted only if the
+// lease is held by this cluster or has expired
+type FailoverArbiterLease struct {
+	// The secret containing the kubeconfig used to connect to the Kubernetes
+	// cluster hosting the lease
+	KubeconfigSecret SecretKeySelector `json:"kubeconfigSecret"`
+
+	// The namespace of the lease
+	Namespace string `json:"namespace"`
+
+	// The name of the lease. Defaults to the name of the cluster
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// The identity used by this cluster as holder of the lease. It must be
+	// different for each cluster contending the same lease
+	Identity string `json:"identity"`
+
+	// The duration of the lease in seconds. If the lease is not renewed
+	// within this time, it can be acquired by another cluster.
+	// Default: 30.
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+}
+
+// GetName gets the name of the lease, defaulting to the passed cluster name
+func (l *FailoverArbiterLease) GetName(clusterName string) string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return clusterName
+}
+
+// GetLeaseDuration gets the duration of the lease
+func (l *FailoverArbiterLease) GetLeaseDuration() time.Duration {
+	if l.LeaseDurationSeconds <= 0 {
+		return DefaultFailoverArbiterLeaseDuration * time.Second
+	}
+	return time.Duration(l.LeaseDurationSeconds) * time.Second
+}
+
+// FailoverDetectionConfiguration contains the thresholds used to detect
+// the failure of the primary and the flap damping of the failovers
+type FailoverDetectionConfiguration struct {
+	// The number of consecutive observations of the status of the primary,
+	// at least 5 seconds apart, in which the primary must be unhealthy
+	// before triggering a failover. A healthy observation resets the count.
+	// Default: 1.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// The minimum amount of time (in seconds) the primary must be
+	// unhealthy before triggering a failover. When `.spec.failoverDelay`
+	// is set too, the longest of the two is used.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinUnreachableDuration int32 `json:"minUnreachableDuration,omitempty"`
+
+	// The amount of time (in seconds) since the last promotion during
+	// which a failover is not triggered, preventing the primary from
+	// flapping between instances. Default: 0, no cooldown.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FlapDampingCooldown int32 `json:"flapDampingCooldown,omitempty"`
+}
+
+// MonitoringConfiguration is the type containing all the monitoring
+// configuration for a certain cluster
+type MonitoringConfiguration struct {
+	// Whether the default queries should be injected.
+	// Set it to `true` if you don't want to inject default queries into the cluster.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	DisableDefaultQueries *bool `json:"disableDefaultQueries,omitempty"`
+
+	// The list of config maps containing the custom queries
+	// +optional
+	CustomQueriesConfigMap []ConfigMapKeySelector `json:"customQueriesConfigMap,omitempty"`
+
+	// The list of secrets containing the custom queries
+	// +optional
+	CustomQueriesSecret []SecretKeySelector `json:"customQueriesSecret,omitempty"`
+
+	// Enable or disable the `PodMonitor`
+	// +kubebuilder:default:=false
+	// +optional
+	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// The list of metric relabelings for the `PodMonitor`. Applied to samples before ingestion.
+	// +optional
+	PodMonitorMetricRelabelConfigs []*monitoringv1.RelabelConfig `json:"podMonitorMetricRelabelings,omitempty"`
+
+	// The list of relabelings for the `PodMonitor`. Applied to samples before scraping.
+	// +optional
+	PodMonitorRelabelConfigs []*monitoringv1.RelabelConfig `json:"podMonitorRelabelings,omitempty"`
+
+	// The configuration of the built-in collector exporting query
+	// statistics from `pg_stat_statements`
 	// +optional
-	AccessKeyIDReference *SecretKeySelector `json:"accessKeyId,omitempty"`
+	QueryStatistics *QueryStatisticsConfiguration `json:"queryStatistics,omitempty"`
 
-	// The reference to the secret access key
+	// The configuration of the built-in collector exporting the size
+	// of the databases, schemas and tables
 	// +optional
-	SecretAccessKeyReference *SecretKeySelector `json:"secretAccessKey,omitempty"`
+	ObjectSizes *ObjectSizesConfiguration `json:"objectSizes,omitempty"`
 
-	// The reference to the secret containing the region name
+	// The alerting rules generated by the operator for the cluster,
+	// in a `PrometheusRule` having the same name of the cluster
 	// +optional
-	RegionReference *SecretKeySelector `json:"region,omitempty"`
+	PrometheusRule *PrometheusRuleConfiguration `json:"prometheusRule,omitempty"`
 
-	// The references to the session key
+	// The Grafana dashboard generated by the operator for the cluster,
+	// in a `ConfigMap` which can be discovered by the Grafana sidecar
 	// +optional
-	SessionToken *SecretKeySelector `json:"sessionToken,omitempty"`
+	GrafanaDashboard *GrafanaDashboardConfiguration `json:"grafanaDashboard,omitempty"`
+}
 
-	// Use the role based authentication without providing explicitly the keys.
+// DefaultQueryStatisticsTopQueries is the default number of queries for
+// which the statistics are exported individually
+const DefaultQueryStatisticsTopQueries = 20
+
+// QueryStatisticsConfiguration contains the configuration of the built-in
+// collector exporting the statistics of the most expensive queries
+// as tracked by `pg_stat_statements`
+type QueryStatisticsConfiguration struct {
+	// When enabled, the operator loads `pg_stat_statements` and the instance
+	// manager exports the statistics of the top queries.
+	// Default: false.
+	// +kubebuilder:default:=false
 	// +optional
-	InheritFromIAMRole bool `json:"inheritFromIAMRole,omitempty"`
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The maximum number of queries, ranked by total execution time,
+	// for which the statistics are exported individually. The remaining
+	// ones are aggregated under a single `other` series, keeping the
+	// cardinality of the metrics bounded.
+	// Default: 20.
+	// +kubebuilder:default:=20
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=200
+	// +optional
+	TopQueries int `json:"topQueries,omitempty"`
 }
 
-// AzureCredentials is the type for the credentials to be used to upload
-// files to Azure Blob Storage. The connection string contains every needed
-// information. If the connection string is not specified, we'll need the
-// storage account name and also one (and only one) of:
-//
-// - storageKey
-// - storageSasToken
-//
-// - inheriting the credentials from the pod environment by setting inheritFromAzureAD to true
-type AzureCredentials struct {
-	// The connection string to be used
+const (
+	// DefaultObjectSizesRefreshInterval is the default number of seconds
+	// after which the size of the objects is collected again
+	DefaultObjectSizesRefreshInterval = 300
+
+	// DefaultObjectSizesMaxTables is the default number of tables whose
+	// size is exported individually
+	DefaultObjectSizesMaxTables = 100
+)
+
+// ObjectSizesConfiguration contains the configuration of the built-in
+// collector exporting the size of the databases, schemas and tables.
+// The patterns are regular expressions: an object is collected when it
+// matches at least one of the include patterns, if any, and none of
+// the exclude ones
+type ObjectSizesConfiguration struct {
+	// When enabled, the instance manager exports the size of the
+	// databases, schemas and tables.
+	// Default: false.
+	// +kubebuilder:default:=false
 	// +optional
-	ConnectionString *SecretKeySelector `json:"connectionString,omitempty"`
+	Enabled bool `json:"enabled,omitempty"`
 
-	// The storage account where to upload data
+	// The number of seconds after which the size of the objects is
+	// collected again. In between, the metrics report the values of the
+	// last collection, as computing them requires a scan of the catalogs
+	// of every database.
+	// Default: 300.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=30
 	// +optional
-	StorageAccount *SecretKeySelector `json:"storageAccount,omitempty"`
+	RefreshInterval int `json:"refreshInterval,omitempty"`
 
-	// The storage account key to be used in conjunction
-	// with the storage account name
+	// The patterns of the names of the databases to be collected
 	// +optional
-	StorageKey *SecretKeySelector `json:"storageKey,omitempty"`
+	IncludeDatabases []string `json:"includeDatabases,omitempty"`
 
-	// A shared-access-signature to be used in conjunction with
-	// the storage account name
+	// The patterns of the names of the databases to be skipped
 	// +optional
-	StorageSasToken *SecretKeySelector `json:"storageSasToken,omitempty"`
+	ExcludeDatabases []string `json:"excludeDatabases,omitempty"`
 
-	// Use the Azure AD based authentication without providing explicitly the keys.
+	// The patterns of the qualified names, in the `schema.table` form,
+	// of the tables to be collected
 	// +optional
-	InheritFromAzureAD bool `json:"inheritFromAzureAD,omitempty"`
-}
+	IncludeTables []string `json:"includeTables,omitempty"`
 
-// GoogleCredentials is the type for the Google Cloud Storage credentials.
-// This needs to be specified even if we run inside a GKE environment.
-type GoogleCredentials struct {
-	// The secret containing the Google Cloud Storage JSON file with the credentials
+	// The patterns of the qualified names, in the `schema.table` form,
+	// of the tables to be skipped
 	// +optional
-	ApplicationCredentials *SecretKeySelector `json:"applicationCredentials,omitempty"`
+	ExcludeTables []string `json:"excludeTables,omitempty"`
 
-	// If set to true, will presume that it's running inside a GKE environment,
-	// default to false.
+	// The maximum number of tables, ranked by size, whose size is
+	// exported individually. The size of the remaining ones is aggregated
+	// under a single `other` series, keeping the cardinality of the
+	// metrics bounded.
+	// Default: 100.
+	// +kubebuilder:default:=100
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
 	// +optional
-	GKEEnvironment bool `json:"gkeEnvironment,omitempty"`
+	MaxTables int `json:"maxTables,omitempty"`
 }
 
-// MonitoringConfiguration is the type containing all the monitoring
-// configuration for a certain cluster
-type MonitoringConfiguration struct {
-	// Whether the default queries should be injected.
-	// Set it to `true` if you don't want to inject default queries into the cluster.
+// GetRefreshInterval gets the time after which the size of the objects
+// is collected again
+func (configuration *ObjectSizesConfiguration) GetRefreshInterval() time.Duration {
+	if configuration == nil || configuration.RefreshInterval <= 0 {
+		return DefaultObjectSizesRefreshInterval * time.Second
+	}
+	return time.Duration(configuration.RefreshInterval) * time.Second
+}
+
+// GetMaxTables gets the number of tables whose size is exported individually
+func (configuration *ObjectSizesConfiguration) GetMaxTables() int {
+	if configuration == nil || configuration.MaxTables <= 0 {
+		return DefaultObjectSizesMaxTables
+	}
+	return configuration.MaxTables
+}
+
+// ObjectSizesFilter selects the objects whose size is collected, using
+// the compiled patterns of an ObjectSizesConfiguration
+// +kubebuilder:object:generate:=false
+type ObjectSizesFilter struct {
+	includeDatabases []*regexp.Regexp
+	excludeDatabases []*regexp.Regexp
+	includeTables    []*regexp.Regexp
+	excludeTables    []*regexp.Regexp
+}
+
+// GetFilter compiles the include and exclude patterns once, so that
+// they can be matched against every database and table
+func (configuration *ObjectSizesConfiguration) GetFilter() (*ObjectSizesFilter, error) {
+	filter := &ObjectSizesFilter{}
+	if configuration == nil {
+		return filter, nil
+	}
+
+	// The patterns are validated by the webhook
+	for _, item := range []struct {
+		patterns []string
+		target   *[]*regexp.Regexp
+	}{
+		{patterns: configuration.IncludeDatabases, target: &filter.includeDatabases},
+		{patterns: configuration.ExcludeDatabases, target: &filter.excludeDatabases},
+		{patterns: configuration.IncludeTables, target: &filter.includeTables},
+		{patterns: configuration.ExcludeTables, target: &filter.excludeTables},
+	} {
+		for _, pattern := range item.patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			*item.target = append(*item.target, re)
+		}
+	}
+
+	return filter, nil
+}
+
+// IsDatabaseIncluded checks whether the size of the passed database
+// should be collected
+func (filter *ObjectSizesFilter) IsDatabaseIncluded(name string) bool {
+	return matchesObjectSizesPatterns(name, filter.includeDatabases, filter.excludeDatabases)
+}
+
+// IsTableIncluded checks whether the size of the passed table should
+// be collected
+func (filter *ObjectSizesFilter) IsTableIncluded(schema, table string) bool {
+	return matchesObjectSizesPatterns(schema+"."+table, filter.includeTables, filter.excludeTables)
+}
+
+// matchesObjectSizesPatterns checks whether the passed name matches at
+// least one of the include patterns, if any, and none of the exclude ones
+func matchesObjectSizesPatterns(name string, include, exclude []*regexp.Regexp) bool {
+	matchesAny := func(patterns []*regexp.Regexp) bool {
+		for _, re := range patterns {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(include) > 0 && !matchesAny(include) {
+		return false
+	}
+	return !matchesAny(exclude)
+}
+
+const (
+	// DefaultPrometheusRuleMaxReplicationLag is the default number of
+	// seconds of replication lag after which an alert is fired
+	DefaultPrometheusRuleMaxReplicationLag = 300
+
+	// DefaultPrometheusRuleMaxTransactionDuration is the default number
+	// of seconds a transaction can run before an alert is fired
+	DefaultPrometheusRuleMaxTransactionDuration = 300
+
+	// DefaultPrometheusRuleMaxConnectionsUsage is the default percentage
+	// of `max_connections` in use after which an alert is fired
+	DefaultPrometheusRuleMaxConnectionsUsage = 80
+
+	// DefaultPrometheusRuleMaxStorageUsage is the default percentage of
+	// a volume in use after which an alert is fired
+	DefaultPrometheusRuleMaxStorageUsage = 80
+
+	// DefaultPrometheusRuleMaxXIDAge is the default age of the oldest
+	// unfrozen transaction ID after which an alert is fired
+	DefaultPrometheusRuleMaxXIDAge = 150000000
+)
+
+// PrometheusRuleConfiguration contains the configuration of the alerting
+// rules the operator generates for the cluster. The thresholds apply to
+// the metrics of the instances scraped through the `PodMonitor`
+type PrometheusRuleConfiguration struct {
+	// When enabled, the operator creates a `PrometheusRule` with the
+	// alerts of the cluster, and keeps it aligned with its specification.
 	// Default: false.
 	// +kubebuilder:default:=false
 	// +optional
-	DisableDefaultQueries *bool `json:"disableDefaultQueries,omitempty"`
+	Enabled bool `json:"enabled,omitempty"`
 
-	// The list of config maps containing the custom queries
+	// Additional labels of the `PrometheusRule`, for example the ones
+	// matched by the `ruleSelector` of the Prometheus instance
 	// +optional
-	CustomQueriesConfigMap []ConfigMapKeySelector `json:"customQueriesConfigMap,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
 
-	// The list of secrets containing the custom queries
+	// The number of seconds of replication lag of a replica after which
+	// an alert is fired.
+	// Default: 300.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
 	// +optional
-	CustomQueriesSecret []SecretKeySelector `json:"customQueriesSecret,omitempty"`
+	MaxReplicationLag int32 `json:"maxReplicationLag,omitempty"`
 
-	// Enable or disable the `PodMonitor`
+	// The number of seconds a transaction can run before an alert is fired.
+	// Default: 300.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxTransactionDuration int32 `json:"maxTransactionDuration,omitempty"`
+
+	// The percentage of `max_connections` in use after which an alert
+	// is fired.
+	// Default: 80.
+	// +kubebuilder:default:=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxConnectionsUsage int32 `json:"maxConnectionsUsage,omitempty"`
+
+	// The percentage of the data or WAL volume in use after which an
+	// alert is fired.
+	// Default: 80.
+	// +kubebuilder:default:=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxStorageUsage int32 `json:"maxStorageUsage,omitempty"`
+
+	// The age of the oldest unfrozen transaction ID of a database after
+	// which an alert is fired, as the database is approaching a
+	// wraparound.
+	// Default: 150000000.
+	// +kubebuilder:default:=150000000
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxXIDAge int64 `json:"maxXIDAge,omitempty"`
+}
+
+// IsEnabled checks whether the operator generates the alerting rules
+func (configuration *PrometheusRuleConfiguration) IsEnabled() bool {
+	return configuration != nil && configuration.Enabled
+}
+
+// GetMaxReplicationLag gets the replication lag, in seconds, after which
+// an alert is fired
+func (configuration *PrometheusRuleConfiguration) GetMaxReplicationLag() int32 {
+	if configuration == nil || configuration.MaxReplicationLag <= 0 {
+		return DefaultPrometheusRuleMaxReplicationLag
+	}
+	return configuration.MaxReplicationLag
+}
+
+// GetMaxTransactionDuration gets the duration, in seconds, of a
+// transaction after which an alert is fired
+func (configuration *PrometheusRuleConfiguration) GetMaxTransactionDuration() int32 {
+	if configuration == nil || configuration.MaxTransactionDuration <= 0 {
+		return DefaultPrometheusRuleMaxTransactionDuration
+	}
+	return configuration.MaxTransactionDuration
+}
+
+// GetMaxConnectionsUsage gets the percentage of `max_connections` in use
+// after which an alert is fired
+func (configuration *PrometheusRuleConfiguration) GetMaxConnectionsUsage() int32 {
+	if configuration == nil || configuration.MaxConnectionsUsage <= 0 {
+		return DefaultPrometheusRuleMaxConnectionsUsage
+	}
+	return configuration.MaxConnectionsUsage
+}
+
+// GetMaxStorageUsage gets the percentage of a volume in use after which
+// an alert is fired
+func (configuration *PrometheusRuleConfiguration) GetMaxStorageUsage() int32 {
+	if configuration == nil || configuration.MaxStorageUsage <= 0 {
+		return DefaultPrometheusRuleMaxStorageUsage
+	}
+	return configuration.MaxStorageUsage
+}
+
+// GetMaxXIDAge gets the age of the oldest unfrozen transaction ID after
+// which an alert is fired
+func (configuration *PrometheusRuleConfiguration) GetMaxXIDAge() int64 {
+	if configuration == nil || configuration.MaxXIDAge <= 0 {
+		return DefaultPrometheusRuleMaxXIDAge
+	}
+	return configuration.MaxXIDAge
+}
+
+// DefaultGrafanaDashboardLabel is the label which the Grafana sidecar
+// uses by default to discover the ConfigMaps containing dashboards
+const DefaultGrafanaDashboardLabel = "grafana_dashboard"
+
+// GrafanaDashboardConfiguration contains the configuration of the
+// Grafana dashboard the operator generates for the cluster
+type GrafanaDashboardConfiguration struct {
+	// When enabled, the operator creates a `ConfigMap` containing a
+	// Grafana dashboard of the cluster, and keeps it aligned with its
+	// specification.
+	// Default: false.
 	// +kubebuilder:default:=false
 	// +optional
-	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+	Enabled bool `json:"enabled,omitempty"`
 
-	// The list of metric relabelings for the `PodMonitor`. Applied to samples before ingestion.
+	// The labels of the `ConfigMap`, which must match the ones the Grafana
+	// sidecar is configured to discover.
+	// Default: `grafana_dashboard: "1"`.
 	// +optional
-	PodMonitorMetricRelabelConfigs []*monitoringv1.RelabelConfig `json:"podMonitorMetricRelabelings,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
 
-	// The list of relabelings for the `PodMonitor`. Applied to samples before scraping.
+	// The annotations of the `ConfigMap`, for example the one setting
+	// the folder of the dashboard in Grafana
 	// +optional
-	PodMonitorRelabelConfigs []*monitoringv1.RelabelConfig `json:"podMonitorRelabelings,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// IsEnabled checks whether the operator generates the Grafana dashboard
+func (configuration *GrafanaDashboardConfiguration) IsEnabled() bool {
+	return configuration != nil && configuration.Enabled
+}
+
+// GetLabels gets the labels of the ConfigMap containing the dashboard
+func (configuration *GrafanaDashboardConfiguration) GetLabels() map[string]string {
+	if configuration == nil || len(configuration.Labels) == 0 {
+		return map[string]string{DefaultGrafanaDashboardLabel: "1"}
+	}
+	return configuration.Labels
 }
 
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
@@ -2293,6 +5344,27 @@ func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
 	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
 }
 
+// IsQueryStatisticsEnabled checks whether the statistics of the top queries
+// should be exported
+func (m *MonitoringConfiguration) IsQueryStatisticsEnabled() bool {
+	return m != nil && m.QueryStatistics != nil && m.QueryStatistics.Enabled
+}
+
+// GetQueryStatisticsTopQueries gets the number of queries for which the
+// statistics are exported individually
+func (m *MonitoringConfiguration) GetQueryStatisticsTopQueries() int {
+	if m == nil || m.QueryStatistics == nil || m.QueryStatistics.TopQueries <= 0 {
+		return DefaultQueryStatisticsTopQueries
+	}
+	return m.QueryStatistics.TopQueries
+}
+
+// IsObjectSizesEnabled checks whether the size of the databases,
+// schemas and tables should be exported
+func (m *MonitoringConfiguration) IsObjectSizesEnabled() bool {
+	return m != nil && m.ObjectSizes != nil && m.ObjectSizes.Enabled
+}
+
 // ExternalCluster represents the connection parameters to an
 // external cluster which is used in the other sections of the configuration
 type ExternalCluster struct {
@@ -2331,6 +5403,14 @@ type ExternalCluster struct {
 	// The configuration for the barman-cloud tool suite
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+
+	// The namespace containing the secrets referenced by this external
+	// cluster, when different from the namespace of the Cluster. The
+	// secrets must be granted to the Cluster by a ReplicationGrant in
+	// that namespace, which allows the service account of the Cluster to
+	// read them
+	// +optional
+	SecretsNamespace string `json:"secretsNamespace,omitempty"`
 }
 
 // AppendAdditionalCommandArgs adds custom arguments as barman cloud command-line options
@@ -2350,6 +5430,62 @@ func (cfg *BarmanObjectStoreConfiguration) AppendAdditionalCommandArgs(options [
 	return options
 }
 
+// GetSecretsNamespace returns the namespace containing the secrets
+// referenced by the external cluster, given the namespace of the Cluster
+func (in ExternalCluster) GetSecretsNamespace(clusterNamespace string) string {
+	if in.SecretsNamespace != "" {
+		return in.SecretsNamespace
+	}
+	return clusterNamespace
+}
+
+// GetSecretsNames returns the names of the secrets referenced by the
+// external cluster, including the credentials of its object store
+func (in ExternalCluster) GetSecretsNames() *stringset.Data {
+	secrets := stringset.New()
+	for _, selector := range []*corev1.SecretKeySelector{in.SSLCert, in.SSLKey, in.SSLRootCert, in.Password} {
+		if selector != nil && selector.Name != "" {
+			secrets.Put(selector.Name)
+		}
+	}
+
+	if in.BarmanObjectStore == nil {
+		return secrets
+	}
+
+	credentials := in.BarmanObjectStore.BarmanCredentials
+	selectors := []*SecretKeySelector{in.BarmanObjectStore.EndpointCA}
+	if credentials.AWS != nil {
+		selectors = append(selectors,
+			credentials.AWS.AccessKeyIDReference,
+			credentials.AWS.SecretAccessKeyReference,
+			credentials.AWS.RegionReference,
+			credentials.AWS.SessionToken)
+	}
+	if credentials.Azure != nil {
+		selectors = append(selectors,
+			credentials.Azure.ConnectionString,
+			credentials.Azure.StorageAccount,
+			credentials.Azure.StorageKey,
+			credentials.Azure.StorageSasToken)
+	}
+	if credentials.Google != nil {
+		selectors = append(selectors, credentials.Google.ApplicationCredentials)
+	}
+	for _, selector := range selectors {
+		if selector != nil && selector.Name != "" {
+			secrets.Put(selector.Name)
+		}
+	}
+
+	if wal := in.BarmanObjectStore.Wal; wal != nil && wal.ClientSideEncryption != nil &&
+		wal.ClientSideEncryption.KeysSecret.Name != "" {
+		secrets.Put(wal.ClientSideEncryption.KeysSecret.Name)
+	}
+
+	return secrets
+}
+
 // GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
 // in the BarmanObjectStore
 func (in ExternalCluster) GetServerName() string {
@@ -2359,22 +5495,210 @@ func (in ExternalCluster) GetServerName() string {
 	return in.Name
 }
 
-// EnsureOption represents whether we should enforce the presence or absence of
-// a Role in a PostgreSQL instance
-type EnsureOption string
+// EnsureOption represents whether we should enforce the presence or absence of
+// a Role in a PostgreSQL instance
+type EnsureOption string
+
+// values taken by EnsureOption
+const (
+	EnsurePresent EnsureOption = "present"
+	EnsureAbsent  EnsureOption = "absent"
+)
+
+// ManagedConfiguration represents the portions of PostgreSQL that are managed
+// by the instance manager
+type ManagedConfiguration struct {
+	// Database roles managed by the `Cluster`
+	// +optional
+	Roles []RoleConfiguration `json:"roles,omitempty"`
+
+	// Services managed by the `Cluster`
+	// +optional
+	Services *ManagedServices `json:"services,omitempty"`
+}
+
+// ManagedServices represents the services managed by the cluster
+type ManagedServices struct {
+	// Additional is a list of additional services, defined by the user,
+	// that the operator creates alongside the default ones
+	// +optional
+	Additional []ManagedService `json:"additional,omitempty"`
+
+	// MultiHostConnection publishes the `-any` headless service, giving
+	// every instance a stable DNS name, and a ConfigMap with multi-host
+	// connection strings listing them, kept up to date when the primary
+	// changes. Drivers using `target_session_attrs` can then fail over
+	// without waiting for the endpoints of the `-rw` service to be updated
+	// +optional
+	MultiHostConnection bool `json:"multiHostConnection,omitempty"`
+
+	// LagRouting removes from the endpoints of the `-ro` service, and of
+	// the additional services of type `ro`, the replicas whose replay lag
+	// exceeds the configured threshold, adding them back once caught up
+	// +optional
+	LagRouting *LagRoutingConfiguration `json:"lagRouting,omitempty"`
+}
+
+// LagRoutingConfiguration defines when a replica is considered too far
+// behind the primary to serve read-only traffic
+type LagRoutingConfiguration struct {
+	// The maximum replay lag, in bytes of WAL, a replica can have
+	// while still receiving read-only traffic
+	// +kubebuilder:validation:Minimum=0
+	MaxLagBytes int64 `json:"maxLagBytes"`
+}
+
+// ServiceSelectorType describes the instances a managed service points to
+// +kubebuilder:validation:Enum=rw;r;ro
+type ServiceSelectorType string
+
+const (
+	// ServiceSelectorTypeRW selects the primary instance, as the `-rw` service
+	ServiceSelectorTypeRW ServiceSelectorType = "rw"
+
+	// ServiceSelectorTypeR selects every instance, as the `-r` service
+	ServiceSelectorTypeR ServiceSelectorType = "r"
+
+	// ServiceSelectorTypeRO selects the replicas, as the `-ro` service
+	ServiceSelectorTypeRO ServiceSelectorType = "ro"
+)
+
+// ManagedService is an additional service managed by the cluster
+type ManagedService struct {
+	// The name of the service, which must not collide with the names of
+	// the default services of the cluster
+	Name string `json:"name"`
+
+	// SelectorType chooses the instances the service points to:
+	// `rw` for the primary, `r` for every instance and `ro` for the replicas
+	SelectorType ServiceSelectorType `json:"selectorType"`
+
+	// ServiceTemplate is the template of the service. The selector is
+	// generated by the operator according to the selector type, and the
+	// PostgreSQL port is used when no ports are specified
+	// +optional
+	ServiceTemplate ServiceTemplateSpec `json:"serviceTemplate,omitempty"`
+}
+
+// RejoinStrategy is the strategy used by a former primary to rejoin
+// the cluster when pg_rewind cannot be used
+type RejoinStrategy string
+
+const (
+	// RejoinStrategyRewind only uses pg_rewind, requiring a manual
+	// intervention when it fails
+	RejoinStrategyRewind RejoinStrategy = "rewind"
+
+	// RejoinStrategyPgBaseBackup clones the data directory from the
+	// current primary using pg_basebackup when pg_rewind fails
+	RejoinStrategyPgBaseBackup RejoinStrategy = "pgBaseBackup"
+
+	// RejoinStrategyObjectStore restores the data directory from the latest
+	// backup in the object store when pg_rewind fails, fetching the WAL
+	// files from the archive
+	RejoinStrategyObjectStore RejoinStrategy = "objectStore"
+)
+
+// ProbesConfiguration represent the configuration for the probes
+// to be injected in the PostgreSQL Pods
+type ProbesConfiguration struct {
+	// The startup probe configuration
+	// +optional
+	Startup *ProbeWithStrategy `json:"startup,omitempty"`
+
+	// The liveness probe configuration
+	// +optional
+	Liveness *Probe `json:"liveness,omitempty"`
+
+	// The readiness probe configuration
+	// +optional
+	Readiness *ProbeWithStrategy `json:"readiness,omitempty"`
+}
+
+// ProbeStrategyType is the type of the strategy used to declare a PostgreSQL instance
+// ready or started up
+// +kubebuilder:validation:Enum=pg_isready;query;streaming
+type ProbeStrategyType string
+
+const (
+	// ProbeStrategyPgIsReady only checks that the postmaster is up
+	// and accepting connections, using pg_isready
+	ProbeStrategyPgIsReady ProbeStrategyType = "pg_isready"
+
+	// ProbeStrategyQuery checks that the instance can be connected to
+	// and can execute a simple query
+	ProbeStrategyQuery ProbeStrategyType = "query"
+
+	// ProbeStrategyStreaming additionally requires replicas to be streaming
+	// from their source, optionally within a maximum replication lag
+	ProbeStrategyStreaming ProbeStrategyType = "streaming"
+)
+
+// ProbeWithStrategy is the configuration of the startup or readiness probe,
+// including the strategy used to check the instance
+type ProbeWithStrategy struct {
+	// Probe is the standard probe configuration
+	Probe `json:",inline"`
+
+	// The probe strategy
+	// +optional
+	Type ProbeStrategyType `json:"type,omitempty"`
+
+	// Lag limit, in bytes of WAL received but not yet replayed by a replica.
+	// Used only when the strategy is `streaming`
+	// +optional
+	MaximumLag *resource.Quantity `json:"maximumLag,omitempty"`
+
+	// Maximum number of queries that a replica is allowed to cancel because
+	// of recovery conflicts between two consecutive probes. When exceeded,
+	// the replica is reported as not ready. Used only when the strategy
+	// is `query` or `streaming`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaximumRecoveryConflicts *int64 `json:"maximumRecoveryConflicts,omitempty"`
+}
+
+// Probe describes a health check to be performed against a container to determine whether it is
+// alive or ready to receive traffic. Fields left empty keep the default value chosen by the operator
+type Probe struct {
+	// Number of seconds after the container has started before liveness probes are initiated.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+	// +optional
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+
+	// Number of seconds after which the probe times out.
+	// Defaults to 1 second. Minimum value is 1.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 
-// values taken by EnsureOption
-const (
-	EnsurePresent EnsureOption = "present"
-	EnsureAbsent  EnsureOption = "absent"
-)
+	// How often (in seconds) to perform the probe.
+	// Default to 10 seconds. Minimum value is 1.
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
 
-// ManagedConfiguration represents the portions of PostgreSQL that are managed
-// by the instance manager
-type ManagedConfiguration struct {
-	// Database roles managed by the `Cluster`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	// Defaults to 1. Must be 1 for liveness and startup. Minimum value is 1.
 	// +optional
-	Roles []RoleConfiguration `json:"roles,omitempty"`
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// Defaults to 3. Minimum value is 1.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// Optional duration in seconds the pod needs to terminate gracefully upon probe failure.
+	// The grace period is the duration in seconds after the processes running in the pod are sent
+	// a termination signal and the time when the processes are forcibly halted with a kill signal.
+	// Set this value longer than the expected cleanup time for your process.
+	// If this value is nil, the pod's terminationGracePeriodSeconds will be used. Otherwise,
+	// this value overrides the value provided by the pod spec.
+	// Value must be non-negative integer. The value zero indicates stop immediately via
+	// the kill signal (no opportunity to shut down).
+	// This is a beta field and requires enabling ProbeTerminationGracePeriod feature gate.
+	// Minimum value is 1. spec.terminationGracePeriodSeconds is used if unset.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 }
 
 // PluginConfiguration specifies a plugin that need to be loaded for this
@@ -2503,6 +5827,16 @@ type RoleConfiguration struct {
 	// Default is `false`.
 	// +optional
 	BypassRLS bool `json:"bypassrls,omitempty"` // Row-Level Security
+
+	// When set to `true`, the operator issues a client certificate for
+	// this role, signed by the client CA of the cluster, and allows the
+	// role to authenticate with it over TLS connections. The certificate
+	// is stored in the `<cluster>-<role>-client` secret, where the role name
+	// is lowercased and its underscores are replaced by dashes, and it is
+	// renewed automatically.
+	// Requires the `login` attribute. Default is `false`.
+	// +optional
+	ClientCertificate bool `json:"clientCertificate,omitempty"`
 }
 
 // GetRoleSecretsName gets the name of the secret which is used to store the role's password
@@ -2513,6 +5847,15 @@ func (roleConfiguration *RoleConfiguration) GetRoleSecretsName() string {
 	return ""
 }
 
+// GetClientCertificateSecretName gets the name of the secret containing the
+// client certificate generated for this role in the passed cluster
+func (roleConfiguration *RoleConfiguration) GetClientCertificateSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-%s%s",
+		clusterName,
+		strings.ReplaceAll(strings.ToLower(roleConfiguration.Name), "_", "-"),
+		ClientCertificateSecretSuffix)
+}
+
 // GetRoleInherit return the inherit attribute of a roleConfiguration
 func (roleConfiguration *RoleConfiguration) GetRoleInherit() bool {
 	if roleConfiguration.Inherit != nil {
@@ -2598,10 +5941,19 @@ type SecretsResourceVersion struct {
 	// +optional
 	BarmanEndpointCA string `json:"barmanEndpointCA,omitempty"`
 
+	// The resource version of the secret containing the TDE passphrase
+	// +optional
+	TDESecretVersion string `json:"tdeSecretVersion,omitempty"`
+
 	// The resource versions of the external cluster secrets
 	// +optional
 	ExternalClusterSecretVersions map[string]string `json:"externalClusterSecretVersion,omitempty"`
 
+	// The resource versions of the secrets containing the credentials
+	// used to access the object stores where the backups are taken
+	// +optional
+	BarmanCredentialsSecretVersions map[string]string `json:"barmanCredentialsSecretVersion,omitempty"`
+
 	// A map with the versions of all the secrets used to pass metrics.
 	// Map keys are the secret names, map values are the versions
 	// +optional
@@ -2646,6 +5998,24 @@ func (secretResourceVersion *SecretsResourceVersion) SetExternalClusterSecretVer
 	secretResourceVersion.ExternalClusterSecretVersions[secretName] = *version
 }
 
+// SetBarmanCredentialsSecretVersion Add or update or delete the resource version of the secret used
+// to access the backup object stores
+func (secretResourceVersion *SecretsResourceVersion) SetBarmanCredentialsSecretVersion(
+	secretName string,
+	version *string,
+) {
+	if secretResourceVersion.BarmanCredentialsSecretVersions == nil {
+		secretResourceVersion.BarmanCredentialsSecretVersions = make(map[string]string)
+	}
+
+	if version == nil {
+		delete(secretResourceVersion.BarmanCredentialsSecretVersions, secretName)
+		return
+	}
+
+	secretResourceVersion.BarmanCredentialsSecretVersions[secretName] = *version
+}
+
 // GetImageName get the name of the image that should be used
 // to create the pods
 func (cluster *Cluster) GetImageName() string {
@@ -2666,6 +6036,48 @@ func (cluster *Cluster) GetImageName() string {
 	return configuration.Current.PostgresImageName
 }
 
+// GetSchedulingArchitecture gets the architecture of the nodes where the
+// pods of the cluster can be scheduled, as constrained on the `kubernetes.io/arch`
+// label by the node selector or by the required node affinity. An empty string
+// is returned when the pods can be scheduled on more than one architecture
+func (cluster *Cluster) GetSchedulingArchitecture() string {
+	if architecture, ok := cluster.Spec.Affinity.NodeSelector[corev1.LabelArchStable]; ok {
+		return architecture
+	}
+
+	nodeAffinity := cluster.Spec.Affinity.NodeAffinity
+	if nodeAffinity == nil || nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+
+	// Node selector terms are ORed, so each one of them
+	// needs to allow the same architecture
+	var result string
+	for _, term := range nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		architecture := getNodeSelectorTermArchitecture(term)
+		if architecture == "" || (result != "" && architecture != result) {
+			return ""
+		}
+		result = architecture
+	}
+
+	return result
+}
+
+// getNodeSelectorTermArchitecture gets the only architecture allowed
+// by a node selector term, if any
+func getNodeSelectorTermArchitecture(term corev1.NodeSelectorTerm) string {
+	for _, requirement := range term.MatchExpressions {
+		if requirement.Key == corev1.LabelArchStable &&
+			requirement.Operator == corev1.NodeSelectorOpIn &&
+			len(requirement.Values) == 1 {
+			return requirement.Values[0]
+		}
+	}
+
+	return ""
+}
+
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
 // image name or from the ImageCatalogRef.
 // Example:
@@ -2708,6 +6120,82 @@ func (cluster *Cluster) GetSuperuserSecretName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, SuperUserSecretSuffix)
 }
 
+// GetPgHBA gets the user-defined lines of the pg_hba.conf file, rendering
+// the structured rules first and then the free-form ones, in the given order
+func (cluster *Cluster) GetPgHBA() []string {
+	configuration := cluster.Spec.PostgresConfiguration
+	if len(configuration.PgHBARules) == 0 {
+		return configuration.PgHBA
+	}
+
+	result := make([]string, 0, len(configuration.PgHBARules)+len(configuration.PgHBA))
+	for _, rule := range configuration.PgHBARules {
+		result = append(result, rule.String())
+	}
+	return append(result, configuration.PgHBA...)
+}
+
+// GetDatabase gets the database names matched by the rule
+func (rule PgHBARule) GetDatabase() string {
+	if rule.Database == "" {
+		return "all"
+	}
+	return rule.Database
+}
+
+// GetUser gets the user names matched by the rule
+func (rule PgHBARule) GetUser() string {
+	if rule.User == "" {
+		return "all"
+	}
+	return rule.User
+}
+
+// String renders the rule as a line of the pg_hba.conf file
+func (rule PgHBARule) String() string {
+	fields := []string{string(rule.Type), rule.GetDatabase(), rule.GetUser()}
+	if rule.Type != PgHBARuleTypeLocal {
+		fields = append(fields, rule.Address)
+	}
+	fields = append(fields, string(rule.Method))
+
+	optionNames := make([]string, 0, len(rule.Options))
+	for name := range rule.Options {
+		optionNames = append(optionNames, name)
+	}
+	slices.Sort(optionNames)
+	for _, name := range optionNames {
+		value := rule.Options[name]
+		if strings.ContainsAny(value, " \t,") {
+			value = `"` + value + `"`
+		}
+		fields = append(fields, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// GetPasswordAuthentication gets the password authentication settings
+// of the cluster, if any
+func (cluster *Cluster) GetPasswordAuthentication() *PasswordAuthenticationConfiguration {
+	return cluster.Spec.PostgresConfiguration.PasswordAuthentication
+}
+
+// ShouldMigrateMD5Passwords is true when the passwords of the managed
+// roles still stored as MD5 hashes should be set again
+func (cluster *Cluster) ShouldMigrateMD5Passwords() bool {
+	passwordAuthentication := cluster.GetPasswordAuthentication()
+	return passwordAuthentication != nil && passwordAuthentication.MigrateMD5
+}
+
+// RequiresSCRAMPasswords is true when the passwords must be hashed with
+// SCRAM-SHA-256, whatever the `password_encryption` set by the user
+func (cluster *Cluster) RequiresSCRAMPasswords() bool {
+	passwordAuthentication := cluster.GetPasswordAuthentication()
+	return passwordAuthentication != nil &&
+		(passwordAuthentication.MigrateMD5 || passwordAuthentication.RequireChannelBinding)
+}
+
 // GetEnableLDAPAuth return true if bind or bind+search method are
 // configured in the cluster configuration
 func (cluster *Cluster) GetEnableLDAPAuth() bool {
@@ -2757,6 +6245,63 @@ func (cluster *Cluster) GetExternalClusterSecrets() *stringset.Data {
 	return secrets
 }
 
+// GetBarmanCredentialsSecrets returns the names of the secrets containing
+// the credentials used to access the object stores where the backups are
+// taken, and the ones of the external clusters the WAL files are restored from
+func (cluster *Cluster) GetBarmanCredentialsSecrets() *stringset.Data {
+	secrets := stringset.New()
+
+	putSecretNames := func(credentials BarmanCredentials) {
+		var selectors []*SecretKeySelector
+		if credentials.AWS != nil {
+			selectors = append(selectors,
+				credentials.AWS.AccessKeyIDReference,
+				credentials.AWS.SecretAccessKeyReference,
+				credentials.AWS.RegionReference,
+				credentials.AWS.SessionToken)
+		}
+		if credentials.Azure != nil {
+			selectors = append(selectors,
+				credentials.Azure.ConnectionString,
+				credentials.Azure.StorageAccount,
+				credentials.Azure.StorageKey,
+				credentials.Azure.StorageSasToken)
+		}
+		if credentials.Google != nil {
+			selectors = append(selectors, credentials.Google.ApplicationCredentials)
+		}
+		for _, selector := range selectors {
+			if selector != nil && selector.Name != "" {
+				secrets.Put(selector.Name)
+			}
+		}
+	}
+
+	putEncryptionSecretName := func(wal *WalBackupConfiguration) {
+		if wal != nil && wal.ClientSideEncryption != nil && wal.ClientSideEncryption.KeysSecret.Name != "" {
+			secrets.Put(wal.ClientSideEncryption.KeysSecret.Name)
+		}
+	}
+
+	if cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil {
+		putSecretNames(cluster.Spec.Backup.BarmanObjectStore.BarmanCredentials)
+		putEncryptionSecretName(cluster.Spec.Backup.BarmanObjectStore.Wal)
+		for _, objectStore := range cluster.Spec.Backup.AdditionalBarmanObjectStores {
+			putSecretNames(objectStore.BarmanCredentials)
+			putEncryptionSecretName(objectStore.Wal)
+		}
+	}
+
+	for _, externalCluster := range cluster.Spec.ExternalClusters {
+		if externalCluster.BarmanObjectStore != nil {
+			putSecretNames(externalCluster.BarmanObjectStore.BarmanCredentials)
+			putEncryptionSecretName(externalCluster.BarmanObjectStore.Wal)
+		}
+	}
+
+	return secrets
+}
+
 // UsesSecretInManagedRoles checks if the given secret name is used in a managed role
 func (cluster *Cluster) UsesSecretInManagedRoles(secretName string) bool {
 	if !cluster.ContainsManagedRolesConfiguration() {
@@ -2770,6 +6315,22 @@ func (cluster *Cluster) UsesSecretInManagedRoles(secretName string) bool {
 	return false
 }
 
+// GetClientCertificateRoles gets the managed roles that need a client
+// certificate to be issued by the operator
+func (cluster *Cluster) GetClientCertificateRoles() []RoleConfiguration {
+	if !cluster.ContainsManagedRolesConfiguration() {
+		return nil
+	}
+
+	var result []RoleConfiguration
+	for _, role := range cluster.Spec.Managed.Roles {
+		if role.ClientCertificate && role.Ensure != EnsureAbsent {
+			result = append(result, role)
+		}
+	}
+	return result
+}
+
 // GetApplicationSecretName get the name of the application secret for any bootstrap type
 func (cluster *Cluster) GetApplicationSecretName() string {
 	bootstrap := cluster.Spec.Bootstrap
@@ -2838,12 +6399,40 @@ func (cluster *Cluster) GetApplicationDatabaseOwner() string {
 	return ""
 }
 
+// GetCertificatesRotation gets the rotation settings of the certificates
+// generated by the operator, if any
+func (cluster *Cluster) GetCertificatesRotation() *CertificatesRotationConfiguration {
+	if cluster.Spec.Certificates == nil {
+		return nil
+	}
+	return cluster.Spec.Certificates.Rotation
+}
+
+// GetCertManagerConfiguration gets the cert-manager configuration of the
+// cluster, if its certificates are issued by cert-manager
+func (cluster *Cluster) GetCertManagerConfiguration() *CertManagerConfiguration {
+	if cluster.Spec.Certificates == nil {
+		return nil
+	}
+	return cluster.Spec.Certificates.CertManager
+}
+
+// UsesCertManager is true when the certificates of the cluster are
+// issued by cert-manager
+func (cluster *Cluster) UsesCertManager() bool {
+	return cluster.GetCertManagerConfiguration() != nil
+}
+
 // GetServerCASecretName get the name of the secret containing the CA
 // of the cluster
 func (cluster *Cluster) GetServerCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerCASecret != "" {
 		return cluster.Spec.Certificates.ServerCASecret
 	}
+	// cert-manager stores the CA in the issued secret
+	if cluster.UsesCertManager() {
+		return cluster.GetServerTLSSecretName()
+	}
 	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
 }
 
@@ -2897,6 +6486,39 @@ func (cluster *Cluster) GetServiceAnyName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServiceAnySuffix)
 }
 
+// IsMultiHostConnectionEnabled is true when the operator publishes the
+// multi-host connection strings of the cluster
+func (cluster *Cluster) IsMultiHostConnectionEnabled() bool {
+	return cluster.Spec.Managed != nil &&
+		cluster.Spec.Managed.Services != nil &&
+		cluster.Spec.Managed.Services.MultiHostConnection
+}
+
+// GetReadOnlyMaxLagBytes returns the maximum replay lag a replica can
+// have while still receiving read-only traffic, and whether lag-based
+// routing is enabled at all
+func (cluster *Cluster) GetReadOnlyMaxLagBytes() (int64, bool) {
+	if cluster.Spec.Managed == nil ||
+		cluster.Spec.Managed.Services == nil ||
+		cluster.Spec.Managed.Services.LagRouting == nil {
+		return 0, false
+	}
+
+	return cluster.Spec.Managed.Services.LagRouting.MaxLagBytes, true
+}
+
+// GetConnectionConfigMapName returns the name of the ConfigMap with the
+// multi-host connection strings of the cluster
+func (cluster *Cluster) GetConnectionConfigMapName() string {
+	return cluster.Name + ConnectionConfigMapSuffix
+}
+
+// GetGrafanaDashboardConfigMapName returns the name of the ConfigMap with
+// the Grafana dashboard of the cluster
+func (cluster *Cluster) GetGrafanaDashboardConfigMapName() string {
+	return cluster.Name + GrafanaDashboardConfigMapSuffix
+}
+
 // GetServiceReadName return the name of the service that is used for
 // read transactions (including the primary)
 func (cluster *Cluster) GetServiceReadName() string {
@@ -2923,6 +6545,33 @@ func (cluster *Cluster) GetMaxStartDelay() int32 {
 	return DefaultStartupDelay
 }
 
+// ApplyInto applies the content of the probe configuration in a Kubernetes
+// probe, leaving untouched the fields that have not been set
+func (p *Probe) ApplyInto(k8sProbe *corev1.Probe) {
+	if p == nil {
+		return
+	}
+
+	if p.InitialDelaySeconds != 0 {
+		k8sProbe.InitialDelaySeconds = p.InitialDelaySeconds
+	}
+	if p.TimeoutSeconds != 0 {
+		k8sProbe.TimeoutSeconds = p.TimeoutSeconds
+	}
+	if p.PeriodSeconds != 0 {
+		k8sProbe.PeriodSeconds = p.PeriodSeconds
+	}
+	if p.SuccessThreshold != 0 {
+		k8sProbe.SuccessThreshold = p.SuccessThreshold
+	}
+	if p.FailureThreshold != 0 {
+		k8sProbe.FailureThreshold = p.FailureThreshold
+	}
+	if p.TerminationGracePeriodSeconds != nil {
+		k8sProbe.TerminationGracePeriodSeconds = p.TerminationGracePeriodSeconds
+	}
+}
+
 // GetMaxStopDelay get the amount of time PostgreSQL has to stop
 func (cluster *Cluster) GetMaxStopDelay() int32 {
 	if cluster.Spec.MaxStopDelay > 0 {
@@ -2931,6 +6580,34 @@ func (cluster *Cluster) GetMaxStopDelay() int32 {
 	return 1800
 }
 
+// GetFailoverDelay gets the amount of time the primary must be unhealthy
+// before triggering a failover
+func (cluster *Cluster) GetFailoverDelay() int32 {
+	if cluster.Spec.FailoverDetection != nil &&
+		cluster.Spec.FailoverDetection.MinUnreachableDuration > cluster.Spec.FailoverDelay {
+		return cluster.Spec.FailoverDetection.MinUnreachableDuration
+	}
+	return cluster.Spec.FailoverDelay
+}
+
+// GetFailoverConsecutiveFailures gets the number of consecutive times the
+// primary must be detected to be unhealthy before triggering a failover
+func (cluster *Cluster) GetFailoverConsecutiveFailures() int32 {
+	if cluster.Spec.FailoverDetection != nil && cluster.Spec.FailoverDetection.ConsecutiveFailures > 1 {
+		return cluster.Spec.FailoverDetection.ConsecutiveFailures
+	}
+	return 1
+}
+
+// GetFlapDampingCooldown gets the amount of time since the last promotion
+// during which a failover is not triggered
+func (cluster *Cluster) GetFlapDampingCooldown() time.Duration {
+	if cluster.Spec.FailoverDetection == nil {
+		return 0
+	}
+	return time.Duration(cluster.Spec.FailoverDetection.FlapDampingCooldown) * time.Second
+}
+
 // GetSmartShutdownTimeout is used to ensure that smart shutdown timeout is a positive integer
 func (cluster *Cluster) GetSmartShutdownTimeout() int32 {
 	if cluster.Spec.SmartShutdownTimeout > 0 {
@@ -2939,6 +6616,14 @@ func (cluster *Cluster) GetSmartShutdownTimeout() int32 {
 	return 180
 }
 
+// GetFastShutdownTimeout is used to ensure that fast shutdown timeout is a positive integer
+func (cluster *Cluster) GetFastShutdownTimeout() int32 {
+	if cluster.Spec.FastShutdownTimeout > 0 {
+		return cluster.Spec.FastShutdownTimeout
+	}
+	return 60
+}
+
 // GetMaxSwitchoverDelay get the amount of time PostgreSQL has to stop before switchover
 func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	if cluster.Spec.MaxSwitchoverDelay > 0 {
@@ -2947,6 +6632,15 @@ func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	return DefaultMaxSwitchoverDelay
 }
 
+// GetReplicaSurgeTimeout get the time in seconds allowed for the new
+// replica created by the `surge` update method to catch up
+func (cluster *Cluster) GetReplicaSurgeTimeout() int32 {
+	if cluster.Spec.ReplicaSurgeTimeout > 0 {
+		return cluster.Spec.ReplicaSurgeTimeout
+	}
+	return DefaultReplicaSurgeTimeout
+}
+
 // GetPrimaryUpdateStrategy get the cluster primary update strategy,
 // defaulting to unsupervised
 func (cluster *Cluster) GetPrimaryUpdateStrategy() PrimaryUpdateStrategy {
@@ -2969,6 +6663,26 @@ func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
 	return strategy
 }
 
+// GetReplicaUpdateMethod get the cluster replica update method,
+// defaults to restart
+func (cluster *Cluster) GetReplicaUpdateMethod() ReplicaUpdateMethod {
+	if cluster.Spec.ReplicaUpdateMethod == "" {
+		return ReplicaUpdateMethodRestart
+	}
+
+	return cluster.Spec.ReplicaUpdateMethod
+}
+
+// GetSurgeInstances gets the number of instances that have been created,
+// on top of the requested ones, to replace outdated replicas
+func (cluster *Cluster) GetSurgeInstances() int {
+	if cluster.Status.ReplicaSurge == nil {
+		return 0
+	}
+
+	return 1
+}
+
 // GetEnablePDB get the cluster EnablePDB value, defaults to true
 func (cluster *Cluster) GetEnablePDB() bool {
 	if cluster.Spec.EnablePDB == nil {
@@ -2983,6 +6697,51 @@ func (cluster *Cluster) IsNodeMaintenanceWindowInProgress() bool {
 	return cluster.Spec.NodeMaintenanceWindow != nil && cluster.Spec.NodeMaintenanceWindow.InProgress
 }
 
+// GetMaintenanceWindowStatus tells whether the passed time is inside one
+// of the maintenance windows of the cluster, together with the time when
+// this will change: the end of the current window or the beginning of the
+// next one. A cluster without maintenance windows, or whose nodes are
+// being maintained, is always inside a window, and the returned time is zero
+func (cluster *Cluster) GetMaintenanceWindowStatus(now time.Time) (bool, time.Time, error) {
+	if len(cluster.Spec.MaintenanceWindows) == 0 || cluster.IsNodeMaintenanceWindowInProgress() {
+		return true, time.Time{}, nil
+	}
+
+	now = now.UTC()
+	var currentEnd, nextStart time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		schedule, err := cron.Parse(window.Schedule)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid maintenance window schedule %q: %w", window.Schedule, err)
+		}
+
+		// The first beginning of the window after the start of the
+		// latest window that could include the passed time
+		start := schedule.Next(now.Add(-window.Duration.Duration))
+		if start.IsZero() {
+			continue
+		}
+
+		if !start.After(now) {
+			end := start.Add(window.Duration.Duration)
+			if end.After(currentEnd) {
+				currentEnd = end
+			}
+			continue
+		}
+
+		if nextStart.IsZero() || start.Before(nextStart) {
+			nextStart = start
+		}
+	}
+
+	if !currentEnd.IsZero() {
+		return true, currentEnd, nil
+	}
+
+	return false, nextStart, nil
+}
+
 // GetPgCtlTimeoutForPromotion returns the timeout that should be waited for an instance to be promoted
 // to primary. As default, DefaultPgCtlTimeoutForPromotion is big enough to simulate an infinite timeout
 func (cluster *Cluster) GetPgCtlTimeoutForPromotion() int32 {
@@ -3018,11 +6777,17 @@ func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 // ShouldResizeInUseVolumes is true when we should resize PVC we already
 // created
 func (cluster *Cluster) ShouldResizeInUseVolumes() bool {
-	if cluster.Spec.StorageConfiguration.ResizeInUseVolumes == nil {
-		return true
-	}
+	return cluster.Spec.StorageConfiguration.ShouldResizeInUseVolumes()
+}
 
-	return *cluster.Spec.StorageConfiguration.ResizeInUseVolumes
+// ShouldResizeInUseVolumesFor is true when the existing PVCs created from the
+// passed storage section should be resized. The sections not setting it
+// follow the setting of spec.storage
+func (cluster *Cluster) ShouldResizeInUseVolumesFor(storage *StorageConfiguration) bool {
+	if storage == nil || storage.ResizeInUseVolumes == nil {
+		return cluster.ShouldResizeInUseVolumes()
+	}
+	return *storage.ResizeInUseVolumes
 }
 
 // ShouldCreateApplicationSecret returns true if for this cluster,
@@ -3149,6 +6914,14 @@ func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
 }
 
+// IsWalStorageEmergencyCleanupEnabled checks whether the instance manager
+// should free space in the WAL volume when it is about to be full
+func (cluster *Cluster) IsWalStorageEmergencyCleanupEnabled() bool {
+	return cluster.ShouldCreateWalArchiveVolume() &&
+		cluster.Spec.WalStorage.EmergencyCleanup != nil &&
+		cluster.Spec.WalStorage.EmergencyCleanup.Enabled
+}
+
 // ContainsTablespaces returns true if for this cluster, we need to create tablespaces
 func (cluster *Cluster) ContainsTablespaces() bool {
 	return len(cluster.Spec.Tablespaces) != 0
@@ -3186,7 +6959,37 @@ func (cluster Cluster) ExternalCluster(name string) (ExternalCluster, bool) {
 
 // IsReplica checks if this is a replica cluster or not
 func (cluster Cluster) IsReplica() bool {
-	return cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled
+	replicaCluster := cluster.Spec.ReplicaCluster
+	if replicaCluster == nil {
+		return false
+	}
+
+	if replicaCluster.Primary != "" {
+		return replicaCluster.Primary != cluster.GetReplicaClusterSelf()
+	}
+
+	return replicaCluster.Enabled
+}
+
+// GetPromotionToken gets the token allowing the designated primary of
+// this cluster to be promoted, giving precedence to the one set in the
+// specification over the one found by the operator
+func (cluster Cluster) GetPromotionToken() string {
+	if cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.PromotionToken != "" {
+		return cluster.Spec.ReplicaCluster.PromotionToken
+	}
+
+	return cluster.Status.PromotionToken
+}
+
+// GetReplicaClusterSelf gets the name of this cluster in a distributed
+// topology, defaulting to the name of the Cluster resource
+func (cluster Cluster) GetReplicaClusterSelf() string {
+	if cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Self != "" {
+		return cluster.Spec.ReplicaCluster.Self
+	}
+
+	return cluster.Name
 }
 
 var slotNameNegativeRegex = regexp.MustCompile("[^a-z0-9_]+")
@@ -3256,6 +7059,12 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 		return true
 	}
 
+	// The secret issued by cert-manager is used before being
+	// recorded in the status, as the instances wait for it
+	if cluster.UsesCertManager() && secret == cluster.GetServerTLSSecretName() {
+		return true
+	}
+
 	if cluster.UsesSecretInManagedRoles(secret) {
 		return true
 	}
@@ -3268,12 +7077,27 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 		return true
 	}
 
+	if tdeSecret := cluster.GetTDESecretKeyRef(); tdeSecret != nil && tdeSecret.Name == secret {
+		return true
+	}
+
 	if cluster.Status.PoolerIntegrations != nil {
 		for _, pgBouncerSecretName := range cluster.Status.PoolerIntegrations.PgBouncerIntegration.Secrets {
 			if pgBouncerSecretName == secret {
 				return true
 			}
 		}
+		for _, user := range cluster.Status.PoolerIntegrations.PgBouncerIntegration.AuthQueryUsers {
+			if user.PasswordSecret != nil && user.PasswordSecret.Name == secret {
+				return true
+			}
+		}
+	}
+
+	// watch the secrets used to access the backup object stores, whose
+	// changes need to be propagated to the WAL archiver
+	if cluster.GetBarmanCredentialsSecrets().Has(secret) {
+		return true
 	}
 
 	// watch the secrets defined in external clusters
@@ -3297,6 +7121,25 @@ func (cluster *Cluster) IsPodMonitorEnabled() bool {
 	return false
 }
 
+// IsPrometheusRuleEnabled checks if the PrometheusRule object needs to be created
+func (cluster *Cluster) IsPrometheusRuleEnabled() bool {
+	if cluster.Spec.Monitoring != nil {
+		return cluster.Spec.Monitoring.PrometheusRule.IsEnabled()
+	}
+
+	return false
+}
+
+// IsGrafanaDashboardEnabled checks if the ConfigMap with the Grafana
+// dashboard needs to be created
+func (cluster *Cluster) IsGrafanaDashboardEnabled() bool {
+	if cluster.Spec.Monitoring != nil {
+		return cluster.Spec.Monitoring.GrafanaDashboard.IsEnabled()
+	}
+
+	return false
+}
+
 // GetEnableSuperuserAccess returns if the superuser access is enabled or not
 func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	if cluster.Spec.EnableSuperuserAccess != nil {
@@ -3306,6 +7149,40 @@ func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	return false
 }
 
+// GetSuperuserPasswordFile gets the path of the file containing the
+// password of the superuser, mounted by the Secrets Store CSI driver,
+// or an empty string when the password comes from a secret
+func (cluster *Cluster) GetSuperuserPasswordFile() string {
+	if cluster.Spec.SecretsStore == nil || cluster.Spec.SecretsStore.SuperuserPasswordFile == "" {
+		return ""
+	}
+
+	return path.Join(SecretsStoreMountPath, cluster.Spec.SecretsStore.SuperuserPasswordFile)
+}
+
+// GetApplicationPasswordFile gets the path of the file containing the
+// password of the application user, mounted by the Secrets Store CSI
+// driver, or an empty string when the password comes from a secret
+func (cluster *Cluster) GetApplicationPasswordFile() string {
+	if cluster.Spec.SecretsStore == nil || cluster.Spec.SecretsStore.ApplicationPasswordFile == "" {
+		return ""
+	}
+
+	return path.Join(SecretsStoreMountPath, cluster.Spec.SecretsStore.ApplicationPasswordFile)
+}
+
+// IsSuperuserSecretInUse checks whether the password of the superuser
+// comes from a secret, rather than from the Secrets Store CSI driver
+func (cluster *Cluster) IsSuperuserSecretInUse() bool {
+	return cluster.GetEnableSuperuserAccess() && cluster.GetSuperuserPasswordFile() == ""
+}
+
+// IsApplicationSecretInUse checks whether the password of the application
+// user comes from a secret, rather than from the Secrets Store CSI driver
+func (cluster *Cluster) IsApplicationSecretInUse() bool {
+	return cluster.ShouldCreateApplicationDatabase() && cluster.GetApplicationPasswordFile() == ""
+}
+
 // LogTimestampsWithMessage prints useful information about timestamps in stdout
 func (cluster *Cluster) LogTimestampsWithMessage(ctx context.Context, logMessage string) {
 	contextLogger := log.FromContext(ctx)
@@ -3413,6 +7290,36 @@ func (cluster *Cluster) GetCoredumpFilter() string {
 	return system.DefaultCoredumpFilter
 }
 
+// IsTDEEnabled checks if the transparent data encryption is enabled
+func (cluster *Cluster) IsTDEEnabled() bool {
+	return cluster.Spec.PostgresConfiguration.TDE != nil && cluster.Spec.PostgresConfiguration.TDE.Enabled
+}
+
+// GetTDESecretKeyRef gets the reference to the secret containing the TDE
+// passphrase, or nil if the transparent data encryption is not enabled
+func (cluster *Cluster) GetTDESecretKeyRef() *SecretKeySelector {
+	if !cluster.IsTDEEnabled() {
+		return nil
+	}
+	return cluster.Spec.PostgresConfiguration.TDE.SecretKeyRef
+}
+
+// GetTDEPassphrasesSecretName gets the name of the secret where the operator
+// keeps the TDE passphrases used before a rotation, until every instance has
+// wrapped its data encryption key with the current one
+func (cluster *Cluster) GetTDEPassphrasesSecretName() string {
+	return cluster.Name + TDEPassphrasesSecretSuffix
+}
+
+// GetRejoinStrategy gets the strategy used by a former primary to rejoin
+// the cluster, defaulting to rewind
+func (cluster *Cluster) GetRejoinStrategy() RejoinStrategy {
+	if cluster.Spec.RejoinStrategy == "" {
+		return RejoinStrategyRewind
+	}
+	return cluster.Spec.RejoinStrategy
+}
+
 // IsInplaceRestartPhase returns true if the cluster is in a phase that handles the Inplace restart
 func (cluster *Cluster) IsInplaceRestartPhase() bool {
 	return cluster.Status.Phase == PhaseInplacePrimaryRestart ||
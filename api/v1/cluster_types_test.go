@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -333,6 +335,62 @@ var _ = Describe("resize in use volumes", func() {
 		}
 		Expect(cluster.ShouldResizeInUseVolumes()).To(BeFalse())
 	})
+
+	It("is configured independently for the WAL volume", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					ResizeInUseVolumes: ptr.To(false),
+				},
+				WalStorage: &StorageConfiguration{
+					ResizeInUseVolumes: ptr.To(true),
+				},
+			},
+		}
+		Expect(cluster.ShouldResizeInUseVolumes()).To(BeFalse())
+		Expect(cluster.ShouldResizeInUseVolumesFor(cluster.Spec.WalStorage)).To(BeTrue())
+	})
+
+	It("follows the storage section when not set for the WAL volume", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					ResizeInUseVolumes: ptr.To(false),
+				},
+				WalStorage: &StorageConfiguration{},
+			},
+		}
+		Expect(cluster.ShouldResizeInUseVolumesFor(cluster.Spec.WalStorage)).To(BeFalse())
+	})
+})
+
+var _ = Describe("WAL storage emergency cleanup", func() {
+	It("is disabled without a WAL volume", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					EmergencyCleanup: &StorageEmergencyCleanupConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.IsWalStorageEmergencyCleanupEnabled()).To(BeFalse())
+	})
+
+	It("uses the default threshold", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{
+					EmergencyCleanup: &StorageEmergencyCleanupConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.IsWalStorageEmergencyCleanupEnabled()).To(BeTrue())
+		Expect(cluster.Spec.WalStorage.EmergencyCleanup.GetThreshold()).
+			To(Equal(DefaultStorageEmergencyCleanupThreshold))
+
+		cluster.Spec.WalStorage.EmergencyCleanup.Threshold = 80
+		Expect(cluster.Spec.WalStorage.EmergencyCleanup.GetThreshold()).To(Equal(80))
+	})
 })
 
 var _ = Describe("external cluster list", func() {
@@ -469,6 +527,40 @@ var _ = Describe("look up for secrets", func() {
 	})
 })
 
+var _ = Describe("look up for the secrets issued by cert-manager", func() {
+	cluster := Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "clustername",
+		},
+		Spec: ClusterSpec{
+			Certificates: &CertificatesConfiguration{
+				CertManager: &CertManagerConfiguration{
+					IssuerRef: CertManagerIssuerReference{Name: "issuer"},
+				},
+			},
+		},
+	}
+
+	It("uses the issued secret as server CA and its own client CA", func() {
+		Expect(cluster.UsesCertManager()).To(BeTrue())
+		Expect(cluster.GetServerCASecretName()).To(Equal("clustername-server"))
+		Expect(cluster.GetClientCASecretName()).To(Equal("clustername-ca"))
+	})
+
+	It("uses the issued secret before it is recorded in the status", func() {
+		Expect(cluster.UsesSecret("clustername-server")).To(BeTrue())
+		Expect(cluster.UsesSecret("clustername-replication")).To(BeFalse())
+	})
+
+	It("uses the CA secrets provided by the user", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Certificates.ServerCASecret = "server-ca"
+		cluster.Spec.Certificates.ClientCASecret = "client-ca"
+		Expect(cluster.GetServerCASecretName()).To(Equal("server-ca"))
+		Expect(cluster.GetClientCASecretName()).To(Equal("client-ca"))
+	})
+})
+
 var _ = Describe("A secret resource version", func() {
 	It("do not contains any secret", func() {
 		cluster := Cluster{
@@ -618,6 +710,96 @@ var _ = Describe("A secret resource version", func() {
 		Expect(found).To(BeTrue())
 	})
 
+	It("contains the secrets of the barman credentials", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "clustername",
+			},
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{
+								AccessKeyIDReference: &SecretKeySelector{
+									LocalObjectReference: LocalObjectReference{Name: "aws-secret"},
+									Key:                  "ACCESS_KEY_ID",
+								},
+							},
+						},
+					},
+					AdditionalBarmanObjectStores: []NamedBarmanObjectStoreConfiguration{
+						{
+							Name: "dr",
+							BarmanObjectStoreConfiguration: BarmanObjectStoreConfiguration{
+								BarmanCredentials: BarmanCredentials{
+									Google: &GoogleCredentials{
+										ApplicationCredentials: &SecretKeySelector{
+											LocalObjectReference: LocalObjectReference{Name: "google-dr-secret"},
+											Key:                  "gcsCredentials",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetBarmanCredentialsSecrets().ToSortedList()).
+			To(Equal([]string{"aws-secret", "google-dr-secret"}))
+		Expect(cluster.UsesSecret("aws-secret")).To(BeTrue())
+		Expect(cluster.UsesSecret("google-dr-secret")).To(BeTrue())
+		Expect(cluster.UsesSecret("unknown-secret")).To(BeFalse())
+	})
+
+	It("contains the secrets of the object stores of the external clusters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "origin",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							BarmanCredentials: BarmanCredentials{
+								Azure: &AzureCredentials{
+									StorageAccount: &SecretKeySelector{
+										LocalObjectReference: LocalObjectReference{Name: "azure-origin-secret"},
+										Key:                  "account",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetBarmanCredentialsSecrets().ToSortedList()).
+			To(Equal([]string{"azure-origin-secret"}))
+		Expect(cluster.UsesSecret("azure-origin-secret")).To(BeTrue())
+	})
+
+	It("contains the secrets of the WAL encryption keys", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "origin",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							Wal: &WalBackupConfiguration{
+								ClientSideEncryption: &WALClientSideEncryption{
+									KeysSecret: LocalObjectReference{Name: "wal-keys"},
+									ActiveKey:  "current",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetBarmanCredentialsSecrets().ToSortedList()).
+			To(Equal([]string{"wal-keys"}))
+		Expect(cluster.Spec.ExternalClusters[0].GetSecretsNames().Has("wal-keys")).To(BeTrue())
+	})
+
 	It("contains the barman endpoint ca secret", func() {
 		cluster := Cluster{
 			ObjectMeta: metav1.ObjectMeta{
@@ -771,6 +953,147 @@ var _ = Describe("Default Metrics", func() {
 	})
 })
 
+var _ = Describe("Query statistics", func() {
+	It("is disabled when no monitoring is passed", func() {
+		var monitoring *MonitoringConfiguration
+		Expect(monitoring.IsQueryStatisticsEnabled()).To(BeFalse())
+		Expect(monitoring.GetQueryStatisticsTopQueries()).To(Equal(DefaultQueryStatisticsTopQueries))
+	})
+
+	It("uses the configured number of top queries", func() {
+		monitoring := &MonitoringConfiguration{
+			QueryStatistics: &QueryStatisticsConfiguration{Enabled: true, TopQueries: 50},
+		}
+		Expect(monitoring.IsQueryStatisticsEnabled()).To(BeTrue())
+		Expect(monitoring.GetQueryStatisticsTopQueries()).To(Equal(50))
+	})
+})
+
+var _ = Describe("Object sizes", func() {
+	It("is disabled when no monitoring is passed", func() {
+		var monitoring *MonitoringConfiguration
+		Expect(monitoring.IsObjectSizesEnabled()).To(BeFalse())
+
+		var objectSizes *ObjectSizesConfiguration
+		Expect(objectSizes.GetRefreshInterval()).To(Equal(DefaultObjectSizesRefreshInterval * time.Second))
+		Expect(objectSizes.GetMaxTables()).To(Equal(DefaultObjectSizesMaxTables))
+	})
+
+	It("uses the configured refresh interval and number of tables", func() {
+		monitoring := &MonitoringConfiguration{
+			ObjectSizes: &ObjectSizesConfiguration{Enabled: true, RefreshInterval: 60, MaxTables: 10},
+		}
+		Expect(monitoring.IsObjectSizesEnabled()).To(BeTrue())
+		Expect(monitoring.ObjectSizes.GetRefreshInterval()).To(Equal(time.Minute))
+		Expect(monitoring.ObjectSizes.GetMaxTables()).To(Equal(10))
+	})
+
+	It("includes every object when no pattern is set", func() {
+		filter, err := (&ObjectSizesConfiguration{Enabled: true}).GetFilter()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter.IsDatabaseIncluded("app")).To(BeTrue())
+		Expect(filter.IsTableIncluded("public", "orders")).To(BeTrue())
+
+		var nilConfiguration *ObjectSizesConfiguration
+		filter, err = nilConfiguration.GetFilter()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter.IsTableIncluded("public", "orders")).To(BeTrue())
+	})
+
+	It("applies the include and exclude patterns", func() {
+		objectSizes := &ObjectSizesConfiguration{
+			IncludeDatabases: []string{"^app"},
+			ExcludeDatabases: []string{"_test$"},
+			IncludeTables:    []string{`^public\.`, `^sales\.`},
+			ExcludeTables:    []string{`\.tmp_`},
+		}
+		filter, err := objectSizes.GetFilter()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter.IsDatabaseIncluded("app")).To(BeTrue())
+		Expect(filter.IsDatabaseIncluded("app_test")).To(BeFalse())
+		Expect(filter.IsDatabaseIncluded("postgres")).To(BeFalse())
+		Expect(filter.IsTableIncluded("sales", "orders")).To(BeTrue())
+		Expect(filter.IsTableIncluded("public", "tmp_orders")).To(BeFalse())
+		Expect(filter.IsTableIncluded("audit", "orders")).To(BeFalse())
+	})
+
+	It("reports invalid patterns", func() {
+		objectSizes := &ObjectSizesConfiguration{ExcludeTables: []string{"([a-z"}}
+		_, err := objectSizes.GetFilter()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("pgaudit output", func() {
+	It("combines the audit records when pgaudit is not enabled", func() {
+		configuration := &PostgresConfiguration{
+			PgAudit: &PgAuditConfiguration{Output: PgAuditOutputDedicated},
+		}
+		Expect(configuration.IsPgAuditEnabled()).To(BeFalse())
+		Expect(configuration.GetPgAuditOutput()).To(Equal(PgAuditOutputCombined))
+	})
+
+	It("uses the configured output", func() {
+		configuration := &PostgresConfiguration{
+			PgAudit: &PgAuditConfiguration{Enabled: true},
+		}
+		Expect(configuration.GetPgAuditOutput()).To(Equal(PgAuditOutputCombined))
+
+		configuration.PgAudit.Output = PgAuditOutputDedicated
+		Expect(configuration.GetPgAuditOutput()).To(Equal(PgAuditOutputDedicated))
+	})
+})
+
+var _ = Describe("Declared extensions", func() {
+	It("uses the listed databases", func() {
+		extension := ExtensionConfiguration{Name: "postgis", Databases: []string{"one", "two"}}
+		Expect(extension.GetDatabases("app")).To(Equal([]string{"one", "two"}))
+	})
+
+	It("defaults to the application database", func() {
+		extension := ExtensionConfiguration{Name: "postgis"}
+		Expect(extension.GetDatabases("app")).To(Equal([]string{"app"}))
+		Expect(extension.GetDatabases("")).To(Equal([]string{"postgres"}))
+	})
+})
+
+var _ = Describe("Replica cluster in a distributed topology", func() {
+	It("follows the enabled flag when no primary is set", func() {
+		cluster := Cluster{Spec: ClusterSpec{ReplicaCluster: &ReplicaClusterConfiguration{Enabled: true}}}
+		Expect(cluster.IsReplica()).To(BeTrue())
+
+		cluster.Spec.ReplicaCluster.Enabled = false
+		Expect(cluster.IsReplica()).To(BeFalse())
+	})
+
+	It("is a replica unless it is the primary of the topology", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
+			Spec: ClusterSpec{ReplicaCluster: &ReplicaClusterConfiguration{
+				Enabled: true,
+				Primary: "cluster-a",
+			}},
+		}
+		Expect(cluster.GetReplicaClusterSelf()).To(Equal("cluster-a"))
+		Expect(cluster.IsReplica()).To(BeFalse())
+
+		cluster.Spec.ReplicaCluster.Primary = "cluster-b"
+		Expect(cluster.IsReplica()).To(BeTrue())
+	})
+
+	It("uses the self name when set", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
+			Spec: ClusterSpec{ReplicaCluster: &ReplicaClusterConfiguration{
+				Primary: "eu-south",
+				Self:    "eu-south",
+			}},
+		}
+		Expect(cluster.GetReplicaClusterSelf()).To(Equal("eu-south"))
+		Expect(cluster.IsReplica()).To(BeFalse())
+	})
+})
+
 var _ = Describe("Barman Endpoint CA for replica cluster", func() {
 	cluster1 := Cluster{}
 	It("is empty if cluster is not replica", func() {
@@ -956,6 +1279,27 @@ var _ = Describe("Managed Roles", func() {
 		}
 		Expect(cluster.ContainsManagedRolesConfiguration()).To(BeFalse())
 		Expect(cluster.UsesSecretInManagedRoles("test_user_secrets")).To(BeFalse())
+		Expect(cluster.GetClientCertificateRoles()).To(BeEmpty())
+	})
+
+	It("lists the roles requiring a client certificate", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{Name: "Batch_Loader", Login: true, ClientCertificate: true},
+						{Name: "removed", Login: true, ClientCertificate: true, Ensure: EnsureAbsent},
+						{Name: "password_only", Login: true},
+					},
+				},
+			},
+		}
+		roles := cluster.GetClientCertificateRoles()
+		Expect(roles).To(HaveLen(1))
+		Expect(roles[0].Name).To(Equal("Batch_Loader"))
+		Expect(roles[0].GetClientCertificateSecretName(cluster.Name)).To(
+			Equal("cluster-example-batch-loader-client"))
 	})
 })
 
@@ -1093,6 +1437,18 @@ var _ = Describe("Tablespaces", func() {
 	})
 })
 
+var _ = Describe("Rejoin strategy", func() {
+	It("defaults to rewind", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetRejoinStrategy()).To(Equal(RejoinStrategyRewind))
+	})
+
+	It("returns the configured strategy", func() {
+		cluster := Cluster{Spec: ClusterSpec{RejoinStrategy: RejoinStrategyObjectStore}}
+		Expect(cluster.GetRejoinStrategy()).To(Equal(RejoinStrategyObjectStore))
+	})
+})
+
 var _ = Describe("SynchronizeReplicasConfiguration", func() {
 	var synchronizeReplicas *SynchronizeReplicasConfiguration
 
@@ -1201,6 +1557,37 @@ var _ = Describe("SynchronizeReplicasConfiguration", func() {
 	})
 })
 
+var _ = Describe("InactiveSlotsConfiguration", func() {
+	It("has sensible defaults", func() {
+		var inactiveSlots *InactiveSlotsConfiguration
+		Expect(inactiveSlots.GetRetentionTime()).To(Equal(time.Hour))
+		Expect(inactiveSlots.GetDrop()).To(BeFalse())
+		Expect(inactiveSlots.IsExcluded("someSlot")).To(BeFalse())
+
+		var replicationSlots *ReplicationSlotsConfiguration
+		Expect(replicationSlots.GetInactiveSlots()).To(BeNil())
+		Expect(replicationSlots.GetUpdateInterval()).To(Equal(30 * time.Second))
+	})
+
+	It("uses the configured values", func() {
+		inactiveSlots := &InactiveSlotsConfiguration{
+			RetentionTime:   600,
+			Drop:            true,
+			ExcludePatterns: []string{"^debezium_"},
+		}
+		Expect(inactiveSlots.GetRetentionTime()).To(Equal(10 * time.Minute))
+		Expect(inactiveSlots.GetDrop()).To(BeTrue())
+		Expect(inactiveSlots.IsExcluded("debezium_orders")).To(BeTrue())
+		Expect(inactiveSlots.IsExcluded("orders")).To(BeFalse())
+	})
+
+	It("returns an error in case of an invalid pattern", func() {
+		inactiveSlots := &InactiveSlotsConfiguration{ExcludePatterns: []string{"([a-z"}}
+		_, err := inactiveSlots.IsExcluded("test")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("AvailableArchitectures", func() {
 	cluster := Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1225,3 +1612,350 @@ var _ = Describe("AvailableArchitectures", func() {
 		Expect(availableArch).To(BeNil())
 	})
 })
+
+var _ = Describe("WAL archive parallelism", func() {
+	It("archives one WAL file at a time when nothing is configured", func() {
+		var walConfig *WalBackupConfiguration
+		Expect(walConfig.GetArchiveParallelism(100)).To(Equal(1))
+		Expect((&WalBackupConfiguration{}).GetArchiveParallelism(100)).To(Equal(1))
+	})
+
+	It("uses maxParallel when no burst is configured", func() {
+		walConfig := &WalBackupConfiguration{MaxParallel: 4}
+		Expect(walConfig.GetArchiveParallelism(100)).To(Equal(4))
+	})
+
+	It("grows the parallelism with the backlog up to the burst", func() {
+		walConfig := &WalBackupConfiguration{MaxParallel: 4, MaxParallelBurst: 16}
+		Expect(walConfig.GetArchiveParallelism(3)).To(Equal(4))
+		Expect(walConfig.GetArchiveParallelism(8)).To(Equal(4))
+		Expect(walConfig.GetArchiveParallelism(12)).To(Equal(12))
+		Expect(walConfig.GetArchiveParallelism(100)).To(Equal(16))
+	})
+})
+
+var _ = Describe("pg_hba rules", func() {
+	It("renders the structured rules before the free-form ones", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{"host all all 0.0.0.0/0 reject"},
+					PgHBARules: []PgHBARule{
+						{
+							Type:     PgHBARuleTypeHostSSL,
+							Database: "app",
+							User:     "app,analytics",
+							Address:  "10.0.0.0/8",
+							Method:   PgHBAMethodScramSHA256,
+						},
+						{
+							Type:   PgHBARuleTypeLocal,
+							Method: PgHBAMethodPeer,
+							Options: map[string]string{
+								"map": "local",
+							},
+						},
+						{
+							Type:    PgHBARuleTypeHost,
+							Address: ".example.com",
+							Method:  PgHBAMethodLDAP,
+							Options: map[string]string{
+								"ldapserver":       "ldap.example.com",
+								"ldapsearchfilter": "(|(uid=$username)(mail=$username))",
+								"ldapbasedn":       "dc=example, dc=com",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(cluster.GetPgHBA()).To(Equal([]string{
+			"hostssl app app,analytics 10.0.0.0/8 scram-sha-256",
+			"local all all peer map=local",
+			`host all all .example.com ldap ldapbasedn="dc=example, dc=com" ` +
+				"ldapsearchfilter=(|(uid=$username)(mail=$username)) ldapserver=ldap.example.com",
+			"host all all 0.0.0.0/0 reject",
+		}))
+	})
+
+	It("uses the free-form rules when no structured one is defined", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{"host all all 0.0.0.0/0 reject"},
+				},
+			},
+		}
+		Expect(cluster.GetPgHBA()).To(Equal([]string{"host all all 0.0.0.0/0 reject"}))
+	})
+})
+
+var _ = Describe("scheduling architecture", func() {
+	archRequirement := func(values ...string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{
+			Key:      corev1.LabelArchStable,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   values,
+		}
+	}
+	clusterWithTerms := func(terms ...corev1.NodeSelectorTerm) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: terms,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("is unknown when the pods are not constrained", func() {
+		Expect((&Cluster{}).GetSchedulingArchitecture()).To(BeEmpty())
+	})
+
+	It("is detected from the node selector", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					NodeSelector: map[string]string{corev1.LabelArchStable: "arm64"},
+				},
+			},
+		}
+		Expect(cluster.GetSchedulingArchitecture()).To(Equal("arm64"))
+	})
+
+	It("is detected from the required node affinity", func() {
+		cluster := clusterWithTerms(
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement("arm64")}},
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "workload", Operator: corev1.NodeSelectorOpExists},
+				archRequirement("arm64"),
+			}},
+		)
+		Expect(cluster.GetSchedulingArchitecture()).To(Equal("arm64"))
+	})
+
+	It("is unknown when the node affinity allows more than one architecture", func() {
+		Expect(clusterWithTerms(
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement("arm64", "amd64")}},
+		).GetSchedulingArchitecture()).To(BeEmpty())
+
+		Expect(clusterWithTerms(
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement("arm64")}},
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement("amd64")}},
+		).GetSchedulingArchitecture()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("additional object stores", func() {
+	backupConfiguration := &BackupConfiguration{
+		BarmanObjectStore: &BarmanObjectStoreConfiguration{DestinationPath: "s3://main/"},
+		AdditionalBarmanObjectStores: []NamedBarmanObjectStoreConfiguration{
+			{
+				Name:                           "dr",
+				BarmanObjectStoreConfiguration: BarmanObjectStoreConfiguration{DestinationPath: "s3://dr/"},
+			},
+		},
+	}
+
+	It("gets the main object store with an empty name", func() {
+		Expect(backupConfiguration.GetBarmanObjectStore("").DestinationPath).To(Equal("s3://main/"))
+	})
+
+	It("gets an additional object store by name", func() {
+		Expect(backupConfiguration.GetBarmanObjectStore("dr").DestinationPath).To(Equal("s3://dr/"))
+	})
+
+	It("returns nil for unknown object stores", func() {
+		Expect(backupConfiguration.GetBarmanObjectStore("unknown")).To(BeNil())
+		Expect((*BackupConfiguration)(nil).GetBarmanObjectStore("")).To(BeNil())
+	})
+
+	It("gets the type of the continuous archiving condition", func() {
+		Expect(GetContinuousArchivingConditionType("")).To(Equal(string(ConditionContinuousArchiving)))
+		Expect(GetContinuousArchivingConditionType("dr")).To(Equal("ContinuousArchiving-dr"))
+	})
+})
+
+var _ = Describe("Maintenance windows", func() {
+	// Every Saturday at 02:00 UTC, for four hours
+	saturdayNight := MaintenanceWindow{
+		Schedule: "0 0 2 * * 6",
+		Duration: metav1.Duration{Duration: 4 * time.Hour},
+	}
+	// Every day at 12:00 UTC, for one hour
+	lunchTime := MaintenanceWindow{
+		Schedule: "0 0 12 * * *",
+		Duration: metav1.Duration{Duration: time.Hour},
+	}
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.June, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	It("considers a cluster without maintenance windows always inside one", func() {
+		cluster := &Cluster{}
+		inside, nextChange, err := cluster.GetMaintenanceWindowStatus(saturday(10, 0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inside).To(BeTrue())
+		Expect(nextChange).To(BeZero())
+	})
+
+	It("considers the node maintenance in progress as a maintenance window", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindows:    []MaintenanceWindow{saturdayNight},
+				NodeMaintenanceWindow: &NodeMaintenanceWindow{InProgress: true},
+			},
+		}
+		inside, _, err := cluster.GetMaintenanceWindowStatus(saturday(10, 0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inside).To(BeTrue())
+	})
+
+	It("detects the current window and when it ends", func() {
+		cluster := &Cluster{Spec: ClusterSpec{MaintenanceWindows: []MaintenanceWindow{saturdayNight, lunchTime}}}
+
+		inside, nextChange, err := cluster.GetMaintenanceWindowStatus(saturday(2, 0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inside).To(BeTrue())
+		Expect(nextChange).To(Equal(saturday(6, 0)))
+
+		inside, nextChange, err = cluster.GetMaintenanceWindowStatus(saturday(12, 30))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inside).To(BeTrue())
+		Expect(nextChange).To(Equal(saturday(13, 0)))
+	})
+
+	It("detects when the next window begins", func() {
+		cluster := &Cluster{Spec: ClusterSpec{MaintenanceWindows: []MaintenanceWindow{saturdayNight, lunchTime}}}
+
+		inside, nextChange, err := cluster.GetMaintenanceWindowStatus(saturday(6, 0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inside).To(BeFalse())
+		Expect(nextChange).To(Equal(saturday(12, 0)))
+
+		inside, nextChange, err = cluster.GetMaintenanceWindowStatus(saturday(1, 59))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inside).To(BeFalse())
+		Expect(nextChange).To(Equal(saturday(2, 0)))
+	})
+
+	It("fails with an invalid schedule", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindows: []MaintenanceWindow{{Schedule: "wrong", Duration: metav1.Duration{Duration: time.Hour}}},
+			},
+		}
+		_, _, err := cluster.GetMaintenanceWindowStatus(saturday(10, 0))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Secrets Store password files", func() {
+	It("returns no file when the Secrets Store is not configured", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetSuperuserPasswordFile()).To(BeEmpty())
+		Expect(cluster.GetApplicationPasswordFile()).To(BeEmpty())
+	})
+
+	It("returns the files inside the mounted volume", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				SecretsStore: &SecretsStoreConfiguration{
+					SecretProviderClass:   "vault-passwords",
+					SuperuserPasswordFile: "superuser-password",
+				},
+			},
+		}
+		Expect(cluster.GetSuperuserPasswordFile()).To(Equal("/etc/secrets-store/superuser-password"))
+		Expect(cluster.GetApplicationPasswordFile()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("PostgreSQL parameters", func() {
+	It("loads pg_stat_statements when the query statistics are enabled", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			Monitoring: &MonitoringConfiguration{
+				QueryStatistics: &QueryStatisticsConfiguration{Enabled: true},
+			},
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters: map[string]string{"shared_buffers": "1GB"},
+			},
+		}}
+		Expect(cluster.GetPostgresParameters()).To(Equal(map[string]string{
+			"shared_buffers":           "1GB",
+			"pg_stat_statements.track": "top",
+		}))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveLen(1))
+
+		cluster.Spec.Monitoring.QueryStatistics.Enabled = false
+		Expect(cluster.GetPostgresParameters()).To(Equal(map[string]string{"shared_buffers": "1GB"}))
+	})
+
+	It("preserves the pg_stat_statements configuration of the user", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			Monitoring: &MonitoringConfiguration{
+				QueryStatistics: &QueryStatisticsConfiguration{Enabled: true},
+			},
+			PostgresConfiguration: PostgresConfiguration{
+				Parameters: map[string]string{"pg_stat_statements.max": "10000"},
+			},
+		}}
+		Expect(cluster.GetPostgresParameters()).To(Equal(map[string]string{"pg_stat_statements.max": "10000"}))
+	})
+
+	It("returns the user parameters when pgaudit is not enabled", func() {
+		configuration := PostgresConfiguration{
+			Parameters: map[string]string{"shared_buffers": "1GB"},
+			PgAudit:    &PgAuditConfiguration{LogRelation: true},
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{"shared_buffers": "1GB"}))
+	})
+
+	It("adds the pgaudit parameters from the default configuration", func() {
+		configuration := PostgresConfiguration{
+			PgAudit: &PgAuditConfiguration{Enabled: true},
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"pgaudit.log":                "ddl, role",
+			"pgaudit.log_catalog":        "on",
+			"pgaudit.log_parameter":      "off",
+			"pgaudit.log_relation":       "off",
+			"pgaudit.log_statement_once": "off",
+		}))
+		Expect(configuration.Parameters).To(BeNil())
+	})
+
+	It("overrides the pgaudit parameters set by the user without changing them", func() {
+		configuration := PostgresConfiguration{
+			Parameters: map[string]string{
+				"pgaudit.log":    "all",
+				"shared_buffers": "1GB",
+			},
+			PgAudit: &PgAuditConfiguration{
+				Enabled:      true,
+				Log:          []string{"all", "-misc"},
+				LogCatalog:   ptr.To(false),
+				LogParameter: true,
+				Role:         "auditor",
+			},
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"shared_buffers":             "1GB",
+			"pgaudit.log":                "all, -misc",
+			"pgaudit.log_catalog":        "off",
+			"pgaudit.log_parameter":      "on",
+			"pgaudit.log_relation":       "off",
+			"pgaudit.log_statement_once": "off",
+			"pgaudit.role":               "auditor",
+		}))
+		Expect(configuration.Parameters).To(Equal(map[string]string{
+			"pgaudit.log":    "all",
+			"shared_buffers": "1GB",
+		}))
+	})
+})
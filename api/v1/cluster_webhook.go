@@ -20,10 +20,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v7/apis/volumesnapshot/v1"
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -308,7 +313,10 @@ var _ webhook.Validator = &Cluster{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateCreate() (admission.Warnings, error) {
 	clusterLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
-	allErrs := r.Validate()
+	allErrs := append(
+		r.Validate(),
+		r.validateUnsafeParameters(nil)...,
+	)
 
 	// Call the plugins to help validating this cluster creation
 	ctx := context.Background()
@@ -360,25 +368,48 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateWalStorageSize,
 		r.validateEphemeralVolumeSource,
 		r.validateTablespaceStorageSize,
+		r.validateStorageEmergencyCleanup,
 		r.validateName,
 		r.validateTablespaceNames,
 		r.validateBootstrapPgBaseBackupSource,
 		r.validateTablespaceBackupSnapshot,
 		r.validateBootstrapRecoverySource,
 		r.validateBootstrapRecoveryDataSource,
+		r.validateBootstrapRecoveryVolumeSource,
 		r.validateExternalClusters,
 		r.validateTolerations,
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
+		r.validateAdditionalBarmanObjectStores,
 		r.validateConfiguration,
+		r.validateReplicaParameters,
 		r.validateLDAP,
+		r.validateGSSAPI,
+		r.validatePasswordAuthentication,
+		r.validatePgHBARules,
 		r.validateReplicationSlots,
+		r.validateInactiveReplicationSlots,
+		r.validateObjectSizes,
+		r.validateReplicaClone,
 		r.validateEnv,
 		r.validateManagedRoles,
+		r.validateManagedServices,
 		r.validateManagedExtensions,
 		r.validateResources,
+		r.validateProbes,
+		r.validateRejoinStrategy,
+		r.validateFailoverArbiter,
+		r.validateShutdownTimeouts,
+		r.validateGarbageCollection,
+		r.validateTDE,
 		r.validateHibernationAnnotation,
+		r.validateMaintenanceWindows,
+		r.validateDatabaseMaintenance,
+		r.validateUpdatePolicy,
+		r.validateLogFiles,
+		r.validateSecretsStore,
+		r.validateLifecycleHooks,
 	}
 
 	for _, validate := range validations {
@@ -448,6 +479,8 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 		r.validateReplicationSlotsChange,
 		r.validateWALLevelChange,
 		r.validateReplicaClusterChange,
+		r.validateTDEChange,
+		r.validateUnsafeParameters,
 	}
 	for _, validate := range validations {
 		allErrs = append(allErrs, validate(old)...)
@@ -491,6 +524,184 @@ func (r *Cluster) validateLDAP() field.ErrorList {
 	return result
 }
 
+// validateGSSAPI validates the GSSAPI postgres configuration
+func (r *Cluster) validateGSSAPI() field.ErrorList {
+	gssapiConfig := r.Spec.PostgresConfiguration.GSSAPI
+	if gssapiConfig == nil {
+		return nil
+	}
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "postgresql", "gssapi")
+	if gssapiConfig.Keytab.Name == "" {
+		result = append(result, field.Required(basePath.Child("keytab", "name"),
+			"the name of the secret containing the keytab is required"))
+	}
+	if gssapiConfig.Keytab.Key == "" {
+		result = append(result, field.Required(basePath.Child("keytab", "key"),
+			"the key of the secret containing the keytab is required"))
+	}
+
+	if gssapiConfig.Address == "" {
+		result = append(result, field.Required(basePath.Child("address"),
+			"the addresses of the clients authenticating through GSSAPI are required, "+
+				"as the GSSAPI rule takes precedence over the default rules"))
+	} else if !isValidPgHBAAddress(gssapiConfig.Address) {
+		result = append(result, field.Invalid(basePath.Child("address"), gssapiConfig.Address,
+			"the address must be a CIDR, a host name, or one of 'all', 'samehost' and 'samenet'"))
+	}
+	if strings.ContainsAny(gssapiConfig.Realm, " \t\n\"#") {
+		result = append(result, field.Invalid(basePath.Child("realm"), gssapiConfig.Realm,
+			"the realm cannot contain spaces, double quotes or hash signs"))
+	}
+
+	for i, mapping := range gssapiConfig.UserMappings {
+		mappingPath := basePath.Child("userMappings").Index(i)
+		if strings.ContainsAny(mapping.Principal, " \t\n\"#") {
+			result = append(result, field.Invalid(mappingPath.Child("principal"), mapping.Principal,
+				"the principal cannot contain spaces, double quotes or hash signs"))
+		}
+		if strings.ContainsAny(mapping.User, " \t\n\"#") {
+			result = append(result, field.Invalid(mappingPath.Child("user"), mapping.User,
+				"the user cannot contain spaces, double quotes or hash signs"))
+		}
+	}
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["krb_server_keyfile"]; ok {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", "krb_server_keyfile"),
+			r.Spec.PostgresConfiguration.Parameters["krb_server_keyfile"],
+			"krb_server_keyfile is managed by the operator when GSSAPI is configured"))
+	}
+
+	return result
+}
+
+// validatePasswordAuthentication checks that the passwords are not
+// hashed with MD5 when SCRAM-SHA-256 is required
+func (r *Cluster) validatePasswordAuthentication() field.ErrorList {
+	if !r.RequiresSCRAMPasswords() {
+		return nil
+	}
+
+	var result field.ErrorList
+	value, ok := r.Spec.PostgresConfiguration.Parameters[postgres.ParameterPasswordEncryption]
+	if ok && value != postgres.PasswordEncryptionSCRAM {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", postgres.ParameterPasswordEncryption),
+			value,
+			fmt.Sprintf("%s must be %s when migrating from MD5 or requiring channel binding",
+				postgres.ParameterPasswordEncryption, postgres.PasswordEncryptionSCRAM)))
+	}
+
+	return result
+}
+
+// validatePgHBARules validates the structured pg_hba rules, ensuring
+// they can be rendered as valid lines of the pg_hba.conf file
+func (r *Cluster) validatePgHBARules() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "postgresql", "pg_hba_rules")
+	for i, rule := range r.Spec.PostgresConfiguration.PgHBARules {
+		rulePath := basePath.Index(i)
+
+		if rule.Type == "" {
+			result = append(result, field.Required(rulePath.Child("type"), "the rule type is required"))
+		}
+		if rule.Method == "" {
+			result = append(result, field.Required(rulePath.Child("method"), "the authentication method is required"))
+		}
+
+		if err := validatePgHBANameList(rule.Database); err != "" {
+			result = append(result, field.Invalid(rulePath.Child("database"), rule.Database, err))
+		}
+		if err := validatePgHBANameList(rule.User); err != "" {
+			result = append(result, field.Invalid(rulePath.Child("user"), rule.User, err))
+		}
+
+		switch {
+		case rule.Type == PgHBARuleTypeLocal && rule.Address != "":
+			result = append(result, field.Invalid(rulePath.Child("address"), rule.Address,
+				"the address is not allowed in local rules"))
+		case rule.Type != PgHBARuleTypeLocal && rule.Address == "":
+			result = append(result, field.Required(rulePath.Child("address"),
+				"the address is required unless the rule type is local"))
+		case rule.Type != PgHBARuleTypeLocal && !isValidPgHBAAddress(rule.Address):
+			result = append(result, field.Invalid(rulePath.Child("address"), rule.Address,
+				"the address must be a CIDR, a host name, or one of 'all', 'samehost' and 'samenet'"))
+		}
+
+		if rule.Method == PgHBAMethodPeer && rule.Type != PgHBARuleTypeLocal {
+			result = append(result, field.Invalid(rulePath.Child("method"), rule.Method,
+				"the peer authentication method is only available for local rules"))
+		}
+		if rule.Method == PgHBAMethodCert && rule.Type != PgHBARuleTypeHostSSL {
+			result = append(result, field.Invalid(rulePath.Child("method"), rule.Method,
+				"the cert authentication method is only available for hostssl rules"))
+		}
+
+		optionNames := make([]string, 0, len(rule.Options))
+		for name := range rule.Options {
+			optionNames = append(optionNames, name)
+		}
+		sort.Strings(optionNames)
+		for _, name := range optionNames {
+			value := rule.Options[name]
+			if name == "" || strings.ContainsAny(name, "= \t\n\"#") {
+				result = append(result, field.Invalid(rulePath.Child("options").Key(name), name,
+					"invalid option name"))
+			}
+			if strings.ContainsAny(value, "\n\"#") {
+				result = append(result, field.Invalid(rulePath.Child("options").Key(name), value,
+					"option values cannot contain double quotes, hash signs or new lines"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validatePgHBANameList validates a comma separated list of database or
+// user names, returning an error message if it is not valid
+func validatePgHBANameList(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		if name == "" {
+			return "the list cannot contain empty names"
+		}
+		if strings.ContainsAny(name, " \t\n\"#") {
+			return "names cannot contain spaces, double quotes or hash signs"
+		}
+	}
+
+	return ""
+}
+
+// isValidPgHBAAddress checks if the passed value can be used
+// as the address of a pg_hba rule
+func isValidPgHBAAddress(address string) bool {
+	switch address {
+	case "all", "samehost", "samenet":
+		return true
+	}
+
+	if _, _, err := net.ParseCIDR(address); err == nil {
+		return true
+	}
+
+	// An IP address requires the CIDR mask
+	if net.ParseIP(address) != nil {
+		return false
+	}
+
+	// A host name can start with a dot, to match its suffix
+	return len(validationutil.IsDNS1123Subdomain(strings.TrimPrefix(strings.ToLower(address), "."))) == 0
+}
+
 // validateEnv validate the environment variables settings proposed by the user
 func (r *Cluster) validateEnv() field.ErrorList {
 	var result field.ErrorList
@@ -640,6 +851,16 @@ func (s Import) validateMicroservice() field.ErrorList {
 		)
 	}
 
+	if s.Globals {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "globals"),
+				s.Globals,
+				"You cannot import the global objects for the `microservice` import type"),
+		)
+	}
+
 	if len(s.Databases) == 1 && strings.Contains(s.Databases[0], "*") {
 		result = append(
 			result,
@@ -806,9 +1027,111 @@ func (r *Cluster) validateCerts() field.ErrorList {
 				"Client CA secret can't be empty when client replication secret is provided"))
 	}
 
+	result = append(result, r.validateCertManager()...)
+	return append(result, r.validateCertificatesRotation()...)
+}
+
+// validateCertManager checks that the certificate issued by cert-manager
+// is not provided by the user too
+func (r *Cluster) validateCertManager() field.ErrorList {
+	var result field.ErrorList
+	if !r.UsesCertManager() {
+		return result
+	}
+
+	certificates := r.Spec.Certificates
+	path := field.NewPath("spec", "certificates")
+	if certificates.ServerTLSSecret != "" {
+		result = append(result, field.Invalid(
+			path.Child("serverTLSSecret"),
+			certificates.ServerTLSSecret,
+			"the server TLS secret can't be provided when the certificates are issued by cert-manager"))
+	}
+
+	return result
+}
+
+// validateCertificatesRotation checks that the certificates can be renewed
+// before they expire, and that the generated CAs outlive their certificates.
+// The settings that are not customized take the values configured in the
+// operator, and the error is reported on the customized ones
+func (r *Cluster) validateCertificatesRotation() field.ErrorList {
+	var result field.ErrorList
+	rotation := r.GetCertificatesRotation()
+	if rotation == nil {
+		return result
+	}
+
+	path := field.NewPath("spec", "certificates", "rotation")
+	defaultDurationDays, defaultRenewBeforeDays := getDefaultCertificatesRotation()
+	caDurationDays := rotation.CADurationDays
+	if caDurationDays <= 0 {
+		caDurationDays = defaultDurationDays
+	}
+	leafDurationDays := rotation.LeafDurationDays
+	if leafDurationDays <= 0 {
+		leafDurationDays = defaultDurationDays
+	}
+	renewBeforeDays := rotation.RenewBeforeDays
+	if renewBeforeDays <= 0 {
+		renewBeforeDays = defaultRenewBeforeDays
+	}
+
+	// invalidSetting reports an error on the first customized setting
+	// among the passed ones
+	invalidSetting := func(detail string, names ...string) {
+		values := map[string]int{
+			"caDurationDays":   rotation.CADurationDays,
+			"leafDurationDays": rotation.LeafDurationDays,
+			"renewBeforeDays":  rotation.RenewBeforeDays,
+		}
+		for _, name := range names {
+			if values[name] > 0 {
+				result = append(result, field.Invalid(path.Child(name), values[name], detail))
+				return
+			}
+		}
+	}
+
+	if leafDurationDays > caDurationDays {
+		invalidSetting(
+			fmt.Sprintf("the certificates, lasting %d days, can't last longer than the CA that signs them, "+
+				"lasting %d days", leafDurationDays, caDurationDays),
+			"leafDurationDays", "caDurationDays")
+	}
+
+	for _, duration := range []struct {
+		name string
+		days int
+	}{
+		{name: "caDurationDays", days: caDurationDays},
+		{name: "leafDurationDays", days: leafDurationDays},
+	} {
+		if renewBeforeDays >= duration.days {
+			invalidSetting(
+				fmt.Sprintf("the renewal window of %d days must be shorter than %s, which is %d days",
+					renewBeforeDays, duration.name, duration.days),
+				"renewBeforeDays", duration.name)
+		}
+	}
+
 	return result
 }
 
+// getDefaultCertificatesRotation returns the lifetime and the renewal
+// window, in days, of the certificates configured in the operator
+func getDefaultCertificatesRotation() (durationDays int, renewBeforeDays int) {
+	durationDays = configuration.Current.CertificateDuration
+	if durationDays <= 0 {
+		durationDays = configuration.CertificateDuration
+	}
+	renewBeforeDays = configuration.Current.ExpiringCheckThreshold
+	if renewBeforeDays <= 0 {
+		renewBeforeDays = configuration.ExpiringCheckThreshold
+	}
+	return durationDays, renewBeforeDays
+}
+
 // ValidateSuperuserSecret validate super user secret value
 func (r *Cluster) validateSuperuserSecret() field.ErrorList {
 	var result field.ErrorList
@@ -939,28 +1262,106 @@ func (r *Cluster) validateBootstrapRecoveryDataSource() field.ErrorList {
 		}
 	}
 
-	result := validateVolumeSnapshotSource(recoverySection.VolumeSnapshots.Storage, recoveryPath.Child("storage"))
+	volumeSnapshotsPath := recoveryPath.Child("volumeSnapshots")
+	result := validateVolumeSnapshotSource(recoverySection.VolumeSnapshots.Storage, volumeSnapshotsPath.Child("storage"))
+
+	if recoverySection.VolumeSnapshots.WalStorage != nil {
+		walStoragePath := volumeSnapshotsPath.Child("walStorage")
+		if r.Spec.WalStorage == nil {
+			result = append(
+				result,
+				field.Invalid(
+					walStoragePath,
+					r.Spec.Bootstrap.Recovery.VolumeSnapshots.WalStorage,
+					"A WAL storage configuration is required when recovering using a DataSource for WALs"))
+		}
+		result = append(
+			result,
+			validateVolumeSnapshotSource(
+				*recoverySection.VolumeSnapshots.WalStorage, walStoragePath)...)
+	}
+
+	tablespaceNames := make([]string, 0, len(recoverySection.VolumeSnapshots.TablespaceStorage))
+	for name := range recoverySection.VolumeSnapshots.TablespaceStorage {
+		tablespaceNames = append(tablespaceNames, name)
+	}
+	sort.Strings(tablespaceNames)
+	for _, name := range tablespaceNames {
+		tablespaceStoragePath := volumeSnapshotsPath.Child("tablespaceStorage").Key(name)
+		source := recoverySection.VolumeSnapshots.TablespaceStorage[name]
+		if r.GetTablespaceConfiguration(name) == nil {
+			result = append(
+				result,
+				field.Invalid(
+					tablespaceStoragePath,
+					source,
+					"A tablespace configuration is required when recovering a tablespace using a DataSource"))
+		}
+		result = append(result, validateVolumeSnapshotSource(source, tablespaceStoragePath)...)
+	}
+
+	return result
+}
+
+// validateBootstrapRecoveryVolumeSource is used to ensure that the PVCs
+// to be adopted are correctly defined
+func (r *Cluster) validateBootstrapRecoveryVolumeSource() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil || r.Spec.Bootstrap.Recovery.VolumeSource == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	recoveryPath := field.NewPath("spec", "bootstrap", "recovery")
+	recoverySection := r.Spec.Bootstrap.Recovery
+	volumeSourcePath := recoveryPath.Child("volumeSource")
+
+	if recoverySection.Backup != nil || recoverySection.Source != "" || recoverySection.VolumeSnapshots != nil {
+		result = append(
+			result,
+			field.Invalid(
+				volumeSourcePath,
+				recoverySection.VolumeSource,
+				"Recovery from volumeSource is not compatible with other types of recovery"))
+	}
 
-	if recoverySection.VolumeSnapshots.WalStorage != nil && r.Spec.WalStorage == nil {
-		walStoragePath := recoveryPath.Child("dataSource", "walStorage")
+	if recoverySection.RecoveryTarget != nil {
 		result = append(
 			result,
 			field.Invalid(
-				walStoragePath,
-				r.Spec.Bootstrap.Recovery.VolumeSnapshots.WalStorage,
-				"A WAL storage configuration is required when recovering using a DataSource for WALs"))
+				recoveryPath.Child("recoveryTarget"),
+				recoverySection.RecoveryTarget,
+				"Cannot specify a recovery target when recovering from a volumeSource"))
+	}
+
+	if recoverySection.VolumeSource.Storage.Name == "" {
 		result = append(
 			result,
-			validateVolumeSnapshotSource(
-				*recoverySection.VolumeSnapshots.WalStorage, walStoragePath)...)
+			field.Required(volumeSourcePath.Child("storage", "name"), "The PVC name is required"))
 	}
 
-	if recoverySection.VolumeSnapshots.WalStorage != nil {
+	if recoverySection.VolumeSource.WalStorage != nil && r.Spec.WalStorage == nil {
 		result = append(
 			result,
-			validateVolumeSnapshotSource(
-				*recoverySection.VolumeSnapshots.WalStorage,
-				recoveryPath.Child("dataSource", "walStorage"))...)
+			field.Invalid(
+				volumeSourcePath.Child("walStorage"),
+				recoverySection.VolumeSource.WalStorage,
+				"A WAL storage configuration is required when recovering WALs from a volumeSource"))
+	}
+
+	tablespaceNames := make([]string, 0, len(recoverySection.VolumeSource.TablespaceStorage))
+	for name := range recoverySection.VolumeSource.TablespaceStorage {
+		tablespaceNames = append(tablespaceNames, name)
+	}
+	sort.Strings(tablespaceNames)
+	for _, name := range tablespaceNames {
+		if r.GetTablespaceConfiguration(name) == nil {
+			result = append(
+				result,
+				field.Invalid(
+					volumeSourcePath.Child("tablespaceStorage").Key(name),
+					recoverySection.VolumeSource.TablespaceStorage[name],
+					"A tablespace configuration is required when recovering a tablespace from a volumeSource"))
+		}
 	}
 
 	return result
@@ -1106,6 +1507,33 @@ func (r *Cluster) validateResources() field.ErrorList {
 	return result
 }
 
+// validateReplicaParameters checks that the parameters meant only for
+// the standby instances can be changed independently from the primary
+func (r *Cluster) validateReplicaParameters() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "postgresql", "replicaParameters")
+	for key, value := range r.Spec.PostgresConfiguration.ReplicaParameters {
+		if _, isFixed := postgres.FixedConfigurationParameters[key]; isFixed {
+			result = append(result, field.Invalid(
+				basePath.Key(key),
+				value,
+				"Can't set fixed configuration parameter"))
+			continue
+		}
+
+		if slices.Contains(postgres.PrimaryDrivenConfigurationParameters, key) {
+			result = append(result, field.Invalid(
+				basePath.Key(key),
+				value,
+				"This parameter must be consistent across the primary and the replicas, "+
+					"set it in `.spec.postgresql.parameters` instead"))
+		}
+	}
+
+	return result
+}
+
 // validateConfiguration determines whether a PostgreSQL configuration is valid
 func (r *Cluster) validateConfiguration() field.ErrorList {
 	var result field.ErrorList
@@ -1204,6 +1632,17 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 				"`wal_log_hints` must be set to `on` when `instances` > 1"))
 	}
 
+	if value, ok := r.Spec.PostgresConfiguration.Parameters[postgres.ParameterSuperuserReservedConnections]; ok {
+		if reserved, err := strconv.Atoi(value); err != nil || reserved < 0 {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "postgresql", "parameters", postgres.ParameterSuperuserReservedConnections),
+					value,
+					"`superuser_reserved_connections` must be a non-negative integer"))
+		}
+	}
+
 	// verify the postgres setting min_wal_size < max_wal_size < volume size
 	result = append(result, validateWalSizeConfiguration(
 		r.Spec.PostgresConfiguration, r.Spec.WalStorage.GetSizeOrNil())...)
@@ -1361,24 +1800,38 @@ func validateSyncReplicaElectionConstraint(constraints SyncReplicaElectionConstr
 	if !constraints.Enabled {
 		return nil
 	}
-	if len(constraints.NodeLabelsAntiAffinity) > 0 {
-		return nil
+	if len(constraints.NodeLabelsAntiAffinity) == 0 {
+		return field.Invalid(
+			field.NewPath(
+				"spec", "postgresql", "syncReplicaElectionConstraint", "nodeLabelsAntiAffinity",
+			),
+			nil,
+			"Can't enable syncReplicaConstraints without passing labels for comparison inside nodeLabelsAntiAffinity",
+		)
 	}
 
-	return field.Invalid(
-		field.NewPath(
-			"spec", "postgresql", "syncReplicaElectionConstraint", "nodeLabelsAntiAffinity",
-		),
-		nil,
-		"Can't enable syncReplicaConstraints without passing labels for comparison inside nodeLabelsAntiAffinity",
-	)
+	if constraints.GetMode() == SyncReplicaElectionModePreferred &&
+		constraints.GetMethod() != SyncReplicaElectionMethodFirst {
+		return field.Invalid(
+			field.NewPath(
+				"spec", "postgresql", "syncReplicaElectionConstraint", "mode",
+			),
+			constraints.Mode,
+			"The preferred mode requires the first method, as the quorum-based "+
+				"synchronous replication doesn't take into account the order of the replicas",
+		)
+	}
+
+	return nil
 }
 
 // validateImageChange validate the change from a certain image name
-// to a new one.
+// to a new one. Upgrading to a newer major version is allowed, as the
+// operator will take care of upgrading the data directory, while
+// downgrading is only allowed to roll back a failed major upgrade.
 func (r *Cluster) validateImageChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
-	var newMajor, oldMajor int
+	var newVersion, oldVersion int
 	var err error
 	var newImagePath *field.Path
 	if r.Spec.ImageCatalogRef != nil {
@@ -1388,7 +1841,7 @@ func (r *Cluster) validateImageChange(old *Cluster) field.ErrorList {
 	}
 
 	r.Status.Image = ""
-	newMajor, err = r.GetPostgresqlVersion()
+	newVersion, err = r.GetPostgresqlVersion()
 	if err != nil {
 		// The validation error will be already raised by the
 		// validateImageName function
@@ -1396,25 +1849,49 @@ func (r *Cluster) validateImageChange(old *Cluster) field.ErrorList {
 	}
 
 	old.Status.Image = ""
-	oldMajor, err = old.GetPostgresqlVersion()
+	oldVersion, err = old.GetPostgresqlVersion()
 	if err != nil {
 		// The validation error will be already raised by the
 		// validateImageName function
 		return result
 	}
 
-	status := postgres.IsUpgradePossible(oldMajor, newMajor)
+	newMajor := postgres.GetPostgresMajorVersion(newVersion)
+	oldMajor := postgres.GetPostgresMajorVersion(oldVersion)
 
-	if !status {
-		result = append(
-			result,
-			field.Invalid(
-				newImagePath,
-				newMajor,
-				fmt.Sprintf("can't upgrade between majors %v and %v",
-					oldMajor, newMajor)))
+	switch {
+	case newMajor == oldMajor:
+		return result
+
+	case newMajor > oldMajor:
+		if r.IsReplica() {
+			result = append(
+				result,
+				field.Invalid(
+					newImagePath,
+					newVersion,
+					fmt.Sprintf("can't upgrade between majors %v and %v in a replica cluster",
+						oldVersion, newVersion)))
+		}
+		return result
+	}
+
+	// Going back to the major version of the data directory is the way
+	// to roll back a failed major upgrade, unless the data directory
+	// has already been upgraded
+	if old.Status.PGDataImageInfo != nil && old.Status.Phase != PhaseMajorUpgradeIncomplete &&
+		old.Status.PGDataImageInfo.MajorVersion == postgres.GetPostgresMajorVersionNumber(newVersion) {
+		return result
 	}
 
+	result = append(
+		result,
+		field.Invalid(
+			newImagePath,
+			newVersion,
+			fmt.Sprintf("can't downgrade between majors %v and %v",
+				oldVersion, newVersion)))
+
 	return result
 }
 
@@ -1635,6 +2112,47 @@ func (r *Cluster) validateTablespaceStorageSize() field.ErrorList {
 	return result
 }
 
+// validateStorageEmergencyCleanup checks that the emergency cleanup is
+// only configured for the WAL volume
+func (r *Cluster) validateStorageEmergencyCleanup() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.StorageConfiguration.EmergencyCleanup != nil {
+		result = append(result, field.Forbidden(
+			field.NewPath("spec", "storage", "emergencyCleanup"),
+			"the emergency cleanup can be specified only in the walStorage section"))
+	}
+
+	for idx, tablespaceConf := range r.Spec.Tablespaces {
+		if tablespaceConf.Storage.EmergencyCleanup != nil {
+			result = append(result, field.Forbidden(
+				field.NewPath("spec", "tablespaces").Index(idx).Child("storage", "emergencyCleanup"),
+				"the emergency cleanup can be specified only in the walStorage section"))
+		}
+	}
+
+	if r.Spec.WalStorage == nil || r.Spec.WalStorage.EmergencyCleanup == nil {
+		return result
+	}
+
+	config := r.Spec.WalStorage.EmergencyCleanup
+	path := field.NewPath("spec", "walStorage", "emergencyCleanup")
+	if config.ThrottleConnectionsThreshold != 0 && config.ThrottleConnectionsThreshold >= config.GetThreshold() {
+		result = append(result, field.Invalid(
+			path.Child("throttleConnectionsThreshold"),
+			config.ThrottleConnectionsThreshold,
+			"must be lower than the threshold"))
+	}
+	if config.FenceWritesThreshold != 0 && config.FenceWritesThreshold <= config.GetThreshold() {
+		result = append(result, field.Invalid(
+			path.Child("fenceWritesThreshold"),
+			config.FenceWritesThreshold,
+			"must be higher than the threshold"))
+	}
+
+	return result
+}
+
 func validateStorageConfigurationSize(
 	structPath field.Path,
 	storageConfiguration StorageConfiguration,
@@ -1854,6 +2372,46 @@ func (r *Cluster) validateExternalClusters() field.ErrorList {
 			"the list of external clusters contains duplicate values"))
 	}
 
+	return append(result, r.validateExternalClustersSecretsNamespaces()...)
+}
+
+// validateExternalClustersSecretsNamespaces checks the namespaces the
+// external clusters take their secrets from, which must be watched by the
+// operator for it to read the grants and delegate the access
+func (r *Cluster) validateExternalClustersSecretsNamespaces() field.ErrorList {
+	var result field.ErrorList
+	watchedNamespaces := configuration.Current.WatchedNamespaces()
+
+	for idx, externalCluster := range r.Spec.ExternalClusters {
+		path := field.NewPath("spec", "externalClusters").Index(idx).Child("secretsNamespace")
+		namespace := externalCluster.SecretsNamespace
+		if namespace == "" || namespace == r.Namespace {
+			continue
+		}
+
+		if errs := validationutil.IsDNS1123Label(namespace); len(errs) > 0 {
+			result = append(result, field.Invalid(path, namespace, strings.Join(errs, ", ")))
+			continue
+		}
+
+		if len(watchedNamespaces) > 0 && !slices.Contains(watchedNamespaces, namespace) {
+			result = append(result, field.Invalid(
+				path,
+				namespace,
+				"the namespace is not watched by the operator"))
+			continue
+		}
+
+		// Every shard only caches the objects of its own namespaces
+		if shardCount := configuration.Current.ShardCount; configuration.Current.IsShardingEnabled() &&
+			configuration.GetNamespaceShard(namespace, shardCount) != configuration.GetNamespaceShard(r.Namespace, shardCount) {
+			result = append(result, field.Invalid(
+				path,
+				namespace,
+				"the namespace is assigned to a different operator shard than the cluster"))
+		}
+	}
+
 	return result
 }
 
@@ -1918,7 +2476,20 @@ func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.Err
 func (r *Cluster) validateReplicaMode() field.ErrorList {
 	var result field.ErrorList
 
-	if r.Spec.ReplicaCluster == nil || !r.Spec.ReplicaCluster.Enabled {
+	if r.Spec.ReplicaCluster == nil {
+		return result
+	}
+
+	if token := r.Spec.ReplicaCluster.PromotionToken; token != "" {
+		if _, err := utils.ParsePgControldataToken(token); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "replica", "promotionToken"),
+				token,
+				err.Error()))
+		}
+	}
+
+	if !r.IsReplica() {
 		return result
 	}
 
@@ -2105,9 +2676,187 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 		}
 	}
 
+	if wal := r.Spec.Backup.BarmanObjectStore.Wal; wal != nil &&
+		wal.MaxParallelBurst != 0 && wal.MaxParallelBurst < wal.MaxParallel {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "barmanObjectStore", "wal", "maxParallelBurst"),
+			wal.MaxParallelBurst,
+			"maxParallelBurst can't be lower than maxParallel",
+		))
+	}
+
+	if wal, data := r.Spec.Backup.BarmanObjectStore.Wal, r.Spec.Backup.BarmanObjectStore.Data; wal != nil &&
+		wal.ClientSideEncryption != nil && (data == nil || data.Encryption == "") {
+		allErrors = append(allErrors, field.Required(
+			field.NewPath("spec", "backup", "barmanObjectStore", "data", "encryption"),
+			"the base backups are uploaded by Barman Cloud directly from the data files and "+
+				"can't be encrypted client-side: the object store must encrypt them when "+
+				"clientSideEncryption is enabled for the WAL files",
+		))
+	}
+
+	allErrors = append(allErrors, validateBackupHooks(field.NewPath("spec", "backup", "hooks"), r.Spec.Backup.Hooks)...)
+
 	return allErrors
 }
 
+// validateBackupHooks checks that every hook has a unique name and
+// exactly one action
+func validateBackupHooks(path *field.Path, hooks *BackupHooks) field.ErrorList {
+	if hooks == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	validatePhase := func(phasePath *field.Path, phaseHooks []BackupHook) {
+		names := stringset.New()
+		for idx, hook := range phaseHooks {
+			hookPath := phasePath.Index(idx)
+			if hook.Name == "" {
+				result = append(result, field.Required(hookPath.Child("name"), "the hook name is required"))
+			} else if names.Has(hook.Name) {
+				result = append(result, field.Duplicate(hookPath.Child("name"), hook.Name))
+			}
+			names.Put(hook.Name)
+
+			if (hook.HTTP == nil) == (hook.SQL == "") {
+				result = append(result, field.Invalid(
+					hookPath,
+					hook.Name,
+					"exactly one between http and sql must be specified",
+				))
+			}
+
+			if hook.Database != "" && hook.SQL == "" {
+				result = append(result, field.Invalid(
+					hookPath.Child("database"),
+					hook.Database,
+					"database can be specified only together with sql",
+				))
+			}
+		}
+	}
+
+	validatePhase(path.Child("pre"), hooks.Pre)
+	validatePhase(path.Child("post"), hooks.Post)
+
+	return result
+}
+
+// validateAdditionalBarmanObjectStores validates the object stores where
+// the WAL files are shipped together with the main one
+func (r *Cluster) validateAdditionalBarmanObjectStores() field.ErrorList {
+	if r.Spec.Backup == nil || len(r.Spec.Backup.AdditionalBarmanObjectStores) == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "backup", "additionalBarmanObjectStores")
+
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		return append(result, field.Invalid(
+			basePath,
+			len(r.Spec.Backup.AdditionalBarmanObjectStores),
+			"additional object stores require barmanObjectStore to be defined",
+		))
+	}
+
+	names := stringset.New()
+	for idx := range r.Spec.Backup.AdditionalBarmanObjectStores {
+		objectStore := &r.Spec.Backup.AdditionalBarmanObjectStores[idx]
+		objectStorePath := basePath.Index(idx)
+
+		if names.Has(objectStore.Name) {
+			result = append(result, field.Duplicate(objectStorePath.Child("name"), objectStore.Name))
+		}
+		names.Put(objectStore.Name)
+
+		credentials := objectStore.BarmanCredentials
+		credentialsCount := 0
+		if credentials.Azure != nil {
+			credentialsCount++
+			result = append(result,
+				credentials.Azure.validateAzureCredentials(objectStorePath.Child("azureCredentials"))...)
+		}
+		if credentials.AWS != nil {
+			credentialsCount++
+			result = append(result,
+				credentials.AWS.validateAwsCredentials(objectStorePath.Child("s3Credentials"))...)
+		}
+		if credentials.Google != nil {
+			credentialsCount++
+			result = append(result,
+				credentials.Google.validateGCSCredentials(objectStorePath.Child("googleCredentials"))...)
+		}
+		if credentialsCount != 1 {
+			result = append(result, field.Invalid(
+				objectStorePath,
+				objectStore.Name,
+				"one and only one of azureCredentials, s3Credentials and googleCredentials are required",
+			))
+		}
+
+		if objectStore.EndpointCA != nil {
+			result = append(result, field.Forbidden(
+				objectStorePath.Child("endpointCA"),
+				"a custom endpoint CA is supported only in barmanObjectStore",
+			))
+		}
+
+		if objectStore.DestinationPath == r.Spec.Backup.BarmanObjectStore.DestinationPath &&
+			objectStore.ServerName == r.Spec.Backup.BarmanObjectStore.ServerName {
+			result = append(result, field.Invalid(
+				objectStorePath.Child("destinationPath"),
+				objectStore.DestinationPath,
+				"the destination path and the server name can't be the same of barmanObjectStore",
+			))
+		}
+	}
+
+	return result
+}
+
+// validateReplicaClone checks that the maximum transfer rate of the
+// clone of the primary is within the range accepted by pg_basebackup
+func (r *Cluster) validateReplicaClone() field.ErrorList {
+	maxRate := r.Spec.ReplicaClone.GetMaxRate()
+	if maxRate == "" {
+		return nil
+	}
+
+	kilobytes, err := parsePgBaseBackupRate(maxRate)
+	if err != nil || kilobytes < 32 || kilobytes > 1024*1024 {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "replicaClone", "maxRate"),
+				maxRate,
+				"the maximum transfer rate must be between 32k and 1024M"),
+		}
+	}
+
+	return nil
+}
+
+// parsePgBaseBackupRate parses a transfer rate in the format accepted by
+// pg_basebackup, returning it in kilobytes per second
+func parsePgBaseBackupRate(rate string) (int64, error) {
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(rate, "k"):
+		rate = strings.TrimSuffix(rate, "k")
+	case strings.HasSuffix(rate, "M"):
+		rate = strings.TrimSuffix(rate, "M")
+		multiplier = 1024
+	}
+
+	value, err := strconv.ParseInt(rate, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return value * multiplier, nil
+}
+
 func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	if r.Spec.ReplicationSlots == nil {
 		r.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
@@ -2164,6 +2913,61 @@ func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	return nil
 }
 
+// validateInactiveReplicationSlots validates the configuration of the
+// inactive replication slots detection
+func (r *Cluster) validateInactiveReplicationSlots() field.ErrorList {
+	inactiveSlots := r.Spec.ReplicationSlots.GetInactiveSlots()
+	if inactiveSlots == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "replicationSlots", "inactiveSlots", "excludePatterns")
+	for idx, pattern := range inactiveSlots.ExcludePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			result = append(result, field.Invalid(
+				basePath.Index(idx),
+				pattern,
+				fmt.Sprintf("Invalid regular expression: %v", err)))
+		}
+	}
+
+	return result
+}
+
+// validateObjectSizes checks that the patterns of the object sizes
+// collector are valid regular expressions
+func (r *Cluster) validateObjectSizes() field.ErrorList {
+	if r.Spec.Monitoring == nil || r.Spec.Monitoring.ObjectSizes == nil {
+		return nil
+	}
+
+	objectSizes := r.Spec.Monitoring.ObjectSizes
+	basePath := field.NewPath("spec", "monitoring", "objectSizes")
+
+	var result field.ErrorList
+	for _, patternsField := range []struct {
+		name     string
+		patterns []string
+	}{
+		{name: "includeDatabases", patterns: objectSizes.IncludeDatabases},
+		{name: "excludeDatabases", patterns: objectSizes.ExcludeDatabases},
+		{name: "includeTables", patterns: objectSizes.IncludeTables},
+		{name: "excludeTables", patterns: objectSizes.ExcludeTables},
+	} {
+		for idx, pattern := range patternsField.patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				result = append(result, field.Invalid(
+					basePath.Child(patternsField.name).Index(idx),
+					pattern,
+					fmt.Sprintf("Invalid regular expression: %v", err)))
+			}
+		}
+	}
+
+	return result
+}
+
 func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
 	newReplicationSlots := r.Spec.ReplicationSlots
 	oldReplicationSlots := old.Spec.ReplicationSlots
@@ -2335,6 +3139,7 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 	}
 
 	managedRoles := make(map[string]interface{})
+	clientCertificateSecrets := make(map[string]string)
 	for _, role := range r.Spec.Managed.Roles {
 		_, found := managedRoles[role.Name]
 		if found {
@@ -2370,11 +3175,411 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 					role.Name,
 					"This role both sets and disables a password"))
 		}
+		result = append(result, r.validateRoleClientCertificate(role, clientCertificateSecrets)...)
+	}
+
+	return result
+}
+
+// validateRoleClientCertificate checks that the client certificate of a
+// managed role, if requested, can be used and stored in its own secret
+func (r *Cluster) validateRoleClientCertificate(
+	role RoleConfiguration,
+	clientCertificateSecrets map[string]string,
+) field.ErrorList {
+	if !role.ClientCertificate {
+		return nil
+	}
+
+	var result field.ErrorList
+	if !role.Login {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "managed", "roles"),
+				role.Name,
+				"A client certificate can only be issued for a role having the login attribute"))
+	}
+
+	secretName := role.GetClientCertificateSecretName(r.Name)
+	for _, msg := range validationutil.IsDNS1123Subdomain(secretName) {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "managed", "roles"),
+				role.Name,
+				fmt.Sprintf("Invalid name for the client certificate secret %s: %s", secretName, msg)))
+	}
+
+	if otherRole, found := clientCertificateSecrets[secretName]; found && otherRole != role.Name {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "managed", "roles"),
+				role.Name,
+				fmt.Sprintf("The client certificate secret %s is already used by role %s", secretName, otherRole)))
+	}
+	clientCertificateSecrets[secretName] = role.Name
+
+	return result
+}
+
+// validateManagedServices validate the additional services defined by the user
+func (r *Cluster) validateManagedServices() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil || r.Spec.Managed.Services == nil {
+		return nil
+	}
+
+	reservedNames := map[string]bool{
+		r.GetServiceAnyName():       true,
+		r.GetServiceReadName():      true,
+		r.GetServiceReadOnlyName():  true,
+		r.GetServiceReadWriteName(): true,
+	}
+	serviceNames := make(map[string]bool)
+	basePath := field.NewPath("spec", "managed", "services", "additional")
+	for idx, service := range r.Spec.Managed.Services.Additional {
+		namePath := basePath.Index(idx).Child("name")
+		for _, msg := range validationutil.IsDNS1035Label(service.Name) {
+			result = append(result, field.Invalid(namePath, service.Name, msg))
+		}
+		if reservedNames[service.Name] {
+			result = append(
+				result,
+				field.Invalid(namePath, service.Name, "This name is reserved for a default service of the cluster"))
+		}
+		if serviceNames[service.Name] {
+			result = append(
+				result,
+				field.Duplicate(namePath, service.Name))
+		}
+		serviceNames[service.Name] = true
+	}
+
+	return result
+}
+
+// validateFailoverArbiter validates the configuration of the external
+// failover arbiter
+func (r *Cluster) validateFailoverArbiter() field.ErrorList {
+	if r.Spec.FailoverArbiter == nil {
+		return nil
+	}
+
+	arbiterPath := field.NewPath("spec", "failoverArbiter")
+	lease := r.Spec.FailoverArbiter.Lease
+	if lease == nil {
+		return field.ErrorList{
+			field.Required(arbiterPath.Child("lease"), "A lease is required to configure the failover arbiter"),
+		}
+	}
+
+	var result field.ErrorList
+	leasePath := arbiterPath.Child("lease")
+	if lease.KubeconfigSecret.Name == "" {
+		result = append(result, field.Required(
+			leasePath.Child("kubeconfigSecret", "name"), "The name of the kubeconfig secret is required"))
+	}
+	if lease.KubeconfigSecret.Key == "" {
+		result = append(result, field.Required(
+			leasePath.Child("kubeconfigSecret", "key"), "The key of the kubeconfig secret is required"))
+	}
+	if lease.Namespace == "" {
+		result = append(result, field.Required(
+			leasePath.Child("namespace"), "The namespace of the lease is required"))
+	}
+	if lease.Identity == "" {
+		result = append(result, field.Required(
+			leasePath.Child("identity"), "The identity of this cluster as holder of the lease is required"))
+	}
+
+	return result
+}
+
+// validateShutdownTimeouts checks that the time reserved for the smart and
+// fast shutdown leaves room for the immediate one within the stop delay
+func (r *Cluster) validateShutdownTimeouts() field.ErrorList {
+	if r.Spec.FastShutdownTimeout <= 0 {
+		return nil
+	}
+
+	if r.GetSmartShutdownTimeout()+r.Spec.FastShutdownTimeout >= r.GetMaxStopDelay() {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "fastShutdownTimeout"),
+				r.Spec.FastShutdownTimeout,
+				fmt.Sprintf("smartShutdownTimeout (%d) and fastShutdownTimeout must be lower than stopDelay (%d)",
+					r.GetSmartShutdownTimeout(), r.GetMaxStopDelay())),
+		}
+	}
+
+	return nil
+}
+
+// validateMaintenanceWindows checks the schedule and the duration
+// of the maintenance windows
+func (r *Cluster) validateMaintenanceWindows() field.ErrorList {
+	var result field.ErrorList
+	for idx, window := range r.Spec.MaintenanceWindows {
+		windowPath := field.NewPath("spec", "maintenanceWindows").Index(idx)
+		if _, err := cron.Parse(window.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				windowPath.Child("schedule"),
+				window.Schedule,
+				err.Error()))
+		}
+		if window.Duration.Duration <= 0 {
+			result = append(result, field.Invalid(
+				windowPath.Child("duration"),
+				window.Duration.Duration.String(),
+				"the duration of a maintenance window must be positive"))
+		}
 	}
 
 	return result
 }
 
+// validateDatabaseMaintenance checks the names and the schedules
+// of the database maintenance tasks
+func (r *Cluster) validateDatabaseMaintenance() field.ErrorList {
+	var result field.ErrorList
+	names := stringset.New()
+	for idx, task := range r.Spec.DatabaseMaintenance.GetTasks() {
+		taskPath := field.NewPath("spec", "databaseMaintenance", "tasks").Index(idx)
+		if names.Has(task.Name) {
+			result = append(result, field.Duplicate(taskPath.Child("name"), task.Name))
+		}
+		names.Put(task.Name)
+
+		if _, err := cron.Parse(task.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				taskPath.Child("schedule"),
+				task.Schedule,
+				err.Error()))
+		}
+
+		for dbIdx, database := range task.Databases {
+			if database == "" {
+				result = append(result, field.Required(
+					taskPath.Child("databases").Index(dbIdx),
+					"the name of the database cannot be empty"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validateUpdatePolicy checks the update policy, which applies only to
+// the images published in an image catalog
+func (r *Cluster) validateUpdatePolicy() field.ErrorList {
+	policy := r.Spec.UpdatePolicy
+	if policy == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	policyPath := field.NewPath("spec", "updatePolicy")
+	if r.Spec.ImageCatalogRef == nil {
+		result = append(result, field.Invalid(
+			policyPath,
+			"",
+			"the update policy requires an imageCatalogRef"))
+	}
+
+	if policy.TagPattern != "" {
+		if _, err := regexp.Compile(policy.TagPattern); err != nil {
+			result = append(result, field.Invalid(
+				policyPath.Child("tagPattern"),
+				policy.TagPattern,
+				err.Error()))
+		}
+	}
+
+	for idx, canary := range policy.Canaries {
+		if canary.Name == r.Name {
+			result = append(result, field.Invalid(
+				policyPath.Child("canaries").Index(idx),
+				canary.Name,
+				"a cluster cannot be its own canary"))
+		}
+	}
+
+	return result
+}
+
+// validateLogFiles checks the limits of the log files and the
+// configuration of their volume
+func (r *Cluster) validateLogFiles() field.ErrorList {
+	logFiles := r.Spec.LogFiles
+	if logFiles == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	logFilesPath := field.NewPath("spec", "logFiles")
+	if logFiles.MaxSize != nil && logFiles.MaxSize.Sign() <= 0 {
+		result = append(result, field.Invalid(
+			logFilesPath.Child("maxSize"),
+			logFiles.MaxSize.String(),
+			"the maximum size of the log files must be positive"))
+	}
+
+	if logFiles.MaxAge != nil && logFiles.MaxAge.Duration < 0 {
+		result = append(result, field.Invalid(
+			logFilesPath.Child("maxAge"),
+			logFiles.MaxAge.Duration.String(),
+			"the maximum age of the log files cannot be negative"))
+	}
+
+	if logFiles.SizeLimit != nil && logFiles.EphemeralVolumeSource != nil {
+		result = append(result, field.Invalid(
+			logFilesPath.Child("sizeLimit"),
+			logFiles.SizeLimit.String(),
+			"the size limit only applies to the emptyDir volume, "+
+				"and cannot be set together with ephemeralVolumeSource"))
+	}
+
+	return result
+}
+
+// validateGarbageCollection checks the retention period of
+// the orphaned resources
+func (r *Cluster) validateGarbageCollection() field.ErrorList {
+	gc := r.Spec.GarbageCollection
+	if gc == nil || gc.RetentionPeriod == nil || gc.RetentionPeriod.Duration >= 0 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "garbageCollection", "retentionPeriod"),
+			gc.RetentionPeriod.Duration.String(),
+			"the retention period cannot be negative"),
+	}
+}
+
+// validateRejoinStrategy validates the rejoin strategy, which requires
+// an object store when the data has to be restored from a backup
+func (r *Cluster) validateRejoinStrategy() field.ErrorList {
+	if r.Spec.RejoinStrategy != RejoinStrategyObjectStore {
+		return nil
+	}
+
+	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "rejoinStrategy"),
+				r.Spec.RejoinStrategy,
+				"the objectStore rejoin strategy requires a barmanObjectStore backup configuration"),
+		}
+	}
+
+	return nil
+}
+
+// validateTDE validates the transparent data encryption configuration
+func (r *Cluster) validateTDE() field.ErrorList {
+	if !r.IsTDEEnabled() {
+		return nil
+	}
+
+	secretKeyRef := r.Spec.PostgresConfiguration.TDE.SecretKeyRef
+	if secretKeyRef == nil || secretKeyRef.Name == "" || secretKeyRef.Key == "" {
+		return field.ErrorList{
+			field.Required(
+				field.NewPath("spec", "postgresql", "tde", "secretKeyRef"),
+				"a secret containing the passphrase is required when TDE is enabled"),
+		}
+	}
+
+	return nil
+}
+
+// validateTDEChange checks that the transparent data encryption is not
+// enabled or disabled on an existing cluster, and that the secret containing
+// the passphrase is not replaced, as the data encryption key is wrapped with it
+func (r *Cluster) validateTDEChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+	tdePath := field.NewPath("spec", "postgresql", "tde")
+
+	if r.IsTDEEnabled() != old.IsTDEEnabled() {
+		result = append(result, field.Invalid(
+			tdePath.Child("enabled"),
+			r.IsTDEEnabled(),
+			"TDE can only be enabled when the cluster is created"))
+	}
+
+	oldSecretKeyRef := old.GetTDESecretKeyRef()
+	newSecretKeyRef := r.GetTDESecretKeyRef()
+	if oldSecretKeyRef != nil && newSecretKeyRef != nil && *oldSecretKeyRef != *newSecretKeyRef {
+		result = append(result, field.Invalid(
+			tdePath.Child("secretKeyRef"),
+			newSecretKeyRef,
+			"the secret containing the passphrase can't be replaced, change its content "+
+				"to rotate the passphrase"))
+	}
+
+	return result
+}
+
+// validateProbes validates the probes configuration
+func (r *Cluster) validateProbes() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Probes == nil {
+		return nil
+	}
+
+	basePath := field.NewPath("spec", "probes")
+	validateProbe := func(probe *ProbeWithStrategy, path *field.Path, defaultStrategy ProbeStrategyType) {
+		if probe == nil {
+			return
+		}
+
+		if probe.MaximumLag != nil {
+			if probe.Type != ProbeStrategyStreaming {
+				result = append(result, field.Invalid(
+					path.Child("maximumLag"),
+					probe.MaximumLag.String(),
+					"maximumLag can only be used with the streaming strategy"))
+			}
+			if probe.MaximumLag.Sign() < 0 {
+				result = append(result, field.Invalid(
+					path.Child("maximumLag"),
+					probe.MaximumLag.String(),
+					"maximumLag must not be negative"))
+			}
+		}
+
+		if probe.MaximumRecoveryConflicts != nil {
+			strategy := probe.Type
+			if strategy == "" {
+				strategy = defaultStrategy
+			}
+			if strategy != ProbeStrategyQuery && strategy != ProbeStrategyStreaming {
+				result = append(result, field.Invalid(
+					path.Child("maximumRecoveryConflicts"),
+					*probe.MaximumRecoveryConflicts,
+					"maximumRecoveryConflicts can only be used with the query or streaming strategies"))
+			}
+			if *probe.MaximumRecoveryConflicts < 0 {
+				result = append(result, field.Invalid(
+					path.Child("maximumRecoveryConflicts"),
+					*probe.MaximumRecoveryConflicts,
+					"maximumRecoveryConflicts must not be negative"))
+			}
+		}
+	}
+
+	validateProbe(r.Spec.Probes.Startup, basePath.Child("startup"), ProbeStrategyPgIsReady)
+	validateProbe(r.Spec.Probes.Readiness, basePath.Child("readiness"), ProbeStrategyQuery)
+
+	return result
+}
+
 // validateManagedExtensions validate the managed extensions parameters set by the user
 func (r *Cluster) validateManagedExtensions() field.ErrorList {
 	allErrors := field.ErrorList{}
@@ -2431,7 +3636,238 @@ func (r *Cluster) validatePgFailoverSlots() field.ErrorList {
 }
 
 func (r *Cluster) getAdmissionWarnings() admission.Warnings {
-	return r.getMaintenanceWindowsAdmissionWarnings()
+	result := r.getMaintenanceWindowsAdmissionWarnings()
+	result = append(result, r.getUnsafeParametersAdmissionWarnings()...)
+	result = append(result, r.getWalLevelAdmissionWarnings()...)
+	result = append(result, r.getSuperuserReservedConnectionsAdmissionWarnings()...)
+	return append(result, r.getMaxConnectionsAdmissionWarnings()...)
+}
+
+// unsafeParameter is a PostgreSQL parameter value putting the
+// integrity of the data at risk
+type unsafeParameter struct {
+	name   string
+	value  bool
+	reason string
+}
+
+// unsafeParameters are the parameter values rejected unless they are
+// explicitly accepted through the unsafe parameters annotation
+var unsafeParameters = []unsafeParameter{
+	{
+		name:   "fsync",
+		value:  false,
+		reason: "a crash of the operating system can corrupt the data",
+	},
+	{
+		name:   "full_page_writes",
+		value:  false,
+		reason: "a crash can leave partially written pages that can't be recovered",
+	},
+	{
+		name:   "zero_damaged_pages",
+		value:  true,
+		reason: "damaged pages are silently zeroed, destroying their content",
+	},
+	{
+		name:   "ignore_checksum_failure",
+		value:  true,
+		reason: "corrupted data is silently read and can spread",
+	},
+}
+
+// parsePostgresBool parses a boolean parameter value as PostgreSQL does,
+// returning false as second value if it is not a boolean
+func parsePostgresBool(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "yes", "1":
+		return true, true
+	case "off", "false", "no", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// getAcceptedUnsafeParameters gets the names of the parameters whose unsafe
+// values are accepted through the unsafe parameters annotation
+func (r *Cluster) getAcceptedUnsafeParameters() *stringset.Data {
+	result := stringset.New()
+	for _, name := range strings.Split(r.Annotations[utils.UnsafeParametersAnnotationName], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			result.Put(name)
+		}
+	}
+	return result
+}
+
+// getUnsafeParameters gets the unsafe values set in the PostgreSQL configuration
+func (r *Cluster) getUnsafeParameters() []unsafeParameter {
+	var result []unsafeParameter
+	for _, parameter := range unsafeParameters {
+		value, ok := r.Spec.PostgresConfiguration.Parameters[parameter.name]
+		if !ok {
+			continue
+		}
+		if enabled, isBool := parsePostgresBool(value); isBool && enabled == parameter.value {
+			result = append(result, parameter)
+		}
+	}
+	return result
+}
+
+// validateUnsafeParameters rejects the PostgreSQL parameter values putting
+// the integrity of the data at risk, unless explicitly accepted. When the
+// cluster is updated, only the values changed from the old cluster are
+// rejected, so that an existing cluster can still be modified
+func (r *Cluster) validateUnsafeParameters(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	accepted := r.getAcceptedUnsafeParameters()
+	for _, parameter := range r.getUnsafeParameters() {
+		if accepted.Has(parameter.name) || old.hasUnsafeParameter(parameter) {
+			continue
+		}
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", parameter.name),
+			r.Spec.PostgresConfiguration.Parameters[parameter.name],
+			fmt.Sprintf("unsafe value, %s. Add `%s` to the `%s` annotation to accept it",
+				parameter.reason, parameter.name, utils.UnsafeParametersAnnotationName)))
+	}
+
+	return result
+}
+
+// hasUnsafeParameter checks if the passed unsafe value is set
+// in the PostgreSQL configuration of the cluster
+func (r *Cluster) hasUnsafeParameter(parameter unsafeParameter) bool {
+	if r == nil {
+		return false
+	}
+	for _, item := range r.getUnsafeParameters() {
+		if item.name == parameter.name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Cluster) getUnsafeParametersAdmissionWarnings() admission.Warnings {
+	var result admission.Warnings
+
+	accepted := r.getAcceptedUnsafeParameters()
+	for _, parameter := range r.getUnsafeParameters() {
+		if !accepted.Has(parameter.name) {
+			// The value was already set before the update
+			result = append(result, fmt.Sprintf(
+				"Unsafe value %q of `%s`: %s. Add `%s` to the `%s` annotation to accept it",
+				r.Spec.PostgresConfiguration.Parameters[parameter.name],
+				parameter.name,
+				parameter.reason,
+				parameter.name,
+				utils.UnsafeParametersAnnotationName))
+			continue
+		}
+		result = append(result, fmt.Sprintf(
+			"Unsafe value %q of `%s` accepted through the `%s` annotation: %s",
+			r.Spec.PostgresConfiguration.Parameters[parameter.name],
+			parameter.name,
+			utils.UnsafeParametersAnnotationName,
+			parameter.reason))
+	}
+
+	return result
+}
+
+func (r *Cluster) getWalLevelAdmissionWarnings() admission.Warnings {
+	walLevel := postgres.WalLevelValue(r.Spec.PostgresConfiguration.Parameters[postgres.ParameterWalLevel])
+	if walLevel != postgres.WalLevelValueMinimal {
+		return nil
+	}
+
+	return admission.Warnings{
+		"`wal_level` is set to `minimal`: the cluster can't have replicas, " +
+			"and can't be recovered to a point in time",
+	}
+}
+
+// getSuperuserReservedConnectionsAdmissionWarnings warns when no connection
+// slot is reserved to superusers, as the instance manager relies on one
+// to run its probes when max_connections is exhausted
+func (r *Cluster) getSuperuserReservedConnectionsAdmissionWarnings() admission.Warnings {
+	value := r.Spec.PostgresConfiguration.Parameters[postgres.ParameterSuperuserReservedConnections]
+	if reserved, err := strconv.Atoi(value); err != nil || reserved != 0 {
+		return nil
+	}
+
+	return admission.Warnings{
+		"`superuser_reserved_connections` is set to `0`: the readiness probe " +
+			"can fail when the applications use every connection allowed by `max_connections`",
+	}
+}
+
+// getMaxConnectionsAdmissionWarnings warns when the memory available to
+// PostgreSQL can't accommodate the shared buffers and the work memory of
+// every allowed connection
+func (r *Cluster) getMaxConnectionsAdmissionWarnings() admission.Warnings {
+	const (
+		defaultMaxConnections = 100
+		defaultSharedBuffers  = "128MB"
+		defaultWorkMem        = "4MB"
+	)
+
+	memory := r.Spec.Resources.Limits.Memory()
+	if memory.IsZero() {
+		memory = r.Spec.Resources.Requests.Memory()
+	}
+	if memory.IsZero() {
+		return nil
+	}
+
+	parameters := r.Spec.PostgresConfiguration.Parameters
+	maxConnections := defaultMaxConnections
+	if value, ok := parameters["max_connections"]; ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil
+		}
+		maxConnections = parsed
+	}
+
+	sharedBuffersValue := parameters[sharedBuffersParameter]
+	if sharedBuffersValue == "" {
+		sharedBuffersValue = defaultSharedBuffers
+	}
+	sharedBuffers, err := parsePostgresQuantityValue(sharedBuffersValue)
+	if err != nil {
+		return nil
+	}
+
+	// Unlike shared_buffers, work_mem is expressed in kilobytes when no unit is given
+	workMemValue := parameters["work_mem"]
+	if workMemValue == "" {
+		workMemValue = defaultWorkMem
+	}
+	if _, err := strconv.Atoi(workMemValue); err == nil {
+		workMemValue += "kB"
+	}
+	workMem, err := parsePostgresQuantityValue(workMemValue)
+	if err != nil {
+		return nil
+	}
+
+	required := sharedBuffers.Value() + int64(maxConnections)*workMem.Value()
+	if required <= memory.Value() {
+		return nil
+	}
+
+	return admission.Warnings{
+		fmt.Sprintf("`max_connections` is %d: the shared buffers and the work memory of every "+
+			"connection need %s, more than the %s of memory available to the instances",
+			maxConnections,
+			resource.NewQuantity(required, resource.BinarySI).String(),
+			memory.String()),
+	}
 }
 
 func (r *Cluster) getMaintenanceWindowsAdmissionWarnings() admission.Warnings {
@@ -2465,3 +3901,63 @@ func (r *Cluster) validateHibernationAnnotation() field.ErrorList {
 		),
 	}
 }
+
+// validateSecretsStore validates the names of the password files
+// mounted by the Secrets Store CSI driver
+func (r *Cluster) validateSecretsStore() field.ErrorList {
+	secretsStore := r.Spec.SecretsStore
+	if secretsStore == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	secretsStorePath := field.NewPath("spec", "secretsStore")
+	validateFile := func(fieldName, fileName string) {
+		if fileName != "" && !filepath.IsLocal(fileName) {
+			result = append(result, field.Invalid(
+				secretsStorePath.Child(fieldName),
+				fileName,
+				"the password file must be a relative path inside the mounted volume"))
+		}
+	}
+	validateFile("superuserPasswordFile", secretsStore.SuperuserPasswordFile)
+	validateFile("applicationPasswordFile", secretsStore.ApplicationPasswordFile)
+
+	return result
+}
+
+// validateLifecycleHooks checks that every lifecycle hook has a unique
+// name and exactly one content, which can be SQL only when PostgreSQL
+// is running
+func (r *Cluster) validateLifecycleHooks() field.ErrorList {
+	if r.Spec.LifecycleHooks == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	for _, point := range GetLifecycleHookPoints() {
+		pointPath := field.NewPath("spec", "lifecycleHooks", string(point))
+		names := stringset.New()
+		for idx, hook := range r.Spec.LifecycleHooks.GetHooks(point) {
+			hookPath := pointPath.Index(idx)
+			if names.Has(hook.Name) {
+				result = append(result, field.Duplicate(hookPath.Child("name"), hook.Name))
+			}
+			names.Put(hook.Name)
+
+			switch {
+			case hook.SQL == nil && hook.Script == nil:
+				result = append(result, field.Required(hookPath,
+					"either a SQL script or a shell script must be specified"))
+			case hook.SQL != nil && hook.Script != nil:
+				result = append(result, field.Invalid(hookPath, hook.Name,
+					"a SQL script and a shell script cannot be specified together"))
+			case hook.SQL != nil && point == LifecycleHookPreStart:
+				result = append(result, field.Invalid(hookPath.Child("sql"), hook.SQL.Name,
+					"SQL scripts cannot be executed before PostgreSQL is started"))
+			}
+		}
+	}
+
+	return result
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v7/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -229,6 +231,104 @@ var _ = Describe("certificates options validation", func() {
 		result := cluster.validateCerts()
 		Expect(result).To(HaveLen(1))
 	})
+	It("accepts a consistent rotation configuration", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					Rotation: &CertificatesRotationConfiguration{
+						CADurationDays:   365,
+						LeafDurationDays: 90,
+						RenewBeforeDays:  30,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCerts()).To(BeEmpty())
+	})
+	It("complains if the certificates last longer than their CA", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					Rotation: &CertificatesRotationConfiguration{
+						CADurationDays:   30,
+						LeafDurationDays: 90,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCerts()).To(HaveLen(1))
+	})
+	It("complains if the certificates can't be renewed before they expire", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					Rotation: &CertificatesRotationConfiguration{
+						CADurationDays:   90,
+						LeafDurationDays: 30,
+						RenewBeforeDays:  30,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCerts()).To(HaveLen(1))
+	})
+	It("complains if the certificates outlive the default lifetime of the CA", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					Rotation: &CertificatesRotationConfiguration{
+						LeafDurationDays: configuration.CertificateDuration + 1,
+					},
+				},
+			},
+		}
+		result := cluster.validateCerts()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.certificates.rotation.leafDurationDays"))
+	})
+	It("complains if the default renewal window is as long as the lifetime", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					Rotation: &CertificatesRotationConfiguration{
+						LeafDurationDays: configuration.ExpiringCheckThreshold,
+					},
+				},
+			},
+		}
+		result := cluster.validateCerts()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.certificates.rotation.leafDurationDays"))
+	})
+	It("accepts the certificates issued by cert-manager with a custom CA", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerCASecret: "test-server-ca",
+					CertManager: &CertManagerConfiguration{
+						IssuerRef: CertManagerIssuerReference{Name: "issuer"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCerts()).To(BeEmpty())
+	})
+	It("complains if the certificate issued by cert-manager is provided too", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerCASecret:       "test-server-ca",
+					ServerTLSSecret:      "test-server-tls",
+					ClientCASecret:       "test-client-ca",
+					ReplicationTLSSecret: "test-replication-tls",
+					CertManager: &CertManagerConfiguration{
+						IssuerRef: CertManagerIssuerReference{Name: "issuer"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateCerts()).To(HaveLen(1))
+	})
 })
 
 var _ = Describe("initdb options validation", func() {
@@ -1404,6 +1504,52 @@ var _ = Describe("configuration change validation", func() {
 			Expect(cluster.validateConfiguration()).To(BeEmpty())
 		})
 	})
+
+	Describe("superuser_reserved_connections", func() {
+		newCluster := func(value string) Cluster {
+			return Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						utils.SkipWalArchiving: "enabled",
+					},
+				},
+				Spec: ClusterSpec{
+					Instances: 1,
+					PostgresConfiguration: PostgresConfiguration{
+						Parameters: map[string]string{
+							"superuser_reserved_connections": value,
+						},
+					},
+				},
+			}
+		}
+
+		It("should allow reserving superuser connections", func() {
+			cluster := newCluster("1")
+			Expect(cluster.validateConfiguration()).To(BeEmpty())
+		})
+
+		It("should allow reserving no connection, with a warning", func() {
+			cluster := newCluster("0")
+			Expect(cluster.validateConfiguration()).To(BeEmpty())
+			Expect(cluster.getSuperuserReservedConnectionsAdmissionWarnings()).To(HaveLen(1))
+		})
+
+		It("should not warn when connections are reserved", func() {
+			cluster := newCluster("3")
+			Expect(cluster.getSuperuserReservedConnectionsAdmissionWarnings()).To(BeEmpty())
+		})
+
+		It("should not allow negative values", func() {
+			cluster := newCluster("-1")
+			Expect(cluster.validateConfiguration()).To(HaveLen(1))
+		})
+
+		It("should not allow invalid values", func() {
+			cluster := newCluster("many")
+			Expect(cluster.validateConfiguration()).To(HaveLen(1))
+		})
+	})
 })
 
 var _ = Describe("validate image name change", func() {
@@ -1445,9 +1591,86 @@ var _ = Describe("validate image name change", func() {
 			}
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
+
+		It("doesn't complain when upgrading to a newer major version", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:15.4",
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:16.1",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+		})
+
+		It("complains when upgrading to a newer major version in a replica cluster", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:15.4",
+					ReplicaCluster: &ReplicaClusterConfiguration{
+						Enabled: true,
+						Source:  "origin",
+					},
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:16.1",
+					ReplicaCluster: &ReplicaClusterConfiguration{
+						Enabled: true,
+						Source:  "origin",
+					},
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
+
+		It("doesn't complain when rolling back to the major version of the data directory", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:16.1",
+				},
+				Status: ClusterStatus{
+					PGDataImageInfo: &ImageInfo{
+						Image:        "postgres:15.4",
+						MajorVersion: 15,
+					},
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:15.4",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+		})
+
+		It("complains when rolling back after the data directory has been upgraded", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:16.1",
+				},
+				Status: ClusterStatus{
+					Phase: PhaseMajorUpgradeIncomplete,
+					PGDataImageInfo: &ImageInfo{
+						Image:        "postgres:15.4",
+						MajorVersion: 15,
+					},
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:15.4",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
 	})
 	Context("using image catalog", func() {
-		It("complains on major upgrades", func() {
+		It("doesn't complain on major upgrades", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageCatalogRef: &ImageCatalogRef{
@@ -1470,7 +1693,7 @@ var _ = Describe("validate image name change", func() {
 					},
 				},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
 	})
 	Context("changing from imageName to imageCatalogRef", func() {
@@ -1493,7 +1716,7 @@ var _ = Describe("validate image name change", func() {
 			}
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
-		It("complains on major upgrades", func() {
+		It("doesn't complain on major upgrades", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageName: "postgres:15.1",
@@ -1510,7 +1733,7 @@ var _ = Describe("validate image name change", func() {
 					},
 				},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
 		It("complains going from default imageName to different major imageCatalogRef", func() {
 			clusterOld := Cluster{
@@ -1568,7 +1791,7 @@ var _ = Describe("validate image name change", func() {
 			}
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
-		It("complains on major upgrades", func() {
+		It("doesn't complain on major upgrades", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageCatalogRef: &ImageCatalogRef{
@@ -1585,9 +1808,9 @@ var _ = Describe("validate image name change", func() {
 					ImageName: "postgres:16.1",
 				},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
-		It("complains going from default imageName to different major imageCatalogRef", func() {
+		It("doesn't complain going from imageCatalogRef to a newer major default imageName", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageCatalogRef: &ImageCatalogRef{
@@ -1602,7 +1825,7 @@ var _ = Describe("validate image name change", func() {
 			clusterNew := Cluster{
 				Spec: ClusterSpec{},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
 		It("doesn't complain going from default imageName to same major imageCatalogRef", func() {
 			clusterOld := Cluster{
@@ -2177,6 +2400,126 @@ var _ = Describe("validation of the list of external clusters", func() {
 	})
 })
 
+var _ = Describe("validation of the secrets namespace of external clusters", func() {
+	newExternalCluster := func(name, secretsNamespace string) ExternalCluster {
+		return ExternalCluster{
+			Name:             name,
+			SecretsNamespace: secretsNamespace,
+			ConnectionParameters: map[string]string{
+				"dbname": "postgres",
+			},
+			SSLRootCert: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "source-ca"},
+				Key:                  "ca.crt",
+			},
+		}
+	}
+
+	It("accepts a valid namespace", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dr"},
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{newExternalCluster("one", "production")},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+
+	It("complains about an invalid namespace", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dr"},
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{newExternalCluster("one", "Not_A_Namespace")},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(HaveLen(1))
+	})
+
+	It("accepts the secrets namespace when the operator watches every namespace", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dr"},
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{newExternalCluster("one", "production")},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+
+	Context("when the operator watches a set of namespaces", func() {
+		BeforeEach(func() {
+			previousWatchNamespace := configuration.Current.WatchNamespace
+			configuration.Current.WatchNamespace = "dr,production"
+			DeferCleanup(func() {
+				configuration.Current.WatchNamespace = previousWatchNamespace
+			})
+		})
+
+		It("accepts a watched namespace", func() {
+			cluster := Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "dr"},
+				Spec: ClusterSpec{
+					ExternalClusters: []ExternalCluster{newExternalCluster("one", "production")},
+				},
+			}
+			Expect(cluster.validateExternalClusters()).To(BeEmpty())
+		})
+
+		It("complains about a namespace that is not watched", func() {
+			cluster := Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "dr"},
+				Spec: ClusterSpec{
+					ExternalClusters: []ExternalCluster{newExternalCluster("one", "staging")},
+				},
+			}
+			Expect(cluster.validateExternalClusters()).To(HaveLen(1))
+		})
+	})
+
+	Context("when the operator is sharded", func() {
+		BeforeEach(func() {
+			previousShardCount := configuration.Current.ShardCount
+			configuration.Current.ShardCount = 2
+			DeferCleanup(func() {
+				configuration.Current.ShardCount = previousShardCount
+			})
+		})
+
+		// findNamespace finds a namespace assigned to the requested shard
+		findNamespace := func(shard int) string {
+			for idx := 0; ; idx++ {
+				namespace := fmt.Sprintf("production-%d", idx)
+				if configuration.GetNamespaceShard(namespace, 2) == shard {
+					return namespace
+				}
+			}
+		}
+
+		It("accepts a namespace of the same shard of the cluster", func() {
+			cluster := Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "dr"},
+				Spec: ClusterSpec{
+					ExternalClusters: []ExternalCluster{
+						newExternalCluster("one", findNamespace(configuration.GetNamespaceShard("dr", 2))),
+					},
+				},
+			}
+			Expect(cluster.validateExternalClusters()).To(BeEmpty())
+		})
+
+		It("complains about a namespace of a different shard", func() {
+			cluster := Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "dr"},
+				Spec: ClusterSpec{
+					ExternalClusters: []ExternalCluster{
+						newExternalCluster("one", findNamespace(1-configuration.GetNamespaceShard("dr", 2))),
+					},
+				},
+			}
+			Expect(cluster.validateExternalClusters()).To(HaveLen(1))
+		})
+	})
+})
+
 var _ = Describe("validation of an external cluster", func() {
 	It("ensure that one of connectionParameters and barmanObjectStore is set", func() {
 		cluster := Cluster{
@@ -2713,6 +3056,51 @@ var _ = Describe("replica mode validation", func() {
 		Expect(result[0].Field).To(Equal("spec.replica.enabled"))
 	})
 
+	It("doesn't validate the bootstrap method of the primary cluster of a distributed topology", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Primary: "cluster-a",
+					Source:  "cluster-b",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+
+		cluster.Spec.ReplicaCluster.Primary = "cluster-b"
+		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	})
+
+	It("complains if the promotion token is not valid", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-b"},
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Primary:        "cluster-b",
+					Source:         "cluster-a",
+					PromotionToken: "not-a-token",
+				},
+			},
+		}
+		result := cluster.validateReplicaMode()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replica.promotionToken"))
+
+		token, err := (&utils.PgControldataTokenContent{
+			DatabaseSystemIdentifier:   "7390877990563459097",
+			LatestCheckpointTimelineID: "1",
+			LatestCheckpointLocation:   "0/6000028",
+			REDOWALFile:                "000000010000000000000006",
+		}).Encode()
+		Expect(err).ToNot(HaveOccurred())
+		cluster.Spec.ReplicaCluster.PromotionToken = token
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+	})
+
 	It("is valid when the pg_basebackup bootstrap option is used", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
@@ -2830,25 +3218,238 @@ var _ = Describe("Backup validation", func() {
 		err := cluster.validateBackupConfiguration()
 		Expect(err).To(HaveLen(2))
 	})
-})
 
-var _ = Describe("Default monitoring queries", func() {
-	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
-		cluster := &Cluster{}
-		cluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret:    "test-secret",
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
-		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  DefaultMonitoringKey,
-			}))
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
+	It("complain if the maximum parallel burst is lower than the maximum parallel", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+						Wal: &WalBackupConfiguration{
+							MaxParallel:      8,
+							MaxParallelBurst: 4,
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.barmanObjectStore.wal.maxParallelBurst"))
+
+		cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallelBurst = 16
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+	})
+
+	It("requires the base backups to be encrypted when the WAL files are encrypted client-side", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+						Wal: &WalBackupConfiguration{
+							ClientSideEncryption: &WALClientSideEncryption{
+								KeysSecret: LocalObjectReference{Name: "wal-encryption-keys"},
+								ActiveKey:  "key-2024",
+							},
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+		Expect(err[0].Field).To(Equal("spec.backup.barmanObjectStore.data.encryption"))
+
+		cluster.Spec.Backup.BarmanObjectStore.Data = &DataBackupConfiguration{Encryption: "aws:kms"}
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+	})
+
+	Context("hooks", func() {
+		var cluster *Cluster
+
+		BeforeEach(func() {
+			cluster = &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("doesn't complain about valid hooks", func() {
+			cluster.Spec.Backup.Hooks = &BackupHooks{
+				Pre: []BackupHook{
+					{
+						Name: "quiesce",
+						HTTP: &BackupHookHTTPAction{
+							URL:           "http://app/quiesce",
+							HeadersSecret: &LocalObjectReference{Name: "app-hooks"},
+						},
+					},
+					{Name: "flush", SQL: "CHECKPOINT", Database: "app"},
+				},
+				Post: []BackupHook{
+					{Name: "quiesce", HTTP: &BackupHookHTTPAction{URL: "http://app/resume"}},
+				},
+			}
+			Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("complains about invalid hooks", func() {
+			cluster.Spec.Backup.Hooks = &BackupHooks{
+				Pre: []BackupHook{
+					{Name: "flush", SQL: "CHECKPOINT"},
+					{Name: "flush", SQL: "CHECKPOINT"},
+					{Name: "both", SQL: "CHECKPOINT", HTTP: &BackupHookHTTPAction{URL: "http://app"}},
+				},
+				Post: []BackupHook{
+					{SQL: "SELECT 1"},
+					{Name: "none"},
+					{Name: "database", HTTP: &BackupHookHTTPAction{URL: "http://app"}, Database: "app"},
+				},
+			}
+			result := cluster.validateBackupConfiguration()
+			Expect(result).To(HaveLen(5))
+			Expect(result[0].Field).To(Equal("spec.backup.hooks.pre[1].name"))
+			Expect(result[1].Field).To(Equal("spec.backup.hooks.pre[2]"))
+			Expect(result[2].Field).To(Equal("spec.backup.hooks.post[0].name"))
+			Expect(result[3].Field).To(Equal("spec.backup.hooks.post[1]"))
+			Expect(result[4].Field).To(Equal("spec.backup.hooks.post[2].database"))
+		})
+	})
+})
+
+var _ = Describe("Additional object stores validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://main/",
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+					},
+					AdditionalBarmanObjectStores: []NamedBarmanObjectStoreConfiguration{
+						{
+							Name: "dr",
+							BarmanObjectStoreConfiguration: BarmanObjectStoreConfiguration{
+								DestinationPath: "s3://dr/",
+								BarmanCredentials: BarmanCredentials{
+									AWS: &S3Credentials{InheritFromIAMRole: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("doesn't complain without additional object stores", func() {
+		cluster.Spec.Backup.AdditionalBarmanObjectStores = nil
+		Expect(cluster.validateAdditionalBarmanObjectStores()).To(BeEmpty())
+	})
+
+	It("accepts a valid additional object store", func() {
+		Expect(cluster.validateAdditionalBarmanObjectStores()).To(BeEmpty())
+	})
+
+	It("requires the main object store", func() {
+		cluster.Spec.Backup.BarmanObjectStore = nil
+		result := cluster.validateAdditionalBarmanObjectStores()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalBarmanObjectStores"))
+	})
+
+	It("complains about duplicate names", func() {
+		objectStore := cluster.Spec.Backup.AdditionalBarmanObjectStores[0]
+		objectStore.DestinationPath = "s3://dr-2/"
+		cluster.Spec.Backup.AdditionalBarmanObjectStores = append(
+			cluster.Spec.Backup.AdditionalBarmanObjectStores, objectStore)
+		result := cluster.validateAdditionalBarmanObjectStores()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalBarmanObjectStores[1].name"))
+	})
+
+	It("complains if the credentials are missing", func() {
+		cluster.Spec.Backup.AdditionalBarmanObjectStores[0].BarmanCredentials = BarmanCredentials{}
+		result := cluster.validateAdditionalBarmanObjectStores()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalBarmanObjectStores[0]"))
+	})
+
+	It("complains if a custom endpoint CA is set", func() {
+		cluster.Spec.Backup.AdditionalBarmanObjectStores[0].EndpointCA = &SecretKeySelector{
+			LocalObjectReference: LocalObjectReference{Name: "ca"},
+			Key:                  "ca.crt",
+		}
+		result := cluster.validateAdditionalBarmanObjectStores()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalBarmanObjectStores[0].endpointCA"))
+	})
+
+	It("complains if the location is the same of the main object store", func() {
+		cluster.Spec.Backup.AdditionalBarmanObjectStores[0].DestinationPath = "s3://main/"
+		result := cluster.validateAdditionalBarmanObjectStores()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalBarmanObjectStores[0].destinationPath"))
+
+		cluster.Spec.Backup.AdditionalBarmanObjectStores[0].ServerName = "dr"
+		Expect(cluster.validateAdditionalBarmanObjectStores()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Replica clone validation", func() {
+	It("accepts a cluster without a maximum transfer rate", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateReplicaClone()).To(BeEmpty())
+	})
+
+	It("accepts a maximum transfer rate within the pg_basebackup limits", func() {
+		for _, maxRate := range []string{"32", "32k", "100M", "1024M"} {
+			cluster := &Cluster{Spec: ClusterSpec{ReplicaClone: &ReplicaCloneConfiguration{MaxRate: maxRate}}}
+			Expect(cluster.validateReplicaClone()).To(BeEmpty(), maxRate)
+		}
+	})
+
+	It("complains about a maximum transfer rate outside the pg_basebackup limits", func() {
+		for _, maxRate := range []string{"16k", "1025M", "10G"} {
+			cluster := &Cluster{Spec: ClusterSpec{ReplicaClone: &ReplicaCloneConfiguration{MaxRate: maxRate}}}
+			Expect(cluster.validateReplicaClone()).To(HaveLen(1), maxRate)
+		}
+	})
+})
+
+var _ = Describe("Default monitoring queries", func() {
+	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
+		cluster := &Cluster{}
+		cluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret:    "test-secret",
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  DefaultMonitoringKey,
+			}))
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
 				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
 				Key:                  DefaultMonitoringKey,
 			}))
@@ -2950,6 +3551,27 @@ var _ = Describe("validation of imports", func() {
 		Expect(result).To(HaveLen(1))
 	})
 
+	It("rejects microservice import of the global objects", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+							Globals:   true,
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
 	It("rejects microservice import without exactly one database", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
@@ -3582,6 +4204,130 @@ var _ = Describe("Role management validation", func() {
 		}
 		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
 	})
+
+	It("should accept a client certificate for a role that can log in", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:              "app_reader",
+							Login:             true,
+							ClientCertificate: true,
+							ConnectionLimit:   -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(BeEmpty())
+	})
+
+	It("should produce an error if a client certificate is requested for a role that cannot log in", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:              "app_reader",
+							ClientCertificate: true,
+							ConnectionLimit:   -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+	})
+
+	It("should produce an error if the client certificate secret name is not valid", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:              "app reader",
+							Login:             true,
+							ClientCertificate: true,
+							ConnectionLimit:   -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+	})
+
+	It("should produce an error if two roles share the same client certificate secret", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:              "app_reader",
+							Login:             true,
+							ClientCertificate: true,
+							ConnectionLimit:   -1,
+						},
+						{
+							Name:              "app-reader",
+							Login:             true,
+							ClientCertificate: true,
+							ConnectionLimit:   -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Managed services validation", func() {
+	newCluster := func(names ...string) Cluster {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{},
+				},
+			},
+		}
+		for _, name := range names {
+			cluster.Spec.Managed.Services.Additional = append(cluster.Spec.Managed.Services.Additional,
+				ManagedService{Name: name, SelectorType: ServiceSelectorTypeRO})
+		}
+		return cluster
+	}
+
+	It("should succeed if there is no management stanza", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateManagedServices()).To(BeEmpty())
+	})
+
+	It("should succeed with valid additional services", func() {
+		cluster := newCluster("cluster-example-ro-zone-a", "cluster-example-ro-zone-b")
+		Expect(cluster.validateManagedServices()).To(BeEmpty())
+	})
+
+	It("complains if the name collides with a default service", func() {
+		cluster := newCluster("cluster-example-rw", "cluster-example-any")
+		Expect(cluster.validateManagedServices()).To(HaveLen(2))
+	})
+
+	It("complains about duplicate names", func() {
+		cluster := newCluster("cluster-example-lb", "cluster-example-lb")
+		Expect(cluster.validateManagedServices()).To(HaveLen(1))
+	})
+
+	It("complains about invalid names", func() {
+		cluster := newCluster("Cluster_LB")
+		Expect(cluster.validateManagedServices()).ToNot(BeEmpty())
+	})
 })
 
 var _ = Describe("Managed Extensions validation", func() {
@@ -3828,41 +4574,363 @@ var _ = Describe("Recovery from volume snapshot validation", func() {
 		})
 	})
 
-	It("prevent recovery from other Objects", func() {
+	It("accepts recovery of a tablespace from a VolumeSnapshot", func() {
 		cluster := clusterFromRecovery(&BootstrapRecovery{
 			VolumeSnapshots: &DataSource{
 				Storage: corev1.TypedLocalObjectReference{
-					APIGroup: ptr.To(""),
-					Kind:     "Secret",
+					APIGroup: ptr.To(storagesnapshotv1.GroupName),
+					Kind:     VolumeSnapshotKind,
 					Name:     "pgdata",
 				},
-				WalStorage: &corev1.TypedLocalObjectReference{
-					APIGroup: ptr.To(""),
-					Kind:     "ConfigMap",
-					Name:     "pgwal",
+				TablespaceStorage: map[string]corev1.TypedLocalObjectReference{
+					"tbs1": {
+						APIGroup: ptr.To(storagesnapshotv1.GroupName),
+						Kind:     VolumeSnapshotKind,
+						Name:     "pgtbs1",
+					},
 				},
 			},
 		})
-		Expect(cluster.validateBootstrapRecoveryDataSource()).To(HaveLen(2))
+		cluster.Spec.Tablespaces = []TablespaceConfiguration{{Name: "tbs1"}}
+		Expect(cluster.validateBootstrapRecoveryDataSource()).To(BeEmpty())
 	})
-})
-
-var _ = Describe("validateResources", func() {
-	var cluster *Cluster
 
-	BeforeEach(func() {
-		cluster = &Cluster{
-			Spec: ClusterSpec{
-				PostgresConfiguration: PostgresConfiguration{
-					Parameters: map[string]string{},
+	It("prevents recovery of a tablespace which is not defined in the cluster", func() {
+		cluster := clusterFromRecovery(&BootstrapRecovery{
+			VolumeSnapshots: &DataSource{
+				Storage: corev1.TypedLocalObjectReference{
+					APIGroup: ptr.To(storagesnapshotv1.GroupName),
+					Kind:     VolumeSnapshotKind,
+					Name:     "pgdata",
 				},
-				Resources: corev1.ResourceRequirements{
-					Requests: map[corev1.ResourceName]resource.Quantity{},
-					Limits:   map[corev1.ResourceName]resource.Quantity{},
+				TablespaceStorage: map[string]corev1.TypedLocalObjectReference{
+					"tbs1": {
+						APIGroup: ptr.To(storagesnapshotv1.GroupName),
+						Kind:     VolumeSnapshotKind,
+						Name:     "pgtbs1",
+					},
 				},
 			},
-		}
-	})
+		})
+		result := cluster.validateBootstrapRecoveryDataSource()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.volumeSnapshots.tablespaceStorage[tbs1]"))
+	})
+
+	It("prevents recovery of a tablespace from other Objects", func() {
+		cluster := clusterFromRecovery(&BootstrapRecovery{
+			VolumeSnapshots: &DataSource{
+				Storage: corev1.TypedLocalObjectReference{
+					APIGroup: ptr.To(storagesnapshotv1.GroupName),
+					Kind:     VolumeSnapshotKind,
+					Name:     "pgdata",
+				},
+				TablespaceStorage: map[string]corev1.TypedLocalObjectReference{
+					"tbs1": {
+						APIGroup: ptr.To(""),
+						Kind:     "Secret",
+						Name:     "pgtbs1",
+					},
+				},
+			},
+		})
+		cluster.Spec.Tablespaces = []TablespaceConfiguration{{Name: "tbs1"}}
+		Expect(cluster.validateBootstrapRecoveryDataSource()).To(HaveLen(1))
+	})
+
+	It("prevent recovery from other Objects", func() {
+		cluster := clusterFromRecovery(&BootstrapRecovery{
+			VolumeSnapshots: &DataSource{
+				Storage: corev1.TypedLocalObjectReference{
+					APIGroup: ptr.To(""),
+					Kind:     "Secret",
+					Name:     "pgdata",
+				},
+				WalStorage: &corev1.TypedLocalObjectReference{
+					APIGroup: ptr.To(""),
+					Kind:     "ConfigMap",
+					Name:     "pgwal",
+				},
+			},
+		})
+		Expect(cluster.validateBootstrapRecoveryDataSource()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("pg_hba rules validation", func() {
+	newCluster := func(rules ...PgHBARule) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBARules: rules,
+				},
+			},
+		}
+	}
+
+	It("accepts sound rules", func() {
+		cluster := newCluster(
+			PgHBARule{Type: PgHBARuleTypeLocal, Method: PgHBAMethodPeer},
+			PgHBARule{Type: PgHBARuleTypeHostSSL, Address: "all", Method: PgHBAMethodCert},
+			PgHBARule{Type: PgHBARuleTypeHost, Database: "app", User: "app,reporting", Address: "10.0.0.0/8",
+				Method: PgHBAMethodScramSHA256},
+			PgHBARule{Type: PgHBARuleTypeHost, Address: "fd00::/8", Method: PgHBAMethodReject},
+			PgHBARule{Type: PgHBARuleTypeHost, Address: ".example.com", Method: PgHBAMethodLDAP,
+				Options: map[string]string{"ldapserver": "ldap.example.com", "ldapbasedn": "dc=example, dc=com"}},
+		)
+		Expect(cluster.validatePgHBARules()).To(BeEmpty())
+	})
+
+	It("requires the type and the method", func() {
+		result := newCluster(PgHBARule{Address: "all"}).validatePgHBARules()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.pg_hba_rules[0].type"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.pg_hba_rules[0].method"))
+	})
+
+	It("validates the address against the rule type", func() {
+		result := newCluster(
+			PgHBARule{Type: PgHBARuleTypeLocal, Address: "all", Method: PgHBAMethodTrust},
+			PgHBARule{Type: PgHBARuleTypeHost, Method: PgHBAMethodTrust},
+			PgHBARule{Type: PgHBARuleTypeHost, Address: "10.0.0.1", Method: PgHBAMethodTrust},
+			PgHBARule{Type: PgHBARuleTypeHost, Address: "not a host", Method: PgHBAMethodTrust},
+		).validatePgHBARules()
+		Expect(result).To(HaveLen(4))
+		for i := range result {
+			Expect(result[i].Field).To(Equal(fmt.Sprintf("spec.postgresql.pg_hba_rules[%d].address", i)))
+		}
+	})
+
+	It("validates the names and the options", func() {
+		result := newCluster(
+			PgHBARule{
+				Type:     PgHBARuleTypeHost,
+				Database: "app,,other",
+				User:     "my user",
+				Address:  "all",
+				Method:   PgHBAMethodLDAP,
+				Options:  map[string]string{"ldapsearchfilter": `"(uid=$username)"`},
+			},
+		).validatePgHBARules()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.postgresql.pg_hba_rules[0].database"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.pg_hba_rules[0].user"))
+		Expect(result[2].Field).To(Equal("spec.postgresql.pg_hba_rules[0].options[ldapsearchfilter]"))
+	})
+
+	It("checks the methods available only for some rule types", func() {
+		result := newCluster(
+			PgHBARule{Type: PgHBARuleTypeHost, Address: "all", Method: PgHBAMethodPeer},
+			PgHBARule{Type: PgHBARuleTypeHost, Address: "all", Method: PgHBAMethodCert},
+		).validatePgHBARules()
+		Expect(result).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("password authentication validation", func() {
+	newCluster := func(
+		passwordAuthentication *PasswordAuthenticationConfiguration,
+		parameters map[string]string,
+	) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters:             parameters,
+					PasswordAuthentication: passwordAuthentication,
+				},
+			},
+		}
+	}
+
+	It("allows MD5 passwords when SCRAM-SHA-256 is not required", func() {
+		cluster := newCluster(
+			&PasswordAuthenticationConfiguration{},
+			map[string]string{"password_encryption": "md5"})
+		Expect(cluster.validatePasswordAuthentication()).To(BeEmpty())
+	})
+
+	It("accepts SCRAM-SHA-256 passwords when migrating from MD5", func() {
+		cluster := newCluster(
+			&PasswordAuthenticationConfiguration{MigrateMD5: true},
+			map[string]string{"password_encryption": "scram-sha-256"})
+		Expect(cluster.validatePasswordAuthentication()).To(BeEmpty())
+	})
+
+	It("rejects MD5 passwords when requiring channel binding", func() {
+		cluster := newCluster(
+			&PasswordAuthenticationConfiguration{RequireChannelBinding: true},
+			map[string]string{"password_encryption": "md5"})
+		result := cluster.validatePasswordAuthentication()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.password_encryption"))
+	})
+})
+
+var _ = Describe("GSSAPI validation", func() {
+	newCluster := func(gssapi *GSSAPIConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					GSSAPI: gssapi,
+				},
+			},
+		}
+	}
+
+	keytab := SecretKeySelector{
+		LocalObjectReference: LocalObjectReference{Name: "pg-keytab"},
+		Key:                  "keytab",
+	}
+
+	It("does nothing when GSSAPI is not configured", func() {
+		Expect(newCluster(nil).validateGSSAPI()).To(BeEmpty())
+	})
+
+	It("accepts a sound configuration", func() {
+		cluster := newCluster(&GSSAPIConfiguration{
+			Keytab:  keytab,
+			Realm:   "EXAMPLE.COM",
+			Address: "10.0.0.0/8",
+			UserMappings: []GSSAPIUserMapping{
+				{Principal: `/^(.*)@EXAMPLE\.COM$`, User: `\1`},
+			},
+		})
+		Expect(cluster.validateGSSAPI()).To(BeEmpty())
+	})
+
+	It("requires the keytab secret and the addresses", func() {
+		result := newCluster(&GSSAPIConfiguration{}).validateGSSAPI()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.postgresql.gssapi.keytab.name"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.gssapi.keytab.key"))
+		Expect(result[2].Field).To(Equal("spec.postgresql.gssapi.address"))
+	})
+
+	It("rejects values that cannot be rendered in pg_hba and pg_ident", func() {
+		result := newCluster(&GSSAPIConfiguration{
+			Keytab:  keytab,
+			Realm:   `EXAMPLE "COM"`,
+			Address: "10.0.0.1",
+			UserMappings: []GSSAPIUserMapping{
+				{Principal: "john doe@EXAMPLE.COM", User: "john#"},
+			},
+		}).validateGSSAPI()
+		Expect(result).To(HaveLen(4))
+		Expect(result[0].Field).To(Equal("spec.postgresql.gssapi.address"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.gssapi.realm"))
+		Expect(result[2].Field).To(Equal("spec.postgresql.gssapi.userMappings[0].principal"))
+		Expect(result[3].Field).To(Equal("spec.postgresql.gssapi.userMappings[0].user"))
+	})
+
+	It("does not allow overriding the keytab path", func() {
+		cluster := newCluster(&GSSAPIConfiguration{Keytab: keytab, Address: "all"})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"krb_server_keyfile": "/tmp/keytab",
+		}
+		result := cluster.validateGSSAPI()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.krb_server_keyfile"))
+	})
+})
+
+var _ = Describe("validateBootstrapRecoveryVolumeSource", func() {
+	It("does nothing when not recovering from a volumeSource", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateBootstrapRecoveryVolumeSource()).To(BeEmpty())
+	})
+
+	It("accepts a sound configuration", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSource: &VolumeSourceRecovery{
+							Storage:    LocalObjectReference{Name: "old-1"},
+							WalStorage: &LocalObjectReference{Name: "old-1-wal"},
+							TablespaceStorage: map[string]LocalObjectReference{
+								"tbs1": {Name: "old-1-tbs-tbs1"},
+							},
+						},
+					},
+				},
+				WalStorage:  &StorageConfiguration{},
+				Tablespaces: []TablespaceConfiguration{{Name: "tbs1"}},
+			},
+		}
+		Expect(cluster.validateBootstrapRecoveryVolumeSource()).To(BeEmpty())
+	})
+
+	It("complains when combined with other recovery sources", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source: "origin",
+						VolumeSource: &VolumeSourceRecovery{
+							Storage: LocalObjectReference{Name: "old-1"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBootstrapRecoveryVolumeSource()).To(HaveLen(1))
+	})
+
+	It("complains about a recovery target", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{TargetImmediate: ptr.To(true)},
+						VolumeSource: &VolumeSourceRecovery{
+							Storage: LocalObjectReference{Name: "old-1"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBootstrapRecoveryVolumeSource()).To(HaveLen(1))
+	})
+
+	It("complains about missing PVC names and storage configurations", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSource: &VolumeSourceRecovery{
+							WalStorage: &LocalObjectReference{Name: "old-1-wal"},
+							TablespaceStorage: map[string]LocalObjectReference{
+								"tbs1": {Name: "old-1-tbs-tbs1"},
+							},
+						},
+					},
+				},
+			},
+		}
+		result := cluster.validateBootstrapRecoveryVolumeSource()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.volumeSource.storage.name"))
+		Expect(result[1].Field).To(Equal("spec.bootstrap.recovery.volumeSource.walStorage"))
+		Expect(result[2].Field).To(Equal("spec.bootstrap.recovery.volumeSource.tablespaceStorage[tbs1]"))
+	})
+})
+
+var _ = Describe("validateResources", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{},
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: map[corev1.ResourceName]resource.Quantity{},
+					Limits:   map[corev1.ResourceName]resource.Quantity{},
+				},
+			},
+		}
+	})
 
 	It("returns an error when the CPU request is greater than CPU limit", func() {
 		cluster.Spec.Resources.Requests["cpu"] = resource.MustParse("2")
@@ -4351,3 +5419,848 @@ var _ = Describe("Validate hibernation", func() {
 		Expect(cluster.validateHibernationAnnotation()).To(HaveLen(1))
 	})
 })
+
+var _ = Describe("sync replica election constraints validation", func() {
+	It("accepts disabled constraints", func() {
+		Expect(validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{})).To(BeNil())
+	})
+
+	It("requires the labels to compare when enabled", func() {
+		Expect(validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{
+			Enabled: true,
+		})).ToNot(BeNil())
+	})
+
+	It("accepts preferred constraints with the priority-based method", func() {
+		Expect(validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{
+			Enabled:                true,
+			NodeLabelsAntiAffinity: []string{"topology.kubernetes.io/zone"},
+			Method:                 SyncReplicaElectionMethodFirst,
+			Mode:                   SyncReplicaElectionModePreferred,
+		})).To(BeNil())
+	})
+
+	It("rejects preferred constraints with the quorum-based method", func() {
+		result := validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{
+			Enabled:                true,
+			NodeLabelsAntiAffinity: []string{"topology.kubernetes.io/zone"},
+			Mode:                   SyncReplicaElectionModePreferred,
+		})
+		Expect(result).ToNot(BeNil())
+		Expect(result.Field).To(Equal("spec.postgresql.syncReplicaElectionConstraint.mode"))
+	})
+})
+
+var _ = Describe("Probes validation", func() {
+	It("accepts clusters without probes configuration", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateProbes()).To(BeEmpty())
+	})
+
+	It("accepts a maximum lag with the streaming strategy", func() {
+		maximumLag := resource.MustParse("16Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ProbeWithStrategy{
+						Type:       ProbeStrategyStreaming,
+						MaximumLag: &maximumLag,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateProbes()).To(BeEmpty())
+	})
+
+	It("rejects a maximum lag with other strategies", func() {
+		maximumLag := resource.MustParse("16Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Startup: &ProbeWithStrategy{
+						Type:       ProbeStrategyQuery,
+						MaximumLag: &maximumLag,
+					},
+				},
+			},
+		}
+		result := cluster.validateProbes()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.probes.startup.maximumLag"))
+	})
+
+	It("rejects a negative maximum lag", func() {
+		maximumLag := resource.MustParse("-1")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ProbeWithStrategy{
+						Type:       ProbeStrategyStreaming,
+						MaximumLag: &maximumLag,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateProbes()).To(HaveLen(1))
+	})
+
+	It("accepts a maximum number of recovery conflicts with the default readiness strategy", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ProbeWithStrategy{
+						MaximumRecoveryConflicts: ptr.To(int64(10)),
+					},
+				},
+			},
+		}
+		Expect(cluster.validateProbes()).To(BeEmpty())
+	})
+
+	It("rejects a maximum number of recovery conflicts with the pg_isready strategy", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Startup: &ProbeWithStrategy{
+						MaximumRecoveryConflicts: ptr.To(int64(10)),
+					},
+				},
+			},
+		}
+		result := cluster.validateProbes()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.probes.startup.maximumRecoveryConflicts"))
+	})
+
+	It("rejects a negative maximum number of recovery conflicts", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ProbeWithStrategy{
+						Type:                     ProbeStrategyStreaming,
+						MaximumRecoveryConflicts: ptr.To(int64(-1)),
+					},
+				},
+			},
+		}
+		Expect(cluster.validateProbes()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Rejoin strategy validation", func() {
+	It("accepts the default strategy", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateRejoinStrategy()).To(BeEmpty())
+	})
+
+	It("accepts the pgBaseBackup strategy without an object store", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				RejoinStrategy: RejoinStrategyPgBaseBackup,
+			},
+		}
+		Expect(cluster.validateRejoinStrategy()).To(BeEmpty())
+	})
+
+	It("rejects the objectStore strategy without an object store", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				RejoinStrategy: RejoinStrategyObjectStore,
+				Backup:         &BackupConfiguration{},
+			},
+		}
+		result := cluster.validateRejoinStrategy()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.rejoinStrategy"))
+	})
+
+	It("accepts the objectStore strategy with an object store", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				RejoinStrategy: RejoinStrategyObjectStore,
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket/path",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRejoinStrategy()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("TDE validation", func() {
+	secretKeyRef := &SecretKeySelector{
+		LocalObjectReference: LocalObjectReference{Name: "tde-secret"},
+		Key:                  "passphrase",
+	}
+
+	It("accepts clusters without TDE", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateTDE()).To(BeEmpty())
+	})
+
+	It("requires the passphrase secret when TDE is enabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					TDE: &TDEConfiguration{Enabled: true},
+				},
+			},
+		}
+		result := cluster.validateTDE()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.tde.secretKeyRef"))
+
+		cluster.Spec.PostgresConfiguration.TDE.SecretKeyRef = secretKeyRef
+		Expect(cluster.validateTDE()).To(BeEmpty())
+	})
+
+	It("doesn't allow TDE to be enabled on an existing cluster", func() {
+		oldCluster := &Cluster{}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					TDE: &TDEConfiguration{Enabled: true, SecretKeyRef: secretKeyRef},
+				},
+			},
+		}
+		Expect(cluster.validateTDEChange(oldCluster)).To(HaveLen(1))
+		Expect(oldCluster.validateTDEChange(cluster)).To(HaveLen(1))
+		Expect(cluster.validateTDEChange(cluster.DeepCopy())).To(BeEmpty())
+	})
+
+	It("doesn't allow the passphrase secret to be replaced", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					TDE: &TDEConfiguration{Enabled: true, SecretKeyRef: secretKeyRef},
+				},
+			},
+		}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.PostgresConfiguration.TDE.SecretKeyRef.Name = "another-secret"
+		result := cluster.validateTDEChange(oldCluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.tde.secretKeyRef"))
+	})
+})
+
+var _ = Describe("validateFailoverArbiter", func() {
+	It("accepts a cluster without failover arbiter", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateFailoverArbiter()).To(BeEmpty())
+	})
+
+	It("requires a lease", func() {
+		cluster := &Cluster{Spec: ClusterSpec{FailoverArbiter: &FailoverArbiterConfiguration{}}}
+		errs := cluster.validateFailoverArbiter()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.failoverArbiter.lease"))
+	})
+
+	It("requires the kubeconfig secret, the namespace and the identity", func() {
+		cluster := &Cluster{Spec: ClusterSpec{FailoverArbiter: &FailoverArbiterConfiguration{
+			Lease: &FailoverArbiterLease{},
+		}}}
+		Expect(cluster.validateFailoverArbiter()).To(HaveLen(4))
+	})
+
+	It("accepts a complete lease configuration", func() {
+		cluster := &Cluster{Spec: ClusterSpec{FailoverArbiter: &FailoverArbiterConfiguration{
+			Lease: &FailoverArbiterLease{
+				KubeconfigSecret: SecretKeySelector{
+					LocalObjectReference: LocalObjectReference{Name: "arbiter"},
+					Key:                  "kubeconfig",
+				},
+				Namespace: "arbiter",
+				Identity:  "site-a",
+			},
+		}}}
+		Expect(cluster.validateFailoverArbiter()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validateGarbageCollection", func() {
+	It("accepts a missing or a sound configuration", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateGarbageCollection()).To(BeEmpty())
+
+		cluster.Spec.GarbageCollection = &GarbageCollectionConfiguration{
+			RetentionPeriod: &metav1.Duration{Duration: time.Hour},
+		}
+		Expect(cluster.validateGarbageCollection()).To(BeEmpty())
+	})
+
+	It("rejects a negative retention period", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				GarbageCollection: &GarbageCollectionConfiguration{
+					RetentionPeriod: &metav1.Duration{Duration: -time.Hour},
+				},
+			},
+		}
+		Expect(cluster.validateGarbageCollection()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validateMaintenanceWindows", func() {
+	It("accepts valid maintenance windows", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindows: []MaintenanceWindow{
+					{Schedule: "0 0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+				},
+			},
+		}
+		Expect(cluster.validateMaintenanceWindows()).To(BeEmpty())
+	})
+
+	It("rejects invalid schedules and durations", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindows: []MaintenanceWindow{
+					{Schedule: "every saturday", Duration: metav1.Duration{Duration: time.Hour}},
+					{Schedule: "0 0 2 * * 6"},
+				},
+			},
+		}
+		result := cluster.validateMaintenanceWindows()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.maintenanceWindows[0].schedule"))
+		Expect(result[1].Field).To(Equal("spec.maintenanceWindows[1].duration"))
+	})
+})
+
+var _ = Describe("validateDatabaseMaintenance", func() {
+	It("accepts valid maintenance tasks", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				DatabaseMaintenance: &DatabaseMaintenanceConfiguration{
+					Tasks: []MaintenanceTask{
+						{Name: "nightly-analyze", Type: MaintenanceTaskAnalyze, Schedule: "0 0 1 * * *"},
+						{Name: "weekly-reindex", Type: MaintenanceTaskReindex, Schedule: "0 0 3 * * 0", Databases: []string{"app"}},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateDatabaseMaintenance()).To(BeEmpty())
+	})
+
+	It("rejects duplicate names, invalid schedules and empty databases", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				DatabaseMaintenance: &DatabaseMaintenanceConfiguration{
+					Tasks: []MaintenanceTask{
+						{Name: "vacuum", Type: MaintenanceTaskVacuum, Schedule: "every night"},
+						{Name: "vacuum", Type: MaintenanceTaskVacuum, Schedule: "0 0 1 * * *", Databases: []string{""}},
+					},
+				},
+			},
+		}
+		result := cluster.validateDatabaseMaintenance()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.databaseMaintenance.tasks[0].schedule"))
+		Expect(result[1].Field).To(Equal("spec.databaseMaintenance.tasks[1].name"))
+		Expect(result[2].Field).To(Equal("spec.databaseMaintenance.tasks[1].databases[0]"))
+	})
+})
+
+var _ = Describe("validateUpdatePolicy", func() {
+	It("accepts a cluster without update policy", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateUpdatePolicy()).To(BeEmpty())
+	})
+
+	It("accepts an update policy for a cluster using an image catalog", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "production"},
+			Spec: ClusterSpec{
+				ImageCatalogRef: &ImageCatalogRef{Major: 16},
+				UpdatePolicy: &UpdatePolicy{
+					TagPattern: `^16\.[0-9]+$`,
+					Canaries:   []LocalObjectReference{{Name: "staging"}},
+				},
+			},
+		}
+		Expect(cluster.validateUpdatePolicy()).To(BeEmpty())
+	})
+
+	It("rejects an update policy without image catalog", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName:    "postgres:16.2",
+				UpdatePolicy: &UpdatePolicy{},
+			},
+		}
+		result := cluster.validateUpdatePolicy()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.updatePolicy"))
+	})
+
+	It("rejects invalid tag patterns and the cluster being its own canary", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "production"},
+			Spec: ClusterSpec{
+				ImageCatalogRef: &ImageCatalogRef{Major: 16},
+				UpdatePolicy: &UpdatePolicy{
+					TagPattern: "16.(",
+					Canaries:   []LocalObjectReference{{Name: "staging"}, {Name: "production"}},
+				},
+			},
+		}
+		result := cluster.validateUpdatePolicy()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.updatePolicy.tagPattern"))
+		Expect(result[1].Field).To(Equal("spec.updatePolicy.canaries[1]"))
+	})
+})
+
+var _ = Describe("validateLogFiles", func() {
+	It("accepts a cluster without log files", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateLogFiles()).To(BeEmpty())
+	})
+
+	It("accepts valid limits", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				LogFiles: &LogFilesConfiguration{
+					Enabled:   true,
+					MaxSize:   ptr.To(resource.MustParse("10Mi")),
+					MaxAge:    &metav1.Duration{Duration: 24 * time.Hour},
+					SizeLimit: ptr.To(resource.MustParse("1Gi")),
+				},
+			},
+		}
+		Expect(cluster.validateLogFiles()).To(BeEmpty())
+	})
+
+	It("rejects invalid limits and a size limit for an ephemeral volume", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				LogFiles: &LogFilesConfiguration{
+					Enabled:               true,
+					MaxSize:               ptr.To(resource.MustParse("0")),
+					MaxAge:                &metav1.Duration{Duration: -time.Hour},
+					SizeLimit:             ptr.To(resource.MustParse("1Gi")),
+					EphemeralVolumeSource: &corev1.EphemeralVolumeSource{},
+				},
+			},
+		}
+		result := cluster.validateLogFiles()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.logFiles.maxSize"))
+		Expect(result[1].Field).To(Equal("spec.logFiles.maxAge"))
+		Expect(result[2].Field).To(Equal("spec.logFiles.sizeLimit"))
+	})
+})
+
+var _ = Describe("validateShutdownTimeouts", func() {
+	It("accepts a cluster without fast shutdown timeout", func() {
+		cluster := &Cluster{Spec: ClusterSpec{MaxStopDelay: 100, SmartShutdownTimeout: 180}}
+		Expect(cluster.validateShutdownTimeouts()).To(BeEmpty())
+	})
+
+	It("accepts timeouts fitting in the stop delay", func() {
+		cluster := &Cluster{Spec: ClusterSpec{FastShutdownTimeout: 60}}
+		Expect(cluster.validateShutdownTimeouts()).To(BeEmpty())
+	})
+
+	It("rejects timeouts not fitting in the stop delay", func() {
+		cluster := &Cluster{Spec: ClusterSpec{MaxStopDelay: 300, SmartShutdownTimeout: 200, FastShutdownTimeout: 100}}
+		errs := cluster.validateShutdownTimeouts()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.fastShutdownTimeout"))
+	})
+})
+
+var _ = Describe("replica parameters validation", func() {
+	newCluster := func(parameters map[string]string) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ReplicaParameters: parameters,
+				},
+			},
+		}
+	}
+
+	It("does nothing when no replica parameters are set", func() {
+		Expect(newCluster(nil).validateReplicaParameters()).To(BeEmpty())
+	})
+
+	It("accepts the parameters that can differ on the replicas", func() {
+		cluster := newCluster(map[string]string{
+			"hot_standby_feedback":        "on",
+			"max_standby_streaming_delay": "-1",
+		})
+		Expect(cluster.validateReplicaParameters()).To(BeEmpty())
+	})
+
+	It("rejects the fixed parameters", func() {
+		cluster := newCluster(map[string]string{"archive_mode": "off"})
+		Expect(cluster.validateReplicaParameters()).To(HaveLen(1))
+	})
+
+	It("rejects the parameters that must be consistent with the primary", func() {
+		cluster := newCluster(map[string]string{
+			"max_connections": "500",
+			"wal_level":       "logical",
+		})
+		Expect(cluster.validateReplicaParameters()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("validateSecretsStore", func() {
+	It("accepts a cluster without the Secrets Store", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateSecretsStore()).To(BeEmpty())
+	})
+
+	It("accepts password files inside the mounted volume", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				SecretsStore: &SecretsStoreConfiguration{
+					SecretProviderClass:     "vault-passwords",
+					SuperuserPasswordFile:   "superuser-password",
+					ApplicationPasswordFile: "app/password",
+				},
+			},
+		}
+		Expect(cluster.validateSecretsStore()).To(BeEmpty())
+	})
+
+	It("rejects password files outside the mounted volume", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				SecretsStore: &SecretsStoreConfiguration{
+					SecretProviderClass:     "vault-passwords",
+					SuperuserPasswordFile:   "/etc/passwd",
+					ApplicationPasswordFile: "../app-secret/password",
+				},
+			},
+		}
+		result := cluster.validateSecretsStore()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.secretsStore.superuserPasswordFile"))
+		Expect(result[1].Field).To(Equal("spec.secretsStore.applicationPasswordFile"))
+	})
+})
+
+var _ = Describe("unsafe parameters", func() {
+	newCluster := func(parameters map[string]string) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{Parameters: parameters},
+			},
+		}
+	}
+
+	It("rejects the unsafe values, whatever their spelling", func() {
+		cluster := newCluster(map[string]string{
+			"fsync":              "False",
+			"full_page_writes":   "on",
+			"zero_damaged_pages": "yes",
+		})
+		result := cluster.validateUnsafeParameters(nil)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.fsync"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.parameters.zero_damaged_pages"))
+	})
+
+	It("accepts the unsafe values listed in the annotation with a warning", func() {
+		cluster := newCluster(map[string]string{
+			"fsync":              "off",
+			"zero_damaged_pages": "on",
+		})
+		cluster.Annotations = map[string]string{
+			utils.UnsafeParametersAnnotationName: "fsync, zero_damaged_pages",
+		}
+		Expect(cluster.validateUnsafeParameters(nil)).To(BeEmpty())
+
+		warnings := cluster.getUnsafeParametersAdmissionWarnings()
+		Expect(warnings).To(HaveLen(2))
+		Expect(warnings[0]).To(ContainSubstring("`fsync`"))
+	})
+
+	It("only accepts the parameters listed in the annotation", func() {
+		cluster := newCluster(map[string]string{
+			"fsync":            "off",
+			"full_page_writes": "off",
+		})
+		cluster.Annotations = map[string]string{utils.UnsafeParametersAnnotationName: "fsync"}
+
+		result := cluster.validateUnsafeParameters(nil)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.full_page_writes"))
+	})
+
+	It("only rejects the unsafe values changed by an update, warning about the others", func() {
+		oldCluster := newCluster(map[string]string{"fsync": "off"})
+		cluster := newCluster(map[string]string{
+			"fsync":            "false",
+			"full_page_writes": "off",
+		})
+
+		result := cluster.validateUnsafeParameters(oldCluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.full_page_writes"))
+
+		delete(cluster.Spec.PostgresConfiguration.Parameters, "full_page_writes")
+		Expect(cluster.validateUnsafeParameters(oldCluster)).To(BeEmpty())
+
+		warnings := cluster.getUnsafeParametersAdmissionWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("Add `fsync`"))
+	})
+})
+
+var _ = Describe("configuration admission warnings", func() {
+	It("warns when wal_level is minimal", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"wal_level": "minimal"},
+				},
+			},
+		}
+		Expect(cluster.getWalLevelAdmissionWarnings()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.Parameters["wal_level"] = "logical"
+		Expect(cluster.getWalLevelAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("warns when the connections can't fit in the available memory", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"max_connections": "1000",
+						"shared_buffers":  "256MB",
+						"work_mem":        "4096",
+					},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+		}
+		warnings := cluster.getMaxConnectionsAdmissionWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("`max_connections` is 1000"))
+
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "100"
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn about the connections when the memory is not constrained", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"max_connections": "10000"},
+				},
+			},
+		}
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("lifecycle hooks validation", func() {
+	hooksConfigMap := func(key string) *ConfigMapKeySelector {
+		return &ConfigMapKeySelector{
+			LocalObjectReference: LocalObjectReference{Name: "hooks"},
+			Key:                  key,
+		}
+	}
+
+	It("accepts SQL and shell scripts", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PreStart:      []LifecycleHook{{Name: "mount", Script: hooksConfigMap("mount.sh")}},
+					PostPromotion: []LifecycleHook{{Name: "notify", SQL: hooksConfigMap("notify.sql")}},
+					PreDemotion:   []LifecycleHook{{Name: "notify", SQL: hooksConfigMap("notify.sql")}},
+				},
+			},
+		}
+		Expect(cluster.validateLifecycleHooks()).To(BeEmpty())
+	})
+
+	It("rejects SQL scripts before PostgreSQL is started", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PreStart: []LifecycleHook{{Name: "setup", SQL: hooksConfigMap("setup.sql")}},
+				},
+			},
+		}
+		errs := cluster.validateLifecycleHooks()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.lifecycleHooks.preStart[0].sql"))
+	})
+
+	It("requires exactly one content for every hook", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PostStart: []LifecycleHook{
+						{Name: "empty"},
+						{Name: "both", SQL: hooksConfigMap("both.sql"), Script: hooksConfigMap("both.sh")},
+					},
+				},
+			},
+		}
+		errs := cluster.validateLifecycleHooks()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.lifecycleHooks.postStart[0]"))
+		Expect(errs[1].Field).To(Equal("spec.lifecycleHooks.postStart[1]"))
+	})
+
+	It("rejects duplicate names in the same point", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PreStop: []LifecycleHook{
+						{Name: "flush", Script: hooksConfigMap("flush.sh")},
+						{Name: "flush", SQL: hooksConfigMap("flush.sql")},
+					},
+				},
+			},
+		}
+		errs := cluster.validateLifecycleHooks()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeDuplicate))
+	})
+})
+
+var _ = Describe("validateInactiveReplicationSlots", func() {
+	It("accepts clusters without inactive slots detection", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateInactiveReplicationSlots()).To(BeEmpty())
+	})
+
+	It("accepts valid exclude patterns", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					InactiveSlots: &InactiveSlotsConfiguration{
+						ExcludePatterns: []string{"^debezium_", "backup$"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInactiveReplicationSlots()).To(BeEmpty())
+	})
+
+	It("rejects invalid exclude patterns", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					InactiveSlots: &InactiveSlotsConfiguration{
+						ExcludePatterns: []string{"^debezium_", "([a-z"},
+					},
+				},
+			},
+		}
+		errs := cluster.validateInactiveReplicationSlots()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.replicationSlots.inactiveSlots.excludePatterns[1]"))
+	})
+})
+
+var _ = Describe("validateObjectSizes", func() {
+	It("accepts clusters without the object sizes collector", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateObjectSizes()).To(BeEmpty())
+	})
+
+	It("rejects invalid patterns", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					ObjectSizes: &ObjectSizesConfiguration{
+						Enabled:          true,
+						IncludeDatabases: []string{"^app"},
+						ExcludeDatabases: []string{"(test"},
+						ExcludeTables:    []string{`\.tmp_`, "[a-z"},
+					},
+				},
+			},
+		}
+		errs := cluster.validateObjectSizes()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.monitoring.objectSizes.excludeDatabases[0]"))
+		Expect(errs[1].Field).To(Equal("spec.monitoring.objectSizes.excludeTables[1]"))
+	})
+})
+
+var _ = Describe("validateStorageEmergencyCleanup", func() {
+	It("accepts the emergency cleanup of the WAL volume", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{
+					EmergencyCleanup: &StorageEmergencyCleanupConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.validateStorageEmergencyCleanup()).To(BeEmpty())
+	})
+
+	It("rejects the emergency cleanup of the other volumes", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					EmergencyCleanup: &StorageEmergencyCleanupConfiguration{Enabled: true},
+				},
+				Tablespaces: []TablespaceConfiguration{
+					{Name: "first"},
+					{
+						Name: "second",
+						Storage: StorageConfiguration{
+							EmergencyCleanup: &StorageEmergencyCleanupConfiguration{},
+						},
+					},
+				},
+			},
+		}
+		errs := cluster.validateStorageEmergencyCleanup()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.storage.emergencyCleanup"))
+		Expect(errs[1].Field).To(Equal("spec.tablespaces[1].storage.emergencyCleanup"))
+	})
+
+	It("accepts the protection stages around the threshold", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{
+					EmergencyCleanup: &StorageEmergencyCleanupConfiguration{
+						Enabled:                      true,
+						ThrottleConnectionsThreshold: 80,
+						FenceWritesThreshold:         95,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateStorageEmergencyCleanup()).To(BeEmpty())
+	})
+
+	It("rejects the protection stages in the wrong order", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalStorage: &StorageConfiguration{
+					EmergencyCleanup: &StorageEmergencyCleanupConfiguration{
+						Enabled:                      true,
+						Threshold:                    85,
+						ThrottleConnectionsThreshold: 85,
+						FenceWritesThreshold:         80,
+					},
+				},
+			},
+		}
+		errs := cluster.validateStorageEmergencyCleanup()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.walStorage.emergencyCleanup.throttleConnectionsThreshold"))
+		Expect(errs[1].Field).To(Equal("spec.walStorage.emergencyCleanup.fenceWritesThreshold"))
+	})
+})
@@ -0,0 +1,237 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// DatabaseReclaimPolicy describes a policy for end-of-life maintenance of databases.
+// +enum
+type DatabaseReclaimPolicy string
+
+const (
+	// DatabaseReclaimDelete means the database will be deleted from PostgreSQL
+	// when the Database object is removed
+	DatabaseReclaimDelete DatabaseReclaimPolicy = "delete"
+
+	// DatabaseReclaimRetain means the database will be left in its current phase
+	// (i.e. not dropped) when the Database object is removed
+	DatabaseReclaimRetain DatabaseReclaimPolicy = "retain"
+)
+
+// DatabaseFinalizerName is the name of the finalizer used by the instance
+// manager to drop the database when the Database object is removed
+const DatabaseFinalizerName = utils.MetadataNamespace + "/deleteDatabase"
+
+// DatabaseSpec is the specification of a PostgreSQL database
+// +kubebuilder:validation:XValidation:rule="!has(self.isTemplate) || !self.isTemplate || self.ensure != 'absent'",message="a template database cannot be absent"
+type DatabaseSpec struct {
+	// The corresponding cluster
+	ClusterRef LocalObjectReference `json:"cluster"`
+
+	// Ensure the PostgreSQL database is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The name inside PostgreSQL
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	// +kubebuilder:validation:XValidation:rule="self != 'postgres'",message="the name postgres is reserved"
+	// +kubebuilder:validation:XValidation:rule="self != 'template0'",message="the name template0 is reserved"
+	// +kubebuilder:validation:XValidation:rule="self != 'template1'",message="the name template1 is reserved"
+	Name string `json:"name"`
+
+	// The owner
+	Owner string `json:"owner"`
+
+	// The name of the template from which to create the new database
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="template is immutable"
+	Template string `json:"template,omitempty"`
+
+	// The encoding (cannot be changed)
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="encoding is immutable"
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The locale (cannot be changed)
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="locale is immutable"
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// The LC_COLLATE (cannot be changed)
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="localeCollate is immutable"
+	// +optional
+	LcCollate string `json:"localeCollate,omitempty"`
+
+	// The LC_CTYPE (cannot be changed)
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="localeCType is immutable"
+	// +optional
+	LcCtype string `json:"localeCType,omitempty"`
+
+	// True when the database is a template
+	// +optional
+	IsTemplate *bool `json:"isTemplate,omitempty"`
+
+	// True when connections to this database are allowed
+	// +optional
+	AllowConnections *bool `json:"allowConnections,omitempty"`
+
+	// Connection limit, -1 means no limit and -2 means the
+	// database is not valid
+	// +optional
+	ConnectionLimit *int `json:"connectionLimit,omitempty"`
+
+	// The default tablespace of this database
+	// +optional
+	Tablespace string `json:"tablespace,omitempty"`
+
+	// The list of extensions to be managed in this database
+	// +optional
+	Extensions []DatabaseExtensionSpec `json:"extensions,omitempty"`
+
+	// The policy for end-of-life maintenance of this database
+	// +kubebuilder:validation:Enum=delete;retain
+	// +kubebuilder:default:=retain
+	// +optional
+	ReclaimPolicy DatabaseReclaimPolicy `json:"databaseReclaimPolicy,omitempty"`
+}
+
+// DatabaseExtensionSpec configures an extension in a database
+type DatabaseExtensionSpec struct {
+	// Name of the extension
+	Name string `json:"name"`
+
+	// Ensure the extension is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The version of the extension to install. If empty, the default
+	// version of the extension will be used, and the extension will
+	// not be updated when already installed
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema where the extension objects will be created.
+	// This is used only when creating the extension
+	// +optional
+	Schema string `json:"schema,omitempty"`
+}
+
+// DatabaseStatus defines the observed state of Database
+type DatabaseStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Applied is true if the database was reconciled correctly
+	// +optional
+	Applied *bool `json:"applied,omitempty"`
+
+	// Message is the reconciliation output message
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Extensions is the list of the extensions installed in the
+	// database, with their versions
+	// +optional
+	Extensions []DatabaseExtensionStatus `json:"extensions,omitempty"`
+}
+
+// DatabaseExtensionStatus is the status of an extension installed in a database
+type DatabaseExtensionStatus struct {
+	// Name of the extension
+	Name string `json:"name"`
+
+	// The installed version of the extension
+	Version string `json:"version"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Applied",type="boolean",JSONPath=".status.applied"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",description="Latest reconciliation message"
+
+// Database is the Schema for the databases API
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Database.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec DatabaseSpec `json:"spec"`
+	// Most recently observed status of the Database. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status DatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseList contains a list of Database
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of databases
+	Items []Database `json:"items"`
+}
+
+// GetReclaimPolicy returns the reclaim policy of the database,
+// defaulting to "retain"
+func (db *Database) GetReclaimPolicy() DatabaseReclaimPolicy {
+	if db.Spec.ReclaimPolicy == "" {
+		return DatabaseReclaimRetain
+	}
+	return db.Spec.ReclaimPolicy
+}
+
+// GetEnsure returns whether the database should be present or absent,
+// defaulting to "present"
+func (db *Database) GetEnsure() EnsureOption {
+	if db.Spec.Ensure == "" {
+		return EnsurePresent
+	}
+	return db.Spec.Ensure
+}
+
+// GetEnsure returns whether the extension should be present or absent,
+// defaulting to "present"
+func (ext DatabaseExtensionSpec) GetEnsure() EnsureOption {
+	if ext.Ensure == "" {
+		return EnsurePresent
+	}
+	return ext.Ensure
+}
+
+func init() {
+	SchemeBuilder.Register(&Database{}, &DatabaseList{})
+}
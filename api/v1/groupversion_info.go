@@ -57,6 +57,12 @@ var (
 	// ClusterImageCatalogKind is the kind name of the cluster-wide image catalogs
 	ClusterImageCatalogKind = "ClusterImageCatalog"
 
+	// BenchmarkKind is the kind name of Benchmarks
+	BenchmarkKind = "Benchmark"
+
+	// ChangeDataCaptureKind is the kind name of ChangeDataCaptures
+	ChangeDataCaptureKind = "ChangeDataCapture"
+
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
 
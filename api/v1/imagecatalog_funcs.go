@@ -18,10 +18,23 @@ package v1
 
 // FindImageForMajor finds the correct image for the selected major version
 func (spec *ImageCatalogSpec) FindImageForMajor(major int) (string, bool) {
+	return spec.FindImageForMajorAndArchitecture(major, "")
+}
+
+// FindImageForMajorAndArchitecture finds the correct image for the selected
+// major version, preferring the one built for the passed architecture, if any
+func (spec *ImageCatalogSpec) FindImageForMajorAndArchitecture(major int, architecture string) (string, bool) {
 	for _, entry := range spec.Images {
-		if entry.Major == major {
-			return entry.Image, true
+		if entry.Major != major {
+			continue
+		}
+
+		for _, architectureImage := range entry.Architectures {
+			if architecture != "" && architectureImage.Architecture == architecture {
+				return architectureImage.Image, true
+			}
 		}
+		return entry.Image, true
 	}
 
 	return "", false
@@ -46,4 +46,34 @@ var _ = Describe("image catalog", func() {
 		Expect(image).To(BeEmpty())
 		Expect(ok).To(BeFalse())
 	})
+
+	It("prefers the image built for the requested architecture", func() {
+		spec := ImageCatalogSpec{
+			Images: []CatalogImage{
+				{
+					Image: "test:16",
+					Major: 16,
+					Architectures: []ArchitectureImage{
+						{Architecture: "amd64", Image: "test@sha256:amd64"},
+						{Architecture: "arm64", Image: "test@sha256:arm64"},
+					},
+				},
+			},
+		}
+
+		image, ok := spec.FindImageForMajorAndArchitecture(16, "arm64")
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal("test@sha256:arm64"))
+
+		image, ok = spec.FindImageForMajorAndArchitecture(16, "s390x")
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal("test:16"))
+
+		image, ok = spec.FindImageForMajorAndArchitecture(16, "")
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal("test:16"))
+
+		_, ok = spec.FindImageForMajorAndArchitecture(15, "arm64")
+		Expect(ok).To(BeFalse())
+	})
 })
@@ -36,6 +36,26 @@ type CatalogImage struct {
 	// +kubebuilder:validation:Minimum=10
 	// The PostgreSQL major version of the image. Must be unique within the catalog.
 	Major int `json:"major"`
+
+	// The images to be used on nodes of a given architecture, usually
+	// pinned by digest. When the pods of a Cluster can only be scheduled on
+	// nodes of one architecture, through the `kubernetes.io/arch` label,
+	// the matching image takes precedence over `image`
+	// +kubebuilder:validation:MaxItems=8
+	// +kubebuilder:validation:XValidation:rule="self.all(e, self.filter(f, f.architecture==e.architecture).size() == 1)",message=Images must have unique architectures
+	// +optional
+	Architectures []ArchitectureImage `json:"architectures,omitempty"`
+}
+
+// ArchitectureImage defines the image to be used on a given architecture
+type ArchitectureImage struct {
+	// The architecture, as reported by the `kubernetes.io/arch` node label
+	// +kubebuilder:validation:MinLength=1
+	Architecture string `json:"architecture"`
+
+	// The image reference
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
 }
 
 // +genclient
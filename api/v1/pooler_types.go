@@ -17,8 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -37,6 +40,19 @@ const (
 
 	// DefaultPgBouncerPoolerAuthQuery is the default auth_query for PgBouncer
 	DefaultPgBouncerPoolerAuthQuery = "SELECT usename, passwd FROM public.user_search($1)"
+
+	// DefaultPgBouncerAuthQueryFunctionSchema is the default schema of the
+	// function looking up the password hashes for the auth query, which is
+	// created by the operator and not writable by the owner of the database
+	DefaultPgBouncerAuthQueryFunctionSchema = "pgbouncer"
+
+	// DefaultPgBouncerAuthQueryFunctionName is the default name of the
+	// function looking up the password hashes for the auth query
+	DefaultPgBouncerAuthQueryFunctionName = "user_search"
+
+	// PgBouncerAdminDatabase is the name of the virtual database
+	// exposing the PgBouncer admin console
+	PgBouncerAdminDatabase = "pgbouncer"
 )
 
 // PgBouncerPoolMode is the mode of PgBouncer
@@ -67,6 +83,13 @@ type PoolerSpec struct {
 	// +optional
 	Instances *int32 `json:"instances,omitempty"`
 
+	// The horizontal autoscaling configuration of the pooler. When set,
+	// the number of PgBouncer instances is managed by an
+	// HorizontalPodAutoscaler and `instances` is only used as the initial
+	// size of the deployment.
+	// +optional
+	Autoscaling *PoolerAutoscaling `json:"autoscaling,omitempty"`
+
 	// The template of the Pod to be created
 	// +optional
 	Template *PodTemplateSpec `json:"template,omitempty"`
@@ -87,6 +110,47 @@ type PoolerSpec struct {
 	ServiceTemplate *ServiceTemplateSpec `json:"serviceTemplate,omitempty"`
 }
 
+// PoolerAutoscaling defines how the number of PgBouncer instances
+// is adjusted depending on the load
+type PoolerAutoscaling struct {
+	// The lower limit for the number of PgBouncer instances. Default: 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinInstances *int32 `json:"minInstances,omitempty"`
+
+	// The upper limit for the number of PgBouncer instances
+	// +kubebuilder:validation:Minimum=1
+	MaxInstances int32 `json:"maxInstances"`
+
+	// The average number of active client connections per PgBouncer
+	// instance the autoscaler will try to keep. The
+	// `cnpg_pgbouncer_pools_cl_active` metric needs to be exposed through
+	// the custom metrics API, e.g. with the Prometheus Adapter.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetClientConnections *int32 `json:"targetClientConnections,omitempty"`
+
+	// The average CPU utilization of the PgBouncer instances the
+	// autoscaler will try to keep, expressed as a percentage of the
+	// requested CPU
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// The scaling behavior of the autoscaler, in both the up and
+	// down directions
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// GetMinInstances gets the lower limit for the number of PgBouncer instances
+func (in *PoolerAutoscaling) GetMinInstances() int32 {
+	if in.MinInstances == nil {
+		return 1
+	}
+	return *in.MinInstances
+}
+
 // PoolerMonitoringConfiguration is the type containing all the monitoring
 // configuration for a certain Pooler.
 //
@@ -144,6 +208,41 @@ type ServiceTemplateSpec struct {
 	Spec corev1.ServiceSpec `json:"spec,omitempty"`
 }
 
+// PgBouncerAuthQueryProvisioning is the configuration of the objects
+// needed by the auth query which are managed by the operator
+type PgBouncerAuthQueryProvisioning struct {
+	// The schema of the function looking up the password hashes. It is
+	// created if it doesn't exist, and must otherwise be owned by a
+	// superuser. Default: `pgbouncer`
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	FunctionSchema string `json:"functionSchema,omitempty"`
+
+	// The name of the function looking up the password hashes.
+	// Default: `user_search`
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	FunctionName string `json:"functionName,omitempty"`
+}
+
+// GetFunctionSchema gets the schema of the function looking up the password hashes
+func (in *PgBouncerAuthQueryProvisioning) GetFunctionSchema() string {
+	if in == nil || in.FunctionSchema == "" {
+		return DefaultPgBouncerAuthQueryFunctionSchema
+	}
+	return in.FunctionSchema
+}
+
+// GetFunctionName gets the name of the function looking up the password hashes
+func (in *PgBouncerAuthQueryProvisioning) GetFunctionName() string {
+	if in == nil || in.FunctionName == "" {
+		return DefaultPgBouncerAuthQueryFunctionName
+	}
+	return in.FunctionName
+}
+
 // PgBouncerSpec defines how to configure PgBouncer
 type PgBouncerSpec struct {
 	// The pool mode. Default: `session`.
@@ -165,6 +264,14 @@ type PgBouncerSpec struct {
 	// +optional
 	AuthQuery string `json:"authQuery,omitempty"`
 
+	// Let the instance manager of the primary create, and keep in sync, the
+	// user of the AuthQuerySecret, the function looking up the password hashes
+	// and the grants they need in every database of the cluster. When it is
+	// set, the AuthQuery can be omitted and defaults to a query calling the
+	// provisioned function
+	// +optional
+	AuthQueryProvisioning *PgBouncerAuthQueryProvisioning `json:"authQueryProvisioning,omitempty"`
+
 	// Additional parameters to be passed to PgBouncer - please check
 	// the CNPG documentation for a list of options you can configure
 	// +optional
@@ -182,6 +289,21 @@ type PgBouncerSpec struct {
 	// +kubebuilder:default:=false
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// The databases to be explicitly declared in PgBouncer, each one with
+	// its own pool settings. Clients connecting to any other database
+	// are still served with the settings of the pooler.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []PgBouncerDatabase `json:"databases,omitempty"`
+
+	// The pool settings specific to some users, overriding the ones
+	// of the pooler and of the databases
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Users []PgBouncerUser `json:"users,omitempty"`
 }
 
 // IsPaused returns whether all database should be paused or not.
@@ -189,6 +311,74 @@ func (in PgBouncerSpec) IsPaused() bool {
 	return in.Paused != nil && *in.Paused
 }
 
+// PgBouncerDatabase is an entry of the `[databases]` section of
+// the PgBouncer configuration
+type PgBouncerDatabase struct {
+	// The name of the database, as requested by the clients
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The name of the database in the PostgreSQL cluster.
+	// Defaults to `name`
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// The pool mode for this database, overriding the one of the pooler
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections of each user/database
+	// pool. Overrides the `default_pool_size` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// The minimum number of server connections kept open in each
+	// user/database pool. Overrides the `min_pool_size` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinPoolSize *int32 `json:"minPoolSize,omitempty"`
+
+	// The number of additional connections allowed to each pool when it
+	// is exhausted. Overrides the `reserve_pool_size` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReservePoolSize *int32 `json:"reservePoolSize,omitempty"`
+
+	// The maximum number of server connections to this database, across
+	// all the pools. Overrides the `max_db_connections` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDBConnections *int32 `json:"maxDBConnections,omitempty"`
+}
+
+// GetDBName gets the name of the database in the PostgreSQL cluster
+func (in PgBouncerDatabase) GetDBName() string {
+	if in.DBName != "" {
+		return in.DBName
+	}
+	return in.Name
+}
+
+// PgBouncerUser is an entry of the `[users]` section of
+// the PgBouncer configuration
+type PgBouncerUser struct {
+	// The name of the user
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The pool mode for this user, overriding the one of the pooler
+	// and of the databases
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections of this user, across
+	// all the pools. Overrides the `max_user_connections` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUserConnections *int32 `json:"maxUserConnections,omitempty"`
+}
+
 // PoolerStatus defines the observed state of Pooler
 type PoolerStatus struct {
 	// The resource version of the config object
@@ -290,9 +480,21 @@ func (in *Pooler) GetAuthQuery() string {
 		return in.Spec.PgBouncer.AuthQuery
 	}
 
+	if provisioning := in.Spec.PgBouncer.AuthQueryProvisioning; provisioning != nil {
+		return fmt.Sprintf("SELECT usename, passwd FROM %s.%s($1)",
+			provisioning.GetFunctionSchema(), provisioning.GetFunctionName())
+	}
+
 	return DefaultPgBouncerPoolerAuthQuery
 }
 
+// IsAuthQueryProvisioned returns whether the objects needed by a custom
+// auth query are managed by the operator
+func (in *Pooler) IsAuthQueryProvisioned() bool {
+	return in.Spec.PgBouncer != nil && in.Spec.PgBouncer.AuthQueryProvisioning != nil &&
+		in.Spec.PgBouncer.AuthQuerySecret != nil && in.Spec.PgBouncer.AuthQuerySecret.Name != ""
+}
+
 // IsAutomatedIntegration returns whether the Pooler integration with the
 // Cluster is automated or not.
 func (in *Pooler) IsAutomatedIntegration() bool {
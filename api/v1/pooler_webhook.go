@@ -18,6 +18,7 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -150,11 +151,17 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 				field.NewPath("spec", "pgbouncer"),
 				"", "required pgbouncer configuration"))
 	case r.Spec.PgBouncer.AuthQuerySecret != nil && r.Spec.PgBouncer.AuthQuerySecret.Name != "" &&
-		r.Spec.PgBouncer.AuthQuery == "":
+		r.Spec.PgBouncer.AuthQuery == "" && r.Spec.PgBouncer.AuthQueryProvisioning == nil:
 		result = append(result,
 			field.Invalid(
 				field.NewPath("spec", "pgbouncer", "authQuery"),
 				"", "must specify an auth query when providing an auth query secret"))
+	case (r.Spec.PgBouncer.AuthQuerySecret == nil || r.Spec.PgBouncer.AuthQuerySecret.Name == "") &&
+		r.Spec.PgBouncer.AuthQueryProvisioning != nil:
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "pgbouncer", "authQuerySecret", "name"),
+				"", "must specify an auth query secret when letting the operator provision the auth query"))
 	case (r.Spec.PgBouncer.AuthQuerySecret == nil || r.Spec.PgBouncer.AuthQuerySecret.Name == "") &&
 		r.Spec.PgBouncer.AuthQuery != "":
 		result = append(result,
@@ -167,9 +174,87 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 		result = append(result, r.validatePgbouncerGenericParameters()...)
 	}
 
+	if r.Spec.PgBouncer != nil {
+		result = append(result, r.validatePgBouncerDatabases()...)
+		result = append(result, r.validatePgBouncerUsers()...)
+		result = append(result, r.validatePgBouncerAuthQueryProvisioning()...)
+	}
+
+	return result
+}
+
+// validatePgBouncerDatabases validates the databases declared in PgBouncer
+func (r *Pooler) validatePgBouncerDatabases() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "pgbouncer", "databases")
+	for i, database := range r.Spec.PgBouncer.Databases {
+		databasePath := basePath.Index(i)
+		switch {
+		case database.Name == "*":
+			result = append(result, field.Invalid(databasePath.Child("name"), database.Name,
+				"the wildcard database is managed by the operator"))
+		case database.Name == PgBouncerAdminDatabase:
+			result = append(result, field.Invalid(databasePath.Child("name"), database.Name,
+				"the name is reserved for the PgBouncer admin console"))
+		case !isValidPgBouncerName(database.Name):
+			result = append(result, field.Invalid(databasePath.Child("name"), database.Name,
+				pgBouncerNameErrorMessage))
+		}
+		if database.DBName != "" && !isValidPgBouncerName(database.DBName) {
+			result = append(result, field.Invalid(databasePath.Child("dbname"), database.DBName,
+				pgBouncerNameErrorMessage))
+		}
+	}
+
+	return result
+}
+
+// validatePgBouncerUsers validates the users declared in PgBouncer
+func (r *Pooler) validatePgBouncerUsers() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "pgbouncer", "users")
+	for i, user := range r.Spec.PgBouncer.Users {
+		if !isValidPgBouncerName(user.Name) {
+			result = append(result, field.Invalid(basePath.Index(i).Child("name"), user.Name,
+				pgBouncerNameErrorMessage))
+		}
+	}
+
 	return result
 }
 
+// validatePgBouncerAuthQueryProvisioning validates the schema of the
+// function provisioned for the auth query, which can't be a schema
+// reserved by PostgreSQL or writable by the owner of the database
+func (r *Pooler) validatePgBouncerAuthQueryProvisioning() field.ErrorList {
+	provisioning := r.Spec.PgBouncer.AuthQueryProvisioning
+	if provisioning == nil {
+		return nil
+	}
+
+	schemaName := provisioning.GetFunctionSchema()
+	if schemaName == "public" || schemaName == "information_schema" || strings.HasPrefix(schemaName, "pg_") {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "pgbouncer", "authQueryProvisioning", "functionSchema"),
+				schemaName, "cannot be public, information_schema or a schema starting with pg_"),
+		}
+	}
+
+	return nil
+}
+
+const pgBouncerNameErrorMessage = "names cannot be empty or contain spaces, quotes, " +
+	"or any of the '=', ';', '#', '[' and ']' characters"
+
+// isValidPgBouncerName checks if the passed name can be used as a key
+// or as a value in the PgBouncer configuration file
+func isValidPgBouncerName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " \t\r\n\"'=;#[]")
+}
+
 func (r *Pooler) validateCluster() field.ErrorList {
 	var result field.ErrorList
 	if r.Spec.Cluster.Name == "" {
@@ -187,11 +272,36 @@ func (r *Pooler) validateCluster() field.ErrorList {
 	return result
 }
 
+func (r *Pooler) validateAutoscaling() field.ErrorList {
+	var result field.ErrorList
+	autoscaling := r.Spec.Autoscaling
+	if autoscaling == nil {
+		return result
+	}
+
+	path := field.NewPath("spec", "autoscaling")
+	if autoscaling.GetMinInstances() > autoscaling.MaxInstances {
+		result = append(result,
+			field.Invalid(
+				path.Child("minInstances"),
+				autoscaling.GetMinInstances(),
+				"minInstances cannot be greater than maxInstances"))
+	}
+	if autoscaling.TargetClientConnections == nil && autoscaling.TargetCPUUtilizationPercentage == nil {
+		result = append(result,
+			field.Required(
+				path,
+				"at least one of targetClientConnections and targetCPUUtilizationPercentage is required"))
+	}
+	return result
+}
+
 // Validate validates the configuration of a Pooler, returning
 // a list of errors
 func (r *Pooler) Validate() (allErrs field.ErrorList) {
 	allErrs = append(allErrs, r.validatePgBouncer()...)
 	allErrs = append(allErrs, r.validateCluster()...)
+	allErrs = append(allErrs, r.validateAutoscaling()...)
 	return allErrs
 }
 
@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -65,6 +66,57 @@ var _ = Describe("Pooler validation", func() {
 		Expect(pooler.validatePgBouncer()).To(BeEmpty())
 	})
 
+	It("allows omitting the authQuery when the operator provisions it", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					AuthQuerySecret: &LocalObjectReference{
+						Name: "test",
+					},
+					AuthQueryProvisioning: &PgBouncerAuthQueryProvisioning{
+						FunctionSchema: "pgbouncer",
+					},
+				},
+			},
+		}
+
+		Expect(pooler.validatePgBouncer()).To(BeEmpty())
+		Expect(pooler.IsAuthQueryProvisioned()).To(BeTrue())
+		Expect(pooler.GetAuthQuery()).To(Equal("SELECT usename, passwd FROM pgbouncer.user_search($1)"))
+	})
+
+	It("doesn't allow provisioning the auth query function in a reserved or shared schema", func() {
+		for _, schemaName := range []string{"public", "pg_catalog", "information_schema"} {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						AuthQuerySecret: &LocalObjectReference{
+							Name: "test",
+						},
+						AuthQueryProvisioning: &PgBouncerAuthQueryProvisioning{
+							FunctionSchema: schemaName,
+						},
+					},
+				},
+			}
+
+			Expect(pooler.validatePgBouncer()).To(HaveLen(1), schemaName)
+		}
+	})
+
+	It("doesn't allow provisioning the authQuery without any authQuerySecret", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					AuthQueryProvisioning: &PgBouncerAuthQueryProvisioning{},
+				},
+			},
+		}
+
+		Expect(pooler.validatePgBouncer()).NotTo(BeEmpty())
+		Expect(pooler.IsAuthQueryProvisioned()).To(BeFalse())
+	})
+
 	It("allows the autoconfiguration mode", func() {
 		pooler := Pooler{
 			Spec: PoolerSpec{
@@ -128,4 +180,88 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("does not complain about a valid autoscaling configuration", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Autoscaling: &PoolerAutoscaling{
+					MinInstances:            ptr.To(int32(2)),
+					MaxInstances:            5,
+					TargetClientConnections: ptr.To(int32(100)),
+				},
+			},
+		}
+		Expect(pooler.validateAutoscaling()).To(BeEmpty())
+	})
+
+	It("complains when minInstances is greater than maxInstances", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Autoscaling: &PoolerAutoscaling{
+					MinInstances:                   ptr.To(int32(3)),
+					MaxInstances:                   2,
+					TargetCPUUtilizationPercentage: ptr.To(int32(80)),
+				},
+			},
+		}
+		Expect(pooler.validateAutoscaling()).To(HaveLen(1))
+	})
+
+	It("complains when the autoscaling configuration has no target", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Autoscaling: &PoolerAutoscaling{
+					MaxInstances: 2,
+				},
+			},
+		}
+		Expect(pooler.validateAutoscaling()).To(HaveLen(1))
+	})
+
+	It("does not complain about valid databases and users", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Databases: []PgBouncerDatabase{
+						{Name: "app"},
+						{Name: "reporting", DBName: "app", PoolMode: PgBouncerPoolModeTransaction},
+					},
+					Users: []PgBouncerUser{
+						{Name: "batch", MaxUserConnections: ptr.To(int32(10))},
+					},
+				},
+			},
+		}
+		Expect(pooler.validatePgBouncerDatabases()).To(BeEmpty())
+		Expect(pooler.validatePgBouncerUsers()).To(BeEmpty())
+	})
+
+	It("complains about reserved or invalid database names", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Databases: []PgBouncerDatabase{
+						{Name: "*"},
+						{Name: PgBouncerAdminDatabase},
+						{Name: "app = host=evil"},
+						{Name: "app", DBName: "app;"},
+					},
+				},
+			},
+		}
+		result := pooler.validatePgBouncerDatabases()
+		Expect(result).To(HaveLen(4))
+		Expect(result[3].Field).To(Equal("spec.pgbouncer.databases[3].dbname"))
+	})
+
+	It("complains about invalid user names", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Users: []PgBouncerUser{{Name: "user #1"}},
+				},
+			},
+		}
+		Expect(pooler.validatePgBouncerUsers()).To(HaveLen(1))
+	})
 })
@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReplicationGrantSpec defines the desired state of ReplicationGrant
+type ReplicationGrantSpec struct {
+	// The names of the secrets, in the namespace of the grant, that
+	// the granted clusters are allowed to use in their external clusters
+	// +kubebuilder:validation:MinItems=1
+	Secrets []string `json:"secrets"`
+
+	// The clusters allowed to use the secrets
+	// +kubebuilder:validation:MinItems=1
+	Grantees []ReplicationGrantee `json:"grantees"`
+}
+
+// ReplicationGrantee identifies the clusters a ReplicationGrant applies to
+type ReplicationGrantee struct {
+	// The namespace of the granted clusters
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// The name of the granted cluster. When empty, every cluster
+	// in the namespace is granted
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Secrets",type="string",JSONPath=".spec.secrets"
+
+// ReplicationGrant allows clusters living in other namespaces to use
+// a set of secrets of its namespace to connect to an external cluster
+type ReplicationGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired ReplicationGrant.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ReplicationGrantSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReplicationGrantList contains a list of ReplicationGrant
+type ReplicationGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of replication grants
+	Items []ReplicationGrant `json:"items"`
+}
+
+// Allows checks whether the grant lets the passed cluster use the
+// secret with the given name
+func (grant *ReplicationGrant) Allows(cluster *Cluster, secretName string) bool {
+	if !slices.Contains(grant.Spec.Secrets, secretName) {
+		return false
+	}
+
+	return slices.ContainsFunc(grant.Spec.Grantees, func(grantee ReplicationGrantee) bool {
+		return grantee.Namespace == cluster.Namespace &&
+			(grantee.Cluster == "" || grantee.Cluster == cluster.Name)
+	})
+}
+
+func init() {
+	SchemeBuilder.Register(&ReplicationGrant{}, &ReplicationGrantList{})
+}
@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReplicationGrant type tests", func() {
+	grant := ReplicationGrant{
+		Spec: ReplicationGrantSpec{
+			Secrets: []string{"cluster-example-replication", "cluster-example-ca"},
+			Grantees: []ReplicationGrantee{
+				{Namespace: "dr"},
+				{Namespace: "staging", Cluster: "cluster-staging"},
+			},
+		},
+	}
+
+	newCluster := func(namespace, name string) *Cluster {
+		return &Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	It("allows every cluster of a namespace granted without a cluster name", func() {
+		Expect(grant.Allows(newCluster("dr", "cluster-dr"), "cluster-example-ca")).To(BeTrue())
+		Expect(grant.Allows(newCluster("dr", "another"), "cluster-example-replication")).To(BeTrue())
+	})
+
+	It("allows only the named cluster when the grantee specifies one", func() {
+		Expect(grant.Allows(newCluster("staging", "cluster-staging"), "cluster-example-ca")).To(BeTrue())
+		Expect(grant.Allows(newCluster("staging", "another"), "cluster-example-ca")).To(BeFalse())
+	})
+
+	It("refuses secrets that are not listed", func() {
+		Expect(grant.Allows(newCluster("dr", "cluster-dr"), "cluster-example-superuser")).To(BeFalse())
+	})
+
+	It("refuses namespaces that are not granted", func() {
+		Expect(grant.Allows(newCluster("other", "cluster-dr"), "cluster-example-ca")).To(BeFalse())
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestorePhase is the phase of an in-place restore
+type RestorePhase string
+
+const (
+	// RestorePhaseRunning means that the instances of the cluster are
+	// being stopped, or that the backup is being restored
+	RestorePhaseRunning RestorePhase = "running"
+
+	// RestorePhaseCompleted means that the cluster has been restored
+	// and its replicas are being cloned again from the new primary
+	RestorePhaseCompleted RestorePhase = "completed"
+
+	// RestorePhaseFailed means that the restore could not be started,
+	// or that the restore job failed
+	RestorePhaseFailed RestorePhase = "failed"
+)
+
+// RestoreSpec defines the desired state of Restore
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="the spec of a restore is immutable"
+type RestoreSpec struct {
+	// The cluster to be restored in place
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The backup to be restored, which must be a completed backup of the
+	// cluster taken on the object store. When omitted, the most recent
+	// backup from which the recovery target can be reached is used
+	// +optional
+	Backup *LocalObjectReference `json:"backup,omitempty"`
+
+	// The point in time, or the named restore point, to recover the
+	// cluster to. By default, all the archived WAL files are replayed
+	// +optional
+	RecoveryTarget *RecoveryTarget `json:"recoveryTarget,omitempty"`
+}
+
+// RestoreStatus defines the observed state of Restore
+type RestoreStatus struct {
+	// The current phase of the restore
+	// +optional
+	Phase RestorePhase `json:"phase,omitempty"`
+
+	// The name of the backup being restored
+	// +optional
+	Backup string `json:"backup,omitempty"`
+
+	// A human-readable description of the outcome of the restore
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// When the restore was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the restore was completed, or failed
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".status.backup"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message"
+
+// Restore requests an existing cluster to be restored in place from one
+// of its backups, replacing the data of all its instances
+type Restore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Restore.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec RestoreSpec `json:"spec"`
+	// Most recently observed status of the Restore. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status RestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RestoreList contains a list of Restore
+type RestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of restores
+	Items []Restore `json:"items"`
+}
+
+// IsDone checks whether the restore reached a final phase
+func (restore *Restore) IsDone() bool {
+	return restore.Status.Phase == RestorePhaseCompleted ||
+		restore.Status.Phase == RestorePhaseFailed
+}
+
+func init() {
+	SchemeBuilder.Register(&Restore{}, &RestoreList{})
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -81,8 +83,62 @@ type ScheduledBackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// The name of the object store, among the ones defined in
+	// `cluster.spec.backup.additionalBarmanObjectStores`, where the
+	// backups are taken. If empty, it defaults to the one defined in
+	// `cluster.spec.backup.barmanObjectStore`
+	// +optional
+	ObjectStoreName string `json:"objectStoreName,omitempty"`
+
+	// When set, the backups created by this ScheduledBackup are verified
+	// once completed, enabling scheduled restore testing
+	// +optional
+	Verify *BackupVerificationConfiguration `json:"verify,omitempty"`
+
+	// What happens to the base backups in the object store when the Backups
+	// created by this ScheduledBackup are deleted. See the `deletionPolicy`
+	// field of the Backup for details
+	// +kubebuilder:validation:Enum=retain;delete
+	// +optional
+	DeletionPolicy BackupDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Specifies how to treat a scheduled run while a backup created by this
+	// ScheduledBackup is still running. Available options are `Allow`, to
+	// create the new backup anyway, `Forbid`, to skip the scheduled run, and
+	// `Replace`, to delete the running backups and create the new one.
+	// When empty, the scheduled run is delayed until the running backups
+	// are completed
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +optional
+	ConcurrencyPolicy ScheduledBackupConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// A time window after each scheduled time in which the backup is
+	// started, at a random point which is stable for each scheduled run.
+	// Used to spread the load of many ScheduledBackups sharing the
+	// same schedule
+	// +optional
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
 }
 
+// ScheduledBackupConcurrencyPolicy describes how to treat a scheduled run
+// while a previous backup is still running
+type ScheduledBackupConcurrencyPolicy string
+
+const (
+	// ScheduledBackupConcurrencyPolicyAllow creates the new backup even if
+	// the previous ones are still running
+	ScheduledBackupConcurrencyPolicyAllow ScheduledBackupConcurrencyPolicy = "Allow"
+
+	// ScheduledBackupConcurrencyPolicyForbid skips the scheduled run if
+	// a previous backup is still running
+	ScheduledBackupConcurrencyPolicyForbid ScheduledBackupConcurrencyPolicy = "Forbid"
+
+	// ScheduledBackupConcurrencyPolicyReplace deletes the running backups
+	// and creates the new one
+	ScheduledBackupConcurrencyPolicyReplace ScheduledBackupConcurrencyPolicy = "Replace"
+)
+
 // ScheduledBackupStatus defines the observed state of ScheduledBackup
 type ScheduledBackupStatus struct {
 	// The latest time the schedule
@@ -166,6 +222,14 @@ func (scheduledBackup *ScheduledBackup) GetSchedule() string {
 	return scheduledBackup.Spec.Schedule
 }
 
+// GetJitter gets the time window in which every scheduled backup is started
+func (scheduledBackup *ScheduledBackup) GetJitter() time.Duration {
+	if scheduledBackup.Spec.Jitter == nil || scheduledBackup.Spec.Jitter.Duration < 0 {
+		return 0
+	}
+	return scheduledBackup.Spec.Jitter.Duration
+}
+
 // GetStatus gets the status that the caller may update
 func (scheduledBackup *ScheduledBackup) GetStatus() *ScheduledBackupStatus {
 	return &scheduledBackup.Status
@@ -185,6 +249,9 @@ func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 			Online:              scheduledBackup.Spec.Online,
 			OnlineConfiguration: scheduledBackup.Spec.OnlineConfiguration,
 			PluginConfiguration: scheduledBackup.Spec.PluginConfiguration,
+			ObjectStoreName:     scheduledBackup.Spec.ObjectStoreName,
+			Verify:              scheduledBackup.Spec.Verify,
+			DeletionPolicy:      scheduledBackup.Spec.DeletionPolicy,
 		},
 	}
 	utils.InheritAnnotations(&backup.ObjectMeta, scheduledBackup.Annotations, nil, configuration.Current)
@@ -65,6 +65,13 @@ var _ = Describe("Scheduled backup", func() {
 		Expect(backup.Spec.Target).To(BeEquivalentTo(BackupTargetPrimary))
 	})
 
+	It("properly creates a backup on an additional object store", func() {
+		scheduledBackup.Spec.ObjectStoreName = "dr"
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup).ToNot(BeNil())
+		Expect(backup.Spec.ObjectStoreName).To(Equal("dr"))
+	})
+
 	It("complains if online is set on a barman backup", func() {
 		scheduledBackup := &ScheduledBackup{
 			Spec: ScheduledBackupSpec{
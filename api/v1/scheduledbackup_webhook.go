@@ -118,5 +118,37 @@ func (r *ScheduledBackup) validate() field.ErrorList {
 		))
 	}
 
+	if r.Spec.ObjectStoreName != "" && r.Spec.Method != BackupMethodBarmanObjectStore {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "objectStoreName"),
+			r.Spec.ObjectStoreName,
+			"ObjectStoreName parameter can be specified only if the method is barmanObjectStore",
+		))
+	}
+
+	if r.Spec.Verify != nil && r.Spec.Method != BackupMethodBarmanObjectStore {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "verify"),
+			r.Spec.Method,
+			"Verify parameter can be specified only if the method is barmanObjectStore",
+		))
+	}
+
+	if r.Spec.DeletionPolicy == BackupDeletionPolicyDelete && r.Spec.Method != BackupMethodBarmanObjectStore {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "deletionPolicy"),
+			r.Spec.DeletionPolicy,
+			"DeletionPolicy delete can be specified only if the method is barmanObjectStore",
+		))
+	}
+
+	if r.Spec.Jitter != nil && r.Spec.Jitter.Duration < 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "jitter"),
+			r.Spec.Jitter.Duration.String(),
+			"jitter must not be negative",
+		))
+	}
+
 	return result
 }
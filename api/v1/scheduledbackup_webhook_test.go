@@ -17,6 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -70,4 +74,53 @@ var _ = Describe("Validate schedule", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.method"))
 	})
+	It("doesn't complain with a positive jitter", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule: "0 0 0 * * *",
+				Jitter:   &metav1.Duration{Duration: 10 * time.Minute},
+			},
+		}
+		Expect(schedule.validate()).To(BeEmpty())
+	})
+
+	It("complains with a negative jitter", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule: "0 0 0 * * *",
+				Jitter:   &metav1.Duration{Duration: -time.Minute},
+			},
+		}
+		result := schedule.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.jitter"))
+	})
+
+	It("complains if objectStoreName is set on a volume snapshot backup", func() {
+		utils.SetVolumeSnapshot(true)
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule:        "0 0 0 * * *",
+				Method:          BackupMethodVolumeSnapshot,
+				ObjectStoreName: "dr",
+			},
+		}
+		result := schedule.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.objectStoreName"))
+	})
+
+	It("complains if the deletion policy is delete on a volume snapshot backup", func() {
+		utils.SetVolumeSnapshot(true)
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule:       "0 0 0 * * *",
+				Method:         BackupMethodVolumeSnapshot,
+				DeletionPolicy: BackupDeletionPolicyDelete,
+			},
+		}
+		result := schedule.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.deletionPolicy"))
+	})
 })
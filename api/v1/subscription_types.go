@@ -0,0 +1,165 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// SubscriptionReclaimPolicy describes a policy for end-of-life maintenance of Subscriptions.
+// +enum
+type SubscriptionReclaimPolicy string
+
+const (
+	// SubscriptionReclaimDelete means the subscription will be deleted from PostgreSQL
+	// when the Subscription object is removed
+	SubscriptionReclaimDelete SubscriptionReclaimPolicy = "delete"
+
+	// SubscriptionReclaimRetain means the subscription will be left in PostgreSQL
+	// when the Subscription object is removed
+	SubscriptionReclaimRetain SubscriptionReclaimPolicy = "retain"
+)
+
+// SubscriptionFinalizerName is the name of the finalizer used by the instance
+// manager to drop the subscription when the Subscription object is removed
+const SubscriptionFinalizerName = utils.MetadataNamespace + "/deleteSubscription"
+
+// SubscriptionSpec defines the desired state of Subscription
+type SubscriptionSpec struct {
+	// The corresponding cluster
+	ClusterRef LocalObjectReference `json:"cluster"`
+
+	// The name of the subscription inside PostgreSQL
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name"`
+
+	// The name of the database where the publication will be installed in
+	// the "subscriber" cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="dbname is immutable"
+	DBName string `json:"dbname"`
+
+	// Subscription parameters part of the `WITH` clause as expected by
+	// PostgreSQL `CREATE SUBSCRIPTION` command. They are only used
+	// when the subscription is created
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The name of the publication inside the PostgreSQL database in the
+	// "publisher"
+	PublicationName string `json:"publicationName"`
+
+	// The name of the database containing the publication on the external
+	// cluster. Defaults to the one in the external cluster definition.
+	// +optional
+	PublicationDBName string `json:"publicationDBName,omitempty"`
+
+	// The name of the external cluster with the publication ("publisher")
+	ExternalClusterName string `json:"externalClusterName"`
+
+	// The policy for end-of-life maintenance of this subscription
+	// +kubebuilder:validation:Enum=delete;retain
+	// +kubebuilder:default:=retain
+	// +optional
+	ReclaimPolicy SubscriptionReclaimPolicy `json:"subscriptionReclaimPolicy,omitempty"`
+}
+
+// SubscriptionStatus defines the observed state of Subscription
+type SubscriptionStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Applied is true if the subscription was reconciled correctly
+	// +optional
+	Applied *bool `json:"applied,omitempty"`
+
+	// Message is the reconciliation output message
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The last write-ahead log location received by the subscription
+	// +optional
+	ReceivedLSN string `json:"receivedLSN,omitempty"`
+
+	// The last write-ahead log location reported to the publisher
+	// +optional
+	LatestEndLSN string `json:"latestEndLSN,omitempty"`
+
+	// The time of the last write-ahead log location reported to the publisher
+	// +optional
+	LatestEndTime string `json:"latestEndTime,omitempty"`
+
+	// The amount of WAL, in bytes, which has been generated on the publisher
+	// and not yet confirmed by the replication slot of the subscription.
+	// It is not reported if the publisher cannot be reached
+	// +optional
+	SlotLagBytes *int64 `json:"slotLagBytes,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Applied",type="boolean",JSONPath=".status.applied"
+// +kubebuilder:printcolumn:name="Lag",type="integer",JSONPath=".status.slotLagBytes",description="Replication slot lag in bytes"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",description="Latest reconciliation message"
+
+// Subscription is the Schema for the subscriptions API
+type Subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Subscription.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec SubscriptionSpec `json:"spec"`
+	// Most recently observed status of the Subscription. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status SubscriptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SubscriptionList contains a list of Subscription
+type SubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of subscriptions
+	Items []Subscription `json:"items"`
+}
+
+// GetReclaimPolicy returns the reclaim policy of the subscription,
+// defaulting to "retain"
+func (sub *Subscription) GetReclaimPolicy() SubscriptionReclaimPolicy {
+	if sub.Spec.ReclaimPolicy == "" {
+		return SubscriptionReclaimRetain
+	}
+	return sub.Spec.ReclaimPolicy
+}
+
+func init() {
+	SchemeBuilder.Register(&Subscription{}, &SubscriptionList{})
+}
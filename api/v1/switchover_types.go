@@ -0,0 +1,142 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwitchoverPhase is the phase of a switchover
+type SwitchoverPhase string
+
+const (
+	// SwitchoverPhasePending means that the operator is waiting for the
+	// cluster to be healthy and for the preconditions to be satisfied
+	SwitchoverPhasePending SwitchoverPhase = "pending"
+
+	// SwitchoverPhaseRunning means that the target instance is being promoted
+	SwitchoverPhaseRunning SwitchoverPhase = "running"
+
+	// SwitchoverPhaseCompleted means that the target instance is the
+	// primary of the cluster
+	SwitchoverPhaseCompleted SwitchoverPhase = "completed"
+
+	// SwitchoverPhaseFailed means that the switchover could not be started
+	// within the timeout, or that another instance has been promoted instead
+	SwitchoverPhaseFailed SwitchoverPhase = "failed"
+)
+
+// SwitchoverSpec defines the desired state of Switchover
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="the spec of a switchover is immutable"
+type SwitchoverSpec struct {
+	// The cluster where the switchover is requested
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The name of the instance to be promoted
+	// +kubebuilder:validation:MinLength=1
+	TargetPrimary string `json:"targetPrimary"`
+
+	// The maximum amount of WAL, compared to the current position of the
+	// primary, the target instance may have still to replay for the
+	// switchover to start, like `16Mi`. By default, the lag is not checked
+	// +optional
+	MaxLag *resource.Quantity `json:"maxLag,omitempty"`
+
+	// When true, the switchover starts only while the target instance is
+	// a synchronous standby of the primary
+	// +optional
+	RequireSynchronous bool `json:"requireSynchronous,omitempty"`
+
+	// How long to wait for the cluster to be healthy and for the
+	// preconditions to be satisfied before failing the switchover.
+	// By default, the operator waits until the Switchover is deleted
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// SwitchoverStatus defines the observed state of Switchover
+type SwitchoverStatus struct {
+	// The current phase of the switchover
+	// +optional
+	Phase SwitchoverPhase `json:"phase,omitempty"`
+
+	// A human-readable description of the state of the switchover,
+	// including the precondition that is not satisfied yet
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The primary instance when the switchover was started
+	// +optional
+	PreviousPrimary string `json:"previousPrimary,omitempty"`
+
+	// When the target instance was requested to be promoted
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the switchover was completed, or failed
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetPrimary"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message"
+
+// Switchover requests an instance of a cluster to be promoted to primary,
+// once the preconditions on its replication state are satisfied
+type Switchover struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Switchover.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec SwitchoverSpec `json:"spec"`
+	// Most recently observed status of the Switchover. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status SwitchoverStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SwitchoverList contains a list of Switchover
+type SwitchoverList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of switchovers
+	Items []Switchover `json:"items"`
+}
+
+// IsDone checks whether the switchover reached a final phase
+func (switchover *Switchover) IsDone() bool {
+	return switchover.Status.Phase == SwitchoverPhaseCompleted ||
+		switchover.Status.Phase == SwitchoverPhaseFailed
+}
+
+func init() {
+	SchemeBuilder.Register(&Switchover{}, &SwitchoverList{})
+}
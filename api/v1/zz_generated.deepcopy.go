@@ -24,6 +24,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -78,6 +79,36 @@ func (in *AffinityConfiguration) DeepCopy() *AffinityConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppArmorProfile) DeepCopyInto(out *AppArmorProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppArmorProfile.
+func (in *AppArmorProfile) DeepCopy() *AppArmorProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AppArmorProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchitectureImage) DeepCopyInto(out *ArchitectureImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchitectureImage.
+func (in *ArchitectureImage) DeepCopy() *ArchitectureImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchitectureImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AvailableArchitecture) DeepCopyInto(out *AvailableArchitecture) {
 	*out = *in
@@ -155,6 +186,29 @@ func (in *Backup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupCatalogEntry) DeepCopyInto(out *BackupCatalogEntry) {
+	*out = *in
+	if in.BeginTime != nil {
+		in, out := &in.BeginTime, &out.BeginTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupCatalogEntry.
+func (in *BackupCatalogEntry) DeepCopy() *BackupCatalogEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupCatalogEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 	*out = *in
@@ -168,6 +222,18 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalBarmanObjectStores != nil {
+		in, out := &in.AdditionalBarmanObjectStores, &out.AdditionalBarmanObjectStores
+		*out = make([]NamedBarmanObjectStoreConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(BackupHooks)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -180,6 +246,75 @@ func (in *BackupConfiguration) DeepCopy() *BackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHook) DeepCopyInto(out *BackupHook) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(BackupHookHTTPAction)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHook.
+func (in *BackupHook) DeepCopy() *BackupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHookHTTPAction) DeepCopyInto(out *BackupHookHTTPAction) {
+	*out = *in
+	if in.HeadersSecret != nil {
+		in, out := &in.HeadersSecret, &out.HeadersSecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHookHTTPAction.
+func (in *BackupHookHTTPAction) DeepCopy() *BackupHookHTTPAction {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHookHTTPAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHooks) DeepCopyInto(out *BackupHooks) {
+	*out = *in
+	if in.Pre != nil {
+		in, out := &in.Pre, &out.Pre
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Post != nil {
+		in, out := &in.Post, &out.Post
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHooks.
+func (in *BackupHooks) DeepCopy() *BackupHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupList) DeepCopyInto(out *BackupList) {
 	*out = *in
@@ -234,6 +369,29 @@ func (in *BackupPluginConfiguration) DeepCopy() *BackupPluginConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupProgress) DeepCopyInto(out *BackupProgress) {
+	*out = *in
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupProgress.
+func (in *BackupProgress) DeepCopy() *BackupProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSnapshotElementStatus) DeepCopyInto(out *BackupSnapshotElementStatus) {
 	*out = *in
@@ -309,6 +467,11 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 		*out = new(OnlineConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(BackupVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSpec.
@@ -330,6 +493,11 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(SecretKeySelector)
 		**out = **in
 	}
+	if in.WALClientSideEncryption != nil {
+		in, out := &in.WALClientSideEncryption, &out.WALClientSideEncryption
+		*out = new(WALClientSideEncryption)
+		**out = **in
+	}
 	if in.StartedAt != nil {
 		in, out := &in.StartedAt, &out.StartedAt
 		*out = (*in).DeepCopy()
@@ -338,6 +506,16 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		in, out := &in.StoppedAt, &out.StoppedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(BackupProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.BackupLabelFile != nil {
 		in, out := &in.BackupLabelFile, &out.BackupLabelFile
 		*out = make([]byte, len(*in))
@@ -371,6 +549,54 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationConfiguration) DeepCopyInto(out *BackupVerificationConfiguration) {
+	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationConfiguration.
+func (in *BackupVerificationConfiguration) DeepCopy() *BackupVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationStatus) DeepCopyInto(out *BackupVerificationStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationStatus.
+func (in *BackupVerificationStatus) DeepCopy() *BackupVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BarmanCredentials) DeepCopyInto(out *BarmanCredentials) {
 	*out = *in
@@ -413,7 +639,7 @@ func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreCon
 	if in.Wal != nil {
 		in, out := &in.Wal, &out.Wal
 		*out = new(WalBackupConfiguration)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
@@ -446,6 +672,131 @@ func (in *BarmanObjectStoreConfiguration) DeepCopy() *BarmanObjectStoreConfigura
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Benchmark) DeepCopyInto(out *Benchmark) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Benchmark.
+func (in *Benchmark) DeepCopy() *Benchmark {
+	if in == nil {
+		return nil
+	}
+	out := new(Benchmark)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Benchmark) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkList) DeepCopyInto(out *BenchmarkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Benchmark, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkList.
+func (in *BenchmarkList) DeepCopy() *BenchmarkList {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BenchmarkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSpec) DeepCopyInto(out *BenchmarkSpec) {
+	*out = *in
+	if in.Pgbench != nil {
+		in, out := &in.Pgbench, &out.Pgbench
+		*out = new(PgbenchConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fio != nil {
+		in, out := &in.Fio, &out.Fio
+		*out = new(FioConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSpec.
+func (in *BenchmarkSpec) DeepCopy() *BenchmarkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkStatus) DeepCopyInto(out *BenchmarkStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Pgbench != nil {
+		in, out := &in.Pgbench, &out.Pgbench
+		*out = new(PgbenchResults)
+		**out = **in
+	}
+	if in.Fio != nil {
+		in, out := &in.Fio, &out.Fio
+		*out = new(FioResults)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkStatus.
+func (in *BenchmarkStatus) DeepCopy() *BenchmarkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 	*out = *in
@@ -564,6 +915,11 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(DataSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VolumeSource != nil {
+		in, out := &in.VolumeSource, &out.VolumeSource
+		*out = new(VolumeSourceRecovery)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RecoveryTarget != nil {
 		in, out := &in.RecoveryTarget, &out.RecoveryTarget
 		*out = new(RecoveryTarget)
@@ -589,6 +945,11 @@ func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogImage) DeepCopyInto(out *CatalogImage) {
 	*out = *in
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]ArchitectureImage, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogImage.
@@ -602,21 +963,77 @@ func (in *CatalogImage) DeepCopy() *CatalogImage {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
+func (in *CertManagerConfiguration) DeepCopyInto(out *CertManagerConfiguration) {
 	*out = *in
-	if in.ServerAltDNSNames != nil {
-		in, out := &in.ServerAltDNSNames, &out.ServerAltDNSNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.IssuerRef = in.IssuerRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesConfiguration.
-func (in *CertificatesConfiguration) DeepCopy() *CertificatesConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerConfiguration.
+func (in *CertManagerConfiguration) DeepCopy() *CertManagerConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(CertificatesConfiguration)
+	out := new(CertManagerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
+	*out = *in
+	if in.ServerAltDNSNames != nil {
+		in, out := &in.ServerAltDNSNames, &out.ServerAltDNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(CertificatesRotationConfiguration)
+		**out = **in
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesConfiguration.
+func (in *CertificatesConfiguration) DeepCopy() *CertificatesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatesRotationConfiguration) DeepCopyInto(out *CertificatesRotationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesRotationConfiguration.
+func (in *CertificatesRotationConfiguration) DeepCopy() *CertificatesRotationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatesRotationConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -644,6 +1061,215 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCapture) DeepCopyInto(out *ChangeDataCapture) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCapture.
+func (in *ChangeDataCapture) DeepCopy() *ChangeDataCapture {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCapture)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChangeDataCapture) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCaptureKafkaSink) DeepCopyInto(out *ChangeDataCaptureKafkaSink) {
+	*out = *in
+	if in.BootstrapServers != nil {
+		in, out := &in.BootstrapServers, &out.BootstrapServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCaptureKafkaSink.
+func (in *ChangeDataCaptureKafkaSink) DeepCopy() *ChangeDataCaptureKafkaSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCaptureKafkaSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCaptureList) DeepCopyInto(out *ChangeDataCaptureList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChangeDataCapture, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCaptureList.
+func (in *ChangeDataCaptureList) DeepCopy() *ChangeDataCaptureList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCaptureList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChangeDataCaptureList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCaptureNATSSink) DeepCopyInto(out *ChangeDataCaptureNATSSink) {
+	*out = *in
+	if in.OffsetStorage != nil {
+		in, out := &in.OffsetStorage, &out.OffsetStorage
+		*out = new(ChangeDataCaptureOffsetStorage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCaptureNATSSink.
+func (in *ChangeDataCaptureNATSSink) DeepCopy() *ChangeDataCaptureNATSSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCaptureNATSSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCaptureOffsetStorage) DeepCopyInto(out *ChangeDataCaptureOffsetStorage) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCaptureOffsetStorage.
+func (in *ChangeDataCaptureOffsetStorage) DeepCopy() *ChangeDataCaptureOffsetStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCaptureOffsetStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCaptureSink) DeepCopyInto(out *ChangeDataCaptureSink) {
+	*out = *in
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(ChangeDataCaptureKafkaSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NATS != nil {
+		in, out := &in.NATS, &out.NATS
+		*out = new(ChangeDataCaptureNATSSink)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCaptureSink.
+func (in *ChangeDataCaptureSink) DeepCopy() *ChangeDataCaptureSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCaptureSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCaptureSpec) DeepCopyInto(out *ChangeDataCaptureSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	out.Credentials = in.Credentials
+	in.Sink.DeepCopyInto(&out.Sink)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCaptureSpec.
+func (in *ChangeDataCaptureSpec) DeepCopy() *ChangeDataCaptureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCaptureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeDataCaptureStatus) DeepCopyInto(out *ChangeDataCaptureStatus) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SlotRecreatedAt != nil {
+		in, out := &in.SlotRecreatedAt, &out.SlotRecreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeDataCaptureStatus.
+func (in *ChangeDataCaptureStatus) DeepCopy() *ChangeDataCaptureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeDataCaptureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -774,12 +1400,47 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ImageCatalogRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(UpdatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicaClone != nil {
+		in, out := &in.ReplicaClone, &out.ReplicaClone
+		*out = new(ReplicaCloneConfiguration)
+		**out = **in
+	}
+	if in.ReplicaAutoReclone != nil {
+		in, out := &in.ReplicaAutoReclone, &out.ReplicaAutoReclone
+		*out = new(ReplicaAutoRecloneConfiguration)
+		**out = **in
+	}
+	if in.StartupCheck != nil {
+		in, out := &in.StartupCheck, &out.StartupCheck
+		*out = new(StartupCheckConfiguration)
+		**out = **in
+	}
+	if in.PostmasterRestart != nil {
+		in, out := &in.PostmasterRestart, &out.PostmasterRestart
+		*out = new(PostmasterRestartConfiguration)
+		**out = **in
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = new(LifecycleHooksConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiagnosticQueries != nil {
+		in, out := &in.DiagnosticQueries, &out.DiagnosticQueries
+		*out = new(DiagnosticQueriesConfiguration)
+		**out = **in
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -800,6 +1461,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SecretsStore != nil {
+		in, out := &in.SecretsStore, &out.SecretsStore
+		*out = new(SecretsStoreConfiguration)
+		**out = **in
+	}
 	if in.Certificates != nil {
 		in, out := &in.Certificates, &out.Certificates
 		*out = new(CertificatesConfiguration)
@@ -826,6 +1492,21 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(corev1.EphemeralVolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FailoverDetection != nil {
+		in, out := &in.FailoverDetection, &out.FailoverDetection
+		*out = new(FailoverDetectionConfiguration)
+		**out = **in
+	}
+	if in.FailoverArbiter != nil {
+		in, out := &in.FailoverArbiter, &out.FailoverArbiter
+		*out = new(FailoverArbiterConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
@@ -850,6 +1531,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(NodeMaintenanceWindow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.DatabaseMaintenance != nil {
+		in, out := &in.DatabaseMaintenance, &out.DatabaseMaintenance
+		*out = new(DatabaseMaintenanceConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringConfiguration)
@@ -862,6 +1553,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LogFiles != nil {
+		in, out := &in.LogFiles, &out.LogFiles
+		*out = new(LogFilesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ProjectedVolumeTemplate != nil {
 		in, out := &in.ProjectedVolumeTemplate, &out.ProjectedVolumeTemplate
 		*out = new(corev1.ProjectedVolumeSource)
@@ -891,6 +1587,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(corev1.SeccompProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AppArmorProfile != nil {
+		in, out := &in.AppArmorProfile, &out.AppArmorProfile
+		*out = new(AppArmorProfile)
+		**out = **in
+	}
 	if in.Tablespaces != nil {
 		in, out := &in.Tablespaces, &out.Tablespaces
 		*out = make([]TablespaceConfiguration, len(*in))
@@ -910,6 +1611,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollectionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -945,7 +1651,7 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		in, out := &in.InstancesReportedState, &out.InstancesReportedState
 		*out = make(map[PodName]InstanceReportedState, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	in.ManagedRolesStatus.DeepCopyInto(&out.ManagedRolesStatus)
@@ -954,6 +1660,21 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]TablespaceState, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExtensionsStatus != nil {
+		in, out := &in.ExtensionsStatus, &out.ExtensionsStatus
+		*out = make([]ExtensionState, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingRestartParameters != nil {
+		in, out := &in.PendingRestartParameters, &out.PendingRestartParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RolesWithMD5Password != nil {
+		in, out := &in.RolesWithMD5Password, &out.RolesWithMD5Password
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
 		in, out := &in.DanglingPVC, &out.DanglingPVC
@@ -997,6 +1718,33 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.LastRetentionPolicyEnforcement != nil {
+		in, out := &in.LastRetentionPolicyEnforcement, &out.LastRetentionPolicyEnforcement
+		*out = new(RetentionPolicyEnforcementStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastReplicaReclone != nil {
+		in, out := &in.LastReplicaReclone, &out.LastReplicaReclone
+		*out = new(ReplicaRecloneStatus)
+		**out = **in
+	}
+	if in.BackupCatalog != nil {
+		in, out := &in.BackupCatalog, &out.BackupCatalog
+		*out = make([]BackupCatalogEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaintenanceHistory != nil {
+		in, out := &in.MaintenanceHistory, &out.MaintenanceHistory
+		*out = make([]MaintenanceTaskRun, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReplicaSurge != nil {
+		in, out := &in.ReplicaSurge, &out.ReplicaSurge
+		*out = new(ReplicaSurgeStatus)
+		**out = **in
+	}
 	if in.PoolerIntegrations != nil {
 		in, out := &in.PoolerIntegrations, &out.PoolerIntegrations
 		*out = new(PoolerIntegrations)
@@ -1019,6 +1767,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PGDataImageInfo != nil {
+		in, out := &in.PGDataImageInfo, &out.PGDataImageInfo
+		*out = new(ImageInfo)
+		**out = **in
+	}
 	if in.PluginStatus != nil {
 		in, out := &in.PluginStatus, &out.PluginStatus
 		*out = make([]PluginStatus, len(*in))
@@ -1027,7 +1780,12 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	out.SwitchReplicaClusterStatus = in.SwitchReplicaClusterStatus
-}
+	if in.LogicalImport != nil {
+		in, out := &in.LogicalImport, &out.LogicalImport
+		*out = new(LogicalImportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
 func (in *ClusterStatus) DeepCopy() *ClusterStatus {
@@ -1130,6 +1888,117 @@ func (in *DataSource) DeepCopy() *DataSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Database) DeepCopyInto(out *Database) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Database.
+func (in *Database) DeepCopy() *Database {
+	if in == nil {
+		return nil
+	}
+	out := new(Database)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Database) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseExtensionSpec) DeepCopyInto(out *DatabaseExtensionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseExtensionSpec.
+func (in *DatabaseExtensionSpec) DeepCopy() *DatabaseExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseExtensionStatus) DeepCopyInto(out *DatabaseExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseExtensionStatus.
+func (in *DatabaseExtensionStatus) DeepCopy() *DatabaseExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseList) DeepCopyInto(out *DatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Database, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseList.
+func (in *DatabaseList) DeepCopy() *DatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseMaintenanceConfiguration) DeepCopyInto(out *DatabaseMaintenanceConfiguration) {
+	*out = *in
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]MaintenanceTask, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseMaintenanceConfiguration.
+func (in *DatabaseMaintenanceConfiguration) DeepCopy() *DatabaseMaintenanceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseMaintenanceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseRoleRef) DeepCopyInto(out *DatabaseRoleRef) {
 	*out = *in
@@ -1145,6 +2014,82 @@ func (in *DatabaseRoleRef) DeepCopy() *DatabaseRoleRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.IsTemplate != nil {
+		in, out := &in.IsTemplate, &out.IsTemplate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowConnections != nil {
+		in, out := &in.AllowConnections, &out.AllowConnections
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]DatabaseExtensionSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
+func (in *DatabaseSpec) DeepCopy() *DatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]DatabaseExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
+func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticQueriesConfiguration) DeepCopyInto(out *DiagnosticQueriesConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticQueriesConfiguration.
+func (in *DiagnosticQueriesConfiguration) DeepCopy() *DiagnosticQueriesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticQueriesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -1199,6 +2144,41 @@ func (in *EphemeralVolumesSizeLimitConfiguration) DeepCopy() *EphemeralVolumesSi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionConfiguration) DeepCopyInto(out *ExtensionConfiguration) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionConfiguration.
+func (in *ExtensionConfiguration) DeepCopy() *ExtensionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionState) DeepCopyInto(out *ExtensionState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionState.
+func (in *ExtensionState) DeepCopy() *ExtensionState {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
@@ -1247,176 +2227,390 @@ func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
+func (in *FailoverArbiterConfiguration) DeepCopyInto(out *FailoverArbiterConfiguration) {
 	*out = *in
-	if in.ApplicationCredentials != nil {
-		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
-		*out = new(SecretKeySelector)
+	if in.Lease != nil {
+		in, out := &in.Lease, &out.Lease
+		*out = new(FailoverArbiterLease)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
-func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverArbiterConfiguration.
+func (in *FailoverArbiterConfiguration) DeepCopy() *FailoverArbiterConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleCredentials)
+	out := new(FailoverArbiterConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageCatalog) DeepCopyInto(out *ImageCatalog) {
+func (in *FailoverArbiterLease) DeepCopyInto(out *FailoverArbiterLease) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.KubeconfigSecret = in.KubeconfigSecret
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalog.
-func (in *ImageCatalog) DeepCopy() *ImageCatalog {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverArbiterLease.
+func (in *FailoverArbiterLease) DeepCopy() *FailoverArbiterLease {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageCatalog)
+	out := new(FailoverArbiterLease)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ImageCatalog) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageCatalogList) DeepCopyInto(out *ImageCatalogList) {
+func (in *FailoverDetectionConfiguration) DeepCopyInto(out *FailoverDetectionConfiguration) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ImageCatalog, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogList.
-func (in *ImageCatalogList) DeepCopy() *ImageCatalogList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverDetectionConfiguration.
+func (in *FailoverDetectionConfiguration) DeepCopy() *FailoverDetectionConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageCatalogList)
+	out := new(FailoverDetectionConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ImageCatalogList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageCatalogRef) DeepCopyInto(out *ImageCatalogRef) {
+func (in *FioConfiguration) DeepCopyInto(out *FioConfiguration) {
 	*out = *in
-	in.TypedLocalObjectReference.DeepCopyInto(&out.TypedLocalObjectReference)
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogRef.
-func (in *ImageCatalogRef) DeepCopy() *ImageCatalogRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioConfiguration.
+func (in *FioConfiguration) DeepCopy() *FioConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageCatalogRef)
+	out := new(FioConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageCatalogSpec) DeepCopyInto(out *ImageCatalogSpec) {
+func (in *FioResults) DeepCopyInto(out *FioResults) {
 	*out = *in
-	if in.Images != nil {
-		in, out := &in.Images, &out.Images
-		*out = make([]CatalogImage, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogSpec.
-func (in *ImageCatalogSpec) DeepCopy() *ImageCatalogSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FioResults.
+func (in *FioResults) DeepCopy() *FioResults {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageCatalogSpec)
+	out := new(FioResults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Import) DeepCopyInto(out *Import) {
+func (in *GSSAPIConfiguration) DeepCopyInto(out *GSSAPIConfiguration) {
 	*out = *in
-	out.Source = in.Source
-	if in.Databases != nil {
-		in, out := &in.Databases, &out.Databases
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.PostImportApplicationSQL != nil {
-		in, out := &in.PostImportApplicationSQL, &out.PostImportApplicationSQL
-		*out = make([]string, len(*in))
+	out.Keytab = in.Keytab
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]GSSAPIUserMapping, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
-func (in *Import) DeepCopy() *Import {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GSSAPIConfiguration.
+func (in *GSSAPIConfiguration) DeepCopy() *GSSAPIConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(Import)
+	out := new(GSSAPIConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImportSource) DeepCopyInto(out *ImportSource) {
+func (in *GSSAPIUserMapping) DeepCopyInto(out *GSSAPIUserMapping) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
-func (in *ImportSource) DeepCopy() *ImportSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GSSAPIUserMapping.
+func (in *GSSAPIUserMapping) DeepCopy() *GSSAPIUserMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(ImportSource)
+	out := new(GSSAPIUserMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceID) DeepCopyInto(out *InstanceID) {
+func (in *GarbageCollectionConfiguration) DeepCopyInto(out *GarbageCollectionConfiguration) {
 	*out = *in
+	if in.RetentionPeriod != nil {
+		in, out := &in.RetentionPeriod, &out.RetentionPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
-func (in *InstanceID) DeepCopy() *InstanceID {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionConfiguration.
+func (in *GarbageCollectionConfiguration) DeepCopy() *GarbageCollectionConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceID)
+	out := new(GarbageCollectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
+	*out = *in
+	if in.ApplicationCredentials != nil {
+		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
+func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardConfiguration) DeepCopyInto(out *GrafanaDashboardConfiguration) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardConfiguration.
+func (in *GrafanaDashboardConfiguration) DeepCopy() *GrafanaDashboardConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalog) DeepCopyInto(out *ImageCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalog.
+func (in *ImageCatalog) DeepCopy() *ImageCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalogList) DeepCopyInto(out *ImageCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogList.
+func (in *ImageCatalogList) DeepCopy() *ImageCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalogRef) DeepCopyInto(out *ImageCatalogRef) {
+	*out = *in
+	in.TypedLocalObjectReference.DeepCopyInto(&out.TypedLocalObjectReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogRef.
+func (in *ImageCatalogRef) DeepCopy() *ImageCatalogRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalogRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCatalogSpec) DeepCopyInto(out *ImageCatalogSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]CatalogImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogSpec.
+func (in *ImageCatalogSpec) DeepCopy() *ImageCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageInfo) DeepCopyInto(out *ImageInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageInfo.
+func (in *ImageInfo) DeepCopy() *ImageInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Import) DeepCopyInto(out *Import) {
+	*out = *in
+	out.Source = in.Source
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostImportApplicationSQL != nil {
+		in, out := &in.PostImportApplicationSQL, &out.PostImportApplicationSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
+func (in *Import) DeepCopy() *Import {
+	if in == nil {
+		return nil
+	}
+	out := new(Import)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportSource) DeepCopyInto(out *ImportSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
+func (in *ImportSource) DeepCopy() *ImportSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InactiveSlotsConfiguration) DeepCopyInto(out *InactiveSlotsConfiguration) {
+	*out = *in
+	if in.ExcludePatterns != nil {
+		in, out := &in.ExcludePatterns, &out.ExcludePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InactiveSlotsConfiguration.
+func (in *InactiveSlotsConfiguration) DeepCopy() *InactiveSlotsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(InactiveSlotsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceID) DeepCopyInto(out *InstanceID) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
+func (in *InstanceID) DeepCopy() *InstanceID {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceID)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1424,501 +2618,2216 @@ func (in *InstanceID) DeepCopy() *InstanceID {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
 	*out = *in
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = make([]LifecycleHookStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartupCheck != nil {
+		in, out := &in.StartupCheck, &out.StartupCheck
+		*out = new(StartupCheckStatus)
+		**out = **in
+	}
+	if in.PostmasterRestart != nil {
+		in, out := &in.PostmasterRestart, &out.PostmasterRestart
+		*out = new(PostmasterRestartStatus)
+		**out = **in
+	}
+	if in.WAL != nil {
+		in, out := &in.WAL, &out.WAL
+		*out = new(InstanceWALStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
+func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceReportedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceWALStatus) DeepCopyInto(out *InstanceWALStatus) {
+	*out = *in
+	if in.ReplayLagBytes != nil {
+		in, out := &in.ReplayLagBytes, &out.ReplayLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceWALStatus.
+func (in *InstanceWALStatus) DeepCopy() *InstanceWALStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceWALStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
+func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindAsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+	*out = *in
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
+func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindSearchAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+	*out = *in
+	if in.BindAsAuth != nil {
+		in, out := &in.BindAsAuth, &out.BindAsAuth
+		*out = new(LDAPBindAsAuth)
+		**out = **in
+	}
+	if in.BindSearchAuth != nil {
+		in, out := &in.BindSearchAuth, &out.BindSearchAuth
+		*out = new(LDAPBindSearchAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
+func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LagRoutingConfiguration) DeepCopyInto(out *LagRoutingConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LagRoutingConfiguration.
+func (in *LagRoutingConfiguration) DeepCopy() *LagRoutingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LagRoutingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHook) DeepCopyInto(out *LifecycleHook) {
+	*out = *in
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = new(ConfigMapKeySelector)
+		**out = **in
+	}
+	if in.Script != nil {
+		in, out := &in.Script, &out.Script
+		*out = new(ConfigMapKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHook.
+func (in *LifecycleHook) DeepCopy() *LifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHookStatus) DeepCopyInto(out *LifecycleHookStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHookStatus.
+func (in *LifecycleHookStatus) DeepCopy() *LifecycleHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHooksConfiguration) DeepCopyInto(out *LifecycleHooksConfiguration) {
+	*out = *in
+	if in.PreStart != nil {
+		in, out := &in.PreStart, &out.PreStart
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostStart != nil {
+		in, out := &in.PostStart, &out.PostStart
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreStop != nil {
+		in, out := &in.PreStop, &out.PreStop
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostPromotion != nil {
+		in, out := &in.PostPromotion, &out.PostPromotion
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreDemotion != nil {
+		in, out := &in.PreDemotion, &out.PreDemotion
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHooksConfiguration.
+func (in *LifecycleHooksConfiguration) DeepCopy() *LifecycleHooksConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHooksConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
+func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogFilesConfiguration) DeepCopyInto(out *LogFilesConfiguration) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxFiles != nil {
+		in, out := &in.MaxFiles, &out.MaxFiles
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.EphemeralVolumeSource != nil {
+		in, out := &in.EphemeralVolumeSource, &out.EphemeralVolumeSource
+		*out = new(corev1.EphemeralVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogFilesConfiguration.
+func (in *LogFilesConfiguration) DeepCopy() *LogFilesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LogFilesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalImportStatus) DeepCopyInto(out *LogicalImportStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalImportStatus.
+func (in *LogicalImportStatus) DeepCopy() *LogicalImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceTask) DeepCopyInto(out *MaintenanceTask) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceTask.
+func (in *MaintenanceTask) DeepCopy() *MaintenanceTask {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceTaskRun) DeepCopyInto(out *MaintenanceTaskRun) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceTaskRun.
+func (in *MaintenanceTaskRun) DeepCopy() *MaintenanceTaskRun {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceTaskRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ManagedServices)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
+func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
+	*out = *in
+	if in.ByStatus != nil {
+		in, out := &in.ByStatus, &out.ByStatus
+		*out = make(map[RoleStatus][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CannotReconcile != nil {
+		in, out := &in.CannotReconcile, &out.CannotReconcile
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.PasswordStatus != nil {
+		in, out := &in.PasswordStatus, &out.PasswordStatus
+		*out = make(map[string]PasswordState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
+func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRoles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedService) DeepCopyInto(out *ManagedService) {
+	*out = *in
+	in.ServiceTemplate.DeepCopyInto(&out.ServiceTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedService.
+func (in *ManagedService) DeepCopy() *ManagedService {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
+	*out = *in
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make([]ManagedService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LagRouting != nil {
+		in, out := &in.LagRouting, &out.LagRouting
+		*out = new(LagRoutingConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedServices.
+func (in *ManagedServices) DeepCopy() *ManagedServices {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metadata) DeepCopyInto(out *Metadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metadata.
+func (in *Metadata) DeepCopy() *Metadata {
+	if in == nil {
+		return nil
+	}
+	out := new(Metadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+	*out = *in
+	if in.DisableDefaultQueries != nil {
+		in, out := &in.DisableDefaultQueries, &out.DisableDefaultQueries
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CustomQueriesConfigMap != nil {
+		in, out := &in.CustomQueriesConfigMap, &out.CustomQueriesConfigMap
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.CustomQueriesSecret != nil {
+		in, out := &in.CustomQueriesSecret, &out.CustomQueriesSecret
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodMonitorMetricRelabelConfigs != nil {
+		in, out := &in.PodMonitorMetricRelabelConfigs, &out.PodMonitorMetricRelabelConfigs
+		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(monitoringv1.RelabelConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.PodMonitorRelabelConfigs != nil {
+		in, out := &in.PodMonitorRelabelConfigs, &out.PodMonitorRelabelConfigs
+		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(monitoringv1.RelabelConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.QueryStatistics != nil {
+		in, out := &in.QueryStatistics, &out.QueryStatistics
+		*out = new(QueryStatisticsConfiguration)
+		**out = **in
+	}
+	if in.ObjectSizes != nil {
+		in, out := &in.ObjectSizes, &out.ObjectSizes
+		*out = new(ObjectSizesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(PrometheusRuleConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaDashboard != nil {
+		in, out := &in.GrafanaDashboard, &out.GrafanaDashboard
+		*out = new(GrafanaDashboardConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
+func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedBarmanObjectStoreConfiguration) DeepCopyInto(out *NamedBarmanObjectStoreConfiguration) {
+	*out = *in
+	in.BarmanObjectStoreConfiguration.DeepCopyInto(&out.BarmanObjectStoreConfiguration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedBarmanObjectStoreConfiguration.
+func (in *NamedBarmanObjectStoreConfiguration) DeepCopy() *NamedBarmanObjectStoreConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedBarmanObjectStoreConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
+	*out = *in
+	if in.ReusePVC != nil {
+		in, out := &in.ReusePVC, &out.ReusePVC
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMaintenanceWindow.
+func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSizesConfiguration) DeepCopyInto(out *ObjectSizesConfiguration) {
+	*out = *in
+	if in.IncludeDatabases != nil {
+		in, out := &in.IncludeDatabases, &out.IncludeDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeDatabases != nil {
+		in, out := &in.ExcludeDatabases, &out.ExcludeDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeTables != nil {
+		in, out := &in.IncludeTables, &out.IncludeTables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeTables != nil {
+		in, out := &in.ExcludeTables, &out.ExcludeTables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSizesConfiguration.
+func (in *ObjectSizesConfiguration) DeepCopy() *ObjectSizesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSizesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnlineConfiguration) DeepCopyInto(out *OnlineConfiguration) {
+	*out = *in
+	if in.WaitForArchive != nil {
+		in, out := &in.WaitForArchive, &out.WaitForArchive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImmediateCheckpoint != nil {
+		in, out := &in.ImmediateCheckpoint, &out.ImmediateCheckpoint
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineConfiguration.
+func (in *OnlineConfiguration) DeepCopy() *OnlineConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OnlineConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordAuthenticationConfiguration) DeepCopyInto(out *PasswordAuthenticationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordAuthenticationConfiguration.
+func (in *PasswordAuthenticationConfiguration) DeepCopy() *PasswordAuthenticationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordAuthenticationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordState) DeepCopyInto(out *PasswordState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordState.
+func (in *PasswordState) DeepCopy() *PasswordState {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgAuditConfiguration) DeepCopyInto(out *PgAuditConfiguration) {
+	*out = *in
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogCatalog != nil {
+		in, out := &in.LogCatalog, &out.LogCatalog
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgAuditConfiguration.
+func (in *PgAuditConfiguration) DeepCopy() *PgAuditConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgAuditConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerAuthQueryProvisioning) DeepCopyInto(out *PgBouncerAuthQueryProvisioning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerAuthQueryProvisioning.
+func (in *PgBouncerAuthQueryProvisioning) DeepCopy() *PgBouncerAuthQueryProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerAuthQueryProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerAuthQueryUser) DeepCopyInto(out *PgBouncerAuthQueryUser) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretVersion)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerAuthQueryUser.
+func (in *PgBouncerAuthQueryUser) DeepCopy() *PgBouncerAuthQueryUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerAuthQueryUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerDatabase) DeepCopyInto(out *PgBouncerDatabase) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinPoolSize != nil {
+		in, out := &in.MinPoolSize, &out.MinPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReservePoolSize != nil {
+		in, out := &in.ReservePoolSize, &out.ReservePoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDBConnections != nil {
+		in, out := &in.MaxDBConnections, &out.MaxDBConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerDatabase.
+func (in *PgBouncerDatabase) DeepCopy() *PgBouncerDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthQueryUsers != nil {
+		in, out := &in.AuthQueryUsers, &out.AuthQueryUsers
+		*out = make([]PgBouncerAuthQueryUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
+func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerIntegrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+	*out = *in
+	out.AuthQuery = in.AuthQuery
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
+func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+	*out = *in
+	if in.AuthQuerySecret != nil {
+		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.AuthQueryProvisioning != nil {
+		in, out := &in.AuthQueryProvisioning, &out.AuthQueryProvisioning
+		*out = new(PgBouncerAuthQueryProvisioning)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PgHBA != nil {
+		in, out := &in.PgHBA, &out.PgHBA
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgBouncerDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PgBouncerUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
+func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerUser) DeepCopyInto(out *PgBouncerUser) {
+	*out = *in
+	if in.MaxUserConnections != nil {
+		in, out := &in.MaxUserConnections, &out.MaxUserConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerUser.
+func (in *PgBouncerUser) DeepCopy() *PgBouncerUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgHBARule) DeepCopyInto(out *PgHBARule) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgHBARule.
+func (in *PgHBARule) DeepCopy() *PgHBARule {
+	if in == nil {
+		return nil
+	}
+	out := new(PgHBARule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgbenchConfiguration) DeepCopyInto(out *PgbenchConfiguration) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgbenchConfiguration.
+func (in *PgbenchConfiguration) DeepCopy() *PgbenchConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgbenchConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgbenchResults) DeepCopyInto(out *PgbenchResults) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgbenchResults.
+func (in *PgbenchResults) DeepCopy() *PgbenchResults {
+	if in == nil {
+		return nil
+	}
+	out := new(PgbenchResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginConfiguration) DeepCopyInto(out *PluginConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginConfiguration.
+func (in *PluginConfiguration) DeepCopy() *PluginConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PluginConfigurationList) DeepCopyInto(out *PluginConfigurationList) {
+	{
+		in := &in
+		*out = make(PluginConfigurationList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginConfigurationList.
+func (in PluginConfigurationList) DeepCopy() PluginConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginConfigurationList)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginStatus) DeepCopyInto(out *PluginStatus) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OperatorCapabilities != nil {
+		in, out := &in.OperatorCapabilities, &out.OperatorCapabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WALCapabilities != nil {
+		in, out := &in.WALCapabilities, &out.WALCapabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackupCapabilities != nil {
+		in, out := &in.BackupCapabilities, &out.BackupCapabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginStatus.
+func (in *PluginStatus) DeepCopy() *PluginStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateSpec.
+func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PodTopologyLabels) DeepCopyInto(out *PodTopologyLabels) {
+	{
+		in := &in
+		*out = make(PodTopologyLabels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTopologyLabels.
+func (in PodTopologyLabels) DeepCopy() PodTopologyLabels {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTopologyLabels)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pooler) DeepCopyInto(out *Pooler) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pooler.
+func (in *Pooler) DeepCopy() *Pooler {
+	if in == nil {
+		return nil
+	}
+	out := new(Pooler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pooler) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscaling) DeepCopyInto(out *PoolerAutoscaling) {
+	*out = *in
+	if in.MinInstances != nil {
+		in, out := &in.MinInstances, &out.MinInstances
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetClientConnections != nil {
+		in, out := &in.TargetClientConnections, &out.TargetClientConnections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscaling.
+func (in *PoolerAutoscaling) DeepCopy() *PoolerAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
+	*out = *in
+	in.PgBouncerIntegration.DeepCopyInto(&out.PgBouncerIntegration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerIntegrations.
+func (in *PoolerIntegrations) DeepCopy() *PoolerIntegrations {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerIntegrations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerList) DeepCopyInto(out *PoolerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Pooler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
+func (in *PoolerList) DeepCopy() *PoolerList {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PoolerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfiguration) {
+	*out = *in
+	if in.PodMonitorMetricRelabelConfigs != nil {
+		in, out := &in.PodMonitorMetricRelabelConfigs, &out.PodMonitorMetricRelabelConfigs
+		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(monitoringv1.RelabelConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.PodMonitorRelabelConfigs != nil {
+		in, out := &in.PodMonitorRelabelConfigs, &out.PodMonitorRelabelConfigs
+		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(monitoringv1.RelabelConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
+func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerMonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
+	*out = *in
+	out.ServerTLS = in.ServerTLS
+	out.ServerCA = in.ServerCA
+	out.ClientCA = in.ClientCA
+	if in.PgBouncerSecrets != nil {
+		in, out := &in.PgBouncerSecrets, &out.PgBouncerSecrets
+		*out = new(PgBouncerSecrets)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSecrets.
+func (in *PoolerSecrets) DeepCopy() *PoolerSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PgBouncer != nil {
+		in, out := &in.PgBouncer, &out.PgBouncer
+		*out = new(PgBouncerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentStrategy != nil {
+		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(PoolerMonitoringConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTemplate != nil {
+		in, out := &in.ServiceTemplate, &out.ServiceTemplate
+		*out = new(ServiceTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
+func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = new(PoolerSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
+func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
+	*out = *in
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRefs != nil {
+		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
+func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(PostInitApplicationSQLRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ReplicaParameters != nil {
+		in, out := &in.ReplicaParameters, &out.ReplicaParameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PgHBA != nil {
+		in, out := &in.PgHBA, &out.PgHBA
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PgHBARules != nil {
+		in, out := &in.PgHBARules, &out.PgHBARules
+		*out = make([]PgHBARule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PgIdent != nil {
+		in, out := &in.PgIdent, &out.PgIdent
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
+	if in.AdditionalLibraries != nil {
+		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GSSAPI != nil {
+		in, out := &in.GSSAPI, &out.GSSAPI
+		*out = new(GSSAPIConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordAuthentication != nil {
+		in, out := &in.PasswordAuthentication, &out.PasswordAuthentication
+		*out = new(PasswordAuthenticationConfiguration)
+		**out = **in
+	}
+	if in.TDE != nil {
+		in, out := &in.TDE, &out.TDE
+		*out = new(TDEConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PgAudit != nil {
+		in, out := &in.PgAudit, &out.PgAudit
+		*out = new(PgAuditConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
+func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostmasterRestartConfiguration) DeepCopyInto(out *PostmasterRestartConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostmasterRestartConfiguration.
+func (in *PostmasterRestartConfiguration) DeepCopy() *PostmasterRestartConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostmasterRestartConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostmasterRestartStatus) DeepCopyInto(out *PostmasterRestartStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostmasterRestartStatus.
+func (in *PostmasterRestartStatus) DeepCopy() *PostmasterRestartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostmasterRestartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
+	if in == nil {
+		return nil
+	}
+	out := new(Probe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeWithStrategy) DeepCopyInto(out *ProbeWithStrategy) {
+	*out = *in
+	in.Probe.DeepCopyInto(&out.Probe)
+	if in.MaximumLag != nil {
+		in, out := &in.MaximumLag, &out.MaximumLag
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaximumRecoveryConflicts != nil {
+		in, out := &in.MaximumRecoveryConflicts, &out.MaximumRecoveryConflicts
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeWithStrategy.
+func (in *ProbeWithStrategy) DeepCopy() *ProbeWithStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeWithStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbesConfiguration) DeepCopyInto(out *ProbesConfiguration) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ProbeWithStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeWithStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfiguration.
+func (in *ProbesConfiguration) DeepCopy() *ProbesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleConfiguration) DeepCopyInto(out *PrometheusRuleConfiguration) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleConfiguration.
+func (in *PrometheusRuleConfiguration) DeepCopy() *PrometheusRuleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Publication) DeepCopyInto(out *Publication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Publication.
+func (in *Publication) DeepCopy() *Publication {
+	if in == nil {
+		return nil
+	}
+	out := new(Publication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Publication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationList) DeepCopyInto(out *PublicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Publication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationList.
+func (in *PublicationList) DeepCopy() *PublicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PublicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationSpec) DeepCopyInto(out *PublicationSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationSpec.
+func (in *PublicationSpec) DeepCopy() *PublicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationStatus) DeepCopyInto(out *PublicationStatus) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationStatus.
+func (in *PublicationStatus) DeepCopy() *PublicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationTarget) DeepCopyInto(out *PublicationTarget) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]PublicationTargetObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTarget.
+func (in *PublicationTarget) DeepCopy() *PublicationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationTargetObject) DeepCopyInto(out *PublicationTargetObject) {
+	*out = *in
+	if in.Table != nil {
+		in, out := &in.Table, &out.Table
+		*out = new(PublicationTargetTable)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTargetObject.
+func (in *PublicationTargetObject) DeepCopy() *PublicationTargetObject {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationTargetObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationTargetTable) DeepCopyInto(out *PublicationTargetTable) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTargetTable.
+func (in *PublicationTargetTable) DeepCopy() *PublicationTargetTable {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationTargetTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryStatisticsConfiguration) DeepCopyInto(out *QueryStatisticsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryStatisticsConfiguration.
+func (in *QueryStatisticsConfiguration) DeepCopy() *QueryStatisticsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryStatisticsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
+	*out = *in
+	if in.TargetImmediate != nil {
+		in, out := &in.TargetImmediate, &out.TargetImmediate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Exclusive != nil {
+		in, out := &in.Exclusive, &out.Exclusive
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
-func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTarget.
+func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceReportedState)
+	out := new(RecoveryTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+func (in *ReplicaAutoRecloneConfiguration) DeepCopyInto(out *ReplicaAutoRecloneConfiguration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
-func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaAutoRecloneConfiguration.
+func (in *ReplicaAutoRecloneConfiguration) DeepCopy() *ReplicaAutoRecloneConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindAsAuth)
+	out := new(ReplicaAutoRecloneConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+func (in *ReplicaCloneConfiguration) DeepCopyInto(out *ReplicaCloneConfiguration) {
 	*out = *in
-	if in.BindPassword != nil {
-		in, out := &in.BindPassword, &out.BindPassword
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
-func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaCloneConfiguration.
+func (in *ReplicaCloneConfiguration) DeepCopy() *ReplicaCloneConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindSearchAuth)
+	out := new(ReplicaCloneConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
 	*out = *in
-	if in.BindAsAuth != nil {
-		in, out := &in.BindAsAuth, &out.BindAsAuth
-		*out = new(LDAPBindAsAuth)
-		**out = **in
-	}
-	if in.BindSearchAuth != nil {
-		in, out := &in.BindSearchAuth, &out.BindSearchAuth
-		*out = new(LDAPBindSearchAuth)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
-func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaClusterConfiguration.
+func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPConfig)
+	out := new(ReplicaClusterConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+func (in *ReplicaRecloneStatus) DeepCopyInto(out *ReplicaRecloneStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
-func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaRecloneStatus.
+func (in *ReplicaRecloneStatus) DeepCopy() *ReplicaRecloneStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalObjectReference)
+	out := new(ReplicaRecloneStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+func (in *ReplicaSurgeStatus) DeepCopyInto(out *ReplicaSurgeStatus) {
 	*out = *in
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]RoleConfiguration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
-func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaSurgeStatus.
+func (in *ReplicaSurgeStatus) DeepCopy() *ReplicaSurgeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedConfiguration)
+	out := new(ReplicaSurgeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
+func (in *ReplicationGrant) DeepCopyInto(out *ReplicationGrant) {
 	*out = *in
-	if in.ByStatus != nil {
-		in, out := &in.ByStatus, &out.ByStatus
-		*out = make(map[RoleStatus][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.CannotReconcile != nil {
-		in, out := &in.CannotReconcile, &out.CannotReconcile
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.PasswordStatus != nil {
-		in, out := &in.PasswordStatus, &out.PasswordStatus
-		*out = make(map[string]PasswordState, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
-func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationGrant.
+func (in *ReplicationGrant) DeepCopy() *ReplicationGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedRoles)
+	out := new(ReplicationGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicationGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Metadata) DeepCopyInto(out *Metadata) {
+func (in *ReplicationGrantList) DeepCopyInto(out *ReplicationGrantList) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ReplicationGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metadata.
-func (in *Metadata) DeepCopy() *Metadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationGrantList.
+func (in *ReplicationGrantList) DeepCopy() *ReplicationGrantList {
 	if in == nil {
 		return nil
 	}
-	out := new(Metadata)
+	out := new(ReplicationGrantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicationGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+func (in *ReplicationGrantSpec) DeepCopyInto(out *ReplicationGrantSpec) {
 	*out = *in
-	if in.DisableDefaultQueries != nil {
-		in, out := &in.DisableDefaultQueries, &out.DisableDefaultQueries
-		*out = new(bool)
-		**out = **in
-	}
-	if in.CustomQueriesConfigMap != nil {
-		in, out := &in.CustomQueriesConfigMap, &out.CustomQueriesConfigMap
-		*out = make([]ConfigMapKeySelector, len(*in))
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.CustomQueriesSecret != nil {
-		in, out := &in.CustomQueriesSecret, &out.CustomQueriesSecret
-		*out = make([]SecretKeySelector, len(*in))
+	if in.Grantees != nil {
+		in, out := &in.Grantees, &out.Grantees
+		*out = make([]ReplicationGrantee, len(*in))
 		copy(*out, *in)
 	}
-	if in.PodMonitorMetricRelabelConfigs != nil {
-		in, out := &in.PodMonitorMetricRelabelConfigs, &out.PodMonitorMetricRelabelConfigs
-		*out = make([]*monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(monitoringv1.RelabelConfig)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
-	if in.PodMonitorRelabelConfigs != nil {
-		in, out := &in.PodMonitorRelabelConfigs, &out.PodMonitorRelabelConfigs
-		*out = make([]*monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(monitoringv1.RelabelConfig)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
-func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationGrantSpec.
+func (in *ReplicationGrantSpec) DeepCopy() *ReplicationGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitoringConfiguration)
+	out := new(ReplicationGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
+func (in *ReplicationGrantee) DeepCopyInto(out *ReplicationGrantee) {
 	*out = *in
-	if in.ReusePVC != nil {
-		in, out := &in.ReusePVC, &out.ReusePVC
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMaintenanceWindow.
-func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationGrantee.
+func (in *ReplicationGrantee) DeepCopy() *ReplicationGrantee {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeMaintenanceWindow)
+	out := new(ReplicationGrantee)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OnlineConfiguration) DeepCopyInto(out *OnlineConfiguration) {
+func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
-	if in.WaitForArchive != nil {
-		in, out := &in.WaitForArchive, &out.WaitForArchive
-		*out = new(bool)
-		**out = **in
+	if in.HighAvailability != nil {
+		in, out := &in.HighAvailability, &out.HighAvailability
+		*out = new(ReplicationSlotsHAConfiguration)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ImmediateCheckpoint != nil {
-		in, out := &in.ImmediateCheckpoint, &out.ImmediateCheckpoint
-		*out = new(bool)
-		**out = **in
+	if in.SynchronizeReplicas != nil {
+		in, out := &in.SynchronizeReplicas, &out.SynchronizeReplicas
+		*out = new(SynchronizeReplicasConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InactiveSlots != nil {
+		in, out := &in.InactiveSlots, &out.InactiveSlots
+		*out = new(InactiveSlotsConfiguration)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineConfiguration.
-func (in *OnlineConfiguration) DeepCopy() *OnlineConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsConfiguration.
+func (in *ReplicationSlotsConfiguration) DeepCopy() *ReplicationSlotsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(OnlineConfiguration)
+	out := new(ReplicationSlotsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PasswordState) DeepCopyInto(out *PasswordState) {
+func (in *ReplicationSlotsHAConfiguration) DeepCopyInto(out *ReplicationSlotsHAConfiguration) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordState.
-func (in *PasswordState) DeepCopy() *PasswordState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsHAConfiguration.
+func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PasswordState)
+	out := new(ReplicationSlotsHAConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+func (in *Restore) DeepCopyInto(out *Restore) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
-func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Restore.
+func (in *Restore) DeepCopy() *Restore {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerIntegrationStatus)
+	out := new(Restore)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Restore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+func (in *RestoreList) DeepCopyInto(out *RestoreList) {
 	*out = *in
-	out.AuthQuery = in.AuthQuery
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Restore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
-func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreList.
+func (in *RestoreList) DeepCopy() *RestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerSecrets)
+	out := new(RestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
 	*out = *in
-	if in.AuthQuerySecret != nil {
-		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
+	out.Cluster = in.Cluster
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.PgHBA != nil {
-		in, out := &in.PgHBA, &out.PgHBA
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Paused != nil {
-		in, out := &in.Paused, &out.Paused
-		*out = new(bool)
-		**out = **in
+	if in.RecoveryTarget != nil {
+		in, out := &in.RecoveryTarget, &out.RecoveryTarget
+		*out = new(RecoveryTarget)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
-func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSpec.
+func (in *RestoreSpec) DeepCopy() *RestoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerSpec)
+	out := new(RestoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PluginConfiguration) DeepCopyInto(out *PluginConfiguration) {
+func (in *RestoreStatus) DeepCopyInto(out *RestoreStatus) {
 	*out = *in
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginConfiguration.
-func (in *PluginConfiguration) DeepCopy() *PluginConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreStatus.
+func (in *RestoreStatus) DeepCopy() *RestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PluginConfiguration)
+	out := new(RestoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in PluginConfigurationList) DeepCopyInto(out *PluginConfigurationList) {
-	{
-		in := &in
-		*out = make(PluginConfigurationList, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+func (in *RetentionPolicyEnforcementStatus) DeepCopyInto(out *RetentionPolicyEnforcementStatus) {
+	*out = *in
+	if in.RemovedBackups != nil {
+		in, out := &in.RemovedBackups, &out.RemovedBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginConfigurationList.
-func (in PluginConfigurationList) DeepCopy() PluginConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicyEnforcementStatus.
+func (in *RetentionPolicyEnforcementStatus) DeepCopy() *RetentionPolicyEnforcementStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PluginConfigurationList)
+	out := new(RetentionPolicyEnforcementStatus)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PluginStatus) DeepCopyInto(out *PluginStatus) {
+func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
 	*out = *in
-	if in.Capabilities != nil {
-		in, out := &in.Capabilities, &out.Capabilities
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(LocalObjectReference)
+		**out = **in
 	}
-	if in.OperatorCapabilities != nil {
-		in, out := &in.OperatorCapabilities, &out.OperatorCapabilities
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ValidUntil != nil {
+		in, out := &in.ValidUntil, &out.ValidUntil
+		*out = (*in).DeepCopy()
 	}
-	if in.WALCapabilities != nil {
-		in, out := &in.WALCapabilities, &out.WALCapabilities
+	if in.InRoles != nil {
+		in, out := &in.InRoles, &out.InRoles
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.BackupCapabilities != nil {
-		in, out := &in.BackupCapabilities, &out.BackupCapabilities
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Inherit != nil {
+		in, out := &in.Inherit, &out.Inherit
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginStatus.
-func (in *PluginStatus) DeepCopy() *PluginStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleConfiguration.
+func (in *RoleConfiguration) DeepCopy() *RoleConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PluginStatus)
+	out := new(RoleConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
+func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateSpec.
-func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateStatus.
+func (in *RollingUpdateStatus) DeepCopy() *RollingUpdateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PodTemplateSpec)
+	out := new(RollingUpdateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in PodTopologyLabels) DeepCopyInto(out *PodTopologyLabels) {
-	{
-		in := &in
-		*out = make(PodTopologyLabels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+func (in *S3Credentials) DeepCopyInto(out *S3Credentials) {
+	*out = *in
+	if in.AccessKeyIDReference != nil {
+		in, out := &in.AccessKeyIDReference, &out.AccessKeyIDReference
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.SecretAccessKeyReference != nil {
+		in, out := &in.SecretAccessKeyReference, &out.SecretAccessKeyReference
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.RegionReference != nil {
+		in, out := &in.RegionReference, &out.RegionReference
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.SessionToken != nil {
+		in, out := &in.SessionToken, &out.SessionToken
+		*out = new(SecretKeySelector)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTopologyLabels.
-func (in PodTopologyLabels) DeepCopy() PodTopologyLabels {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Credentials.
+func (in *S3Credentials) DeepCopy() *S3Credentials {
 	if in == nil {
 		return nil
 	}
-	out := new(PodTopologyLabels)
+	out := new(S3Credentials)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Pooler) DeepCopyInto(out *Pooler) {
+func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1926,18 +4835,18 @@ func (in *Pooler) DeepCopyInto(out *Pooler) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pooler.
-func (in *Pooler) DeepCopy() *Pooler {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackup.
+func (in *ScheduledBackup) DeepCopy() *ScheduledBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(Pooler)
+	out := new(ScheduledBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Pooler) DeepCopyObject() runtime.Object {
+func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1945,47 +4854,31 @@ func (in *Pooler) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
-	*out = *in
-	in.PgBouncerIntegration.DeepCopyInto(&out.PgBouncerIntegration)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerIntegrations.
-func (in *PoolerIntegrations) DeepCopy() *PoolerIntegrations {
-	if in == nil {
-		return nil
-	}
-	out := new(PoolerIntegrations)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerList) DeepCopyInto(out *PoolerList) {
+func (in *ScheduledBackupList) DeepCopyInto(out *ScheduledBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Pooler, len(*in))
+		*out = make([]ScheduledBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
-func (in *PoolerList) DeepCopy() *PoolerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupList.
+func (in *ScheduledBackupList) DeepCopy() *ScheduledBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerList)
+	out := new(ScheduledBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PoolerList) DeepCopyObject() runtime.Object {
+func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1993,371 +4886,287 @@ func (in *PoolerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfiguration) {
-	*out = *in
-	if in.PodMonitorMetricRelabelConfigs != nil {
-		in, out := &in.PodMonitorMetricRelabelConfigs, &out.PodMonitorMetricRelabelConfigs
-		*out = make([]*monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(monitoringv1.RelabelConfig)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
-	if in.PodMonitorRelabelConfigs != nil {
-		in, out := &in.PodMonitorRelabelConfigs, &out.PodMonitorRelabelConfigs
-		*out = make([]*monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(monitoringv1.RelabelConfig)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
-func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfiguration {
-	if in == nil {
-		return nil
-	}
-	out := new(PoolerMonitoringConfiguration)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
-	*out = *in
-	out.ServerTLS = in.ServerTLS
-	out.ServerCA = in.ServerCA
-	out.ClientCA = in.ClientCA
-	if in.PgBouncerSecrets != nil {
-		in, out := &in.PgBouncerSecrets, &out.PgBouncerSecrets
-		*out = new(PgBouncerSecrets)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSecrets.
-func (in *PoolerSecrets) DeepCopy() *PoolerSecrets {
-	if in == nil {
-		return nil
-	}
-	out := new(PoolerSecrets)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
-	*out = *in
-	out.Cluster = in.Cluster
-	if in.Instances != nil {
-		in, out := &in.Instances, &out.Instances
-		*out = new(int32)
+func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Template != nil {
-		in, out := &in.Template, &out.Template
-		*out = new(PodTemplateSpec)
-		(*in).DeepCopyInto(*out)
+	if in.Immediate != nil {
+		in, out := &in.Immediate, &out.Immediate
+		*out = new(bool)
+		**out = **in
 	}
-	if in.PgBouncer != nil {
-		in, out := &in.PgBouncer, &out.PgBouncer
-		*out = new(PgBouncerSpec)
+	out.Cluster = in.Cluster
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(BackupPluginConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.DeploymentStrategy != nil {
-		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
-		*out = new(appsv1.DeploymentStrategy)
-		(*in).DeepCopyInto(*out)
+	if in.Online != nil {
+		in, out := &in.Online, &out.Online
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Monitoring != nil {
-		in, out := &in.Monitoring, &out.Monitoring
-		*out = new(PoolerMonitoringConfiguration)
+	if in.OnlineConfiguration != nil {
+		in, out := &in.OnlineConfiguration, &out.OnlineConfiguration
+		*out = new(OnlineConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ServiceTemplate != nil {
-		in, out := &in.ServiceTemplate, &out.ServiceTemplate
-		*out = new(ServiceTemplateSpec)
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(BackupVerificationConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Jitter != nil {
+		in, out := &in.Jitter, &out.Jitter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
-func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
+func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerSpec)
+	out := new(ScheduledBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = new(PoolerSecrets)
-		(*in).DeepCopyInto(*out)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
-func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
+func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerStatus)
+	out := new(ScheduledBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
-	if in.SecretRefs != nil {
-		in, out := &in.SecretRefs, &out.SecretRefs
-		*out = make([]SecretKeySelector, len(*in))
-		copy(*out, *in)
-	}
-	if in.ConfigMapRefs != nil {
-		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
-		*out = make([]ConfigMapKeySelector, len(*in))
-		copy(*out, *in)
-	}
+	out.LocalObjectReference = in.LocalObjectReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
-func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
 	if in == nil {
 		return nil
 	}
-	out := new(PostInitApplicationSQLRefs)
+	out := new(SecretKeySelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+func (in *SecretVersion) DeepCopyInto(out *SecretVersion) {
 	*out = *in
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.PgHBA != nil {
-		in, out := &in.PgHBA, &out.PgHBA
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.PgIdent != nil {
-		in, out := &in.PgIdent, &out.PgIdent
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
-	if in.AdditionalLibraries != nil {
-		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.LDAP != nil {
-		in, out := &in.LDAP, &out.LDAP
-		*out = new(LDAPConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
-func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVersion.
+func (in *SecretVersion) DeepCopy() *SecretVersion {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresConfiguration)
+	out := new(SecretVersion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
+func (in *SecretsResourceVersion) DeepCopyInto(out *SecretsResourceVersion) {
 	*out = *in
-	if in.TargetImmediate != nil {
-		in, out := &in.TargetImmediate, &out.TargetImmediate
-		*out = new(bool)
-		**out = **in
+	if in.ManagedRoleSecretVersions != nil {
+		in, out := &in.ManagedRoleSecretVersions, &out.ManagedRoleSecretVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.Exclusive != nil {
-		in, out := &in.Exclusive, &out.Exclusive
-		*out = new(bool)
-		**out = **in
+	if in.ExternalClusterSecretVersions != nil {
+		in, out := &in.ExternalClusterSecretVersions, &out.ExternalClusterSecretVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BarmanCredentialsSecretVersions != nil {
+		in, out := &in.BarmanCredentialsSecretVersions, &out.BarmanCredentialsSecretVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTarget.
-func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsResourceVersion.
+func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
 	if in == nil {
 		return nil
 	}
-	out := new(RecoveryTarget)
+	out := new(SecretsResourceVersion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
+func (in *SecretsStoreConfiguration) DeepCopyInto(out *SecretsStoreConfiguration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaClusterConfiguration.
-func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsStoreConfiguration.
+func (in *SecretsStoreConfiguration) DeepCopy() *SecretsStoreConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicaClusterConfiguration)
+	out := new(SecretsStoreConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
+func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
 	*out = *in
-	if in.HighAvailability != nil {
-		in, out := &in.HighAvailability, &out.HighAvailability
-		*out = new(ReplicationSlotsHAConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SynchronizeReplicas != nil {
-		in, out := &in.SynchronizeReplicas, &out.SynchronizeReplicas
-		*out = new(SynchronizeReplicasConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
+	in.Metadata.DeepCopyInto(&out.Metadata)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsConfiguration.
-func (in *ReplicationSlotsConfiguration) DeepCopy() *ReplicationSlotsConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTemplate.
+func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicationSlotsConfiguration)
+	out := new(ServiceAccountTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicationSlotsHAConfiguration) DeepCopyInto(out *ReplicationSlotsHAConfiguration) {
+func (in *ServiceTemplateSpec) DeepCopyInto(out *ServiceTemplateSpec) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsHAConfiguration.
-func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplateSpec.
+func (in *ServiceTemplateSpec) DeepCopy() *ServiceTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicationSlotsHAConfiguration)
+	out := new(ServiceTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
+func (in *StartupCheckConfiguration) DeepCopyInto(out *StartupCheckConfiguration) {
 	*out = *in
-	if in.PasswordSecret != nil {
-		in, out := &in.PasswordSecret, &out.PasswordSecret
-		*out = new(LocalObjectReference)
-		**out = **in
-	}
-	if in.ValidUntil != nil {
-		in, out := &in.ValidUntil, &out.ValidUntil
-		*out = (*in).DeepCopy()
-	}
-	if in.InRoles != nil {
-		in, out := &in.InRoles, &out.InRoles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Inherit != nil {
-		in, out := &in.Inherit, &out.Inherit
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleConfiguration.
-func (in *RoleConfiguration) DeepCopy() *RoleConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupCheckConfiguration.
+func (in *StartupCheckConfiguration) DeepCopy() *StartupCheckConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(RoleConfiguration)
+	out := new(StartupCheckConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
+func (in *StartupCheckStatus) DeepCopyInto(out *StartupCheckStatus) {
 	*out = *in
-	in.StartedAt.DeepCopyInto(&out.StartedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateStatus.
-func (in *RollingUpdateStatus) DeepCopy() *RollingUpdateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupCheckStatus.
+func (in *StartupCheckStatus) DeepCopy() *StartupCheckStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RollingUpdateStatus)
+	out := new(StartupCheckStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3Credentials) DeepCopyInto(out *S3Credentials) {
+func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
-	if in.AccessKeyIDReference != nil {
-		in, out := &in.AccessKeyIDReference, &out.AccessKeyIDReference
-		*out = new(SecretKeySelector)
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
 		**out = **in
 	}
-	if in.SecretAccessKeyReference != nil {
-		in, out := &in.SecretAccessKeyReference, &out.SecretAccessKeyReference
-		*out = new(SecretKeySelector)
+	if in.ResizeInUseVolumes != nil {
+		in, out := &in.ResizeInUseVolumes, &out.ResizeInUseVolumes
+		*out = new(bool)
 		**out = **in
 	}
-	if in.RegionReference != nil {
-		in, out := &in.RegionReference, &out.RegionReference
-		*out = new(SecretKeySelector)
-		**out = **in
+	if in.PersistentVolumeClaimTemplate != nil {
+		in, out := &in.PersistentVolumeClaimTemplate, &out.PersistentVolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.SessionToken != nil {
-		in, out := &in.SessionToken, &out.SessionToken
-		*out = new(SecretKeySelector)
+	if in.EmergencyCleanup != nil {
+		in, out := &in.EmergencyCleanup, &out.EmergencyCleanup
+		*out = new(StorageEmergencyCleanupConfiguration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Credentials.
-func (in *S3Credentials) DeepCopy() *S3Credentials {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
+func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageEmergencyCleanupConfiguration) DeepCopyInto(out *StorageEmergencyCleanupConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageEmergencyCleanupConfiguration.
+func (in *StorageEmergencyCleanupConfiguration) DeepCopy() *StorageEmergencyCleanupConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(S3Credentials)
+	out := new(StorageEmergencyCleanupConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
+func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -2365,18 +5174,18 @@ func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackup.
-func (in *ScheduledBackup) DeepCopy() *ScheduledBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subscription.
+func (in *Subscription) DeepCopy() *Subscription {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackup)
+	out := new(Subscription)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
+func (in *Subscription) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2384,31 +5193,31 @@ func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupList) DeepCopyInto(out *ScheduledBackupList) {
+func (in *SubscriptionList) DeepCopyInto(out *SubscriptionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ScheduledBackup, len(*in))
+		*out = make([]Subscription, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupList.
-func (in *ScheduledBackupList) DeepCopy() *ScheduledBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionList.
+func (in *SubscriptionList) DeepCopy() *SubscriptionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupList)
+	out := new(SubscriptionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
+func (in *SubscriptionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2416,214 +5225,172 @@ func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
+func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 	*out = *in
-	if in.Suspend != nil {
-		in, out := &in.Suspend, &out.Suspend
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Immediate != nil {
-		in, out := &in.Immediate, &out.Immediate
-		*out = new(bool)
-		**out = **in
-	}
-	out.Cluster = in.Cluster
-	if in.PluginConfiguration != nil {
-		in, out := &in.PluginConfiguration, &out.PluginConfiguration
-		*out = new(BackupPluginConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Online != nil {
-		in, out := &in.Online, &out.Online
-		*out = new(bool)
-		**out = **in
-	}
-	if in.OnlineConfiguration != nil {
-		in, out := &in.OnlineConfiguration, &out.OnlineConfiguration
-		*out = new(OnlineConfiguration)
-		(*in).DeepCopyInto(*out)
+	out.ClusterRef = in.ClusterRef
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
-func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
+func (in *SubscriptionSpec) DeepCopy() *SubscriptionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupSpec)
+	out := new(SubscriptionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
+func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 	*out = *in
-	if in.LastCheckTime != nil {
-		in, out := &in.LastCheckTime, &out.LastCheckTime
-		*out = (*in).DeepCopy()
-	}
-	if in.LastScheduleTime != nil {
-		in, out := &in.LastScheduleTime, &out.LastScheduleTime
-		*out = (*in).DeepCopy()
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(bool)
+		**out = **in
 	}
-	if in.NextScheduleTime != nil {
-		in, out := &in.NextScheduleTime, &out.NextScheduleTime
-		*out = (*in).DeepCopy()
+	if in.SlotLagBytes != nil {
+		in, out := &in.SlotLagBytes, &out.SlotLagBytes
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
-func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
+func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupStatus)
+	out := new(SubscriptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+func (in *SwitchReplicaClusterStatus) DeepCopyInto(out *SwitchReplicaClusterStatus) {
 	*out = *in
-	out.LocalObjectReference = in.LocalObjectReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
-func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchReplicaClusterStatus.
+func (in *SwitchReplicaClusterStatus) DeepCopy() *SwitchReplicaClusterStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeySelector)
+	out := new(SwitchReplicaClusterStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretVersion) DeepCopyInto(out *SecretVersion) {
+func (in *Switchover) DeepCopyInto(out *Switchover) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVersion.
-func (in *SecretVersion) DeepCopy() *SecretVersion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Switchover.
+func (in *Switchover) DeepCopy() *Switchover {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretVersion)
+	out := new(Switchover)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretsResourceVersion) DeepCopyInto(out *SecretsResourceVersion) {
-	*out = *in
-	if in.ManagedRoleSecretVersions != nil {
-		in, out := &in.ManagedRoleSecretVersions, &out.ManagedRoleSecretVersions
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExternalClusterSecretVersions != nil {
-		in, out := &in.ExternalClusterSecretVersions, &out.ExternalClusterSecretVersions
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Metrics != nil {
-		in, out := &in.Metrics, &out.Metrics
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsResourceVersion.
-func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Switchover) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(SecretsResourceVersion)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
+func (in *SwitchoverList) DeepCopyInto(out *SwitchoverList) {
 	*out = *in
-	in.Metadata.DeepCopyInto(&out.Metadata)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Switchover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTemplate.
-func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverList.
+func (in *SwitchoverList) DeepCopy() *SwitchoverList {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceAccountTemplate)
+	out := new(SwitchoverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceTemplateSpec) DeepCopyInto(out *ServiceTemplateSpec) {
-	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplateSpec.
-func (in *ServiceTemplateSpec) DeepCopy() *ServiceTemplateSpec {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwitchoverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(ServiceTemplateSpec)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
+func (in *SwitchoverSpec) DeepCopyInto(out *SwitchoverSpec) {
 	*out = *in
-	if in.StorageClass != nil {
-		in, out := &in.StorageClass, &out.StorageClass
-		*out = new(string)
-		**out = **in
+	out.Cluster = in.Cluster
+	if in.MaxLag != nil {
+		in, out := &in.MaxLag, &out.MaxLag
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.ResizeInUseVolumes != nil {
-		in, out := &in.ResizeInUseVolumes, &out.ResizeInUseVolumes
-		*out = new(bool)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.PersistentVolumeClaimTemplate != nil {
-		in, out := &in.PersistentVolumeClaimTemplate, &out.PersistentVolumeClaimTemplate
-		*out = new(corev1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
-func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverSpec.
+func (in *SwitchoverSpec) DeepCopy() *SwitchoverSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageConfiguration)
+	out := new(SwitchoverSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwitchReplicaClusterStatus) DeepCopyInto(out *SwitchReplicaClusterStatus) {
+func (in *SwitchoverStatus) DeepCopyInto(out *SwitchoverStatus) {
 	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchReplicaClusterStatus.
-func (in *SwitchReplicaClusterStatus) DeepCopy() *SwitchReplicaClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverStatus.
+func (in *SwitchoverStatus) DeepCopy() *SwitchoverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SwitchReplicaClusterStatus)
+	out := new(SwitchoverStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2674,6 +5441,26 @@ func (in *SynchronizeReplicasConfiguration) DeepCopy() *SynchronizeReplicasConfi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TDEConfiguration) DeepCopyInto(out *TDEConfiguration) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TDEConfiguration.
+func (in *TDEConfiguration) DeepCopy() *TDEConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TDEConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TablespaceConfiguration) DeepCopyInto(out *TablespaceConfiguration) {
 	*out = *in
@@ -2739,6 +5526,26 @@ func (in *Topology) DeepCopy() *Topology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatePolicy) DeepCopyInto(out *UpdatePolicy) {
+	*out = *in
+	if in.Canaries != nil {
+		in, out := &in.Canaries, &out.Canaries
+		*out = make([]LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatePolicy.
+func (in *UpdatePolicy) DeepCopy() *UpdatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfiguration) {
 	*out = *in
@@ -2781,9 +5588,58 @@ func (in *VolumeSnapshotConfiguration) DeepCopy() *VolumeSnapshotConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSourceRecovery) DeepCopyInto(out *VolumeSourceRecovery) {
+	*out = *in
+	out.Storage = in.Storage
+	if in.WalStorage != nil {
+		in, out := &in.WalStorage, &out.WalStorage
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.TablespaceStorage != nil {
+		in, out := &in.TablespaceStorage, &out.TablespaceStorage
+		*out = make(map[string]LocalObjectReference, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSourceRecovery.
+func (in *VolumeSourceRecovery) DeepCopy() *VolumeSourceRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSourceRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WALClientSideEncryption) DeepCopyInto(out *WALClientSideEncryption) {
+	*out = *in
+	out.KeysSecret = in.KeysSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WALClientSideEncryption.
+func (in *WALClientSideEncryption) DeepCopy() *WALClientSideEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(WALClientSideEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WalBackupConfiguration) DeepCopyInto(out *WalBackupConfiguration) {
 	*out = *in
+	if in.ClientSideEncryption != nil {
+		in, out := &in.ClientSideEncryption, &out.ClientSideEncryption
+		*out = new(WALClientSideEncryption)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalBackupConfiguration.
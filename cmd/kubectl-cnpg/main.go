@@ -45,6 +45,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/report"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/snapshot"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/sql"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/status"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -96,6 +97,7 @@ func main() {
 	rootCmd.AddCommand(versions.NewCmd())
 	rootCmd.AddCommand(backup.NewCmd())
 	rootCmd.AddCommand(psql.NewCmd())
+	rootCmd.AddCommand(sql.NewCmd())
 	rootCmd.AddCommand(snapshot.NewCmd())
 	rootCmd.AddCommand(logs.NewCmd())
 	rootCmd.AddCommand(pgadmin.NewCmd())
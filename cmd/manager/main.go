@@ -59,6 +59,7 @@ func main() {
 	cmd.AddCommand(show.NewCmd())
 	cmd.AddCommand(walarchive.NewCmd())
 	cmd.AddCommand(walrestore.NewCmd())
+	cmd.AddCommand(walrestore.NewDecryptCmd())
 	cmd.AddCommand(versions.NewCmd())
 	cmd.AddCommand(pgbouncer.NewCmd())
 	cmd.AddCommand(debug.NewCmd())
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v7/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,8 +43,10 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/volumesnapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
@@ -76,7 +79,7 @@ func NewBackupReconciler(mgr manager.Manager, discoveryClient *discovery.Discove
 		Client:               mgr.GetClient(),
 		DiscoveryClient:      discoveryClient,
 		Scheme:               mgr.GetScheme(),
-		Recorder:             mgr.GetEventRecorderFor("cloudnative-pg-backup"),
+		Recorder:             events.NewRecorder(mgr.GetEventRecorderFor("cloudnative-pg-backup")),
 		instanceStatusClient: instance.NewStatusClient(),
 	}
 }
@@ -88,6 +91,9 @@ func NewBackupReconciler(mgr manager.Manager, discoveryClient *discovery.Discove
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;create
 
 // Reconcile is the main reconciliation loop
 // nolint: gocognit
@@ -103,9 +109,18 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	if result, err := r.reconcileBackupFinalizer(ctx, &backup); err != nil || result != nil {
+		if result == nil {
+			result = &ctrl.Result{}
+		}
+		return *result, err
+	}
+
 	switch backup.Status.Phase {
-	case apiv1.BackupPhaseFailed, apiv1.BackupPhaseCompleted:
+	case apiv1.BackupPhaseFailed:
 		return ctrl.Result{}, nil
+	case apiv1.BackupPhaseCompleted:
+		return r.reconcileBackupVerification(ctx, &backup)
 	}
 
 	clusterName := backup.Spec.Cluster.Name
@@ -115,13 +130,13 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		Name:      clusterName,
 	}, &cluster); err != nil {
 		if apierrs.IsNotFound(err) {
-			r.Recorder.Eventf(&backup, "Warning", "FindingCluster",
+			r.Recorder.Eventf(&backup, "Warning", events.FindingCluster,
 				"Unknown cluster %v, will retry in 30 seconds", clusterName)
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 		}
 
 		tryFlagBackupAsFailed(ctx, r.Client, &backup, fmt.Errorf("while getting cluster %s: %w", clusterName, err))
-		r.Recorder.Eventf(&backup, "Warning", "FindingCluster",
+		r.Recorder.Eventf(&backup, "Warning", events.FindingCluster,
 			"Error getting cluster %v, will not retry: %s", clusterName, err.Error())
 		return ctrl.Result{}, nil
 	}
@@ -135,7 +150,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	if backup.Spec.Method == apiv1.BackupMethodVolumeSnapshot && !utils.HaveVolumeSnapshot() {
 		message := "cannot proceed with the backup as the Kubernetes cluster has no VolumeSnapshot support"
 		contextLogger.Warning(message)
-		r.Recorder.Event(&backup, "Warning", "ClusterHasNoVolumeSnapshotCRD", message)
+		r.Recorder.Event(&backup, "Warning", events.ClusterHasNoVolumeSnapshotCRD, message)
 		tryFlagBackupAsFailed(ctx, r.Client, &backup, errors.New(message))
 		return ctrl.Result{}, nil
 	}
@@ -155,11 +170,18 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			return ctrl.Result{}, nil
 		}
 
+		if cluster.Spec.Backup.GetBarmanObjectStore(backup.Spec.ObjectStoreName) == nil {
+			tryFlagBackupAsFailed(ctx, r.Client, &backup,
+				fmt.Errorf("no additional object store named %q defined on the target cluster",
+					backup.Spec.ObjectStoreName))
+			return ctrl.Result{}, nil
+		}
+
 		if isRunning {
 			return ctrl.Result{}, nil
 		}
 
-		r.Recorder.Eventf(&backup, "Normal", "Starting",
+		r.Recorder.Eventf(&backup, "Normal", events.BackupStarted,
 			"Starting backup for cluster %v", cluster.Name)
 	}
 
@@ -168,7 +190,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			return ctrl.Result{}, nil
 		}
 
-		r.Recorder.Eventf(&backup, "Normal", "Starting",
+		r.Recorder.Eventf(&backup, "Normal", events.BackupStarted,
 			"Starting backup for cluster %v", cluster.Name)
 	}
 
@@ -182,7 +204,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		// If no good running backups are found we elect a pod for the backup
 		pod, err := r.getBackupTargetPod(ctx, &cluster, &backup)
 		if apierrs.IsNotFound(err) {
-			r.Recorder.Eventf(&backup, "Warning", "FindingPod",
+			r.Recorder.Eventf(&backup, "Warning", events.FindingPod,
 				"Couldn't find target pod %s, will retry in 30 seconds", cluster.Status.TargetPrimary)
 			contextLogger.Info("Couldn't find target pod, will retry in 30 seconds", "target",
 				cluster.Status.TargetPrimary)
@@ -191,7 +213,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 		if err != nil {
 			tryFlagBackupAsFailed(ctx, r.Client, &backup, fmt.Errorf("while getting pod: %w", err))
-			r.Recorder.Eventf(&backup, "Warning", "FindingPod", "Error getting target pod: %s",
+			r.Recorder.Eventf(&backup, "Warning", events.FindingPod, "Error getting target pod: %s",
 				cluster.Status.TargetPrimary)
 			return ctrl.Result{}, nil
 		}
@@ -200,7 +222,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		if !utils.IsPodReady(*pod) {
 			contextLogger.Info("Backup target is not ready, will retry in 30 seconds", "target", pod.Name)
 			backup.Status.Phase = apiv1.BackupPhasePending
-			r.Recorder.Eventf(&backup, "Warning", "BackupPending", "Backup target pod not ready: %s",
+			r.Recorder.Eventf(&backup, "Warning", events.BackupPending, "Backup target pod not ready: %s",
 				cluster.Status.TargetPrimary)
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, r.Status().Patch(ctx, &backup, client.MergeFrom(origBackup))
 		}
@@ -211,7 +233,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 		// This backup has been started
 		if err := startInstanceManagerBackup(ctx, r.Client, &backup, pod, &cluster); err != nil {
-			r.Recorder.Eventf(&backup, "Warning", "Error", "Backup exit with error %v", err)
+			r.Recorder.Eventf(&backup, "Warning", events.BackupError, "Backup exit with error %v", err)
 			tryFlagBackupAsFailed(ctx, r.Client, &backup, fmt.Errorf("encountered an error while taking the backup: %w", err))
 			return ctrl.Result{}, nil
 		}
@@ -263,7 +285,7 @@ func (r *BackupReconciler) isValidBackupRunning(
 		r.Recorder.Eventf(
 			backup,
 			"Normal",
-			"ReStarting",
+			events.BackupRestarting,
 			"Could not find the elected backup pod. Restarting backup for cluster %v on instance %v",
 			cluster.Name,
 			pod.Name,
@@ -306,7 +328,7 @@ func (r *BackupReconciler) isValidBackupRunning(
 	)
 
 	// We need to restart the backup as the previously selected instance doesn't look healthy
-	r.Recorder.Eventf(backup, "Normal", "ReStarting",
+	r.Recorder.Eventf(backup, "Normal", events.BackupRestarting,
 		"Restarted backup for cluster %v on instance %v", cluster.Name, pod.Name)
 
 	return false, nil
@@ -324,7 +346,7 @@ func (r *BackupReconciler) reconcileSnapshotBackup(
 		r.Recorder.Eventf(
 			backup,
 			"Warning",
-			"FindingPod",
+			events.FindingPod,
 			"Couldn't find target pod %s, will retry in 30 seconds",
 			cluster.Status.TargetPrimary,
 		)
@@ -340,7 +362,7 @@ func (r *BackupReconciler) reconcileSnapshotBackup(
 	}
 	if err != nil {
 		tryFlagBackupAsFailed(ctx, r.Client, backup, fmt.Errorf("while getting pod: %w", err))
-		r.Recorder.Eventf(backup, "Warning", "FindingPod", "Error getting target pod: %s",
+		r.Recorder.Eventf(backup, "Warning", events.FindingPod, "Error getting target pod: %s",
 			cluster.Status.TargetPrimary)
 		return &ctrl.Result{}, nil
 	}
@@ -404,7 +426,7 @@ func (r *BackupReconciler) reconcileSnapshotBackup(
 			contextLogger.Error(errCond, "Error while updating backup condition (backup snapshot failed)")
 		}
 
-		r.Recorder.Eventf(backup, "Warning", "Error", "snapshot backup failed: %v", err)
+		r.Recorder.Eventf(backup, "Warning", events.BackupError, "snapshot backup failed: %v", err)
 		tryFlagBackupAsFailed(ctx, r.Client, backup, fmt.Errorf("can't execute snapshot backup: %w", err))
 		return nil, volumesnapshot.EnsurePodIsUnfenced(ctx, r.Client, r.Recorder, cluster, backup, targetPod)
 	}
@@ -518,7 +540,37 @@ func (r *BackupReconciler) getBackupTargetPod(ctx context.Context,
 		backupTarget = backup.Spec.Target
 	}
 	postgresqlStatusList := r.instanceStatusClient.GetStatusFromInstances(ctx, pods)
-	for _, item := range postgresqlStatusList.Items {
+	if pod := electBackupTargetPod(ctx, backupTarget, postgresqlStatusList); pod != nil {
+		return pod, nil
+	}
+
+	contextLogger.Debug("No ready instances found as target for backup, defaulting to primary")
+
+	var pod corev1.Pod
+	err = r.Get(ctx, client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.TargetPrimary,
+	}, &pod)
+
+	return &pod, err
+}
+
+// electBackupTargetPod chooses, between the instances in the passed status list,
+// the one that should run the backup according to the target policy.
+// When a standby is preferred, instances whose status could not be collected are
+// discarded and standbys streaming from the primary are preferred to the ones that
+// are only fetching WALs from the archive, as the latter may be lagging behind.
+// The list is expected to be sorted, so that the most up-to-date standby comes first.
+// Returns nil if no suitable instance is found
+func electBackupTargetPod(
+	ctx context.Context,
+	backupTarget apiv1.BackupTarget,
+	statusList postgresSpec.PostgresqlStatusList,
+) *corev1.Pod {
+	contextLogger := log.FromContext(ctx)
+
+	var fallbackStandby *corev1.Pod
+	for _, item := range statusList.Items {
 		if !item.IsPodReady {
 			contextLogger.Debug("Instance not ready, discarded as target for backup",
 				"pod", item.Pod.Name)
@@ -529,26 +581,34 @@ func (r *BackupReconciler) getBackupTargetPod(ctx context.Context,
 			if item.IsPrimary {
 				contextLogger.Debug("Primary Instance is elected as backup target",
 					"instance", item.Pod.Name)
-				return item.Pod, nil
+				return item.Pod
 			}
 		case apiv1.BackupTargetStandby, "":
-			if !item.IsPrimary {
+			if item.IsPrimary {
+				continue
+			}
+			if item.Error != nil {
+				contextLogger.Debug("Unable to get the status of the instance, discarded as target for backup",
+					"pod", item.Pod.Name, "error", item.Error)
+				continue
+			}
+			if item.IsWalReceiverActive {
 				contextLogger.Debug("Standby Instance is elected as backup target",
 					"instance", item.Pod.Name)
-				return item.Pod, nil
+				return item.Pod
+			}
+			if fallbackStandby == nil {
+				fallbackStandby = item.Pod
 			}
 		}
 	}
 
-	contextLogger.Debug("No ready instances found as target for backup, defaulting to primary")
-
-	var pod corev1.Pod
-	err = r.Get(ctx, client.ObjectKey{
-		Namespace: cluster.Namespace,
-		Name:      cluster.Status.TargetPrimary,
-	}, &pod)
+	if fallbackStandby != nil {
+		contextLogger.Debug("No streaming standby found, electing a non-streaming one as backup target",
+			"instance", fallbackStandby.Name)
+	}
 
-	return &pod, err
+	return fallbackStandby
 }
 
 // startInstanceManagerBackup request a backup in a Pod and marks the backup started
@@ -624,6 +684,7 @@ func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 
 	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Backup{}).
+		Owns(&batchv1.Job{}).
 		Watches(&apiv1.Cluster{},
 			handler.EnqueueRequestsFromMapFunc(r.mapClustersToBackup()),
 			builder.WithPredicates(clustersWithBackupPredicate),
@@ -638,7 +699,7 @@ func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 	// TODO: allow concurrent reconciliations when the hot snapshot backup reconciler
 	// will allow that
 	controllerBuilder = controllerBuilder.WithOptions(controller.Options{MaxConcurrentReconciles: 1})
-	return controllerBuilder.Complete(r)
+	return controllerBuilder.Complete(withSharding(withReconcileMetrics("backup", r)))
 }
 
 func tryFlagBackupAsFailed(
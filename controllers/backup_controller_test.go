@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	volumesnapshot "github.com/kubernetes-csi/external-snapshotter/client/v7/apis/volumesnapshot/v1"
@@ -28,6 +29,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -159,6 +161,73 @@ var _ = Describe("backup_controller barmanObjectStore unit tests", func() {
 	})
 })
 
+var _ = Describe("backup target election", func() {
+	newStatus := func(name string, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			},
+			IsPrimary:           isPrimary,
+			IsPodReady:          true,
+			IsWalReceiverActive: !isPrimary,
+		}
+	}
+
+	var statusList postgres.PostgresqlStatusList
+
+	BeforeEach(func() {
+		statusList = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				newStatus("cluster-example-2", false),
+				newStatus("cluster-example-3", false),
+			},
+		}
+	})
+
+	It("elects the primary when requested", func(ctx context.Context) {
+		pod := electBackupTargetPod(ctx, apiv1.BackupTargetPrimary, statusList)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-1"))
+	})
+
+	It("elects the first ready standby by default", func(ctx context.Context) {
+		pod := electBackupTargetPod(ctx, "", statusList)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-2"))
+
+		statusList.Items[1].IsPodReady = false
+		pod = electBackupTargetPod(ctx, apiv1.BackupTargetStandby, statusList)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("discards the standbys whose status is not available", func(ctx context.Context) {
+		statusList.Items[1].Error = errors.New("connection refused")
+		pod := electBackupTargetPod(ctx, apiv1.BackupTargetStandby, statusList)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("prefers streaming standbys", func(ctx context.Context) {
+		statusList.Items[1].IsWalReceiverActive = false
+		pod := electBackupTargetPod(ctx, apiv1.BackupTargetStandby, statusList)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-3"))
+
+		statusList.Items[2].IsWalReceiverActive = false
+		pod = electBackupTargetPod(ctx, apiv1.BackupTargetStandby, statusList)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-2"))
+	})
+
+	It("returns nil when no standby is suitable", func(ctx context.Context) {
+		statusList.Items[1].IsPodReady = false
+		statusList.Items[2].Error = errors.New("connection refused")
+		Expect(electBackupTargetPod(ctx, apiv1.BackupTargetStandby, statusList)).To(BeNil())
+	})
+})
+
 var _ = Describe("backup_controller volumeSnapshot unit tests", func() {
 	When("there's a running backup", func() {
 		It("prevents concurrent backups", func() {
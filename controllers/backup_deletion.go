@@ -0,0 +1,177 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// deleteBackupInInstance asks the instance manager running in the
+// given Pod to remove the base backup from the object store
+var deleteBackupInInstance = func(ctx context.Context, pod *corev1.Pod, backup *apiv1.Backup) error {
+	config := ctrl.GetConfigOrDie()
+	clientInterface := kubernetes.NewForConfigOrDie(config)
+
+	stdout, stderr, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		config,
+		*pod,
+		specs.PostgresContainerName,
+		nil,
+		"/controller/manager",
+		"backup",
+		"--delete",
+		backup.GetName(),
+	)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "executing backup deletion", "stdout", stdout, "stderr", stderr)
+	}
+	return err
+}
+
+// reconcileBackupFinalizer adds the finalizer to the backups whose base
+// backup should be removed from the object store together with them, and
+// carries out the removal when they are being deleted. A non-nil result
+// means that the reconciliation loop must stop here
+func (r *BackupReconciler) reconcileBackupFinalizer(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (*ctrl.Result, error) {
+	origBackup := backup.DeepCopy()
+
+	if backup.DeletionTimestamp.IsZero() {
+		if backup.Spec.DeletionPolicy != apiv1.BackupDeletionPolicyDelete ||
+			!controllerutil.AddFinalizer(backup, apiv1.BackupFinalizerName) {
+			return nil, nil
+		}
+		return nil, r.Patch(ctx, backup, client.MergeFrom(origBackup))
+	}
+
+	if !controllerutil.ContainsFinalizer(backup, apiv1.BackupFinalizerName) {
+		return &ctrl.Result{}, nil
+	}
+
+	result, err := r.deleteBackupFromObjectStore(ctx, backup)
+	if err != nil || result != nil {
+		return result, err
+	}
+
+	controllerutil.RemoveFinalizer(backup, apiv1.BackupFinalizerName)
+	return &ctrl.Result{}, r.Patch(ctx, backup, client.MergeFrom(origBackup))
+}
+
+// deleteBackupFromObjectStore removes the base backup from the object store
+// through the instance manager of the current primary. A nil result
+// without errors means that the finalizer can be removed
+func (r *BackupReconciler) deleteBackupFromObjectStore(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if running, err := r.isBackupStillRunning(ctx, backup); err != nil || running {
+		if running {
+			contextLogger.Info("Waiting for the backup to end before removing it from the object store",
+				"phase", backup.Status.Phase)
+		}
+		return &ctrl.Result{RequeueAfter: 30 * time.Second}, err
+	}
+
+	if backup.Status.Phase != apiv1.BackupPhaseCompleted || backup.Status.BackupID == "" {
+		contextLogger.Info("Backup not completed, nothing to remove from the object store")
+		return nil, nil
+	}
+
+	var cluster apiv1.Cluster
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.Cluster.Name}, &cluster)
+	if apierrs.IsNotFound(err) {
+		r.Recorder.Eventf(backup, "Warning", events.BackupRetained,
+			"Unknown cluster %v, the base backup has been retained in the object store",
+			backup.Spec.Cluster.Name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.Spec.Backup == nil || !cluster.Spec.Backup.AllowBackupDeletion {
+		r.Recorder.Event(backup, "Warning", events.BackupRetained,
+			"Backup deletion is not allowed by the cluster, "+
+				"the base backup has been retained in the object store")
+		return nil, nil
+	}
+
+	var pod corev1.Pod
+	err = r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: cluster.Status.CurrentPrimary}, &pod)
+	if apierrs.IsNotFound(err) || (err == nil && !utils.IsPodReady(pod)) {
+		contextLogger.Info("Waiting for the primary instance to remove the base backup",
+			"primary", cluster.Status.CurrentPrimary)
+		return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deleteBackupInInstance(ctx, &pod, backup); err != nil {
+		r.Recorder.Eventf(backup, "Warning", events.BackupDeletionFailed,
+			"Error removing the base backup from the object store, will retry in 30 seconds: %s",
+			err.Error())
+		return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	contextLogger.Info("Base backup removed from the object store", "backupID", backup.Status.BackupID)
+	r.Recorder.Eventf(backup, "Normal", events.BackupDeleted,
+		"Base backup %s removed from the object store", backup.Status.BackupID)
+	return nil, nil
+}
+
+// isBackupStillRunning tells whether the backup has been started and the
+// instance taking it is still there, meaning that it can still upload
+// objects to the object store
+func (r *BackupReconciler) isBackupStillRunning(ctx context.Context, backup *apiv1.Backup) (bool, error) {
+	switch backup.Status.Phase {
+	case apiv1.BackupPhaseStarted, apiv1.BackupPhaseRunning, apiv1.BackupPhaseFinalizing:
+	default:
+		return false, nil
+	}
+
+	if backup.Status.InstanceID == nil {
+		return false, nil
+	}
+
+	var pod corev1.Pod
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Status.InstanceID.PodName}, &pod)
+	if apierrs.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
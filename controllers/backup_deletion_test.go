@@ -0,0 +1,223 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup deletion", func() {
+	var (
+		cluster        *apiv1.Cluster
+		backup         *apiv1.Backup
+		primary        *corev1.Pod
+		deletedBackups []string
+		deletionError  error
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{AllowBackupDeletion: true},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		primary = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "backup-example",
+				Namespace:         "default",
+				Finalizers:        []string{apiv1.BackupFinalizerName},
+				DeletionTimestamp: ptr.To(metav1.Now()),
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster:        apiv1.LocalObjectReference{Name: cluster.Name},
+				Method:         apiv1.BackupMethodBarmanObjectStore,
+				DeletionPolicy: apiv1.BackupDeletionPolicyDelete,
+			},
+			Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted, BackupID: "20240101T000000"},
+		}
+
+		deletedBackups = nil
+		deletionError = nil
+		originalDeleteBackupInInstance := deleteBackupInInstance
+		deleteBackupInInstance = func(_ context.Context, _ *corev1.Pod, backup *apiv1.Backup) error {
+			deletedBackups = append(deletedBackups, backup.Name)
+			return deletionError
+		}
+		DeferCleanup(func() {
+			deleteBackupInInstance = originalDeleteBackupInInstance
+		})
+	})
+
+	newReconciler := func(objects ...client.Object) *BackupReconciler {
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			WithStatusSubresource(&apiv1.Backup{}).
+			Build()
+		return &BackupReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	expectBackupGone := func(ctx context.Context, r *BackupReconciler) {
+		var updatedBackup apiv1.Backup
+		err := r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	}
+
+	It("adds the finalizer when the deletion policy is delete", func(ctx SpecContext) {
+		backup.DeletionTimestamp = nil
+		backup.Finalizers = nil
+		r := newReconciler(cluster, backup)
+
+		result, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Finalizers).To(ContainElement(apiv1.BackupFinalizerName))
+	})
+
+	It("doesn't add the finalizer when the deletion policy is retain", func(ctx SpecContext) {
+		backup.DeletionTimestamp = nil
+		backup.Finalizers = nil
+		backup.Spec.DeletionPolicy = apiv1.BackupDeletionPolicyRetain
+		r := newReconciler(cluster, backup)
+
+		result, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Finalizers).To(BeEmpty())
+	})
+
+	It("removes the base backup before releasing the Backup", func(ctx SpecContext) {
+		r := newReconciler(cluster, primary, backup)
+
+		result, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(deletedBackups).To(ConsistOf(backup.Name))
+		expectBackupGone(ctx, r)
+	})
+
+	It("retains the base backup when the cluster doesn't allow deletion", func(ctx SpecContext) {
+		cluster.Spec.Backup.AllowBackupDeletion = false
+		r := newReconciler(cluster, primary, backup)
+
+		_, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deletedBackups).To(BeEmpty())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("BackupRetained")))
+		expectBackupGone(ctx, r)
+	})
+
+	It("doesn't touch the object store for backups that are not completed", func(ctx SpecContext) {
+		backup.Status.Phase = apiv1.BackupPhaseFailed
+		r := newReconciler(cluster, primary, backup)
+
+		_, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deletedBackups).To(BeEmpty())
+		expectBackupGone(ctx, r)
+	})
+
+	It("waits for a running backup to end", func(ctx SpecContext) {
+		backup.Status.Phase = apiv1.BackupPhaseRunning
+		backup.Status.BackupID = ""
+		backup.Status.InstanceID = &apiv1.InstanceID{PodName: primary.Name}
+		r := newReconciler(cluster, primary, backup)
+
+		result, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+		Expect(deletedBackups).To(BeEmpty())
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Finalizers).To(ContainElement(apiv1.BackupFinalizerName))
+	})
+
+	It("releases a running backup whose instance is gone", func(ctx SpecContext) {
+		backup.Status.Phase = apiv1.BackupPhaseRunning
+		backup.Status.InstanceID = &apiv1.InstanceID{PodName: "cluster-example-2"}
+		r := newReconciler(cluster, primary, backup)
+
+		_, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deletedBackups).To(BeEmpty())
+		expectBackupGone(ctx, r)
+	})
+
+	It("waits for the primary to be ready", func(ctx SpecContext) {
+		primary.Status.Conditions = nil
+		r := newReconciler(cluster, primary, backup)
+
+		result, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+		Expect(deletedBackups).To(BeEmpty())
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Finalizers).To(ContainElement(apiv1.BackupFinalizerName))
+	})
+
+	It("keeps the finalizer when the removal fails", func(ctx SpecContext) {
+		deletionError = errors.New("boom")
+		r := newReconciler(cluster, primary, backup)
+
+		result, err := r.reconcileBackupFinalizer(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("BackupDeletionFailed")))
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Finalizers).To(ContainElement(apiv1.BackupFinalizerName))
+	})
+})
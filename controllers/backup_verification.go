@@ -0,0 +1,182 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileBackupVerification starts the job verifying a completed backup,
+// when requested, and records its outcome in the backup status
+func (r *BackupReconciler) reconcileBackupVerification(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (ctrl.Result, error) {
+	if backup.Spec.Verify == nil ||
+		backup.Spec.Method != apiv1.BackupMethodBarmanObjectStore ||
+		backup.Status.Verification.IsDone() {
+		return ctrl.Result{}, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	var job batchv1.Job
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      specs.GetBackupVerificationJobName(backup.Name),
+	}, &job)
+	if apierrs.IsNotFound(err) {
+		if backup.Status.Verification != nil {
+			return ctrl.Result{}, r.setBackupVerificationOutcome(ctx, backup,
+				apiv1.BackupVerificationPhaseFailed, "the verification job has been deleted")
+		}
+		return ctrl.Result{}, r.startBackupVerification(ctx, backup)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case utils.JobHasOneCompletion(job):
+		contextLogger.Info("Backup verification succeeded", "job", job.Name)
+		r.Recorder.Event(backup, "Normal", events.VerificationSucceeded, "Backup verified")
+		return ctrl.Result{}, r.setBackupVerificationOutcome(ctx, backup,
+			apiv1.BackupVerificationPhaseSucceeded, "")
+
+	case utils.JobHasFailed(job):
+		contextLogger.Info("Backup verification failed", "job", job.Name)
+		r.Recorder.Eventf(backup, "Warning", events.VerificationFailed,
+			"Backup verification failed, check the logs of job %s", job.Name)
+		return ctrl.Result{}, r.setBackupVerificationOutcome(ctx, backup,
+			apiv1.BackupVerificationPhaseFailed,
+			fmt.Sprintf("the verification job %s failed, check its logs for details", job.Name))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// startBackupVerification creates the job verifying the passed backup
+func (r *BackupReconciler) startBackupVerification(ctx context.Context, backup *apiv1.Backup) error {
+	var cluster apiv1.Cluster
+	if err := r.Get(ctx, client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      backup.Spec.Cluster.Name,
+	}, &cluster); err != nil {
+		if apierrs.IsNotFound(err) {
+			return r.setBackupVerificationOutcome(ctx, backup,
+				apiv1.BackupVerificationPhaseFailed,
+				fmt.Sprintf("cluster %s not found", backup.Spec.Cluster.Name))
+		}
+		return err
+	}
+
+	if err := r.createBackupVerificationServiceAccount(ctx, &cluster, backup); err != nil {
+		return fmt.Errorf("while creating the backup verification service account: %w", err)
+	}
+
+	job := specs.CreateBackupVerificationJob(cluster, backup)
+	log.FromContext(ctx).Info("Starting backup verification", "job", job.Name)
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating the backup verification job: %w", err)
+	}
+	r.Recorder.Eventf(backup, "Normal", events.VerificationStarted, "Started backup verification job %s", job.Name)
+
+	origBackup := backup.DeepCopy()
+	backup.Status.Verification = &apiv1.BackupVerificationStatus{
+		Phase:     apiv1.BackupVerificationPhaseRunning,
+		JobName:   job.Name,
+		StartedAt: ptr.To(metav1.Now()),
+	}
+	return r.Status().Patch(ctx, backup, client.MergeFrom(origBackup))
+}
+
+// createBackupVerificationServiceAccount creates the service account of
+// the job verifying the passed backup, together with the role granting it
+// access only to what is needed to restore the backup. The image pull
+// secrets are the ones of the service account of the cluster
+func (r *BackupReconciler) createBackupVerificationServiceAccount(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) error {
+	var clusterServiceAccount corev1.ServiceAccount
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name},
+		&clusterServiceAccount); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	role := specs.CreateBackupVerificationRole(*cluster, backup)
+	serviceAccount := corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: role.Namespace,
+			Name:      role.Name,
+			Labels:    role.Labels,
+		},
+		ImagePullSecrets: clusterServiceAccount.ImagePullSecrets,
+	}
+	roleBinding := specs.CreateRoleBinding(role.ObjectMeta)
+
+	for _, objectMeta := range []*metav1.ObjectMeta{
+		&serviceAccount.ObjectMeta, &role.ObjectMeta, &roleBinding.ObjectMeta,
+	} {
+		utils.SetAsOwnedBy(objectMeta, backup.ObjectMeta, metav1.TypeMeta{
+			Kind:       apiv1.BackupKind,
+			APIVersion: apiv1.GroupVersion.String(),
+		})
+	}
+
+	for _, object := range []client.Object{&serviceAccount, &role, &roleBinding} {
+		if err := r.Create(ctx, object); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setBackupVerificationOutcome records the outcome of the verification
+// of the passed backup
+func (r *BackupReconciler) setBackupVerificationOutcome(
+	ctx context.Context,
+	backup *apiv1.Backup,
+	phase apiv1.BackupVerificationPhase,
+	errMessage string,
+) error {
+	origBackup := backup.DeepCopy()
+	if backup.Status.Verification == nil {
+		backup.Status.Verification = &apiv1.BackupVerificationStatus{}
+	}
+	backup.Status.Verification.Phase = phase
+	backup.Status.Verification.Error = errMessage
+	backup.Status.Verification.StoppedAt = ptr.To(metav1.Now())
+	return r.Status().Patch(ctx, backup, client.MergeFrom(origBackup))
+}
@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup verification", func() {
+	var (
+		cluster *apiv1.Cluster
+		backup  *apiv1.Backup
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: "default"},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+				Method:  apiv1.BackupMethodBarmanObjectStore,
+				Verify:  &apiv1.BackupVerificationConfiguration{Queries: []string{"SELECT 1"}},
+			},
+			Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted},
+		}
+	})
+
+	newReconciler := func(objects ...client.Object) *BackupReconciler {
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			WithStatusSubresource(&apiv1.Backup{}).
+			Build()
+		return &BackupReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	It("doesn't do anything if the verification is not requested", func(ctx SpecContext) {
+		backup.Spec.Verify = nil
+		r := newReconciler(cluster, backup)
+
+		_, err := r.reconcileBackupVerification(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+
+		var jobs batchv1.JobList
+		Expect(r.List(ctx, &jobs)).To(Succeed())
+		Expect(jobs.Items).To(BeEmpty())
+	})
+
+	It("starts the verification job", func(ctx SpecContext) {
+		clusterServiceAccount := &corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: cluster.Name, Namespace: cluster.Namespace},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+		}
+		r := newReconciler(cluster, backup, clusterServiceAccount)
+
+		_, err := r.reconcileBackupVerification(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+
+		var job batchv1.Job
+		Expect(r.Get(ctx, client.ObjectKey{
+			Namespace: backup.Namespace,
+			Name:      specs.GetBackupVerificationJobName(backup.Name),
+		}, &job)).To(Succeed())
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Status.Verification).ToNot(BeNil())
+		Expect(updatedBackup.Status.Verification.Phase).To(Equal(apiv1.BackupVerificationPhaseRunning))
+		Expect(updatedBackup.Status.Verification.JobName).To(Equal(job.Name))
+
+		By("running it with a dedicated service account", func() {
+			key := client.ObjectKey{Namespace: backup.Namespace, Name: job.Spec.Template.Spec.ServiceAccountName}
+			Expect(key.Name).ToNot(Equal(cluster.Name))
+
+			var serviceAccount corev1.ServiceAccount
+			Expect(r.Get(ctx, key, &serviceAccount)).To(Succeed())
+			Expect(serviceAccount.ImagePullSecrets).To(Equal(clusterServiceAccount.ImagePullSecrets))
+			Expect(serviceAccount.OwnerReferences).To(HaveLen(1))
+			Expect(r.Get(ctx, key, &rbacv1.Role{})).To(Succeed())
+			Expect(r.Get(ctx, key, &rbacv1.RoleBinding{})).To(Succeed())
+		})
+	})
+
+	It("records the outcome of the verification job", func(ctx SpecContext) {
+		backup.Status.Verification = &apiv1.BackupVerificationStatus{
+			Phase:   apiv1.BackupVerificationPhaseRunning,
+			JobName: specs.GetBackupVerificationJobName(backup.Name),
+		}
+		job := specs.CreateBackupVerificationJob(*cluster, backup)
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+		}
+		r := newReconciler(cluster, backup, job)
+
+		_, err := r.reconcileBackupVerification(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Status.Verification.Phase).To(Equal(apiv1.BackupVerificationPhaseFailed))
+		Expect(updatedBackup.Status.Verification.Error).ToNot(BeEmpty())
+		Expect(updatedBackup.Status.Verification.IsDone()).To(BeTrue())
+	})
+})
@@ -0,0 +1,246 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// BenchmarkReconciler reconciles a Benchmark object
+type BenchmarkReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// APIReader reads directly from the API server, and is used to
+	// confirm that a job which is missing from the cache has really
+	// been deleted
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=benchmarks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=benchmarks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile runs the job of a benchmark and collects its results
+func (r *BenchmarkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var benchmark apiv1.Benchmark
+	if err := r.Get(ctx, req.NamespacedName, &benchmark); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot get the benchmark resource: %w", err)
+	}
+
+	if benchmark.IsDone() {
+		return ctrl.Result{}, nil
+	}
+
+	var job batchv1.Job
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: benchmark.Namespace,
+		Name:      specs.GetBenchmarkJobName(benchmark.Name),
+	}, &job)
+	if apierrs.IsNotFound(err) && benchmark.Status.JobName != "" {
+		// The cache may not contain a job that has just been created,
+		// so we ask the API server before declaring it deleted
+		err = r.APIReader.Get(ctx, client.ObjectKey{
+			Namespace: benchmark.Namespace,
+			Name:      benchmark.Status.JobName,
+		}, &job)
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, r.stopBenchmark(ctx, &benchmark,
+				apiv1.BenchmarkPhaseFailed, "the benchmark job has been deleted", "")
+		}
+	}
+	if apierrs.IsNotFound(err) {
+		return ctrl.Result{}, r.startBenchmark(ctx, &benchmark)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case utils.JobHasOneCompletion(job):
+		output, err := r.getBenchmarkOutput(ctx, &job)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		contextLogger.Info("Benchmark completed", "job", job.Name)
+		r.Recorder.Event(&benchmark, "Normal", events.BenchmarkCompleted, "Benchmark completed")
+		return ctrl.Result{}, r.stopBenchmark(ctx, &benchmark, apiv1.BenchmarkPhaseCompleted, "", output)
+
+	case utils.JobHasFailed(job):
+		output, err := r.getBenchmarkOutput(ctx, &job)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		contextLogger.Info("Benchmark failed", "job", job.Name)
+		r.Recorder.Eventf(&benchmark, "Warning", events.BenchmarkFailed,
+			"Benchmark failed, check the logs of job %s", job.Name)
+		return ctrl.Result{}, r.stopBenchmark(ctx, &benchmark, apiv1.BenchmarkPhaseFailed,
+			fmt.Sprintf("the benchmark job %s failed, check its logs for details", job.Name), output)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// startBenchmark creates the resources needed to run the passed benchmark
+func (r *BenchmarkReconciler) startBenchmark(ctx context.Context, benchmark *apiv1.Benchmark) error {
+	var job *batchv1.Job
+	switch {
+	case benchmark.Spec.Pgbench != nil:
+		var cluster apiv1.Cluster
+		if err := r.Get(ctx, client.ObjectKey{
+			Namespace: benchmark.Namespace,
+			Name:      benchmark.Spec.Pgbench.Cluster.Name,
+		}, &cluster); err != nil {
+			if apierrs.IsNotFound(err) {
+				return r.stopBenchmark(ctx, benchmark, apiv1.BenchmarkPhaseFailed,
+					fmt.Sprintf("cluster %s not found", benchmark.Spec.Pgbench.Cluster.Name), "")
+			}
+			return err
+		}
+
+		// pgbench connects with the credentials of the application user,
+		// and its pod would never start without them
+		var secret corev1.Secret
+		if err := r.Get(ctx, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      cluster.GetApplicationSecretName(),
+		}, &secret); err != nil {
+			if apierrs.IsNotFound(err) {
+				return r.stopBenchmark(ctx, benchmark, apiv1.BenchmarkPhaseFailed,
+					fmt.Sprintf("application secret %s not found", cluster.GetApplicationSecretName()), "")
+			}
+			return err
+		}
+		job = specs.CreatePgbenchJob(cluster, benchmark)
+
+	case benchmark.Spec.Fio != nil:
+		pvc := specs.CreateBenchmarkPVC(benchmark)
+		if err := r.Create(ctx, pvc); err != nil && !apierrs.IsAlreadyExists(err) {
+			return fmt.Errorf("while creating the benchmark volume: %w", err)
+		}
+		job = specs.CreateFioJob(benchmark)
+
+	default:
+		return r.stopBenchmark(ctx, benchmark, apiv1.BenchmarkPhaseFailed,
+			"no benchmark tool has been configured", "")
+	}
+
+	log.FromContext(ctx).Info("Starting benchmark", "job", job.Name)
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating the benchmark job: %w", err)
+	}
+	r.Recorder.Eventf(benchmark, "Normal", events.BenchmarkStarted, "Started benchmark job %s", job.Name)
+
+	origBenchmark := benchmark.DeepCopy()
+	benchmark.Status.Phase = apiv1.BenchmarkPhaseRunning
+	benchmark.Status.JobName = job.Name
+	benchmark.Status.StartedAt = ptr.To(metav1.Now())
+	return r.Status().Patch(ctx, benchmark, client.MergeFrom(origBenchmark))
+}
+
+// stopBenchmark records the outcome of the passed benchmark, together
+// with the results parsed from the output of the benchmark tool, and
+// releases the volume used by fio
+func (r *BenchmarkReconciler) stopBenchmark(
+	ctx context.Context,
+	benchmark *apiv1.Benchmark,
+	phase apiv1.BenchmarkPhase,
+	message string,
+	output string,
+) error {
+	if benchmark.Spec.Fio != nil {
+		pvc := specs.CreateBenchmarkPVC(benchmark)
+		if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting the benchmark volume: %w", err)
+		}
+	}
+
+	origBenchmark := benchmark.DeepCopy()
+	benchmark.Status.Phase = phase
+	benchmark.Status.Message = message
+	benchmark.Status.Output = output
+	benchmark.Status.StoppedAt = ptr.To(metav1.Now())
+	if phase == apiv1.BenchmarkPhaseCompleted {
+		switch {
+		case benchmark.Spec.Pgbench != nil:
+			benchmark.Status.Pgbench = parsePgbenchOutput(output)
+		case benchmark.Spec.Fio != nil:
+			benchmark.Status.Fio = parseFioOutput(output)
+		}
+	}
+	return r.Status().Patch(ctx, benchmark, client.MergeFrom(origBenchmark))
+}
+
+// getBenchmarkOutput gets the output of the benchmark tool from the
+// termination message of the pod of the passed job
+func (r *BenchmarkReconciler) getBenchmarkOutput(ctx context.Context, job *batchv1.Job) (string, error) {
+	var pods corev1.PodList
+	if err := r.List(
+		ctx,
+		&pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{utils.BenchmarkNameLabelName: job.Labels[utils.BenchmarkNameLabelName]},
+	); err != nil {
+		return "", fmt.Errorf("while listing the benchmark pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == "benchmark" && containerStatus.State.Terminated != nil {
+				return containerStatus.State.Terminated.Message, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// SetupWithManager setup this controller inside the controller manager
+func (r *BenchmarkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Benchmark{}).
+		Owns(&batchv1.Job{}).
+		Complete(withSharding(withReconcileMetrics("benchmark", r)))
+}
@@ -0,0 +1,268 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const pgbenchOutput = `pgbench (16.2)
+transaction type: <builtin: TPC-B (sort of)>
+scaling factor: 10
+query mode: simple
+number of clients: 4
+number of threads: 1
+maximum number of tries: 1
+duration: 30 s
+number of transactions actually processed: 24661
+number of failed transactions: 0 (0.000%)
+latency average = 4.865 ms
+initial connection time = 12.420 ms
+tps = 822.053614 (without initial connection time)
+`
+
+var _ = Describe("benchmark results", func() {
+	It("parses the output of pgbench", func() {
+		Expect(parsePgbenchOutput(pgbenchOutput)).To(Equal(&apiv1.PgbenchResults{
+			Transactions:       24661,
+			FailedTransactions: 0,
+			LatencyAverage:     "4.865",
+			TPS:                "822.053614",
+		}))
+	})
+
+	It("parses the output of older pgbench versions", func() {
+		output := `number of transactions actually processed: 100/100
+latency average = 1.5 ms
+tps = 640.1 (including connections establishing)
+tps = 655.2 (excluding connections establishing)
+`
+		Expect(parsePgbenchOutput(output)).To(Equal(&apiv1.PgbenchResults{
+			Transactions:   100,
+			LatencyAverage: "1.5",
+			TPS:            "655.2",
+		}))
+	})
+
+	It("parses the terse output of fio", func() {
+		line := func(readBandwidth, readIOPS, writeBandwidth, writeIOPS string) string {
+			fields := make([]string, 130)
+			for i := range fields {
+				fields[i] = "0"
+			}
+			fields[0] = "3"
+			fields[6], fields[7] = readBandwidth, readIOPS
+			fields[47], fields[48] = writeBandwidth, writeIOPS
+			return strings.Join(fields, ";")
+		}
+		output := "fio: some warning\n" + line("8000", "1000", "3200", "400") + "\n" + line("8000", "1000.4", "0", "0")
+
+		Expect(parseFioOutput(output)).To(Equal(&apiv1.FioResults{
+			ReadBandwidth:  16000,
+			ReadIOPS:       2000,
+			WriteBandwidth: 3200,
+			WriteIOPS:      400,
+		}))
+	})
+
+	It("doesn't report results without a summary", func() {
+		Expect(parsePgbenchOutput("connection refused")).To(BeNil())
+		Expect(parseFioOutput("fio: unknown option")).To(BeNil())
+	})
+})
+
+var _ = Describe("benchmark controller", func() {
+	var (
+		cluster    *apiv1.Cluster
+		appSecret  *corev1.Secret
+		benchmark  *apiv1.Benchmark
+		fakeClient k8client.Client
+		r          *BenchmarkReconciler
+	)
+
+	reconcileBenchmark := func(ctx SpecContext) *apiv1.Benchmark {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8client.ObjectKeyFromObject(benchmark)})
+		Expect(err).ToNot(HaveOccurred())
+
+		var result apiv1.Benchmark
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(benchmark), &result)).To(Succeed())
+		return &result
+	}
+
+	newClient := func(objects ...k8client.Object) {
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			WithStatusSubresource(&apiv1.Benchmark{}).
+			Build()
+		r = &BenchmarkReconciler{
+			Client:    fakeClient,
+			Recorder:  record.NewFakeRecorder(120),
+			APIReader: fakeClient,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		appSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-app", Namespace: "default"},
+		}
+		benchmark = &apiv1.Benchmark{
+			ObjectMeta: metav1.ObjectMeta{Name: "tpcb", Namespace: "default"},
+			Spec: apiv1.BenchmarkSpec{
+				Pgbench: &apiv1.PgbenchConfiguration{
+					Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+				},
+			},
+		}
+	})
+
+	It("fails when the cluster doesn't exist", func(ctx SpecContext) {
+		newClient(benchmark)
+
+		result := reconcileBenchmark(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseFailed))
+		Expect(result.Status.Message).To(ContainSubstring("not found"))
+	})
+
+	It("fails when the application secret doesn't exist", func(ctx SpecContext) {
+		newClient(cluster, benchmark)
+
+		result := reconcileBenchmark(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseFailed))
+		Expect(result.Status.Message).To(Equal("application secret cluster-example-app not found"))
+
+		var job batchv1.Job
+		err := fakeClient.Get(ctx, k8client.ObjectKey{Namespace: "default", Name: "tpcb-benchmark"}, &job)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("doesn't fail when the job is missing from the cache only", func(ctx SpecContext) {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "tpcb-benchmark", Namespace: "default"},
+		}
+		benchmark.Status = apiv1.BenchmarkStatus{
+			Phase:   apiv1.BenchmarkPhaseRunning,
+			JobName: job.Name,
+		}
+		newClient(cluster, appSecret, benchmark, job)
+		r.Client = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, appSecret, benchmark).
+			Build()
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8client.ObjectKeyFromObject(benchmark)})
+		Expect(err).ToNot(HaveOccurred())
+
+		var result apiv1.Benchmark
+		Expect(r.Client.Get(ctx, k8client.ObjectKeyFromObject(benchmark), &result)).To(Succeed())
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseRunning))
+	})
+
+	It("fails when the job has been deleted", func(ctx SpecContext) {
+		benchmark.Status = apiv1.BenchmarkStatus{
+			Phase:   apiv1.BenchmarkPhaseRunning,
+			JobName: "tpcb-benchmark",
+		}
+		newClient(cluster, appSecret, benchmark)
+
+		result := reconcileBenchmark(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseFailed))
+		Expect(result.Status.Message).To(Equal("the benchmark job has been deleted"))
+	})
+
+	It("runs pgbench and collects its results", func(ctx SpecContext) {
+		newClient(cluster, appSecret, benchmark)
+
+		result := reconcileBenchmark(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseRunning))
+		Expect(result.Status.JobName).To(Equal("tpcb-benchmark"))
+		Expect(result.Status.StartedAt).ToNot(BeNil())
+
+		var job batchv1.Job
+		Expect(fakeClient.Get(ctx, k8client.ObjectKey{Namespace: "default", Name: "tpcb-benchmark"}, &job)).
+			To(Succeed())
+		job.Status.Succeeded = 1
+		Expect(fakeClient.Status().Update(ctx, &job)).To(Succeed())
+		Expect(fakeClient.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tpcb-benchmark-abcde",
+				Namespace: "default",
+				Labels:    map[string]string{utils.BenchmarkNameLabelName: "tpcb"},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "benchmark",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{Message: pgbenchOutput},
+						},
+					},
+				},
+			},
+		})).To(Succeed())
+
+		result = reconcileBenchmark(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseCompleted))
+		Expect(result.Status.StoppedAt).ToNot(BeNil())
+		Expect(result.Status.Output).To(Equal(pgbenchOutput))
+		Expect(result.Status.Pgbench).ToNot(BeNil())
+		Expect(result.Status.Pgbench.TPS).To(Equal("822.053614"))
+	})
+
+	It("releases the fio volume when the benchmark fails", func(ctx SpecContext) {
+		benchmark.Spec = apiv1.BenchmarkSpec{Fio: &apiv1.FioConfiguration{}}
+		newClient(benchmark)
+
+		result := reconcileBenchmark(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseRunning))
+
+		var pvc corev1.PersistentVolumeClaim
+		pvcKey := k8client.ObjectKey{Namespace: "default", Name: "tpcb-benchmark"}
+		Expect(fakeClient.Get(ctx, pvcKey, &pvc)).To(Succeed())
+
+		var job batchv1.Job
+		Expect(fakeClient.Get(ctx, pvcKey, &job)).To(Succeed())
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+		}
+		Expect(fakeClient.Status().Update(ctx, &job)).To(Succeed())
+
+		result = reconcileBenchmark(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.BenchmarkPhaseFailed))
+		Expect(result.Status.Fio).To(BeNil())
+		Expect(apierrs.IsNotFound(fakeClient.Get(ctx, pvcKey, &pvc))).To(BeTrue())
+	})
+})
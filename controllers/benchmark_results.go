@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+var (
+	pgbenchTransactionsRegex       = regexp.MustCompile(`(?m)^number of transactions actually processed: (\d+)`)
+	pgbenchFailedTransactionsRegex = regexp.MustCompile(`(?m)^number of failed transactions: (\d+)`)
+	pgbenchLatencyRegex            = regexp.MustCompile(`(?m)^latency average = ([\d.]+) ms`)
+	pgbenchTPSRegex                = regexp.MustCompile(
+		`(?m)^tps = ([\d.]+) \((?:without initial connection time|excluding connections establishing)\)`)
+)
+
+// The positions of the fields of the version 3 of the fio terse output
+const (
+	fioTerseVersionField   = 0
+	fioReadBandwidthField  = 6
+	fioReadIOPSField       = 7
+	fioWriteBandwidthField = 47
+	fioWriteIOPSField      = 48
+)
+
+// parsePgbenchOutput parses the summary printed by pgbench at the end of
+// a run. Nil is returned when the output doesn't contain any result
+func parsePgbenchOutput(output string) *apiv1.PgbenchResults {
+	tps := findSubmatch(pgbenchTPSRegex, output)
+	if tps == "" {
+		return nil
+	}
+
+	results := &apiv1.PgbenchResults{
+		TPS:            tps,
+		LatencyAverage: findSubmatch(pgbenchLatencyRegex, output),
+	}
+	results.Transactions, _ = strconv.ParseInt(findSubmatch(pgbenchTransactionsRegex, output), 10, 64)
+	results.FailedTransactions, _ = strconv.ParseInt(findSubmatch(pgbenchFailedTransactionsRegex, output), 10, 64)
+
+	return results
+}
+
+// parseFioOutput parses the terse output of fio, summing the results of
+// every reported job. Nil is returned when the output doesn't contain
+// any result
+func parseFioOutput(output string) *apiv1.FioResults {
+	var results *apiv1.FioResults
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ";")
+		if len(fields) <= fioWriteIOPSField || fields[fioTerseVersionField] != "3" {
+			continue
+		}
+
+		if results == nil {
+			results = &apiv1.FioResults{}
+		}
+		results.ReadBandwidth += parseFioValue(fields[fioReadBandwidthField])
+		results.ReadIOPS += parseFioValue(fields[fioReadIOPSField])
+		results.WriteBandwidth += parseFioValue(fields[fioWriteBandwidthField])
+		results.WriteIOPS += parseFioValue(fields[fioWriteIOPSField])
+	}
+
+	return results
+}
+
+// parseFioValue parses a numeric field of the fio terse output, which
+// may be reported with decimals depending on the fio version
+func parseFioValue(value string) int64 {
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(math.Round(result))
+}
+
+// findSubmatch returns the first group matched by the passed regular
+// expression, or an empty string
+func findSubmatch(regex *regexp.Regexp, value string) string {
+	match := regex.FindStringSubmatch(value)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
@@ -0,0 +1,202 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// changeDataCaptureClusterRetryInterval is the time to wait before
+// checking again for a cluster that doesn't exist yet
+const changeDataCaptureClusterRetryInterval = 30 * time.Second
+
+// ChangeDataCaptureReconciler reconciles a ChangeDataCapture object, running the
+// bridge that publishes the changes. The replication slot consumed by the bridge
+// is managed by the instance manager of the primary
+type ChangeDataCaptureReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=changedatacaptures,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=changedatacaptures/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates and updates the deployment of the bridge of a ChangeDataCapture
+func (r *ChangeDataCaptureReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var cdc apiv1.ChangeDataCapture
+	if err := r.Get(ctx, req.NamespacedName, &cdc); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot get the change data capture resource: %w", err)
+	}
+
+	var cluster apiv1.Cluster
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: cdc.Namespace,
+		Name:      cdc.Spec.ClusterRef.Name,
+	}, &cluster)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	clusterExists := err == nil
+
+	// The deployment is removed by the garbage collector, while the
+	// replication slot is dropped by the instance manager of the primary
+	if !cdc.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.releaseFinalizer(ctx, &cdc, clusterExists && !cluster.IsReplica())
+	}
+
+	if !clusterExists {
+		contextLogger.Info("Cluster not found, waiting for it to be created",
+			"cluster", cdc.Spec.ClusterRef.Name)
+		return ctrl.Result{RequeueAfter: changeDataCaptureClusterRetryInterval}, nil
+	}
+
+	if specs.ChangeDataCaptureNeedsOffsetsPVC(&cdc) {
+		if err := r.reconcileOffsetsPVC(ctx, &cdc); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	deployment, err := r.reconcileDeployment(ctx, &cdc, &cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if cdc.Status.ReadyReplicas == deployment.Status.ReadyReplicas {
+		return ctrl.Result{}, nil
+	}
+	origCDC := cdc.DeepCopy()
+	cdc.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+	return ctrl.Result{}, r.Status().Patch(ctx, &cdc, client.MergeFrom(origCDC))
+}
+
+// releaseFinalizer removes the finalizer dropping the replication slot when
+// there is no primary able to drop it: the cluster has been deleted, or it is
+// a replica cluster, whose instances can't drop the slots
+func (r *ChangeDataCaptureReconciler) releaseFinalizer(
+	ctx context.Context,
+	cdc *apiv1.ChangeDataCapture,
+	hasPrimary bool,
+) error {
+	if hasPrimary || !controllerutil.ContainsFinalizer(cdc, apiv1.ChangeDataCaptureFinalizerName) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("No primary can drop the replication slot, removing the finalizer",
+		"cluster", cdc.Spec.ClusterRef.Name, "slotName", cdc.Spec.SlotName)
+	origCDC := cdc.DeepCopy()
+	controllerutil.RemoveFinalizer(cdc, apiv1.ChangeDataCaptureFinalizerName)
+	return r.Patch(ctx, cdc, client.MergeFrom(origCDC))
+}
+
+// reconcileOffsetsPVC creates the volume where the bridge stores its offsets,
+// if it doesn't exist yet
+func (r *ChangeDataCaptureReconciler) reconcileOffsetsPVC(ctx context.Context, cdc *apiv1.ChangeDataCapture) error {
+	generatedPVC := specs.CreateChangeDataCaptureOffsetsPVC(cdc)
+
+	var pvc corev1.PersistentVolumeClaim
+	err := r.Get(ctx, client.ObjectKeyFromObject(generatedPVC), &pvc)
+	if !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Creating the offsets volume of the change data capture bridge",
+		"pvc", generatedPVC.Name)
+	if err := r.Create(ctx, generatedPVC); err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating the offsets volume of the change data capture bridge: %w", err)
+	}
+	return nil
+}
+
+// reconcileDeployment creates the deployment of the bridge, or updates it
+// when the generated specification changed
+func (r *ChangeDataCaptureReconciler) reconcileDeployment(
+	ctx context.Context,
+	cdc *apiv1.ChangeDataCapture,
+	cluster *apiv1.Cluster,
+) (*appsv1.Deployment, error) {
+	contextLogger := log.FromContext(ctx)
+
+	generatedDeployment, err := specs.CreateChangeDataCaptureDeployment(cdc, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployment appsv1.Deployment
+	err = r.Get(ctx, client.ObjectKeyFromObject(generatedDeployment), &deployment)
+	if apierrs.IsNotFound(err) {
+		contextLogger.Info("Creating the change data capture bridge", "deployment", generatedDeployment.Name)
+		if err := r.Create(ctx, generatedDeployment); err != nil && !apierrs.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("while creating the change data capture bridge: %w", err)
+		}
+		r.Recorder.Eventf(cdc, "Normal", events.ChangeDataCaptureBridgeCreated,
+			"Created bridge deployment %s", generatedDeployment.Name)
+		return generatedDeployment, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if deployment.Annotations[utils.CNPGHashAnnotationName] ==
+		generatedDeployment.Annotations[utils.CNPGHashAnnotationName] {
+		return &deployment, nil
+	}
+
+	updatedDeployment := deployment.DeepCopy()
+	updatedDeployment.Spec = generatedDeployment.Spec
+	utils.MergeObjectsMetadata(updatedDeployment, generatedDeployment)
+
+	contextLogger.Info("Updating the change data capture bridge", "deployment", deployment.Name)
+	if err := r.Patch(ctx, updatedDeployment, client.MergeFrom(&deployment)); err != nil {
+		return nil, fmt.Errorf("while updating the change data capture bridge: %w", err)
+	}
+	r.Recorder.Eventf(cdc, "Normal", events.ChangeDataCaptureBridgeUpdated,
+		"Updated bridge deployment %s", deployment.Name)
+
+	return updatedDeployment, nil
+}
+
+// SetupWithManager setup this controller inside the controller manager
+func (r *ChangeDataCaptureReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.ChangeDataCapture{}).
+		Owns(&appsv1.Deployment{}).
+		Complete(withSharding(withReconcileMetrics("changedatacapture", r)))
+}
@@ -0,0 +1,175 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("change data capture controller", func() {
+	var (
+		cluster    *apiv1.Cluster
+		cdc        *apiv1.ChangeDataCapture
+		fakeClient k8client.Client
+		r          *ChangeDataCaptureReconciler
+	)
+
+	deploymentKey := k8client.ObjectKey{Namespace: "default", Name: "orders-cdc"}
+
+	reconcileChangeDataCapture := func(ctx SpecContext) ctrl.Result {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: k8client.ObjectKeyFromObject(cdc)})
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	newClient := func(objects ...k8client.Object) {
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			WithStatusSubresource(&apiv1.ChangeDataCapture{}, &appsv1.Deployment{}).
+			Build()
+		r = &ChangeDataCaptureReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		cdc = &apiv1.ChangeDataCapture{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+			Spec: apiv1.ChangeDataCaptureSpec{
+				ClusterRef:      apiv1.LocalObjectReference{Name: cluster.Name},
+				DBName:          "app",
+				SlotName:        "orders_cdc",
+				PublicationName: "orders_pub",
+				Credentials:     apiv1.LocalObjectReference{Name: "cdc-user"},
+				Sink: apiv1.ChangeDataCaptureSink{
+					Kafka: &apiv1.ChangeDataCaptureKafkaSink{BootstrapServers: []string{"kafka:9092"}},
+				},
+			},
+		}
+	})
+
+	It("waits for the cluster to be created", func(ctx SpecContext) {
+		newClient(cdc)
+
+		result := reconcileChangeDataCapture(ctx)
+		Expect(result.RequeueAfter).To(Equal(changeDataCaptureClusterRetryInterval))
+
+		var deployment appsv1.Deployment
+		Expect(fakeClient.Get(ctx, deploymentKey, &deployment)).ToNot(Succeed())
+	})
+
+	It("creates and updates the bridge deployment", func(ctx SpecContext) {
+		newClient(cluster, cdc)
+
+		reconcileChangeDataCapture(ctx)
+		var deployment appsv1.Deployment
+		Expect(fakeClient.Get(ctx, deploymentKey, &deployment)).To(Succeed())
+		originalHash := deployment.Annotations[utils.CNPGHashAnnotationName]
+		Expect(originalHash).ToNot(BeEmpty())
+
+		var current apiv1.ChangeDataCapture
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cdc), &current)).To(Succeed())
+		current.Spec.Image = "debezium/server:custom"
+		Expect(fakeClient.Update(ctx, &current)).To(Succeed())
+
+		reconcileChangeDataCapture(ctx)
+		Expect(fakeClient.Get(ctx, deploymentKey, &deployment)).To(Succeed())
+		Expect(deployment.Annotations[utils.CNPGHashAnnotationName]).ToNot(Equal(originalHash))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("debezium/server:custom"))
+	})
+
+	It("reports the ready replicas of the bridge", func(ctx SpecContext) {
+		newClient(cluster, cdc)
+
+		reconcileChangeDataCapture(ctx)
+		var deployment appsv1.Deployment
+		Expect(fakeClient.Get(ctx, deploymentKey, &deployment)).To(Succeed())
+		deployment.Status.ReadyReplicas = 1
+		Expect(fakeClient.Status().Update(ctx, &deployment)).To(Succeed())
+
+		reconcileChangeDataCapture(ctx)
+		var current apiv1.ChangeDataCapture
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cdc), &current)).To(Succeed())
+		Expect(current.Status.ReadyReplicas).To(BeEquivalentTo(1))
+	})
+
+	It("creates the volume storing the offsets when publishing to NATS", func(ctx SpecContext) {
+		cdc.Spec.Sink = apiv1.ChangeDataCaptureSink{
+			NATS: &apiv1.ChangeDataCaptureNATSSink{URL: "nats://nats:4222"},
+		}
+		newClient(cluster, cdc)
+
+		reconcileChangeDataCapture(ctx)
+		var pvc corev1.PersistentVolumeClaim
+		Expect(fakeClient.Get(ctx, k8client.ObjectKey{Namespace: "default", Name: "orders-cdc-offsets"}, &pvc)).
+			To(Succeed())
+	})
+
+	It("releases the finalizer when the cluster doesn't exist anymore", func(ctx SpecContext) {
+		cdc.Finalizers = []string{apiv1.ChangeDataCaptureFinalizerName}
+		cdc.DeletionTimestamp = ptr.To(metav1.Now())
+		newClient(cdc)
+
+		reconcileChangeDataCapture(ctx)
+		var current apiv1.ChangeDataCapture
+		err := fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cdc), &current)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("releases the finalizer of a replica cluster", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: true, Source: "origin"}
+		cdc.Finalizers = []string{apiv1.ChangeDataCaptureFinalizerName}
+		cdc.DeletionTimestamp = ptr.To(metav1.Now())
+		newClient(cluster, cdc)
+
+		reconcileChangeDataCapture(ctx)
+		var current apiv1.ChangeDataCapture
+		err := fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cdc), &current)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("leaves the finalizer to the primary", func(ctx SpecContext) {
+		cdc.Finalizers = []string{apiv1.ChangeDataCaptureFinalizerName}
+		cdc.DeletionTimestamp = ptr.To(metav1.Now())
+		newClient(cluster, cdc)
+
+		reconcileChangeDataCapture(ctx)
+		var current apiv1.ChangeDataCapture
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cdc), &current)).To(Succeed())
+		Expect(current.Finalizers).To(ConsistOf(apiv1.ChangeDataCaptureFinalizerName))
+	})
+})
@@ -0,0 +1,333 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// certManagerCertificateGVK is the kind of the cert-manager Certificates.
+// The operator doesn't depend on the cert-manager API, and handles them
+// as unstructured objects
+var certManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// certManagerCertificateNameAnnotation is the annotation set by
+// cert-manager on the secrets it issued
+const certManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+
+// setupCertManagerPKI delegates the server certificate of the cluster to
+// cert-manager. The client certificates are signed by the CA of the
+// cluster, as in the default mode, so that a client certificate issued for
+// another cluster by a shared issuer can't be used to authenticate
+func (r *ClusterReconciler) setupCertManagerPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	haveCertManager, err := utils.CertManagerExist(r.DiscoveryClient)
+	if err != nil {
+		return err
+	}
+	if !haveCertManager {
+		r.Recorder.Event(cluster, "Warning", events.CertManagerNotFound,
+			"The Certificate resource of cert-manager is not available")
+		return fmt.Errorf("cert-manager is not installed, cannot issue the certificates")
+	}
+
+	if err := r.deleteStaleCertManagerCertificates(ctx, cluster); err != nil {
+		return err
+	}
+
+	serverSecretName := client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetServerTLSSecretName()}
+	if err := r.ensureCertManagerCertificate(
+		ctx,
+		cluster,
+		serverSecretName,
+		cluster.GetServiceReadWriteName(),
+		cluster.GetClusterAltDNSNames(),
+	); err != nil {
+		return fmt.Errorf("issuing server TLS certificate: %w", err)
+	}
+
+	// The instances can't start until cert-manager has issued the
+	// certificate. A secret with the same name generated by the operator
+	// before switching to cert-manager is not used
+	var serverSecret v1.Secret
+	err = r.Get(ctx, serverSecretName, &serverSecret)
+	if err == nil && serverSecret.Annotations[certManagerCertificateNameAnnotation] != serverSecretName.Name {
+		err = apierrors.NewNotFound(v1.Resource("secrets"), serverSecretName.Name)
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("waiting for secret %s to be issued: %w", serverSecretName.Name, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.ensureCertManagerServerCA(ctx, cluster, &serverSecret); err != nil {
+		return err
+	}
+
+	clientCaSecret, err := r.ensureClientCASecret(ctx, cluster)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("missing specified client CA secret %s: %w", cluster.GetClientCASecretName(), err)
+		}
+		return fmt.Errorf("generating client CA certificate: %w", err)
+	}
+
+	replicationSecretName := client.ObjectKey{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.GetReplicationSecretName(),
+	}
+	if err := r.ensureReplicationClientLeafCertificate(
+		ctx,
+		cluster,
+		replicationSecretName,
+		apiv1.StreamingReplicationUser,
+		clientCaSecret,
+		certs.CertTypeClient,
+		nil,
+		&x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}},
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("missing specified streaming replication client TLS secret %s: %w",
+				cluster.Status.Certificates.ReplicationTLSSecret, err)
+		}
+		return fmt.Errorf("generating streaming replication client certificate: %w", err)
+	}
+
+	return r.reconcileRoleClientCertificates(ctx, cluster, clientCaSecret)
+}
+
+// ensureCertManagerServerCA checks that the CA of the server certificate is
+// available. Unless the user provides it, the CA is the one cert-manager
+// stores in the issued secret, which is empty for issuers like ACME
+func (r *ClusterReconciler) ensureCertManagerServerCA(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	serverSecret *v1.Secret,
+) error {
+	if cluster.Spec.Certificates.ServerCASecret == "" {
+		if len(serverSecret.Data[certs.CACertKey]) == 0 {
+			r.Recorder.Eventf(cluster, "Warning", events.InvalidCASecret,
+				"The issuer didn't store its CA in the %s secret, set serverCASecret", serverSecret.Name)
+			return fmt.Errorf("missing %s in secret %s issued by cert-manager, the serverCASecret must be provided",
+				certs.CACertKey, serverSecret.Name)
+		}
+		return r.verifyCAValidity(*serverSecret, cluster)
+	}
+
+	var caSecret v1.Secret
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetServerCASecretName()},
+		&caSecret,
+	); err != nil {
+		r.Recorder.Event(cluster, "Warning", events.SecretNotFound,
+			"Getting secret "+cluster.GetServerCASecretName())
+		return fmt.Errorf("missing specified server CA secret %s: %w", cluster.GetServerCASecretName(), err)
+	}
+
+	return r.verifyCAValidity(caSecret, cluster)
+}
+
+// ensureCertManagerCertificate creates or updates the cert-manager
+// Certificate issuing the server certificate in the secret with the given name
+func (r *ClusterReconciler) ensureCertManagerCertificate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secretName client.ObjectKey,
+	commonName string,
+	altDNSNames []string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	expectedCertificate := buildCertManagerCertificate(cluster, secretName, commonName, altDNSNames)
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certManagerCertificateGVK)
+	err := r.Get(ctx, client.ObjectKeyFromObject(expectedCertificate), certificate)
+	if apierrors.IsNotFound(err) {
+		contextLogger.Info("Creating cert-manager Certificate", "name", expectedCertificate.GetName())
+		r.Recorder.Eventf(cluster, "Normal", events.IssuingCertificate,
+			"Creating cert-manager Certificate %s", expectedCertificate.GetName())
+		return r.Create(ctx, expectedCertificate)
+	}
+	if err != nil {
+		return err
+	}
+
+	origCertificate := certificate.DeepCopy()
+	certificate.Object["spec"] = expectedCertificate.Object["spec"]
+	// We don't override the current labels/annotations given that there could be data that isn't managed by us
+	utils.MergeObjectsMetadata(certificate, expectedCertificate)
+	if reflect.DeepEqual(origCertificate, certificate) {
+		return nil
+	}
+
+	contextLogger.Info("Updating cert-manager Certificate", "name", certificate.GetName())
+	return r.Patch(ctx, certificate, client.MergeFrom(origCertificate))
+}
+
+// buildCertManagerCertificate builds the cert-manager Certificate issuing
+// the server certificate in the secret with the given name. The issued secret
+// is labelled to be watched, so that the instances reload the certificate
+// cert-manager renews
+func buildCertManagerCertificate(
+	cluster *apiv1.Cluster,
+	secretName client.ObjectKey,
+	commonName string,
+	altDNSNames []string,
+) *unstructured.Unstructured {
+	configuration := cluster.GetCertManagerConfiguration()
+
+	labels := map[string]interface{}{
+		utils.ClusterLabelName: cluster.Name,
+		utils.WatchedLabelName: "true",
+	}
+
+	spec := map[string]interface{}{
+		"secretName": secretName.Name,
+		"commonName": commonName,
+		"usages":     []interface{}{"digital signature", "key encipherment", "server auth"},
+		"issuerRef": map[string]interface{}{
+			"name":  configuration.IssuerRef.Name,
+			"kind":  configuration.IssuerRef.Kind,
+			"group": configuration.IssuerRef.Group,
+		},
+		"privateKey": map[string]interface{}{
+			"algorithm":      "ECDSA",
+			"size":           int64(256),
+			"rotationPolicy": "Always",
+		},
+		"secretTemplate": map[string]interface{}{
+			"labels": labels,
+		},
+	}
+
+	if len(altDNSNames) > 0 {
+		dnsNames := make([]interface{}, len(altDNSNames))
+		for idx, name := range altDNSNames {
+			dnsNames[idx] = name
+		}
+		spec["dnsNames"] = dnsNames
+	}
+
+	if rotation := cluster.GetCertificatesRotation(); rotation != nil {
+		if rotation.LeafDurationDays > 0 {
+			spec["duration"] = fmt.Sprintf("%dh", rotation.LeafDurationDays*24)
+		}
+		if rotation.RenewBeforeDays > 0 {
+			spec["renewBefore"] = fmt.Sprintf("%dh", rotation.RenewBeforeDays*24)
+		}
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	certificate.SetGroupVersionKind(certManagerCertificateGVK)
+	certificate.SetNamespace(secretName.Namespace)
+	certificate.SetName(secretName.Name)
+
+	objectMeta := metav1.ObjectMeta{
+		Labels: map[string]string{utils.ClusterLabelName: cluster.Name},
+	}
+	cluster.SetInheritedDataAndOwnership(&objectMeta)
+	certificate.SetLabels(objectMeta.Labels)
+	certificate.SetAnnotations(objectMeta.Annotations)
+	certificate.SetOwnerReferences(objectMeta.OwnerReferences)
+
+	return certificate
+}
+
+// deleteStaleCertManagerCertificates deletes the cert-manager Certificates
+// created for this cluster which are not needed anymore, together with the
+// secrets they issued, so that the operator can generate them again. This
+// happens when the cluster stops using cert-manager, and for the client
+// certificates which were issued by cert-manager before
+func (r *ClusterReconciler) deleteStaleCertManagerCertificates(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	certificates := &unstructured.UnstructuredList{}
+	certificates.SetGroupVersionKind(certManagerCertificateGVK.GroupVersion().WithKind(
+		certManagerCertificateGVK.Kind + "List"))
+	if err := r.List(
+		ctx,
+		certificates,
+		client.InNamespace(cluster.GetNamespace()),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return err
+	}
+
+	for idx := range certificates.Items {
+		certificate := &certificates.Items[idx]
+		if cluster.UsesCertManager() && certificate.GetName() == cluster.GetServerTLSSecretName() {
+			continue
+		}
+
+		if owner := metav1.GetControllerOf(certificate); owner == nil ||
+			owner.Kind != apiv1.ClusterKind || owner.Name != cluster.Name {
+			continue
+		}
+
+		contextLogger.Info("Deleting a cert-manager Certificate not needed anymore",
+			"certificate", certificate.GetName())
+		if err := r.Delete(ctx, certificate); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		// cert-manager doesn't remove the secrets it issued
+		secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: cluster.GetNamespace(), Name: secretName}}
+		if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanupCertManagerCertificates deletes the cert-manager Certificates left
+// behind by a cluster which stopped using cert-manager, if cert-manager
+// is installed
+func (r *ClusterReconciler) cleanupCertManagerCertificates(ctx context.Context, cluster *apiv1.Cluster) error {
+	haveCertManager, err := utils.CertManagerExist(r.DiscoveryClient)
+	if err != nil || !haveCertManager {
+		return err
+	}
+
+	return r.deleteStaleCertManagerCertificates(ctx, cluster)
+}
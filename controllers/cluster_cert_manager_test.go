@@ -0,0 +1,263 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/x509"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	return value
+}
+
+func nestedStringSlice(obj map[string]interface{}, fields ...string) []string {
+	value, _, _ := unstructured.NestedStringSlice(obj, fields...)
+	return value
+}
+
+func nestedStringMap(obj map[string]interface{}, fields ...string) map[string]string {
+	value, _, _ := unstructured.NestedStringMap(obj, fields...)
+	return value
+}
+
+var _ = Describe("certificates issued by cert-manager", func() {
+	var (
+		cluster         *apiv1.Cluster
+		fakeClient      k8client.Client
+		discoveryClient *fakediscovery.FakeDiscovery
+		r               *ClusterReconciler
+	)
+
+	getCertificate := func(ctx SpecContext, name string) (*unstructured.Unstructured, error) {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certManagerCertificateGVK)
+		err := fakeClient.Get(ctx, k8client.ObjectKey{Namespace: cluster.Namespace, Name: name}, certificate)
+		return certificate, err
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					CertManager: &apiv1.CertManagerConfiguration{
+						IssuerRef: apiv1.CertManagerIssuerReference{
+							Name:  "ca-issuer",
+							Kind:  "ClusterIssuer",
+							Group: "cert-manager.io",
+						},
+					},
+					Rotation: &apiv1.CertificatesRotationConfiguration{
+						LeafDurationDays: 30,
+						RenewBeforeDays:  10,
+					},
+				},
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{Name: "batch_loader", Login: true, ClientCertificate: true},
+					},
+				},
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		discoveryClient = &fakediscovery.FakeDiscovery{
+			Fake: &k8stesting.Fake{
+				Resources: []*metav1.APIResourceList{
+					{
+						GroupVersion: "cert-manager.io/v1",
+						APIResources: []metav1.APIResource{
+							{
+								Name:       "certificates",
+								Kind:       "Certificate",
+								Namespaced: true,
+							},
+						},
+					},
+				},
+			},
+		}
+		r = &ClusterReconciler{
+			Client:          fakeClient,
+			DiscoveryClient: discoveryClient,
+			Recorder:        record.NewFakeRecorder(120),
+		}
+	})
+
+	It("builds the Certificate of the server", func() {
+		certificate := buildCertManagerCertificate(
+			cluster,
+			k8client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServerTLSSecretName()},
+			cluster.GetServiceReadWriteName(),
+			cluster.GetClusterAltDNSNames())
+
+		Expect(certificate.GetName()).To(Equal("cluster-example-server"))
+		Expect(metav1.IsControlledBy(certificate, cluster)).To(BeTrue())
+
+		Expect(nestedString(certificate.Object, "spec", "secretName")).
+			To(Equal("cluster-example-server"))
+		Expect(nestedString(certificate.Object, "spec", "commonName")).
+			To(Equal("cluster-example-rw"))
+		Expect(nestedStringSlice(certificate.Object, "spec", "dnsNames")).
+			To(HaveLen(9))
+		Expect(nestedStringSlice(certificate.Object, "spec", "usages")).
+			To(ContainElement("server auth"))
+		Expect(nestedStringMap(certificate.Object, "spec", "issuerRef")).To(Equal(map[string]string{
+			"name":  "ca-issuer",
+			"kind":  "ClusterIssuer",
+			"group": "cert-manager.io",
+		}))
+		Expect(nestedString(certificate.Object, "spec", "duration")).To(Equal("720h"))
+		Expect(nestedString(certificate.Object, "spec", "renewBefore")).To(Equal("240h"))
+		Expect(nestedStringMap(certificate.Object, "spec", "secretTemplate", "labels")).
+			To(HaveKeyWithValue(utils.WatchedLabelName, "true"))
+	})
+
+	It("refuses to issue the certificates without cert-manager", func(ctx SpecContext) {
+		discoveryClient.Resources = nil
+		Expect(r.setupCertManagerPKI(ctx, cluster)).ToNot(Succeed())
+
+		_, err := getCertificate(ctx, cluster.GetServerTLSSecretName())
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("creates the Certificate of the server and waits for it to be issued", func(ctx SpecContext) {
+		err := r.setupCertManagerPKI(ctx, cluster)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		server, err := getCertificate(ctx, cluster.GetServerTLSSecretName())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nestedStringSlice(server.Object, "spec", "usages")).
+			To(ContainElement("server auth"))
+
+		_, err = getCertificate(ctx, cluster.GetReplicationSecretName())
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("doesn't use a server secret not issued by cert-manager", func(ctx SpecContext) {
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: cluster.GetServerTLSSecretName()},
+			Data:       map[string][]byte{certs.CACertKey: []byte("operator CA")},
+		})).To(Succeed())
+
+		err := r.setupCertManagerPKI(ctx, cluster)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("requires the server CA when the issuer doesn't provide it", func(ctx SpecContext) {
+		Expect(fakeClient.Create(ctx, issuedServerSecret(cluster, nil))).To(Succeed())
+
+		err := r.setupCertManagerPKI(ctx, cluster)
+		Expect(err).To(MatchError(ContainSubstring("serverCASecret")))
+	})
+
+	It("signs the client certificates with the CA of the cluster", func(ctx SpecContext) {
+		issuerCA, err := certs.CreateRootCA("issuer", "cert-manager")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeClient.Create(ctx, issuedServerSecret(cluster, issuerCA.Certificate))).To(Succeed())
+
+		Expect(r.setupCertManagerPKI(ctx, cluster)).To(Succeed())
+
+		clientAuth := &x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+		var clientCA, replication, roleSecret corev1.Secret
+		Expect(fakeClient.Get(ctx, k8client.ObjectKey{Namespace: cluster.Namespace, Name: "cluster-example-ca"},
+			&clientCA)).To(Succeed())
+		Expect(clientCA.Data[certs.CACertKey]).ToNot(Equal(issuerCA.Certificate))
+		Expect(fakeClient.Get(ctx, k8client.ObjectKey{Namespace: cluster.Namespace,
+			Name: cluster.GetReplicationSecretName()}, &replication)).To(Succeed())
+		Expect(validateLeafCertificate(&clientCA, &replication, clientAuth)).To(Succeed())
+		Expect(fakeClient.Get(ctx, k8client.ObjectKey{Namespace: cluster.Namespace,
+			Name: "cluster-example-batch-loader-client"}, &roleSecret)).To(Succeed())
+		Expect(validateLeafCertificate(&clientCA, &roleSecret, clientAuth)).To(Succeed())
+
+		_, err = getCertificate(ctx, cluster.GetReplicationSecretName())
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("updates the Certificate when the issuer changes", func(ctx SpecContext) {
+		secretName := k8client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServerTLSSecretName()}
+		Expect(r.ensureCertManagerCertificate(ctx, cluster, secretName, "cluster-example-rw", nil)).To(Succeed())
+
+		cluster.Spec.Certificates.CertManager.IssuerRef.Name = "another-issuer"
+		Expect(r.ensureCertManagerCertificate(ctx, cluster, secretName, "cluster-example-rw", nil)).To(Succeed())
+
+		certificate, err := getCertificate(ctx, secretName.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nestedString(certificate.Object, "spec", "issuerRef", "name")).
+			To(Equal("another-issuer"))
+	})
+
+	It("removes the Certificates when the cluster stops using cert-manager", func(ctx SpecContext) {
+		secretName := k8client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServerTLSSecretName()}
+		Expect(r.ensureCertManagerCertificate(ctx, cluster, secretName, "cluster-example-rw", nil)).To(Succeed())
+		Expect(fakeClient.Create(ctx, issuedServerSecret(cluster, nil))).To(Succeed())
+
+		Expect(r.cleanupCertManagerCertificates(ctx, cluster)).To(Succeed())
+		_, err := getCertificate(ctx, secretName.Name)
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster.Spec.Certificates.CertManager = nil
+		Expect(r.cleanupCertManagerCertificates(ctx, cluster)).To(Succeed())
+		_, err = getCertificate(ctx, secretName.Name)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		err = fakeClient.Get(ctx, secretName, &corev1.Secret{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+// issuedServerSecret builds the server secret as issued by cert-manager
+func issuedServerSecret(cluster *apiv1.Cluster, caCertificate []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.GetServerTLSSecretName(),
+			Annotations: map[string]string{
+				certManagerCertificateNameAnnotation: cluster.GetServerTLSSecretName(),
+			},
+		},
+		Data: map[string][]byte{certs.CACertKey: caCertificate},
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// certificateExpirationLayout is the layout used to write the certificate
+// expiration dates in the cluster status, i.e. the one of time.Time.String()
+const certificateExpirationLayout = "2006-01-02 15:04:05 -0700 MST"
+
+// setReplicationCondition sets the Replication condition depending on
+// the standby instances streaming from their upstream
+func setReplicationCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	expectedStandbys := cluster.Spec.Instances - 1
+	var streaming int
+	var notStreaming []string
+	for _, item := range statuses.Items {
+		// The designated primary of a replica cluster is not a standby
+		// of this cluster, even if it is not a primary
+		if item.IsPrimary || item.Pod.Name == cluster.Status.CurrentPrimary {
+			continue
+		}
+		if item.Error == nil && item.IsWalReceiverActive {
+			streaming++
+			continue
+		}
+		notStreaming = append(notStreaming, item.Pod.Name)
+	}
+
+	condition := metav1.Condition{
+		Type:               string(apiv1.ConditionReplication),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(apiv1.ConditionReasonStandbysStreaming),
+		Message:            fmt.Sprintf("%d/%d standby instances are streaming", streaming, expectedStandbys),
+		ObservedGeneration: cluster.Generation,
+	}
+	switch {
+	case len(notStreaming) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonStandbysNotStreaming)
+		condition.Message = fmt.Sprintf("Standby instances not streaming: %s",
+			strings.Join(notStreaming, ", "))
+	case streaming < expectedStandbys:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonStandbysNotStreaming)
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setConfigAppliedCondition sets the ConfigApplied condition depending on
+// the instances waiting for a restart to apply the PostgreSQL configuration
+func setConfigAppliedCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	var pendingRestart, unavailable []string
+	for _, item := range statuses.Items {
+		switch {
+		case item.Error != nil:
+			unavailable = append(unavailable, item.Pod.Name)
+		case item.PendingRestart:
+			pendingRestart = append(pendingRestart, item.Pod.Name)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               string(apiv1.ConditionConfigApplied),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(apiv1.ConditionReasonConfigurationApplied),
+		Message:            "The PostgreSQL configuration has been applied by every instance",
+		ObservedGeneration: cluster.Generation,
+	}
+	switch {
+	case len(pendingRestart) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonPendingRestart)
+		condition.Message = fmt.Sprintf("Instances waiting for a restart: %s",
+			strings.Join(pendingRestart, ", "))
+	case len(unavailable) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = string(apiv1.ConditionReasonInstanceStatusUnavailable)
+		condition.Message = fmt.Sprintf("Cannot get the status of the instances: %s",
+			strings.Join(unavailable, ", "))
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setPostmasterRestartsCondition sets the PostmasterRestarts condition when
+// the instance manager restarts PostgreSQL after an unexpected exit, and
+// removes it otherwise
+func setPostmasterRestartsCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	if !cluster.Spec.PostmasterRestart.IsEnabled() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionPostmasterRestarts))
+		return
+	}
+
+	var paused []string
+	for _, item := range statuses.Items {
+		if item.PostmasterRestart != nil && item.PostmasterRestart.Paused {
+			paused = append(paused, fmt.Sprintf("%s (%s)",
+				item.Pod.Name, item.PostmasterRestart.LastExitReason))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               string(apiv1.ConditionPostmasterRestarts),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(apiv1.ConditionReasonPostmasterRestartsAllowed),
+		Message:            "No instance paused the restarts of PostgreSQL",
+		ObservedGeneration: cluster.Generation,
+	}
+	if len(paused) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonPostmasterRestartsPaused)
+		condition.Message = fmt.Sprintf("Instances not restarting PostgreSQL: %s",
+			strings.Join(paused, ", "))
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setCertificatesValidCondition sets the CertificatesValid condition
+// depending on the certificate expiration dates in the cluster status
+func setCertificatesValidCondition(cluster *apiv1.Cluster, now time.Time) {
+	var expired []string
+	var firstExpiration time.Time
+	for secretName, expiration := range cluster.Status.Certificates.Expirations {
+		expirationTime, err := time.Parse(certificateExpirationLayout, expiration)
+		if err != nil {
+			continue
+		}
+		if !expirationTime.After(now) {
+			expired = append(expired, secretName)
+		}
+		if firstExpiration.IsZero() || expirationTime.Before(firstExpiration) {
+			firstExpiration = expirationTime
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               string(apiv1.ConditionCertificatesValid),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(apiv1.ConditionReasonCertificatesValid),
+		Message:            "The certificates are valid",
+		ObservedGeneration: cluster.Generation,
+	}
+	if !firstExpiration.IsZero() {
+		condition.Message = fmt.Sprintf("The certificates are valid, the first one expires on %s",
+			firstExpiration.UTC().Format(time.RFC3339))
+	}
+	if len(expired) > 0 {
+		sort.Strings(expired)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonCertificateExpired)
+		condition.Message = fmt.Sprintf("Expired certificates: %s", strings.Join(expired, ", "))
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster conditions", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Generation: 3},
+			Spec:       apiv1.ClusterSpec{Instances: 3},
+			Status:     apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+	})
+
+	newStatus := func(podName string, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			IsPrimary:           isPrimary,
+			IsWalReceiverActive: !isPrimary,
+		}
+	}
+
+	getCondition := func(conditionType apiv1.ClusterConditionType) *metav1.Condition {
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(conditionType))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.ObservedGeneration).To(BeEquivalentTo(3))
+		return condition
+	}
+
+	Context("Replication", func() {
+		It("is true when every standby is streaming", func() {
+			setReplicationCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				newStatus("cluster-example-2", false),
+				newStatus("cluster-example-3", false),
+			}})
+
+			condition := getCondition(apiv1.ConditionReplication)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonStandbysStreaming)))
+		})
+
+		It("is false when a standby is not streaming", func() {
+			standby := newStatus("cluster-example-3", false)
+			standby.IsWalReceiverActive = false
+			setReplicationCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				newStatus("cluster-example-2", false),
+				standby,
+			}})
+
+			condition := getCondition(apiv1.ConditionReplication)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonStandbysNotStreaming)))
+			Expect(condition.Message).To(ContainSubstring("cluster-example-3"))
+		})
+
+		It("is false when a standby is missing", func() {
+			setReplicationCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				newStatus("cluster-example-2", false),
+			}})
+
+			condition := getCondition(apiv1.ConditionReplication)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("1/2 standby instances are streaming"))
+		})
+
+		It("doesn't consider the designated primary of a replica cluster as a standby", func() {
+			designatedPrimary := newStatus("cluster-example-1", false)
+			designatedPrimary.IsWalReceiverActive = false
+			setReplicationCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				designatedPrimary,
+				newStatus("cluster-example-2", false),
+				newStatus("cluster-example-3", false),
+			}})
+
+			Expect(getCondition(apiv1.ConditionReplication).Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("ConfigApplied", func() {
+		It("is true when no instance is waiting for a restart", func() {
+			setConfigAppliedCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				newStatus("cluster-example-2", false),
+			}})
+
+			Expect(getCondition(apiv1.ConditionConfigApplied).Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("is false when an instance is waiting for a restart", func() {
+			standby := newStatus("cluster-example-2", false)
+			standby.PendingRestart = true
+			setConfigAppliedCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				standby,
+			}})
+
+			condition := getCondition(apiv1.ConditionConfigApplied)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPendingRestart)))
+			Expect(condition.Message).To(ContainSubstring("cluster-example-2"))
+		})
+
+		It("is unknown when the status of an instance is not available", func() {
+			standby := newStatus("cluster-example-2", false)
+			standby.Error = errors.New("connection refused")
+			setConfigAppliedCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				standby,
+			}})
+
+			condition := getCondition(apiv1.ConditionConfigApplied)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonInstanceStatusUnavailable)))
+		})
+	})
+
+	Context("PostmasterRestarts", func() {
+		It("is set only when the restarts are managed by the instance manager", func() {
+			setPostmasterRestartsCondition(cluster, postgres.PostgresqlStatusList{})
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions,
+				string(apiv1.ConditionPostmasterRestarts))).To(BeNil())
+
+			cluster.Spec.PostmasterRestart = &apiv1.PostmasterRestartConfiguration{Enabled: true}
+			setPostmasterRestartsCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+			}})
+			Expect(getCondition(apiv1.ConditionPostmasterRestarts).Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("is false when an instance paused the restarts", func() {
+			cluster.Spec.PostmasterRestart = &apiv1.PostmasterRestartConfiguration{Enabled: true}
+			standby := newStatus("cluster-example-2", false)
+			standby.PostmasterRestart = &postgres.PostmasterRestartResult{
+				Attempts:       5,
+				LastExitReason: string(apiv1.PostmasterExitOOMKilled),
+				Paused:         true,
+			}
+			setPostmasterRestartsCondition(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true),
+				standby,
+			}})
+
+			condition := getCondition(apiv1.ConditionPostmasterRestarts)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPostmasterRestartsPaused)))
+			Expect(condition.Message).To(ContainSubstring("cluster-example-2 (OOMKilled)"))
+		})
+	})
+
+	Context("CertificatesValid", func() {
+		now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		It("is true when no certificate is expired", func() {
+			cluster.Status.Certificates.Expirations = map[string]string{
+				"cluster-example-ca":     now.AddDate(1, 0, 0).String(),
+				"cluster-example-server": now.AddDate(0, 3, 0).String(),
+			}
+			setCertificatesValidCondition(cluster, now)
+
+			condition := getCondition(apiv1.ConditionCertificatesValid)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("2024-09-01T00:00:00Z"))
+		})
+
+		It("is false when a certificate is expired", func() {
+			cluster.Status.Certificates.Expirations = map[string]string{
+				"cluster-example-ca":     now.AddDate(1, 0, 0).String(),
+				"cluster-example-server": now.AddDate(0, 0, -1).String(),
+			}
+			setCertificatesValidCondition(cluster, now)
+
+			condition := getCondition(apiv1.ConditionCertificatesValid)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonCertificateExpired)))
+			Expect(condition.Message).To(ContainSubstring("cluster-example-server"))
+		})
+	})
+})
@@ -44,8 +44,11 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cnpi/plugin/operatorclient"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/failoverarbiter"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/garbagecollection"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
 	instanceReconciler "github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/instance"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
@@ -75,6 +78,8 @@ type ClusterReconciler struct {
 	Recorder        record.EventRecorder
 
 	*instance.StatusClient
+
+	failoverArbiters *failoverarbiter.Registry
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
@@ -84,7 +89,9 @@ func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.Discov
 		DiscoveryClient: discoveryClient,
 		Client:          operatorclient.NewExtendedClient(mgr.GetClient()),
 		Scheme:          mgr.GetScheme(),
-		Recorder:        mgr.GetEventRecorderFor("cloudnative-pg"),
+		Recorder:        events.NewRecorder(mgr.GetEventRecorderFor("cloudnative-pg")),
+
+		failoverArbiters: failoverarbiter.NewRegistry(),
 	}
 }
 
@@ -96,14 +103,16 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update;list;patch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update;list
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;create;list;watch;delete;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/status,verbs=get;watch;update;patch
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;update;get;list;watch
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch;update;get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;update;get;list;watch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch;update;get;list;watch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=configmaps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
@@ -113,12 +122,23 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=imagecatalogs,verbs=get;watch;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterimagecatalogs,verbs=get;watch;list
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=subscriptions,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=subscriptions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=replicationgrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=restores,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=restores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=switchovers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=switchovers/status,verbs=get;update;patch
 
 // Reconcile is the operator reconcile loop
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -136,6 +156,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil {
+		clusterRecoverabilityMetrics.forget(req.NamespacedName)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -144,18 +165,49 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				"namespace", req.Namespace,
 			)
 		}
+		// The access to the secrets of other namespaces can't be
+		// owned by the cluster and needs to be revoked explicitly
+		deletedCluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name},
+		}
+		if err := r.deleteExternalSecretsAccess(ctx, deletedCluster, func(string) bool { return true }); err != nil {
+			contextLogger.Error(err, "error while revoking the access to the secrets of other namespaces")
+		}
+		if err := r.releaseLogicalReplicationFinalizers(ctx, req.NamespacedName); err != nil {
+			return ctrl.Result{}, fmt.Errorf("while releasing the finalizers of publications and subscriptions: %w", err)
+		}
 		return ctrl.Result{}, err
 	}
 	ctx = cluster.SetInContext(ctx)
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
 	if errors.Is(err, ErrNextLoop) {
-		return result, nil
+		return requeueOnMaintenanceWindowChange(cluster, result), nil
 	}
 	if errors.Is(err, utils.ErrTerminateLoop) {
 		return ctrl.Result{}, nil
 	}
-	return result, err
+	if err != nil {
+		return result, err
+	}
+	return requeueOnMaintenanceWindowChange(cluster, result), nil
+}
+
+// requeueOnMaintenanceWindowChange ensures the cluster is reconciled again
+// when a maintenance window begins or ends, to resume the deferred
+// operations and update the PodDisruptionBudget of the replicas
+func requeueOnMaintenanceWindowChange(cluster *apiv1.Cluster, result ctrl.Result) ctrl.Result {
+	_, nextChange, err := cluster.GetMaintenanceWindowStatus(time.Now())
+	if err != nil || nextChange.IsZero() || (result.Requeue && result.RequeueAfter == 0) {
+		return result
+	}
+
+	untilChange := max(time.Until(nextChange), time.Second)
+	if result.RequeueAfter == 0 || untilChange < result.RequeueAfter {
+		result.RequeueAfter = untilChange
+	}
+
+	return result
 }
 
 // Inner reconcile loop. Anything inside can require the reconciliation loop to stop by returning ErrNextLoop
@@ -251,6 +303,7 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 
 	// Get the replication status
 	instancesStatus := r.StatusClient.GetStatusFromInstances(ctx, resources.instances)
+	nodeConditionsRequeue := r.setNodeUnavailabilityReasons(ctx, cluster, &instancesStatus)
 
 	// we update all the cluster status fields that require the instances status
 	if err := r.updateClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus); err != nil {
@@ -261,6 +314,7 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		}
 		return ctrl.Result{}, fmt.Errorf("cannot update the instances status on the cluster: %w", err)
 	}
+	clusterRecoverabilityMetrics.update(cluster, instancesStatus)
 
 	if err := persistentvolumeclaim.ReconcileMetadata(
 		ctx,
@@ -280,6 +334,15 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	readOnlyTrafficRequeue, err := r.reconcileReadOnlyTraffic(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileTDEPassphrases(ctx, cluster, instancesStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the TDE passphrases: %w", err)
+	}
+
 	if err := persistentvolumeclaim.ReconcileSerialAnnotation(
 		ctx,
 		r.Client,
@@ -290,6 +353,20 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	// Report the resources that are not used anymore, and remove
+	// the ones whose retention period has expired
+	garbageCollectionRequeue, err := garbagecollection.Reconcile(
+		ctx,
+		r.Client,
+		r.Recorder,
+		cluster,
+		resources.jobs.Items,
+		resources.pvcs.Items,
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot collect the orphaned resources: %w", err)
+	}
+
 	if instancesStatus.AllReadyInstancesStatusUnreachable() {
 		contextLogger.Warning(
 			"Failed to extract instance status from ready instances. Attempting to requeue...",
@@ -358,6 +435,24 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return *result, err
 	}
 
+	// If the cluster has to be restored in place, we need to shut down
+	// every instance and replace the data directory of the primary
+	if result, err := r.reconcileInPlaceRestore(ctx, cluster, resources); result != nil || err != nil {
+		if result != nil {
+			return *result, err
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the in-place restore: %w", err)
+	}
+
+	// If a newer PostgreSQL major version has been requested, we need to
+	// upgrade the data directory before rolling out the new image
+	if result, err := r.reconcileMajorUpgrade(ctx, cluster, resources); result != nil || err != nil {
+		if result != nil {
+			return *result, err
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the major upgrade: %w", err)
+	}
+
 	// We have already updated the status in updateResourceStatus call,
 	// so we need to issue an extra update when the OnlineUpdateEnabled changes.
 	// It's okay because it should not change often.
@@ -384,6 +479,23 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return *result, nil
 	}
 
+	// Promote the instance requested by a Switchover object, if any
+	promotedInstance, switchoverRequeue, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the switchover request: %w", err)
+	}
+	if promotedInstance != "" {
+		contextLogger.Info("Waiting for the new primary to notice the promotion request",
+			"newPrimary", promotedInstance)
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	// Keep holding the failover arbiter lease while the primary is healthy
+	arbiterRenewal, err := r.renewFailoverArbiterLease(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the failover arbiter lease: %w", err)
+	}
+
 	// Updates all the objects managed by the controller
 	res, err := r.reconcileResources(ctx, cluster, resources, instancesStatus)
 	if err != nil || !res.IsZero() {
@@ -392,6 +504,24 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 
 	// Calls post-reconcile hooks
 	hookResult := postReconcilePluginHooks(ctx, cluster, cluster)
+	if hookResult.Err == nil && hookResult.Result.IsZero() {
+		requeueAfter := arbiterRenewal
+		if garbageCollectionRequeue > 0 && (requeueAfter == 0 || garbageCollectionRequeue < requeueAfter) {
+			requeueAfter = garbageCollectionRequeue
+		}
+		if switchoverRequeue > 0 && (requeueAfter == 0 || switchoverRequeue < requeueAfter) {
+			requeueAfter = switchoverRequeue
+		}
+		if readOnlyTrafficRequeue > 0 && (requeueAfter == 0 || readOnlyTrafficRequeue < requeueAfter) {
+			requeueAfter = readOnlyTrafficRequeue
+		}
+		if nodeConditionsRequeue > 0 && (requeueAfter == 0 || nodeConditionsRequeue < requeueAfter) {
+			requeueAfter = nodeConditionsRequeue
+		}
+		if requeueAfter > 0 {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+	}
 	return hookResult.Result, hookResult.Err
 }
 
@@ -430,10 +560,21 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for the failover delay to expire")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if errors.Is(err, ErrWaitingOnFailureThreshold) {
+			return &ctrl.Result{RequeueAfter: failureDetectionInterval}, nil
+		}
+		if errors.Is(err, ErrWaitingOnFlapDamping) {
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 		if errors.Is(err, ErrWalReceiversRunning) {
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if errors.Is(err, ErrFailoverArbiterDenied) {
+			contextLogger.Info("Waiting for the failover arbiter to grant the promotion of a new primary",
+				"error", err)
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"error", err)
@@ -447,13 +588,18 @@ func (r *ClusterReconciler) handleSwitchover(
 	}
 
 	// Primary is healthy, No switchover in progress.
-	// If we have a currentPrimaryFailingSince timestamp, let's unset it.
-	if cluster.Status.CurrentPrimaryFailingSinceTimestamp != "" {
+	// If we have a currentPrimaryFailingSince timestamp or a failure count, let's unset them.
+	if cluster.Status.CurrentPrimaryFailingSinceTimestamp != "" || cluster.Status.CurrentPrimaryFailureCount != 0 {
 		cluster.Status.CurrentPrimaryFailingSinceTimestamp = ""
+		cluster.Status.CurrentPrimaryFailureCount = 0
+		cluster.Status.CurrentPrimaryLastFailureTimestamp = ""
 		if err := r.Status().Update(ctx, cluster); err != nil {
 			return nil, err
 		}
 	}
+	if err := r.clearFailoverDampened(ctx, cluster); err != nil {
+		return nil, err
+	}
 
 	return nil, nil
 }
@@ -507,6 +653,32 @@ func (r *ClusterReconciler) setDefaults(ctx context.Context, cluster *apiv1.Clus
 	return nil
 }
 
+// logShutdownStatus reports the phase of the shutdown procedure of
+// the instances being terminated, when their instance manager is reachable
+func (r *ClusterReconciler) logShutdownStatus(ctx context.Context, resources *managedResources) {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if pod.DeletionTimestamp == nil || pod.Status.PodIP == "" {
+			continue
+		}
+
+		status, err := r.GetShutdownStatusFromInstance(ctx, pod)
+		if err != nil {
+			contextLogger.Debug("Cannot get the shutdown status of the instance",
+				"instance", pod.Name, "error", err)
+			continue
+		}
+
+		contextLogger.Info("Waiting for the instance to shut down",
+			"instance", pod.Name,
+			"phase", status.Phase,
+			"phaseStartedAt", status.PhaseStartedAt,
+			"phaseTimeout", status.PhaseTimeout)
+	}
+}
+
 // reconcileResources updates all the objects managed by the controller
 func (r *ClusterReconciler) reconcileResources(
 	ctx context.Context, cluster *apiv1.Cluster,
@@ -530,6 +702,16 @@ func (r *ClusterReconciler) reconcileResources(
 		return *result, err
 	}
 
+	// Re-create the replicas whose data is damaged
+	result, err = r.recloneDamagedReplica(ctx, cluster, instancesStatus)
+	if err != nil {
+		contextLogger.Error(err, "While re-creating a damaged replica")
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if result != nil {
+		return *result, err
+	}
+
 	// TODO: move into a central waiting phase
 	// If we are joining a node, we should wait for the process to finish
 	if resources.countRunningJobs() > 0 {
@@ -542,6 +724,7 @@ func (r *ClusterReconciler) reconcileResources(
 
 	if !resources.allInstancesAreActive() {
 		contextLogger.Debug("Instance pod not active. Retrying in one second.")
+		r.logShutdownStatus(ctx, resources)
 
 		// Preserve phases that handle the in-place restart behaviour for the following reasons:
 		// 1. Technically: The Inplace phases help determine if a switchover is required.
@@ -639,7 +822,7 @@ func (r *ClusterReconciler) deleteEvictedOrUnscheduledInstances(
 		}
 		deletedPods = true
 
-		r.Recorder.Eventf(cluster, "Normal", "DeletePod",
+		r.Recorder.Eventf(cluster, "Normal", events.DeletePod,
 			"Deleted evicted/unscheduled Pod %v",
 			instance.Name)
 
@@ -657,7 +840,7 @@ func (r *ClusterReconciler) deleteEvictedOrUnscheduledInstances(
 		); err != nil {
 			return nil, err
 		}
-		r.Recorder.Eventf(cluster, "Normal", "DeletePVCs",
+		r.Recorder.Eventf(cluster, "Normal", events.DeletePVCs,
 			"Deleted evicted/unscheduled Pod %v PVCs",
 			instance.Name)
 	}
@@ -722,8 +905,9 @@ func (r *ClusterReconciler) reconcilePods(
 		return r.joinReplicaInstance(ctx, newNodeSerial, cluster)
 	}
 
-	// Are there nodes to be removed? Remove one of them
-	if cluster.Status.Instances > cluster.Spec.Instances {
+	// Are there nodes to be removed? Remove one of them, keeping the
+	// instance created to replace an outdated replica
+	if cluster.Status.Instances > cluster.Spec.Instances+cluster.GetSurgeInstances() {
 		if err := r.scaleDownCluster(ctx, cluster, resources); err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot scale down cluster: %w", err)
 		}
@@ -784,7 +968,12 @@ func (r *ClusterReconciler) handleRollingUpdate(
 
 	// If we need to roll out a restart of any instance, this is the right moment
 	done, err := r.rolloutRequiredInstances(ctx, cluster, &instancesStatus)
+	rolloutDeferred := errors.Is(err, errRolloutDeferred)
 	switch {
+	case rolloutDeferred:
+		// The instance manager can still be upgraded online, as this
+		// doesn't restart PostgreSQL
+		contextLogger.Debug("Rollout deferred to the next maintenance window")
 	case errors.Is(err, errLogShippingReplicaElected):
 		contextLogger.Warning(
 			"The primary needs to be restarted, but the chosen new primary is still " +
@@ -798,7 +987,7 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		return ctrl.Result{}, ErrNextLoop
 	}
 
-	if instancesStatus.ArePodsWaitingForDecreasedSettings() {
+	if !rolloutDeferred && instancesStatus.ArePodsWaitingForDecreasedSettings() {
 		// requeue and wait for the pods to be ready to be restarted,
 		// which will be handled by rolloutDueToCondition
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
@@ -822,6 +1011,11 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		}
 	}
 
+	if rolloutDeferred {
+		// Keep the phase reporting the deferred rollout
+		return ctrl.Result{}, ErrNextLoop
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -849,10 +1043,30 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretsToClusters()),
 			builder.WithPredicates(secretsPredicate),
 		).
+		Watches(
+			&apiv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.mapCanaryClustersToClusters()),
+		).
+		Watches(
+			&apiv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.mapDemotedClustersToClusters()),
+		).
 		Watches(
 			&apiv1.Pooler{},
 			handler.EnqueueRequestsFromMapFunc(r.mapPoolersToClusters()),
 		).
+		Watches(
+			&apiv1.Restore{},
+			handler.EnqueueRequestsFromMapFunc(r.mapRestoresToClusters()),
+		).
+		Watches(
+			&apiv1.Switchover{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSwitchoversToClusters()),
+		).
+		Watches(
+			&apiv1.ReplicationGrant{},
+			handler.EnqueueRequestsFromMapFunc(r.mapReplicationGrantsToClusters()),
+		).
 		Watches(
 			&corev1.Node{},
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters()),
@@ -868,7 +1082,7 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			handler.EnqueueRequestsFromMapFunc(r.mapClusterImageCatalogsToClusters()),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
-		Complete(r)
+		Complete(withSharding(withReconcileMetrics("cluster", r)))
 }
 
 // createFieldIndexes creates the indexes needed by this controller
@@ -908,6 +1122,15 @@ func (r *ClusterReconciler) createFieldIndexes(ctx context.Context, mgr ctrl.Man
 		return err
 	}
 
+	// Create a new indexed field on Clusters. This field will be used to easily
+	// find all Clusters taking secrets from a certain namespace
+	if err := mgr.GetFieldIndexer().IndexField(
+		ctx,
+		&apiv1.Cluster{},
+		externalClusterSecretsNamespaceKey, externalClusterSecretsNamespaces); err != nil {
+		return err
+	}
+
 	// Create a new indexed field on Pods. This field will be used to easily
 	// find all the Pods created by node
 	if err := mgr.GetFieldIndexer().IndexField(
@@ -1144,9 +1367,8 @@ func filterClustersUsingConfigMap(
 func (r *ClusterReconciler) mapNodeToClusters() handler.MapFunc {
 	return func(ctx context.Context, obj client.Object) []reconcile.Request {
 		node := obj.(*corev1.Node)
-		// exit if the node is schedulable (e.g. not cordoned)
-		// could be expanded here with other conditions (e.g. pressure or issues)
-		if !node.Spec.Unschedulable {
+		// exit if the node is available (e.g. not cordoned nor under pressure)
+		if utils.GetNodeUnavailabilityReason(node) == "" {
 			return nil
 		}
 		var childPods corev1.PodList
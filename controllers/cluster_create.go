@@ -41,6 +41,8 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
@@ -61,6 +63,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.reconcileExternalClusterSecrets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.reconcilePostgresServices(ctx, cluster)
 	if err != nil {
 		return err
@@ -98,6 +105,16 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = reconcilePrometheusRule(ctx, r.Client, r.DiscoveryClient, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileGrafanaDashboard(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	// TODO: only required to cleanup custom monitoring queries configmaps from older versions (v1.10 and v1.11)
 	// 		 that could have been copied with the source configmap name instead of the new default one.
 	// 		 Should be removed in future releases.
@@ -144,10 +161,25 @@ func (r *ClusterReconciler) reconcilePodDisruptionBudget(ctx context.Context, cl
 		return err
 	}
 
-	return r.createOrPatchOwnedPodDisruptionBudget(ctx,
-		cluster,
-		specs.BuildReplicasPodDisruptionBudget(cluster),
-	)
+	// Outside the maintenance windows no replica can be evicted,
+	// so that the nodes can't be drained
+	insideMaintenanceWindow, _, err := cluster.GetMaintenanceWindowStatus(time.Now())
+	if err != nil {
+		return err
+	}
+
+	replicasPdb := specs.BuildReplicasPodDisruptionBudget(cluster)
+	if !insideMaintenanceWindow {
+		replicasPdb = specs.BuildBlockingReplicasPodDisruptionBudget(cluster)
+	}
+	if replicasPdb == nil {
+		// Clusters having too few instances don't need a replicas PDB:
+		// remove the one left by a scale down or by the previous
+		// maintenance window configuration
+		return r.deleteReplicasPodDisruptionBudget(ctx, cluster)
+	}
+
+	return r.createOrPatchOwnedPodDisruptionBudget(ctx, cluster, replicasPdb)
 }
 
 func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
@@ -172,7 +204,7 @@ func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluste
 func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluster *apiv1.Cluster) error {
 	// We need to create a secret for the 'postgres' user when superuser
 	// access is enabled and the user haven't specified his own
-	if cluster.GetEnableSuperuserAccess() &&
+	if cluster.IsSuperuserSecretInUse() &&
 		(cluster.Spec.SuperuserSecret == nil || cluster.Spec.SuperuserSecret.Name == "") {
 		postgresPassword, err := password.Generate(64, 10, 0, false, true)
 		if err != nil {
@@ -190,29 +222,23 @@ func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluste
 		return createOrPatchClusterCredentialSecret(ctx, r.Client, postgresSecret)
 	}
 
-	// If we don't have Superuser enabled we make sure the automatically generated secret doesn't exist
-	if !cluster.GetEnableSuperuserAccess() {
-		var secret corev1.Secret
-		err := r.Get(
-			ctx,
-			client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetSuperuserSecretName()},
-			&secret)
-		if err != nil {
-			if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
-				return nil
-			}
-			return err
-		}
-
-		if _, owned := IsOwnedByCluster(&secret); owned {
-			return r.Delete(ctx, &secret)
-		}
+	// If we don't have Superuser enabled, or its password comes from the
+	// Secrets Store, we make sure the automatically generated secret doesn't
+	// exist, as it wouldn't contain the password set in PostgreSQL
+	if !cluster.IsSuperuserSecretInUse() {
+		return r.deleteGeneratedCredentialSecret(ctx, cluster, cluster.GetSuperuserSecretName())
 	}
 
 	return nil
 }
 
 func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.GetApplicationPasswordFile() != "" {
+		// The password comes from the Secrets Store, and the
+		// generated secret wouldn't contain the one set in PostgreSQL
+		return r.deleteGeneratedCredentialSecret(ctx, cluster, cluster.GetApplicationSecretName())
+	}
+
 	if cluster.ShouldCreateApplicationSecret() {
 		appPassword, err := password.Generate(64, 10, 0, false, true)
 		if err != nil {
@@ -232,6 +258,32 @@ func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster
 	return nil
 }
 
+// deleteGeneratedCredentialSecret deletes a credential secret, if it
+// has been generated by the operator
+func (r *ClusterReconciler) deleteGeneratedCredentialSecret(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secretName string,
+) error {
+	var secret corev1.Secret
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: secretName},
+		&secret)
+	if err != nil {
+		if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, owned := IsOwnedByCluster(&secret); owned {
+		return r.Delete(ctx, &secret)
+	}
+
+	return nil
+}
+
 func createOrPatchClusterCredentialSecret(
 	ctx context.Context,
 	cli client.Client,
@@ -302,36 +354,180 @@ func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster
 }
 
 func (r *ClusterReconciler) reconcilePostgresServices(ctx context.Context, cluster *apiv1.Cluster) error {
-	if configuration.Current.CreateAnyService {
-		anyService := specs.CreateClusterAnyService(*cluster)
-		cluster.SetInheritedDataAndOwnership(&anyService.ObjectMeta)
-
-		if err := r.serviceReconciler(ctx, anyService); err != nil {
-			return err
-		}
+	if err := r.reconcileAnyService(ctx, cluster); err != nil {
+		return err
 	}
 
 	readService := specs.CreateClusterReadService(*cluster)
 	cluster.SetInheritedDataAndOwnership(&readService.ObjectMeta)
 
-	if err := r.serviceReconciler(ctx, readService); err != nil {
+	if err := r.serviceReconciler(ctx, readService, false); err != nil {
 		return err
 	}
 
 	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
 	cluster.SetInheritedDataAndOwnership(&readOnlyService.ObjectMeta)
 
-	if err := r.serviceReconciler(ctx, readOnlyService); err != nil {
+	if err := r.serviceReconciler(ctx, readOnlyService, false); err != nil {
 		return err
 	}
 
 	readWriteService := specs.CreateClusterReadWriteService(*cluster)
 	cluster.SetInheritedDataAndOwnership(&readWriteService.ObjectMeta)
 
-	return r.serviceReconciler(ctx, readWriteService)
+	if err := r.serviceReconciler(ctx, readWriteService, false); err != nil {
+		return err
+	}
+
+	if err := r.reconcileManagedServices(ctx, cluster); err != nil {
+		return err
+	}
+
+	return r.reconcileConnectionConfigMap(ctx, cluster)
+}
+
+// reconcileAnyService ensures that the headless `-any` service exists when
+// the cluster publishes its multi-host connection strings, and that it is
+// removed otherwise. Since a service can't be turned into a headless one,
+// an existing service having a cluster IP, like the ones created by older
+// versions of the operator, is recreated
+func (r *ClusterReconciler) reconcileAnyService(ctx context.Context, cluster *apiv1.Cluster) error {
+	anyService := specs.CreateClusterAnyService(*cluster)
+	cluster.SetInheritedDataAndOwnership(&anyService.ObjectMeta)
+
+	var livingService corev1.Service
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(anyService), &livingService)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	// we never touch a service that we haven't created
+	if found {
+		if ownerName, isOwned := IsOwnedByCluster(&livingService); !isOwned || ownerName != cluster.Name {
+			if cluster.IsMultiHostConnectionEnabled() {
+				log.FromContext(ctx).Info("Skipping the any service, as it is not owned by the cluster",
+					"service", livingService.Name)
+			}
+			return nil
+		}
+	}
+
+	if found && (!cluster.IsMultiHostConnectionEnabled() || livingService.Spec.ClusterIP != corev1.ClusterIPNone) {
+		log.FromContext(ctx).Info("Deleting the any service", "service", livingService.Name)
+		if err := r.Client.Delete(ctx, &livingService); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if !cluster.IsMultiHostConnectionEnabled() {
+		return nil
+	}
+
+	return r.serviceReconciler(ctx, anyService, false)
+}
+
+// reconcileConnectionConfigMap ensures that the ConfigMap with the
+// multi-host connection strings exists and lists the current instances,
+// starting with the primary, deleting it when it is no longer required
+func (r *ClusterReconciler) reconcileConnectionConfigMap(ctx context.Context, cluster *apiv1.Cluster) error {
+	var livingConfigMap corev1.ConfigMap
+	err := r.Client.Get(
+		ctx,
+		types.NamespacedName{Name: cluster.GetConnectionConfigMapName(), Namespace: cluster.Namespace},
+		&livingConfigMap)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	// we never touch a ConfigMap that we haven't created
+	if found {
+		if ownerName, isOwned := IsOwnedByCluster(&livingConfigMap); !isOwned || ownerName != cluster.Name {
+			if cluster.IsMultiHostConnectionEnabled() {
+				log.FromContext(ctx).Info("Skipping the connection ConfigMap, as it is not owned by the cluster",
+					"configMap", livingConfigMap.Name)
+			}
+			return nil
+		}
+	}
+
+	if !cluster.IsMultiHostConnectionEnabled() {
+		if !found {
+			return nil
+		}
+		return client.IgnoreNotFound(r.Client.Delete(ctx, &livingConfigMap))
+	}
+
+	proposed := specs.CreateConnectionConfigMap(*cluster)
+	cluster.SetInheritedDataAndOwnership(&proposed.ObjectMeta)
+
+	if !found {
+		return r.Client.Create(ctx, proposed)
+	}
+
+	if reflect.DeepEqual(livingConfigMap.Data, proposed.Data) {
+		return nil
+	}
+
+	patchedConfigMap := livingConfigMap.DeepCopy()
+	patchedConfigMap.Data = proposed.Data
+	return r.Client.Patch(ctx, patchedConfigMap, client.MergeFrom(&livingConfigMap))
+}
+
+// reconcileManagedServices ensures that the additional services defined
+// in the cluster exist, deleting the ones that have been removed
+func (r *ClusterReconciler) reconcileManagedServices(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	expectedServices := make(map[string]bool)
+	for _, managedService := range specs.CreateClusterManagedServices(*cluster) {
+		cluster.SetInheritedDataAndOwnership(&managedService.ObjectMeta)
+		expectedServices[managedService.Name] = true
+
+		if err := r.serviceReconciler(ctx, managedService, true); err != nil {
+			return err
+		}
+	}
+
+	var livingServices corev1.ServiceList
+	if err := r.List(
+		ctx,
+		&livingServices,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			utils.ClusterLabelName:   cluster.Name,
+			utils.IsManagedLabelName: "true",
+		},
+	); err != nil {
+		return err
+	}
+
+	for idx := range livingServices.Items {
+		service := &livingServices.Items[idx]
+		if expectedServices[service.Name] {
+			continue
+		}
+		if ownerName, isOwned := IsOwnedByCluster(service); !isOwned || ownerName != cluster.Name {
+			continue
+		}
+
+		contextLogger.Info("Deleting managed service not defined in the cluster anymore", "service", service.Name)
+		if err := r.Delete(ctx, service); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (r *ClusterReconciler) serviceReconciler(ctx context.Context, proposed *corev1.Service) error {
+// serviceReconciler creates or updates a service. The type of the existing
+// service is reconciled only if enforceType is true
+func (r *ClusterReconciler) serviceReconciler(
+	ctx context.Context,
+	proposed *corev1.Service,
+	enforceType bool,
+) error {
 	var livingService corev1.Service
 	err := r.Client.Get(ctx, types.NamespacedName{Name: proposed.Name, Namespace: proposed.Namespace}, &livingService)
 	if apierrs.IsNotFound(err) {
@@ -349,6 +545,13 @@ func (r *ClusterReconciler) serviceReconciler(ctx context.Context, proposed *cor
 		shouldUpdate = true
 	}
 
+	// the ports need to be replaced too, to drop the allocated node ports
+	if enforceType && proposed.Spec.Type != livingService.Spec.Type {
+		livingService.Spec.Type = proposed.Spec.Type
+		livingService.Spec.Ports = proposed.Spec.Ports
+		shouldUpdate = true
+	}
+
 	// we ensure we've some space to store the labels and the annotations
 	if livingService.Labels == nil {
 		livingService.Labels = make(map[string]string)
@@ -393,7 +596,7 @@ func (r *ClusterReconciler) createOrPatchOwnedPodDisruptionBudget(
 			return fmt.Errorf("while getting PodDisruptionBudget: %w", err)
 		}
 
-		r.Recorder.Event(cluster, "Normal", "CreatingPodDisruptionBudget",
+		r.Recorder.Event(cluster, "Normal", events.CreatingPodDisruptionBudget,
 			fmt.Sprintf("Creating PodDisruptionBudget %s", pdb.Name))
 		if err = r.Create(ctx, pdb); err != nil {
 			return fmt.Errorf("while creating PodDisruptionBudget: %w", err)
@@ -410,7 +613,7 @@ func (r *ClusterReconciler) createOrPatchOwnedPodDisruptionBudget(
 		return nil
 	}
 
-	r.Recorder.Event(cluster, "Normal", "UpdatingPodDisruptionBudget",
+	r.Recorder.Event(cluster, "Normal", events.UpdatingPodDisruptionBudget,
 		fmt.Sprintf("Updating PodDisruptionBudget %s", pdb.Name))
 
 	if err := r.Patch(ctx, patchedPdb, client.MergeFrom(&oldPdb)); err != nil {
@@ -463,7 +666,7 @@ func (r *ClusterReconciler) deletePodDisruptionBudget(
 
 	r.Recorder.Event(cluster,
 		"Normal",
-		"DeletingPodDisruptionBudget",
+		events.DeletingPodDisruptionBudget,
 		"Deleting Pod Disruption Budget "+key.Name)
 
 	err = r.Delete(ctx, &targetPdb)
@@ -485,7 +688,7 @@ func (r *ClusterReconciler) createOrPatchServiceAccount(ctx context.Context, clu
 			return fmt.Errorf("while getting service account: %w", err)
 		}
 
-		r.Recorder.Event(cluster, "Normal", "CreatingServiceAccount", "Creating ServiceAccount")
+		r.Recorder.Event(cluster, "Normal", events.CreatingServiceAccount, "Creating ServiceAccount")
 		return r.createServiceAccount(ctx, cluster)
 	}
 
@@ -507,7 +710,7 @@ func (r *ClusterReconciler) createOrPatchServiceAccount(ctx context.Context, clu
 		return nil
 	}
 
-	r.Recorder.Event(cluster, "Normal", "UpdatingServiceAccount", "Updating ServiceAccount")
+	r.Recorder.Event(cluster, "Normal", events.UpdatingServiceAccount, "Updating ServiceAccount")
 	if err := r.Patch(ctx, &sa, client.MergeFrom(origSa)); err != nil {
 		return fmt.Errorf("while patching service account: %w", err)
 	}
@@ -626,7 +829,7 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 			return fmt.Errorf("while getting role: %w", err)
 		}
 
-		r.Recorder.Event(cluster, "Normal", "CreatingRole", "Creating Cluster Role")
+		r.Recorder.Event(cluster, "Normal", events.CreatingRole, "Creating Cluster Role")
 		return r.createRole(ctx, cluster, originBackup)
 	}
 
@@ -636,7 +839,7 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		return nil
 	}
 
-	r.Recorder.Event(cluster, "Normal", "UpdatingRole", "Updating Cluster Role")
+	r.Recorder.Event(cluster, "Normal", events.UpdatingRole, "Updating Cluster Role")
 
 	// The configuration changed, and we need the patch the
 	// configMap we have
@@ -991,6 +1194,7 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	// If the cluster is bootstrapping from recovery, it may do so from:
 	//  1 - a backup object, which may be done with volume snapshots or object storage
 	//  2 - volume snapshots
+	//  3 - existing PVCs to be adopted
 	// We need to check that whichever alternative is used, the backup/snapshot is completed.
 	if cluster.Spec.Bootstrap != nil &&
 		cluster.Spec.Bootstrap.Recovery != nil {
@@ -1031,6 +1235,10 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	isBootstrappingFromRecovery := cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil
 	isBootstrappingFromBaseBackup := cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.PgBaseBackup != nil
 	switch {
+	case isBootstrappingFromRecovery && cluster.Spec.Bootstrap.Recovery.VolumeSource != nil:
+		r.Recorder.Event(cluster, "Normal", events.CreatingInstance, "Primary instance (from volumeSource)")
+		job = specs.CreatePrimaryJobViaVolumeSource(*cluster, nodeSerial)
+
 	case isBootstrappingFromRecovery && recoverySnapshot != nil:
 		var snapshot volumesnapshot.VolumeSnapshot
 		if err := r.Client.Get(ctx,
@@ -1038,19 +1246,19 @@ func (r *ClusterReconciler) createPrimaryInstance(
 			&snapshot); err != nil {
 			return ctrl.Result{}, err
 		}
-		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from volumeSnapshots)")
+		r.Recorder.Event(cluster, "Normal", events.CreatingInstance, "Primary instance (from volumeSnapshots)")
 		job = specs.CreatePrimaryJobViaRestoreSnapshot(*cluster, nodeSerial, snapshot, backup)
 
 	case isBootstrappingFromRecovery:
-		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from backup)")
+		r.Recorder.Event(cluster, "Normal", events.CreatingInstance, "Primary instance (from backup)")
 		job = specs.CreatePrimaryJobViaRecovery(*cluster, nodeSerial, backup)
 
 	case isBootstrappingFromBaseBackup:
-		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from physical backup)")
+		r.Recorder.Event(cluster, "Normal", events.CreatingInstance, "Primary instance (from physical backup)")
 		job = specs.CreatePrimaryJobViaPgBaseBackup(*cluster, nodeSerial)
 
 	default:
-		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (initdb)")
+		r.Recorder.Event(cluster, "Normal", events.CreatingInstance, "Primary instance (initdb)")
 		job = specs.CreatePrimaryJobViaInitdb(*cluster, nodeSerial)
 	}
 
@@ -1114,7 +1322,7 @@ func (r *ClusterReconciler) getOriginBackup(ctx context.Context, cluster *apiv1.
 	err := r.Get(ctx, backupObjectKey, &backup)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
-			r.Recorder.Eventf(cluster, "Warning", "ErrorNoBackup",
+			r.Recorder.Eventf(cluster, "Warning", events.ErrorNoBackup,
 				"Backup object \"%v/%v\" is missing",
 				backupObjectKey.Namespace, backupObjectKey.Name)
 
@@ -1158,7 +1366,7 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		"role", job.Spec.Template.ObjectMeta.Labels[utils.JobRoleLabelName],
 	)
 
-	r.Recorder.Eventf(cluster, "Normal", "CreatingInstance",
+	r.Recorder.Eventf(cluster, "Normal", events.CreatingInstance,
 		"Creating instance %v-%v", cluster.Name, nodeSerial)
 
 	if err := r.RegisterPhase(ctx, cluster,
@@ -1355,7 +1563,29 @@ func findInstancePodToCreate(
 	return nil, nil
 }
 
-// checkReadyForRecovery checks if the backup or volumeSnapshots are ready, and
+// getBackupSourceCluster gets the cluster which took the passed backup,
+// returning nil if it doesn't exist anymore
+func (r *ClusterReconciler) getBackupSourceCluster(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (*apiv1.Cluster, error) {
+	if backup.Spec.Cluster.Name == "" {
+		return nil, nil
+	}
+
+	var sourceCluster apiv1.Cluster
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.Cluster.Name}, &sourceCluster)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sourceCluster, nil
+}
+
+// checkReadyForRecovery checks if the backup, volumeSnapshots or volumeSource are ready, and
 // returns for requeue if not
 func (r *ClusterReconciler) checkReadyForRecovery(
 	ctx context.Context,
@@ -1382,6 +1612,37 @@ func (r *ClusterReconciler) checkReadyForRecovery(
 				RequeueAfter: time.Minute,
 			}, nil
 		}
+
+		sourceCluster, err := r.getBackupSourceCluster(ctx, backup)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		recoveryTarget := cluster.Spec.Bootstrap.Recovery.RecoveryTarget
+		err = backup.ValidateRecoveryTarget(recoveryTarget)
+		if err == nil {
+			err = backup.ValidateRecoveryTargetArchived(recoveryTarget, sourceCluster)
+		}
+		if err != nil {
+			contextLogger.Warning("The recovery target can't be reached, can't continue full recovery",
+				"backup", cluster.Spec.Bootstrap.Recovery.Backup,
+				"err", err.Error())
+			r.Recorder.Event(cluster, "Warning", events.RecoveryTargetUnreachable, err.Error())
+			if errCond := conditions.Patch(
+				ctx, r.Client, cluster, apiv1.BuildRecoveryTargetUnreachableCondition(err)); errCond != nil {
+				return ctrl.Result{}, errCond
+			}
+			return ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: time.Minute,
+			}, nil
+		}
+
+		if cluster.Spec.Bootstrap.Recovery.RecoveryTarget != nil {
+			if err := conditions.Patch(ctx, r.Client, cluster, apiv1.RecoveryTargetReachableCondition); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 	}
 
 	volumeSnapshotsRecovery := cluster.Spec.Bootstrap.Recovery.VolumeSnapshots
@@ -1405,5 +1666,27 @@ func (r *ClusterReconciler) checkReadyForRecovery(
 				"status", status)
 		}
 	}
+
+	volumeSourceRecovery := cluster.Spec.Bootstrap.Recovery.VolumeSource
+	if volumeSourceRecovery != nil {
+		status, err := persistentvolumeclaim.VerifyVolumeSourceCoherence(
+			ctx, r.Client, cluster.Namespace, volumeSourceRecovery)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if status.ContainsErrors() {
+			contextLogger.Warning(
+				"Volume source verification failed, retrying",
+				"status", status)
+			return ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: 5 * time.Second,
+			}, nil
+		}
+		if status.ContainsWarnings() {
+			contextLogger.Warning("Volume source verification warnings",
+				"status", status)
+		}
+	}
 	return ctrl.Result{}, nil
 }
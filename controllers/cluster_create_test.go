@@ -18,12 +18,14 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	volumesnapshot "github.com/kubernetes-csi/external-snapshotter/client/v7/apis/volumesnapshot/v1"
 	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -37,7 +39,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -136,6 +137,25 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 
+	It("removes the generated secrets of the passwords read from the Secrets Store", func(ctx SpecContext) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		cluster.Spec.EnableSuperuserAccess = ptr.To(true)
+
+		Expect(env.clusterReconciler.reconcilePostgresSecrets(ctx, cluster)).To(Succeed())
+		expectResourceExists(env.client, cluster.GetSuperuserSecretName(), namespace, &corev1.Secret{})
+		expectResourceExists(env.client, cluster.GetApplicationSecretName(), namespace, &corev1.Secret{})
+
+		cluster.Spec.SecretsStore = &apiv1.SecretsStoreConfiguration{
+			SecretProviderClass:     "vault-passwords",
+			SuperuserPasswordFile:   "superuser-password",
+			ApplicationPasswordFile: "app-password",
+		}
+		Expect(env.clusterReconciler.reconcilePostgresSecrets(ctx, cluster)).To(Succeed())
+		expectResourceDoesntExist(env.client, cluster.GetSuperuserSecretName(), namespace, &corev1.Secret{})
+		expectResourceDoesntExist(env.client, cluster.GetApplicationSecretName(), namespace, &corev1.Secret{})
+	})
+
 	It("should make sure that reconcilePostgresServices works correctly", func(ctx SpecContext) {
 		namespace := newFakeNamespace(env.client)
 		cluster := newFakeCNPGCluster(env.client, namespace)
@@ -152,11 +172,13 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 
-	It("should make sure that reconcilePostgresServices works correctly if create any service is enabled",
+	It("should make sure that reconcilePostgresServices works correctly if the multi-host connection is enabled",
 		func(ctx SpecContext) {
 			namespace := newFakeNamespace(env.client)
 			cluster := newFakeCNPGCluster(env.client, namespace)
-			configuration.Current.CreateAnyService = true
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Services: &apiv1.ManagedServices{MultiHostConnection: true},
+			}
 
 			By("executing reconcilePostgresServices", func() {
 				err := env.clusterReconciler.reconcilePostgresServices(ctx, cluster)
@@ -175,7 +197,9 @@ var _ = Describe("cluster_create unit tests", func() {
 		func(ctx SpecContext) {
 			namespace := newFakeNamespace(env.client)
 			cluster := newFakeCNPGCluster(env.client, namespace)
-			configuration.Current.CreateAnyService = true
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Services: &apiv1.ManagedServices{MultiHostConnection: true},
+			}
 
 			createOutdatedService := func(svc *corev1.Service) {
 				cluster.SetInheritedDataAndOwnership(&svc.ObjectMeta)
@@ -406,6 +430,53 @@ var _ = Describe("cluster_create unit tests", func() {
 			)
 		})
 	})
+
+	It("prevents the eviction of the replicas outside the maintenance windows", func(ctx SpecContext) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			// Once a year, for a second
+			cluster.Spec.MaintenanceWindows = []apiv1.MaintenanceWindow{
+				{Schedule: "0 0 0 1 1 *", Duration: metav1.Duration{Duration: time.Second}},
+			}
+		})
+		pdbReplicaName := specs.BuildReplicasPodDisruptionBudget(cluster).Name
+		getReplicasMinAvailable := func() int32 {
+			var pdb policyv1.PodDisruptionBudget
+			Expect(env.client.Get(ctx, types.NamespacedName{Name: pdbReplicaName, Namespace: namespace}, &pdb)).
+				To(Succeed())
+			return pdb.Spec.MinAvailable.IntVal
+		}
+
+		Expect(env.clusterReconciler.reconcilePodDisruptionBudget(ctx, cluster)).To(Succeed())
+		Expect(getReplicasMinAvailable()).To(BeEquivalentTo(cluster.Spec.Instances - 1))
+
+		// Every second, for an hour
+		cluster.Spec.MaintenanceWindows = []apiv1.MaintenanceWindow{
+			{Schedule: "* * * * * *", Duration: metav1.Duration{Duration: time.Hour}},
+		}
+		Expect(env.clusterReconciler.reconcilePodDisruptionBudget(ctx, cluster)).To(Succeed())
+		Expect(getReplicasMinAvailable()).To(BeEquivalentTo(cluster.Spec.Instances - 2))
+	})
+
+	It("removes the replicas PDB which is no more needed", func(ctx SpecContext) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Instances = 2
+			// Once a year, for a second
+			cluster.Spec.MaintenanceWindows = []apiv1.MaintenanceWindow{
+				{Schedule: "0 0 0 1 1 *", Duration: metav1.Duration{Duration: time.Second}},
+			}
+		})
+		pdbReplicaName := specs.BuildBlockingReplicasPodDisruptionBudget(cluster).Name
+
+		Expect(env.clusterReconciler.reconcilePodDisruptionBudget(ctx, cluster)).To(Succeed())
+		Expect(env.client.Get(ctx, types.NamespacedName{Name: pdbReplicaName, Namespace: namespace},
+			&policyv1.PodDisruptionBudget{})).To(Succeed())
+
+		cluster.Spec.MaintenanceWindows = nil
+		Expect(env.clusterReconciler.reconcilePodDisruptionBudget(ctx, cluster)).To(Succeed())
+		expectResourceDoesntExist(env.client, pdbReplicaName, namespace, &policyv1.PodDisruptionBudget{})
+	})
 })
 
 var _ = Describe("check if bootstrap recovery can proceed", func() {
@@ -471,6 +542,137 @@ var _ = Describe("check if bootstrap recovery can proceed", func() {
 		Entry("when bootstrapping a backup that is not there",
 			nil, true),
 	)
+
+	It("refuses to proceed when the recovery target can't be reached", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName,
+				Namespace: namespace,
+			},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "1G",
+				},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						Backup: &apiv1.BackupSource{
+							LocalObjectReference: apiv1.LocalObjectReference{
+								Name: name,
+							},
+						},
+						RecoveryTarget: &apiv1.RecoveryTarget{
+							TargetTime: "2024-01-01 09:00:00Z",
+						},
+					},
+				},
+			},
+		}
+		Expect(env.client.Create(ctx, cluster)).To(Succeed())
+
+		backup := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				Phase:     apiv1.BackupPhaseCompleted,
+				BackupID:  "20240101T100000",
+				StoppedAt: &metav1.Time{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		}
+
+		res, err := env.clusterReconciler.checkReadyForRecovery(ctx, backup, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(Equal(reconcile.Result{}))
+
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionRecoveryTargetReachable))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRecoveryTargetUnreachable)))
+
+		By("accepting a recovery target after the end of the backup", func() {
+			cluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetTime = "2024-01-01 11:00:00Z"
+			res, err := env.clusterReconciler.checkReadyForRecovery(ctx, backup, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(reconcile.Result{}))
+
+			Expect(env.client.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+				string(apiv1.ConditionRecoveryTargetReachable))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	It("refuses a recovery target time not yet archived by the source cluster", func(ctx SpecContext) {
+		sourceCluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "source",
+				Namespace: namespace,
+			},
+		}
+		Expect(env.client.Create(ctx, sourceCluster)).To(Succeed())
+		sourceCluster.Status.LastRecoverabilityPoint = "2024-01-01T10:30:00Z"
+		Expect(env.client.Status().Update(ctx, sourceCluster)).To(Succeed())
+
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName,
+				Namespace: namespace,
+			},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "1G",
+				},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						Backup: &apiv1.BackupSource{
+							LocalObjectReference: apiv1.LocalObjectReference{
+								Name: name,
+							},
+						},
+						RecoveryTarget: &apiv1.RecoveryTarget{
+							TargetTime: "2024-01-01 11:00:00Z",
+						},
+					},
+				},
+			},
+		}
+		Expect(env.client.Create(ctx, cluster)).To(Succeed())
+
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: sourceCluster.Name},
+			},
+			Status: apiv1.BackupStatus{
+				Phase:     apiv1.BackupPhaseCompleted,
+				BackupID:  "20240101T100000",
+				StoppedAt: &metav1.Time{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		}
+
+		res, err := env.clusterReconciler.checkReadyForRecovery(ctx, backup, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(Equal(reconcile.Result{}))
+
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionRecoveryTargetReachable))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("after the last WAL file archived by cluster source"))
+
+		By("accepting a recovery target time already archived", func() {
+			cluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetTime = "2024-01-01 10:15:00Z"
+			res, err := env.clusterReconciler.checkReadyForRecovery(ctx, backup, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(reconcile.Result{}))
+		})
+	})
 })
 
 var _ = Describe("check if bootstrap recovery can proceed from volume snapshot", func() {
@@ -1117,3 +1319,263 @@ var _ = Describe("deletePodDisruptionBudgetIfExists", func() {
 		Expect(apierrs.IsNotFound(err)).To(BeTrue())
 	})
 })
+
+var _ = Describe("reconcileManagedServices", func() {
+	var (
+		fakeClient k8client.Client
+		reconciler *ClusterReconciler
+		cluster    *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-uid",
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Services: &apiv1.ManagedServices{
+						Additional: []apiv1.ManagedService{
+							{
+								Name:         "cluster-example-ro-lb",
+								SelectorType: apiv1.ServiceSelectorTypeRO,
+								ServiceTemplate: apiv1.ServiceTemplateSpec{
+									ObjectMeta: apiv1.Metadata{
+										Annotations: map[string]string{"lb": "internal"},
+									},
+									Spec: corev1.ServiceSpec{
+										Type: corev1.ServiceTypeLoadBalancer,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).Build()
+		reconciler = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+		}
+	})
+
+	getService := func(ctx context.Context, name string) (*corev1.Service, error) {
+		var service corev1.Service
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, &service)
+		return &service, err
+	}
+
+	It("creates the additional services", func(ctx SpecContext) {
+		Expect(reconciler.reconcileManagedServices(ctx, cluster)).To(Succeed())
+
+		service, err := getService(ctx, "cluster-example-ro-lb")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(service.Spec.Selector).To(Equal(map[string]string{
+			utils.ClusterLabelName:     cluster.Name,
+			utils.ClusterRoleLabelName: specs.ClusterRoleLabelReplica,
+		}))
+		Expect(service.Labels).To(HaveKeyWithValue(utils.IsManagedLabelName, "true"))
+		Expect(service.Annotations).To(HaveKeyWithValue("lb", "internal"))
+		Expect(metav1.IsControlledBy(service, cluster)).To(BeTrue())
+	})
+
+	It("updates the type of the additional services", func(ctx SpecContext) {
+		Expect(reconciler.reconcileManagedServices(ctx, cluster)).To(Succeed())
+
+		cluster.Spec.Managed.Services.Additional[0].ServiceTemplate.Spec.Type = corev1.ServiceTypeNodePort
+		Expect(reconciler.reconcileManagedServices(ctx, cluster)).To(Succeed())
+
+		service, err := getService(ctx, "cluster-example-ro-lb")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+	})
+
+	It("deletes the additional services removed from the cluster", func(ctx SpecContext) {
+		Expect(reconciler.reconcileManagedServices(ctx, cluster)).To(Succeed())
+
+		notOwnedService := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "not-owned",
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					utils.ClusterLabelName:   cluster.Name,
+					utils.IsManagedLabelName: "true",
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, notOwnedService)).To(Succeed())
+
+		cluster.Spec.Managed.Services = nil
+		Expect(reconciler.reconcileManagedServices(ctx, cluster)).To(Succeed())
+
+		_, err := getService(ctx, "cluster-example-ro-lb")
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		_, err = getService(ctx, "not-owned")
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("multi-host connection", func() {
+	var (
+		fakeClient k8client.Client
+		reconciler *ClusterReconciler
+		cluster    *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-uid",
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Services: &apiv1.ManagedServices{
+						MultiHostConnection: true,
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				InstanceNames:  []string{"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).Build()
+		reconciler = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+		}
+	})
+
+	getConfigMap := func(ctx context.Context) (*corev1.ConfigMap, error) {
+		var configMap corev1.ConfigMap
+		err := fakeClient.Get(
+			ctx,
+			types.NamespacedName{Name: cluster.GetConnectionConfigMapName(), Namespace: cluster.Namespace},
+			&configMap)
+		return &configMap, err
+	}
+
+	It("publishes the headless any service and the connection strings", func(ctx SpecContext) {
+		Expect(reconciler.reconcilePostgresServices(ctx, cluster)).To(Succeed())
+
+		var service corev1.Service
+		Expect(fakeClient.Get(
+			ctx,
+			types.NamespacedName{Name: cluster.GetServiceAnyName(), Namespace: cluster.Namespace},
+			&service)).To(Succeed())
+		Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+
+		configMap, err := getConfigMap(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(configMap, cluster)).To(BeTrue())
+		Expect(configMap.Data["hosts"]).To(Equal(
+			"cluster-example-2.cluster-example-any.default.svc:5432," +
+				"cluster-example-1.cluster-example-any.default.svc:5432," +
+				"cluster-example-3.cluster-example-any.default.svc:5432"))
+	})
+
+	It("lists the new primary first after a switchover", func(ctx SpecContext) {
+		Expect(reconciler.reconcileConnectionConfigMap(ctx, cluster)).To(Succeed())
+
+		cluster.Status.CurrentPrimary = "cluster-example-3"
+		Expect(reconciler.reconcileConnectionConfigMap(ctx, cluster)).To(Succeed())
+
+		configMap, err := getConfigMap(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configMap.Data["uri"]).To(HavePrefix(
+			"postgresql://cluster-example-3.cluster-example-any.default.svc:5432,"))
+	})
+
+	newAnyService := func() *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetServiceAnyName(),
+				Namespace: cluster.Namespace,
+			},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: "10.0.0.10",
+			},
+		}
+	}
+
+	It("recreates an existing any service as a headless one", func(ctx SpecContext) {
+		service := newAnyService()
+		cluster.SetInheritedDataAndOwnership(&service.ObjectMeta)
+		Expect(fakeClient.Create(ctx, service)).To(Succeed())
+
+		Expect(reconciler.reconcileAnyService(ctx, cluster)).To(Succeed())
+
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(service), service)).To(Succeed())
+		Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+	})
+
+	It("deletes the any service when the multi-host connection is disabled", func(ctx SpecContext) {
+		service := newAnyService()
+		cluster.SetInheritedDataAndOwnership(&service.ObjectMeta)
+		Expect(fakeClient.Create(ctx, service)).To(Succeed())
+
+		cluster.Spec.Managed.Services.MultiHostConnection = false
+		Expect(reconciler.reconcileAnyService(ctx, cluster)).To(Succeed())
+
+		err := fakeClient.Get(ctx, k8client.ObjectKeyFromObject(service), service)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("doesn't touch an any service not owned by the cluster", func(ctx SpecContext) {
+		service := newAnyService()
+		Expect(fakeClient.Create(ctx, service)).To(Succeed())
+
+		cluster.Spec.Managed.Services.MultiHostConnection = false
+		Expect(reconciler.reconcileAnyService(ctx, cluster)).To(Succeed())
+
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(service), service)).To(Succeed())
+		Expect(service.Spec.ClusterIP).To(Equal("10.0.0.10"))
+	})
+
+	It("deletes the connection strings when they are no longer required", func(ctx SpecContext) {
+		Expect(reconciler.reconcileConnectionConfigMap(ctx, cluster)).To(Succeed())
+
+		cluster.Spec.Managed.Services.MultiHostConnection = false
+		Expect(reconciler.reconcileConnectionConfigMap(ctx, cluster)).To(Succeed())
+
+		_, err := getConfigMap(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("doesn't touch a ConfigMap not owned by the cluster", func(ctx SpecContext) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetConnectionConfigMapName(),
+				Namespace: cluster.Namespace,
+			},
+			Data: map[string]string{"user": "data"},
+		}
+		Expect(fakeClient.Create(ctx, configMap)).To(Succeed())
+
+		Expect(reconciler.reconcileConnectionConfigMap(ctx, cluster)).To(Succeed())
+
+		configMap, err := getConfigMap(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configMap.Data).To(Equal(map[string]string{"user": "data"}))
+	})
+})
@@ -22,10 +22,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 )
 
 // deleteDanglingMonitoringQueries deletes the default monitoring configMap and/or secret if no cluster in the namespace
@@ -87,3 +90,56 @@ func (r *ClusterReconciler) deleteDanglingMonitoringQueries(ctx context.Context,
 
 	return nil
 }
+
+// releaseLogicalReplicationFinalizers removes the finalizers of the publications
+// and subscriptions of a deleted cluster. They are handled by the instance
+// manager of the primary, and without it they would block the deletion of
+// those objects forever
+func (r *ClusterReconciler) releaseLogicalReplicationFinalizers(
+	ctx context.Context,
+	cluster types.NamespacedName,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var publications apiv1.PublicationList
+	if err := r.List(ctx, &publications, client.InNamespace(cluster.Namespace)); err != nil {
+		return err
+	}
+	for idx := range publications.Items {
+		publication := &publications.Items[idx]
+		if publication.Spec.ClusterRef.Name != cluster.Name ||
+			!controllerutil.ContainsFinalizer(publication, apiv1.PublicationFinalizerName) {
+			continue
+		}
+
+		contextLogger.Info("Cluster deleted, removing the finalizer of the publication",
+			"publication", publication.Name)
+		origPublication := publication.DeepCopy()
+		controllerutil.RemoveFinalizer(publication, apiv1.PublicationFinalizerName)
+		if err := r.Patch(ctx, publication, client.MergeFrom(origPublication)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	var subscriptions apiv1.SubscriptionList
+	if err := r.List(ctx, &subscriptions, client.InNamespace(cluster.Namespace)); err != nil {
+		return err
+	}
+	for idx := range subscriptions.Items {
+		subscription := &subscriptions.Items[idx]
+		if subscription.Spec.ClusterRef.Name != cluster.Name ||
+			!controllerutil.ContainsFinalizer(subscription, apiv1.SubscriptionFinalizerName) {
+			continue
+		}
+
+		contextLogger.Info("Cluster deleted, removing the finalizer of the subscription",
+			"subscription", subscription.Name)
+		origSubscription := subscription.DeepCopy()
+		controllerutil.RemoveFinalizer(subscription, apiv1.SubscriptionFinalizerName)
+		if err := r.Patch(ctx, subscription, client.MergeFrom(origSubscription)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -21,7 +21,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -132,3 +134,71 @@ var _ = Describe("ensures that deleteDanglingMonitoringQueries works correctly",
 		})
 	})
 })
+
+var _ = Describe("releaseLogicalReplicationFinalizers", func() {
+	var env *testingEnvironment
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+	})
+
+	It("removes the finalizers of the publications and subscriptions of the deleted cluster", func(ctx SpecContext) {
+		namespace := newFakeNamespace(env.client)
+
+		newPublication := func(name, clusterName string) *apiv1.Publication {
+			return &apiv1.Publication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  namespace,
+					Finalizers: []string{apiv1.PublicationFinalizerName},
+				},
+				Spec: apiv1.PublicationSpec{
+					ClusterRef: apiv1.LocalObjectReference{Name: clusterName},
+					Name:       name,
+					DBName:     "app",
+				},
+			}
+		}
+		newSubscription := func(name, clusterName string) *apiv1.Subscription {
+			return &apiv1.Subscription{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  namespace,
+					Finalizers: []string{apiv1.SubscriptionFinalizerName},
+				},
+				Spec: apiv1.SubscriptionSpec{
+					ClusterRef:          apiv1.LocalObjectReference{Name: clusterName},
+					Name:                name,
+					DBName:              "app",
+					PublicationName:     "pub",
+					ExternalClusterName: "source",
+				},
+			}
+		}
+
+		objects := []client.Object{
+			newPublication("deleted-pub", "deleted"),
+			newPublication("other-pub", "other"),
+			newSubscription("deleted-sub", "deleted"),
+			newSubscription("other-sub", "other"),
+		}
+		for _, object := range objects {
+			Expect(env.client.Create(ctx, object)).To(Succeed())
+		}
+
+		Expect(env.clusterReconciler.releaseLogicalReplicationFinalizers(
+			ctx, types.NamespacedName{Namespace: namespace, Name: "deleted"})).To(Succeed())
+
+		var deletedPublication, otherPublication apiv1.Publication
+		expectResourceExists(env.client, "deleted-pub", namespace, &deletedPublication)
+		Expect(deletedPublication.Finalizers).To(BeEmpty())
+		expectResourceExists(env.client, "other-pub", namespace, &otherPublication)
+		Expect(otherPublication.Finalizers).To(ConsistOf(apiv1.PublicationFinalizerName))
+
+		var deletedSubscription, otherSubscription apiv1.Subscription
+		expectResourceExists(env.client, "deleted-sub", namespace, &deletedSubscription)
+		Expect(deletedSubscription.Finalizers).To(BeEmpty())
+		expectResourceExists(env.client, "other-sub", namespace, &otherSubscription)
+		Expect(otherSubscription.Finalizers).To(ConsistOf(apiv1.SubscriptionFinalizerName))
+	})
+})
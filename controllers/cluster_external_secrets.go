@@ -0,0 +1,289 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// externalClusterSecretsNamespaceKey is the name of the field index
+// containing the namespaces external clusters take their secrets from
+const externalClusterSecretsNamespaceKey = ".spec.externalClusters.secretsNamespace"
+
+// reconcileExternalClusterSecrets allows the ServiceAccount of the cluster
+// to read the secrets its external clusters take from other namespaces, as
+// long as they are granted by a ReplicationGrant in the source namespace.
+// The access to the namespaces not used anymore is revoked
+func (r *ClusterReconciler) reconcileExternalClusterSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
+	grantedSecrets, err := r.getGrantedExternalClusterSecrets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	for namespace, secrets := range grantedSecrets {
+		secretNames := secrets.ToSortedList()
+		if err := r.reconcileExternalSecretsRole(ctx, cluster, namespace, secretNames); err != nil {
+			return fmt.Errorf("while granting the secrets of namespace %s: %w", namespace, err)
+		}
+	}
+
+	return r.deleteExternalSecretsAccess(ctx, cluster, func(namespace string) bool {
+		_, granted := grantedSecrets[namespace]
+		return !granted
+	})
+}
+
+// getGrantedExternalClusterSecrets gets, for every namespace the external
+// clusters take secrets from, the names of the secrets granted to the cluster
+func (r *ClusterReconciler) getGrantedExternalClusterSecrets(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (map[string]*stringset.Data, error) {
+	contextLogger := log.FromContext(ctx)
+	grantedSecrets := make(map[string]*stringset.Data)
+
+	for _, externalCluster := range cluster.Spec.ExternalClusters {
+		namespace := externalCluster.GetSecretsNamespace(cluster.Namespace)
+		if namespace == cluster.Namespace {
+			continue
+		}
+
+		if !isNamespaceWatched(namespace) {
+			contextLogger.Warning("Ignoring the secrets of a namespace not watched by this operator shard",
+				"externalCluster", externalCluster.Name, "secretsNamespace", namespace)
+			continue
+		}
+
+		var grants apiv1.ReplicationGrantList
+		if err := r.List(ctx, &grants, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("while listing the replication grants in namespace %s: %w", namespace, err)
+		}
+
+		for _, secretName := range externalCluster.GetSecretsNames().ToSortedList() {
+			granted := slices.ContainsFunc(grants.Items, func(grant apiv1.ReplicationGrant) bool {
+				return grant.Allows(cluster, secretName)
+			})
+			if !granted {
+				r.Recorder.Eventf(cluster, "Warning", events.SecretNotGranted,
+					"Secret %s/%s is not granted to this cluster by any ReplicationGrant", namespace, secretName)
+				continue
+			}
+
+			if grantedSecrets[namespace] == nil {
+				grantedSecrets[namespace] = stringset.New()
+			}
+			grantedSecrets[namespace].Put(secretName)
+		}
+	}
+
+	return grantedSecrets, nil
+}
+
+// reconcileExternalSecretsRole ensures that the ServiceAccount of the cluster
+// can read the passed secrets in the passed namespace
+func (r *ClusterReconciler) reconcileExternalSecretsRole(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	namespace string,
+	secretNames []string,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("secretsNamespace", namespace)
+
+	generatedRole := specs.CreateExternalSecretsRole(*cluster, namespace, secretNames)
+	var role rbacv1.Role
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&generatedRole), &role); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+
+		contextLogger.Info("Creating the Role to read the granted secrets")
+		r.Recorder.Eventf(cluster, "Normal", events.CreatingRole,
+			"Creating Role %s/%s to read the granted secrets", namespace, generatedRole.Name)
+		if err := r.Create(ctx, &generatedRole); err != nil {
+			return err
+		}
+	} else {
+		if !isExternalSecretsAccessOf(&role, cluster) {
+			return fmt.Errorf("role %s/%s already exists and doesn't belong to this cluster",
+				namespace, role.Name)
+		}
+
+		if !reflect.DeepEqual(role.Rules, generatedRole.Rules) {
+			contextLogger.Info("Updating the Role to read the granted secrets")
+			r.Recorder.Eventf(cluster, "Normal", events.UpdatingRole,
+				"Updating Role %s/%s to read the granted secrets", namespace, role.Name)
+			origRole := role.DeepCopy()
+			role.Rules = generatedRole.Rules
+			if err := r.Patch(ctx, &role, client.MergeFrom(origRole)); err != nil {
+				return err
+			}
+		}
+	}
+
+	generatedRoleBinding := specs.CreateExternalSecretsRoleBinding(*cluster, namespace)
+	var roleBinding rbacv1.RoleBinding
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&generatedRoleBinding), &roleBinding); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+
+		contextLogger.Info("Creating the RoleBinding to read the granted secrets")
+		return r.Create(ctx, &generatedRoleBinding)
+	}
+
+	if !isExternalSecretsAccessOf(&roleBinding, cluster) {
+		return fmt.Errorf("role binding %s/%s already exists and doesn't belong to this cluster",
+			namespace, roleBinding.Name)
+	}
+
+	return nil
+}
+
+// deleteExternalSecretsAccess deletes the Roles and the RoleBindings allowing
+// the cluster to read the secrets of the namespaces matching the passed filter
+func (r *ClusterReconciler) deleteExternalSecretsAccess(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	shouldDelete func(namespace string) bool,
+) error {
+	contextLogger := log.FromContext(ctx)
+	labels := client.MatchingLabels(specs.GetExternalSecretsLabels(*cluster))
+
+	var roleBindings rbacv1.RoleBindingList
+	if err := r.List(ctx, &roleBindings, labels); err != nil {
+		return fmt.Errorf("while listing the role bindings of the granted secrets: %w", err)
+	}
+	for idx := range roleBindings.Items {
+		roleBinding := &roleBindings.Items[idx]
+		if !shouldDelete(roleBinding.Namespace) {
+			continue
+		}
+		contextLogger.Info("Deleting the RoleBinding of the secrets not used anymore",
+			"secretsNamespace", roleBinding.Namespace, "name", roleBinding.Name)
+		if err := r.Delete(ctx, roleBinding); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	var roles rbacv1.RoleList
+	if err := r.List(ctx, &roles, labels); err != nil {
+		return fmt.Errorf("while listing the roles of the granted secrets: %w", err)
+	}
+	for idx := range roles.Items {
+		role := &roles.Items[idx]
+		if !shouldDelete(role.Namespace) {
+			continue
+		}
+		contextLogger.Info("Deleting the Role of the secrets not used anymore",
+			"secretsNamespace", role.Namespace, "name", role.Name)
+		if err := r.Delete(ctx, role); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isExternalSecretsAccessOf checks whether the passed object has been
+// created to let the passed cluster read the secrets of another namespace
+func isExternalSecretsAccessOf(object client.Object, cluster *apiv1.Cluster) bool {
+	labels := object.GetLabels()
+	return labels[utils.ClusterLabelName] == cluster.Name &&
+		labels[utils.ClusterNamespaceLabelName] == cluster.Namespace
+}
+
+// isNamespaceWatched checks whether the operator watches the passed namespace.
+// The namespaces of the other shards are not cached by this operator
+// deployment, so they are not considered watched
+func isNamespaceWatched(namespace string) bool {
+	if !configuration.Current.IsNamespaceInShard(namespace) {
+		return false
+	}
+
+	watchedNamespaces := configuration.Current.WatchedNamespaces()
+	return len(watchedNamespaces) == 0 || slices.Contains(watchedNamespaces, namespace)
+}
+
+// mapReplicationGrantsToClusters returns a function mapping the replication
+// grants to the clusters taking their secrets from the namespace of the grant
+func (r *ClusterReconciler) mapReplicationGrantsToClusters() handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		grant, ok := obj.(*apiv1.ReplicationGrant)
+		if !ok {
+			return nil
+		}
+
+		clusters, err := r.getClustersUsingSecretsOfNamespace(ctx, grant.Namespace)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "while getting the clusters for a replication grant",
+				"namespace", grant.Namespace, "name", grant.Name)
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(clusters.Items))
+		for _, cluster := range clusters.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name},
+			})
+		}
+		return requests
+	}
+}
+
+// getClustersUsingSecretsOfNamespace lists the clusters having an external
+// cluster taking its secrets from the passed namespace
+func (r *ClusterReconciler) getClustersUsingSecretsOfNamespace(
+	ctx context.Context,
+	namespace string,
+) (clusters apiv1.ClusterList, err error) {
+	err = r.List(ctx, &clusters, client.MatchingFields{externalClusterSecretsNamespaceKey: namespace})
+	return clusters, err
+}
+
+// externalClusterSecretsNamespaces is the indexing function for
+// externalClusterSecretsNamespaceKey
+func externalClusterSecretsNamespaces(rawObj client.Object) []string {
+	cluster := rawObj.(*apiv1.Cluster)
+
+	var namespaces []string
+	for _, externalCluster := range cluster.Spec.ExternalClusters {
+		namespace := externalCluster.SecretsNamespace
+		if namespace == "" || namespace == cluster.Namespace || slices.Contains(namespaces, namespace) {
+			continue
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
@@ -0,0 +1,193 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("secrets of external clusters in other namespaces", func() {
+	var (
+		cluster    *apiv1.Cluster
+		grant      *apiv1.ReplicationGrant
+		fakeClient k8client.Client
+		recorder   *record.FakeRecorder
+		r          *ClusterReconciler
+	)
+
+	accessKey := types.NamespacedName{Namespace: "production", Name: "dr-cluster-dr"}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-dr", Namespace: "dr"},
+			Spec: apiv1.ClusterSpec{
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name:             "cluster-example",
+						SecretsNamespace: "production",
+						SSLRootCert: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "cluster-example-ca"},
+							Key:                  "ca.crt",
+						},
+						Password: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "cluster-example-superuser"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+		}
+		grant = &apiv1.ReplicationGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "dr", Namespace: "production"},
+			Spec: apiv1.ReplicationGrantSpec{
+				Secrets:  []string{"cluster-example-ca"},
+				Grantees: []apiv1.ReplicationGrantee{{Namespace: "dr"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, grant).
+			Build()
+		recorder = record.NewFakeRecorder(120)
+		r = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: recorder,
+		}
+	})
+
+	It("lets the service account of the cluster read the granted secrets", func(ctx SpecContext) {
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+
+		var role rbacv1.Role
+		Expect(fakeClient.Get(ctx, accessKey, &role)).To(Succeed())
+		Expect(role.Rules).To(HaveLen(1))
+		Expect(role.Rules[0].ResourceNames).To(ConsistOf("cluster-example-ca"))
+
+		var roleBinding rbacv1.RoleBinding
+		Expect(fakeClient.Get(ctx, accessKey, &roleBinding)).To(Succeed())
+		Expect(roleBinding.Subjects).To(ConsistOf(rbacv1.Subject{
+			Kind:      "ServiceAccount",
+			Name:      "cluster-dr",
+			Namespace: "dr",
+		}))
+
+		Expect(recorder.Events).To(Receive(ContainSubstring("SecretNotGranted")))
+	})
+
+	It("updates the Role when more secrets are granted", func(ctx SpecContext) {
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+
+		grant.Spec.Secrets = append(grant.Spec.Secrets, "cluster-example-superuser")
+		Expect(fakeClient.Update(ctx, grant)).To(Succeed())
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+
+		var role rbacv1.Role
+		Expect(fakeClient.Get(ctx, accessKey, &role)).To(Succeed())
+		Expect(role.Rules[0].ResourceNames).To(ConsistOf("cluster-example-ca", "cluster-example-superuser"))
+	})
+
+	It("revokes the access when the grant is deleted", func(ctx SpecContext) {
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+		Expect(fakeClient.Delete(ctx, grant)).To(Succeed())
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+
+		Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.Role{}))).To(BeTrue())
+		Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.RoleBinding{}))).To(BeTrue())
+	})
+
+	It("revokes the access when the secrets namespace is removed", func(ctx SpecContext) {
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+
+		cluster.Spec.ExternalClusters[0].SecretsNamespace = ""
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+
+		Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.Role{}))).To(BeTrue())
+		Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.RoleBinding{}))).To(BeTrue())
+	})
+
+	It("revokes the access when the cluster is deleted", func(ctx SpecContext) {
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+		Expect(fakeClient.Delete(ctx, cluster)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: k8client.ObjectKeyFromObject(cluster)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.Role{}))).To(BeTrue())
+		Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.RoleBinding{}))).To(BeTrue())
+	})
+
+	Context("when the grant is for another cluster", func() {
+		BeforeEach(func() {
+			grant.Spec.Grantees = []apiv1.ReplicationGrantee{{Namespace: "dr", Cluster: "another"}}
+		})
+
+		It("doesn't grant any access", func(ctx SpecContext) {
+			Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+
+			Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.Role{}))).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("SecretNotGranted")))
+		})
+	})
+
+	It("doesn't take over a Role it didn't create", func(ctx SpecContext) {
+		Expect(fakeClient.Create(ctx, &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Namespace: accessKey.Namespace, Name: accessKey.Name},
+		})).To(Succeed())
+
+		Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).ToNot(Succeed())
+
+		var role rbacv1.Role
+		Expect(fakeClient.Get(ctx, accessKey, &role)).To(Succeed())
+		Expect(role.Rules).To(BeEmpty())
+	})
+
+	Context("when the operator doesn't watch the secrets namespace", func() {
+		BeforeEach(func() {
+			previousWatchNamespace := configuration.Current.WatchNamespace
+			configuration.Current.WatchNamespace = "dr"
+			DeferCleanup(func() {
+				configuration.Current.WatchNamespace = previousWatchNamespace
+			})
+		})
+
+		It("ignores the secrets namespace", func(ctx SpecContext) {
+			Expect(r.reconcileExternalClusterSecrets(ctx, cluster)).To(Succeed())
+			Expect(apierrs.IsNotFound(fakeClient.Get(ctx, accessKey, &rbacv1.Role{}))).To(BeTrue())
+		})
+	})
+
+	It("indexes the clusters by the namespaces of their secrets", func() {
+		Expect(externalClusterSecretsNamespaces(cluster)).To(ConsistOf("production"))
+	})
+})
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 )
 
@@ -87,7 +88,7 @@ func (r *ClusterReconciler) reconcileImage(ctx context.Context, cluster *apiv1.C
 	err := r.Client.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: catalogName}, catalog)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
-			r.Recorder.Eventf(cluster, "Warning", "DiscoverImage", "Cannot get %v/%v",
+			r.Recorder.Eventf(cluster, "Warning", events.DiscoverImage, "Cannot get %v/%v",
 				catalogKind, catalogName)
 			return &ctrl.Result{}, nil
 		}
@@ -95,14 +96,18 @@ func (r *ClusterReconciler) reconcileImage(ctx context.Context, cluster *apiv1.C
 		return nil, err
 	}
 
-	// Catalog found, we try to find the image for the major version
+	// Catalog found, we try to find the image for the major version,
+	// built for the architecture of the nodes where the pods can run
 	requestedMajorVersion := cluster.Spec.ImageCatalogRef.Major
-	catalogImage, ok := catalog.GetSpec().FindImageForMajor(requestedMajorVersion)
+	catalogImage, ok := catalog.GetSpec().FindImageForMajorAndArchitecture(
+		requestedMajorVersion,
+		cluster.GetSchedulingArchitecture(),
+	)
 	if !ok {
 		r.Recorder.Eventf(
 			cluster,
 			"Warning",
-			"DiscoverImage", "Cannot find major %v in %v/%v",
+			events.DiscoverImage, "Cannot find major %v in %v/%v",
 			cluster.Spec.ImageCatalogRef.Major,
 			catalogKind,
 			catalogName)
@@ -112,9 +117,22 @@ func (r *ClusterReconciler) reconcileImage(ctx context.Context, cluster *apiv1.C
 			"Selected major version is not available in the catalog")
 	}
 
+	// A new image published in the catalog is rolled out only
+	// when the update policy of the cluster allows it
+	if cluster.Status.Image != "" && cluster.Status.Image != catalogImage {
+		reason, err := r.checkImageUpdatePolicy(ctx, cluster, catalogImage)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			return nil, r.deferImageUpdate(ctx, cluster, catalogImage, reason)
+		}
+	}
+
 	// If the image is different, we set it into the cluster status
-	if cluster.Spec.ImageName != catalogImage {
+	if cluster.Status.Image != catalogImage || cluster.Status.PendingImage != "" {
 		cluster.Status.Image = catalogImage
+		cluster.Status.PendingImage = ""
 		patch := client.MergeFrom(oldCluster)
 		if err := r.Status().Patch(ctx, cluster, patch); err != nil {
 			patchBytes, _ := patch.Data(cluster)
@@ -0,0 +1,173 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// checkImageUpdatePolicy checks whether the update policy of the cluster
+// allows the rollout of the passed image, published in the image catalog.
+// When it doesn't, the reason is returned. The policy only applies to the
+// new images of the major version the cluster is running: selecting a
+// different major version in the catalog is a change requested by the user
+func (r *ClusterReconciler) checkImageUpdatePolicy(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	image string,
+) (string, error) {
+	policy := cluster.Spec.UpdatePolicy
+	if policy == nil || isImageCatalogMajorChanged(cluster) {
+		return "", nil
+	}
+
+	if reason := checkImageUpdateTag(policy, cluster.Status.Image, image); reason != "" {
+		return reason, nil
+	}
+
+	for _, canary := range policy.Canaries {
+		var canaryCluster apiv1.Cluster
+		err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: canary.Name}, &canaryCluster)
+		if apierrs.IsNotFound(err) {
+			return fmt.Sprintf("the canary cluster %s doesn't exist", canary.Name), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if canaryCluster.Status.Image != image {
+			return fmt.Sprintf("the canary cluster %s is not running the image yet", canary.Name), nil
+		}
+
+		if canaryCluster.Status.Phase != apiv1.PhaseHealthy ||
+			canaryCluster.Status.ReadyInstances != canaryCluster.Spec.Instances {
+			return fmt.Sprintf("the canary cluster %s is not healthy", canary.Name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// isImageCatalogMajorChanged is true when the major version selected in
+// the image catalog is not the one of the image the cluster is running
+func isImageCatalogMajorChanged(cluster *apiv1.Cluster) bool {
+	if cluster.Spec.ImageCatalogRef == nil {
+		return false
+	}
+
+	if cluster.Status.PGDataImageInfo != nil {
+		return cluster.Status.PGDataImageInfo.MajorVersion != cluster.Spec.ImageCatalogRef.Major
+	}
+
+	currentVersion, err := postgres.GetPostgresVersionFromTag(utils.GetImageTag(cluster.Status.Image))
+	if err != nil {
+		return false
+	}
+	return postgres.GetPostgresMajorVersionNumber(currentVersion) != cluster.Spec.ImageCatalogRef.Major
+}
+
+// checkImageUpdateTag checks whether the tag of the new image matches the
+// pattern required by the update policy, and the image doesn't contain an
+// older PostgreSQL version than the current one. Images pinned only by
+// digest have no tag to match, and are only subject to the canaries.
+// Likewise, the versions are not compared when they can't be detected
+func checkImageUpdateTag(policy *apiv1.UpdatePolicy, currentImage, newImage string) string {
+	newTag := utils.GetImageTag(newImage)
+	if policy.TagPattern != "" && newTag != "" {
+		if matched, err := regexp.MatchString(policy.TagPattern, newTag); err != nil || !matched {
+			return fmt.Sprintf("the tag %q doesn't match the pattern %q", newTag, policy.TagPattern)
+		}
+	}
+
+	currentVersion, currentErr := postgres.GetPostgresVersionFromTag(utils.GetImageTag(currentImage))
+	newVersion, newErr := postgres.GetPostgresVersionFromTag(newTag)
+	if currentErr == nil && newErr == nil && newVersion < currentVersion {
+		return fmt.Sprintf("the image %s has an older PostgreSQL version than the current one", newImage)
+	}
+
+	return ""
+}
+
+// deferImageUpdate records the image waiting for the update policy to
+// allow its rollout
+func (r *ClusterReconciler) deferImageUpdate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	image string,
+	reason string,
+) error {
+	if cluster.Status.PendingImage == image {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Deferring the rollout of the new image published in the catalog",
+		"image", image,
+		"reason", reason)
+	r.Recorder.Eventf(cluster, "Normal", events.ImageUpdateDeferred,
+		"Deferring the rollout of %s: %s", image, reason)
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.PendingImage = image
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// mapCanaryClustersToClusters reconciles the clusters using the changed
+// one as a canary, to resume their pending image updates
+func (r *ClusterReconciler) mapCanaryClustersToClusters() handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		canary, ok := obj.(*apiv1.Cluster)
+		if !ok {
+			return nil
+		}
+
+		var clusters apiv1.ClusterList
+		if err := r.List(ctx, &clusters, client.InNamespace(canary.Namespace)); err != nil {
+			log.FromContext(ctx).Error(err, "while getting cluster list")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, cluster := range clusters.Items {
+			if cluster.Spec.UpdatePolicy == nil {
+				continue
+			}
+			for _, reference := range cluster.Spec.UpdatePolicy.Canaries {
+				if reference.Name == canary.Name {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+					})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
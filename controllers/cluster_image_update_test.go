@@ -0,0 +1,176 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("checkImageUpdateTag", func() {
+	It("accepts any newer image without a tag pattern", func() {
+		policy := &apiv1.UpdatePolicy{}
+		Expect(checkImageUpdateTag(policy, "postgres:16.2", "postgres:16.3")).To(BeEmpty())
+	})
+
+	It("rejects the tags not matching the pattern", func() {
+		policy := &apiv1.UpdatePolicy{TagPattern: `^16\.[0-9]+-bookworm$`}
+		Expect(checkImageUpdateTag(policy, "postgres:16.2-bookworm", "postgres:16.3-bookworm")).To(BeEmpty())
+		Expect(checkImageUpdateTag(policy, "postgres:16.2-bookworm", "postgres:16.3-bullseye")).ToNot(BeEmpty())
+	})
+
+	It("rejects the images with an older PostgreSQL version", func() {
+		policy := &apiv1.UpdatePolicy{}
+		Expect(checkImageUpdateTag(policy, "postgres:16.3", "postgres:16.2")).ToNot(BeEmpty())
+	})
+
+	It("doesn't match the tag pattern on the images pinned only by digest", func() {
+		policy := &apiv1.UpdatePolicy{TagPattern: `^16\.[0-9]+$`}
+		Expect(checkImageUpdateTag(policy, "postgres:16.2",
+			"postgres@sha256:3f1e5b1d6f2c4a8e9b7d0c1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e")).To(BeEmpty())
+		Expect(checkImageUpdateTag(policy, "postgres:16.2",
+			"postgres:17.0@sha256:3f1e5b1d6f2c4a8e9b7d0c1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e")).ToNot(BeEmpty())
+	})
+
+	It("doesn't compare the versions of the images pinned by digest", func() {
+		policy := &apiv1.UpdatePolicy{}
+		Expect(checkImageUpdateTag(policy, "postgres:16.3",
+			"postgres@sha256:3f1e5b1d6f2c4a8e9b7d0c1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("checkImageUpdatePolicy", func() {
+	const (
+		namespace = "default"
+		newImage  = "postgres:16.3"
+	)
+
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				Instances:       3,
+				ImageCatalogRef: &apiv1.ImageCatalogRef{Major: 16},
+				UpdatePolicy: &apiv1.UpdatePolicy{
+					Canaries: []apiv1.LocalObjectReference{{Name: "staging"}},
+				},
+			},
+			Status: apiv1.ClusterStatus{Image: "postgres:16.2"},
+		}
+	})
+
+	newReconciler := func(objects ...*apiv1.Cluster) *ClusterReconciler {
+		builder := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.Cluster{})
+		for _, object := range objects {
+			builder = builder.WithObjects(object)
+		}
+		return &ClusterReconciler{
+			Client:   builder.Build(),
+			Recorder: record.NewFakeRecorder(120),
+		}
+	}
+
+	newCanary := func(image string, phase string) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: namespace},
+			Spec:       apiv1.ClusterSpec{Instances: 1},
+			Status: apiv1.ClusterStatus{
+				Image:          image,
+				Phase:          phase,
+				ReadyInstances: 1,
+			},
+		}
+	}
+
+	It("allows the rollout without update policy", func(ctx SpecContext) {
+		cluster.Spec.UpdatePolicy = nil
+		reason, err := newReconciler().checkImageUpdatePolicy(ctx, cluster, newImage)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("allows the rollout of a different major version selected by the user", func(ctx SpecContext) {
+		cluster.Spec.ImageCatalogRef.Major = 17
+		cluster.Status.PGDataImageInfo = &apiv1.ImageInfo{Image: "postgres:16.2", MajorVersion: 16}
+		reason, err := newReconciler().checkImageUpdatePolicy(ctx, cluster, "postgres:17.0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("detects a different major version from the tag of the current image", func() {
+		Expect(isImageCatalogMajorChanged(cluster)).To(BeFalse())
+		cluster.Spec.ImageCatalogRef.Major = 17
+		Expect(isImageCatalogMajorChanged(cluster)).To(BeTrue())
+	})
+
+	It("defers the rollout when the canary cluster doesn't exist", func(ctx SpecContext) {
+		reason, err := newReconciler().checkImageUpdatePolicy(ctx, cluster, newImage)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(ContainSubstring("doesn't exist"))
+	})
+
+	It("defers the rollout until the canary cluster runs the image", func(ctx SpecContext) {
+		r := newReconciler(newCanary("postgres:16.2", apiv1.PhaseHealthy))
+		reason, err := r.checkImageUpdatePolicy(ctx, cluster, newImage)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(ContainSubstring("not running the image"))
+	})
+
+	It("defers the rollout until the canary cluster is healthy", func(ctx SpecContext) {
+		r := newReconciler(newCanary(newImage, apiv1.PhaseUpgrade))
+		reason, err := r.checkImageUpdatePolicy(ctx, cluster, newImage)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(ContainSubstring("not healthy"))
+	})
+
+	It("allows the rollout when the canary cluster is healthy with the image", func(ctx SpecContext) {
+		r := newReconciler(newCanary(newImage, apiv1.PhaseHealthy))
+		reason, err := r.checkImageUpdatePolicy(ctx, cluster, newImage)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("records the pending image only once", func(ctx SpecContext) {
+		r := newReconciler(cluster)
+		recorder := r.Recorder.(*record.FakeRecorder)
+
+		Expect(r.deferImageUpdate(ctx, cluster, newImage, "testing")).To(Succeed())
+		Expect(cluster.Status.PendingImage).To(Equal(newImage))
+		Expect(r.deferImageUpdate(ctx, cluster, newImage, "testing")).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+
+	It("maps the canary clusters to the clusters using them", func(ctx SpecContext) {
+		canary := newCanary(newImage, apiv1.PhaseHealthy)
+		r := newReconciler(cluster, canary)
+		requests := r.mapCanaryClustersToClusters()(ctx, canary)
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Name).To(Equal("production"))
+		Expect(r.mapCanaryClustersToClusters()(ctx, cluster)).To(BeEmpty())
+	})
+})
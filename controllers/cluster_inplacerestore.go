@@ -0,0 +1,506 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileInPlaceRestore restores the cluster in place when this is
+// requested by a Restore object. Every instance is shut down, then the
+// data directory of the primary is replaced with the content of a backup
+// by a dedicated job, and the replicas are cloned again from it
+func (r *ClusterReconciler) reconcileInPlaceRestore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	restore, err := r.getActiveRestore(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if restore == nil {
+		if cluster.Status.Phase == apiv1.PhaseInPlaceRestoreFailed {
+			if _, ok := cluster.Annotations[utils.AbandonInPlaceRestoreAnnotationName]; ok {
+				return r.abandonInPlaceRestore(ctx, cluster, resources)
+			}
+
+			// The primary has no usable data directory, and there is
+			// nothing we can do until a new restore is requested or
+			// the restore is abandoned
+			return &ctrl.Result{}, nil
+		}
+		return nil, nil
+	}
+
+	contextLogger = contextLogger.WithValues("restore", restore.Name)
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	if restore.Status.Phase == "" {
+		return r.startInPlaceRestore(ctx, cluster, restore, resources.jobs.Items)
+	}
+
+	if job := getInPlaceRestoreJob(resources.jobs.Items); job != nil {
+		return r.reconcileInPlaceRestoreJob(ctx, cluster, restore, job, resources.pvcs.Items)
+	}
+
+	// The data directory can be replaced only when every instance
+	// has been shut down
+	if len(resources.instances.Items) > 0 {
+		for idx := range resources.instances.Items {
+			instance := &resources.instances.Items[idx]
+			if !instance.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			contextLogger.Info("Deleting Pod as requested by the in-place restore procedure",
+				"podName", instance.Name)
+			if err := r.Delete(ctx, instance); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	primaryPVC := findPrimaryPGDataPVC(cluster, resources.pvcs.Items)
+	if primaryPVC == nil {
+		return nil, r.failInPlaceRestore(ctx, cluster, restore,
+			fmt.Sprintf("cannot find the PGDATA PVC of the primary instance %s", cluster.Status.CurrentPrimary))
+	}
+
+	nodeSerial, err := specs.GetNodeSerial(primaryPVC.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	var backup apiv1.Backup
+	if err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Status.Backup},
+		&backup); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, r.failInPlaceRestore(ctx, cluster, restore,
+				fmt.Sprintf("backup %s not found", restore.Status.Backup))
+		}
+		return nil, err
+	}
+
+	job := specs.CreateInPlaceRestoreJob(*cluster, nodeSerial, restore, &backup)
+	contextLogger.Info("Creating the in-place restore job",
+		"jobName", job.Name,
+		"backup", backup.Name)
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	if err := r.setRestoreStatus(ctx, restore, func(status *apiv1.RestoreStatus) {
+		status.Message = fmt.Sprintf("Restoring backup %s on instance %s", backup.Name, primaryPVC.Name)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// startInPlaceRestore validates a new Restore object, choosing the backup
+// to be restored, and records that the cluster is being restored
+func (r *ClusterReconciler) startInPlaceRestore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	restore *apiv1.Restore,
+	jobs []batchv1.Job,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.IsReplica() {
+		return nil, r.failInPlaceRestore(ctx, cluster, restore,
+			"a replica cluster cannot be restored in place")
+	}
+
+	backup, reason, err := r.getInPlaceRestoreBackup(ctx, cluster, restore)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return nil, r.failInPlaceRestore(ctx, cluster, restore, reason)
+	}
+
+	// A job left behind by a previous failed restore would
+	// otherwise be taken as the result of this one
+	if job := getInPlaceRestoreJob(jobs); job != nil {
+		contextLogger.Info("Removing the job of a previous in-place restore", "jobName", job.Name)
+		if err := r.Delete(ctx, job, client.PropagationPolicy("Background")); err != nil &&
+			!apierrs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	contextLogger.Info("Starting the in-place restore of the cluster", "backup", backup.Name)
+	if err := r.setRestoreStatus(ctx, restore, func(status *apiv1.RestoreStatus) {
+		status.Phase = apiv1.RestorePhaseRunning
+		status.Backup = backup.Name
+		status.Message = "Shutting down the instances"
+		status.StartedAt = ptr.To(metav1.Now())
+	}); err != nil {
+		return nil, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", events.InPlaceRestore,
+		"Restoring backup %s as requested by %s", backup.Name, restore.Name)
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseInPlaceRestore,
+		fmt.Sprintf("Restoring backup %s as requested by %s", backup.Name, restore.Name)); err != nil {
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: time.Second}, nil
+}
+
+// reconcileInPlaceRestoreJob follows the execution of the in-place restore job
+func (r *ClusterReconciler) reconcileInPlaceRestoreJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	restore *apiv1.Restore,
+	job *batchv1.Job,
+	pvcs []corev1.PersistentVolumeClaim,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithValues("jobName", job.Name)
+
+	switch {
+	case utils.JobHasOneCompletion(*job):
+		// The replicas still contain the data that has been replaced
+		// on the primary, and will be cloned again from it
+		primaryInstance := job.Labels[utils.InstanceNameLabelName]
+		for idx := range pvcs {
+			pvc := &pvcs[idx]
+			if pvc.Labels[utils.InstanceNameLabelName] == primaryInstance {
+				continue
+			}
+
+			contextLogger.Info("Deleting the PVC of a replica after the in-place restore", "pvcName", pvc.Name)
+			if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+
+		if err := r.setRestoreStatus(ctx, restore, func(status *apiv1.RestoreStatus) {
+			status.Phase = apiv1.RestorePhaseCompleted
+			status.Message = fmt.Sprintf("Backup %s restored on instance %s", restore.Status.Backup, primaryInstance)
+			status.StoppedAt = ptr.To(metav1.Now())
+		}); err != nil {
+			return nil, err
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", events.InPlaceRestoreCompleted,
+			"Backup %s has been restored as requested by %s", restore.Status.Backup, restore.Name)
+		if err := r.Delete(ctx, job, client.PropagationPolicy("Background")); err != nil &&
+			!apierrs.IsNotFound(err) {
+			return nil, err
+		}
+
+		return &ctrl.Result{RequeueAfter: time.Second}, nil
+
+	case utils.JobHasFailed(*job):
+		return &ctrl.Result{}, r.failInPlaceRestore(ctx, cluster, restore,
+			fmt.Sprintf("the in-place restore job %s failed", job.Name))
+
+	default:
+		contextLogger.Debug("Waiting for the in-place restore job to complete")
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+}
+
+// failInPlaceRestore marks the passed restore as failed. The cluster is
+// marked as failed too if its instances have already been shut down
+func (r *ClusterReconciler) failInPlaceRestore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	restore *apiv1.Restore,
+	reason string,
+) error {
+	log.FromContext(ctx).Info("The in-place restore failed", "reason", reason)
+
+	wasRunning := restore.Status.Phase == apiv1.RestorePhaseRunning
+	if err := r.setRestoreStatus(ctx, restore, func(status *apiv1.RestoreStatus) {
+		status.Phase = apiv1.RestorePhaseFailed
+		status.Message = reason
+		status.StoppedAt = ptr.To(metav1.Now())
+	}); err != nil {
+		return err
+	}
+
+	r.Recorder.Eventf(cluster, "Warning", events.InPlaceRestoreFailed,
+		"The in-place restore requested by %s failed: %s", restore.Name, reason)
+	if !wasRunning {
+		return nil
+	}
+
+	return r.RegisterPhase(ctx, cluster, apiv1.PhaseInPlaceRestoreFailed,
+		fmt.Sprintf("The in-place restore requested by %s failed: %s. "+
+			"Create a new Restore object to retry, or set the %s annotation to abandon it",
+			restore.Name, reason, utils.AbandonInPlaceRestoreAnnotationName))
+}
+
+// abandonInPlaceRestore brings back a cluster whose in-place restore
+// failed, promoting the replica with the lowest serial and removing the
+// volumes of the primary, which is cloned again from it. The replicas
+// are shut down before the data directory of the primary is touched, and
+// still contain the data the cluster had before the restore
+func (r *ClusterReconciler) abandonInPlaceRestore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+	pvcs := resources.pvcs.Items
+
+	var newPrimary *corev1.PersistentVolumeClaim
+	newPrimarySerial := 0
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		if pvc.Labels[utils.InstanceNameLabelName] == cluster.Status.CurrentPrimary ||
+			pvc.Labels[utils.PvcRoleLabelName] != string(utils.PVCRolePgData) ||
+			pvc.Annotations[utils.PVCStatusAnnotationName] != persistentvolumeclaim.StatusReady {
+			continue
+		}
+
+		serial, err := specs.GetNodeSerial(pvc.ObjectMeta)
+		if err != nil {
+			continue
+		}
+		if newPrimary == nil || serial < newPrimarySerial {
+			newPrimary, newPrimarySerial = pvc, serial
+		}
+	}
+
+	if newPrimary == nil {
+		contextLogger.Info("Cannot abandon the in-place restore, as no replica is left to be promoted")
+		r.Recorder.Event(cluster, "Warning", events.InPlaceRestoreFailed,
+			"Cannot abandon the in-place restore, as no replica is left to be promoted")
+		return &ctrl.Result{}, nil
+	}
+
+	newPrimaryName := newPrimary.Labels[utils.InstanceNameLabelName]
+	contextLogger.Info("Abandoning the in-place restore",
+		"oldPrimary", cluster.Status.CurrentPrimary,
+		"newPrimary", newPrimaryName)
+	if job := getInPlaceRestoreJob(resources.jobs.Items); job != nil {
+		if err := r.Delete(ctx, job, client.PropagationPolicy("Background")); err != nil &&
+			!apierrs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		if pvc.Labels[utils.InstanceNameLabelName] != cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.AbandonInPlaceRestoreAnnotationName)
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return nil, err
+	}
+
+	oldPrimaryName := cluster.Status.CurrentPrimary
+	cluster.Status.CurrentPrimary = newPrimaryName
+	cluster.Status.CurrentPrimaryTimestamp = utils.GetCurrentTimestamp()
+	cluster.Status.TargetPrimary = newPrimaryName
+	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	r.Recorder.Eventf(cluster, "Normal", events.InPlaceRestore,
+		"In-place restore abandoned, promoting %s in place of %s", newPrimaryName, oldPrimaryName)
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForInstancesToBeActive,
+		fmt.Sprintf("In-place restore abandoned, promoting %s", newPrimaryName)); err != nil {
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: time.Second}, nil
+}
+
+// setRestoreStatus applies the passed changes to the status of a restore
+func (r *ClusterReconciler) setRestoreStatus(
+	ctx context.Context,
+	restore *apiv1.Restore,
+	update func(status *apiv1.RestoreStatus),
+) error {
+	origRestore := restore.DeepCopy()
+	update(&restore.Status)
+	return r.Status().Patch(ctx, restore, client.MergeFrom(origRestore))
+}
+
+// getActiveRestore returns the oldest Restore object of the cluster
+// that is not yet completed or failed, if any
+func (r *ClusterReconciler) getActiveRestore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*apiv1.Restore, error) {
+	var restores apiv1.RestoreList
+	if err := r.List(ctx, &restores, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var result *apiv1.Restore
+	for idx := range restores.Items {
+		restore := &restores.Items[idx]
+		if restore.Spec.Cluster.Name != cluster.Name || restore.IsDone() {
+			continue
+		}
+
+		if result == nil || restore.CreationTimestamp.Before(&result.CreationTimestamp) ||
+			(restore.CreationTimestamp.Equal(&result.CreationTimestamp) && restore.Name < result.Name) {
+			result = restore
+		}
+	}
+
+	return result, nil
+}
+
+// getInPlaceRestoreBackup returns the backup to be restored in place. When
+// no suitable backup can be found, the reason is returned instead
+func (r *ClusterReconciler) getInPlaceRestoreBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	restore *apiv1.Restore,
+) (*apiv1.Backup, string, error) {
+	if restore.Spec.Backup != nil {
+		var backup apiv1.Backup
+		err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.Backup.Name},
+			&backup)
+		if apierrs.IsNotFound(err) {
+			return nil, fmt.Sprintf("backup %s not found", restore.Spec.Backup.Name), nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if reason := checkInPlaceRestoreBackup(cluster, &backup, restore.Spec.RecoveryTarget); reason != "" {
+			return nil, reason, nil
+		}
+		return &backup, "", nil
+	}
+
+	var backups apiv1.BackupList
+	if err := r.List(ctx, &backups, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, "", err
+	}
+
+	var candidates []apiv1.Backup
+	for _, backup := range backups.Items {
+		if checkInPlaceRestoreBackup(cluster, &backup, restore.Spec.RecoveryTarget) == "" {
+			candidates = append(candidates, backup)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, "no completed backup of the cluster on the object store allows " +
+			"to reach the recovery target", nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[j].Status.StoppedAt.Before(candidates[i].Status.StoppedAt)
+	})
+	return &candidates[0], "", nil
+}
+
+// checkInPlaceRestoreBackup checks if the passed backup can be used to
+// restore the cluster in place up to the recovery target, returning the
+// reason why it can't otherwise
+func checkInPlaceRestoreBackup(
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	recoveryTarget *apiv1.RecoveryTarget,
+) string {
+	switch {
+	case backup.Spec.Cluster.Name != cluster.Name:
+		return fmt.Sprintf("backup %s does not belong to cluster %s", backup.Name, cluster.Name)
+
+	case backup.Status.Phase != apiv1.BackupPhaseCompleted:
+		return fmt.Sprintf("backup %s is not completed", backup.Name)
+
+	case backup.Status.Method != apiv1.BackupMethodBarmanObjectStore:
+		return fmt.Sprintf("backup %s has not been taken on the object store", backup.Name)
+
+	case recoveryTarget != nil && recoveryTarget.BackupID != "" &&
+		recoveryTarget.BackupID != backup.Status.BackupID:
+		return fmt.Sprintf("backup %s does not match the backupID of the recovery target", backup.Name)
+	}
+
+	if err := backup.ValidateRecoveryTarget(recoveryTarget); err != nil {
+		return err.Error()
+	}
+
+	if err := backup.ValidateRecoveryTargetArchived(recoveryTarget, cluster); err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
+
+// getInPlaceRestoreJob returns the in-place restore job, if any
+func getInPlaceRestoreJob(jobs []batchv1.Job) *batchv1.Job {
+	for idx := range jobs {
+		if specs.IsInPlaceRestoreJob(jobs[idx]) {
+			return &jobs[idx]
+		}
+	}
+	return nil
+}
+
+// mapRestoresToClusters returns a function mapping the Restore objects
+// to the reconcile requests of the clusters they refer to
+func (r *ClusterReconciler) mapRestoresToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		restore, ok := obj.(*apiv1.Restore)
+		if !ok || restore.Spec.Cluster.Name == "" {
+			return nil
+		}
+
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: restore.Namespace,
+					Name:      restore.Spec.Cluster.Name,
+				},
+			},
+		}
+	}
+}
@@ -0,0 +1,337 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("in-place restore reconciliation", func() {
+	var (
+		cluster    *apiv1.Cluster
+		restore    *apiv1.Restore
+		backups    []apiv1.Backup
+		pvcs       []corev1.PersistentVolumeClaim
+		fakeClient k8client.Client
+		r          *ClusterReconciler
+	)
+
+	newPVC := func(instanceName string, serial string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instanceName,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					utils.InstanceNameLabelName: instanceName,
+					utils.PvcRoleLabelName:      string(utils.PVCRolePgData),
+				},
+				Annotations: map[string]string{
+					utils.ClusterSerialAnnotationName: serial,
+				},
+			},
+		}
+	}
+
+	newBackup := func(name string, clusterName string, stoppedAt time.Time) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: clusterName},
+			},
+			Status: apiv1.BackupStatus{
+				Phase:     apiv1.BackupPhaseCompleted,
+				Method:    apiv1.BackupMethodBarmanObjectStore,
+				BackupID:  name,
+				StoppedAt: &metav1.Time{Time: stoppedAt},
+			},
+		}
+	}
+
+	getRestore := func(ctx SpecContext) *apiv1.Restore {
+		var result apiv1.Restore
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(restore), &result)).To(Succeed())
+		return &result
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:16.1",
+				Instances: 2,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		restore = &apiv1.Restore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flashback",
+				Namespace: cluster.Namespace,
+			},
+			Spec: apiv1.RestoreSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+				RecoveryTarget: &apiv1.RecoveryTarget{
+					TargetTime: "2024-05-10 12:00:00+00",
+				},
+			},
+		}
+		backupTime := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+		backups = []apiv1.Backup{
+			newBackup("backup-old", cluster.Name, backupTime.Add(-24*time.Hour)),
+			newBackup("backup-recent", cluster.Name, backupTime),
+			newBackup("backup-after-target", cluster.Name, backupTime.Add(24*time.Hour)),
+			newBackup("backup-other-cluster", "other", backupTime.Add(time.Hour)),
+		}
+		pvcs = []corev1.PersistentVolumeClaim{
+			newPVC("cluster-example-1", "1"),
+			newPVC("cluster-example-2", "2"),
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, restore, &pvcs[0], &pvcs[1]).
+			WithObjects(&backups[0], &backups[1], &backups[2], &backups[3]).
+			WithStatusSubresource(cluster, restore).
+			Build()
+		r = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+		}
+	})
+
+	It("does nothing when no restore has been requested", func(ctx SpecContext) {
+		Expect(fakeClient.Delete(ctx, restore)).To(Succeed())
+
+		res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("starts the restore from the most recent backup reaching the target", func(ctx SpecContext) {
+		res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseInPlaceRestore))
+
+		result := getRestore(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.RestorePhaseRunning))
+		Expect(result.Status.Backup).To(Equal("backup-recent"))
+		Expect(result.Status.StartedAt).ToNot(BeNil())
+	})
+
+	It("rejects a backup that cannot be used", func(ctx SpecContext) {
+		restore.Spec.Backup = &apiv1.LocalObjectReference{Name: "backup-other-cluster"}
+		Expect(fakeClient.Update(ctx, restore)).To(Succeed())
+
+		res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(cluster.Status.Phase).To(BeEmpty())
+
+		result := getRestore(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.RestorePhaseFailed))
+		Expect(result.Status.Message).To(ContainSubstring("does not belong to cluster"))
+	})
+
+	Context("when the restore is running", func() {
+		BeforeEach(func(ctx SpecContext) {
+			restore.Status.Phase = apiv1.RestorePhaseRunning
+			restore.Status.Backup = "backup-recent"
+			Expect(fakeClient.Status().Update(ctx, restore)).To(Succeed())
+		})
+
+		It("shuts down the instances", func(ctx SpecContext) {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+			Expect(fakeClient.Create(ctx, pod)).To(Succeed())
+
+			res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{
+				instances: corev1.PodList{Items: []corev1.Pod{*pod}},
+				pvcs:      corev1.PersistentVolumeClaimList{Items: pvcs},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+
+			err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(pod), &corev1.Pod{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("creates the restore job on the primary instance", func(ctx SpecContext) {
+			res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{
+				pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+
+			var job batchv1.Job
+			Expect(fakeClient.Get(ctx, k8client.ObjectKey{
+				Namespace: cluster.Namespace,
+				Name:      "cluster-example-1-inplace-restore",
+			}, &job)).To(Succeed())
+			Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements(
+				"--in-place-restore", "flashback"))
+		})
+
+		It("clones the replicas again when the restore is complete", func(ctx SpecContext) {
+			job := specs.CreateInPlaceRestoreJob(*cluster, 1, restore, &backups[1])
+			job.Status.Succeeded = 1
+			Expect(fakeClient.Create(ctx, job)).To(Succeed())
+
+			res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{
+				pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+				jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+			Expect(getRestore(ctx).Status.Phase).To(Equal(apiv1.RestorePhaseCompleted))
+
+			Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(&pvcs[0]), &corev1.PersistentVolumeClaim{})).
+				To(Succeed())
+			err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(&pvcs[1]), &corev1.PersistentVolumeClaim{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+			err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("stops reconciling the cluster when the restore fails", func(ctx SpecContext) {
+			job := specs.CreateInPlaceRestoreJob(*cluster, 1, restore, &backups[1])
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+			}
+			Expect(fakeClient.Create(ctx, job)).To(Succeed())
+
+			res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{
+				jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+			Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseInPlaceRestoreFailed))
+			Expect(getRestore(ctx).Status.Phase).To(Equal(apiv1.RestorePhaseFailed))
+
+			By("waiting for a new restore", func() {
+				res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{
+					jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(&ctrl.Result{}))
+			})
+		})
+
+		It("promotes a replica when the failed restore is abandoned", func(ctx SpecContext) {
+			for idx := range pvcs {
+				pvcs[idx].Annotations[utils.PVCStatusAnnotationName] = persistentvolumeclaim.StatusReady
+			}
+			job := specs.CreateInPlaceRestoreJob(*cluster, 1, restore, &backups[1])
+			Expect(fakeClient.Create(ctx, job)).To(Succeed())
+
+			restore.Status.Phase = apiv1.RestorePhaseFailed
+			Expect(fakeClient.Status().Update(ctx, restore)).To(Succeed())
+			cluster.Annotations = map[string]string{utils.AbandonInPlaceRestoreAnnotationName: "true"}
+			Expect(fakeClient.Update(ctx, cluster)).To(Succeed())
+			cluster.Status.Phase = apiv1.PhaseInPlaceRestoreFailed
+			Expect(fakeClient.Status().Update(ctx, cluster)).To(Succeed())
+
+			res, err := r.reconcileInPlaceRestore(ctx, cluster, &managedResources{
+				pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+				jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+
+			var result apiv1.Cluster
+			Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cluster), &result)).To(Succeed())
+			Expect(result.Annotations).ToNot(HaveKey(utils.AbandonInPlaceRestoreAnnotationName))
+			Expect(result.Status.Phase).To(Equal(apiv1.PhaseWaitingForInstancesToBeActive))
+			Expect(result.Status.CurrentPrimary).To(Equal("cluster-example-2"))
+			Expect(result.Status.TargetPrimary).To(Equal("cluster-example-2"))
+
+			err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(&pvcs[0]), &corev1.PersistentVolumeClaim{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+			Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(&pvcs[1]), &corev1.PersistentVolumeClaim{})).
+				To(Succeed())
+			err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("checkInPlaceRestoreBackup", func() {
+	cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+	backup := &apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+		},
+		Status: apiv1.BackupStatus{
+			Phase:    apiv1.BackupPhaseCompleted,
+			Method:   apiv1.BackupMethodBarmanObjectStore,
+			BackupID: "20240510T000000",
+			EndLSN:   "0/5000000",
+		},
+	}
+
+	It("accepts a completed backup of the cluster", func() {
+		Expect(checkInPlaceRestoreBackup(cluster, backup, nil)).To(BeEmpty())
+	})
+
+	It("rejects the backups that are not completed", func() {
+		running := backup.DeepCopy()
+		running.Status.Phase = apiv1.BackupPhaseRunning
+		Expect(checkInPlaceRestoreBackup(cluster, running, nil)).To(ContainSubstring("not completed"))
+	})
+
+	It("rejects the volume snapshot backups", func() {
+		snapshot := backup.DeepCopy()
+		snapshot.Status.Method = apiv1.BackupMethodVolumeSnapshot
+		Expect(checkInPlaceRestoreBackup(cluster, snapshot, nil)).To(ContainSubstring("object store"))
+	})
+
+	It("rejects the backups not matching the backupID of the target", func() {
+		Expect(checkInPlaceRestoreBackup(cluster, backup, &apiv1.RecoveryTarget{BackupID: "other"})).
+			To(ContainSubstring("backupID"))
+	})
+
+	It("rejects the backups ending after the target LSN", func() {
+		Expect(checkInPlaceRestoreBackup(cluster, backup, &apiv1.RecoveryTarget{TargetLSN: "0/4000000"})).
+			ToNot(BeEmpty())
+	})
+})
@@ -0,0 +1,188 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// laggingReplicasCheckInterval is how often the replay lag of the replicas
+// excluded from the read-only services is checked again, as a replica
+// catching up doesn't change any watched object
+const laggingReplicasCheckInterval = 10 * time.Second
+
+// reconcileReadOnlyTraffic labels the replicas depending on their replay lag,
+// so that the read-only services only select the ones close enough to the
+// primary. Transitions are reported with an event on the cluster. While any
+// replica is excluded, the time after which the lag has to be checked again
+// is returned
+func (r *ClusterReconciler) reconcileReadOnlyTraffic(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	maxLagBytes, enabled := cluster.GetReadOnlyMaxLagBytes()
+	if !enabled {
+		return 0, r.removeReadOnlyTrafficLabels(ctx, statuses)
+	}
+
+	referenceLSN, ok := getReplayReferenceLSN(cluster, statuses)
+	if !ok {
+		// Without a reference point we cannot tell which replicas are
+		// lagging, and we keep the current routing as is
+		return getLaggingReplicasRequeue(statuses), nil
+	}
+
+	for _, item := range statuses.Items {
+		if item.IsPrimary || item.Pod.Name == cluster.Status.CurrentPrimary {
+			continue
+		}
+		if item.Error != nil {
+			// The readiness probe takes care of the unreachable instances
+			continue
+		}
+
+		replayLSN, err := item.ReplayLsn.Parse()
+		if err != nil {
+			continue
+		}
+		lagBytes := max(referenceLSN-replayLSN, 0)
+
+		value := specs.ReadOnlyTrafficEnabled
+		if lagBytes > maxLagBytes {
+			value = specs.ReadOnlyTrafficDisabled
+		}
+
+		previousValue, found := item.Pod.Labels[utils.ReadOnlyTrafficLabelName]
+		if previousValue == value {
+			continue
+		}
+
+		if err := setReadOnlyTrafficLabel(ctx, r.Client, item.Pod, value); err != nil {
+			return 0, err
+		}
+
+		switch {
+		case value == specs.ReadOnlyTrafficDisabled:
+			r.Recorder.Eventf(cluster, "Warning", events.ReplicaLagging,
+				"Removing %s from the read-only services: replay lag of %d bytes exceeds %d",
+				item.Pod.Name, lagBytes, maxLagBytes)
+		case found:
+			r.Recorder.Eventf(cluster, "Normal", events.ReplicaCaughtUp,
+				"Adding %s back to the read-only services: replay lag of %d bytes",
+				item.Pod.Name, lagBytes)
+		}
+	}
+
+	return getLaggingReplicasRequeue(statuses), nil
+}
+
+// getLaggingReplicasRequeue gets the time after which the replay lag of the
+// replicas has to be checked again, or zero when no replica is excluded
+// from the read-only services
+func getLaggingReplicasRequeue(statuses postgres.PostgresqlStatusList) time.Duration {
+	for _, item := range statuses.Items {
+		if item.Pod.Labels[utils.ReadOnlyTrafficLabelName] == specs.ReadOnlyTrafficDisabled {
+			return laggingReplicasCheckInterval
+		}
+	}
+
+	return 0
+}
+
+// getReplayReferenceLSN gets the position the replay lag of the replicas is
+// measured against: the current LSN of the primary or, in a replica cluster,
+// the replay LSN of the designated primary
+func getReplayReferenceLSN(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) (int64, bool) {
+	for _, item := range statuses.Items {
+		if item.Error != nil {
+			continue
+		}
+
+		var lsn postgres.LSN
+		switch {
+		case item.IsPrimary:
+			lsn = item.CurrentLsn
+		case item.Pod.Name == cluster.Status.CurrentPrimary:
+			lsn = item.ReplayLsn
+		default:
+			continue
+		}
+
+		value, err := lsn.Parse()
+		if err != nil {
+			return 0, false
+		}
+		return value, true
+	}
+
+	return 0, false
+}
+
+// removeReadOnlyTrafficLabels removes the read-only traffic label from the
+// instances after lag-based routing has been disabled
+func (r *ClusterReconciler) removeReadOnlyTrafficLabels(
+	ctx context.Context,
+	statuses postgres.PostgresqlStatusList,
+) error {
+	for _, item := range statuses.Items {
+		if _, found := item.Pod.Labels[utils.ReadOnlyTrafficLabelName]; !found {
+			continue
+		}
+		if err := setReadOnlyTrafficLabel(ctx, r.Client, item.Pod, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setReadOnlyTrafficLabel sets the read-only traffic label of the instance,
+// removing it when the value is empty
+func setReadOnlyTrafficLabel(ctx context.Context, cli client.Client, pod *corev1.Pod, value string) error {
+	contextLogger := log.FromContext(ctx)
+
+	origPod := pod.DeepCopy()
+	if value == "" {
+		delete(pod.Labels, utils.ReadOnlyTrafficLabelName)
+	} else {
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		pod.Labels[utils.ReadOnlyTrafficLabelName] = value
+	}
+
+	contextLogger.Info("Updating read-only traffic label",
+		"podName", pod.Name, "value", value)
+	if err := cli.Patch(ctx, pod, client.MergeFrom(origPod)); err != nil {
+		return fmt.Errorf("cannot update the read-only traffic label of %s: %w", pod.Name, err)
+	}
+
+	return nil
+}
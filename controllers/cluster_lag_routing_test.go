@@ -0,0 +1,149 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("read-only traffic routing", func() {
+	var (
+		cluster  *apiv1.Cluster
+		pods     []*corev1.Pod
+		recorder *record.FakeRecorder
+		r        *ClusterReconciler
+	)
+
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				Managed: &apiv1.ManagedConfiguration{
+					Services: &apiv1.ManagedServices{
+						LagRouting: &apiv1.LagRoutingConfiguration{MaxLagBytes: 1024},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		pods = []*corev1.Pod{
+			newPod("cluster-example-1"),
+			newPod("cluster-example-2"),
+			newPod("cluster-example-3"),
+		}
+
+		objects := make([]client.Object, 0, len(pods))
+		for _, pod := range pods {
+			objects = append(objects, pod)
+		}
+		recorder = record.NewFakeRecorder(10)
+		r = &ClusterReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).WithObjects(objects...).Build(),
+			Recorder: recorder,
+		}
+	})
+
+	statuses := func(primaryLSN, replica2LSN, replica3LSN postgres.LSN) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: pods[0], IsPrimary: true, CurrentLsn: primaryLSN},
+			{Pod: pods[1], ReplayLsn: replica2LSN},
+			{Pod: pods[2], ReplayLsn: replica3LSN},
+		}}
+	}
+
+	trafficLabel := func(ctx SpecContext, name string) (string, bool) {
+		var pod corev1.Pod
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &pod)).To(Succeed())
+		value, found := pod.Labels[utils.ReadOnlyTrafficLabelName]
+		return value, found
+	}
+
+	It("removes the lagging replicas and adds them back once caught up", func(ctx SpecContext) {
+		requeueAfter, err := r.reconcileReadOnlyTraffic(ctx, cluster, statuses("0/10000", "0/FF00", "0/8000"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(Equal(laggingReplicasCheckInterval))
+
+		value, _ := trafficLabel(ctx, "cluster-example-2")
+		Expect(value).To(Equal(specs.ReadOnlyTrafficEnabled))
+		value, _ = trafficLabel(ctx, "cluster-example-3")
+		Expect(value).To(Equal(specs.ReadOnlyTrafficDisabled))
+		_, found := trafficLabel(ctx, "cluster-example-1")
+		Expect(found).To(BeFalse())
+		Expect(recorder.Events).To(Receive(ContainSubstring("ReplicaLagging")))
+		Expect(recorder.Events).ToNot(Receive())
+
+		requeueAfter, err = r.reconcileReadOnlyTraffic(ctx, cluster, statuses("0/10000", "0/10000", "0/FE00"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+
+		value, _ = trafficLabel(ctx, "cluster-example-3")
+		Expect(value).To(Equal(specs.ReadOnlyTrafficEnabled))
+		Expect(recorder.Events).To(Receive(ContainSubstring("ReplicaCaughtUp")))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("keeps the current routing when the primary status is not available", func(ctx SpecContext) {
+		list := statuses("0/10000", "0/FF00", "0/8000")
+		list.Items[0].Error = errors.New("connection refused")
+		Expect(r.reconcileReadOnlyTraffic(ctx, cluster, list)).Error().ToNot(HaveOccurred())
+
+		_, found := trafficLabel(ctx, "cluster-example-3")
+		Expect(found).To(BeFalse())
+	})
+
+	It("measures the lag against the designated primary of a replica cluster", func(ctx SpecContext) {
+		list := statuses("", "0/FF00", "0/8000")
+		list.Items[0].IsPrimary = false
+		list.Items[0].ReplayLsn = "0/10000"
+		Expect(r.reconcileReadOnlyTraffic(ctx, cluster, list)).Error().ToNot(HaveOccurred())
+
+		value, _ := trafficLabel(ctx, "cluster-example-3")
+		Expect(value).To(Equal(specs.ReadOnlyTrafficDisabled))
+	})
+
+	It("removes the labels when lag-based routing is disabled", func(ctx SpecContext) {
+		Expect(r.reconcileReadOnlyTraffic(ctx, cluster, statuses("0/10000", "0/FF00", "0/8000"))).Error().ToNot(HaveOccurred())
+
+		cluster.Spec.Managed = nil
+		Expect(r.reconcileReadOnlyTraffic(ctx, cluster, statuses("0/10000", "0/FF00", "0/8000"))).Error().ToNot(HaveOccurred())
+
+		for _, pod := range pods {
+			_, found := trafficLabel(ctx, pod.Name)
+			Expect(found).To(BeFalse())
+		}
+	})
+})
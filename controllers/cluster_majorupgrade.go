@@ -0,0 +1,300 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileMajorUpgrade upgrades the data directory of the cluster when the
+// image of a newer PostgreSQL major version has been selected. The upgrade
+// is executed with pg_upgrade on the primary instance, while the replicas
+// are cloned again from it once the upgrade is complete
+func (r *ClusterReconciler) reconcileMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	requestedVersion, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return nil, err
+	}
+	requestedMajor := postgres.GetPostgresMajorVersionNumber(requestedVersion)
+
+	if cluster.Status.PGDataImageInfo == nil {
+		return nil, r.setPGDataImageInfo(ctx, cluster, resources.instances.Items, requestedMajor)
+	}
+
+	if job := getMajorUpgradeJob(resources.jobs.Items); job != nil {
+		return r.reconcileMajorUpgradeJob(ctx, cluster, job, resources.pvcs.Items, requestedMajor)
+	}
+
+	pgDataMajor := cluster.Status.PGDataImageInfo.MajorVersion
+	if requestedMajor <= pgDataMajor {
+		return nil, nil
+	}
+
+	if cluster.Status.Phase != apiv1.PhaseMajorUpgrade {
+		r.Recorder.Eventf(cluster, "Normal", events.MajorUpgrade,
+			"Upgrading the data directory from major %d to %d", pgDataMajor, requestedMajor)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgrade,
+			fmt.Sprintf("Upgrading from major %d to %d", pgDataMajor, requestedMajor)); err != nil {
+			return nil, err
+		}
+	}
+
+	// pg_upgrade requires every instance to be shut down
+	if len(resources.instances.Items) > 0 {
+		for idx := range resources.instances.Items {
+			instance := &resources.instances.Items[idx]
+			if !instance.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			contextLogger.Info("Deleting Pod as requested by the major upgrade procedure",
+				"podName", instance.Name)
+			if err := r.Delete(ctx, instance); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	primaryPVC := findPrimaryPGDataPVC(cluster, resources.pvcs.Items)
+	if primaryPVC == nil {
+		return nil, fmt.Errorf("cannot find the PGDATA PVC of the primary instance %s",
+			cluster.Status.CurrentPrimary)
+	}
+
+	nodeSerial, err := specs.GetNodeSerial(primaryPVC.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	job := specs.CreateMajorUpgradeJob(*cluster, nodeSerial, cluster.Status.PGDataImageInfo.Image)
+	contextLogger.Info("Creating the major upgrade job",
+		"jobName", job.Name,
+		"oldImage", cluster.Status.PGDataImageInfo.Image,
+		"newImage", cluster.GetImageName())
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// reconcileMajorUpgradeJob follows the execution of the major upgrade job
+func (r *ClusterReconciler) reconcileMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	job *batchv1.Job,
+	pvcs []corev1.PersistentVolumeClaim,
+	requestedMajor int,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithValues("jobName", job.Name)
+
+	switch {
+	case utils.JobHasOneCompletion(*job):
+		// The replicas are not upgraded, and will be cloned again
+		// from the primary instance
+		primaryInstance := job.Labels[utils.InstanceNameLabelName]
+		for idx := range pvcs {
+			pvc := &pvcs[idx]
+			if pvc.Labels[utils.InstanceNameLabelName] == primaryInstance {
+				continue
+			}
+
+			contextLogger.Info("Deleting the PVC of a replica after the major upgrade", "pvcName", pvc.Name)
+			if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.PGDataImageInfo = &apiv1.ImageInfo{
+			Image:        job.Spec.Template.Spec.Containers[0].Image,
+			MajorVersion: requestedMajor,
+		}
+		if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return nil, err
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", events.MajorUpgradeCompleted,
+			"The data directory has been upgraded to major %d", requestedMajor)
+		if err := r.Delete(ctx, job, client.PropagationPolicy("Background")); err != nil &&
+			!apierrs.IsNotFound(err) {
+			return nil, err
+		}
+
+		return &ctrl.Result{RequeueAfter: time.Second}, nil
+
+	case utils.JobHasFailed(*job):
+		upgraded, err := r.isMajorUpgradeJobDataDirectoryUpgraded(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		if upgraded {
+			// pg_upgrade completed, but the old data directory couldn't be
+			// replaced: the old major version can't run on it anymore, and
+			// a new job will complete the replacement
+			if cluster.Status.Phase != apiv1.PhaseMajorUpgradeIncomplete {
+				r.Recorder.Eventf(cluster, "Warning", events.MajorUpgradeFailed,
+					"The major upgrade job %s failed after upgrading the data directory", job.Name)
+				if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeIncomplete,
+					fmt.Sprintf("The major upgrade job %s failed after upgrading the data directory, "+
+						"that can't be restored: keep the image %s and delete the job "+
+						"to complete the upgrade",
+						job.Name, job.Spec.Template.Spec.Containers[0].Image)); err != nil {
+					return nil, err
+				}
+			}
+
+			return &ctrl.Result{}, nil
+		}
+
+		if requestedMajor <= cluster.Status.PGDataImageInfo.MajorVersion {
+			// The user rolled back to the major version of the data
+			// directory, that has been restored by the failed job
+			contextLogger.Info("Removing the failed major upgrade job after a rollback")
+			if err := r.Delete(ctx, job, client.PropagationPolicy("Background")); err != nil &&
+				!apierrs.IsNotFound(err) {
+				return nil, err
+			}
+			return &ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+
+		if cluster.Status.Phase != apiv1.PhaseMajorUpgradeFailed {
+			r.Recorder.Eventf(cluster, "Warning", events.MajorUpgradeFailed,
+				"The major upgrade job %s failed", job.Name)
+			if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeFailed,
+				fmt.Sprintf("The major upgrade job %s failed and the data directory has been restored: "+
+					"select the image %s to roll back, or delete the job to retry",
+					job.Name, cluster.Status.PGDataImageInfo.Image)); err != nil {
+				return nil, err
+			}
+		}
+
+		return &ctrl.Result{}, nil
+
+	default:
+		contextLogger.Debug("Waiting for the major upgrade job to complete")
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+}
+
+// isMajorUpgradeJobDataDirectoryUpgraded checks if the passed failed major
+// upgrade job reported that the data directory had already been upgraded
+func (r *ClusterReconciler) isMajorUpgradeJobDataDirectoryUpgraded(
+	ctx context.Context,
+	job *batchv1.Job,
+) (bool, error) {
+	var pods corev1.PodList
+	if err := r.List(
+		ctx,
+		&pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels(job.Spec.Template.Labels),
+	); err != nil {
+		return false, fmt.Errorf("while listing the major upgrade pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == specs.MajorUpgradeContainerName &&
+				containerStatus.State.Terminated != nil &&
+				containerStatus.State.Terminated.Message == specs.MajorUpgradeDataDirectoryUpgradedMessage {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// setPGDataImageInfo records the image that is running on the data
+// directory of the cluster, as it was not tracked yet
+func (r *ClusterReconciler) setPGDataImageInfo(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instances []corev1.Pod,
+	requestedMajor int,
+) error {
+	imageInfo := apiv1.ImageInfo{
+		Image:        cluster.GetImageName(),
+		MajorVersion: requestedMajor,
+	}
+
+	// The running instances may still be using the previous image
+	for idx := range instances {
+		image, err := specs.GetPostgresImageName(instances[idx])
+		if err != nil {
+			continue
+		}
+
+		imageInfo.Image = image
+		if version, err := postgres.GetPostgresVersionFromTag(utils.GetImageTag(image)); err == nil {
+			imageInfo.MajorVersion = postgres.GetPostgresMajorVersionNumber(version)
+		}
+		break
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PGDataImageInfo = &imageInfo
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getMajorUpgradeJob returns the major upgrade job, if any
+func getMajorUpgradeJob(jobs []batchv1.Job) *batchv1.Job {
+	for idx := range jobs {
+		if specs.IsMajorUpgradeJob(jobs[idx]) {
+			return &jobs[idx]
+		}
+	}
+	return nil
+}
+
+// findPrimaryPGDataPVC returns the PGDATA PVC of the primary instance, if any
+func findPrimaryPGDataPVC(
+	cluster *apiv1.Cluster,
+	pvcs []corev1.PersistentVolumeClaim,
+) *corev1.PersistentVolumeClaim {
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		if pvc.Labels[utils.InstanceNameLabelName] == cluster.Status.CurrentPrimary &&
+			pvc.Labels[utils.PvcRoleLabelName] == string(utils.PVCRolePgData) {
+			return pvc
+		}
+	}
+	return nil
+}
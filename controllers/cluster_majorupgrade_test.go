@@ -0,0 +1,248 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("major upgrade reconciliation", func() {
+	var (
+		cluster    *apiv1.Cluster
+		pvcs       []corev1.PersistentVolumeClaim
+		fakeClient k8client.Client
+		r          *ClusterReconciler
+	)
+
+	newPVC := func(instanceName string, serial string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instanceName,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					utils.InstanceNameLabelName: instanceName,
+					utils.PvcRoleLabelName:      string(utils.PVCRolePgData),
+				},
+				Annotations: map[string]string{
+					utils.ClusterSerialAnnotationName: serial,
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:16.1",
+				Instances: 2,
+			},
+			Status: apiv1.ClusterStatus{
+				Image:          "postgres:16.1",
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				PGDataImageInfo: &apiv1.ImageInfo{
+					Image:        "postgres:15.4",
+					MajorVersion: 15,
+				},
+			},
+		}
+		pvcs = []corev1.PersistentVolumeClaim{
+			newPVC("cluster-example-1", "1"),
+			newPVC("cluster-example-2", "2"),
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, &pvcs[0], &pvcs[1]).
+			WithStatusSubresource(cluster).
+			Build()
+		r = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+		}
+	})
+
+	It("records the image running on the data directory when missing", func(ctx SpecContext) {
+		cluster.Status.PGDataImageInfo = nil
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		pod.Spec.Containers[0].Image = "postgres:15.4"
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{*pod}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(cluster.Status.PGDataImageInfo).To(Equal(&apiv1.ImageInfo{
+			Image:        "postgres:15.4",
+			MajorVersion: 15,
+		}))
+	})
+
+	It("does nothing when the data directory is already on the requested major", func(ctx SpecContext) {
+		cluster.Status.PGDataImageInfo.MajorVersion = 16
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("shuts down the instances before upgrading", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		Expect(fakeClient.Create(ctx, pod)).To(Succeed())
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{*pod}},
+			pvcs:      corev1.PersistentVolumeClaimList{Items: pvcs},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseMajorUpgrade))
+
+		err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(pod), &corev1.Pod{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("creates the upgrade job on the primary instance", func(ctx SpecContext) {
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+
+		var job batchv1.Job
+		Expect(fakeClient.Get(ctx, k8client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      "cluster-example-1-major-upgrade",
+		}, &job)).To(Succeed())
+		Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("postgres:16.1"))
+		Expect(job.Spec.Template.Spec.InitContainers[1].Image).To(Equal("postgres:15.4"))
+	})
+
+	It("clones the replicas again when the upgrade is complete", func(ctx SpecContext) {
+		job := specs.CreateMajorUpgradeJob(*cluster, 1, "postgres:15.4")
+		job.Status.Succeeded = 1
+		Expect(fakeClient.Create(ctx, job)).To(Succeed())
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+			jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.PGDataImageInfo).To(Equal(&apiv1.ImageInfo{
+			Image:        "postgres:16.1",
+			MajorVersion: 16,
+		}))
+
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(&pvcs[0]), &corev1.PersistentVolumeClaim{})).
+			To(Succeed())
+		err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(&pvcs[1]), &corev1.PersistentVolumeClaim{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(job), &batchv1.Job{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("waits for the user when the upgrade fails", func(ctx SpecContext) {
+		job := specs.CreateMajorUpgradeJob(*cluster, 1, "postgres:15.4")
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+		}
+		Expect(fakeClient.Create(ctx, job)).To(Succeed())
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseMajorUpgradeFailed))
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(job), &batchv1.Job{})).To(Succeed())
+
+		By("rolling back to the previous image", func() {
+			cluster.Spec.ImageName = "postgres:15.4"
+			cluster.Status.Image = "postgres:15.4"
+			res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+				jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+			err = fakeClient.Get(ctx, k8client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	It("doesn't allow a rollback when the data directory has already been upgraded", func(ctx SpecContext) {
+		job := specs.CreateMajorUpgradeJob(*cluster, 1, "postgres:15.4")
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+		}
+		Expect(fakeClient.Create(ctx, job)).To(Succeed())
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      job.Name + "-abcde",
+				Namespace: job.Namespace,
+				Labels:    job.Spec.Template.Labels,
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: specs.MajorUpgradeContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  specs.MajorUpgradeDataDirectoryUpgradedMessage,
+						},
+					},
+				}},
+			},
+		}
+		Expect(fakeClient.Create(ctx, pod)).To(Succeed())
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseMajorUpgradeIncomplete))
+		Expect(cluster.Status.PhaseReason).To(ContainSubstring("delete the job to complete the upgrade"))
+
+		cluster.Spec.ImageName = "postgres:15.4"
+		cluster.Status.Image = "postgres:15.4"
+		res, err = r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			jobs: batchv1.JobList{Items: []batchv1.Job{*job}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(job), &batchv1.Job{})).To(Succeed())
+	})
+})
@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+var (
+	lastArchivedWALTimeDesc = prometheus.NewDesc(
+		"cnpg_cluster_last_archived_wal_time",
+		"The time when the last WAL file was archived by the primary instance, as a unix timestamp",
+		[]string{"namespace", "cluster"}, nil)
+	lastBackupEndTimeDesc = prometheus.NewDesc(
+		"cnpg_cluster_last_backup_end_time",
+		"The time when the last successful backup was completed, as a unix timestamp",
+		[]string{"namespace", "cluster"}, nil)
+	firstRecoverabilityPointDesc = prometheus.NewDesc(
+		"cnpg_cluster_first_recoverability_point",
+		"The first point of recoverability for the cluster, as a unix timestamp",
+		[]string{"namespace", "cluster"}, nil)
+	dataLossWindowDesc = prometheus.NewDesc(
+		"cnpg_cluster_data_loss_window_seconds",
+		"The estimated amount of changes, in seconds, that would be lost if the cluster was "+
+			"recovered from the object store now",
+		[]string{"namespace", "cluster"}, nil)
+)
+
+// clusterRecoverabilityMetrics are the disaster recovery metrics of the
+// clusters reconciled by this operator
+var clusterRecoverabilityMetrics = newRecoverabilityCollector()
+
+func init() {
+	metrics.Registry.MustRegister(clusterRecoverabilityMetrics)
+}
+
+// clusterRecoverability is what we know about the recoverability of a cluster
+// from its status and the status of its primary instance
+type clusterRecoverability struct {
+	lastArchivedWALTime      time.Time
+	lastBackupEndTime        time.Time
+	firstRecoverabilityPoint time.Time
+}
+
+// recoverabilityCollector exports the disaster recovery metrics of the
+// clusters, computing the data loss window when the metrics are collected
+type recoverabilityCollector struct {
+	mu       sync.Mutex
+	clusters map[types.NamespacedName]clusterRecoverability
+	now      func() time.Time
+}
+
+func newRecoverabilityCollector() *recoverabilityCollector {
+	return &recoverabilityCollector{
+		clusters: make(map[types.NamespacedName]clusterRecoverability),
+		now:      time.Now,
+	}
+}
+
+// update refreshes the recoverability information of a cluster. The time
+// of the last archived WAL file is kept when the primary instance could
+// not be reached
+func (c *recoverabilityCollector) update(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	state := c.clusters[key]
+	state.lastBackupEndTime = parseStatusTime(cluster.Status.LastSuccessfulBackup)
+	state.firstRecoverabilityPoint = parseStatusTime(cluster.Status.FirstRecoverabilityPoint)
+
+	for _, item := range statuses.Items {
+		if item.Error != nil || item.Pod == nil || item.Pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+		if lastArchivedWALTime, err := utils.ParseTargetTime(nil, item.LastArchivedWALTime); err == nil {
+			state.lastArchivedWALTime = lastArchivedWALTime
+		}
+	}
+
+	c.clusters[key] = state
+}
+
+// forget removes the metrics of a cluster that doesn't exist anymore
+func (c *recoverabilityCollector) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.clusters, key)
+}
+
+// Describe implements prometheus.Collector
+func (c *recoverabilityCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lastArchivedWALTimeDesc
+	ch <- lastBackupEndTimeDesc
+	ch <- firstRecoverabilityPointDesc
+	ch <- dataLossWindowDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *recoverabilityCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, state := range c.clusters {
+		collectTimestamp := func(desc *prometheus.Desc, value time.Time) {
+			if value.IsZero() {
+				return
+			}
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue,
+				float64(value.Unix()), key.Namespace, key.Name)
+		}
+
+		collectTimestamp(lastArchivedWALTimeDesc, state.lastArchivedWALTime)
+		collectTimestamp(lastBackupEndTimeDesc, state.lastBackupEndTime)
+		collectTimestamp(firstRecoverabilityPointDesc, state.firstRecoverabilityPoint)
+
+		// Without a backup, the WAL archive is not enough to recover the cluster
+		if state.firstRecoverabilityPoint.IsZero() {
+			continue
+		}
+
+		recoverableUntil := state.lastBackupEndTime
+		if state.lastArchivedWALTime.After(recoverableUntil) {
+			recoverableUntil = state.lastArchivedWALTime
+		}
+		ch <- prometheus.MustNewConstMetric(dataLossWindowDesc, prometheus.GaugeValue,
+			now.Sub(recoverableUntil).Seconds(), key.Namespace, key.Name)
+	}
+}
+
+// parseStatusTime parses a time stored in the status of a cluster,
+// returning the zero time when it is not set or not valid
+func parseStatusTime(value string) time.Time {
+	result, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return result
+}
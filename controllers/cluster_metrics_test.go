@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster recoverability metrics", func() {
+	var (
+		collector *recoverabilityCollector
+		cluster   *apiv1.Cluster
+		statuses  postgres.PostgresqlStatusList
+	)
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		collector = newRecoverabilityCollector()
+		collector.now = func() time.Time { return now }
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary:           "cluster-example-1",
+				FirstRecoverabilityPoint: "2024-05-01T00:00:00Z",
+				LastSuccessfulBackup:     "2024-05-10T00:00:00Z",
+			},
+		}
+		statuses = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary:           true,
+					LastArchivedWALTime: "2024-05-10T11:55:00.123456Z",
+				},
+				{
+					Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					LastArchivedWALTime: "2024-05-10T11:59:00Z",
+				},
+			},
+		}
+	})
+
+	It("exports the recoverability of the cluster", func() {
+		collector.update(cluster, statuses)
+
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP cnpg_cluster_data_loss_window_seconds The estimated amount of changes, in seconds, that would be lost if the cluster was recovered from the object store now
+# TYPE cnpg_cluster_data_loss_window_seconds gauge
+cnpg_cluster_data_loss_window_seconds{cluster="cluster-example",namespace="default"} 299.876544
+# HELP cnpg_cluster_first_recoverability_point The first point of recoverability for the cluster, as a unix timestamp
+# TYPE cnpg_cluster_first_recoverability_point gauge
+cnpg_cluster_first_recoverability_point{cluster="cluster-example",namespace="default"} 1.7145216e+09
+# HELP cnpg_cluster_last_archived_wal_time The time when the last WAL file was archived by the primary instance, as a unix timestamp
+# TYPE cnpg_cluster_last_archived_wal_time gauge
+cnpg_cluster_last_archived_wal_time{cluster="cluster-example",namespace="default"} 1.7153421e+09
+# HELP cnpg_cluster_last_backup_end_time The time when the last successful backup was completed, as a unix timestamp
+# TYPE cnpg_cluster_last_backup_end_time gauge
+cnpg_cluster_last_backup_end_time{cluster="cluster-example",namespace="default"} 1.7152992e+09
+`))).To(Succeed())
+	})
+
+	It("keeps the last archived WAL time when the primary is not reachable", func() {
+		collector.update(cluster, statuses)
+		collector.update(cluster, postgres.PostgresqlStatusList{})
+
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP cnpg_cluster_last_archived_wal_time The time when the last WAL file was archived by the primary instance, as a unix timestamp
+# TYPE cnpg_cluster_last_archived_wal_time gauge
+cnpg_cluster_last_archived_wal_time{cluster="cluster-example",namespace="default"} 1.7153421e+09
+`), "cnpg_cluster_last_archived_wal_time")).To(Succeed())
+	})
+
+	It("doesn't estimate the data loss window of a cluster without backups", func() {
+		cluster.Status.FirstRecoverabilityPoint = ""
+		cluster.Status.LastSuccessfulBackup = ""
+		collector.update(cluster, statuses)
+
+		Expect(testutil.CollectAndCount(collector, "cnpg_cluster_data_loss_window_seconds")).To(BeZero())
+		Expect(testutil.CollectAndCount(collector)).To(Equal(1))
+	})
+
+	It("removes the metrics of the deleted clusters", func() {
+		collector.update(cluster, statuses)
+		collector.forget(types.NamespacedName{Namespace: "default", Name: "cluster-example"})
+
+		Expect(testutil.CollectAndCount(collector)).To(BeZero())
+	})
+})
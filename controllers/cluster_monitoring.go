@@ -0,0 +1,161 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcilePrometheusRule ensures that the PrometheusRule with the alerts
+// of the cluster exists and reflects its specification, deleting it when
+// it is no longer required. The rule is owned by the cluster, and is
+// garbage collected together with it
+func reconcilePrometheusRule(
+	ctx context.Context,
+	cli client.Client,
+	discoveryClient discovery.DiscoveryInterface,
+	cluster *apiv1.Cluster,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	havePrometheusRuleCRD, err := utils.PrometheusRuleExist(discoveryClient)
+	if err != nil {
+		return err
+	}
+
+	if !havePrometheusRuleCRD {
+		if cluster.IsPrometheusRuleEnabled() {
+			// The controller cannot do anything until the CRD is installed
+			contextLogger.Warning("PrometheusRule CRD not present. Cannot create the PrometheusRule object")
+		}
+		return nil
+	}
+
+	var prometheusRule monitoringv1.PrometheusRule
+	err = cli.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, &prometheusRule)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting the PrometheusRule: %w", err)
+	}
+	found := err == nil
+
+	// we never touch a PrometheusRule that we haven't created
+	if found {
+		if ownerName, isOwned := IsOwnedByCluster(&prometheusRule); !isOwned || ownerName != cluster.Name {
+			if cluster.IsPrometheusRuleEnabled() {
+				contextLogger.Info("Skipping the PrometheusRule, as it is not owned by the cluster",
+					"prometheusRule", prometheusRule.Name)
+			}
+			return nil
+		}
+	}
+
+	if !cluster.IsPrometheusRuleEnabled() {
+		if !found {
+			return nil
+		}
+		contextLogger.Info("Deleting PrometheusRule")
+		return client.IgnoreNotFound(cli.Delete(ctx, &prometheusRule))
+	}
+
+	expectedPrometheusRule := specs.CreatePrometheusRule(cluster)
+	if !found {
+		contextLogger.Debug("Creating PrometheusRule")
+		return cli.Create(ctx, expectedPrometheusRule)
+	}
+
+	origPrometheusRule := prometheusRule.DeepCopy()
+	prometheusRule.Spec = expectedPrometheusRule.Spec
+	// We don't override the current labels/annotations given that there could be data that isn't managed by us
+	utils.MergeObjectsMetadata(&prometheusRule, expectedPrometheusRule)
+
+	if reflect.DeepEqual(origPrometheusRule, &prometheusRule) {
+		return nil
+	}
+
+	contextLogger.Debug("Patching PrometheusRule")
+	return cli.Patch(ctx, &prometheusRule, client.MergeFrom(origPrometheusRule))
+}
+
+// reconcileGrafanaDashboard ensures that the ConfigMap with the Grafana
+// dashboard of the cluster exists and reflects its specification,
+// deleting it when it is no longer required
+func (r *ClusterReconciler) reconcileGrafanaDashboard(ctx context.Context, cluster *apiv1.Cluster) error {
+	var livingConfigMap corev1.ConfigMap
+	err := r.Client.Get(
+		ctx,
+		types.NamespacedName{Name: cluster.GetGrafanaDashboardConfigMapName(), Namespace: cluster.Namespace},
+		&livingConfigMap)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	// we never touch a ConfigMap that we haven't created
+	if found {
+		if ownerName, isOwned := IsOwnedByCluster(&livingConfigMap); !isOwned || ownerName != cluster.Name {
+			if cluster.IsGrafanaDashboardEnabled() {
+				log.FromContext(ctx).Info("Skipping the Grafana dashboard ConfigMap, as it is not owned by the cluster",
+					"configMap", livingConfigMap.Name)
+			}
+			return nil
+		}
+	}
+
+	if !cluster.IsGrafanaDashboardEnabled() {
+		if !found {
+			return nil
+		}
+		return client.IgnoreNotFound(r.Client.Delete(ctx, &livingConfigMap))
+	}
+
+	proposed, err := specs.CreateGrafanaDashboardConfigMap(cluster)
+	if err != nil {
+		return fmt.Errorf("while generating the Grafana dashboard: %w", err)
+	}
+	cluster.SetInheritedDataAndOwnership(&proposed.ObjectMeta)
+
+	if !found {
+		return r.Client.Create(ctx, proposed)
+	}
+
+	if reflect.DeepEqual(livingConfigMap.Data, proposed.Data) &&
+		utils.IsMapSubset(livingConfigMap.Labels, proposed.Labels) &&
+		utils.IsMapSubset(livingConfigMap.Annotations, proposed.Annotations) {
+		return nil
+	}
+
+	patchedConfigMap := livingConfigMap.DeepCopy()
+	patchedConfigMap.Data = proposed.Data
+	// We don't override the current labels/annotations given that there could be data that isn't managed by us
+	utils.MergeObjectsMetadata(patchedConfigMap, proposed)
+
+	return r.Client.Patch(ctx, patchedConfigMap, client.MergeFrom(&livingConfigMap))
+}
@@ -0,0 +1,172 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster monitoring resources", func() {
+	var (
+		cluster             *apiv1.Cluster
+		fakeClient          k8client.Client
+		fakeDiscoveryClient *fakediscovery.FakeDiscovery
+		reconciler          *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-example-uid",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				Monitoring: &apiv1.MonitoringConfiguration{
+					PrometheusRule:   &apiv1.PrometheusRuleConfiguration{Enabled: true},
+					GrafanaDashboard: &apiv1.GrafanaDashboardConfiguration{Enabled: true},
+				},
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).Build()
+		fakeDiscoveryClient = &fakediscovery.FakeDiscovery{
+			Fake: &testing.Fake{
+				Resources: []*metav1.APIResourceList{
+					{
+						GroupVersion: "monitoring.coreos.com/v1",
+						APIResources: []metav1.APIResource{
+							{
+								Name:       "prometheusrules",
+								Kind:       "PrometheusRule",
+								Namespaced: true,
+							},
+						},
+					},
+				},
+			},
+		}
+		reconciler = &ClusterReconciler{
+			Client:          fakeClient,
+			DiscoveryClient: fakeDiscoveryClient,
+			Recorder:        record.NewFakeRecorder(120),
+			Scheme:          schemeBuilder.BuildWithAllKnownScheme(),
+		}
+	})
+
+	getPrometheusRule := func(ctx SpecContext) (*monitoringv1.PrometheusRule, error) {
+		var prometheusRule monitoringv1.PrometheusRule
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+			&prometheusRule)
+		return &prometheusRule, err
+	}
+
+	getDashboard := func(ctx SpecContext) (*corev1.ConfigMap, error) {
+		var configMap corev1.ConfigMap
+		err := fakeClient.Get(
+			ctx,
+			types.NamespacedName{Name: cluster.GetGrafanaDashboardConfigMapName(), Namespace: cluster.Namespace},
+			&configMap)
+		return &configMap, err
+	}
+
+	It("creates the PrometheusRule and updates its thresholds", func(ctx SpecContext) {
+		Expect(reconcilePrometheusRule(ctx, fakeClient, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		prometheusRule, err := getPrometheusRule(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(prometheusRule, cluster)).To(BeTrue())
+
+		cluster.Spec.Instances = 5
+		Expect(reconcilePrometheusRule(ctx, fakeClient, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		prometheusRule, err = getPrometheusRule(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prometheusRule.Spec.Groups[0].Rules[0].Expr.String()).To(HaveSuffix("< 5"))
+	})
+
+	It("deletes the PrometheusRule when it is disabled", func(ctx SpecContext) {
+		Expect(reconcilePrometheusRule(ctx, fakeClient, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		cluster.Spec.Monitoring.PrometheusRule.Enabled = false
+		Expect(reconcilePrometheusRule(ctx, fakeClient, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		_, err := getPrometheusRule(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("does nothing when the PrometheusRule CRD is not installed", func(ctx SpecContext) {
+		fakeDiscoveryClient.Resources = nil
+		Expect(reconcilePrometheusRule(ctx, fakeClient, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		_, err := getPrometheusRule(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("creates the Grafana dashboard and deletes it when it is disabled", func(ctx SpecContext) {
+		Expect(reconciler.reconcileGrafanaDashboard(ctx, cluster)).To(Succeed())
+
+		configMap, err := getDashboard(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(configMap, cluster)).To(BeTrue())
+		Expect(configMap.Labels).To(HaveKeyWithValue(apiv1.DefaultGrafanaDashboardLabel, "1"))
+
+		cluster.Spec.Monitoring.GrafanaDashboard.Enabled = false
+		Expect(reconciler.reconcileGrafanaDashboard(ctx, cluster)).To(Succeed())
+
+		_, err = getDashboard(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("never touches a ConfigMap not owned by the cluster", func(ctx SpecContext) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetGrafanaDashboardConfigMapName(),
+				Namespace: cluster.Namespace,
+			},
+			Data: map[string]string{"custom.json": "{}"},
+		}
+		Expect(fakeClient.Create(ctx, configMap)).To(Succeed())
+
+		Expect(reconciler.reconcileGrafanaDashboard(ctx, cluster)).To(Succeed())
+
+		livingConfigMap, err := getDashboard(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(livingConfigMap.Data).To(Equal(configMap.Data))
+	})
+})
@@ -20,20 +20,73 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// getCertificatesValidity returns the validity of the CA certificates and of
+// the leaf certificates generated by the operator for the cluster. Rotation
+// settings that would renew the certificates at every reconciliation loop,
+// which the webhook refuses, raise an error instead of being adjusted
+func getCertificatesValidity(cluster *apiv1.Cluster) (caValidity certs.Validity, leafValidity certs.Validity, err error) {
+	caValidity = certs.DefaultValidity()
+	leafValidity = caValidity
+
+	rotation := cluster.GetCertificatesRotation()
+	if rotation == nil {
+		return caValidity, leafValidity, nil
+	}
+
+	const day = 24 * time.Hour
+	if rotation.CADurationDays > 0 {
+		caValidity.Duration = time.Duration(rotation.CADurationDays) * day
+	}
+	if rotation.LeafDurationDays > 0 {
+		leafValidity.Duration = time.Duration(rotation.LeafDurationDays) * day
+	}
+	if rotation.RenewBeforeDays > 0 {
+		caValidity.RenewBefore = time.Duration(rotation.RenewBeforeDays) * day
+		leafValidity.RenewBefore = caValidity.RenewBefore
+	}
+
+	if leafValidity.Duration > caValidity.Duration {
+		return caValidity, leafValidity, fmt.Errorf(
+			"invalid certificates rotation: the certificates last %v, longer than their CA (%v)",
+			leafValidity.Duration, caValidity.Duration)
+	}
+	if caValidity.RenewBefore >= leafValidity.Duration {
+		return caValidity, leafValidity, fmt.Errorf(
+			"invalid certificates rotation: the renewal window of %v is not shorter than the "+
+				"lifetime of the certificates (%v)",
+			caValidity.RenewBefore, leafValidity.Duration)
+	}
+
+	return caValidity, leafValidity, nil
+}
+
 // setupPostgresPKI create all the PKI infrastructure that PostgreSQL need to work
 // if using ssl=on
 func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.UsesCertManager() {
+		return r.setupCertManagerPKI(ctx, cluster)
+	}
+
+	// The server certificate issued by cert-manager, when the cluster
+	// stops using it, is replaced by one signed by the CA of the cluster
+	if err := r.cleanupCertManagerCertificates(ctx, cluster); err != nil {
+		return fmt.Errorf("removing the cert-manager Certificates: %w", err)
+	}
+
 	// This is the CA of cluster
 	serverCaSecret, err := r.ensureServerCASecret(ctx, cluster)
 	if err != nil {
@@ -93,6 +146,76 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 		return fmt.Errorf("generating streaming replication client certificate: %w", err)
 	}
 
+	return r.reconcileRoleClientCertificates(ctx, cluster, clientCaSecret)
+}
+
+// reconcileRoleClientCertificates generates and renews the client certificates
+// of the managed roles requiring them, and removes the ones that are not
+// required anymore
+func (r *ClusterReconciler) reconcileRoleClientCertificates(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	clientCaSecret *v1.Secret,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	requiredSecrets := make(map[string]bool)
+	for _, role := range cluster.GetClientCertificateRoles() {
+		secretName := client.ObjectKey{
+			Namespace: cluster.GetNamespace(),
+			Name:      role.GetClientCertificateSecretName(cluster.Name),
+		}
+		requiredSecrets[secretName.Name] = true
+
+		err := r.ensureLeafCertificate(
+			ctx,
+			cluster,
+			secretName,
+			role.Name,
+			clientCaSecret,
+			certs.CertTypeClient,
+			nil,
+			map[string]string{
+				utils.ClusterLabelName:               cluster.Name,
+				utils.ClientCertificateRoleLabelName: role.Name,
+			})
+		if err != nil {
+			return fmt.Errorf("generating the client certificate of role %s: %w", role.Name, err)
+		}
+	}
+
+	var secrets v1.SecretList
+	if err := r.List(
+		ctx,
+		&secrets,
+		client.InNamespace(cluster.GetNamespace()),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.ClientCertificateRoleLabelName},
+	); err != nil {
+		return err
+	}
+
+	for idx := range secrets.Items {
+		secret := &secrets.Items[idx]
+		if requiredSecrets[secret.Name] {
+			continue
+		}
+
+		// We only remove the certificates that have been generated
+		// by the operator for this cluster
+		if owner := metav1.GetControllerOf(secret); owner == nil ||
+			owner.Kind != apiv1.ClusterKind || owner.Name != cluster.Name {
+			continue
+		}
+
+		contextLogger.Info("Deleting the client certificate of a role not requiring it anymore",
+			"secret", secret.Name,
+			"role", secret.Labels[utils.ClientCertificateRoleLabelName])
+		if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -107,7 +230,7 @@ func (r *ClusterReconciler) ensureClientCASecret(ctx context.Context, cluster *a
 		&secret)
 	// If specified and error, bubble up
 	if err != nil {
-		r.Recorder.Event(cluster, "Warning", "SecretNotFound",
+		r.Recorder.Event(cluster, "Warning", events.SecretNotFound,
 			"Getting secret "+cluster.GetClientCASecretName())
 		return nil, err
 	}
@@ -121,7 +244,7 @@ func (r *ClusterReconciler) ensureClientCASecret(ctx context.Context, cluster *a
 	if cluster.Spec.Certificates.ReplicationTLSSecret == "" {
 		_, err = certs.ParseCASecret(&secret)
 		if err != nil {
-			r.Recorder.Event(cluster, "Warning", "InvalidCASecret",
+			r.Recorder.Event(cluster, "Warning", events.InvalidCASecret,
 				fmt.Sprintf("Parsing client secret %s: %s", secret.Name, err.Error()))
 			return nil, err
 		}
@@ -144,7 +267,7 @@ func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *a
 		&secret)
 	// If specified and error, bubble up
 	if err != nil {
-		r.Recorder.Event(cluster, "Warning", "SecretNotFound",
+		r.Recorder.Event(cluster, "Warning", events.SecretNotFound,
 			"Getting secret "+cluster.GetServerCASecretName())
 		return nil, err
 	}
@@ -158,7 +281,7 @@ func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *a
 	if cluster.Spec.Certificates.ServerTLSSecret == "" {
 		_, err = certs.ParseCASecret(&secret)
 		if err != nil {
-			r.Recorder.Event(cluster, "Warning", "InvalidCASecret",
+			r.Recorder.Event(cluster, "Warning", events.InvalidCASecret,
 				fmt.Sprintf("Parsing server secret %s: %s", secret.Name, err.Error()))
 			return nil, err
 		}
@@ -179,11 +302,15 @@ func (r *ClusterReconciler) verifyCAValidity(secret v1.Secret, cluster *apiv1.Cl
 		Certificate: publicKey,
 	}
 
-	isExpiring, _, err := caPair.IsExpiring()
+	caValidity, _, err := getCertificatesValidity(cluster)
+	if err != nil {
+		return err
+	}
+	isExpiring, _, err := caPair.IsExpiringWithin(caValidity.RenewBefore)
 	if err != nil {
 		return err
 	} else if isExpiring {
-		r.Recorder.Event(cluster, "Warning", "SecretIsExpiring",
+		r.Recorder.Event(cluster, "Warning", events.SecretIsExpiring,
 			"Checking expiring date of secret "+secret.Name)
 		log.Info("CA certificate is expiring or is already expired", "secret", secret.Name)
 	}
@@ -194,11 +321,16 @@ func (r *ClusterReconciler) verifyCAValidity(secret v1.Secret, cluster *apiv1.Cl
 func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.Cluster,
 	secretName string,
 ) (*v1.Secret, error) {
+	caValidity, _, err := getCertificatesValidity(cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	var secret v1.Secret
-	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: secretName}, &secret)
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: secretName}, &secret)
 	if err == nil {
 		// Verify the validity of this CA and renew it if needed
-		err = r.renewCASecret(ctx, &secret)
+		err = r.renewCASecret(ctx, &secret, caValidity)
 		if err != nil {
 			return nil, err
 		}
@@ -208,7 +340,7 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 		return nil, err
 	}
 
-	caPair, err := certs.CreateRootCA(cluster.Name, cluster.Namespace)
+	caPair, err := certs.CreateRootCAWithDuration(cluster.Name, cluster.Namespace, caValidity.Duration)
 	if err != nil {
 		return nil, fmt.Errorf("while creating the CA of the cluster: %w", err)
 	}
@@ -220,14 +352,17 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	return derivedCaSecret, err
 }
 
-// renewCASecret check if this CA secret is valid and renew it if needed
-func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret) error {
+// renewCASecret check if this CA secret is valid and renew it if needed.
+// The renewed certificate keeps the private key of the CA, so that the
+// certificates it signed before are still trusted by the clients knowing
+// only the new CA certificate, and vice versa
+func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret, validity certs.Validity) error {
 	pair, err := certs.ParseCASecret(secret)
 	if err != nil {
 		return err
 	}
 
-	expiring, _, err := pair.IsExpiring()
+	expiring, _, err := pair.IsExpiringWithin(validity.RenewBefore)
 	if err != nil {
 		return err
 	}
@@ -240,7 +375,7 @@ func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret
 		return err
 	}
 
-	err = pair.RenewCertificate(privateKey, nil)
+	err = pair.RenewCertificateWithDuration(privateKey, nil, validity.Duration)
 	if err != nil {
 		return err
 	}
@@ -327,13 +462,19 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	altDNSNames []string,
 	additionalLabels map[string]string,
 ) error {
+	_, leafValidity, err := getCertificatesValidity(cluster)
+	if err != nil {
+		return err
+	}
+
 	var secret v1.Secret
-	err := r.Get(ctx, secretName, &secret)
+	err = r.Get(ctx, secretName, &secret)
 	if err == nil {
-		return r.renewAndUpdateCertificate(ctx, caSecret, &secret)
+		return r.renewAndUpdateCertificate(ctx, caSecret, &secret, leafValidity)
 	}
 
-	serverSecret, err := generateCertificateFromCA(caSecret, commonName, usage, altDNSNames, secretName)
+	serverSecret, err := generateCertificateFromCA(
+		caSecret, commonName, usage, altDNSNames, secretName, leafValidity.Duration)
 	if err != nil {
 		return err
 	}
@@ -355,13 +496,14 @@ func generateCertificateFromCA(
 	usage certs.CertType,
 	altDNSNames []string,
 	secretName client.ObjectKey,
+	duration time.Duration,
 ) (*v1.Secret, error) {
 	caPair, err := certs.ParseCASecret(caSecret)
 	if err != nil {
 		return nil, err
 	}
 
-	serverPair, err := caPair.CreateAndSignPair(commonName, usage, altDNSNames)
+	serverPair, err := caPair.CreateAndSignPairWithDuration(commonName, usage, altDNSNames, duration)
 	if err != nil {
 		return nil, err
 	}
@@ -376,9 +518,10 @@ func (r *ClusterReconciler) renewAndUpdateCertificate(
 	ctx context.Context,
 	caSecret *v1.Secret,
 	secret *v1.Secret,
+	validity certs.Validity,
 ) error {
 	origSecret := secret.DeepCopy()
-	hasBeenRenewed, err := certs.RenewLeafCertificate(caSecret, secret)
+	hasBeenRenewed, err := certs.RenewLeafCertificateWithValidity(caSecret, secret, validity)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,230 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("client certificates of the managed roles", func() {
+	var (
+		cluster    *apiv1.Cluster
+		caSecret   *corev1.Secret
+		fakeClient k8client.Client
+		r          *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{Name: "batch_loader", Login: true, ClientCertificate: true},
+						{Name: "app", Login: true},
+					},
+				},
+			},
+		}
+
+		caPair, err := certs.CreateRootCA(cluster.Name, cluster.Namespace)
+		Expect(err).ToNot(HaveOccurred())
+		caSecret = caPair.GenerateCASecret(cluster.Namespace, cluster.GetClientCASecretName())
+
+		staleSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-reporting-client",
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					utils.ClusterLabelName:               cluster.Name,
+					utils.ClientCertificateRoleLabelName: "reporting",
+				},
+			},
+		}
+		utils.SetAsOwnedBy(&staleSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, caSecret, staleSecret).
+			Build()
+		r = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+		}
+	})
+
+	It("issues a client certificate for the roles requiring it", func(ctx SpecContext) {
+		Expect(r.reconcileRoleClientCertificates(ctx, cluster, caSecret)).To(Succeed())
+
+		var secret corev1.Secret
+		Expect(fakeClient.Get(ctx, k8client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      "cluster-example-batch-loader-client",
+		}, &secret)).To(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		Expect(secret.Labels).To(HaveKeyWithValue(utils.ClientCertificateRoleLabelName, "batch_loader"))
+
+		pair, err := certs.ParseServerSecret(&secret)
+		Expect(err).ToNot(HaveOccurred())
+		certificate, err := pair.ParseCertificate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate.Subject.CommonName).To(Equal("batch_loader"))
+
+		err = fakeClient.Get(ctx, k8client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      "cluster-example-app-client",
+		}, &corev1.Secret{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("removes the client certificates that are not required anymore", func(ctx SpecContext) {
+		Expect(r.reconcileRoleClientCertificates(ctx, cluster, caSecret)).To(Succeed())
+
+		err := fakeClient.Get(ctx, k8client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      "cluster-example-reporting-client",
+		}, &corev1.Secret{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("validity of the generated certificates", func() {
+	const day = 24 * time.Hour
+
+	It("uses the settings of the operator by default", func() {
+		caValidity, leafValidity, err := getCertificatesValidity(&apiv1.Cluster{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(caValidity).To(Equal(certs.DefaultValidity()))
+		Expect(leafValidity).To(Equal(certs.DefaultValidity()))
+	})
+
+	It("uses the rotation settings of the cluster", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					Rotation: &apiv1.CertificatesRotationConfiguration{
+						CADurationDays:   365,
+						LeafDurationDays: 30,
+						RenewBeforeDays:  10,
+					},
+				},
+			},
+		}
+
+		caValidity, leafValidity, err := getCertificatesValidity(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(caValidity).To(Equal(certs.Validity{Duration: 365 * day, RenewBefore: 10 * day}))
+		Expect(leafValidity).To(Equal(certs.Validity{Duration: 30 * day, RenewBefore: 10 * day}))
+	})
+
+	It("refuses a renewal window as long as the lifetime of the certificates", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					Rotation: &apiv1.CertificatesRotationConfiguration{
+						LeafDurationDays: 4,
+						RenewBeforeDays:  4,
+					},
+				},
+			},
+		}
+
+		_, _, err := getCertificatesValidity(cluster)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("refuses certificates lasting longer than their CA", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					Rotation: &apiv1.CertificatesRotationConfiguration{
+						CADurationDays:   30,
+						LeafDurationDays: 60,
+					},
+				},
+			},
+		}
+
+		_, _, err := getCertificatesValidity(cluster)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("generates the certificates with the lifetime set in the cluster", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					Rotation: &apiv1.CertificatesRotationConfiguration{
+						CADurationDays:   200,
+						LeafDurationDays: 20,
+					},
+				},
+			},
+		}
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(120),
+		}
+
+		caSecret, err := r.ensureCASecret(ctx, cluster, cluster.GetServerCASecretName())
+		Expect(err).ToNot(HaveOccurred())
+		caPair := certs.KeyPair{Certificate: caSecret.Data[certs.CACertKey]}
+		_, caExpiration, err := caPair.IsExpiring()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*caExpiration).To(BeTemporally("~", time.Now().Add(200*day), time.Hour))
+
+		secretName := k8client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServerTLSSecretName()}
+		Expect(r.ensureLeafCertificate(ctx, cluster, secretName, cluster.GetServiceReadWriteName(), caSecret,
+			certs.CertTypeServer, nil, nil)).To(Succeed())
+
+		var serverSecret corev1.Secret
+		Expect(r.Get(ctx, secretName, &serverSecret)).To(Succeed())
+		serverPair := certs.KeyPair{Certificate: serverSecret.Data[certs.TLSCertKey]}
+		_, leafExpiration, err := serverPair.IsExpiring()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*leafExpiration).To(BeTemporally("~", time.Now().Add(20*day), time.Hour))
+	})
+})
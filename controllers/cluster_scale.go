@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -47,7 +48,7 @@ func (r *ClusterReconciler) scaleDownCluster(
 			return err
 		}
 
-		r.Recorder.Eventf(cluster, "Warning", "NoScaleDown",
+		r.Recorder.Eventf(cluster, "Warning", events.NoScaleDown,
 			"Can't scale down lower than maxSyncReplicas, going back to %v",
 			cluster.Spec.Instances)
 
@@ -62,7 +63,7 @@ func (r *ClusterReconciler) scaleDownCluster(
 	}
 
 	message := fmt.Sprintf("Scaling down - removing instance: %v", instanceName)
-	r.Recorder.Event(cluster, "Normal", "ScaleDown", message)
+	r.Recorder.Event(cluster, "Normal", events.ScaleDown, message)
 	contextLogger.Info(message)
 
 	return r.ensureInstanceIsDeleted(ctx, cluster, instanceName)
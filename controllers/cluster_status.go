@@ -18,10 +18,13 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"runtime"
 	"sort"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -34,9 +37,12 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	pgbouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
@@ -233,7 +239,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 ) error {
 	// Retrieve the cluster key
 
-	existingClusterStatus := cluster.Status
+	existingClusterStatus := *cluster.Status.DeepCopy()
 
 	persistentvolumeclaim.EnrichStatus(
 		ctx,
@@ -333,6 +339,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 	if err := r.refreshCertsExpirations(ctx, cluster); err != nil {
 		return err
 	}
+	setCertificatesValidCondition(cluster, time.Now())
 
 	if err := r.refreshSecretResourceVersions(ctx, cluster); err != nil {
 		return err
@@ -391,6 +398,12 @@ func (r *ClusterReconciler) updateOnlineUpdateEnabled(
 	return r.Status().Update(ctx, cluster)
 }
 
+// instanceWALStatusRefreshInterval is the minimum time between two updates
+// of the position of an instance in the WAL stream. As it changes whenever
+// WAL is written, refreshing it at every reconciliation loop would update
+// the cluster status, and trigger new reconciliation loops, continuously
+const instanceWALStatusRefreshInterval = time.Minute
+
 // getPoolerIntegrationsNeeded returns a struct with all the pooler integrations needed
 func (r *ClusterReconciler) getPoolerIntegrationsNeeded(ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -426,6 +439,19 @@ func (r *ClusterReconciler) getPgbouncerIntegrationStatus(
 			continue
 		}
 
+		// The poolers using a custom auth query can let the instance
+		// manager create its user and the function it calls
+		if pooler.IsAuthQueryProvisioned() {
+			user, err := r.getPgBouncerAuthQueryUser(ctx, cluster, &pooler)
+			if err != nil {
+				return apiv1.PgBouncerIntegrationStatus{}, err
+			}
+			if user != nil && !containsAuthQueryUser(poolersIntegrations.AuthQueryUsers, *user) {
+				poolersIntegrations.AuthQueryUsers = append(poolersIntegrations.AuthQueryUsers, *user)
+			}
+			continue
+		}
+
 		// The integrated poolers are the ones whose permissions are directly
 		// managed by the instance manager.
 		//
@@ -478,9 +504,104 @@ func (r *ClusterReconciler) getPgbouncerIntegrationStatus(
 		}
 	}
 
+	sort.Slice(poolersIntegrations.AuthQueryUsers, func(i, j int) bool {
+		left, right := poolersIntegrations.AuthQueryUsers[i], poolersIntegrations.AuthQueryUsers[j]
+		if left.Name != right.Name {
+			return left.Name < right.Name
+		}
+		if left.FunctionSchema != right.FunctionSchema {
+			return left.FunctionSchema < right.FunctionSchema
+		}
+		return left.FunctionName < right.FunctionName
+	})
+
 	return poolersIntegrations, nil
 }
 
+// containsAuthQueryUser checks whether a list of auth query users contains the passed one
+func containsAuthQueryUser(users []apiv1.PgBouncerAuthQueryUser, user apiv1.PgBouncerAuthQueryUser) bool {
+	for _, item := range users {
+		if reflect.DeepEqual(item, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPgBouncerAuthQueryUser gets the user of the auth query of a pooler
+// which lets the operator provision it, reading its name from the auth
+// query secret. It returns nil when the secret doesn't exist yet or when
+// its content is not valid, as the pooler will wait for it anyway, and
+// when the user is a role the operator or the user already manage
+func (r *ClusterReconciler) getPgBouncerAuthQueryUser(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pooler *apiv1.Pooler,
+) (*apiv1.PgBouncerAuthQueryUser, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var authQuerySecret corev1.Secret
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: pooler.Namespace, Name: pooler.GetAuthQuerySecretName()},
+		&authQuerySecret,
+	)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while getting the auth query secret of pooler %s: %w", pooler.Name, err)
+	}
+
+	userName, isCertAuth, err := pgbouncerConfig.GetAuthQueryUser(&authQuerySecret)
+	if err != nil || userName == "" {
+		contextLogger.Warning("Cannot get the auth query user from the secret, skipping its provisioning",
+			"pooler", pooler.Name, "secret", authQuerySecret.Name, "error", err)
+		return nil, nil
+	}
+	if !canProvisionAuthQueryUser(cluster, userName) {
+		contextLogger.Warning("The auth query user is a role which is reserved or already managed, "+
+			"skipping its provisioning",
+			"pooler", pooler.Name, "secret", authQuerySecret.Name, "role", userName)
+		return nil, nil
+	}
+
+	user := &apiv1.PgBouncerAuthQueryUser{
+		Name:           userName,
+		FunctionSchema: pooler.Spec.PgBouncer.AuthQueryProvisioning.GetFunctionSchema(),
+		FunctionName:   pooler.Spec.PgBouncer.AuthQueryProvisioning.GetFunctionName(),
+	}
+	if !isCertAuth {
+		user.PasswordSecret = &apiv1.SecretVersion{
+			Name:    authQuerySecret.Name,
+			Version: authQuerySecret.ResourceVersion,
+		}
+	}
+
+	return user, nil
+}
+
+// canProvisionAuthQueryUser checks whether the operator can provision a
+// role as the user of an auth query. As provisioning a role lets it log
+// in and sets its password, the roles reserved by PostgreSQL or by the
+// operator, the owner of the application database and the managed roles
+// are refused
+func canProvisionAuthQueryUser(cluster *apiv1.Cluster, userName string) bool {
+	if postgres.IsRoleReserved(userName) || userName == cluster.GetApplicationDatabaseOwner() {
+		return false
+	}
+
+	if cluster.ContainsManagedRolesConfiguration() {
+		for _, role := range cluster.Spec.Managed.Roles {
+			if role.Name == userName {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // refreshCertExpiration check the expiration date of all the certificates used by the cluster
 func (r *ClusterReconciler) refreshCertsExpirations(ctx context.Context, cluster *apiv1.Cluster) error {
 	namespace := cluster.GetNamespace()
@@ -508,6 +629,14 @@ func (r *ClusterReconciler) refreshCertsExpirations(ctx context.Context, cluster
 		return err
 	}
 
+	for _, role := range cluster.GetClientCertificateRoles() {
+		err = r.setCertExpiration(ctx, cluster, role.GetClientCertificateSecretName(cluster.Name),
+			namespace, certs.TLSCertKey)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -568,7 +697,7 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 	var version string
 	var err error
 
-	if cluster.GetEnableSuperuserAccess() {
+	if cluster.IsSuperuserSecretInUse() {
 		version, err = r.getSecretResourceVersion(ctx, cluster, cluster.GetSuperuserSecretName())
 		if err != nil {
 			return err
@@ -602,6 +731,14 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 		versions.SetExternalClusterSecretVersion(secretName, &externalSecretVersion)
 	}
 
+	for _, secretName := range cluster.GetBarmanCredentialsSecrets().ToList() {
+		barmanSecretVersion, err := r.getSecretResourceVersion(ctx, cluster, secretName)
+		if err != nil {
+			return err
+		}
+		versions.SetBarmanCredentialsSecretVersion(secretName, &barmanSecretVersion)
+	}
+
 	certificates := cluster.Status.Certificates
 	// Reset the content of the unused CASecretVersion field
 	cluster.Status.SecretsResourceVersion.CASecretVersion = ""
@@ -639,6 +776,14 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 		versions.BarmanEndpointCA = version
 	}
 
+	if tdeSecret := cluster.GetTDESecretKeyRef(); tdeSecret != nil {
+		version, err = r.getSecretResourceVersion(ctx, cluster, tdeSecret.Name)
+		if err != nil {
+			return err
+		}
+		versions.TDESecretVersion = version
+	}
+
 	if cluster.Spec.Monitoring != nil {
 		versions.Metrics = make(map[string]string)
 		for _, secret := range cluster.Spec.Monitoring.CustomQueriesSecret {
@@ -715,23 +860,25 @@ func (r *ClusterReconciler) RegisterPhase(ctx context.Context,
 		cluster.Status.Conditions = []metav1.Condition{}
 	}
 
-	existingClusterStatus := cluster.Status
+	existingClusterStatus := *cluster.Status.DeepCopy()
 	cluster.Status.Phase = phase
 	cluster.Status.PhaseReason = reason
 
 	condition := metav1.Condition{
-		Type:    string(apiv1.ConditionClusterReady),
-		Status:  metav1.ConditionFalse,
-		Reason:  string(apiv1.ClusterIsNotReady),
-		Message: "Cluster Is Not Ready",
+		Type:               string(apiv1.ConditionClusterReady),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(apiv1.ClusterIsNotReady),
+		Message:            "Cluster Is Not Ready",
+		ObservedGeneration: cluster.Generation,
 	}
 
 	if cluster.Status.Phase == apiv1.PhaseHealthy {
 		condition = metav1.Condition{
-			Type:    string(apiv1.ConditionClusterReady),
-			Status:  metav1.ConditionTrue,
-			Reason:  string(apiv1.ClusterReady),
-			Message: "Cluster is Ready",
+			Type:               string(apiv1.ConditionClusterReady),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(apiv1.ClusterReady),
+			Message:            "Cluster is Ready",
+			ObservedGeneration: cluster.Generation,
 		}
 	}
 
@@ -752,15 +899,25 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	cluster *apiv1.Cluster,
 	statuses postgres.PostgresqlStatusList,
 ) error {
-	existingClusterStatus := cluster.Status
+	existingClusterStatus := *cluster.Status.DeepCopy()
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
+	var primary *postgres.PostgresqlStatus
+	for idx := range statuses.Items {
+		if statuses.Items[idx].IsPrimary && statuses.Items[idx].Error == nil {
+			primary = &statuses.Items[idx]
+		}
+	}
+
 	// we extract the instances reported state
+	now := time.Now()
 	for _, item := range statuses.Items {
-		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
-			IsPrimary:  item.IsPrimary,
-			TimeLineID: item.TimeLineID,
+		podName := apiv1.PodName(item.Pod.Name)
+		var previous *apiv1.InstanceReportedState
+		if state, ok := existingClusterStatus.InstancesReportedState[podName]; ok {
+			previous = &state
 		}
+		cluster.Status.InstancesReportedState[podName] = getInstanceReportedState(item, primary, previous, now)
 	}
 
 	// we update any relevant cluster status that depends on the primary instance
@@ -770,14 +927,185 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		if item.IsPrimary && item.TimeLineID != 0 {
 			cluster.Status.TimelineID = item.TimeLineID
 		}
+
+		// the last recoverability point is kept when the primary
+		// can't be reached or didn't archive any WAL file yet
+		if item.IsPrimary && item.LastArchivedWALTime != "" {
+			cluster.Status.LastRecoverabilityPoint = item.LastArchivedWALTime
+		}
+
+		// the roles with an MD5 password are only known by a
+		// reachable primary
+		if item.IsPrimary && item.Error == nil {
+			cluster.Status.RolesWithMD5Password = item.MD5PasswordRoles
+		}
 	}
 
+	cluster.Status.PendingRestartParameters = r.getPendingRestartParameters(ctx, cluster, primary)
+	setReplicationCondition(cluster, statuses)
+	setConfigAppliedCondition(cluster, statuses)
+	setReplicaIntegrityCondition(cluster, statuses)
+	setPostmasterRestartsCondition(cluster, statuses)
+
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
 		return r.Status().Update(ctx, cluster)
 	}
 	return nil
 }
 
+// getInstanceReportedState builds the state of an instance stored in the
+// cluster status, from its status and from the one of the primary. The
+// position of the instance in the WAL stream is taken from the previous
+// state until it expires, unless the role of the instance changed
+func getInstanceReportedState(
+	item postgres.PostgresqlStatus,
+	primary *postgres.PostgresqlStatus,
+	previous *apiv1.InstanceReportedState,
+	now time.Time,
+) apiv1.InstanceReportedState {
+	state := apiv1.InstanceReportedState{
+		IsPrimary:         item.IsPrimary,
+		TimeLineID:        item.TimeLineID,
+		LifecycleHooks:    getLifecycleHooksStatus(item.LifecycleHooks),
+		StartupCheck:      getStartupCheckStatus(item.StartupCheck),
+		PostmasterRestart: getPostmasterRestartStatus(item.PostmasterRestart),
+		Ready:             item.IsPodReady,
+		PendingRestart:    item.PendingRestart,
+	}
+
+	switch {
+	case item.Error != nil:
+		state.Error = getInstanceReportedError(item.Error)
+	case item.IsPrimary:
+		state.Role = specs.ClusterRoleLabelPrimary
+	default:
+		state.Role = specs.ClusterRoleLabelReplica
+	}
+
+	state.WAL = getInstanceWALStatus(item, primary, now)
+	if previous != nil && previous.WAL != nil {
+		// The last known position is kept when the instance can't be reached
+		unreachable := state.WAL == nil
+		fresh := previous.Role == state.Role && !isInstanceWALStatusExpired(previous.WAL, now)
+		if unreachable || fresh {
+			state.WAL = previous.WAL.DeepCopy()
+		}
+	}
+
+	return state
+}
+
+// getInstanceReportedError gets the reason why the status of an instance
+// couldn't be read. The error details, such as addresses, timings or the
+// body of the response, change at every attempt and storing them would
+// update the cluster status at every reconciliation loop: they are logged
+// by the status client instead
+func getInstanceReportedError(err error) string {
+	var statusError *instance.StatusError
+	if errors.As(err, &statusError) {
+		return fmt.Sprintf("the instance manager answered with status code %d", statusError.StatusCode)
+	}
+
+	var netError net.Error
+	switch {
+	case errors.As(err, &netError) && netError.Timeout():
+		return "timeout while reading the status of the instance"
+	case netError != nil:
+		return "cannot connect to the instance manager"
+	default:
+		return "cannot read the status of the instance"
+	}
+}
+
+// getInstanceWALStatus gets the position of an instance in the WAL stream,
+// computing the replay lag of the replicas from the status of the primary
+func getInstanceWALStatus(
+	item postgres.PostgresqlStatus,
+	primary *postgres.PostgresqlStatus,
+	now time.Time,
+) *apiv1.InstanceWALStatus {
+	if item.Error != nil {
+		return nil
+	}
+
+	status := &apiv1.InstanceWALStatus{
+		CurrentLSN:  string(item.CurrentLsn),
+		ReceivedLSN: string(item.ReceivedLsn),
+		ReplayLSN:   string(item.ReplayLsn),
+		ObservedAt:  now.UTC().Format(time.RFC3339),
+	}
+	if item.IsPrimary || primary == nil {
+		return status
+	}
+
+	primaryLSN, primaryErr := primary.CurrentLsn.Parse()
+	replayLSN, replayErr := item.ReplayLsn.Parse()
+	if primaryErr == nil && replayErr == nil {
+		lag := max(primaryLSN-replayLSN, 0)
+		status.ReplayLagBytes = &lag
+	}
+
+	for _, replica := range primary.ReplicationInfo {
+		if replica.ApplicationName == item.Pod.Name {
+			status.ReplayLag = replica.ReplayLag
+		}
+	}
+
+	return status
+}
+
+// isInstanceWALStatusExpired checks whether the position of an instance
+// in the WAL stream needs to be refreshed
+func isInstanceWALStatusExpired(status *apiv1.InstanceWALStatus, now time.Time) bool {
+	observedAt, err := time.Parse(time.RFC3339, status.ObservedAt)
+	return err != nil || now.Sub(observedAt) >= instanceWALStatusRefreshInterval
+}
+
+// getPendingRestartParameters returns the configuration parameters of the
+// cluster whose new value is not in use by the primary yet, as they need a
+// restart of PostgreSQL. The primary compares them with the settings it is
+// running with, so the result is the same before and after the new
+// configuration is loaded. The previous list is kept when the primary can't
+// be reached
+func (r *ClusterReconciler) getPendingRestartParameters(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	primary *postgres.PostgresqlStatus,
+) []string {
+	parameters := cluster.Spec.PostgresConfiguration.Parameters
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	if primary == nil || primary.Pod == nil {
+		return cluster.Status.PendingRestartParameters
+	}
+
+	diff, err := r.GetConfigurationDiffFromInstance(ctx, primary.Pod, parameters)
+	if err != nil {
+		log.FromContext(ctx).Info("Cannot get the parameters pending a restart from the primary",
+			"primary", primary.Pod.Name, "err", err)
+		return cluster.Status.PendingRestartParameters
+	}
+
+	return getRestartParameterNames(diff)
+}
+
+// getRestartParameterNames returns the sorted names of the parameters
+// needing a restart in the passed configuration diff
+func getRestartParameterNames(diff *postgres.ConfigurationDiff) []string {
+	if !diff.RequiresRestart() {
+		return nil
+	}
+
+	names := make([]string, len(diff.Restart))
+	for i, change := range diff.Restart {
+		names[i] = change.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
 // getPodsTopology returns a map with all the information about the pods topology
 func getPodsTopology(
 	ctx context.Context,
@@ -810,3 +1138,57 @@ func getPodsTopology(
 
 	return apiv1.Topology{SuccessfullyExtracted: true, Instances: data, NodesUsed: int32(len(nodesMap))}
 }
+
+// getLifecycleHooksStatus converts the results of the lifecycle hooks
+// reported by an instance to the format stored in the cluster status
+func getLifecycleHooksStatus(results []postgres.LifecycleHookResult) []apiv1.LifecycleHookStatus {
+	if len(results) == 0 {
+		return nil
+	}
+
+	status := make([]apiv1.LifecycleHookStatus, len(results))
+	for i, result := range results {
+		status[i] = apiv1.LifecycleHookStatus{
+			Name:      result.Name,
+			Point:     apiv1.LifecycleHookPoint(result.Point),
+			StartedAt: result.StartedAt,
+			Duration:  result.Duration,
+			Succeeded: result.Succeeded,
+			Message:   result.Message,
+		}
+	}
+	return status
+}
+
+// getStartupCheckStatus converts the result of the startup consistency
+// checks reported by an instance to the format stored in the cluster status
+func getStartupCheckStatus(result *postgres.StartupCheckResult) *apiv1.StartupCheckStatus {
+	if result == nil {
+		return nil
+	}
+
+	return &apiv1.StartupCheckStatus{
+		Time:          result.Time,
+		Passed:        result.Passed,
+		VerifiedFiles: result.VerifiedFiles,
+		Message:       result.Message,
+		Quarantined:   result.Quarantined,
+	}
+}
+
+// getPostmasterRestartStatus converts the unexpected exits of the postmaster
+// reported by an instance to the format stored in the cluster status
+func getPostmasterRestartStatus(result *postgres.PostmasterRestartResult) *apiv1.PostmasterRestartStatus {
+	if result == nil {
+		return nil
+	}
+
+	return &apiv1.PostmasterRestartStatus{
+		Attempts:        result.Attempts,
+		LastExitReason:  apiv1.PostmasterExitReason(result.LastExitReason),
+		LastExitTime:    result.LastExitTime,
+		Message:         result.Message,
+		NextRestartTime: result.NextRestartTime,
+		Paused:          result.Paused,
+	}
+}
@@ -18,15 +18,22 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/url"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -76,6 +83,60 @@ var _ = Describe("cluster_status unit tests", func() {
 		Expect(intStatus.Secrets).To(HaveLen(1))
 	})
 
+	It("reports the auth query users which the poolers let the operator provision", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pgbouncer-auth", Namespace: namespace},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("pgbouncer_auth"),
+				corev1.BasicAuthPasswordKey: []byte("password"),
+			},
+		}
+		Expect(env.client.Create(ctx, secret)).To(Succeed())
+
+		provisionedPooler := func() v1.Pooler {
+			pooler := *newFakePooler(env.client, cluster)
+			pooler.Spec.PgBouncer.AuthQuerySecret = &v1.LocalObjectReference{Name: secret.Name}
+			pooler.Spec.PgBouncer.AuthQueryProvisioning = &v1.PgBouncerAuthQueryProvisioning{}
+			return pooler
+		}
+		poolerList := v1.PoolerList{Items: []v1.Pooler{provisionedPooler(), provisionedPooler()}}
+
+		intStatus, err := env.clusterReconciler.getPgbouncerIntegrationStatus(ctx, cluster, poolerList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(intStatus.Secrets).To(BeEmpty())
+		Expect(intStatus.AuthQueryUsers).To(ConsistOf(v1.PgBouncerAuthQueryUser{
+			Name:           "pgbouncer_auth",
+			PasswordSecret: &v1.SecretVersion{Name: secret.Name, Version: secret.ResourceVersion},
+			FunctionSchema: v1.DefaultPgBouncerAuthQueryFunctionSchema,
+			FunctionName:   v1.DefaultPgBouncerAuthQueryFunctionName,
+		}))
+	})
+
+	It("doesn't provision the auth query users which are reserved or already managed", func() {
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Bootstrap: &v1.BootstrapConfiguration{
+					InitDB: &v1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				Managed: &v1.ManagedConfiguration{
+					Roles: []v1.RoleConfiguration{{Name: "dante"}},
+				},
+			},
+		}
+
+		Expect(canProvisionAuthQueryUser(cluster, "pgbouncer_auth")).To(BeTrue())
+		Expect(canProvisionAuthQueryUser(cluster, "postgres")).To(BeFalse())
+		Expect(canProvisionAuthQueryUser(cluster, "streaming_replica")).To(BeFalse())
+		Expect(canProvisionAuthQueryUser(cluster, "pg_monitor")).To(BeFalse())
+		Expect(canProvisionAuthQueryUser(cluster, "app")).To(BeFalse())
+		Expect(canProvisionAuthQueryUser(cluster, "dante")).To(BeFalse())
+	})
+
 	It("makes sure getObjectResourceVersion returns the correct object version", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace(env.client)
@@ -171,3 +232,159 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("pending restart parameters", func() {
+	It("lists the parameters needing a restart", func() {
+		diff := &postgres.ConfigurationDiff{
+			Restart: []postgres.ParameterChange{
+				{Name: "shared_buffers", CurrentValue: "128MB", NewValue: "1GB", Context: "postmaster"},
+				{Name: "max_connections", CurrentValue: "100", NewValue: "200", Context: "postmaster"},
+			},
+			Reload: []postgres.ParameterChange{
+				{Name: "work_mem", CurrentValue: "4MB", NewValue: "16MB", Context: "user"},
+			},
+		}
+		Expect(getRestartParameterNames(diff)).To(Equal([]string{"max_connections", "shared_buffers"}))
+	})
+
+	It("is empty when no parameter needs a restart", func() {
+		Expect(getRestartParameterNames(&postgres.ConfigurationDiff{
+			Reload: []postgres.ParameterChange{{Name: "work_mem", NewValue: "16MB"}},
+		})).To(BeNil())
+	})
+
+	It("is empty when the cluster has no parameters", func(ctx SpecContext) {
+		cluster := &v1.Cluster{
+			Status: v1.ClusterStatus{PendingRestartParameters: []string{"shared_buffers"}},
+		}
+		Expect((&ClusterReconciler{}).getPendingRestartParameters(ctx, cluster, nil)).To(BeNil())
+	})
+
+	It("keeps the previous parameters when the primary is not available", func(ctx SpecContext) {
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				PostgresConfiguration: v1.PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "1GB"},
+				},
+			},
+			Status: v1.ClusterStatus{PendingRestartParameters: []string{"shared_buffers"}},
+		}
+		Expect((&ClusterReconciler{}).getPendingRestartParameters(ctx, cluster, nil)).
+			To(Equal([]string{"shared_buffers"}))
+	})
+})
+
+var _ = Describe("lifecycle hooks status", func() {
+	It("converts the results reported by the instances", func() {
+		Expect(getLifecycleHooksStatus([]postgres.LifecycleHookResult{
+			{
+				Name:      "notify",
+				Point:     "postPromotion",
+				StartedAt: "2024-05-01T10:00:00Z",
+				Duration:  2,
+				Message:   "timed out after 1s",
+			},
+		})).To(Equal([]v1.LifecycleHookStatus{
+			{
+				Name:      "notify",
+				Point:     v1.LifecycleHookPostPromotion,
+				StartedAt: "2024-05-01T10:00:00Z",
+				Duration:  2,
+				Message:   "timed out after 1s",
+			},
+		}))
+	})
+
+	It("is empty when no hook has been executed", func() {
+		Expect(getLifecycleHooksStatus(nil)).To(BeNil())
+	})
+})
+
+var _ = Describe("instances reported state", func() {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	primary := postgres.PostgresqlStatus{
+		Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+		IsPrimary:  true,
+		IsPodReady: true,
+		TimeLineID: 2,
+		CurrentLsn: "0/3000100",
+		ReplicationInfo: postgres.PgStatReplicationList{
+			{ApplicationName: "cluster-example-2", ReplayLag: "00:00:00.0042"},
+		},
+	}
+	replica := postgres.PostgresqlStatus{
+		Pod:            &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+		TimeLineID:     2,
+		ReceivedLsn:    "0/3000100",
+		ReplayLsn:      "0/3000000",
+		PendingRestart: true,
+	}
+
+	It("reports the role and the position in the WAL stream of the instances", func() {
+		state := getInstanceReportedState(primary, &primary, nil, now)
+		Expect(state.Role).To(Equal("primary"))
+		Expect(state.Ready).To(BeTrue())
+		Expect(state.WAL.CurrentLSN).To(Equal("0/3000100"))
+		Expect(state.WAL.ReplayLagBytes).To(BeNil())
+
+		state = getInstanceReportedState(replica, &primary, nil, now)
+		Expect(state.Role).To(Equal("replica"))
+		Expect(state.PendingRestart).To(BeTrue())
+		Expect(state.WAL.ReplayLSN).To(Equal("0/3000000"))
+		Expect(*state.WAL.ReplayLagBytes).To(BeEquivalentTo(0x100))
+		Expect(state.WAL.ReplayLag).To(Equal("00:00:00.0042"))
+		Expect(state.WAL.ObservedAt).To(Equal("2024-06-01T12:00:00Z"))
+	})
+
+	It("refreshes the position in the WAL stream at most once per interval", func() {
+		previous := getInstanceReportedState(replica, &primary, nil, now)
+
+		progressed := replica
+		progressed.ReplayLsn = "0/3000100"
+		state := getInstanceReportedState(progressed, &primary, &previous, now.Add(10*time.Second))
+		Expect(state.WAL.ReplayLSN).To(Equal("0/3000000"))
+		Expect(state.PendingRestart).To(BeTrue())
+
+		state = getInstanceReportedState(progressed, &primary, &previous, now.Add(instanceWALStatusRefreshInterval))
+		Expect(state.WAL.ReplayLSN).To(Equal("0/3000100"))
+		Expect(*state.WAL.ReplayLagBytes).To(BeEquivalentTo(0))
+	})
+
+	It("keeps the last known position of the instances that can't be reached", func() {
+		previous := getInstanceReportedState(replica, &primary, nil, now)
+
+		unreachable := postgres.PostgresqlStatus{
+			Pod:   replica.Pod,
+			Error: errors.New("connection refused"),
+		}
+		state := getInstanceReportedState(unreachable, &primary, &previous, now.Add(time.Hour))
+		Expect(state.Role).To(BeEmpty())
+		Expect(state.Error).To(Equal("cannot read the status of the instance"))
+		Expect(state.WAL.ReplayLSN).To(Equal("0/3000000"))
+		Expect(state.WAL.ObservedAt).To(Equal("2024-06-01T12:00:00Z"))
+	})
+})
+
+var _ = Describe("getInstanceReportedError", func() {
+	It("doesn't report the details changing at every attempt", func() {
+		first := &url.Error{Op: "Get", URL: "http://10.0.0.1:8000/pg/status", Err: &net.OpError{
+			Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused"),
+		}}
+		second := &url.Error{Op: "Get", URL: "http://10.0.0.2:8000/pg/status", Err: &net.OpError{
+			Op: "dial", Net: "tcp", Err: errors.New("connect: no route to host"),
+		}}
+		Expect(getInstanceReportedError(first)).To(Equal("cannot connect to the instance manager"))
+		Expect(getInstanceReportedError(second)).To(Equal(getInstanceReportedError(first)))
+	})
+
+	It("reports the status code answered by the instance manager", func() {
+		err := &instance.StatusError{StatusCode: 500, Body: "error at 2024-06-01T12:00:00Z"}
+		Expect(getInstanceReportedError(err)).To(Equal("the instance manager answered with status code 500"))
+	})
+
+	It("reports the timeouts", func() {
+		err := &url.Error{Op: "Get", URL: "http://10.0.0.1:8000/pg/status", Err: context.DeadlineExceeded}
+		Expect(getInstanceReportedError(err)).To(Equal("timeout while reading the status of the instance"))
+	})
+})
@@ -0,0 +1,312 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// switchoverCheckInterval is how often the preconditions of a
+// pending switchover are checked again
+const switchoverCheckInterval = 5 * time.Second
+
+// reconcileSwitchoverRequest promotes the instance requested by a Switchover
+// object once the cluster is healthy and the preconditions on the replication
+// state of the instance are satisfied. It returns the name of the instance
+// whose promotion has just been requested, if any, and how long to wait
+// before checking the switchover again, if it is pending or running
+func (r *ClusterReconciler) reconcileSwitchoverRequest(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (string, time.Duration, error) {
+	switchover, err := r.getActiveSwitchover(ctx, cluster)
+	if err != nil || switchover == nil {
+		return "", 0, err
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("switchover", switchover.Name)
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	if switchover.Status.Phase == apiv1.SwitchoverPhaseRunning {
+		// The new primary has not noticed the promotion request yet
+		if cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+			return "", switchoverCheckInterval, nil
+		}
+		if cluster.Status.CurrentPrimary == switchover.Spec.TargetPrimary {
+			return "", 0, r.completeSwitchover(ctx, cluster, switchover,
+				fmt.Sprintf("Instance %s promoted", switchover.Spec.TargetPrimary))
+		}
+		return "", 0, r.failSwitchover(ctx, cluster, switchover,
+			fmt.Sprintf("instance %s has been promoted instead", cluster.Status.CurrentPrimary))
+	}
+
+	if cluster.Status.CurrentPrimary == switchover.Spec.TargetPrimary {
+		return "", 0, r.completeSwitchover(ctx, cluster, switchover,
+			fmt.Sprintf("Instance %s is already the primary", switchover.Spec.TargetPrimary))
+	}
+
+	if reason := checkSwitchoverPreconditions(cluster, switchover, instancesStatus); reason != "" {
+		timeout := switchover.Spec.Timeout
+		if timeout != nil && time.Since(switchover.CreationTimestamp.Time) > timeout.Duration {
+			return "", 0, r.failSwitchover(ctx, cluster, switchover,
+				fmt.Sprintf("timeout expired while waiting: %s", reason))
+		}
+
+		message := fmt.Sprintf("Waiting: %s", reason)
+		if switchover.Status.Phase != apiv1.SwitchoverPhasePending || switchover.Status.Message != message {
+			contextLogger.Info("Waiting for the preconditions of the switchover", "reason", reason)
+			if err := r.setSwitchoverStatus(ctx, switchover, func(status *apiv1.SwitchoverStatus) {
+				status.Phase = apiv1.SwitchoverPhasePending
+				status.Message = message
+			}); err != nil {
+				return "", 0, err
+			}
+		}
+		return "", switchoverCheckInterval, nil
+	}
+
+	previousPrimary := cluster.Status.CurrentPrimary
+	contextLogger.Info("Switching over as requested by the Switchover object",
+		"currentPrimary", previousPrimary,
+		"targetPrimary", switchover.Spec.TargetPrimary)
+	r.Recorder.Eventf(cluster, "Normal", events.SwitchingOver,
+		"Switching over from %v to %v, as requested by switchover %v",
+		previousPrimary, switchover.Spec.TargetPrimary, switchover.Name)
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v, as requested by switchover %v",
+			switchover.Spec.TargetPrimary, switchover.Name)); err != nil {
+		return "", 0, err
+	}
+	if err := r.setPrimaryInstance(ctx, cluster, switchover.Spec.TargetPrimary); err != nil {
+		return "", 0, err
+	}
+
+	return switchover.Spec.TargetPrimary, 0, r.setSwitchoverStatus(ctx, switchover,
+		func(status *apiv1.SwitchoverStatus) {
+			status.Phase = apiv1.SwitchoverPhaseRunning
+			status.Message = fmt.Sprintf("Promoting instance %s", switchover.Spec.TargetPrimary)
+			status.PreviousPrimary = previousPrimary
+			status.StartedAt = ptr.To(metav1.Now())
+		})
+}
+
+// checkSwitchoverPreconditions checks whether the target instance of the
+// switchover can be promoted. When it can't, the reason is returned
+func checkSwitchoverPreconditions(
+	cluster *apiv1.Cluster,
+	switchover *apiv1.Switchover,
+	instancesStatus postgres.PostgresqlStatusList,
+) string {
+	target := switchover.Spec.TargetPrimary
+
+	if cluster.Status.Phase != apiv1.PhaseHealthy {
+		return fmt.Sprintf("the cluster is not healthy (%s)", cluster.Status.Phase)
+	}
+
+	if cluster.IsInstanceFenced(target) {
+		return fmt.Sprintf("instance %s is fenced", target)
+	}
+
+	targetStatus := findInstanceStatus(instancesStatus, target)
+	if targetStatus == nil {
+		return fmt.Sprintf("instance %s not found", target)
+	}
+	if !targetStatus.HasHTTPStatus() {
+		return fmt.Sprintf("the status of instance %s is not available", target)
+	}
+	if targetStatus.Pod == nil || !utils.IsPodReady(*targetStatus.Pod) {
+		return fmt.Sprintf("instance %s is not ready", target)
+	}
+	if !targetStatus.IsWalReceiverActive {
+		return fmt.Sprintf("instance %s is not streaming from the primary", target)
+	}
+
+	primaryStatus := findInstanceStatus(instancesStatus, cluster.Status.CurrentPrimary)
+	if primaryStatus == nil || !primaryStatus.HasHTTPStatus() {
+		return fmt.Sprintf("the status of the primary %s is not available", cluster.Status.CurrentPrimary)
+	}
+
+	if switchover.Spec.MaxLag != nil {
+		lag, err := getReplayLag(primaryStatus, targetStatus)
+		if err != nil {
+			return fmt.Sprintf("cannot compute the lag of instance %s: %v", target, err)
+		}
+		if lag > switchover.Spec.MaxLag.Value() {
+			return fmt.Sprintf("instance %s has %d bytes of WAL still to replay, more than %s",
+				target, lag, switchover.Spec.MaxLag.String())
+		}
+	}
+
+	if switchover.Spec.RequireSynchronous && !isSynchronousStandby(primaryStatus, target) {
+		return fmt.Sprintf("instance %s is not a synchronous standby", target)
+	}
+
+	return ""
+}
+
+// findInstanceStatus returns the status of the instance with the passed name
+func findInstanceStatus(instancesStatus postgres.PostgresqlStatusList, name string) *postgres.PostgresqlStatus {
+	for idx := range instancesStatus.Items {
+		status := &instancesStatus.Items[idx]
+		if status.Pod != nil && status.Pod.Name == name {
+			return status
+		}
+	}
+	return nil
+}
+
+// getReplayLag returns the amount of WAL, in bytes, that the target
+// instance has still to replay to reach the position of the primary.
+// A designated primary, being in recovery, reports its replay position
+func getReplayLag(primaryStatus, targetStatus *postgres.PostgresqlStatus) (int64, error) {
+	primaryLsn := primaryStatus.CurrentLsn
+	if primaryLsn == "" {
+		primaryLsn = primaryStatus.ReplayLsn
+	}
+
+	primaryPosition, err := primaryLsn.Parse()
+	if err != nil {
+		return 0, err
+	}
+	targetPosition, err := targetStatus.ReplayLsn.Parse()
+	if err != nil {
+		return 0, err
+	}
+
+	return max(primaryPosition-targetPosition, 0), nil
+}
+
+// isSynchronousStandby checks whether the primary reports the
+// passed instance as a synchronous standby
+func isSynchronousStandby(primaryStatus *postgres.PostgresqlStatus, name string) bool {
+	for _, replication := range primaryStatus.ReplicationInfo {
+		if replication.ApplicationName == name {
+			return replication.SyncState == "sync" || replication.SyncState == "quorum"
+		}
+	}
+	return false
+}
+
+// completeSwitchover marks the switchover as completed
+func (r *ClusterReconciler) completeSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	switchover *apiv1.Switchover,
+	message string,
+) error {
+	log.FromContext(ctx).Info("Switchover completed", "currentPrimary", cluster.Status.CurrentPrimary)
+	r.Recorder.Eventf(cluster, "Normal", events.SwitchoverCompleted, "Switchover %s completed: %s",
+		switchover.Name, message)
+
+	return r.setSwitchoverStatus(ctx, switchover, func(status *apiv1.SwitchoverStatus) {
+		status.Phase = apiv1.SwitchoverPhaseCompleted
+		status.Message = message
+		status.StoppedAt = ptr.To(metav1.Now())
+	})
+}
+
+// failSwitchover marks the switchover as failed, reporting the reason
+func (r *ClusterReconciler) failSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	switchover *apiv1.Switchover,
+	reason string,
+) error {
+	log.FromContext(ctx).Warning("Switchover failed", "reason", reason)
+	r.Recorder.Eventf(cluster, "Warning", events.SwitchoverFailed, "Switchover %s failed: %s",
+		switchover.Name, reason)
+
+	return r.setSwitchoverStatus(ctx, switchover, func(status *apiv1.SwitchoverStatus) {
+		status.Phase = apiv1.SwitchoverPhaseFailed
+		status.Message = reason
+		status.StoppedAt = ptr.To(metav1.Now())
+	})
+}
+
+// setSwitchoverStatus patches the status of the switchover
+func (r *ClusterReconciler) setSwitchoverStatus(
+	ctx context.Context,
+	switchover *apiv1.Switchover,
+	update func(status *apiv1.SwitchoverStatus),
+) error {
+	origSwitchover := switchover.DeepCopy()
+	update(&switchover.Status)
+	return r.Status().Patch(ctx, switchover, client.MergeFrom(origSwitchover))
+}
+
+// getActiveSwitchover returns the oldest Switchover object of the cluster
+// that is not yet completed or failed, if any
+func (r *ClusterReconciler) getActiveSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*apiv1.Switchover, error) {
+	var switchovers apiv1.SwitchoverList
+	if err := r.List(ctx, &switchovers, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var result *apiv1.Switchover
+	for idx := range switchovers.Items {
+		switchover := &switchovers.Items[idx]
+		if switchover.Spec.Cluster.Name != cluster.Name || switchover.IsDone() {
+			continue
+		}
+
+		if result == nil || switchover.CreationTimestamp.Before(&result.CreationTimestamp) ||
+			(switchover.CreationTimestamp.Equal(&result.CreationTimestamp) && switchover.Name < result.Name) {
+			result = switchover
+		}
+	}
+
+	return result, nil
+}
+
+// mapSwitchoversToClusters returns a function mapping a Switchover
+// object to the cluster it refers to
+func (r *ClusterReconciler) mapSwitchoversToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		switchover, ok := obj.(*apiv1.Switchover)
+		if !ok || switchover.Spec.Cluster.Name == "" {
+			return nil
+		}
+
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: switchover.Namespace,
+					Name:      switchover.Spec.Cluster.Name,
+				},
+			},
+		}
+	}
+}
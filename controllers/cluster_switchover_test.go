@@ -0,0 +1,264 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("declarative switchover", func() {
+	var (
+		cluster         *apiv1.Cluster
+		switchover      *apiv1.Switchover
+		instancesStatus postgres.PostgresqlStatusList
+		fakeClient      k8client.Client
+		r               *ClusterReconciler
+	)
+
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	getSwitchover := func(ctx SpecContext) *apiv1.Switchover {
+		var result apiv1.Switchover
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(switchover), &result)).To(Succeed())
+		return &result
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{Instances: 3},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		switchover = &apiv1.Switchover{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "promote-2",
+				Namespace:         cluster.Namespace,
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: apiv1.SwitchoverSpec{
+				Cluster:       apiv1.LocalObjectReference{Name: cluster.Name},
+				TargetPrimary: "cluster-example-2",
+			},
+		}
+		instancesStatus = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:        newPod("cluster-example-1"),
+					IsPrimary:  true,
+					CurrentLsn: "0/5000000",
+					ReplicationInfo: postgres.PgStatReplicationList{
+						{ApplicationName: "cluster-example-2", SyncState: "async"},
+						{ApplicationName: "cluster-example-3", SyncState: "quorum"},
+					},
+				},
+				{
+					Pod:                 newPod("cluster-example-2"),
+					IsWalReceiverActive: true,
+					ReplayLsn:           "0/4000000",
+				},
+				{
+					Pod:                 newPod("cluster-example-3"),
+					IsWalReceiverActive: true,
+					ReplayLsn:           "0/5000000",
+				},
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, switchover).
+			WithStatusSubresource(cluster, switchover).
+			Build()
+		r = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+		}
+	})
+
+	Context("checking the preconditions", func() {
+		It("accepts a streaming replica of a healthy cluster", func() {
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).To(BeEmpty())
+		})
+
+		It("waits for the cluster to be healthy", func() {
+			cluster.Status.Phase = apiv1.PhaseUpgrade
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).
+				To(ContainSubstring("not healthy"))
+		})
+
+		It("rejects unknown and not streaming instances", func() {
+			switchover.Spec.TargetPrimary = "cluster-example-4"
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).
+				To(ContainSubstring("not found"))
+
+			switchover.Spec.TargetPrimary = "cluster-example-2"
+			instancesStatus.Items[1].IsWalReceiverActive = false
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).
+				To(ContainSubstring("not streaming"))
+		})
+
+		It("checks the replay lag of the target instance", func() {
+			switchover.Spec.MaxLag = ptrQuantity("8Mi")
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).
+				To(ContainSubstring("still to replay"))
+
+			switchover.Spec.MaxLag = ptrQuantity("16Mi")
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).To(BeEmpty())
+		})
+
+		It("checks the synchronous state of the target instance", func() {
+			switchover.Spec.RequireSynchronous = true
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).
+				To(ContainSubstring("not a synchronous standby"))
+
+			switchover.Spec.TargetPrimary = "cluster-example-3"
+			Expect(checkSwitchoverPreconditions(cluster, switchover, instancesStatus)).To(BeEmpty())
+		})
+	})
+
+	It("does nothing without a switchover request", func(ctx SpecContext) {
+		Expect(fakeClient.Delete(ctx, switchover)).To(Succeed())
+
+		promoted, requeue, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeEmpty())
+		Expect(requeue).To(BeZero())
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("doesn't hold the reconciliation loop while the primary is being bootstrapped", func(ctx SpecContext) {
+		// The target primary is set as soon as the first instance is
+		// created, while the current one is set once it is running
+		cluster.Status.Phase = apiv1.PhaseFirstPrimary
+		cluster.Status.CurrentPrimary = ""
+		instancesStatus = postgres.PostgresqlStatusList{}
+
+		Expect(fakeClient.Delete(ctx, switchover)).To(Succeed())
+		promoted, requeue, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeEmpty())
+		Expect(requeue).To(BeZero())
+
+		switchover.ResourceVersion = ""
+		Expect(fakeClient.Create(ctx, switchover)).To(Succeed())
+		promoted, requeue, err = r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeEmpty())
+		Expect(requeue).To(Equal(switchoverCheckInterval))
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("keeps the switchover pending while the preconditions are not satisfied", func(ctx SpecContext) {
+		switchover.Spec.RequireSynchronous = true
+		Expect(fakeClient.Update(ctx, switchover)).To(Succeed())
+
+		promoted, requeue, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeEmpty())
+		Expect(requeue).To(Equal(switchoverCheckInterval))
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+
+		result := getSwitchover(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.SwitchoverPhasePending))
+		Expect(result.Status.Message).To(ContainSubstring("not a synchronous standby"))
+	})
+
+	It("fails the switchover when the timeout expires", func(ctx SpecContext) {
+		switchover.Spec.RequireSynchronous = true
+		switchover.Spec.Timeout = &metav1.Duration{Duration: time.Minute}
+		switchover.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		Expect(fakeClient.Update(ctx, switchover)).To(Succeed())
+
+		_, requeue, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeue).To(BeZero())
+
+		result := getSwitchover(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.SwitchoverPhaseFailed))
+		Expect(result.Status.Message).To(ContainSubstring("timeout expired"))
+	})
+
+	It("promotes the target instance and completes the switchover", func(ctx SpecContext) {
+		promoted, _, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(Equal("cluster-example-2"))
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+
+		result := getSwitchover(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.SwitchoverPhaseRunning))
+		Expect(result.Status.PreviousPrimary).To(Equal("cluster-example-1"))
+		Expect(result.Status.StartedAt).ToNot(BeNil())
+
+		// The new primary has not been promoted yet
+		promoted, requeue, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeEmpty())
+		Expect(requeue).To(Equal(switchoverCheckInterval))
+		Expect(getSwitchover(ctx).Status.Phase).To(Equal(apiv1.SwitchoverPhaseRunning))
+
+		cluster.Status.CurrentPrimary = "cluster-example-2"
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		_, _, err = r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+
+		result = getSwitchover(ctx)
+		Expect(result.Status.Phase).To(Equal(apiv1.SwitchoverPhaseCompleted))
+		Expect(result.Status.StoppedAt).ToNot(BeNil())
+	})
+
+	It("fails a running switchover when another instance has been promoted", func(ctx SpecContext) {
+		switchover.Status.Phase = apiv1.SwitchoverPhaseRunning
+		Expect(fakeClient.Status().Update(ctx, switchover)).To(Succeed())
+		cluster.Status.CurrentPrimary = "cluster-example-3"
+		cluster.Status.TargetPrimary = "cluster-example-3"
+
+		_, _, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getSwitchover(ctx).Status.Phase).To(Equal(apiv1.SwitchoverPhaseFailed))
+	})
+})
+
+func ptrQuantity(value string) *resource.Quantity {
+	quantity := resource.MustParse(value)
+	return &quantity
+}
@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcileTDEPassphrases keeps, in a secret owned by the cluster, every TDE
+// passphrase which may still be wrapping the data encryption key of an
+// instance. The passphrase contained in the TDE secret is added as soon as
+// it is seen, while the ones used before a rotation are removed only when
+// every instance reports having wrapped its key with the current one
+func (r *ClusterReconciler) reconcileTDEPassphrases(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	secretKeyRef := cluster.GetTDESecretKeyRef()
+	if secretKeyRef == nil {
+		return nil
+	}
+
+	var tdeSecret corev1.Secret
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: secretKeyRef.Name},
+		&tdeSecret,
+	); err != nil {
+		return err
+	}
+
+	passphrase, ok := tdeSecret.Data[secretKeyRef.Key]
+	if !ok {
+		return fmt.Errorf("missing %s entry in Secret %s", secretKeyRef.Key, secretKeyRef.Name)
+	}
+
+	var passphrasesSecret corev1.Secret
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetTDEPassphrasesSecretName()},
+		&passphrasesSecret,
+	)
+	if apierrs.IsNotFound(err) {
+		passphrasesSecret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetTDEPassphrasesSecretName(),
+				Namespace: cluster.Namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{tdeSecret.ResourceVersion: passphrase},
+		}
+		cluster.SetInheritedDataAndOwnership(&passphrasesSecret.ObjectMeta)
+		return r.Create(ctx, &passphrasesSecret)
+	}
+	if err != nil {
+		return err
+	}
+
+	origSecret := passphrasesSecret.DeepCopy()
+	if passphrasesSecret.Data == nil {
+		passphrasesSecret.Data = make(map[string][]byte)
+	}
+
+	if isTDERotationCompleted(cluster, instancesStatus, tdeSecret.ResourceVersion) {
+		passphrasesSecret.Data = map[string][]byte{tdeSecret.ResourceVersion: passphrase}
+	} else if !containsTDEPassphrase(passphrasesSecret.Data, passphrase) {
+		passphrasesSecret.Data[tdeSecret.ResourceVersion] = passphrase
+	}
+
+	if maps.EqualFunc(origSecret.Data, passphrasesSecret.Data, func(a, b []byte) bool {
+		return string(a) == string(b)
+	}) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Updating the TDE passphrases which may still be in use",
+		"secret", passphrasesSecret.Name, "count", len(passphrasesSecret.Data))
+	return r.Patch(ctx, &passphrasesSecret, client.MergeFrom(origSecret))
+}
+
+// isTDERotationCompleted checks if every instance of the cluster reports
+// having wrapped its data encryption key with the passphrase contained
+// in the passed version of the TDE secret
+func isTDERotationCompleted(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	secretVersion string,
+) bool {
+	for _, instanceName := range cluster.Status.InstanceNames {
+		reported := false
+		for _, item := range instancesStatus.Items {
+			if item.Pod == nil || item.Pod.Name != instanceName {
+				continue
+			}
+			reported = item.Error == nil && item.TDESecretVersion == secretVersion
+		}
+		if !reported {
+			return false
+		}
+	}
+	return true
+}
+
+// containsTDEPassphrase checks if the passed passphrase is
+// among the ones already kept
+func containsTDEPassphrase(passphrases map[string][]byte, passphrase []byte) bool {
+	for _, value := range passphrases {
+		if string(value) == string(passphrase) {
+			return true
+		}
+	}
+	return false
+}
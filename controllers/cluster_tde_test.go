@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TDE passphrases", func() {
+	var (
+		cluster   *apiv1.Cluster
+		tdeSecret *corev1.Secret
+		r         *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 2,
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					TDE: &apiv1.TDEConfiguration{
+						Enabled: true,
+						SecretKeyRef: &apiv1.SecretKeySelector{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "tde"},
+							Key:                  "passphrase",
+						},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				InstanceNames: []string{"cluster-example-1", "cluster-example-2"},
+			},
+		}
+		tdeSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tde", Namespace: "default"},
+			Data:       map[string][]byte{"passphrase": []byte("old")},
+		}
+		r = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster, tdeSecret).
+				Build(),
+		}
+	})
+
+	statuses := func(version1, version2 string) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				TDESecretVersion: version1,
+			},
+			{
+				Pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				TDESecretVersion: version2,
+			},
+		}}
+	}
+
+	passphrases := func(ctx SpecContext) []string {
+		var secret corev1.Secret
+		Expect(r.Get(ctx, client.ObjectKey{
+			Namespace: "default",
+			Name:      cluster.GetTDEPassphrasesSecretName(),
+		}, &secret)).To(Succeed())
+
+		result := make([]string, 0, len(secret.Data))
+		for _, value := range secret.Data {
+			result = append(result, string(value))
+		}
+		return result
+	}
+
+	rotate := func(ctx SpecContext, passphrase string) string {
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(tdeSecret), tdeSecret)).To(Succeed())
+		tdeSecret.Data["passphrase"] = []byte(passphrase)
+		Expect(r.Update(ctx, tdeSecret)).To(Succeed())
+		return tdeSecret.ResourceVersion
+	}
+
+	It("keeps the previous passphrases until every instance has wrapped its key again", func(ctx SpecContext) {
+		Expect(r.reconcileTDEPassphrases(ctx, cluster, statuses("", ""))).To(Succeed())
+		Expect(passphrases(ctx)).To(ConsistOf("old"))
+
+		version := rotate(ctx, "new")
+		Expect(r.reconcileTDEPassphrases(ctx, cluster, statuses(version, ""))).To(Succeed())
+		Expect(passphrases(ctx)).To(ConsistOf("old", "new"))
+
+		newerVersion := rotate(ctx, "newer")
+		Expect(r.reconcileTDEPassphrases(ctx, cluster, statuses(version, version))).To(Succeed())
+		Expect(passphrases(ctx)).To(ConsistOf("old", "new", "newer"))
+
+		Expect(r.reconcileTDEPassphrases(ctx, cluster, statuses(newerVersion, newerVersion))).To(Succeed())
+		Expect(passphrases(ctx)).To(ConsistOf("newer"))
+	})
+
+	It("waits for the instances not reporting their status", func(ctx SpecContext) {
+		Expect(r.reconcileTDEPassphrases(ctx, cluster, statuses("", ""))).To(Succeed())
+		version := rotate(ctx, "new")
+
+		partial := statuses(version, version)
+		partial.Items = partial.Items[:1]
+		Expect(r.reconcileTDEPassphrases(ctx, cluster, partial)).To(Succeed())
+		Expect(passphrases(ctx)).To(ConsistOf("old", "new"))
+	})
+})
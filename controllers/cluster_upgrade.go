@@ -25,6 +25,7 @@ import (
 	"net/http"
 	neturl "net/url"
 	"reflect"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -32,6 +33,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -45,6 +47,10 @@ import (
 // instance is not connected via streaming replication
 var errLogShippingReplicaElected = errors.New("log shipping replica elected as a new post-switchover primary")
 
+// errRolloutDeferred is raised when the rollout of an instance has been
+// deferred to the next maintenance window of the cluster
+var errRolloutDeferred = errors.New("rollout deferred to the next maintenance window")
+
 type rolloutReason = string
 
 func (r *ClusterReconciler) rolloutRequiredInstances(
@@ -55,6 +61,12 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 	// The following code works under the assumption that podList.Items list is ordered
 	// by lag (primary first)
 
+	// An outdated replica is being replaced by a new one: wait for the
+	// replacement to complete before upgrading any other instance
+	if cluster.Status.ReplicaSurge != nil {
+		return r.reconcileReplicaSurge(ctx, cluster, podList)
+	}
+
 	// upgrade all the replicas starting from the more lagged
 	var primaryPostgresqlStatus *postgres.PostgresqlStatus
 	for i := len(podList.Items) - 1; i >= 0; i-- {
@@ -75,6 +87,14 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 			continue
 		}
 
+		if err := r.deferRollout(ctx, cluster, postgresqlStatus.Pod.Name, podRollout); err != nil {
+			return false, err
+		}
+
+		if cluster.GetReplicaUpdateMethod() == apiv1.ReplicaUpdateMethodSurge {
+			return true, r.startReplicaSurge(ctx, cluster, postgresqlStatus.Pod.Name, podRollout.reason)
+		}
+
 		restartMessage := fmt.Sprintf("Restarting instance %s, because: %s",
 			postgresqlStatus.Pod.Name, podRollout.reason)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade, restartMessage); err != nil {
@@ -108,10 +128,44 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		return false, nil
 	}
 
+	if err := r.deferRollout(ctx, cluster, primaryPostgresqlStatus.Pod.Name, podRollout); err != nil {
+		return false, err
+	}
+
 	return r.updatePrimaryPod(ctx, cluster, podList, *primaryPostgresqlStatus.Pod,
 		podRollout.canBeInPlace, podRollout.primaryForceRecreate, podRollout.reason)
 }
 
+// deferRollout checks whether the rollout of an instance, not being urgent,
+// has to wait for the next maintenance window of the cluster. When this
+// happens, the cluster phase reports it and errRolloutDeferred is returned
+func (r *ClusterReconciler) deferRollout(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podName string,
+	podRollout rollout,
+) error {
+	if podRollout.urgent {
+		return nil
+	}
+
+	inside, nextWindow, err := cluster.GetMaintenanceWindowStatus(time.Now())
+	if err != nil || inside {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Deferring the rollout of the instance to the next maintenance window",
+		"pod", podName,
+		"reason", podRollout.reason,
+		"nextWindow", nextWindow)
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForMaintenanceWindow,
+		fmt.Sprintf("Restart of instance %s deferred to %s, because: %s",
+			podName, nextWindow.Format(time.RFC3339), podRollout.reason)); err != nil {
+		return err
+	}
+	return errRolloutDeferred
+}
+
 func (r *ClusterReconciler) updatePrimaryPod(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -194,7 +248,7 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			"currentPrimary", primaryPod.Name,
 			"targetPrimary", targetInstance.Pod.Name,
 			"podList", podList)
-		r.Recorder.Eventf(cluster, "Normal", "Switchover",
+		r.Recorder.Eventf(cluster, "Normal", events.Switchover,
 			"Initiating switchover to %s to upgrade %s", targetInstance.Pod.Name, primaryPod.Name)
 		return true, r.setPrimaryInstance(ctx, cluster, targetInstance.Pod.Name)
 	}
@@ -240,6 +294,8 @@ type rollout struct {
 	canBeInPlace         bool
 	primaryForceRecreate bool
 	reason               string
+	// urgent rollouts are not deferred to the next maintenance window
+	urgent bool
 }
 
 type rolloutChecker func(
@@ -294,6 +350,8 @@ func isPodNeedingRollout(
 		"pod image is outdated":                checkPodImageIsOutdated,
 		"postgres restart required":            checkPostgresPendingRestart,
 		"cluster has newer restart annotation": checkClusterHasNewerRestartAnnotation,
+		"pod subdomain is outdated":            checkPodSubdomainIsOutdated,
+		"pod AppArmor profile is outdated":     checkPodAppArmorProfileIsOutdated,
 	}
 
 	podRollout := applyCheckers(checkers)
@@ -385,6 +443,44 @@ func checkPodNeedsUpdatedTopology(
 	}, nil
 }
 
+// checkPodSubdomainIsOutdated detects the pods that aren't using the `-any`
+// service as their subdomain when the cluster publishes its multi-host
+// connection strings, since their DNS name can't be resolved
+func checkPodSubdomainIsOutdated(
+	status postgres.PostgresqlStatus,
+	cluster *apiv1.Cluster,
+) (rollout, error) {
+	if !cluster.IsMultiHostConnectionEnabled() || status.Pod.Spec.Subdomain == cluster.GetServiceAnyName() {
+		return rollout{}, nil
+	}
+
+	return rollout{
+		required: true,
+		reason: fmt.Sprintf(
+			"pod '%s' needs the '%s' subdomain to be reachable through the multi-host connection strings",
+			status.Pod.Name,
+			cluster.GetServiceAnyName(),
+		),
+	}, nil
+}
+
+// checkPodAppArmorProfileIsOutdated detects the pods whose AppArmor
+// annotations don't match the cluster. These annotations are immutable,
+// so the pod needs to be recreated
+func checkPodAppArmorProfileIsOutdated(
+	status postgres.PostgresqlStatus,
+	cluster *apiv1.Cluster,
+) (rollout, error) {
+	if !specs.IsPodAppArmorProfileOutdated(cluster, status.Pod) {
+		return rollout{}, nil
+	}
+
+	return rollout{
+		required: true,
+		reason:   fmt.Sprintf("pod '%s' AppArmor profile is outdated", status.Pod.Name),
+	}, nil
+}
+
 func checkSchedulerIsOutdated(
 	status postgres.PostgresqlStatus,
 	cluster *apiv1.Cluster,
@@ -505,6 +601,7 @@ func checkHasMissingPVCs(
 			required:             true,
 			primaryForceRecreate: true,
 			reason:               "attaching a new PVC to the instance Pod",
+			urgent:               true,
 		}, nil
 	}
 	return rollout{}, nil
@@ -526,6 +623,7 @@ func checkClusterHasNewerRestartAnnotation(
 				required:     true,
 				reason:       "cluster has been explicitly restarted via annotation",
 				canBeInPlace: true,
+				urgent:       true,
 			}, nil
 		}
 	}
@@ -658,7 +756,7 @@ func (r *ClusterReconciler) upgradePod(
 		"reason", reason,
 	)
 
-	r.Recorder.Eventf(cluster, "Normal", "UpgradingInstance",
+	r.Recorder.Eventf(cluster, "Normal", events.UpgradingInstance,
 		"Upgrading instance %v", pod.Name)
 
 	// let's wait for this Pod to be recloned or recreated, using the same storage
@@ -749,7 +847,7 @@ func (r *ClusterReconciler) upgradeInstanceManager(
 					operatorHash[:6],
 					err)
 
-				r.Recorder.Event(cluster, "Warning", "InstanceManagerUpgradeFailed",
+				r.Recorder.Event(cluster, "Warning", events.InstanceManagerUpgradeFailed,
 					fmt.Sprintf("Error %s", enrichedError))
 				return enrichedError
 			}
@@ -759,7 +857,7 @@ func (r *ClusterReconciler) upgradeInstanceManager(
 				operatorHash[:6],
 				instanceManagerHash[:6])
 
-			r.Recorder.Event(cluster, "Normal", "InstanceManagerUpgraded", message)
+			r.Recorder.Event(cluster, "Normal", events.InstanceManagerUpgraded, message)
 			contextLogger.Info(message)
 		}
 	}
@@ -18,10 +18,12 @@ package controllers
 
 import (
 	"encoding/json"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -629,6 +631,87 @@ var _ = Describe("Test pod rollout due to topology", func() {
 	})
 })
 
+var _ = Describe("Test pod rollout due to the multi-host connection", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Services: &apiv1.ManagedServices{},
+				},
+			},
+		}
+	})
+
+	It("requires a rollout of the pods without the any subdomain", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		cluster.Spec.Managed.Services.MultiHostConnection = true
+
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+		rollout := isPodNeedingRollout(ctx, status, cluster)
+		Expect(rollout.required).To(BeTrue())
+		Expect(rollout.canBeInPlace).To(BeFalse())
+		Expect(rollout.reason).To(ContainSubstring("cluster-example-any"))
+	})
+
+	It("doesn't require a rollout of the pods created with the any subdomain", func(ctx SpecContext) {
+		cluster.Spec.Managed.Services.MultiHostConnection = true
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+		rollout := isPodNeedingRollout(ctx, status, cluster)
+		Expect(rollout.required).To(BeFalse())
+	})
+})
+
+var _ = Describe("Test pod rollout due to the AppArmor profile", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		}
+	})
+
+	It("requires a rollout of the pods created with a different profile", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		cluster.Spec.AppArmorProfile = &apiv1.AppArmorProfile{Type: apiv1.AppArmorProfileTypeRuntimeDefault}
+
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+		rollout := isPodNeedingRollout(ctx, status, cluster)
+		Expect(rollout.required).To(BeTrue())
+		Expect(rollout.canBeInPlace).To(BeFalse())
+		Expect(rollout.reason).To(ContainSubstring("AppArmor"))
+	})
+
+	It("doesn't require a rollout of the pods created with the same profile", func(ctx SpecContext) {
+		cluster.Spec.AppArmorProfile = &apiv1.AppArmorProfile{Type: apiv1.AppArmorProfileTypeRuntimeDefault}
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+		rollout := isPodNeedingRollout(ctx, status, cluster)
+		Expect(rollout.required).To(BeFalse())
+	})
+})
+
 var _ = Describe("hasValidPodSpec", func() {
 	var status postgres.PostgresqlStatus
 
@@ -666,3 +749,55 @@ var _ = Describe("hasValidPodSpec", func() {
 		})
 	})
 })
+
+var _ = Describe("Maintenance windows", func() {
+	var env *testingEnvironment
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			// Once a year, for a second
+			cluster.Spec.MaintenanceWindows = []apiv1.MaintenanceWindow{
+				{Schedule: "0 0 0 1 1 *", Duration: metav1.Duration{Duration: time.Second}},
+			}
+		})
+	})
+
+	It("defers the rollouts outside the maintenance windows", func(ctx SpecContext) {
+		err := env.clusterReconciler.deferRollout(ctx, cluster, "test-1", rollout{
+			required: true,
+			reason:   "pod image is outdated",
+		})
+		Expect(err).To(MatchError(errRolloutDeferred))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseWaitingForMaintenanceWindow))
+		Expect(cluster.Status.PhaseReason).To(ContainSubstring("pod image is outdated"))
+	})
+
+	It("doesn't defer the urgent rollouts", func(ctx SpecContext) {
+		err := env.clusterReconciler.deferRollout(ctx, cluster, "test-1", rollout{
+			required: true,
+			urgent:   true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("doesn't defer the rollouts inside the maintenance windows", func(ctx SpecContext) {
+		cluster.Spec.MaintenanceWindows = nil
+		err := env.clusterReconciler.deferRollout(ctx, cluster, "test-1", rollout{required: true})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("requeues the cluster when the next maintenance window begins", func() {
+		result := requeueOnMaintenanceWindowChange(cluster, ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeNumerically(">", time.Second))
+
+		result = requeueOnMaintenanceWindowChange(cluster, ctrl.Result{RequeueAfter: time.Second})
+		Expect(result.RequeueAfter).To(Equal(time.Second))
+
+		cluster.Spec.MaintenanceWindows = nil
+		result = requeueOnMaintenanceWindowChange(cluster, ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+})
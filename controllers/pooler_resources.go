@@ -20,6 +20,7 @@ import (
 	"context"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -38,6 +39,9 @@ type poolerManagedResources struct {
 	// This is the pgbouncer deployment
 	Deployment *appsv1.Deployment
 
+	// This is the autoscaler of the pgbouncer deployment
+	HorizontalPodAutoscaler *autoscalingv2.HorizontalPodAutoscaler
+
 	// This is the service where pgbouncer is accessible
 	Service *corev1.Service
 
@@ -72,6 +76,13 @@ func (r *PoolerReconciler) getManagedResources(ctx context.Context,
 		return nil, err
 	}
 
+	// Get the pooler autoscaler
+	result.HorizontalPodAutoscaler, err = getHorizontalPodAutoscalerOrNil(
+		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the service deployment
 	result.Service, err = getServiceOrNil(
 		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
@@ -124,6 +135,23 @@ func getDeploymentOrNil(
 	return &deployment, nil
 }
 
+// getHorizontalPodAutoscalerOrNil gets an autoscaler with a certain name, returning nil when it doesn't exist
+func getHorizontalPodAutoscalerOrNil(
+	ctx context.Context, r client.Client, objectKey client.ObjectKey,
+) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	var hpa autoscalingv2.HorizontalPodAutoscaler
+	err := r.Get(ctx, objectKey, &hpa)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &hpa, nil
+}
+
 // getServiceOrNil gets a service with a certain name, returning nil when it doesn't exist
 func getServiceOrNil(ctx context.Context, r client.Client, objectKey client.ObjectKey) (*corev1.Service, error) {
 	var service corev1.Service
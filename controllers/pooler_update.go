@@ -44,6 +44,10 @@ func (r *PoolerReconciler) updateOwnedObjects(
 		return err
 	}
 
+	if err := r.reconcileHorizontalPodAutoscaler(ctx, pooler, resources); err != nil {
+		return err
+	}
+
 	if err := r.updateServiceAccount(ctx, pooler, resources); err != nil {
 		return err
 	}
@@ -100,6 +104,10 @@ func (r *PoolerReconciler) updateDeployment(
 
 		deployment := resources.Deployment.DeepCopy()
 		deployment.Spec = generatedDeployment.Spec
+		if pooler.Spec.Autoscaling != nil {
+			// The number of replicas is managed by the autoscaler
+			deployment.Spec.Replicas = resources.Deployment.Spec.Replicas
+		}
 
 		utils.MergeObjectsMetadata(deployment, generatedDeployment)
 
@@ -115,6 +123,60 @@ func (r *PoolerReconciler) updateDeployment(
 	return nil
 }
 
+// reconcileHorizontalPodAutoscaler creates, updates or deletes the autoscaler
+// of the pgbouncer deployment, depending on the pooler configuration
+func (r *PoolerReconciler) reconcileHorizontalPodAutoscaler(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	resources *poolerManagedResources,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	expectedHPA := pgbouncer.HorizontalPodAutoscaler(pooler)
+	if expectedHPA == nil {
+		if resources.HorizontalPodAutoscaler == nil ||
+			!metav1.IsControlledBy(resources.HorizontalPodAutoscaler, pooler) {
+			return nil
+		}
+
+		contextLog.Info("Deleting the autoscaler")
+		if err := r.Delete(ctx, resources.HorizontalPodAutoscaler); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		resources.HorizontalPodAutoscaler = nil
+		return nil
+	}
+
+	if resources.HorizontalPodAutoscaler == nil {
+		if err := ctrl.SetControllerReference(pooler, expectedHPA, r.Scheme); err != nil {
+			return err
+		}
+
+		contextLog.Info("Creating the autoscaler")
+		if err := r.Create(ctx, expectedHPA); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		resources.HorizontalPodAutoscaler = expectedHPA
+		return nil
+	}
+
+	patchedHPA := resources.HorizontalPodAutoscaler.DeepCopy()
+	patchedHPA.Spec = expectedHPA.Spec
+	utils.MergeObjectsMetadata(patchedHPA, expectedHPA)
+
+	if reflect.DeepEqual(patchedHPA.ObjectMeta, resources.HorizontalPodAutoscaler.ObjectMeta) &&
+		reflect.DeepEqual(patchedHPA.Spec, resources.HorizontalPodAutoscaler.Spec) {
+		return nil
+	}
+
+	contextLog.Info("Updating the autoscaler")
+	if err := r.Patch(ctx, patchedHPA, client.MergeFrom(resources.HorizontalPodAutoscaler)); err != nil {
+		return err
+	}
+	resources.HorizontalPodAutoscaler = patchedHPA
+	return nil
+}
+
 // reconcileService update or create the pgbouncer service as needed
 func (r *PoolerReconciler) reconcileService(
 	ctx context.Context,
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -289,6 +290,84 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 			Expect(expectedSVC.Labels[utils.ClusterLabelName]).To(Equal(cluster.Name))
 		})
 	})
+
+	It("should reconcile the autoscaler of the pooler", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler := newFakePooler(env.client, cluster)
+		res := &poolerManagedResources{Cluster: cluster}
+
+		getHPA := func() (*autoscalingv2.HorizontalPodAutoscaler, error) {
+			var hpa autoscalingv2.HorizontalPodAutoscaler
+			err := env.client.Get(ctx, types.NamespacedName{Name: pooler.Name, Namespace: pooler.Namespace}, &hpa)
+			return &hpa, err
+		}
+
+		By("making sure no autoscaler is created without an autoscaling configuration", func() {
+			err := env.poolerReconciler.reconcileHorizontalPodAutoscaler(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.HorizontalPodAutoscaler).To(BeNil())
+
+			_, err = getHPA()
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		By("making sure the autoscaler is created", func() {
+			pooler.Spec.Autoscaling = &apiv1.PoolerAutoscaling{
+				MinInstances:            ptr.To(int32(2)),
+				MaxInstances:            4,
+				TargetClientConnections: ptr.To(int32(100)),
+			}
+			err := env.poolerReconciler.reconcileHorizontalPodAutoscaler(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+
+			hpa, err := getHPA()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hpa.Spec.ScaleTargetRef.Name).To(Equal(pooler.Name))
+			Expect(*hpa.Spec.MinReplicas).To(BeEquivalentTo(2))
+			Expect(hpa.Spec.MaxReplicas).To(BeEquivalentTo(4))
+			Expect(metav1.IsControlledBy(hpa, pooler)).To(BeTrue())
+		})
+
+		By("making sure the autoscaler is updated", func() {
+			pooler.Spec.Autoscaling.MaxInstances = 6
+			err := env.poolerReconciler.reconcileHorizontalPodAutoscaler(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+
+			hpa, err := getHPA()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hpa.Spec.MaxReplicas).To(BeEquivalentTo(6))
+		})
+
+		By("making sure the replicas chosen by the autoscaler are preserved", func() {
+			err := env.poolerReconciler.updateDeployment(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+
+			deployment := getPoolerDeployment(ctx, env.client, pooler)
+			origDeployment := deployment.DeepCopy()
+			deployment.Spec.Replicas = ptr.To(int32(5))
+			Expect(env.client.Patch(ctx, deployment, k8client.MergeFrom(origDeployment))).To(Succeed())
+			res.Deployment = deployment
+
+			pooler.Spec.Autoscaling.TargetCPUUtilizationPercentage = ptr.To(int32(80))
+			err = env.poolerReconciler.updateDeployment(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+
+			deployment = getPoolerDeployment(ctx, env.client, pooler)
+			Expect(*deployment.Spec.Replicas).To(BeEquivalentTo(5))
+		})
+
+		By("making sure the autoscaler is removed with the autoscaling configuration", func() {
+			pooler.Spec.Autoscaling = nil
+			err := env.poolerReconciler.reconcileHorizontalPodAutoscaler(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.HorizontalPodAutoscaler).To(BeNil())
+
+			_, err = getHPA()
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
 })
 
 var _ = Describe("ensureServiceAccountPullSecret", func() {
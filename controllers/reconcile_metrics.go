@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileFailures counts the failed reconciliations of the operator
+// controllers, complementing the reconciliation metrics exported by
+// controller-runtime with the reason of the failure
+var reconcileFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cnpg_operator_reconcile_failures_total",
+		Help: "The number of failed reconciliations, by controller and by reason of the failure",
+	},
+	[]string{"controller", "reason"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileFailures)
+}
+
+// instrumentedReconciler counts the failures of the wrapped reconciler
+type instrumentedReconciler struct {
+	controller string
+	reconciler reconcile.Reconciler
+}
+
+// withReconcileMetrics wraps a reconciler, counting its failures in the
+// operator metrics. The controller name should match the one assigned
+// by controller-runtime, which is the lowercase kind of the reconciled
+// object
+func withReconcileMetrics(controller string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return instrumentedReconciler{
+		controller: controller,
+		reconciler: reconciler,
+	}
+}
+
+// Reconcile implements the reconcile.Reconciler interface
+func (r instrumentedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result, err := r.reconciler.Reconcile(ctx, req)
+	if err != nil {
+		reconcileFailures.WithLabelValues(r.controller, getReconcileFailureReason(err)).Inc()
+	}
+	return result, err
+}
+
+// getReconcileFailureReason classifies the error of a failed reconciliation,
+// using the reason of the Kubernetes API errors
+func getReconcileFailureReason(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return string(metav1.StatusReasonTimeout)
+	case errors.Is(err, context.Canceled):
+		return "Canceled"
+	}
+
+	if reason := apierrs.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+
+	return "Unknown"
+}
@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("reconcile metrics", func() {
+	resource := schema.GroupResource{Group: "postgresql.cnpg.io", Resource: "clusters"}
+
+	DescribeTable("classifies the reconciliation failures",
+		func(err error, reason string) {
+			Expect(getReconcileFailureReason(err)).To(Equal(reason))
+		},
+		Entry("conflict", apierrs.NewConflict(resource, "test", errors.New("changed")), "Conflict"),
+		Entry("wrapped not found", fmt.Errorf("while reconciling: %w", apierrs.NewNotFound(resource, "test")),
+			"NotFound"),
+		Entry("timeout", fmt.Errorf("while waiting: %w", context.DeadlineExceeded), "Timeout"),
+		Entry("canceled", context.Canceled, "Canceled"),
+		Entry("any other error", errors.New("boom"), "Unknown"),
+	)
+
+	It("counts the failures of the wrapped reconciler", func() {
+		failures := func() float64 {
+			return testutil.ToFloat64(reconcileFailures.WithLabelValues("test", "Conflict"))
+		}
+		before := failures()
+
+		var result error
+		reconciler := withReconcileMetrics("test", reconcile.Func(
+			func(_ context.Context, _ ctrl.Request) (ctrl.Result, error) {
+				return ctrl.Result{}, result
+			}))
+
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failures()).To(Equal(before))
+
+		result = apierrs.NewConflict(resource, "test", errors.New("changed"))
+		_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{})
+		Expect(err).To(HaveOccurred())
+		Expect(failures()).To(Equal(before + 1))
+	})
+})
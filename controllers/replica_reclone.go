@@ -0,0 +1,294 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// damagedReplica is a replica whose data can't be trusted anymore
+type damagedReplica struct {
+	status postgres.PostgresqlStatus
+	reason string
+}
+
+// getDamagedReplicas gets the replicas reporting data checksum failures, the
+// ones quarantined by the startup consistency checks, or whose PostgreSQL
+// container keeps failing, as happens when the replay of the WAL files hits
+// a corrupted or diverged data directory. A replica that stopped restarting
+// PostgreSQL after repeated crashes is failing too
+func getDamagedReplicas(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) []damagedReplica {
+	config := cluster.Spec.ReplicaAutoReclone
+
+	var result []damagedReplica
+	for _, item := range statuses.Items {
+		if item.Pod == nil || item.IsPrimary ||
+			item.Pod.Name == cluster.Status.CurrentPrimary ||
+			item.Pod.Name == cluster.Status.TargetPrimary {
+			continue
+		}
+
+		if item.StartupCheck != nil && item.StartupCheck.Quarantined {
+			result = append(result, damagedReplica{
+				status: item,
+				reason: fmt.Sprintf("quarantined by the startup checks: %s", item.StartupCheck.Message),
+			})
+			continue
+		}
+
+		if restart := item.PostmasterRestart; restart != nil && restart.Paused &&
+			restart.LastExitReason == string(apiv1.PostmasterExitCrashed) {
+			result = append(result, damagedReplica{
+				status: item,
+				reason: fmt.Sprintf("%d consecutive crashes of PostgreSQL", restart.Attempts),
+			})
+			continue
+		}
+
+		if item.Error == nil && item.ChecksumFailures >= config.GetMaxChecksumFailures() {
+			result = append(result, damagedReplica{
+				status: item,
+				reason: fmt.Sprintf("%d data checksum failures", item.ChecksumFailures),
+			})
+			continue
+		}
+
+		if restarts := getPostgresRestartsWhileFailing(item.Pod); restarts >= config.GetMaxRestarts() {
+			result = append(result, damagedReplica{
+				status: item,
+				reason: fmt.Sprintf("%d restarts of the PostgreSQL container", restarts),
+			})
+		}
+	}
+
+	return result
+}
+
+// getPostgresRestartsWhileFailing gets the number of restarts of the
+// PostgreSQL container of a pod which is not ready, since the pod was
+// last seen ready. Restarts caused by the container running out of
+// memory are not a sign of damaged data, and are not counted
+func getPostgresRestartsWhileFailing(pod *corev1.Pod) int32 {
+	if utils.IsPodReady(*pod) {
+		return 0
+	}
+
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name != specs.PostgresContainerName {
+			continue
+		}
+		if terminated := containerStatus.LastTerminationState.Terminated; terminated != nil &&
+			terminated.Reason == "OOMKilled" {
+			return 0
+		}
+
+		baseline, err := strconv.ParseInt(pod.Annotations[utils.PostgresRestartsBaselineAnnotationName], 10, 32)
+		if err != nil || baseline < 0 || int32(baseline) > containerStatus.RestartCount {
+			baseline = 0
+		}
+		return containerStatus.RestartCount - int32(baseline)
+	}
+
+	return 0
+}
+
+// getPostgresRestartCount gets the number of restarts of the PostgreSQL
+// container of a pod, if the container has a status
+func getPostgresRestartCount(pod *corev1.Pod) (int32, bool) {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == specs.PostgresContainerName {
+			return containerStatus.RestartCount, true
+		}
+	}
+	return 0, false
+}
+
+// updatePostgresRestartsBaseline records, in the ready pods, the number of
+// restarts of their PostgreSQL container. As the restarts are counted from
+// there, the ones of a replica which recovered are not counted anymore
+// when it fails again
+func (r *ClusterReconciler) updatePostgresRestartsBaseline(
+	ctx context.Context,
+	statuses postgres.PostgresqlStatusList,
+) error {
+	for _, item := range statuses.Items {
+		if item.Pod == nil || !utils.IsPodReady(*item.Pod) {
+			continue
+		}
+
+		restartCount, ok := getPostgresRestartCount(item.Pod)
+		if !ok {
+			continue
+		}
+		baseline := strconv.FormatInt(int64(restartCount), 10)
+		if item.Pod.Annotations[utils.PostgresRestartsBaselineAnnotationName] == baseline {
+			continue
+		}
+
+		pod := item.Pod.DeepCopy()
+		origPod := pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[utils.PostgresRestartsBaselineAnnotationName] = baseline
+		if err := r.Patch(ctx, pod, client.MergeFrom(origPod)); err != nil {
+			return fmt.Errorf("while recording the restarts of pod %s: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// setReplicaIntegrityCondition sets the ReplicaIntegrity condition when the
+// damaged replicas are automatically re-created, and removes it otherwise
+func setReplicaIntegrityCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	if !cluster.Spec.ReplicaAutoReclone.IsEnabled() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionReplicaIntegrity))
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               string(apiv1.ConditionReplicaIntegrity),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(apiv1.ConditionReasonReplicasIntact),
+		Message:            "No replica is damaged",
+		ObservedGeneration: cluster.Generation,
+	}
+
+	if damaged := getDamagedReplicas(cluster, statuses); len(damaged) > 0 {
+		descriptions := make([]string, 0, len(damaged))
+		for _, replica := range damaged {
+			descriptions = append(descriptions,
+				fmt.Sprintf("%s (%s)", replica.status.Pod.Name, replica.reason))
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonReplicaDamaged)
+		condition.Message = fmt.Sprintf("Damaged replicas: %s", strings.Join(descriptions, ", "))
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// recloneDamagedReplica deletes the first damaged replica together with its
+// PVCs, letting the cluster create a new replica cloning the primary. At most
+// one replica is re-created in the configured interval, and only while the
+// primary is healthy and not changing
+func (r *ClusterReconciler) recloneDamagedReplica(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	config := cluster.Spec.ReplicaAutoReclone
+	if !config.IsEnabled() {
+		return nil, nil
+	}
+
+	if err := r.updatePostgresRestartsBaseline(ctx, statuses); err != nil {
+		return nil, err
+	}
+
+	damaged := getDamagedReplicas(cluster, statuses)
+	if len(damaged) == 0 {
+		return nil, nil
+	}
+
+	if cluster.Status.CurrentPrimary == "" || cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		contextLogger.Info("Waiting for the primary to be stable before re-creating a damaged replica")
+		return nil, nil
+	}
+	if !isInstanceReporting(statuses, cluster.Status.CurrentPrimary) {
+		contextLogger.Info("Waiting for the primary to be reachable before re-creating a damaged replica")
+		return nil, nil
+	}
+
+	if last := cluster.Status.LastReplicaReclone; last != nil {
+		lastTime, err := time.Parse(time.RFC3339, last.Time)
+		if err == nil && time.Since(lastTime) < config.GetMinimumInterval() {
+			contextLogger.Info("Delaying the re-creation of a damaged replica",
+				"lastReplicaReclone", last)
+			return nil, nil
+		}
+	}
+
+	replica := damaged[0]
+	pod := replica.status.Pod
+
+	// We record the re-creation first, so that the rate limit
+	// is enforced even if one of the following steps fails
+	origCluster := cluster.DeepCopy()
+	cluster.Status.LastReplicaReclone = &apiv1.ReplicaRecloneStatus{
+		InstanceName: pod.Name,
+		Time:         time.Now().Format(time.RFC3339),
+		Reason:       replica.reason,
+	}
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return nil, err
+	}
+
+	contextLogger.Warning("Re-creating a damaged replica",
+		"instance", pod.Name,
+		"reason", replica.reason)
+	r.Recorder.Eventf(cluster, "Warning", events.RecloneReplica,
+		"Re-creating replica %s from the primary: %s", pod.Name, replica.reason)
+
+	if err := r.Delete(ctx, pod); err != nil {
+		return nil, err
+	}
+	if err := persistentvolumeclaim.EnsureInstancePVCGroupIsDeleted(
+		ctx,
+		r.Client,
+		cluster,
+		pod.Name,
+		pod.Namespace,
+	); err != nil {
+		return nil, err
+	}
+
+	// Let's wait for the informer cache to notice the deletion
+	return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+}
+
+// isInstanceReporting checks whether the given instance returned its status
+func isInstanceReporting(statuses postgres.PostgresqlStatusList, name string) bool {
+	for _, item := range statuses.Items {
+		if item.Pod != nil && item.Pod.Name == name {
+			return item.Error == nil
+		}
+	}
+
+	return false
+}
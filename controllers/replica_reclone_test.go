@@ -0,0 +1,276 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("automatic re-creation of damaged replicas", func() {
+	var (
+		cluster  *apiv1.Cluster
+		pods     []*corev1.Pod
+		statuses postgres.PostgresqlStatusList
+	)
+
+	newPod := func(name string, ready bool) *corev1.Pod {
+		condition := corev1.ConditionTrue
+		if !ready {
+			condition = corev1.ConditionFalse
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: condition}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ReplicaAutoReclone: &apiv1.ReplicaAutoRecloneConfiguration{
+					Enabled:         true,
+					MinimumInterval: 3600,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		pods = []*corev1.Pod{
+			newPod("cluster-example-1", true),
+			newPod("cluster-example-2", true),
+			newPod("cluster-example-3", true),
+		}
+		statuses = postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: pods[0], IsPrimary: true},
+			{Pod: pods[1]},
+			{Pod: pods[2]},
+		}}
+	})
+
+	Context("detection", func() {
+		It("reports the replicas with checksum failures", func() {
+			statuses.Items[0].ChecksumFailures = 10
+			statuses.Items[2].ChecksumFailures = 2
+
+			damaged := getDamagedReplicas(cluster, statuses)
+			Expect(damaged).To(HaveLen(1))
+			Expect(damaged[0].status.Pod.Name).To(Equal("cluster-example-3"))
+			Expect(damaged[0].reason).To(Equal("2 data checksum failures"))
+		})
+
+		It("reports the replicas quarantined by the startup checks", func() {
+			statuses.Items[1].StartupCheck = &postgres.StartupCheckResult{
+				Message:     "3 bad data checksums",
+				Quarantined: true,
+			}
+			statuses.Items[2].StartupCheck = &postgres.StartupCheckResult{Passed: true}
+
+			damaged := getDamagedReplicas(cluster, statuses)
+			Expect(damaged).To(HaveLen(1))
+			Expect(damaged[0].status.Pod.Name).To(Equal("cluster-example-2"))
+			Expect(damaged[0].reason).To(Equal("quarantined by the startup checks: 3 bad data checksums"))
+		})
+
+		It("reports the replicas that stopped restarting PostgreSQL after repeated crashes", func() {
+			statuses.Items[1].PostmasterRestart = &postgres.PostmasterRestartResult{
+				Attempts:       5,
+				LastExitReason: string(apiv1.PostmasterExitCrashed),
+				Paused:         true,
+			}
+			statuses.Items[2].PostmasterRestart = &postgres.PostmasterRestartResult{
+				Attempts:       5,
+				LastExitReason: string(apiv1.PostmasterExitOOMKilled),
+				Paused:         true,
+			}
+
+			damaged := getDamagedReplicas(cluster, statuses)
+			Expect(damaged).To(HaveLen(1))
+			Expect(damaged[0].status.Pod.Name).To(Equal("cluster-example-2"))
+			Expect(damaged[0].reason).To(Equal("5 consecutive crashes of PostgreSQL"))
+		})
+
+		It("reports the replicas whose PostgreSQL container keeps failing", func() {
+			statuses.Items[1].Pod = newPod("cluster-example-2", false)
+			statuses.Items[1].Pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{Name: specs.PostgresContainerName, RestartCount: 5},
+			}
+			statuses.Items[1].Error = errors.New("connection refused")
+
+			damaged := getDamagedReplicas(cluster, statuses)
+			Expect(damaged).To(HaveLen(1))
+			Expect(damaged[0].reason).To(Equal("5 restarts of the PostgreSQL container"))
+		})
+
+		It("counts only the restarts since the replica was last seen ready", func() {
+			statuses.Items[1].Pod = newPod("cluster-example-2", false)
+			statuses.Items[1].Pod.Annotations = map[string]string{
+				utils.PostgresRestartsBaselineAnnotationName: "20",
+			}
+			statuses.Items[1].Pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{Name: specs.PostgresContainerName, RestartCount: 22},
+			}
+
+			Expect(getDamagedReplicas(cluster, statuses)).To(BeEmpty())
+
+			statuses.Items[1].Pod.Status.ContainerStatuses[0].RestartCount = 25
+			damaged := getDamagedReplicas(cluster, statuses)
+			Expect(damaged).To(HaveLen(1))
+			Expect(damaged[0].reason).To(Equal("5 restarts of the PostgreSQL container"))
+		})
+
+		It("ignores the restarts of ready replicas and the ones caused by the lack of memory", func() {
+			statuses.Items[1].Pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{Name: specs.PostgresContainerName, RestartCount: 10},
+			}
+			statuses.Items[2].Pod = newPod("cluster-example-3", false)
+			statuses.Items[2].Pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name:         specs.PostgresContainerName,
+					RestartCount: 10,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"},
+					},
+				},
+			}
+
+			Expect(getDamagedReplicas(cluster, statuses)).To(BeEmpty())
+		})
+
+		It("sets the ReplicaIntegrity condition only when enabled", func() {
+			statuses.Items[2].ChecksumFailures = 1
+			setReplicaIntegrityCondition(cluster, statuses)
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionReplicaIntegrity))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonReplicaDamaged)))
+			Expect(condition.Message).To(ContainSubstring("cluster-example-3 (1 data checksum failures)"))
+
+			cluster.Spec.ReplicaAutoReclone = nil
+			setReplicaIntegrityCondition(cluster, statuses)
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions,
+				string(apiv1.ConditionReplicaIntegrity))).To(BeNil())
+		})
+	})
+
+	Context("re-creation", func() {
+		var (
+			r   *ClusterReconciler
+			pvc *corev1.PersistentVolumeClaim
+		)
+
+		BeforeEach(func() {
+			pvc = &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3", Namespace: "default"},
+			}
+			r = &ClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+					WithObjects(cluster, pods[0], pods[1], pods[2], pvc).
+					WithStatusSubresource(&apiv1.Cluster{}).
+					Build(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			statuses.Items[2].ChecksumFailures = 1
+		})
+
+		expectDeleted := func(ctx SpecContext, obj client.Object) {
+			err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		}
+
+		It("deletes the damaged replica and its PVCs", func(ctx SpecContext) {
+			result, err := r.recloneDamagedReplica(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeNil())
+
+			expectDeleted(ctx, &corev1.Pod{ObjectMeta: pods[2].ObjectMeta})
+			expectDeleted(ctx, pvc)
+
+			var updatedCluster apiv1.Cluster
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.LastReplicaReclone).ToNot(BeNil())
+			Expect(updatedCluster.Status.LastReplicaReclone.InstanceName).To(Equal("cluster-example-3"))
+		})
+
+		It("records the restarts of the ready pods", func(ctx SpecContext) {
+			pods[1].Status.ContainerStatuses = []corev1.ContainerStatus{
+				{Name: specs.PostgresContainerName, RestartCount: 3},
+			}
+
+			_, err := r.recloneDamagedReplica(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+
+			var pod corev1.Pod
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pods[1]), &pod)).To(Succeed())
+			Expect(pod.Annotations).To(HaveKeyWithValue(utils.PostgresRestartsBaselineAnnotationName, "3"))
+		})
+
+		It("re-creates at most one replica in the configured interval", func(ctx SpecContext) {
+			cluster.Status.LastReplicaReclone = &apiv1.ReplicaRecloneStatus{
+				InstanceName: "cluster-example-2",
+				Time:         time.Now().Add(-10 * time.Minute).Format(time.RFC3339),
+			}
+
+			result, err := r.recloneDamagedReplica(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeNil())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})).To(Succeed())
+		})
+
+		It("waits for the primary to be reachable", func(ctx SpecContext) {
+			statuses.Items[0].Error = errors.New("connection refused")
+
+			result, err := r.recloneDamagedReplica(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeNil())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})).To(Succeed())
+		})
+
+		It("does nothing when disabled", func(ctx SpecContext) {
+			cluster.Spec.ReplicaAutoReclone.Enabled = false
+
+			result, err := r.recloneDamagedReplica(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeNil())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,216 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// replicationStateStreaming is the state reported by pg_stat_replication
+// for a standby that caught up with the primary
+const replicationStateStreaming = "streaming"
+
+// startReplicaSurge starts the replacement of an outdated replica with a
+// new one, which is created on top of the requested instances. The
+// outdated replica is removed by reconcileReplicaSurge once the new one
+// is streaming from the primary
+func (r *ClusterReconciler) startReplicaSurge(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	outdatedInstance string,
+	reason rolloutReason,
+) error {
+	nodeSerial, err := r.generateNodeSerial(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	newInstance := specs.GetInstanceName(cluster.Name, nodeSerial)
+
+	log.FromContext(ctx).Info("Replacing outdated replica with a new instance",
+		"outdatedInstance", outdatedInstance,
+		"newInstance", newInstance,
+		"reason", reason)
+	r.Recorder.Eventf(cluster, "Normal", events.ReplacingInstance,
+		"Creating instance %s to replace %s, because: %s", newInstance, outdatedInstance, reason)
+
+	if _, err := r.joinReplicaInstance(ctx, nodeSerial, cluster); err != nil && !errors.Is(err, ErrNextLoop) {
+		return err
+	}
+
+	// The surge is recorded only once the new instance exists, so that
+	// the replacement never waits for an instance which was never created.
+	// Until then, the new instance is an extra one for the scale down logic
+	cluster.Status.ReplicaSurge = &apiv1.ReplicaSurgeStatus{
+		OutdatedInstance: outdatedInstance,
+		NewInstance:      newInstance,
+		Reason:           reason,
+		StartedAt:        time.Now().UTC().Format(time.RFC3339),
+	}
+	return r.Status().Update(ctx, cluster)
+}
+
+// reconcileReplicaSurge removes the outdated replica being replaced as
+// soon as the new one caught up with the primary, returning true while
+// the replacement is in progress
+func (r *ClusterReconciler) reconcileReplicaSurge(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+	surge := cluster.Status.ReplicaSurge
+
+	switch {
+	case cluster.GetReplicaUpdateMethod() != apiv1.ReplicaUpdateMethodSurge:
+		// The user changed the update method in the middle of the
+		// replacement: the scale down logic will remove the extra instance
+		contextLogger.Info("Replica update method changed, abandoning the replacement of the outdated replica",
+			"outdatedInstance", surge.OutdatedInstance,
+			"newInstance", surge.NewInstance)
+		return true, r.clearReplicaSurge(ctx, cluster)
+
+	case cluster.Status.CurrentPrimary == surge.OutdatedInstance ||
+		cluster.Status.TargetPrimary == surge.OutdatedInstance:
+		// The outdated replica has been promoted in the meantime, and
+		// it will be upgraded as the primary
+		contextLogger.Info("The outdated replica has been promoted, abandoning its replacement",
+			"outdatedInstance", surge.OutdatedInstance,
+			"newInstance", surge.NewInstance)
+		return true, r.clearReplicaSurge(ctx, cluster)
+
+	case !slices.Contains(cluster.Status.InstanceNames, surge.OutdatedInstance):
+		// The outdated replica is already gone
+		return true, r.clearReplicaSurge(ctx, cluster)
+	}
+
+	if !isReplicaStreaming(cluster, podList, surge.NewInstance) {
+		if isReplicaSurgeExpired(cluster, time.Now()) {
+			return true, r.abandonReplicaSurge(ctx, cluster, podList)
+		}
+
+		contextLogger.Info("Waiting for the new instance to catch up with the primary",
+			"outdatedInstance", surge.OutdatedInstance,
+			"newInstance", surge.NewInstance)
+		return true, r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade,
+			fmt.Sprintf("Waiting for instance %s to catch up before removing instance %s, because: %s",
+				surge.NewInstance, surge.OutdatedInstance, surge.Reason))
+	}
+
+	contextLogger.Info("The new instance caught up with the primary, removing the outdated replica",
+		"outdatedInstance", surge.OutdatedInstance,
+		"newInstance", surge.NewInstance)
+	r.Recorder.Eventf(cluster, "Normal", events.ReplacedInstance,
+		"Removing instance %s, replaced by %s", surge.OutdatedInstance, surge.NewInstance)
+	if err := r.ensureInstanceIsDeleted(ctx, cluster, surge.OutdatedInstance); err != nil {
+		return false, err
+	}
+
+	return true, r.clearReplicaSurge(ctx, cluster)
+}
+
+// abandonReplicaSurge removes the new instance which didn't catch up with
+// the primary in time, and restarts the outdated replica in-place
+func (r *ClusterReconciler) abandonReplicaSurge(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+) error {
+	surge := cluster.Status.ReplicaSurge
+
+	log.FromContext(ctx).Warning("The new instance didn't catch up with the primary in time, "+
+		"restarting the outdated replica in-place",
+		"outdatedInstance", surge.OutdatedInstance,
+		"newInstance", surge.NewInstance,
+		"startedAt", surge.StartedAt,
+		"timeout", cluster.GetReplicaSurgeTimeout())
+	r.Recorder.Eventf(cluster, "Warning", events.ReplaceInstanceTimeout,
+		"Instance %s didn't catch up within %d seconds, restarting %s in-place",
+		surge.NewInstance, cluster.GetReplicaSurgeTimeout(), surge.OutdatedInstance)
+
+	if err := r.ensureInstanceIsDeleted(ctx, cluster, surge.NewInstance); err != nil {
+		return err
+	}
+
+	if err := r.clearReplicaSurge(ctx, cluster); err != nil {
+		return err
+	}
+
+	for _, item := range podList.Items {
+		if item.Pod == nil || item.Pod.Name != surge.OutdatedInstance {
+			continue
+		}
+
+		restartMessage := fmt.Sprintf("Restarting instance %s, because: %s", item.Pod.Name, surge.Reason)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade, restartMessage); err != nil {
+			return err
+		}
+		return r.upgradePod(ctx, cluster, item.Pod, restartMessage)
+	}
+
+	return nil
+}
+
+// isReplicaSurgeExpired checks whether the new instance has been given
+// more time than allowed to catch up with the primary
+func isReplicaSurgeExpired(cluster *apiv1.Cluster, now time.Time) bool {
+	startedAt, err := time.Parse(time.RFC3339, cluster.Status.ReplicaSurge.StartedAt)
+	if err != nil {
+		return false
+	}
+
+	timeout := time.Duration(cluster.GetReplicaSurgeTimeout()) * time.Second
+	return now.After(startedAt.Add(timeout))
+}
+
+// clearReplicaSurge marks the replacement of the outdated replica as
+// completed
+func (r *ClusterReconciler) clearReplicaSurge(ctx context.Context, cluster *apiv1.Cluster) error {
+	cluster.Status.ReplicaSurge = nil
+	return r.Status().Update(ctx, cluster)
+}
+
+// isReplicaStreaming checks whether the primary reports the passed
+// instance as a standby that caught up and is streaming the WAL
+func isReplicaStreaming(
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+	instanceName string,
+) bool {
+	for _, item := range podList.Items {
+		if item.Pod == nil || item.Pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		for _, replica := range item.ReplicationInfo {
+			if replica.ApplicationName == instanceName && replica.State == replicationStateStreaming {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,197 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("surge-based replica update", func() {
+	var (
+		cluster  *apiv1.Cluster
+		pods     []*corev1.Pod
+		pvc      *corev1.PersistentVolumeClaim
+		statuses postgres.PostgresqlStatusList
+		r        *ClusterReconciler
+	)
+
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiGVString,
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances:           3,
+				ReplicaUpdateMethod: apiv1.ReplicaUpdateMethodSurge,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary:      "cluster-example-1",
+				TargetPrimary:       "cluster-example-1",
+				LatestGeneratedNode: 3,
+				InstanceNames:       []string{"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+			},
+		}
+		pods = []*corev1.Pod{
+			newPod("cluster-example-1"),
+			newPod("cluster-example-2"),
+			newPod("cluster-example-3"),
+		}
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3", Namespace: "default"},
+		}
+		statuses = postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: pods[0], IsPrimary: true},
+			{Pod: pods[1]},
+			{Pod: pods[2]},
+		}}
+
+		scheme := schemeBuilder.BuildWithAllKnownScheme()
+		r = &ClusterReconciler{
+			Client: fakeClientWithIndexAdapter{
+				Client: fake.NewClientBuilder().WithScheme(scheme).
+					WithObjects(cluster, pods[0], pods[1], pods[2], pvc).
+					WithStatusSubresource(&apiv1.Cluster{}).
+					Build(),
+			},
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	getCluster := func(ctx SpecContext) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	It("creates a new replica to replace the outdated one", func(ctx SpecContext) {
+		Expect(r.startReplicaSurge(ctx, cluster, "cluster-example-3", "the image changed")).To(Succeed())
+
+		updatedCluster := getCluster(ctx)
+		Expect(updatedCluster.Status.LatestGeneratedNode).To(Equal(4))
+		Expect(updatedCluster.Status.ReplicaSurge).ToNot(BeNil())
+		Expect(updatedCluster.Status.ReplicaSurge.OutdatedInstance).To(Equal("cluster-example-3"))
+		Expect(updatedCluster.Status.ReplicaSurge.NewInstance).To(Equal("cluster-example-4"))
+		Expect(updatedCluster.GetSurgeInstances()).To(Equal(1))
+
+		job := specs.JoinReplicaInstance(*cluster, 4)
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(job), job)).To(Succeed())
+	})
+
+	When("a replacement is in progress", func() {
+		BeforeEach(func(ctx SpecContext) {
+			cluster.Status.ReplicaSurge = &apiv1.ReplicaSurgeStatus{
+				OutdatedInstance: "cluster-example-3",
+				NewInstance:      "cluster-example-4",
+				Reason:           "the image changed",
+				StartedAt:        time.Now().UTC().Format(time.RFC3339),
+			}
+			cluster.Status.InstanceNames = append(cluster.Status.InstanceNames, "cluster-example-4")
+			Expect(r.Status().Update(ctx, cluster)).To(Succeed())
+			statuses.Items = append(statuses.Items, postgres.PostgresqlStatus{Pod: newPod("cluster-example-4")})
+		})
+
+		It("waits for the new replica to catch up", func(ctx SpecContext) {
+			statuses.Items[0].ReplicationInfo = postgres.PgStatReplicationList{
+				{ApplicationName: "cluster-example-4", State: "catchup"},
+			}
+
+			inProgress, err := r.reconcileReplicaSurge(ctx, cluster, &statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.Status.ReplicaSurge).ToNot(BeNil())
+			Expect(updatedCluster.Status.Phase).To(Equal(apiv1.PhaseUpgrade))
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})).To(Succeed())
+		})
+
+		It("restarts the outdated replica in-place when the new one doesn't catch up in time",
+			func(ctx SpecContext) {
+				cluster.Status.ReplicaSurge.StartedAt = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+				inProgress, err := r.reconcileReplicaSurge(ctx, cluster, &statuses)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(inProgress).To(BeTrue())
+
+				updatedCluster := getCluster(ctx)
+				Expect(updatedCluster.Status.ReplicaSurge).To(BeNil())
+				Expect(updatedCluster.Status.Phase).To(Equal(apiv1.PhaseUpgrade))
+				err = r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})
+				Expect(apierrs.IsNotFound(err)).To(BeTrue())
+				Expect(r.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})).To(Succeed())
+			})
+
+		It("removes the outdated replica once the new one is streaming", func(ctx SpecContext) {
+			statuses.Items[0].ReplicationInfo = postgres.PgStatReplicationList{
+				{ApplicationName: "cluster-example-2", State: "streaming"},
+				{ApplicationName: "cluster-example-4", State: "streaming"},
+			}
+
+			inProgress, err := r.reconcileReplicaSurge(ctx, cluster, &statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+
+			Expect(getCluster(ctx).Status.ReplicaSurge).To(BeNil())
+			err = r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+			err = r.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("abandons the replacement when the outdated replica has been promoted", func(ctx SpecContext) {
+			cluster.Status.TargetPrimary = "cluster-example-3"
+
+			inProgress, err := r.reconcileReplicaSurge(ctx, cluster, &statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+			Expect(getCluster(ctx).Status.ReplicaSurge).To(BeNil())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})).To(Succeed())
+		})
+
+		It("abandons the replacement when the update method changes", func(ctx SpecContext) {
+			cluster.Spec.ReplicaUpdateMethod = apiv1.ReplicaUpdateMethodRestart
+
+			inProgress, err := r.reconcileReplicaSurge(ctx, cluster, &statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+			Expect(getCluster(ctx).Status.ReplicaSurge).To(BeNil())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pods[2]), &corev1.Pod{})).To(Succeed())
+		})
+	})
+})
@@ -18,20 +18,33 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// failureDetectionInterval is the minimum time between two observations of
+// an unhealthy primary counted against .spec.failoverDetection.consecutiveFailures
+const failureDetectionInterval = 5 * time.Second
+
 // ErrWalReceiversRunning is raised when a new primary server can't be elected
 // because there is a WAL receiver running in our Pod list
 var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
@@ -40,6 +53,18 @@ var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 // elapsed yet
 var ErrWaitingOnFailOverDelay = fmt.Errorf("current primary isn't healthy, waiting for the delay before triggering a failover") //nolint: lll
 
+// ErrWaitingOnFailureThreshold is raised when the primary server can't be elected because the current one
+// hasn't been detected to be unhealthy for .spec.failoverDetection.consecutiveFailures times yet
+var ErrWaitingOnFailureThreshold = fmt.Errorf("current primary isn't healthy, waiting for more consecutive failures before triggering a failover") //nolint: lll
+
+// ErrWaitingOnFlapDamping is raised when the primary server can't be elected because the current one
+// has been promoted within .spec.failoverDetection.flapDampingCooldown
+var ErrWaitingOnFlapDamping = fmt.Errorf("current primary isn't healthy, waiting for the flap damping cooldown before triggering a failover") //nolint: lll
+
+// ErrFailoverArbiterDenied is raised when the primary server can't be elected because
+// the external failover arbiter didn't grant the promotion
+var ErrFailoverArbiterDenied = fmt.Errorf("the failover arbiter didn't grant the promotion of a new primary")
+
 // reconcileTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will return the name of the new primary selected for promotion.
 // Returns the name of the primary if any changes was made and any error encountered.
@@ -58,20 +83,15 @@ func (r *ClusterReconciler) reconcileTargetPrimaryFromPods(
 		return "", nil
 	}
 
-	// First step: check if the current primary is running in an unschedulable node
-	// and issue a switchover if that's the case
+	// First step: check if the current primary is running in a node being drained
+	// or not healthy and issue a switchover if that's the case
 	if primary := status.Items[0]; (primary.IsPrimary || (cluster.IsReplica() && primary.IsPodReady)) &&
 		primary.Pod.Name == cluster.Status.CurrentPrimary &&
-		cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
-		isPrimaryOnUnschedulableNode, err := r.isNodeUnschedulable(ctx, primary.Node)
-		if err != nil {
-			contextLogger.Error(err, "while checking if current primary is on an unschedulable node")
-			// in case of error it's better to proceed with the normal target primary reconciliation
-		} else if isPrimaryOnUnschedulableNode {
-			contextLogger.Info("Primary is running on an unschedulable node, will try switching over",
-				"node", primary.Node, "primary", primary.Pod.Name)
-			return r.setPrimaryOnSchedulableNode(ctx, cluster, status, &primary)
-		}
+		cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary &&
+		primary.NodeUnavailabilityReason != "" {
+		contextLogger.Info("Primary is running on an unavailable node, will try switching over",
+			"node", primary.Node, "reason", primary.NodeUnavailabilityReason, "primary", primary.Pod.Name)
+		return r.setPrimaryOnSchedulableNode(ctx, cluster, status, &primary)
 	}
 
 	// Second step: check if the first element of the sorted list is the primary
@@ -102,7 +122,15 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 		return "", nil
 	}
 
-	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
+	if err := r.enforceFailoverDetection(ctx, cluster); err != nil {
+		return "", err
+	}
+
+	if err := r.enforceFlapDamping(ctx, cluster); err != nil {
+		return "", err
+	}
+
+	if err := r.enforceFailoverArbiter(ctx, cluster); err != nil {
 		return "", err
 	}
 
@@ -115,7 +143,7 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 		contextLogger.Info("Current primary isn't healthy, initiating a failover")
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before initiating the failover", "instances", resources.instances)
-		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
+		r.Recorder.Eventf(cluster, "Normal", events.FailoverTriggered,
 			"Current primary isn't healthy, initiating a failover from %v", cluster.Status.CurrentPrimary)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
 			fmt.Sprintf("Initiating a failover from %v", cluster.Status.CurrentPrimary)); err != nil {
@@ -139,7 +167,7 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 		contextLogger.Info("Failing over", "newPrimary", mostAdvancedInstance.Pod.Name)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
-		r.Recorder.Eventf(cluster, "Normal", "FailoverTarget",
+		r.Recorder.Eventf(cluster, "Normal", events.FailoverTarget,
 			"Failing over from %v to %v",
 			cluster.Status.CurrentPrimary, mostAdvancedInstance.Pod.Name)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
@@ -152,7 +180,7 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 			"newPrimary", mostAdvancedInstance.Pod.Name)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before switching target", "instances", resources.instances)
-		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
+		r.Recorder.Eventf(cluster, "Normal", events.FailoverTriggered,
 			"Target primary isn't healthy, switching target from %v to %v",
 			cluster.Status.TargetPrimary, mostAdvancedInstance.Pod.Name)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
@@ -165,18 +193,53 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 	return mostAdvancedInstance.Pod.Name, r.setPrimaryInstance(ctx, cluster, mostAdvancedInstance.Pod.Name)
 }
 
-// isNodeUnschedulable checks whether a node is set to unschedulable
-func (r *ClusterReconciler) isNodeUnschedulable(ctx context.Context, nodeName string) (bool, error) {
-	var node corev1.Node
-	err := r.Get(ctx, client.ObjectKey{Name: nodeName}, &node)
-	if err != nil {
-		return false, err
+// setNodeUnavailabilityReasons records in the status of each instance whether
+// its node is being drained or is not healthy, and sorts the list again so that
+// such instances are promoted only if no equally updated one is available.
+// The conditions of the nodes are considered only after the grace period of
+// the cluster: while any of them is pending, the time remaining is returned
+func (r *ClusterReconciler) setNodeUnavailabilityReasons(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status *postgres.PostgresqlStatusList,
+) time.Duration {
+	contextLogger := log.FromContext(ctx)
+
+	now := time.Now()
+	gracePeriod := time.Duration(cluster.Spec.NodeConditionsGracePeriod) * time.Second
+	var requeueAfter time.Duration
+	reasons := make(map[string]utils.NodeUnavailabilityReason)
+	for idx := range status.Items {
+		nodeName := status.Items[idx].Node
+		if nodeName == "" {
+			continue
+		}
+
+		reason, ok := reasons[nodeName]
+		if !ok {
+			var node corev1.Node
+			if err := r.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+				// in case of error it's better to proceed considering the node available
+				contextLogger.Error(err, "while checking the conditions of a node", "node", nodeName)
+			} else {
+				var pending time.Duration
+				reason, pending = utils.GetSettledNodeUnavailabilityReason(&node, gracePeriod, now)
+				if pending > 0 && (requeueAfter == 0 || pending < requeueAfter) {
+					requeueAfter = pending
+				}
+			}
+			reasons[nodeName] = reason
+		}
+
+		status.Items[idx].NodeUnavailabilityReason = string(reason)
 	}
-	return node.Spec.Unschedulable, nil
+
+	sort.Sort(status)
+	return requeueAfter
 }
 
-// Pick the next primary on a schedulable node, if the current is running on an unschedulable one,
-// e.g. in case a drain is in progress
+// Pick the next primary on an available node, if the current is running on an unavailable one,
+// e.g. in case a drain is in progress or the node is not healthy
 func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -198,7 +261,7 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 		(cluster.Spec.Instances != cluster.Status.ReadyInstances ||
 			// e.g. we want all instances to be moved to a schedulable node before triggering the switchover
 			len(podsOnOtherNodes.Items) < cluster.Spec.Instances-1) {
-		contextLogger.Info("Current primary is running on unschedulable node and something is already in progress",
+		contextLogger.Info("Current primary is running on unavailable node and something is already in progress",
 			"currentPrimary", primaryPod.Pod.Name,
 			"podsOnOtherNodes", len(podsOnOtherNodes.Items),
 			"instances", cluster.Spec.Instances,
@@ -215,8 +278,8 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 
 	// Start looking for the next primary among the pods
 	for _, candidate := range podsOnOtherNodes.Items {
-		// If candidate on an unschedulable node too, skip it
-		if unschedulable, _ := r.isNodeUnschedulable(ctx, candidate.Node); unschedulable {
+		// If candidate on an unavailable node too, skip it
+		if candidate.NodeUnavailabilityReason != "" {
 			continue
 		}
 
@@ -230,25 +293,27 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 		}
 
 		// Set the current candidate as targetPrimary
-		contextLogger.Info("Current primary is running on unschedulable node, triggering a switchover",
+		contextLogger.Info("Current primary is running on unavailable node, triggering a switchover",
 			"currentPrimary", primaryPod.Pod.Name, "currentPrimaryNode", primaryPod.Node,
+			"reason", primaryPod.NodeUnavailabilityReason,
 			"targetPrimary", candidate.Pod.Name, "targetPrimaryNode", candidate.Node)
 		status.LogStatus(ctx)
-		r.Recorder.Eventf(cluster, "Normal", "SwitchingOver",
-			"Current primary is running on unschedulable node %v, switching over from %v to %v",
-			primaryPod.Node, cluster.Status.TargetPrimary, candidate.Pod.Name)
+		r.Recorder.Eventf(cluster, "Normal", events.SwitchingOver,
+			"Current primary is running on unavailable node %v (%v), switching over from %v to %v",
+			primaryPod.Node, primaryPod.NodeUnavailabilityReason, cluster.Status.TargetPrimary, candidate.Pod.Name)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
 			fmt.Sprintf("Switching over to %v, because primary instance "+
-				"was running on unschedulable node %v",
+				"was running on unavailable node %v (%v)",
 				candidate.Pod.Name,
-				primaryPod.Node)); err != nil {
+				primaryPod.Node,
+				primaryPod.NodeUnavailabilityReason)); err != nil {
 			return "", err
 		}
 		return candidate.Pod.Name, r.setPrimaryInstance(ctx, cluster, candidate.Pod.Name)
 	}
 
 	// if we are here this means no new primary has been chosen
-	contextLogger.Info("Current primary is running on unschedulable node, but there are no valid candidates",
+	contextLogger.Info("Current primary is running on unavailable node, but there are no valid candidates",
 		"currentPrimary", status.Items[0].Pod.Name,
 		"primaryNode", status.Items[0].Node,
 		"instances", status.Items)
@@ -277,7 +342,11 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForReplicaCluster(
 		}
 	}
 
-	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
+	if err := r.enforceFailoverDetection(ctx, cluster); err != nil {
+		return "", err
+	}
+
+	if err := r.enforceFlapDamping(ctx, cluster); err != nil {
 		return "", err
 	}
 
@@ -293,7 +362,7 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForReplicaCluster(
 		"newPrimary", status.Items[0].Pod.Name)
 	status.LogStatus(ctx)
 	contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
-	r.Recorder.Eventf(cluster, "Normal", "FailingOver",
+	r.Recorder.Eventf(cluster, "Normal", events.FailoverTriggered,
 		"Current target primary isn't healthy, failing over from %v to %v",
 		cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
 	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
@@ -321,6 +390,24 @@ func GetPodsNotOnPrimaryNode(
 	return podsOnOtherNodes
 }
 
+// enforceFailoverDetection checks whether the current primary has been unhealthy
+// for long enough, and for enough consecutive times, to trigger a failover.
+// Both the thresholds are evaluated at once, so that they run concurrently.
+func (r *ClusterReconciler) enforceFailoverDetection(ctx context.Context, cluster *apiv1.Cluster) error {
+	delayErr := r.enforceFailoverDelay(ctx, cluster)
+	if delayErr != nil && !errors.Is(delayErr, ErrWaitingOnFailOverDelay) {
+		return delayErr
+	}
+	thresholdErr := r.enforceFailureThreshold(ctx, cluster)
+	if thresholdErr != nil && !errors.Is(thresholdErr, ErrWaitingOnFailureThreshold) {
+		return thresholdErr
+	}
+	if delayErr != nil {
+		return delayErr
+	}
+	return thresholdErr
+}
+
 // If the cluster is not in the online upgrading phase, enforceFailoverDelay will evaluate the failover delay specified
 // in the cluster's specification.
 // If the user has set a custom failoverDelay value and the cluster is in the OnlineUpgrading phase, the function will
@@ -337,7 +424,7 @@ func (r *ClusterReconciler) enforceFailoverDelay(ctx context.Context, cluster *a
 		}
 	}
 
-	return r.evaluateFailoverDelay(ctx, cluster, cluster.Spec.FailoverDelay)
+	return r.evaluateFailoverDelay(ctx, cluster, cluster.GetFailoverDelay())
 }
 
 func (r *ClusterReconciler) evaluateFailoverDelay(
@@ -354,6 +441,9 @@ func (r *ClusterReconciler) evaluateFailoverDelay(
 		if err := r.Status().Update(ctx, cluster); err != nil {
 			return err
 		}
+		r.Recorder.Eventf(cluster, "Normal", events.FailoverPostponed,
+			"Current primary %v isn't healthy, postponing the failover for %v seconds",
+			cluster.Status.CurrentPrimary, failOverDelay)
 	}
 	primaryFailingSince, err := utils.DifferenceBetweenTimestamps(
 		utils.GetCurrentTimestamp(),
@@ -370,6 +460,303 @@ func (r *ClusterReconciler) evaluateFailoverDelay(
 	return nil
 }
 
+// enforceFailureThreshold counts the consecutive observations of the
+// status of the primary in which it has been unhealthy, returning
+// ErrWaitingOnFailureThreshold until the threshold set in
+// .spec.failoverDetection.consecutiveFailures is reached. The count is
+// reset as soon as the primary is observed to be healthy.
+// The reconciliation loop can run many times in a row, for example after
+// updating the status, so an observation is not counted when it's less
+// than failureDetectionInterval since the last counted one
+func (r *ClusterReconciler) enforceFailureThreshold(ctx context.Context, cluster *apiv1.Cluster) error {
+	threshold := cluster.GetFailoverConsecutiveFailures()
+	if threshold <= 1 {
+		return nil
+	}
+
+	counted := true
+	if cluster.Status.CurrentPrimaryLastFailureTimestamp != "" {
+		sinceLastFailure, err := utils.DifferenceBetweenTimestamps(
+			utils.GetCurrentTimestamp(),
+			cluster.Status.CurrentPrimaryLastFailureTimestamp,
+		)
+		if err != nil {
+			return err
+		}
+		counted = sinceLastFailure >= failureDetectionInterval
+	}
+
+	failures := cluster.Status.CurrentPrimaryFailureCount
+	if counted && failures < threshold {
+		failures++
+		if cluster.Status.CurrentPrimaryFailingSinceTimestamp == "" {
+			cluster.Status.CurrentPrimaryFailingSinceTimestamp = utils.GetCurrentTimestamp()
+		}
+		cluster.Status.CurrentPrimaryFailureCount = failures
+		cluster.Status.CurrentPrimaryLastFailureTimestamp = utils.GetCurrentTimestamp()
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return err
+		}
+		if failures == 1 {
+			r.Recorder.Eventf(cluster, "Normal", events.FailoverPostponed,
+				"Current primary %v isn't healthy, postponing the failover until it fails %v consecutive times",
+				cluster.Status.CurrentPrimary, threshold)
+		}
+	}
+
+	if failures < threshold {
+		log.FromContext(ctx).Info("Current primary isn't healthy, waiting for more consecutive failures",
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"failures", failures,
+			"threshold", threshold)
+		return ErrWaitingOnFailureThreshold
+	}
+
+	return nil
+}
+
+// enforceFlapDamping returns ErrWaitingOnFlapDamping when a failover would
+// start within .spec.failoverDetection.flapDampingCooldown since the last
+// promotion. A failover already in progress is never stopped.
+func (r *ClusterReconciler) enforceFlapDamping(ctx context.Context, cluster *apiv1.Cluster) error {
+	cooldown := cluster.GetFlapDampingCooldown()
+	if cooldown == 0 ||
+		cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary ||
+		cluster.Status.CurrentPrimaryTimestamp == "" {
+		return r.clearFailoverDampened(ctx, cluster)
+	}
+
+	sinceLastPromotion, err := utils.DifferenceBetweenTimestamps(
+		utils.GetCurrentTimestamp(),
+		cluster.Status.CurrentPrimaryTimestamp,
+	)
+	if err != nil {
+		return err
+	}
+	if sinceLastPromotion >= cooldown {
+		return r.clearFailoverDampened(ctx, cluster)
+	}
+
+	log.FromContext(ctx).Info("Current primary isn't healthy, waiting for the flap damping cooldown",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"currentPrimaryTimestamp", cluster.Status.CurrentPrimaryTimestamp,
+		"cooldown", cooldown)
+
+	// The event is emitted only when the failover starts being dampened
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionFailoverDampened)) {
+		return ErrWaitingOnFlapDamping
+	}
+	message := fmt.Sprintf(
+		"Current primary %v isn't healthy, but it was promoted less than %v ago: postponing the failover",
+		cluster.Status.CurrentPrimary, cooldown)
+	if err := conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionFailoverDampened),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonFlapDampingCooldown),
+		Message: message,
+	}); err != nil {
+		return err
+	}
+	r.Recorder.Event(cluster, "Warning", events.FailoverDampened, message)
+	return ErrWaitingOnFlapDamping
+}
+
+// clearFailoverDampened removes the FailoverDampened condition, if set,
+// once the failover isn't dampened anymore
+func (r *ClusterReconciler) clearFailoverDampened(ctx context.Context, cluster *apiv1.Cluster) error {
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionFailoverDampened)) == nil {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionFailoverDampened))
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// failoverArbiterRetryInterval is the time to wait before consulting
+// the failover arbiter again when it can't be reached
+const failoverArbiterRetryInterval = 5 * time.Second
+
+// enforceFailoverArbiter consults the external failover arbiter, if configured,
+// returning ErrFailoverArbiterDenied unless the promotion of a new primary
+// is granted
+func (r *ClusterReconciler) enforceFailoverArbiter(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	arbiter, err := r.failoverArbiters.Get(ctx, r.Client, cluster)
+	if err != nil {
+		contextLogger.Error(err, "while creating the failover arbiter")
+		return fmt.Errorf("%w: %v", ErrFailoverArbiterDenied, err)
+	}
+	if arbiter == nil {
+		return nil
+	}
+
+	acquired, holder, err := arbiter.TryAcquire(ctx)
+	if err != nil {
+		contextLogger.Error(err, "while consulting the failover arbiter")
+		return fmt.Errorf("%w: %v", ErrFailoverArbiterDenied, err)
+	}
+	if !acquired {
+		r.Recorder.Eventf(cluster, "Warning", events.FailoverArbiterDenied,
+			"The failover arbiter lease is held by %v, waiting before promoting a new primary", holder)
+		return ErrFailoverArbiterDenied
+	}
+
+	// The instance manager waits for this condition before promoting
+	return r.setFailoverArbiterLeaseHeld(ctx, cluster)
+}
+
+// renewFailoverArbiterLease keeps holding the failover arbiter lease while
+// the primary is healthy, fencing the primary when the lease is held by
+// another cluster or can't be renewed within its duration. It returns the
+// time after which the lease should be renewed again, or zero if no arbiter
+// is configured.
+// Replica clusters don't hold the lease, so that their designated primary
+// is promoted only after the lease has been acquired
+func (r *ClusterReconciler) renewFailoverArbiterLease(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Spec.FailoverArbiter == nil || cluster.Spec.FailoverArbiter.Lease == nil || cluster.IsReplica() {
+		r.failoverArbiters.Forget(client.ObjectKeyFromObject(cluster))
+		return 0, r.removeFailoverArbiterLeaseCondition(ctx, cluster)
+	}
+
+	if cluster.Status.CurrentPrimary == "" || cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return 0, nil
+	}
+
+	arbiter, err := r.failoverArbiters.Get(ctx, r.Client, cluster)
+	if err != nil {
+		contextLogger.Error(err, "while creating the failover arbiter")
+		return failoverArbiterRetryInterval, nil
+	}
+
+	if delay := arbiter.GetRenewalDelay(time.Now()); delay > 0 {
+		return delay, nil
+	}
+
+	acquired, holder, err := arbiter.TryAcquire(ctx)
+	switch {
+	case acquired:
+		return arbiter.GetLeaseDuration() / 3, r.setFailoverArbiterLeaseHeld(ctx, cluster)
+
+	case err != nil && !arbiter.IsExpired(time.Now()):
+		contextLogger.Error(err, "while renewing the failover arbiter lease")
+		return failoverArbiterRetryInterval, nil
+
+	case err != nil:
+		// Another cluster may have acquired the lease in the meantime
+		contextLogger.Error(err, "the failover arbiter lease couldn't be renewed within its duration")
+		holder = "an unknown cluster"
+	}
+
+	return failoverArbiterRetryInterval, r.setFailoverArbiterLeaseLost(ctx, cluster, instancesStatus, holder)
+}
+
+// setFailoverArbiterLeaseHeld records that this cluster holds the failover
+// arbiter lease, lifting the fence from the primary if it was fenced when
+// the lease was lost
+func (r *ClusterReconciler) setFailoverArbiterLeaseHeld(ctx context.Context, cluster *apiv1.Cluster) error {
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionFailoverArbiterLease))
+	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
+	if err != nil {
+		return err
+	}
+	if condition != nil && condition.Reason == string(apiv1.ConditionReasonFailoverArbiterLeaseLost) &&
+		fencedInstances.Has(cluster.Status.CurrentPrimary) {
+		log.FromContext(ctx).Info("Failover arbiter lease acquired again, lifting the fence from the primary",
+			"primary", cluster.Status.CurrentPrimary)
+		if err := utils.NewFencingMetadataExecutor(r.Client).RemoveFencing().
+			ForInstance(cluster.Status.CurrentPrimary).
+			Execute(ctx, client.ObjectKeyFromObject(cluster), &apiv1.Cluster{}); err != nil {
+			return err
+		}
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionFailoverArbiterLease),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonFailoverArbiterLeaseHeld),
+		Message: "The failover arbiter lease is held by this cluster",
+	})
+}
+
+// setFailoverArbiterLeaseLost records that the failover arbiter lease is
+// held by another cluster, fencing the current primary unless it is a
+// designated primary still waiting to be promoted
+func (r *ClusterReconciler) setFailoverArbiterLeaseLost(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	holder string,
+) error {
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionFailoverArbiterLease))
+	if condition != nil && condition.Reason == string(apiv1.ConditionReasonFailoverArbiterLeaseLost) {
+		// The primary has already been fenced
+		return nil
+	}
+
+	if isWaitingForPromotion(cluster, instancesStatus) {
+		if condition == nil || condition.Status != metav1.ConditionFalse {
+			r.Recorder.Eventf(cluster, "Warning", events.FailoverArbiterDenied,
+				"The failover arbiter lease is held by %v, waiting before promoting a new primary", holder)
+		}
+		return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionFailoverArbiterLease),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonFailoverArbiterLeaseNotAcquired),
+			Message: fmt.Sprintf("The failover arbiter lease is held by %v", holder),
+		})
+	}
+
+	log.FromContext(ctx).Warning("The failover arbiter lease is held by another cluster while "+
+		"this cluster is running a primary, fencing it", "holder", holder, "primary", cluster.Status.CurrentPrimary)
+	if err := utils.NewFencingMetadataExecutor(r.Client).AddFencing().
+		ForInstance(cluster.Status.CurrentPrimary).
+		Execute(ctx, client.ObjectKeyFromObject(cluster), &apiv1.Cluster{}); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(cluster, "Warning", events.FailoverArbiterLeaseLost,
+		"The failover arbiter lease is held by %v while this cluster is running a primary: fencing %v",
+		holder, cluster.Status.CurrentPrimary)
+
+	return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+		Type:   string(apiv1.ConditionFailoverArbiterLease),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonFailoverArbiterLeaseLost),
+		Message: fmt.Sprintf("The failover arbiter lease is held by %v, the primary %v has been fenced",
+			holder, cluster.Status.CurrentPrimary),
+	})
+}
+
+// isWaitingForPromotion checks if the current primary is reported to be a
+// standby, like the designated primary of a replica cluster being promoted
+func isWaitingForPromotion(cluster *apiv1.Cluster, instancesStatus postgres.PostgresqlStatusList) bool {
+	for _, item := range instancesStatus.Items {
+		if item.Pod != nil && item.Pod.Name == cluster.Status.CurrentPrimary {
+			return item.HasHTTPStatus() && !item.IsPrimary
+		}
+	}
+	return false
+}
+
+// removeFailoverArbiterLeaseCondition removes the FailoverArbiterLease
+// condition, if set, when the cluster doesn't contend the lease
+func (r *ClusterReconciler) removeFailoverArbiterLeaseCondition(ctx context.Context, cluster *apiv1.Cluster) error {
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionFailoverArbiterLease)) == nil {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionFailoverArbiterLease))
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
 // findDeletableInstance get the Pod who is supposed to be deleted when the cluster is scaled down
 func findDeletableInstance(cluster *apiv1.Cluster, instances []corev1.Pod) string {
 	resultIdx := -1
@@ -409,3 +796,34 @@ func findDeletableInstance(cluster *apiv1.Cluster, instances []corev1.Pod) strin
 
 	return instances[resultIdx].Name
 }
+
+// mapDemotedClustersToClusters returns a function mapping a cluster which
+// has been demoted in a distributed topology to the cluster taking its
+// place in the same namespace, which uses its demotion token
+func (r *ClusterReconciler) mapDemotedClustersToClusters() handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		demoted, ok := obj.(*apiv1.Cluster)
+		if !ok || demoted.Status.DemotionToken == "" || demoted.Spec.ReplicaCluster == nil {
+			return nil
+		}
+
+		var clusters apiv1.ClusterList
+		if err := r.List(ctx, &clusters, client.InNamespace(demoted.Namespace)); err != nil {
+			log.FromContext(ctx).Error(err, "while getting cluster list")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, cluster := range clusters.Items {
+			if cluster.UID == demoted.UID || cluster.Spec.ReplicaCluster == nil ||
+				cluster.Spec.ReplicaCluster.Source != demoted.GetReplicaClusterSelf() ||
+				cluster.GetReplicaClusterSelf() != demoted.Spec.ReplicaCluster.Primary {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+			})
+		}
+		return requests
+	}
+}
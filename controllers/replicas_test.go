@@ -17,10 +17,18 @@ limitations under the License.
 package controllers
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -151,3 +159,338 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Failover arbiter", func() {
+	It("grants the promotion when no arbiter is configured", func(ctx SpecContext) {
+		r := &ClusterReconciler{Recorder: record.NewFakeRecorder(10)}
+		Expect(r.enforceFailoverArbiter(ctx, &apiv1.Cluster{})).To(Succeed())
+	})
+
+	It("denies the promotion when the arbiter can't be consulted", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				FailoverArbiter: &apiv1.FailoverArbiterConfiguration{
+					Lease: &apiv1.FailoverArbiterLease{
+						KubeconfigSecret: apiv1.SecretKeySelector{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "missing"},
+							Key:                  "kubeconfig",
+						},
+						Namespace: "arbiter",
+						Identity:  "site-a",
+					},
+				},
+			},
+		}
+		r := &ClusterReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+		Expect(r.enforceFailoverArbiter(ctx, cluster)).To(MatchError(ErrFailoverArbiterDenied))
+	})
+
+	When("the lease is held by another cluster", func() {
+		var (
+			cluster *apiv1.Cluster
+			r       *ClusterReconciler
+		)
+
+		BeforeEach(func() {
+			cluster = &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+				Status: apiv1.ClusterStatus{
+					CurrentPrimary: "cluster-example-1",
+					TargetPrimary:  "cluster-example-1",
+				},
+			}
+			primary := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"}}
+			r = &ClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+					WithObjects(cluster, primary).WithStatusSubresource(cluster).Build(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+		})
+
+		statusOf := func(isPrimary bool) postgres.PostgresqlStatusList {
+			return postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{{
+				Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary: isPrimary,
+			}}}
+		}
+
+		It("fences the primary, lifting the fence once the lease is acquired again", func(ctx SpecContext) {
+			Expect(r.setFailoverArbiterLeaseLost(ctx, cluster, statusOf(true), "site-b")).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			Expect(cluster.IsInstanceFenced("cluster-example-1")).To(BeTrue())
+			condition := meta.FindStatusCondition(cluster.Status.Conditions,
+				string(apiv1.ConditionFailoverArbiterLease))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonFailoverArbiterLeaseLost)))
+
+			Expect(r.setFailoverArbiterLeaseHeld(ctx, cluster)).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			Expect(cluster.IsInstanceFenced("cluster-example-1")).To(BeFalse())
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions,
+				string(apiv1.ConditionFailoverArbiterLease))).To(BeTrue())
+		})
+
+		It("doesn't fence a designated primary waiting to be promoted", func(ctx SpecContext) {
+			Expect(r.setFailoverArbiterLeaseLost(ctx, cluster, statusOf(false), "site-b")).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			Expect(cluster.IsInstanceFenced("cluster-example-1")).To(BeFalse())
+			condition := meta.FindStatusCondition(cluster.Status.Conditions,
+				string(apiv1.ConditionFailoverArbiterLease))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonFailoverArbiterLeaseNotAcquired)))
+		})
+	})
+
+	It("doesn't contend the lease in a replica cluster", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{Enabled: true, Source: "origin"},
+				FailoverArbiter: &apiv1.FailoverArbiterConfiguration{
+					Lease: &apiv1.FailoverArbiterLease{Namespace: "arbiter", Identity: "site-a"},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Conditions: []metav1.Condition{{
+					Type:   string(apiv1.ConditionFailoverArbiterLease),
+					Status: metav1.ConditionTrue,
+					Reason: string(apiv1.ConditionReasonFailoverArbiterLeaseHeld),
+				}},
+			},
+		}
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).WithStatusSubresource(cluster).Build(),
+		}
+
+		renewal, err := r.renewFailoverArbiterLease(ctx, cluster, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewal).To(BeZero())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions,
+			string(apiv1.ConditionFailoverArbiterLease))).To(BeNil())
+	})
+})
+
+var _ = Describe("Node unavailability", func() {
+	newNode := func(name string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	newStatus := func(podName, nodeName string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName}},
+			Node:        nodeName,
+			ReceivedLsn: "1/A0",
+			ReplayLsn:   "1/A0",
+		}
+	}
+
+	It("moves the instances running on unavailable nodes down the election order", func(ctx SpecContext) {
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(newNode("node-1", true), newNode("node-2", false)).Build(),
+		}
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", "node-1"),
+				newStatus("cluster-example-2", "node-2"),
+				newStatus("cluster-example-3", "missing-node"),
+			},
+		}
+
+		Expect(r.setNodeUnavailabilityReasons(ctx, &apiv1.Cluster{}, &status)).To(BeZero())
+
+		Expect(status.GetNames()).To(Equal([]string{
+			"cluster-example-2",
+			"cluster-example-3",
+			"cluster-example-1",
+		}))
+		Expect(status.Items[2].NodeUnavailabilityReason).To(BeEquivalentTo(utils.NodeUnschedulable))
+	})
+
+	It("waits for the grace period before considering a node not ready", func(ctx SpecContext) {
+		node := newNode("node-1", false)
+		node.Status.Conditions[0].Status = corev1.ConditionFalse
+		node.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-10 * time.Second))
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(node).Build(),
+		}
+		cluster := &apiv1.Cluster{Spec: apiv1.ClusterSpec{NodeConditionsGracePeriod: 60}}
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newStatus("cluster-example-1", "node-1")},
+		}
+
+		requeueAfter := r.setNodeUnavailabilityReasons(ctx, cluster, &status)
+		Expect(requeueAfter).To(BeNumerically("~", 50*time.Second, 5*time.Second))
+		Expect(status.Items[0].NodeUnavailabilityReason).To(BeEmpty())
+
+		cluster.Spec.NodeConditionsGracePeriod = 5
+		Expect(r.setNodeUnavailabilityReasons(ctx, cluster, &status)).To(BeZero())
+		Expect(status.Items[0].NodeUnavailabilityReason).To(BeEquivalentTo(utils.NodeNotReady))
+	})
+})
+
+var _ = Describe("Failover detection", func() {
+	var (
+		cluster  *apiv1.Cluster
+		recorder *record.FakeRecorder
+		r        *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				FailoverDetection: &apiv1.FailoverDetectionConfiguration{
+					ConsecutiveFailures: 3,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		r = &ClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).WithStatusSubresource(cluster).Build(),
+			Recorder: recorder,
+		}
+	})
+
+	// observeLater moves the last counted observation back in time, as
+	// if the status of the primary was observed again after it
+	observeLater := func(elapsed time.Duration) {
+		cluster.Status.CurrentPrimaryLastFailureTimestamp = time.Now().
+			Add(-elapsed).Format(metav1.RFC3339Micro)
+	}
+
+	It("waits for the consecutive failures threshold", func(ctx SpecContext) {
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailureThreshold))
+		Expect(cluster.Status.CurrentPrimaryFailureCount).To(BeEquivalentTo(1))
+		Expect(cluster.Status.CurrentPrimaryFailingSinceTimestamp).ToNot(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring(events.FailoverPostponed)))
+
+		// Reconciling again right away doesn't count as a new failure
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailureThreshold))
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailureThreshold))
+		Expect(cluster.Status.CurrentPrimaryFailureCount).To(BeEquivalentTo(1))
+		Expect(recorder.Events).ToNot(Receive())
+
+		observeLater(failureDetectionInterval)
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailureThreshold))
+		Expect(cluster.Status.CurrentPrimaryFailureCount).To(BeEquivalentTo(2))
+
+		observeLater(failureDetectionInterval)
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.CurrentPrimaryFailureCount).To(BeEquivalentTo(3))
+	})
+
+	It("counts the observations and not the elapsed time", func(ctx SpecContext) {
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailureThreshold))
+
+		// A long time without observations counts as a single failure
+		cluster.Status.CurrentPrimaryFailingSinceTimestamp = time.Now().
+			Add(-time.Hour).Format(metav1.RFC3339Micro)
+		observeLater(time.Hour)
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailureThreshold))
+		Expect(cluster.Status.CurrentPrimaryFailureCount).To(BeEquivalentTo(2))
+	})
+
+	It("waits for both the failover delay and the consecutive failures", func(ctx SpecContext) {
+		cluster.Spec.FailoverDelay = 30
+		Expect(r.Update(ctx, cluster)).To(Succeed())
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailOverDelay))
+		Expect(cluster.Status.CurrentPrimaryFailureCount).To(BeEquivalentTo(1))
+
+		observeLater(failureDetectionInterval)
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailOverDelay))
+		observeLater(failureDetectionInterval)
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(MatchError(ErrWaitingOnFailOverDelay))
+		Expect(cluster.Status.CurrentPrimaryFailureCount).To(BeEquivalentTo(3))
+
+		cluster.Status.CurrentPrimaryFailingSinceTimestamp = time.Now().
+			Add(-time.Minute).Format(metav1.RFC3339Micro)
+		Expect(r.enforceFailoverDetection(ctx, cluster)).To(Succeed())
+	})
+
+	It("uses the longest between the failover delay and the minimum unreachable duration", func() {
+		cluster.Spec.FailoverDelay = 10
+		Expect(cluster.GetFailoverDelay()).To(BeEquivalentTo(10))
+
+		cluster.Spec.FailoverDetection.MinUnreachableDuration = 30
+		Expect(cluster.GetFailoverDelay()).To(BeEquivalentTo(30))
+	})
+
+	It("dampens the failovers happening right after a promotion", func(ctx SpecContext) {
+		cluster.Spec.FailoverDetection.FlapDampingCooldown = 300
+		Expect(r.Update(ctx, cluster)).To(Succeed())
+		cluster.Status.CurrentPrimaryTimestamp = utils.GetCurrentTimestamp()
+		Expect(r.Status().Update(ctx, cluster)).To(Succeed())
+		Expect(r.enforceFlapDamping(ctx, cluster)).To(MatchError(ErrWaitingOnFlapDamping))
+		Expect(recorder.Events).To(Receive(ContainSubstring(events.FailoverDampened)))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			string(apiv1.ConditionFailoverDampened))).To(BeTrue())
+
+		// The event is emitted only when the damping starts
+		Expect(r.enforceFlapDamping(ctx, cluster)).To(MatchError(ErrWaitingOnFlapDamping))
+		Expect(recorder.Events).ToNot(Receive())
+
+		cluster.Status.CurrentPrimaryTimestamp = time.Now().Add(-10 * time.Minute).Format(metav1.RFC3339Micro)
+		Expect(r.enforceFlapDamping(ctx, cluster)).To(Succeed())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions,
+			string(apiv1.ConditionFailoverDampened))).To(BeNil())
+	})
+
+	It("never stops a failover already in progress", func(ctx SpecContext) {
+		cluster.Spec.FailoverDetection.FlapDampingCooldown = 300
+		cluster.Status.CurrentPrimaryTimestamp = utils.GetCurrentTimestamp()
+		cluster.Status.TargetPrimary = apiv1.PendingFailoverMarker
+		Expect(r.enforceFlapDamping(ctx, cluster)).To(Succeed())
+	})
+})
+
+var _ = Describe("Demoted clusters", func() {
+	It("are mapped to the cluster taking their place", func(ctx SpecContext) {
+		demoted := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-eu-south", Namespace: "default", UID: "south"},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Primary: "cluster-eu-central",
+					Source:  "cluster-eu-central",
+				},
+			},
+			Status: apiv1.ClusterStatus{DemotionToken: "token"},
+		}
+		promoted := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-eu-central", Namespace: "default", UID: "central"},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Primary: "cluster-eu-central",
+					Source:  "cluster-eu-south",
+				},
+			},
+		}
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(demoted, promoted).Build(),
+		}
+
+		requests := r.mapDemotedClustersToClusters()(ctx, demoted)
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Name).To(Equal("cluster-eu-central"))
+
+		demoted.Status.DemotionToken = ""
+		Expect(r.mapDemotedClustersToClusters()(ctx, demoted)).To(BeEmpty())
+	})
+})
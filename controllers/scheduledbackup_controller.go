@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"strconv"
 	"time"
 
@@ -33,13 +34,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 const (
-	backupOwnerKey = ".metadata.controller"
-
 	// ImmediateBackupLabelName label is applied to backups to tell if a backup
 	// is immediate or not
 	ImmediateBackupLabelName = utils.ImmediateBackupLabelName
@@ -58,7 +58,7 @@ type ScheduledBackupReconciler struct {
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledbackups/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;create
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;create;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is the main reconciler logic
@@ -95,29 +95,6 @@ func (r *ScheduledBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, nil
 	}
 
-	// We are supposed to start a new backup. Let's extract
-	// the list of backups we have already taken to see if anything
-	// is running now
-	childBackups, err := r.GetChildBackups(ctx, scheduledBackup)
-	if err != nil {
-		contextLogger.Error(err,
-			"Cannot extract the list of created backups")
-		return ctrl.Result{}, err
-	}
-
-	// We are supposed to start a new backup. Let's extract
-	// the list of backups we have already taken to see if anything
-	// is running now
-	for _, backup := range childBackups {
-		if !backup.Status.IsDone() {
-			contextLogger.Info(
-				"The system is already taking a scheduledBackup, retrying in 60 seconds",
-				"backupName", backup.GetName(),
-				"backupPhase", backup.Status.Phase)
-			return ctrl.Result{RequeueAfter: time.Minute}, nil
-		}
-	}
-
 	return ReconcileScheduledBackup(ctx, r.Recorder, r.Client, &scheduledBackup)
 }
 
@@ -151,7 +128,7 @@ func ReconcileScheduledBackup(
 			event.Eventf(
 				scheduledBackup,
 				"Normal",
-				"InvalidCluster",
+				events.InvalidCluster,
 				"Cannot get cluster %v, %v",
 				scheduledBackup.Spec.Cluster.Name,
 				err.Error(),
@@ -163,7 +140,7 @@ func ReconcileScheduledBackup(
 			event.Eventf(
 				scheduledBackup,
 				"Warning",
-				"ClusterNotHealthy",
+				events.ClusterNotHealthy,
 				"Waiting for cluster to be healthy, was \"%v\"",
 				cluster.Status.Phase,
 			)
@@ -187,26 +164,87 @@ func ReconcileScheduledBackup(
 		}
 
 		if scheduledBackup.IsImmediate() {
-			event.Eventf(scheduledBackup, "Normal", "BackupSchedule", "Scheduled immediate backup now: %v", now)
+			event.Eventf(scheduledBackup, "Normal", events.BackupSchedule, "Scheduled immediate backup now: %v", now)
 			return createBackup(ctx, event, cli, scheduledBackup, now, now, schedule, true)
 		}
 
 		nextTime := schedule.Next(now)
 		contextLogger.Info("Next backup schedule", "next", nextTime)
-		event.Eventf(scheduledBackup, "Normal", "BackupSchedule", "Scheduled first backup by %v", nextTime)
+		event.Eventf(scheduledBackup, "Normal", events.BackupSchedule, "Scheduled first backup by %v", nextTime)
 		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
 	}
 
 	// Let's check if we are supposed to start a new backup.
 	nextTime := schedule.Next(scheduledBackup.GetStatus().LastCheckTime.Time)
-	contextLogger.Info("Next backup schedule", "next", nextTime)
+	startTime := nextTime.Add(getJitterDelay(scheduledBackup, nextTime))
+	contextLogger.Info("Next backup schedule", "next", nextTime, "start", startTime)
 
-	if now.Before(nextTime) {
+	if now.Before(startTime) {
 		// No need to schedule a new backup, let's wait a bit
-		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+		return ctrl.Result{RequeueAfter: startTime.Sub(now)}, nil
+	}
+
+	// We are supposed to start a new backup. Let's extract
+	// the list of backups we have already taken to see if anything
+	// is running now
+	runningBackups, err := getRunningBackups(ctx, cli, scheduledBackup)
+	if err != nil {
+		contextLogger.Error(err, "Cannot extract the list of created backups")
+		return ctrl.Result{}, err
+	}
+	if len(runningBackups) == 0 {
+		return createBackup(ctx, event, cli, scheduledBackup, nextTime, now, schedule, false)
+	}
+
+	switch scheduledBackup.Spec.ConcurrencyPolicy {
+	case apiv1.ScheduledBackupConcurrencyPolicyAllow:
+		return createBackup(ctx, event, cli, scheduledBackup, nextTime, now, schedule, false)
+
+	case apiv1.ScheduledBackupConcurrencyPolicyForbid:
+		contextLogger.Info("Skipping the scheduled backup as another one is still running",
+			"backupName", runningBackups[0].GetName(),
+			"backupPhase", runningBackups[0].Status.Phase)
+		event.Eventf(scheduledBackup, "Normal", events.BackupSkipped,
+			"Skipped backup scheduled by %v, as backup %v is still running",
+			nextTime, runningBackups[0].GetName())
+		return scheduleNextBackup(ctx, event, cli, scheduledBackup, scheduledBackup.DeepCopy(), now, schedule)
+
+	case apiv1.ScheduledBackupConcurrencyPolicyReplace:
+		for i := range runningBackups {
+			backup := &runningBackups[i]
+			contextLogger.Info("Deleting running backup to replace it",
+				"backupName", backup.GetName(),
+				"backupPhase", backup.Status.Phase)
+			if err := cli.Delete(ctx, backup); err != nil && !apierrs.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			event.Eventf(scheduledBackup, "Normal", events.BackupReplaced,
+				"Deleted running backup %v to replace it", backup.GetName())
+		}
+		return createBackup(ctx, event, cli, scheduledBackup, nextTime, now, schedule, false)
+
+	default:
+		contextLogger.Info(
+			"The system is already taking a scheduledBackup, retrying in 60 seconds",
+			"backupName", runningBackups[0].GetName(),
+			"backupPhase", runningBackups[0].Status.Phase)
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+}
+
+// getJitterDelay gets the delay to be applied to the backup scheduled at the
+// passed time. The delay is picked inside the jitter window of the scheduled
+// backup, and is stable across reconciliations of the same scheduled time
+func getJitterDelay(scheduledBackup *apiv1.ScheduledBackup, scheduledTime time.Time) time.Duration {
+	jitter := scheduledBackup.GetJitter()
+	if jitter <= 0 {
+		return 0
 	}
 
-	return createBackup(ctx, event, cli, scheduledBackup, nextTime, now, schedule, false)
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(scheduledBackup.UID))
+	_, _ = hash.Write([]byte(scheduledTime.UTC().Format(time.RFC3339)))
+	return time.Duration(hash.Sum64() % uint64(jitter)) // #nosec G115
 }
 
 // createBackup creates a scheduled backup for a backuptime, updating the ScheduledBackup accordingly
@@ -266,18 +304,36 @@ func createBackup(
 		contextLogger.Error(
 			err, "Error while creating backup object",
 			"backupName", backup.GetName())
-		event.Event(scheduledBackup, "Warning", "BackupCreation", "Error while creating backup object")
+		event.Event(scheduledBackup, "Warning", events.BackupCreation, "Error while creating backup object")
 		return ctrl.Result{}, err
 	}
 
+	scheduledBackup.Status.LastScheduleTime = &metav1.Time{
+		Time: backupTime,
+	}
+	return scheduleNextBackup(ctx, event, cli, scheduledBackup, origScheduled, now, schedule)
+}
+
+// scheduleNextBackup updates the ScheduledBackup status after the backup
+// scheduled before now has been handled, and requeues the reconciliation
+// at the start of the next one
+func scheduleNextBackup(
+	ctx context.Context,
+	event record.EventRecorder,
+	cli client.Client,
+	scheduledBackup *apiv1.ScheduledBackup,
+	origScheduled *apiv1.ScheduledBackup,
+	now time.Time,
+	schedule cron.Schedule,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
 	// Ok, now update the latest check to now
 	scheduledBackup.Status.LastCheckTime = &metav1.Time{
 		Time: now,
 	}
-	scheduledBackup.Status.LastScheduleTime = &metav1.Time{
-		Time: backupTime,
-	}
-	nextBackupTime := schedule.Next(now)
+	nextScheduleTime := schedule.Next(now)
+	nextBackupTime := nextScheduleTime.Add(getJitterDelay(scheduledBackup, nextScheduleTime))
 	scheduledBackup.Status.NextScheduleTime = &metav1.Time{
 		Time: nextBackupTime,
 	}
@@ -291,56 +347,40 @@ func createBackup(
 		return ctrl.Result{}, err
 	}
 
-	contextLogger.Info("Next backup schedule", "next", backupTime)
-	event.Eventf(scheduledBackup, "Normal", "BackupSchedule", "Next backup scheduled by %v", nextBackupTime)
+	contextLogger.Info("Next backup schedule", "next", nextBackupTime)
+	event.Eventf(scheduledBackup, "Normal", events.BackupSchedule, "Next backup scheduled by %v", nextBackupTime)
 	return ctrl.Result{RequeueAfter: nextBackupTime.Sub(now)}, nil
 }
 
-// GetChildBackups gets all the backups scheduled by a certain scheduler
-func (r *ScheduledBackupReconciler) GetChildBackups(
+// getRunningBackups gets the backups created by a certain scheduled backup
+// that are not completed yet
+func getRunningBackups(
 	ctx context.Context,
-	scheduledBackup apiv1.ScheduledBackup,
+	cli client.Client,
+	scheduledBackup *apiv1.ScheduledBackup,
 ) ([]apiv1.Backup, error) {
 	var childBackups apiv1.BackupList
 
-	if err := r.List(ctx, &childBackups,
+	if err := cli.List(ctx, &childBackups,
 		client.InNamespace(scheduledBackup.Namespace),
-		client.MatchingFields{backupOwnerKey: scheduledBackup.Name},
+		client.MatchingLabels{utils.ParentScheduledBackupLabelName: scheduledBackup.Name},
 	); err != nil {
-		return nil, fmt.Errorf("unable to list child pods resource: %w", err)
+		return nil, fmt.Errorf("unable to list child backups: %w", err)
+	}
+
+	runningBackups := make([]apiv1.Backup, 0, len(childBackups.Items))
+	for _, backup := range childBackups.Items {
+		if !backup.Status.IsDone() {
+			runningBackups = append(runningBackups, backup)
+		}
 	}
 
-	return childBackups.Items, nil
+	return runningBackups, nil
 }
 
 // SetupWithManager install this controller in the controller manager
-func (r *ScheduledBackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
-	// Create a new indexed field on backups. This field will be used to easily
-	// find all the backups created by this controller
-	if err := mgr.GetFieldIndexer().IndexField(
-		ctx,
-		&apiv1.Backup{},
-		backupOwnerKey, func(rawObj client.Object) []string {
-			pod := rawObj.(*apiv1.Backup)
-			owner := metav1.GetControllerOf(pod)
-			if owner == nil {
-				return nil
-			}
-
-			if owner.Kind != apiv1.BackupKind {
-				return nil
-			}
-
-			if owner.APIVersion != apiGVString {
-				return nil
-			}
-
-			return []string{owner.Name}
-		}); err != nil {
-		return err
-	}
-
+func (r *ScheduledBackupReconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.ScheduledBackup{}).
-		Complete(r)
+		Complete(withSharding(withReconcileMetrics("scheduledbackup", r)))
 }
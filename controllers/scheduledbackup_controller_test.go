@@ -0,0 +1,154 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scheduled backup jitter", func() {
+	scheduledTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	It("doesn't delay backups without a jitter", func() {
+		scheduledBackup := &apiv1.ScheduledBackup{}
+		Expect(getJitterDelay(scheduledBackup, scheduledTime)).To(BeZero())
+	})
+
+	It("delays backups inside the jitter window", func() {
+		scheduledBackup := &apiv1.ScheduledBackup{
+			ObjectMeta: metav1.ObjectMeta{UID: "5f0b0ed2-3b5a-4a5e-9f41-8a0bb8d1c2d7"},
+			Spec: apiv1.ScheduledBackupSpec{
+				Jitter: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+		}
+
+		delay := getJitterDelay(scheduledBackup, scheduledTime)
+		Expect(delay).To(BeNumerically(">=", 0))
+		Expect(delay).To(BeNumerically("<", 10*time.Minute))
+		Expect(getJitterDelay(scheduledBackup, scheduledTime)).To(Equal(delay))
+	})
+})
+
+var _ = Describe("scheduled backup concurrency policy", func() {
+	var (
+		cli             client.Client
+		scheduledBackup *apiv1.ScheduledBackup
+		runningBackup   *apiv1.Backup
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		cli = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.ScheduledBackup{}, &apiv1.Backup{}).
+			Build()
+
+		scheduledBackup = &apiv1.ScheduledBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "daily",
+				Namespace: "default",
+			},
+			Spec: apiv1.ScheduledBackupSpec{
+				Schedule: "0 0 0 * * *",
+				Cluster:  apiv1.LocalObjectReference{Name: "cluster-example"},
+			},
+		}
+		Expect(cli.Create(ctx, scheduledBackup)).To(Succeed())
+		scheduledBackup.Status.LastCheckTime = &metav1.Time{Time: time.Now().Add(-48 * time.Hour)}
+		Expect(cli.Status().Update(ctx, scheduledBackup)).To(Succeed())
+
+		runningBackup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "daily-running",
+				Namespace: "default",
+				Labels: map[string]string{
+					utils.ParentScheduledBackupLabelName: scheduledBackup.Name,
+				},
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: scheduledBackup.Spec.Cluster,
+			},
+		}
+		Expect(cli.Create(ctx, runningBackup)).To(Succeed())
+		runningBackup.Status.Phase = apiv1.BackupPhaseRunning
+		Expect(cli.Status().Update(ctx, runningBackup)).To(Succeed())
+	})
+
+	listBackups := func(ctx context.Context) []apiv1.Backup {
+		var backups apiv1.BackupList
+		Expect(cli.List(ctx, &backups, client.InNamespace("default"))).To(Succeed())
+		return backups.Items
+	}
+
+	reconcile := func(ctx context.Context) time.Duration {
+		result, err := ReconcileScheduledBackup(ctx, record.NewFakeRecorder(10), cli, scheduledBackup)
+		Expect(err).ToNot(HaveOccurred())
+		return result.RequeueAfter
+	}
+
+	It("waits for the running backup when no policy is set", func(ctx context.Context) {
+		Expect(reconcile(ctx)).To(Equal(time.Minute))
+		Expect(listBackups(ctx)).To(HaveLen(1))
+	})
+
+	It("creates the new backup with the Allow policy", func(ctx context.Context) {
+		scheduledBackup.Spec.ConcurrencyPolicy = apiv1.ScheduledBackupConcurrencyPolicyAllow
+		reconcile(ctx)
+		Expect(listBackups(ctx)).To(HaveLen(2))
+	})
+
+	It("skips the scheduled run with the Forbid policy", func(ctx context.Context) {
+		scheduledBackup.Spec.ConcurrencyPolicy = apiv1.ScheduledBackupConcurrencyPolicyForbid
+		Expect(reconcile(ctx)).To(BeNumerically(">", time.Minute))
+		Expect(listBackups(ctx)).To(HaveLen(1))
+
+		var updated apiv1.ScheduledBackup
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(scheduledBackup), &updated)).To(Succeed())
+		Expect(updated.Status.LastCheckTime.Time).To(BeTemporally("~", time.Now(), time.Minute))
+		Expect(updated.Status.LastScheduleTime).To(BeNil())
+	})
+
+	It("replaces the running backup with the Replace policy", func(ctx context.Context) {
+		scheduledBackup.Spec.ConcurrencyPolicy = apiv1.ScheduledBackupConcurrencyPolicyReplace
+		reconcile(ctx)
+
+		backups := listBackups(ctx)
+		Expect(backups).To(HaveLen(1))
+		Expect(backups[0].Name).ToNot(Equal(runningBackup.Name))
+	})
+
+	It("creates the backup when nothing is running", func(ctx context.Context) {
+		scheduledBackup.Spec.ConcurrencyPolicy = apiv1.ScheduledBackupConcurrencyPolicyForbid
+		runningBackup.Status.Phase = apiv1.BackupPhaseCompleted
+		Expect(cli.Status().Update(ctx, runningBackup)).To(Succeed())
+
+		reconcile(ctx)
+		Expect(listBackups(ctx)).To(HaveLen(2))
+	})
+})
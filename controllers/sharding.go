@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// shardedReconciler skips the requests for the objects in the namespaces
+// assigned to other operator shards
+type shardedReconciler struct {
+	reconciler reconcile.Reconciler
+}
+
+// withSharding wraps a reconciler, making it reconcile only the objects
+// in the namespaces assigned to the shard of this operator deployment.
+// Every object of a cluster lives in the cluster namespace, so a cluster
+// and its backups, poolers and instances are always reconciled by the
+// same shard, even when the request comes from a cluster-wide object
+// such as a node
+func withSharding(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return shardedReconciler{
+		reconciler: reconciler,
+	}
+}
+
+// Reconcile implements the reconcile.Reconciler interface
+func (r shardedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !configuration.Current.IsNamespaceInShard(req.Namespace) {
+		log.FromContext(ctx).Trace("Skipping request for a namespace assigned to another shard")
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconciler.Reconcile(ctx, req)
+}
+
+// StripObjectsOfOtherShards is a cache transform function replacing the
+// objects in the namespaces assigned to other shards with empty objects,
+// having only the metadata identifying them. This way the informers of an
+// operator watching every namespace don't keep the content of the objects
+// it won't reconcile. Cluster-wide objects and the objects in the operator
+// namespace are always kept
+func StripObjectsOfOtherShards(obj interface{}) (interface{}, error) {
+	object, ok := obj.(client.Object)
+	if !ok {
+		return obj, nil
+	}
+
+	namespace := object.GetNamespace()
+	if namespace == "" ||
+		namespace == configuration.Current.OperatorNamespace ||
+		configuration.Current.IsNamespaceInShard(namespace) {
+		return obj, nil
+	}
+
+	stripped, ok := reflect.New(reflect.TypeOf(object).Elem()).Interface().(client.Object)
+	if !ok {
+		return obj, nil
+	}
+	stripped.GetObjectKind().SetGroupVersionKind(object.GetObjectKind().GroupVersionKind())
+	stripped.SetNamespace(namespace)
+	stripped.SetName(object.GetName())
+	stripped.SetUID(object.GetUID())
+	stripped.SetResourceVersion(object.GetResourceVersion())
+
+	return stripped, nil
+}
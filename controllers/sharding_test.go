@@ -0,0 +1,144 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sharding", func() {
+	var reconciled []string
+	reconciler := withSharding(reconcile.Func(
+		func(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+			reconciled = append(reconciled, req.Namespace)
+			return ctrl.Result{}, nil
+		}))
+
+	BeforeEach(func() {
+		reconciled = nil
+		previous := configuration.Current
+		configuration.Current = configuration.NewConfiguration()
+		DeferCleanup(func() {
+			configuration.Current = previous
+		})
+	})
+
+	reconcileAll := func(namespaces []string) {
+		for _, namespace := range namespaces {
+			_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{
+				NamespacedName: types.NamespacedName{Namespace: namespace, Name: "cluster-example"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+		}
+	}
+
+	namespaces := []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta"}
+
+	It("reconciles every namespace when sharding is disabled", func() {
+		reconcileAll(namespaces)
+		Expect(reconciled).To(Equal(namespaces))
+	})
+
+	It("reconciles only the namespaces of its shard", func() {
+		configuration.Current.ShardCount = 2
+
+		var shards [][]string
+		for index := 0; index < 2; index++ {
+			reconciled = nil
+			configuration.Current.ShardIndex = index
+			reconcileAll(namespaces)
+			for _, namespace := range reconciled {
+				Expect(configuration.GetNamespaceShard(namespace, 2)).To(Equal(index))
+			}
+			shards = append(shards, reconciled)
+		}
+
+		Expect(append(shards[0], shards[1]...)).To(ConsistOf(namespaces))
+	})
+
+	Context("when stripping the cached objects", func() {
+		newCluster := func(namespace string) *apiv1.Cluster {
+			return &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       namespace,
+					Name:            "cluster-example",
+					UID:             "d1d9a4b6-7b0d-4a54-8f5a-0b4a1a6c8f51",
+					ResourceVersion: "42",
+					Labels:          map[string]string{"app": "example"},
+				},
+				Spec: apiv1.ClusterSpec{Instances: 3},
+			}
+		}
+
+		BeforeEach(func() {
+			configuration.Current.ShardCount = 2
+			configuration.Current.OperatorNamespace = "cnpg-system"
+		})
+
+		It("keeps the objects of its shard", func() {
+			for _, namespace := range namespaces {
+				if configuration.GetNamespaceShard(namespace, 2) != 0 {
+					continue
+				}
+				cluster := newCluster(namespace)
+				result, err := StripObjectsOfOtherShards(cluster)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeIdenticalTo(cluster))
+			}
+		})
+
+		It("keeps the objects of the operator namespace and the cluster-wide ones", func() {
+			for _, namespace := range []string{"cnpg-system", ""} {
+				cluster := newCluster(namespace)
+				result, err := StripObjectsOfOtherShards(cluster)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeIdenticalTo(cluster))
+			}
+		})
+
+		It("strips the objects of the other shards", func() {
+			for _, namespace := range namespaces {
+				if configuration.GetNamespaceShard(namespace, 2) == 0 {
+					continue
+				}
+				cluster := newCluster(namespace)
+				result, err := StripObjectsOfOtherShards(cluster)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeAssignableToTypeOf(&apiv1.Cluster{}))
+
+				stripped := result.(*apiv1.Cluster)
+				Expect(stripped.Namespace).To(Equal(namespace))
+				Expect(stripped.Name).To(Equal(cluster.Name))
+				Expect(stripped.UID).To(Equal(cluster.UID))
+				Expect(stripped.ResourceVersion).To(Equal(cluster.ResourceVersion))
+				Expect(stripped.Labels).To(BeEmpty())
+				Expect(stripped.Spec.Instances).To(BeZero())
+			}
+		})
+	})
+})
@@ -25,17 +25,33 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 )
 
 // NewCmd create a new cobra command
 func NewCmd() *cobra.Command {
+	var deleteBackup bool
+
 	cmd := cobra.Command{
 		Use: "backup [backup_name]",
-		RunE: func(_ *cobra.Command, args []string) error {
-			backupURL := url.Local(url.PathPgBackup, url.LocalPort)
-			resp, err := http.Get(backupURL + "?name=" + args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method := http.MethodGet
+			if deleteBackup {
+				method = http.MethodDelete
+			}
+
+			backupURL := url.Local(url.Versioned(url.PathPgBackup), url.LocalPort)
+			req, err := http.NewRequestWithContext(cmd.Context(), method, backupURL+"?name="+args[0], nil)
+			if err != nil {
+				return err
+			}
+			if err := localauth.Authorize(req); err != nil {
+				return err
+			}
+
+			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
 				log.Error(err, "Error while requesting backup")
 				return err
@@ -81,5 +97,8 @@ func NewCmd() *cobra.Command {
 		Args: cobra.ExactArgs(1),
 	}
 
+	cmd.Flags().BoolVar(&deleteBackup, "delete", false,
+		"Remove the base backup from the object store instead of taking it")
+
 	return &cmd
 }
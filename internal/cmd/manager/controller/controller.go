@@ -23,12 +23,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"os"
+	"path/filepath"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -39,6 +42,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/multicache"
@@ -75,6 +79,9 @@ const (
 	// CaSecretName is the name of the secret which is hosting the Operator CA
 	CaSecretName = "cnpg-ca-secret" // #nosec
 
+	// readinessCacheSyncTimeout is how long the readiness probe waits
+	// for the cache to be synced
+	readinessCacheSyncTimeout = 500 * time.Millisecond
 )
 
 // leaderElectionConfiguration contains the leader parameters that will be passed to controllerruntime.Options.
@@ -108,6 +115,11 @@ func RunController(
 		startPprofDebugServer(ctx)
 	}
 
+	if err := configuration.Current.ValidateSharding(); err != nil {
+		setupLog.Error(err, "invalid sharding configuration")
+		return err
+	}
+
 	managerOptions := ctrl.Options{
 		Scheme: scheme,
 		Metrics: server.Options{
@@ -116,7 +128,7 @@ func RunController(
 		LeaderElection:   leaderConfig.enable,
 		LeaseDuration:    &leaderConfig.leaseDuration,
 		RenewDeadline:    &leaderConfig.renewDeadline,
-		LeaderElectionID: LeaderElectionID,
+		LeaderElectionID: getLeaderElectionID(),
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port:    port,
 			CertDir: defaultWebhookCertDir,
@@ -135,15 +147,26 @@ func RunController(
 	}
 
 	if configuration.Current.WatchNamespace != "" {
-		namespaces := configuration.Current.WatchedNamespaces()
+		namespaces := getCachedNamespaces()
 		managerOptions.NewCache = multicache.DelegatingMultiNamespacedCacheBuilder(
 			namespaces,
 			configuration.Current.OperatorNamespace)
 		setupLog.Info("Listening for changes", "watchNamespaces", namespaces)
 	} else {
+		if configuration.Current.IsShardingEnabled() {
+			// We can't select the namespaces of this shard with the informers,
+			// so we only keep the metadata of the objects of the other shards
+			managerOptions.Cache.DefaultTransform = controllers.StripObjectsOfOtherShards
+		}
 		setupLog.Info("Listening for changes on all namespaces")
 	}
 
+	if configuration.Current.IsShardingEnabled() {
+		setupLog.Info("Reconciling only the namespaces assigned to this shard",
+			"shardIndex", configuration.Current.ShardIndex,
+			"shardCount", configuration.Current.ShardCount)
+	}
+
 	if configuration.Current.WebhookCertDir != "" {
 		// If OLM will generate certificates for us, let's just
 		// use those
@@ -176,11 +199,21 @@ func RunController(
 		return err
 	}
 
+	shardCount, shardIndex := configuration.Current.ShardCount, configuration.Current.ShardIndex
 	err = loadConfiguration(ctx, kubeClient, configMapName, secretName)
 	if err != nil {
 		return err
 	}
 
+	// The leader election lease has already been chosen with the sharding
+	// configuration read from the environment
+	if configuration.Current.ShardCount != shardCount || configuration.Current.ShardIndex != shardIndex {
+		err = errors.New("the sharding configuration can only be set with environment variables " +
+			"of the operator deployment, and not in the operator ConfigMap or Secret")
+		setupLog.Error(err, "invalid sharding configuration")
+		return err
+	}
+
 	setupLog.Info("Operator configuration loaded", "configuration", configuration.Current)
 
 	discoveryClient, err := utils.GetDiscoveryClient()
@@ -226,7 +259,12 @@ func RunController(
 		"availableArchitectures", utils.GetAvailableArchitectures(),
 	)
 
-	if err := ensurePKI(ctx, kubeClient, webhookServer.Options.CertDir); err != nil {
+	if isPKIManager() {
+		err = ensurePKI(ctx, kubeClient, webhookServer.Options.CertDir)
+	} else {
+		err = waitForPKI(ctx, kubeClient, webhookServer.Options.CertDir)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -243,7 +281,7 @@ func RunController(
 	if err = (&controllers.ScheduledBackupReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-scheduledbackup"),
+		Recorder: events.NewRecorder(mgr.GetEventRecorderFor("cloudnative-pg-scheduledbackup")),
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ScheduledBackup")
 		return err
@@ -253,12 +291,31 @@ func RunController(
 		Client:          mgr.GetClient(),
 		DiscoveryClient: discoveryClient,
 		Scheme:          mgr.GetScheme(),
-		Recorder:        mgr.GetEventRecorderFor("cloudnative-pg-pooler"),
+		Recorder:        events.NewRecorder(mgr.GetEventRecorderFor("cloudnative-pg-pooler")),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pooler")
 		return err
 	}
 
+	if err = (&controllers.BenchmarkReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  events.NewRecorder(mgr.GetEventRecorderFor("cloudnative-pg-benchmark")),
+		APIReader: mgr.GetAPIReader(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Benchmark")
+		return err
+	}
+
+	if err = (&controllers.ChangeDataCaptureReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: events.NewRecorder(mgr.GetEventRecorderFor("cloudnative-pg-changedatacapture")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChangeDataCapture")
+		return err
+	}
+
 	if err = (&apiv1.Cluster{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Cluster", "version", "v1")
 		return err
@@ -279,10 +336,12 @@ func RunController(
 		return err
 	}
 
-	// Setup the handler used by the readiness and liveliness probe.
+	// Setup the handlers used by the readiness and liveliness probe.
 	//
-	// Unfortunately the readiness of the probe is not sufficient for the operator to be
-	// working correctly. The probe may be positive even when:
+	// The operator is ready when its cache is synced and the webhook
+	// certificate is valid. Unfortunately the readiness of the probe is not
+	// sufficient for the operator to be working correctly. The probe may be
+	// positive even when:
 	//
 	// 1. the CA is not yet updated inside the CRD and/or in the validating/mutating
 	//    webhook configuration. In that case we have a timeout error after trying
@@ -290,7 +349,11 @@ func RunController(
 	//
 	// 2. the webhook service and/or the CNI are being updated, e.g. when a POD is
 	//    deleted. In that case we could get a "Connection refused" error message.
-	webhookServer.WebhookMux().HandleFunc("/readyz", readinessProbeHandler)
+	webhookServer.WebhookMux().Handle("/readyz", readinessProbeHandler{
+		cache:    mgr.GetCache(),
+		certFile: filepath.Join(webhookServer.Options.CertDir, webhookServer.Options.CertName),
+	})
+	webhookServer.WebhookMux().HandleFunc("/healthz", livenessProbeHandler)
 
 	// +kubebuilder:scaffold:builder
 
@@ -303,6 +366,49 @@ func RunController(
 	return nil
 }
 
+// getCachedNamespaces gets the watched namespaces whose objects are
+// cached by this operator deployment, that are the ones assigned to its shard
+func getCachedNamespaces() []string {
+	namespaces := configuration.Current.WatchedNamespaces()
+	if !configuration.Current.IsShardingEnabled() {
+		return namespaces
+	}
+
+	result := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if configuration.Current.IsNamespaceInShard(namespace) {
+			result = append(result, namespace)
+		}
+	}
+
+	// An empty list of namespaces would make the cache watch every
+	// namespace, so we only cache the operator one instead
+	if len(result) == 0 {
+		result = append(result, configuration.Current.OperatorNamespace)
+	}
+
+	return result
+}
+
+// isPKIManager checks if this operator deployment is the one managing the
+// PKI. When sharding is enabled, the first shard generates and renews the
+// webhook certificate and injects it into the webhook configurations and
+// CRDs, while the other shards just use it
+func isPKIManager() bool {
+	return !configuration.Current.IsShardingEnabled() || configuration.Current.ShardIndex == 0
+}
+
+// getLeaderElectionID gets the ID of the lease used for leader election.
+// Every shard has its own leader, as the operator deployments of the
+// different shards run concurrently
+func getLeaderElectionID() string {
+	if !configuration.Current.IsShardingEnabled() {
+		return LeaderElectionID
+	}
+
+	return fmt.Sprintf("shard-%d-%s", configuration.Current.ShardIndex, LeaderElectionID)
+}
+
 // loadConfiguration reads the configuration from the provided configmap and secret
 func loadConfiguration(
 	ctx context.Context,
@@ -348,8 +454,38 @@ func loadConfiguration(
 	return nil
 }
 
-// readinessProbeHandler is used to implement the readiness probe handler
-func readinessProbeHandler(w http.ResponseWriter, _ *http.Request) {
+// livenessProbeHandler is used to implement the liveness probe handler
+func livenessProbeHandler(w http.ResponseWriter, _ *http.Request) {
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// readinessProbeHandler is used to implement the readiness probe handler,
+// checking that the cache is synced and the webhook certificate is valid
+type readinessProbeHandler struct {
+	cache    cache.Cache
+	certFile string
+}
+
+// ServeHTTP implements the http.Handler interface
+func (h readinessProbeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessCacheSyncTimeout)
+	defer cancel()
+
+	if !h.cache.WaitForCacheSync(ctx) {
+		http.Error(w, "the cache is not synced", http.StatusServiceUnavailable)
+		return
+	}
+
+	certificate, err := os.ReadFile(h.certFile)
+	if err == nil {
+		pair := certs.KeyPair{Certificate: certificate}
+		err = pair.CheckValidity()
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid webhook certificate: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
 	_, _ = fmt.Fprint(w, "OK")
 }
 
@@ -367,7 +503,38 @@ func ensurePKI(
 
 	// We need to self-manage required PKI infrastructure and install the certificates into
 	// the webhooks configuration
-	pkiConfig := certs.PublicKeyInfrastructure{
+	pkiConfig := getPKIConfig(mgrCertDir)
+	err := pkiConfig.Setup(ctx, kubeClient)
+	if err != nil {
+		setupLog.Error(err, "unable to setup PKI infrastructure")
+	}
+	return err
+}
+
+// waitForPKI waits for the webhook certificate managed by the first shard
+// to be available
+func waitForPKI(
+	ctx context.Context,
+	kubeClient client.Client,
+	mgrCertDir string,
+) error {
+	if configuration.Current.WebhookCertDir != "" {
+		// OLM is generating certificates for us
+		return nil
+	}
+
+	setupLog.Info("Waiting for the webhook certificate managed by the first shard")
+	pkiConfig := getPKIConfig(mgrCertDir)
+	err := pkiConfig.WaitForCertificates(ctx, kubeClient)
+	if err != nil {
+		setupLog.Error(err, "unable to load the webhook certificate")
+	}
+	return err
+}
+
+// getPKIConfig gets the configuration of the PKI of the operator
+func getPKIConfig(mgrCertDir string) certs.PublicKeyInfrastructure {
+	return certs.PublicKeyInfrastructure{
 		CaSecretName:                       CaSecretName,
 		CertDir:                            mgrCertDir,
 		SecretName:                         WebhookSecretName,
@@ -382,11 +549,6 @@ func ensurePKI(
 		},
 		OperatorDeploymentLabelSelector: "app.kubernetes.io/name=cloudnative-pg",
 	}
-	err := pkiConfig.Setup(ctx, kubeClient)
-	if err != nil {
-		setupLog.Error(err, "unable to setup PKI infrastructure")
-	}
-	return err
 }
 
 // readConfigMap reads the configMap and returns its content as map
@@ -22,6 +22,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/configsnapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/initdb"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/join"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/pgbasebackup"
@@ -29,6 +30,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restoresnapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/topology"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/upgrade"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/verifybackup"
 )
 
 // NewCmd creates the "instance" command
@@ -45,9 +49,13 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(join.NewCmd())
 	cmd.AddCommand(run.NewCmd())
 	cmd.AddCommand(status.NewCmd())
+	cmd.AddCommand(configsnapshot.NewCmd())
+	cmd.AddCommand(topology.NewCmd())
 	cmd.AddCommand(pgbasebackup.NewCmd())
 	cmd.AddCommand(restore.NewCmd())
 	cmd.AddCommand(restoresnapshot.NewCmd())
+	cmd.AddCommand(upgrade.NewCmd())
+	cmd.AddCommand(verifybackup.NewCmd())
 
 	return cmd
 }
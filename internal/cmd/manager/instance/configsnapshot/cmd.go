@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configsnapshot implement the "instance config-snapshot" subcommand
+// of the operator
+package configsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// requestTimeout is the time given to each request to the local webserver
+const requestTimeout = 10 * time.Second
+
+// NewCmd create the "instance config-snapshot" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config-snapshot",
+		Short: "Print the parsed pg_controldata output and the non-default settings",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return configSnapshotSubCommand(cmd.Context())
+		},
+	}
+
+	return cmd
+}
+
+func configSnapshotSubCommand(ctx context.Context) error {
+	var snapshot postgres.ConfigurationSnapshot
+
+	controlData, err := getFromLocalWebserver[map[string]string](ctx, url.PathPGControlData)
+	if err != nil {
+		log.Error(err, "Error while requesting the pg_controldata output")
+		return err
+	}
+	snapshot.ControlData = *controlData
+
+	settings, err := getFromLocalWebserver[[]postgres.Setting](ctx, url.PathPgSettings)
+	if err != nil {
+		log.Error(err, "Error while requesting the non-default settings")
+		return err
+	}
+	snapshot.Settings = *settings
+
+	return json.NewEncoder(os.Stdout).Encode(snapshot)
+}
+
+// getFromLocalWebserver reads the payload of a local webserver endpoint
+func getFromLocalWebserver[T any](ctx context.Context, path string) (*T, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		url.Local(url.Versioned(path), url.LocalPort),
+		nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := localauth.Authorize(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body webserver.Response[T]
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("while decoding the response of %s: %w", path, err)
+	}
+	if err := body.EnsureDataIsPresent(); err != nil {
+		return nil, err
+	}
+
+	return body.Data, nil
+}
@@ -94,6 +94,11 @@ func NewCmd() *cobra.Command {
 }
 
 func joinSubCommand(ctx context.Context, instance *postgres.Instance, info postgres.InitInfo) error {
+	if err := info.RemoveInterruptedClone(); err != nil {
+		log.Error(err, "Error while removing the data left by an interrupted clone")
+		return err
+	}
+
 	err := info.VerifyPGData()
 	if err != nil {
 		return err
@@ -109,9 +114,14 @@ func joinSubCommand(ctx context.Context, instance *postgres.Instance, info postg
 	if err != nil {
 		return err
 	}
+	eventRecorder, err := management.NewEventRecorder()
+	if err != nil {
+		log.Error(err, "Error creating event recorder")
+		return err
+	}
 	// Let's download the crypto material from the cluster
 	// secrets.
-	reconciler := controller.NewInstanceReconciler(instance, client, metricServer)
+	reconciler := controller.NewInstanceReconciler(instance, client, metricServer, eventRecorder)
 	if err != nil {
 		log.Error(err, "Error creating reconciler to download certificates")
 		return err
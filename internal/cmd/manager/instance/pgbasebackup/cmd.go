@@ -141,7 +141,7 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 		connectionString += " options='-c wal_sender_timeout=0s'"
 	}
 
-	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal)
+	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal, postgres.CloneOptions{})
 	if err != nil {
 		return err
 	}
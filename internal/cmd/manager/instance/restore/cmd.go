@@ -40,6 +40,7 @@ func NewCmd() *cobra.Command {
 	var namespace string
 	var pgData string
 	var pgWal string
+	var inPlaceRestore string
 
 	cmd := &cobra.Command{
 		Use:           "restore [flags]",
@@ -60,6 +61,10 @@ func NewCmd() *cobra.Command {
 				PgWal:       pgWal,
 			}
 
+			if inPlaceRestore != "" {
+				return restoreInPlaceSubCommand(ctx, info, inPlaceRestore)
+			}
+
 			return restoreSubCommand(ctx, info)
 		},
 		PostRunE: func(cmd *cobra.Command, _ []string) error {
@@ -77,6 +82,8 @@ func NewCmd() *cobra.Command {
 		"the cluster and the Pod in k8s")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be restored")
 	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL to be restored")
+	cmd.Flags().StringVar(&inPlaceRestore, "in-place-restore", "", "The name of the Restore object "+
+		"requesting to replace the existing PGDATA with the content of a backup")
 
 	return cmd
 }
@@ -97,6 +104,18 @@ func restoreSubCommand(ctx context.Context, info postgres.InitInfo) error {
 	return nil
 }
 
+// restoreInPlaceSubCommand replaces the data directory of the primary
+// instance with the content of the backup selected by the passed Restore
+// object. The operator follows the job running it to update the Restore
+func restoreInPlaceSubCommand(ctx context.Context, info postgres.InitInfo, restoreName string) error {
+	if err := info.RestoreInPlace(ctx, restoreName); err != nil {
+		log.Error(err, "Error while restoring a backup in place", "restore", restoreName)
+		return err
+	}
+
+	return nil
+}
+
 func cleanupDataDirectoryIfNeeded(restoreError error, dataDirectory string) {
 	var barmanError *barman.CloudRestoreError
 	if !errors.As(restoreError, &barmanError) {
@@ -37,10 +37,18 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/backupcatalog"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/changedatacaptures"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/databases"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/externalservers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/maintenance"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/publications"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/inactive"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/subscriptions"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/tablespaces"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/walstorage"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
@@ -127,6 +135,26 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 						instance.Namespace: {},
 					},
 				},
+				&apiv1.Database{}: {
+					Namespaces: map[string]cache.Config{
+						instance.Namespace: {},
+					},
+				},
+				&apiv1.Publication{}: {
+					Namespaces: map[string]cache.Config{
+						instance.Namespace: {},
+					},
+				},
+				&apiv1.Subscription{}: {
+					Namespaces: map[string]cache.Config{
+						instance.Namespace: {},
+					},
+				},
+				&apiv1.ChangeDataCapture{}: {
+					Namespaces: map[string]cache.Config{
+						instance.Namespace: {},
+					},
+				},
 			},
 		},
 		// We don't need a cache for secrets and configmap, as all reloads
@@ -156,7 +184,13 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 	postgresStartConditions := concurrency.MultipleExecuted{}
 	exitedConditions := concurrency.MultipleExecuted{}
 
-	reconciler := controller.NewInstanceReconciler(instance, mgr.GetClient(), metricsServer)
+	eventRecorder, err := management.NewEventRecorder()
+	if err != nil {
+		setupLog.Error(err, "unable to create event recorder")
+		return err
+	}
+
+	reconciler := controller.NewInstanceReconciler(instance, mgr.GetClient(), metricsServer, eventRecorder)
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Cluster{}).
 		Complete(reconciler)
@@ -223,6 +257,25 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	if err = mgr.Add(backupcatalog.NewRefresher(instance, reconciler.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create backup catalog refresher")
+		return err
+	}
+
+	if err = mgr.Add(maintenance.NewScheduler(instance, reconciler.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create database maintenance scheduler")
+		return err
+	}
+
+	if err = mgr.Add(inactive.NewMonitor(instance, reconciler.GetClient(), eventRecorder)); err != nil {
+		setupLog.Error(err, "unable to create inactive replication slots monitor")
+		return err
+	}
+	if err = mgr.Add(walstorage.NewMonitor(instance, reconciler.GetClient(), eventRecorder)); err != nil {
+		setupLog.Error(err, "unable to create WAL storage monitor")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
@@ -240,7 +293,7 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
-	localSrv, err := webserver.NewLocalWebServer(instance)
+	localSrv, err := webserver.NewLocalWebServer(instance, reconciler.RefreshCache)
 	if err != nil {
 		return err
 	}
@@ -249,6 +302,15 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	diagnosticsSrv, err := webserver.NewDiagnosticsWebServer(instance)
+	if err != nil {
+		return err
+	}
+	if err = mgr.Add(diagnosticsSrv); err != nil {
+		setupLog.Error(err, "unable to add diagnostics webserver runnable")
+		return err
+	}
+
 	setupLog.Info("starting tablespace manager")
 	if err := tablespaces.NewTablespaceReconciler(instance, mgr.GetClient()).
 		SetupWithManager(mgr); err != nil {
@@ -256,6 +318,34 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	setupLog.Info("starting database reconciler")
+	if err := databases.NewDatabaseReconciler(instance, mgr.GetClient()).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create database reconciler")
+		return err
+	}
+
+	setupLog.Info("starting publication reconciler")
+	if err := publications.NewPublicationReconciler(instance, mgr.GetClient()).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create publication reconciler")
+		return err
+	}
+
+	setupLog.Info("starting subscription reconciler")
+	if err := subscriptions.NewSubscriptionReconciler(instance, mgr.GetClient()).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create subscription reconciler")
+		return err
+	}
+
+	setupLog.Info("starting change data capture reconciler")
+	if err := changedatacaptures.NewChangeDataCaptureReconciler(instance, mgr.GetClient()).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create change data capture reconciler")
+		return err
+	}
+
 	setupLog.Info("starting external server manager")
 	if err := externalservers.NewReconciler(instance, mgr.GetClient()).
 		SetupWithManager(mgr); err != nil {
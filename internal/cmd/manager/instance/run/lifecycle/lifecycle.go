@@ -24,6 +24,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -76,6 +77,10 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 		// will contain any error returned by the process.
 		postMasterErrChan := i.runPostgresAndWait(ctx)
 
+		// This channel is closed when PostgreSQL can be started
+		// again after an unexpected exit of the postmaster
+		var postMasterRestartChan <-chan struct{}
+
 	signalLoop:
 		for {
 			contextLogger.Debug("starting signal loop")
@@ -89,7 +94,8 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 				// 2 - a postmaster child has crashed, and postmaster decided to fly away
 				//
 				// In this case we want to terminate the instance manager and let the Kubelet
-				// restart the Pod.
+				// restart the Pod, unless the restarts of the postmaster are managed by
+				// the instance manager itself.
 				if err != nil {
 					var exitError *exec.ExitError
 					if !errors.As(err, &exitError) {
@@ -99,8 +105,20 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 					}
 				}
 				if !i.instance.MightBeUnavailable() {
-					return err
+					restartChan, handled := i.instance.RecordPostmasterExit(ctx, err)
+					if !handled {
+						return err
+					}
+					// The postmaster channel is closed, we stop listening to it
+					// until PostgreSQL is started again
+					postMasterErrChan = nil
+					postMasterRestartChan = restartChan
 				}
+
+			case <-postMasterRestartChan:
+				contextLogger.Info("Restarting PostgreSQL after an unexpected exit")
+				break signalLoop
+
 			case <-ctx.Done():
 				// The controller manager asked us to terminate our operations.
 				// We shut down PostgreSQL and terminate using the smart
@@ -111,6 +129,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 					return nil
 				}
 				contextLogger.Info("Context has been cancelled, shutting down and exiting")
+				i.runPreStopHooks(ctx)
 				if err := i.instance.TryShuttingDownSmartFast(ctx); err != nil {
 					contextLogger.Error(err, "error shutting down instance, proceeding")
 				}
@@ -124,7 +143,9 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 				contextLogger.Info("Received termination signal",
 					"signal", sig,
 					"smartShutdownTimeout", i.instance.SmartStopDelay,
+					"fastShutdownTimeout", i.instance.FastStopDelay,
 				)
+				i.runPreStopHooks(ctx)
 				if err := i.instance.TryShuttingDownSmartFast(ctx); err != nil {
 					contextLogger.Error(err, "error while shutting down instance, proceeding")
 				}
@@ -152,3 +173,9 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 		// process
 	}
 }
+
+// runPreStopHooks executes the preStop lifecycle hooks, even when
+// the passed context has already been cancelled
+func (i *PostgresLifecycle) runPreStopHooks(ctx context.Context) {
+	i.instance.RunLifecycleHooks(context.WithoutCancel(ctx), apiv1.LifecycleHookPreStop)
+}
@@ -88,6 +88,17 @@ func (i *PostgresLifecycle) runPostgresAndWait(ctx context.Context) <-chan error
 			return
 		}
 
+		// A data directory failing the startup consistency checks is
+		// quarantined: the instance manager keeps running to report
+		// it, but PostgreSQL is not started
+		if i.instance.RunStartupCheck(postgresContext) {
+			contextLogger.Warning("Instance is quarantined, won't start postgres")
+			<-postgresContext.Done()
+			return
+		}
+
+		i.instance.RunLifecycleHooks(postgresContext, apiv1.LifecycleHookPreStart)
+
 		i.instance.LogPgControldata(postgresContext, "postmaster start up")
 		defer i.instance.LogPgControldata(postgresContext, "postmaster has exited")
 
@@ -97,6 +108,7 @@ func (i *PostgresLifecycle) runPostgresAndWait(ctx context.Context) <-chan error
 			errChan <- err
 			return
 		}
+		i.instance.NotifyPostmasterStarted()
 
 		// Now we'll wait for PostgreSQL to accept connections, and setup everything required
 		// for replication and pg_rewind to work correctly.
@@ -108,6 +120,7 @@ func (i *PostgresLifecycle) runPostgresAndWait(ctx context.Context) <-chan error
 				errChan <- err
 				return
 			}
+			runPostStartHooks(postgresContext, i.instance)
 		}()
 
 		// From now on the instance can be checked for readiness. This is
@@ -121,6 +134,20 @@ func (i *PostgresLifecycle) runPostgresAndWait(ctx context.Context) <-chan error
 	return errChan
 }
 
+// runPostStartHooks executes the postStart lifecycle hooks as soon
+// as PostgreSQL accepts connections, which is recorded to classify a
+// subsequent exit of the postmaster
+func runPostStartHooks(ctx context.Context, instance *postgres.Instance) {
+	if err := instance.WaitForSuperuserConnectionAvailable(ctx); err != nil {
+		log.FromContext(ctx).Info("PostgreSQL is not accepting connections, skipping the postStart hooks",
+			"err", err)
+		return
+	}
+	instance.NotifyPostmasterAcceptingConnections()
+
+	instance.RunLifecycleHooks(ctx, apiv1.LifecycleHookPostStart)
+}
+
 // ConfigureInstancePermissions creates the expected users and databases in a new
 // PostgreSQL instance
 func configureInstancePermissions(ctx context.Context, instance *postgres.Instance) error {
@@ -147,6 +174,11 @@ func configureInstancePermissions(ctx context.Context, instance *postgres.Instan
 	contextLogger.Debug("Verifying connection to DB")
 	err = instance.WaitForSuperuserConnectionAvailable(ctx)
 	if err != nil {
+		if ctx.Err() != nil {
+			// The postmaster exited before accepting connections,
+			// and the lifecycle loop is handling that
+			return fmt.Errorf("while waiting for the instance to accept connections: %w", err)
+		}
 		contextLogger.Error(err, "DB not available")
 		os.Exit(1)
 	}
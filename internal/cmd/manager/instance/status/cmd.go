@@ -42,7 +42,7 @@ func NewCmd() *cobra.Command {
 }
 
 func statusSubCommand() error {
-	statusURL := url.Local(url.PathPgStatus, url.StatusPort)
+	statusURL := url.Local(url.Versioned(url.PathPgStatus), url.StatusPort)
 	resp, err := http.Get(statusURL) // nolint:gosec
 	if err != nil {
 		log.Error(err, "Error while requesting instance status")
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topology implement the "instance topology" subcommand
+// of the operator
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// requestTimeout is the time given to the request to the instance manager
+const requestTimeout = 10 * time.Second
+
+// NewCmd create the "instance topology" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Print the position of the instance in the replication topology",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return topologySubCommand(cmd.Context())
+		},
+	}
+
+	return cmd
+}
+
+func topologySubCommand(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		url.Local(url.Versioned(url.PathPgTopology), url.StatusPort),
+		nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Error(err, "Error while requesting the instance topology")
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body webserver.Response[postgres.InstanceTopology]
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("while decoding the instance topology: %w", err)
+	}
+	if err := body.EnsureDataIsPresent(); err != nil {
+		return err
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(body.Data)
+}
@@ -0,0 +1,450 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	pgUpgradeName     = "pg_upgrade"
+	pgControlDataName = "pg_controldata"
+
+	// newDirectorySuffix is the suffix of the directories
+	// where the upgraded data directory is created
+	newDirectorySuffix = "-new"
+
+	// oldDirectorySuffix is the suffix of the directories where
+	// the old data directory is moved before being removed
+	oldDirectorySuffix = "-old"
+
+	// swapMarkerFileName is the name of the file, created next to the
+	// data directory, marking that pg_upgrade completed and the old data
+	// directory is being replaced with the upgraded one
+	swapMarkerFileName = "cnpg-major-upgrade-swap"
+
+	// terminationMessagePath is where the upgrade container writes
+	// the message read by the operator once it terminates
+	terminationMessagePath = "/dev/termination-log"
+
+	checksumVersionKey = "Data page checksum version"
+	walSegmentSizeKey  = "Bytes per WAL segment"
+)
+
+// serverOptions are the options used to start the PostgreSQL servers
+// during the upgrade. The configuration written by the operator refers
+// to files, like the server certificates, that are not available in the job
+const serverOptions = "-c ssl=off -c archive_mode=off -c logging_collector=off"
+
+// configurationFiles are the files managed by the operator
+// that are copied into the upgraded data directory
+var configurationFiles = []string{
+	constants.PostgresqlCustomConfigurationFile,
+	constants.PostgresqlOverrideConfigurationFile,
+	constants.PostgresqlHBARulesFile,
+	constants.PostgresqlIdentFile,
+	"postgresql.auto.conf",
+}
+
+// upgradeInfo contains the information needed
+// to upgrade a data directory
+type upgradeInfo struct {
+	pgData      string
+	pgWal       string
+	oldBinaries string
+	initDBFlags []string
+}
+
+// newExecuteCmd creates the "instance upgrade execute" command, which is
+// executed with the new PostgreSQL image and upgrades the data directory
+// using the binaries copied by the prepare command
+func newExecuteCmd() *cobra.Command {
+	var pgData string
+	var pgWal string
+	var oldBinaries string
+	var initDBFlagsString string
+
+	cmd := &cobra.Command{
+		Use:   "execute [options]",
+		Short: "Upgrade the data directory to the PostgreSQL major version of the current image",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			initDBFlags, err := shellquote.Split(initDBFlagsString)
+			if err != nil {
+				log.Error(err, "Error while parsing initdb flags")
+				return err
+			}
+
+			info := upgradeInfo{
+				pgData:      pgData,
+				pgWal:       pgWal,
+				oldBinaries: oldBinaries,
+				initDBFlags: initDBFlags,
+			}
+
+			return info.execute(cmd.Context())
+		},
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be upgraded")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL to be upgraded")
+	cmd.Flags().StringVar(&oldBinaries, "old-binaries", "", "The directory containing "+
+		"the binaries of the old PostgreSQL version, as populated by the prepare command")
+	cmd.Flags().StringVar(&initDBFlagsString, "initdb-flags", "", "The list of flags to be passed "+
+		"to initdb while creating the upgraded data directory")
+
+	return cmd
+}
+
+// execute upgrades the data directory, restoring the old one if the
+// upgrade fails. Once pg_upgrade has completed, the old data directory
+// can't be restored anymore: a failure while replacing it with the
+// upgraded one is reported to the operator, and the replacement is
+// completed by the next execution
+func (info upgradeInfo) execute(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	swapping, err := fileutils.FileExists(info.swapMarker())
+	if err != nil {
+		return err
+	}
+	if swapping {
+		contextLogger.Info("Completing the replacement of the data directory started by a previous upgrade")
+		return info.completeSwap(ctx)
+	}
+
+	if err := info.upgrade(ctx); err != nil {
+		contextLogger.Error(err, "Major upgrade failed, restoring the old data directory")
+		if rollbackErr := info.rollback(); rollbackErr != nil {
+			contextLogger.Error(rollbackErr, "Error while restoring the old data directory")
+		}
+		return err
+	}
+
+	if _, err := fileutils.WriteStringToFile(info.swapMarker(), info.pgData); err != nil {
+		contextLogger.Error(err, "Error while marking the upgrade as completed, restoring the old data directory")
+		if rollbackErr := info.rollback(); rollbackErr != nil {
+			contextLogger.Error(rollbackErr, "Error while restoring the old data directory")
+		}
+		return err
+	}
+
+	return info.completeSwap(ctx)
+}
+
+// completeSwap replaces the old data directory with the upgraded one,
+// telling the operator that the data directory has already been
+// upgraded if this fails
+func (info upgradeInfo) completeSwap(ctx context.Context) error {
+	err := info.swapDirectories(ctx)
+	if err == nil {
+		return nil
+	}
+
+	log.FromContext(ctx).Error(err, "Error while replacing the old data directory with the upgraded one")
+	if writeErr := os.WriteFile(
+		terminationMessagePath,
+		[]byte(specs.MajorUpgradeDataDirectoryUpgradedMessage),
+		0o600,
+	); writeErr != nil {
+		log.FromContext(ctx).Error(writeErr, "Error while writing the termination message")
+	}
+	return err
+}
+
+// swapMarker is the location of the file marking that the old data
+// directory is being replaced with the upgraded one
+func (info upgradeInfo) swapMarker() string {
+	return filepath.Join(filepath.Dir(info.pgData), swapMarkerFileName)
+}
+
+// newPgData is the location of the upgraded data directory
+func (info upgradeInfo) newPgData() string {
+	return info.pgData + newDirectorySuffix
+}
+
+// newPgWal is the location of the upgraded WAL directory,
+// if a separate WAL storage is used
+func (info upgradeInfo) newPgWal() string {
+	if info.pgWal == "" {
+		return ""
+	}
+	return info.pgWal + newDirectorySuffix
+}
+
+// upgrade creates a new data directory and upgrades the
+// old one into it using pg_upgrade in link mode
+func (info upgradeInfo) upgrade(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	oldBinDir, err := readOldBinDir(info.oldBinaries)
+	if err != nil {
+		return err
+	}
+
+	newBinDirs, err := getPgConfigDirectories("--bindir")
+	if err != nil {
+		return err
+	}
+	newBinDir := newBinDirs[0]
+
+	oldControlData, err := getControlData(oldBinDir, info.pgData)
+	if err != nil {
+		return err
+	}
+
+	// Remove any leftover of a previous attempt
+	if err := info.removeNewDirectories(); err != nil {
+		return err
+	}
+
+	initInfo := postgres.InitInfo{
+		PgData:        info.newPgData(),
+		PgWal:         info.newPgWal(),
+		InitDBOptions: buildInitDBOptions(info.initDBFlags, oldControlData),
+	}
+	if err := initInfo.CreateDataDirectory(); err != nil {
+		return err
+	}
+
+	newControlData, err := getControlData(newBinDir, info.newPgData())
+	if err != nil {
+		return err
+	}
+	if err := checkDataChecksums(oldControlData, newControlData); err != nil {
+		return err
+	}
+
+	contextLogger.Info("Upgrading the data directory",
+		"oldBinDir", oldBinDir,
+		"newBinDir", newBinDir,
+		"pgdata", info.pgData)
+
+	pgUpgradeCmd := exec.Command( // #nosec G204
+		filepath.Join(newBinDir, pgUpgradeName),
+		"--link",
+		"--old-bindir", oldBinDir,
+		"--new-bindir", newBinDir,
+		"--old-datadir", info.pgData,
+		"--new-datadir", info.newPgData(),
+		"--old-options", serverOptions,
+		"--new-options", serverOptions,
+	)
+	// pg_upgrade needs a writable working directory for its logs
+	pgUpgradeCmd.Dir = filepath.Dir(info.pgData)
+	if err := execlog.RunStreaming(pgUpgradeCmd, pgUpgradeName); err != nil {
+		return fmt.Errorf("while upgrading the data directory: %w", err)
+	}
+
+	for _, file := range configurationFiles {
+		source := filepath.Join(info.pgData, file)
+		exists, err := fileutils.FileExists(source)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := fileutils.CopyFile(source, filepath.Join(info.newPgData(), file)); err != nil {
+			return fmt.Errorf("while copying %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// rollback removes the upgraded data directory and makes
+// the old one usable again
+func (info upgradeInfo) rollback() error {
+	if err := info.removeNewDirectories(); err != nil {
+		return err
+	}
+
+	// In link mode, pg_upgrade renames the control file of the old data
+	// directory when it starts linking the files into the new one
+	pgControl := filepath.Join(info.pgData, "global", "pg_control")
+	pgControlOld := pgControl + ".old"
+	exists, err := fileutils.FileExists(pgControlOld)
+	if err != nil || !exists {
+		return err
+	}
+
+	return os.Rename(pgControlOld, pgControl)
+}
+
+func (info upgradeInfo) removeNewDirectories() error {
+	if err := os.RemoveAll(info.newPgData()); err != nil {
+		return err
+	}
+
+	if info.newPgWal() != "" {
+		return os.RemoveAll(info.newPgWal())
+	}
+
+	return nil
+}
+
+// swapDirectories replaces the old data directory with the upgraded one.
+// Every step can be repeated, so that a replacement interrupted by an
+// error can be completed running it again
+func (info upgradeInfo) swapDirectories(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Info("Replacing the old data directory with the upgraded one", "pgdata", info.pgData)
+
+	if err := swapDirectory(info.pgData, info.newPgData()); err != nil {
+		return err
+	}
+
+	if info.pgWal != "" {
+		if err := swapDirectory(info.pgWal, info.newPgWal()); err != nil {
+			return err
+		}
+
+		walLink := filepath.Join(info.pgData, "pg_wal")
+		if target, err := os.Readlink(walLink); err != nil || target != info.pgWal {
+			if err := os.RemoveAll(walLink); err != nil {
+				return err
+			}
+			if err := os.Symlink(info.pgWal, walLink); err != nil {
+				return err
+			}
+		}
+
+		if err := os.RemoveAll(info.pgWal + oldDirectorySuffix); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(info.pgData + oldDirectorySuffix); err != nil {
+		return err
+	}
+
+	return fileutils.RemoveFile(info.swapMarker())
+}
+
+// swapDirectory moves the upgraded directory in place of the passed
+// one, which is moved aside with the old directory suffix
+func swapDirectory(current, upgraded string) error {
+	upgradedExists, err := fileutils.FileExists(upgraded)
+	if err != nil {
+		return err
+	}
+	currentExists, err := fileutils.FileExists(current)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !upgradedExists && currentExists:
+		// The upgraded directory has already been moved in place
+		return nil
+	case !upgradedExists:
+		return fmt.Errorf("neither %s nor %s exist", current, upgraded)
+	case currentExists:
+		if err := os.Rename(current, current+oldDirectorySuffix); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(upgraded, current)
+}
+
+// getControlData runs the pg_controldata executable inside
+// the passed bindir against the passed data directory
+func getControlData(binDir, pgData string) (map[string]string, error) {
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	pgControlDataCmd := exec.Command(filepath.Join(binDir, pgControlDataName), "-D", pgData) // #nosec G204
+	pgControlDataCmd.Stdout = &stdoutBuffer
+	pgControlDataCmd.Stderr = &stderrBuffer
+	pgControlDataCmd.Env = append(pgControlDataCmd.Env, "LANG=C", "LC_MESSAGES=C")
+	if err := pgControlDataCmd.Run(); err != nil {
+		return nil, fmt.Errorf("while reading the control data of %s: %w (%s)",
+			pgData, err, stderrBuffer.String())
+	}
+
+	return utils.ParsePgControldataOutput(stdoutBuffer.String()), nil
+}
+
+// buildInitDBOptions returns the initdb options to be used to create the
+// upgraded data directory, enforcing the data checksums and the WAL
+// segment size of the old one, as pg_upgrade requires them to match
+func buildInitDBOptions(initDBFlags []string, oldControlData map[string]string) []string {
+	options := make([]string, 0, len(initDBFlags)+2)
+	for idx := 0; idx < len(initDBFlags); idx++ {
+		flag := initDBFlags[idx]
+		switch {
+		case flag == "-k", flag == "--data-checksums", flag == "--no-data-checksums":
+			continue
+		case flag == "--wal-segsize":
+			idx++
+			continue
+		case strings.HasPrefix(flag, "--wal-segsize="):
+			continue
+		}
+		options = append(options, flag)
+	}
+
+	if checksumVersion := oldControlData[checksumVersionKey]; checksumVersion != "" && checksumVersion != "0" {
+		options = append(options, "--data-checksums")
+	}
+
+	if walSegmentSize, err := strconv.Atoi(oldControlData[walSegmentSizeKey]); err == nil && walSegmentSize > 0 {
+		options = append(options, fmt.Sprintf("--wal-segsize=%d", walSegmentSize/(1024*1024)))
+	}
+
+	return options
+}
+
+// checkDataChecksums ensures that the data checksums are
+// enabled in both data directories or in none of them
+func checkDataChecksums(oldControlData, newControlData map[string]string) error {
+	oldEnabled := oldControlData[checksumVersionKey] != "0"
+	newEnabled := newControlData[checksumVersionKey] != "0"
+	if oldEnabled != newEnabled {
+		return errors.New("data checksums settings of the old and the upgraded data directories don't match")
+	}
+
+	return nil
+}
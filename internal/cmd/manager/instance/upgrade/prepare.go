@@ -0,0 +1,178 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// pgConfigName is the name of the pg_config executable
+	pgConfigName = "pg_config"
+
+	// oldBinDirFile is the name of the file, inside the directory containing the
+	// binaries of the old PostgreSQL version, where the location of bindir is stored
+	oldBinDirFile = "bindir"
+)
+
+// newPrepareCmd creates the "instance upgrade prepare" command, which is
+// executed with the old PostgreSQL image to copy its binaries in a location
+// where they can be used by pg_upgrade
+func newPrepareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prepare [target]",
+		Short: "Copy the PostgreSQL binaries of the current image inside the target directory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return prepareSubCommand(cmd.Context(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+func prepareSubCommand(ctx context.Context, target string) error {
+	contextLogger := log.FromContext(ctx)
+
+	directories, err := getPgConfigDirectories("--bindir", "--pkglibdir", "--sharedir")
+	if err != nil {
+		return err
+	}
+
+	// PostgreSQL finds its libraries and shared files using paths
+	// relative to the location of its binaries, so we preserve the
+	// whole directory layout inside the target directory
+	for _, directory := range directories {
+		destination := filepath.Join(target, directory)
+		contextLogger.Info("Copying PostgreSQL files", "source", directory, "destination", destination)
+		if err := copyDirectory(directory, destination); err != nil {
+			return fmt.Errorf("while copying %s: %w", directory, err)
+		}
+	}
+
+	_, err = fileutils.WriteStringToFile(
+		filepath.Join(target, oldBinDirFile),
+		filepath.Join(target, directories[0]))
+	return err
+}
+
+// getPgConfigDirectories returns the directories reported by
+// pg_config for the passed options, in the same order
+func getPgConfigDirectories(options ...string) ([]string, error) {
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	pgConfigCmd := exec.Command(pgConfigName, options...) // #nosec G204
+	pgConfigCmd.Stdout = &stdoutBuffer
+	pgConfigCmd.Stderr = &stderrBuffer
+	if err := pgConfigCmd.Run(); err != nil {
+		return nil, fmt.Errorf("while executing %s: %w (%s)", pgConfigName, err, stderrBuffer.String())
+	}
+
+	directories := strings.Split(strings.TrimSpace(stdoutBuffer.String()), "\n")
+	if len(directories) != len(options) {
+		return nil, fmt.Errorf("unexpected %s output: %s", pgConfigName, stdoutBuffer.String())
+	}
+
+	return directories, nil
+}
+
+// readOldBinDir reads the location of the binaries of the old
+// PostgreSQL version, as written by the prepare command
+func readOldBinDir(oldBinaries string) (string, error) {
+	content, err := fileutils.ReadFile(filepath.Join(oldBinaries, oldBinDirFile))
+	if err != nil {
+		return "", fmt.Errorf("while reading the location of the old binaries: %w", err)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// copyDirectory copies the content of the source directory inside the
+// destination one, preserving permissions and symbolic links
+func copyDirectory(source, destination string) error {
+	return filepath.WalkDir(source, func(sourcePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, sourcePath)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(destination, relativePath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(destinationPath, info.Mode().Perm()|0o700)
+
+		case info.Mode()&fs.ModeSymlink != 0:
+			linkTarget, err := os.Readlink(sourcePath)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(linkTarget, destinationPath)
+
+		default:
+			return copyFileWithMode(sourcePath, destinationPath, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFileWithMode(source, destination string, mode fs.FileMode) (err error) {
+	in, err := os.Open(source) // #nosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeError := in.Close()
+		if err == nil && closeError != nil {
+			err = closeError
+		}
+	}()
+
+	out, err := os.OpenFile(filepath.Clean(destination), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeError := out.Close()
+		if err == nil && closeError != nil {
+			err = closeError
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
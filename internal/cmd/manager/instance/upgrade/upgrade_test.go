@@ -0,0 +1,172 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("initdb options", func() {
+	It("enforces the data checksums and the WAL segment size of the old data directory", func() {
+		options := buildInitDBOptions(
+			[]string{"--encoding=UTF8", "--wal-segsize", "64", "--lc-collate=C"},
+			map[string]string{
+				checksumVersionKey: "1",
+				walSegmentSizeKey:  "16777216",
+			})
+		Expect(options).To(Equal([]string{
+			"--encoding=UTF8",
+			"--lc-collate=C",
+			"--data-checksums",
+			"--wal-segsize=16",
+		}))
+	})
+
+	It("doesn't enable the data checksums if they were disabled", func() {
+		options := buildInitDBOptions(
+			[]string{"-k", "--wal-segsize=64"},
+			map[string]string{
+				checksumVersionKey: "0",
+				walSegmentSizeKey:  "67108864",
+			})
+		Expect(options).To(Equal([]string{"--wal-segsize=64"}))
+	})
+})
+
+var _ = Describe("data checksums validation", func() {
+	It("accepts matching settings", func() {
+		Expect(checkDataChecksums(
+			map[string]string{checksumVersionKey: "1"},
+			map[string]string{checksumVersionKey: "1"},
+		)).To(Succeed())
+	})
+
+	It("rejects different settings", func() {
+		Expect(checkDataChecksums(
+			map[string]string{checksumVersionKey: "1"},
+			map[string]string{checksumVersionKey: "0"},
+		)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("data directories", func() {
+	var info upgradeInfo
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		info = upgradeInfo{
+			pgData: filepath.Join(tempDir, "pgdata"),
+			pgWal:  filepath.Join(tempDir, "pg_wal"),
+		}
+
+		Expect(os.MkdirAll(filepath.Join(info.pgData, "global"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(info.pgWal, 0o700)).To(Succeed())
+		Expect(os.Symlink(info.pgWal, filepath.Join(info.pgData, "pg_wal"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(info.pgData, "PG_VERSION"), []byte("15"), 0o600)).To(Succeed())
+
+		Expect(os.MkdirAll(filepath.Join(info.newPgData(), "global"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(info.newPgWal(), 0o700)).To(Succeed())
+		Expect(os.Symlink(info.newPgWal(), filepath.Join(info.newPgData(), "pg_wal"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(info.newPgData(), "PG_VERSION"), []byte("16"), 0o600)).To(Succeed())
+	})
+
+	It("are restored when the upgrade fails", func() {
+		pgControl := filepath.Join(info.pgData, "global", "pg_control")
+		Expect(os.WriteFile(pgControl+".old", []byte("control"), 0o600)).To(Succeed())
+
+		Expect(info.rollback()).To(Succeed())
+		Expect(pgControl).To(BeAnExistingFile())
+		Expect(pgControl + ".old").ToNot(BeAnExistingFile())
+		Expect(info.newPgData()).ToNot(BeADirectory())
+		Expect(info.newPgWal()).ToNot(BeADirectory())
+	})
+
+	It("are swapped when the upgrade succeeds", func(ctx SpecContext) {
+		Expect(info.swapDirectories(ctx)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.pgData, "PG_VERSION"))).To(BeEquivalentTo("16"))
+		Expect(os.Readlink(filepath.Join(info.pgData, "pg_wal"))).To(Equal(info.pgWal))
+		Expect(info.newPgData()).ToNot(BeADirectory())
+		Expect(info.newPgWal()).ToNot(BeADirectory())
+		Expect(info.pgData + oldDirectorySuffix).ToNot(BeADirectory())
+		Expect(info.pgWal + oldDirectorySuffix).ToNot(BeADirectory())
+	})
+
+	It("completes a replacement interrupted after moving the old data directory aside", func(ctx SpecContext) {
+		Expect(os.WriteFile(info.swapMarker(), []byte(info.pgData), 0o600)).To(Succeed())
+		Expect(os.Rename(info.pgData, info.pgData+oldDirectorySuffix)).To(Succeed())
+
+		Expect(info.execute(ctx)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.pgData, "PG_VERSION"))).To(BeEquivalentTo("16"))
+		Expect(os.Readlink(filepath.Join(info.pgData, "pg_wal"))).To(Equal(info.pgWal))
+		Expect(info.newPgData()).ToNot(BeADirectory())
+		Expect(info.newPgWal()).ToNot(BeADirectory())
+		Expect(info.pgData + oldDirectorySuffix).ToNot(BeADirectory())
+		Expect(info.pgWal + oldDirectorySuffix).ToNot(BeADirectory())
+		Expect(info.swapMarker()).ToNot(BeAnExistingFile())
+	})
+
+	It("completes a replacement interrupted while replacing the WAL directory", func(ctx SpecContext) {
+		Expect(os.WriteFile(info.swapMarker(), []byte(info.pgData), 0o600)).To(Succeed())
+		Expect(swapDirectory(info.pgData, info.newPgData())).To(Succeed())
+		Expect(os.Rename(info.pgWal, info.pgWal+oldDirectorySuffix)).To(Succeed())
+
+		Expect(info.execute(ctx)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.pgData, "PG_VERSION"))).To(BeEquivalentTo("16"))
+		Expect(os.Readlink(filepath.Join(info.pgData, "pg_wal"))).To(Equal(info.pgWal))
+		Expect(info.newPgWal()).ToNot(BeADirectory())
+		Expect(info.pgWal + oldDirectorySuffix).ToNot(BeADirectory())
+		Expect(info.swapMarker()).ToNot(BeAnExistingFile())
+	})
+
+	It("fails when neither the old nor the upgraded data directory exist", func() {
+		Expect(os.RemoveAll(info.newPgData())).To(Succeed())
+		Expect(os.RemoveAll(info.pgData)).To(Succeed())
+		Expect(swapDirectory(info.pgData, info.newPgData())).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("binaries copy", func() {
+	It("preserves the permissions and the symbolic links", func() {
+		source := GinkgoT().TempDir()
+		destination := filepath.Join(GinkgoT().TempDir(), "old")
+
+		Expect(os.MkdirAll(filepath.Join(source, "bin"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(source, "bin", "postgres"), []byte("binary"), 0o755)).To(Succeed())
+		Expect(os.Symlink("postgres", filepath.Join(source, "bin", "postmaster"))).To(Succeed())
+
+		Expect(copyDirectory(source, destination)).To(Succeed())
+
+		info, err := os.Stat(filepath.Join(destination, "bin", "postgres"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o755)))
+		Expect(os.Readlink(filepath.Join(destination, "bin", "postmaster"))).To(Equal("postgres"))
+	})
+
+	It("reads the location of the old binaries", func() {
+		oldBinaries := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(oldBinaries, oldBinDirFile), []byte("/controller/old/bin\n"), 0o600)).
+			To(Succeed())
+		Expect(readOldBinDir(oldBinaries)).To(Equal("/controller/old/bin"))
+	})
+})
@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package verifybackup implements the "instance verify-backup" subcommand
+// of the operator
+package verifybackup
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// NewCmd creates the "verify-backup" subcommand
+func NewCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+	var pgData string
+	var pgWal string
+	var backupName string
+
+	cmd := &cobra.Command{
+		Use:           "verify-backup [flags]",
+		Short:         "Restore a backup in scratch space and check it is usable",
+		SilenceErrors: true,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return management.WaitKubernetesAPIServer(cmd.Context(), ctrl.ObjectKey{
+				Name:      clusterName,
+				Namespace: namespace,
+			})
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			info := postgres.InitInfo{
+				ClusterName: clusterName,
+				Namespace:   namespace,
+				PgData:      pgData,
+				PgWal:       pgWal,
+			}
+
+			if err := info.VerifyPGData(); err != nil {
+				return err
+			}
+
+			if err := info.VerifyBackup(cmd.Context(), backupName); err != nil {
+				log.Error(err, "Error while verifying the backup", "backup", backupName)
+				return err
+			}
+
+			log.Info("Backup verified", "backup", backupName)
+			return nil
+		},
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"cluster the backup belongs to")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and the backup in k8s")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The scratch PGDATA where the "+
+		"backup is restored")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The scratch PGWAL where the backup is restored")
+	cmd.Flags().StringVar(&backupName, "backup-name", "", "The name of the backup to be verified")
+	_ = cmd.MarkFlagRequired("backup-name")
+
+	return cmd
+}
@@ -18,10 +18,13 @@ limitations under the License.
 package walarchive
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -30,6 +33,7 @@ import (
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
@@ -39,7 +43,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -95,27 +101,16 @@ func NewCmd() *cobra.Command {
 					contextLog.Error(err, logErrorMessage)
 				}
 
-				condition := metav1.Condition{
-					Type:    string(apiv1.ConditionContinuousArchiving),
-					Status:  metav1.ConditionFalse,
-					Reason:  string(apiv1.ConditionReasonContinuousArchivingFailing),
-					Message: err.Error(),
-				}
-				if errCond := conditions.Patch(ctx, typedClient, cluster, &condition); errCond != nil {
-					log.Error(errCond, "Error changing wal archiving condition (wal archiving failed)")
-				}
+				patchContinuousArchivingCondition(ctx, typedClient, cluster, "", err)
 				return err
 			}
 
 			// Update the condition if needed.
-			condition := metav1.Condition{
-				Type:    string(apiv1.ConditionContinuousArchiving),
-				Status:  metav1.ConditionTrue,
-				Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
-				Message: "Continuous archiving is working",
-			}
-			if errCond := conditions.Patch(ctx, typedClient, cluster, &condition); errCond != nil {
-				log.Error(errCond, "Error changing wal archiving condition (wal archiving succeeded)")
+			patchContinuousArchivingCondition(ctx, typedClient, cluster, "", nil)
+
+			if err := archiveWALToAdditionalObjectStores(ctx, typedClient, podName, pgData, cluster, args[0]); err != nil {
+				contextLog.Error(err, logErrorMessage)
+				return err
 			}
 
 			return nil
@@ -134,11 +129,10 @@ func run(
 	cluster *apiv1.Cluster,
 	args []string,
 ) error {
-	startTime := time.Now()
 	contextLog := log.FromContext(ctx)
 	walName := args[0]
 
-	if cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled {
+	if cluster.IsReplica() {
 		if podName != cluster.Status.CurrentPrimary && podName != cluster.Status.TargetPrimary {
 			contextLog.Debug("WAL archiving on a replica cluster, "+
 				"but this node is not the target primary nor the current one. "+
@@ -159,6 +153,10 @@ func run(
 		return errSwitchoverInProgress
 	}
 
+	if err := checkInjectedWALArchiveFault(ctx); err != nil {
+		return err
+	}
+
 	// Request the plugins to archive this WAL
 	if err := archiveWALViaPlugins(ctx, cluster, path.Join(pgData, walName)); err != nil {
 		return err
@@ -175,20 +173,73 @@ func run(
 		return nil
 	}
 
+	return archiveWALViaBarmanCloud(ctx, pgData, cluster, walName, "", cluster.Spec.Backup.BarmanObjectStore)
+}
+
+// archiveWALToAdditionalObjectStores archives the passed WAL file on every
+// additional object store, updating the condition reporting the archiving
+// status of each of them. The WAL file is considered archived only when every
+// object store has it, so an error is returned if any of them fails
+func archiveWALToAdditionalObjectStores(
+	ctx context.Context,
+	cli client.Client,
+	podName, pgData string,
+	cluster *apiv1.Cluster,
+	walName string,
+) error {
+	// The WAL file has not been archived on the main object store
+	// by this instance, and neither it will be on the additional ones
+	if cluster.Spec.Backup == nil || cluster.Status.CurrentPrimary != podName {
+		return nil
+	}
+
+	var result error
+	for i := range cluster.Spec.Backup.AdditionalBarmanObjectStores {
+		objectStore := &cluster.Spec.Backup.AdditionalBarmanObjectStores[i]
+		err := archiveWALViaBarmanCloud(
+			ctx,
+			pgData,
+			cluster,
+			walName,
+			objectStore.Name,
+			&objectStore.BarmanObjectStoreConfiguration,
+		)
+		if err != nil {
+			err = fmt.Errorf("while archiving on object store %s: %w", objectStore.Name, err)
+			result = errors.Join(result, err)
+		}
+		patchContinuousArchivingCondition(ctx, cli, cluster, objectStore.Name, err)
+	}
+
+	return result
+}
+
+// archiveWALViaBarmanCloud archives the passed WAL file on an object store
+// using Barman Cloud, together with the other WAL files ready to be archived
+// that fit into the configured parallelism. The name of the object store is
+// empty for the one defined in `barmanObjectStore`
+func archiveWALViaBarmanCloud(
+	ctx context.Context,
+	pgData string,
+	cluster *apiv1.Cluster,
+	walName string,
+	objectStoreName string,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+) error {
+	startTime := time.Now()
+	contextLog := log.FromContext(ctx).WithValues("objectStoreName", objectStoreName)
+
 	// Get environment from cache
-	env, err := cacheClient.GetEnv(cache.WALArchiveKey)
+	env, err := cacheClient.GetEnv(cache.GetWALArchiveKey(objectStoreName))
 	if err != nil {
 		return fmt.Errorf("failed to get envs: %w", err)
 	}
 
-	maxParallel := 1
-	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
-		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
-	}
-
-	// Create the archiver
+	// Create the archiver. Every object store has its own spool, as
+	// the pre-archived WAL files are different for each of them
 	var walArchiver *archiver.WALArchiver
-	if walArchiver, err = archiver.New(ctx, cluster, env, SpoolDirectory, pgData); err != nil {
+	if walArchiver, err = archiver.New(
+		ctx, cluster, env, getSpoolDirectory(objectStoreName), pgData); err != nil {
 		return fmt.Errorf("while creating the archiver: %w", err)
 	}
 
@@ -206,17 +257,27 @@ func run(
 		return nil
 	}
 
+	// Step 2: tune the parallelism on the number of WAL files waiting
+	// to be archived
+	backlog, err := countReadyWALFiles(pgData)
+	if err != nil {
+		contextLog.Debug("Cannot count the WAL files waiting to be archived", "err", err)
+	}
+	maxParallel := configuration.Wal.GetArchiveParallelism(backlog)
+
 	// Step 3: gather the WAL files names to archive
 	walFilesList := gatherWALFilesToArchive(ctx, walName, maxParallel)
 
-	// Step 4: Check if the archive location is safe to perform archiving
-	if utils.IsEmptyWalArchiveCheckEnabled(&cluster.ObjectMeta) {
+	// Step 4: Check if the archive location is safe to perform archiving.
+	// This is only done for the main object store, as it is the one
+	// used to bootstrap new clusters from this one
+	if objectStoreName == "" && utils.IsEmptyWalArchiveCheckEnabled(&cluster.ObjectMeta) {
 		if err := checkWalArchive(ctx, cluster, walArchiver, pgData); err != nil {
 			return err
 		}
 	}
 
-	options, err := barmanCloudWalArchiveOptions(cluster, cluster.Name)
+	options, err := barmanCloudWalArchiveOptions(configuration, cluster.Name)
 	if err != nil {
 		return err
 	}
@@ -224,14 +285,18 @@ func run(
 	// Step 5: archive the WAL files in parallel
 	uploadStartTime := time.Now()
 	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options)
+	uploadTotalTime := time.Since(uploadStartTime)
 	if len(walStatus) > 1 {
 		contextLog.Info("Completed archive command (parallel)",
 			"walsCount", len(walStatus),
 			"startTime", startTime,
 			"uploadStartTime", uploadStartTime,
-			"uploadTotalTime", time.Since(uploadStartTime),
+			"uploadTotalTime", uploadTotalTime,
 			"totalTime", time.Since(startTime))
 	}
+	if objectStoreName == "" {
+		reportWALArchiveBatch(ctx, newWALArchiveBatch(walStatus, maxParallel, uploadTotalTime))
+	}
 
 	// We return only the first error to PostgreSQL, because the first error
 	// is the one raised by the file that PostgreSQL has requested to archive.
@@ -240,6 +305,178 @@ func run(
 	return walStatus[0].Err
 }
 
+// getSpoolDirectory gets the spool directory used for the object store
+// with the passed name
+func getSpoolDirectory(objectStoreName string) string {
+	if objectStoreName == "" {
+		return SpoolDirectory
+	}
+	return SpoolDirectory + "-" + objectStoreName
+}
+
+// patchContinuousArchivingCondition sets the condition reporting the
+// status of the WAL archiving on the object store with the passed name
+// according to the outcome of the archive command
+func patchContinuousArchivingCondition(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	objectStoreName string,
+	archiveErr error,
+) {
+	condition := metav1.Condition{
+		Type:    apiv1.GetContinuousArchivingConditionType(objectStoreName),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
+		Message: "Continuous archiving is working",
+	}
+	if archiveErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonContinuousArchivingFailing)
+		condition.Message = archiveErr.Error()
+	}
+
+	if errCond := conditions.Patch(ctx, cli, cluster, &condition); errCond != nil {
+		log.Error(errCond, "Error changing wal archiving condition",
+			"objectStoreName", objectStoreName,
+			"archivingSucceeded", archiveErr == nil)
+	}
+}
+
+// countReadyWALFiles counts the WAL files waiting to be archived
+func countReadyWALFiles(pgData string) (int, error) {
+	entries, err := os.ReadDir(path.Join(pgData, "pg_wal", "archive_status"))
+	if err != nil {
+		return 0, err
+	}
+
+	ready := 0
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".ready") {
+			ready++
+		}
+	}
+	return ready, nil
+}
+
+// newWALArchiveBatch summarizes the outcome of the archiving of a list
+// of WAL files
+func newWALArchiveBatch(
+	walStatus []archiver.WALArchiverResult,
+	maxParallel int,
+	uploadTime time.Duration,
+) postgres.WALArchiveBatch {
+	batch := postgres.WALArchiveBatch{
+		Parallelism:   min(len(walStatus), max(maxParallel, 1)),
+		UploadSeconds: uploadTime.Seconds(),
+	}
+	for _, result := range walStatus {
+		if result.Err != nil {
+			batch.Failed++
+		} else {
+			batch.Archived++
+		}
+	}
+	return batch
+}
+
+// reportWALArchiveBatch sends the outcome of the archive command to the
+// instance manager, which keeps the archiving statistics. Failures are
+// just logged, as they must not prevent the WAL files from being archived
+func reportWALArchiveBatch(ctx context.Context, batch postgres.WALArchiveBatch) {
+	const reportTimeout = 5 * time.Second
+
+	contextLog := log.FromContext(ctx)
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		contextLog.Debug("Cannot encode the WAL archive statistics", "err", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reportTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		url.Local(url.Versioned(url.PathPgWALArchiveStatus), url.LocalPort),
+		bytes.NewReader(body))
+	if err != nil {
+		contextLog.Debug("Cannot build the WAL archive statistics request", "err", err)
+		return
+	}
+	if err := localauth.Authorize(req); err != nil {
+		contextLog.Debug("Cannot authorize the WAL archive statistics request", "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		contextLog.Debug("Cannot report the WAL archive statistics", "err", err)
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+// checkInjectedWALArchiveFault returns an error if the WAL archiving fault
+// has been injected in the instance manager. This can only happen when the
+// fault injection is enabled, and the instance manager can't be reached
+// otherwise, so any failure asking for the faults is just logged
+func checkInjectedWALArchiveFault(ctx context.Context) error {
+	const requestTimeout = 2 * time.Second
+
+	if !postgres.IsFaultInjectionEnabled() {
+		return nil
+	}
+
+	contextLog := log.FromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		url.Local(url.Versioned(url.PathFaults), url.LocalPort),
+		nil)
+	if err != nil {
+		contextLog.Debug("Cannot build the injected faults request", "err", err)
+		return nil
+	}
+	if err := localauth.Authorize(req); err != nil {
+		contextLog.Debug("Cannot authorize the injected faults request", "err", err)
+		return nil
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		contextLog.Debug("Cannot get the injected faults", "err", err)
+		return nil
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// The response of the local webserver, which can't be
+	// imported here without an import cycle
+	var body struct {
+		Data *[]postgres.Fault `json:"data,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Data == nil {
+		contextLog.Debug("Cannot decode the injected faults", "err", err)
+		return nil
+	}
+
+	for _, fault := range *body.Data {
+		if fault.Type == postgres.FaultTypeWALArchive {
+			return fmt.Errorf("injected fault: %s", fault.Type)
+		}
+	}
+	return nil
+}
+
 // archiveWALViaPlugins requests every capable plugin to archive the passed
 // WAL file, and returns an error if a configured plugin fails to do so.
 // It will not return an error if there's no plugin capable of WAL archiving
@@ -334,19 +571,18 @@ func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, paral
 }
 
 func barmanCloudWalArchiveOptions(
-	cluster *apiv1.Cluster,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
 	capabilities, err := barmanCapabilities.CurrentCapabilities()
 	if err != nil {
 		return nil, err
 	}
-	configuration := cluster.Spec.Backup.BarmanObjectStore
 
 	var options []string
 	if configuration.Wal != nil {
-		if configuration.Wal.Compression == apiv1.CompressionTypeSnappy && !capabilities.HasSnappy {
-			return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
+		if err := capabilities.CheckCompression(configuration.Wal.Compression); err != nil {
+			return nil, err
 		}
 		if len(configuration.Wal.Compression) != 0 {
 			options = append(
@@ -18,9 +18,12 @@ limitations under the License.
 package walrestore
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"strings"
@@ -33,7 +36,10 @@ import (
 	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -48,6 +54,10 @@ var (
 	// an external cluster which is not defined. This should be prevented
 	// from the validation webhook
 	ErrExternalClusterNotFound = errors.New("external cluster not found")
+
+	// ErrStreamingPreferred is returned when most of the recent fetches from
+	// the object store failed, to let PostgreSQL switch to streaming replication
+	ErrStreamingPreferred = errors.New("object store failing, streaming replication preferred")
 )
 
 const (
@@ -83,6 +93,11 @@ func NewCmd() *cobra.Command {
 					"end-of-wal-stream flag found." +
 						"Exiting with error once to let Postgres try switching to streaming replication")
 				return err
+			case errors.Is(err, ErrStreamingPreferred):
+				contextLog.Info(
+					"Most of the recent fetches from the object store failed. " +
+						"Exiting with error to let Postgres receive the WAL via streaming replication")
+				return err
 			default:
 				contextLog.Info("wal-restore command failed", "error", err)
 			}
@@ -165,13 +180,23 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 
 	// Step 2: return error if the end-of-wal-stream flag is set.
 	// We skip this step if streaming connection is not available
-	if isStreamingAvailable(cluster, podName) {
+	streamingAvailable := isStreamingAvailable(cluster, podName)
+	if streamingAvailable {
 		if err := checkEndOfWALStreamFlag(walRestorer); err != nil {
 			return err
 		}
 	}
 
-	// Step 3: gather the WAL files names to restore. If the required file isn't a regular WAL, we download it directly.
+	// Step 3: skip the object store when the instance manager, scoring the
+	// recent fetches, prefers streaming replication. The files that are not
+	// regular WAL files, like the timeline history ones, are always fetched
+	if streamingAvailable && postgres.IsWALFile(walName) &&
+		getWALRestoreMode(ctx) == postgres.WALRestoreModeStreaming {
+		reportWALRestoreResult(ctx, postgres.WALRestoreResult{Skipped: true, StreamingAvailable: true})
+		return ErrStreamingPreferred
+	}
+
+	// Step 4: gather the WAL files names to restore. If the required file isn't a regular WAL, we download it directly.
 	var walFilesList []string
 	maxParallel := 1
 	if barmanConfiguration.Wal != nil && barmanConfiguration.Wal.MaxParallel > 1 {
@@ -187,9 +212,10 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 		walFilesList = []string{walName}
 	}
 
-	// Step 4: download the WAL files into the required place
+	// Step 5: download the WAL files into the required place
 	downloadStartTime := time.Now()
 	walStatus := walRestorer.RestoreList(ctx, walFilesList, destinationPath, options)
+	reportWALRestoreResult(ctx, newWALRestoreResult(walStatus, streamingAvailable))
 
 	// We return immediately if the first WAL has errors, because the first WAL
 	// is the one that PostgreSQL has requested to restore.
@@ -198,10 +224,10 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 		return walStatus[0].Err
 	}
 
-	// Step 5: set end-of-wal-stream flag if any download job returned file-not-found
+	// Step 6: set end-of-wal-stream flag if any download job returned file-not-found
 	// We skip this step if streaming connection is not available
 	endOfWALStream := isEndOfWALStream(walStatus)
-	if isStreamingAvailable(cluster, podName) && endOfWALStream {
+	if streamingAvailable && endOfWALStream {
 		contextLog.Info(
 			"Set end-of-wal-stream flag as one of the WAL files to be prefetched was not found")
 
@@ -283,6 +309,109 @@ func isEndOfWALStream(results []restorer.Result) bool {
 	return false
 }
 
+// newWALRestoreResult summarizes the outcome of the restore of a list
+// of WAL files
+func newWALRestoreResult(walStatus []restorer.Result, streamingAvailable bool) postgres.WALRestoreResult {
+	result := postgres.WALRestoreResult{StreamingAvailable: streamingAvailable}
+	for _, status := range walStatus {
+		switch {
+		case status.Err == nil:
+			result.Restored++
+		case errors.Is(status.Err, restorer.ErrWALNotFound):
+			result.NotFound++
+		default:
+			result.Failed++
+			if result.Error == "" {
+				result.Error = status.Err.Error()
+			}
+		}
+	}
+	return result
+}
+
+// getWALRestoreMode asks the instance manager where the WAL files should
+// be fetched from. The object store is used when the instance manager
+// can't be reached
+func getWALRestoreMode(ctx context.Context) postgres.WALRestoreMode {
+	const requestTimeout = 2 * time.Second
+
+	contextLog := log.FromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		url.Local(url.Versioned(url.PathPgWALRestoreStatus), url.LocalPort),
+		nil)
+	if err != nil {
+		contextLog.Debug("Cannot build the WAL restore status request", "err", err)
+		return postgres.WALRestoreModeArchive
+	}
+	if err := localauth.Authorize(req); err != nil {
+		contextLog.Debug("Cannot authorize the WAL restore status request", "err", err)
+		return postgres.WALRestoreModeArchive
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		contextLog.Debug("Cannot get the WAL restore status", "err", err)
+		return postgres.WALRestoreModeArchive
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body webserver.Response[postgres.WALRestoreStatus]
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Data == nil {
+		contextLog.Debug("Cannot decode the WAL restore status", "err", err)
+		return postgres.WALRestoreModeArchive
+	}
+
+	return body.Data.Mode
+}
+
+// reportWALRestoreResult sends the outcome of the restore command to the
+// instance manager, which scores the object store. Failures are just
+// logged, as they must not prevent the WAL files from being restored
+func reportWALRestoreResult(ctx context.Context, result postgres.WALRestoreResult) {
+	const reportTimeout = 5 * time.Second
+
+	contextLog := log.FromContext(ctx)
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		contextLog.Debug("Cannot encode the WAL restore result", "err", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reportTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		url.Local(url.Versioned(url.PathPgWALRestoreStatus), url.LocalPort),
+		bytes.NewReader(body))
+	if err != nil {
+		contextLog.Debug("Cannot build the WAL restore result request", "err", err)
+		return
+	}
+	if err := localauth.Authorize(req); err != nil {
+		contextLog.Debug("Cannot authorize the WAL restore result request", "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		contextLog.Debug("Cannot report the WAL restore result", "err", err)
+		return
+	}
+	_ = resp.Body.Close()
+}
+
 // mergeEnv merges all the values inside incomingEnv into env
 func mergeEnv(env []string, incomingEnv []string) {
 	for _, incomingItem := range incomingEnv {
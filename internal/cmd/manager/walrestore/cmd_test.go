@@ -17,7 +17,11 @@ limitations under the License.
 package walrestore
 
 import (
+	"errors"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -108,3 +112,21 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function newWALRestoreResult", func() {
+	It("counts the restored, missing and failed WAL files", func() {
+		result := newWALRestoreResult([]restorer.Result{
+			{WalName: "000000010000000000000001"},
+			{WalName: "000000010000000000000002", Err: errors.New("connection reset")},
+			{WalName: "000000010000000000000003", Err: errors.New("access denied")},
+			{WalName: "000000010000000000000004", Err: restorer.ErrWALNotFound},
+		}, true)
+		Expect(result).To(Equal(postgres.WALRestoreResult{
+			Restored:           1,
+			Failed:             2,
+			NotFound:           1,
+			StreamingAvailable: true,
+			Error:              "connection reset",
+		}))
+	})
+})
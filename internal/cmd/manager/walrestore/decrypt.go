@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walrestore
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewDecryptCmd creates the command decrypting in place a WAL file
+// restored by barman-cloud-wal-restore, as done while recovering
+// from a backup, when the instance manager is not running yet
+func NewDecryptCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:           "wal-decrypt [path]",
+		SilenceErrors: true,
+		Hidden:        true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			contextLog := log.WithName("wal-decrypt")
+
+			keys, err := encryption.FromEnv(os.Environ())
+			if err != nil {
+				contextLog.Error(err, "while loading the WAL encryption keys")
+				return err
+			}
+
+			if err := encryption.DecryptFile(keys, args[0]); err != nil {
+				contextLog.Error(err, "while decrypting the WAL file", "path", args[0])
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return &cmd
+}
@@ -43,6 +43,9 @@ type backupCommandOptions struct {
 	online              *bool
 	immediateCheckpoint *bool
 	waitForArchive      *bool
+	pluginName          string
+	pluginParameters    map[string]string
+	objectStoreName     string
 }
 
 func (options backupCommandOptions) getOnlineConfiguration() *apiv1.OnlineConfiguration {
@@ -56,9 +59,33 @@ func (options backupCommandOptions) getOnlineConfiguration() *apiv1.OnlineConfig
 	return onlineConfiguration
 }
 
+func (options backupCommandOptions) getPluginConfiguration() *apiv1.BackupPluginConfiguration {
+	if options.pluginName == "" {
+		return nil
+	}
+	return &apiv1.BackupPluginConfiguration{
+		Name:       options.pluginName,
+		Parameters: options.pluginParameters,
+	}
+}
+
+// validatePluginOptions checks that the plugin options are set if and only
+// if the backup is requested to be taken by a plugin
+func validatePluginOptions(backupMethod, pluginName string, pluginParameters map[string]string) error {
+	if backupMethod == string(apiv1.BackupMethodPlugin) && pluginName == "" {
+		return fmt.Errorf("plugin-name is required when using the %s backup method", apiv1.BackupMethodPlugin)
+	}
+	if backupMethod != string(apiv1.BackupMethodPlugin) && (pluginName != "" || len(pluginParameters) > 0) {
+		return fmt.Errorf("plugin options can only be used with the %s backup method", apiv1.BackupMethodPlugin)
+	}
+	return nil
+}
+
 // NewCmd creates the new "backup" subcommand
 func NewCmd() *cobra.Command {
-	var backupName, backupTarget, backupMethod, online, immediateCheckpoint, waitForArchive string
+	var backupName, backupTarget, backupMethod, online, immediateCheckpoint, waitForArchive, pluginName string
+	var objectStoreName string
+	var pluginParameters map[string]string
 
 	backupSubcommand := &cobra.Command{
 		Use:   "backup [cluster]",
@@ -93,11 +120,21 @@ func NewCmd() *cobra.Command {
 				"",
 				string(apiv1.BackupMethodBarmanObjectStore),
 				string(apiv1.BackupMethodVolumeSnapshot),
+				string(apiv1.BackupMethodPlugin),
 			}
 			if !slices.Contains(allowedBackupMethods, backupMethod) {
 				return fmt.Errorf("backup-method: %s is not supported by the backup command", backupMethod)
 			}
 
+			if err := validatePluginOptions(backupMethod, pluginName, pluginParameters); err != nil {
+				return err
+			}
+
+			if objectStoreName != "" && backupMethod != "" &&
+				backupMethod != string(apiv1.BackupMethodBarmanObjectStore) {
+				return fmt.Errorf("object-store-name can be used only with the barmanObjectStore backup method")
+			}
+
 			var cluster apiv1.Cluster
 			// check if the cluster exists
 			err := plugin.Client.Get(
@@ -135,6 +172,9 @@ func NewCmd() *cobra.Command {
 					online:              parsedOnline,
 					immediateCheckpoint: parsedImmediateCheckpoint,
 					waitForArchive:      parsedWaitForArchive,
+					pluginName:          pluginName,
+					pluginParameters:    pluginParameters,
+					objectStoreName:     objectStoreName,
 				})
 		},
 	}
@@ -160,7 +200,7 @@ func NewCmd() *cobra.Command {
 		"m",
 		"",
 		"If present, will override the backup method defined in backup resource, "+
-			"valid values are volumeSnapshot and barmanObjectStore.",
+			"valid values are volumeSnapshot, barmanObjectStore and plugin.",
 	)
 
 	const optionalAcceptedValues = "Optional. Accepted values: true|false|\"\"."
@@ -186,6 +226,22 @@ func NewCmd() *cobra.Command {
 			optionalAcceptedValues,
 	)
 
+	backupSubcommand.Flags().StringVar(&pluginName, "plugin-name", "",
+		"The name of the plugin that should take the backup. "+
+			"Required when the plugin backup method is used.",
+	)
+
+	backupSubcommand.Flags().StringToStringVar(&pluginParameters, "plugin-parameters", nil,
+		"The parameters to be passed to the plugin taking the backup, "+
+			"in the key=value format. Can be used only with the plugin backup method.",
+	)
+
+	backupSubcommand.Flags().StringVar(&objectStoreName, "object-store-name", "",
+		"The name of the additional object store where the backup is taken. "+
+			"If not specified, the one in '.spec.backup.barmanObjectStore' "+
+			"will be used. Can be used only with the barmanObjectStore backup method.",
+	)
+
 	return backupSubcommand
 }
 
@@ -204,6 +260,8 @@ func createBackup(ctx context.Context, options backupCommandOptions) error {
 			Method:              options.method,
 			Online:              options.online,
 			OnlineConfiguration: options.getOnlineConfiguration(),
+			PluginConfiguration: options.getPluginConfiguration(),
+			ObjectStoreName:     options.objectStoreName,
 		},
 	}
 	utils.LabelClusterName(&backup.ObjectMeta, options.clusterName)
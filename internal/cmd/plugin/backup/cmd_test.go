@@ -0,0 +1,51 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("plugin backup options", func() {
+	It("requires a plugin name with the plugin method", func() {
+		Expect(validatePluginOptions(string(apiv1.BackupMethodPlugin), "", nil)).ToNot(Succeed())
+		Expect(validatePluginOptions(string(apiv1.BackupMethodPlugin), "cnpg-i-pgbackrest", nil)).To(Succeed())
+	})
+
+	It("rejects the plugin options with other methods", func() {
+		Expect(validatePluginOptions("", "cnpg-i-pgbackrest", nil)).ToNot(Succeed())
+		Expect(validatePluginOptions(string(apiv1.BackupMethodBarmanObjectStore), "",
+			map[string]string{"type": "full"})).ToNot(Succeed())
+		Expect(validatePluginOptions(string(apiv1.BackupMethodVolumeSnapshot), "", nil)).To(Succeed())
+	})
+
+	It("builds the plugin configuration of the backup", func() {
+		Expect(backupCommandOptions{}.getPluginConfiguration()).To(BeNil())
+
+		options := backupCommandOptions{
+			pluginName:       "cnpg-i-pgbackrest",
+			pluginParameters: map[string]string{"type": "full"},
+		}
+		Expect(options.getPluginConfiguration()).To(Equal(&apiv1.BackupPluginConfiguration{
+			Name:       "cnpg-i-pgbackrest",
+			Parameters: map[string]string{"type": "full"},
+		}))
+	})
+})
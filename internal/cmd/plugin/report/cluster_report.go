@@ -20,6 +20,7 @@ import (
 	"archive/zip"
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -30,6 +31,8 @@ import (
 
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -72,11 +75,49 @@ func (cr clusterReport) writeToZip(zipper *zip.Writer, format plugin.OutputForma
 	return nil
 }
 
+// writeConfigurationSnapshotsToZip adds the parsed pg_controldata output
+// and the non-default settings of each running instance to a new section
+// in the ZIP file. Instances that can't be queried are skipped, as they
+// must not prevent the report from being written
+func writeConfigurationSnapshotsToZip(
+	ctx context.Context,
+	pods []corev1.Pod,
+	format plugin.OutputFormat,
+	folder string,
+	zipper *zip.Writer,
+) error {
+	newFolder := filepath.Join(folder, "configuration")
+	if _, err := zipper.Create(newFolder + "/"); err != nil {
+		return err
+	}
+
+	for idx := range pods {
+		pod := pods[idx]
+		if pod.Labels[utils.PodRoleLabelName] != string(utils.PodRoleInstance) ||
+			pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		snapshot, err := resources.GetConfigurationSnapshot(ctx, plugin.Config, pod, specs.PostgresContainerName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping the configuration of pod %s: %v\n", pod.Name, err)
+			continue
+		}
+
+		if err := addContentToZip(snapshot, pod.Name, newFolder, format, zipper); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // cluster implements the "report cluster" subcommand
 // Produces a zip file containing
 //   - cluster pod and job definitions
 //   - cluster resource (same content as `kubectl get cluster -o yaml`)
 //   - events in the cluster namespace
+//   - pg_controldata output and non-default settings of the running instances
 //   - logs from the cluster pods (optional - activated with `includeLogs`)
 //   - logs from the cluster jobs (optional - activated with `includeLogs`)
 func cluster(ctx context.Context, clusterName, namespace string, format plugin.OutputFormat,
@@ -130,7 +171,11 @@ func cluster(ctx context.Context, clusterName, namespace string, format plugin.O
 		return rep.writeToZip(zipper, format, dirname)
 	}
 
-	sections := []zipFileWriter{reportZipper}
+	configurationZipper := func(zipper *zip.Writer, dirname string) error {
+		return writeConfigurationSnapshotsToZip(ctx, pods.Items, format, dirname, zipper)
+	}
+
+	sections := []zipFileWriter{reportZipper, configurationZipper}
 
 	if includeLogs {
 		logsZipper := func(zipper *zip.Writer, dirname string) error {
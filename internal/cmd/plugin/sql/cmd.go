@@ -0,0 +1,82 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// NewCmd creates the "sql" command
+func NewCmd() *cobra.Command {
+	var replica bool
+	var database string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "sql [cluster] [query]",
+		Short: "Run a read-only diagnostic query on a CloudNativePG cluster",
+		Long: `This command runs a read-only diagnostic query through the instance manager
+of a PostgreSQL Pod, reached with a port-forward instead of an exec into the Pod.
+The diagnostic queries must be enabled in the ".spec.diagnosticQueries" section
+of the cluster, and are executed by the unprivileged "cnpg_diagnostics" role,
+a member of "pg_monitor". Every query is logged by the instance manager.`,
+		Args: plugin.RequiresArguments(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd.Context(), Options{
+				ClusterName: args[0],
+				Replica:     replica,
+				Request: postgres.DiagnosticQueryRequest{
+					Database: database,
+					Query:    args[1],
+				},
+				Format: plugin.OutputFormat(output),
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&replica,
+		"replica",
+		false,
+		"Runs the query on the first replica on the pod list (by default runs it on the primary)",
+	)
+	cmd.Flags().StringVarP(
+		&database,
+		"dbname",
+		"d",
+		"postgres",
+		"The database where the query is executed",
+	)
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		"text",
+		"Output format. One of text|json|yaml",
+	)
+
+	return cmd
+}
@@ -0,0 +1,286 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// Options are the options of a diagnostic query
+type Options struct {
+	// The cluster name
+	ClusterName string
+
+	// Run the query on the first replica instead of the primary
+	Replica bool
+
+	// The query and the database where it is executed
+	Request postgres.DiagnosticQueryRequest
+
+	// The output format
+	Format plugin.OutputFormat
+}
+
+// Run executes a diagnostic query through the instance manager of the
+// target Pod, reached with a port-forward, and prints its result
+func Run(ctx context.Context, options Options) error {
+	pod, err := getTargetPod(ctx, options.ClusterName, options.Replica)
+	if err != nil {
+		return err
+	}
+
+	request := options.Request
+	request.Caller = getCaller(ctx)
+
+	result, err := runQuery(ctx, pod, request)
+	if err != nil {
+		return err
+	}
+
+	if options.Format != plugin.OutputFormatText {
+		return plugin.Print(result, options.Format, os.Stdout)
+	}
+
+	printResult(result, os.Stdout)
+	return nil
+}
+
+// getCaller gets the name of the Kubernetes user running the plugin,
+// which the instance manager logs together with the query as the claimed
+// caller. An empty name is returned when the API server can't tell it
+func getCaller(ctx context.Context) string {
+	review, err := plugin.ClientInterface.AuthenticationV1().SelfSubjectReviews().Create(
+		ctx,
+		&authenticationv1.SelfSubjectReview{},
+		metav1.CreateOptions{},
+	)
+	if err != nil {
+		return ""
+	}
+
+	return review.Status.UserInfo.Username
+}
+
+// getTargetPod gets the first Pod of the cluster with the required role
+func getTargetPod(ctx context.Context, clusterName string, replica bool) (*corev1.Pod, error) {
+	var pods corev1.PodList
+	if err := plugin.Client.List(
+		ctx,
+		&pods,
+		client.MatchingLabels{utils.ClusterLabelName: clusterName},
+		client.InNamespace(plugin.Namespace),
+	); err != nil {
+		return nil, err
+	}
+
+	targetPodRole := specs.ClusterRoleLabelPrimary
+	if replica {
+		targetPodRole = specs.ClusterRoleLabelReplica
+	}
+
+	for i := range pods.Items {
+		podRole, _ := utils.GetInstanceRole(pods.Items[i].Labels)
+		if podRole == targetPodRole {
+			return &pods.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot find Pod with role %q in cluster %s", targetPodRole, clusterName)
+}
+
+// getToken reads the token required by the diagnostics webserver
+// from the PostgreSQL container of the Pod
+func getToken(ctx context.Context, pod *corev1.Pod) (string, error) {
+	timeout := time.Second * 10
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		plugin.ClientInterface,
+		plugin.Config,
+		*pod,
+		specs.PostgresContainerName,
+		&timeout,
+		"cat", localauth.DiagnosticsTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("while reading the diagnostics token of %s: %w", pod.Name, err)
+	}
+
+	return strings.TrimSpace(stdout), nil
+}
+
+// runQuery forwards a local port to the diagnostics webserver of the
+// instance manager and sends it the query
+func runQuery(
+	ctx context.Context,
+	pod *corev1.Pod,
+	request postgres.DiagnosticQueryRequest,
+) (*postgres.DiagnosticQueryResult, error) {
+	token, err := getToken(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(plugin.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	portForwardURL := plugin.ClientInterface.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, portForwardURL)
+
+	stopChannel := make(chan struct{})
+	readyChannel := make(chan struct{})
+	defer close(stopChannel)
+
+	forwarder, err := portforward.New(
+		dialer,
+		[]string{fmt.Sprintf("0:%d", url.DiagnosticsPort)},
+		stopChannel,
+		readyChannel,
+		io.Discard,
+		os.Stderr,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	errChannel := make(chan error, 1)
+	go func() {
+		errChannel <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case err := <-errChannel:
+		return nil, fmt.Errorf("while forwarding the diagnostics port of %s: %w", pod.Name, err)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-readyChannel:
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return nil, err
+	}
+
+	return postQuery(ctx, url.Local(url.Versioned(url.PathPgQuery), int(ports[0].Local)), token, request)
+}
+
+// postQuery sends the query to the diagnostics webserver, authorized
+// by the passed token, and decodes its response
+func postQuery(
+	ctx context.Context,
+	queryURL string,
+	token string,
+	request postgres.DiagnosticQueryRequest,
+) (*postgres.DiagnosticQueryResult, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, queryURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var response webserver.Response[postgres.DiagnosticQueryResult]
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("while decoding the response (status %s): %w", resp.Status, err)
+	}
+
+	if response.Error != nil {
+		return nil, fmt.Errorf("%s: %s", response.Error.Code, response.Error.Message)
+	}
+	if err := response.EnsureDataIsPresent(); err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+// printResult prints the result of a query as a table, with
+// the NULL values printed as empty cells
+func printResult(result *postgres.DiagnosticQueryResult, writer io.Writer) {
+	table := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+
+	header := make([]interface{}, len(result.Columns))
+	for i, column := range result.Columns {
+		header[i] = column
+	}
+	table.AddHeader(header...)
+
+	for _, row := range result.Rows {
+		line := make([]interface{}, len(row))
+		for i, value := range row {
+			if value != nil {
+				line[i] = strings.ReplaceAll(*value, "\n", "\\n")
+			} else {
+				line[i] = ""
+			}
+		}
+		table.AddLine(line...)
+	}
+	table.Print()
+
+	rowsText := "rows"
+	if len(result.Rows) == 1 {
+		rowsText = "row"
+	}
+	_, _ = fmt.Fprintf(writer, "(%d %s)\n", len(result.Rows), rowsText)
+	if result.Truncated {
+		_, _ = fmt.Fprintln(writer, "The result has been truncated to the maximum number of rows allowed")
+	}
+}
@@ -61,6 +61,27 @@ type PostgresqlStatus struct {
 	// PodDisruptionBudgetList prints every PDB that matches against the cluster
 	// with the label selector
 	PodDisruptionBudgetList policyv1.PodDisruptionBudgetList
+
+	// PrimaryTopology is the position of the primary in the replication
+	// topology, including the replicas streaming from it. It is nil when
+	// the instance manager of the primary can't report it
+	PrimaryTopology *postgres.InstanceTopology `json:"primaryTopology,omitempty"`
+}
+
+// getReplicationInfo gets the replicas streaming from the primary, preferring
+// the ones reported by its topology, which are read in the same transaction
+// as its current WAL location
+func (fullStatus *PostgresqlStatus) getReplicationInfo() postgres.PgStatReplicationList {
+	if fullStatus.PrimaryTopology != nil && fullStatus.PrimaryTopology.IsPrimary {
+		return fullStatus.PrimaryTopology.Replicas
+	}
+
+	primary := fullStatus.tryGetPrimaryInstance()
+	if primary == nil {
+		return nil
+	}
+
+	return primary.ReplicationInfo
 }
 
 func (fullStatus *PostgresqlStatus) getReplicationSlotList() postgres.PgReplicationSlotList {
@@ -114,6 +135,10 @@ func Status(ctx context.Context, clusterName string, verbose bool, format plugin
 		if err != nil {
 			nonFatalError = err
 		}
+		err = status.printConfigurationSnapshot(ctx)
+		if err != nil {
+			nonFatalError = err
+		}
 	}
 	status.printCertificatesStatus()
 	status.printBackupStatus()
@@ -165,6 +190,17 @@ func ExtractPostgresqlStatus(ctx context.Context, clusterName string) (*Postgres
 		PrimaryPod:              primaryPod,
 		PodDisruptionBudgetList: pdbl,
 	}
+
+	// The instance managers not serving the topology yet are
+	// reported through the status of the instance only
+	if primaryPod.Name != "" {
+		status.PrimaryTopology, _ = resources.GetInstanceTopology(
+			ctx,
+			plugin.Config,
+			primaryPod,
+			specs.PostgresContainerName)
+	}
+
 	return &status, nil
 }
 
@@ -237,6 +273,11 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 		}
 	}
 
+	if len(cluster.Status.PendingRestartParameters) > 0 {
+		summary.AddLine("Pending restart parameters:",
+			aurora.Yellow(strings.Join(cluster.Status.PendingRestartParameters, ", ")))
+	}
+
 	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
 		if cluster.Status.CurrentPrimary == "" {
 			fmt.Println(aurora.Red("Primary server is initializing"))
@@ -292,7 +333,7 @@ func (fullStatus *PostgresqlStatus) getStatus(isPrimaryFenced bool, cluster *api
 	switch cluster.Status.Phase {
 	case apiv1.PhaseHealthy, apiv1.PhaseFirstPrimary, apiv1.PhaseCreatingReplica:
 		return fmt.Sprintf("%v %v", aurora.Green(cluster.Status.Phase), cluster.Status.PhaseReason)
-	case apiv1.PhaseUpgrade, apiv1.PhaseWaitingForUser:
+	case apiv1.PhaseUpgrade, apiv1.PhaseWaitingForUser, apiv1.PhaseWaitingForMaintenanceWindow:
 		return fmt.Sprintf("%v %v", aurora.Yellow(cluster.Status.Phase), cluster.Status.PhaseReason)
 	default:
 		return fmt.Sprintf("%v %v", aurora.Red(cluster.Status.Phase), cluster.Status.PhaseReason)
@@ -332,6 +373,73 @@ func (fullStatus *PostgresqlStatus) printPostgresConfiguration(ctx context.Conte
 	return nil
 }
 
+// controlDataSummaryFields are the pg_controldata fields shown by the
+// verbose status, in the order they are printed
+var controlDataSummaryFields = []string{
+	"Database system identifier",
+	"Database cluster state",
+	"pg_control last modified",
+	"Latest checkpoint location",
+	"Latest checkpoint's REDO location",
+	"Latest checkpoint's REDO WAL file",
+	"Latest checkpoint's TimeLineID",
+	"Time of latest checkpoint",
+	"wal_level setting",
+	"Data page checksum version",
+}
+
+// getControlDataSummary extracts the fields shown by the verbose status
+// from the parsed pg_controldata output, skipping the missing ones
+func getControlDataSummary(controlData map[string]string) [][2]string {
+	summary := make([][2]string, 0, len(controlDataSummaryFields))
+	for _, field := range controlDataSummaryFields {
+		if value, ok := controlData[field]; ok {
+			summary = append(summary, [2]string{field, value})
+		}
+	}
+
+	return summary
+}
+
+// printConfigurationSnapshot prints the pg_controldata summary and the
+// parameters not set to their default value of the primary instance
+func (fullStatus *PostgresqlStatus) printConfigurationSnapshot(ctx context.Context) error {
+	snapshot, err := resources.GetConfigurationSnapshot(
+		ctx,
+		plugin.Config,
+		fullStatus.PrimaryPod,
+		specs.PostgresContainerName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(aurora.Green("PostgreSQL Control Data"))
+	controlData := tabby.New()
+	for _, field := range getControlDataSummary(snapshot.ControlData) {
+		controlData.AddLine(field[0]+":", field[1])
+	}
+	controlData.Print()
+	fmt.Println()
+
+	fmt.Println(aurora.Green("PostgreSQL Settings (non-default)"))
+	settings := tabby.New()
+	settings.AddHeader("Name", "Setting", "Unit", "Source", "Default", "Pending Restart")
+	for _, setting := range snapshot.Settings {
+		settings.AddLine(
+			setting.Name,
+			setting.Setting,
+			setting.Unit,
+			setting.Source,
+			setting.BootValue,
+			setting.PendingRestart,
+		)
+	}
+	settings.Print()
+	fmt.Println()
+
+	return nil
+}
+
 func (fullStatus *PostgresqlStatus) printBackupStatus() {
 	cluster := fullStatus.Cluster
 
@@ -501,7 +609,8 @@ func (fullStatus *PostgresqlStatus) printReplicaStatus(verbose bool) {
 		return
 	}
 
-	if len(primaryInstanceStatus.ReplicationInfo) == 0 {
+	replicationInfo := fullStatus.getReplicationInfo()
+	if len(replicationInfo) == 0 {
 		fmt.Println(aurora.Yellow("Not available yet").String())
 		fmt.Println()
 		return
@@ -522,7 +631,6 @@ func (fullStatus *PostgresqlStatus) printReplicaStatus(verbose bool) {
 		}
 	}
 
-	replicationInfo := primaryInstanceStatus.ReplicationInfo
 	sort.Sort(replicationInfo)
 	for _, replication := range replicationInfo {
 		columns := []interface{}{
@@ -708,7 +816,8 @@ func getReplicaRole(instance postgres.PostgresqlStatus, fullStatus *PostgresqlSt
 		return "Unknown"
 	}
 
-	for _, state := range primaryInstanceStatus.ReplicationInfo {
+	replicationInfo := fullStatus.getReplicationInfo()
+	for _, state := range replicationInfo {
 		// todo: handle others states other than 'streaming'
 		if !(state.ApplicationName == instance.Pod.Name && state.State == "streaming") {
 			continue
@@ -726,7 +835,7 @@ func getReplicaRole(instance postgres.PostgresqlStatus, fullStatus *PostgresqlSt
 	// TODO: improve the way we detect a standby in a replica cluster.
 	// A fuller fix would make sure the Designated Primary gets the replication
 	// list from pg_stat_replication
-	if len(primaryInstanceStatus.ReplicationInfo) == 0 {
+	if len(replicationInfo) == 0 {
 		return "Standby (in Replica Cluster)"
 	}
 
@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -76,3 +77,54 @@ var _ = Describe("getPrimaryStartTime", func() {
 		})
 	})
 })
+
+var _ = Describe("getControlDataSummary", func() {
+	It("reports the known fields in order, skipping the missing ones", func() {
+		controlData := map[string]string{
+			"Time of latest checkpoint":      "Thu 15 Oct 2026 10:00:00 AM UTC",
+			"Database cluster state":         "in production",
+			"Latest checkpoint's TimeLineID": "2",
+			"Maximum data alignment":         "8",
+		}
+
+		Expect(getControlDataSummary(controlData)).To(Equal([][2]string{
+			{"Database cluster state", "in production"},
+			{"Latest checkpoint's TimeLineID", "2"},
+			{"Time of latest checkpoint", "Thu 15 Oct 2026 10:00:00 AM UTC"},
+		}))
+	})
+
+	It("is empty when pg_controldata reported nothing", func() {
+		Expect(getControlDataSummary(nil)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("getReplicationInfo", func() {
+	var fullStatus *PostgresqlStatus
+
+	BeforeEach(func() {
+		fullStatus = &PostgresqlStatus{
+			Cluster: &apiv1.Cluster{},
+			InstanceStatus: &postgres.PostgresqlStatusList{
+				Items: []postgres.PostgresqlStatus{{
+					IsPrimary:       true,
+					ReplicationInfo: postgres.PgStatReplicationList{{ApplicationName: "from-status"}},
+				}},
+			},
+		}
+	})
+
+	It("prefers the replicas reported by the topology of the primary", func() {
+		fullStatus.PrimaryTopology = &postgres.InstanceTopology{
+			IsPrimary: true,
+			Replicas:  postgres.PgStatReplicationList{{ApplicationName: "from-topology"}},
+		}
+		Expect(fullStatus.getReplicationInfo()).To(HaveLen(1))
+		Expect(fullStatus.getReplicationInfo()[0].ApplicationName).To(Equal("from-topology"))
+	})
+
+	It("falls back to the status of the primary without a topology", func() {
+		Expect(fullStatus.getReplicationInfo()).To(HaveLen(1))
+		Expect(fullStatus.getReplicationInfo()[0].ApplicationName).To(Equal("from-status"))
+	})
+})
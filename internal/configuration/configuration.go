@@ -19,6 +19,8 @@ limitations under the License.
 package configuration
 
 import (
+	"fmt"
+	"hash/fnv"
 	"path"
 	"strings"
 
@@ -106,9 +108,14 @@ type Data struct {
 	// Threshold to consider a certificate as expiring
 	ExpiringCheckThreshold int `json:"expiringCheckThreshold" env:"EXPIRING_CHECK_THRESHOLD"`
 
-	// CreateAnyService is true when the user wants the operator to create
-	// the <cluster-name>-any service. Defaults to false.
-	CreateAnyService bool `json:"createAnyService" env:"CREATE_ANY_SERVICE"`
+	// ShardCount is the number of shards the namespaces are split into,
+	// each one reconciled by a different operator deployment. Sharding is
+	// disabled when lower than 2
+	ShardCount int `json:"shardCount" env:"SHARD_COUNT"`
+
+	// ShardIndex is the shard reconciled by this operator deployment,
+	// from 0 to ShardCount-1
+	ShardIndex int `json:"shardIndex" env:"SHARD_INDEX"`
 }
 
 // Current is the configuration used by the operator
@@ -121,7 +128,6 @@ func newDefaultConfig() *Data {
 		OperatorImageName:      versions.DefaultOperatorImageName,
 		PostgresImageName:      versions.DefaultImageName,
 		PluginSocketDir:        DefaultPluginSocketDir,
-		CreateAnyService:       false,
 		CertificateDuration:    CertificateDuration,
 		ExpiringCheckThreshold: ExpiringCheckThreshold,
 	}
@@ -159,6 +165,51 @@ func (config *Data) WatchedNamespaces() []string {
 	return cleanNamespaceList(config.WatchNamespace)
 }
 
+// IsShardingEnabled returns true if the reconciliation of the namespaces
+// is split between multiple operator deployments
+func (config *Data) IsShardingEnabled() bool {
+	return config.ShardCount > 1
+}
+
+// ValidateSharding checks that the shard of this operator deployment
+// is one of the configured shards
+func (config *Data) ValidateSharding() error {
+	if !config.IsShardingEnabled() {
+		return nil
+	}
+
+	if config.ShardIndex < 0 || config.ShardIndex >= config.ShardCount {
+		return fmt.Errorf("invalid shard index %d, expected a value between 0 and %d",
+			config.ShardIndex, config.ShardCount-1)
+	}
+
+	return nil
+}
+
+// IsNamespaceInShard returns true if the objects in the passed namespace
+// are reconciled by this operator deployment. Namespaces are assigned to
+// the shards by hashing their names, so that every object of a cluster,
+// and every object referring to it, belongs to the same shard
+func (config *Data) IsNamespaceInShard(namespace string) bool {
+	if !config.IsShardingEnabled() {
+		return true
+	}
+
+	return GetNamespaceShard(namespace, config.ShardCount) == config.ShardIndex
+}
+
+// GetNamespaceShard returns the shard, between 0 and shardCount-1, the
+// passed namespace is assigned to
+func GetNamespaceShard(namespace string, shardCount int) int {
+	if shardCount <= 1 {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(namespace))
+	return int(hash.Sum32() % uint32(shardCount))
+}
+
 func cleanNamespaceList(namespaces string) (result []string) {
 	unfilteredList := strings.Split(namespaces, ",")
 	result = make([]string, 0, len(unfilteredList))
@@ -17,6 +17,8 @@ limitations under the License.
 package configuration
 
 import (
+	"fmt"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -128,3 +130,38 @@ var _ = Describe("Annotation and label inheritance", func() {
 		})
 	})
 })
+
+var _ = Describe("Sharding", func() {
+	It("is disabled by default", func() {
+		config := Data{}
+		Expect(config.IsShardingEnabled()).To(BeFalse())
+		Expect(config.ValidateSharding()).To(Succeed())
+		Expect(config.IsNamespaceInShard("default")).To(BeTrue())
+	})
+
+	It("validates the shard index", func() {
+		Expect((&Data{ShardCount: 3, ShardIndex: 2}).ValidateSharding()).To(Succeed())
+		Expect((&Data{ShardCount: 3, ShardIndex: 3}).ValidateSharding()).ToNot(Succeed())
+		Expect((&Data{ShardCount: 3, ShardIndex: -1}).ValidateSharding()).ToNot(Succeed())
+	})
+
+	It("assigns every namespace to exactly one shard", func() {
+		const shardCount = 4
+		assigned := make(map[int]int)
+		for i := 0; i < 100; i++ {
+			namespace := fmt.Sprintf("namespace-%d", i)
+			owners := 0
+			for index := 0; index < shardCount; index++ {
+				config := Data{ShardCount: shardCount, ShardIndex: index}
+				if config.IsNamespaceInShard(namespace) {
+					owners++
+					assigned[index]++
+				}
+			}
+			Expect(owners).To(Equal(1))
+		}
+
+		// Every shard gets a share of the namespaces
+		Expect(assigned).To(HaveLen(shardCount))
+	})
+})
@@ -19,6 +19,7 @@ limitations under the License.
 package cache
 
 import (
+	"strings"
 	"sync"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -35,6 +36,22 @@ const (
 
 var cache sync.Map
 
+// GetWALArchiveKey returns the key to be used to access the cached envs
+// for wal-archive on the object store with the passed name. The empty
+// name refers to the main object store
+func GetWALArchiveKey(objectStoreName string) string {
+	if objectStoreName == "" {
+		return WALArchiveKey
+	}
+	return WALArchiveKey + "-" + objectStoreName
+}
+
+// IsWALArchiveKey checks if the passed key refers to the cached envs
+// for wal-archive on any object store
+func IsWALArchiveKey(c string) bool {
+	return c == WALArchiveKey || strings.HasPrefix(c, WALArchiveKey+"-")
+}
+
 // Store write an object into the local cache
 func Store(c string, v interface{}) {
 	cache.Store(c, v)
@@ -45,6 +62,16 @@ func Delete(c string) {
 	cache.Delete(c)
 }
 
+// DeleteIf deletes every object whose key satisfies the passed condition
+func DeleteIf(condition func(c string) bool) {
+	cache.Range(func(key, _ interface{}) bool {
+		if c, ok := key.(string); ok && condition(c) {
+			cache.Delete(c)
+		}
+		return true
+	})
+}
+
 // LoadEnv loads a key from the local cache
 func LoadEnv(c string) ([]string, error) {
 	value, ok := cache.Load(c)
@@ -27,6 +27,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 )
 
@@ -79,7 +80,15 @@ func httpCacheGet(urlPath string) ([]byte, error) {
 }
 
 func get(urlPath string) ([]byte, error) {
-	resp, err := http.Get(url.Local(url.PathCache+urlPath, url.LocalPort))
+	req, err := http.NewRequest(http.MethodGet, url.Local(url.Versioned(url.PathCache+urlPath), url.LocalPort), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := localauth.Authorize(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcatalog
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// catalogRefreshInterval is the time between two refreshes of the backup
+// catalog that are not triggered by a backup
+const catalogRefreshInterval = 10 * time.Minute
+
+// A Refresher is a Kubernetes manager.Runnable that periodically reports
+// the content of the object store in the cluster status, so that the
+// catalog reflects the backups taken or removed outside the operator,
+// i.e. by the retention policy of another cluster or by hand
+type Refresher struct {
+	instance *postgres.Instance
+	client   client.Client
+}
+
+// NewRefresher creates a new backup catalog Refresher
+func NewRefresher(instance *postgres.Instance, client client.Client) *Refresher {
+	return &Refresher{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts running the Refresher
+func (r *Refresher) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("backup_catalog_refresher")
+
+	ticker := time.NewTicker(catalogRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			contextLog.Info("Terminated backup catalog refresher loop")
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := r.refresh(ctx); err != nil {
+			contextLog.Warning("while refreshing the backup catalog", "err", err)
+		}
+	}
+}
+
+// refresh reports the backup catalog in the cluster status when this
+// instance is the current primary, which is in charge of the backups
+func (r *Refresher) refresh(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := r.client.Get(ctx, types.NamespacedName{
+		Name:      r.instance.ClusterName,
+		Namespace: r.instance.Namespace,
+	}, &cluster); err != nil {
+		return err
+	}
+
+	if cluster.Status.CurrentPrimary != r.instance.PodName {
+		return nil
+	}
+
+	return postgres.RefreshBackupCatalog(ctx, r.client, &cluster)
+}
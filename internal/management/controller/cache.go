@@ -19,17 +19,43 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
+// RefreshCache reads the cluster and updates the internal cache with it,
+// without waiting for the next reconciliation loop. The credentials used
+// by the WAL archiver, the restorer and the running backups are read
+// again from their secrets
+func (r *InstanceReconciler) RefreshCache(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := r.GetClient().Get(ctx, client.ObjectKey{
+		Namespace: r.instance.Namespace,
+		Name:      r.instance.ClusterName,
+	}, &cluster); err != nil {
+		return fmt.Errorf("while getting cluster: %w", err)
+	}
+
+	cache.StoreCluster(&cluster)
+	if err := r.updateWALArchiveSettingsCache(ctx, &cluster); err != nil {
+		return fmt.Errorf("while getting backup credentials: %w", err)
+	}
+	r.updateWALRestoreSettingsCache(ctx, &cluster)
+	r.instance.RefreshBackupCredentials(ctx)
+
+	return nil
+}
+
 // updateCacheFromCluster will update the internal cache with the cluster
 //
 // returns true if the update was not total, and should be retried
@@ -45,6 +71,10 @@ func (r *InstanceReconciler) updateCacheFromCluster(ctx context.Context, cluster
 
 	// Populate the cache with the recover configuration
 	r.updateWALRestoreSettingsCache(ctx, cluster)
+
+	// Refresh the credentials of the running backups, which
+	// may have been rotated while they were running
+	r.instance.RefreshBackupCredentials(ctx)
 	return missingPermissions
 }
 
@@ -60,10 +90,19 @@ func (r *InstanceReconciler) updateWALRestoreSettingsCache(ctx context.Context,
 	}
 	env = append(env, os.Environ()...)
 
+	// The designated primary of a replica cluster restores the WAL files
+	// from the source, whose secrets may live in another namespace
+	secretsNamespace := cluster.Namespace
+	if cluster.IsReplica() && cluster.Status.CurrentPrimary == r.instance.PodName {
+		if server, ok := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source); ok {
+			secretsNamespace = server.GetSecretsNamespace(cluster.Namespace)
+		}
+	}
+
 	envRestore, err := barmanCredentials.EnvSetBackupCloudCredentials(
 		ctx,
 		r.GetClient(),
-		cluster.Namespace,
+		secretsNamespace,
 		barmanConfiguration,
 		env,
 	)
@@ -81,28 +120,76 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 ) (shouldRetry bool) {
+	err := r.updateWALArchiveSettingsCache(ctx, cluster)
+	if apierrors.IsForbidden(err) {
+		log.Info("backup credentials don't yet have access permissions. Will retry reconciliation loop")
+		return true
+	}
+
+	if err != nil {
+		log.Error(err, "while getting backup credentials")
+	}
+	return false
+}
+
+// updateWALArchiveSettingsCache updates the cache with the backup credentials
+// of every object store where the WAL files are archived. The cached
+// environment of an object store is left untouched if its credentials
+// can't be read
+func (r *InstanceReconciler) updateWALArchiveSettingsCache(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) error {
 	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
-		cache.Delete(cache.WALArchiveKey)
-		return false
+		cache.DeleteIf(cache.IsWALArchiveKey)
+		return nil
 	}
 
-	// Populate the cache with the backup configuration
+	// Remove the credentials of the object stores that are not
+	// configured anymore
+	configuredKeys := stringset.New()
+	configuredKeys.Put(cache.WALArchiveKey)
+	for _, objectStore := range cluster.Spec.Backup.AdditionalBarmanObjectStores {
+		configuredKeys.Put(cache.GetWALArchiveKey(objectStore.Name))
+	}
+	cache.DeleteIf(func(key string) bool {
+		return cache.IsWALArchiveKey(key) && !configuredKeys.Has(key)
+	})
+
+	result := r.updateObjectStoreSettingsCache(ctx, cluster, "", cluster.Spec.Backup.BarmanObjectStore)
+	for i := range cluster.Spec.Backup.AdditionalBarmanObjectStores {
+		objectStore := &cluster.Spec.Backup.AdditionalBarmanObjectStores[i]
+		if err := r.updateObjectStoreSettingsCache(
+			ctx,
+			cluster,
+			objectStore.Name,
+			&objectStore.BarmanObjectStoreConfiguration,
+		); err != nil {
+			result = errors.Join(result, fmt.Errorf("object store %s: %w", objectStore.Name, err))
+		}
+	}
+
+	return result
+}
+
+// updateObjectStoreSettingsCache updates the cache with the backup
+// credentials of the object store with the passed name
+func (r *InstanceReconciler) updateObjectStoreSettingsCache(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	objectStoreName string,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+) error {
 	envArchive, err := barmanCredentials.EnvSetBackupCloudCredentials(
 		ctx,
 		r.GetClient(),
 		cluster.Namespace,
-		cluster.Spec.Backup.BarmanObjectStore,
+		configuration,
 		os.Environ())
-	if apierrors.IsForbidden(err) {
-		log.Info("backup credentials don't yet have access permissions. Will retry reconciliation loop")
-		return true
-	}
-
 	if err != nil {
-		log.Error(err, "while getting backup credentials")
-		return false
+		return err
 	}
 
-	cache.Store(cache.WALArchiveKey, envArchive)
-	return false
+	cache.Store(cache.GetWALArchiveKey(objectStoreName), envArchive)
+	return nil
 }
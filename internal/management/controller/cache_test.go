@@ -0,0 +1,156 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archive settings cache", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		cluster    *apiv1.Cluster
+		secret     *corev1.Secret
+		cli        client.Client
+		reconciler *InstanceReconciler
+	)
+
+	awsCredentials := func(secretName string) apiv1.BarmanCredentials {
+		return apiv1.BarmanCredentials{
+			AWS: &apiv1.S3Credentials{
+				AccessKeyIDReference: &apiv1.SecretKeySelector{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: secretName},
+					Key:                  "ACCESS_KEY_ID",
+				},
+				SecretAccessKeyReference: &apiv1.SecretKeySelector{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: secretName},
+					Key:                  "ACCESS_SECRET_KEY",
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cache.DeleteIf(cache.IsWALArchiveKey)
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: namespace},
+			Data: map[string][]byte{
+				"ACCESS_KEY_ID":     []byte("old-key"),
+				"ACCESS_SECRET_KEY": []byte("old-secret"),
+			},
+		}
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath:   "s3://main/",
+						BarmanCredentials: awsCredentials("aws-creds"),
+					},
+				},
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, secret).
+			Build()
+
+		instance := postgres.NewInstance()
+		instance.Namespace = namespace
+		instance.ClusterName = cluster.Name
+		reconciler = &InstanceReconciler{client: cli, instance: instance}
+	})
+
+	AfterEach(func() {
+		cache.DeleteIf(cache.IsWALArchiveKey)
+	})
+
+	It("picks up the rotated credentials on refresh", func() {
+		Expect(reconciler.RefreshCache(ctx)).To(Succeed())
+		env, err := cache.LoadEnv(cache.WALArchiveKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ContainElement("AWS_ACCESS_KEY_ID=old-key"))
+
+		secret.Data["ACCESS_KEY_ID"] = []byte("new-key")
+		Expect(cli.Update(ctx, secret)).To(Succeed())
+
+		Expect(reconciler.RefreshCache(ctx)).To(Succeed())
+		env, err = cache.LoadEnv(cache.WALArchiveKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ContainElement("AWS_ACCESS_KEY_ID=new-key"))
+		Expect(env).ToNot(ContainElement("AWS_ACCESS_KEY_ID=old-key"))
+	})
+
+	It("caches the environment of every object store", func() {
+		cluster.Spec.Backup.AdditionalBarmanObjectStores = []apiv1.NamedBarmanObjectStoreConfiguration{
+			{
+				Name: "dr",
+				BarmanObjectStoreConfiguration: apiv1.BarmanObjectStoreConfiguration{
+					DestinationPath:   "s3://dr/",
+					BarmanCredentials: awsCredentials("aws-creds"),
+				},
+			},
+		}
+		Expect(reconciler.updateWALArchiveSettingsCache(ctx, cluster)).To(Succeed())
+		_, err := cache.LoadEnv(cache.GetWALArchiveKey("dr"))
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster.Spec.Backup.AdditionalBarmanObjectStores = nil
+		Expect(reconciler.updateWALArchiveSettingsCache(ctx, cluster)).To(Succeed())
+		_, err = cache.LoadEnv(cache.GetWALArchiveKey("dr"))
+		Expect(err).To(MatchError(cache.ErrCacheMiss))
+		_, err = cache.LoadEnv(cache.WALArchiveKey)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("keeps the previous environment when the credentials can't be read", func() {
+		Expect(reconciler.updateWALArchiveSettingsCache(ctx, cluster)).To(Succeed())
+
+		Expect(cli.Delete(ctx, secret)).To(Succeed())
+		Expect(reconciler.RefreshCache(ctx)).ToNot(Succeed())
+
+		env, err := cache.LoadEnv(cache.WALArchiveKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ContainElement("AWS_ACCESS_KEY_ID=old-key"))
+	})
+
+	It("removes the environment when the backup is not configured", func() {
+		Expect(reconciler.updateWALArchiveSettingsCache(ctx, cluster)).To(Succeed())
+
+		cluster.Spec.Backup = nil
+		Expect(reconciler.updateWALArchiveSettingsCache(ctx, cluster)).To(Succeed())
+		_, err := cache.LoadEnv(cache.WALArchiveKey)
+		Expect(err).To(MatchError(cache.ErrCacheMiss))
+	})
+})
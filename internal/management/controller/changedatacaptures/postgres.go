@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changedatacaptures
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// slotInfo is the information about a replication slot, as read from pg_replication_slots
+type slotInfo struct {
+	Plugin            sql.NullString
+	Database          sql.NullString
+	Active            bool
+	ConfirmedFlushLSN sql.NullString
+}
+
+// detectSlot reads the information about the passed replication slot from
+// the catalog, returning nil if it doesn't exist
+func detectSlot(ctx context.Context, db *sql.DB, name string) (*slotInfo, error) {
+	row := db.QueryRowContext(
+		ctx,
+		"SELECT plugin, database, active, confirmed_flush_lsn "+
+			"FROM pg_catalog.pg_replication_slots WHERE slot_name = $1",
+		name)
+
+	var info slotInfo
+	err := row.Scan(&info.Plugin, &info.Database, &info.Active, &info.ConfirmedFlushLSN)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while detecting replication slot %s: %w", name, err)
+	}
+
+	return &info, nil
+}
+
+// checkSlot verifies that an existing replication slot can be consumed
+// by the bridge publishing the changes of the passed database
+func checkSlot(name, dbname string, info *slotInfo) error {
+	if !info.Plugin.Valid {
+		return fmt.Errorf("replication slot %s is a physical replication slot", name)
+	}
+	if info.Plugin.String != "pgoutput" {
+		return fmt.Errorf("replication slot %s uses the %s plugin instead of pgoutput",
+			name, info.Plugin.String)
+	}
+	if info.Database.String != dbname {
+		return fmt.Errorf("replication slot %s belongs to database %s instead of %s",
+			name, info.Database.String, dbname)
+	}
+
+	return nil
+}
+
+// createSlot creates a logical replication slot using the pgoutput plugin.
+// When failover is true the slot is synchronized to the standbys, which
+// requires PostgreSQL 17
+func createSlot(ctx context.Context, db *sql.DB, name string, failover bool) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := "SELECT pg_catalog.pg_create_logical_replication_slot($1, 'pgoutput')"
+	if failover {
+		query = "SELECT pg_catalog.pg_create_logical_replication_slot($1, 'pgoutput', false, false, true)"
+	}
+
+	contextLogger.Info("Creating replication slot", "slotName", name, "failover", failover)
+	if _, err := db.ExecContext(ctx, query, name); err != nil {
+		return fmt.Errorf("while creating replication slot %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// dropSlot drops the replication slot with the passed name, if it exists,
+// terminating the walsender consuming it
+func dropSlot(ctx context.Context, db *sql.DB, name string) error {
+	contextLogger := log.FromContext(ctx)
+
+	contextLogger.Info("Dropping replication slot", "slotName", name)
+	if _, err := db.ExecContext(
+		ctx,
+		"SELECT pg_catalog.pg_terminate_backend(active_pid) "+
+			"FROM pg_catalog.pg_replication_slots WHERE slot_name = $1 AND active_pid IS NOT NULL",
+		name,
+	); err != nil {
+		return fmt.Errorf("while terminating the consumer of replication slot %s: %w", name, err)
+	}
+
+	if _, err := db.ExecContext(
+		ctx,
+		"SELECT pg_catalog.pg_drop_replication_slot(slot_name) "+
+			"FROM pg_catalog.pg_replication_slots WHERE slot_name = $1",
+		name,
+	); err != nil {
+		return fmt.Errorf("while dropping replication slot %s: %w", name, err)
+	}
+
+	return nil
+}
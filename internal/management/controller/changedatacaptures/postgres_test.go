@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changedatacaptures
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed replication slot SQL", func() {
+	const detectQuery = "SELECT plugin, database, active, confirmed_flush_lsn " +
+		"FROM pg_catalog.pg_replication_slots WHERE slot_name = $1"
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("detects a missing replication slot", func(ctx SpecContext) {
+		mock.ExpectQuery(detectQuery).WithArgs("cdc").
+			WillReturnRows(sqlmock.NewRows([]string{"plugin", "database", "active", "confirmed_flush_lsn"}))
+
+		info, err := detectSlot(ctx, db, "cdc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(BeNil())
+	})
+
+	It("detects an existing replication slot", func(ctx SpecContext) {
+		mock.ExpectQuery(detectQuery).WithArgs("cdc").
+			WillReturnRows(sqlmock.NewRows([]string{"plugin", "database", "active", "confirmed_flush_lsn"}).
+				AddRow("pgoutput", "app", true, "0/3000148"))
+
+		info, err := detectSlot(ctx, db, "cdc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).ToNot(BeNil())
+		Expect(info.Active).To(BeTrue())
+		Expect(info.ConfirmedFlushLSN.String).To(Equal("0/3000148"))
+		Expect(checkSlot("cdc", "app", info)).To(Succeed())
+		Expect(checkSlot("cdc", "other", info)).ToNot(Succeed())
+	})
+
+	It("refuses to use physical replication slots", func() {
+		info := &slotInfo{}
+		Expect(checkSlot("cdc", "app", info)).To(MatchError(ContainSubstring("physical")))
+	})
+
+	It("creates failover replication slots when supported", func(ctx SpecContext) {
+		mock.ExpectExec("SELECT pg_catalog.pg_create_logical_replication_slot($1, 'pgoutput')").
+			WithArgs("cdc").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("SELECT pg_catalog.pg_create_logical_replication_slot($1, 'pgoutput', false, false, true)").
+			WithArgs("cdc").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(createSlot(ctx, db, "cdc", false)).To(Succeed())
+		Expect(createSlot(ctx, db, "cdc", true)).To(Succeed())
+	})
+
+	It("terminates the consumer before dropping the replication slot", func(ctx SpecContext) {
+		mock.ExpectExec("SELECT pg_catalog.pg_terminate_backend(active_pid) " +
+			"FROM pg_catalog.pg_replication_slots WHERE slot_name = $1 AND active_pid IS NOT NULL").
+			WithArgs("cdc").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("SELECT pg_catalog.pg_drop_replication_slot(slot_name) " +
+			"FROM pg_catalog.pg_replication_slots WHERE slot_name = $1").
+			WithArgs("cdc").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(dropSlot(ctx, db, "cdc")).To(Succeed())
+	})
+})
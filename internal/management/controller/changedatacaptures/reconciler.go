@@ -0,0 +1,229 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changedatacaptures
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// changeDataCaptureReconciliationInterval is the time between two reconciliations
+// of the same ChangeDataCapture, used to refresh the position of the replication slot
+const changeDataCaptureReconciliationInterval = 30 * time.Second
+
+// Reconcile is the main reconciliation loop for the ChangeDataCapture objects
+func (r *ChangeDataCaptureReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("change_data_capture_reconciler").
+		WithValues("changeDataCapture", req.Name)
+	// if the context has already been cancelled,
+	// trying to reconcile would just lead to misleading errors being reported
+	if err := ctx.Err(); err != nil {
+		contextLogger.Warning("Context cancelled, will not start change data capture reconcile", "err", err)
+		return reconcile.Result{}, nil
+	}
+
+	var cdc apiv1.ChangeDataCapture
+	if err := r.GetClient().Get(ctx, req.NamespacedName, &cdc); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This ChangeDataCapture belongs to another cluster
+	if cdc.Spec.ClusterRef.Name != r.instance.ClusterName {
+		return reconcile.Result{}, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !isPrimary {
+		contextLogger.Debug("skipping the change data capture reconciler in replicas")
+		return reconcile.Result{RequeueAfter: changeDataCaptureReconciliationInterval}, nil
+	}
+
+	// Fetch the Cluster from the cache
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The cluster has been deleted.
+			// We just need to wait for this instance manager to be terminated
+			contextLogger.Debug("Could not find Cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	if cluster.IsReplica() {
+		contextLogger.Debug("skipping the change data capture reconciler in replica clusters")
+		return reconcile.Result{RequeueAfter: changeDataCaptureReconciliationInterval}, nil
+	}
+
+	if r.instance.IsServerReady() != nil {
+		contextLogger.Debug("database not ready, skipping change data capture reconciling")
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+
+	if err := r.reconcileFinalizer(ctx, &cdc); err != nil {
+		return reconcile.Result{}, err
+	}
+	if !cdc.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	origCDC := cdc.DeepCopy()
+	reconcileErr := r.reconcileSlot(ctx, &cdc)
+	cdc.Status.ObservedGeneration = cdc.Generation
+	cdc.Status.Applied = ptr.To(reconcileErr == nil)
+	if reconcileErr != nil {
+		cdc.Status.Message = reconcileErr.Error()
+	}
+	if err := r.GetClient().Status().Patch(ctx, &cdc, client.MergeFrom(origCDC)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while setting the change data capture reconciler status: %w", err)
+	}
+
+	return reconcile.Result{RequeueAfter: changeDataCaptureReconciliationInterval}, nil
+}
+
+// reconcileFinalizer ensures the finalizer is set when the replication slot needs to be
+// dropped on deletion, and drops it when the ChangeDataCapture object is being deleted
+func (r *ChangeDataCaptureReconciler) reconcileFinalizer(ctx context.Context, cdc *apiv1.ChangeDataCapture) error {
+	origCDC := cdc.DeepCopy()
+
+	if cdc.DeletionTimestamp.IsZero() {
+		if cdc.GetSlotReclaimPolicy() != apiv1.ChangeDataCaptureSlotReclaimDelete ||
+			!controllerutil.AddFinalizer(cdc, apiv1.ChangeDataCaptureFinalizerName) {
+			return nil
+		}
+		return r.GetClient().Patch(ctx, cdc, client.MergeFrom(origCDC))
+	}
+
+	if !controllerutil.ContainsFinalizer(cdc, apiv1.ChangeDataCaptureFinalizerName) {
+		return nil
+	}
+
+	if cdc.GetSlotReclaimPolicy() == apiv1.ChangeDataCaptureSlotReclaimDelete {
+		err := r.withDatabase(cdc.Spec.DBName, func(db *sql.DB) error {
+			return dropSlot(ctx, db, cdc.Spec.SlotName)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(cdc, apiv1.ChangeDataCaptureFinalizerName)
+	return r.GetClient().Patch(ctx, cdc, client.MergeFrom(origCDC))
+}
+
+// reconcileSlot ensures the replication slot of the ChangeDataCapture exists in
+// the primary, and reports its position in the status.
+// A slot that is missing after it was consumed, for example because the
+// primary changed and the slot wasn't synchronized to the promoted standby,
+// is created again and the gap is reported in the status
+func (r *ChangeDataCaptureReconciler) reconcileSlot(ctx context.Context, cdc *apiv1.ChangeDataCapture) error {
+	return r.withDatabase(cdc.Spec.DBName, func(db *sql.DB) error {
+		info, err := detectSlot(ctx, db, cdc.Spec.SlotName)
+		if err != nil {
+			return err
+		}
+
+		cdc.Status.Message = ""
+		if info == nil {
+			if err := createSlot(ctx, db, cdc.Spec.SlotName, r.supportsFailoverSlots()); err != nil {
+				return err
+			}
+
+			if cdc.Status.ConfirmedFlushLSN != "" {
+				cdc.Status.SlotRecreatedAt = ptr.To(metav1.Now())
+				log.FromContext(ctx).Warning("Replication slot was missing and has been created again",
+					"slotName", cdc.Spec.SlotName,
+					"lastConfirmedFlushLSN", cdc.Status.ConfirmedFlushLSN)
+			}
+
+			if info, err = detectSlot(ctx, db, cdc.Spec.SlotName); err != nil {
+				return err
+			}
+			if info == nil {
+				return fmt.Errorf("replication slot %s not found after its creation", cdc.Spec.SlotName)
+			}
+		}
+
+		if err := checkSlot(cdc.Spec.SlotName, cdc.Spec.DBName, info); err != nil {
+			return err
+		}
+
+		cdc.Status.SlotInstance = r.instance.PodName
+		cdc.Status.SlotActive = info.Active
+		cdc.Status.ConfirmedFlushLSN = info.ConfirmedFlushLSN.String
+		if cdc.Status.SlotRecreatedAt != nil {
+			cdc.Status.Message = fmt.Sprintf(
+				"replication slot created again at %s, the changes done before may not have been published",
+				cdc.Status.SlotRecreatedAt.Format(time.RFC3339))
+			meta.SetStatusCondition(&cdc.Status.Conditions, metav1.Condition{
+				Type:               apiv1.ChangeDataCaptureConditionChangesLost,
+				Status:             metav1.ConditionTrue,
+				Reason:             apiv1.ChangeDataCaptureSlotRecreatedReason,
+				Message:            cdc.Status.Message,
+				ObservedGeneration: cdc.Generation,
+			})
+		}
+
+		return nil
+	})
+}
+
+// supportsFailoverSlots checks whether the replication slots can be
+// synchronized to the standbys, which happens since PostgreSQL 17
+func (r *ChangeDataCaptureReconciler) supportsFailoverSlots() bool {
+	version, err := r.instance.GetPgVersion()
+	return err == nil && version.Major >= 17
+}
+
+// withDatabase runs the passed function with a connection to the passed database.
+// Logical replication slots belong to the database they are created in, and we use
+// a dedicated connection, which is closed at the end of the reconciliation,
+// to avoid blocking a subsequent drop of the database
+func (r *ChangeDataCaptureReconciler) withDatabase(dbname string, f func(db *sql.DB) error) error {
+	db, err := pool.NewDBConnection(
+		r.instance.ConnectionPool().GetDsn(dbname),
+		pool.ConnectionProfilePostgresql,
+	)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", dbname, err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	return f(db)
+}
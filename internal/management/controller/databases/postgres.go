@@ -0,0 +1,239 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// databaseInfo is the information about a database, as read from pg_database
+type databaseInfo struct {
+	Name             string
+	Owner            string
+	IsTemplate       bool
+	AllowConnections bool
+	ConnectionLimit  int
+	Tablespace       string
+}
+
+// detectDatabase reads the information about the passed database from
+// the catalog, returning nil if it doesn't exist
+func detectDatabase(ctx context.Context, db *sql.DB, name string) (*databaseInfo, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT d.datname, r.rolname, d.datistemplate, d.datallowconn, d.datconnlimit, t.spcname
+		FROM pg_catalog.pg_database d
+		JOIN pg_catalog.pg_roles r ON d.datdba = r.oid
+		JOIN pg_catalog.pg_tablespace t ON d.dattablespace = t.oid
+		WHERE d.datname = $1
+		`,
+		name)
+
+	var info databaseInfo
+	err := row.Scan(
+		&info.Name,
+		&info.Owner,
+		&info.IsTemplate,
+		&info.AllowConnections,
+		&info.ConnectionLimit,
+		&info.Tablespace,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while detecting database %s: %w", name, err)
+	}
+
+	return &info, nil
+}
+
+// createDatabase creates the database as described by the passed specification
+func createDatabase(ctx context.Context, db *sql.DB, spec *apiv1.DatabaseSpec) error {
+	contextLogger := log.FromContext(ctx)
+
+	var query strings.Builder
+	query.WriteString(fmt.Sprintf("CREATE DATABASE %s", pgx.Identifier{spec.Name}.Sanitize()))
+	if len(spec.Owner) > 0 {
+		query.WriteString(fmt.Sprintf(" OWNER %s", pgx.Identifier{spec.Owner}.Sanitize()))
+	}
+	if len(spec.Template) > 0 {
+		query.WriteString(fmt.Sprintf(" TEMPLATE %s", pgx.Identifier{spec.Template}.Sanitize()))
+	}
+	if len(spec.Tablespace) > 0 {
+		query.WriteString(fmt.Sprintf(" TABLESPACE %s", pgx.Identifier{spec.Tablespace}.Sanitize()))
+	}
+	if len(spec.Encoding) > 0 {
+		query.WriteString(fmt.Sprintf(" ENCODING %s", pq.QuoteLiteral(spec.Encoding)))
+	}
+	if len(spec.Locale) > 0 {
+		query.WriteString(fmt.Sprintf(" LOCALE %s", pq.QuoteLiteral(spec.Locale)))
+	}
+	if len(spec.LcCollate) > 0 {
+		query.WriteString(fmt.Sprintf(" LC_COLLATE %s", pq.QuoteLiteral(spec.LcCollate)))
+	}
+	if len(spec.LcCtype) > 0 {
+		query.WriteString(fmt.Sprintf(" LC_CTYPE %s", pq.QuoteLiteral(spec.LcCtype)))
+	}
+	if spec.IsTemplate != nil {
+		query.WriteString(fmt.Sprintf(" IS_TEMPLATE %v", *spec.IsTemplate))
+	}
+	if spec.AllowConnections != nil {
+		query.WriteString(fmt.Sprintf(" ALLOW_CONNECTIONS %v", *spec.AllowConnections))
+	}
+	if spec.ConnectionLimit != nil {
+		query.WriteString(fmt.Sprintf(" CONNECTION LIMIT %v", *spec.ConnectionLimit))
+	}
+
+	contextLogger.Info("Creating database", "query", query.String())
+	if _, err := db.ExecContext(ctx, query.String()); err != nil {
+		return fmt.Errorf("while creating database %s: %w", spec.Name, err)
+	}
+
+	return nil
+}
+
+// updateDatabase alters the existing database to match the passed specification
+func updateDatabase(ctx context.Context, db *sql.DB, spec *apiv1.DatabaseSpec, info *databaseInfo) error {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{spec.Name}.Sanitize()
+
+	var statements []string
+	if len(spec.Owner) > 0 && spec.Owner != info.Owner {
+		statements = append(statements,
+			fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", identifier, pgx.Identifier{spec.Owner}.Sanitize()))
+	}
+	if spec.IsTemplate != nil && *spec.IsTemplate != info.IsTemplate {
+		statements = append(statements,
+			fmt.Sprintf("ALTER DATABASE %s WITH IS_TEMPLATE %v", identifier, *spec.IsTemplate))
+	}
+	if spec.AllowConnections != nil && *spec.AllowConnections != info.AllowConnections {
+		statements = append(statements,
+			fmt.Sprintf("ALTER DATABASE %s WITH ALLOW_CONNECTIONS %v", identifier, *spec.AllowConnections))
+	}
+	if spec.ConnectionLimit != nil && *spec.ConnectionLimit != info.ConnectionLimit {
+		statements = append(statements,
+			fmt.Sprintf("ALTER DATABASE %s WITH CONNECTION LIMIT %v", identifier, *spec.ConnectionLimit))
+	}
+	if len(spec.Tablespace) > 0 && spec.Tablespace != info.Tablespace {
+		statements = append(statements,
+			fmt.Sprintf("ALTER DATABASE %s SET TABLESPACE %s", identifier, pgx.Identifier{spec.Tablespace}.Sanitize()))
+	}
+
+	for _, statement := range statements {
+		contextLogger.Info("Updating database", "query", statement)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("while updating database %s: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// dropDatabase drops the database with the passed name, if it exists
+func dropDatabase(ctx context.Context, db *sql.DB, name string) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := fmt.Sprintf("DROP DATABASE IF EXISTS %s", pgx.Identifier{name}.Sanitize())
+	contextLogger.Info("Dropping database", "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while dropping database %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// listExtensions lists the extensions installed in the database
+// the passed connection points to
+func listExtensions(ctx context.Context, db *sql.DB) ([]apiv1.DatabaseExtensionStatus, error) {
+	rows, err := db.QueryContext(ctx, "SELECT extname, extversion FROM pg_catalog.pg_extension ORDER BY extname")
+	if err != nil {
+		return nil, fmt.Errorf("while listing extensions: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []apiv1.DatabaseExtensionStatus
+	for rows.Next() {
+		var extension apiv1.DatabaseExtensionStatus
+		if err := rows.Scan(&extension.Name, &extension.Version); err != nil {
+			return nil, fmt.Errorf("while listing extensions: %w", err)
+		}
+		result = append(result, extension)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("while listing extensions: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// reconcileExtension creates, updates or drops the passed extension in the
+// database the passed connection points to, given the installed version
+// or an empty string if the extension is not installed.
+// It returns true if the extension has been changed
+func reconcileExtension(
+	ctx context.Context,
+	db *sql.DB,
+	extension apiv1.DatabaseExtensionSpec,
+	installedVersion string,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{extension.Name}.Sanitize()
+
+	var query string
+	switch {
+	case extension.GetEnsure() == apiv1.EnsureAbsent && installedVersion != "":
+		query = fmt.Sprintf("DROP EXTENSION %s", identifier)
+
+	case extension.GetEnsure() == apiv1.EnsurePresent && installedVersion == "":
+		query = fmt.Sprintf("CREATE EXTENSION %s", identifier)
+		if len(extension.Schema) > 0 {
+			query += fmt.Sprintf(" SCHEMA %s", pgx.Identifier{extension.Schema}.Sanitize())
+		}
+		if len(extension.Version) > 0 {
+			query += fmt.Sprintf(" VERSION %s", pq.QuoteLiteral(extension.Version))
+		}
+
+	case extension.GetEnsure() == apiv1.EnsurePresent &&
+		len(extension.Version) > 0 && extension.Version != installedVersion:
+		query = fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", identifier, pq.QuoteLiteral(extension.Version))
+
+	default:
+		return false, nil
+	}
+
+	contextLogger.Info("Reconciling extension", "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return false, fmt.Errorf("while reconciling extension %s: %w", extension.Name, err)
+	}
+
+	return true, nil
+}
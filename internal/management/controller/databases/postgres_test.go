@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed database SQL", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates a database with the requested options", func(ctx SpecContext) {
+		spec := &apiv1.DatabaseSpec{
+			Name:            "one",
+			Owner:           "app",
+			Encoding:        "UTF8",
+			IsTemplate:      ptr.To(false),
+			ConnectionLimit: ptr.To(10),
+		}
+		mock.ExpectExec(`CREATE DATABASE "one" OWNER "app" ENCODING 'UTF8' IS_TEMPLATE false CONNECTION LIMIT 10`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(createDatabase(ctx, db, spec)).To(Succeed())
+	})
+
+	It("only alters the attributes that differ", func(ctx SpecContext) {
+		spec := &apiv1.DatabaseSpec{
+			Name:            "one",
+			Owner:           "app",
+			ConnectionLimit: ptr.To(10),
+			IsTemplate:      ptr.To(false),
+		}
+		info := &databaseInfo{
+			Name:            "one",
+			Owner:           "postgres",
+			ConnectionLimit: 10,
+		}
+		mock.ExpectExec(`ALTER DATABASE "one" OWNER TO "app"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(updateDatabase(ctx, db, spec, info)).To(Succeed())
+	})
+
+	It("reports a missing database", func(ctx SpecContext) {
+		mock.ExpectQuery(`
+		SELECT d.datname, r.rolname, d.datistemplate, d.datallowconn, d.datconnlimit, t.spcname
+		FROM pg_catalog.pg_database d
+		JOIN pg_catalog.pg_roles r ON d.datdba = r.oid
+		JOIN pg_catalog.pg_tablespace t ON d.dattablespace = t.oid
+		WHERE d.datname = $1
+		`).WithArgs("one").WillReturnError(sql.ErrNoRows)
+
+		info, err := detectDatabase(ctx, db, "one")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(BeNil())
+	})
+
+	It("drops a database", func(ctx SpecContext) {
+		mock.ExpectExec(`DROP DATABASE IF EXISTS "one"`).
+			WillReturnError(fmt.Errorf("boom"))
+
+		Expect(dropDatabase(ctx, db, "one")).To(MatchError(ContainSubstring("boom")))
+	})
+
+	It("creates a missing extension", func(ctx SpecContext) {
+		mock.ExpectExec(`CREATE EXTENSION "hstore" SCHEMA "public" VERSION '1.8'`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		changed, err := reconcileExtension(ctx, db, apiv1.DatabaseExtensionSpec{
+			Name:    "hstore",
+			Schema:  "public",
+			Version: "1.8",
+		}, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+	})
+
+	It("updates an extension to the requested version", func(ctx SpecContext) {
+		mock.ExpectExec(`ALTER EXTENSION "hstore" UPDATE TO '1.8'`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		changed, err := reconcileExtension(ctx, db, apiv1.DatabaseExtensionSpec{
+			Name:    "hstore",
+			Version: "1.8",
+		}, "1.7")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+	})
+
+	It("drops an extension that must be absent", func(ctx SpecContext) {
+		mock.ExpectExec(`DROP EXTENSION "hstore"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		changed, err := reconcileExtension(ctx, db, apiv1.DatabaseExtensionSpec{
+			Name:   "hstore",
+			Ensure: apiv1.EnsureAbsent,
+		}, "1.7")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+	})
+
+	It("does nothing when the extension is already in the requested state", func(ctx SpecContext) {
+		changed, err := reconcileExtension(ctx, db, apiv1.DatabaseExtensionSpec{
+			Name: "hstore",
+		}, "1.7")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+})
@@ -0,0 +1,228 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// databaseReconciliationInterval is the time between two reconciliations
+// of the same Database, used to detect drifts and role changes
+const databaseReconciliationInterval = 30 * time.Second
+
+// Reconcile is the main reconciliation loop for the Database objects
+func (r *DatabaseReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("db_reconciler").WithValues("database", req.Name)
+	// if the context has already been cancelled,
+	// trying to reconcile would just lead to misleading errors being reported
+	if err := ctx.Err(); err != nil {
+		contextLogger.Warning("Context cancelled, will not start database reconcile", "err", err)
+		return reconcile.Result{}, nil
+	}
+
+	var database apiv1.Database
+	if err := r.GetClient().Get(ctx, req.NamespacedName, &database); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This Database belongs to another cluster
+	if database.Spec.ClusterRef.Name != r.instance.ClusterName {
+		return reconcile.Result{}, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !isPrimary {
+		contextLogger.Debug("skipping the database reconciler in replicas")
+		return reconcile.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	}
+
+	// Fetch the Cluster from the cache
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The cluster has been deleted.
+			// We just need to wait for this instance manager to be terminated
+			contextLogger.Debug("Could not find Cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	if cluster.IsReplica() {
+		contextLogger.Debug("skipping the database reconciler in replica clusters")
+		return reconcile.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	}
+
+	if r.instance.IsServerReady() != nil {
+		contextLogger.Debug("database not ready, skipping database reconciling")
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+
+	if err := r.reconcileFinalizer(ctx, &database); err != nil {
+		return reconcile.Result{}, err
+	}
+	if !database.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	extensions, reconcileErr := r.reconcileDatabase(ctx, &database)
+
+	origDatabase := database.DeepCopy()
+	database.Status.ObservedGeneration = database.Generation
+	database.Status.Applied = ptr.To(reconcileErr == nil)
+	database.Status.Extensions = extensions
+	if reconcileErr != nil {
+		database.Status.Message = reconcileErr.Error()
+	} else {
+		database.Status.Message = ""
+	}
+	if err := r.GetClient().Status().Patch(ctx, &database, client.MergeFrom(origDatabase)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while setting the database reconciler status: %w", err)
+	}
+
+	return reconcile.Result{RequeueAfter: databaseReconciliationInterval}, nil
+}
+
+// reconcileFinalizer ensures the finalizer is set when the database needs to be
+// dropped on deletion, and drops it when the Database object is being deleted
+func (r *DatabaseReconciler) reconcileFinalizer(ctx context.Context, database *apiv1.Database) error {
+	origDatabase := database.DeepCopy()
+
+	if database.DeletionTimestamp.IsZero() {
+		if database.GetReclaimPolicy() != apiv1.DatabaseReclaimDelete ||
+			!controllerutil.AddFinalizer(database, apiv1.DatabaseFinalizerName) {
+			return nil
+		}
+		return r.GetClient().Patch(ctx, database, client.MergeFrom(origDatabase))
+	}
+
+	if !controllerutil.ContainsFinalizer(database, apiv1.DatabaseFinalizerName) {
+		return nil
+	}
+
+	if database.GetReclaimPolicy() == apiv1.DatabaseReclaimDelete {
+		superUserDB, err := r.instance.GetSuperUserDB()
+		if err != nil {
+			return fmt.Errorf("while getting the superuser connection: %w", err)
+		}
+		if err := dropDatabase(ctx, superUserDB, database.Spec.Name); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(database, apiv1.DatabaseFinalizerName)
+	return r.GetClient().Patch(ctx, database, client.MergeFrom(origDatabase))
+}
+
+// reconcileDatabase applies the Database specification to PostgreSQL, returning
+// the list of the extensions installed in the database
+func (r *DatabaseReconciler) reconcileDatabase(
+	ctx context.Context,
+	database *apiv1.Database,
+) ([]apiv1.DatabaseExtensionStatus, error) {
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return nil, fmt.Errorf("while getting the superuser connection: %w", err)
+	}
+
+	if database.GetEnsure() == apiv1.EnsureAbsent {
+		return nil, dropDatabase(ctx, superUserDB, database.Spec.Name)
+	}
+
+	info, err := detectDatabase(ctx, superUserDB, database.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		err = createDatabase(ctx, superUserDB, &database.Spec)
+	} else {
+		err = updateDatabase(ctx, superUserDB, &database.Spec, info)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if database.Spec.AllowConnections != nil && !*database.Spec.AllowConnections {
+		// We can't connect to this database to manage its extensions
+		return nil, nil
+	}
+
+	return r.reconcileExtensions(ctx, database)
+}
+
+// reconcileExtensions applies the extensions configuration to the database,
+// returning the list of the installed extensions.
+// We use a dedicated connection, which is closed at the end of the
+// reconciliation, to avoid blocking a subsequent drop of the database
+func (r *DatabaseReconciler) reconcileExtensions(
+	ctx context.Context,
+	database *apiv1.Database,
+) ([]apiv1.DatabaseExtensionStatus, error) {
+	db, err := pool.NewDBConnection(
+		r.instance.ConnectionPool().GetDsn(database.Spec.Name),
+		pool.ConnectionProfilePostgresql,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("while connecting to database %s: %w", database.Spec.Name, err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	installed, err := listExtensions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	installedVersions := make(map[string]string, len(installed))
+	for _, extension := range installed {
+		installedVersions[extension.Name] = extension.Version
+	}
+
+	changed := false
+	for _, extension := range database.Spec.Extensions {
+		extensionChanged, err := reconcileExtension(ctx, db, extension, installedVersions[extension.Name])
+		if err != nil {
+			return installed, err
+		}
+		changed = changed || extensionChanged
+	}
+
+	if !changed {
+		return installed, nil
+	}
+	return listExtensions(ctx, db)
+}
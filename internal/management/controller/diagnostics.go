@@ -0,0 +1,133 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileDiagnosticsRole creates the role used by the instance manager
+// to run the diagnostic queries when they are enabled, and ensures that an
+// existing role has no other privilege than being a member of `pg_monitor`.
+// The role is not dropped when they are disabled, as the instance manager
+// refuses the queries anyway
+func reconcileDiagnosticsRole(
+	ctx context.Context,
+	db *sql.DB,
+	config *apiv1.DiagnosticQueriesConfiguration,
+) error {
+	if !config.IsEnabled() {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("role", apiv1.DiagnosticsUserName)
+	roleIdentifier := pgx.Identifier{apiv1.DiagnosticsUserName}.Sanitize()
+
+	var (
+		isPrivileged bool
+		memberOf     pq.StringArray
+	)
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT rolsuper OR rolcreaterole OR rolcreatedb OR rolreplication OR rolbypassrls,
+			ARRAY(
+				SELECT parent.rolname
+				FROM pg_catalog.pg_auth_members m
+				JOIN pg_catalog.pg_roles parent ON m.roleid = parent.oid
+				WHERE m.member = r.oid
+				ORDER BY parent.rolname
+			)
+		FROM pg_catalog.pg_roles r
+		WHERE rolname = $1`,
+		apiv1.DiagnosticsUserName)
+	err := row.Scan(&isPrivileged, &memberOf)
+
+	var statements []string
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		contextLogger.Info("Creating the role running the diagnostic queries")
+		statements = []string{
+			fmt.Sprintf("CREATE ROLE %s WITH LOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE NOREPLICATION NOBYPASSRLS",
+				roleIdentifier),
+			fmt.Sprintf("ALTER ROLE %s SET default_transaction_read_only TO on", roleIdentifier),
+			fmt.Sprintf("GRANT pg_monitor TO %s", roleIdentifier),
+		}
+
+	case err != nil:
+		return err
+
+	default:
+		statements = getDiagnosticsRoleFixes(roleIdentifier, isPrivileged, memberOf)
+		if len(statements) == 0 {
+			return nil
+		}
+		contextLogger.Warning("Revoking the privileges the role running the diagnostic queries must not have",
+			"privileged", isPrivileged, "memberOf", []string(memberOf))
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This is a no-op when the transaction is committed
+		_ = tx.Rollback()
+	}()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// getDiagnosticsRoleFixes gets the statements aligning an existing
+// diagnostics role to the one created by the instance manager
+func getDiagnosticsRoleFixes(roleIdentifier string, isPrivileged bool, memberOf []string) []string {
+	var statements []string
+	if isPrivileged {
+		statements = append(statements,
+			fmt.Sprintf("ALTER ROLE %s WITH NOSUPERUSER NOCREATEDB NOCREATEROLE NOREPLICATION NOBYPASSRLS",
+				roleIdentifier))
+	}
+
+	for _, parent := range memberOf {
+		if parent == "pg_monitor" {
+			continue
+		}
+		statements = append(statements,
+			fmt.Sprintf("REVOKE %s FROM %s", pgx.Identifier{parent}.Sanitize(), roleIdentifier))
+	}
+
+	if !slices.Contains(memberOf, "pg_monitor") {
+		statements = append(statements, fmt.Sprintf("GRANT pg_monitor TO %s", roleIdentifier))
+	}
+
+	return statements
+}
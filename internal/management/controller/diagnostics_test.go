@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("diagnostics role", func() {
+	enabled := &apiv1.DiagnosticQueriesConfiguration{Enabled: true}
+
+	It("does nothing when the diagnostic queries are disabled", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reconcileDiagnosticsRole(ctx, db, nil)).To(Succeed())
+		Expect(reconcileDiagnosticsRole(ctx, db, &apiv1.DiagnosticQueriesConfiguration{})).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	roleColumns := []string{"privileged", "member_of"}
+
+	It("creates the role when it doesn't exist", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT rolsuper").WithArgs(apiv1.DiagnosticsUserName).
+			WillReturnRows(sqlmock.NewRows(roleColumns))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE ROLE "cnpg_diagnostics" WITH LOGIN NOSUPERUSER`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER ROLE "cnpg_diagnostics" SET default_transaction_read_only TO on`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`GRANT pg_monitor TO "cnpg_diagnostics"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(reconcileDiagnosticsRole(ctx, db, enabled)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("leaves an existing role with the expected privileges untouched", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT rolsuper").WithArgs(apiv1.DiagnosticsUserName).
+			WillReturnRows(sqlmock.NewRows(roleColumns).AddRow(false, "{pg_monitor}"))
+
+		Expect(reconcileDiagnosticsRole(ctx, db, enabled)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("revokes the privileges an existing role must not have", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT rolsuper").WithArgs(apiv1.DiagnosticsUserName).
+			WillReturnRows(sqlmock.NewRows(roleColumns).AddRow(true, "{app_owner,pg_read_server_files}"))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER ROLE "cnpg_diagnostics" WITH NOSUPERUSER`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`REVOKE "app_owner" FROM "cnpg_diagnostics"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`REVOKE "pg_read_server_files" FROM "cnpg_diagnostics"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`GRANT pg_monitor TO "cnpg_diagnostics"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(reconcileDiagnosticsRole(ctx, db, enabled)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,116 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extensions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// errExtensionNotAvailable is raised when the extension is not shipped
+// with the operand image
+var errExtensionNotAvailable = errors.New("extension not available in the operand image")
+
+// extensionInfo is the information about an extension, as read from
+// pg_available_extensions
+type extensionInfo struct {
+	// The version installed in the database, empty if the extension
+	// is not installed
+	InstalledVersion string
+
+	// The default version shipped with the operand image
+	DefaultVersion string
+}
+
+// detectExtension reads the information about the passed extension from the
+// database the passed connection points to
+func detectExtension(ctx context.Context, db *sql.DB, name string) (*extensionInfo, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT COALESCE(e.extversion, ''), a.default_version
+		FROM pg_catalog.pg_available_extensions a
+		LEFT JOIN pg_catalog.pg_extension e ON e.extname = a.name
+		WHERE a.name = $1
+		`,
+		name)
+
+	var info extensionInfo
+	err := row.Scan(&info.InstalledVersion, &info.DefaultVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errExtensionNotAvailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while detecting extension %s: %w", name, err)
+	}
+
+	return &info, nil
+}
+
+// reconcileExtension creates or updates the passed extension in the database
+// the passed connection points to, returning the installed version.
+// Extensions without an explicit version are kept at the default version
+// of the operand image, so that they are updated when the image changes
+func reconcileExtension(
+	ctx context.Context,
+	db *sql.DB,
+	extension apiv1.ExtensionConfiguration,
+) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	info, err := detectExtension(ctx, db, extension.Name)
+	if err != nil {
+		return "", err
+	}
+
+	targetVersion := extension.Version
+	if targetVersion == "" {
+		targetVersion = info.DefaultVersion
+	}
+
+	identifier := pgx.Identifier{extension.Name}.Sanitize()
+	var query string
+	switch {
+	case info.InstalledVersion == "":
+		query = fmt.Sprintf("CREATE EXTENSION %s", identifier)
+		if len(extension.Schema) > 0 {
+			query += fmt.Sprintf(" SCHEMA %s", pgx.Identifier{extension.Schema}.Sanitize())
+		}
+		query += fmt.Sprintf(" VERSION %s", pq.QuoteLiteral(targetVersion))
+
+	case info.InstalledVersion != targetVersion:
+		query = fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", identifier, pq.QuoteLiteral(targetVersion))
+
+	default:
+		return info.InstalledVersion, nil
+	}
+
+	contextLogger.Info("Reconciling extension", "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return info.InstalledVersion, fmt.Errorf("while reconciling extension %s: %w", extension.Name, err)
+	}
+
+	return targetVersion, nil
+}
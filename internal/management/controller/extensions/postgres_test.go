@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extensions
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Declared extensions SQL", func() {
+	const detectQuery = "SELECT COALESCE(e.extversion, ''), a.default_version"
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectDetect := func(installed, available string) {
+		mock.ExpectQuery(regexp.QuoteMeta(detectQuery)).
+			WithArgs("postgis").
+			WillReturnRows(sqlmock.NewRows([]string{"extversion", "default_version"}).
+				AddRow(installed, available))
+	}
+
+	It("creates a missing extension at the default version", func(ctx SpecContext) {
+		expectDetect("", "3.4.2")
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE EXTENSION "postgis" SCHEMA "gis" VERSION '3.4.2'`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		version, err := reconcileExtension(ctx, db, apiv1.ExtensionConfiguration{Name: "postgis", Schema: "gis"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).To(Equal("3.4.2"))
+	})
+
+	It("updates an extension when the image ships a newer default version", func(ctx SpecContext) {
+		expectDetect("3.4.1", "3.4.2")
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER EXTENSION "postgis" UPDATE TO '3.4.2'`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		version, err := reconcileExtension(ctx, db, apiv1.ExtensionConfiguration{Name: "postgis"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).To(Equal("3.4.2"))
+	})
+
+	It("keeps an extension at the requested version", func(ctx SpecContext) {
+		expectDetect("3.4.1", "3.4.2")
+
+		version, err := reconcileExtension(ctx, db, apiv1.ExtensionConfiguration{Name: "postgis", Version: "3.4.1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).To(Equal("3.4.1"))
+	})
+
+	It("reports the installed version when the update fails", func(ctx SpecContext) {
+		expectDetect("3.4.1", "3.4.2")
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER EXTENSION "postgis" UPDATE TO '3.5.0'`)).
+			WillReturnError(errors.New("no update path"))
+
+		version, err := reconcileExtension(ctx, db, apiv1.ExtensionConfiguration{Name: "postgis", Version: "3.5.0"})
+		Expect(err).To(HaveOccurred())
+		Expect(version).To(Equal("3.4.1"))
+	})
+
+	It("fails when the extension is not available", func(ctx SpecContext) {
+		mock.ExpectQuery(regexp.QuoteMeta(detectQuery)).
+			WithArgs("postgis").
+			WillReturnRows(sqlmock.NewRows([]string{"extversion", "default_version"}))
+
+		_, err := reconcileExtension(ctx, db, apiv1.ExtensionConfiguration{Name: "postgis"})
+		Expect(err).To(MatchError(errExtensionNotAvailable))
+	})
+})
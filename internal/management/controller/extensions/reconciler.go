@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extensions
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// Reconcile installs and updates the extensions declared in the cluster
+// specification, and reports their versions into the cluster status
+func Reconcile(
+	ctx context.Context,
+	instance *postgres.Instance,
+	cluster *apiv1.Cluster,
+	c client.Client,
+) (reconcile.Result, error) {
+	extensions := cluster.Spec.PostgresConfiguration.Extensions
+	if len(extensions) == 0 && len(cluster.Status.ExtensionsStatus) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Debug("Updating extensions information")
+
+	applicationDatabase := cluster.GetApplicationDatabaseName()
+	states := make([]apiv1.ExtensionState, 0, len(extensions))
+	failed := false
+	for _, extension := range extensions {
+		for _, database := range extension.GetDatabases(applicationDatabase) {
+			state := apiv1.ExtensionState{
+				Name:     extension.Name,
+				Database: database,
+			}
+
+			version, err := reconcileExtensionInDatabase(ctx, instance, database, extension)
+			state.Version = version
+			if err != nil {
+				contextLogger.Warning("Cannot reconcile extension",
+					"extension", extension.Name, "database", database, "err", err)
+				state.Error = err.Error()
+				failed = true
+			}
+
+			states = append(states, state)
+		}
+	}
+
+	var result reconcile.Result
+	if failed {
+		// Databases may be created after the cluster, and new operand images
+		// may need some time to be rolled out
+		result.RequeueAfter = 30 * time.Second
+	}
+
+	if len(states) == 0 {
+		states = nil
+	}
+	if equality.Semantic.DeepEqual(states, cluster.Status.ExtensionsStatus) {
+		return result, nil
+	}
+
+	updatedCluster := cluster.DeepCopy()
+	updatedCluster.Status.ExtensionsStatus = states
+	return result, c.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster))
+}
+
+// reconcileExtensionInDatabase reconciles the passed extension in the
+// passed database, returning the installed version
+func reconcileExtensionInDatabase(
+	ctx context.Context,
+	instance *postgres.Instance,
+	database string,
+	extension apiv1.ExtensionConfiguration,
+) (string, error) {
+	db, err := instance.ConnectionPool().Connection(database)
+	if err != nil {
+		return "", err
+	}
+
+	return reconcileExtension(ctx, db, extension)
+}
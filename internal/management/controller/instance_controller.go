@@ -18,17 +18,21 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"errors"
 	"fmt"
 	"math"
 	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -38,17 +42,20 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/extensions"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/infrastructure"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/reconciler"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
@@ -62,6 +69,7 @@ const (
 	userSearchFunctionSchema = "public"
 	userSearchFunctionName   = "user_search"
 	userSearchFunction       = "SELECT usename, passwd FROM pg_catalog.pg_shadow WHERE usename=$1;"
+	userSearchFunctionConfig = "search_path=pg_catalog, pg_temp"
 )
 
 // RetryUntilWalReceiverDown is the default retry configuration that is used
@@ -121,20 +129,49 @@ func (r *InstanceReconciler) Reconcile(
 	// Refresh the cache
 	requeueOnMissingPermissions := r.updateCacheFromCluster(ctx, cluster)
 
+	// Start or stop writing a copy of the PostgreSQL logs to the log files
+	if err := logpipe.ConfigureLogFiles(cluster.Spec.LogFiles); err != nil {
+		contextLogger.Error(err, "Error while configuring the log files")
+	}
+
 	// Reconcile monitoring section
 	r.reconcileMetrics(cluster)
 	r.reconcileMonitoringQueries(ctx, cluster)
 
+	// Read the lifecycle hooks executed by this instance
+	r.reconcileLifecycleHooks(ctx, cluster)
+
+	// The startup checks are executed before the postmaster is started,
+	// which waits for the first reconciliation
+	r.instance.SetStartupCheckConfiguration(cluster.Spec.StartupCheck)
+
+	// Read how PostgreSQL is restarted after an unexpected exit
+	r.instance.SetPostmasterRestartConfiguration(cluster.Spec.PostmasterRestart)
+
+	// Read the limits applied to the diagnostic queries
+	r.instance.SetDiagnosticQueriesConfiguration(cluster.Spec.DiagnosticQueries)
+
 	// Reconcile secrets and cryptographic material
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadNeeded := r.RefreshSecrets(ctx, cluster)
 
+	// Wrap the data encryption key again if the TDE passphrase has changed
+	if err := r.reconcileDataEncryptionKey(ctx, cluster); err != nil {
+		contextLogger.Error(err, "Error while rotating the TDE passphrase")
+	}
+
 	reloadConfigNeeded, err := r.refreshConfigurationFiles(ctx, cluster)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 	reloadNeeded = reloadNeeded || reloadConfigNeeded
 
+	// A changed configuration may fix the reason why the
+	// postmaster exited, so we try starting it again
+	if reloadConfigNeeded {
+		r.instance.ResumePostmasterRestarts(ctx)
+	}
+
 	// here we execute initialization tasks that need to be executed only on the first reconciliation loop
 	if !r.firstReconcileDone.Load() {
 		if err = r.initialize(ctx, cluster); err != nil {
@@ -150,7 +187,7 @@ func (r *InstanceReconciler) Reconcile(
 	// Reconcile cluster role without DB
 	reloadClusterRoleConfig, err := r.reconcileClusterRoleWithoutDB(ctx, cluster)
 	if err != nil {
-		return reconcile.Result{}, err
+		return handleErrNextLoop(err)
 	}
 	reloadNeeded = reloadNeeded || reloadClusterRoleConfig
 
@@ -173,12 +210,12 @@ func (r *InstanceReconciler) Reconcile(
 
 	restarted, err := r.reconcilePrimary(ctx, cluster)
 	if err != nil {
-		return reconcile.Result{}, err
+		return handleErrNextLoop(err)
 	}
 
 	restartedFromOldPrimary, err := r.reconcileOldPrimary(ctx, cluster)
 	if err != nil {
-		return reconcile.Result{}, err
+		return handleErrNextLoop(err)
 	}
 
 	restarted = restarted || restartedFromOldPrimary
@@ -196,9 +233,13 @@ func (r *InstanceReconciler) Reconcile(
 	if reloadNeeded && !restarted {
 		contextLogger.Info("reloading the instance")
 		if err = r.instance.Reload(ctx); err != nil {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, events.ConfigReloadFailed,
+				"Instance %s failed to reload the configuration: %v", r.instance.PodName, err)
 			return reconcile.Result{}, fmt.Errorf("while reloading the instance: %w", err)
 		}
 		if err = r.processConfigReloadAndManageRestart(ctx, cluster); err != nil {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, events.ConfigReloadFailed,
+				"Instance %s failed to apply the new configuration: %v", r.instance.PodName, err)
 			return reconcile.Result{}, fmt.Errorf("cannot apply new PostgreSQL configuration: %w", err)
 		}
 	}
@@ -233,6 +274,13 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	if r.instance.PodName == cluster.Status.CurrentPrimary && !cluster.IsReplica() {
+		result, err := extensions.Reconcile(ctx, r.instance, cluster, r.client)
+		if err != nil || !result.IsZero() {
+			return result, err
+		}
+	}
+
 	// EXTREMELY IMPORTANT
 	//
 	// The reconciliation loop may not have applied all the changes needed. In this case
@@ -446,6 +494,13 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 		return false, err
 	}
 
+	// The hooks are executed in the background, once, and the
+	// demotion waits for them without blocking the reconciliation loop
+	if !r.instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion) {
+		contextLogger.Info("Waiting for the preDemotion lifecycle hooks to complete")
+		return false, controllers.ErrNextLoop
+	}
+
 	contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion")
 
 	db, err := r.instance.GetSuperUserDB()
@@ -505,9 +560,13 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 		return fmt.Errorf("getting the superuserdb: %w", err)
 	}
 
+	if err := reconcileDiagnosticsRole(ctx, db, cluster.Spec.DiagnosticQueries); err != nil {
+		return fmt.Errorf("while reconciling the diagnostics role: %w", err)
+	}
+
 	extensionStatusChanged := false
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := extension.IsUsed(cluster.GetPostgresParameters())
 		if lastStatus, ok := r.extensionStatus[extension.Name]; !ok || lastStatus != extensionIsUsed {
 			extensionStatusChanged = true
 			break
@@ -515,6 +574,11 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 	}
 
 	databases, errors := r.getAllAccessibleDatabases(ctx, db)
+	authQueryUsers, staleAuthQueryUsers, err := r.reconcileAuthQueryUsers(
+		ctx, db, cluster.Status.PoolerIntegrations)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("could not reconcile the auth query users: %w", err))
+	}
 	for _, databaseName := range databases {
 		db, err := r.instance.ConnectionPool().Connection(databaseName)
 		if err != nil {
@@ -523,22 +587,29 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 			continue
 		}
 		if extensionStatusChanged {
-			if err = r.reconcileExtensions(ctx, db, cluster.Spec.PostgresConfiguration.Parameters); err != nil {
+			if err = r.reconcileExtensions(ctx, db, cluster.GetPostgresParameters()); err != nil {
 				errors = append(errors,
 					fmt.Errorf("could not reconcile extensions for database %s: %w", databaseName, err))
 			}
 		}
-		if err = r.reconcilePoolers(ctx, db, databaseName, cluster.Status.PoolerIntegrations); err != nil {
+		if err = r.reconcilePoolers(ctx, db, databaseName, cluster.Status.PoolerIntegrations,
+			authQueryUsers, staleAuthQueryUsers); err != nil {
 			errors = append(errors,
-				fmt.Errorf("could not reconcile extensions for database %s: %w", databaseName, err))
+				fmt.Errorf("could not reconcile poolers for database %s: %w", databaseName, err))
 		}
 	}
 	if errors != nil {
 		return fmt.Errorf("got errors while reconciling databases: %v", errors)
 	}
 
+	// The privileges of the auth query users no longer needed have been
+	// revoked in every database, so they can be dropped
+	if err := dropAuthQueryUsers(ctx, db, staleAuthQueryUsers); err != nil {
+		return err
+	}
+
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := extension.IsUsed(cluster.GetPostgresParameters())
 		r.extensionStatus[extension.Name] = extensionIsUsed
 	}
 
@@ -616,12 +687,23 @@ func (r *InstanceReconciler) reconcileExtensions(
 	return tx.Commit()
 }
 
-// ReconcileExtensions reconciles the expected extensions for this
-// PostgreSQL instance
+// reconcilePoolers creates, in the passed database, the functions called
+// by the auth queries of the poolers and grants the provisioned users the
+// privileges needed to call them, revoking the ones of the users no longer
+// needed
 func (r *InstanceReconciler) reconcilePoolers(
-	ctx context.Context, db *sql.DB, dbName string, integrations *apiv1.PoolerIntegrations,
+	ctx context.Context,
+	db *sql.DB,
+	dbName string,
+	integrations *apiv1.PoolerIntegrations,
+	authQueryUsers []apiv1.PgBouncerAuthQueryUser,
+	staleAuthQueryUsers []string,
 ) (err error) {
-	if integrations == nil || len(integrations.PgBouncerIntegration.Secrets) == 0 {
+	var secrets []string
+	if integrations != nil {
+		secrets = integrations.PgBouncerIntegration.Secrets
+	}
+	if len(secrets) == 0 && len(authQueryUsers) == 0 && len(staleAuthQueryUsers) == 0 {
 		return err
 	}
 
@@ -634,57 +716,41 @@ func (r *InstanceReconciler) reconcilePoolers(
 		_ = tx.Rollback()
 	}()
 
-	var existsRole bool
-	row := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) > 0 FROM pg_catalog.pg_roles WHERE rolname = '%s'",
-		apiv1.PGBouncerPoolerUserName))
-	err = row.Scan(&existsRole)
-	if err != nil {
-		return err
-	}
-	if !existsRole {
-		_, err := tx.Exec(fmt.Sprintf("CREATE ROLE %s WITH LOGIN", apiv1.PGBouncerPoolerUserName))
+	if len(secrets) > 0 {
+		var existsRole bool
+		row := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) > 0 FROM pg_catalog.pg_roles WHERE rolname = '%s'",
+			apiv1.PGBouncerPoolerUserName))
+		err = row.Scan(&existsRole)
 		if err != nil {
 			return err
 		}
-		_, err = tx.Exec(fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s", dbName, apiv1.PGBouncerPoolerUserName))
-		if err != nil {
-			return err
+		if !existsRole {
+			_, err := tx.Exec(fmt.Sprintf("CREATE ROLE %s WITH LOGIN", apiv1.PGBouncerPoolerUserName))
+			if err != nil {
+				return err
+			}
 		}
-	}
 
-	var existsFunction bool
-	row = tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) > 0 FROM pg_proc WHERE proname='%s' and prosrc='%s'",
-		userSearchFunctionName,
-		userSearchFunction))
-	err = row.Scan(&existsFunction)
-	if err != nil {
-		return err
-	}
-	if !existsFunction {
-		_, err = tx.Exec(fmt.Sprintf("CREATE OR REPLACE FUNCTION %s.%s(uname TEXT) "+
-			"RETURNS TABLE (usename name, passwd text) "+
-			"as '%s' "+
-			"LANGUAGE sql SECURITY DEFINER",
-			userSearchFunctionSchema,
-			userSearchFunctionName,
-			userSearchFunction))
-		if err != nil {
+		if err = reconcileAuthQueryFunction(ctx, tx, dbName,
+			userSearchFunctionSchema, userSearchFunctionName, apiv1.PGBouncerPoolerUserName); err != nil {
 			return err
 		}
-		_, err = tx.Exec(fmt.Sprintf("REVOKE ALL ON FUNCTION %s.%s(text) FROM public;",
-			userSearchFunctionSchema, userSearchFunctionName))
-		if err != nil {
-			return err
+	}
+
+	for _, user := range authQueryUsers {
+		if err = ensureAuthQuerySchema(ctx, tx, user.FunctionSchema); err != nil {
+			return fmt.Errorf("while provisioning the auth query of %s: %w", user.Name, err)
 		}
-		_, err = tx.Exec(fmt.Sprintf("GRANT EXECUTE ON FUNCTION %s.%s(text) TO %s",
-			userSearchFunctionSchema,
-			userSearchFunctionName,
-			apiv1.PGBouncerPoolerUserName))
-		if err != nil {
-			return err
+		if err = reconcileAuthQueryFunction(ctx, tx, dbName,
+			user.FunctionSchema, user.FunctionName, user.Name); err != nil {
+			return fmt.Errorf("while provisioning the auth query of %s: %w", user.Name, err)
 		}
 	}
 
+	if err = revokeAuthQueryUsers(ctx, tx, staleAuthQueryUsers); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -914,6 +980,9 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
 	r.instance.SmartStopDelay = cluster.GetSmartShutdownTimeout()
+	r.instance.FastStopDelay = cluster.GetFastShutdownTimeout()
+	r.instance.SetHibernating(
+		cluster.Annotations[pkgUtils.HibernationAnnotationName] == string(pkgUtils.HibernationAnnotationValueOn))
 	r.instance.RequiresDesignatedPrimaryTransition = detectRequiresDesignatedPrimaryTransition()
 }
 
@@ -999,6 +1068,72 @@ func (r *InstanceReconciler) processConfigReloadAndManageRestart(ctx context.Con
 	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
 }
 
+// reconcileDataEncryptionKey ensures that the data encryption key is wrapped
+// with the passphrase contained in the TDE secret
+func (r *InstanceReconciler) reconcileDataEncryptionKey(ctx context.Context, cluster *apiv1.Cluster) error {
+	secretKeyRef := cluster.GetTDESecretKeyRef()
+	if secretKeyRef == nil {
+		return nil
+	}
+
+	var secret corev1.Secret
+	if err := r.GetClient().Get(
+		ctx,
+		client.ObjectKey{Namespace: r.instance.Namespace, Name: secretKeyRef.Name},
+		&secret); err != nil {
+		return err
+	}
+
+	passphrase, ok := secret.Data[secretKeyRef.Key]
+	if !ok {
+		return fmt.Errorf("missing %s entry in Secret %s", secretKeyRef.Key, secretKeyRef.Name)
+	}
+
+	previousPassphrases, err := r.getPreviousTDEPassphrases(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	rewrapped, err := r.instance.RewrapDataEncryptionKey(
+		ctx,
+		cluster.Spec.PostgresConfiguration.TDE,
+		secret.ResourceVersion,
+		string(passphrase),
+		previousPassphrases,
+	)
+	if rewrapped {
+		log.FromContext(ctx).Info("Data encryption key wrapped with the new TDE passphrase",
+			"secret", secretKeyRef.Name)
+	}
+	return err
+}
+
+// getPreviousTDEPassphrases gets the TDE passphrases used before a rotation,
+// which the operator keeps until every instance has wrapped its data
+// encryption key with the current one
+func (r *InstanceReconciler) getPreviousTDEPassphrases(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]string, error) {
+	var secret corev1.Secret
+	err := r.GetClient().Get(
+		ctx,
+		client.ObjectKey{Namespace: r.instance.Namespace, Name: cluster.GetTDEPassphrasesSecretName()},
+		&secret)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	passphrases := make([]string, 0, len(secret.Data))
+	for _, passphrase := range secret.Data {
+		passphrases = append(passphrases, string(passphrase))
+	}
+	return passphrases, nil
+}
+
 // refreshCertificateFilesFromSecret receive a secret and rewrite the file
 // corresponding to the server certificate
 func (r *InstanceReconciler) refreshCertificateFilesFromSecret(
@@ -1110,17 +1245,32 @@ func (r *InstanceReconciler) reconcilePrimary(ctx context.Context, cluster *apiv
 
 	// If I'm not the primary, let's promote myself
 	if !isPrimary {
+		if err := r.checkPromotionToken(ctx, cluster); err != nil {
+			return false, err
+		}
+
+		if err := checkFailoverArbiterLease(ctx, cluster); err != nil {
+			return false, err
+		}
+
 		cluster.LogTimestampsWithMessage(ctx, "Setting myself as primary")
 		if err := r.handlePromotion(ctx, cluster); err != nil {
 			return false, err
 		}
 		restarted = true
+
+		// This instance can be demoted again in the future
+		r.instance.ResetLifecycleHooks(apiv1.LifecycleHookPreDemotion)
+		r.instance.ResetLifecycleHooks(apiv1.LifecycleHookPostPromotion)
+		r.instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPostPromotion)
 	}
 
 	// if the currentPrimary doesn't match the PodName we set the correct value.
-	if cluster.Status.CurrentPrimary != r.instance.PodName {
+	// The demotion token, if any, belongs to a previous demotion of this cluster
+	if cluster.Status.CurrentPrimary != r.instance.PodName || (restarted && cluster.Status.DemotionToken != "") {
 		cluster.Status.CurrentPrimary = r.instance.PodName
 		cluster.Status.CurrentPrimaryTimestamp = pkgUtils.GetCurrentTimestamp()
+		cluster.Status.DemotionToken = ""
 
 		if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
 			return restarted, err
@@ -1138,6 +1288,51 @@ func (r *InstanceReconciler) reconcilePrimary(ctx context.Context, cluster *apiv
 	return restarted, nil
 }
 
+// checkPromotionToken makes sure that the designated primary of a replica
+// cluster being promoted has replayed the WAL up to the shutdown checkpoint
+// of the demoted primary cluster. In a distributed topology, the promotion
+// waits for a token to be available
+func (r *InstanceReconciler) checkPromotionToken(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.Spec.ReplicaCluster == nil || cluster.Status.CurrentPrimary != r.instance.PodName {
+		return nil
+	}
+
+	token := cluster.GetPromotionToken()
+	if token == "" {
+		if cluster.Spec.ReplicaCluster.Primary == "" {
+			return nil
+		}
+		log.FromContext(ctx).Info("Waiting for the promotion token of the former primary cluster before promoting")
+		return controllers.ErrNextLoop
+	}
+
+	err := r.instance.CheckPromotionToken(token)
+	if errors.Is(err, postgresManagement.ErrPromotionTokenNotReached) {
+		log.FromContext(ctx).Info("Waiting for the promotion token to be reached before promoting", "reason", err.Error())
+		return controllers.ErrNextLoop
+	}
+	if err != nil {
+		return fmt.Errorf("while checking the promotion token: %w", err)
+	}
+
+	return nil
+}
+
+// checkFailoverArbiterLease makes sure that the operator acquired the
+// failover arbiter lease, when configured, before promoting the instance
+func checkFailoverArbiterLease(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.Spec.FailoverArbiter == nil || cluster.Spec.FailoverArbiter.Lease == nil {
+		return nil
+	}
+
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionFailoverArbiterLease)) {
+		log.FromContext(ctx).Info("Waiting for the failover arbiter lease to be acquired before promoting")
+		return controllers.ErrNextLoop
+	}
+
+	return nil
+}
+
 func (r *InstanceReconciler) handlePromotion(ctx context.Context, cluster *apiv1.Cluster) error {
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Info("I'm the target primary, wait for the wal_receiver to be terminated")
@@ -1169,6 +1364,19 @@ func (r *InstanceReconciler) reconcileDesignatedPrimary(
 		return false, nil
 	}
 
+	// The demotion token must be generated while this instance is
+	// still a primary, before writing the replica configuration
+	var demotionToken string
+	if r.instance.RequiresDesignatedPrimaryTransition {
+		if !r.instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion) {
+			log.FromContext(ctx).Info("Waiting for the preDemotion lifecycle hooks to complete")
+			return false, controllers.ErrNextLoop
+		}
+		if demotionToken, err = r.instance.GenerateDemotionToken(ctx); err != nil {
+			return false, err
+		}
+	}
+
 	// We need to ensure that this instance is replicating from the correct server
 	changed, err = r.instance.RefreshReplicaConfiguration(ctx, cluster, r.client)
 	if err != nil {
@@ -1182,6 +1390,7 @@ func (r *InstanceReconciler) reconcileDesignatedPrimary(
 	cluster.Status.CurrentPrimary = r.instance.PodName
 	cluster.Status.CurrentPrimaryTimestamp = pkgUtils.GetCurrentTimestamp()
 	if r.instance.RequiresDesignatedPrimaryTransition {
+		cluster.Status.DemotionToken = demotionToken
 		externalcluster.SetDesignatedPrimaryTransitionCompleted(cluster)
 	}
 	return changed, r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
@@ -1228,7 +1437,13 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 	}
 
 	if cluster.GetEnableSuperuserAccess() {
-		err = r.reconcileUser(ctx, "postgres", cluster.GetSuperuserSecretName(), db)
+		r.forceMD5PasswordRehash(cluster, "postgres",
+			cluster.GetSuperuserSecretName(), cluster.GetSuperuserPasswordFile())
+		if passwordFile := cluster.GetSuperuserPasswordFile(); passwordFile != "" {
+			err = r.reconcileUserFromFile(ctx, "postgres", passwordFile, db)
+		} else {
+			err = r.reconcileUser(ctx, "postgres", cluster.GetSuperuserSecretName(), db)
+		}
 		if err != nil {
 			return err
 		}
@@ -1240,7 +1455,14 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 	}
 
 	if cluster.ShouldCreateApplicationDatabase() {
-		err = r.reconcileUser(ctx, cluster.GetApplicationDatabaseOwner(), cluster.GetApplicationSecretName(), db)
+		owner := cluster.GetApplicationDatabaseOwner()
+		r.forceMD5PasswordRehash(cluster, owner,
+			cluster.GetApplicationSecretName(), cluster.GetApplicationPasswordFile())
+		if passwordFile := cluster.GetApplicationPasswordFile(); passwordFile != "" {
+			err = r.reconcileUserFromFile(ctx, owner, passwordFile, db)
+		} else {
+			err = r.reconcileUser(ctx, owner, cluster.GetApplicationSecretName(), db)
+		}
 		if err != nil {
 			return err
 		}
@@ -1249,6 +1471,21 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 	return nil
 }
 
+// forceMD5PasswordRehash forgets the applied version of the credentials
+// of a user whose password is still stored as an MD5 hash, when the
+// cluster is migrating to SCRAM. This way the next refresh sets the
+// password again, and PostgreSQL stores it as a SCRAM verifier
+func (r *InstanceReconciler) forceMD5PasswordRehash(cluster *apiv1.Cluster, username string, keys ...string) {
+	if !cluster.ShouldMigrateMD5Passwords() ||
+		!slices.Contains(cluster.Status.RolesWithMD5Password, username) {
+		return
+	}
+
+	for _, key := range keys {
+		delete(r.secretVersions, key)
+	}
+}
+
 func (r *InstanceReconciler) reconcileUser(ctx context.Context, username string, secretName string, db *sql.DB) error {
 	var secret corev1.Secret
 	err := r.GetClient().Get(
@@ -1286,6 +1523,38 @@ func (r *InstanceReconciler) reconcileUser(ctx context.Context, username string,
 	return nil
 }
 
+// reconcileUserFromFile sets the password of a user to the content of the
+// passed file, mounted by the Secrets Store CSI driver. The password is
+// only changed when the content of the file changes
+func (r *InstanceReconciler) reconcileUserFromFile(
+	ctx context.Context,
+	username string,
+	passwordFile string,
+	db *sql.DB,
+) error {
+	content, err := fileutils.ReadFile(passwordFile)
+	if err != nil {
+		return fmt.Errorf("while reading the password of %s: %w", username, err)
+	}
+
+	// The secrets versions are tracked by name, and a secret name
+	// can't contain a slash, so the file path can't clash with them
+	checksum := fmt.Sprintf("%x", sha256.Sum256(content))
+	if r.secretVersions[passwordFile] == checksum {
+		return nil
+	}
+
+	password := strings.TrimRight(string(content), "\r\n")
+	if err := postgresutils.SetUserPassword(username, password, db); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Password updated from the secrets store", "username", username)
+	r.secretVersions[passwordFile] = checksum
+
+	return nil
+}
+
 func (r *InstanceReconciler) refreshPGHBA(ctx context.Context, cluster *apiv1.Cluster) (
 	postgresHBAChanged bool,
 	err error,
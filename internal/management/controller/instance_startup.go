@@ -269,7 +269,19 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(ctx context.Context
 			// Then let's go back to the point of the new primary
 			err = r.instance.Rewind(ctx, pgMajorVersion)
 			if err != nil {
-				return err
+				if cluster.GetRejoinStrategy() == apiv1.RejoinStrategyRewind {
+					return err
+				}
+
+				// pg_rewind can't be used to rejoin the cluster, so we
+				// need to get a fresh copy of the data directory
+				contextLogger.Info(
+					"pg_rewind failed, recloning the data directory",
+					"rejoinStrategy", cluster.GetRejoinStrategy(),
+					"err", err)
+				if err := r.instance.Reclone(ctx, r.client, cluster); err != nil {
+					return err
+				}
 			}
 		}
 
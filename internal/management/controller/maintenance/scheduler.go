@@ -0,0 +1,251 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+const (
+	// maxCheckInterval is the maximum time between two checks of the
+	// schedules, so that the changes to the tasks are picked up
+	maxCheckInterval = time.Minute
+
+	// minCheckInterval is the minimum time between two checks of the
+	// schedules
+	minCheckInterval = time.Second
+)
+
+// A Scheduler is a Kubernetes manager.Runnable that executes the scheduled
+// database maintenance tasks when this instance is the primary, and
+// records their runs in the cluster status
+type Scheduler struct {
+	instance *postgres.Instance
+	client   client.Client
+
+	// lastCheck is the last time the schedules have been checked
+	lastCheck time.Time
+
+	// runTask executes a maintenance task
+	runTask func(ctx context.Context, task apiv1.MaintenanceTask) error
+
+	// getActiveSessions counts the active sessions of the instance
+	getActiveSessions func(ctx context.Context) (int, error)
+}
+
+// NewScheduler creates a new database maintenance Scheduler
+func NewScheduler(instance *postgres.Instance, client client.Client) *Scheduler {
+	scheduler := &Scheduler{
+		instance: instance,
+		client:   client,
+	}
+	scheduler.runTask = func(ctx context.Context, task apiv1.MaintenanceTask) error {
+		return runMaintenanceTask(ctx, instance.ConnectionPool(), task)
+	}
+	scheduler.getActiveSessions = func(ctx context.Context) (int, error) {
+		db, err := instance.GetSuperUserDB()
+		if err != nil {
+			return 0, err
+		}
+		return countActiveSessions(ctx, db)
+	}
+	return scheduler
+}
+
+// Start starts running the Scheduler
+func (s *Scheduler) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("database_maintenance_scheduler")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	s.lastCheck = time.Now()
+	timer := time.NewTimer(maxCheckInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			contextLog.Info("Terminated database maintenance scheduler loop")
+			return nil
+		case <-timer.C:
+		}
+
+		next, err := s.check(ctx, time.Now())
+		if err != nil {
+			contextLog.Warning("while executing the database maintenance tasks", "err", err)
+		}
+		timer.Reset(getCheckInterval(next, time.Now()))
+	}
+}
+
+// check executes the tasks that are due since the last check, returning
+// the next time a task will be due
+func (s *Scheduler) check(ctx context.Context, now time.Time) (time.Time, error) {
+	var cluster apiv1.Cluster
+	if err := s.client.Get(ctx, types.NamespacedName{
+		Name:      s.instance.ClusterName,
+		Namespace: s.instance.Namespace,
+	}, &cluster); err != nil {
+		return time.Time{}, err
+	}
+
+	dueTasks, next := getDueTasks(cluster.Spec.DatabaseMaintenance.GetTasks(), s.lastCheck, now)
+	s.lastCheck = now
+	if len(dueTasks) == 0 || !s.canRunTasks(&cluster) {
+		return next, nil
+	}
+
+	runs := make([]apiv1.MaintenanceTaskRun, 0, len(dueTasks))
+	for _, task := range dueTasks {
+		runs = append(runs, s.execute(ctx, &cluster, task))
+	}
+
+	return next, s.recordRuns(ctx, &cluster, runs)
+}
+
+// canRunTasks checks whether this instance is in charge of the
+// maintenance tasks, being a primary accepting writes
+func (s *Scheduler) canRunTasks(cluster *apiv1.Cluster) bool {
+	return cluster.Status.CurrentPrimary == s.instance.PodName &&
+		!cluster.IsReplica() &&
+		!s.instance.IsFenced()
+}
+
+// execute runs a maintenance task unless the instance is under high load
+func (s *Scheduler) execute(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	task apiv1.MaintenanceTask,
+) apiv1.MaintenanceTaskRun {
+	contextLog := log.FromContext(ctx).WithValues("task", task.Name, "type", task.Type)
+
+	startedAt := time.Now()
+	run := apiv1.MaintenanceTaskRun{
+		Name:         task.Name,
+		Type:         task.Type,
+		InstanceName: s.instance.PodName,
+		StartedAt:    startedAt.UTC().Format(time.RFC3339),
+	}
+
+	if maxActiveSessions := cluster.Spec.DatabaseMaintenance.GetMaxActiveSessions(); maxActiveSessions > 0 {
+		activeSessions, err := s.getActiveSessions(ctx)
+		switch {
+		case err != nil:
+			run.Result = apiv1.MaintenanceTaskSkipped
+			run.Message = err.Error()
+		case activeSessions > int(maxActiveSessions):
+			run.Result = apiv1.MaintenanceTaskSkipped
+			run.Message = fmt.Sprintf("%d active sessions, more than the maximum of %d",
+				activeSessions, maxActiveSessions)
+		}
+		if run.Result == apiv1.MaintenanceTaskSkipped {
+			contextLog.Info("Skipping database maintenance task", "reason", run.Message)
+			return run
+		}
+	}
+
+	contextLog.Info("Executing database maintenance task")
+	err := s.runTask(ctx, task)
+	run.Duration = int32(time.Since(startedAt).Seconds())
+	if err != nil {
+		run.Result = apiv1.MaintenanceTaskFailed
+		run.Message = err.Error()
+		contextLog.Error(err, "Database maintenance task failed")
+		return run
+	}
+
+	run.Result = apiv1.MaintenanceTaskSucceeded
+	contextLog.Info("Database maintenance task completed", "duration", run.Duration)
+	return run
+}
+
+// recordRuns adds the passed runs, in execution order, to the
+// maintenance history in the cluster status
+func (s *Scheduler) recordRuns(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	runs []apiv1.MaintenanceTaskRun,
+) error {
+	origCluster := cluster.DeepCopy()
+
+	history := make([]apiv1.MaintenanceTaskRun, 0, len(runs)+len(cluster.Status.MaintenanceHistory))
+	for i := len(runs) - 1; i >= 0; i-- {
+		history = append(history, runs[i])
+	}
+	history = append(history, cluster.Status.MaintenanceHistory...)
+	if len(history) > apiv1.MaxMaintenanceHistory {
+		history = history[:apiv1.MaxMaintenanceHistory]
+	}
+
+	cluster.Status.MaintenanceHistory = history
+	return s.client.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getDueTasks gets the tasks whose schedule fired after the last check and
+// not after now, together with the next time one of the tasks will be due
+func getDueTasks(
+	tasks []apiv1.MaintenanceTask,
+	lastCheck time.Time,
+	now time.Time,
+) ([]apiv1.MaintenanceTask, time.Time) {
+	var dueTasks []apiv1.MaintenanceTask
+	var next time.Time
+	for _, task := range tasks {
+		// The schedules are validated by the webhook
+		schedule, err := cron.Parse(task.Schedule)
+		if err != nil {
+			continue
+		}
+
+		if due := schedule.Next(lastCheck); !due.IsZero() && !due.After(now) {
+			dueTasks = append(dueTasks, task)
+		}
+
+		if upcoming := schedule.Next(now); !upcoming.IsZero() && (next.IsZero() || upcoming.Before(next)) {
+			next = upcoming
+		}
+	}
+
+	return dueTasks, next
+}
+
+// getCheckInterval gets the time to wait before checking the schedules
+// again, given the next time a task will be due
+func getCheckInterval(next time.Time, now time.Time) time.Duration {
+	if next.IsZero() {
+		return maxCheckInterval
+	}
+
+	interval := next.Sub(now)
+	switch {
+	case interval < minCheckInterval:
+		return minCheckInterval
+	case interval > maxCheckInterval:
+		return maxCheckInterval
+	default:
+		return interval
+	}
+}
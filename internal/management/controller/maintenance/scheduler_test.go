@@ -0,0 +1,182 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("maintenance schedules", func() {
+	lastCheck := time.Date(2024, 5, 1, 0, 59, 30, 0, time.UTC)
+
+	It("finds the tasks due since the last check", func() {
+		tasks := []apiv1.MaintenanceTask{
+			{Name: "hourly", Schedule: "0 0 * * * *"},
+			{Name: "nightly", Schedule: "0 0 3 * * *"},
+		}
+
+		due, next := getDueTasks(tasks, lastCheck, lastCheck.Add(time.Minute))
+		Expect(due).To(HaveLen(1))
+		Expect(due[0].Name).To(Equal("hourly"))
+		Expect(next).To(Equal(time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)))
+
+		due, _ = getDueTasks(tasks, lastCheck.Add(time.Minute), lastCheck.Add(2*time.Minute))
+		Expect(due).To(BeEmpty())
+	})
+
+	It("bounds the time between two checks", func() {
+		now := time.Now()
+		Expect(getCheckInterval(time.Time{}, now)).To(Equal(maxCheckInterval))
+		Expect(getCheckInterval(now.Add(time.Hour), now)).To(Equal(maxCheckInterval))
+		Expect(getCheckInterval(now.Add(-time.Second), now)).To(Equal(minCheckInterval))
+		Expect(getCheckInterval(now.Add(10*time.Second), now)).To(Equal(10 * time.Second))
+	})
+})
+
+var _ = Describe("maintenance scheduler", func() {
+	const namespace = "default"
+
+	var (
+		cluster     *apiv1.Cluster
+		cli         client.Client
+		scheduler   *Scheduler
+		executed    []string
+		lastCheck   time.Time
+		now         time.Time
+		activeCount int
+	)
+
+	BeforeEach(func() {
+		lastCheck = time.Date(2024, 5, 1, 0, 59, 30, 0, time.UTC)
+		now = lastCheck.Add(time.Minute)
+		executed = nil
+		activeCount = 0
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				DatabaseMaintenance: &apiv1.DatabaseMaintenanceConfiguration{
+					Tasks: []apiv1.MaintenanceTask{
+						{Name: "analyze", Type: apiv1.MaintenanceTaskAnalyze, Schedule: "0 0 * * * *"},
+						{Name: "reindex", Type: apiv1.MaintenanceTaskReindex, Schedule: "0 0 * * * *"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+
+		instance := postgres.NewInstance()
+		instance.Namespace = namespace
+		instance.ClusterName = cluster.Name
+		instance.PodName = "cluster-example-1"
+		scheduler = &Scheduler{
+			instance:  instance,
+			client:    cli,
+			lastCheck: lastCheck,
+			runTask: func(_ context.Context, task apiv1.MaintenanceTask) error {
+				executed = append(executed, task.Name)
+				if task.Type == apiv1.MaintenanceTaskReindex {
+					return errors.New("exit status 1")
+				}
+				return nil
+			},
+			getActiveSessions: func(context.Context) (int, error) {
+				return activeCount, nil
+			},
+		}
+	})
+
+	getHistory := func(ctx context.Context) []apiv1.MaintenanceTaskRun {
+		var updatedCluster apiv1.Cluster
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return updatedCluster.Status.MaintenanceHistory
+	}
+
+	It("executes the due tasks on the primary and records their runs", func(ctx SpecContext) {
+		next, err := scheduler.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next).To(Equal(time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)))
+		Expect(executed).To(Equal([]string{"analyze", "reindex"}))
+
+		history := getHistory(ctx)
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].Name).To(Equal("reindex"))
+		Expect(history[0].Result).To(Equal(apiv1.MaintenanceTaskFailed))
+		Expect(history[0].Message).To(Equal("exit status 1"))
+		Expect(history[0].InstanceName).To(Equal("cluster-example-1"))
+		Expect(history[1].Name).To(Equal("analyze"))
+		Expect(history[1].Result).To(Equal(apiv1.MaintenanceTaskSucceeded))
+	})
+
+	It("doesn't execute the tasks on the replicas", func(ctx SpecContext) {
+		scheduler.instance.PodName = "cluster-example-2"
+		_, err := scheduler.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(executed).To(BeEmpty())
+		Expect(getHistory(ctx)).To(BeEmpty())
+	})
+
+	It("skips the tasks when the primary is under high load", func(ctx SpecContext) {
+		cluster.Spec.DatabaseMaintenance.MaxActiveSessions = 10
+		Expect(cli.Update(ctx, cluster)).To(Succeed())
+		activeCount = 11
+
+		_, err := scheduler.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(executed).To(BeEmpty())
+
+		history := getHistory(ctx)
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].Result).To(Equal(apiv1.MaintenanceTaskSkipped))
+		Expect(history[0].Message).To(Equal("11 active sessions, more than the maximum of 10"))
+	})
+
+	It("keeps a bounded history", func(ctx SpecContext) {
+		for i := 0; i < apiv1.MaxMaintenanceHistory; i++ {
+			cluster.Status.MaintenanceHistory = append(cluster.Status.MaintenanceHistory,
+				apiv1.MaintenanceTaskRun{Name: fmt.Sprintf("old-%d", i)})
+		}
+		Expect(cli.Status().Update(ctx, cluster)).To(Succeed())
+
+		_, err := scheduler.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		history := getHistory(ctx)
+		Expect(history).To(HaveLen(apiv1.MaxMaintenanceHistory))
+		Expect(history[0].Name).To(Equal("reindex"))
+		Expect(history[2].Name).To(Equal("old-0"))
+	})
+})
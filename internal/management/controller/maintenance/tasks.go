@@ -0,0 +1,223 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+const (
+	vacuumdbName  = "vacuumdb"
+	reindexdbName = "reindexdb"
+)
+
+// runMaintenanceTask executes a maintenance task in every requested
+// database, proceeding with the next database when one fails
+func runMaintenanceTask(ctx context.Context, connectionPool pool.Pooler, task apiv1.MaintenanceTask) error {
+	var errs []error
+	if len(task.Databases) == 0 {
+		if err := runMaintenanceCommand(ctx, task, ""); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		for _, database := range task.Databases {
+			if err := runMaintenanceCommand(ctx, task, database); err != nil {
+				errs = append(errs, fmt.Errorf("database %q: %w", database, err))
+			}
+		}
+	}
+
+	// A REINDEX CONCURRENTLY which failed or was interrupted leaves
+	// behind invalid indexes, which are still updated by every write
+	if task.Type == apiv1.MaintenanceTaskReindex {
+		if err := dropInvalidReindexIndexes(ctx, connectionPool, task.Databases); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runMaintenanceCommand executes the maintenance command of a task in the
+// passed database, or in every database when it is empty. The output of
+// the command is written in the instance manager log, and the command is
+// killed when the context is done
+func runMaintenanceCommand(ctx context.Context, task apiv1.MaintenanceTask, database string) error {
+	commandName, options := buildMaintenanceCommand(task, database, postgres.GetSocketDir(), postgres.GetServerPort())
+	cmd := exec.CommandContext(ctx, commandName, options...) // #nosec G204
+	return execlog.RunStreaming(cmd, commandName)
+}
+
+// dropInvalidReindexIndexes drops, in the passed databases or in every
+// database accepting connections when none is passed, the invalid
+// indexes left behind by an unsuccessful REINDEX CONCURRENTLY
+func dropInvalidReindexIndexes(ctx context.Context, connectionPool pool.Pooler, databases []string) error {
+	if len(databases) == 0 {
+		db, err := connectionPool.Connection("postgres")
+		if err != nil {
+			return err
+		}
+		if databases, err = listConnectableDatabases(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, database := range databases {
+		db, err := connectionPool.Connection(database)
+		if err == nil {
+			err = dropInvalidReindexIndexesInDatabase(ctx, db)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("database %q: %w", database, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listConnectableDatabases lists the databases accepting connections,
+// excluding the templates
+func listConnectableDatabases(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT datname FROM pg_catalog.pg_database
+		WHERE datallowconn AND NOT datistemplate
+		ORDER BY datname`)
+	if err != nil {
+		return nil, fmt.Errorf("while listing the databases: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, fmt.Errorf("while listing the databases: %w", err)
+		}
+		databases = append(databases, database)
+	}
+	return databases, rows.Err()
+}
+
+// dropInvalidReindexIndexesInDatabase drops the invalid "_ccnew" and
+// "_ccold" indexes which REINDEX CONCURRENTLY leaves behind when it
+// doesn't complete
+func dropInvalidReindexIndexesInDatabase(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx,
+		`SELECT pg_catalog.format('%I.%I', n.nspname, c.relname)
+		FROM pg_catalog.pg_index i
+		JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT i.indisvalid
+		AND c.relname ~ '_cc(new|old)[0-9]*$'`)
+	if err != nil {
+		return fmt.Errorf("while looking for invalid indexes: %w", err)
+	}
+
+	var indexes []string
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("while looking for invalid indexes: %w", err)
+		}
+		indexes = append(indexes, index)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("while looking for invalid indexes: %w", err)
+	}
+
+	var errs []error
+	for _, index := range indexes {
+		log.FromContext(ctx).Info("Dropping the invalid index left by REINDEX CONCURRENTLY", "index", index)
+		// The index name is quoted by the query above
+		if _, err := db.ExecContext(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+index); err != nil {
+			errs = append(errs, fmt.Errorf("while dropping the invalid index %s: %w", index, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// buildMaintenanceCommand gets the command and the options executing a
+// maintenance task in the passed database, or in every database when
+// it is empty
+func buildMaintenanceCommand(
+	task apiv1.MaintenanceTask,
+	database string,
+	socketDir string,
+	port int,
+) (string, []string) {
+	commandName := vacuumdbName
+	options := []string{
+		"--host", socketDir,
+		"--port", strconv.Itoa(port),
+		"--username", "postgres",
+		"--no-password",
+	}
+
+	switch task.Type {
+	case apiv1.MaintenanceTaskAnalyze:
+		options = append(options, "--analyze-only")
+	case apiv1.MaintenanceTaskVacuumAnalyze:
+		options = append(options, "--analyze")
+	case apiv1.MaintenanceTaskReindex:
+		commandName = reindexdbName
+		options = append(options, "--concurrently")
+	}
+
+	if jobs := task.GetJobs(); jobs > 1 {
+		options = append(options, "--jobs", strconv.Itoa(int(jobs)))
+	}
+
+	if database == "" {
+		options = append(options, "--all")
+	} else {
+		options = append(options, "--dbname", database)
+	}
+
+	return commandName, options
+}
+
+// countActiveSessions counts the client sessions executing a query,
+// excluding the current one
+func countActiveSessions(ctx context.Context, db *sql.DB) (int, error) {
+	row := db.QueryRowContext(ctx,
+		`SELECT count(*)
+		FROM pg_catalog.pg_stat_activity
+		WHERE state = 'active'
+		AND backend_type = 'client backend'
+		AND pid <> pg_catalog.pg_backend_pid()`)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("while counting the active sessions: %w", err)
+	}
+	return count, nil
+}
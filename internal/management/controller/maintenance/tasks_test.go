@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("maintenance commands", func() {
+	connectionOptions := []string{
+		"--host", "/controller/run",
+		"--port", "5432",
+		"--username", "postgres",
+		"--no-password",
+	}
+
+	It("runs vacuumdb in every database", func() {
+		name, options := buildMaintenanceCommand(
+			apiv1.MaintenanceTask{Type: apiv1.MaintenanceTaskVacuum}, "", "/controller/run", 5432)
+		Expect(name).To(Equal("vacuumdb"))
+		Expect(options).To(Equal(append(connectionOptions, "--all")))
+	})
+
+	It("analyzes a database using multiple jobs", func() {
+		name, options := buildMaintenanceCommand(
+			apiv1.MaintenanceTask{Type: apiv1.MaintenanceTaskAnalyze, Jobs: 4}, "app", "/controller/run", 5432)
+		Expect(name).To(Equal("vacuumdb"))
+		Expect(options).To(Equal(append(connectionOptions,
+			"--analyze-only", "--jobs", "4", "--dbname", "app")))
+	})
+
+	It("vacuums and analyzes a database", func() {
+		_, options := buildMaintenanceCommand(
+			apiv1.MaintenanceTask{Type: apiv1.MaintenanceTaskVacuumAnalyze}, "app", "/controller/run", 5432)
+		Expect(options).To(Equal(append(connectionOptions, "--analyze", "--dbname", "app")))
+	})
+
+	It("rebuilds the indexes concurrently", func() {
+		name, options := buildMaintenanceCommand(
+			apiv1.MaintenanceTask{Type: apiv1.MaintenanceTaskReindex}, "app", "/controller/run", 5432)
+		Expect(name).To(Equal("reindexdb"))
+		Expect(options).To(Equal(append(connectionOptions, "--concurrently", "--dbname", "app")))
+	})
+
+	It("counts the active sessions", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*)\n\t\tFROM pg_catalog.pg_stat_activity")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+		count, err := countActiveSessions(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(count).To(Equal(7))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+	It("drops the invalid indexes left by REINDEX CONCURRENTLY in every database", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT datname FROM pg_catalog.pg_database")).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app").AddRow("postgres"))
+		mock.ExpectQuery(regexp.QuoteMeta("WHERE NOT i.indisvalid")).
+			WillReturnRows(sqlmock.NewRows([]string{"index"}).AddRow(`public."orders_pkey_ccnew"`))
+		mock.ExpectExec(regexp.QuoteMeta(`DROP INDEX CONCURRENTLY IF EXISTS public."orders_pkey_ccnew"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("WHERE NOT i.indisvalid")).
+			WillReturnRows(sqlmock.NewRows([]string{"index"}))
+
+		Expect(dropInvalidReindexIndexes(ctx, fakePooler{db: db}, nil)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports the invalid indexes which cannot be dropped", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta("WHERE NOT i.indisvalid")).
+			WillReturnRows(sqlmock.NewRows([]string{"index"}).AddRow("public.idx_ccold"))
+		mock.ExpectExec(regexp.QuoteMeta("DROP INDEX CONCURRENTLY IF EXISTS public.idx_ccold")).
+			WillReturnError(errors.New("lock timeout"))
+
+		err = dropInvalidReindexIndexes(ctx, fakePooler{db: db}, []string{"app"})
+		Expect(err).To(MatchError(ContainSubstring(`database "app"`)))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,322 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// authQueryUserComment is the comment marking the roles created as users
+// of the auth queries, which are the only roles the instance manager lets
+// log in, changes the password of, and drops when no pooler needs them
+const authQueryUserComment = "Auth query user provisioned by CloudNativePG"
+
+// errRoleNotProvisioned is raised when the user of an auth query is a role
+// which exists but wasn't created by the instance manager
+var errRoleNotProvisioned = errors.New("the role exists and was not created as a user of an auth query")
+
+// reconcileAuthQueryUsers creates the users of the auth queries which
+// the poolers let the operator provision, and keeps their passwords in
+// sync with the content of their secrets. It returns the users which have
+// been provisioned, skipping the roles which already existed, and the
+// previously provisioned roles which are no longer needed
+func (r *InstanceReconciler) reconcileAuthQueryUsers(
+	ctx context.Context,
+	db *sql.DB,
+	integrations *apiv1.PoolerIntegrations,
+) (provisioned []apiv1.PgBouncerAuthQueryUser, stale []string, err error) {
+	contextLogger := log.FromContext(ctx)
+
+	var users []apiv1.PgBouncerAuthQueryUser
+	if integrations != nil {
+		users = integrations.PgBouncerIntegration.AuthQueryUsers
+	}
+
+	for _, user := range users {
+		created, err := ensureLoginRole(ctx, db, user.Name)
+		if errors.Is(err, errRoleNotProvisioned) {
+			contextLogger.Warning("Refusing to provision the auth query user, as the role already exists",
+				"role", user.Name)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("while creating the auth query user %s: %w", user.Name, err)
+		}
+		provisioned = append(provisioned, user)
+
+		if user.PasswordSecret == nil {
+			continue
+		}
+
+		// A role created again, e.g. after having been dropped,
+		// needs its password to be set even if the secret didn't change
+		if created {
+			delete(r.secretVersions, user.PasswordSecret.Name)
+		}
+		if err := r.reconcileUser(ctx, user.Name, user.PasswordSecret.Name, db); err != nil {
+			return nil, nil, fmt.Errorf("while setting the password of the auth query user %s: %w", user.Name, err)
+		}
+	}
+
+	stale, err = getStaleAuthQueryUsers(ctx, db, users)
+	if err != nil {
+		return nil, nil, fmt.Errorf("while listing the auth query users no longer needed: %w", err)
+	}
+
+	return provisioned, stale, nil
+}
+
+// ensureLoginRole creates a role which can log in if it doesn't exist,
+// marking it as provisioned, and lets it log in if it can't. It refuses
+// the roles which it didn't create, and the ones which gained the
+// superuser or the replication attributes. It returns true when the
+// role is created
+func ensureLoginRole(ctx context.Context, db *sql.DB, roleName string) (bool, error) {
+	roleIdentifier := pgx.Identifier{roleName}.Sanitize()
+
+	var canLogin, isSuperuser, isReplication, isProvisioned bool
+	row := db.QueryRowContext(
+		ctx,
+		"SELECT rolcanlogin, rolsuper, rolreplication, "+
+			"COALESCE(pg_catalog.shobj_description(oid, 'pg_authid') = $2, false) "+
+			"FROM pg_catalog.pg_roles WHERE rolname = $1",
+		roleName, authQueryUserComment)
+	err := row.Scan(&canLogin, &isSuperuser, &isReplication, &isProvisioned)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.FromContext(ctx).Info("Creating the auth query user", "role", roleName)
+		statements := []string{
+			fmt.Sprintf("CREATE ROLE %s WITH LOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE NOREPLICATION",
+				roleIdentifier),
+			fmt.Sprintf("COMMENT ON ROLE %s IS %s", roleIdentifier, pq.QuoteLiteral(authQueryUserComment)),
+		}
+		for _, statement := range statements {
+			if _, err := db.ExecContext(ctx, statement); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case err != nil:
+		return false, err
+
+	case !isProvisioned || isSuperuser || isReplication:
+		return false, errRoleNotProvisioned
+
+	case !canLogin:
+		log.FromContext(ctx).Info("Letting the auth query user log in", "role", roleName)
+		_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER ROLE %s WITH LOGIN", roleIdentifier))
+		return false, err
+	}
+
+	return false, nil
+}
+
+// getStaleAuthQueryUsers gets the roles created as users of the auth
+// queries which are not among the passed ones anymore
+func getStaleAuthQueryUsers(
+	ctx context.Context,
+	db *sql.DB,
+	users []apiv1.PgBouncerAuthQueryUser,
+) ([]string, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		"SELECT rolname FROM pg_catalog.pg_roles "+
+			"WHERE pg_catalog.shobj_description(oid, 'pg_authid') = $1 ORDER BY rolname",
+		authQueryUserComment)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var stale []string
+	for rows.Next() {
+		var roleName string
+		if err := rows.Scan(&roleName); err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(users, func(user apiv1.PgBouncerAuthQueryUser) bool {
+			return user.Name == roleName
+		}) {
+			stale = append(stale, roleName)
+		}
+	}
+
+	return stale, rows.Err()
+}
+
+// revokeAuthQueryUsers removes, from the database of the transaction, the
+// privileges granted to the passed auth query users, so that they can be
+// dropped. The objects they may have created are given to the superuser
+// rather than being dropped
+func revokeAuthQueryUsers(ctx context.Context, tx *sql.Tx, roleNames []string) error {
+	for _, roleName := range roleNames {
+		roleIdentifier := pgx.Identifier{roleName}.Sanitize()
+		statements := []string{
+			fmt.Sprintf("REASSIGN OWNED BY %s TO CURRENT_USER", roleIdentifier),
+			fmt.Sprintf("DROP OWNED BY %s", roleIdentifier),
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("while revoking the privileges of the auth query user %s: %w", roleName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// dropAuthQueryUsers drops the passed auth query users, whose privileges
+// must have been revoked in every database
+func dropAuthQueryUsers(ctx context.Context, db *sql.DB, roleNames []string) error {
+	for _, roleName := range roleNames {
+		log.FromContext(ctx).Info("Dropping the auth query user no longer needed", "role", roleName)
+		if _, err := db.ExecContext(ctx,
+			fmt.Sprintf("DROP ROLE IF EXISTS %s", pgx.Identifier{roleName}.Sanitize())); err != nil {
+			return fmt.Errorf("while dropping the auth query user %s: %w", roleName, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureAuthQuerySchema creates, in the database of the transaction, the
+// schema of the function provisioned for an auth query when it doesn't
+// exist. As the owner of a schema can replace the objects it contains, an
+// existing schema is refused unless it is owned by a superuser
+func ensureAuthQuerySchema(ctx context.Context, tx *sql.Tx, schemaName string) error {
+	var isOwnedBySuperuser bool
+	row := tx.QueryRowContext(
+		ctx,
+		"SELECT r.rolsuper FROM pg_catalog.pg_namespace n "+
+			"JOIN pg_catalog.pg_roles r ON n.nspowner = r.oid "+
+			"WHERE n.nspname = $1",
+		schemaName)
+	err := row.Scan(&isOwnedBySuperuser)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA %s", pgx.Identifier{schemaName}.Sanitize()))
+		return err
+
+	case err != nil:
+		return err
+
+	case !isOwnedBySuperuser:
+		return fmt.Errorf("the schema %s is not owned by a superuser", schemaName)
+	}
+
+	return nil
+}
+
+// reconcileAuthQueryFunction creates, in the database of the transaction,
+// the function looking up the password hashes for the auth query when it
+// doesn't exist or differs from the expected one, and grants the passed user
+// the privileges needed to call it. As the function runs with the privileges
+// of its owner, a function owned by a role which is not a superuser is refused
+func reconcileAuthQueryFunction(
+	ctx context.Context,
+	tx *sql.Tx,
+	dbName string,
+	functionSchema string,
+	functionName string,
+	userName string,
+) error {
+	schemaIdentifier := pgx.Identifier{functionSchema}.Sanitize()
+	functionIdentifier := pgx.Identifier{functionSchema, functionName}.Sanitize()
+	userIdentifier := pgx.Identifier{userName}.Sanitize()
+
+	var isOwnedBySuperuser, isInSync bool
+	row := tx.QueryRowContext(
+		ctx,
+		"SELECT r.rolsuper, p.prosrc = $3 AND p.prosecdef AND p.proconfig = ARRAY[$4] "+
+			"FROM pg_catalog.pg_proc p "+
+			"JOIN pg_catalog.pg_namespace n ON p.pronamespace = n.oid "+
+			"JOIN pg_catalog.pg_roles r ON p.proowner = r.oid "+
+			"WHERE n.nspname = $1 AND p.proname = $2 "+
+			"AND pg_catalog.oidvectortypes(p.proargtypes) = 'text'",
+		functionSchema, functionName, userSearchFunction, userSearchFunctionConfig)
+	err := row.Scan(&isOwnedBySuperuser, &isInSync)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return err
+	case !isOwnedBySuperuser:
+		return fmt.Errorf("the function %s is not owned by a superuser", functionIdentifier)
+	}
+
+	if !isInSync {
+		statements := []string{
+			fmt.Sprintf("CREATE OR REPLACE FUNCTION %s(uname TEXT) "+
+				"RETURNS TABLE (usename name, passwd text) "+
+				"as '%s' "+
+				"LANGUAGE sql SECURITY DEFINER "+
+				"SET search_path = pg_catalog, pg_temp",
+				functionIdentifier,
+				userSearchFunction),
+			fmt.Sprintf("REVOKE ALL ON FUNCTION %s(text) FROM public", functionIdentifier),
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+	}
+
+	var canConnect, canUseSchema, canExecute bool
+	row = tx.QueryRowContext(
+		ctx,
+		"SELECT has_database_privilege($1, $2, 'CONNECT'), "+
+			"has_schema_privilege($1, $3, 'USAGE'), "+
+			"has_function_privilege($1, $4, 'EXECUTE')",
+		userName, dbName, functionSchema, functionIdentifier+"(text)")
+	if err := row.Scan(&canConnect, &canUseSchema, &canExecute); err != nil {
+		return err
+	}
+
+	var grants []string
+	if !canConnect {
+		grants = append(grants, fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s",
+			pgx.Identifier{dbName}.Sanitize(), userIdentifier))
+	}
+	if !canUseSchema {
+		grants = append(grants, fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s",
+			schemaIdentifier, userIdentifier))
+	}
+	if !canExecute {
+		grants = append(grants, fmt.Sprintf("GRANT EXECUTE ON FUNCTION %s(text) TO %s",
+			functionIdentifier, userIdentifier))
+	}
+	for _, grant := range grants {
+		if _, err := tx.ExecContext(ctx, grant); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("auth query provisioning", func() {
+	roleColumns := []string{"rolcanlogin", "rolsuper", "rolreplication", "provisioned"}
+
+	It("creates the auth query user when it doesn't exist", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT rolcanlogin").WithArgs("pgbouncer_auth", authQueryUserComment).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec(`CREATE ROLE "pgbouncer_auth" WITH LOGIN NOSUPERUSER`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`COMMENT ON ROLE "pgbouncer_auth" IS 'Auth query user provisioned`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(ensureLoginRole(ctx, db, "pgbouncer_auth")).To(BeTrue())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("lets an existing auth query user log in", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT rolcanlogin").WithArgs("pgbouncer_auth", authQueryUserComment).
+			WillReturnRows(sqlmock.NewRows(roleColumns).AddRow(false, false, false, true))
+		mock.ExpectExec(`ALTER ROLE "pgbouncer_auth" WITH LOGIN`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(ensureLoginRole(ctx, db, "pgbouncer_auth")).To(BeFalse())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	DescribeTable("refuses the roles which weren't created as auth query users",
+		func(ctx SpecContext, isSuperuser, isReplication, isProvisioned bool) {
+			db, mock, err := sqlmock.New()
+			Expect(err).ToNot(HaveOccurred())
+
+			mock.ExpectQuery("SELECT rolcanlogin").WithArgs("app", authQueryUserComment).
+				WillReturnRows(sqlmock.NewRows(roleColumns).AddRow(false, isSuperuser, isReplication, isProvisioned))
+
+			_, err = ensureLoginRole(ctx, db, "app")
+			Expect(err).To(MatchError(errRoleNotProvisioned))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		},
+		Entry("a role created by the user", false, false, false),
+		Entry("a provisioned role which became a superuser", true, false, true),
+		Entry("a provisioned role which can replicate", false, true, true),
+	)
+
+	It("finds the auth query users which are no longer needed", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT rolname").WithArgs(authQueryUserComment).
+			WillReturnRows(sqlmock.NewRows([]string{"rolname"}).AddRow("pgbouncer_auth").AddRow("pgbouncer_old"))
+
+		Expect(getStaleAuthQueryUsers(ctx, db, []apiv1.PgBouncerAuthQueryUser{{Name: "pgbouncer_auth"}})).
+			To(Equal([]string{"pgbouncer_old"}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("revokes the privileges of the auth query users before dropping them", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`REASSIGN OWNED BY "pgbouncer_old" TO CURRENT_USER`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DROP OWNED BY "pgbouncer_old"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DROP ROLE IF EXISTS "pgbouncer_old"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		tx, err := db.BeginTx(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(revokeAuthQueryUsers(ctx, tx, []string{"pgbouncer_old"})).To(Succeed())
+		Expect(dropAuthQueryUsers(ctx, db, []string{"pgbouncer_old"})).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates the schema of the function only when it doesn't exist", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT r.rolsuper FROM pg_catalog.pg_namespace").WithArgs("pgbouncer").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec(`CREATE SCHEMA "pgbouncer"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT r.rolsuper FROM pg_catalog.pg_namespace").WithArgs("app").
+			WillReturnRows(sqlmock.NewRows([]string{"rolsuper"}).AddRow(false))
+
+		tx, err := db.BeginTx(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ensureAuthQuerySchema(ctx, tx, "pgbouncer")).To(Succeed())
+		Expect(ensureAuthQuerySchema(ctx, tx, "app")).To(MatchError(ContainSubstring("not owned by a superuser")))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates the function and grants the privileges which are missing", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT r.rolsuper, p.prosrc").
+			WithArgs("pgbouncer", "lookup", userSearchFunction, userSearchFunctionConfig).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE OR REPLACE FUNCTION "pgbouncer"."lookup"(uname TEXT)`) +
+			".*" + regexp.QuoteMeta("SECURITY DEFINER SET search_path = pg_catalog, pg_temp")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`REVOKE ALL ON FUNCTION "pgbouncer"."lookup"(text) FROM public`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT has_database_privilege").
+			WithArgs("pgbouncer_auth", "app", "pgbouncer", `"pgbouncer"."lookup"(text)`).
+			WillReturnRows(sqlmock.NewRows([]string{"connect", "usage", "execute"}).AddRow(true, false, false))
+		mock.ExpectExec(`GRANT USAGE ON SCHEMA "pgbouncer" TO "pgbouncer_auth"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`GRANT EXECUTE ON FUNCTION "pgbouncer"."lookup"(text) TO "pgbouncer_auth"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		tx, err := db.BeginTx(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconcileAuthQueryFunction(ctx, tx, "app", "pgbouncer", "lookup", "pgbouncer_auth")).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("leaves alone a function which is already in sync", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT r.rolsuper, p.prosrc").
+			WithArgs("public", "user_search", userSearchFunction, userSearchFunctionConfig).
+			WillReturnRows(sqlmock.NewRows([]string{"rolsuper", "in_sync"}).AddRow(true, true))
+		mock.ExpectQuery("SELECT has_database_privilege").
+			WillReturnRows(sqlmock.NewRows([]string{"connect", "usage", "execute"}).AddRow(true, true, true))
+
+		tx, err := db.BeginTx(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconcileAuthQueryFunction(ctx, tx, "app", "public", "user_search", "pgbouncer_auth")).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("refuses a function which is not owned by a superuser", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT r.rolsuper, p.prosrc").
+			WithArgs("pgbouncer", "user_search", userSearchFunction, userSearchFunctionConfig).
+			WillReturnRows(sqlmock.NewRows([]string{"rolsuper", "in_sync"}).AddRow(false, false))
+
+		tx, err := db.BeginTx(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconcileAuthQueryFunction(ctx, tx, "app", "pgbouncer", "user_search", "pgbouncer_auth")).
+			To(MatchError(ContainSubstring("not owned by a superuser")))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
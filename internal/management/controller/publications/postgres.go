@@ -0,0 +1,243 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// publicationInfo is the information about a publication, as read from pg_publication
+type publicationInfo struct {
+	Name      string
+	AllTables bool
+}
+
+// detectPublication reads the information about the passed publication from
+// the catalog, returning nil if it doesn't exist
+func detectPublication(ctx context.Context, db *sql.DB, name string) (*publicationInfo, error) {
+	row := db.QueryRowContext(
+		ctx,
+		"SELECT pubname, puballtables FROM pg_catalog.pg_publication WHERE pubname = $1",
+		name)
+
+	var info publicationInfo
+	err := row.Scan(&info.Name, &info.AllTables)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while detecting publication %s: %w", name, err)
+	}
+
+	return &info, nil
+}
+
+// createPublication creates the publication as described by the passed specification
+func createPublication(ctx context.Context, db *sql.DB, spec *apiv1.PublicationSpec) error {
+	contextLogger := log.FromContext(ctx)
+
+	var query strings.Builder
+	query.WriteString(fmt.Sprintf("CREATE PUBLICATION %s", pgx.Identifier{spec.Name}.Sanitize()))
+	if target := toPublicationTargetSQL(&spec.Target); len(target) > 0 {
+		query.WriteString(fmt.Sprintf(" FOR %s", target))
+	}
+	if len(spec.Parameters) > 0 {
+		query.WriteString(fmt.Sprintf(" WITH (%s)", toParametersSQL(spec.Parameters)))
+	}
+
+	contextLogger.Info("Creating publication", "query", query.String())
+	if _, err := db.ExecContext(ctx, query.String()); err != nil {
+		return fmt.Errorf("while creating publication %s: %w", spec.Name, err)
+	}
+
+	return nil
+}
+
+// updatePublication alters the existing publication to match the passed specification
+func updatePublication(ctx context.Context, db *sql.DB, spec *apiv1.PublicationSpec, info *publicationInfo) error {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{spec.Name}.Sanitize()
+
+	if spec.Target.AllTables != info.AllTables {
+		return fmt.Errorf("publication %s: the allTables target cannot be changed, "+
+			"the publication needs to be recreated", spec.Name)
+	}
+
+	var statements []string
+	if target := toPublicationTargetSQL(&spec.Target); !spec.Target.AllTables && len(target) > 0 {
+		statements = append(statements, fmt.Sprintf("ALTER PUBLICATION %s SET %s", identifier, target))
+	}
+	if len(spec.Parameters) > 0 {
+		statements = append(statements,
+			fmt.Sprintf("ALTER PUBLICATION %s SET (%s)", identifier, toParametersSQL(spec.Parameters)))
+	}
+
+	for _, statement := range statements {
+		contextLogger.Info("Updating publication", "query", statement)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("while updating publication %s: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// dropPublishedObjects removes every table and schema from the passed publication.
+// This is needed when the list of published objects becomes empty, as it can't
+// be set with ALTER PUBLICATION ... SET. Schemas can be published since
+// PostgreSQL 15
+func dropPublishedObjects(ctx context.Context, db *sql.DB, name string, pgMajorVersion uint64) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := `
+		SELECT pg_catalog.format('TABLE %I.%I', n.nspname, c.relname)
+		FROM pg_catalog.pg_publication p
+		JOIN pg_catalog.pg_publication_rel pr ON pr.prpubid = p.oid
+		JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE p.pubname = $1`
+	if pgMajorVersion >= 15 {
+		query += `
+		UNION ALL
+		SELECT pg_catalog.format('TABLES IN SCHEMA %I', n.nspname)
+		FROM pg_catalog.pg_publication p
+		JOIN pg_catalog.pg_publication_namespace pn ON pn.pnpubid = p.oid
+		JOIN pg_catalog.pg_namespace n ON n.oid = pn.pnnspid
+		WHERE p.pubname = $1`
+	}
+
+	rows, err := db.QueryContext(ctx, query, name)
+	if err != nil {
+		return fmt.Errorf("while listing the objects of publication %s: %w", name, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var objects []string
+	for rows.Next() {
+		var object string
+		if err := rows.Scan(&object); err != nil {
+			return fmt.Errorf("while listing the objects of publication %s: %w", name, err)
+		}
+		objects = append(objects, object)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("while listing the objects of publication %s: %w", name, err)
+	}
+
+	if len(objects) == 0 {
+		return nil
+	}
+
+	statement := fmt.Sprintf("ALTER PUBLICATION %s DROP %s",
+		pgx.Identifier{name}.Sanitize(), strings.Join(objects, ", "))
+	contextLogger.Info("Updating publication", "query", statement)
+	if _, err := db.ExecContext(ctx, statement); err != nil {
+		return fmt.Errorf("while updating publication %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// dropPublication drops the publication with the passed name, if it exists
+func dropPublication(ctx context.Context, db *sql.DB, name string) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pgx.Identifier{name}.Sanitize())
+	contextLogger.Info("Dropping publication", "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while dropping publication %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// toPublicationTargetSQL renders the objects published by a publication,
+// as expected after the FOR keyword of CREATE PUBLICATION
+func toPublicationTargetSQL(target *apiv1.PublicationTarget) string {
+	if target.AllTables {
+		return "ALL TABLES"
+	}
+
+	objects := make([]string, 0, len(target.Objects))
+	for _, object := range target.Objects {
+		switch {
+		case len(object.TablesInSchema) > 0:
+			objects = append(objects,
+				fmt.Sprintf("TABLES IN SCHEMA %s", pgx.Identifier{object.TablesInSchema}.Sanitize()))
+
+		case object.Table != nil:
+			objects = append(objects, toPublicationTableSQL(object.Table))
+		}
+	}
+
+	return strings.Join(objects, ", ")
+}
+
+// toPublicationTableSQL renders a table published by a publication
+func toPublicationTableSQL(table *apiv1.PublicationTargetTable) string {
+	var result strings.Builder
+	result.WriteString("TABLE ")
+	if table.Only {
+		result.WriteString("ONLY ")
+	}
+
+	if len(table.Schema) > 0 {
+		result.WriteString(pgx.Identifier{table.Schema, table.Name}.Sanitize())
+	} else {
+		result.WriteString(pgx.Identifier{table.Name}.Sanitize())
+	}
+
+	if len(table.Columns) > 0 {
+		columns := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			columns[i] = pgx.Identifier{column}.Sanitize()
+		}
+		result.WriteString(fmt.Sprintf(" (%s)", strings.Join(columns, ", ")))
+	}
+
+	return result.String()
+}
+
+// toParametersSQL renders the parameters of a publication, as expected
+// by the WITH clause. Parameters are sorted by name to produce a stable output
+func toParametersSQL(parameters map[string]string) string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	options := make([]string, len(keys))
+	for i, key := range keys {
+		options[i] = fmt.Sprintf("%s = %s", pgx.Identifier{key}.Sanitize(), pq.QuoteLiteral(parameters[key]))
+	}
+
+	return strings.Join(options, ", ")
+}
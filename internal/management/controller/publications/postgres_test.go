@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publications
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed publication SQL", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates a publication for all tables", func(ctx SpecContext) {
+		spec := &apiv1.PublicationSpec{
+			Name:       "pub",
+			Target:     apiv1.PublicationTarget{AllTables: true},
+			Parameters: map[string]string{"publish": "insert", "publish_via_partition_root": "true"},
+		}
+		mock.ExpectExec(`CREATE PUBLICATION "pub" FOR ALL TABLES ` +
+			`WITH ("publish" = 'insert', "publish_via_partition_root" = 'true')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(createPublication(ctx, db, spec)).To(Succeed())
+	})
+
+	It("creates a publication for a list of objects", func(ctx SpecContext) {
+		spec := &apiv1.PublicationSpec{
+			Name: "pub",
+			Target: apiv1.PublicationTarget{
+				Objects: []apiv1.PublicationTargetObject{
+					{TablesInSchema: "sales"},
+					{Table: &apiv1.PublicationTargetTable{Name: "users", Schema: "public", Only: true}},
+					{Table: &apiv1.PublicationTargetTable{Name: "orders", Columns: []string{"id", "total"}}},
+				},
+			},
+		}
+		mock.ExpectExec(`CREATE PUBLICATION "pub" FOR TABLES IN SCHEMA "sales", ` +
+			`TABLE ONLY "public"."users", TABLE "orders" ("id", "total")`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(createPublication(ctx, db, spec)).To(Succeed())
+	})
+
+	It("updates the published objects and the parameters", func(ctx SpecContext) {
+		spec := &apiv1.PublicationSpec{
+			Name: "pub",
+			Target: apiv1.PublicationTarget{
+				Objects: []apiv1.PublicationTargetObject{
+					{Table: &apiv1.PublicationTargetTable{Name: "users"}},
+				},
+			},
+			Parameters: map[string]string{"publish": "insert"},
+		}
+		mock.ExpectExec(`ALTER PUBLICATION "pub" SET TABLE "users"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`ALTER PUBLICATION "pub" SET ("publish" = 'insert')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(updatePublication(ctx, db, spec, &publicationInfo{Name: "pub"})).To(Succeed())
+	})
+
+	It("removes every published object", func(ctx SpecContext) {
+		mock.ExpectQuery(`
+		SELECT pg_catalog.format('TABLE %I.%I', n.nspname, c.relname)
+		FROM pg_catalog.pg_publication p
+		JOIN pg_catalog.pg_publication_rel pr ON pr.prpubid = p.oid
+		JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE p.pubname = $1
+		UNION ALL
+		SELECT pg_catalog.format('TABLES IN SCHEMA %I', n.nspname)
+		FROM pg_catalog.pg_publication p
+		JOIN pg_catalog.pg_publication_namespace pn ON pn.pnpubid = p.oid
+		JOIN pg_catalog.pg_namespace n ON n.oid = pn.pnnspid
+		WHERE p.pubname = $1`).
+			WithArgs("pub").
+			WillReturnRows(sqlmock.NewRows([]string{"format"}).
+				AddRow(`TABLE public.users`).
+				AddRow(`TABLES IN SCHEMA sales`))
+		mock.ExpectExec(`ALTER PUBLICATION "pub" DROP TABLE public.users, TABLES IN SCHEMA sales`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(dropPublishedObjects(ctx, db, "pub", 15)).To(Succeed())
+	})
+
+	It("doesn't alter a publication without objects", func(ctx SpecContext) {
+		mock.ExpectQuery(`
+		SELECT pg_catalog.format('TABLE %I.%I', n.nspname, c.relname)
+		FROM pg_catalog.pg_publication p
+		JOIN pg_catalog.pg_publication_rel pr ON pr.prpubid = p.oid
+		JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE p.pubname = $1`).
+			WithArgs("pub").
+			WillReturnRows(sqlmock.NewRows([]string{"format"}))
+
+		Expect(dropPublishedObjects(ctx, db, "pub", 14)).To(Succeed())
+	})
+
+	It("refuses to change the allTables target", func(ctx SpecContext) {
+		spec := &apiv1.PublicationSpec{
+			Name:   "pub",
+			Target: apiv1.PublicationTarget{AllTables: true},
+		}
+
+		Expect(updatePublication(ctx, db, spec, &publicationInfo{Name: "pub"})).ToNot(Succeed())
+	})
+
+	It("reports a missing publication", func(ctx SpecContext) {
+		mock.ExpectQuery("SELECT pubname, puballtables FROM pg_catalog.pg_publication WHERE pubname = $1").
+			WithArgs("pub").
+			WillReturnRows(sqlmock.NewRows([]string{"pubname", "puballtables"}))
+
+		info, err := detectPublication(ctx, db, "pub")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(BeNil())
+	})
+
+	It("drops a publication", func(ctx SpecContext) {
+		mock.ExpectExec(`DROP PUBLICATION IF EXISTS "pub"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(dropPublication(ctx, db, "pub")).To(Succeed())
+	})
+})
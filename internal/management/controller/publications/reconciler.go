@@ -0,0 +1,193 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publications
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// publicationReconciliationInterval is the time between two reconciliations
+// of the same Publication, used to detect drifts and role changes
+const publicationReconciliationInterval = 30 * time.Second
+
+// Reconcile is the main reconciliation loop for the Publication objects
+func (r *PublicationReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("publication_reconciler").WithValues("publication", req.Name)
+	// if the context has already been cancelled,
+	// trying to reconcile would just lead to misleading errors being reported
+	if err := ctx.Err(); err != nil {
+		contextLogger.Warning("Context cancelled, will not start publication reconcile", "err", err)
+		return reconcile.Result{}, nil
+	}
+
+	var publication apiv1.Publication
+	if err := r.GetClient().Get(ctx, req.NamespacedName, &publication); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This Publication belongs to another cluster
+	if publication.Spec.ClusterRef.Name != r.instance.ClusterName {
+		return reconcile.Result{}, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !isPrimary {
+		contextLogger.Debug("skipping the publication reconciler in replicas")
+		return reconcile.Result{RequeueAfter: publicationReconciliationInterval}, nil
+	}
+
+	// Fetch the Cluster from the cache
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The cluster has been deleted.
+			// We just need to wait for this instance manager to be terminated
+			contextLogger.Debug("Could not find Cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	if cluster.IsReplica() {
+		contextLogger.Debug("skipping the publication reconciler in replica clusters")
+		return reconcile.Result{RequeueAfter: publicationReconciliationInterval}, nil
+	}
+
+	if r.instance.IsServerReady() != nil {
+		contextLogger.Debug("database not ready, skipping publication reconciling")
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+
+	if err := r.reconcileFinalizer(ctx, &publication); err != nil {
+		return reconcile.Result{}, err
+	}
+	if !publication.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	reconcileErr := r.reconcilePublication(ctx, &publication)
+
+	origPublication := publication.DeepCopy()
+	publication.Status.ObservedGeneration = publication.Generation
+	publication.Status.Applied = ptr.To(reconcileErr == nil)
+	if reconcileErr != nil {
+		publication.Status.Message = reconcileErr.Error()
+	} else {
+		publication.Status.Message = ""
+	}
+	if err := r.GetClient().Status().Patch(ctx, &publication, client.MergeFrom(origPublication)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while setting the publication reconciler status: %w", err)
+	}
+
+	return reconcile.Result{RequeueAfter: publicationReconciliationInterval}, nil
+}
+
+// reconcileFinalizer ensures the finalizer is set when the publication needs to be
+// dropped on deletion, and drops it when the Publication object is being deleted
+func (r *PublicationReconciler) reconcileFinalizer(ctx context.Context, publication *apiv1.Publication) error {
+	origPublication := publication.DeepCopy()
+
+	if publication.DeletionTimestamp.IsZero() {
+		if publication.GetReclaimPolicy() != apiv1.PublicationReclaimDelete ||
+			!controllerutil.AddFinalizer(publication, apiv1.PublicationFinalizerName) {
+			return nil
+		}
+		return r.GetClient().Patch(ctx, publication, client.MergeFrom(origPublication))
+	}
+
+	if !controllerutil.ContainsFinalizer(publication, apiv1.PublicationFinalizerName) {
+		return nil
+	}
+
+	if publication.GetReclaimPolicy() == apiv1.PublicationReclaimDelete {
+		err := r.withDatabase(publication.Spec.DBName, func(db *sql.DB) error {
+			return dropPublication(ctx, db, publication.Spec.Name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(publication, apiv1.PublicationFinalizerName)
+	return r.GetClient().Patch(ctx, publication, client.MergeFrom(origPublication))
+}
+
+// reconcilePublication applies the Publication specification to PostgreSQL
+func (r *PublicationReconciler) reconcilePublication(
+	ctx context.Context,
+	publication *apiv1.Publication,
+) error {
+	return r.withDatabase(publication.Spec.DBName, func(db *sql.DB) error {
+		info, err := detectPublication(ctx, db, publication.Spec.Name)
+		if err != nil {
+			return err
+		}
+
+		if info == nil {
+			return createPublication(ctx, db, &publication.Spec)
+		}
+		if err := updatePublication(ctx, db, &publication.Spec, info); err != nil {
+			return err
+		}
+
+		if publication.Spec.Target.AllTables || len(publication.Spec.Target.Objects) > 0 {
+			return nil
+		}
+		pgVersion, err := r.instance.GetPgVersion()
+		if err != nil {
+			return err
+		}
+		return dropPublishedObjects(ctx, db, publication.Spec.Name, pgVersion.Major)
+	})
+}
+
+// withDatabase runs the passed function with a connection to the passed database.
+// We use a dedicated connection, which is closed at the end of the
+// reconciliation, to avoid blocking a subsequent drop of the database
+func (r *PublicationReconciler) withDatabase(dbname string, f func(db *sql.DB) error) error {
+	db, err := pool.NewDBConnection(
+		r.instance.ConnectionPool().GetDsn(dbname),
+		pool.ConnectionProfilePostgresql,
+	)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", dbname, err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	return f(db)
+}
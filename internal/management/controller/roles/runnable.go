@@ -21,6 +21,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -34,6 +35,10 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
 
+// roleDriftCheckInterval is the time between two reconciliations of the
+// managed roles that are not triggered by a change in the cluster
+const roleDriftCheckInterval = time.Minute
+
 // roleAction encodes the action necessary for a role, i.e. ignore, or CRUD
 type roleAction string
 
@@ -86,11 +91,22 @@ func (sr *RoleSynchronizer) Start(ctx context.Context) error {
 			contextLog.Info("Terminated RoleSynchronizer loop")
 		}()
 
+		ticker := time.NewTicker(roleDriftCheckInterval)
+		defer ticker.Stop()
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case config = <-sr.instance.RoleSynchronizerChan():
+			case <-ticker.C:
+				// Periodically reconcile the managed roles, to correct any drift
+				// introduced by changes made directly in PostgreSQL
+				var err error
+				if config, err = sr.getManagedConfigurationForDriftCheck(ctx); err != nil {
+					contextLog.Error(err, "while checking managed roles for drifts")
+					continue
+				}
 			}
 			contextLog.Debug("RoleSynchronizer loop triggered")
 
@@ -111,6 +127,31 @@ func (sr *RoleSynchronizer) Start(ctx context.Context) error {
 	return nil
 }
 
+// getManagedConfigurationForDriftCheck gets the managed configuration from the
+// latest version of the cluster, returning nil when this instance is not the
+// primary and the roles must not be reconciled
+func (sr *RoleSynchronizer) getManagedConfigurationForDriftCheck(
+	ctx context.Context,
+) (*apiv1.ManagedConfiguration, error) {
+	isPrimary, err := sr.instance.IsPrimary()
+	if err != nil {
+		return nil, err
+	}
+	if !isPrimary {
+		return nil, nil
+	}
+
+	var cluster apiv1.Cluster
+	if err := sr.client.Get(ctx, types.NamespacedName{
+		Name:      sr.instance.ClusterName,
+		Namespace: sr.instance.Namespace,
+	}, &cluster); err != nil {
+		return nil, err
+	}
+
+	return cluster.Spec.Managed, nil
+}
+
 // reconcile applied any necessary changes to the database to bring it in line
 // with the spec. It also updates the cluster Status with the latest applied changes
 func (sr *RoleSynchronizer) reconcile(ctx context.Context, config *apiv1.ManagedConfiguration) error {
@@ -153,6 +194,12 @@ func (sr *RoleSynchronizer) reconcile(ctx context.Context, config *apiv1.Managed
 		return fmt.Errorf("while syncrhonizing managed roles: %w", err)
 	}
 
+	if remoteCluster.ShouldMigrateMD5Passwords() {
+		if err = sr.rehashMD5Passwords(ctx, roleManager, config, appliedState); err != nil {
+			return fmt.Errorf("while migrating MD5 passwords: %w", err)
+		}
+	}
+
 	if err = sr.client.Get(ctx, types.NamespacedName{
 		Name:      sr.instance.ClusterName,
 		Namespace: sr.instance.Namespace,
@@ -209,6 +256,53 @@ func (sr *RoleSynchronizer) synchronizeRoles(
 	return storedPasswordState, irreconcilableRoles, nil
 }
 
+// rehashMD5Passwords sets again, from their secrets, the passwords of the
+// managed roles that are still stored as MD5 hashes. As the instances run
+// with password_encryption set to scram-sha-256, PostgreSQL stores the
+// new passwords as SCRAM verifiers. The applied changes are merged into
+// the passed password state
+func (sr *RoleSynchronizer) rehashMD5Passwords(
+	ctx context.Context,
+	roleManager RoleManager,
+	config *apiv1.ManagedConfiguration,
+	passwordState map[string]apiv1.PasswordState,
+) error {
+	contextLog := log.FromContext(ctx).WithName("roles_reconciler")
+
+	rolesInDB, err := roleManager.List(ctx)
+	if err != nil {
+		return err
+	}
+	md5Roles := make(map[string]DatabaseRole, len(rolesInDB))
+	for _, role := range rolesInDB {
+		if role.password.Valid && strings.HasPrefix(role.password.String, "md5") {
+			md5Roles[role.Name] = role
+		}
+	}
+
+	for _, role := range config.Roles {
+		roleInDB, isMD5 := md5Roles[role.Name]
+		if !isMD5 || role.Ensure == apiv1.EnsureAbsent ||
+			role.PasswordSecret == nil || role.DisablePassword {
+			continue
+		}
+
+		internalRole := roleConfigurationAdapter{
+			RoleConfiguration:        role,
+			validUntilNullIsInfinity: roleInDB.ValidUntil.Valid,
+		}
+		appliedState, err := sr.applyRoleCreateUpdate(ctx, roleManager, internalRole, roleUpdate)
+		if err != nil {
+			contextLog.Error(err, "while migrating the MD5 password of a role", "role", role.Name)
+			continue
+		}
+		contextLog.Info("Migrated the MD5 password of a role", "role", role.Name)
+		passwordState[role.Name] = appliedState
+	}
+
+	return nil
+}
+
 // applyRoleActions applies the actions to reconcile roles in the DB with the Spec
 // It returns the apiv1.PasswordState for each role, as well as a map of roles that
 // cannot be reconciled for expectable errors, e.g. dropping a role owning content
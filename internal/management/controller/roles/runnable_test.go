@@ -18,7 +18,10 @@ package roles
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	corev1 "k8s.io/api/core/v1"
@@ -919,3 +922,113 @@ var _ = DescribeTable("role secrets test",
 		true,
 	),
 )
+
+var _ = Describe("Role drift detection", func() {
+	var (
+		cluster *apiv1.Cluster
+		sr      *RoleSynchronizer
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{Name: "app", Ensure: apiv1.EnsurePresent},
+					},
+				},
+			},
+		}
+		instance := postgres.NewInstance()
+		instance.PgData = GinkgoT().TempDir()
+		instance.ClusterName = cluster.Name
+		instance.Namespace = cluster.Namespace
+		sr = NewRoleSynchronizer(
+			instance,
+			fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).WithObjects(cluster).Build(),
+		)
+	})
+
+	It("uses the managed configuration of the latest cluster on the primary", func(ctx context.Context) {
+		config, err := sr.getManagedConfigurationForDriftCheck(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(Equal(cluster.Spec.Managed))
+	})
+
+	It("skips the drift detection on replicas", func(ctx context.Context) {
+		Expect(os.WriteFile(filepath.Join(sr.instance.PgData, "standby.signal"), nil, 0o600)).To(Succeed())
+
+		config, err := sr.getManagedConfigurationForDriftCheck(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(BeNil())
+	})
+})
+
+var _ = Describe("MD5 password migration", func() {
+	It("sets again only the MD5 passwords of the roles with a secret", func(ctx context.Context) {
+		const namespace = "default"
+		secret := corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "legacy-secret",
+				Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("legacy"),
+				corev1.BasicAuthPasswordKey: []byte("s3cret"),
+			},
+		}
+		sr := RoleSynchronizer{
+			instance: &postgres.Instance{Namespace: namespace},
+			client: fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(&secret).Build(),
+		}
+		managedConf := apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{
+					Name:           "legacy",
+					Ensure:         apiv1.EnsurePresent,
+					PasswordSecret: &apiv1.LocalObjectReference{Name: "legacy-secret"},
+				},
+				{
+					Name:           "modern",
+					Ensure:         apiv1.EnsurePresent,
+					PasswordSecret: &apiv1.LocalObjectReference{Name: "modern-secret"},
+				},
+				{
+					Name:   "unmanaged-password",
+					Ensure: apiv1.EnsurePresent,
+				},
+			},
+		}
+		rm := mockRoleManager{
+			roles: map[string]DatabaseRole{
+				"legacy": {
+					Name:     "legacy",
+					password: sql.NullString{Valid: true, String: "md5a3556571e93b0d20722ba62be61e8c2d"},
+				},
+				"modern": {
+					Name:     "modern",
+					password: sql.NullString{Valid: true, String: "SCRAM-SHA-256$4096:salt$key"},
+				},
+				"unmanaged-password": {
+					Name:     "unmanaged-password",
+					password: sql.NullString{Valid: true, String: "md5a3556571e93b0d20722ba62be61e8c2d"},
+				},
+			},
+		}
+
+		passwordState := map[string]apiv1.PasswordState{}
+		Expect(sr.rehashMD5Passwords(ctx, &rm, &managedConf, passwordState)).To(Succeed())
+		Expect(rm.callHistory).To(ConsistOf(
+			funcCall{"list", ""},
+			funcCall{"update", "legacy"},
+		))
+		Expect(rm.roles["legacy"].password.String).To(Equal("s3cret"))
+		Expect(passwordState).To(HaveKey("legacy"))
+		Expect(passwordState["legacy"].SecretResourceVersion).ToNot(BeEmpty())
+	})
+})
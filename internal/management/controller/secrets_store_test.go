@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("passwords from the Secrets Store", func() {
+	It("applies the password only when the file changes", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		reconciler := &InstanceReconciler{secretVersions: make(map[string]string)}
+
+		passwordFile := filepath.Join(GinkgoT().TempDir(), "app-password")
+		Expect(os.WriteFile(passwordFile, []byte("first\n"), 0o600)).To(Succeed())
+
+		mock.ExpectExec(`ALTER ROLE "app" WITH PASSWORD 'first'`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(reconciler.reconcileUserFromFile(ctx, "app", passwordFile, db)).To(Succeed())
+		Expect(reconciler.reconcileUserFromFile(ctx, "app", passwordFile, db)).To(Succeed())
+
+		Expect(os.WriteFile(passwordFile, []byte("second"), 0o600)).To(Succeed())
+		mock.ExpectExec(`ALTER ROLE "app" WITH PASSWORD 'second'`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(reconciler.reconcileUserFromFile(ctx, "app", passwordFile, db)).To(Succeed())
+
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("fails when the file is missing", func(ctx SpecContext) {
+		db, _, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		reconciler := &InstanceReconciler{secretVersions: make(map[string]string)}
+
+		err = reconciler.reconcileUserFromFile(ctx, "app", filepath.Join(GinkgoT().TempDir(), "missing"), db)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("sets again the password of a user still stored as MD5 during a migration", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		reconciler := &InstanceReconciler{secretVersions: make(map[string]string)}
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					PasswordAuthentication: &apiv1.PasswordAuthenticationConfiguration{MigrateMD5: true},
+				},
+			},
+			Status: apiv1.ClusterStatus{RolesWithMD5Password: []string{"app"}},
+		}
+
+		passwordFile := filepath.Join(GinkgoT().TempDir(), "app-password")
+		Expect(os.WriteFile(passwordFile, []byte("first"), 0o600)).To(Succeed())
+
+		mock.ExpectExec(`ALTER ROLE "app" WITH PASSWORD 'first'`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(reconciler.reconcileUserFromFile(ctx, "app", passwordFile, db)).To(Succeed())
+
+		reconciler.forceMD5PasswordRehash(cluster, "postgres", passwordFile)
+		Expect(reconciler.secretVersions).To(HaveKey(passwordFile))
+
+		mock.ExpectExec(`ALTER ROLE "app" WITH PASSWORD 'first'`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		reconciler.forceMD5PasswordRehash(cluster, "app", passwordFile)
+		Expect(reconciler.reconcileUserFromFile(ctx, "app", passwordFile, db)).To(Succeed())
+
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inactive
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// metricsNamespace is the namespace of the inactive replication slots
+	// metrics, the same used for the other metrics exposed by the
+	// instance manager
+	metricsNamespace = "cnpg"
+
+	// metricsSubsystem is the subsystem of the inactive replication slots
+	// metrics
+	metricsSubsystem = "instance_manager_replication_slots"
+)
+
+var (
+	inactiveSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "inactive_seconds",
+		Help:      "Time since the replication slot has been detected as inactive on the primary.",
+	}, []string{"slot_name", "slot_type"})
+
+	retainedWALBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "inactive_retained_wal_bytes",
+		Help:      "Amount of WAL retained on the primary by the inactive replication slot.",
+	}, []string{"slot_name", "slot_type"})
+
+	leakedSlots = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "leaked",
+		Help:      "Number of replication slots inactive for more than the retention time.",
+	})
+
+	droppedSlotsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "dropped_total",
+		Help:      "Total number of inactive replication slots dropped, by outcome.",
+	}, []string{"outcome"})
+)
+
+// Labels used by the inactive replication slots metrics
+const (
+	dropSucceeded = "succeeded"
+	dropFailed    = "failed"
+)
+
+// Collectors returns the collectors of the metrics of the inactive
+// replication slots, to be exposed by the metrics server
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		inactiveSeconds,
+		retainedWALBytes,
+		leakedSlots,
+		droppedSlotsTotal,
+	}
+}
+
+// resetMetrics clears the metrics describing the inactive replication
+// slots, as when this instance is not the primary anymore
+func resetMetrics() {
+	inactiveSeconds.Reset()
+	retainedWALBytes.Reset()
+	leakedSlots.Set(0)
+}
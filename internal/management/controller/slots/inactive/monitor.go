@@ -0,0 +1,256 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inactive
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// slotState is what the Monitor knows about an inactive replication slot
+type slotState struct {
+	// inactiveSince is when the slot has been first seen inactive
+	inactiveSince time.Time
+
+	// reported is true when the slot has already been reported as leaked
+	reported bool
+}
+
+// A Monitor is a Kubernetes manager.Runnable that detects the replication
+// slots that are inactive on the primary instance, reporting via events
+// and metrics the ones inactive for more than the retention time and,
+// if requested, dropping them
+type Monitor struct {
+	instance *postgres.Instance
+	client   client.Client
+	recorder record.EventRecorder
+
+	// inactiveSlots are the replication slots currently seen inactive
+	inactiveSlots map[string]*slotState
+
+	// listSlots gets the replication slots of the instance
+	listSlots func(ctx context.Context) ([]replicationSlot, error)
+
+	// dropSlot drops a replication slot from the instance
+	dropSlot func(ctx context.Context, slotName string) error
+}
+
+// NewMonitor creates a new inactive replication slots Monitor
+func NewMonitor(instance *postgres.Instance, client client.Client, recorder record.EventRecorder) *Monitor {
+	return &Monitor{
+		instance:      instance,
+		client:        client,
+		recorder:      recorder,
+		inactiveSlots: make(map[string]*slotState),
+		listSlots: func(ctx context.Context) ([]replicationSlot, error) {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return nil, err
+			}
+			return listReplicationSlots(ctx, db)
+		},
+		dropSlot: func(ctx context.Context, slotName string) error {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return err
+			}
+			return dropReplicationSlot(ctx, db, slotName)
+		},
+	}
+}
+
+// Start starts running the Monitor
+func (m *Monitor) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("inactive_slots_monitor")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	timer := time.NewTimer(apiv1.DefaultReplicationSlotsUpdateInterval * time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			contextLog.Info("Terminated inactive replication slots monitor loop")
+			return nil
+		case <-timer.C:
+		}
+
+		interval, err := m.check(ctx, time.Now())
+		if err != nil {
+			contextLog.Warning("while checking the inactive replication slots", "err", err)
+		}
+		timer.Reset(interval)
+	}
+}
+
+// check updates the state of the replication slots of the instance,
+// returning the time to wait before checking them again
+func (m *Monitor) check(ctx context.Context, now time.Time) (time.Duration, error) {
+	var cluster apiv1.Cluster
+	if err := m.client.Get(ctx, types.NamespacedName{
+		Name:      m.instance.ClusterName,
+		Namespace: m.instance.Namespace,
+	}, &cluster); err != nil {
+		return apiv1.DefaultReplicationSlotsUpdateInterval * time.Second, err
+	}
+
+	interval := cluster.Spec.ReplicationSlots.GetUpdateInterval()
+	if cluster.Status.CurrentPrimary != m.instance.PodName || m.instance.IsFenced() {
+		m.reset()
+		return interval, nil
+	}
+
+	slots, err := m.listSlots(ctx)
+	if err != nil {
+		return interval, err
+	}
+
+	m.update(ctx, &cluster, slots, now)
+	return interval, nil
+}
+
+// update tracks the inactive replication slots, reporting and dropping
+// the ones inactive for more than the retention time
+func (m *Monitor) update(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	slots []replicationSlot,
+	now time.Time,
+) {
+	contextLog := log.FromContext(ctx)
+	config := cluster.Spec.ReplicationSlots.GetInactiveSlots()
+	var haConfig *apiv1.ReplicationSlotsHAConfiguration
+	if cluster.Spec.ReplicationSlots != nil {
+		haConfig = cluster.Spec.ReplicationSlots.HighAvailability
+	}
+	haSlotPrefix := haConfig.GetSlotPrefix()
+
+	resetMetrics()
+	seen := make(map[string]bool, len(slots))
+	leaked := 0
+	for _, slot := range slots {
+		// The replication slots for HA are managed by the operator
+		if strings.HasPrefix(slot.SlotName, haSlotPrefix) {
+			continue
+		}
+
+		excluded, err := config.IsExcluded(slot.SlotName)
+		if err != nil {
+			contextLog.Warning("while matching the exclude patterns", "slotName", slot.SlotName, "err", err)
+			continue
+		}
+		if excluded || slot.Active {
+			continue
+		}
+
+		seen[slot.SlotName] = true
+		state, ok := m.inactiveSlots[slot.SlotName]
+		if !ok {
+			state = &slotState{inactiveSince: now}
+			m.inactiveSlots[slot.SlotName] = state
+		}
+
+		inactiveFor := now.Sub(state.inactiveSince)
+		if inactiveFor < config.GetRetentionTime() {
+			inactiveSeconds.WithLabelValues(slot.SlotName, slot.SlotType).Set(inactiveFor.Seconds())
+			retainedWALBytes.WithLabelValues(slot.SlotName, slot.SlotType).Set(float64(slot.RetainedWALBytes))
+			continue
+		}
+
+		if config.GetDrop() {
+			err := m.drop(ctx, cluster, slot, inactiveFor)
+			if err == nil {
+				delete(seen, slot.SlotName)
+				continue
+			}
+			contextLog.Error(err, "while dropping leaked replication slot", "slotName", slot.SlotName)
+			if !state.reported {
+				m.recorder.Eventf(cluster, corev1.EventTypeWarning, events.DropReplicationSlotFailed,
+					"Failed to drop the inactive replication slot %q: %v", slot.SlotName, err)
+			}
+		}
+
+		leaked++
+		inactiveSeconds.WithLabelValues(slot.SlotName, slot.SlotType).Set(inactiveFor.Seconds())
+		retainedWALBytes.WithLabelValues(slot.SlotName, slot.SlotType).Set(float64(slot.RetainedWALBytes))
+		if !state.reported {
+			contextLog.Info("Detected leaked replication slot",
+				"slotName", slot.SlotName,
+				"slotType", slot.SlotType,
+				"inactiveFor", inactiveFor.Round(time.Second).String(),
+				"retainedWALBytes", slot.RetainedWALBytes)
+			m.recorder.Eventf(cluster, corev1.EventTypeWarning, events.InactiveReplicationSlot,
+				"The %s replication slot %q has been inactive for %s, retaining %d bytes of WAL",
+				slot.SlotType, slot.SlotName, inactiveFor.Round(time.Second).String(),
+				slot.RetainedWALBytes)
+			state.reported = true
+		}
+	}
+	leakedSlots.Set(float64(leaked))
+
+	// Forget the slots that have been dropped or are now active
+	for slotName := range m.inactiveSlots {
+		if !seen[slotName] {
+			delete(m.inactiveSlots, slotName)
+		}
+	}
+}
+
+// drop drops a leaked replication slot
+func (m *Monitor) drop(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	slot replicationSlot,
+	inactiveFor time.Duration,
+) error {
+	if err := m.dropSlot(ctx, slot.SlotName); err != nil {
+		droppedSlotsTotal.WithLabelValues(dropFailed).Inc()
+		return err
+	}
+
+	droppedSlotsTotal.WithLabelValues(dropSucceeded).Inc()
+	log.FromContext(ctx).Info("Dropped leaked replication slot",
+		"slotName", slot.SlotName,
+		"slotType", slot.SlotType,
+		"inactiveFor", inactiveFor.Round(time.Second).String(),
+		"retainedWALBytes", slot.RetainedWALBytes)
+	m.recorder.Eventf(cluster, corev1.EventTypeNormal, events.DroppedReplicationSlot,
+		"Dropped the %s replication slot %q, inactive for %s and retaining %d bytes of WAL",
+		slot.SlotType, slot.SlotName, inactiveFor.Round(time.Second).String(),
+		slot.RetainedWALBytes)
+	return nil
+}
+
+// reset forgets the state of the replication slots, as this instance
+// is not the primary anymore
+func (m *Monitor) reset() {
+	if len(m.inactiveSlots) > 0 {
+		m.inactiveSlots = make(map[string]*slotState)
+	}
+	resetMetrics()
+}
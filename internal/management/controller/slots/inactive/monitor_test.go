@@ -0,0 +1,187 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inactive
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("inactive replication slots monitor", func() {
+	const namespace = "default"
+
+	var (
+		cluster  *apiv1.Cluster
+		monitor  *Monitor
+		recorder *record.FakeRecorder
+		slots    []replicationSlot
+		dropped  []string
+		dropErr  error
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		dropped = nil
+		dropErr = nil
+		slots = []replicationSlot{
+			{SlotName: "_cnpg_cluster_example_2", SlotType: "physical"},
+			{SlotName: "debezium", SlotType: "logical", RetainedWALBytes: 1024},
+			{SlotName: "backup_tool", SlotType: "physical", RetainedWALBytes: 2048},
+			{SlotName: "standby", SlotType: "physical", Active: true},
+		}
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				ReplicationSlots: &apiv1.ReplicationSlotsConfiguration{
+					UpdateInterval: 10,
+					InactiveSlots: &apiv1.InactiveSlotsConfiguration{
+						RetentionTime:   600,
+						ExcludePatterns: []string{"^backup_"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+
+		instance := postgres.NewInstance()
+		instance.Namespace = namespace
+		instance.ClusterName = cluster.Name
+		instance.PodName = "cluster-example-1"
+
+		recorder = record.NewFakeRecorder(10)
+		monitor = &Monitor{
+			instance:      instance,
+			recorder:      recorder,
+			inactiveSlots: make(map[string]*slotState),
+			listSlots: func(context.Context) ([]replicationSlot, error) {
+				return slots, nil
+			},
+			dropSlot: func(_ context.Context, slotName string) error {
+				dropped = append(dropped, slotName)
+				return dropErr
+			},
+		}
+	})
+
+	setCluster := func() {
+		monitor.client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+	}
+
+	It("tracks the inactive slots and reports the leaked ones once", func(ctx SpecContext) {
+		setCluster()
+
+		interval, err := monitor.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interval).To(Equal(10 * time.Second))
+		Expect(monitor.inactiveSlots).To(HaveLen(1))
+		Expect(monitor.inactiveSlots).To(HaveKey("debezium"))
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(testutil.ToFloat64(leakedSlots)).To(BeZero())
+
+		_, err = monitor.check(ctx, now.Add(11*time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("InactiveReplicationSlot")))
+		Expect(testutil.ToFloat64(leakedSlots)).To(BeEquivalentTo(1))
+		Expect(testutil.ToFloat64(inactiveSeconds.WithLabelValues("debezium", "logical"))).
+			To(BeEquivalentTo(660))
+		Expect(testutil.ToFloat64(retainedWALBytes.WithLabelValues("debezium", "logical"))).
+			To(BeEquivalentTo(1024))
+
+		_, err = monitor.check(ctx, now.Add(12*time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(dropped).To(BeEmpty())
+	})
+
+	It("forgets the slots that become active again", func(ctx SpecContext) {
+		setCluster()
+
+		_, err := monitor.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		slots[1].Active = true
+		_, err = monitor.check(ctx, now.Add(5*time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(monitor.inactiveSlots).To(BeEmpty())
+
+		slots[1].Active = false
+		_, err = monitor.check(ctx, now.Add(11*time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(monitor.inactiveSlots["debezium"].inactiveSince).To(Equal(now.Add(11 * time.Minute)))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("drops the leaked slots when requested", func(ctx SpecContext) {
+		cluster.Spec.ReplicationSlots.InactiveSlots.Drop = true
+		setCluster()
+
+		_, err := monitor.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dropped).To(BeEmpty())
+
+		_, err = monitor.check(ctx, now.Add(11*time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dropped).To(Equal([]string{"debezium"}))
+		Expect(recorder.Events).To(Receive(ContainSubstring("DroppedReplicationSlot")))
+		Expect(monitor.inactiveSlots).To(BeEmpty())
+		Expect(testutil.ToFloat64(leakedSlots)).To(BeZero())
+	})
+
+	It("reports the slots that can't be dropped", func(ctx SpecContext) {
+		cluster.Spec.ReplicationSlots.InactiveSlots.Drop = true
+		setCluster()
+		dropErr = errors.New("replication slot \"debezium\" is active")
+
+		_, err := monitor.check(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = monitor.check(ctx, now.Add(11*time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("DropReplicationSlotFailed")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("InactiveReplicationSlot")))
+		Expect(testutil.ToFloat64(leakedSlots)).To(BeEquivalentTo(1))
+	})
+
+	It("does nothing when this instance is not the primary", func(ctx SpecContext) {
+		cluster.Status.CurrentPrimary = "cluster-example-2"
+		setCluster()
+		monitor.inactiveSlots["debezium"] = &slotState{inactiveSince: now}
+
+		_, err := monitor.check(ctx, now.Add(time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(monitor.inactiveSlots).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(testutil.ToFloat64(leakedSlots)).To(BeZero())
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inactive
+
+import (
+	"context"
+	"database/sql"
+)
+
+// replicationSlot is a replication slot, as seen in the primary
+type replicationSlot struct {
+	SlotName         string
+	SlotType         string
+	Active           bool
+	RetainedWALBytes int64
+}
+
+// listReplicationSlots gets the non-temporary physical and logical
+// replication slots defined in the instance, together with the amount
+// of WAL they are retaining
+func listReplicationSlots(ctx context.Context, db *sql.DB) ([]replicationSlot, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT slot_name, slot_type, active,
+			COALESCE(pg_catalog.pg_wal_lsn_diff(
+				CASE WHEN pg_catalog.pg_is_in_recovery()
+					THEN pg_catalog.pg_last_wal_replay_lsn()
+					ELSE pg_catalog.pg_current_wal_lsn()
+				END, restart_lsn), 0)::bigint
+		FROM pg_catalog.pg_replication_slots
+		WHERE NOT temporary
+		ORDER BY slot_name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var slots []replicationSlot
+	for rows.Next() {
+		var slot replicationSlot
+		if err := rows.Scan(
+			&slot.SlotName,
+			&slot.SlotType,
+			&slot.Active,
+			&slot.RetainedWALBytes,
+		); err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, rows.Err()
+}
+
+// dropReplicationSlot drops a replication slot. PostgreSQL refuses to
+// drop it if, in the meantime, a consumer started using it
+func dropReplicationSlot(ctx context.Context, db *sql.DB, slotName string) error {
+	_, err := db.ExecContext(ctx, "SELECT pg_catalog.pg_drop_replication_slot($1)", slotName)
+	return err
+}
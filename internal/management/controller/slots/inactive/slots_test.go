@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inactive
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication slots queries", func() {
+	It("lists the replication slots with the retained WAL", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("FROM pg_catalog.pg_replication_slots").
+			WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "active", "retained"}).
+				AddRow("_cnpg_cluster_example_2", "physical", true, 0).
+				AddRow("debezium", "logical", false, 16777216))
+
+		slots, err := listReplicationSlots(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(slots).To(Equal([]replicationSlot{
+			{SlotName: "_cnpg_cluster_example_2", SlotType: "physical", Active: true},
+			{SlotName: "debezium", SlotType: "logical", RetainedWALBytes: 16777216},
+		}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("drops a replication slot", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectExec("SELECT pg_catalog.pg_drop_replication_slot").
+			WithArgs("debezium").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(dropReplicationSlot(ctx, db, "debezium")).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -18,11 +18,13 @@ package infrastructure
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 )
 
 // PostgresManager is a Manager for a database instance
@@ -51,10 +53,22 @@ func (sm PostgresManager) List(
 		return ReplicationSlotList{}, err
 	}
 
+	version, err := utils.GetPgVersion(db)
+	if err != nil {
+		return ReplicationSlotList{}, err
+	}
+
+	// The WAL status of the replication slots is available since PostgreSQL 13
+	walStatusColumn := "''"
+	if version.Major >= 13 {
+		walStatusColumn = "coalesce(wal_status, '')"
+	}
+
 	rows, err := db.QueryContext(
 		ctx,
-		`SELECT slot_name, slot_type, active, coalesce(restart_lsn::TEXT, '') AS restart_lsn FROM pg_replication_slots
-            WHERE NOT temporary AND slot_type = 'physical'`,
+		fmt.Sprintf(`SELECT slot_name, slot_type, active, coalesce(restart_lsn::TEXT, '') AS restart_lsn,
+            %s AS wal_status FROM pg_replication_slots
+            WHERE NOT temporary AND slot_type = 'physical'`, walStatusColumn), // #nosec G201
 	)
 	if err != nil {
 		return ReplicationSlotList{}, err
@@ -71,6 +85,7 @@ func (sm PostgresManager) List(
 			&slot.Type,
 			&slot.Active,
 			&slot.RestartLSN,
+			&slot.WALStatus,
 		)
 		if err != nil {
 			return ReplicationSlotList{}, err
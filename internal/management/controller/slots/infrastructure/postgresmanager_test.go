@@ -87,11 +87,14 @@ var _ = Describe("PostgresManager", func() {
 		})
 
 		It("should successfully list replication slots", func() {
-			rows := sqlmock.NewRows([]string{"slot_name", "slot_type", "active", "restart_lsn"}).
-				AddRow("_cnpg_slot1", string(SlotTypePhysical), true, "lsn1").
-				AddRow("slot2", string(SlotTypePhysical), true, "lsn2")
+			mock.ExpectQuery("SHOW server_version_num").
+				WillReturnRows(sqlmock.NewRows([]string{"server_version_num"}).AddRow("160002"))
 
-			mock.ExpectQuery("^SELECT (.+) FROM pg_replication_slots").
+			rows := sqlmock.NewRows([]string{"slot_name", "slot_type", "active", "restart_lsn", "wal_status"}).
+				AddRow("_cnpg_slot1", string(SlotTypePhysical), true, "lsn1", "reserved").
+				AddRow("slot2", string(SlotTypePhysical), true, "lsn2", "lost")
+
+			mock.ExpectQuery("^SELECT (.+) coalesce\\(wal_status, ''\\) (.+) FROM pg_replication_slots").
 				WillReturnRows(rows)
 
 			result, err := manager.List(context.Background(), config)
@@ -105,15 +108,34 @@ var _ = Describe("PostgresManager", func() {
 			Expect(slot1.Active).To(BeTrue())
 			Expect(slot1.RestartLSN).To(Equal("lsn1"))
 			Expect(slot1.IsHA).To(BeTrue())
+			Expect(slot1.IsLost()).To(BeFalse())
 
 			slot2 := result.Get("slot2")
 			Expect(slot2.Type).To(Equal(SlotTypePhysical))
 			Expect(slot2.Active).To(BeTrue())
 			Expect(slot2.RestartLSN).To(Equal("lsn2"))
 			Expect(slot2.IsHA).To(BeFalse())
+			Expect(slot2.IsLost()).To(BeTrue())
+		})
+
+		It("should not read the WAL status before PostgreSQL 13", func() {
+			mock.ExpectQuery("SHOW server_version_num").
+				WillReturnRows(sqlmock.NewRows([]string{"server_version_num"}).AddRow("120010"))
+
+			rows := sqlmock.NewRows([]string{"slot_name", "slot_type", "active", "restart_lsn", "wal_status"}).
+				AddRow("_cnpg_slot1", string(SlotTypePhysical), true, "lsn1", "")
+
+			mock.ExpectQuery("^SELECT (.+) '' AS wal_status FROM pg_replication_slots").
+				WillReturnRows(rows)
+
+			result, err := manager.List(context.Background(), config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Get("_cnpg_slot1").IsLost()).To(BeFalse())
 		})
 
 		It("should return error when database query fails", func() {
+			mock.ExpectQuery("SHOW server_version_num").
+				WillReturnRows(sqlmock.NewRows([]string{"server_version_num"}).AddRow("160002"))
 			mock.ExpectQuery("^SELECT (.+) FROM pg_replication_slots").
 				WillReturnError(errors.New("mock error"))
 
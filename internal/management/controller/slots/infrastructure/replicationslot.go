@@ -22,6 +22,10 @@ type SlotType string
 // SlotTypePhysical represents the physical replication slot
 const SlotTypePhysical SlotType = "physical"
 
+// WALStatusLost is the WAL status of a replication slot that has been
+// invalidated, as the WAL files it required have been removed
+const WALStatusLost = "lost"
+
 // ReplicationSlot represents a single replication slot
 type ReplicationSlot struct {
 	SlotName   string   `json:"slotName,omitempty"`
@@ -29,6 +33,13 @@ type ReplicationSlot struct {
 	Active     bool     `json:"active"`
 	RestartLSN string   `json:"restartLSN,omitempty"`
 	IsHA       bool     `json:"isHA,omitempty"`
+	WALStatus  string   `json:"walStatus,omitempty"`
+}
+
+// IsLost returns true if the replication slot has been invalidated, as happens
+// when the WAL it retains exceeds `max_slot_wal_keep_size`
+func (slot ReplicationSlot) IsLost() bool {
+	return slot.WALStatus == WALStatusLost
 }
 
 // ReplicationSlotList contains a list of replication slots
@@ -83,8 +83,19 @@ func reconcilePrimaryHAReplicationSlots(
 		slotName := cluster.GetSlotNameFromInstanceName(instanceName)
 		expectedSlots[slotName] = true
 
-		if currentSlots.Has(slotName) {
-			continue
+		if slot := currentSlots.Get(slotName); slot != nil {
+			if !slot.IsLost() || slot.Active {
+				continue
+			}
+
+			// The slot has been invalidated because the standby fell too far
+			// behind. We recreate it, so that the standby can resume streaming
+			// once it catches up with the WAL archive
+			contextLogger.Info("Recreating invalidated HA replication slot", "slot", slotName)
+			if err := manager.Delete(ctx, *slot); err != nil {
+				return reconcile.Result{}, fmt.Errorf("dropping invalidated HA replication slot %q: %w",
+					slotName, err)
+			}
 		}
 
 		// At this point, the cluster instance does not have a HA replication slot
@@ -35,6 +35,7 @@ type fakeSlot struct {
 	name   string
 	active bool
 	isHA   bool
+	lost   bool
 }
 
 type fakeReplicationSlotManager struct {
@@ -55,7 +56,12 @@ func (fk fakeReplicationSlotManager) Delete(_ context.Context, slot infrastructu
 	if fk.triggerDeleteError {
 		return errors.New("triggered delete error")
 	}
-	delete(fk.replicationSlots, fakeSlot{name: slot.SlotName, active: slot.Active, isHA: slot.IsHA})
+	delete(fk.replicationSlots, fakeSlot{
+		name:   slot.SlotName,
+		active: slot.Active,
+		isHA:   slot.IsHA,
+		lost:   slot.IsLost(),
+	})
 	return nil
 }
 
@@ -73,13 +79,17 @@ func (fk fakeReplicationSlotManager) List(
 	}
 
 	for slot := range fk.replicationSlots {
-		slotList.Items = append(slotList.Items, infrastructure.ReplicationSlot{
+		replicationSlot := infrastructure.ReplicationSlot{
 			SlotName:   slot.name,
 			RestartLSN: "",
 			Type:       infrastructure.SlotTypePhysical,
 			Active:     slot.active,
 			IsHA:       slot.isHA,
-		})
+		}
+		if slot.lost {
+			replicationSlot.WALStatus = infrastructure.WALStatusLost
+		}
+		slotList.Items = append(slotList.Items, replicationSlot)
 	}
 	return slotList, nil
 }
@@ -103,6 +113,25 @@ func makeClusterWithInstanceNames(instanceNames []string, primary string) apiv1.
 }
 
 var _ = Describe("HA Replication Slots reconciliation in Primary", func() {
+	It("recreates an invalidated HA replication slot", func() {
+		fakeSlotManager := fakeReplicationSlotManager{
+			replicationSlots: map[fakeSlot]bool{
+				{name: slotPrefix + "instance2", isHA: true, lost: true}:               true,
+				{name: slotPrefix + "instance3", isHA: true, active: true, lost: true}: true,
+			},
+		}
+
+		cluster := makeClusterWithInstanceNames([]string{"instance1", "instance2", "instance3"}, "instance1")
+
+		_, err := ReconcileReplicationSlots(context.TODO(), "instance1", fakeSlotManager, &cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(fakeSlotManager.replicationSlots).To(HaveLen(2))
+		Expect(fakeSlotManager.replicationSlots[fakeSlot{name: "_cnpg_instance2", isHA: true}]).To(BeTrue())
+		Expect(fakeSlotManager.replicationSlots[fakeSlot{
+			name: "_cnpg_instance3", isHA: true, active: true, lost: true,
+		}]).To(BeTrue())
+	})
+
 	It("can create a new replication slot for a new cluster instance", func() {
 		fakeSlotManager := fakeReplicationSlotManager{
 			replicationSlots: map[fakeSlot]bool{
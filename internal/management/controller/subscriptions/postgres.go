@@ -0,0 +1,259 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// subscriptionInfo is the information about a subscription, as read from pg_subscription
+type subscriptionInfo struct {
+	Name         string
+	ConnInfo     string
+	Publications []string
+}
+
+// subscriptionProgress is the progress of the apply worker of a subscription,
+// as read from pg_stat_subscription
+type subscriptionProgress struct {
+	SlotName      string
+	ReceivedLSN   string
+	LatestEndLSN  string
+	LatestEndTime string
+}
+
+// getSubscriptionConnectionString gets the connection string to be used by the
+// subscription to connect to the publisher, as defined in the external clusters
+func getSubscriptionConnectionString(cluster *apiv1.Cluster, spec *apiv1.SubscriptionSpec) (string, error) {
+	server, ok := cluster.ExternalCluster(spec.ExternalClusterName)
+	if !ok {
+		return "", fmt.Errorf("external cluster %s not found in the cluster definition",
+			spec.ExternalClusterName)
+	}
+
+	if len(spec.PublicationDBName) > 0 {
+		server.ConnectionParameters = maps.Clone(server.ConnectionParameters)
+		if server.ConnectionParameters == nil {
+			server.ConnectionParameters = make(map[string]string, 1)
+		}
+		server.ConnectionParameters["dbname"] = spec.PublicationDBName
+	}
+
+	return external.GetServerConnectionString(&server), nil
+}
+
+// detectSubscription reads the information about the passed subscription from
+// the catalog, returning nil if it doesn't exist in the current database
+func detectSubscription(ctx context.Context, db *sql.DB, name string) (*subscriptionInfo, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT subname, subconninfo, subpublications
+		FROM pg_catalog.pg_subscription
+		WHERE subname = $1
+		  AND subdbid = (SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database())
+		`,
+		name)
+
+	var info subscriptionInfo
+	var publications pq.StringArray
+	err := row.Scan(&info.Name, &info.ConnInfo, &publications)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while detecting subscription %s: %w", name, err)
+	}
+	info.Publications = publications
+
+	return &info, nil
+}
+
+// createSubscription creates the subscription as described by the passed specification
+func createSubscription(
+	ctx context.Context,
+	db *sql.DB,
+	spec *apiv1.SubscriptionSpec,
+	connectionString string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var query strings.Builder
+	query.WriteString(fmt.Sprintf(
+		"CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s",
+		pgx.Identifier{spec.Name}.Sanitize(),
+		pq.QuoteLiteral(connectionString),
+		pgx.Identifier{spec.PublicationName}.Sanitize(),
+	))
+	if len(spec.Parameters) > 0 {
+		query.WriteString(fmt.Sprintf(" WITH (%s)", toParametersSQL(spec.Parameters)))
+	}
+
+	// The connection string may contain sensitive information,
+	// so we avoid logging the query
+	contextLogger.Info("Creating subscription", "name", spec.Name)
+	if _, err := db.ExecContext(ctx, query.String()); err != nil {
+		return fmt.Errorf("while creating subscription %s: %w", spec.Name, err)
+	}
+
+	return nil
+}
+
+// updateSubscription alters the existing subscription to match the passed specification
+func updateSubscription(
+	ctx context.Context,
+	db *sql.DB,
+	spec *apiv1.SubscriptionSpec,
+	connectionString string,
+	info *subscriptionInfo,
+) error {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{spec.Name}.Sanitize()
+
+	var statements []string
+	if info.ConnInfo != connectionString {
+		statements = append(statements,
+			fmt.Sprintf("ALTER SUBSCRIPTION %s CONNECTION %s", identifier, pq.QuoteLiteral(connectionString)))
+	}
+	if !slices.Equal(info.Publications, []string{spec.PublicationName}) {
+		statements = append(statements,
+			fmt.Sprintf("ALTER SUBSCRIPTION %s SET PUBLICATION %s",
+				identifier, pgx.Identifier{spec.PublicationName}.Sanitize()))
+	}
+
+	for _, statement := range statements {
+		contextLogger.Info("Updating subscription", "name", spec.Name)
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("while updating subscription %s: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// dropSubscription drops the subscription with the passed name, if it exists
+func dropSubscription(ctx context.Context, db *sql.DB, name string) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := fmt.Sprintf("DROP SUBSCRIPTION IF EXISTS %s", pgx.Identifier{name}.Sanitize())
+	contextLogger.Info("Dropping subscription", "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while dropping subscription %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// getSubscriptionProgress reads the progress of the apply worker of the
+// passed subscription. The LSNs are empty when the worker is not running.
+// Since PostgreSQL 16 the parallel apply workers are listed too, without a
+// relation like the leader apply worker, so we skip the ones having a leader
+func getSubscriptionProgress(
+	ctx context.Context,
+	db *sql.DB,
+	name string,
+	pgMajorVersion uint64,
+) (*subscriptionProgress, error) {
+	workerCondition := "st.relid IS NULL"
+	if pgMajorVersion >= 16 {
+		workerCondition += " AND st.leader_pid IS NULL"
+	}
+
+	row := db.QueryRowContext(
+		ctx,
+		fmt.Sprintf(`
+		SELECT s.subslotname, st.received_lsn, st.latest_end_lsn, st.latest_end_time
+		FROM pg_catalog.pg_subscription s
+		LEFT JOIN pg_catalog.pg_stat_subscription st ON st.subid = s.oid AND %s
+		WHERE s.subname = $1
+		  AND s.subdbid = (SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database())
+		`, workerCondition),
+		name)
+
+	var slotName, receivedLSN, latestEndLSN sql.NullString
+	var latestEndTime sql.NullTime
+	if err := row.Scan(&slotName, &receivedLSN, &latestEndLSN, &latestEndTime); err != nil {
+		return nil, fmt.Errorf("while getting the progress of subscription %s: %w", name, err)
+	}
+
+	progress := &subscriptionProgress{
+		SlotName:     slotName.String,
+		ReceivedLSN:  receivedLSN.String,
+		LatestEndLSN: latestEndLSN.String,
+	}
+	if latestEndTime.Valid {
+		progress.LatestEndTime = latestEndTime.Time.Format(time.RFC3339)
+	}
+
+	return progress, nil
+}
+
+// getSlotLag reads, on the publisher, the amount of WAL not yet confirmed
+// by the passed replication slot, returning nil if the slot doesn't exist
+func getSlotLag(ctx context.Context, db *sql.DB, slotName string) (*int64, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn)::bigint
+		FROM pg_catalog.pg_replication_slots
+		WHERE slot_name = $1
+		`,
+		slotName)
+
+	var lag sql.NullInt64
+	err := row.Scan(&lag)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !lag.Valid) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while getting the lag of replication slot %s: %w", slotName, err)
+	}
+
+	return &lag.Int64, nil
+}
+
+// toParametersSQL renders the parameters of a subscription, as expected
+// by the WITH clause. Parameters are sorted by name to produce a stable output
+func toParametersSQL(parameters map[string]string) string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	options := make([]string, len(keys))
+	for i, key := range keys {
+		options[i] = fmt.Sprintf("%s = %s", pgx.Identifier{key}.Sanitize(), pq.QuoteLiteral(parameters[key]))
+	}
+
+	return strings.Join(options, ", ")
+}
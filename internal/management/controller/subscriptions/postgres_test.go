@@ -0,0 +1,193 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptions
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subscription connection string", func() {
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			ExternalClusters: []apiv1.ExternalCluster{
+				{
+					Name: "source",
+					ConnectionParameters: map[string]string{
+						"host":   "source-rw",
+						"dbname": "app",
+					},
+				},
+			},
+		},
+	}
+
+	It("uses the external cluster connection parameters", func() {
+		connectionString, err := getSubscriptionConnectionString(cluster, &apiv1.SubscriptionSpec{
+			ExternalClusterName: "source",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(connectionString).To(Equal("dbname='app' host='source-rw'"))
+	})
+
+	It("overrides the database name when requested", func() {
+		connectionString, err := getSubscriptionConnectionString(cluster, &apiv1.SubscriptionSpec{
+			ExternalClusterName: "source",
+			PublicationDBName:   "sales",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(connectionString).To(Equal("dbname='sales' host='source-rw'"))
+		Expect(cluster.Spec.ExternalClusters[0].ConnectionParameters["dbname"]).To(Equal("app"))
+	})
+
+	It("fails when the external cluster doesn't exist", func() {
+		_, err := getSubscriptionConnectionString(cluster, &apiv1.SubscriptionSpec{
+			ExternalClusterName: "missing",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Managed subscription SQL", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	const connectionString = "dbname='app' host='source-rw'"
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates a subscription with the requested parameters", func(ctx SpecContext) {
+		spec := &apiv1.SubscriptionSpec{
+			Name:            "sub",
+			PublicationName: "pub",
+			Parameters:      map[string]string{"copy_data": "false"},
+		}
+		mock.ExpectExec(`CREATE SUBSCRIPTION "sub" CONNECTION 'dbname=''app'' host=''source-rw''' ` +
+			`PUBLICATION "pub" WITH ("copy_data" = 'false')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(createSubscription(ctx, db, spec, connectionString)).To(Succeed())
+	})
+
+	It("doesn't alter a subscription matching the specification", func(ctx SpecContext) {
+		spec := &apiv1.SubscriptionSpec{Name: "sub", PublicationName: "pub"}
+		info := &subscriptionInfo{Name: "sub", ConnInfo: connectionString, Publications: []string{"pub"}}
+
+		Expect(updateSubscription(ctx, db, spec, connectionString, info)).To(Succeed())
+	})
+
+	It("alters the connection and the publication when they differ", func(ctx SpecContext) {
+		spec := &apiv1.SubscriptionSpec{Name: "sub", PublicationName: "pub"}
+		info := &subscriptionInfo{Name: "sub", ConnInfo: "host='old'", Publications: []string{"old"}}
+		mock.ExpectExec(`ALTER SUBSCRIPTION "sub" CONNECTION 'dbname=''app'' host=''source-rw'''`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`ALTER SUBSCRIPTION "sub" SET PUBLICATION "pub"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(updateSubscription(ctx, db, spec, connectionString, info)).To(Succeed())
+	})
+
+	It("drops a subscription", func(ctx SpecContext) {
+		mock.ExpectExec(`DROP SUBSCRIPTION IF EXISTS "sub"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(dropSubscription(ctx, db, "sub")).To(Succeed())
+	})
+
+	It("reads the progress of the subscription", func(ctx SpecContext) {
+		latestEndTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`
+		SELECT s.subslotname, st.received_lsn, st.latest_end_lsn, st.latest_end_time
+		FROM pg_catalog.pg_subscription s
+		LEFT JOIN pg_catalog.pg_stat_subscription st ON st.subid = s.oid AND st.relid IS NULL
+		WHERE s.subname = $1
+		  AND s.subdbid = (SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database())
+		`).
+			WithArgs("sub").
+			WillReturnRows(sqlmock.NewRows(
+				[]string{"subslotname", "received_lsn", "latest_end_lsn", "latest_end_time"}).
+				AddRow("sub", "0/3000060", "0/3000028", latestEndTime))
+
+		progress, err := getSubscriptionProgress(ctx, db, "sub", 15)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*progress).To(Equal(subscriptionProgress{
+			SlotName:      "sub",
+			ReceivedLSN:   "0/3000060",
+			LatestEndLSN:  "0/3000028",
+			LatestEndTime: "2024-05-01T10:00:00Z",
+		}))
+	})
+
+	It("skips the parallel apply workers since PostgreSQL 16", func(ctx SpecContext) {
+		mock.ExpectQuery(`
+		SELECT s.subslotname, st.received_lsn, st.latest_end_lsn, st.latest_end_time
+		FROM pg_catalog.pg_subscription s
+		LEFT JOIN pg_catalog.pg_stat_subscription st ON st.subid = s.oid AND st.relid IS NULL AND st.leader_pid IS NULL
+		WHERE s.subname = $1
+		  AND s.subdbid = (SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database())
+		`).
+			WithArgs("sub").
+			WillReturnRows(sqlmock.NewRows(
+				[]string{"subslotname", "received_lsn", "latest_end_lsn", "latest_end_time"}).
+				AddRow(nil, nil, nil, nil))
+
+		progress, err := getSubscriptionProgress(ctx, db, "sub", 16)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*progress).To(Equal(subscriptionProgress{}))
+	})
+
+	It("reads the lag of the replication slot on the publisher", func(ctx SpecContext) {
+		query := `
+		SELECT pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn)::bigint
+		FROM pg_catalog.pg_replication_slots
+		WHERE slot_name = $1
+		`
+		mock.ExpectQuery(query).
+			WithArgs("sub").
+			WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(1024))
+
+		lag, err := getSlotLag(ctx, db, "sub")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lag).ToNot(BeNil())
+		Expect(*lag).To(BeEquivalentTo(1024))
+
+		mock.ExpectQuery(query).
+			WithArgs("missing").
+			WillReturnRows(sqlmock.NewRows([]string{"lag"}))
+
+		lag, err = getSlotLag(ctx, db, "missing")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lag).To(BeNil())
+	})
+})
@@ -0,0 +1,236 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// subscriptionReconciliationInterval is the time between two reconciliations
+// of the same Subscription, used to detect drifts, role changes and to
+// refresh the replication progress
+const subscriptionReconciliationInterval = 30 * time.Second
+
+// Reconcile is the main reconciliation loop for the Subscription objects
+func (r *SubscriptionReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("subscription_reconciler").WithValues("subscription", req.Name)
+	// if the context has already been cancelled,
+	// trying to reconcile would just lead to misleading errors being reported
+	if err := ctx.Err(); err != nil {
+		contextLogger.Warning("Context cancelled, will not start subscription reconcile", "err", err)
+		return reconcile.Result{}, nil
+	}
+
+	var subscription apiv1.Subscription
+	if err := r.GetClient().Get(ctx, req.NamespacedName, &subscription); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This Subscription belongs to another cluster
+	if subscription.Spec.ClusterRef.Name != r.instance.ClusterName {
+		return reconcile.Result{}, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !isPrimary {
+		contextLogger.Debug("skipping the subscription reconciler in replicas")
+		return reconcile.Result{RequeueAfter: subscriptionReconciliationInterval}, nil
+	}
+
+	// Fetch the Cluster from the cache
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The cluster has been deleted.
+			// We just need to wait for this instance manager to be terminated
+			contextLogger.Debug("Could not find Cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	if cluster.IsReplica() {
+		contextLogger.Debug("skipping the subscription reconciler in replica clusters")
+		return reconcile.Result{RequeueAfter: subscriptionReconciliationInterval}, nil
+	}
+
+	if r.instance.IsServerReady() != nil {
+		contextLogger.Debug("database not ready, skipping subscription reconciling")
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+
+	if err := r.reconcileFinalizer(ctx, &subscription); err != nil {
+		return reconcile.Result{}, err
+	}
+	if !subscription.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	origSubscription := subscription.DeepCopy()
+	reconcileErr := r.reconcileSubscription(ctx, cluster, &subscription)
+
+	subscription.Status.ObservedGeneration = subscription.Generation
+	subscription.Status.Applied = ptr.To(reconcileErr == nil)
+	if reconcileErr != nil {
+		subscription.Status.Message = reconcileErr.Error()
+	} else {
+		subscription.Status.Message = ""
+	}
+	if err := r.GetClient().Status().Patch(ctx, &subscription, client.MergeFrom(origSubscription)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while setting the subscription reconciler status: %w", err)
+	}
+
+	return reconcile.Result{RequeueAfter: subscriptionReconciliationInterval}, nil
+}
+
+// reconcileFinalizer ensures the finalizer is set when the subscription needs to be
+// dropped on deletion, and drops it when the Subscription object is being deleted
+func (r *SubscriptionReconciler) reconcileFinalizer(ctx context.Context, subscription *apiv1.Subscription) error {
+	origSubscription := subscription.DeepCopy()
+
+	if subscription.DeletionTimestamp.IsZero() {
+		if subscription.GetReclaimPolicy() != apiv1.SubscriptionReclaimDelete ||
+			!controllerutil.AddFinalizer(subscription, apiv1.SubscriptionFinalizerName) {
+			return nil
+		}
+		return r.GetClient().Patch(ctx, subscription, client.MergeFrom(origSubscription))
+	}
+
+	if !controllerutil.ContainsFinalizer(subscription, apiv1.SubscriptionFinalizerName) {
+		return nil
+	}
+
+	if subscription.GetReclaimPolicy() == apiv1.SubscriptionReclaimDelete {
+		err := r.withDatabase(r.instance.ConnectionPool().GetDsn(subscription.Spec.DBName), func(db *sql.DB) error {
+			return dropSubscription(ctx, db, subscription.Spec.Name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(subscription, apiv1.SubscriptionFinalizerName)
+	return r.GetClient().Patch(ctx, subscription, client.MergeFrom(origSubscription))
+}
+
+// reconcileSubscription applies the Subscription specification to PostgreSQL,
+// and updates the replication progress in the status of the passed object
+func (r *SubscriptionReconciler) reconcileSubscription(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	subscription *apiv1.Subscription,
+) error {
+	connectionString, err := getSubscriptionConnectionString(cluster, &subscription.Spec)
+	if err != nil {
+		return err
+	}
+
+	pgVersion, err := r.instance.GetPgVersion()
+	if err != nil {
+		return err
+	}
+
+	var progress *subscriptionProgress
+	err = r.withDatabase(r.instance.ConnectionPool().GetDsn(subscription.Spec.DBName), func(db *sql.DB) error {
+		info, err := detectSubscription(ctx, db, subscription.Spec.Name)
+		if err != nil {
+			return err
+		}
+
+		if info == nil {
+			err = createSubscription(ctx, db, &subscription.Spec, connectionString)
+		} else {
+			err = updateSubscription(ctx, db, &subscription.Spec, connectionString, info)
+		}
+		if err != nil {
+			return err
+		}
+
+		progress, err = getSubscriptionProgress(ctx, db, subscription.Spec.Name, pgVersion.Major)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	subscription.Status.ReceivedLSN = progress.ReceivedLSN
+	subscription.Status.LatestEndLSN = progress.LatestEndLSN
+	subscription.Status.LatestEndTime = progress.LatestEndTime
+	subscription.Status.SlotLagBytes = r.getPublisherSlotLag(ctx, connectionString, progress.SlotName)
+
+	return nil
+}
+
+// getPublisherSlotLag connects to the publisher to read the lag of the replication
+// slot used by the subscription. Errors are only logged, as the publisher may
+// not be reachable from this instance
+func (r *SubscriptionReconciler) getPublisherSlotLag(
+	ctx context.Context,
+	connectionString string,
+	slotName string,
+) *int64 {
+	if len(slotName) == 0 {
+		return nil
+	}
+
+	var lag *int64
+	err := r.withDatabase(connectionString, func(db *sql.DB) (err error) {
+		lag, err = getSlotLag(ctx, db, slotName)
+		return err
+	})
+	if err != nil {
+		log.FromContext(ctx).Info("Cannot read the replication slot lag from the publisher",
+			"slotName", slotName, "err", err)
+		return nil
+	}
+
+	return lag
+}
+
+// withDatabase runs the passed function with a connection to the passed DSN.
+// We use a dedicated connection, which is closed at the end of the
+// reconciliation, to avoid blocking a subsequent drop of the database
+func (r *SubscriptionReconciler) withDatabase(dsn string, f func(db *sql.DB) error) error {
+	db, err := pool.NewDBConnection(dsn, pool.ConnectionProfilePostgresql)
+	if err != nil {
+		return fmt.Errorf("while connecting to the database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	return f(db)
+}
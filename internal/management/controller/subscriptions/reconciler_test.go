@@ -0,0 +1,182 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptions
+
+import (
+	"os"
+	"path/filepath"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subscription reconciler", func() {
+	const namespace = "default"
+
+	var (
+		instance     *postgres.Instance
+		cluster      *apiv1.Cluster
+		subscription *apiv1.Subscription
+	)
+
+	BeforeEach(func() {
+		instance = postgres.NewInstance()
+		instance.PgData = GinkgoT().TempDir()
+		instance.Namespace = namespace
+		instance.ClusterName = "cluster-example"
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				ExternalClusters: []apiv1.ExternalCluster{
+					{Name: "source", ConnectionParameters: map[string]string{"host": "source-rw"}},
+				},
+			},
+		}
+
+		subscription = &apiv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "sub"},
+			Spec: apiv1.SubscriptionSpec{
+				ClusterRef:          apiv1.LocalObjectReference{Name: "cluster-example"},
+				Name:                "sub",
+				DBName:              "app",
+				PublicationName:     "pub",
+				ExternalClusterName: "source",
+				ReclaimPolicy:       apiv1.SubscriptionReclaimDelete,
+			},
+		}
+	})
+
+	reconcileSubscription := func(ctx SpecContext, objects ...client.Object) (reconcile.Result, *apiv1.Subscription) {
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			WithStatusSubresource(&apiv1.Subscription{}).
+			Build()
+
+		result, err := NewSubscriptionReconciler(instance, fakeClient).Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: namespace, Name: "sub"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		var updatedSubscription apiv1.Subscription
+		err = fakeClient.Get(ctx, client.ObjectKeyFromObject(subscription), &updatedSubscription)
+		if err != nil {
+			Expect(client.IgnoreNotFound(err)).To(Succeed())
+			return result, nil
+		}
+		return result, &updatedSubscription
+	}
+
+	It("ignores a subscription that doesn't exist", func(ctx SpecContext) {
+		result, updatedSubscription := reconcileSubscription(ctx, cluster)
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(updatedSubscription).To(BeNil())
+	})
+
+	It("ignores the subscriptions of other clusters", func(ctx SpecContext) {
+		subscription.Spec.ClusterRef.Name = "another-cluster"
+
+		result, updatedSubscription := reconcileSubscription(ctx, cluster, subscription)
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(updatedSubscription.Finalizers).To(BeEmpty())
+		Expect(updatedSubscription.Status.Applied).To(BeNil())
+	})
+
+	It("waits for the next reconciliation on the replicas", func(ctx SpecContext) {
+		Expect(os.WriteFile(filepath.Join(instance.PgData, "standby.signal"), nil, 0o600)).To(Succeed())
+
+		result, updatedSubscription := reconcileSubscription(ctx, cluster, subscription)
+		Expect(result).To(Equal(reconcile.Result{RequeueAfter: subscriptionReconciliationInterval}))
+		Expect(updatedSubscription.Finalizers).To(BeEmpty())
+		Expect(updatedSubscription.Status.Applied).To(BeNil())
+	})
+
+	It("doesn't requeue the subscription when the cluster doesn't exist", func(ctx SpecContext) {
+		result, updatedSubscription := reconcileSubscription(ctx, subscription)
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(updatedSubscription.Finalizers).To(BeEmpty())
+	})
+
+	It("waits for the next reconciliation in replica clusters", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: true, Source: "source"}
+
+		result, updatedSubscription := reconcileSubscription(ctx, cluster, subscription)
+		Expect(result).To(Equal(reconcile.Result{RequeueAfter: subscriptionReconciliationInterval}))
+		Expect(updatedSubscription.Finalizers).To(BeEmpty())
+		Expect(updatedSubscription.Status.Applied).To(BeNil())
+	})
+
+	It("retries shortly when the server is not ready", func(ctx SpecContext) {
+		result, updatedSubscription := reconcileSubscription(ctx, cluster, subscription)
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<", subscriptionReconciliationInterval))
+		Expect(updatedSubscription.Finalizers).To(BeEmpty())
+		Expect(updatedSubscription.Status.Applied).To(BeNil())
+	})
+
+	Context("finalizer", func() {
+		newReconciler := func(objects ...client.Object) *SubscriptionReconciler {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(objects...).
+				Build()
+			return NewSubscriptionReconciler(instance, fakeClient)
+		}
+
+		It("is added when the subscription needs to be dropped", func(ctx SpecContext) {
+			reconciler := newReconciler(subscription)
+			Expect(reconciler.reconcileFinalizer(ctx, subscription)).To(Succeed())
+
+			var updatedSubscription apiv1.Subscription
+			Expect(reconciler.GetClient().Get(ctx, client.ObjectKeyFromObject(subscription), &updatedSubscription)).
+				To(Succeed())
+			Expect(updatedSubscription.Finalizers).To(ConsistOf(apiv1.SubscriptionFinalizerName))
+		})
+
+		It("is not added when the subscription is retained", func(ctx SpecContext) {
+			subscription.Spec.ReclaimPolicy = apiv1.SubscriptionReclaimRetain
+			reconciler := newReconciler(subscription)
+			Expect(reconciler.reconcileFinalizer(ctx, subscription)).To(Succeed())
+			Expect(subscription.Finalizers).To(BeEmpty())
+		})
+
+		It("is removed without dropping a retained subscription", func(ctx SpecContext) {
+			subscription.Spec.ReclaimPolicy = apiv1.SubscriptionReclaimRetain
+			subscription.Finalizers = []string{apiv1.SubscriptionFinalizerName}
+			reconciler := newReconciler(subscription)
+			Expect(reconciler.GetClient().Delete(ctx, subscription)).To(Succeed())
+			Expect(reconciler.GetClient().Get(ctx, client.ObjectKeyFromObject(subscription), subscription)).
+				To(Succeed())
+
+			Expect(reconciler.reconcileFinalizer(ctx, subscription)).To(Succeed())
+			err := reconciler.GetClient().Get(ctx, client.ObjectKeyFromObject(subscription), &apiv1.Subscription{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
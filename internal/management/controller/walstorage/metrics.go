@@ -0,0 +1,68 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstorage
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// metricsNamespace is the namespace of the WAL storage metrics, the
+	// same used for the other metrics exposed by the instance manager
+	metricsNamespace = "cnpg"
+
+	// metricsSubsystem is the subsystem of the WAL storage metrics
+	metricsSubsystem = "instance_manager_wal_storage"
+)
+
+var emergencyCleanupsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "emergency_cleanups_total",
+	Help: "Total number of emergency cleanups of the WAL volume, by outcome: " +
+		"`freed` when the used space went below the threshold, `full` otherwise.",
+}, []string{"outcome"})
+
+var terminatedSessionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "terminated_sessions_total",
+	Help:      "Total number of idle sessions terminated to throttle the connections while the WAL volume fills up.",
+})
+
+var writesFenced = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "writes_fenced",
+	Help:      "1 if the writes are fenced because the WAL volume is full, 0 otherwise.",
+})
+
+// Labels used by the WAL storage metrics
+const (
+	cleanupFreed = "freed"
+	cleanupFull  = "full"
+)
+
+// Collectors returns the collectors of the WAL storage metrics, to be
+// exposed by the metrics server
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		emergencyCleanupsTotal,
+		terminatedSessionsTotal,
+		writesFenced,
+	}
+}
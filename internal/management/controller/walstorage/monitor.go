@@ -0,0 +1,405 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstorage
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// checkInterval is the time between two checks of the used space of
+// the WAL volume
+const checkInterval = 30 * time.Second
+
+// A Monitor is a Kubernetes manager.Runnable that checks the used space of
+// the WAL volume and progressively protects the instance from running out
+// of it. As the used space grows, the primary throttles the connections,
+// then the instance requests a checkpoint to remove the WAL files not
+// needed anymore, dropping the inactive replication slots if requested,
+// and finally the primary fences the writes
+type Monitor struct {
+	instance *postgres.Instance
+	client   client.Client
+	recorder record.EventRecorder
+
+	// walPath is the directory containing the WAL files
+	walPath string
+
+	// usedPercentage gets the percentage of used space of the
+	// filesystem containing the passed path
+	usedPercentage func(path string) (float64, error)
+
+	// checkpoint requests a checkpoint to the instance
+	checkpoint func(ctx context.Context) error
+
+	// listSlots gets the inactive replication slots of the instance
+	listSlots func(ctx context.Context) ([]inactiveSlot, error)
+
+	// dropSlot drops a replication slot from the instance
+	dropSlot func(ctx context.Context, slotName string) error
+
+	// terminateIdleSessions terminates the idle sessions of the
+	// applications, returning their number
+	terminateIdleSessions func(ctx context.Context) (int, error)
+
+	// isWritesFenced checks whether the writes are fenced
+	isWritesFenced func(ctx context.Context) (bool, error)
+
+	// fenceWrites turns the databases read-only
+	fenceWrites func(ctx context.Context) error
+
+	// unfenceWrites turns the databases read-write again
+	unfenceWrites func(ctx context.Context) error
+
+	// throttling is true while the connections are throttled, to avoid
+	// repeating the same event
+	throttling bool
+
+	// full is true when the last cleanup couldn't bring the used space
+	// below the threshold, to avoid repeating the same event
+	full bool
+}
+
+// NewMonitor creates a new WAL storage Monitor
+func NewMonitor(instance *postgres.Instance, client client.Client, recorder record.EventRecorder) *Monitor {
+	return &Monitor{
+		instance:       instance,
+		client:         client,
+		recorder:       recorder,
+		walPath:        path.Join(instance.PgData, "pg_wal"),
+		usedPercentage: getUsedPercentage,
+		checkpoint: func(ctx context.Context) error {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return err
+			}
+			return checkpoint(ctx, db)
+		},
+		listSlots: func(ctx context.Context) ([]inactiveSlot, error) {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return nil, err
+			}
+			return listInactiveSlots(ctx, db)
+		},
+		dropSlot: func(ctx context.Context, slotName string) error {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return err
+			}
+			return dropReplicationSlot(ctx, db, slotName)
+		},
+		terminateIdleSessions: func(ctx context.Context) (int, error) {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return 0, err
+			}
+			return terminateIdleSessions(ctx, db)
+		},
+		isWritesFenced: func(ctx context.Context) (bool, error) {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return false, err
+			}
+			return isWritesFenced(ctx, db)
+		},
+		fenceWrites: func(ctx context.Context) error {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return err
+			}
+			return fenceWrites(ctx, db)
+		},
+		unfenceWrites: func(ctx context.Context) error {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return err
+			}
+			return unfenceWrites(ctx, db)
+		},
+	}
+}
+
+// getUsedPercentage gets the percentage of used space of the filesystem
+// containing the passed path
+func getUsedPercentage(path string) (float64, error) {
+	total, available, err := compatibility.GetDiskUsage(path)
+	if err != nil || total == 0 {
+		return 0, err
+	}
+	return float64(total-available) * 100 / float64(total), nil
+}
+
+// Start starts running the Monitor
+func (m *Monitor) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("wal_storage_monitor")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			contextLog.Info("Terminated WAL storage monitor loop")
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := m.check(ctx); err != nil {
+			contextLog.Warning("while checking the WAL storage", "err", err)
+		}
+	}
+}
+
+// check applies the protection stages matching the used space
+// of the WAL volume
+func (m *Monitor) check(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := m.client.Get(ctx, types.NamespacedName{
+		Name:      m.instance.ClusterName,
+		Namespace: m.instance.Namespace,
+	}, &cluster); err != nil {
+		return err
+	}
+
+	if !cluster.IsWalStorageEmergencyCleanupEnabled() || m.instance.IsFenced() {
+		m.full = false
+		m.throttling = false
+		return nil
+	}
+
+	config := cluster.Spec.WalStorage.EmergencyCleanup
+	isPrimary := cluster.Status.CurrentPrimary == m.instance.PodName
+	threshold := float64(config.GetThreshold())
+	used, err := m.usedPercentage(m.walPath)
+	if err != nil {
+		return err
+	}
+
+	if isPrimary && config.ThrottleConnectionsThreshold > 0 &&
+		used >= float64(config.ThrottleConnectionsThreshold) {
+		if err := m.throttleConnections(ctx, &cluster, used); err != nil {
+			return err
+		}
+	} else {
+		m.throttling = false
+	}
+
+	if used >= threshold {
+		if used, err = m.cleanup(ctx, &cluster, used, threshold); err != nil {
+			return err
+		}
+	} else {
+		m.full = false
+	}
+
+	if !isPrimary {
+		return nil
+	}
+	return m.protectWrites(ctx, &cluster, used)
+}
+
+// throttleConnections terminates the idle sessions of the applications,
+// reducing the connections to the primary
+func (m *Monitor) throttleConnections(ctx context.Context, cluster *apiv1.Cluster, used float64) error {
+	terminated, err := m.terminateIdleSessions(ctx)
+	if err != nil {
+		return err
+	}
+	terminatedSessionsTotal.Add(float64(terminated))
+
+	if !m.throttling {
+		log.FromContext(ctx).Warning("The WAL volume is filling up, throttling the connections",
+			"usedPercentage", used, "terminatedSessions", terminated)
+		m.recorder.Eventf(cluster, corev1.EventTypeWarning, events.WALStorageThrottlingConnections,
+			"The WAL volume of instance %s is %.0f%% full, terminating the idle sessions",
+			m.instance.PodName, used)
+	}
+	m.throttling = true
+	return nil
+}
+
+// protectWrites fences the writes when the used space of the WAL volume
+// reaches the configured threshold, and lifts the fence once it goes
+// below it
+func (m *Monitor) protectWrites(ctx context.Context, cluster *apiv1.Cluster, used float64) error {
+	contextLog := log.FromContext(ctx)
+	fenceThreshold := float64(cluster.Spec.WalStorage.EmergencyCleanup.FenceWritesThreshold)
+
+	fenced, err := m.isWritesFenced(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case fenceThreshold > 0 && used >= fenceThreshold:
+		if !fenced {
+			if err := m.fenceWrites(ctx); err != nil {
+				return err
+			}
+			contextLog.Warning("The WAL volume is full, fencing the writes", "usedPercentage", used)
+			m.recorder.Eventf(cluster, corev1.EventTypeWarning, events.WALStorageWritesFenced,
+				"The WAL volume of instance %s is %.0f%% full, the databases are now read-only",
+				m.instance.PodName, used)
+		}
+		writesFenced.Set(1)
+
+	case fenced:
+		if err := m.unfenceWrites(ctx); err != nil {
+			return err
+		}
+		contextLog.Info("The WAL volume has been freed, lifting the write fencing", "usedPercentage", used)
+		m.recorder.Eventf(cluster, corev1.EventTypeNormal, events.WALStorageWritesUnfenced,
+			"The WAL volume of instance %s is %.0f%% full, the databases are read-write again",
+			m.instance.PodName, used)
+		writesFenced.Set(0)
+
+	default:
+		writesFenced.Set(0)
+	}
+
+	return nil
+}
+
+// cleanup requests a checkpoint and, if requested and this instance is the
+// primary, drops the inactive replication slots until the used space of
+// the WAL volume goes below the threshold. It returns the used space
+// after the cleanup
+func (m *Monitor) cleanup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	used, threshold float64,
+) (float64, error) {
+	contextLog := log.FromContext(ctx)
+	contextLog.Warning("The WAL volume is almost full, starting the emergency cleanup",
+		"usedPercentage", used, "threshold", threshold)
+	if !m.full {
+		m.recorder.Eventf(cluster, corev1.EventTypeWarning, events.WALStorageAlmostFull,
+			"The WAL volume of instance %s is %.0f%% full, starting the emergency cleanup",
+			m.instance.PodName, used)
+	}
+
+	used, err := m.checkpointAndCheck(ctx)
+	if err != nil {
+		return used, err
+	}
+
+	config := cluster.Spec.WalStorage.EmergencyCleanup
+	if used >= threshold && config.DropInactiveSlots && cluster.Status.CurrentPrimary == m.instance.PodName {
+		used, err = m.dropInactiveSlots(ctx, cluster, used, threshold)
+		if err != nil {
+			return used, err
+		}
+	}
+
+	if used < threshold {
+		emergencyCleanupsTotal.WithLabelValues(cleanupFreed).Inc()
+		contextLog.Info("The emergency cleanup freed the WAL volume")
+		m.full = false
+		return used, nil
+	}
+
+	emergencyCleanupsTotal.WithLabelValues(cleanupFull).Inc()
+	if !m.full {
+		m.recorder.Eventf(cluster, corev1.EventTypeWarning, events.WALStorageFull,
+			"The emergency cleanup couldn't free the WAL volume of instance %s, "+
+				"please check the WAL archiving and the replication slots, or enlarge the volume",
+			m.instance.PodName)
+	}
+	m.full = true
+	return used, nil
+}
+
+// dropInactiveSlots drops the inactive replication slots used for high
+// availability, starting from the ones retaining the most WAL, until the used space of the WAL volume goes
+// below the threshold. The slots matching the exclude patterns of the
+// inactive replication slots detection are never dropped. It returns the
+// used space after dropping the slots
+func (m *Monitor) dropInactiveSlots(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	used, threshold float64,
+) (float64, error) {
+	contextLog := log.FromContext(ctx)
+	excludeConfig := cluster.Spec.ReplicationSlots.GetInactiveSlots()
+
+	// Only the slots used for high availability are dropped, as the
+	// operator recreates them. The ones of the other consumers are
+	// never touched
+	slotPrefix := apiv1.DefaultReplicationSlotsHASlotPrefix
+	if cluster.Spec.ReplicationSlots != nil {
+		slotPrefix = cluster.Spec.ReplicationSlots.HighAvailability.GetSlotPrefix()
+	}
+
+	slots, err := m.listSlots(ctx)
+	if err != nil {
+		return used, err
+	}
+
+	for _, slot := range slots {
+		if !strings.HasPrefix(slot.SlotName, slotPrefix) {
+			continue
+		}
+
+		excluded, err := excludeConfig.IsExcluded(slot.SlotName)
+		if err != nil || excluded {
+			continue
+		}
+
+		if err := m.dropSlot(ctx, slot.SlotName); err != nil {
+			contextLog.Error(err, "while dropping replication slot to free the WAL volume",
+				"slotName", slot.SlotName)
+			continue
+		}
+
+		contextLog.Info("Dropped inactive replication slot to free the WAL volume",
+			"slotName", slot.SlotName, "retainedWALBytes", slot.RetainedWALBytes)
+		m.recorder.Eventf(cluster, corev1.EventTypeWarning, events.DroppedReplicationSlot,
+			"Dropped the inactive replication slot %q, retaining %d bytes of WAL, to free the WAL volume",
+			slot.SlotName, slot.RetainedWALBytes)
+
+		if used, err = m.checkpointAndCheck(ctx); err != nil || used < threshold {
+			return used, err
+		}
+	}
+
+	return used, nil
+}
+
+// checkpointAndCheck requests a checkpoint and gets the used space
+// of the WAL volume afterwards
+func (m *Monitor) checkpointAndCheck(ctx context.Context) (float64, error) {
+	if err := m.checkpoint(ctx); err != nil {
+		return 0, err
+	}
+
+	return m.usedPercentage(m.walPath)
+}
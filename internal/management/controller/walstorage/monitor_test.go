@@ -0,0 +1,275 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstorage
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL storage monitor", func() {
+	const namespace = "default"
+
+	var (
+		cluster     *apiv1.Cluster
+		monitor     *Monitor
+		recorder    *record.FakeRecorder
+		usage       []float64
+		checkpoints int
+		slots       []inactiveSlot
+		dropped     []string
+		terminated  int
+		fenced      bool
+	)
+
+	BeforeEach(func() {
+		usage = nil
+		checkpoints = 0
+		dropped = nil
+		terminated = 0
+		fenced = false
+		slots = []inactiveSlot{
+			{SlotName: "debezium", RetainedWALBytes: 8192},
+			{SlotName: "_cnpg_cluster_example_2", RetainedWALBytes: 4096},
+			{SlotName: "_cnpg_backup_tool", RetainedWALBytes: 2048},
+			{SlotName: "_cnpg_cluster_example_3", RetainedWALBytes: 1024},
+		}
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				WalStorage: &apiv1.StorageConfiguration{
+					Size: "1Gi",
+					EmergencyCleanup: &apiv1.StorageEmergencyCleanupConfiguration{
+						Enabled:   true,
+						Threshold: 80,
+					},
+				},
+				ReplicationSlots: &apiv1.ReplicationSlotsConfiguration{
+					InactiveSlots: &apiv1.InactiveSlotsConfiguration{
+						ExcludePatterns: []string{"^_cnpg_backup_"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+
+		instance := postgres.NewInstance()
+		instance.Namespace = namespace
+		instance.ClusterName = cluster.Name
+		instance.PodName = "cluster-example-1"
+
+		recorder = record.NewFakeRecorder(10)
+		monitor = &Monitor{
+			instance: instance,
+			recorder: recorder,
+			walPath:  "/var/lib/postgresql/data/pgdata/pg_wal",
+			usedPercentage: func(string) (float64, error) {
+				// Every read consumes a value, the last one sticks
+				value := usage[0]
+				if len(usage) > 1 {
+					usage = usage[1:]
+				}
+				return value, nil
+			},
+			checkpoint: func(context.Context) error {
+				checkpoints++
+				return nil
+			},
+			listSlots: func(context.Context) ([]inactiveSlot, error) {
+				return slots, nil
+			},
+			dropSlot: func(_ context.Context, slotName string) error {
+				dropped = append(dropped, slotName)
+				return nil
+			},
+			terminateIdleSessions: func(context.Context) (int, error) {
+				terminated++
+				return 2, nil
+			},
+			isWritesFenced: func(context.Context) (bool, error) {
+				return fenced, nil
+			},
+			fenceWrites: func(context.Context) error {
+				fenced = true
+				return nil
+			},
+			unfenceWrites: func(context.Context) error {
+				fenced = false
+				return nil
+			},
+		}
+	})
+
+	setCluster := func() {
+		monitor.client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+	}
+
+	It("does nothing while the used space is below the threshold", func(ctx SpecContext) {
+		setCluster()
+		usage = []float64{79}
+
+		Expect(monitor.check(ctx)).To(Succeed())
+		Expect(checkpoints).To(BeZero())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("does nothing when the emergency cleanup is disabled", func(ctx SpecContext) {
+		cluster.Spec.WalStorage.EmergencyCleanup.Enabled = false
+		setCluster()
+		usage = []float64{99}
+
+		Expect(monitor.check(ctx)).To(Succeed())
+		Expect(checkpoints).To(BeZero())
+	})
+
+	It("stops after the checkpoint if it freed enough space", func(ctx SpecContext) {
+		cluster.Spec.WalStorage.EmergencyCleanup.DropInactiveSlots = true
+		setCluster()
+		usage = []float64{85, 40}
+		freed := testutil.ToFloat64(emergencyCleanupsTotal.WithLabelValues(cleanupFreed))
+
+		Expect(monitor.check(ctx)).To(Succeed())
+		Expect(checkpoints).To(Equal(1))
+		Expect(dropped).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageAlmostFull")))
+		Expect(testutil.ToFloat64(emergencyCleanupsTotal.WithLabelValues(cleanupFreed))).
+			To(Equal(freed + 1))
+	})
+
+	It("drops the inactive high availability slots not excluded until enough space is freed",
+		func(ctx SpecContext) {
+			cluster.Spec.WalStorage.EmergencyCleanup.DropInactiveSlots = true
+			setCluster()
+			usage = []float64{95, 94, 90, 60}
+
+			Expect(monitor.check(ctx)).To(Succeed())
+			Expect(dropped).To(Equal([]string{"_cnpg_cluster_example_2", "_cnpg_cluster_example_3"}))
+			Expect(checkpoints).To(Equal(3))
+			Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageAlmostFull")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("_cnpg_cluster_example_2")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("_cnpg_cluster_example_3")))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+	It("never drops slots on a replica", func(ctx SpecContext) {
+		cluster.Spec.WalStorage.EmergencyCleanup.DropInactiveSlots = true
+		cluster.Status.CurrentPrimary = "cluster-example-2"
+		setCluster()
+		usage = []float64{95}
+
+		Expect(monitor.check(ctx)).To(Succeed())
+		Expect(checkpoints).To(Equal(1))
+		Expect(dropped).To(BeEmpty())
+	})
+
+	It("reports a full volume only once", func(ctx SpecContext) {
+		setCluster()
+		usage = []float64{95}
+		full := testutil.ToFloat64(emergencyCleanupsTotal.WithLabelValues(cleanupFull))
+
+		Expect(monitor.check(ctx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageAlmostFull")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageFull")))
+
+		Expect(monitor.check(ctx)).To(Succeed())
+		Expect(checkpoints).To(Equal(2))
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(testutil.ToFloat64(emergencyCleanupsTotal.WithLabelValues(cleanupFull))).
+			To(Equal(full + 2))
+	})
+
+	Context("with the protection stages", func() {
+		BeforeEach(func() {
+			cluster.Spec.WalStorage.EmergencyCleanup.ThrottleConnectionsThreshold = 70
+			cluster.Spec.WalStorage.EmergencyCleanup.FenceWritesThreshold = 95
+		})
+
+		It("throttles the connections before the cleanup", func(ctx SpecContext) {
+			setCluster()
+			usage = []float64{75}
+
+			Expect(monitor.check(ctx)).To(Succeed())
+			Expect(terminated).To(Equal(1))
+			Expect(checkpoints).To(BeZero())
+			Expect(fenced).To(BeFalse())
+			Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageThrottlingConnections")))
+
+			Expect(monitor.check(ctx)).To(Succeed())
+			Expect(terminated).To(Equal(2))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("fences the writes when the cleanup can't free the volume", func(ctx SpecContext) {
+			setCluster()
+			usage = []float64{97}
+
+			Expect(monitor.check(ctx)).To(Succeed())
+			Expect(checkpoints).To(Equal(1))
+			Expect(fenced).To(BeTrue())
+			Expect(testutil.ToFloat64(writesFenced)).To(BeEquivalentTo(1))
+			Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageThrottlingConnections")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageAlmostFull")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageFull")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageWritesFenced")))
+		})
+
+		It("doesn't fence the writes when the cleanup frees the volume", func(ctx SpecContext) {
+			setCluster()
+			usage = []float64{97, 60}
+
+			Expect(monitor.check(ctx)).To(Succeed())
+			Expect(fenced).To(BeFalse())
+			Expect(testutil.ToFloat64(writesFenced)).To(BeZero())
+		})
+
+		It("lifts the fence once the volume has been freed", func(ctx SpecContext) {
+			setCluster()
+			fenced = true
+			usage = []float64{60}
+
+			Expect(monitor.check(ctx)).To(Succeed())
+			Expect(fenced).To(BeFalse())
+			Expect(recorder.Events).To(Receive(ContainSubstring("WALStorageWritesUnfenced")))
+		})
+
+		It("leaves the connections and the writes alone on a replica", func(ctx SpecContext) {
+			cluster.Status.CurrentPrimary = "cluster-example-2"
+			setCluster()
+			usage = []float64{99}
+
+			Expect(monitor.check(ctx)).To(Succeed())
+			Expect(terminated).To(BeZero())
+			Expect(checkpoints).To(Equal(1))
+			Expect(fenced).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+)
+
+// inactiveSlot is an inactive replication slot retaining WAL files
+type inactiveSlot struct {
+	SlotName         string
+	RetainedWALBytes int64
+}
+
+// listInactiveSlots gets the non-temporary replication slots that are not
+// in use, starting from the ones retaining the most WAL
+func listInactiveSlots(ctx context.Context, db *sql.DB) ([]inactiveSlot, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT slot_name,
+			COALESCE(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), restart_lsn), 0)::bigint
+		FROM pg_catalog.pg_replication_slots
+		WHERE NOT temporary AND NOT active AND restart_lsn IS NOT NULL
+		ORDER BY 2 DESC, slot_name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var slots []inactiveSlot
+	for rows.Next() {
+		var slot inactiveSlot
+		if err := rows.Scan(&slot.SlotName, &slot.RetainedWALBytes); err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, rows.Err()
+}
+
+// dropReplicationSlot drops a replication slot. PostgreSQL refuses to
+// drop it if, in the meantime, a consumer started using it
+func dropReplicationSlot(ctx context.Context, db *sql.DB, slotName string) error {
+	_, err := db.ExecContext(ctx, "SELECT pg_catalog.pg_drop_replication_slot($1)", slotName)
+	return err
+}
+
+// checkpoint requests an immediate checkpoint, or a restartpoint on a
+// replica, allowing PostgreSQL to remove the WAL files not needed anymore
+func checkpoint(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "CHECKPOINT")
+	return err
+}
+
+// terminateIdleSessions terminates the idle sessions of the non-superuser
+// roles, returning their number
+func terminateIdleSessions(ctx context.Context, db *sql.DB) (int, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT pg_catalog.count(*) FILTER (WHERE terminated) FROM (
+			SELECT pg_catalog.pg_terminate_backend(a.pid) AS terminated
+			FROM pg_catalog.pg_stat_activity a
+			JOIN pg_catalog.pg_roles r ON r.oid = a.usesysid
+			WHERE a.backend_type = 'client backend'
+				AND a.state = 'idle'
+				AND NOT r.rolsuper
+				AND a.pid <> pg_catalog.pg_backend_pid()
+		) sessions`,
+	)
+
+	var terminated int
+	err := row.Scan(&terminated)
+	return terminated, err
+}
+
+// fenceMarker is the custom setting marking the databases turned read-only
+// by the fencing, whose value is the previous default_transaction_read_only
+// setting of the database, or fenceMarkerUnset if there was none. Being
+// stored together with the settings of the databases, it is replicated and
+// survives a failover, and it lets the fencing leave untouched the databases
+// the user turned read-only
+const fenceMarker = "cnpg.wal_storage_fence"
+
+// fenceMarkerUnset is the value of the fence marker of the databases
+// without a default_transaction_read_only setting before the fencing
+const fenceMarkerUnset = "unset"
+
+// databaseSetting is the value of a setting of a database, empty if unset
+type databaseSetting struct {
+	database string
+	value    string
+}
+
+// isWritesFenced checks whether any database has been turned read-only
+// by the fencing
+func isWritesFenced(ctx context.Context, db *sql.DB) (bool, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM pg_catalog.pg_db_role_setting s, pg_catalog.unnest(s.setconfig) c
+			WHERE s.setrole = 0 AND pg_catalog.starts_with(c, $1)
+		)`,
+		fenceMarker+"=",
+	)
+
+	var fenced bool
+	err := row.Scan(&fenced)
+	return fenced, err
+}
+
+// fenceWrites turns every database read-only by default, marking the
+// databases whose setting has been changed, and then terminates the
+// sessions of the non-superuser roles to make them reconnect with the
+// new default
+func fenceWrites(ctx context.Context, db *sql.DB) error {
+	err := alterDatabases(
+		ctx,
+		db,
+		`SELECT d.datname, COALESCE((
+			SELECT pg_catalog.substr(c, pg_catalog.length($1) + 1)
+			FROM pg_catalog.pg_db_role_setting s, pg_catalog.unnest(s.setconfig) c
+			WHERE s.setdatabase = d.oid AND s.setrole = 0 AND pg_catalog.starts_with(c, $1)
+		), '')
+		FROM pg_catalog.pg_database d
+		WHERE d.datallowconn AND NOT d.datistemplate`,
+		"default_transaction_read_only=",
+		func(setting databaseSetting) []string {
+			if isEnabledSetting(setting.value) {
+				return nil
+			}
+
+			previous := setting.value
+			if previous == "" {
+				previous = fenceMarkerUnset
+			}
+			database := pgx.Identifier{setting.database}.Sanitize()
+			return []string{
+				fmt.Sprintf("ALTER DATABASE %s SET default_transaction_read_only TO on", database),
+				fmt.Sprintf("ALTER DATABASE %s SET %s TO %s", database, fenceMarker, pq.QuoteLiteral(previous)),
+			}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(
+		ctx,
+		`SELECT pg_catalog.pg_terminate_backend(a.pid)
+		FROM pg_catalog.pg_stat_activity a
+		JOIN pg_catalog.pg_roles r ON r.oid = a.usesysid
+		WHERE a.backend_type = 'client backend'
+			AND NOT r.rolsuper
+			AND a.pid <> pg_catalog.pg_backend_pid()`,
+	)
+	return err
+}
+
+// unfenceWrites restores the default access mode the databases turned
+// read-only by the fencing had before, and removes their marker
+func unfenceWrites(ctx context.Context, db *sql.DB) error {
+	return alterDatabases(
+		ctx,
+		db,
+		`SELECT d.datname, pg_catalog.substr(c, pg_catalog.length($1) + 1)
+		FROM pg_catalog.pg_database d
+		JOIN pg_catalog.pg_db_role_setting s ON s.setdatabase = d.oid AND s.setrole = 0,
+			pg_catalog.unnest(s.setconfig) c
+		WHERE pg_catalog.starts_with(c, $1)`,
+		fenceMarker+"=",
+		func(setting databaseSetting) []string {
+			database := pgx.Identifier{setting.database}.Sanitize()
+			restore := fmt.Sprintf("ALTER DATABASE %s RESET default_transaction_read_only", database)
+			if setting.value != fenceMarkerUnset {
+				restore = fmt.Sprintf("ALTER DATABASE %s SET default_transaction_read_only TO %s",
+					database, pq.QuoteLiteral(setting.value))
+			}
+			return []string{
+				restore,
+				fmt.Sprintf("ALTER DATABASE %s RESET %s", database, fenceMarker),
+			}
+		},
+	)
+}
+
+// isEnabledSetting checks whether the value of a boolean setting is true,
+// using the same spelling rules of PostgreSQL
+func isEnabledSetting(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "tru", "tr", "t", "yes", "ye", "y", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// alterDatabases gets, in a single read-write transaction, the value of
+// a setting of the databases returned by the passed query, and runs the
+// statements the passed function generates for each of them. The query
+// gets the prefix of the setting as its only parameter. The transaction
+// is explicitly read-write, as the default could be read-only because of
+// a previous fencing
+func alterDatabases(
+	ctx context.Context,
+	db *sql.DB,
+	query, settingPrefix string,
+	statements func(setting databaseSetting) []string,
+) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "SET TRANSACTION READ WRITE"); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, settingPrefix)
+	if err != nil {
+		return err
+	}
+	var settings []databaseSetting
+	for rows.Next() {
+		var setting databaseSetting
+		if err := rows.Scan(&setting.database, &setting.value); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		settings = append(settings, setting)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, setting := range settings {
+		for _, statement := range statements(setting) {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
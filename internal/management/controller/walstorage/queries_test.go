@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstorage
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL storage queries", func() {
+	It("lists the inactive replication slots", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("FROM pg_catalog.pg_replication_slots").
+			WillReturnRows(sqlmock.NewRows([]string{"slot_name", "retained"}).
+				AddRow("debezium", 16777216).
+				AddRow("old_standby", 0))
+
+		slots, err := listInactiveSlots(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(slots).To(Equal([]inactiveSlot{
+			{SlotName: "debezium", RetainedWALBytes: 16777216},
+			{SlotName: "old_standby"},
+		}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("fences the writes of the databases which are not read-only and then terminates the sessions",
+		func(ctx SpecContext) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			Expect(err).ToNot(HaveOccurred())
+
+			mock.ExpectBegin()
+			mock.ExpectExec("SET TRANSACTION READ WRITE").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("FROM pg_catalog.pg_database").WithArgs("default_transaction_read_only=").
+				WillReturnRows(sqlmock.NewRows([]string{"datname", "value"}).
+					AddRow("app", "").
+					AddRow("my-db", "off").
+					AddRow("reports", "on"))
+			mock.ExpectExec(`ALTER DATABASE "app" SET default_transaction_read_only TO on`).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`ALTER DATABASE "app" SET cnpg.wal_storage_fence TO 'unset'`).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`ALTER DATABASE "my-db" SET default_transaction_read_only TO on`).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`ALTER DATABASE "my-db" SET cnpg.wal_storage_fence TO 'off'`).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectCommit()
+			mock.ExpectExec("pg_terminate_backend").WillReturnResult(sqlmock.NewResult(0, 3))
+
+			Expect(fenceWrites(ctx, db)).To(Succeed())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+	It("restores the previous access mode of the fenced databases", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SET TRANSACTION READ WRITE").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("pg_db_role_setting").WithArgs("cnpg.wal_storage_fence=").
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "value"}).
+				AddRow("app", "unset").
+				AddRow("my-db", "off"))
+		mock.ExpectExec(`ALTER DATABASE "app" RESET default_transaction_read_only`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`ALTER DATABASE "app" RESET cnpg.wal_storage_fence`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`ALTER DATABASE "my-db" SET default_transaction_read_only TO 'off'`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`ALTER DATABASE "my-db" RESET cnpg.wal_storage_fence`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(unfenceWrites(ctx, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("detects the fencing only through its marker", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("pg_db_role_setting").WithArgs("cnpg.wal_storage_fence=").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		Expect(isWritesFenced(ctx, db)).To(BeFalse())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
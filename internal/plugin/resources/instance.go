@@ -119,6 +119,66 @@ func getInstanceStatusFromPodViaExec(
 	return result
 }
 
+// GetConfigurationSnapshot gets the parsed pg_controldata output and the
+// non-default settings of the instance running in the given pod
+func GetConfigurationSnapshot(
+	ctx context.Context,
+	config *rest.Config,
+	pod v1.Pod,
+	postgresContainerName string,
+) (*postgres.ConfigurationSnapshot, error) {
+	timeout := time.Second * 10
+	clientInterface := kubernetes.NewForConfigOrDie(config)
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		config,
+		pod,
+		postgresContainerName,
+		&timeout,
+		"/controller/manager", "instance", "config-snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("while reading the configuration of pod %s: %w", pod.Name, err)
+	}
+
+	var result postgres.ConfigurationSnapshot
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		return nil, fmt.Errorf("can't parse the configuration of pod %s: %w", pod.Name, err)
+	}
+
+	return &result, nil
+}
+
+// GetInstanceTopology gets the position in the replication topology of the
+// instance running in the given pod, as reported by the instance manager
+func GetInstanceTopology(
+	ctx context.Context,
+	config *rest.Config,
+	pod v1.Pod,
+	postgresContainerName string,
+) (*postgres.InstanceTopology, error) {
+	timeout := time.Second * 10
+	clientInterface := kubernetes.NewForConfigOrDie(config)
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		config,
+		pod,
+		postgresContainerName,
+		&timeout,
+		"/controller/manager", "instance", "topology")
+	if err != nil {
+		return nil, fmt.Errorf("while reading the topology of pod %s: %w", pod.Name, err)
+	}
+
+	var result postgres.InstanceTopology
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		return nil, fmt.Errorf("can't parse the topology of pod %s: %w", pod.Name, err)
+	}
+
+	return &result, nil
+}
+
 // IsInstanceRunning returns a boolean indicating if the given instance is running and any error encountered
 func IsInstanceRunning(
 	ctx context.Context,
@@ -140,9 +140,19 @@ func (pair KeyPair) IsValid(caPair *KeyPair, opts *x509.VerifyOptions) error {
 
 // CreateAndSignPair given a CA keypair, generate and sign a leaf keypair
 func (pair KeyPair) CreateAndSignPair(host string, usage CertType, altDNSNames []string) (*KeyPair, error) {
-	certificateDuration := getCertificateDuration()
+	return pair.CreateAndSignPairWithDuration(host, usage, altDNSNames, getCertificateDuration())
+}
+
+// CreateAndSignPairWithDuration given a CA keypair, generate and sign a leaf
+// keypair valid for the passed duration
+func (pair KeyPair) CreateAndSignPairWithDuration(
+	host string,
+	usage CertType,
+	altDNSNames []string,
+	duration time.Duration,
+) (*KeyPair, error) {
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(duration)
 	return pair.createAndSignPairWithValidity(host, notBefore, notAfter, usage, altDNSNames)
 }
 
@@ -260,14 +270,23 @@ func (pair KeyPair) GenerateCertificateSecret(namespace, name string) *v1.Secret
 // parent certificate. If the parent certificate is nil the certificate
 // will be self-signed
 func (pair *KeyPair) RenewCertificate(caPrivateKey *ecdsa.PrivateKey, parentCertificate *x509.Certificate) error {
+	return pair.RenewCertificateWithDuration(caPrivateKey, parentCertificate, getCertificateDuration())
+}
+
+// RenewCertificateWithDuration is like RenewCertificate, with the new
+// certificate being valid for the passed duration
+func (pair *KeyPair) RenewCertificateWithDuration(
+	caPrivateKey *ecdsa.PrivateKey,
+	parentCertificate *x509.Certificate,
+	duration time.Duration,
+) error {
 	oldCertificate, err := pair.ParseCertificate()
 	if err != nil {
 		return err
 	}
 
-	certificateDuration := getCertificateDuration()
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(duration)
 
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
@@ -305,6 +324,11 @@ func (pair *KeyPair) RenewCertificate(caPrivateKey *ecdsa.PrivateKey, parentCert
 
 // IsExpiring check if the certificate will expire in the configured duration
 func (pair *KeyPair) IsExpiring() (bool, *time.Time, error) {
+	return pair.IsExpiringWithin(getCheckThreshold())
+}
+
+// IsExpiringWithin check if the certificate will expire in the passed duration
+func (pair *KeyPair) IsExpiringWithin(threshold time.Duration) (bool, *time.Time, error) {
 	cert, err := pair.ParseCertificate()
 	if err != nil {
 		return true, nil, err
@@ -313,14 +337,32 @@ func (pair *KeyPair) IsExpiring() (bool, *time.Time, error) {
 	if time.Now().Before(cert.NotBefore) {
 		return true, &cert.NotAfter, nil
 	}
-	expiringCheckThreshold := getCheckThreshold()
-	if time.Now().Add(expiringCheckThreshold).After(cert.NotAfter) {
+	if time.Now().Add(threshold).After(cert.NotAfter) {
 		return true, &cert.NotAfter, nil
 	}
 
 	return false, &cert.NotAfter, nil
 }
 
+// CheckValidity checks that the certificate in the pair is valid now,
+// returning an error describing why it isn't
+func (pair *KeyPair) CheckValidity() error {
+	cert, err := pair.ParseCertificate()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("the certificate is not valid before %s", cert.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("the certificate expired on %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
 // CreateDerivedCA create a new CA derived from the certificate in the
 // keypair
 func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit string) (*KeyPair, error) {
@@ -343,9 +385,18 @@ func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit strin
 
 // CreateRootCA generates a CA returning its keys
 func CreateRootCA(commonName string, organizationalUnit string) (*KeyPair, error) {
-	certificateDuration := getCertificateDuration()
+	return CreateRootCAWithDuration(commonName, organizationalUnit, getCertificateDuration())
+}
+
+// CreateRootCAWithDuration generates a CA valid for the passed duration,
+// returning its keys
+func CreateRootCAWithDuration(
+	commonName string,
+	organizationalUnit string,
+	duration time.Duration,
+) (*KeyPair, error) {
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(duration)
 	return createCAWithValidity(notBefore, notAfter, nil, nil, commonName, organizationalUnit)
 }
 
@@ -469,6 +520,25 @@ func encodePrivateKey(derBytes []byte) []byte {
 	return pem.EncodeToMemory(&pem.Block{Type: ecPrivateKeyPEMBlockType, Bytes: derBytes})
 }
 
+// Validity is the lifetime of the generated certificates, and how long
+// before their expiration they are renewed
+type Validity struct {
+	// Duration is the lifetime of a new certificate
+	Duration time.Duration
+
+	// RenewBefore is how long before its expiration a certificate is
+	// considered expiring, and renewed
+	RenewBefore time.Duration
+}
+
+// DefaultValidity returns the validity configured in the operator
+func DefaultValidity() Validity {
+	return Validity{
+		Duration:    getCertificateDuration(),
+		RenewBefore: getCheckThreshold(),
+	}
+}
+
 func getCertificateDuration() time.Duration {
 	duration := configuration.Current.CertificateDuration
 	if duration <= 0 {
@@ -108,6 +108,41 @@ var _ = Describe("Keypair generation", func() {
 		Expect(isExpiring, err).To(BeFalse())
 	})
 
+	It("uses the passed duration and renewal window", func() {
+		ca, err := CreateRootCAWithDuration("test", "namespace", 20*24*time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		isExpiring, notAfter, err := ca.IsExpiringWithin(10 * 24 * time.Hour)
+		Expect(isExpiring, err).To(BeFalse())
+		Expect(*notAfter).To(BeTemporally("~", time.Now().Add(20*24*time.Hour), time.Hour))
+
+		isExpiring, _, err = ca.IsExpiringWithin(30 * 24 * time.Hour)
+		Expect(isExpiring, err).To(BeTrue())
+
+		pair, err := ca.CreateAndSignPairWithDuration("this.host.name.com", CertTypeServer, nil, 5*24*time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		_, notAfter, err = pair.IsExpiringWithin(0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*notAfter).To(BeTemporally("~", time.Now().Add(5*24*time.Hour), time.Hour))
+	})
+
+	It("checks the validity of a certificate", func() {
+		ca, err := CreateRootCA("test", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ca.CheckValidity()).To(Succeed())
+
+		notAfter := time.Now().Add(-10 * time.Hour)
+		notBefore := notAfter.Add(-90 * 24 * time.Hour)
+		expired, err := createCAWithValidity(notBefore, notAfter, nil, nil, "root", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expired.CheckValidity()).To(MatchError(ContainSubstring("expired")))
+
+		notBefore = time.Now().Add(10 * time.Hour)
+		notYetValid, err := createCAWithValidity(notBefore, notBefore.Add(time.Hour), nil, nil, "root", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(notYetValid.CheckValidity()).To(MatchError(ContainSubstring("not valid before")))
+	})
+
 	When("we have a CA generated", func() {
 		It("should successfully generate a leaf certificate", func() {
 			rootCA, err := CreateRootCA("test", "namespace")
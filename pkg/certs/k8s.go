@@ -51,6 +51,15 @@ var (
 		Factor:   2,
 		Steps:    10,
 	}
+
+	// webhookSecretCheckBackoff is used while waiting for the webhook
+	// certificate to be generated by another operator deployment
+	webhookSecretCheckBackoff = wait.Backoff{
+		Duration: 1 * time.Second,
+		Jitter:   0.1,
+		Factor:   1.5,
+		Steps:    10,
+	}
 )
 
 // PublicKeyInfrastructure represent the PKI under which the operator and the WebHook server
@@ -92,13 +101,20 @@ type PublicKeyInfrastructure struct {
 // certificate given the secret containing the CA that will sign it.
 // Returns true if the certificate has been renewed
 func RenewLeafCertificate(caSecret *v1.Secret, secret *v1.Secret) (bool, error) {
+	return RenewLeafCertificateWithValidity(caSecret, secret, DefaultValidity())
+}
+
+// RenewLeafCertificateWithValidity is like RenewLeafCertificate, using the
+// passed validity to decide whether the certificate is expiring and how
+// long the renewed one lasts
+func RenewLeafCertificateWithValidity(caSecret *v1.Secret, secret *v1.Secret, validity Validity) (bool, error) {
 	// Verify the temporal validity of this CA
 	pair, err := ParseServerSecret(secret)
 	if err != nil {
 		return false, err
 	}
 
-	expiring, _, err := pair.IsExpiring()
+	expiring, _, err := pair.IsExpiringWithin(validity.RenewBefore)
 	if err != nil {
 		return false, err
 	}
@@ -122,7 +138,7 @@ func RenewLeafCertificate(caSecret *v1.Secret, secret *v1.Secret) (bool, error)
 		return false, err
 	}
 
-	err = pair.RenewCertificate(caPrivateKey, caCertificate)
+	err = pair.RenewCertificateWithDuration(caPrivateKey, caCertificate, validity.Duration)
 	if err != nil {
 		return false, err
 	}
@@ -154,6 +170,31 @@ func (pki *PublicKeyInfrastructure) Setup(
 	return nil
 }
 
+// WaitForCertificates waits for the webhook certificate to be available
+// in the certificate directory, without generating or renewing it. It is used
+// by the operator deployments that share the PKI managed by another one.
+// Once the certificate is renewed, the kubelet updates the mounted files
+// and the webhook server reloads them
+func (pki *PublicKeyInfrastructure) WaitForCertificates(
+	ctx context.Context,
+	kubeClient client.Client,
+) error {
+	return retry.OnError(webhookSecretCheckBackoff, func(err error) bool {
+		return apierrors.IsNotFound(err) || isSecretsMountNotRefreshedError(err)
+	}, func() error {
+		var webhookSecret v1.Secret
+		if err := kubeClient.Get(
+			ctx,
+			types.NamespacedName{Namespace: pki.OperatorNamespace, Name: pki.SecretName},
+			&webhookSecret,
+		); err != nil {
+			return err
+		}
+
+		return ensureMountedSecretsAreInSync(&webhookSecret, pki.CertDir)
+	})
+}
+
 // ensureRootCACertificate ensure that in the cluster there is a root CA Certificate
 func (pki *PublicKeyInfrastructure) ensureRootCACertificate(
 	ctx context.Context,
@@ -19,6 +19,7 @@ package certs
 import (
 	"context"
 	"os"
+	"path"
 	"time"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
@@ -428,3 +429,36 @@ var _ = Describe("Webhook environment creation", func() {
 		Expect(updatedSecondCrd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal(webhookSecret.Data["tls.crt"]))
 	})
 })
+
+var _ = Describe("Waiting for the webhook certificate", func() {
+	It("succeeds when the certificate is mounted", func(ctx SpecContext) {
+		tempDirName, err := os.MkdirTemp("/tmp", "cert_*")
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			err = os.RemoveAll(tempDirName)
+			Expect(err).ToNot(HaveOccurred())
+		}()
+
+		pki := pkiEnvironmentTemplate
+		pki.CertDir = tempDirName
+
+		webhookSecret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: pki.OperatorNamespace,
+				Name:      pki.SecretName,
+			},
+			Data: map[string][]byte{
+				"tls.crt": []byte("certificate"),
+				"tls.key": []byte("key"),
+			},
+		}
+		kubeClient := generateFakeClient()
+		Expect(kubeClient.Create(ctx, &webhookSecret)).To(Succeed())
+
+		for name, content := range webhookSecret.Data {
+			Expect(os.WriteFile(path.Join(tempDirName, name), content, 0o600)).To(Succeed())
+		}
+
+		Expect(pki.WaitForCertificates(ctx, kubeClient)).To(Succeed())
+	})
+})
@@ -0,0 +1,377 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+// The reasons of the events are part of the public interface of the
+// operator, as they are used by the alerting pipelines to select the
+// events: they must never be changed once released.
+
+// Reasons of the events about the instances of a cluster
+const (
+	// CreatingInstance is emitted when a new instance is being created
+	CreatingInstance = "CreatingInstance"
+
+	// ScaleDown is emitted when an instance is removed from the cluster
+	ScaleDown = "ScaleDown"
+
+	// NoScaleDown is emitted when the cluster can't be scaled down
+	NoScaleDown = "NoScaleDown"
+
+	// DeletePod is emitted when the Pod of an evicted or unschedulable
+	// instance is deleted
+	DeletePod = "DeletePod"
+
+	// DeletePVCs is emitted when the PVCs of an unschedulable instance
+	// are deleted
+	DeletePVCs = "DeletePVCs"
+
+	// RecloneReplica is emitted when a replica is cloned again from the primary
+	RecloneReplica = "RecloneReplica"
+
+	// ReplacingInstance is emitted when a surge instance is created to
+	// replace an existing one
+	ReplacingInstance = "ReplacingInstance"
+
+	// ReplacedInstance is emitted when an instance has been replaced
+	// by a surge instance
+	ReplacedInstance = "ReplacedInstance"
+
+	// ReplaceInstanceTimeout is emitted when a surge instance didn't catch
+	// up in time, and the outdated instance is restarted in-place
+	ReplaceInstanceTimeout = "ReplaceInstanceTimeout"
+
+	// ReplicaLagging is emitted when a replica is excluded from the
+	// read-only services because of its lag
+	ReplicaLagging = "ReplicaLagging"
+
+	// ReplicaCaughtUp is emitted when a lagging replica is included again
+	// in the read-only services
+	ReplicaCaughtUp = "ReplicaCaughtUp"
+
+	// ConfigReloadFailed is emitted when an instance fails to apply the
+	// new PostgreSQL configuration
+	ConfigReloadFailed = "ConfigReloadFailed"
+)
+
+// Reasons of the events about failovers and switchovers
+const (
+	// FailoverTriggered is emitted when the operator starts a failover.
+	// The value predates this catalogue, and is kept for compatibility
+	FailoverTriggered = "FailingOver"
+
+	// FailoverTarget is emitted when the new primary has been selected
+	FailoverTarget = "FailoverTarget"
+
+	// FailoverPostponed is emitted when the primary is detected to be
+	// unhealthy, but the failover detection thresholds are not reached yet
+	FailoverPostponed = "FailoverPostponed"
+
+	// FailoverDampened is emitted when a failover is not triggered because
+	// the last promotion happened within the flap damping cooldown
+	FailoverDampened = "FailoverDampened"
+
+	// FailoverArbiterDenied is emitted when the failover arbiter denies
+	// the promotion of a replica
+	FailoverArbiterDenied = "FailoverArbiterDenied"
+
+	// FailoverArbiterLeaseLost is emitted when the operator loses the
+	// lease of the failover arbiter
+	FailoverArbiterLeaseLost = "FailoverArbiterLeaseLost"
+
+	// SwitchingOver is emitted when a switchover starts
+	SwitchingOver = "SwitchingOver"
+
+	// SwitchoverCompleted is emitted when a switchover completes
+	SwitchoverCompleted = "SwitchoverCompleted"
+
+	// SwitchoverFailed is emitted when a switchover fails
+	SwitchoverFailed = "SwitchoverFailed"
+)
+
+// Reasons of the events about upgrades
+const (
+	// UpgradingInstance is emitted when an instance is being upgraded
+	UpgradingInstance = "UpgradingInstance"
+
+	// Switchover is emitted when a switchover is started to upgrade
+	// the primary
+	Switchover = "Switchover"
+
+	// InstanceManagerUpgraded is emitted when the instance manager has
+	// been upgraded in place
+	InstanceManagerUpgraded = "InstanceManagerUpgraded"
+
+	// InstanceManagerUpgradeFailed is emitted when the in-place upgrade
+	// of the instance manager fails
+	InstanceManagerUpgradeFailed = "InstanceManagerUpgradeFailed"
+
+	// DiscoverImage is emitted when the image catalog can't be found
+	DiscoverImage = "DiscoverImage"
+
+	// ImageUpdateDeferred is emitted when the update of the image is
+	// postponed to the next maintenance window
+	ImageUpdateDeferred = "ImageUpdateDeferred"
+
+	// MajorUpgrade is emitted when a major upgrade starts
+	MajorUpgrade = "MajorUpgrade"
+
+	// MajorUpgradeCompleted is emitted when a major upgrade completes
+	MajorUpgradeCompleted = "MajorUpgradeCompleted"
+
+	// MajorUpgradeFailed is emitted when a major upgrade fails
+	MajorUpgradeFailed = "MajorUpgradeFailed"
+)
+
+// Reasons of the events about the resources owned by a cluster
+const (
+	// CreatingServiceAccount is emitted when the service account is created
+	CreatingServiceAccount = "CreatingServiceAccount"
+
+	// UpdatingServiceAccount is emitted when the service account is updated
+	UpdatingServiceAccount = "UpdatingServiceAccount"
+
+	// CreatingRole is emitted when the role is created
+	CreatingRole = "CreatingRole"
+
+	// UpdatingRole is emitted when the role is updated
+	UpdatingRole = "UpdatingRole"
+
+	// CreatingPodDisruptionBudget is emitted when a PodDisruptionBudget is created
+	CreatingPodDisruptionBudget = "CreatingPodDisruptionBudget"
+
+	// UpdatingPodDisruptionBudget is emitted when a PodDisruptionBudget is updated
+	UpdatingPodDisruptionBudget = "UpdatingPodDisruptionBudget"
+
+	// DeletingPodDisruptionBudget is emitted when a PodDisruptionBudget is deleted
+	DeletingPodDisruptionBudget = "DeletingPodDisruptionBudget"
+
+	// OrphanedResource is emitted when a resource of a deleted cluster
+	// is found
+	OrphanedResource = "OrphanedResource"
+
+	// GarbageCollected is emitted when an orphaned resource is deleted
+	GarbageCollected = "GarbageCollected"
+
+	// NameClash is emitted when a Pooler has the same name of a Cluster
+	NameClash = "NameClash"
+)
+
+// Reasons of the events about certificates
+const (
+	// SecretNotFound is emitted when a secret containing a certificate
+	// doesn't exist
+	SecretNotFound = "SecretNotFound"
+
+	// InvalidCASecret is emitted when the secret of a CA is not valid
+	InvalidCASecret = "InvalidCASecret"
+
+	// SecretIsExpiring is emitted when a certificate is about to expire
+	SecretIsExpiring = "SecretIsExpiring"
+
+	// IssuingCertificate is emitted when a cert-manager Certificate is
+	// created to issue a certificate of the cluster
+	IssuingCertificate = "IssuingCertificate"
+
+	// CertManagerNotFound is emitted when the certificates of a cluster
+	// should be issued by cert-manager, but cert-manager is not installed
+	CertManagerNotFound = "CertManagerNotFound"
+)
+
+// Reasons of the events about the secrets of the external clusters
+const (
+	// SecretNotGranted is emitted when a secret of another namespace
+	// is not granted to the cluster by any ReplicationGrant
+	SecretNotGranted = "SecretNotGranted"
+)
+
+// Reasons of the events about backups
+const (
+	// BackupStarted is emitted when a backup starts. The values of the
+	// reasons in this block predate this catalogue, and are kept for
+	// compatibility with the existing alerts
+	BackupStarted = "Starting"
+
+	// BackupRestarting is emitted when a backup is started again because
+	// the selected instance is not available anymore
+	BackupRestarting = "ReStarting"
+
+	// BackupCompleted is emitted when a backup completes
+	BackupCompleted = "Completed"
+
+	// BackupFailed is emitted by the instance manager when a backup fails
+	BackupFailed = "Failed"
+
+	// BackupError is emitted by the operator when a backup can't be taken
+	BackupError = "Error"
+
+	// BackupPending is emitted when a backup waits for the target
+	// instance to be ready
+	BackupPending = "BackupPending"
+
+	// BackupHookFailed is emitted when a backup hook fails
+	BackupHookFailed = "BackupHookFailed"
+
+	// FindingCluster is emitted when the cluster of a backup can't be found
+	FindingCluster = "FindingCluster"
+
+	// FindingPod is emitted when the instance to be backed up can't be found
+	FindingPod = "FindingPod"
+
+	// ClusterHasNoVolumeSnapshotCRD is emitted when a volume snapshot backup
+	// is requested but the VolumeSnapshot CRD is not installed
+	ClusterHasNoVolumeSnapshotCRD = "ClusterHasNoVolumeSnapshotCRD"
+
+	// FencePod is emitted when an instance is fenced for a cold backup
+	FencePod = "FencePod"
+
+	// UnfencePod is emitted when an instance is unfenced after a cold backup
+	UnfencePod = "UnfencePod"
+
+	// CreateSnapshot is emitted when the volume snapshots are created
+	CreateSnapshot = "CreateSnapshot"
+
+	// RetentionPolicyApplied is emitted when the retention policy has
+	// been applied
+	RetentionPolicyApplied = "RetentionPolicyApplied"
+
+	// RetentionPolicyFailed is emitted when the retention policy can't
+	// be applied
+	RetentionPolicyFailed = "RetentionPolicyFailed"
+
+	// BackupDeleted is emitted when a backup is deleted from the object store
+	BackupDeleted = "BackupDeleted"
+
+	// BackupDeletionFailed is emitted when a backup can't be deleted from
+	// the object store
+	BackupDeletionFailed = "BackupDeletionFailed"
+
+	// BackupRetained is emitted when a backup is kept in the object store
+	// after the deletion of its Backup object
+	BackupRetained = "BackupRetained"
+
+	// VerificationStarted is emitted when the verification of a backup starts
+	VerificationStarted = "VerificationStarted"
+
+	// VerificationSucceeded is emitted when a backup has been verified
+	VerificationSucceeded = "VerificationSucceeded"
+
+	// VerificationFailed is emitted when the verification of a backup fails
+	VerificationFailed = "VerificationFailed"
+)
+
+// Reasons of the events about scheduled backups
+const (
+	// BackupSchedule is emitted when a backup is scheduled
+	BackupSchedule = "BackupSchedule"
+
+	// BackupCreation is emitted when a scheduled backup can't be created
+	BackupCreation = "BackupCreation"
+
+	// BackupSkipped is emitted when a scheduled backup is skipped
+	BackupSkipped = "BackupSkipped"
+
+	// BackupReplaced is emitted when a running scheduled backup is
+	// replaced by a new one
+	BackupReplaced = "BackupReplaced"
+
+	// InvalidCluster is emitted when the cluster of a scheduled backup
+	// can't be found
+	InvalidCluster = "InvalidCluster"
+
+	// ClusterNotHealthy is emitted when a scheduled backup waits for
+	// the cluster to be healthy
+	ClusterNotHealthy = "ClusterNotHealthy"
+)
+
+// Reasons of the events about recoveries
+const (
+	// ErrorNoBackup is emitted when the Backup object to recover from
+	// is missing
+	ErrorNoBackup = "ErrorNoBackup"
+
+	// RecoveryTargetUnreachable is emitted when the recovery target can't
+	// be reached with the available backups
+	RecoveryTargetUnreachable = "RecoveryTargetUnreachable"
+
+	// InPlaceRestore is emitted when an in-place restore starts
+	InPlaceRestore = "InPlaceRestore"
+
+	// InPlaceRestoreCompleted is emitted when an in-place restore completes
+	InPlaceRestoreCompleted = "InPlaceRestoreCompleted"
+
+	// InPlaceRestoreFailed is emitted when an in-place restore fails
+	InPlaceRestoreFailed = "InPlaceRestoreFailed"
+)
+
+// Reasons of the events about replication slots
+const (
+	// InactiveReplicationSlot is emitted when a replication slot has
+	// been inactive for longer than the retention time
+	InactiveReplicationSlot = "InactiveReplicationSlot"
+
+	// DroppedReplicationSlot is emitted when a replication slot is dropped
+	DroppedReplicationSlot = "DroppedReplicationSlot"
+
+	// DropReplicationSlotFailed is emitted when a replication slot can't
+	// be dropped
+	DropReplicationSlotFailed = "DropReplicationSlotFailed"
+)
+
+// Reasons of the events about the WAL volume
+const (
+	// WALStorageAlmostFull is emitted when the emergency cleanup of the
+	// WAL volume starts
+	WALStorageAlmostFull = "WALStorageAlmostFull"
+
+	// WALStorageFull is emitted when the emergency cleanup can't free
+	// the WAL volume
+	WALStorageFull = "WALStorageFull"
+
+	// WALStorageThrottlingConnections is emitted when the primary starts
+	// throttling the connections
+	WALStorageThrottlingConnections = "WALStorageThrottlingConnections"
+
+	// WALStorageWritesFenced is emitted when the writes are fenced
+	WALStorageWritesFenced = "WALStorageWritesFenced"
+
+	// WALStorageWritesUnfenced is emitted when the fencing of the writes
+	// is lifted
+	WALStorageWritesUnfenced = "WALStorageWritesUnfenced"
+)
+
+// Reasons of the events about benchmarks
+const (
+	// BenchmarkStarted is emitted when a benchmark starts
+	BenchmarkStarted = "BenchmarkStarted"
+
+	// BenchmarkCompleted is emitted when a benchmark completes
+	BenchmarkCompleted = "BenchmarkCompleted"
+
+	// BenchmarkFailed is emitted when a benchmark fails
+	BenchmarkFailed = "BenchmarkFailed"
+)
+
+// Reasons of the events about change data captures
+const (
+	// ChangeDataCaptureBridgeCreated is emitted when the deployment of the
+	// bridge publishing the changes is created
+	ChangeDataCaptureBridgeCreated = "ChangeDataCaptureBridgeCreated"
+
+	// ChangeDataCaptureBridgeUpdated is emitted when the deployment of the
+	// bridge publishing the changes is updated
+	ChangeDataCaptureBridgeUpdated = "ChangeDataCaptureBridgeUpdated"
+)
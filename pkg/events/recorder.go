@@ -0,0 +1,194 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// DefaultDeduplicationWindow is the time during which an event
+	// identical to an already emitted one is discarded
+	DefaultDeduplicationWindow = 5 * time.Minute
+
+	// DefaultRateLimitBurst is the number of events with the same reason
+	// that can be emitted at once for an object
+	DefaultRateLimitBurst = 10
+
+	// DefaultRateLimitInterval is the time needed to allow one more event
+	// with the same reason for an object, once the burst is exhausted
+	DefaultRateLimitInterval = 30 * time.Second
+)
+
+// A Recorder is a record.EventRecorder discarding the events identical to
+// the ones emitted in the deduplication window, and limiting the rate of
+// the events emitted with the same reason for each object. This keeps the
+// events readable and consumable by the alerting pipelines even when a
+// reconciliation loop keeps failing for the same reason
+type Recorder struct {
+	recorder record.EventRecorder
+
+	deduplicationWindow time.Duration
+	burst               float64
+	interval            time.Duration
+
+	// now is the clock used to deduplicate and rate limit the events
+	now func() time.Time
+
+	mu        sync.Mutex
+	emitted   map[emittedKey]time.Time
+	limiters  map[limiterKey]*limiter
+	lastSweep time.Time
+}
+
+// emittedKey identifies an event
+type emittedKey struct {
+	object    string
+	eventtype string
+	reason    string
+	message   string
+}
+
+// limiterKey identifies the events with the same reason for an object
+type limiterKey struct {
+	object string
+	reason string
+}
+
+// limiter is a token bucket limiting the rate of the events
+type limiter struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRecorder creates a Recorder emitting the events through the passed
+// one with the default deduplication window and rate limit
+func NewRecorder(recorder record.EventRecorder) *Recorder {
+	return &Recorder{
+		recorder:            recorder,
+		deduplicationWindow: DefaultDeduplicationWindow,
+		burst:               DefaultRateLimitBurst,
+		interval:            DefaultRateLimitInterval,
+		now:                 time.Now,
+		emitted:             make(map[emittedKey]time.Time),
+		limiters:            make(map[limiterKey]*limiter),
+	}
+}
+
+// Event implements record.EventRecorder
+func (r *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.allow(object, eventtype, reason, message) {
+		r.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+// Eventf implements record.EventRecorder
+func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf implements record.EventRecorder. The annotations are not
+// considered when deduplicating the events
+func (r *Recorder) AnnotatedEventf(
+	object runtime.Object,
+	annotations map[string]string,
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if r.allow(object, eventtype, reason, message) {
+		r.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+// allow checks whether an event should be emitted, recording it
+func (r *Recorder) allow(object runtime.Object, eventtype, reason, message string) bool {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		// Let the underlying recorder report the invalid object
+		return true
+	}
+	// The UID tells apart an object from the one recreated with the same
+	// name, whose events must not be discarded
+	objectKey := string(accessor.GetUID())
+	if objectKey == "" {
+		objectKey = fmt.Sprintf("%T/%s/%s", object, accessor.GetNamespace(), accessor.GetName())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	key := emittedKey{object: objectKey, eventtype: eventtype, reason: reason, message: message}
+	if emittedAt, ok := r.emitted[key]; ok && now.Sub(emittedAt) < r.deduplicationWindow {
+		log.Debug("Discarded duplicated event",
+			"name", accessor.GetName(), "namespace", accessor.GetNamespace(),
+			"reason", reason, "message", message)
+		return false
+	}
+
+	bucket := r.limiters[limiterKey{object: objectKey, reason: reason}]
+	if bucket == nil {
+		bucket = &limiter{tokens: r.burst, last: now}
+		r.limiters[limiterKey{object: objectKey, reason: reason}] = bucket
+	}
+	bucket.tokens = math.Min(r.burst, bucket.tokens+float64(now.Sub(bucket.last))/float64(r.interval))
+	bucket.last = now
+	if bucket.tokens < 1 {
+		log.Debug("Discarded rate limited event",
+			"name", accessor.GetName(), "namespace", accessor.GetNamespace(),
+			"reason", reason, "message", message)
+		return false
+	}
+	bucket.tokens--
+
+	r.emitted[key] = now
+	return true
+}
+
+// sweep forgets the events out of the deduplication window and the
+// rate limits that have been fully restored, once per window
+func (r *Recorder) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.deduplicationWindow {
+		return
+	}
+	r.lastSweep = now
+
+	for key, emittedAt := range r.emitted {
+		if now.Sub(emittedAt) >= r.deduplicationWindow {
+			delete(r.emitted, key)
+		}
+	}
+
+	restore := time.Duration(r.burst * float64(r.interval))
+	for key, bucket := range r.limiters {
+		if now.Sub(bucket.last) >= restore {
+			delete(r.limiters, key)
+		}
+	}
+}
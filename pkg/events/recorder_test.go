@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("events recorder", func() {
+	var (
+		fake     *record.FakeRecorder
+		recorder *Recorder
+		now      time.Time
+		cluster  *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		fake = record.NewFakeRecorder(100)
+		recorder = NewRecorder(fake)
+		recorder.now = func() time.Time { return now }
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+	})
+
+	It("discards the duplicated events in the deduplication window", func() {
+		recorder.Eventf(cluster, corev1.EventTypeWarning, FailoverTriggered, "Failing over from %s", "pod-1")
+		recorder.Event(cluster, corev1.EventTypeWarning, FailoverTriggered, "Failing over from pod-1")
+		Expect(fake.Events).To(HaveLen(1))
+
+		recorder.Event(cluster, corev1.EventTypeWarning, FailoverTriggered, "Failing over from pod-2")
+		Expect(fake.Events).To(HaveLen(2))
+
+		now = now.Add(DefaultDeduplicationWindow)
+		recorder.Event(cluster, corev1.EventTypeWarning, FailoverTriggered, "Failing over from pod-1")
+		Expect(fake.Events).To(HaveLen(3))
+	})
+
+	It("deduplicates the events of each object separately", func() {
+		other := cluster.DeepCopy()
+		other.Name = "other"
+
+		recorder.Event(cluster, corev1.EventTypeNormal, BackupStarted, "Backup started")
+		recorder.Event(other, corev1.EventTypeNormal, BackupStarted, "Backup started")
+		recorder.Event(&apiv1.Pooler{ObjectMeta: cluster.ObjectMeta}, corev1.EventTypeNormal,
+			BackupStarted, "Backup started")
+		Expect(fake.Events).To(HaveLen(3))
+	})
+
+	It("doesn't discard the events of an object recreated with the same name", func() {
+		cluster.UID = "first-uid"
+		recorder.Event(cluster, corev1.EventTypeNormal, BackupStarted, "Backup started")
+
+		recreated := cluster.DeepCopy()
+		recreated.UID = "second-uid"
+		recorder.Event(recreated, corev1.EventTypeNormal, BackupStarted, "Backup started")
+		recorder.Event(cluster, corev1.EventTypeNormal, BackupStarted, "Backup started")
+		Expect(fake.Events).To(HaveLen(2))
+	})
+
+	It("limits the rate of the events with the same reason", func() {
+		for i := 0; i < DefaultRateLimitBurst+5; i++ {
+			recorder.Eventf(cluster, corev1.EventTypeWarning, ConfigReloadFailed, "attempt %d", i)
+		}
+		Expect(fake.Events).To(HaveLen(DefaultRateLimitBurst))
+
+		recorder.Event(cluster, corev1.EventTypeNormal, BackupStarted, "Backup started")
+		Expect(fake.Events).To(HaveLen(DefaultRateLimitBurst + 1))
+
+		now = now.Add(DefaultRateLimitInterval)
+		recorder.Event(cluster, corev1.EventTypeWarning, ConfigReloadFailed, "attempt 100")
+		recorder.Event(cluster, corev1.EventTypeWarning, ConfigReloadFailed, "attempt 101")
+		Expect(fake.Events).To(HaveLen(DefaultRateLimitBurst + 2))
+	})
+
+	It("keeps the annotations of the events", func() {
+		recorder.AnnotatedEventf(cluster, map[string]string{"key": "value"},
+			corev1.EventTypeNormal, BackupStarted, "Backup %s started", "first")
+		recorder.AnnotatedEventf(cluster, map[string]string{"key": "value"},
+			corev1.EventTypeNormal, BackupStarted, "Backup %s started", "first")
+		Expect(fake.Events).To(HaveLen(1))
+		Expect(fake.Events).To(Receive(Equal("Normal Starting Backup first started map[key:value]")))
+	})
+
+	It("forgets the old events", func() {
+		recorder.Event(cluster, corev1.EventTypeNormal, BackupStarted, "Backup started")
+		Expect(recorder.emitted).To(HaveLen(1))
+		Expect(recorder.limiters).To(HaveLen(1))
+
+		now = now.Add(DefaultRateLimitBurst * DefaultRateLimitInterval)
+		recorder.Event(cluster, corev1.EventTypeNormal, BackupCompleted, "Backup completed")
+		Expect(recorder.emitted).To(HaveLen(1))
+		Expect(recorder.limiters).To(HaveLen(1))
+	})
+})
@@ -43,7 +43,31 @@ func AddInstanceRunCommands(cmd *exec.Cmd) {
 	}
 }
 
+// KillProcessGroupOnCancel runs the command in a new process group, which
+// is entirely killed when the context of the command is cancelled
+func KillProcessGroupOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
 // Umask sets the process's unix umask to prevent/allow permissions changes
 func Umask(mask int) int {
 	return unix.Umask(mask)
 }
+
+// GetDiskUsage gets the total size and the space available to unprivileged
+// users, in bytes, of the filesystem containing the passed path
+func GetDiskUsage(path string) (total uint64, available uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	// The types of the fields depend on the platform
+	blockSize := uint64(stat.Bsize) // #nosec G115
+	return uint64(stat.Blocks) * blockSize, uint64(stat.Bavail) * blockSize, nil
+}
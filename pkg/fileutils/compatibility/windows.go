@@ -35,7 +35,17 @@ func AddInstanceRunCommands(cmd *exec.Cmd) {
 	return
 }
 
+// KillProcessGroupOnCancel mimics the behavior for compatibility issues
+func KillProcessGroupOnCancel(cmd *exec.Cmd) {
+	return
+}
+
 // Umask sets the process's unix umask to prevent/allow permissions changes
 func Umask(mask int) int {
 	return mask
 }
+
+// GetDiskUsage fakes function for cross-compiling compatibility
+func GetDiskUsage(path string) (total uint64, available uint64, err error) {
+	return 0, 0, fmt.Errorf("function GetDiskUsage() is not available in Windows")
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
@@ -31,9 +32,11 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/spool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 const (
@@ -43,6 +46,10 @@ const (
 	CheckEmptyWalArchiveFile = ".check-empty-wal-archive"
 )
 
+// encryptionDirectory is where the encrypted copies of the WAL files
+// are written before being archived
+const encryptionDirectory = postgres.ScratchDataDirectory
+
 // WALArchiver is a structure containing every info need to archive a set of WAL files
 // using barman-cloud-wal-archive
 type WALArchiver struct {
@@ -55,6 +62,10 @@ type WALArchiver struct {
 	// The environment that should be used to invoke barman-cloud-wal-archive
 	env []string
 
+	// The keys used to encrypt the WAL files before archiving them,
+	// nil when the client-side encryption is not configured
+	encryptionKeys *encryption.Keys
+
 	pgDataDirectory string
 }
 
@@ -89,10 +100,16 @@ func New(
 		return nil, fmt.Errorf("while creating spool directory: %w", err)
 	}
 
+	encryptionKeys, err := encryption.FromEnv(env)
+	if err != nil {
+		return nil, fmt.Errorf("while loading the WAL encryption keys: %w", err)
+	}
+
 	archiver = &WALArchiver{
 		cluster:         cluster,
 		spool:           walArchiveSpool,
 		env:             env,
+		encryptionKeys:  encryptionKeys,
 		pgDataDirectory: pgDataDirectory,
 	}
 	return archiver, nil
@@ -167,9 +184,20 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 	if optionsLength >= math.MaxInt-1 {
 		return fmt.Errorf("can't archive wal file %v, options too long", walName)
 	}
+
+	walPath := walName
+	if archiver.encryptionKeys != nil {
+		encryptedWALPath, cleanup, err := archiver.encrypt(walName)
+		if err != nil {
+			return fmt.Errorf("while encrypting the WAL file %v: %w", walName, err)
+		}
+		defer cleanup()
+		walPath = encryptedWALPath
+	}
+
 	options := make([]string, optionsLength, optionsLength+1)
 	copy(options, baseOptions)
-	options = append(options, walName)
+	options = append(options, walPath)
 
 	log.Trace("Executing "+barmanCapabilities.BarmanCloudWalArchive,
 		"walName", walName,
@@ -203,6 +231,26 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 	return nil
 }
 
+// encrypt writes the encrypted copy of a WAL file in a temporary directory,
+// with the same name so that it is archived under that name. The returned
+// function removes it
+func (archiver *WALArchiver) encrypt(walName string) (string, func(), error) {
+	directory, err := os.MkdirTemp(encryptionDirectory, "wal-encryption-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(directory)
+	}
+
+	encryptedWALPath := filepath.Join(directory, filepath.Base(walName))
+	if err := archiver.encryptionKeys.EncryptFile(walName, encryptedWALPath); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return encryptedWALPath, cleanup, nil
+}
+
 // IsCheckWalArchiveFlagFilePresent returns true if the file CheckEmptyWalArchiveFile is present in the PGDATA directory
 func (archiver *WALArchiver) IsCheckWalArchiveFlagFilePresent(ctx context.Context, pgDataDirectory string) bool {
 	contextLogger := log.FromContext(ctx)
@@ -36,7 +36,8 @@ import (
 // the retention policies, the server name and the environment variables
 func DeleteBackupsByPolicy(
 	ctx context.Context,
-	backupConfig *v1.BackupConfiguration,
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	retentionPolicy string,
 	serverName string,
 	env []string,
 ) error {
@@ -55,7 +56,6 @@ func DeleteBackupsByPolicy(
 		return err
 	}
 
-	barmanConfiguration := backupConfig.BarmanObjectStore
 	var options []string
 	if barmanConfiguration.EndpointURL != "" {
 		options = append(options, "--endpoint-url", barmanConfiguration.EndpointURL)
@@ -66,7 +66,7 @@ func DeleteBackupsByPolicy(
 		return err
 	}
 
-	parsedPolicy, err := utils.ParsePolicy(backupConfig.RetentionPolicy)
+	parsedPolicy, err := utils.ParsePolicy(retentionPolicy)
 	if err != nil {
 		return err
 	}
@@ -97,14 +97,84 @@ func DeleteBackupsByPolicy(
 	return nil
 }
 
+// DeleteBackup executes a command that deletes the base backup referenced
+// by the passed Backup from the object store, given the Barman object store
+// configuration, the cluster name and the environment variables. The WAL
+// files that are not needed anymore by the remaining backups are removed too.
+// The backup must have been taken on the object store having the passed
+// configuration
+func DeleteBackup(
+	ctx context.Context,
+	backup *v1.Backup,
+	clusterName string,
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	env []string,
+) error {
+	contextLogger := log.FromContext(ctx).WithName("barman")
+
+	if !useSameBackupLocation(&backup.Status, clusterName, barmanConfiguration) {
+		return fmt.Errorf("backup %s was not taken on the configured object store", backup.Name)
+	}
+
+	capabilities, err := barmanCapabilities.CurrentCapabilities()
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.HasRetentionPolicy {
+		err := fmt.Errorf(
+			"barman >= 2.14 is required to delete a backup, current: %v",
+			capabilities.Version)
+		contextLogger.Error(err, "Failed deleting backup")
+		return err
+	}
+
+	var options []string
+	if barmanConfiguration.EndpointURL != "" {
+		options = append(options, "--endpoint-url", barmanConfiguration.EndpointURL)
+	}
+
+	options, err = AppendCloudProviderOptionsFromConfiguration(options, barmanConfiguration)
+	if err != nil {
+		return err
+	}
+
+	options = append(
+		options,
+		"--backup-id",
+		backup.Status.BackupID,
+		barmanConfiguration.DestinationPath,
+		backup.Status.ServerName)
+
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	cmd := exec.Command(barmanCapabilities.BarmanCloudBackupDelete, options...) // #nosec G204
+	cmd.Env = env
+	cmd.Stdout = &stdoutBuffer
+	cmd.Stderr = &stderrBuffer
+	err = cmd.Run()
+	if err != nil {
+		contextLogger.Error(err,
+			"Error invoking "+barmanCapabilities.BarmanCloudBackupDelete,
+			"options", options,
+			"stdout", stdoutBuffer.String(),
+			"stderr", stderrBuffer.String())
+		return err
+	}
+
+	return nil
+}
+
 // DeleteBackupsNotInCatalog deletes all Backup objects pointing to the given cluster that are not
-// present in the backup anymore
+// present in the backup anymore, returning the IDs of the deleted ones. Only the backups taken
+// on the object store having the passed configuration are considered
 func DeleteBackupsNotInCatalog(
 	ctx context.Context,
 	cli client.Client,
 	cluster *v1.Cluster,
+	configuration *v1.BarmanObjectStoreConfiguration,
 	catalog *catalog.Catalog,
-) error {
+) ([]string, error) {
 	// We had two options:
 	//
 	// A. quicker
@@ -129,15 +199,16 @@ func DeleteBackupsNotInCatalog(
 	backups := v1.BackupList{}
 	err := cli.List(ctx, &backups, client.InNamespace(cluster.GetNamespace()))
 	if err != nil {
-		return fmt.Errorf("while getting backups: %w", err)
+		return nil, fmt.Errorf("while getting backups: %w", err)
 	}
 
+	var deletedBackupIDs []string
 	var errors []error
 	for id, backup := range backups.Items {
 		backup := backup
 		if backup.Spec.Cluster.Name != cluster.GetName() ||
 			backup.Status.Phase != v1.BackupPhaseCompleted ||
-			!useSameBackupLocation(&backup.Status, cluster) {
+			!useSameBackupLocation(&backup.Status, cluster.Name, configuration) {
 			continue
 		}
 		var found bool
@@ -158,26 +229,31 @@ func DeleteBackupsNotInCatalog(
 					backup.Name,
 					err,
 				))
+				continue
 			}
+			deletedBackupIDs = append(deletedBackupIDs, backup.Status.BackupID)
 		}
 	}
 
 	if errors != nil {
-		return fmt.Errorf("got errors while deleting Backups not in the cluster: %v", errors)
+		return deletedBackupIDs, fmt.Errorf("got errors while deleting Backups not in the cluster: %v", errors)
 	}
-	return nil
+	return deletedBackupIDs, nil
 }
 
 // useSameBackupLocation checks whether the given backup was taken using the same configuration as provided
-func useSameBackupLocation(backup *v1.BackupStatus, cluster *v1.Cluster) bool {
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+func useSameBackupLocation(
+	backup *v1.BackupStatus,
+	clusterName string,
+	configuration *v1.BarmanObjectStoreConfiguration,
+) bool {
+	if configuration == nil {
 		return false
 	}
-	configuration := cluster.Spec.Backup.BarmanObjectStore
 	return backup.EndpointURL == configuration.EndpointURL &&
 		backup.DestinationPath == configuration.DestinationPath &&
 		(backup.ServerName == configuration.ServerName ||
 			// if not specified we use the cluster name as server name
-			(configuration.ServerName == "" && backup.ServerName == cluster.Name)) &&
+			(configuration.ServerName == "" && backup.ServerName == clusterName)) &&
 		reflect.DeepEqual(backup.BarmanCredentials, configuration.BarmanCredentials)
 }
@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("useSameBackupLocation", func() {
+	configuration := &v1.BarmanObjectStoreConfiguration{
+		DestinationPath: "s3://dr/",
+		EndpointURL:     "https://dr.example.com",
+	}
+
+	It("matches a backup taken on the same object store", func() {
+		backup := &v1.BackupStatus{
+			DestinationPath: "s3://dr/",
+			EndpointURL:     "https://dr.example.com",
+			ServerName:      "cluster-example",
+		}
+		Expect(useSameBackupLocation(backup, "cluster-example", configuration)).To(BeTrue())
+	})
+
+	It("doesn't match a backup taken on a different object store", func() {
+		backup := &v1.BackupStatus{
+			DestinationPath: "s3://main/",
+			ServerName:      "cluster-example",
+		}
+		Expect(useSameBackupLocation(backup, "cluster-example", configuration)).To(BeFalse())
+	})
+
+	It("doesn't match anything without a configuration", func() {
+		Expect(useSameBackupLocation(&v1.BackupStatus{}, "cluster-example", nil)).To(BeFalse())
+	})
+})
+
+var _ = Describe("DeleteBackupsNotInCatalog", func() {
+	const namespace = "default"
+
+	configuration := &v1.BarmanObjectStoreConfiguration{
+		DestinationPath: "s3://backups/",
+	}
+	cluster := &v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+	}
+
+	newBackup := func(name, backupID string) *v1.Backup {
+		return &v1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       v1.BackupSpec{Cluster: v1.LocalObjectReference{Name: cluster.Name}},
+			Status: v1.BackupStatus{
+				Phase:             v1.BackupPhaseCompleted,
+				BackupID:          backupID,
+				BarmanCredentials: configuration.BarmanCredentials,
+				DestinationPath:   configuration.DestinationPath,
+				ServerName:        cluster.Name,
+			},
+		}
+	}
+
+	It("deletes the backups missing in the catalog and returns their IDs", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				newBackup("backup-1", "20240101T000000"),
+				newBackup("backup-2", "20240102T000000"),
+				newBackup("backup-3", "20240103T000000"),
+			).
+			Build()
+		backupCatalog := catalog.NewCatalog([]catalog.BarmanBackup{{ID: "20240103T000000"}})
+
+		deleted, err := DeleteBackupsNotInCatalog(ctx, cli, cluster, configuration, backupCatalog)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(ConsistOf("20240101T000000", "20240102T000000"))
+
+		var backups v1.BackupList
+		Expect(cli.List(ctx, &backups)).To(Succeed())
+		Expect(backups.Items).To(HaveLen(1))
+		Expect(backups.Items[0].Name).To(Equal("backup-3"))
+	})
+})
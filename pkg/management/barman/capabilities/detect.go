@@ -41,6 +41,11 @@ func detect(version *semver.Version) *Capabilities {
 	newCapabilities.Version = version
 
 	switch {
+	case version.GE(semver.Version{Major: 3, Minor: 12}):
+		// zstd and lz4 compression of the WAL files, added in Barman >= 3.12
+		newCapabilities.HasZstd = true
+		newCapabilities.HasLZ4 = true
+		fallthrough
 	case version.GE(semver.Version{Major: 3, Minor: 4}):
 		// The --name flag was added to Barman in version 3.3 but we also require the
 		// barman-cloud-backup-show command which was not added until Barman version 3.4
@@ -19,11 +19,36 @@ package capabilities
 import (
 	"github.com/blang/semver"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("detect capabilities", func() {
+	It("ensures that the 3.12 version supports zstd and lz4 compression", func() {
+		version, err := semver.ParseTolerant("3.12.0")
+		Expect(err).ToNot(HaveOccurred())
+		capabilities := detect(&version)
+		Expect(capabilities.HasZstd).To(BeTrue())
+		Expect(capabilities.HasLZ4).To(BeTrue())
+		Expect(capabilities.CheckCompression(apiv1.CompressionTypeZstd)).To(Succeed())
+		Expect(capabilities.CheckCompression(apiv1.CompressionTypeLZ4)).To(Succeed())
+	})
+
+	It("rejects the compression algorithms not supported by the Barman version", func() {
+		version, err := semver.ParseTolerant("3.4.0")
+		Expect(err).ToNot(HaveOccurred())
+		capabilities := detect(&version)
+		Expect(capabilities.CheckCompression(apiv1.CompressionTypeNone)).To(Succeed())
+		Expect(capabilities.CheckCompression(apiv1.CompressionTypeGzip)).To(Succeed())
+		Expect(capabilities.CheckCompression(apiv1.CompressionTypeSnappy)).To(Succeed())
+		Expect(capabilities.CheckCompression(apiv1.CompressionTypeZstd)).To(
+			MatchError("zstd compression is not supported in Barman 3.4.0"))
+		Expect(capabilities.CheckCompression(apiv1.CompressionTypeLZ4)).To(
+			MatchError("lz4 compression is not supported in Barman 3.4.0"))
+	})
+
 	It("ensures that all capabilities are true for the 3.4 version", func() {
 		version, err := semver.ParseTolerant("3.4.0")
 		Expect(err).ToNot(HaveOccurred())
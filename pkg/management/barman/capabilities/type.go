@@ -18,6 +18,8 @@ limitations under the License.
 package capabilities
 
 import (
+	"fmt"
+
 	"github.com/blang/semver"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -35,6 +37,8 @@ type Capabilities struct {
 	HasTags                    bool
 	HasCheckWalArchive         bool
 	HasSnappy                  bool
+	HasZstd                    bool
+	HasLZ4                     bool
 	HasErrorCodesForWALRestore bool
 	HasErrorCodesForRestore    bool
 	HasAzureManagedIdentity    bool
@@ -48,3 +52,25 @@ func (c *Capabilities) ShouldExecuteBackupWithName(cluster *apiv1.Cluster) bool
 
 	return c.hasName
 }
+
+// CheckCompression returns an error if the passed compression algorithm
+// is not supported by the Barman version in use
+func (c *Capabilities) CheckCompression(compression apiv1.CompressionType) error {
+	var supported bool
+	switch compression {
+	case apiv1.CompressionTypeSnappy:
+		supported = c.HasSnappy
+	case apiv1.CompressionTypeZstd:
+		supported = c.HasZstd
+	case apiv1.CompressionTypeLZ4:
+		supported = c.HasLZ4
+	default:
+		supported = true
+	}
+
+	if !supported {
+		return fmt.Errorf("%s compression is not supported in Barman %v", compression, c.Version)
+	}
+
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -65,13 +66,32 @@ func EnvSetRestoreCloudCredentials(
 }
 
 // envSetCloudCredentials sets the AWS environment variables given the configuration
-// inside the cluster
+// inside the cluster, together with the keys used to encrypt the WAL files
 func envSetCloudCredentials(
 	ctx context.Context,
 	c client.Client,
 	namespace string,
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	env []string,
+) (envs []string, err error) {
+	if configuration.Wal != nil && configuration.Wal.ClientSideEncryption != nil {
+		env, err = envSetWALEncryptionKeys(ctx, c, namespace, configuration.Wal.ClientSideEncryption, env)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return envSetProviderCredentials(ctx, c, namespace, configuration, env)
+}
+
+// envSetProviderCredentials sets the environment variables holding
+// the credentials of the cloud provider
+func envSetProviderCredentials(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	env []string,
 ) (envs []string, err error) {
 	if configuration.BarmanCredentials.AWS != nil {
 		return envSetAWSCredentials(ctx, c, namespace, configuration.BarmanCredentials.AWS, env)
@@ -287,6 +307,44 @@ func reconcileGoogleCredentials(
 	return err
 }
 
+// envSetWALEncryptionKeys sets the environment variable holding the keys
+// used to encrypt and decrypt the WAL files, read from the keys secret
+func envSetWALEncryptionKeys(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	configuration *apiv1.WALClientSideEncryption,
+	env []string,
+) ([]string, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: configuration.KeysSecret.Name}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("while getting secret %s: %w", configuration.KeysSecret.Name, err)
+	}
+
+	keys := encryption.Keys{
+		Active:           configuration.ActiveKey,
+		Keys:             make(map[string][]byte, len(secret.Data)),
+		AllowUnencrypted: configuration.AllowUnencryptedFiles,
+	}
+	for name, value := range secret.Data {
+		key, err := encryption.ParseKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s, inside secret %s: %w", name, configuration.KeysSecret.Name, err)
+		}
+		keys.Keys[name] = key
+	}
+	if err := keys.Validate(); err != nil {
+		return nil, fmt.Errorf("inside secret %s: %w", configuration.KeysSecret.Name, err)
+	}
+
+	keysEnv, err := keys.ToEnv()
+	if err != nil {
+		return nil, err
+	}
+	return append(env, keysEnv), nil
+}
+
 func extractValueFromSecret(
 	ctx context.Context,
 	c client.Client,
@@ -0,0 +1,381 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption implements the client-side encryption of the WAL
+// files archived in an object store.
+//
+// Every file is encrypted with AES-256-GCM using a random data key, which
+// is stored in the header of the file wrapped by one of the keys of the
+// user, named in the header too. The content is split in chunks, each one
+// sealed with a nonce made of its sequence number and of a flag marking
+// the last chunk, so that a truncated file can't be decrypted.
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeysEnvironmentVariable is the environment variable passing the keys
+// to the processes archiving and restoring the WAL files
+const KeysEnvironmentVariable = "CNPG_WAL_ENCRYPTION_KEYS"
+
+const (
+	// keySize is the size of the keys, both the ones of the user and
+	// the data keys, as required by AES-256
+	keySize = 32
+
+	// chunkSize is the size of the plaintext sealed in every chunk
+	chunkSize = 64 * 1024
+)
+
+// magic is the beginning of every encrypted file, including the
+// version of the format
+var magic = []byte("CNPGENC\x01")
+
+// ErrNoKeys is returned when an encrypted file is found
+// but no key has been configured
+var ErrNoKeys = errors.New("the file is encrypted, but no encryption key is configured")
+
+// ErrNotEncrypted is returned when a file which is not encrypted is
+// found while the encryption is configured
+var ErrNotEncrypted = errors.New("the file is not encrypted, but the encryption is configured")
+
+// Keys are the keys used to encrypt and decrypt the WAL files
+type Keys struct {
+	// The name of the key used to encrypt the new files
+	Active string `json:"active"`
+
+	// Every available key, by name
+	Keys map[string][]byte `json:"keys"`
+
+	// Whether the files which are not encrypted are accepted
+	// while decrypting, like the ones archived before
+	// configuring the encryption
+	AllowUnencrypted bool `json:"allowUnencrypted,omitempty"`
+}
+
+// ParseKey parses the value of a key, that can be either made
+// of 32 bytes or be the base64 encoding of them
+func ParseKey(value []byte) ([]byte, error) {
+	if len(value) == keySize {
+		return value, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(value)))
+	if err != nil || len(decoded) != keySize {
+		return nil, fmt.Errorf("a key must be made of %d bytes, or of their base64 encoding", keySize)
+	}
+	return decoded, nil
+}
+
+// Validate checks that the active key exists and that every key has the right size
+func (keys *Keys) Validate() error {
+	if _, ok := keys.Keys[keys.Active]; !ok {
+		return fmt.Errorf("missing active encryption key %q", keys.Active)
+	}
+	for name, key := range keys.Keys {
+		if len(name) == 0 || len(name) > 255 {
+			return fmt.Errorf("invalid encryption key name %q", name)
+		}
+		if len(key) != keySize {
+			return fmt.Errorf("the encryption key %q must be made of %d bytes", name, keySize)
+		}
+	}
+	return nil
+}
+
+// ToEnv gets the environment variable passing the keys
+func (keys *Keys) ToEnv() (string, error) {
+	content, err := json.Marshal(keys)
+	if err != nil {
+		return "", err
+	}
+	return KeysEnvironmentVariable + "=" + string(content), nil
+}
+
+// FromEnv loads the keys from the passed environment,
+// returning nil when the encryption is not configured
+func FromEnv(env []string) (*Keys, error) {
+	var content string
+	found := false
+	for _, item := range env {
+		if value, ok := strings.CutPrefix(item, KeysEnvironmentVariable+"="); ok {
+			content = value
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	var keys Keys
+	if err := json.Unmarshal([]byte(content), &keys); err != nil {
+		return nil, fmt.Errorf("while decoding the encryption keys: %w", err)
+	}
+	if err := keys.Validate(); err != nil {
+		return nil, err
+	}
+	return &keys, nil
+}
+
+// Encrypt encrypts the content of the reader with the active key,
+// writing the result to the writer
+func (keys *Keys) Encrypt(writer io.Writer, reader io.Reader) error {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return err
+	}
+
+	keyCipher, err := newGCM(keys.Keys[keys.Active])
+	if err != nil {
+		return err
+	}
+	keyNonce := make([]byte, keyCipher.NonceSize())
+	if _, err := rand.Read(keyNonce); err != nil {
+		return err
+	}
+
+	header := make([]byte, 0, len(magic)+1+len(keys.Active))
+	header = append(header, magic...)
+	header = append(header, byte(len(keys.Active)))
+	header = append(header, keys.Active...)
+	wrappedKey := keyCipher.Seal(nil, keyNonce, dataKey, header)
+
+	for _, part := range [][]byte{header, keyNonce, wrappedKey} {
+		if _, err := writer.Write(part); err != nil {
+			return err
+		}
+	}
+
+	dataCipher, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+
+	bufferedReader := bufio.NewReaderSize(reader, chunkSize)
+	chunk := make([]byte, chunkSize)
+	for counter := uint64(0); ; counter++ {
+		n, err := io.ReadFull(bufferedReader, chunk)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		last := n < chunkSize
+		if !last {
+			if _, err := bufferedReader.Peek(1); errors.Is(err, io.EOF) {
+				last = true
+			}
+		}
+
+		sealed := dataCipher.Seal(nil, chunkNonce(counter, last), chunk[:n], nil)
+		if _, err := writer.Write(sealed); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// Decrypt decrypts the content of the reader, that has been
+// encrypted by Encrypt, writing the result to the writer
+func (keys *Keys) Decrypt(writer io.Writer, reader io.Reader) error {
+	bufferedReader := bufio.NewReaderSize(reader, chunkSize+aes.BlockSize)
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(bufferedReader, header); err != nil {
+		return fmt.Errorf("while reading the header: %w", err)
+	}
+	if !bytes.Equal(header[:len(magic)], magic) {
+		return errors.New("the file has not been encrypted by the instance manager")
+	}
+	keyName := make([]byte, header[len(magic)])
+	if _, err := io.ReadFull(bufferedReader, keyName); err != nil {
+		return fmt.Errorf("while reading the header: %w", err)
+	}
+	header = append(header, keyName...)
+
+	key, ok := keys.Keys[string(keyName)]
+	if !ok {
+		return fmt.Errorf("the file has been encrypted with the key %q, which is not available", keyName)
+	}
+	keyCipher, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	wrappedKey := make([]byte, keyCipher.NonceSize()+keySize+keyCipher.Overhead())
+	if _, err := io.ReadFull(bufferedReader, wrappedKey); err != nil {
+		return fmt.Errorf("while reading the header: %w", err)
+	}
+	dataKey, err := keyCipher.Open(
+		nil, wrappedKey[:keyCipher.NonceSize()], wrappedKey[keyCipher.NonceSize():], header)
+	if err != nil {
+		return fmt.Errorf("while decrypting the data key with the key %q: %w", keyName, err)
+	}
+
+	dataCipher, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+
+	chunk := make([]byte, chunkSize+dataCipher.Overhead())
+	for counter := uint64(0); ; counter++ {
+		n, err := io.ReadFull(bufferedReader, chunk)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		last := n < len(chunk)
+		if !last {
+			if _, err := bufferedReader.Peek(1); errors.Is(err, io.EOF) {
+				last = true
+			}
+		}
+
+		plaintext, err := dataCipher.Open(nil, chunkNonce(counter, last), chunk[:n], nil)
+		if err != nil {
+			return fmt.Errorf("while decrypting the content, the file may be truncated or corrupted: %w", err)
+		}
+		if _, err := writer.Write(plaintext); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// EncryptFile encrypts a file with the active key, writing the result
+// to the destination file
+func (keys *Keys) EncryptFile(sourceFileName, destinationFileName string) (err error) {
+	source, err := os.Open(sourceFileName) // #nosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = source.Close()
+	}()
+
+	destination, err := os.OpenFile(destinationFileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600) // #nosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := destination.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return keys.Encrypt(destination, source)
+}
+
+// IsEncryptedFile checks whether a file has been encrypted by EncryptFile
+func IsEncryptedFile(fileName string) (bool, error) {
+	file, err := os.Open(fileName) // #nosec
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(header, magic), nil
+}
+
+// DecryptFile decrypts in place a file encrypted by EncryptFile. The files
+// that are not encrypted are left untouched when the encryption is not
+// configured, and refused otherwise, unless the keys explicitly allow
+// them, like it happens for the ones archived before configuring the
+// encryption
+func DecryptFile(keys *Keys, fileName string) error {
+	encrypted, err := IsEncryptedFile(fileName)
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		if keys != nil && !keys.AllowUnencrypted {
+			return ErrNotEncrypted
+		}
+		return nil
+	}
+	if keys == nil {
+		return ErrNoKeys
+	}
+
+	source, err := os.Open(fileName) // #nosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = source.Close()
+	}()
+
+	destination, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".decrypt-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = destination.Close()
+		_ = os.Remove(destination.Name())
+	}()
+
+	if err := keys.Decrypt(destination, source); err != nil {
+		return err
+	}
+	if err := destination.Close(); err != nil {
+		return err
+	}
+	return os.Rename(destination.Name(), fileName)
+}
+
+// newGCM creates the AES-GCM cipher using the passed key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce gets the nonce of a chunk, made of its sequence number
+// and of a flag marking the last chunk of the file. A data key is
+// never used for more than one file, so the nonces are never reused
+func chunkNonce(counter uint64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL files encryption", func() {
+	var keys *Keys
+
+	newKey := func() []byte {
+		key := make([]byte, keySize)
+		_, err := rand.Read(key)
+		Expect(err).ToNot(HaveOccurred())
+		return key
+	}
+
+	BeforeEach(func() {
+		keys = &Keys{
+			Active: "current",
+			Keys: map[string][]byte{
+				"current":  newKey(),
+				"previous": newKey(),
+			},
+		}
+	})
+
+	DescribeTable("encrypts and decrypts the content",
+		func(size int) {
+			plaintext := make([]byte, size)
+			_, err := rand.Read(plaintext)
+			Expect(err).ToNot(HaveOccurred())
+
+			var encrypted bytes.Buffer
+			Expect(keys.Encrypt(&encrypted, bytes.NewReader(plaintext))).To(Succeed())
+			Expect(bytes.Contains(encrypted.Bytes(), plaintext)).To(Equal(size == 0))
+
+			var decrypted bytes.Buffer
+			Expect(keys.Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()))).To(Succeed())
+			Expect(decrypted.Bytes()).To(Equal(plaintext))
+		},
+		Entry("empty", 0),
+		Entry("smaller than a chunk", 1000),
+		Entry("made of exactly two chunks", 2*chunkSize),
+		Entry("larger than many chunks", 5*chunkSize+42),
+	)
+
+	It("decrypts the files encrypted with a key that is not active anymore", func() {
+		var encrypted bytes.Buffer
+		Expect(keys.Encrypt(&encrypted, bytes.NewReader([]byte("wal")))).To(Succeed())
+
+		keys.Active = "previous"
+		var decrypted bytes.Buffer
+		Expect(keys.Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()))).To(Succeed())
+		Expect(decrypted.String()).To(Equal("wal"))
+
+		delete(keys.Keys, "current")
+		Expect(keys.Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()))).To(
+			MatchError(ContainSubstring(`the key "current"`)))
+	})
+
+	It("refuses truncated or corrupted files", func() {
+		var encrypted bytes.Buffer
+		Expect(keys.Encrypt(&encrypted, bytes.NewReader(make([]byte, 3*chunkSize)))).To(Succeed())
+		content := encrypted.Bytes()
+
+		var decrypted bytes.Buffer
+		truncated := content[:len(content)-chunkSize-16]
+		Expect(keys.Decrypt(&decrypted, bytes.NewReader(truncated))).ToNot(Succeed())
+
+		corrupted := bytes.Clone(content)
+		corrupted[len(corrupted)-1] ^= 1
+		Expect(keys.Decrypt(&decrypted, bytes.NewReader(corrupted))).ToNot(Succeed())
+	})
+
+	It("decrypts the files in place, refusing the ones that are not encrypted", func() {
+		dir := GinkgoT().TempDir()
+		source := filepath.Join(dir, "000000010000000000000001")
+		destination := filepath.Join(dir, "encrypted")
+		Expect(os.WriteFile(source, []byte("wal content"), 0o600)).To(Succeed())
+
+		Expect(DecryptFile(keys, source)).To(MatchError(ErrNotEncrypted))
+		Expect(DecryptFile(nil, source)).To(Succeed())
+		keys.AllowUnencrypted = true
+		Expect(DecryptFile(keys, source)).To(Succeed())
+		Expect(os.ReadFile(source)).To(BeEquivalentTo("wal content"))
+
+		Expect(keys.EncryptFile(source, destination)).To(Succeed())
+		Expect(IsEncryptedFile(destination)).To(BeTrue())
+		Expect(DecryptFile(nil, destination)).To(MatchError(ErrNoKeys))
+
+		Expect(DecryptFile(keys, destination)).To(Succeed())
+		Expect(os.ReadFile(destination)).To(BeEquivalentTo("wal content"))
+		Expect(os.ReadDir(dir)).To(HaveLen(2))
+	})
+
+	It("passes the keys through the environment", func() {
+		env, err := keys.ToEnv()
+		Expect(err).ToNot(HaveOccurred())
+
+		loaded, err := FromEnv([]string{"PGDATA=/var/lib/postgresql/data/pgdata", env})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(loaded).To(Equal(keys))
+
+		loaded, err = FromEnv([]string{"PGDATA=/var/lib/postgresql/data/pgdata"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(loaded).To(BeNil())
+
+		keys.Active = "missing"
+		env, err = keys.ToEnv()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = FromEnv([]string{env})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("parses the keys either raw or base64 encoded", func() {
+		key := newKey()
+		Expect(ParseKey(key)).To(Equal(key))
+		Expect(ParseKey([]byte(base64.StdEncoding.EncodeToString(key) + "\n"))).To(Equal(key))
+		_, err := ParseKey([]byte("too short"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -28,6 +28,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/encryption"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/spool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -51,6 +52,10 @@ type WALRestorer struct {
 
 	// The environment that should be used to invoke barman-cloud-wal-archive
 	env []string
+
+	// The keys used to decrypt the WAL files encrypted while being
+	// archived, nil when the client-side encryption is not configured
+	encryptionKeys *encryption.Keys
 }
 
 // Result is the structure filled by the restore process on completion
@@ -84,10 +89,16 @@ func New(ctx context.Context, cluster *apiv1.Cluster, env []string, spoolDirecto
 		return nil, fmt.Errorf("while creating spool directory: %w", err)
 	}
 
+	encryptionKeys, err := encryption.FromEnv(env)
+	if err != nil {
+		return nil, fmt.Errorf("while loading the WAL encryption keys: %w", err)
+	}
+
 	restorer = &WALRestorer{
-		cluster: cluster,
-		spool:   walRecoverSpool,
-		env:     env,
+		cluster:        cluster,
+		spool:          walRecoverSpool,
+		env:            env,
+		encryptionKeys: encryptionKeys,
 	}
 	return restorer, nil
 }
@@ -247,6 +258,9 @@ func (restorer *WALRestorer) Restore(walName, destinationPath string, baseOption
 
 	err := execlog.RunStreaming(barmanCloudWalRestoreCmd, barmanCapabilities.BarmanCloudWalRestore)
 	if err == nil {
+		if err := encryption.DecryptFile(restorer.encryptionKeys, destinationPath); err != nil {
+			return fmt.Errorf("while decrypting %q: %w", walName, err)
+		}
 		return nil
 	}
 
@@ -99,6 +99,22 @@ func (catalog *Catalog) FirstRecoverabilityPoint() *time.Time {
 	return nil
 }
 
+// CompletedBackups gets the backups in the catalog that completed
+// successfully, in order of time
+func (catalog *Catalog) CompletedBackups() []BarmanBackup {
+	// the code below assumes the catalog to be sorted, therefore, we enforce it first
+	sort.Sort(catalog)
+
+	result := make([]BarmanBackup, 0, len(catalog.List))
+	for _, backup := range catalog.List {
+		if backup.isBackupDone() {
+			result = append(result, backup)
+		}
+	}
+
+	return result
+}
+
 // FindBackupInfo finds the backup info that should be used to file
 // a PITR request via target parameters specified within `RecoveryTarget`
 func (catalog *Catalog) FindBackupInfo(recoveryTarget *v1.RecoveryTarget) (*BarmanBackup, error) {
@@ -254,6 +270,9 @@ type BarmanBackup struct {
 
 	// The TimeLine
 	TimeLine int `json:"timeline"`
+
+	// The size of the backup in bytes
+	Size int64 `json:"size"`
 }
 
 type barmanBackupShow struct {
@@ -63,6 +63,22 @@ var _ = Describe("Backup catalog", func() {
 		Expect(catalog.LatestBackupInfo().ID).To(Equal("202101031200"))
 	})
 
+	It("lists the completed backups in order of time", func() {
+		withFailedBackup := NewCatalog(append([]BarmanBackup{
+			{
+				ID:        "202101041200",
+				BeginTime: time.Date(2021, 1, 4, 12, 0, 0, 0, time.UTC),
+				Error:     "failure",
+				TimeLine:  1,
+			},
+		}, catalog.List...))
+
+		backups := withFailedBackup.CompletedBackups()
+		Expect(backups).To(HaveLen(3))
+		Expect(backups[0].ID).To(Equal("202101011200"))
+		Expect(backups[2].ID).To(Equal("202101031200"))
+	})
+
 	It("can find the closest backup info when there is one", func() {
 		recoveryTarget := &v1.RecoveryTarget{TargetTime: time.Now().Format("2006-01-02 15:04:04")}
 		closestBackupInfo, err := catalog.FindBackupInfo(recoveryTarget)
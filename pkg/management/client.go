@@ -38,6 +38,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
 )
@@ -109,7 +110,8 @@ func newClientGoClient() (kubernetes.Interface, error) {
 	return kubernetes.NewForConfig(config)
 }
 
-// NewEventRecorder creates a new event recorder
+// NewEventRecorder creates a new event recorder, deduplicating and
+// rate limiting the events
 func NewEventRecorder() (record.EventRecorder, error) {
 	kubeClient, err := newClientGoClient()
 	if err != nil {
@@ -126,7 +128,7 @@ func NewEventRecorder() (record.EventRecorder, error) {
 		v1.EventSource{Component: "instance-manager"},
 	)
 
-	return recorder, nil
+	return events.NewRecorder(recorder), nil
 }
 
 // WaitKubernetesAPIServer will wait for the kubernetes API server to by ready.
@@ -59,7 +59,8 @@ func GetServerConnectionString(
 
 // ConfigureConnectionToServer creates a connection string to the external
 // server, using the configuration inside the cluster and dumping the secret when
-// needed in a custom passfile.
+// needed in a custom passfile. The secrets are read from the namespace of the
+// cluster, unless the external server takes them from another namespace.
 // Returns a connection string or any error encountered
 func ConfigureConnectionToServer(
 	ctx context.Context,
@@ -68,6 +69,7 @@ func ConfigureConnectionToServer(
 	server *apiv1.ExternalCluster,
 ) (string, error) {
 	connectionParameters := maps.Clone(server.ConnectionParameters)
+	namespace = server.GetSecretsNamespace(namespace)
 
 	if server.SSLCert != nil {
 		name, err := dumpSecretKeyRefToFile(ctx, client, namespace, server.Name, server.SSLCert)
@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package localauth protects the local webserver of the instance manager
+// with a bearer token that only the processes running in the PostgreSQL
+// container can read
+package localauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// TokenFile is the file where the instance manager writes the token
+// required by the local webserver. It's only readable by the user
+// running PostgreSQL
+const TokenFile = postgres.ScratchDataDirectory + "/local-webserver.token"
+
+// DiagnosticsTokenFile is the file where the instance manager writes the
+// token required by the diagnostics webserver. The kubectl plugin reads it
+// from the PostgreSQL container before opening the port-forward
+const DiagnosticsTokenFile = postgres.ScratchDataDirectory + "/diagnostics-webserver.token"
+
+// tokenLength is the number of random bytes of a token
+const tokenLength = 32
+
+// GenerateToken creates a new random token and writes it in the token file,
+// replacing the one of a previous run of the instance manager
+func GenerateToken() (string, error) {
+	return generateToken(TokenFile)
+}
+
+// GenerateDiagnosticsToken creates a new random token for the diagnostics
+// webserver and writes it in the diagnostics token file
+func GenerateDiagnosticsToken() (string, error) {
+	return generateToken(DiagnosticsTokenFile)
+}
+
+// Authorize adds the token to a request directed to the local webserver.
+// When the token file doesn't exist, the request is left untouched, as
+// it's directed to an instance manager not requiring it
+func Authorize(req *http.Request) error {
+	return authorize(req, TokenFile)
+}
+
+// Middleware rejects the requests not carrying the passed token
+func Middleware(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestToken, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(requestToken), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func generateToken(fileName string) (string, error) {
+	data := make([]byte, tokenLength)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("while generating the local webserver token: %w", err)
+	}
+	token := hex.EncodeToString(data)
+
+	if _, err := fileutils.WriteFileAtomic(fileName, []byte(token), 0o600); err != nil {
+		return "", fmt.Errorf("while writing the local webserver token: %w", err)
+	}
+
+	return token, nil
+}
+
+func authorize(req *http.Request, fileName string) error {
+	token, err := os.ReadFile(fileName) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while reading the local webserver token: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+string(token))
+	return nil
+}
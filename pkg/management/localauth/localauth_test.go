@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("local webserver token", func() {
+	var tokenFile string
+
+	BeforeEach(func() {
+		tokenFile = filepath.Join(GinkgoT().TempDir(), "local-webserver.token")
+	})
+
+	It("writes a new token readable only by its owner", func() {
+		token, err := generateToken(tokenFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(HaveLen(2 * tokenLength))
+
+		info, err := os.Stat(tokenFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		newToken, err := generateToken(tokenFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newToken).ToNot(Equal(token))
+	})
+
+	It("adds the token to the requests", func() {
+		token, err := generateToken(tokenFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/pg/backup", nil)
+		Expect(authorize(req, tokenFile)).To(Succeed())
+		Expect(req.Header.Get("Authorization")).To(Equal("Bearer " + token))
+	})
+
+	It("leaves the requests untouched when there's no token", func() {
+		req := httptest.NewRequest(http.MethodGet, "/v1/pg/backup", nil)
+		Expect(authorize(req, tokenFile)).To(Succeed())
+		Expect(req.Header.Get("Authorization")).To(BeEmpty())
+	})
+
+	Context("middleware", func() {
+		handler := Middleware("secret", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		DescribeTable("checks the bearer token",
+			func(header string, expectedCode int) {
+				req := httptest.NewRequest(http.MethodGet, "/v1/pg/backup", nil)
+				if header != "" {
+					req.Header.Set("Authorization", header)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				Expect(rec.Code).To(Equal(expectedCode))
+			},
+			Entry("with the right token", "Bearer secret", http.StatusOK),
+			Entry("with a wrong token", "Bearer wrong", http.StatusUnauthorized),
+			Entry("with another scheme", "Basic secret", http.StatusUnauthorized),
+			Entry("without a token", "", http.StatusUnauthorized),
+		)
+	})
+})
@@ -100,6 +100,9 @@ func (l *Flags) ConfigureLogging() {
 	controllerruntime.SetLogger(logger)
 	klog.SetLogger(logger)
 	SetLogger(logger)
+
+	auditOptions := l.zapOptions
+	SetAuditLogger(zap.New(zap.UseFlagOptions(&auditOptions), customLevel, auditDestination, remapKeys))
 }
 
 func getLogLevel(l string) zapcore.Level {
@@ -156,6 +159,12 @@ func customLevel(in *zap.Options) {
 	})
 }
 
+// auditDestination writes the audit records on the standard output,
+// keeping them apart from the rest of the log stream
+func auditDestination(in *zap.Options) {
+	in.DestWriter = os.Stdout
+}
+
 func customDestination(in *zap.Options) {
 	if logDestination == "" {
 		return
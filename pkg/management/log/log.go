@@ -72,11 +72,21 @@ type logger struct {
 // Log is the logger that will be used in this package
 var log = &logger{Logger: ctrl.Log}
 
+// auditLog is the logger used to emit the audit records on a dedicated
+// output. Until logging is configured it is the same as the default one
+var auditLog = &logger{Logger: ctrl.Log}
+
 // GetLogger returns the default logger
 func GetLogger() Logger {
 	return log
 }
 
+// GetAuditLogger returns the logger emitting the audit records on
+// a dedicated output
+func GetAuditLogger() Logger {
+	return auditLog
+}
+
 // Logger is a reduced version of logr.Logger
 type Logger interface {
 	Enabled() bool
@@ -96,6 +106,12 @@ func SetLogger(logr logr.Logger) {
 	log.Logger = logr
 }
 
+// SetAuditLogger will set the backing logr implementation for the
+// audit records emitted on a dedicated output
+func SetAuditLogger(logr logr.Logger) {
+	auditLog.Logger = logr
+}
+
 func (l *logger) enrich(forceCaller bool) logr.Logger {
 	cl := l.GetLogger()
 
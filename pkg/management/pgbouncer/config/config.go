@@ -23,8 +23,6 @@ import (
 	"strings"
 	"text/template"
 
-	corev1 "k8s.io/api/core/v1"
-
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -71,6 +69,7 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
+{{ .Databases -}}
 * = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
 
 [pgbouncer]
@@ -79,6 +78,10 @@ auth_user = {{ .AuthQueryUser }}
 auth_query = {{ .AuthQuery }}
 
 {{ .Parameters -}}
+{{ if .Users }}
+[users]
+{{ .Users -}}
+{{ end -}}
 `
 	pgbouncerHBAFileTemplateString = `
 local pgbouncer pgbouncer peer
@@ -138,39 +141,18 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 	var pgbouncerUserList bytes.Buffer
 	var pgbouncerHBA bytes.Buffer
 
-	var authQueryUser, authQueryPassword string
-	var isCertAuth bool
+	var authQueryPassword string
 
-	// if no user is provided we have to check the secret for a username, and we must be using basic auth
-	// if a user is provided it will overwrite the user in the secret, or we could be using cert auth
-	authQuerySecretType, err := detectSecretType(secrets.AuthQuery)
+	authQueryUser, isCertAuth, err := GetAuthQueryUser(secrets.AuthQuery)
 	if err != nil {
-		return nil, fmt.Errorf("while detecting auth user secret type: %w", err)
+		return nil, err
 	}
 
-	switch authQuerySecretType {
-	case corev1.SecretTypeBasicAuth:
-		authQueryUser = string(secrets.AuthQuery.Data["username"])
-		authQueryPassword = strings.ReplaceAll(string(secrets.AuthQuery.Data["password"]), "\"", "\"\"")
-
-	case corev1.SecretTypeTLS:
-		keyPair, err := certs.ParseServerSecret(secrets.AuthQuery)
-		if err != nil {
-			return nil, fmt.Errorf("while parsing TLS secret for auth user: %w", err)
-		}
-
-		certificate, err := keyPair.ParseCertificate()
-		if err != nil {
-			return nil, fmt.Errorf("while parsing certificate for auth user: %w", err)
-		}
-
-		authQueryUser = certificate.Subject.CommonName
-		isCertAuth = true
+	if isCertAuth {
 		files[authUserCrtPath] = secrets.AuthQuery.Data[certs.TLSCertKey]
 		files[authUserKeyPath] = secrets.AuthQuery.Data[certs.TLSPrivateKeyKey]
-
-	default:
-		return nil, fmt.Errorf("unsupported secret type for auth query: %s", secrets.AuthQuery.Type)
+	} else {
+		authQueryPassword = strings.ReplaceAll(string(secrets.AuthQuery.Data["password"]), "\"", "\"\"")
 	}
 
 	parameters := buildPgBouncerParameters(pooler.Spec.PgBouncer.Parameters)
@@ -188,6 +170,8 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		AuthQueryUser     string
 		AuthQueryPassword string
 		Parameters        string
+		Databases         string
+		Users             string
 		PgHba             []string
 	}{
 		Pooler:            pooler,
@@ -202,7 +186,11 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		// Also, we want the list of parameters inside the PgBouncer configuration
 		// to be stable.
 		Parameters: stringifyPgBouncerParameters(parameters),
-		PgHba:      pooler.Spec.PgBouncer.PgHBA,
+		Databases: stringifyPgBouncerDatabases(
+			fmt.Sprintf("%s-%s", pooler.Spec.Cluster.Name, pooler.Spec.Type),
+			pooler.Spec.PgBouncer.Databases),
+		Users: stringifyPgBouncerUsers(pooler.Spec.PgBouncer.Users),
+		PgHba: pooler.Spec.PgBouncer.PgHBA,
 	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
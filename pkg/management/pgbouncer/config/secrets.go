@@ -22,6 +22,8 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 )
 
 // ErrorUnknownSecretType is raised when the detection of
@@ -94,3 +96,35 @@ func detectSecretType(secret *corev1.Secret) (corev1.SecretType, error) {
 	// this secret is.
 	return "", NewErrorUnknownSecretType(secret)
 }
+
+// GetAuthQueryUser gets the name of the user running the auth query from
+// its secret, which is the username of a basic-auth secret or the common
+// name of the certificate of a TLS secret. The returned flag is true when
+// the user authenticates with the certificate
+func GetAuthQueryUser(secret *corev1.Secret) (string, bool, error) {
+	secretType, err := detectSecretType(secret)
+	if err != nil {
+		return "", false, fmt.Errorf("while detecting auth user secret type: %w", err)
+	}
+
+	switch secretType {
+	case corev1.SecretTypeBasicAuth:
+		return string(secret.Data[corev1.BasicAuthUsernameKey]), false, nil
+
+	case corev1.SecretTypeTLS:
+		keyPair, err := certs.ParseServerSecret(secret)
+		if err != nil {
+			return "", false, fmt.Errorf("while parsing TLS secret for auth user: %w", err)
+		}
+
+		certificate, err := keyPair.ParseCertificate()
+		if err != nil {
+			return "", false, fmt.Errorf("while parsing certificate for auth user: %w", err)
+		}
+
+		return certificate.Subject.CommonName, true, nil
+
+	default:
+		return "", false, fmt.Errorf("unsupported secret type for auth query: %s", secret.Type)
+	}
+}
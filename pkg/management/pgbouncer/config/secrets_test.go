@@ -19,6 +19,8 @@ package config
 import (
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -61,4 +63,23 @@ var _ = Describe("Secret type detection", func() {
 		Expect(detectedType).To(BeEmpty())
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("gets the auth query user from basic-auth secrets", func() {
+		user, isCertAuth, err := GetAuthQueryUser(&corev1.Secret{Type: corev1.SecretTypeOpaque, Data: basicAuthData})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(user).To(Equal("test-username"))
+		Expect(isCertAuth).To(BeFalse())
+	})
+
+	It("gets the auth query user from the certificate of TLS secrets", func() {
+		rootCA, err := certs.CreateRootCA("test", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		pair, err := rootCA.CreateAndSignPair("pgbouncer_auth", certs.CertTypeClient, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		user, isCertAuth, err := GetAuthQueryUser(pair.GenerateCertificateSecret("namespace", "name"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(user).To(Equal("pgbouncer_auth"))
+		Expect(isCertAuth).To(BeTrue())
+	})
 })
@@ -21,6 +21,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // stringifyPgBouncerParameters will take map of PgBouncer parameters and emit
@@ -39,6 +41,52 @@ func stringifyPgBouncerParameters(parameters map[string]string) (paramsString st
 	return paramsString
 }
 
+// stringifyPgBouncerDatabases emits the entries of the `[databases]`
+// section declared in the Pooler, pointing them to the passed host.
+// The wildcard entry is not included.
+func stringifyPgBouncerDatabases(host string, databases []apiv1.PgBouncerDatabase) (databasesString string) {
+	for _, database := range databases {
+		connectionString := fmt.Sprintf("host=%s dbname=%s", host, database.GetDBName())
+		if database.PoolMode != "" {
+			connectionString += fmt.Sprintf(" pool_mode=%s", database.PoolMode)
+		}
+		if database.PoolSize != nil {
+			connectionString += fmt.Sprintf(" pool_size=%d", *database.PoolSize)
+		}
+		if database.MinPoolSize != nil {
+			connectionString += fmt.Sprintf(" min_pool_size=%d", *database.MinPoolSize)
+		}
+		if database.ReservePoolSize != nil {
+			connectionString += fmt.Sprintf(" reserve_pool=%d", *database.ReservePoolSize)
+		}
+		if database.MaxDBConnections != nil {
+			connectionString += fmt.Sprintf(" max_db_connections=%d", *database.MaxDBConnections)
+		}
+		databasesString += fmt.Sprintf("%s = %s\n", database.Name, connectionString)
+	}
+	return databasesString
+}
+
+// stringifyPgBouncerUsers emits the entries of the `[users]` section
+// declared in the Pooler. Users without any setting are skipped, as
+// PgBouncer doesn't accept empty entries
+func stringifyPgBouncerUsers(users []apiv1.PgBouncerUser) (usersString string) {
+	for _, user := range users {
+		var settings []string
+		if user.PoolMode != "" {
+			settings = append(settings, fmt.Sprintf("pool_mode=%s", user.PoolMode))
+		}
+		if user.MaxUserConnections != nil {
+			settings = append(settings, fmt.Sprintf("max_user_connections=%d", *user.MaxUserConnections))
+		}
+		if len(settings) == 0 {
+			continue
+		}
+		usersString += fmt.Sprintf("%s = %s\n", user.Name, strings.Join(settings, " "))
+	}
+	return usersString
+}
+
 // buildPgBouncerParameters will build a PgBouncer configuration applying any
 // default parameters and forcing any required parameter needed for the
 // controller to work correctly
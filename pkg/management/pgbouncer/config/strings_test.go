@@ -17,6 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -45,3 +49,40 @@ var _ = Describe("PgBouncer configuration", func() {
 		Expect(params).NotTo(MatchRegexp("^pid_file.*"))
 	})
 })
+
+var _ = Describe("PgBouncer databases and users", func() {
+	It("renders the declared databases", func() {
+		databases := []apiv1.PgBouncerDatabase{
+			{Name: "app"},
+			{
+				Name:             "reporting",
+				DBName:           "app",
+				PoolMode:         apiv1.PgBouncerPoolModeTransaction,
+				PoolSize:         ptr.To[int32](20),
+				MinPoolSize:      ptr.To[int32](5),
+				ReservePoolSize:  ptr.To[int32](2),
+				MaxDBConnections: ptr.To[int32](50),
+			},
+		}
+		Expect(stringifyPgBouncerDatabases("cluster-example-rw", databases)).To(Equal(
+			"app = host=cluster-example-rw dbname=app\n" +
+				"reporting = host=cluster-example-rw dbname=app pool_mode=transaction " +
+				"pool_size=20 min_pool_size=5 reserve_pool=2 max_db_connections=50\n"))
+	})
+
+	It("renders the users with specific settings", func() {
+		users := []apiv1.PgBouncerUser{
+			{Name: "batch", PoolMode: apiv1.PgBouncerPoolModeSession, MaxUserConnections: ptr.To[int32](10)},
+			{Name: "idle"},
+			{Name: "web", PoolMode: apiv1.PgBouncerPoolModeTransaction},
+		}
+		Expect(stringifyPgBouncerUsers(users)).To(Equal(
+			"batch = pool_mode=session max_user_connections=10\n" +
+				"web = pool_mode=transaction\n"))
+	})
+
+	It("renders nothing when no database or user is declared", func() {
+		Expect(stringifyPgBouncerDatabases("cluster-example-rw", nil)).To(BeEmpty())
+		Expect(stringifyPgBouncerUsers(nil)).To(BeEmpty())
+	})
+})
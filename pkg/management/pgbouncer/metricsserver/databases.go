@@ -0,0 +1,187 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ShowDatabasesMetrics contains all the SHOW DATABASES Metrics,
+// indexed by the name of the corresponding column
+type ShowDatabasesMetrics map[string]*prometheus.GaugeVec
+
+// Describe produces the description for all the contained Metrics
+func (s ShowDatabasesMetrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range s {
+		m.Describe(ch)
+	}
+}
+
+// Reset resets all the contained Metrics
+func (s ShowDatabasesMetrics) Reset() {
+	for _, m := range s {
+		m.Reset()
+	}
+}
+
+// NewShowDatabasesMetrics builds the default ShowDatabasesMetrics
+func NewShowDatabasesMetrics(subsystem string) ShowDatabasesMetrics {
+	subsystem += "_databases"
+	return ShowDatabasesMetrics{
+		"pool_size": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "pool_size",
+			Help:      "Maximum number of server connections of each pool of the database.",
+		}, []string{"database"}),
+		"min_pool_size": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "min_pool_size",
+			Help:      "Minimum number of server connections of each pool of the database.",
+		}, []string{"database"}),
+		"reserve_pool": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "reserve_pool",
+			Help:      "Maximum number of additional connections for each pool of the database.",
+		}, []string{"database"}),
+		"max_connections": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "max_connections",
+			Help:      "Maximum number of allowed server connections to the database, 0 if unlimited.",
+		}, []string{"database"}),
+		"current_connections": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "current_connections",
+			Help:      "Current number of server connections to the database.",
+		}, []string{"database"}),
+		"paused": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "paused",
+			Help:      "1 if the database is paused, 0 otherwise.",
+		}, []string{"database"}),
+		"disabled": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disabled",
+			Help:      "1 if the database is disabled, 0 otherwise.",
+		}, []string{"database"}),
+		"pool_mode": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "pool_mode",
+			Help: "The pooling mode of the database. 1 for session, 2 for transaction, 3 for statement, " +
+				"-1 if unknown or inherited from the pooler",
+		}, []string{"database"}),
+	}
+}
+
+func (e *Exporter) collectShowDatabases(ch chan<- prometheus.Metric, db *sql.DB) {
+	e.Metrics.ShowDatabases.Reset()
+	// First, let's check the connection. No need to proceed if this fails.
+	rows, err := db.Query("SHOW DATABASES;")
+	if err != nil {
+		log.Error(err, "Error while executing SHOW DATABASES")
+		e.Metrics.PgbouncerUp.Set(0)
+		e.Metrics.Error.Set(1)
+		return
+	}
+
+	e.Metrics.PgbouncerUp.Set(1)
+	e.Metrics.Error.Set(0)
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err, "while closing rows for SHOW DATABASES")
+		}
+	}()
+
+	// The columns of SHOW DATABASES change between the PgBouncer
+	// versions, so we read them by name
+	cols, err := rows.Columns()
+	if err != nil {
+		log.Error(err, "Error while getting number of columns")
+		e.Metrics.PgbouncerUp.Set(0)
+		e.Metrics.Error.Set(1)
+		return
+	}
+
+	values := make([]sql.NullString, len(cols))
+	pointers := make([]interface{}, len(cols))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			log.Error(err, "Error while executing SHOW DATABASES")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+			continue
+		}
+
+		row := make(map[string]sql.NullString, len(cols))
+		for i, col := range cols {
+			row[col] = values[i]
+		}
+		database := row["name"].String
+
+		for col, metric := range e.Metrics.ShowDatabases {
+			value, ok := row[col]
+			if !ok {
+				continue
+			}
+
+			if col == "pool_mode" {
+				metric.WithLabelValues(database).Set(float64(poolModeToInt(value.String)))
+				continue
+			}
+
+			// NULL values, like the ones of the admin console,
+			// are not reported
+			if !value.Valid {
+				continue
+			}
+			number, err := strconv.ParseFloat(value.String, 64)
+			if err != nil {
+				log.Error(err, "Error while parsing SHOW DATABASES", "column", col, "value", value.String)
+				e.Metrics.Error.Set(1)
+				e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+				continue
+			}
+			metric.WithLabelValues(database).Set(number)
+		}
+	}
+
+	for _, metric := range e.Metrics.ShowDatabases {
+		metric.Collect(ch)
+	}
+
+	if err = rows.Err(); err != nil {
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+	}
+}
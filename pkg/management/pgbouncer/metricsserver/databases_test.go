@@ -0,0 +1,156 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		registry *prometheus.Registry
+		db       *sql.DB
+		mock     sqlmock.Sqlmock
+		exp      *Exporter
+		ch       chan prometheus.Metric
+		columns  = []string{
+			"name",
+			"host",
+			"port",
+			"database",
+			"force_user",
+			"pool_size",
+			"min_pool_size",
+			"reserve_pool",
+			"pool_mode",
+			"max_connections",
+			"current_connections",
+			"paused",
+			"disabled",
+		}
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		exp = &Exporter{
+			Metrics: newMetrics(),
+			pool:    fakePooler{db: db},
+		}
+
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exp.Metrics.PgbouncerUp)
+		registry.MustRegister(exp.Metrics.Error)
+		for _, metric := range exp.Metrics.ShowDatabases {
+			registry.MustRegister(metric)
+		}
+
+		ch = make(chan prometheus.Metric, 1000)
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("collectShowDatabases", func() {
+		It("should react properly if SQL shows no databases", func() {
+			mock.ExpectQuery("SHOW DATABASES;").WillReturnError(sql.ErrNoRows)
+			exp.collectShowDatabases(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			pgBouncerUpValue := getMetric(metrics, pgBouncerUpKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(pgBouncerUpValue).Should(BeEquivalentTo(0))
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(1))
+		})
+
+		It("should export the pool limits of every database", func() {
+			mock.ExpectQuery("SHOW DATABASES;").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("app", "cluster-rw", 5432, "app", nil, 20, 5, 2, "transaction", 100, 12, 0, 0).
+					AddRow("pgbouncer", nil, 6432, "pgbouncer", "pgbouncer", 2, 0, 0, "statement", 0, 0, 0, 0))
+
+			exp.collectShowDatabases(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(0))
+
+			poolSize := getMetric(metrics, "cnpg_pgbouncer_databases_pool_size").GetMetric()
+			Expect(poolSize).To(HaveLen(2))
+			Expect(poolSize[0].GetLabel()[0].GetValue()).To(Equal("app"))
+			Expect(poolSize[0].GetGauge().GetValue()).To(BeEquivalentTo(20))
+
+			current := getMetric(metrics, "cnpg_pgbouncer_databases_current_connections").GetMetric()
+			Expect(current[0].GetGauge().GetValue()).To(BeEquivalentTo(12))
+
+			poolMode := getMetric(metrics, "cnpg_pgbouncer_databases_pool_mode").GetMetric()
+			Expect(poolMode[0].GetGauge().GetValue()).To(BeEquivalentTo(2))
+			Expect(poolMode[1].GetGauge().GetValue()).To(BeEquivalentTo(3))
+		})
+
+		It("should skip the columns that are not part of the output", func() {
+			mock.ExpectQuery("SHOW DATABASES;").
+				WillReturnRows(sqlmock.NewRows([]string{"name", "pool_size"}).
+					AddRow("app", 10))
+
+			exp.collectShowDatabases(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(0))
+
+			poolSize := getMetric(metrics, "cnpg_pgbouncer_databases_pool_size").GetMetric()
+			Expect(poolSize[0].GetGauge().GetValue()).To(BeEquivalentTo(10))
+			Expect(getMetric(metrics, "cnpg_pgbouncer_databases_max_connections")).To(BeNil())
+		})
+
+		It("should count the values that cannot be parsed as collection errors", func() {
+			mock.ExpectQuery("SHOW DATABASES;").
+				WillReturnRows(sqlmock.NewRows([]string{"name", "pool_size"}).
+					AddRow("app", "error"))
+
+			exp.collectShowDatabases(ch, db)
+
+			registry.MustRegister(exp.Metrics.PgCollectionErrors)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(1))
+
+			errorsMetric := getMetric(metrics, collectionErrorsTotalKey).GetMetric()[0]
+			Expect(errorsMetric.GetCounter().GetValue()).To(BeEquivalentTo(1))
+		})
+	})
+})
@@ -47,6 +47,7 @@ type metrics struct {
 	Error              prometheus.Gauge
 	CollectionDuration *prometheus.GaugeVec
 	PgbouncerUp        prometheus.Gauge
+	ShowDatabases      ShowDatabasesMetrics
 	ShowLists          ShowListsMetrics
 	ShowPools          *ShowPoolsMetrics
 	ShowStats          *ShowStatsMetrics
@@ -93,9 +94,10 @@ func newMetrics() *metrics {
 			Name:      "collection_duration_seconds",
 			Help:      "Collection time duration in seconds",
 		}, []string{"collector"}),
-		ShowLists: NewShowListsMetrics(subsystem),
-		ShowPools: NewShowPoolsMetrics(subsystem),
-		ShowStats: NewShowStatsMetrics(subsystem),
+		ShowDatabases: NewShowDatabasesMetrics(subsystem),
+		ShowLists:     NewShowListsMetrics(subsystem),
+		ShowPools:     NewShowPoolsMetrics(subsystem),
+		ShowStats:     NewShowStatsMetrics(subsystem),
 	}
 }
 
@@ -105,6 +107,7 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- e.Metrics.Error.Desc()
 	e.Metrics.PgCollectionErrors.Describe(ch)
 	e.Metrics.CollectionDuration.Describe(ch)
+	e.Metrics.ShowDatabases.Describe(ch)
 	e.Metrics.ShowLists.Describe(ch)
 	e.Metrics.ShowPools.Describe(ch)
 	e.Metrics.ShowStats.Describe(ch)
@@ -134,6 +137,7 @@ func (e *Exporter) collectPgBouncerMetrics(ch chan<- prometheus.Metric) {
 		return
 	}
 
+	e.collectShowDatabases(ch, db)
 	e.collectShowLists(ch, db)
 	e.collectShowPools(ch, db)
 	e.collectShowStats(ch, db)
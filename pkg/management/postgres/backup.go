@@ -24,6 +24,7 @@ import (
 	"os/exec"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -32,10 +33,12 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
@@ -67,6 +70,10 @@ type BackupCommand struct {
 	Log          log.Logger
 	Instance     *Instance
 	Capabilities *barmanCapabilities.Capabilities
+
+	// envLock protects Env, which is refreshed while the
+	// backup is running when the credentials are rotated
+	envLock sync.RWMutex
 }
 
 // NewBarmanBackupCommand initializes a BackupCommand object, taking a physical
@@ -96,6 +103,12 @@ func NewBarmanBackupCommand(
 	}, nil
 }
 
+// getBarmanConfiguration gets the configuration of the object store
+// where the backup is taken
+func (b *BackupCommand) getBarmanConfiguration() *apiv1.BarmanObjectStoreConfiguration {
+	return b.Cluster.Spec.Backup.GetBarmanObjectStore(b.Backup.Spec.ObjectStoreName)
+}
+
 // getDataConfiguration gets the configuration in the `Data` object of the Barman configuration
 func getDataConfiguration(
 	options []string,
@@ -106,8 +119,8 @@ func getDataConfiguration(
 		return options, nil
 	}
 
-	if configuration.Data.Compression == apiv1.CompressionTypeSnappy && !capabilities.HasSnappy {
-		return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
+	if err := capabilities.CheckCompression(configuration.Data.Compression); err != nil {
+		return nil, err
 	}
 
 	if len(configuration.Data.Compression) != 0 {
@@ -214,17 +227,19 @@ func (b *BackupCommand) Start(ctx context.Context) error {
 		}
 	}
 
-	b.Env, err = barmanCredentials.EnvSetBackupCloudCredentials(
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
 		ctx,
 		b.Client,
 		b.Cluster.Namespace,
-		b.Cluster.Spec.Backup.BarmanObjectStore,
-		b.Env)
+		b.getBarmanConfiguration(),
+		b.getEnv())
 	if err != nil {
 		return fmt.Errorf("cannot recover backup credentials: %w", err)
 	}
+	b.setEnv(env)
 
 	// Run the actual backup process
+	b.Instance.trackBackupCommand(b)
 	go b.run(ctx)
 
 	return nil
@@ -258,12 +273,14 @@ func (b *BackupCommand) retryWithRefreshedCluster(
 // This method will take long time and is supposed to run inside a dedicated
 // goroutine.
 func (b *BackupCommand) run(ctx context.Context) {
+	defer b.Instance.untrackBackupCommand(b)
+
 	if err := b.takeBackup(ctx); err != nil {
 		backupStatus := b.Backup.GetStatus()
 
 		// record the failure
 		b.Log.Error(err, "Backup failed")
-		b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
+		b.Recorder.Event(b.Backup, "Warning", events.BackupFailed, "Backup failed")
 
 		// update backup status as failed
 		backupStatus.SetAsFailed(err)
@@ -292,7 +309,7 @@ func (b *BackupCommand) run(ctx context.Context) {
 }
 
 func (b *BackupCommand) takeBackup(ctx context.Context) error {
-	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
+	barmanConfiguration := b.getBarmanConfiguration()
 	backupStatus := b.Backup.GetStatus()
 
 	options, backupErr := b.getBarmanCloudBackupOptions(barmanConfiguration, backupStatus.ServerName)
@@ -303,7 +320,7 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 
 	// record the backup beginning
 	b.Log.Info("Starting barman-cloud-backup", "options", options)
-	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
+	b.Recorder.Event(b.Backup, "Normal", events.BackupStarted, "Backup started")
 
 	// Update backup status in cluster conditions on startup
 	if err := b.retryWithRefreshedCluster(ctx, func() error {
@@ -320,10 +337,20 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 		return err
 	}
 
+	// The post-backup hooks are run as soon as barman-cloud-backup
+	// terminates, even when it fails, so that the applications are
+	// not left quiesced
+	if err := b.runPreBackupHooks(ctx); err != nil {
+		b.runPostBackupHooks(ctx)
+		return err
+	}
+
 	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
-	cmd.Env = b.Env
+	cmd.Env = b.getEnv()
 	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
-	if err := execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup); err != nil {
+	err := b.runBarmanCloudBackup(ctx, cmd)
+	b.runPostBackupHooks(ctx)
+	if err != nil {
 		const badArgumentsErrorCode = "3"
 		if err.Error() == badArgumentsErrorCode {
 			descriptiveError := errors.New("invalid arguments for barman-cloud-backup. " +
@@ -336,7 +363,7 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	}
 
 	b.Log.Info("Backup completed")
-	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+	b.Recorder.Event(b.Backup, "Normal", events.BackupCompleted, "Backup completed")
 
 	// Set the status to completed
 	b.Backup.Status.SetAsCompleted()
@@ -363,6 +390,44 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	return nil
 }
 
+// runBarmanCloudBackup executes barman-cloud-backup, reporting the
+// progress of the backup while it is running
+func (b *BackupCommand) runBarmanCloudBackup(ctx context.Context, cmd *exec.Cmd) error {
+	streamingCmd, err := execlog.RunStreamingNoWait(cmd, barmanCapabilities.BarmanCloudBackup)
+	if err != nil {
+		return err
+	}
+
+	tracker, err := b.Instance.newBackupProgressTracker(ctx, cmd.Process.Pid)
+	if err != nil {
+		// The progress is just informative, we go on with the backup anyway
+		b.Log.Warning("Cannot track the backup progress", "err", err.Error())
+		return streamingCmd.Wait()
+	}
+
+	defer removeBackupProgress(b.Backup.Name)
+	setBackupProgress(b.Backup.Name, apiv1.BackupProgress{})
+
+	progressCtx, cancelProgress := context.WithCancel(ctx)
+	progressResult := make(chan apiv1.BackupProgress, 1)
+	origBackup := b.Backup.DeepCopy()
+	go func() {
+		progressResult <- b.reportBackupProgress(progressCtx, tracker, origBackup)
+	}()
+
+	err = streamingCmd.Wait()
+	cancelProgress()
+	lastProgress := <-progressResult
+
+	if err == nil {
+		b.Backup.Status.Progress = ptr.To(tracker.completed(lastProgress))
+	} else if lastProgress.UpdatedAt != nil {
+		b.Backup.Status.Progress = &lastProgress
+	}
+
+	return err
+}
+
 func (b *BackupCommand) getExecutedBackupInfo(
 	ctx context.Context,
 ) (*catalog.BarmanBackup, error) {
@@ -371,8 +436,8 @@ func (b *BackupCommand) getExecutedBackupInfo(
 			ctx,
 			b.Backup.Status.BackupName,
 			b.Backup.Status.ServerName,
-			b.Cluster.Spec.Backup.BarmanObjectStore,
-			b.Env,
+			b.getBarmanConfiguration(),
+			b.getEnv(),
 		)
 	}
 	// we don't know the id or the name of the executed backup so it fetches the last executed barman backup.
@@ -380,44 +445,62 @@ func (b *BackupCommand) getExecutedBackupInfo(
 	return barman.GetLatestBackup(
 		ctx,
 		b.Backup.Status.ServerName,
-		b.Cluster.Spec.Backup.BarmanObjectStore,
-		b.Env,
+		b.getBarmanConfiguration(),
+		b.getEnv(),
 	)
 }
 
 func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 	// Delete backups per policy
-	if b.Cluster.Spec.Backup.RetentionPolicy != "" {
-		b.Log.Info("Applying backup retention policy",
-			"retentionPolicy", b.Cluster.Spec.Backup.RetentionPolicy)
-		if err := barman.DeleteBackupsByPolicy(ctx, b.Cluster.Spec.Backup, b.Backup.Status.ServerName, b.Env); err != nil {
-			// Proper logging already happened inside DeleteBackupsByPolicy
-			b.Recorder.Event(b.Cluster, "Warning", "RetentionPolicyFailed", "Retention policy failed")
-			// We do not want to return here, we must go on to set the fist recoverability point
-		}
-	}
+	retentionPolicyStatus := b.enforceRetentionPolicy(ctx)
 
-	// Extracting the latest backup using barman-cloud-backup-list
+	// Extracting the latest backup using barman-cloud-backup-list. The
+	// same catalog tells which backups the retention policy removed
 	backupList, err := barman.GetBackupList(
 		ctx,
-		b.Cluster.Spec.Backup.BarmanObjectStore,
+		b.getBarmanConfiguration(),
 		b.Backup.Status.ServerName,
-		b.Env,
+		b.getEnv(),
 	)
 	if err != nil {
 		// Proper logging already happened inside GetBackupList
-		return
+		if retentionPolicyStatus != nil && retentionPolicyStatus.Error == "" {
+			retentionPolicyStatus.Error = err.Error()
+		}
+		if retentionPolicyStatus == nil {
+			return
+		}
 	}
 
-	if err := barman.DeleteBackupsNotInCatalog(ctx, b.Client, b.Cluster, backupList); err != nil {
-		b.Log.Error(err, "while deleting Backups not present in the catalog")
+	if backupList != nil {
+		removedBackups, err := barman.DeleteBackupsNotInCatalog(
+			ctx, b.Client, b.Cluster, b.getBarmanConfiguration(), backupList)
+		if err != nil {
+			b.Log.Error(err, "while deleting Backups not present in the catalog")
+		}
+		if retentionPolicyStatus != nil {
+			retentionPolicyStatus.RemovedBackups = removedBackups
+			if len(removedBackups) > 0 {
+				b.Recorder.Eventf(b.Cluster, "Normal", events.RetentionPolicyApplied,
+					"Retention policy removed %d backup(s)", len(removedBackups))
+			}
+		}
 	}
 
 	if err := b.retryWithRefreshedCluster(ctx, func() error {
 		origCluster := b.Cluster.DeepCopy()
 
-		// Set the first recoverability point and the last successful backup
-		updateClusterStatusWithBackupTimes(b.Cluster, backupList)
+		// Set the first recoverability point and the last successful backup.
+		// They refer to the main object store, the additional ones are
+		// only reported by their backups
+		if backupList != nil && b.Backup.Spec.ObjectStoreName == "" {
+			updateClusterStatusWithBackupTimes(b.Cluster, backupList)
+		}
+
+		// Report the outcome of the retention policy enforcement
+		if retentionPolicyStatus != nil {
+			b.Cluster.Status.LastRetentionPolicyEnforcement = retentionPolicyStatus.DeepCopy()
+		}
 
 		if reflect.DeepEqual(origCluster.Status, b.Cluster.Status) {
 			return nil
@@ -428,8 +511,132 @@ func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 	}
 }
 
-// updateClusterStatusWithBackupTimes updates the last successful backup time and first
-// recoverability point for the cluster
+// enforceRetentionPolicy deletes the backups from the object store according
+// to the retention policy, returning the outcome of the operation or nil
+// if no retention policy is defined. The removed backups are detected
+// afterwards, from the Backup objects missing in the catalog
+func (b *BackupCommand) enforceRetentionPolicy(ctx context.Context) *apiv1.RetentionPolicyEnforcementStatus {
+	if b.Cluster.Spec.Backup.RetentionPolicy == "" {
+		return nil
+	}
+
+	b.Log.Info("Applying backup retention policy",
+		"retentionPolicy", b.Cluster.Spec.Backup.RetentionPolicy)
+
+	status := &apiv1.RetentionPolicyEnforcementStatus{
+		Time:            utils.GetCurrentTimestamp(),
+		RetentionPolicy: b.Cluster.Spec.Backup.RetentionPolicy,
+	}
+
+	if err := barman.DeleteBackupsByPolicy(
+		ctx,
+		b.getBarmanConfiguration(),
+		b.Cluster.Spec.Backup.RetentionPolicy,
+		b.Backup.Status.ServerName,
+		b.getEnv(),
+	); err != nil {
+		// Proper logging already happened inside DeleteBackupsByPolicy
+		b.Recorder.Event(b.Cluster, "Warning", events.RetentionPolicyFailed, "Retention policy failed")
+		// We do not want to return here, we must go on to set the fist recoverability point
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+// DeleteBackupFromObjectStore removes the base backup referenced by the
+// passed Backup, together with the WAL files not needed anymore, from the
+// object store where it has been taken. The recoverability information of
+// the cluster is refreshed, keeping it consistent with the catalog
+func DeleteBackupFromObjectStore(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) error {
+	configuration := cluster.Spec.Backup.GetBarmanObjectStore(backup.Spec.ObjectStoreName)
+	if configuration == nil {
+		return fmt.Errorf("object store of backup %s is not configured in the cluster", backup.Name)
+	}
+
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		cli,
+		cluster.Namespace,
+		configuration,
+		os.Environ())
+	if err != nil {
+		return fmt.Errorf("cannot recover backup credentials: %w", err)
+	}
+
+	if err := barman.DeleteBackup(ctx, backup, cluster.Name, configuration, env); err != nil {
+		return err
+	}
+
+	// The first recoverability point and the last successful backup
+	// only refer to the main object store
+	if backup.Spec.ObjectStoreName != "" {
+		return nil
+	}
+
+	backupList, err := barman.GetBackupList(ctx, configuration, backup.Status.ServerName, env)
+	if err != nil {
+		return err
+	}
+
+	origCluster := cluster.DeepCopy()
+	updateClusterStatusWithBackupTimes(cluster, backupList)
+	if reflect.DeepEqual(origCluster.Status, cluster.Status) {
+		return nil
+	}
+	return cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// RefreshBackupCatalog reads the catalog of the main object store of the
+// cluster and reports it in the cluster status, together with the first
+// recoverability point and the last successful backup
+func RefreshBackupCatalog(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) error {
+	configuration := cluster.Spec.Backup.GetBarmanObjectStore("")
+	if configuration == nil {
+		// Forget the catalog of an object store which is not used anymore
+		if len(cluster.Status.BackupCatalog) == 0 {
+			return nil
+		}
+		origCluster := cluster.DeepCopy()
+		cluster.Status.BackupCatalog = nil
+		return cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		cli,
+		cluster.Namespace,
+		configuration,
+		os.Environ())
+	if err != nil {
+		return fmt.Errorf("cannot recover backup credentials: %w", err)
+	}
+
+	serverName := configuration.ServerName
+	if serverName == "" {
+		serverName = cluster.Name
+	}
+
+	backupList, err := barman.GetBackupList(ctx, configuration, serverName, env)
+	if err != nil {
+		return err
+	}
+
+	origCluster := cluster.DeepCopy()
+	updateClusterStatusWithBackupTimes(cluster, backupList)
+	if reflect.DeepEqual(origCluster.Status, cluster.Status) {
+		return nil
+	}
+	return cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// updateClusterStatusWithBackupTimes updates the last successful backup time, first
+// recoverability point and backup catalog for the cluster
 func updateClusterStatusWithBackupTimes(cluster *apiv1.Cluster, backupList *catalog.Catalog) {
 	firstRecoverabilityPoint := backupList.FirstRecoverabilityPoint()
 	var lastSuccessfulBackup *time.Time
@@ -438,6 +645,38 @@ func updateClusterStatusWithBackupTimes(cluster *apiv1.Cluster, backupList *cata
 	}
 
 	cluster.UpdateBackupTimes(apiv1.BackupMethodBarmanObjectStore, firstRecoverabilityPoint, lastSuccessfulBackup)
+	cluster.Status.BackupCatalog = getBackupCatalogEntries(backupList)
+}
+
+// getBackupCatalogEntries converts the most recent completed backups of
+// a catalog to the entries reported in the cluster status, which would
+// otherwise grow with the retention policy up to the object size limit
+func getBackupCatalogEntries(backupList *catalog.Catalog) []apiv1.BackupCatalogEntry {
+	completedBackups := backupList.CompletedBackups()
+	if len(completedBackups) == 0 {
+		return nil
+	}
+	if len(completedBackups) > apiv1.MaxBackupCatalogEntries {
+		completedBackups = completedBackups[len(completedBackups)-apiv1.MaxBackupCatalogEntries:]
+	}
+
+	entries := make([]apiv1.BackupCatalogEntry, 0, len(completedBackups))
+	for _, backup := range completedBackups {
+		entries = append(entries, apiv1.BackupCatalogEntry{
+			ID:         backup.ID,
+			Name:       backup.BackupName,
+			TimeLineID: backup.TimeLine,
+			BeginTime:  &metav1.Time{Time: backup.BeginTime},
+			EndTime:    &metav1.Time{Time: backup.EndTime},
+			BeginWal:   backup.BeginWal,
+			EndWal:     backup.EndWal,
+			BeginLSN:   backup.BeginLSN,
+			EndLSN:     backup.EndLSN,
+			Size:       backup.Size,
+		})
+	}
+
+	return entries
 }
 
 // PatchBackupStatusAndRetry updates a certain backup's status in the k8s database,
@@ -466,7 +705,7 @@ func PatchBackupStatusAndRetry(
 
 // setupBackupStatus configures the backup's status from the provided configuration and instance
 func (b *BackupCommand) setupBackupStatus() {
-	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
+	barmanConfiguration := b.getBarmanConfiguration()
 	backupStatus := b.Backup.GetStatus()
 
 	if b.Capabilities.ShouldExecuteBackupWithName(b.Cluster) {
@@ -479,6 +718,10 @@ func (b *BackupCommand) setupBackupStatus() {
 	if barmanConfiguration.Data != nil {
 		backupStatus.Encryption = string(barmanConfiguration.Data.Encryption)
 	}
+	if barmanConfiguration.Wal != nil {
+		backupStatus.WALCompression = barmanConfiguration.Wal.Compression
+		backupStatus.WALClientSideEncryption = barmanConfiguration.Wal.ClientSideEncryption
+	}
 	// Set the barman server name as specified by the user.
 	// If not explicitly configured use the cluster name
 	backupStatus.ServerName = barmanConfiguration.ServerName
@@ -0,0 +1,81 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// getEnv returns the environment used to run the barman-cloud commands
+func (b *BackupCommand) getEnv() []string {
+	b.envLock.RLock()
+	defer b.envLock.RUnlock()
+
+	return slices.Clone(b.Env)
+}
+
+// setEnv sets the environment used to run the barman-cloud commands
+func (b *BackupCommand) setEnv(env []string) {
+	b.envLock.Lock()
+	defer b.envLock.Unlock()
+
+	b.Env = env
+}
+
+// trackBackupCommand registers a running backup command, whose
+// credentials will be refreshed when they are rotated
+func (instance *Instance) trackBackupCommand(b *BackupCommand) {
+	instance.runningBackups.Store(b, struct{}{})
+}
+
+// untrackBackupCommand removes a completed backup command
+// from the running ones
+func (instance *Instance) untrackBackupCommand(b *BackupCommand) {
+	instance.runningBackups.Delete(b)
+}
+
+// RefreshBackupCredentials updates the object store credentials of the
+// running backup commands with the ones cached for the WAL archiver, which
+// the instance reconciler reads from the current cluster definition. They
+// will be used for the barman-cloud commands started from now on, such as
+// the ones enforcing the retention policy. The barman-cloud-backup process
+// which is already running keeps the credentials it was started with
+func (instance *Instance) RefreshBackupCredentials(ctx context.Context) {
+	contextLogger := log.FromContext(ctx)
+
+	instance.runningBackups.Range(func(key, _ any) bool {
+		b, ok := key.(*BackupCommand)
+		if !ok {
+			return true
+		}
+
+		env, err := cache.LoadEnv(cache.GetWALArchiveKey(b.Backup.Spec.ObjectStoreName))
+		if err != nil {
+			contextLogger.Warning("Cannot refresh the credentials of the running backup",
+				"backupName", b.Backup.GetName(),
+				"err", err.Error())
+			return true
+		}
+
+		b.setEnv(env)
+		return true
+	})
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("refreshing the credentials of the running backups", func() {
+	const namespace = "test"
+
+	var (
+		instance *Instance
+		command  *BackupCommand
+	)
+
+	BeforeEach(func() {
+		instance = &Instance{}
+		command = &BackupCommand{
+			Cluster: &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: namespace},
+			},
+			Backup:   &apiv1.Backup{ObjectMeta: metav1.ObjectMeta{Name: "test-backup", Namespace: namespace}},
+			Instance: instance,
+		}
+		command.setEnv([]string{"AWS_ACCESS_KEY_ID=old-id"})
+
+		DeferCleanup(func() {
+			cache.DeleteIf(cache.IsWALArchiveKey)
+		})
+	})
+
+	It("refreshes the credentials of the tracked backups", func(ctx SpecContext) {
+		instance.trackBackupCommand(command)
+		cache.Store(cache.WALArchiveKey, []string{"AWS_ACCESS_KEY_ID=new-id"})
+
+		instance.RefreshBackupCredentials(ctx)
+		Expect(command.getEnv()).To(ContainElement("AWS_ACCESS_KEY_ID=new-id"))
+		Expect(command.getEnv()).ToNot(ContainElement("AWS_ACCESS_KEY_ID=old-id"))
+	})
+
+	It("uses the credentials of the object store of the backup", func(ctx SpecContext) {
+		command.Backup.Spec.ObjectStoreName = "dr"
+		instance.trackBackupCommand(command)
+		cache.Store(cache.WALArchiveKey, []string{"AWS_ACCESS_KEY_ID=main-id"})
+		cache.Store(cache.GetWALArchiveKey("dr"), []string{"AWS_ACCESS_KEY_ID=dr-id"})
+
+		instance.RefreshBackupCredentials(ctx)
+		Expect(command.getEnv()).To(ConsistOf("AWS_ACCESS_KEY_ID=dr-id"))
+	})
+
+	It("doesn't touch the backups that are not running anymore", func(ctx SpecContext) {
+		instance.trackBackupCommand(command)
+		instance.untrackBackupCommand(command)
+		cache.Store(cache.WALArchiveKey, []string{"AWS_ACCESS_KEY_ID=new-id"})
+
+		instance.RefreshBackupCredentials(ctx)
+		Expect(command.getEnv()).To(ContainElement("AWS_ACCESS_KEY_ID=old-id"))
+	})
+
+	It("keeps the current credentials when none are cached", func(ctx SpecContext) {
+		instance.trackBackupCommand(command)
+
+		instance.RefreshBackupCredentials(ctx)
+		Expect(command.getEnv()).To(ContainElement("AWS_ACCESS_KEY_ID=old-id"))
+	})
+})
@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+)
+
+// backupHookMaxMessageLength is the maximum length of the body of a
+// failed HTTP response that is reported in the error
+const backupHookMaxMessageLength = 256
+
+// runPreBackupHooks sequentially executes the hooks to be run before
+// the backup is started, stopping at the first failure
+func (b *BackupCommand) runPreBackupHooks(ctx context.Context) error {
+	for _, hook := range b.Cluster.Spec.Backup.GetHooks().GetPre() {
+		b.Log.Info("Executing pre-backup hook", "hook", hook.Name)
+		if err := executeBackupHook(ctx, hook, b.Instance.ConnectionPool().Connection, b.getSecret); err != nil {
+			b.Recorder.Eventf(b.Backup, "Warning", events.BackupHookFailed,
+				"Pre-backup hook %s failed: %v", hook.Name, err)
+			return fmt.Errorf("pre-backup hook %s failed: %w", hook.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// runPostBackupHooks sequentially executes the hooks to be run after
+// the backup. Failing hooks are logged and reported as events, but don't
+// interrupt the execution of the following ones. As they must resume the
+// activity of the applications, they are run even when the backup has
+// been cancelled
+func (b *BackupCommand) runPostBackupHooks(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	for _, hook := range b.Cluster.Spec.Backup.GetHooks().GetPost() {
+		b.Log.Info("Executing post-backup hook", "hook", hook.Name)
+		if err := executeBackupHook(ctx, hook, b.Instance.ConnectionPool().Connection, b.getSecret); err != nil {
+			b.Log.Error(err, "Post-backup hook failed", "hook", hook.Name)
+			b.Recorder.Eventf(b.Backup, "Warning", events.BackupHookFailed,
+				"Post-backup hook %s failed: %v", hook.Name, err)
+		}
+	}
+}
+
+// getSecret gets a secret in the namespace of the cluster
+func (b *BackupCommand) getSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	if err := b.Client.Get(ctx, client.ObjectKey{Namespace: b.Cluster.Namespace, Name: name}, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// executeBackupHook executes a backup hook, cancelling it when it lasts
+// longer than its timeout. The passed functions are used to get the
+// connection to the database where the SQL hooks are executed, and the
+// secrets containing the headers of the HTTP requests
+func executeBackupHook(
+	ctx context.Context,
+	hook apiv1.BackupHook,
+	connect func(dbname string) (*sql.DB, error),
+	getSecret func(ctx context.Context, name string) (*corev1.Secret, error),
+) error {
+	hookCtx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+
+	var err error
+	switch {
+	case hook.SQL != "":
+		err = executeBackupHookSQL(hookCtx, hook, connect)
+	case hook.HTTP != nil:
+		err = executeBackupHookHTTP(hookCtx, hook.HTTP, getSecret)
+	}
+
+	if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %v", hook.GetTimeout())
+	}
+	return err
+}
+
+// executeBackupHookSQL executes the SQL statement of a hook as the superuser
+func executeBackupHookSQL(
+	ctx context.Context,
+	hook apiv1.BackupHook,
+	connect func(dbname string) (*sql.DB, error),
+) error {
+	db, err := connect(hook.GetDatabase())
+	if err != nil {
+		return fmt.Errorf("while connecting to database %q: %w", hook.GetDatabase(), err)
+	}
+
+	_, err = db.ExecContext(ctx, hook.SQL)
+	return err
+}
+
+// executeBackupHookHTTP sends the HTTP request of a hook, failing when
+// the response status is not successful
+func executeBackupHookHTTP(
+	ctx context.Context,
+	action *apiv1.BackupHookHTTPAction,
+	getSecret func(ctx context.Context, name string) (*corev1.Secret, error),
+) error {
+	req, err := http.NewRequestWithContext(ctx, action.GetMethod(), action.URL, http.NoBody)
+	if err != nil {
+		return err
+	}
+	if action.HeadersSecret != nil {
+		secret, err := getSecret(ctx, action.HeadersSecret.Name)
+		if err != nil {
+			return fmt.Errorf("while getting the headers from secret %q: %w", action.HeadersSecret.Name, err)
+		}
+		for name, value := range secret.Data {
+			req.Header.Set(name, string(value))
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, backupHookMaxMessageLength))
+	message := strings.TrimSpace(string(bytes.ToValidUTF8(body, nil)))
+	if message == "" {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return fmt.Errorf("unexpected status %s: %s", resp.Status, message)
+}
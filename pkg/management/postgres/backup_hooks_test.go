@@ -0,0 +1,154 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup hooks", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	connect := func(dbname string) (*sql.DB, error) {
+		if dbname != "app" && dbname != "postgres" {
+			return nil, errors.New("unknown database")
+		}
+		return db, nil
+	}
+
+	getSecret := func(_ context.Context, name string) (*corev1.Secret, error) {
+		if name != "app-hooks" {
+			return nil, errors.New("secret not found")
+		}
+		return &corev1.Secret{
+			Data: map[string][]byte{"X-Backup": []byte("quiesce")},
+		}, nil
+	}
+
+	It("executes the SQL statement in the requested database", func() {
+		mock.ExpectExec("CHECKPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		hook := apiv1.BackupHook{Name: "flush", SQL: "CHECKPOINT", Database: "app"}
+		Expect(executeBackupHook(context.Background(), hook, connect, getSecret)).To(Succeed())
+	})
+
+	It("reports the failures of the SQL statement", func() {
+		mock.ExpectExec("CHECKPOINT").WillReturnError(errors.New("boom"))
+
+		hook := apiv1.BackupHook{Name: "flush", SQL: "CHECKPOINT"}
+		Expect(executeBackupHook(context.Background(), hook, connect, getSecret)).To(MatchError("boom"))
+	})
+
+	It("reports the failures connecting to the database", func() {
+		hook := apiv1.BackupHook{Name: "flush", SQL: "CHECKPOINT", Database: "other"}
+		err := executeBackupHook(context.Background(), hook, connect, getSecret)
+		Expect(err).To(MatchError(ContainSubstring(`while connecting to database "other"`)))
+	})
+
+	It("sends the HTTP request with the requested method and headers", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPut))
+			Expect(r.Header.Get("X-Backup")).To(Equal("quiesce"))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		DeferCleanup(server.Close)
+
+		hook := apiv1.BackupHook{
+			Name: "quiesce",
+			HTTP: &apiv1.BackupHookHTTPAction{
+				URL:           server.URL,
+				Method:        http.MethodPut,
+				HeadersSecret: &apiv1.LocalObjectReference{Name: "app-hooks"},
+			},
+		}
+		Expect(executeBackupHook(context.Background(), hook, connect, getSecret)).To(Succeed())
+	})
+
+	It("reports the failures getting the headers of the HTTP request", func() {
+		hook := apiv1.BackupHook{
+			Name: "quiesce",
+			HTTP: &apiv1.BackupHookHTTPAction{
+				URL:           "http://app/quiesce",
+				HeadersSecret: &apiv1.LocalObjectReference{Name: "missing"},
+			},
+		}
+		err := executeBackupHook(context.Background(), hook, connect, getSecret)
+		Expect(err).To(MatchError(ContainSubstring(`while getting the headers from secret "missing"`)))
+	})
+
+	It("reports the unsuccessful HTTP responses", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("still writing\n"))
+		}))
+		DeferCleanup(server.Close)
+
+		hook := apiv1.BackupHook{Name: "quiesce", HTTP: &apiv1.BackupHookHTTPAction{URL: server.URL}}
+		err := executeBackupHook(context.Background(), hook, connect, getSecret)
+		Expect(err).To(MatchError("unexpected status 503 Service Unavailable: still writing"))
+	})
+
+	It("cancels the hooks exceeding their timeout", func() {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+		DeferCleanup(server.Close)
+		DeferCleanup(func() { close(release) })
+
+		hook := apiv1.BackupHook{
+			Name:    "quiesce",
+			HTTP:    &apiv1.BackupHookHTTPAction{URL: server.URL},
+			Timeout: 1,
+		}
+		startedAt := time.Now()
+		err := executeBackupHook(context.Background(), hook, connect, getSecret)
+		Expect(err).To(MatchError("timed out after 1s"))
+		Expect(time.Since(startedAt)).To(BeNumerically("<", 5*time.Second))
+	})
+})
@@ -0,0 +1,266 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// backupProgressInterval is the interval between two progress reports of
+// a running backup
+const backupProgressInterval = 30 * time.Second
+
+// runningBackups holds the progress of the backups running on this instance
+var runningBackups = struct {
+	sync.Mutex
+	progress map[string]apiv1.BackupProgress
+}{
+	progress: make(map[string]apiv1.BackupProgress),
+}
+
+// GetBackupProgress returns the progress of a backup running on this instance,
+// and false if no backup with the passed name is running
+func GetBackupProgress(backupName string) (*apiv1.BackupProgress, bool) {
+	runningBackups.Lock()
+	defer runningBackups.Unlock()
+
+	progress, ok := runningBackups.progress[backupName]
+	if !ok {
+		return nil, false
+	}
+	return progress.DeepCopy(), true
+}
+
+func setBackupProgress(backupName string, progress apiv1.BackupProgress) {
+	runningBackups.Lock()
+	defer runningBackups.Unlock()
+	runningBackups.progress[backupName] = progress
+}
+
+func removeBackupProgress(backupName string) {
+	runningBackups.Lock()
+	defer runningBackups.Unlock()
+	delete(runningBackups.progress, backupName)
+}
+
+// backupProgressTracker estimates the progress of a running
+// barman-cloud-backup process
+type backupProgressTracker struct {
+	// The path of the I/O statistics of the backup process
+	ioStatsPath string
+
+	startedAt           time.Time
+	totalBytes          int64
+	initialArchivedWALs int64
+}
+
+// newBackupProgressTracker creates a tracker for the backup process with
+// the passed PID, estimating the amount of data to be copied from
+// the size of the databases
+func (instance *Instance) newBackupProgressTracker(
+	ctx context.Context,
+	pid int,
+) (*backupProgressTracker, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := &backupProgressTracker{
+		ioStatsPath: filepath.Join("/proc", strconv.Itoa(pid), "io"),
+		startedAt:   time.Now(),
+	}
+	if tracker.totalBytes, err = getDatabasesSize(ctx, superUserDB); err != nil {
+		return nil, err
+	}
+	if tracker.initialArchivedWALs, err = instance.getArchivedWALsCount(ctx); err != nil {
+		return nil, err
+	}
+
+	return tracker, nil
+}
+
+// collect reads the current progress of the backup process
+func (tracker *backupProgressTracker) collect(
+	ctx context.Context,
+	instance *Instance,
+) (apiv1.BackupProgress, error) {
+	copiedBytes, err := readProcessReadBytes(tracker.ioStatsPath)
+	if err != nil {
+		return apiv1.BackupProgress{}, err
+	}
+
+	archivedWALs, err := instance.getArchivedWALsCount(ctx)
+	if err != nil {
+		return apiv1.BackupProgress{}, err
+	}
+
+	return computeBackupProgress(
+		tracker.totalBytes,
+		copiedBytes,
+		archivedWALs-tracker.initialArchivedWALs,
+		tracker.startedAt,
+		time.Now(),
+	), nil
+}
+
+// completed returns the progress of a backup whose copy has been completed,
+// starting from the last reported one
+func (tracker *backupProgressTracker) completed(lastProgress apiv1.BackupProgress) apiv1.BackupProgress {
+	progress := lastProgress
+	progress.TotalBytes = max(tracker.totalBytes, progress.CopiedBytes)
+	progress.CopiedBytes = progress.TotalBytes
+	progress.Completion = "100%"
+	progress.EstimatedCompletionTime = nil
+	progress.UpdatedAt = &metav1.Time{Time: time.Now()}
+	return progress
+}
+
+// computeBackupProgress computes the completion percentage and the
+// estimated completion time of a backup, given the data copied so far.
+// As the total is just an estimation, the completion stays below 100%
+// until the backup is terminated
+func computeBackupProgress(
+	totalBytes int64,
+	copiedBytes int64,
+	archivedWALs int64,
+	startedAt time.Time,
+	now time.Time,
+) apiv1.BackupProgress {
+	progress := apiv1.BackupProgress{
+		TotalBytes:   totalBytes,
+		CopiedBytes:  copiedBytes,
+		ArchivedWALs: max(archivedWALs, 0),
+		UpdatedAt:    &metav1.Time{Time: now},
+	}
+
+	if totalBytes <= 0 {
+		return progress
+	}
+
+	percentage := min(copiedBytes*100/totalBytes, 99)
+	progress.Completion = fmt.Sprintf("%d%%", percentage)
+
+	if copiedBytes > 0 && copiedBytes < totalBytes {
+		elapsed := now.Sub(startedAt)
+		remaining := time.Duration(float64(elapsed) * float64(totalBytes-copiedBytes) / float64(copiedBytes))
+		progress.EstimatedCompletionTime = &metav1.Time{Time: now.Add(remaining).Truncate(time.Second)}
+	}
+
+	return progress
+}
+
+// readProcessReadBytes reads the number of bytes read by a process
+// from its I/O statistics
+func readProcessReadBytes(ioStatsPath string) (int64, error) {
+	file, err := os.Open(ioStatsPath) // #nosec G304
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, found := strings.CutPrefix(scanner.Text(), "rchar:")
+		if !found {
+			continue
+		}
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("rchar not found in %s", ioStatsPath)
+}
+
+// getDatabasesSize returns the size of all the databases of the instance,
+// tablespaces included
+func getDatabasesSize(ctx context.Context, db *sql.DB) (int64, error) {
+	var size int64
+	row := db.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(sum(pg_catalog.pg_database_size(oid)), 0) FROM pg_catalog.pg_database")
+	if err := row.Scan(&size); err != nil {
+		return 0, fmt.Errorf("while estimating the size of the databases: %w", err)
+	}
+	return size, nil
+}
+
+// getArchivedWALsCount returns the number of WAL files archived by
+// the instance
+func (instance *Instance) getArchivedWALsCount(ctx context.Context) (int64, error) {
+	status, err := instance.GetWALArchiveStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return status.ArchivedCount, nil
+}
+
+// reportBackupProgress periodically collects the progress of the
+// backup, exposing it through the local webserver and in the Backup
+// status, until the context is cancelled. The last collected progress
+// is returned
+func (b *BackupCommand) reportBackupProgress(
+	ctx context.Context,
+	tracker *backupProgressTracker,
+	origBackup *apiv1.Backup,
+) apiv1.BackupProgress {
+	var progress apiv1.BackupProgress
+
+	ticker := time.NewTicker(backupProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return progress
+		case <-ticker.C:
+		}
+
+		currentProgress, err := tracker.collect(ctx, b.Instance)
+		if err != nil {
+			// The process could have just terminated
+			b.Log.Debug("while collecting backup progress", "err", err.Error())
+			continue
+		}
+		progress = currentProgress
+		setBackupProgress(origBackup.Name, progress)
+
+		backup := origBackup.DeepCopy()
+		backup.Status.Progress = progress.DeepCopy()
+		if err := b.Client.Status().Patch(ctx, backup, client.MergeFrom(origBackup)); err != nil {
+			b.Log.Warning("Cannot report the backup progress", "err", err.Error())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup progress", func() {
+	startedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	It("estimates the completion of a running backup", func() {
+		now := startedAt.Add(10 * time.Minute)
+		progress := computeBackupProgress(1000, 250, 3, startedAt, now)
+		Expect(progress.TotalBytes).To(BeEquivalentTo(1000))
+		Expect(progress.CopiedBytes).To(BeEquivalentTo(250))
+		Expect(progress.ArchivedWALs).To(BeEquivalentTo(3))
+		Expect(progress.Completion).To(Equal("25%"))
+		Expect(progress.EstimatedCompletionTime.Time).To(Equal(now.Add(30 * time.Minute)))
+		Expect(progress.UpdatedAt.Time).To(Equal(now))
+	})
+
+	It("doesn't report a completed backup before it terminates", func() {
+		progress := computeBackupProgress(1000, 1200, 0, startedAt, startedAt.Add(time.Minute))
+		Expect(progress.Completion).To(Equal("99%"))
+		Expect(progress.EstimatedCompletionTime).To(BeNil())
+	})
+
+	It("doesn't estimate the completion without the total size", func() {
+		progress := computeBackupProgress(0, 1200, -1, startedAt, startedAt.Add(time.Minute))
+		Expect(progress.Completion).To(BeEmpty())
+		Expect(progress.EstimatedCompletionTime).To(BeNil())
+		Expect(progress.ArchivedWALs).To(BeZero())
+	})
+
+	It("marks the progress as completed", func() {
+		tracker := backupProgressTracker{totalBytes: 1000}
+		progress := tracker.completed(apiv1.BackupProgress{
+			TotalBytes:              1000,
+			CopiedBytes:             1100,
+			ArchivedWALs:            2,
+			Completion:              "99%",
+			EstimatedCompletionTime: &metav1.Time{Time: startedAt},
+		})
+		Expect(progress.TotalBytes).To(BeEquivalentTo(1100))
+		Expect(progress.CopiedBytes).To(BeEquivalentTo(1100))
+		Expect(progress.ArchivedWALs).To(BeEquivalentTo(2))
+		Expect(progress.Completion).To(Equal("100%"))
+		Expect(progress.EstimatedCompletionTime).To(BeNil())
+	})
+
+	It("reads the bytes read by a process", func() {
+		ioStatsPath := filepath.Join(GinkgoT().TempDir(), "io")
+		Expect(os.WriteFile(ioStatsPath, []byte(
+			"rchar: 123456\nwchar: 789\nsyscr: 10\nsyscw: 2\nread_bytes: 4096\n"), 0o600)).To(Succeed())
+
+		readBytes, err := readProcessReadBytes(ioStatsPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readBytes).To(BeEquivalentTo(123456))
+	})
+
+	It("fails when the I/O statistics are not available", func() {
+		_, err := readProcessReadBytes(filepath.Join(GinkgoT().TempDir(), "io"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("keeps track of the running backups", func() {
+		_, ok := GetBackupProgress("backup-example")
+		Expect(ok).To(BeFalse())
+
+		setBackupProgress("backup-example", apiv1.BackupProgress{Completion: "42%"})
+		progress, ok := GetBackupProgress("backup-example")
+		Expect(ok).To(BeTrue())
+		Expect(progress.Completion).To(Equal("42%"))
+
+		removeBackupProgress("backup-example")
+		_, ok = GetBackupProgress("backup-example")
+		Expect(ok).To(BeFalse())
+	})
+})
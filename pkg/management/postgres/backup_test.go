@@ -18,6 +18,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -135,6 +136,18 @@ var _ = Describe("testing backup command", func() {
 		}
 	})
 
+	It("records how the WAL files are archived in the backup status", func() {
+		cluster.Spec.Backup.BarmanObjectStore.Wal.Compression = apiv1.CompressionTypeZstd
+		cluster.Spec.Backup.BarmanObjectStore.Wal.ClientSideEncryption = &apiv1.WALClientSideEncryption{
+			KeysSecret: apiv1.LocalObjectReference{Name: "wal-keys"},
+			ActiveKey:  "current",
+		}
+		backupCommand.setupBackupStatus()
+		Expect(backup.Status.WALCompression).To(Equal(apiv1.CompressionTypeZstd))
+		Expect(backup.Status.WALClientSideEncryption.KeysSecret.Name).To(Equal("wal-keys"))
+		Expect(backup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseRunning))
+	})
+
 	It("should fail and update cluster and backup resource", func() {
 		backupCommand.run(context.Background())
 		Expect(cluster.Status.LastFailedBackup).ToNot(BeEmpty())
@@ -205,6 +218,46 @@ var _ = Describe("update barman backup metadata", func() {
 			ToNot(HaveKey(apiv1.BackupMethodVolumeSnapshot))
 	})
 
+	It("reports the completed backups in the catalog", func() {
+		barmanBackups.List = append(barmanBackups.List, catalog.BarmanBackup{
+			BackupName: "failed",
+			BeginTime:  oneHourAgo.Time,
+			Error:      "failure",
+		})
+		barmanBackups.List[0].ID = "twoHoursAgoID"
+		barmanBackups.List[0].BeginWal = "000000010000000000000002"
+		barmanBackups.List[0].Size = 1024
+
+		updateClusterStatusWithBackupTimes(cluster, barmanBackups)
+
+		Expect(cluster.Status.BackupCatalog).To(HaveLen(2))
+		Expect(cluster.Status.BackupCatalog[0]).To(Equal(apiv1.BackupCatalogEntry{
+			ID:        "twoHoursAgoID",
+			Name:      "twoHoursAgo",
+			BeginTime: &threeHoursAgo,
+			EndTime:   &twoHoursAgo,
+			BeginWal:  "000000010000000000000002",
+			Size:      1024,
+		}))
+		Expect(cluster.Status.BackupCatalog[1].Name).To(Equal("youngest"))
+	})
+
+	It("reports only the most recent backups in the catalog", func() {
+		backups := &catalog.Catalog{}
+		for i := range apiv1.MaxBackupCatalogEntries + 10 {
+			backups.List = append(backups.List, catalog.BarmanBackup{
+				ID:        fmt.Sprintf("backup-%03d", i),
+				BeginTime: now.Add(time.Duration(i) * time.Minute),
+				EndTime:   now.Add(time.Duration(i)*time.Minute + time.Second),
+			})
+		}
+
+		entries := getBackupCatalogEntries(backups)
+		Expect(entries).To(HaveLen(apiv1.MaxBackupCatalogEntries))
+		Expect(entries[0].ID).To(Equal("backup-010"))
+		Expect(entries[len(entries)-1].ID).To(Equal(fmt.Sprintf("backup-%03d", apiv1.MaxBackupCatalogEntries+9)))
+	})
+
 	It("will update the metadata if they are outdated", func() {
 		cluster.Status = apiv1.ClusterStatus{
 			FirstRecoverabilityPoint: now.Format(time.RFC3339),
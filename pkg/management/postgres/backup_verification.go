@@ -0,0 +1,239 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+
+	"github.com/jackc/pgx/v5"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// backupVerificationRoleName is the name of the role running the sanity
+// queries configured in the backup, in the instance restored to verify it
+const backupVerificationRoleName = "cnpg_backup_verification"
+
+// VerifyBackup checks that the backup with the passed name can be restored.
+// The backup is restored in the data directory, which is expected to be
+// scratch space, and the archived WAL files are replayed until the instance
+// reaches a consistent state. The sanity queries configured in the backup
+// are then run against the restored instance
+func (info InitInfo) VerifyBackup(ctx context.Context, backupName string) error {
+	contextLogger := log.FromContext(ctx).WithValues("backup", backupName)
+
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := info.loadCluster(ctx, typedClient)
+	if err != nil {
+		return err
+	}
+
+	var backup apiv1.Backup
+	if err := typedClient.Get(
+		ctx,
+		client.ObjectKey{Namespace: info.Namespace, Name: backupName},
+		&backup,
+	); err != nil {
+		return err
+	}
+	if backup.Status.Phase != apiv1.BackupPhaseCompleted {
+		return fmt.Errorf("backup %s is not completed (phase: %s)", backupName, backup.Status.Phase)
+	}
+
+	env, err := getBackupRestoreEnv(ctx, typedClient, &backup)
+	if err != nil {
+		return err
+	}
+
+	// The WAL files needed to make the backup consistent must be in the
+	// archive, and must be readable by barman-cloud-wal-restore
+	if err := checkWALCompression(&backup); err != nil {
+		return err
+	}
+	for _, walName := range []string{backup.Status.BeginWal, backup.Status.EndWal} {
+		if err := info.ensureArchiveContainsWAL(ctx, cluster, env, &backup, walName); err != nil {
+			return fmt.Errorf("while checking the presence of WAL file %s in the archive: %w", walName, err)
+		}
+	}
+
+	contextLogger.Info("Restoring the backup to verify it")
+	if err := info.restoreDataDir(&backup, cluster, env); err != nil {
+		return err
+	}
+	if _, err := info.restoreCustomWalDir(ctx); err != nil {
+		return err
+	}
+	if err := info.WriteInitialPostgresqlConf(cluster); err != nil {
+		return err
+	}
+	if _, err := info.GetInstance().migratePostgresAutoConfFile(ctx); err != nil {
+		return err
+	}
+	if err := info.WriteRestoreHbaConf(); err != nil {
+		return err
+	}
+	// The sanity queries are run by an unprivileged role, which can
+	// connect from the job pod like the superuser does
+	if err := fileutils.AppendStringToFile(
+		path.Join(info.PgData, constants.PostgresqlIdentFile),
+		fmt.Sprintf("local %s %s\n", getCurrentUserOrDefaultToInsecureMapping(), backupVerificationRoleName),
+	); err != nil {
+		return err
+	}
+
+	restoreCommand, err := buildRestoreCommand(&backup)
+	if err != nil {
+		return err
+	}
+	// The backup is usable as soon as the instance is consistent, so
+	// there's no need to replay the WAL files archived after it
+	if err := info.writeRecoveryConfiguration(fmt.Sprintf(
+		"recovery_target_action = promote\n"+
+			"restore_command = '%s'\n"+
+			"recovery_target = 'immediate'\n",
+		restoreCommand,
+	)); err != nil {
+		return err
+	}
+
+	instance := info.GetInstance()
+	instance.Env = env
+	if err := instance.VerifyPgDataCoherence(ctx); err != nil {
+		return err
+	}
+
+	var database string
+	var queries []string
+	if backup.Spec.Verify != nil {
+		database = backup.Spec.Verify.GetDatabase()
+		queries = backup.Spec.Verify.Queries
+	}
+
+	return instance.WithActiveInstance(func() error {
+		superUserDB, err := instance.GetSuperUserDB()
+		if err != nil {
+			return err
+		}
+		if err := waitUntilRecoveryFinishes(superUserDB); err != nil {
+			return fmt.Errorf("while waiting for the backup to be consistent: %w", err)
+		}
+		contextLogger.Info("The restored backup reached a consistent state")
+
+		if len(queries) == 0 {
+			return nil
+		}
+
+		pgVersion, err := instance.GetPgVersion()
+		if err != nil {
+			return err
+		}
+		if err := createBackupVerificationRole(ctx, superUserDB, pgVersion.Major); err != nil {
+			return err
+		}
+
+		verificationPool := pool.NewPostgresqlConnectionPool(fmt.Sprintf(
+			"host=%s port=%v user=%v sslmode=disable application_name=cnpg-backup-verification",
+			GetSocketDir(),
+			GetServerPort(),
+			backupVerificationRoleName,
+		))
+		defer verificationPool.ShutdownConnections()
+
+		db, err := verificationPool.Connection(database)
+		if err != nil {
+			return err
+		}
+		return runVerificationQueries(ctx, db, queries)
+	})
+}
+
+// createBackupVerificationRole creates the unprivileged role running
+// the sanity queries, which can read every table starting from
+// PostgreSQL 14
+func createBackupVerificationRole(ctx context.Context, db *sql.DB, pgMajorVersion uint64) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1)",
+		backupVerificationRoleName,
+	).Scan(&exists); err != nil {
+		return err
+	}
+
+	statement := "CREATE ROLE "
+	if exists {
+		statement = "ALTER ROLE "
+	}
+	statement += pgx.Identifier{backupVerificationRoleName}.Sanitize() +
+		" LOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE NOREPLICATION NOBYPASSRLS"
+	if _, err := db.ExecContext(ctx, statement); err != nil {
+		return fmt.Errorf("while creating the role running the verification queries: %w", err)
+	}
+
+	if pgMajorVersion < 14 {
+		return nil
+	}
+
+	if _, err := db.ExecContext(ctx, "GRANT pg_read_all_data TO "+
+		pgx.Identifier{backupVerificationRoleName}.Sanitize()); err != nil {
+		return fmt.Errorf("while granting read access to the role running the verification queries: %w", err)
+	}
+
+	return nil
+}
+
+// runVerificationQueries runs the passed sanity queries, failing at
+// the first one raising an error. Every query runs in a read-only
+// transaction which is rolled back
+func runVerificationQueries(ctx context.Context, db *sql.DB, queries []string) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx, query := range queries {
+		contextLogger.Info("Running verification query", "query", query)
+		if err := runVerificationQuery(ctx, db, query); err != nil {
+			return fmt.Errorf("verification query #%d failed: %w", idx+1, err)
+		}
+	}
+
+	return nil
+}
+
+// runVerificationQuery runs a sanity query in a read-only transaction
+func runVerificationQuery(ctx context.Context, db *sql.DB, query string) error {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, query)
+	return err
+}
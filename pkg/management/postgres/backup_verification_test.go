@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup verification queries", func() {
+	It("runs every query", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT 1")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT count(*) FROM orders")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		Expect(runVerificationQueries(ctx, db, []string{"SELECT 1", "SELECT count(*) FROM orders"})).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("stops at the first failing query", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT count(*) FROM orders")).
+			WillReturnError(errors.New(`relation "orders" does not exist`))
+		mock.ExpectRollback()
+
+		err = runVerificationQueries(ctx, db, []string{"SELECT count(*) FROM orders", "SELECT 1"})
+		Expect(err).To(MatchError(ContainSubstring("verification query #1 failed")))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates the unprivileged role running the queries", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS")).WithArgs(backupVerificationRoleName).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE ROLE "cnpg_backup_verification" LOGIN NOSUPERUSER`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`GRANT pg_read_all_data TO "cnpg_backup_verification"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(createBackupVerificationRole(ctx, db, 16)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// BaseBackupOptions are the options used to take a base backup
+// of the local instance with pg_basebackup
+type BaseBackupOptions struct {
+	// MaxRate is the maximum transfer rate of the data directory,
+	// using the syntax of the pg_basebackup `--max-rate` option
+	// (i.e. "32M"). An empty value means no limit
+	MaxRate string
+
+	// FastCheckpoint requests an immediate checkpoint instead
+	// of a spread one
+	FastCheckpoint bool
+}
+
+// buildBaseBackupArgs returns the pg_basebackup command line arguments
+// needed to write a tar-format base backup on the standard output
+func (options BaseBackupOptions) buildBaseBackupArgs(connectionString string) []string {
+	args := []string{
+		"-D", "-",
+		"-F", "tar",
+		"-X", "fetch",
+		"-d", connectionString,
+	}
+
+	if options.FastCheckpoint {
+		args = append(args, "-c", "fast")
+	} else {
+		args = append(args, "-c", "spread")
+	}
+
+	if options.MaxRate != "" {
+		args = append(args, "--max-rate", options.MaxRate)
+	}
+
+	return args
+}
+
+// TakeBaseBackup takes a base backup of the local instance using the
+// streaming replication protocol, and writes it as a tarball into
+// the passed writer. The WAL files required to make the backup
+// consistent are included in the tarball.
+// Since pg_basebackup can write a single tablespace to its standard
+// output, this is not supported for instances having tablespaces.
+func (instance *Instance) TakeBaseBackup(
+	ctx context.Context,
+	options BaseBackupOptions,
+	output io.Writer,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	connectionString := buildPrimaryConnInfo("localhost", instance.PodName) + " dbname=postgres"
+	args := options.buildBaseBackupArgs(connectionString)
+
+	contextLogger.Info("Starting streaming base backup", "options", options)
+
+	pgBaseBackupCmd := exec.CommandContext(ctx, pgBaseBackupName, args...) // #nosec
+	pgBaseBackupCmd.Stdout = output
+	pgBaseBackupCmd.Stderr = &execlog.LogWriter{
+		Logger: log.WithName(pgBaseBackupName).WithValues(execlog.PipeKey, execlog.StdErr),
+	}
+	if err := pgBaseBackupCmd.Run(); err != nil {
+		return fmt.Errorf("error in pg_basebackup, %w", err)
+	}
+
+	contextLogger.Info("Streaming base backup completed")
+	return nil
+}
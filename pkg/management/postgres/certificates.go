@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// certificateFile is a certificate used by the instance
+type certificateFile struct {
+	name string
+	path string
+}
+
+// instanceCertificates are the certificates used by every instance
+var instanceCertificates = []certificateFile{
+	{name: "server", path: postgres.ServerCertificateLocation},
+	{name: "streaming-replica", path: postgres.StreamingReplicaCertificateLocation},
+	{name: "server-ca", path: postgres.ServerCACertificateLocation},
+	{name: "client-ca", path: postgres.ClientCACertificateLocation},
+}
+
+// GetCertificatesExpiration reads the validity of the certificates used
+// by the instance, as they have been written by the instance manager.
+// A certificate not written yet is skipped
+func (instance *Instance) GetCertificatesExpiration() ([]postgres.CertificateExpiration, error) {
+	return getCertificatesExpiration(instanceCertificates, time.Now())
+}
+
+// getCertificatesExpiration reads the validity of the passed certificates,
+// computing the days left from the passed time
+func getCertificatesExpiration(
+	files []certificateFile,
+	now time.Time,
+) ([]postgres.CertificateExpiration, error) {
+	result := make([]postgres.CertificateExpiration, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file.path) // #nosec G304
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// The CA files may contain a bundle: the first certificate is the
+		// one of the CA
+		certificate, err := certs.KeyPair{Certificate: content}.ParseCertificate()
+		if err != nil {
+			return nil, fmt.Errorf("while parsing certificate %s: %w", file.path, err)
+		}
+
+		result = append(result, postgres.CertificateExpiration{
+			Name:         file.name,
+			CommonName:   certificate.Subject.CommonName,
+			NotBefore:    certificate.NotBefore,
+			NotAfter:     certificate.NotAfter,
+			DaysToExpiry: certificate.NotAfter.Sub(now).Hours() / 24,
+		})
+	}
+
+	return result, nil
+}
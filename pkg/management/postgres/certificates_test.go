@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("certificates expiration", func() {
+	var (
+		directory string
+		ca        *certs.KeyPair
+	)
+
+	BeforeEach(func() {
+		directory = GinkgoT().TempDir()
+
+		var err error
+		ca, err = certs.CreateRootCAWithDuration("cluster-example", "default", 30*24*time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(directory, "ca.crt"), ca.Certificate, 0o600)).To(Succeed())
+
+		server, err := ca.CreateAndSignPairWithDuration("cluster-example-rw", certs.CertTypeServer, nil,
+			10*24*time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(directory, "server.crt"), server.Certificate, 0o600)).To(Succeed())
+	})
+
+	It("reports the days left before each certificate expires", func() {
+		files := []certificateFile{
+			{name: "server", path: filepath.Join(directory, "server.crt")},
+			{name: "server-ca", path: filepath.Join(directory, "ca.crt")},
+		}
+
+		expirations, err := getCertificatesExpiration(files, time.Now())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expirations).To(HaveLen(2))
+
+		Expect(expirations[0].Name).To(Equal("server"))
+		Expect(expirations[0].CommonName).To(Equal("cluster-example-rw"))
+		Expect(expirations[0].DaysToExpiry).To(BeNumerically("~", 10, 0.01))
+
+		Expect(expirations[1].Name).To(Equal("server-ca"))
+		Expect(expirations[1].DaysToExpiry).To(BeNumerically("~", 30, 0.01))
+	})
+
+	It("reports expired certificates with a negative number of days", func() {
+		files := []certificateFile{{name: "server", path: filepath.Join(directory, "server.crt")}}
+
+		expirations, err := getCertificatesExpiration(files, time.Now().Add(12*24*time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expirations[0].DaysToExpiry).To(BeNumerically("~", -2, 0.01))
+	})
+
+	It("skips the certificates not written yet", func() {
+		files := []certificateFile{{name: "client-ca", path: filepath.Join(directory, "missing.crt")}}
+
+		expirations, err := getCertificatesExpiration(files, time.Now())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expirations).To(BeEmpty())
+	})
+
+	It("fails on a file not containing a certificate", func() {
+		path := filepath.Join(directory, "invalid.crt")
+		Expect(os.WriteFile(path, []byte("not a certificate"), 0o600)).To(Succeed())
+
+		_, err := getCertificatesExpiration([]certificateFile{{name: "server", path: path}}, time.Now())
+		Expect(err).To(HaveOccurred())
+	})
+})
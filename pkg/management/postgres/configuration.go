@@ -19,7 +19,6 @@ package postgres
 import (
 	"context"
 	"fmt"
-	"os"
 	"path"
 	"path/filepath"
 	"sort"
@@ -30,7 +29,6 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
-	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -61,7 +59,15 @@ func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	cluster *apiv1.Cluster,
 	preserveUserSettings bool,
 ) (bool, error) {
-	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster, preserveUserSettings)
+	// The parameters meant for the replicas are applied according to
+	// the current role of this instance, and will be refreshed at
+	// the next reconciliation loop after a promotion or a demotion
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return false, err
+	}
+
+	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster, preserveUserSettings, !isPrimary)
 	if err != nil {
 		return false, err
 	}
@@ -102,10 +108,19 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 		defaultAuthenticationMethod = "md5"
 	}
 
+	clientCertificateRoles := make([]string, 0, len(cluster.GetClientCertificateRoles()))
+	for _, role := range cluster.GetClientCertificateRoles() {
+		clientCertificateRoles = append(clientCertificateRoles, role.Name)
+	}
+
+	passwordAuthentication := cluster.GetPasswordAuthentication()
 	return postgres.CreateHBARules(
-		cluster.Spec.PostgresConfiguration.PgHBA,
+		cluster.GetPgHBA(),
+		clientCertificateRoles,
 		defaultAuthenticationMethod,
-		buildLDAPConfigString(cluster, ldapBindPassword))
+		buildLDAPConfigString(cluster, ldapBindPassword),
+		buildGSSAPIConfigString(cluster),
+		passwordAuthentication != nil && passwordAuthentication.RequireChannelBinding)
 }
 
 // RefreshPGHBA generates and writes down the pg_hba.conf file
@@ -190,6 +205,55 @@ func buildLDAPConfigString(cluster *apiv1.Cluster, ldapBindPassword string) stri
 	return ldapConfigString
 }
 
+// buildGSSAPIConfigString creates the pg_hba rule enabling the
+// GSSAPI (Kerberos) authentication, if configured
+func buildGSSAPIConfigString(cluster *apiv1.Cluster) string {
+	gssapiConfig := cluster.Spec.PostgresConfiguration.GSSAPI
+	if gssapiConfig == nil {
+		return ""
+	}
+
+	connectionType := "host"
+	if gssapiConfig.RequireEncryption {
+		connectionType = "hostgssenc"
+	}
+
+	rule := fmt.Sprintf("%s all all %s gss", connectionType, gssapiConfig.Address)
+
+	// Without user mappings the principal, stripped of the realm,
+	// must match the PostgreSQL user
+	if len(gssapiConfig.UserMappings) > 0 {
+		rule += fmt.Sprintf(" include_realm=1 map=%s", apiv1.GSSAPIIdentMapName)
+	} else {
+		rule += " include_realm=0"
+	}
+
+	if gssapiConfig.Realm != "" {
+		rule += fmt.Sprintf(" krb_realm=%s", quoteHbaLiteral(gssapiConfig.Realm))
+	}
+
+	return rule
+}
+
+// buildGSSAPIIdentRules creates the pg_ident rules mapping the Kerberos
+// principals to the PostgreSQL users
+func buildGSSAPIIdentRules(cluster *apiv1.Cluster) []string {
+	gssapiConfig := cluster.Spec.PostgresConfiguration.GSSAPI
+	if gssapiConfig == nil {
+		return nil
+	}
+
+	rules := make([]string, 0, len(gssapiConfig.UserMappings))
+	for _, mapping := range gssapiConfig.UserMappings {
+		// The values are not quoted, as a quoted principal wouldn't be
+		// interpreted as a regular expression. The webhook rejects
+		// values which can't be written as a single token
+		rules = append(rules, fmt.Sprintf("%s %s %s",
+			apiv1.GSSAPIIdentMapName, mapping.Principal, mapping.User))
+	}
+	return rules
+}
+
 // quoteHbaLiteral quotes a string according to pg_hba.conf rules
 // (see https://www.postgresql.org/docs/current/auth-pg-hba-conf.html)
 func quoteHbaLiteral(literal string) string {
@@ -200,7 +264,8 @@ func quoteHbaLiteral(literal string) string {
 
 // GeneratePostgresqlIdent generates the pg_ident.conf content
 func (instance *Instance) GeneratePostgresqlIdent(cluster *apiv1.Cluster) (string, error) {
-	return postgres.CreateIdentRules(cluster.Spec.PostgresConfiguration.PgIdent,
+	return postgres.CreateIdentRules(
+		append(buildGSSAPIIdentRules(cluster), cluster.Spec.PostgresConfiguration.PgIdent...),
 		getCurrentUserOrDefaultToInsecureMapping())
 }
 
@@ -227,42 +292,58 @@ func (instance *Instance) RefreshPGIdent(cluster *apiv1.Cluster) (postgresIdentC
 // UpdateReplicaConfiguration updates the override.conf or recovery.conf file for the proper version
 // of PostgreSQL, using the specified connection string to connect to the primary server
 func UpdateReplicaConfiguration(pgData, primaryConnInfo, slotName string) (changed bool, err error) {
-	changed, err = configurePostgresOverrideConfFile(pgData, primaryConnInfo, slotName)
+	profile, err := getRecoveryProfileForPgData(pgData)
 	if err != nil {
-		return changed, err
+		return false, err
 	}
 
-	major, err := postgresutils.GetMajorVersion(pgData)
+	changed, err = writePostgresOverrideConfFile(pgData, profile, primaryConnInfo, slotName)
 	if err != nil {
-		return false, err
+		return changed, err
 	}
 
-	if major < 12 {
-		return configureRecoveryConfFile(pgData, primaryConnInfo, slotName)
+	if !profile.hasRecoveryConfigurationParameters() {
+		return configureRecoveryConfFile(pgData, profile, primaryConnInfo, slotName)
 	}
 
-	return changed, createStandbySignal(pgData)
+	return changed, profile.createStandbySignal(pgData)
 }
 
-// configureRecoveryConfFile configures replication in the recovery.conf file
-// for PostgreSQL 11 and earlier
-func configureRecoveryConfFile(pgData, primaryConnInfo, slotName string) (changed bool, err error) {
-	targetFile := path.Join(pgData, "recovery.conf")
-
-	options := map[string]string{
-		"standby_mode": "on",
+// getReplicaRecoveryParameters gets the recovery parameters of a replica
+// following the primary server with the passed connection string and
+// replication slot
+func getReplicaRecoveryParameters(primaryConnInfo, slotName string) map[string]string {
+	return map[string]string{
 		"restore_command": fmt.Sprintf(
 			"/controller/manager wal-restore --log-destination %s/%s.json %%f %%p",
 			postgres.LogPath, postgres.LogFileName),
 		"recovery_target_timeline": "latest",
+		"primary_slot_name":        slotName,
+		"primary_conninfo":         primaryConnInfo,
+	}
+}
+
+// configureRecoveryConfFile configures replication in the recovery
+// configuration file, for the versions not having recovery parameters
+// in the PostgreSQL configuration
+func configureRecoveryConfFile(
+	pgData string,
+	profile recoveryProfile,
+	primaryConnInfo, slotName string,
+) (changed bool, err error) {
+	targetFile := path.Join(pgData, profile.recoveryConfFile)
+
+	options := getReplicaRecoveryParameters(primaryConnInfo, slotName)
+	for name, value := range profile.standbyParameters {
+		options[name] = value
 	}
 
-	if slotName != "" {
-		options["primary_slot_name"] = slotName
+	if slotName == "" {
+		delete(options, "primary_slot_name")
 	}
 
-	if primaryConnInfo != "" {
-		options["primary_conninfo"] = primaryConnInfo
+	if primaryConnInfo == "" {
+		delete(options, "primary_conninfo")
 	}
 
 	changed, err = configfile.UpdatePostgresConfigurationFile(
@@ -275,7 +356,7 @@ func configureRecoveryConfFile(pgData, primaryConnInfo, slotName string) (change
 		return false, err
 	}
 	if changed {
-		log.Info("Updated replication settings", "filename", "recovery.conf")
+		log.Info("Updated replication settings", "filename", profile.recoveryConfFile)
 	}
 
 	return changed, nil
@@ -284,25 +365,27 @@ func configureRecoveryConfFile(pgData, primaryConnInfo, slotName string) (change
 // configurePostgresOverrideConfFile writes the content of override.conf file, including
 // replication information
 func configurePostgresOverrideConfFile(pgData, primaryConnInfo, slotName string) (changed bool, err error) {
-	targetFile := path.Join(pgData, constants.PostgresqlOverrideConfigurationFile)
-
-	major, err := postgresutils.GetMajorVersion(pgData)
+	profile, err := getRecoveryProfileForPgData(pgData)
 	if err != nil {
 		return false, err
 	}
 
-	options := make(map[string]string)
+	return writePostgresOverrideConfFile(pgData, profile, primaryConnInfo, slotName)
+}
 
-	// Write replication control as GUCs (from PostgreSQL 12 or above)
-	if major >= 12 {
-		options = map[string]string{
-			"restore_command": fmt.Sprintf(
-				"/controller/manager wal-restore --log-destination %s/%s.json %%f %%p",
-				postgres.LogPath, postgres.LogFileName),
-			"recovery_target_timeline": "latest",
-			"primary_slot_name":        slotName,
-			"primary_conninfo":         primaryConnInfo,
-		}
+// writePostgresOverrideConfFile writes the content of override.conf file for
+// the passed recovery profile. The replication information is included only
+// when the recovery parameters are configuration parameters
+func writePostgresOverrideConfFile(
+	pgData string,
+	profile recoveryProfile,
+	primaryConnInfo, slotName string,
+) (changed bool, err error) {
+	targetFile := path.Join(pgData, constants.PostgresqlOverrideConfigurationFile)
+
+	options := make(map[string]string)
+	if profile.hasRecoveryConfigurationParameters() {
+		options = getReplicaRecoveryParameters(primaryConnInfo, slotName)
 	}
 
 	// Ensure that override.conf file contains just the above options
@@ -318,16 +401,6 @@ func configurePostgresOverrideConfFile(pgData, primaryConnInfo, slotName string)
 	return changed, nil
 }
 
-// createStandbySignal creates a standby.signal file for PostgreSQL 12 and beyond
-func createStandbySignal(pgData string) error {
-	emptyFile, err := os.Create(filepath.Clean(filepath.Join(pgData, "standby.signal")))
-	if emptyFile != nil {
-		_ = emptyFile.Close()
-	}
-
-	return err
-}
-
 var migrateAutoConfOptions = []string{
 	"archive_mode",
 	"primary_conninfo",
@@ -411,8 +484,13 @@ func (instance *Instance) migratePostgresAutoConfFile(ctx context.Context) (bool
 }
 
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
-// used for this cluster and return it and its sha256 checksum
-func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings bool) (string, string, error) {
+// used for this cluster and return it and its sha256 checksum. The replica
+// parameters are included only when isStandby is true
+func createPostgresqlConfiguration(
+	cluster *apiv1.Cluster,
+	preserveUserSettings bool,
+	isStandby bool,
+) (string, string, error) {
 	// Extract the PostgreSQL major version
 	fromVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
@@ -422,26 +500,48 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 	info := postgres.ConfigurationInfo{
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     fromVersion,
-		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
+		UserSettings:                     cluster.GetPostgresParameters(),
 		IncludingSharedPreloadLibraries:  true,
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
 		IsReplicaCluster:                 cluster.IsReplica(),
 		IsWalArchivingDisabled:           utils.IsWalArchivingDisabled(&cluster.ObjectMeta),
 	}
 
+	if isStandby {
+		info.ReplicaSettings = cluster.Spec.PostgresConfiguration.ReplicaParameters
+	}
+
+	if cluster.Spec.PostgresConfiguration.WorkloadType != "" {
+		info.WorkloadType = string(cluster.Spec.PostgresConfiguration.WorkloadType)
+		info.WorkloadResources = getWorkloadResources(cluster)
+	}
+
+	if cluster.IsTDEEnabled() {
+		info.DataEncryptionKeyUnwrapCommand = cluster.Spec.PostgresConfiguration.TDE.GetUnwrapCommand()
+	}
+
+	if cluster.Spec.PostgresConfiguration.GSSAPI != nil {
+		info.KerberosServerKeyFile = cluster.Spec.PostgresConfiguration.GSSAPI.GetKeytabPath()
+	}
+
+	if cluster.RequiresSCRAMPasswords() {
+		info.PasswordEncryption = postgres.PasswordEncryptionSCRAM
+	}
+
 	if preserveUserSettings {
 		info.PreserveFixedSettingsFromUser = true
 	} else {
 		info.IncludingMandatory = true
 	}
 
-	// Compute the actual number of sync replicas
+	// Compute the actual number of sync replicas. The electable replicas
+	// are already consistently ordered, avoiding spurious configuration
+	// changes, and respecting their priority
 	syncReplicas, electable := cluster.GetSyncReplicasData()
 	info.SyncReplicas = syncReplicas
 	info.SyncReplicasElectable = electable
-
-	// Ensure a consistent ordering to avoid spurious configuration changes
-	sort.Strings(info.SyncReplicasElectable)
+	info.SyncReplicasMethod = strings.ToUpper(
+		string(cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.GetMethod()))
 
 	// Set cluster name
 	info.ClusterName = cluster.Name
@@ -458,6 +558,36 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 	return conf, sha256, nil
 }
 
+// getWorkloadResources gets the resources available to the instances of
+// the cluster, preferring the limits to the requests. The WAL files are
+// stored in the data volume, unless a dedicated volume is used
+func getWorkloadResources(cluster *apiv1.Cluster) postgres.WorkloadResources {
+	var result postgres.WorkloadResources
+
+	resources := cluster.Spec.Resources
+	if memory := resources.Limits.Memory(); !memory.IsZero() {
+		result.Memory = memory.Value()
+	} else if memory := resources.Requests.Memory(); !memory.IsZero() {
+		result.Memory = memory.Value()
+	}
+
+	if cpu := resources.Limits.Cpu(); !cpu.IsZero() {
+		result.CPU = cpu.Value()
+	} else if cpu := resources.Requests.Cpu(); !cpu.IsZero() {
+		result.CPU = cpu.Value()
+	}
+
+	walVolumeSize := cluster.Spec.StorageConfiguration.GetSizeOrNil()
+	if cluster.ShouldCreateWalArchiveVolume() {
+		walVolumeSize = cluster.Spec.WalStorage.GetSizeOrNil()
+	}
+	if walVolumeSize != nil {
+		result.WALVolumeSize = walVolumeSize.Value()
+	}
+
+	return result
+}
+
 // configurePostgresForImport configures Postgres to be optimized for the firt import
 // process, by writing dedicated options the override.conf file just for this phase
 func configurePostgresForImport(ctx context.Context, pgData string) (changed bool, err error) {
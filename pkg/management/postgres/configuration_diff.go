@@ -0,0 +1,243 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// pgSetting is the content of pg_settings about a configuration parameter
+type pgSetting struct {
+	setting        string
+	currentSetting string
+	context        string
+	vartype        string
+	unit           string
+}
+
+// memoryUnits are the factors of the memory units accepted by PostgreSQL
+var memoryUnits = map[string]float64{
+	"B":  1,
+	"kB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+}
+
+// timeUnits are the factors of the time units accepted by PostgreSQL
+var timeUnits = map[string]float64{
+	"us":  1,
+	"ms":  1000,
+	"s":   1000 * 1000,
+	"min": 60 * 1000 * 1000,
+	"h":   60 * 60 * 1000 * 1000,
+	"d":   24 * 60 * 60 * 1000 * 1000,
+}
+
+// DiffConfiguration compares the passed parameters with the ones the instance
+// is running with, classifying the changed ones by whether they need a restart
+// of PostgreSQL to be applied or just a reload of the configuration
+func (instance *Instance) DiffConfiguration(parameters map[string]string) (postgres.ConfigurationDiff, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return postgres.ConfigurationDiff{}, err
+	}
+
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, strings.ToLower(name))
+	}
+
+	rows, err := superUserDB.Query(
+		`SELECT name, setting, current_setting(name), context, vartype, COALESCE(unit, '')
+		FROM pg_catalog.pg_settings
+		WHERE name = ANY($1)`,
+		names)
+	if err != nil {
+		return postgres.ConfigurationDiff{}, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	settings := make(map[string]pgSetting, len(names))
+	for rows.Next() {
+		var name string
+		var setting pgSetting
+		if err := rows.Scan(
+			&name,
+			&setting.setting,
+			&setting.currentSetting,
+			&setting.context,
+			&setting.vartype,
+			&setting.unit,
+		); err != nil {
+			return postgres.ConfigurationDiff{}, err
+		}
+		settings[name] = setting
+	}
+	if err := rows.Err(); err != nil {
+		return postgres.ConfigurationDiff{}, err
+	}
+
+	return diffConfiguration(parameters, settings), nil
+}
+
+// diffConfiguration classifies the parameters whose value differs from the
+// current settings. Parameters unknown to the instance, like the ones of
+// a library that is not loaded yet, are assumed to require a restart
+func diffConfiguration(parameters map[string]string, settings map[string]pgSetting) postgres.ConfigurationDiff {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result postgres.ConfigurationDiff
+	for _, name := range names {
+		newValue := parameters[name]
+		setting, found := settings[strings.ToLower(name)]
+		if found && setting.isCurrentValue(newValue) {
+			continue
+		}
+
+		change := postgres.ParameterChange{
+			Name:         name,
+			CurrentValue: setting.currentSetting,
+			NewValue:     newValue,
+			Context:      setting.context,
+		}
+		if !found || setting.context == "postmaster" {
+			result.Restart = append(result.Restart, change)
+		} else {
+			result.Reload = append(result.Reload, change)
+		}
+	}
+
+	return result
+}
+
+// isCurrentValue checks if the passed value is the one the instance is
+// running with. Numeric values are compared after being converted to the
+// unit of the setting, so that "1GB" matches a shared_buffers of 131072
+func (setting pgSetting) isCurrentValue(value string) bool {
+	switch setting.vartype {
+	case "bool":
+		value = normalizeBoolValue(value)
+	case "integer", "real":
+		if equal, ok := setting.isCurrentNumericValue(value); ok {
+			return equal
+		}
+	}
+
+	return strings.EqualFold(value, setting.setting) || strings.EqualFold(value, setting.currentSetting)
+}
+
+// isCurrentNumericValue compares the passed numeric value, with an optional
+// unit, with the setting. The second return value is false when the value
+// can't be interpreted as a number in the unit of the setting
+func (setting pgSetting) isCurrentNumericValue(value string) (bool, bool) {
+	current, err := strconv.ParseFloat(setting.setting, 64)
+	if err != nil {
+		return false, false
+	}
+
+	number, unit := splitValueUnit(value)
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return false, false
+	}
+
+	if unit != "" {
+		settingFactor, units, ok := parseSettingUnit(setting.unit)
+		if !ok {
+			return false, false
+		}
+		valueFactor, ok := units[unit]
+		if !ok {
+			return false, false
+		}
+		parsed = parsed * valueFactor / settingFactor
+	}
+
+	if setting.vartype == "integer" {
+		return math.Round(parsed) == current, true
+	}
+
+	return math.Abs(parsed-current) <= 1e-9*math.Max(math.Abs(parsed), math.Abs(current)), true
+}
+
+// splitValueUnit separates the number from the unit of the passed value
+func splitValueUnit(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	idx := strings.IndexFunc(value, func(r rune) bool {
+		return unicode.IsLetter(r) && r != 'e' && r != 'E'
+	})
+	if idx < 0 {
+		return value, ""
+	}
+
+	return strings.TrimSpace(value[:idx]), strings.TrimSpace(value[idx:])
+}
+
+// parseSettingUnit returns the factor of the unit of a setting, as reported
+// in pg_settings, and the units of the same kind. That unit can have a
+// multiplier, like the "8kB" of shared_buffers
+func parseSettingUnit(unit string) (float64, map[string]float64, bool) {
+	idx := strings.IndexFunc(unit, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	if idx < 0 {
+		return 0, nil, false
+	}
+
+	multiplier := 1.0
+	if idx > 0 {
+		parsed, err := strconv.ParseFloat(unit[:idx], 64)
+		if err != nil {
+			return 0, nil, false
+		}
+		multiplier = parsed
+	}
+
+	for _, units := range []map[string]float64{memoryUnits, timeUnits} {
+		if factor, ok := units[unit[idx:]]; ok {
+			return multiplier * factor, units, true
+		}
+	}
+
+	return 0, nil, false
+}
+
+// normalizeBoolValue converts the passed boolean value to the way
+// PostgreSQL reports it
+func normalizeBoolValue(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "yes", "1", "t", "y":
+		return "on"
+	case "off", "false", "no", "0", "f", "n":
+		return "off"
+	default:
+		return value
+	}
+}
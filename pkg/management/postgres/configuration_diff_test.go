@@ -0,0 +1,100 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("configuration diff", func() {
+	settings := map[string]pgSetting{
+		"shared_buffers": {
+			setting: "16384", currentSetting: "128MB", context: "postmaster", vartype: "integer", unit: "8kB",
+		},
+		"work_mem": {setting: "4096", currentSetting: "4MB", context: "user", vartype: "integer", unit: "kB"},
+		"checkpoint_timeout": {
+			setting: "300", currentSetting: "5min", context: "sighup", vartype: "integer", unit: "s",
+		},
+		"checkpoint_completion_target": {
+			setting: "0.9", currentSetting: "0.9", context: "sighup", vartype: "real",
+		},
+		"log_checkpoints": {setting: "on", currentSetting: "on", context: "sighup", vartype: "bool"},
+	}
+
+	It("ignores the parameters whose value doesn't change", func() {
+		diff := diffConfiguration(map[string]string{
+			"shared_buffers":  "128MB",
+			"work_mem":        "4096",
+			"log_checkpoints": "true",
+		}, settings)
+		Expect(diff).To(Equal(postgres.ConfigurationDiff{}))
+		Expect(diff.RequiresRestart()).To(BeFalse())
+	})
+
+	It("compares the values converting them to the unit of the setting", func() {
+		diff := diffConfiguration(map[string]string{
+			"shared_buffers":               "131072kB",
+			"work_mem":                     "4 MB",
+			"checkpoint_timeout":           "300000ms",
+			"checkpoint_completion_target": "0.90",
+		}, settings)
+		Expect(diff).To(Equal(postgres.ConfigurationDiff{}))
+
+		diff = diffConfiguration(map[string]string{"shared_buffers": "0.125GB"}, settings)
+		Expect(diff).To(Equal(postgres.ConfigurationDiff{}))
+	})
+
+	It("detects a changed value expressed with a different unit", func() {
+		diff := diffConfiguration(map[string]string{
+			"shared_buffers":     "1GB",
+			"checkpoint_timeout": "10min",
+		}, settings)
+		Expect(diff.Restart).To(Equal([]postgres.ParameterChange{
+			{Name: "shared_buffers", CurrentValue: "128MB", NewValue: "1GB", Context: "postmaster"},
+		}))
+		Expect(diff.Reload).To(Equal([]postgres.ParameterChange{
+			{Name: "checkpoint_timeout", CurrentValue: "5min", NewValue: "10min", Context: "sighup"},
+		}))
+	})
+
+	It("classifies the changed parameters by their context", func() {
+		diff := diffConfiguration(map[string]string{
+			"shared_buffers":  "256MB",
+			"work_mem":        "8MB",
+			"log_checkpoints": "off",
+		}, settings)
+		Expect(diff.RequiresRestart()).To(BeTrue())
+		Expect(diff.Restart).To(Equal([]postgres.ParameterChange{
+			{Name: "shared_buffers", CurrentValue: "128MB", NewValue: "256MB", Context: "postmaster"},
+		}))
+		Expect(diff.Reload).To(Equal([]postgres.ParameterChange{
+			{Name: "log_checkpoints", CurrentValue: "on", NewValue: "off", Context: "sighup"},
+			{Name: "work_mem", CurrentValue: "4MB", NewValue: "8MB", Context: "user"},
+		}))
+	})
+
+	It("assumes a restart is needed for parameters unknown to the instance", func() {
+		diff := diffConfiguration(map[string]string{"pg_stat_statements.max": "10000"}, settings)
+		Expect(diff.Restart).To(Equal([]postgres.ParameterChange{
+			{Name: "pg_stat_statements.max", NewValue: "10000"},
+		}))
+		Expect(diff.Reload).To(BeEmpty())
+	})
+})
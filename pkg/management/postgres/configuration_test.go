@@ -21,9 +21,11 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -114,6 +116,45 @@ var _ = Describe("testing the building of the ldap config string", func() {
 	})
 })
 
+var _ = Describe("testing the building of the gssapi configuration", func() {
+	newCluster := func(gssapi *apiv1.GSSAPIConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{GSSAPI: gssapi},
+			},
+		}
+	}
+
+	It("doesn't add anything without gssapi configuration", func() {
+		cluster := newCluster(nil)
+		Expect(buildGSSAPIConfigString(cluster)).To(BeEmpty())
+		Expect(buildGSSAPIIdentRules(cluster)).To(BeEmpty())
+	})
+
+	It("strips the realm when there are no user mappings", func() {
+		cluster := newCluster(&apiv1.GSSAPIConfiguration{Realm: "EXAMPLE.COM", Address: "samenet"})
+		Expect(buildGSSAPIConfigString(cluster)).To(Equal(
+			`host all all samenet gss include_realm=0 krb_realm="EXAMPLE.COM"`))
+	})
+
+	It("uses the user mappings", func() {
+		cluster := newCluster(&apiv1.GSSAPIConfiguration{
+			Address:           "10.0.0.0/8",
+			RequireEncryption: true,
+			UserMappings: []apiv1.GSSAPIUserMapping{
+				{Principal: "alice@EXAMPLE.COM", User: "app"},
+				{Principal: `/^(.*)@EXAMPLE\.COM$`, User: `\1`},
+			},
+		})
+		Expect(buildGSSAPIConfigString(cluster)).To(Equal(
+			"hostgssenc all all 10.0.0.0/8 gss include_realm=1 map=gssapi"))
+		Expect(buildGSSAPIIdentRules(cluster)).To(Equal([]string{
+			"gssapi alice@EXAMPLE.COM app",
+			`gssapi /^(.*)@EXAMPLE\.COM$ \1`,
+		}))
+	})
+})
+
 var _ = Describe("Test building of the list of temporary tablespaces", func() {
 	clusterWithoutTablespaces := apiv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -165,20 +206,158 @@ var _ = Describe("Test building of the list of temporary tablespaces", func() {
 	}
 
 	It("doesn't set temp_tablespaces if there are no declared tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithoutTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithoutTablespaces, true, false)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("temp_tablespaces"))
 	})
 
 	It("doesn't set temp_tablespaces if there are no temporary tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithoutTemporaryTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithoutTemporaryTablespaces, true, false)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("temp_tablespaces"))
 	})
 
 	It("sets temp_tablespaces when there are temporary tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithTemporaryTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithTemporaryTablespaces, true, false)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).To(ContainSubstring("temp_tablespaces = 'other_temporary_tablespace,temporary_tablespace'"))
 	})
 })
+
+var _ = Describe("Test the parameters applied only on the replicas", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configurationTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				Parameters: map[string]string{
+					"hot_standby_feedback":        "off",
+					"max_standby_streaming_delay": "30s",
+				},
+				ReplicaParameters: map[string]string{
+					"hot_standby_feedback":        "on",
+					"max_standby_streaming_delay": "-1",
+				},
+			},
+		},
+	}
+
+	It("ignores the replica parameters on the primary", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, false, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("hot_standby_feedback = 'off'"))
+		Expect(config).To(ContainSubstring("max_standby_streaming_delay = '30s'"))
+	})
+
+	It("applies the replica parameters on the standby instances", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, false, true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("hot_standby_feedback = 'on'"))
+		Expect(config).To(ContainSubstring("max_standby_streaming_delay = '-1'"))
+	})
+
+	It("generates a different configuration when the role changes", func() {
+		_, primarySha, err := createPostgresqlConfiguration(&cluster, false, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, standbySha, err := createPostgresqlConfiguration(&cluster, false, true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(primarySha).ToNot(Equal(standbySha))
+	})
+})
+
+var _ = Describe("Test the parameters tuned for the workload", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configurationTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				WorkloadType: apiv1.WorkloadTypeAnalytics,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+					corev1.ResourceCPU:    resource.MustParse("1500m"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+			StorageConfiguration: apiv1.StorageConfiguration{
+				Size: "1Gi",
+			},
+			WalStorage: &apiv1.StorageConfiguration{
+				Size: "40Gi",
+			},
+		},
+	}
+
+	It("uses the limits, then the requests, and the size of the WAL volume", func() {
+		Expect(getWorkloadResources(&cluster)).To(Equal(postgres.WorkloadResources{
+			Memory:        4 * 1024 * 1024 * 1024,
+			CPU:           2,
+			WALVolumeSize: 40 * 1024 * 1024 * 1024,
+		}))
+	})
+
+	It("tunes the configuration for the workload", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, false, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("shared_buffers = '819MB'"))
+		Expect(config).To(ContainSubstring("max_wal_size = '8192MB'"))
+	})
+
+	It("doesn't tune the configuration when no workload type is set", func() {
+		untuned := cluster.DeepCopy()
+		untuned.Spec.PostgresConfiguration.WorkloadType = ""
+		config, _, err := createPostgresqlConfiguration(untuned, false, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).ToNot(ContainSubstring("shared_buffers"))
+	})
+})
+
+var _ = Describe("Test the password authentication settings", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configurationTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				Parameters: map[string]string{
+					"password_encryption": "md5",
+				},
+			},
+		},
+	}
+
+	It("keeps the password_encryption set by the user", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, false, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("password_encryption = 'md5'"))
+	})
+
+	It("hashes the passwords with SCRAM-SHA-256 when migrating from MD5", func() {
+		migrating := cluster.DeepCopy()
+		migrating.Spec.PostgresConfiguration.PasswordAuthentication = &apiv1.PasswordAuthenticationConfiguration{
+			MigrateMD5: true,
+		}
+		config, _, err := createPostgresqlConfiguration(migrating, false, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("password_encryption = 'scram-sha-256'"))
+	})
+
+	It("only accepts SCRAM-SHA-256 over TLS when channel binding is required", func() {
+		requiring := cluster.DeepCopy()
+		requiring.Spec.ImageName = "postgres:16"
+		requiring.Spec.PostgresConfiguration.PasswordAuthentication = &apiv1.PasswordAuthenticationConfiguration{
+			RequireChannelBinding: true,
+		}
+		rules, err := NewInstance().GeneratePostgresqlHBA(requiring, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring("\nhostssl all all all scram-sha-256\n"))
+	})
+})
@@ -48,4 +48,9 @@ const (
 
 	// Startup is the name of a file that is created once during the first reconcile of an instance
 	Startup = "cnpg_initialized"
+
+	// QuarantineFile is the name of the file, in the data directory, marking
+	// an instance that failed the startup consistency checks. It contains
+	// the description of the failure
+	QuarantineFile = "cnpg_quarantined"
 )
@@ -0,0 +1,226 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ErrDiagnosticQueriesDisabled is returned when a diagnostic query is
+// requested but the diagnostic queries are not enabled in the cluster
+var ErrDiagnosticQueriesDisabled = errors.New("diagnostic queries are not enabled")
+
+// ErrEmptyDiagnosticQuery is returned when a diagnostic query is empty
+var ErrEmptyDiagnosticQuery = errors.New("empty diagnostic query")
+
+// diagnosticsTracker keeps the configuration of the diagnostic queries
+type diagnosticsTracker struct {
+	mu     sync.Mutex
+	config *apiv1.DiagnosticQueriesConfiguration
+}
+
+// setConfiguration replaces the configuration of the diagnostic queries
+func (tracker *diagnosticsTracker) setConfiguration(config *apiv1.DiagnosticQueriesConfiguration) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.config = config.DeepCopy()
+}
+
+// getConfiguration gets the configuration of the diagnostic queries
+func (tracker *diagnosticsTracker) getConfiguration() *apiv1.DiagnosticQueriesConfiguration {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.config.DeepCopy()
+}
+
+// SetDiagnosticQueriesConfiguration sets the configuration of the
+// diagnostic queries, as defined in the cluster
+func (instance *Instance) SetDiagnosticQueriesConfiguration(config *apiv1.DiagnosticQueriesConfiguration) {
+	instance.diagnostics.setConfiguration(config)
+}
+
+// DiagnosticsConnectionPool gets or initializes the connection pool used to
+// run the diagnostic queries, which connects with the unprivileged role
+// created for them
+func (instance *Instance) DiagnosticsConnectionPool() *pool.ConnectionPool {
+	const applicationName = "cnpg-diagnostics"
+	instance.diagnosticsPoolMutex.Lock()
+	defer instance.diagnosticsPoolMutex.Unlock()
+
+	if instance.diagnosticsPool == nil {
+		dsn := fmt.Sprintf(
+			"host=%s port=%v user=%v sslmode=disable application_name=%v",
+			GetSocketDir(),
+			GetServerPort(),
+			apiv1.DiagnosticsUserName,
+			applicationName,
+		)
+
+		instance.diagnosticsPool = pool.NewPostgresqlConnectionPool(dsn)
+	}
+
+	return instance.diagnosticsPool
+}
+
+// RunDiagnosticQuery executes a diagnostic query in a read-only transaction,
+// applying the statement timeout and the maximum number of rows configured
+// in the cluster. Every query is logged, together with its outcome and the
+// caller claimed by the client, which isn't verified: the authoritative
+// identity is in the audit record of the port-forward in the API server
+func (instance *Instance) RunDiagnosticQuery(
+	ctx context.Context,
+	request postgres.DiagnosticQueryRequest,
+) (*postgres.DiagnosticQueryResult, error) {
+	config := instance.diagnostics.getConfiguration()
+	if !config.IsEnabled() {
+		return nil, ErrDiagnosticQueriesDisabled
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		return nil, ErrEmptyDiagnosticQuery
+	}
+
+	database := request.Database
+	if database == "" {
+		database = "postgres"
+	}
+
+	db, err := instance.DiagnosticsConnectionPool().Connection(database)
+	if err != nil {
+		return nil, fmt.Errorf("while connecting to database %s: %w", database, err)
+	}
+
+	start := time.Now()
+	result, err := runDiagnosticQuery(ctx, db, request.Query, config)
+
+	contextLogger := log.FromContext(ctx).WithValues(
+		"claimedCaller", request.Caller,
+		"database", database,
+		"query", request.Query,
+		"duration", time.Since(start).String(),
+	)
+	if err != nil {
+		contextLogger.Info("Diagnostic query failed", "err", err.Error())
+		return nil, err
+	}
+	contextLogger.Info("Diagnostic query executed",
+		"rows", len(result.Rows),
+		"truncated", result.Truncated)
+
+	return result, nil
+}
+
+// runDiagnosticQuery executes the query in a read-only transaction which
+// is always rolled back. The database driver uses the extended query
+// protocol, which refuses to execute more than one statement
+func runDiagnosticQuery(
+	ctx context.Context,
+	db *sql.DB,
+	query string,
+	config *apiv1.DiagnosticQueriesConfiguration,
+) (*postgres.DiagnosticQueryResult, error) {
+	timeout := config.GetStatementTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx,
+		fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	maxRows := config.GetMaxRows()
+	result := &postgres.DiagnosticQueryResult{
+		Columns: columns,
+		Rows:    make([][]*string, 0),
+	}
+	for rows.Next() {
+		if len(result.Rows) == maxRows {
+			result.Truncated = true
+			break
+		}
+
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make([]*string, len(columns))
+		for i, value := range values {
+			row[i] = formatDiagnosticValue(value)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// formatDiagnosticValue gets the text representation of a value
+// read from the database, or nil for NULL
+func formatDiagnosticValue(value any) *string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		text = string(v)
+	case time.Time:
+		text = v.Format(time.RFC3339Nano)
+	default:
+		text = fmt.Sprint(v)
+	}
+	return &text
+}
@@ -0,0 +1,68 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("diagnostic queries", func() {
+	It("refuses the queries when they are not enabled", func(ctx SpecContext) {
+		instance := &Instance{}
+		_, err := instance.RunDiagnosticQuery(ctx, postgres.DiagnosticQueryRequest{Query: "SELECT 1"})
+		Expect(err).To(MatchError(ErrDiagnosticQueriesDisabled))
+	})
+
+	It("refuses empty queries", func(ctx SpecContext) {
+		instance := &Instance{}
+		instance.SetDiagnosticQueriesConfiguration(&apiv1.DiagnosticQueriesConfiguration{Enabled: true})
+		_, err := instance.RunDiagnosticQuery(ctx, postgres.DiagnosticQueryRequest{Query: "  "})
+		Expect(err).To(MatchError(ErrEmptyDiagnosticQuery))
+	})
+
+	It("runs the query in a read-only transaction, truncating the rows", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SET LOCAL statement_timeout = 5000").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT datname, datconnlimit FROM pg_database").
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "datconnlimit"}).
+				AddRow("postgres", nil).
+				AddRow("app", int64(10)).
+				AddRow("template1", int64(-1)))
+		mock.ExpectRollback()
+
+		result, err := runDiagnosticQuery(ctx, db, "SELECT datname, datconnlimit FROM pg_database",
+			&apiv1.DiagnosticQueriesConfiguration{Enabled: true, StatementTimeout: 5, MaxRows: 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Columns).To(Equal([]string{"datname", "datconnlimit"}))
+		Expect(result.Rows).To(HaveLen(2))
+		Expect(*result.Rows[0][0]).To(Equal("postgres"))
+		Expect(result.Rows[0][1]).To(BeNil())
+		Expect(*result.Rows[1][1]).To(Equal("10"))
+		Expect(result.Truncated).To(BeTrue())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
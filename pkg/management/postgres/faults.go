@@ -0,0 +1,166 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ErrInjectedFault is the error reported by the operations failing
+// because of a fault injected in the instance manager
+var ErrInjectedFault = errors.New("injected fault")
+
+// injectedFault is a fault which is active in the instance manager
+type injectedFault struct {
+	fault     postgres.Fault
+	delay     time.Duration
+	expiresAt time.Time
+}
+
+// isExpired checks whether the fault is not active anymore
+func (fault *injectedFault) isExpired(now time.Time) bool {
+	return !fault.expiresAt.IsZero() && !now.Before(fault.expiresAt)
+}
+
+// injectedFaults tracks the faults injected in the instance manager,
+// at most one for each type
+type injectedFaults struct {
+	mu     sync.Mutex
+	faults map[postgres.FaultType]*injectedFault
+}
+
+// inject activates a fault at the passed time, replacing the
+// one of the same type, if any
+func (faults *injectedFaults) inject(fault postgres.Fault, now time.Time) (postgres.Fault, error) {
+	if err := fault.Validate(); err != nil {
+		return postgres.Fault{}, err
+	}
+	delay, _ := fault.GetDelay()
+	duration, _ := fault.GetDuration()
+
+	item := &injectedFault{fault: fault, delay: delay}
+	item.fault.ExpiresAt = ""
+	if duration > 0 {
+		item.expiresAt = now.Add(duration)
+		item.fault.ExpiresAt = item.expiresAt.UTC().Format(time.RFC3339)
+	}
+
+	faults.mu.Lock()
+	defer faults.mu.Unlock()
+
+	if faults.faults == nil {
+		faults.faults = make(map[postgres.FaultType]*injectedFault)
+	}
+	faults.faults[fault.Type] = item
+	return item.fault, nil
+}
+
+// remove deactivates the fault of the passed type, or
+// every fault if the type is empty
+func (faults *injectedFaults) remove(faultType postgres.FaultType) {
+	faults.mu.Lock()
+	defer faults.mu.Unlock()
+
+	if faultType == "" {
+		faults.faults = nil
+		return
+	}
+	delete(faults.faults, faultType)
+}
+
+// get gets the fault of the passed type if it is active at the passed time
+func (faults *injectedFaults) get(faultType postgres.FaultType, now time.Time) *injectedFault {
+	faults.mu.Lock()
+	defer faults.mu.Unlock()
+
+	faults.prune(now)
+	return faults.faults[faultType]
+}
+
+// list gets the faults active at the passed time, sorted by type
+func (faults *injectedFaults) list(now time.Time) []postgres.Fault {
+	faults.mu.Lock()
+	defer faults.mu.Unlock()
+
+	faults.prune(now)
+	result := make([]postgres.Fault, 0, len(faults.faults))
+	for _, item := range faults.faults {
+		result = append(result, item.fault)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Type < result[j].Type
+	})
+	return result
+}
+
+// prune removes the faults which expired
+func (faults *injectedFaults) prune(now time.Time) {
+	for faultType, item := range faults.faults {
+		if item.isExpired(now) {
+			delete(faults.faults, faultType)
+		}
+	}
+}
+
+// InjectFault activates a fault in the instance manager, replacing
+// the one of the same type, and returns it as it has been recorded
+func (instance *Instance) InjectFault(fault postgres.Fault) (postgres.Fault, error) {
+	return instance.injectedFaults.inject(fault, time.Now())
+}
+
+// RemoveInjectedFault deactivates the fault of the passed type,
+// or every fault if the type is empty
+func (instance *Instance) RemoveInjectedFault(faultType postgres.FaultType) {
+	instance.injectedFaults.remove(faultType)
+}
+
+// GetInjectedFaults gets the faults which are active in the instance manager
+func (instance *Instance) GetInjectedFaults() []postgres.Fault {
+	return instance.injectedFaults.list(time.Now())
+}
+
+// CheckInjectedFault returns an error wrapping ErrInjectedFault
+// if a fault of the passed type is active
+func (instance *Instance) CheckInjectedFault(faultType postgres.FaultType) error {
+	if instance.injectedFaults.get(faultType, time.Now()) == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInjectedFault, faultType)
+}
+
+// WaitInjectedFault waits for the delay of the fault of the passed
+// type, if it is active, or until the context is done
+func (instance *Instance) WaitInjectedFault(ctx context.Context, faultType postgres.FaultType) {
+	fault := instance.injectedFaults.get(faultType, time.Now())
+	if fault == nil || fault.delay == 0 {
+		return
+	}
+
+	timer := time.NewTimer(fault.delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
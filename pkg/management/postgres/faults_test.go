@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("injected faults", func() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	It("replaces the fault of the same type and removes the expired ones", func() {
+		var faults injectedFaults
+		_, err := faults.inject(postgres.Fault{Type: postgres.FaultTypeSlowStatus, Delay: "10s"}, now)
+		Expect(err).ToNot(HaveOccurred())
+		fault, err := faults.inject(postgres.Fault{
+			Type:     postgres.FaultTypeSlowStatus,
+			Delay:    "20s",
+			Duration: "1m",
+		}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fault.ExpiresAt).To(Equal("2024-01-01T12:01:00Z"))
+
+		Expect(faults.list(now)).To(HaveLen(1))
+		Expect(faults.get(postgres.FaultTypeSlowStatus, now).delay).To(Equal(20 * time.Second))
+		Expect(faults.get(postgres.FaultTypeSlowStatus, now.Add(time.Minute))).To(BeNil())
+		Expect(faults.list(now)).To(BeEmpty())
+	})
+
+	It("removes the faults on request", func() {
+		var faults injectedFaults
+		for _, faultType := range []postgres.FaultType{
+			postgres.FaultTypeWALArchive,
+			postgres.FaultTypeLivenessProbe,
+			postgres.FaultTypeReadinessProbe,
+		} {
+			_, err := faults.inject(postgres.Fault{Type: faultType}, now)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		faults.remove(postgres.FaultTypeLivenessProbe)
+		Expect(faults.list(now)).To(Equal([]postgres.Fault{
+			{Type: postgres.FaultTypeReadinessProbe},
+			{Type: postgres.FaultTypeWALArchive},
+		}))
+
+		faults.remove("")
+		Expect(faults.list(now)).To(BeEmpty())
+	})
+
+	It("refuses the invalid faults", func() {
+		var faults injectedFaults
+		_, err := faults.inject(postgres.Fault{Type: postgres.FaultTypeSlowStatus}, now)
+		Expect(err).To(HaveOccurred())
+		Expect(faults.list(now)).To(BeEmpty())
+	})
+
+	It("delays the operations until the context is done", func(ctx SpecContext) {
+		instance := NewInstance()
+		_, err := instance.InjectFault(postgres.Fault{Type: postgres.FaultTypeSlowStatus, Delay: "1h"})
+		Expect(err).ToNot(HaveOccurred())
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		instance.WaitInjectedFault(waitCtx, postgres.FaultTypeSlowStatus)
+		Expect(time.Since(start)).To(BeNumerically("<", time.Minute))
+		Expect(instance.CheckInjectedFault(postgres.FaultTypeSlowStatus)).To(MatchError(ErrInjectedFault))
+		Expect(instance.CheckInjectedFault(postgres.FaultTypeWALArchive)).To(Succeed())
+	})
+})
@@ -29,6 +29,7 @@ import (
 	"sort"
 
 	"github.com/jackc/pgx/v5"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -430,17 +431,45 @@ func executeLogicalImport(
 	}
 	defer originPool.ShutdownConnections()
 
+	report := newLogicalImportProgressReporter(client, cluster)
+
 	cloneType := cluster.Spec.Bootstrap.InitDB.Import.Type
 	switch cloneType {
 	case apiv1.MicroserviceSnapshotType:
-		return logicalimport.Microservice(ctx, cluster, destinationPool, originPool)
+		return logicalimport.Microservice(ctx, cluster, destinationPool, originPool, report)
 	case apiv1.MonolithSnapshotType:
-		return logicalimport.Monolith(ctx, cluster, destinationPool, originPool)
+		return logicalimport.Monolith(ctx, cluster, destinationPool, originPool, report)
 	default:
 		return fmt.Errorf("unrecognized clone type %s", cloneType)
 	}
 }
 
+// newLogicalImportProgressReporter creates a reporter storing the
+// progress of the logical import in the status of the cluster. Failing to
+// report the progress doesn't interrupt the import
+func newLogicalImportProgressReporter(
+	client ctrl.Client,
+	cluster *apiv1.Cluster,
+) logicalimport.ProgressReporter {
+	return func(ctx context.Context, status apiv1.LogicalImportStatus) {
+		contextLogger := log.FromContext(ctx)
+
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			var livingCluster apiv1.Cluster
+			if err := client.Get(ctx, ctrl.ObjectKeyFromObject(cluster), &livingCluster); err != nil {
+				return err
+			}
+
+			updatedCluster := livingCluster.DeepCopy()
+			updatedCluster.Status.LogicalImport = &status
+			return client.Status().Patch(ctx, updatedCluster, ctrl.MergeFrom(&livingCluster))
+		})
+		if err != nil {
+			contextLogger.Warning("Cannot report the progress of the logical import", "err", err.Error())
+		}
+	}
+}
+
 func getConnectionPoolerForExternalCluster(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -28,6 +28,7 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/blang/semver"
@@ -138,6 +139,13 @@ type Instance struct {
 	// Pool of DB connections pointing to primary instance
 	primaryPool *pool.ConnectionPool
 
+	// Dedicated connection used by the probes, kept open across checks
+	probeDB *sql.DB
+
+	// Protects the probe connection, which is used concurrently
+	// by the probes
+	probeDBMutex sync.Mutex
+
 	// The namespace of the k8s object representing this cluster
 	Namespace string
 
@@ -175,6 +183,10 @@ type Instance struct {
 	// SmartStopDelay is used to control PostgreSQL smart shutdown timeout
 	SmartStopDelay int32
 
+	// FastStopDelay is used to control PostgreSQL fast shutdown timeout,
+	// after which an immediate shutdown is requested
+	FastStopDelay int32
+
 	// RequiresDesignatedPrimaryTransition indicates if this instance is a primary that needs to become
 	// a designatedPrimary
 	RequiresDesignatedPrimaryTransition bool
@@ -191,6 +203,9 @@ type Instance struct {
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
 
+	// hibernating specifies whether the cluster is being hibernated
+	hibernating atomic.Bool
+
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
 
@@ -199,6 +214,46 @@ type Instance struct {
 
 	// tablespaceSynchronizerChan is used to send tablespace configuration to the tablespace synchronizer
 	tablespaceSynchronizerChan chan map[string]apiv1.TablespaceConfiguration
+
+	// walArchiveStatistics collects the batches archived by the archive command
+	walArchiveStatistics walArchiveStatistics
+
+	// walRestoreStatistics scores the WAL files fetched by the restore command
+	walRestoreStatistics walRestoreStatistics
+
+	// injectedFaults tracks the faults injected to test how the
+	// operator and the monitoring system react to them
+	injectedFaults injectedFaults
+
+	// runningBackups tracks the running backup commands, whose
+	// credentials are refreshed when they are rotated
+	runningBackups sync.Map
+
+	// shutdownStatus tracks the progress of the shutdown procedure
+	shutdownStatus shutdownStatusTracker
+
+	// lifecycleHooks tracks the lifecycle hooks and the results
+	// of their last execution
+	lifecycleHooks lifecycleHooksTracker
+
+	// startupCheck tracks the configuration of the startup consistency
+	// checks and the result of their last execution
+	startupCheck startupCheckTracker
+
+	// postmasterRestart tracks the configuration of the restarts of
+	// PostgreSQL and the unexpected exits of the postmaster
+	postmasterRestart postmasterRestartTracker
+
+	// tde remembers the passphrase wrapping the data encryption key
+	tde tdeTracker
+
+	// diagnostics keeps the configuration of the diagnostic queries
+	diagnostics diagnosticsTracker
+
+	// diagnosticsPool is the connection pool used by the diagnostic
+	// queries, which are executed concurrently with the reconciliation loop
+	diagnosticsPool      *pool.ConnectionPool
+	diagnosticsPoolMutex sync.Mutex
 }
 
 // SetAlterSystemEnabled allows or deny the usage of the
@@ -252,6 +307,16 @@ func (instance *Instance) SetMightBeUnavailable(enabled bool) {
 	instance.mightBeUnavailable.Store(enabled)
 }
 
+// IsHibernating checks whether the cluster is being hibernated
+func (instance *Instance) IsHibernating() bool {
+	return instance.hibernating.Load()
+}
+
+// SetHibernating marks whether the cluster is being hibernated
+func (instance *Instance) SetHibernating(enabled bool) {
+	instance.hibernating.Store(enabled)
+}
+
 // ConfigureSlotReplicator sends the configuration to the slot replicator
 func (instance *Instance) ConfigureSlotReplicator(config *apiv1.ReplicationSlotsConfiguration) {
 	go func() {
@@ -409,6 +474,7 @@ func (instance *Instance) Startup() error {
 		return fmt.Errorf("error starting PostgreSQL instance: %w", err)
 	}
 
+	instance.shutdownStatus.reset()
 	return nil
 }
 
@@ -420,6 +486,12 @@ func (instance *Instance) ShutdownConnections() {
 	if instance.primaryPool != nil {
 		instance.primaryPool.ShutdownConnections()
 	}
+	instance.diagnosticsPoolMutex.Lock()
+	if instance.diagnosticsPool != nil {
+		instance.diagnosticsPool.ShutdownConnections()
+	}
+	instance.diagnosticsPoolMutex.Unlock()
+	instance.shutdownProbeConnection()
 }
 
 // Shutdown shuts down a PostgreSQL instance which was previously started
@@ -467,14 +539,43 @@ func (instance *Instance) Shutdown(options shutdownOptions) error {
 	return nil
 }
 
+// switchWALBeforeHibernation completes the current WAL file on the primary
+// instance, so that it is archived while PostgreSQL is shutting down and the
+// object store contains every change made before the cluster is hibernated
+func (instance *Instance) switchWALBeforeHibernation(ctx context.Context) {
+	contextLogger := log.FromContext(ctx)
+
+	isPrimary, err := instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return
+	}
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		contextLogger.Warning("Cannot switch WAL before hibernation", "err", err)
+		return
+	}
+
+	contextLogger.Info("Switching WAL before hibernating the cluster")
+	if err := checkpointAndSwitchWAL(db); err != nil {
+		contextLogger.Warning("Cannot switch WAL before hibernation", "err", err)
+	}
+}
+
 // TryShuttingDownSmartFast first tries to shut down the instance with mode smart,
 // then in case of failure or the given timeout expiration,
-// it will issue a fast shutdown request and wait for it to complete.
+// it will issue a fast shutdown request. If the fast shutdown doesn't complete
+// within its timeout, an immediate shutdown is requested.
+// N.B. immediate shutdown requires crash recovery at the next start.
 func (instance *Instance) TryShuttingDownSmartFast(ctx context.Context) error {
 	contextLogger := log.FromContext(ctx)
 
 	var err error
 
+	if instance.IsHibernating() {
+		instance.switchWALBeforeHibernation(ctx)
+	}
+
 	smartTimeout := instance.SmartStopDelay
 	if instance.MaxStopDelay <= instance.SmartStopDelay {
 		contextLogger.Warning("Ignoring maxStopDelay <= smartShutdownTimeout",
@@ -486,6 +587,7 @@ func (instance *Instance) TryShuttingDownSmartFast(ctx context.Context) error {
 
 	if smartTimeout > 0 {
 		contextLogger.Info("Requesting smart shutdown of the PostgreSQL instance")
+		instance.setShutdownPhase(postgres.ShutdownPhaseSmart, smartTimeout)
 		err = instance.Shutdown(shutdownOptions{
 			Mode:    shutdownModeSmart,
 			Wait:    true,
@@ -497,17 +599,14 @@ func (instance *Instance) TryShuttingDownSmartFast(ctx context.Context) error {
 	}
 
 	if err != nil || smartTimeout == 0 {
-		contextLogger.Info("Requesting fast shutdown of the PostgreSQL instance")
-		err = instance.Shutdown(shutdownOptions{
-			Mode: shutdownModeFast,
-			Wait: true,
-		})
+		err = instance.tryShuttingDownFastImmediate(ctx, instance.FastStopDelay)
 	}
 	if err != nil {
 		contextLogger.Error(err, "Error while shutting down the PostgreSQL instance")
 		return err
 	}
 
+	instance.setShutdownPhase(postgres.ShutdownPhaseCompleted, 0)
 	contextLogger.Info("PostgreSQL instance shut down")
 	return nil
 }
@@ -517,18 +616,36 @@ func (instance *Instance) TryShuttingDownSmartFast(ctx context.Context) error {
 // it will issue an immediate shutdown request and wait for it to complete.
 // N.B. immediate shutdown can cause data loss.
 func (instance *Instance) TryShuttingDownFastImmediate(ctx context.Context) error {
+	if err := instance.tryShuttingDownFastImmediate(ctx, instance.MaxSwitchoverDelay); err != nil {
+		return err
+	}
+
+	instance.setShutdownPhase(postgres.ShutdownPhaseCompleted, 0)
+	return nil
+}
+
+// tryShuttingDownFastImmediate requests a fast shutdown, escalating to an
+// immediate one if it doesn't complete within the passed timeout. A zero
+// timeout means the default one of pg_ctl
+func (instance *Instance) tryShuttingDownFastImmediate(ctx context.Context, fastTimeout int32) error {
 	contextLogger := log.FromContext(ctx)
 
+	options := shutdownOptions{
+		Mode: shutdownModeFast,
+		Wait: true,
+	}
+	if fastTimeout > 0 {
+		options.Timeout = &fastTimeout
+	}
+
 	contextLogger.Info("Requesting fast shutdown of the PostgreSQL instance")
-	err := instance.Shutdown(shutdownOptions{
-		Mode:    shutdownModeFast,
-		Wait:    true,
-		Timeout: &instance.MaxSwitchoverDelay,
-	})
+	instance.setShutdownPhase(postgres.ShutdownPhaseFast, fastTimeout)
+	err := instance.Shutdown(options)
 	var exitError *exec.ExitError
 	if errors.As(err, &exitError) {
 		contextLogger.Info("Graceful shutdown failed. Issuing immediate shutdown",
 			"exitCode", exitError.ExitCode())
+		instance.setShutdownPhase(postgres.ShutdownPhaseImmediate, 0)
 		err = instance.Shutdown(shutdownOptions{
 			Mode: shutdownModeImmediate,
 			Wait: true,
@@ -687,6 +804,46 @@ func (instance *Instance) GetPgVersion() (semver.Version, error) {
 	return *parsedVersion, nil
 }
 
+// GetProbeDB gets the connection dedicated to the probes. Differently from the
+// connection pool, it keeps its only connection open between probes, so that
+// the instance manager holds on to the superuser slot it got and the probes
+// don't fail when every other connection slot is in use
+func (instance *Instance) GetProbeDB() (*sql.DB, error) {
+	instance.probeDBMutex.Lock()
+	defer instance.probeDBMutex.Unlock()
+
+	if instance.probeDB != nil {
+		return instance.probeDB, nil
+	}
+
+	db, err := pool.NewDBConnection(
+		instance.ConnectionPool().GetDsn("postgres"),
+		pool.ConnectionProfilePostgresql,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the probe connection: %w", err)
+	}
+
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxIdleTime(0)
+	db.SetConnMaxLifetime(0)
+
+	instance.probeDB = db
+	return db, nil
+}
+
+// shutdownProbeConnection closes the connection dedicated to the probes
+func (instance *Instance) shutdownProbeConnection() {
+	instance.probeDBMutex.Lock()
+	defer instance.probeDBMutex.Unlock()
+
+	if instance.probeDB != nil {
+		_ = instance.probeDB.Close()
+		instance.probeDB = nil
+	}
+}
+
 // ConnectionPool gets or initializes the connection pool for this instance
 func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
 	const applicationName = "cnpg-instance-manager"
@@ -716,24 +873,18 @@ func (instance *Instance) PrimaryConnectionPool() *pool.ConnectionPool {
 }
 
 // IsPrimary check if the data directory belongs to a primary server or to a
-// secondary one by looking for a "standby.signal" file inside the data
-// directory. IMPORTANT: this method also works when the instance is not
-// started up
+// secondary one by looking for the files asking the postmaster to start as
+// a standby, such as "standby.signal", inside the data directory.
+// IMPORTANT: this method also works when the instance is not started up
 func (instance *Instance) IsPrimary() (bool, error) {
-	result, err := fileutils.FileExists(filepath.Join(instance.PgData, "standby.signal"))
-	if err != nil {
-		return false, err
-	}
-	if result {
-		return false, nil
-	}
-
-	result, err = fileutils.FileExists(filepath.Join(instance.PgData, "recovery.conf"))
-	if err != nil {
-		return false, err
-	}
-	if result {
-		return false, nil
+	for _, signalFile := range getStandbySignalFiles() {
+		result, err := fileutils.FileExists(filepath.Join(instance.PgData, signalFile))
+		if err != nil {
+			return false, err
+		}
+		if result {
+			return false, nil
+		}
 	}
 
 	return true, nil
@@ -224,6 +224,14 @@ var _ = Describe("check atomic bool", func() {
 		unAvailable = instance.MightBeUnavailable()
 		Expect(unAvailable).To(BeTrue())
 	})
+
+	It("should recognize whether the cluster is being hibernated based on the setting", func() {
+		instance.SetHibernating(false)
+		Expect(instance.IsHibernating()).To(BeFalse())
+
+		instance.SetHibernating(true)
+		Expect(instance.IsHibernating()).To(BeTrue())
+	})
 })
 
 var _ = Describe("ALTER SYSTEM enable and disable", func() {
@@ -18,9 +18,13 @@ package postgres
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"time"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
@@ -30,9 +34,17 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// CloneOptions contains the options of pg_basebackup when
+// cloning an existing server
+type CloneOptions struct {
+	// The maximum transfer rate, as accepted by `--max-rate`.
+	// Empty means no limit
+	MaxRate string
+}
+
 // ClonePgData clones an existing server, given its connection string,
 // to a certain data directory
-func ClonePgData(connectionString, targetPgData, walDir string) error {
+func ClonePgData(connectionString, targetPgData, walDir string, cloneOptions CloneOptions) error {
 	log.Info("Waiting for server to be available", "connectionString", connectionString)
 
 	db, err := pool.NewDBConnection(connectionString, pool.ConnectionProfilePostgresqlPhysicalReplication)
@@ -59,6 +71,10 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 		options = append(options, "--waldir", walDir)
 	}
 
+	if cloneOptions.MaxRate != "" {
+		options = append(options, "--max-rate", cloneOptions.MaxRate)
+	}
+
 	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
 	err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
 	if err != nil {
@@ -90,7 +106,17 @@ func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 		return err
 	}
 
-	if err = ClonePgData(primaryConnInfo, info.PgData, info.PgWal); err != nil {
+	cloneConfiguration := cluster.Spec.ReplicaClone
+	err = cloneWithRetries(
+		cloneConfiguration.GetMaxAttempts(),
+		cloneConfiguration.GetRetryDelay(),
+		func() error {
+			return ClonePgData(primaryConnInfo, info.PgData, info.PgWal,
+				CloneOptions{MaxRate: cloneConfiguration.GetMaxRate()})
+		},
+		info.removePartialClone,
+	)
+	if err != nil {
 		return err
 	}
 
@@ -98,3 +124,65 @@ func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
 	return err
 }
+
+// cloneWithRetries runs the passed clone function up to maxAttempts times,
+// cleaning up the partially cloned data before every new attempt
+func cloneWithRetries(
+	maxAttempts int,
+	retryDelay time.Duration,
+	clone func() error,
+	cleanup func() error,
+) error {
+	for attempt := 1; ; attempt++ {
+		err := clone()
+		if err == nil || attempt >= maxAttempts {
+			return err
+		}
+
+		log.Warning("Error while cloning the primary, retrying",
+			"attempt", attempt,
+			"maxAttempts", maxAttempts,
+			"retryDelay", retryDelay,
+			"err", err)
+
+		if err := cleanup(); err != nil {
+			return fmt.Errorf("while removing the partially cloned data: %w", err)
+		}
+
+		time.Sleep(retryDelay)
+	}
+}
+
+// removePartialClone removes the data directory and the WAL directory
+// left by a failed clone, so that pg_basebackup can be run again
+func (info InitInfo) removePartialClone() error {
+	if err := os.RemoveAll(info.PgData); err != nil {
+		return err
+	}
+
+	if info.PgWal != "" {
+		return os.RemoveAll(info.PgWal)
+	}
+
+	return nil
+}
+
+// RemoveInterruptedClone removes the data directory left by a join whose
+// pod has been interrupted while pg_basebackup was running, which would
+// otherwise prevent the next pod of the job from cloning the primary.
+// pg_basebackup transfers the control file last, so a data directory
+// without it has never been completely cloned
+func (info InitInfo) RemoveInterruptedClone() error {
+	pgDataExists, err := fileutils.FileExists(info.PgData)
+	if err != nil || !pgDataExists {
+		return err
+	}
+
+	controlFileExists, err := fileutils.FileExists(filepath.Join(info.PgData, "global", "pg_control"))
+	if err != nil || controlFileExists {
+		return err
+	}
+
+	log.Info("Removing the data directory left by an interrupted clone", "pgdata", info.PgData)
+	return info.removePartialClone()
+}
@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clone with retries", func() {
+	errClone := errors.New("connection reset by peer")
+
+	It("doesn't retry a successful clone", func() {
+		var clones, cleanups int
+		err := cloneWithRetries(3, 0,
+			func() error { clones++; return nil },
+			func() error { cleanups++; return nil })
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clones).To(Equal(1))
+		Expect(cleanups).To(BeZero())
+	})
+
+	It("retries after cleaning up the partial clone", func() {
+		var clones, cleanups int
+		err := cloneWithRetries(3, 0,
+			func() error {
+				clones++
+				if clones < 3 {
+					return errClone
+				}
+				return nil
+			},
+			func() error { cleanups++; return nil })
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clones).To(Equal(3))
+		Expect(cleanups).To(Equal(2))
+	})
+
+	It("gives up after the maximum number of attempts", func() {
+		var clones int
+		err := cloneWithRetries(2, 0,
+			func() error { clones++; return errClone },
+			func() error { return nil })
+		Expect(err).To(MatchError(errClone))
+		Expect(clones).To(Equal(2))
+	})
+
+	It("stops when the cleanup fails", func() {
+		var clones int
+		err := cloneWithRetries(3, 0,
+			func() error { clones++; return errClone },
+			func() error { return errors.New("permission denied") })
+		Expect(err).To(MatchError(ContainSubstring("permission denied")))
+		Expect(clones).To(Equal(1))
+	})
+
+	It("removes the data and WAL directories of a partial clone", func() {
+		tempDir := GinkgoT().TempDir()
+		info := InitInfo{
+			PgData: filepath.Join(tempDir, "pgdata"),
+			PgWal:  filepath.Join(tempDir, "pg_wal"),
+		}
+		Expect(os.MkdirAll(filepath.Join(info.PgData, "base"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(info.PgWal, 0o700)).To(Succeed())
+
+		Expect(info.removePartialClone()).To(Succeed())
+		Expect(info.PgData).ToNot(BeAnExistingFile())
+		Expect(info.PgWal).ToNot(BeAnExistingFile())
+	})
+
+	It("removes the data directory of an interrupted clone", func() {
+		tempDir := GinkgoT().TempDir()
+		info := InitInfo{PgData: filepath.Join(tempDir, "pgdata")}
+		Expect(os.MkdirAll(filepath.Join(info.PgData, "base"), 0o700)).To(Succeed())
+
+		Expect(info.RemoveInterruptedClone()).To(Succeed())
+		Expect(info.PgData).ToNot(BeAnExistingFile())
+		Expect(info.RemoveInterruptedClone()).To(Succeed())
+	})
+
+	It("keeps a completely cloned data directory", func() {
+		tempDir := GinkgoT().TempDir()
+		info := InitInfo{PgData: filepath.Join(tempDir, "pgdata")}
+		Expect(os.MkdirAll(filepath.Join(info.PgData, "global"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(info.PgData, "global", "pg_control"), nil, 0o600)).To(Succeed())
+
+		Expect(info.RemoveInterruptedClone()).To(Succeed())
+		Expect(info.PgData).To(BeADirectory())
+	})
+})
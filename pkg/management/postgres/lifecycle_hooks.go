@@ -0,0 +1,299 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// lifecycleHookWaitDelay is the time we wait for the output of a
+	// script to be closed after the script itself has been terminated
+	lifecycleHookWaitDelay = 5 * time.Second
+
+	// lifecycleHookMaxMessageLength is the maximum length of the
+	// output of a failed script that is reported in the status
+	lifecycleHookMaxMessageLength = 256
+)
+
+// LifecycleHook is a lifecycle hook whose content has been read
+// from the referenced ConfigMap
+type LifecycleHook struct {
+	// The name of the hook
+	Name string
+
+	// The point of the lifecycle where the hook is executed
+	Point apiv1.LifecycleHookPoint
+
+	// The SQL script to be executed, if this is a SQL hook
+	SQL string
+
+	// The shell script to be executed, if this is a script hook
+	Script string
+
+	// The database where the SQL script is executed
+	Database string
+
+	// The time after which the hook is cancelled
+	Timeout time.Duration
+
+	// The error raised while reading the content of the hook, which
+	// is reported as the hook result every time the hook is executed
+	ResolutionError error
+}
+
+// lifecycleHooksTracker keeps the lifecycle hooks of the instance and
+// the results of their last execution
+type lifecycleHooksTracker struct {
+	mu      sync.Mutex
+	hooks   map[apiv1.LifecycleHookPoint][]LifecycleHook
+	results []postgres.LifecycleHookResult
+
+	// The points whose hooks are being executed in the background,
+	// or have been executed since the last reset, by StartLifecycleHooks
+	started   map[apiv1.LifecycleHookPoint]bool
+	completed map[apiv1.LifecycleHookPoint]bool
+}
+
+// setHooks replaces the hooks to be executed
+func (tracker *lifecycleHooksTracker) setHooks(hooks []LifecycleHook) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.hooks = make(map[apiv1.LifecycleHookPoint][]LifecycleHook)
+	for _, hook := range hooks {
+		tracker.hooks[hook.Point] = append(tracker.hooks[hook.Point], hook)
+	}
+}
+
+// getHooks gets the hooks to be executed at the passed point
+func (tracker *lifecycleHooksTracker) getHooks(point apiv1.LifecycleHookPoint) []LifecycleHook {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return append([]LifecycleHook(nil), tracker.hooks[point]...)
+}
+
+// record stores the result of the execution of a hook, replacing
+// the result of the previous execution of the same hook
+func (tracker *lifecycleHooksTracker) record(result postgres.LifecycleHookResult) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	for i := range tracker.results {
+		if tracker.results[i].Name == result.Name && tracker.results[i].Point == result.Point {
+			tracker.results[i] = result
+			return
+		}
+	}
+	tracker.results = append(tracker.results, result)
+}
+
+// start marks the hooks of the passed point as started, unless they
+// already are. Returns whether they have been started and whether they
+// have been completed
+func (tracker *lifecycleHooksTracker) start(point apiv1.LifecycleHookPoint) (alreadyStarted, completed bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if tracker.started[point] {
+		return true, tracker.completed[point]
+	}
+
+	if tracker.started == nil {
+		tracker.started = make(map[apiv1.LifecycleHookPoint]bool)
+		tracker.completed = make(map[apiv1.LifecycleHookPoint]bool)
+	}
+	tracker.started[point] = true
+	return false, false
+}
+
+// complete marks the hooks of the passed point as completed
+func (tracker *lifecycleHooksTracker) complete(point apiv1.LifecycleHookPoint) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.completed[point] = true
+}
+
+// reset allows the hooks of the passed point to be started again,
+// unless they are still being executed
+func (tracker *lifecycleHooksTracker) reset(point apiv1.LifecycleHookPoint) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if tracker.started[point] && !tracker.completed[point] {
+		return
+	}
+	delete(tracker.started, point)
+	delete(tracker.completed, point)
+}
+
+// getResults gets the results of the last execution of the hooks
+func (tracker *lifecycleHooksTracker) getResults() []postgres.LifecycleHookResult {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return append([]postgres.LifecycleHookResult(nil), tracker.results...)
+}
+
+// SetLifecycleHooks replaces the lifecycle hooks executed by this instance
+func (instance *Instance) SetLifecycleHooks(hooks []LifecycleHook) {
+	instance.lifecycleHooks.setHooks(hooks)
+}
+
+// GetLifecycleHookResults gets the results of the last execution
+// of the lifecycle hooks of this instance
+func (instance *Instance) GetLifecycleHookResults() []postgres.LifecycleHookResult {
+	return instance.lifecycleHooks.getResults()
+}
+
+// StartLifecycleHooks executes the hooks of the passed point in the
+// background, so that the caller is not blocked while they run. The hooks
+// are executed only once, until ResetLifecycleHooks is called for the same
+// point. Returns true when the hooks have been completed
+func (instance *Instance) StartLifecycleHooks(ctx context.Context, point apiv1.LifecycleHookPoint) bool {
+	if len(instance.lifecycleHooks.getHooks(point)) == 0 {
+		return true
+	}
+
+	alreadyStarted, completed := instance.lifecycleHooks.start(point)
+	if alreadyStarted {
+		return completed
+	}
+
+	hooksCtx := context.WithoutCancel(ctx)
+	go func() {
+		defer instance.lifecycleHooks.complete(point)
+		instance.RunLifecycleHooks(hooksCtx, point)
+	}()
+	return false
+}
+
+// ResetLifecycleHooks allows the hooks of the passed point to be
+// executed again by StartLifecycleHooks
+func (instance *Instance) ResetLifecycleHooks(point apiv1.LifecycleHookPoint) {
+	instance.lifecycleHooks.reset(point)
+}
+
+// RunLifecycleHooks sequentially executes the hooks of the passed point
+// of the lifecycle. Failing hooks are logged and reported in the
+// instance status, but never interrupt the lifecycle of the instance
+func (instance *Instance) RunLifecycleHooks(ctx context.Context, point apiv1.LifecycleHookPoint) {
+	contextLogger := log.FromContext(ctx).WithValues("point", point)
+
+	for _, hook := range instance.lifecycleHooks.getHooks(point) {
+		hookLogger := contextLogger.WithValues("hook", hook.Name)
+		hookLogger.Info("Executing lifecycle hook")
+
+		startedAt := time.Now()
+		err := instance.executeLifecycleHook(ctx, hook)
+		result := postgres.LifecycleHookResult{
+			Name:      hook.Name,
+			Point:     string(point),
+			StartedAt: startedAt.UTC().Format(time.RFC3339),
+			Duration:  int32(time.Since(startedAt).Seconds()),
+			Succeeded: err == nil,
+		}
+		if err != nil {
+			result.Message = err.Error()
+			hookLogger.Error(err, "Lifecycle hook failed")
+		} else {
+			hookLogger.Info("Lifecycle hook completed")
+		}
+		instance.lifecycleHooks.record(result)
+	}
+}
+
+// executeLifecycleHook executes a hook, cancelling it when it lasts
+// longer than its timeout
+func (instance *Instance) executeLifecycleHook(ctx context.Context, hook LifecycleHook) error {
+	if hook.ResolutionError != nil {
+		return hook.ResolutionError
+	}
+
+	hookCtx, cancel := context.WithTimeout(ctx, hook.Timeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case hook.SQL != "":
+		err = instance.executeLifecycleHookSQL(hookCtx, hook)
+	case hook.Script != "":
+		err = instance.executeLifecycleHookScript(hookCtx, hook)
+	}
+
+	if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %v", hook.Timeout)
+	}
+	return err
+}
+
+// executeLifecycleHookSQL executes the SQL script of a hook as the superuser
+func (instance *Instance) executeLifecycleHookSQL(ctx context.Context, hook LifecycleHook) error {
+	db, err := instance.ConnectionPool().Connection(hook.Database)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %q: %w", hook.Database, err)
+	}
+
+	_, err = db.ExecContext(ctx, hook.SQL)
+	return err
+}
+
+// executeLifecycleHookScript executes the shell script of a hook, passing
+// the name of the hook and the point of the lifecycle in the environment
+func (instance *Instance) executeLifecycleHookScript(ctx context.Context, hook LifecycleHook) error {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", hook.Script) // #nosec G204
+	cmd.Dir = instance.PgData
+	cmd.Env = append(os.Environ(),
+		"PGDATA="+instance.PgData,
+		"CNPG_HOOK_NAME="+hook.Name,
+		"CNPG_HOOK_POINT="+string(hook.Point),
+	)
+	cmd.WaitDelay = lifecycleHookWaitDelay
+	compatibility.KillProcessGroupOnCancel(cmd)
+
+	output, err := cmd.CombinedOutput()
+	log.FromContext(ctx).Info("Lifecycle hook output",
+		"hook", hook.Name,
+		"point", hook.Point,
+		"output", string(output))
+	if err != nil {
+		message := strings.TrimSpace(string(output))
+		if len(message) > lifecycleHookMaxMessageLength {
+			message = message[len(message)-lifecycleHookMaxMessageLength:]
+		}
+		if message == "" {
+			return err
+		}
+		return fmt.Errorf("%w: %s", err, message)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lifecycle hooks", func() {
+	var instance *Instance
+
+	BeforeEach(func() {
+		instance = &Instance{PgData: GinkgoT().TempDir()}
+	})
+
+	runHook := func(hook LifecycleHook) {
+		instance.SetLifecycleHooks([]LifecycleHook{hook})
+		instance.RunLifecycleHooks(context.Background(), hook.Point)
+	}
+
+	It("only executes the hooks of the requested point", func() {
+		instance.SetLifecycleHooks([]LifecycleHook{
+			{Name: "first", Point: apiv1.LifecycleHookPreStart, Script: "true", Timeout: time.Second},
+			{Name: "second", Point: apiv1.LifecycleHookPreStop, Script: "true", Timeout: time.Second},
+		})
+		instance.RunLifecycleHooks(context.Background(), apiv1.LifecycleHookPreStart)
+
+		results := instance.GetLifecycleHookResults()
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Name).To(Equal("first"))
+		Expect(results[0].Point).To(Equal("preStart"))
+		Expect(results[0].Succeeded).To(BeTrue())
+		Expect(results[0].StartedAt).ToNot(BeEmpty())
+	})
+
+	It("passes the hook details to the script", func() {
+		runHook(LifecycleHook{
+			Name:    "env",
+			Point:   apiv1.LifecycleHookPostStart,
+			Script:  `test "$CNPG_HOOK_NAME" = env && test "$CNPG_HOOK_POINT" = postStart && test "$PGDATA" = "$(pwd)"`,
+			Timeout: 5 * time.Second,
+		})
+		Expect(instance.GetLifecycleHookResults()[0].Succeeded).To(BeTrue())
+	})
+
+	It("reports the output of the failing scripts", func() {
+		runHook(LifecycleHook{
+			Name:    "failing",
+			Point:   apiv1.LifecycleHookPreStart,
+			Script:  "echo 'cannot mount the volume' >&2; exit 3",
+			Timeout: 5 * time.Second,
+		})
+
+		result := instance.GetLifecycleHookResults()[0]
+		Expect(result.Succeeded).To(BeFalse())
+		Expect(result.Message).To(ContainSubstring("exit status 3"))
+		Expect(result.Message).To(ContainSubstring("cannot mount the volume"))
+	})
+
+	It("cancels the hooks exceeding their timeout", func() {
+		runHook(LifecycleHook{
+			Name:    "slow",
+			Point:   apiv1.LifecycleHookPreStop,
+			Script:  "sleep 10",
+			Timeout: 100 * time.Millisecond,
+		})
+
+		result := instance.GetLifecycleHookResults()[0]
+		Expect(result.Succeeded).To(BeFalse())
+		Expect(result.Message).To(Equal("timed out after 100ms"))
+	})
+
+	It("reports the hooks whose content could not be read", func() {
+		runHook(LifecycleHook{
+			Name:            "missing",
+			Point:           apiv1.LifecycleHookPostPromotion,
+			ResolutionError: errors.New(`configmap "hooks" not found`),
+		})
+
+		result := instance.GetLifecycleHookResults()[0]
+		Expect(result.Succeeded).To(BeFalse())
+		Expect(result.Message).To(Equal(`configmap "hooks" not found`))
+	})
+
+	It("keeps only the result of the last execution of each hook", func() {
+		hook := LifecycleHook{Name: "hook", Point: apiv1.LifecycleHookPreDemotion, Script: "exit 1", Timeout: time.Second}
+		runHook(hook)
+		hook.Script = "true"
+		runHook(hook)
+
+		results := instance.GetLifecycleHookResults()
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Succeeded).To(BeTrue())
+	})
+
+	It("executes the hooks in the background only once until they are reset", func(ctx SpecContext) {
+		marker := filepath.Join(instance.PgData, "executions")
+		instance.SetLifecycleHooks([]LifecycleHook{{
+			Name:    "count",
+			Point:   apiv1.LifecycleHookPreDemotion,
+			Script:  "echo x >> executions",
+			Timeout: 5 * time.Second,
+		}})
+
+		Expect(instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion)).To(BeFalse())
+		Eventually(func() bool {
+			return instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion)
+		}).Should(BeTrue())
+		Expect(instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion)).To(BeTrue())
+		Expect(os.ReadFile(marker)).To(Equal([]byte("x\n")))
+
+		instance.ResetLifecycleHooks(apiv1.LifecycleHookPreDemotion)
+		Expect(instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion)).To(BeFalse())
+		Eventually(func() bool {
+			return instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion)
+		}).Should(BeTrue())
+		Expect(os.ReadFile(marker)).To(Equal([]byte("x\nx\n")))
+	})
+
+	It("doesn't wait when there are no hooks", func(ctx SpecContext) {
+		Expect(instance.StartLifecycleHooks(ctx, apiv1.LifecycleHookPreDemotion)).To(BeTrue())
+	})
+})
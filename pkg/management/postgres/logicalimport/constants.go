@@ -30,8 +30,10 @@ type (
 const (
 	pgDump           executable = "pg_dump"
 	pgRestore        executable = "pg_restore"
+	pgDumpAll        executable = "pg_dumpall"
 	postgresDatabase            = "postgres"
 	dumpDirectory               = specs.PgDataPath + "/dumps"
+	globalsFileName             = dumpDirectory + "/globals.sql"
 )
 
 func createDumpsDirectory() error {
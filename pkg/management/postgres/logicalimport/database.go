@@ -31,7 +31,8 @@ import (
 )
 
 type databaseSnapshotter struct {
-	cluster *apiv1.Cluster
+	cluster  *apiv1.Cluster
+	progress *progressTracker
 }
 
 func (ds *databaseSnapshotter) getDatabaseList(ctx context.Context, target pool.Pooler) ([]string, error) {
@@ -98,13 +99,14 @@ func (ds *databaseSnapshotter) exportDatabases(
 
 	for _, database := range databases {
 		contextLogger.Info("exporting database", "databaseName", database)
+		ds.progress.setStep(ctx, apiv1.LogicalImportStepExport, database)
 		dsn := target.GetDsn(database)
-		options := []string{
-			"-Fc",
+		options := ds.getDumpFormatOptions()
+		options = append(options,
 			"-f", generateFileNameForDatabase(database),
 			"-d", dsn,
 			"-v",
-		}
+		)
 		options = append(options, sectionsToExport...)
 
 		contextLogger.Info("Running pg_dump", "cmd", pgDump,
@@ -127,6 +129,7 @@ func (ds *databaseSnapshotter) importDatabases(
 	contextLogger := log.FromContext(ctx)
 
 	for _, database := range databases {
+		ds.progress.setStep(ctx, apiv1.LogicalImportStepImport, database)
 		for _, section := range ds.getSectionsToExecute() {
 			targetDatabase := target.GetDsn(database)
 			contextLogger.Info(
@@ -153,10 +156,11 @@ func (ds *databaseSnapshotter) importDatabases(
 				"-U", "postgres",
 				"-d", targetDatabase,
 				"--section", section,
-				generateFileNameForDatabase(database),
 			}
 
 			options = append(options, alwaysPresentOptions...)
+			options = append(options, ds.getRestoreJobsOptions()...)
+			options = append(options, generateFileNameForDatabase(database))
 
 			contextLogger.Info("Running pg_restore",
 				"cmd", pgRestore,
@@ -168,6 +172,7 @@ func (ds *databaseSnapshotter) importDatabases(
 				return fmt.Errorf("error while executing pg_restore, section:%s, %w", section, err)
 			}
 		}
+		ds.progress.databaseImported(ctx)
 	}
 
 	return nil
@@ -211,8 +216,9 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 			fmt.Sprintf("--role=%s", owner),
 			"-d", targetDatabase,
 			"--section", section,
-			generateFileNameForDatabase(database),
 		}
+		options = append(options, ds.getRestoreJobsOptions()...)
+		options = append(options, generateFileNameForDatabase(database))
 
 		contextLogger.Info("Running pg_restore",
 			"cmd", pgRestore,
@@ -291,6 +297,7 @@ func (ds *databaseSnapshotter) analyze(
 
 	for _, database := range databases {
 		contextLogger.Info(fmt.Sprintf("running analyze for database: %s", database))
+		ds.progress.setStep(ctx, apiv1.LogicalImportStepAnalyze, database)
 		db, err := target.Connection(database)
 		if err != nil {
 			return err
@@ -364,3 +371,24 @@ func (ds *databaseSnapshotter) getSectionsToExecute() []string {
 
 	return []string{preData, data, postData}
 }
+
+// getDumpFormatOptions returns the pg_dump options selecting the format
+// of the dump. The directory format is needed to export a database with
+// parallel jobs
+func (ds *databaseSnapshotter) getDumpFormatOptions() []string {
+	if jobs := ds.cluster.Spec.Bootstrap.InitDB.Import.Jobs; jobs > 1 {
+		return []string{"-Fd", fmt.Sprintf("--jobs=%d", jobs)}
+	}
+
+	return []string{"-Fc"}
+}
+
+// getRestoreJobsOptions returns the pg_restore options setting the
+// number of parallel jobs
+func (ds *databaseSnapshotter) getRestoreJobsOptions() []string {
+	if jobs := ds.cluster.Spec.Bootstrap.InitDB.Import.Jobs; jobs > 1 {
+		return []string{fmt.Sprintf("--jobs=%d", jobs)}
+	}
+
+	return nil
+}
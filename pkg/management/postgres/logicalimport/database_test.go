@@ -129,6 +129,41 @@ var _ = Describe("databaseSnapshotter methods test", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should report the progress of analyze", func() {
+		var steps []apiv1.LogicalImportStatus
+		ds.progress = newProgressTracker(func(_ context.Context, status apiv1.LogicalImportStatus) {
+			steps = append(steps, status)
+		})
+
+		mock.ExpectExec("ANALYZE VERBOSE").WillReturnResult(sqlmock.NewResult(0, 0))
+		err := ds.analyze(ctx, fp, []string{"test"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(steps).To(HaveLen(1))
+		Expect(steps[0].Step).To(Equal(apiv1.LogicalImportStepAnalyze))
+		Expect(steps[0].CurrentDatabase).To(Equal("test"))
+	})
+
+	Context("parallel jobs", func() {
+		BeforeEach(func() {
+			ds.cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Import: &apiv1.Import{},
+				},
+			}
+		})
+
+		It("should use the custom format without parallel jobs", func() {
+			Expect(ds.getDumpFormatOptions()).To(Equal([]string{"-Fc"}))
+			Expect(ds.getRestoreJobsOptions()).To(BeEmpty())
+		})
+
+		It("should use the directory format with parallel jobs", func() {
+			ds.cluster.Spec.Bootstrap.InitDB.Import.Jobs = 4
+			Expect(ds.getDumpFormatOptions()).To(Equal([]string{"-Fd", "--jobs=4"}))
+			Expect(ds.getRestoreJobsOptions()).To(Equal([]string{"--jobs=4"}))
+		})
+	})
+
 	Context("dropExtensionsFromDatabase testing", func() {
 		var expectedQuery *sqlmock.ExpectedQuery
 
@@ -0,0 +1,276 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"k8s.io/utils/strings/slices"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+const identifierPattern = `("(?:[^"]|"")+"|[^\s;"]+)`
+
+var (
+	// roleStatementRegex matches the statements creating, altering or
+	// commenting a role
+	roleStatementRegex = regexp.MustCompile(`^(?:CREATE|ALTER|COMMENT ON) ROLE ` + identifierPattern)
+
+	// roleAttributesRegex matches the statements setting the attributes
+	// of a role
+	roleAttributesRegex = regexp.MustCompile(`^ALTER ROLE ` + identifierPattern + ` WITH `)
+
+	// createTablespaceRegex matches the statements creating a tablespace
+	createTablespaceRegex = regexp.MustCompile(
+		`^CREATE TABLESPACE ` + identifierPattern + ` OWNER ` + identifierPattern + ` LOCATION `)
+
+	// tablespaceStatementRegex matches the statements altering, commenting
+	// or granting privileges on a tablespace
+	tablespaceStatementRegex = regexp.MustCompile(
+		`^(?:ALTER TABLESPACE|COMMENT ON TABLESPACE|(?:GRANT|REVOKE) .+ ON TABLESPACE) ` + identifierPattern)
+
+	// granteeRegex matches the role receiving the privileges of a GRANT statement
+	granteeRegex = regexp.MustCompile(`^GRANT .+ TO ` + identifierPattern)
+
+	// privilegedAttributesRegex matches the role attributes that are
+	// not imported
+	privilegedAttributesRegex = regexp.MustCompile(`\b(SUPERUSER|REPLICATION)\b`)
+)
+
+// exportGlobals writes into the passed file the global objects of the
+// PostgreSQL instance reachable with the passed DSN, i.e. the roles with
+// their memberships and settings, and the tablespaces
+func exportGlobals(ctx context.Context, dsn string, fileName string) error {
+	contextLogger := log.FromContext(ctx)
+
+	options := []string{
+		"--globals-only",
+		"-f", fileName,
+		"-d", dsn,
+	}
+
+	contextLogger.Info("Running pg_dumpall", "cmd", pgDumpAll,
+		"options", options)
+	pgDumpAllCommand := exec.Command(pgDumpAll, options...) // #nosec
+	if err := execlog.RunStreaming(pgDumpAllCommand, pgDumpAll); err != nil {
+		return fmt.Errorf("error in pg_dumpall, %w", err)
+	}
+
+	return nil
+}
+
+type globalsManager struct {
+	cluster *apiv1.Cluster
+}
+
+func cloneGlobals(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	destination pool.Pooler,
+	origin pool.Pooler,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if err := createDumpsDirectory(); err != nil {
+		return err
+	}
+
+	// The export contains the password hashes of the roles, and
+	// must not outlive the import
+	defer func() {
+		if err := os.Remove(globalsFileName); err != nil && !os.IsNotExist(err) {
+			contextLogger.Error(err, "while removing the export of the global objects")
+		}
+	}()
+
+	if err := exportGlobals(ctx, origin.GetDsn(postgresDatabase), globalsFileName); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(globalsFileName) // #nosec
+	if err != nil {
+		return err
+	}
+
+	gm := globalsManager{cluster: cluster}
+	statements, err := gm.getStatements(string(content))
+	if err != nil {
+		return err
+	}
+
+	db, err := destination.Connection(postgresDatabase)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		contextLogger.Info("executing import globals query", "query", redactPassword(statement))
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("while importing the global objects (%s): %w", redactPassword(statement), err)
+		}
+	}
+
+	return nil
+}
+
+// getStatements returns the statements of a globals export that need to
+// be applied in the destination cluster. The statements referring to the
+// roles which are not imported and to the tablespaces not declared in the
+// cluster are skipped, and the superuser and replication attributes are
+// removed from the roles, as we do in the role import.
+// The owner of the application database is created by the bootstrap, so
+// only its definition is skipped, keeping its memberships, settings and
+// privileges.
+func (gm *globalsManager) getStatements(content string) ([]string, error) {
+	rolesToImport := gm.cluster.Spec.Bootstrap.InitDB.Import.Roles
+	owner := gm.cluster.Spec.Bootstrap.InitDB.Owner
+	rolesToSkip := []string{
+		"postgres",
+		apiv1.StreamingReplicationUser,
+		apiv1.PGBouncerPoolerUserName,
+		apiv1.DiagnosticsUserName,
+	}
+	shouldImport := func(identifier string) bool {
+		name := unquoteIdentifier(identifier)
+		if name == owner {
+			return true
+		}
+		return !slices.Contains(rolesToSkip, name) && shouldImportRole(name, rolesToImport)
+	}
+	isRoleDefinition := func(statement string) bool {
+		return strings.HasPrefix(statement, "CREATE ROLE ") || roleAttributesRegex.MatchString(statement)
+	}
+
+	statements, err := splitGlobalsStatements(content)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		if match := roleStatementRegex.FindStringSubmatch(statement); match != nil {
+			if !shouldImport(match[1]) {
+				continue
+			}
+			if unquoteIdentifier(match[1]) == owner && isRoleDefinition(statement) {
+				continue
+			}
+			if roleAttributesRegex.MatchString(statement) {
+				statement = downgradeRoleAttributes(statement)
+			}
+			result = append(result, statement)
+			continue
+		}
+
+		if match := createTablespaceRegex.FindStringSubmatch(statement); match != nil {
+			// The tablespaces are created by the operator in their own
+			// volumes, so we only transfer their ownership
+			if gm.isTablespaceDeclared(match[1]) && shouldImport(match[2]) {
+				result = append(result, fmt.Sprintf("ALTER TABLESPACE %s OWNER TO %s;", match[1], match[2]))
+			}
+			continue
+		}
+
+		if match := tablespaceStatementRegex.FindStringSubmatch(statement); match != nil &&
+			!gm.isTablespaceDeclared(match[1]) {
+			continue
+		}
+
+		if match := granteeRegex.FindStringSubmatch(statement); match != nil && !shouldImport(match[1]) {
+			continue
+		}
+
+		result = append(result, statement)
+	}
+
+	return result, nil
+}
+
+func (gm *globalsManager) isTablespaceDeclared(identifier string) bool {
+	name := unquoteIdentifier(identifier)
+	for _, tablespace := range gm.cluster.Spec.Tablespaces {
+		if tablespace.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splitGlobalsStatements splits the content of a pg_dumpall export in its
+// SQL statements, skipping the comments, the session settings and the
+// psql meta-commands
+func splitGlobalsStatements(content string) ([]string, error) {
+	var statements []string
+	var current strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if current.Len() == 0 {
+			if line == "" || strings.HasPrefix(line, "--") ||
+				strings.HasPrefix(line, "SET ") || strings.HasPrefix(line, `\`) {
+				continue
+			}
+		} else {
+			current.WriteString("\n")
+		}
+
+		current.WriteString(line)
+		if strings.HasSuffix(line, ";") {
+			statements = append(statements, current.String())
+			current.Reset()
+		}
+	}
+
+	return statements, scanner.Err()
+}
+
+// downgradeRoleAttributes removes the superuser and replication attributes
+// from an ALTER ROLE statement, leaving the password untouched
+func downgradeRoleAttributes(statement string) string {
+	attributes, password, hasPassword := strings.Cut(statement, " PASSWORD ")
+	attributes = privilegedAttributesRegex.ReplaceAllString(attributes, "NO$1")
+	if !hasPassword {
+		return attributes
+	}
+	return attributes + " PASSWORD " + password
+}
+
+// redactPassword hides the password of an ALTER ROLE statement
+func redactPassword(statement string) string {
+	attributes, _, hasPassword := strings.Cut(statement, " PASSWORD ")
+	if !hasPassword {
+		return statement
+	}
+	return attributes + " PASSWORD ******;"
+}
+
+func unquoteIdentifier(identifier string) string {
+	if len(identifier) < 2 || !strings.HasPrefix(identifier, `"`) || !strings.HasSuffix(identifier, `"`) {
+		return identifier
+	}
+	return strings.ReplaceAll(identifier[1:len(identifier)-1], `""`, `"`)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("globals import", func() {
+	const export = `--
+-- PostgreSQL database cluster dump
+--
+
+SET default_transaction_read_only = off;
+
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+
+--
+-- Roles
+--
+
+CREATE ROLE app;
+ALTER ROLE app WITH NOSUPERUSER INHERIT NOCREATEROLE NOCREATEDB LOGIN NOREPLICATION NOBYPASSRLS;
+CREATE ROLE postgres;
+ALTER ROLE postgres WITH SUPERUSER INHERIT CREATEROLE CREATEDB LOGIN REPLICATION BYPASSRLS;
+CREATE ROLE "Reporting";
+ALTER ROLE "Reporting" WITH SUPERUSER INHERIT NOCREATEROLE NOCREATEDB LOGIN REPLICATION NOBYPASSRLS PASSWORD 'SCRAM-SHA-256$4096:SUPERUSER';
+COMMENT ON ROLE "Reporting" IS 'the reporting
+user';
+ALTER ROLE "Reporting" SET work_mem TO '64MB';
+CREATE ROLE skipped;
+
+--
+-- Role memberships
+--
+
+GRANT pg_read_all_data TO "Reporting" GRANTED BY postgres;
+GRANT "Reporting" TO app GRANTED BY postgres;
+GRANT pg_monitor TO skipped GRANTED BY postgres;
+
+--
+-- Tablespaces
+--
+
+CREATE TABLESPACE reports OWNER "Reporting" LOCATION '/var/lib/postgresql/tablespaces/reports';
+GRANT ALL ON TABLESPACE reports TO app;
+CREATE TABLESPACE archive OWNER "Reporting" LOCATION '/var/lib/postgresql/tablespaces/archive';
+GRANT ALL ON TABLESPACE archive TO "Reporting";
+
+--
+-- PostgreSQL database cluster dump complete
+--
+`
+
+	var gm globalsManager
+
+	BeforeEach(func() {
+		gm = globalsManager{
+			cluster: &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					Bootstrap: &apiv1.BootstrapConfiguration{
+						InitDB: &apiv1.BootstrapInitDB{
+							Owner: "app",
+							Import: &apiv1.Import{
+								Type:    apiv1.MonolithSnapshotType,
+								Roles:   []string{"app", "Reporting"},
+								Globals: true,
+							},
+						},
+					},
+					Tablespaces: []apiv1.TablespaceConfiguration{
+						{Name: "reports"},
+					},
+				},
+			},
+		}
+	})
+
+	It("splits the export in statements", func() {
+		statements, err := splitGlobalsStatements(export)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statements).To(HaveLen(16))
+		Expect(statements).To(ContainElement("COMMENT ON ROLE \"Reporting\" IS 'the reporting\nuser';"))
+	})
+
+	It("applies only the statements of the imported roles and declared tablespaces", func() {
+		statements, err := gm.getStatements(export)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statements).To(Equal([]string{
+			`CREATE ROLE "Reporting";`,
+			`ALTER ROLE "Reporting" WITH NOSUPERUSER INHERIT NOCREATEROLE NOCREATEDB LOGIN NOREPLICATION ` +
+				`NOBYPASSRLS PASSWORD 'SCRAM-SHA-256$4096:SUPERUSER';`,
+			"COMMENT ON ROLE \"Reporting\" IS 'the reporting\nuser';",
+			`ALTER ROLE "Reporting" SET work_mem TO '64MB';`,
+			`GRANT pg_read_all_data TO "Reporting" GRANTED BY postgres;`,
+			`GRANT "Reporting" TO app GRANTED BY postgres;`,
+			`ALTER TABLESPACE reports OWNER TO "Reporting";`,
+			`GRANT ALL ON TABLESPACE reports TO app;`,
+		}))
+	})
+
+	It("keeps the memberships and privileges of the application owner", func() {
+		gm.cluster.Spec.Bootstrap.InitDB.Import.Roles = []string{"Reporting"}
+		statements, err := gm.getStatements(export)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statements).To(ContainElements(
+			`GRANT "Reporting" TO app GRANTED BY postgres;`,
+			`GRANT ALL ON TABLESPACE reports TO app;`,
+		))
+		Expect(statements).ToNot(ContainElement(HavePrefix("CREATE ROLE app")))
+		Expect(statements).ToNot(ContainElement(HavePrefix("ALTER ROLE app WITH")))
+	})
+
+	It("imports all the roles with a wildcard", func() {
+		gm.cluster.Spec.Bootstrap.InitDB.Import.Roles = []string{"*"}
+		statements, err := gm.getStatements(export)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statements).To(ContainElements(
+			"CREATE ROLE skipped;",
+			"GRANT pg_monitor TO skipped GRANTED BY postgres;",
+		))
+		Expect(statements).ToNot(ContainElement(ContainSubstring("ROLE postgres")))
+	})
+
+	It("doesn't log the passwords", func() {
+		Expect(redactPassword(`ALTER ROLE app WITH LOGIN PASSWORD 'secret';`)).
+			To(Equal(`ALTER ROLE app WITH LOGIN PASSWORD ******;`))
+		Expect(redactPassword(`CREATE ROLE app;`)).To(Equal(`CREATE ROLE app;`))
+	})
+
+	It("unquotes the identifiers", func() {
+		Expect(unquoteIdentifier(`"My ""Role"""`)).To(Equal(`My "Role"`))
+		Expect(unquoteIdentifier(`app`)).To(Equal(`app`))
+	})
+})
@@ -24,18 +24,24 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 )
 
-// Microservice executes the microservice clone type
+// Microservice executes the microservice clone type, passing its progress
+// to the reporter
 func Microservice(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	destination pool.Pooler,
 	origin pool.Pooler,
+	report ProgressReporter,
 ) error {
 	contextLogger := log.FromContext(ctx)
-	ds := databaseSnapshotter{cluster: cluster}
+	progress := newProgressTracker(report)
+	ds := databaseSnapshotter{cluster: cluster, progress: progress}
 	databases := cluster.Spec.Bootstrap.InitDB.Import.Databases
 	contextLogger.Info("starting microservice clone process")
 
+	progress.start(ctx)
+	progress.setTotalDatabases(len(databases))
+
 	if err := createDumpsDirectory(); err != nil {
 		return nil
 	}
@@ -44,6 +50,7 @@ func Microservice(
 		return err
 	}
 
+	progress.setStep(ctx, apiv1.LogicalImportStepImport, databases[0])
 	if err := ds.dropExtensionsFromDatabase(ctx, destination, cluster.Spec.Bootstrap.InitDB.Database); err != nil {
 		return err
 	}
@@ -57,6 +64,7 @@ func Microservice(
 	); err != nil {
 		return err
 	}
+	progress.databaseImported(ctx)
 
 	if err := cleanDumpDirectory(); err != nil {
 		return err
@@ -66,5 +74,10 @@ func Microservice(
 		return err
 	}
 
-	return ds.analyze(ctx, destination, []string{cluster.Spec.Bootstrap.InitDB.Database})
+	if err := ds.analyze(ctx, destination, []string{cluster.Spec.Bootstrap.InitDB.Database}); err != nil {
+		return err
+	}
+
+	progress.complete(ctx)
+	return nil
 }
@@ -24,29 +24,41 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 )
 
-// Monolith executes the monolith clone type
+// Monolith executes the monolith clone type, passing its progress
+// to the reporter
 func Monolith(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	destination pool.Pooler,
 	origin pool.Pooler,
+	report ProgressReporter,
 ) error {
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Info("starting monolith clone process")
 
-	if err := cloneRoles(ctx, cluster, destination, origin); err != nil {
-		return err
-	}
+	progress := newProgressTracker(report)
+	progress.start(ctx)
 
-	if err := cloneRoleInheritance(ctx, destination, origin); err != nil {
-		return err
+	if cluster.Spec.Bootstrap.InitDB.Import.Globals {
+		if err := cloneGlobals(ctx, cluster, destination, origin); err != nil {
+			return err
+		}
+	} else {
+		if err := cloneRoles(ctx, cluster, destination, origin); err != nil {
+			return err
+		}
+
+		if err := cloneRoleInheritance(ctx, destination, origin); err != nil {
+			return err
+		}
 	}
 
-	ds := databaseSnapshotter{cluster: cluster}
+	ds := databaseSnapshotter{cluster: cluster, progress: progress}
 	databases, err := ds.getDatabaseList(ctx, origin)
 	if err != nil {
 		return err
 	}
+	progress.setTotalDatabases(len(databases))
 
 	if err := createDumpsDirectory(); err != nil {
 		return err
@@ -64,5 +76,10 @@ func Monolith(
 		return err
 	}
 
-	return ds.analyze(ctx, destination, databases)
+	if err := ds.analyze(ctx, destination, databases); err != nil {
+		return err
+	}
+
+	progress.complete(ctx)
+	return nil
 }
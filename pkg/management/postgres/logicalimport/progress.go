@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// ProgressReporter receives the progress of the logical import every
+// time it changes
+type ProgressReporter func(ctx context.Context, status apiv1.LogicalImportStatus)
+
+// progressTracker keeps the progress of the logical import, forwarding
+// it to a ProgressReporter. A nil tracker discards the progress
+type progressTracker struct {
+	status apiv1.LogicalImportStatus
+	report ProgressReporter
+}
+
+func newProgressTracker(report ProgressReporter) *progressTracker {
+	return &progressTracker{report: report}
+}
+
+// start marks the beginning of the logical import
+func (t *progressTracker) start(ctx context.Context) {
+	if t == nil {
+		return
+	}
+
+	now := metav1.Now()
+	t.status.StartedAt = &now
+	t.status.Step = apiv1.LogicalImportStepRoles
+	t.notify(ctx)
+}
+
+// setTotalDatabases sets the number of databases to be imported
+func (t *progressTracker) setTotalDatabases(totalDatabases int) {
+	if t == nil {
+		return
+	}
+
+	t.status.TotalDatabases = totalDatabases
+}
+
+// setStep records the step being executed, and the database it is
+// processing if any
+func (t *progressTracker) setStep(ctx context.Context, step apiv1.LogicalImportStep, database string) {
+	if t == nil {
+		return
+	}
+
+	t.status.Step = step
+	t.status.CurrentDatabase = database
+	t.notify(ctx)
+}
+
+// databaseImported records the import of a database
+func (t *progressTracker) databaseImported(ctx context.Context) {
+	if t == nil {
+		return
+	}
+
+	t.status.ImportedDatabases++
+	t.notify(ctx)
+}
+
+// complete marks the end of the logical import
+func (t *progressTracker) complete(ctx context.Context) {
+	if t == nil {
+		return
+	}
+
+	now := metav1.Now()
+	t.status.CompletedAt = &now
+	t.status.Step = apiv1.LogicalImportStepCompleted
+	t.status.CurrentDatabase = ""
+	t.notify(ctx)
+}
+
+func (t *progressTracker) notify(ctx context.Context) {
+	now := metav1.Now()
+	t.status.UpdatedAt = &now
+	if t.report != nil {
+		t.report(ctx, *t.status.DeepCopy())
+	}
+}
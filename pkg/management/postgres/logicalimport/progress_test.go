@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("logical import progress", func() {
+	It("reports every change of the progress", func() {
+		var reports []apiv1.LogicalImportStatus
+		tracker := newProgressTracker(func(_ context.Context, status apiv1.LogicalImportStatus) {
+			reports = append(reports, status)
+		})
+
+		ctx := context.TODO()
+		tracker.start(ctx)
+		tracker.setTotalDatabases(2)
+		tracker.setStep(ctx, apiv1.LogicalImportStepImport, "app")
+		tracker.databaseImported(ctx)
+		tracker.complete(ctx)
+
+		Expect(reports).To(HaveLen(4))
+		Expect(reports[0].Step).To(Equal(apiv1.LogicalImportStepRoles))
+		Expect(reports[0].StartedAt).ToNot(BeNil())
+		Expect(reports[1].TotalDatabases).To(Equal(2))
+		Expect(reports[1].CurrentDatabase).To(Equal("app"))
+		Expect(reports[2].ImportedDatabases).To(Equal(1))
+		Expect(reports[3].Step).To(Equal(apiv1.LogicalImportStepCompleted))
+		Expect(reports[3].CurrentDatabase).To(BeEmpty())
+		Expect(reports[3].CompletedAt).ToNot(BeNil())
+	})
+
+	It("discards the progress without a tracker", func() {
+		var tracker *progressTracker
+		Expect(func() {
+			tracker.start(context.TODO())
+			tracker.databaseImported(context.TODO())
+		}).ToNot(Panic())
+	})
+})
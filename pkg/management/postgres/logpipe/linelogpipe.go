@@ -21,6 +21,7 @@ package logpipe
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -64,6 +65,9 @@ func NewJSONLineLogPipe(fileName string) *LineLogPipe {
 		fileName: fileName,
 		handler: func(line []byte) {
 			fmt.Println(string(line))
+			if json.Valid(line) {
+				writeToLogFiles(LoggingCollectorRecordName, json.RawMessage(line))
+			}
 		},
 		initialized: concurrency.NewExecuted(),
 		exited:      concurrency.NewExecuted(),
@@ -0,0 +1,336 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// logFilesPrefix is the prefix of the name of every log file
+	logFilesPrefix = "postgres"
+
+	// logFilesExtension is the extension of the log files, before compression
+	logFilesExtension = ".json"
+
+	// rotatedLogFileTimeFormat is the format of the timestamp included in
+	// the name of the rotated log files, which sorts them chronologically
+	rotatedLogFileTimeFormat = "20060102T150405.000"
+)
+
+// logFileEntry is a line of the log files
+type logFileEntry struct {
+	Timestamp  time.Time   `json:"ts"`
+	Logger     string      `json:"logger"`
+	LoggingPod string      `json:"logging_pod,omitempty"`
+	Record     interface{} `json:"record"`
+}
+
+// logFiles is the writer of the log files, nil when the log files
+// are not enabled
+var logFiles struct {
+	sync.Mutex
+	configuration *apiv1.LogFilesConfiguration
+	writer        *rotatingFileWriter
+}
+
+// ConfigureLogFiles starts or stops writing the PostgreSQL logs to the
+// log files, following the passed configuration. Nothing is done when
+// the configuration didn't change since the last call
+func ConfigureLogFiles(configuration *apiv1.LogFilesConfiguration) error {
+	if configuration != nil && !configuration.Enabled {
+		configuration = nil
+	}
+
+	logFiles.Lock()
+	defer logFiles.Unlock()
+
+	if reflect.DeepEqual(configuration, logFiles.configuration) {
+		return nil
+	}
+	logFiles.configuration = configuration.DeepCopy()
+
+	if logFiles.writer != nil {
+		if err := logFiles.writer.Close(); err != nil {
+			log.Warning("Error while closing the log file", "err", err)
+		}
+		logFiles.writer = nil
+	}
+
+	if configuration == nil {
+		return nil
+	}
+
+	writer, err := newRotatingFileWriter(postgres.LogFilesPath, *configuration)
+	if err != nil {
+		return err
+	}
+	logFiles.writer = writer
+	return nil
+}
+
+// writeToLogFiles writes the passed record to the log files, when enabled
+func writeToLogFiles(name string, record interface{}) {
+	logFiles.Lock()
+	defer logFiles.Unlock()
+
+	if logFiles.writer == nil {
+		return
+	}
+
+	line, err := json.Marshal(logFileEntry{
+		Timestamp:  time.Now().UTC(),
+		Logger:     name,
+		LoggingPod: os.Getenv("POD_NAME"),
+		Record:     record,
+	})
+	if err != nil {
+		log.Warning("Error while encoding a log record for the log files", "err", err)
+		return
+	}
+
+	if _, err := logFiles.writer.Write(append(line, '\n')); err != nil {
+		log.Warning("Error while writing to the log file", "err", err)
+	}
+}
+
+// rotatingFileWriter writes to a file that is rotated when it reaches
+// the maximum size or age. The rotated files are compressed and the
+// oldest ones are deleted, in background
+type rotatingFileWriter struct {
+	directory     string
+	configuration apiv1.LogFilesConfiguration
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// now returns the current time, and can be replaced in the tests
+	now func() time.Time
+
+	// housekeeping tracks the compression and the deletion of the rotated files
+	housekeeping sync.WaitGroup
+}
+
+// newRotatingFileWriter creates a writer for the log files in the passed
+// directory, which must exist
+func newRotatingFileWriter(
+	directory string,
+	configuration apiv1.LogFilesConfiguration,
+) (*rotatingFileWriter, error) {
+	if _, err := os.Stat(directory); err != nil {
+		return nil, fmt.Errorf("while checking the log files directory: %w", err)
+	}
+
+	writer := &rotatingFileWriter{
+		directory:     directory,
+		configuration: configuration,
+		now:           time.Now,
+	}
+	if err := writer.open(); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// currentFileName is the name of the log file being written
+func (w *rotatingFileWriter) currentFileName() string {
+	return filepath.Join(w.directory, logFilesPrefix+logFilesExtension)
+}
+
+// open opens the current log file, appending to it if it already exists
+func (w *rotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.currentFileName(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	return nil
+}
+
+// Write writes the passed data to the current log file, rotating it
+// before when needed
+func (w *rotatingFileWriter) Write(data []byte) (int, error) {
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(data))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	return n, err
+}
+
+// shouldRotate checks whether the current log file must be rotated
+// before writing the passed amount of data
+func (w *rotatingFileWriter) shouldRotate(length int64) bool {
+	if w.size == 0 {
+		return false
+	}
+
+	if w.size+length > w.configuration.GetMaxSize() {
+		return true
+	}
+
+	maxAge := w.configuration.GetMaxAge()
+	return maxAge > 0 && w.now().Sub(w.openedAt) >= maxAge
+}
+
+// rotate renames the current log file, including the rotation time in
+// its name, and opens a new one
+func (w *rotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	rotatedFileName := filepath.Join(
+		w.directory,
+		fmt.Sprintf("%s-%s%s", logFilesPrefix, w.now().UTC().Format(rotatedLogFileTimeFormat), logFilesExtension),
+	)
+	if err := os.Rename(w.currentFileName(), rotatedFileName); err != nil {
+		return err
+	}
+
+	w.housekeeping.Add(1)
+	go func() {
+		defer w.housekeeping.Done()
+		w.cleanRotatedFile(rotatedFileName)
+	}()
+
+	return w.open()
+}
+
+// cleanRotatedFile compresses the passed rotated file, when requested,
+// and deletes the oldest rotated files exceeding the retention
+func (w *rotatingFileWriter) cleanRotatedFile(rotatedFileName string) {
+	if w.configuration.GetCompression() == apiv1.LogFilesCompressionGzip {
+		if err := compressFile(rotatedFileName); err != nil {
+			log.Warning("Error while compressing the rotated log file",
+				"fileName", rotatedFileName, "err", err)
+		}
+	}
+
+	if err := w.deleteExceedingFiles(); err != nil {
+		log.Warning("Error while deleting the old log files", "err", err)
+	}
+}
+
+// deleteExceedingFiles deletes the oldest rotated files, keeping only
+// the configured number of them
+func (w *rotatingFileWriter) deleteExceedingFiles() error {
+	entries, err := os.ReadDir(w.directory)
+	if err != nil {
+		return err
+	}
+
+	var rotatedFiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasPrefix(name, logFilesPrefix+"-") {
+			continue
+		}
+		if strings.HasSuffix(name, logFilesExtension) || strings.HasSuffix(name, logFilesExtension+".gz") {
+			rotatedFiles = append(rotatedFiles, name)
+		}
+	}
+
+	sort.Strings(rotatedFiles)
+	exceedingFiles := len(rotatedFiles) - w.configuration.GetMaxFiles()
+	for i := 0; i < exceedingFiles; i++ {
+		if err := os.Remove(filepath.Join(w.directory, rotatedFiles[i])); err != nil &&
+			!errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the current log file, waiting for the rotated files to
+// be compressed
+func (w *rotatingFileWriter) Close() error {
+	defer w.housekeeping.Wait()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// compressFile compresses the passed file with gzip, replacing it
+func compressFile(fileName string) (err error) {
+	source, err := os.Open(fileName) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = source.Close()
+	}()
+
+	destination, err := os.OpenFile(fileName+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := destination.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	compressor := gzip.NewWriter(destination)
+	if _, err = io.Copy(compressor, source); err != nil {
+		_ = os.Remove(destination.Name())
+		return err
+	}
+	if err = compressor.Close(); err != nil {
+		_ = os.Remove(destination.Name())
+		return err
+	}
+
+	return os.Remove(fileName)
+}
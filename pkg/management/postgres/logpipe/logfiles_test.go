@@ -0,0 +1,146 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rotating log files", func() {
+	var (
+		directory string
+		now       time.Time
+	)
+
+	newWriter := func(configuration apiv1.LogFilesConfiguration) *rotatingFileWriter {
+		writer, err := newRotatingFileWriter(directory, configuration)
+		Expect(err).ToNot(HaveOccurred())
+		writer.now = func() time.Time { return now }
+		writer.openedAt = now
+		return writer
+	}
+
+	listFiles := func() []string {
+		entries, err := os.ReadDir(directory)
+		Expect(err).ToNot(HaveOccurred())
+		result := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			result = append(result, entry.Name())
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		directory = GinkgoT().TempDir()
+		now = time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	})
+
+	It("requires the directory to exist", func() {
+		_, err := newRotatingFileWriter(filepath.Join(directory, "missing"), apiv1.LogFilesConfiguration{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rotates the log file by size, compressing the rotated files", func() {
+		writer := newWriter(apiv1.LogFilesConfiguration{
+			MaxSize: ptr.To(resource.MustParse("10")),
+		})
+
+		_, err := writer.Write([]byte("123456\n"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = writer.Write([]byte("789\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		Expect(listFiles()).To(ConsistOf("postgres.json", "postgres-20240510T120000.000.json.gz"))
+
+		current, err := os.ReadFile(filepath.Join(directory, "postgres.json"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(current)).To(Equal("789\n"))
+
+		rotated, err := os.Open(filepath.Join(directory, "postgres-20240510T120000.000.json.gz"))
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			_ = rotated.Close()
+		}()
+		reader, err := gzip.NewReader(rotated)
+		Expect(err).ToNot(HaveOccurred())
+		content, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("123456\n"))
+	})
+
+	It("rotates the log file by age, keeping the configured number of files", func() {
+		writer := newWriter(apiv1.LogFilesConfiguration{
+			MaxAge:      &metav1.Duration{Duration: time.Hour},
+			MaxFiles:    ptr.To[int32](2),
+			Compression: apiv1.LogFilesCompressionNone,
+		})
+
+		for i := 0; i < 4; i++ {
+			_, err := writer.Write([]byte("line\n"))
+			Expect(err).ToNot(HaveOccurred())
+			now = now.Add(time.Hour)
+			writer.housekeeping.Wait()
+		}
+		Expect(writer.Close()).To(Succeed())
+
+		Expect(listFiles()).To(ConsistOf(
+			"postgres.json",
+			"postgres-20240510T150000.000.json",
+			"postgres-20240510T140000.000.json",
+		))
+	})
+
+	It("writes the records to the log files only while enabled", func() {
+		logFilesDirectory := directory
+
+		Expect(ConfigureLogFiles(&apiv1.LogFilesConfiguration{Enabled: false})).To(Succeed())
+		writeToLogFiles("postgres", map[string]string{"message": "discarded"})
+
+		logFiles.Lock()
+		writer, err := newRotatingFileWriter(logFilesDirectory, apiv1.LogFilesConfiguration{Enabled: true})
+		Expect(err).ToNot(HaveOccurred())
+		logFiles.writer = writer
+		logFiles.configuration = &apiv1.LogFilesConfiguration{Enabled: true}
+		logFiles.Unlock()
+
+		writeToLogFiles("pgaudit", map[string]string{"message": "kept"})
+		Expect(ConfigureLogFiles(nil)).To(Succeed())
+		Expect(logFiles.writer).To(BeNil())
+
+		content, err := os.ReadFile(filepath.Join(logFilesDirectory, "postgres.json"))
+		Expect(err).ToNot(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]).To(ContainSubstring(`"logger":"pgaudit"`))
+		Expect(lines[0]).To(ContainSubstring(`"record":{"message":"kept"}`))
+	})
+})
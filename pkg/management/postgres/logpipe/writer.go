@@ -17,6 +17,8 @@ limitations under the License.
 package logpipe
 
 import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 )
 
@@ -30,10 +32,30 @@ type RecordWriter interface {
 }
 
 // LogRecordWriter implements the `RecordWriter` interface writing to the
-// instance manager logger
+// instance manager logger. Audit records are written to the audit logger
+// when the cluster requires them on a dedicated output
 type LogRecordWriter struct{}
 
-// Write writes the PostgreSQL log record to the instance manager logger
+// Write writes the PostgreSQL log record to the instance manager logger,
+// and to the log files when enabled
 func (writer *LogRecordWriter) Write(record NamedRecord) {
-	log.WithName(record.GetName()).Info(logRecordKey, logRecordKey, record)
+	logger := log.GetLogger()
+	if record.GetName() == PgAuditRecordName && getPgAuditOutput() == apiv1.PgAuditOutputDedicated {
+		logger = log.GetAuditLogger()
+	}
+
+	logger.WithName(record.GetName()).Info(logRecordKey, logRecordKey, record)
+	writeToLogFiles(record.GetName(), record)
+}
+
+// getPgAuditOutput gets the output of the audit records from the cached
+// cluster. Until the cluster is cached, audit records are combined
+// with the rest of the logs
+func getPgAuditOutput() apiv1.PgAuditOutput {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return apiv1.PgAuditOutputCombined
+	}
+
+	return cluster.Spec.PostgresConfiguration.GetPgAuditOutput()
 }
@@ -0,0 +1,81 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Log record writer", func() {
+	var defaultLines, auditLines []string
+
+	newCapturingLogger := func(lines *[]string) logr.Logger {
+		return funcr.New(func(prefix, args string) {
+			*lines = append(*lines, prefix)
+		}, funcr.Options{})
+	}
+
+	BeforeEach(func() {
+		defaultLines = nil
+		auditLines = nil
+		previousLogger := log.GetLogger().GetLogger()
+		previousAuditLogger := log.GetAuditLogger().GetLogger()
+		log.SetLogger(newCapturingLogger(&defaultLines))
+		log.SetAuditLogger(newCapturingLogger(&auditLines))
+		DeferCleanup(func() {
+			log.SetLogger(previousLogger)
+			log.SetAuditLogger(previousAuditLogger)
+			cache.Delete(cache.ClusterKey)
+		})
+	})
+
+	storeClusterWithPgAuditOutput := func(output apiv1.PgAuditOutput) {
+		cache.StoreCluster(&apiv1.Cluster{Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				PgAudit: &apiv1.PgAuditConfiguration{Enabled: true, Output: output},
+			},
+		}})
+	}
+
+	It("combines the audit records with the other logs by default", func() {
+		writer := &LogRecordWriter{}
+		writer.Write(NewPgAuditLoggingDecorator())
+		writer.Write(&LoggingRecord{})
+
+		Expect(defaultLines).To(Equal([]string{PgAuditRecordName, LoggingCollectorRecordName}))
+		Expect(auditLines).To(BeEmpty())
+	})
+
+	It("writes the audit records on the dedicated output when requested", func() {
+		storeClusterWithPgAuditOutput(apiv1.PgAuditOutputDedicated)
+
+		writer := &LogRecordWriter{}
+		writer.Write(NewPgAuditLoggingDecorator())
+		writer.Write(&LoggingRecord{})
+
+		Expect(defaultLines).To(Equal([]string{LoggingCollectorRecordName}))
+		Expect(auditLines).To(Equal([]string{PgAuditRecordName}))
+	})
+})
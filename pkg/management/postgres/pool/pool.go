@@ -21,6 +21,7 @@ package pool
 import (
 	"database/sql"
 	"fmt"
+	"sync"
 
 	// this is needed to correctly open the sql connection with the pgx driver
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -47,8 +48,10 @@ type ConnectionPool struct {
 	// The configuration to be used
 	connectionProfile ConnectionProfile
 
-	// A map of connection for every used database
+	// A map of connection for every used database, which is
+	// shared by the goroutines of the instance manager
 	connectionMap map[string]*sql.DB
+	mu            sync.Mutex
 }
 
 // NewPostgresqlConnectionPool creates a new connectionMap of connections given
@@ -75,6 +78,9 @@ func newConnectionPool(baseConnectionString string, connectionProfile Connection
 
 // Connection gets the connection for the given database
 func (pool *ConnectionPool) Connection(dbname string) (*sql.DB, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
 	if result, ok := pool.connectionMap[dbname]; ok {
 		return result, nil
 	}
@@ -90,6 +96,9 @@ func (pool *ConnectionPool) Connection(dbname string) (*sql.DB, error) {
 
 // ShutdownConnections closes every database connection
 func (pool *ConnectionPool) ShutdownConnections() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
 	for _, db := range pool.connectionMap {
 		_ = db.Close()
 	}
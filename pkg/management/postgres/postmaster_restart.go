@@ -0,0 +1,370 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ErrPrimaryWaitingPostmasterRestart is reported in the status of a primary
+// which is waiting to be restarted after an unexpected exit, so that the
+// operator doesn't consider it healthy and can fail over
+var ErrPrimaryWaitingPostmasterRestart = errors.New(
+	"the primary is waiting for PostgreSQL to be restarted after an unexpected exit")
+
+// postmasterStableUptime is how long the postmaster needs to keep
+// running for its previous unexpected exits to be forgotten
+const postmasterStableUptime = 10 * time.Minute
+
+// oomKillCounterFiles are the cgroup files reporting how many processes
+// of the container have been killed by the OOM killer, for cgroup v2
+// and cgroup v1 respectively
+var oomKillCounterFiles = []string{
+	"/sys/fs/cgroup/memory.events",
+	"/sys/fs/cgroup/memory/memory.oom_control",
+}
+
+// postmasterRestartTracker keeps the configuration of the restarts of the
+// postmaster and the history of its unexpected exits
+type postmasterRestartTracker struct {
+	mu     sync.Mutex
+	config *apiv1.PostmasterRestartConfiguration
+	result *postgres.PostmasterRestartResult
+
+	// startTime is when the running postmaster has been started
+	startTime time.Time
+
+	// acceptingConnections is true when the running postmaster
+	// accepted connections at least once
+	acceptingConnections bool
+
+	// oomKillCount is the value of the OOM killer counter of the
+	// cgroup when the postmaster has been started, -1 if not available
+	oomKillCount int64
+
+	// waiting is true when the postmaster exited and PostgreSQL
+	// has not been started again
+	waiting bool
+
+	// restart is closed when PostgreSQL can be started again
+	restart chan struct{}
+}
+
+// setConfiguration replaces the configuration of the restarts
+func (tracker *postmasterRestartTracker) setConfiguration(config *apiv1.PostmasterRestartConfiguration) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.config = config.DeepCopy()
+}
+
+// getResult gets the history of the unexpected exits of the postmaster
+func (tracker *postmasterRestartTracker) getResult() *postgres.PostmasterRestartResult {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if tracker.result == nil {
+		return nil
+	}
+	result := *tracker.result
+	return &result
+}
+
+// isWaiting checks whether the postmaster exited and PostgreSQL
+// has not been started again
+func (tracker *postmasterRestartTracker) isWaiting() bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.waiting
+}
+
+// isPaused checks whether the postmaster exited and the
+// restarts of PostgreSQL are paused
+func (tracker *postmasterRestartTracker) isPaused() bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.waiting && tracker.result != nil && tracker.result.Paused
+}
+
+// started records that a new postmaster has been started
+func (tracker *postmasterRestartTracker) started(now time.Time, oomKillCount int64) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.startTime = now
+	tracker.acceptingConnections = false
+	tracker.oomKillCount = oomKillCount
+	tracker.waiting = false
+	tracker.restart = nil
+}
+
+// accepted records that the running postmaster accepts connections
+func (tracker *postmasterRestartTracker) accepted() {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.acceptingConnections = true
+}
+
+// recordExit classifies an exit of the postmaster and schedules the
+// restart of PostgreSQL, unless the restarts are paused. It returns
+// false when the instance manager is not in charge of restarting
+// PostgreSQL, either because the restarts are not enabled or because
+// the postmaster could not be run at all
+func (tracker *postmasterRestartTracker) recordExit(err error, now time.Time, oomKillCount int64) bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if !tracker.config.IsEnabled() {
+		return false
+	}
+
+	var exitError *exec.ExitError
+	if !errors.As(err, &exitError) {
+		return false
+	}
+
+	oomKilled := isKilledBySIGKILL(exitError) ||
+		(tracker.oomKillCount >= 0 && oomKillCount > tracker.oomKillCount)
+	reason, message := classifyPostmasterExit(exitError, tracker.acceptingConnections, oomKilled)
+
+	attempts := int32(1)
+	if tracker.result != nil && now.Sub(tracker.startTime) < postmasterStableUptime {
+		attempts = tracker.result.Attempts + 1
+	}
+
+	result := postgres.PostmasterRestartResult{
+		Attempts:       attempts,
+		LastExitReason: string(reason),
+		LastExitTime:   now.UTC().Format(time.RFC3339),
+		Message:        message,
+		Paused: reason == apiv1.PostmasterExitStartupFailed ||
+			int(attempts) >= tracker.config.GetMaxAttempts(),
+	}
+
+	restart := make(chan struct{})
+	if !result.Paused {
+		backoff := getPostmasterRestartBackoff(tracker.config, int(attempts))
+		result.NextRestartTime = now.Add(backoff).UTC().Format(time.RFC3339)
+		time.AfterFunc(backoff, func() {
+			close(restart)
+		})
+	}
+
+	tracker.result = &result
+	tracker.waiting = true
+	tracker.restart = restart
+	return true
+}
+
+// resume restarts PostgreSQL when the restarts are paused,
+// returning true if this happened
+func (tracker *postmasterRestartTracker) resume(now time.Time) bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if !tracker.waiting || tracker.result == nil || !tracker.result.Paused {
+		return false
+	}
+
+	tracker.result.Paused = false
+	tracker.result.Attempts = 0
+	tracker.result.NextRestartTime = now.UTC().Format(time.RFC3339)
+	close(tracker.restart)
+	return true
+}
+
+// getRestartChan gets the channel that will be closed when PostgreSQL
+// can be started again, nil when the postmaster is running
+func (tracker *postmasterRestartTracker) getRestartChan() <-chan struct{} {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.restart
+}
+
+// classifyPostmasterExit finds why the postmaster exited, and describes it
+func classifyPostmasterExit(
+	exitError *exec.ExitError,
+	acceptingConnections bool,
+	oomKilled bool,
+) (apiv1.PostmasterExitReason, string) {
+	switch {
+	case oomKilled:
+		return apiv1.PostmasterExitOOMKilled,
+			fmt.Sprintf("PostgreSQL has been killed by the OOM killer (%s)", exitError.Error())
+	case !acceptingConnections:
+		return apiv1.PostmasterExitStartupFailed,
+			fmt.Sprintf("PostgreSQL exited before accepting connections (%s)", exitError.Error())
+	default:
+		return apiv1.PostmasterExitCrashed,
+			fmt.Sprintf("PostgreSQL exited unexpectedly (%s)", exitError.Error())
+	}
+}
+
+// isKilledBySIGKILL checks whether a process has been terminated by
+// SIGKILL, which is what the OOM killer uses
+func isKilledBySIGKILL(exitError *exec.ExitError) bool {
+	status, ok := exitError.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == syscall.SIGKILL
+}
+
+// getPostmasterRestartBackoff gets the time to wait before starting
+// PostgreSQL after the passed number of consecutive exits
+func getPostmasterRestartBackoff(config *apiv1.PostmasterRestartConfiguration, attempts int) time.Duration {
+	backoff := config.GetInitialBackoff()
+	maxBackoff := config.GetMaxBackoff()
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// readOOMKillCount reads how many processes of the container have been
+// killed by the OOM killer, returning -1 if the counter is not available
+func readOOMKillCount(fileNames ...string) int64 {
+	for _, fileName := range fileNames {
+		count, err := readOOMKillCounterFile(fileName)
+		if err == nil {
+			return count
+		}
+	}
+	return -1
+}
+
+// readOOMKillCounterFile reads the `oom_kill` line of a cgroup memory file
+func readOOMKillCounterFile(fileName string) (int64, error) {
+	file, err := os.Open(fileName) // #nosec
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), " ")
+		if found && key == "oom_kill" {
+			return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no oom_kill counter in %s", fileName)
+}
+
+// SetPostmasterRestartConfiguration replaces the configuration of the
+// restarts of PostgreSQL after an unexpected exit of the postmaster
+func (instance *Instance) SetPostmasterRestartConfiguration(config *apiv1.PostmasterRestartConfiguration) {
+	instance.postmasterRestart.setConfiguration(config)
+}
+
+// GetPostmasterRestartResult gets the history of the unexpected exits
+// of the postmaster, nil if it never exited unexpectedly
+func (instance *Instance) GetPostmasterRestartResult() *postgres.PostmasterRestartResult {
+	return instance.postmasterRestart.getResult()
+}
+
+// IsWaitingPostmasterRestart checks whether the postmaster exited
+// unexpectedly and PostgreSQL has not been started again, either
+// because of the backoff or because the restarts are paused
+func (instance *Instance) IsWaitingPostmasterRestart() bool {
+	return instance.postmasterRestart.isWaiting()
+}
+
+// ShouldSkipProbesForPostmasterRestart checks whether the liveness and
+// startup probes must be skipped because PostgreSQL is waiting to be
+// restarted by the instance manager. This is not the case of a primary
+// whose restarts are paused: nothing would restart it, and its probes
+// fail so that the kubelet restarts the container
+func (instance *Instance) ShouldSkipProbesForPostmasterRestart() bool {
+	if !instance.postmasterRestart.isWaiting() {
+		return false
+	}
+	if !instance.postmasterRestart.isPaused() {
+		return true
+	}
+
+	isPrimary, err := instance.IsPrimary()
+	return err == nil && !isPrimary
+}
+
+// NotifyPostmasterStarted records that a new postmaster has been started
+func (instance *Instance) NotifyPostmasterStarted() {
+	instance.postmasterRestart.started(time.Now(), readOOMKillCount(oomKillCounterFiles...))
+}
+
+// NotifyPostmasterAcceptingConnections records that the running
+// postmaster accepts connections
+func (instance *Instance) NotifyPostmasterAcceptingConnections() {
+	instance.postmasterRestart.accepted()
+}
+
+// RecordPostmasterExit records an unexpected exit of the postmaster and
+// schedules the restart of PostgreSQL, returning false when the instance
+// manager is not in charge of restarting it. The returned channel
+// is closed when PostgreSQL can be started again
+func (instance *Instance) RecordPostmasterExit(ctx context.Context, err error) (<-chan struct{}, bool) {
+	contextLogger := log.FromContext(ctx).WithName("postmaster_restart")
+
+	if !instance.postmasterRestart.recordExit(err, time.Now(), readOOMKillCount(oomKillCounterFiles...)) {
+		return nil, false
+	}
+
+	result := instance.postmasterRestart.getResult()
+	if result.Paused {
+		contextLogger.Warning("PostgreSQL exited unexpectedly, restarts are paused",
+			"reason", result.LastExitReason,
+			"attempts", result.Attempts,
+			"message", result.Message)
+	} else {
+		contextLogger.Info("PostgreSQL exited unexpectedly, scheduling a restart",
+			"reason", result.LastExitReason,
+			"attempts", result.Attempts,
+			"nextRestartTime", result.NextRestartTime,
+			"message", result.Message)
+	}
+
+	return instance.postmasterRestart.getRestartChan(), true
+}
+
+// ResumePostmasterRestarts starts PostgreSQL again when its restarts are
+// paused, as it is done when the configuration changes
+func (instance *Instance) ResumePostmasterRestarts(ctx context.Context) {
+	if instance.postmasterRestart.resume(time.Now()) {
+		log.FromContext(ctx).WithName("postmaster_restart").Info(
+			"Resuming the restarts of PostgreSQL")
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("restarts of the postmaster", func() {
+	var tracker *postmasterRestartTracker
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	runShell := func(script string) error {
+		return exec.Command("sh", "-c", script).Run() // #nosec
+	}
+
+	BeforeEach(func() {
+		tracker = &postmasterRestartTracker{}
+		tracker.setConfiguration(&apiv1.PostmasterRestartConfiguration{
+			Enabled:        true,
+			InitialBackoff: 1,
+			MaxBackoff:     60,
+			MaxAttempts:    3,
+		})
+		tracker.started(now, 0)
+		tracker.accepted()
+	})
+
+	It("leaves the exits to the kubelet when not enabled", func() {
+		tracker.setConfiguration(nil)
+		Expect(tracker.recordExit(runShell("exit 1"), now, 0)).To(BeFalse())
+		Expect(tracker.getResult()).To(BeNil())
+		Expect(tracker.isWaiting()).To(BeFalse())
+	})
+
+	It("leaves the failures to run the postmaster to the kubelet", func() {
+		Expect(tracker.recordExit(errors.New("no such file or directory"), now, 0)).To(BeFalse())
+		Expect(tracker.getResult()).To(BeNil())
+	})
+
+	It("restarts a crashed postmaster after the backoff", func() {
+		Expect(tracker.recordExit(runShell("exit 1"), now.Add(time.Minute), 0)).To(BeTrue())
+		Expect(tracker.isWaiting()).To(BeTrue())
+
+		result := tracker.getResult()
+		Expect(result.Attempts).To(BeEquivalentTo(1))
+		Expect(result.LastExitReason).To(Equal(string(apiv1.PostmasterExitCrashed)))
+		Expect(result.LastExitTime).To(Equal("2024-06-01T00:01:00Z"))
+		Expect(result.NextRestartTime).To(Equal("2024-06-01T00:01:01Z"))
+		Expect(result.Message).To(ContainSubstring("exit status 1"))
+		Expect(result.Paused).To(BeFalse())
+
+		Eventually(tracker.getRestartChan()).WithTimeout(5 * time.Second).Should(BeClosed())
+	})
+
+	It("pauses the restarts after the maximum number of consecutive crashes", func() {
+		for attempt := 1; attempt <= 3; attempt++ {
+			Expect(tracker.recordExit(runShell("exit 1"), now, 0)).To(BeTrue())
+			Expect(tracker.getResult().Attempts).To(BeEquivalentTo(attempt))
+			tracker.started(now, 0)
+			tracker.accepted()
+		}
+
+		result := tracker.getResult()
+		Expect(result.Paused).To(BeTrue())
+		Expect(result.NextRestartTime).To(BeEmpty())
+	})
+
+	It("forgets the crashes of a postmaster that kept running", func() {
+		Expect(tracker.recordExit(runShell("exit 1"), now, 0)).To(BeTrue())
+		tracker.started(now, 0)
+		Expect(tracker.recordExit(runShell("exit 1"), now.Add(postmasterStableUptime), 0)).To(BeTrue())
+		Expect(tracker.getResult().Attempts).To(BeEquivalentTo(1))
+	})
+
+	It("detects the postmaster being killed by the OOM killer", func() {
+		Expect(tracker.recordExit(runShell("kill -9 $$"), now, 0)).To(BeTrue())
+		Expect(tracker.getResult().LastExitReason).To(Equal(string(apiv1.PostmasterExitOOMKilled)))
+	})
+
+	It("detects a child of the postmaster being killed by the OOM killer", func() {
+		Expect(tracker.recordExit(runShell("exit 1"), now, 1)).To(BeTrue())
+		Expect(tracker.getResult().LastExitReason).To(Equal(string(apiv1.PostmasterExitOOMKilled)))
+	})
+
+	It("pauses the restarts of a postmaster that could not start until resumed", func() {
+		tracker.started(now, 0)
+		Expect(tracker.recordExit(runShell("exit 1"), now, 0)).To(BeTrue())
+
+		result := tracker.getResult()
+		Expect(result.LastExitReason).To(Equal(string(apiv1.PostmasterExitStartupFailed)))
+		Expect(result.Paused).To(BeTrue())
+		Consistently(tracker.getRestartChan()).WithTimeout(100 * time.Millisecond).ShouldNot(BeClosed())
+
+		Expect(tracker.resume(now)).To(BeTrue())
+		Expect(tracker.getRestartChan()).To(BeClosed())
+		Expect(tracker.getResult().Paused).To(BeFalse())
+		Expect(tracker.resume(now)).To(BeFalse())
+	})
+
+	Context("on an instance waiting to be restarted", func() {
+		var instance *Instance
+
+		BeforeEach(func() {
+			instance = NewInstance()
+			instance.PgData = GinkgoT().TempDir()
+			instance.postmasterRestart.setConfiguration(&apiv1.PostmasterRestartConfiguration{
+				Enabled:        true,
+				InitialBackoff: 60,
+				MaxBackoff:     60,
+				MaxAttempts:    2,
+			})
+			instance.postmasterRestart.started(now, 0)
+			instance.postmasterRestart.accepted()
+			Expect(instance.postmasterRestart.recordExit(runShell("exit 1"), now, 0)).To(BeTrue())
+		})
+
+		It("reports a primary as failing, so that the operator can fail over", func() {
+			status, err := instance.GetStatus()
+			Expect(err).To(MatchError(ErrPrimaryWaitingPostmasterRestart))
+			Expect(status.IsPrimary).To(BeTrue())
+			Expect(status.PostmasterRestart).ToNot(BeNil())
+		})
+
+		It("reports the status of a replica", func() {
+			Expect(os.WriteFile(filepath.Join(instance.PgData, "standby.signal"), nil, 0o600)).To(Succeed())
+			status, err := instance.GetStatus()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.IsPrimary).To(BeFalse())
+		})
+
+		It("lets the probes of a primary fail once the restarts are paused", func() {
+			Expect(instance.ShouldSkipProbesForPostmasterRestart()).To(BeTrue())
+
+			Expect(instance.postmasterRestart.recordExit(runShell("exit 1"), now, 0)).To(BeTrue())
+			Expect(instance.postmasterRestart.getResult().Paused).To(BeTrue())
+			Expect(instance.ShouldSkipProbesForPostmasterRestart()).To(BeFalse())
+
+			Expect(os.WriteFile(filepath.Join(instance.PgData, "standby.signal"), nil, 0o600)).To(Succeed())
+			Expect(instance.ShouldSkipProbesForPostmasterRestart()).To(BeTrue())
+		})
+	})
+
+	It("doubles the backoff up to the maximum", func() {
+		config := &apiv1.PostmasterRestartConfiguration{InitialBackoff: 5, MaxBackoff: 60}
+		Expect(getPostmasterRestartBackoff(config, 1)).To(Equal(5 * time.Second))
+		Expect(getPostmasterRestartBackoff(config, 2)).To(Equal(10 * time.Second))
+		Expect(getPostmasterRestartBackoff(config, 4)).To(Equal(40 * time.Second))
+		Expect(getPostmasterRestartBackoff(config, 10)).To(Equal(60 * time.Second))
+	})
+
+	It("reads the OOM killer counter of the cgroup", func() {
+		dir := GinkgoT().TempDir()
+		memoryEvents := filepath.Join(dir, "memory.events")
+		Expect(os.WriteFile(memoryEvents,
+			[]byte("low 0\nhigh 0\nmax 12\noom 2\noom_kill 2\n"), 0o600)).To(Succeed())
+
+		Expect(readOOMKillCount(filepath.Join(dir, "missing"), memoryEvents)).To(BeEquivalentTo(2))
+		Expect(readOOMKillCount(filepath.Join(dir, "missing"))).To(BeEquivalentTo(-1))
+	})
+})
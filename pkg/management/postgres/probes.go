@@ -17,12 +17,14 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,12 +57,12 @@ func (instance *Instance) IsServerReady() error {
 	if !instance.CanCheckReadiness() {
 		return fmt.Errorf("instance is not ready yet")
 	}
-	superUserDB, err := instance.GetSuperUserDB()
+	probeDB, err := instance.GetProbeDB()
 	if err != nil {
 		return err
 	}
 
-	return superUserDB.Ping()
+	return probeDB.Ping()
 }
 
 // GetStatus Extract the status of this PostgreSQL database
@@ -69,6 +71,10 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		Pod:                    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: instance.PodName}},
 		InstanceManagerVersion: versions.Version,
 		MightBeUnavailable:     instance.MightBeUnavailable(),
+		LifecycleHooks:         instance.GetLifecycleHookResults(),
+		StartupCheck:           instance.GetStartupCheckResult(),
+		PostmasterRestart:      instance.GetPostmasterRestartResult(),
+		TDESecretVersion:       instance.GetTDESecretVersion(),
 	}
 
 	// this deferred function may override the error returned. Take extra care.
@@ -88,6 +94,25 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		}
 	}()
 
+	if instance.IsQuarantined() {
+		// PostgreSQL has not been started, and the result of the
+		// startup checks is all we can report together with the role
+		result.IsPrimary, err = instance.IsPrimary()
+		return result, err
+	}
+
+	if instance.IsWaitingPostmasterRestart() {
+		// PostgreSQL is waiting to be restarted after an unexpected
+		// exit, and the history of the exits is what we can report.
+		// A primary which is not running must not look healthy,
+		// otherwise the operator would never fail over
+		result.IsPrimary, err = instance.IsPrimary()
+		if err == nil && result.IsPrimary {
+			err = ErrPrimaryWaitingPostmasterRestart
+		}
+		return result, err
+	}
+
 	if instance.PgRewindIsRunning {
 		// We know that pg_rewind is running, so we exit with the proper status
 		// updated, and we can provide that information to the user.
@@ -125,6 +150,7 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		return result, err
 	}
 
+	result.WALRestoreStatus = instance.GetWALRestoreStatus()
 	result.InstanceArch = runtime.GOARCH
 
 	result.ExecutableHash, err = executablehash.Get()
@@ -271,6 +297,16 @@ func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
 		return err
 	}
 
+	if err := fillChecksumFailures(superUserDB, result); err != nil {
+		return err
+	}
+
+	if result.IsPrimary {
+		if result.MD5PasswordRoles, err = getMD5PasswordRoles(superUserDB); err != nil {
+			return err
+		}
+	}
+
 	if err := instance.fillBasebackupStats(superUserDB, result); err != nil {
 		return err
 	}
@@ -383,6 +419,115 @@ func fillArchiverStatus(superUserDB *sql.DB, result *postgres.PostgresqlStatus)
 	)
 }
 
+// fillChecksumFailures get the number of data page checksum failures
+// detected in the databases of the instance
+func fillChecksumFailures(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	row := superUserDB.QueryRow(
+		"SELECT COALESCE(SUM(checksum_failures), 0) FROM pg_catalog.pg_stat_database")
+
+	return row.Scan(&result.ChecksumFailures)
+}
+
+// getMD5PasswordRoles gets the sorted list of the roles whose password
+// is still stored as an MD5 hash
+func getMD5PasswordRoles(superUserDB *sql.DB) ([]string, error) {
+	rows, err := superUserDB.Query(
+		`SELECT rolname FROM pg_catalog.pg_authid WHERE rolpassword LIKE 'md5%' ORDER BY rolname`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		result = append(result, name)
+	}
+
+	return result, rows.Err()
+}
+
+// GetWALArchiveStatus gets the status of the WAL archiving process,
+// including the archive lag when running on the primary
+func (instance *Instance) GetWALArchiveStatus(ctx context.Context) (*postgres.WALArchiveStatus, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var result postgres.WALArchiveStatus
+	var lastArchivedTime, lastFailedTime sql.NullTime
+	var walSegmentSize int64
+	row := superUserDB.QueryRowContext(
+		ctx,
+		`
+		SELECT
+			NOT pg_catalog.pg_is_in_recovery(),
+			COALESCE(last_archived_wal, ''),
+			last_archived_time,
+			COALESCE(last_failed_wal, ''),
+			last_failed_time,
+			archived_count,
+			failed_count,
+			COALESCE(last_archived_time,'-infinity') > COALESCE(last_failed_time, '-infinity') AS is_archiving,
+			(SELECT setting::bigint FROM pg_catalog.pg_settings WHERE name = 'wal_segment_size')
+		FROM pg_catalog.pg_stat_archiver
+		`)
+	if err := row.Scan(
+		&result.IsPrimary,
+		&result.LastArchivedWAL,
+		&lastArchivedTime,
+		&result.LastFailedWAL,
+		&lastFailedTime,
+		&result.ArchivedCount,
+		&result.FailedCount,
+		&result.IsArchivingWAL,
+		&walSegmentSize,
+	); err != nil {
+		return nil, fmt.Errorf("while reading pg_stat_archiver: %w", err)
+	}
+
+	if lastArchivedTime.Valid {
+		result.LastArchivedWALTime = lastArchivedTime.Time.Format(time.RFC3339)
+	}
+	if lastFailedTime.Valid {
+		result.LastFailedWALTime = lastFailedTime.Time.Format(time.RFC3339)
+	}
+
+	if result.ReadyWALFiles, _, err = GetWALArchiveCounters(); err != nil {
+		return nil, err
+	}
+	instance.walArchiveStatistics.fill(&result, time.Now())
+
+	if !result.IsPrimary {
+		return &result, nil
+	}
+
+	row = superUserDB.QueryRowContext(
+		ctx,
+		"SELECT pg_catalog.pg_walfile_name(pg_catalog.pg_current_wal_lsn()), pg_catalog.pg_current_wal_lsn()")
+	if err := row.Scan(&result.CurrentWAL, &result.CurrentLsn); err != nil {
+		return nil, fmt.Errorf("while reading the current WAL location: %w", err)
+	}
+
+	// The lag can't be computed when nothing has been archived yet,
+	// or when the last archived file is not a WAL segment (i.e. a
+	// timeline history file)
+	segments, bytes, err := postgres.ArchiveLag(result.LastArchivedWAL, result.CurrentLsn, walSegmentSize)
+	if err != nil {
+		return &result, nil
+	}
+	result.ArchiveLagSegments = &segments
+	result.ArchiveLagBytes = &bytes
+
+	return &result, nil
+}
+
 // fillReplicationSlotsStatus get information about the replication slots
 func (instance *Instance) fillReplicationSlotsStatus(result *postgres.PostgresqlStatus) error {
 	if !result.IsPrimary {
@@ -465,9 +610,31 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 		return nil
 	}
 	var err error
-	var replicationInfo postgres.PgStatReplicationList
+	result.ReplicationInfo, err = getReplicationInfo(superUserDB, instance.ClusterName)
+	if err != nil {
+		return err
+	}
 
-	rows, err := superUserDB.Query(
+	result.ReadyWALFiles, _, err = GetWALArchiveCounters()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// rowsQuerier is implemented by both database connections and transactions
+type rowsQuerier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// getReplicationInfo reads the status of the replicas of the passed
+// cluster that are streaming from this instance
+func getReplicationInfo(
+	querier rowsQuerier,
+	clusterName string,
+) (replicationInfo postgres.PgStatReplicationList, err error) {
+	rows, err := querier.Query(
 		`SELECT
 			application_name,
 			coalesce(state, ''),
@@ -482,11 +649,11 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 			coalesce(sync_priority, 0)
 		FROM pg_catalog.pg_stat_replication
 		WHERE application_name ~ $1 AND usename = $2`,
-		fmt.Sprintf("%s-[0-9]+$", instance.ClusterName),
+		fmt.Sprintf("%s-[0-9]+$", clusterName),
 		v1.StreamingReplicationUser,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil && err == nil {
@@ -510,22 +677,16 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 			&pgr.SyncPriority,
 		)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		replicationInfo = append(replicationInfo, pgr)
 	}
-	result.ReplicationInfo = replicationInfo
 
 	if err = rows.Err(); err != nil {
-		return err
-	}
-
-	result.ReadyWALFiles, _, err = GetWALArchiveCounters()
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return replicationInfo, nil
 }
 
 // fillStatusFromReplica get WAL information for replica servers
@@ -89,6 +89,35 @@ var _ = Describe("probes", func() {
 		Expect(status.IsArchivingWAL).To(BeFalse())
 	})
 
+	It("fillChecksumFailures reports the checksum failures of every database", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(`SELECT COALESCE\(SUM\(checksum_failures\), 0\) FROM pg_catalog.pg_stat_database`).
+			WillReturnRows(sqlmock.NewRows([]string{"checksum_failures"}).AddRow(3))
+
+		status := &postgres.PostgresqlStatus{}
+		Expect(fillChecksumFailures(db, status)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(status.ChecksumFailures).To(Equal(int64(3)))
+	})
+
+	It("getMD5PasswordRoles lists the roles with an MD5 password", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT rolname FROM pg_catalog.pg_authid WHERE rolpassword LIKE 'md5%' ORDER BY rolname`)).
+			WillReturnRows(sqlmock.NewRows([]string{"rolname"}).
+				AddRow("app").
+				AddRow("legacy"))
+
+		roles, err := getMD5PasswordRoles(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roles).To(Equal([]string{"app", "legacy"}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
 	Context("Fill basebackup stats", func() {
 		It("does nothing in case of that major version is less than 13 ", func() {
 			instance := &Instance{
@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// ErrPromotionTokenNotReached is raised when the instance has not yet
+// replayed the WAL up to the shutdown checkpoint of the demoted primary
+var ErrPromotionTokenNotReached = errors.New("the promotion token has not been reached yet")
+
+// GenerateDemotionToken generates the token describing the shutdown
+// checkpoint of this instance, which has been cleanly stopped as
+// a primary, and archives the WAL file containing it. The token
+// allows a replica cluster to be promoted once it has replayed
+// every WAL generated by this instance
+func (instance *Instance) GenerateDemotionToken(ctx context.Context) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	output, err := instance.GetPgControldata()
+	if err != nil {
+		return "", err
+	}
+
+	pgControlData := utils.ParsePgControldataOutput(output)
+	if state := pgControlData[utils.PgControlDataKeyDatabaseClusterState]; state !=
+		utils.PgControlDataDatabaseClusterStateShutDown {
+		return "", fmt.Errorf("cannot generate the demotion token of an instance in the %q state", state)
+	}
+
+	content := utils.NewPgControldataTokenContent(pgControlData)
+	if err := content.IsValid(); err != nil {
+		return "", fmt.Errorf("while generating the demotion token: %w", err)
+	}
+
+	contextLogger.Info("Archiving the WAL file containing the shutdown checkpoint",
+		"walFile", content.REDOWALFile,
+		"latestCheckpointLocation", content.LatestCheckpointLocation)
+	if err := instance.archiveWALFile(content.REDOWALFile); err != nil {
+		return "", fmt.Errorf("while archiving the WAL file containing the shutdown checkpoint: %w", err)
+	}
+
+	return content.Encode()
+}
+
+// archiveWALFile archives the passed WAL file running the same
+// command PostgreSQL uses as `archive_command`
+func (instance *Instance) archiveWALFile(walFile string) error {
+	instanceManager, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// #nosec
+	archiveCmd := exec.Command(instanceManager, "wal-archive", path.Join("pg_wal", walFile))
+	archiveCmd.Dir = instance.PgData
+	archiveCmd.Env = os.Environ()
+	return execlog.RunStreaming(archiveCmd, "wal-archive")
+}
+
+// CheckPromotionToken checks if this instance can be promoted according
+// to the passed token, i.e. it belongs to the same database cluster of
+// the demoted primary and has replayed its shutdown checkpoint on the
+// same timeline. ErrPromotionTokenNotReached is returned when the replay
+// is still in progress
+func (instance *Instance) CheckPromotionToken(token string) error {
+	content, err := utils.ParsePgControldataToken(token)
+	if err != nil {
+		return err
+	}
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	// A restartpoint moves the latest checkpoint in pg_control to
+	// the last checkpoint record that has been replayed
+	if _, err := db.Exec("CHECKPOINT"); err != nil {
+		return fmt.Errorf("while requesting a restartpoint: %w", err)
+	}
+
+	output, err := instance.GetPgControldata()
+	if err != nil {
+		return err
+	}
+
+	return checkPromotionTokenContent(content, utils.ParsePgControldataOutput(output))
+}
+
+// checkPromotionTokenContent checks the content of a promotion token
+// against the parsed output of pg_controldata of this instance
+func checkPromotionTokenContent(
+	content *utils.PgControldataTokenContent,
+	pgControlData map[string]string,
+) error {
+	systemIdentifier := pgControlData[utils.PgControlDataKeyDatabaseSystemIdentifier]
+	if content.DatabaseSystemIdentifier != systemIdentifier {
+		return fmt.Errorf("the promotion token belongs to the database system %q, while this instance is part of %q",
+			content.DatabaseSystemIdentifier, systemIdentifier)
+	}
+
+	tokenTimeline, err := strconv.Atoi(content.LatestCheckpointTimelineID)
+	if err != nil {
+		return fmt.Errorf("while parsing the timeline of the promotion token: %w", err)
+	}
+	timeline, err := strconv.Atoi(pgControlData[utils.PgControlDataKeyLatestCheckpointTimelineID])
+	if err != nil {
+		return fmt.Errorf("while parsing the timeline of the latest checkpoint: %w", err)
+	}
+
+	// A later timeline means that this instance has diverged from the
+	// demoted primary, or that the token belongs to a former demotion
+	checkpointLocation := postgres.LSN(pgControlData[utils.PgControlDataKeyLatestCheckpointLocation])
+	switch {
+	case timeline > tokenTimeline:
+		return fmt.Errorf("the promotion token refers to timeline %d, while this instance is on timeline %d",
+			tokenTimeline, timeline)
+	case timeline < tokenTimeline || checkpointLocation.Less(postgres.LSN(content.LatestCheckpointLocation)):
+		return fmt.Errorf("%w: latest checkpoint at %s on timeline %d, shutdown checkpoint at %s on timeline %d",
+			ErrPromotionTokenNotReached, checkpointLocation, timeline,
+			content.LatestCheckpointLocation, tokenTimeline)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Promotion token", func() {
+	content := &utils.PgControldataTokenContent{
+		DatabaseSystemIdentifier:   "7390877990563459097",
+		LatestCheckpointTimelineID: "1",
+		LatestCheckpointLocation:   "0/6000028",
+		REDOWALFile:                "000000010000000000000006",
+	}
+
+	controlData := func(systemIdentifier, timeline, location string) map[string]string {
+		return map[string]string{
+			utils.PgControlDataKeyDatabaseSystemIdentifier:   systemIdentifier,
+			utils.PgControlDataKeyLatestCheckpointTimelineID: timeline,
+			utils.PgControlDataKeyLatestCheckpointLocation:   location,
+		}
+	}
+
+	It("is reached once the shutdown checkpoint has been replayed", func() {
+		Expect(checkPromotionTokenContent(content, controlData("7390877990563459097", "1", "0/6000028"))).
+			To(Succeed())
+	})
+
+	It("is not reached until the shutdown checkpoint has been replayed", func() {
+		Expect(checkPromotionTokenContent(content, controlData("7390877990563459097", "1", "0/5000060"))).
+			To(MatchError(ErrPromotionTokenNotReached))
+	})
+
+	It("is not reached while replaying a previous timeline", func() {
+		laterContent := *content
+		laterContent.LatestCheckpointTimelineID = "2"
+		Expect(checkPromotionTokenContent(&laterContent, controlData("7390877990563459097", "1", "0/7000028"))).
+			To(MatchError(ErrPromotionTokenNotReached))
+	})
+
+	It("refuses a token of a previous timeline", func() {
+		err := checkPromotionTokenContent(content, controlData("7390877990563459097", "2", "0/7000028"))
+		Expect(err).To(MatchError(ContainSubstring("timeline")))
+		Expect(err).ToNot(MatchError(ErrPromotionTokenNotReached))
+	})
+
+	It("belongs to the same database system", func() {
+		err := checkPromotionTokenContent(content, controlData("7390877990563459000", "1", "0/6000028"))
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(ErrPromotionTokenNotReached))
+	})
+})
@@ -0,0 +1,156 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+)
+
+// recoveryProfile describes how the instances of a range of PostgreSQL
+// major versions are driven through recovery: where the recovery
+// parameters are written and which files ask the postmaster to start in
+// recovery. Supporting a major version changing this behavior only
+// requires adding a profile
+type recoveryProfile struct {
+	// The first major version using this profile
+	minMajorVersion int
+
+	// The file, in PGDATA, where the recovery parameters are written when
+	// they are not configuration parameters. When empty, the recovery
+	// parameters are written in the PostgreSQL configuration files
+	recoveryConfFile string
+
+	// The file, in PGDATA, asking the postmaster to start as a standby
+	standbySignalFile string
+
+	// The file, in PGDATA, asking the postmaster to start a targeted
+	// recovery and to be promoted once it is completed
+	recoverySignalFile string
+
+	// The parameters added to the recovery ones when starting as a standby
+	standbyParameters map[string]string
+}
+
+// recoveryProfiles are the known recovery profiles, sorted by descending
+// first major version
+var recoveryProfiles = []recoveryProfile{
+	{
+		// Since PostgreSQL 12 the recovery parameters are configuration
+		// parameters and the signal files select the recovery mode
+		minMajorVersion:    12,
+		standbySignalFile:  "standby.signal",
+		recoverySignalFile: "recovery.signal",
+	},
+	{
+		// Before PostgreSQL 12 the recovery parameters are written in
+		// recovery.conf, whose presence starts the recovery
+		minMajorVersion:    0,
+		recoveryConfFile:   "recovery.conf",
+		standbySignalFile:  "recovery.conf",
+		recoverySignalFile: "recovery.conf",
+		standbyParameters:  map[string]string{"standby_mode": "on"},
+	},
+}
+
+// getRecoveryProfile gets the recovery profile of the passed major version
+func getRecoveryProfile(majorVersion int) recoveryProfile {
+	for _, profile := range recoveryProfiles {
+		if majorVersion >= profile.minMajorVersion {
+			return profile
+		}
+	}
+	return recoveryProfiles[len(recoveryProfiles)-1]
+}
+
+// getRecoveryProfileForPgData gets the recovery profile of the major
+// version of the passed data directory
+func getRecoveryProfileForPgData(pgData string) (recoveryProfile, error) {
+	majorVersion, err := postgresutils.GetMajorVersion(pgData)
+	if err != nil {
+		return recoveryProfile{}, fmt.Errorf("cannot detect major version: %w", err)
+	}
+	return getRecoveryProfile(majorVersion), nil
+}
+
+// getStandbySignalFiles gets the files that, in any of the known
+// profiles, ask the postmaster to start as a standby
+func getStandbySignalFiles() []string {
+	result := make([]string, 0, len(recoveryProfiles))
+	for _, profile := range recoveryProfiles {
+		if !slices.Contains(result, profile.standbySignalFile) {
+			result = append(result, profile.standbySignalFile)
+		}
+	}
+	return result
+}
+
+// hasRecoveryConfigurationParameters checks whether the recovery parameters
+// are written in the PostgreSQL configuration files
+func (profile recoveryProfile) hasRecoveryConfigurationParameters() bool {
+	return profile.recoveryConfFile == ""
+}
+
+// createStandbySignal asks the postmaster to start as a standby
+func (profile recoveryProfile) createStandbySignal(pgData string) error {
+	emptyFile, err := os.Create(filepath.Clean(filepath.Join(pgData, profile.standbySignalFile)))
+	if emptyFile != nil {
+		_ = emptyFile.Close()
+	}
+
+	return err
+}
+
+// writeTargetedRecoveryConfiguration writes the passed recovery parameters
+// and asks the postmaster to start a targeted recovery. When the recovery
+// parameters are configuration parameters, they are appended to the custom
+// configuration file, while the replication settings are emptied
+func (profile recoveryProfile) writeTargetedRecoveryConfiguration(pgData, recoveryFileContents string) error {
+	if !profile.hasRecoveryConfigurationParameters() {
+		return os.WriteFile(
+			path.Join(pgData, profile.recoveryConfFile),
+			[]byte(recoveryFileContents),
+			0o600)
+	}
+
+	err := fileutils.AppendStringToFile(
+		path.Join(pgData, constants.PostgresqlCustomConfigurationFile),
+		recoveryFileContents)
+	if err != nil {
+		return fmt.Errorf("cannot write recovery config: %w", err)
+	}
+
+	err = os.WriteFile(
+		path.Join(pgData, constants.PostgresqlOverrideConfigurationFile),
+		[]byte(""),
+		0o600)
+	if err != nil {
+		return fmt.Errorf("cannot erase auto config: %w", err)
+	}
+
+	return os.WriteFile(
+		path.Join(pgData, profile.recoverySignalFile),
+		[]byte(""),
+		0o600)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("recovery profiles", func() {
+	var pgData string
+
+	setMajorVersion := func(version string) {
+		Expect(os.WriteFile(path.Join(pgData, "PG_VERSION"), []byte(version+"\n"), 0o600)).To(Succeed())
+	}
+
+	readFile := func(name string) string {
+		content, err := fileutils.ReadFile(path.Join(pgData, name))
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+	})
+
+	It("selects the profile of each major version", func() {
+		Expect(getRecoveryProfile(17).standbySignalFile).To(Equal("standby.signal"))
+		Expect(getRecoveryProfile(12).recoverySignalFile).To(Equal("recovery.signal"))
+		Expect(getRecoveryProfile(12).hasRecoveryConfigurationParameters()).To(BeTrue())
+		Expect(getRecoveryProfile(11).recoveryConfFile).To(Equal("recovery.conf"))
+		Expect(getRecoveryProfile(11).hasRecoveryConfigurationParameters()).To(BeFalse())
+	})
+
+	It("lists the standby signal files of every profile once", func() {
+		Expect(getStandbySignalFiles()).To(Equal([]string{"standby.signal", "recovery.conf"}))
+	})
+
+	It("fails when the major version cannot be detected", func() {
+		_, err := getRecoveryProfileForPgData(pgData)
+		Expect(err).To(MatchError(ContainSubstring("cannot detect major version")))
+	})
+
+	It("writes the replication settings as configuration parameters", func() {
+		setMajorVersion("16")
+
+		changed, err := UpdateReplicaConfiguration(pgData, "host=primary", "slot")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(readFile(constants.PostgresqlOverrideConfigurationFile)).To(And(
+			ContainSubstring("primary_conninfo = 'host=primary'"),
+			ContainSubstring("primary_slot_name = 'slot'"),
+		))
+		Expect(fileutils.FileExists(path.Join(pgData, "standby.signal"))).To(BeTrue())
+		Expect(fileutils.FileExists(path.Join(pgData, "recovery.conf"))).To(BeFalse())
+
+		isPrimary, err := (&Instance{PgData: pgData}).IsPrimary()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(isPrimary).To(BeFalse())
+	})
+
+	It("writes the replication settings in recovery.conf before PostgreSQL 12", func() {
+		setMajorVersion("11")
+
+		changed, err := UpdateReplicaConfiguration(pgData, "host=primary", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(readFile(constants.PostgresqlOverrideConfigurationFile)).ToNot(ContainSubstring("primary_conninfo"))
+		Expect(readFile("recovery.conf")).To(And(
+			ContainSubstring("standby_mode = 'on'"),
+			ContainSubstring("primary_conninfo = 'host=primary'"),
+			Not(ContainSubstring("primary_slot_name")),
+		))
+		Expect(fileutils.FileExists(path.Join(pgData, "standby.signal"))).To(BeFalse())
+
+		isPrimary, err := (&Instance{PgData: pgData}).IsPrimary()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(isPrimary).To(BeFalse())
+	})
+
+	It("writes the targeted recovery configuration with the recovery signal", func() {
+		Expect(os.WriteFile(
+			path.Join(pgData, constants.PostgresqlCustomConfigurationFile),
+			[]byte("archive_command = 'false'\n"),
+			0o600)).To(Succeed())
+		Expect(os.WriteFile(
+			path.Join(pgData, constants.PostgresqlOverrideConfigurationFile),
+			[]byte("primary_conninfo = 'host=primary'\n"),
+			0o600)).To(Succeed())
+
+		Expect(getRecoveryProfile(16).writeTargetedRecoveryConfiguration(
+			pgData, "recovery_target_action = promote\n")).To(Succeed())
+		Expect(readFile(constants.PostgresqlCustomConfigurationFile)).To(
+			ContainSubstring("recovery_target_action = promote"))
+		Expect(readFile(constants.PostgresqlOverrideConfigurationFile)).To(BeEmpty())
+		Expect(fileutils.FileExists(path.Join(pgData, "recovery.signal"))).To(BeTrue())
+
+		isPrimary, err := (&Instance{PgData: pgData}).IsPrimary()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(isPrimary).To(BeTrue())
+	})
+
+	It("writes the targeted recovery configuration in recovery.conf before PostgreSQL 12", func() {
+		Expect(getRecoveryProfile(11).writeTargetedRecoveryConfiguration(
+			pgData, "recovery_target_action = promote\n")).To(Succeed())
+		Expect(readFile("recovery.conf")).To(Equal("recovery_target_action = promote\n"))
+		Expect(fileutils.FileExists(path.Join(pgData, "recovery.signal"))).To(BeFalse())
+	})
+})
@@ -0,0 +1,293 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// stagingDirectorySuffix is appended to the data and WAL directories to get
+// the ones where a new copy is prepared before replacing them. They are on
+// the same volumes, so that the replacement is done by renaming them
+const stagingDirectorySuffix = ".reclone"
+
+// Reclone replaces the data directory of a former primary which cannot be
+// rewound with a fresh copy, obtained according to the rejoin strategy of
+// the cluster. The instance must not be running.
+// The source of the copy is resolved and validated first, and the copy is
+// prepared in a staging directory, so that the current data directory is
+// replaced only once the new one is complete
+func (instance *Instance) Reclone(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	// The probes treat the instance as if pg_rewind was running, as the
+	// data directory is going to be unavailable for a while
+	instance.PgRewindIsRunning = true
+	defer func() {
+		instance.PgRewindIsRunning = false
+	}()
+
+	info := InitInfo{
+		PgData:      instance.PgData,
+		PgWal:       getWalDirectory(cluster),
+		ParentNode:  cluster.GetServiceReadWriteName(),
+		PodName:     instance.PodName,
+		ClusterName: instance.ClusterName,
+		Namespace:   instance.Namespace,
+	}
+	staging := info.getStagingInitInfo()
+
+	strategy := cluster.GetRejoinStrategy()
+	contextLogger.Info("Recloning the data directory of the former primary",
+		"strategy", strategy,
+		"pgdata", info.PgData,
+		"pgwal", info.PgWal,
+		"stagingPgData", staging.PgData)
+
+	var restore func() error
+	switch strategy {
+	case apiv1.RejoinStrategyPgBaseBackup:
+		if err := info.checkCloneSource(instance); err != nil {
+			return fmt.Errorf("while checking the primary to clone: %w", err)
+		}
+		restore = func() error {
+			return staging.Join(cluster)
+		}
+
+	case apiv1.RejoinStrategyObjectStore:
+		backup, env, err := loadLatestBackupFromObjectStore(ctx, cli, cluster)
+		if err != nil {
+			return fmt.Errorf("while looking for the backup to restore: %w", err)
+		}
+		restore = func() error {
+			return staging.restoreFromObjectStore(ctx, cluster, backup, env)
+		}
+
+	default:
+		return fmt.Errorf("cannot reclone the data directory with the %q rejoin strategy", strategy)
+	}
+
+	// Remove what is left by a previous attempt
+	if err := staging.removePartialClone(); err != nil {
+		return fmt.Errorf("while cleaning up the staging directories: %w", err)
+	}
+
+	// Both pg_basebackup and barman-cloud-restore restore the tablespaces
+	// in their locations, which need to be empty
+	if err := removeTablespacesContent(cluster); err != nil {
+		return fmt.Errorf("while cleaning up the tablespaces: %w", err)
+	}
+
+	if err := restore(); err != nil {
+		if cleanupErr := staging.removePartialClone(); cleanupErr != nil {
+			contextLogger.Error(cleanupErr, "while cleaning up the staging directories")
+		}
+		return err
+	}
+
+	if err := info.replaceDataDirectories(staging); err != nil {
+		return fmt.Errorf("while replacing the data directories: %w", err)
+	}
+
+	return nil
+}
+
+// getWalDirectory gets the WAL directory to be used when the data directory
+// is recreated, which is empty when the WAL files are kept inside PGDATA
+func getWalDirectory(cluster *apiv1.Cluster) string {
+	if !cluster.ShouldCreateWalArchiveVolume() {
+		return ""
+	}
+	return specs.PgWalVolumePgWalPath
+}
+
+// getStagingInitInfo gets the information to prepare a new copy of the data
+// directory and of the WAL directory beside the current ones
+func (info InitInfo) getStagingInitInfo() InitInfo {
+	staging := info
+	staging.PgData = info.PgData + stagingDirectorySuffix
+	if info.PgWal != "" {
+		staging.PgWal = info.PgWal + stagingDirectorySuffix
+	}
+	return staging
+}
+
+// checkCloneSource checks that the primary can be cloned, and that it
+// belongs to the same system of the passed instance
+func (info InitInfo) checkCloneSource(instance *Instance) error {
+	controldata, err := instance.GetPgControldata()
+	if err != nil {
+		return err
+	}
+	localSystemID := utils.ParsePgControldataOutput(controldata)["Database system identifier"]
+
+	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
+	db, err := pool.NewDBConnection(primaryConnInfo, pool.ConnectionProfilePostgresqlPhysicalReplication)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	if err := waitForStreamingConnectionAvailable(db); err != nil {
+		return fmt.Errorf("primary not available: %w", err)
+	}
+
+	var systemID, timeline, walPosition string
+	var dbName sql.NullString
+	if err := db.QueryRow("IDENTIFY_SYSTEM").Scan(&systemID, &timeline, &walPosition, &dbName); err != nil {
+		return err
+	}
+	if systemID != localSystemID {
+		return fmt.Errorf("the primary belongs to the system %q, while this instance belongs to %q",
+			systemID, localSystemID)
+	}
+
+	return nil
+}
+
+// removeTablespacesContent removes the content of the locations of the
+// tablespaces of the cluster
+func removeTablespacesContent(cluster *apiv1.Cluster) error {
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		location := specs.LocationForTablespace(tablespace.Name)
+		if err := fileutils.RemoveDirectoryContent(location); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceDataDirectories replaces the data directory and the WAL directory
+// with the ones prepared in the passed staging directories, pointing the
+// pg_wal symbolic link to the final WAL directory
+func (info InitInfo) replaceDataDirectories(staging InitInfo) error {
+	if err := os.RemoveAll(info.PgData); err != nil {
+		return err
+	}
+	if err := os.Rename(staging.PgData, info.PgData); err != nil {
+		return err
+	}
+
+	if info.PgWal == "" {
+		return nil
+	}
+
+	if err := os.RemoveAll(info.PgWal); err != nil {
+		return err
+	}
+	if err := os.Rename(staging.PgWal, info.PgWal); err != nil {
+		return err
+	}
+
+	pgDataWal := path.Join(info.PgData, "pg_wal")
+	if err := os.Remove(pgDataWal); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(info.PgWal, pgDataWal)
+}
+
+// restoreFromObjectStore restores the data directory from the passed backup,
+// found in the object store of the cluster. The WAL files needed to
+// reach a consistent state are fetched from the archive once the instance
+// is started as a replica.
+func (info InitInfo) restoreFromObjectStore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	env []string,
+) error {
+	if err := info.restoreDataDir(backup, cluster, env); err != nil {
+		return err
+	}
+
+	if _, err := info.restoreCustomWalDir(ctx); err != nil {
+		return err
+	}
+
+	if err := info.WriteInitialPostgresqlConf(cluster); err != nil {
+		return err
+	}
+
+	if _, err := info.GetInstance().migratePostgresAutoConfFile(ctx); err != nil {
+		return err
+	}
+
+	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
+	_, err := UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
+	return err
+}
+
+// loadLatestBackupFromObjectStore looks for the latest backup in the object
+// store of the cluster, returning it together with the environment needed
+// to access it
+func loadLatestBackupFromObjectStore(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+) (*apiv1.Backup, []string, error) {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return nil, nil, fmt.Errorf("no object store configured in the cluster")
+	}
+
+	configuration := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.Name
+	if configuration.ServerName != "" {
+		serverName = configuration.ServerName
+	}
+
+	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
+		ctx,
+		cli,
+		cluster.Namespace,
+		configuration,
+		os.Environ())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backupCatalog, err := barman.GetBackupList(ctx, configuration, serverName, env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetBackup := backupCatalog.LatestBackupInfo()
+	if targetBackup == nil {
+		return nil, nil, fmt.Errorf("no backup found in the object store")
+	}
+
+	log.FromContext(ctx).Info("Target backup found", "backup", targetBackup)
+
+	return newBackupFromCatalog(configuration, serverName, targetBackup), env, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rejoin helpers", func() {
+	It("uses the WAL volume only when it is configured", func() {
+		cluster := &apiv1.Cluster{}
+		Expect(getWalDirectory(cluster)).To(BeEmpty())
+
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{}
+		Expect(getWalDirectory(cluster)).To(Equal(specs.PgWalVolumePgWalPath))
+	})
+
+	It("prepares the new copy beside the current directories", func() {
+		info := InitInfo{PgData: "/var/lib/postgresql/data/pgdata", PgWal: "/var/lib/postgresql/wal/pg_wal"}
+		staging := info.getStagingInitInfo()
+		Expect(staging.PgData).To(Equal("/var/lib/postgresql/data/pgdata.reclone"))
+		Expect(staging.PgWal).To(Equal("/var/lib/postgresql/wal/pg_wal.reclone"))
+
+		info.PgWal = ""
+		Expect(info.getStagingInitInfo().PgWal).To(BeEmpty())
+	})
+
+	It("replaces the data and the WAL directories with the staging ones", func() {
+		tempDir, err := os.MkdirTemp("", "rejoin")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		info := InitInfo{
+			PgData: path.Join(tempDir, "pgdata"),
+			PgWal:  path.Join(tempDir, "wal", "pg_wal"),
+		}
+		staging := info.getStagingInitInfo()
+		Expect(fileutils.EnsureDirectoryExists(path.Join(info.PgData, "old"))).To(Succeed())
+		Expect(fileutils.EnsureDirectoryExists(path.Join(info.PgWal, "old"))).To(Succeed())
+		Expect(fileutils.EnsureDirectoryExists(path.Join(staging.PgData, "base"))).To(Succeed())
+		Expect(fileutils.EnsureDirectoryExists(path.Join(staging.PgWal, "archive_status"))).To(Succeed())
+		Expect(os.Symlink(staging.PgWal, path.Join(staging.PgData, "pg_wal"))).To(Succeed())
+
+		Expect(info.replaceDataDirectories(staging)).To(Succeed())
+		Expect(fileutils.FileExists(path.Join(info.PgData, "base"))).To(BeTrue())
+		Expect(fileutils.FileExists(path.Join(info.PgData, "old"))).To(BeFalse())
+		Expect(fileutils.FileExists(path.Join(info.PgWal, "archive_status"))).To(BeTrue())
+		Expect(fileutils.FileExists(path.Join(info.PgWal, "old"))).To(BeFalse())
+		Expect(fileutils.FileExists(staging.PgData)).To(BeFalse())
+		Expect(fileutils.FileExists(staging.PgWal)).To(BeFalse())
+		Expect(os.Readlink(path.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+	})
+
+	It("doesn't fail cleaning up the staging directories if they don't exist", func() {
+		info := InitInfo{
+			PgData: path.Join(os.TempDir(), "rejoin-non-existent", "pgdata"),
+		}
+		Expect(info.getStagingInitInfo().removePartialClone()).To(Succeed())
+	})
+})
@@ -27,6 +27,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
@@ -52,6 +54,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -92,6 +95,18 @@ func (info InitInfo) RestoreSnapshot(ctx context.Context, cli client.Client, imm
 		return err
 	}
 
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil &&
+		cluster.Spec.Bootstrap.Recovery.VolumeSource != nil {
+		contextLogger.Info("Validating the adopted data directory")
+		if err := info.verifyAdoptedDataDirectory(
+			cluster.Spec.Bootstrap.Recovery.VolumeSource,
+			cluster,
+			info.GetInstance().GetPgControldata,
+		); err != nil {
+			return err
+		}
+	}
+
 	contextLogger.Info("Cleaning up PGDATA from stale files")
 	if err := fileutils.RemoveRestoreExcludedFiles(ctx, info.PgData); err != nil {
 		return fmt.Errorf("error while cleaning up the recovered PGDATA: %w", err)
@@ -162,6 +177,49 @@ func (info InitInfo) RestoreSnapshot(ctx context.Context, cli client.Client, imm
 	return info.ConfigureInstanceAfterRestore(ctx, cluster, env)
 }
 
+// verifyAdoptedDataDirectory checks that the data directory cloned from an
+// existing PVC can be used by this cluster, because it belongs to the same
+// PostgreSQL major version and, when requested, to the expected system
+func (info InitInfo) verifyAdoptedDataDirectory(
+	volumeSource *apiv1.VolumeSourceRecovery,
+	cluster *apiv1.Cluster,
+	getControldata func() (string, error),
+) error {
+	dataMajorVersion, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("cannot detect the major version of the adopted data directory: %w", err)
+	}
+
+	imageVersion, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return fmt.Errorf("cannot detect the major version of the cluster image: %w", err)
+	}
+
+	if imageMajorVersion := postgresSpec.GetPostgresMajorVersionNumber(imageVersion); imageMajorVersion != dataMajorVersion {
+		return fmt.Errorf(
+			"the adopted data directory belongs to PostgreSQL %d, while the cluster is running PostgreSQL %d",
+			dataMajorVersion, imageMajorVersion)
+	}
+
+	if volumeSource.SystemID == "" {
+		return nil
+	}
+
+	controldata, err := getControldata()
+	if err != nil {
+		return err
+	}
+
+	systemID := utils.ParsePgControldataOutput(controldata)["Database system identifier"]
+	if systemID != volumeSource.SystemID {
+		return fmt.Errorf(
+			"the adopted data directory belongs to the system %q, while %q was expected",
+			systemID, volumeSource.SystemID)
+	}
+
+	return nil
+}
+
 // createBackupObjectForSnapshotRestore creates a fake Backup object that can be used during the
 // snapshot restore process
 func (info InitInfo) createBackupObjectForSnapshotRestore(
@@ -186,7 +244,7 @@ func (info InitInfo) createBackupObjectForSnapshotRestore(
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
-		cluster.Namespace,
+		server.GetSecretsNamespace(cluster.Namespace),
 		server.BarmanObjectStore,
 		os.Environ())
 	if err != nil {
@@ -239,17 +297,71 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	// If we need to download data from a backup, we do it
-	backup, env, err := info.loadBackup(ctx, typedClient, cluster)
+	return info.restoreFromBackup(ctx, typedClient, cluster)
+}
+
+// restoreFromBackup restores the backup selected in the recovery
+// bootstrap section of the passed cluster into the data directory,
+// and recovers it up to the requested target
+func (info InitInfo) restoreFromBackup(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+) error {
+	backup, env, err := info.prepareRestoreFromBackup(ctx, typedClient, cluster)
 	if err != nil {
 		return err
 	}
 
+	return info.restorePreparedBackup(ctx, typedClient, cluster, backup, env)
+}
+
+// prepareRestoreFromBackup loads the backup selected in the recovery
+// bootstrap section of the passed cluster, together with the environment
+// needed to access it, and checks that it can be restored without
+// touching the data directory
+func (info InitInfo) prepareRestoreFromBackup(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+) (*apiv1.Backup, []string, error) {
+	backup, env, err := info.loadBackup(ctx, typedClient, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Before downloading anything, we check if the recovery target can be reached
+	// from the chosen backup, as PostgreSQL would otherwise refuse to start
+	if err := backup.ValidateRecoveryTarget(cluster.Spec.Bootstrap.Recovery.RecoveryTarget); err != nil {
+		return nil, nil, reportUnreachableRecoveryTarget(ctx, typedClient, cluster, err)
+	}
+
+	if err := checkWALCompression(backup); err != nil {
+		return nil, nil, err
+	}
+
 	if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	if err := info.ensureArchiveContainsRecoveryTargetWAL(ctx, cluster, env, backup); err != nil {
+		return nil, nil, reportUnreachableRecoveryTarget(ctx, typedClient, cluster, err)
 	}
 
-	if err := info.restoreDataDir(backup, env); err != nil {
+	return backup, env, nil
+}
+
+// restorePreparedBackup downloads the passed backup into the data
+// directory and recovers it up to the target requested in the
+// recovery bootstrap section of the passed cluster
+func (info InitInfo) restorePreparedBackup(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	env []string,
+) error {
+	if err := info.restoreDataDir(backup, cluster, env); err != nil {
 		return err
 	}
 
@@ -295,13 +407,108 @@ func (info InitInfo) Restore(ctx context.Context) error {
 	return info.ConfigureInstanceAfterRestore(ctx, cluster, env)
 }
 
+// checkWALCompression ensures that the WAL files archived together with the
+// passed backup can be decompressed by barman-cloud-wal-restore, which
+// detects their compression algorithm automatically
+func checkWALCompression(backup *apiv1.Backup) error {
+	if backup.Status.WALCompression == apiv1.CompressionTypeNone {
+		return nil
+	}
+
+	capabilities, err := barmanCapabilities.CurrentCapabilities()
+	if err != nil {
+		return err
+	}
+
+	if err := capabilities.CheckCompression(backup.Status.WALCompression); err != nil {
+		return fmt.Errorf("cannot restore the WAL files archived with backup %s: %w", backup.Name, err)
+	}
+
+	return nil
+}
+
 func (info InitInfo) ensureArchiveContainsLastCheckpointRedoWAL(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	env []string,
 	backup *apiv1.Backup,
 ) error {
-	// it's the full path of the file that will temporarily contain the LastCheckpointRedoWAL
+	if err := info.ensureArchiveContainsWAL(ctx, cluster, env, backup, backup.Status.BeginWal); err != nil {
+		return fmt.Errorf("encountered an error while checking the presence of first needed WAL in the archive: %w", err)
+	}
+
+	return nil
+}
+
+// ensureArchiveContainsRecoveryTargetWAL checks that the WAL file containing
+// the targetLSN of the recovery target has been archived, either on the
+// timeline of the backup or on one of the following timelines found in
+// the archive. The WAL files needed to reach a targetTime can't be
+// computed without reading them, and are checked by the operator against
+// the last recoverability point of the source cluster instead
+func (info InitInfo) ensureArchiveContainsRecoveryTargetWAL(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	env []string,
+	backup *apiv1.Backup,
+) error {
+	recoveryTarget := cluster.Spec.Bootstrap.Recovery.RecoveryTarget
+	if recoveryTarget == nil || recoveryTarget.TargetLSN == "" ||
+		backup.Status.BeginWal == "" || backup.Status.BeginLSN == "" {
+		return nil
+	}
+
+	walSegmentSize, err := postgresSpec.DetectWALSegmentSize(
+		backup.Status.BeginWal, postgresSpec.LSN(backup.Status.BeginLSN))
+	if err != nil {
+		return err
+	}
+	backupSegment, err := postgresSpec.SegmentFromName(backup.Status.BeginWal)
+	if err != nil {
+		return err
+	}
+
+	// When the recovery target timeline is a number, PostgreSQL doesn't
+	// follow the timelines after it
+	lastTimeline := int64(math.MaxInt32)
+	if recoveryTarget.TargetTLI != "" && recoveryTarget.TargetTLI != "latest" &&
+		recoveryTarget.TargetTLI != "current" {
+		if lastTimeline, err = strconv.ParseInt(recoveryTarget.TargetTLI, 0, 32); err != nil {
+			return fmt.Errorf("while parsing recovery target targetTLI: %w", err)
+		}
+	}
+
+	for tli := backupSegment.Tli; int64(tli) <= lastTimeline; tli++ {
+		if tli != backupSegment.Tli {
+			historyFile := fmt.Sprintf("%08X.history", tli)
+			if err := info.ensureArchiveContainsWAL(ctx, cluster, env, backup, historyFile); err != nil {
+				break
+			}
+		}
+
+		segment, err := postgresSpec.SegmentFromLSN(tli, postgresSpec.LSN(recoveryTarget.TargetLSN), walSegmentSize)
+		if err != nil {
+			return fmt.Errorf("while parsing recovery target targetLSN: %w", err)
+		}
+		if err := info.ensureArchiveContainsWAL(ctx, cluster, env, backup, segment.Name()); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the WAL file containing the recovery target LSN %s has not been archived",
+		recoveryTarget.TargetLSN)
+}
+
+// ensureArchiveContainsWAL checks that the WAL file with the passed name
+// can be downloaded from the archive of the passed backup
+func (info InitInfo) ensureArchiveContainsWAL(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	env []string,
+	backup *apiv1.Backup,
+	walName string,
+) error {
+	// it's the full path of the file that will temporarily contain the WAL file
 	const testWALPath = postgresSpec.RecoveryTemporaryDirectory + "/test.wal"
 	contextLogger := log.FromContext(ctx)
 
@@ -320,22 +527,12 @@ func (info InitInfo) ensureArchiveContainsLastCheckpointRedoWAL(
 		return err
 	}
 
-	opts, err := barman.CloudWalRestoreOptions(&apiv1.BarmanObjectStoreConfiguration{
-		BarmanCredentials: backup.Status.BarmanCredentials,
-		EndpointCA:        backup.Status.EndpointCA,
-		EndpointURL:       backup.Status.EndpointURL,
-		DestinationPath:   backup.Status.DestinationPath,
-		ServerName:        backup.Status.ServerName,
-	}, cluster.Name)
+	opts, err := barman.CloudWalRestoreOptions(getBackupObjectStoreConfiguration(backup), cluster.Name)
 	if err != nil {
 		return err
 	}
 
-	if err := rest.Restore(backup.Status.BeginWal, testWALPath, opts); err != nil {
-		return fmt.Errorf("encountered an error while checking the presence of first needed WAL in the archive: %w", err)
-	}
-
-	return nil
+	return rest.Restore(walName, testWALPath, opts)
 }
 
 // restoreCustomWalDir moves the current pg_wal data to the specified custom wal dir and applies the symlink
@@ -375,23 +572,12 @@ func (info InitInfo) restoreCustomWalDir(ctx context.Context) (bool, error) {
 }
 
 // restoreDataDir restores PGDATA from an existing backup
-func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
-	var options []string
-
-	if backup.Status.EndpointURL != "" {
-		options = append(options, "--endpoint-url", backup.Status.EndpointURL)
-	}
-	options = append(options, backup.Status.DestinationPath)
-	options = append(options, backup.Status.ServerName)
-	options = append(options, backup.Status.BackupID)
-
-	options, err := barman.AppendCloudProviderOptionsFromBackup(options, backup)
+func (info InitInfo) restoreDataDir(backup *apiv1.Backup, cluster *apiv1.Cluster, env []string) error {
+	options, err := info.getRestoreDataDirOptions(backup, cluster)
 	if err != nil {
 		return err
 	}
 
-	options = append(options, info.PgData)
-
 	log.Info("Starting barman-cloud-restore",
 		"options", options)
 
@@ -411,6 +597,34 @@ func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
 	return nil
 }
 
+// getRestoreDataDirOptions builds the barman-cloud-restore command line options
+func (info InitInfo) getRestoreDataDirOptions(backup *apiv1.Backup, cluster *apiv1.Cluster) ([]string, error) {
+	var options []string
+
+	if backup.Status.EndpointURL != "" {
+		options = append(options, "--endpoint-url", backup.Status.EndpointURL)
+	}
+	options = append(options, backup.Status.DestinationPath)
+	options = append(options, backup.Status.ServerName)
+	options = append(options, backup.Status.BackupID)
+
+	options, err := barman.AppendCloudProviderOptionsFromBackup(options, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tablespaces are restored into the volumes mounted for them,
+	// regardless of the location they had in the backed up cluster
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		options = append(options, "--tablespace",
+			fmt.Sprintf("%s:%s", tablespace.Name, specs.LocationForTablespace(tablespace.Name)))
+	}
+
+	options = append(options, info.PgData)
+
+	return options, nil
+}
+
 // loadCluster loads the cluster definition from the API server
 func (info InitInfo) loadCluster(ctx context.Context, typedClient client.Client) (*apiv1.Cluster, error) {
 	var cluster apiv1.Cluster
@@ -461,7 +675,7 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
-		cluster.Namespace,
+		server.GetSecretsNamespace(cluster.Namespace),
 		server.BarmanObjectStore,
 		os.Environ())
 	if err != nil {
@@ -485,22 +699,42 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 		targetBackup = backupCatalog.LatestBackupInfo()
 	}
 	if targetBackup == nil {
-		return nil, nil, fmt.Errorf("no target backup found")
+		recoveryTarget := cluster.Spec.Bootstrap.Recovery.RecoveryTarget
+		if recoveryTarget == nil || (recoveryTarget.TargetTime == "" && recoveryTarget.TargetLSN == "") {
+			return nil, nil, fmt.Errorf("no target backup found")
+		}
+
+		err := fmt.Errorf("no backup completed before the recovery target was found")
+		if firstRecoverabilityPoint := backupCatalog.FirstRecoverabilityPoint(); firstRecoverabilityPoint != nil {
+			err = fmt.Errorf("%w, the first recoverability point is %s",
+				err, firstRecoverabilityPoint.Format(time.RFC3339))
+		}
+		return nil, nil, reportUnreachableRecoveryTarget(ctx, typedClient, cluster, err)
 	}
 
 	log.Info("Target backup found", "backup", targetBackup)
 
-	return &apiv1.Backup{
+	return newBackupFromCatalog(server.BarmanObjectStore, serverName, targetBackup), env, nil
+}
+
+// newBackupFromCatalog builds an in-memory Backup object describing a backup
+// found in the catalog of the passed object store
+func newBackupFromCatalog(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	serverName string,
+	targetBackup *catalog.BarmanBackup,
+) *apiv1.Backup {
+	backup := &apiv1.Backup{
 		Spec: apiv1.BackupSpec{
 			Cluster: apiv1.LocalObjectReference{
 				Name: serverName,
 			},
 		},
 		Status: apiv1.BackupStatus{
-			BarmanCredentials: server.BarmanObjectStore.BarmanCredentials,
-			EndpointCA:        server.BarmanObjectStore.EndpointCA,
-			EndpointURL:       server.BarmanObjectStore.EndpointURL,
-			DestinationPath:   server.BarmanObjectStore.DestinationPath,
+			BarmanCredentials: configuration.BarmanCredentials,
+			EndpointCA:        configuration.EndpointCA,
+			EndpointURL:       configuration.EndpointURL,
+			DestinationPath:   configuration.DestinationPath,
 			ServerName:        serverName,
 			BackupID:          targetBackup.ID,
 			Phase:             apiv1.BackupPhaseCompleted,
@@ -514,7 +748,27 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 			CommandOutput:     "",
 			CommandError:      "",
 		},
-	}, env, nil
+	}
+	if configuration.Wal != nil {
+		backup.Status.WALClientSideEncryption = configuration.Wal.ClientSideEncryption
+	}
+	return backup
+}
+
+// reportUnreachableRecoveryTarget sets the cluster condition signaling that
+// the recovery target can't be reached, returning the passed error
+func reportUnreachableRecoveryTarget(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+	err error,
+) error {
+	if errCond := conditions.Patch(
+		ctx, typedClient, cluster, apiv1.BuildRecoveryTargetUnreachableCondition(err)); errCond != nil {
+		log.FromContext(ctx).Error(errCond, "Error changing the recovery target condition")
+	}
+
+	return err
 }
 
 // loadBackupFromReference loads a backup object and the required credentials given the backup object resource
@@ -532,18 +786,7 @@ func (info InitInfo) loadBackupFromReference(
 		return nil, nil, err
 	}
 
-	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
-		ctx,
-		typedClient,
-		cluster.Namespace,
-		&apiv1.BarmanObjectStoreConfiguration{
-			BarmanCredentials: backup.Status.BarmanCredentials,
-			EndpointCA:        backup.Status.EndpointCA,
-			EndpointURL:       backup.Status.EndpointURL,
-			DestinationPath:   backup.Status.DestinationPath,
-			ServerName:        backup.Status.ServerName,
-		},
-		os.Environ())
+	env, err := getBackupRestoreEnv(ctx, typedClient, &backup)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -552,10 +795,61 @@ func (info InitInfo) loadBackupFromReference(
 	return &backup, env, nil
 }
 
+// getBackupRestoreEnv gets the environment variables holding the credentials
+// needed to restore the passed backup from the object store
+func getBackupRestoreEnv(
+	ctx context.Context,
+	typedClient client.Client,
+	backup *apiv1.Backup,
+) ([]string, error) {
+	return barmanCredentials.EnvSetRestoreCloudCredentials(
+		ctx,
+		typedClient,
+		backup.Namespace,
+		getBackupObjectStoreConfiguration(backup),
+		os.Environ())
+}
+
+// getBackupObjectStoreConfiguration gets the configuration of the object
+// store where the passed backup, and the WAL files archived with it, are
+func getBackupObjectStoreConfiguration(backup *apiv1.Backup) *apiv1.BarmanObjectStoreConfiguration {
+	configuration := &apiv1.BarmanObjectStoreConfiguration{
+		BarmanCredentials: backup.Status.BarmanCredentials,
+		EndpointCA:        backup.Status.EndpointCA,
+		EndpointURL:       backup.Status.EndpointURL,
+		DestinationPath:   backup.Status.DestinationPath,
+		ServerName:        backup.Status.ServerName,
+	}
+	if backup.Status.WALClientSideEncryption != nil {
+		configuration.Wal = &apiv1.WalBackupConfiguration{
+			ClientSideEncryption: backup.Status.WALClientSideEncryption,
+		}
+	}
+	return configuration
+}
+
 // writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
 // to complete the WAL recovery from the object storage and then start
 // as a new primary
 func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
+	restoreCommand, err := buildRestoreCommand(backup)
+	if err != nil {
+		return err
+	}
+
+	recoveryFileContents := fmt.Sprintf(
+		"recovery_target_action = promote\n"+
+			"restore_command = '%s'\n"+
+			"%s",
+		restoreCommand,
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
+
+	return info.writeRecoveryConfiguration(recoveryFileContents)
+}
+
+// buildRestoreCommand builds the restore_command fetching the WAL files
+// archived together with the passed backup from the object store
+func buildRestoreCommand(backup *apiv1.Backup) (string, error) {
 	var err error
 
 	cmd := []string{barmanCapabilities.BarmanCloudWalRestore}
@@ -567,27 +861,26 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 
 	cmd, err = barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	cmd = append(cmd, "%f", "%p")
 
-	recoveryFileContents := fmt.Sprintf(
-		"recovery_target_action = promote\n"+
-			"restore_command = '%s'\n"+
-			"%s",
-		strings.Join(cmd, " "),
-		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
+	// The WAL files encrypted while being archived are decrypted by the
+	// instance manager, with the keys passed through the environment
+	if backup.Status.WALClientSideEncryption != nil {
+		cmd = append(cmd, "&&", "/controller/manager", "wal-decrypt", "%p")
+	}
 
-	return info.writeRecoveryConfiguration(recoveryFileContents)
+	return strings.Join(cmd, " "), nil
 }
 
 func (info InitInfo) writeRecoveryConfiguration(recoveryFileContents string) error {
 	// Ensure restore_command is used to correctly recover WALs
 	// from the object storage
-	major, err := postgresutils.GetMajorVersion(info.PgData)
+	profile, err := getRecoveryProfileForPgData(info.PgData)
 	if err != nil {
-		return fmt.Errorf("cannot detect major version: %w", err)
+		return err
 	}
 
 	log.Info("Generated recovery configuration", "configuration", recoveryFileContents)
@@ -618,36 +911,7 @@ func (info InitInfo) writeRecoveryConfiguration(recoveryFileContents string) err
 		}
 	}
 
-	if major >= 12 {
-		// Append restore_command to the end of the
-		// custom configs file
-		err = fileutils.AppendStringToFile(
-			path.Join(info.PgData, constants.PostgresqlCustomConfigurationFile),
-			recoveryFileContents)
-		if err != nil {
-			return fmt.Errorf("cannot write recovery config: %w", err)
-		}
-
-		err = os.WriteFile(
-			path.Join(info.PgData, constants.PostgresqlOverrideConfigurationFile),
-			[]byte(""),
-			0o600)
-		if err != nil {
-			return fmt.Errorf("cannot erase auto config: %w", err)
-		}
-
-		// Create recovery signal file
-		return os.WriteFile(
-			path.Join(info.PgData, "recovery.signal"),
-			[]byte(""),
-			0o600)
-	}
-
-	// We need to generate a recovery.conf
-	return os.WriteFile(
-		path.Join(info.PgData, "recovery.conf"),
-		[]byte(recoveryFileContents),
-		0o600)
+	return profile.writeTargetedRecoveryConfiguration(info.PgData, recoveryFileContents)
 }
 
 // GetEnforcedParametersThroughPgControldata will parse the output of pg_controldata in order to get
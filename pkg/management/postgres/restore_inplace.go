@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
+)
+
+// RestoreInPlace replaces the data directory of an existing cluster with
+// the content of the backup chosen by the passed Restore object, and
+// recovers it up to the requested target. The backup and the WAL archive
+// are checked first, and the current content of the data directory, of
+// the WAL volume, and of the tablespace volumes is removed only when they
+// can be used. As the removal is repeated on every attempt, the restore
+// can be safely retried when the download of the backup fails
+func (info InitInfo) RestoreInPlace(ctx context.Context, restoreName string) error {
+	contextLogger := log.FromContext(ctx)
+
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := info.loadCluster(ctx, typedClient)
+	if err != nil {
+		return err
+	}
+
+	var restore apiv1.Restore
+	if err := typedClient.Get(
+		ctx,
+		client.ObjectKey{Namespace: info.Namespace, Name: restoreName},
+		&restore,
+	); err != nil {
+		return err
+	}
+
+	if restore.Status.Backup == "" {
+		return fmt.Errorf("restore %s has no backup selected", restoreName)
+	}
+
+	coredumpFilter := cluster.GetCoredumpFilter()
+	if err := system.SetCoredumpFilter(coredumpFilter); err != nil {
+		return err
+	}
+
+	// The backup archive of the cluster is expected to be non-empty, and
+	// the application database already exists in the backup, so we only
+	// need to replace the recovery configuration of the cluster
+	restoreCluster := getInPlaceRestoreCluster(cluster, &restore)
+	backup, env, err := info.prepareRestoreFromBackup(ctx, typedClient, restoreCluster)
+	if err != nil {
+		return fmt.Errorf("while checking backup %s: %w", restore.Status.Backup, err)
+	}
+
+	contextLogger.Info("Removing the current data directory before restoring it in place",
+		"restore", restoreName,
+		"backup", restore.Status.Backup)
+	for _, directory := range info.getInPlaceRestoreDirectories(cluster) {
+		if err := fileutils.RemoveDirectory(directory); err != nil {
+			return fmt.Errorf("while removing %s: %w", directory, err)
+		}
+	}
+
+	return info.restorePreparedBackup(ctx, typedClient, restoreCluster, backup, env)
+}
+
+// getInPlaceRestoreDirectories returns the directories that need to be
+// removed before restoring a cluster in place
+func (info InitInfo) getInPlaceRestoreDirectories(cluster *apiv1.Cluster) []string {
+	directories := []string{info.PgData}
+	if info.PgWal != "" {
+		directories = append(directories, info.PgWal)
+	}
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		directories = append(directories, specs.LocationForTablespace(tablespace.Name))
+	}
+
+	return directories
+}
+
+// getInPlaceRestoreCluster returns a copy of the passed cluster whose
+// recovery bootstrap section points to the backup and to the target
+// selected by the passed Restore object
+func getInPlaceRestoreCluster(cluster *apiv1.Cluster, restore *apiv1.Restore) *apiv1.Cluster {
+	result := cluster.DeepCopy()
+	result.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+		Recovery: &apiv1.BootstrapRecovery{
+			Backup: &apiv1.BackupSource{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: restore.Status.Backup},
+			},
+			RecoveryTarget: restore.Spec.RecoveryTarget,
+		},
+	}
+
+	return result
+}
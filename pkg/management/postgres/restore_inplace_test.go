@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("in-place restore", func() {
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{Database: "app"},
+			},
+			Tablespaces: []apiv1.TablespaceConfiguration{
+				{Name: "tbs1"},
+			},
+		},
+	}
+	restore := &apiv1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Name: "flashback"},
+		Spec: apiv1.RestoreSpec{
+			RecoveryTarget: &apiv1.RecoveryTarget{TargetTime: "2024-05-10 12:00:00+00"},
+		},
+		Status: apiv1.RestoreStatus{Backup: "backup-recent"},
+	}
+
+	It("recovers the cluster from the selected backup up to the target", func() {
+		result := getInPlaceRestoreCluster(cluster, restore)
+		Expect(result.Spec.Bootstrap.InitDB).To(BeNil())
+		Expect(result.Spec.Bootstrap.Recovery.Backup.Name).To(Equal("backup-recent"))
+		Expect(result.Spec.Bootstrap.Recovery.RecoveryTarget.TargetTime).To(Equal("2024-05-10 12:00:00+00"))
+
+		By("leaving the original cluster untouched", func() {
+			Expect(cluster.Spec.Bootstrap.InitDB).ToNot(BeNil())
+			Expect(cluster.Spec.Bootstrap.Recovery).To(BeNil())
+		})
+	})
+
+	It("removes the data directory, the WAL and the tablespaces", func() {
+		initInfo := InitInfo{
+			PgData: "/var/lib/postgresql/data/pgdata",
+			PgWal:  "/var/lib/postgresql/wal/pg_wal",
+		}
+		Expect(initInfo.getInPlaceRestoreDirectories(cluster)).To(Equal([]string{
+			"/var/lib/postgresql/data/pgdata",
+			"/var/lib/postgresql/wal/pg_wal",
+			"/var/lib/postgresql/tablespaces/tbs1/data",
+		}))
+	})
+})
@@ -20,10 +20,12 @@ import (
 	"context"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/thoas/go-funk"
 	"k8s.io/utils/strings/slices"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -148,3 +150,102 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(chg).To(BeFalse())
 	})
 })
+
+var _ = Describe("barman-cloud-restore options", func() {
+	backup := &apiv1.Backup{
+		Status: apiv1.BackupStatus{
+			BarmanCredentials: apiv1.BarmanCredentials{
+				AWS: &apiv1.S3Credentials{},
+			},
+			EndpointURL:     "https://s3.example.com",
+			DestinationPath: "s3://backups/",
+			ServerName:      "cluster-example",
+			BackupID:        "20240101T000000",
+		},
+	}
+	initInfo := InitInfo{PgData: "/var/lib/postgresql/data/pgdata"}
+
+	It("restores the data directory from the given backup", func() {
+		options, err := initInfo.getRestoreDataDirOptions(backup, &apiv1.Cluster{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options[:5]).To(Equal([]string{
+			"--endpoint-url", "https://s3.example.com",
+			"s3://backups/", "cluster-example", "20240101T000000",
+		}))
+		Expect(options[len(options)-1]).To(Equal("/var/lib/postgresql/data/pgdata"))
+	})
+
+	It("relocates the tablespaces into their volumes", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					{Name: "tbs1"},
+					{Name: "tbs2"},
+				},
+			},
+		}
+
+		options, err := initInfo.getRestoreDataDirOptions(backup, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Join(options, " ")).To(ContainSubstring(
+			"--tablespace tbs1:/var/lib/postgresql/tablespaces/tbs1/data " +
+				"--tablespace tbs2:/var/lib/postgresql/tablespaces/tbs2/data " +
+				"/var/lib/postgresql/data/pgdata"))
+	})
+
+	It("decrypts the WAL files restored from an object store with client-side encryption", func() {
+		restoreCommand, err := buildRestoreCommand(backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restoreCommand).ToNot(ContainSubstring("wal-decrypt"))
+
+		encryptedBackup := backup.DeepCopy()
+		encryptedBackup.Status.WALClientSideEncryption = &apiv1.WALClientSideEncryption{
+			KeysSecret: apiv1.LocalObjectReference{Name: "wal-keys"},
+			ActiveKey:  "current",
+		}
+		restoreCommand, err = buildRestoreCommand(encryptedBackup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restoreCommand).To(HaveSuffix("%f %p && /controller/manager wal-decrypt %p"))
+		Expect(getBackupObjectStoreConfiguration(encryptedBackup).Wal.ClientSideEncryption).To(
+			Equal(encryptedBackup.Status.WALClientSideEncryption))
+	})
+})
+
+var _ = Describe("adopting a data directory from an existing PVC", func() {
+	var initInfo InitInfo
+
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+		},
+	}
+	controldata := func() (string, error) {
+		return "pg_control version number:            1300\n" +
+			"Database system identifier:           7318379935542337564\n", nil
+	}
+
+	BeforeEach(func() {
+		initInfo = InitInfo{PgData: GinkgoT().TempDir()}
+		Expect(os.WriteFile(path.Join(initInfo.PgData, "PG_VERSION"), []byte("16\n"), 0o600)).To(Succeed())
+	})
+
+	It("accepts a data directory of the same major version", func() {
+		Expect(initInfo.verifyAdoptedDataDirectory(
+			&apiv1.VolumeSourceRecovery{}, cluster, controldata)).To(Succeed())
+	})
+
+	It("refuses a data directory of a different major version", func() {
+		Expect(os.WriteFile(path.Join(initInfo.PgData, "PG_VERSION"), []byte("15\n"), 0o600)).To(Succeed())
+		err := initInfo.verifyAdoptedDataDirectory(&apiv1.VolumeSourceRecovery{}, cluster, controldata)
+		Expect(err).To(MatchError(ContainSubstring("belongs to PostgreSQL 15")))
+	})
+
+	It("checks the system identifier when requested", func() {
+		Expect(initInfo.verifyAdoptedDataDirectory(
+			&apiv1.VolumeSourceRecovery{SystemID: "7318379935542337564"}, cluster, controldata)).To(Succeed())
+
+		err := initInfo.verifyAdoptedDataDirectory(
+			&apiv1.VolumeSourceRecovery{SystemID: "1234"}, cluster, controldata)
+		Expect(err).To(MatchError(ContainSubstring(`"1234" was expected`)))
+	})
+})
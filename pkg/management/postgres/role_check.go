@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RoleCheck is the role of an instance, as needed by the load balancers
+// routing the connections to the primary or to the replicas
+type RoleCheck struct {
+	// True when the instance is not in recovery
+	IsPrimary bool
+
+	// True when the replica is streaming from its upstream
+	IsStreaming bool
+
+	// The amount of WAL reported by the upstream that the replica
+	// still has to replay, in bytes. Only meaningful when streaming
+	LagBytes int64
+}
+
+// CheckRole reads the role of the instance and, for a replica, how far
+// it is behind its upstream
+func (instance *Instance) CheckRole(ctx context.Context) (*RoleCheck, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return checkRole(ctx, superUserDB)
+}
+
+// checkRole reads the role of the instance using the passed connection.
+// The lag is measured against the latest WAL location reported by the
+// upstream to the WAL receiver
+func checkRole(ctx context.Context, db *sql.DB) (*RoleCheck, error) {
+	var result RoleCheck
+	var lagBytes sql.NullInt64
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT
+			NOT pg_is_in_recovery(),
+			(SELECT GREATEST(pg_wal_lsn_diff(latest_end_lsn, pg_last_wal_replay_lsn()), 0)::bigint
+			 FROM pg_catalog.pg_stat_wal_receiver
+			 WHERE status = 'streaming')
+		`)
+	if err := row.Scan(&result.IsPrimary, &lagBytes); err != nil {
+		return nil, err
+	}
+
+	result.IsStreaming = lagBytes.Valid
+	result.LagBytes = lagBytes.Int64
+	return &result, nil
+}
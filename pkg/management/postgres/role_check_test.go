@@ -0,0 +1,68 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("role check", func() {
+	query := regexp.QuoteMeta("SELECT NOT pg_is_in_recovery(), " +
+		"(SELECT GREATEST(pg_wal_lsn_diff(latest_end_lsn, pg_last_wal_replay_lsn()), 0)::bigint " +
+		"FROM pg_catalog.pg_stat_wal_receiver WHERE status = 'streaming')")
+
+	It("reports a primary", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"is_primary", "lag"}).AddRow(true, nil))
+
+		result, err := checkRole(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(&RoleCheck{IsPrimary: true}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports the lag of a streaming replica", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"is_primary", "lag"}).AddRow(false, 1024))
+
+		result, err := checkRole(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(&RoleCheck{IsStreaming: true, LagBytes: 1024}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports a replica that is not streaming", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"is_primary", "lag"}).AddRow(false, nil))
+
+		result, err := checkRole(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(&RoleCheck{}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetParsedControlData returns the output of pg_controldata as a map
+// of the reported fields
+func (instance *Instance) GetParsedControlData() (map[string]string, error) {
+	out, err := instance.GetPgControldata()
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParsePgControldataOutput(out), nil
+}
+
+// GetNonDefaultSettings returns the configuration parameters whose value
+// doesn't come from the built-in defaults
+func (instance *Instance) GetNonDefaultSettings(ctx context.Context) ([]postgres.Setting, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return getNonDefaultSettings(ctx, superUserDB)
+}
+
+// getNonDefaultSettings reads the parameters not set to their default
+// value using the passed connection
+func getNonDefaultSettings(ctx context.Context, db *sql.DB) ([]postgres.Setting, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT name, setting, COALESCE(unit, ''), source, COALESCE(boot_val, ''), pending_restart
+		FROM pg_catalog.pg_settings
+		WHERE source <> 'default'
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	settings := make([]postgres.Setting, 0)
+	for rows.Next() {
+		var setting postgres.Setting
+		if err := rows.Scan(
+			&setting.Name,
+			&setting.Setting,
+			&setting.Unit,
+			&setting.Source,
+			&setting.BootValue,
+			&setting.PendingRestart,
+		); err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("non-default settings", func() {
+	const settingsQuery = "FROM pg_catalog.pg_settings WHERE source <> 'default'"
+
+	It("reports the parameters not set to their default value", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta(settingsQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"name", "setting", "unit", "source", "boot_val", "pending_restart",
+			}).
+				AddRow("max_connections", "200", "", "configuration file", "100", true).
+				AddRow("shared_buffers", "16384", "8kB", "configuration file", "16384", false))
+
+		settings, err := getNonDefaultSettings(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(settings).To(Equal([]postgres.Setting{
+			{
+				Name:           "max_connections",
+				Setting:        "200",
+				Source:         "configuration file",
+				BootValue:      "100",
+				PendingRestart: true,
+			},
+			{
+				Name:      "shared_buffers",
+				Setting:   "16384",
+				Unit:      "8kB",
+				Source:    "configuration file",
+				BootValue: "16384",
+			},
+		}))
+	})
+
+	It("fails when the query fails", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta(settingsQuery)).WillReturnError(errors.New("boom"))
+
+		_, err = getNonDefaultSettings(ctx, db)
+		Expect(err).To(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
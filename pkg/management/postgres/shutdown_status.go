@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// shutdownStatusTracker keeps track of the phase of the shutdown
+// procedure of the instance
+type shutdownStatusTracker struct {
+	mu     sync.Mutex
+	status postgres.ShutdownStatus
+}
+
+// set records the beginning of a new phase at the passed time
+func (tracker *shutdownStatusTracker) set(phase postgres.ShutdownPhase, timeout int32, now time.Time) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.status = postgres.ShutdownStatus{
+		Phase:          phase,
+		PhaseStartedAt: now.UTC().Format(time.RFC3339),
+		PhaseTimeout:   timeout,
+	}
+}
+
+// reset marks the instance as running
+func (tracker *shutdownStatusTracker) reset() {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.status = postgres.ShutdownStatus{}
+}
+
+// get returns the status of the shutdown procedure
+func (tracker *shutdownStatusTracker) get() postgres.ShutdownStatus {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if tracker.status.Phase == "" {
+		return postgres.ShutdownStatus{Phase: postgres.ShutdownPhaseRunning}
+	}
+	return tracker.status
+}
+
+// GetShutdownStatus gets the progress of the shutdown procedure of the instance
+func (instance *Instance) GetShutdownStatus() postgres.ShutdownStatus {
+	return instance.shutdownStatus.get()
+}
+
+// setShutdownPhase records the beginning of a phase of the shutdown procedure
+func (instance *Instance) setShutdownPhase(phase postgres.ShutdownPhase, timeout int32) {
+	instance.shutdownStatus.set(phase, timeout, time.Now())
+}
@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("shutdown status", func() {
+	It("reports a running instance when no shutdown was requested", func() {
+		var tracker shutdownStatusTracker
+		Expect(tracker.get()).To(Equal(postgres.ShutdownStatus{Phase: postgres.ShutdownPhaseRunning}))
+	})
+
+	It("reports the current phase with its timeout", func() {
+		var tracker shutdownStatusTracker
+		now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+		tracker.set(postgres.ShutdownPhaseSmart, 180, now)
+		tracker.set(postgres.ShutdownPhaseFast, 60, now.Add(3*time.Minute))
+		Expect(tracker.get()).To(Equal(postgres.ShutdownStatus{
+			Phase:          postgres.ShutdownPhaseFast,
+			PhaseStartedAt: "2024-05-01T10:03:00Z",
+			PhaseTimeout:   60,
+		}))
+	})
+
+	It("reports a running instance after a restart", func() {
+		var tracker shutdownStatusTracker
+		tracker.set(postgres.ShutdownPhaseCompleted, 0, time.Now())
+		tracker.reset()
+		Expect(tracker.get().Phase).To(Equal(postgres.ShutdownPhaseRunning))
+	})
+})
@@ -0,0 +1,376 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	pgChecksumsName = "pg_checksums"
+
+	// startupCheckMaxMessageLength is the maximum length of the
+	// description of a failure that is reported in the status
+	startupCheckMaxMessageLength = 256
+)
+
+// startupCheckTracker keeps the configuration of the startup consistency
+// checks and the result of their last execution
+type startupCheckTracker struct {
+	mu     sync.Mutex
+	config *apiv1.StartupCheckConfiguration
+	result *postgres.StartupCheckResult
+}
+
+// setConfiguration replaces the configuration of the checks
+func (tracker *startupCheckTracker) setConfiguration(config *apiv1.StartupCheckConfiguration) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.config = config.DeepCopy()
+}
+
+// getConfiguration gets the configuration of the checks
+func (tracker *startupCheckTracker) getConfiguration() *apiv1.StartupCheckConfiguration {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.config.DeepCopy()
+}
+
+// setResult stores the result of the last execution of the checks
+func (tracker *startupCheckTracker) setResult(result postgres.StartupCheckResult) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.result = &result
+}
+
+// getResult gets the result of the last execution of the checks
+func (tracker *startupCheckTracker) getResult() *postgres.StartupCheckResult {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if tracker.result == nil {
+		return nil
+	}
+	result := *tracker.result
+	return &result
+}
+
+// SetStartupCheckConfiguration replaces the configuration of the consistency
+// checks executed before starting PostgreSQL
+func (instance *Instance) SetStartupCheckConfiguration(config *apiv1.StartupCheckConfiguration) {
+	instance.startupCheck.setConfiguration(config)
+}
+
+// GetStartupCheckResult gets the result of the last execution of the
+// startup consistency checks, nil if they never ran
+func (instance *Instance) GetStartupCheckResult() *postgres.StartupCheckResult {
+	return instance.startupCheck.getResult()
+}
+
+// IsQuarantined checks whether PostgreSQL has not been started because
+// the data directory failed the startup consistency checks
+func (instance *Instance) IsQuarantined() bool {
+	result := instance.startupCheck.getResult()
+	return result != nil && result.Quarantined
+}
+
+// RunStartupCheck validates the control file and verifies the data
+// checksums, as configured, when the data directory has not been cleanly
+// shut down. It returns true when the instance is quarantined, and
+// PostgreSQL must not be started
+func (instance *Instance) RunStartupCheck(ctx context.Context) bool {
+	contextLogger := log.FromContext(ctx).WithName("startup_check")
+
+	config := instance.startupCheck.getConfiguration()
+	if !config.IsEnabled() {
+		return false
+	}
+
+	quarantineFile := filepath.Join(instance.PgData, constants.QuarantineFile)
+	if config.Quarantine {
+		// A quarantined instance stays quarantined until its data
+		// directory is re-created, even if the crash recovery we
+		// completed to verify the checksums made it look clean
+		content, err := fileutils.ReadFile(quarantineFile)
+		if err != nil {
+			contextLogger.Error(err, "while reading the quarantine file")
+		}
+		if len(content) > 0 {
+			contextLogger.Warning("The instance is quarantined, PostgreSQL won't be started",
+				"reason", string(content))
+			instance.startupCheck.setResult(postgres.StartupCheckResult{
+				Time:        time.Now().UTC().Format(time.RFC3339),
+				Message:     string(content),
+				Quarantined: true,
+			})
+			return true
+		}
+	}
+
+	result := instance.checkDataDirectory(ctx, config)
+	if result == nil {
+		return false
+	}
+
+	if !result.Passed && config.Quarantine {
+		isPrimary, err := instance.IsPrimary()
+		if err != nil {
+			contextLogger.Error(err, "while checking the role of the instance, not quarantining it")
+		}
+		if err == nil && !isPrimary {
+			if _, err := fileutils.WriteStringToFile(quarantineFile, result.Message); err != nil {
+				contextLogger.Error(err, "while writing the quarantine file")
+			}
+			result.Quarantined = true
+		}
+	}
+	instance.startupCheck.setResult(*result)
+
+	if result.Passed {
+		contextLogger.Info("The data directory passed the startup consistency checks",
+			"verifiedFiles", result.VerifiedFiles,
+			"message", result.Message)
+	} else {
+		contextLogger.Warning("The data directory failed the startup consistency checks",
+			"quarantined", result.Quarantined,
+			"message", result.Message)
+	}
+
+	return result.Quarantined
+}
+
+// checkDataDirectory executes the consistency checks of the data
+// directory, returning nil when it has been cleanly shut down
+func (instance *Instance) checkDataDirectory(
+	ctx context.Context,
+	config *apiv1.StartupCheckConfiguration,
+) *postgres.StartupCheckResult {
+	contextLogger := log.FromContext(ctx).WithName("startup_check")
+
+	result := &postgres.StartupCheckResult{
+		Time:   time.Now().UTC().Format(time.RFC3339),
+		Passed: true,
+	}
+
+	controlData, err := instance.GetPgControldata()
+	if err != nil {
+		result.Passed = false
+		result.Message = truncateStartupCheckMessage(fmt.Sprintf("invalid control file: %v", err))
+		return result
+	}
+
+	state, checksumsEnabled, err := validateControlData(controlData)
+	if err != nil {
+		result.Passed = false
+		result.Message = truncateStartupCheckMessage(fmt.Sprintf("invalid control file: %v", err))
+		return result
+	}
+	if state == utils.PgControlDataDatabaseClusterStateShutDown ||
+		state == utils.PgControlDataDatabaseClusterStateShutDownInRecovery {
+		contextLogger.Debug("The data directory has been cleanly shut down, skipping the startup checks")
+		return nil
+	}
+
+	contextLogger.Info("Unclean shutdown detected, checking the consistency of the data directory",
+		"state", state,
+		"checksums", config.GetChecksumsMode())
+
+	switch {
+	case config.GetChecksumsMode() == apiv1.StartupChecksumsDisabled:
+		result.Message = "control file validated"
+
+	case !checksumsEnabled:
+		result.Message = "control file validated, data checksums are not enabled"
+
+	default:
+		// pg_checksums requires a cleanly shut down data directory
+		if err := instance.CompleteCrashRecovery(ctx); err != nil {
+			result.Passed = false
+			result.Message = truncateStartupCheckMessage(fmt.Sprintf("crash recovery failed: %v", err))
+			return result
+		}
+
+		result.VerifiedFiles, err = instance.verifyDataChecksums(ctx, config)
+		if err != nil {
+			result.Passed = false
+			result.Message = truncateStartupCheckMessage(err.Error())
+			return result
+		}
+		result.Message = fmt.Sprintf("control file validated, data checksums verified (%s)",
+			config.GetChecksumsMode())
+	}
+
+	return result
+}
+
+// validateControlData checks the output of pg_controldata, returning the
+// state of the database cluster and whether the data checksums are enabled
+func validateControlData(controlData string) (string, bool, error) {
+	// pg_controldata reports a mismatching CRC, or values that can't
+	// be trusted, with a warning preceding the content of the file
+	for _, line := range strings.Split(controlData, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "WARNING:") {
+			return "", false, errors.New(strings.TrimSpace(line))
+		}
+	}
+
+	parsed := utils.ParsePgControldataOutput(controlData)
+	state, ok := parsed[utils.PgControlDataKeyDatabaseClusterState]
+	if !ok || state == "" {
+		return "", false, fmt.Errorf("missing %q", utils.PgControlDataKeyDatabaseClusterState)
+	}
+
+	checksumVersion := parsed[utils.PgControlDataKeyDataPageChecksumVersion]
+	return state, checksumVersion != "" && checksumVersion != "0", nil
+}
+
+// verifyDataChecksums verifies the data checksums with pg_checksums, of
+// every relation or of a random sample of them, returning the number of
+// verified files
+func (instance *Instance) verifyDataChecksums(
+	ctx context.Context,
+	config *apiv1.StartupCheckConfiguration,
+) (int64, error) {
+	if config.GetChecksumsMode() == apiv1.StartupChecksumsFull {
+		return instance.runPgChecksums(ctx)
+	}
+
+	filenodes, err := sampleRelationFilenodes(instance.PgData, config.GetSampleSize())
+	if err != nil {
+		return 0, fmt.Errorf("while sampling the relations: %w", err)
+	}
+
+	var verifiedFiles int64
+	for _, filenode := range filenodes {
+		files, err := instance.runPgChecksums(ctx, "--filenode", filenode)
+		verifiedFiles += files
+		if err != nil {
+			return verifiedFiles, err
+		}
+	}
+	return verifiedFiles, nil
+}
+
+// runPgChecksums runs pg_checksums in check mode, returning the number
+// of scanned files and an error describing the bad checksums, if any
+func (instance *Instance) runPgChecksums(ctx context.Context, args ...string) (int64, error) {
+	options := append([]string{"--check", "--pgdata", instance.PgData}, args...)
+	cmd := exec.CommandContext(ctx, pgChecksumsName, options...) // #nosec
+	cmd.Env = instance.Env
+	output, err := cmd.CombinedOutput()
+
+	summary := utils.ParsePgControldataOutput(string(output))
+	scanned, _ := strconv.ParseInt(summary["Files scanned"], 10, 64)
+	if err == nil {
+		return scanned, nil
+	}
+
+	if bad, _ := strconv.ParseInt(summary["Bad checksums"], 10, 64); bad > 0 {
+		return scanned, fmt.Errorf("%d bad data checksums: %s", bad, firstChecksumFailure(string(output)))
+	}
+	return scanned, fmt.Errorf("while running pg_checksums: %w: %s", err, strings.TrimSpace(string(output)))
+}
+
+// firstChecksumFailure gets the first failure reported by pg_checksums
+func firstChecksumFailure(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "checksum verification failed") {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+// sampleRelationFilenodes gets a random sample of the filenodes of the
+// relations stored in the default and global tablespaces
+func sampleRelationFilenodes(pgData string, size int) ([]string, error) {
+	directories, err := filepath.Glob(filepath.Join(pgData, "base", "*"))
+	if err != nil {
+		return nil, err
+	}
+	directories = append(directories, filepath.Join(pgData, "global"))
+
+	seen := make(map[string]bool)
+	var filenodes []string
+	for _, directory := range directories {
+		entries, err := os.ReadDir(directory)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			// the main fork of a relation is named after its filenode,
+			// with a numeric suffix for the segments after the first one
+			filenode, _, _ := strings.Cut(entry.Name(), ".")
+			if entry.IsDir() || !isFilenode(filenode) || seen[filenode] {
+				continue
+			}
+			seen[filenode] = true
+			filenodes = append(filenodes, filenode)
+		}
+	}
+
+	// the sample doesn't need a cryptographically secure source
+	rand.Shuffle(len(filenodes), func(i, j int) { // #nosec G404
+		filenodes[i], filenodes[j] = filenodes[j], filenodes[i]
+	})
+	if len(filenodes) > size {
+		filenodes = filenodes[:size]
+	}
+	return filenodes, nil
+}
+
+// isFilenode checks whether the passed file name is a relation filenode
+func isFilenode(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// truncateStartupCheckMessage limits the length of the description
+// of a failure reported in the status
+func truncateStartupCheckMessage(message string) string {
+	if len(message) > startupCheckMaxMessageLength {
+		return message[:startupCheckMaxMessageLength]
+	}
+	return message
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("startup consistency checks", func() {
+	Context("control file validation", func() {
+		It("gets the state and the checksums of a valid control file", func() {
+			state, checksums, err := validateControlData(
+				"pg_control version number:            1300\n" +
+					"Database cluster state:               in production\n" +
+					"Data page checksum version:           1\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state).To(Equal("in production"))
+			Expect(checksums).To(BeTrue())
+		})
+
+		It("detects the disabled checksums", func() {
+			_, checksums, err := validateControlData(
+				"Database cluster state:               shut down\n" +
+					"Data page checksum version:           0\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checksums).To(BeFalse())
+		})
+
+		It("rejects a control file with a mismatching CRC", func() {
+			_, _, err := validateControlData(
+				"WARNING: Calculated CRC checksum does not match value stored in file.\n" +
+					"Either the file is corrupt, or it has a different layout than this program\n" +
+					"Database cluster state:               in production\n")
+			Expect(err).To(MatchError(ContainSubstring("Calculated CRC checksum")))
+		})
+
+		It("rejects a control file without the cluster state", func() {
+			_, _, err := validateControlData("pg_control version number:            1300\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("samples the relation filenodes of the default and global tablespaces", func() {
+		pgData := GinkgoT().TempDir()
+		for _, file := range []string{
+			"base/1/1234", "base/1/1234.1", "base/1/1234_fsm", "base/1/PG_VERSION",
+			"base/5/5678", "global/1260", "global/pg_filenode.map",
+		} {
+			Expect(os.MkdirAll(filepath.Dir(filepath.Join(pgData, file)), 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(pgData, file), nil, 0o600)).To(Succeed())
+		}
+
+		filenodes, err := sampleRelationFilenodes(pgData, 100)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filenodes).To(ConsistOf("1234", "5678", "1260"))
+
+		filenodes, err = sampleRelationFilenodes(pgData, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filenodes).To(HaveLen(2))
+	})
+
+	Context("quarantine", func() {
+		var instance *Instance
+
+		BeforeEach(func() {
+			instance = &Instance{PgData: GinkgoT().TempDir()}
+		})
+
+		It("does nothing when disabled", func(ctx context.Context) {
+			instance.SetStartupCheckConfiguration(&apiv1.StartupCheckConfiguration{Quarantine: true})
+			Expect(instance.RunStartupCheck(ctx)).To(BeFalse())
+			Expect(instance.GetStartupCheckResult()).To(BeNil())
+		})
+
+		It("keeps an instance quarantined until its data directory is re-created", func(ctx context.Context) {
+			Expect(os.WriteFile(filepath.Join(instance.PgData, constants.QuarantineFile),
+				[]byte("3 bad data checksums"), 0o600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(instance.PgData, "standby.signal"), nil, 0o600)).To(Succeed())
+			instance.SetStartupCheckConfiguration(&apiv1.StartupCheckConfiguration{
+				Enabled:    true,
+				Quarantine: true,
+			})
+
+			Expect(instance.RunStartupCheck(ctx)).To(BeTrue())
+			Expect(instance.IsQuarantined()).To(BeTrue())
+			Expect(instance.GetStartupCheckResult().Message).To(Equal("3 bad data checksums"))
+
+			status, err := instance.GetStatus()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.IsPrimary).To(BeFalse())
+			Expect(status.StartupCheck).ToNot(BeNil())
+			Expect(status.StartupCheck.Quarantined).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,207 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// dataEncryptionKeyFile is the path, relative to PGDATA, of the
+// file containing the wrapped data encryption key
+const dataEncryptionKeyFile = "pg_encryption/key.bin"
+
+// tdeTracker remembers which passphrase is known to be wrapping
+// the data encryption key
+type tdeTracker struct {
+	mu            sync.Mutex
+	passphrase    string
+	secretVersion string
+}
+
+// set records the passphrase wrapping the data encryption key, and the
+// version of the TDE secret containing it
+func (tracker *tdeTracker) set(passphrase, secretVersion string) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.passphrase = passphrase
+	tracker.secretVersion = secretVersion
+}
+
+// isWrappedWith checks if the data encryption key is known to be
+// wrapped with the passed passphrase
+func (tracker *tdeTracker) isWrappedWith(passphrase string) bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.secretVersion != "" && tracker.passphrase == passphrase
+}
+
+// getSecretVersion gets the version of the TDE secret containing
+// the passphrase wrapping the data encryption key
+func (tracker *tdeTracker) getSecretVersion() string {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.secretVersion
+}
+
+// GetTDESecretVersion gets the resource version of the TDE secret whose
+// passphrase is wrapping the data encryption key, empty if the key
+// has not been checked yet
+func (instance *Instance) GetTDESecretVersion() string {
+	return instance.tde.getSecretVersion()
+}
+
+// RewrapDataEncryptionKey ensures that the data encryption key is wrapped
+// with the passed passphrase, which is then used for every subsequent start
+// of PostgreSQL. The key is unwrapped with the first passphrase that works
+// among the passed one, the one currently in use and the ones used before
+// the rotation, which are kept by the operator until every instance has
+// wrapped its key again. This makes the rotation safe even when the
+// instance is restarted with the new passphrase before having wrapped the
+// key with it. Returns true if the key has been wrapped again.
+func (instance *Instance) RewrapDataEncryptionKey(
+	ctx context.Context,
+	tde *apiv1.TDEConfiguration,
+	secretVersion string,
+	passphrase string,
+	previousPassphrases []string,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if instance.tde.isWrappedWith(passphrase) {
+		instance.tde.set(passphrase, secretVersion)
+		return false, nil
+	}
+
+	keyFile := filepath.Join(instance.PgData, dataEncryptionKeyFile)
+	exists, err := fileutils.FileExists(keyFile)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		instance.tde.set(passphrase, secretVersion)
+		return false, nil
+	}
+
+	currentEnv := instance.getEnvironment()
+	candidates := []string{passphrase, instance.getTDEPassphrase()}
+	candidates = append(candidates, previousPassphrases...)
+
+	var key bytes.Buffer
+	var unwrapErr error
+	unwrappingPassphrase := ""
+	tried := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate == "" || slices.Contains(tried, candidate) {
+			continue
+		}
+		tried = append(tried, candidate)
+		key.Reset()
+		unwrapCmd := exec.Command("sh", "-c", expandKeyFilePath(tde.GetUnwrapCommand(), keyFile)) // #nosec G204
+		unwrapCmd.Env = setEnvironmentVariable(currentEnv, apiv1.TDEPassphraseEnvVar, candidate)
+		unwrapCmd.Stdout = &key
+		if unwrapErr = unwrapCmd.Run(); unwrapErr == nil {
+			unwrappingPassphrase = candidate
+			break
+		}
+	}
+	if unwrappingPassphrase == "" {
+		return false, fmt.Errorf("while unwrapping the data encryption key with any of the known passphrases: %w",
+			unwrapErr)
+	}
+
+	newEnv := setEnvironmentVariable(currentEnv, apiv1.TDEPassphraseEnvVar, passphrase)
+	if unwrappingPassphrase == passphrase {
+		instance.Env = newEnv
+		instance.tde.set(passphrase, secretVersion)
+		return false, nil
+	}
+
+	contextLogger.Info("TDE passphrase changed, wrapping the data encryption key again",
+		"keyFile", keyFile)
+
+	newKeyFile := keyFile + ".new"
+	wrapCmd := exec.Command("sh", "-c", expandKeyFilePath(tde.GetWrapCommand(), newKeyFile)) // #nosec G204
+	wrapCmd.Env = newEnv
+	wrapCmd.Stdin = &key
+	if err := wrapCmd.Run(); err != nil {
+		_ = os.Remove(newKeyFile)
+		return false, fmt.Errorf("while wrapping the data encryption key: %w", err)
+	}
+
+	if err := os.Rename(newKeyFile, keyFile); err != nil {
+		return false, fmt.Errorf("while replacing the data encryption key: %w", err)
+	}
+
+	instance.Env = newEnv
+	instance.tde.set(passphrase, secretVersion)
+	return true, nil
+}
+
+// getTDEPassphrase gets the passphrase currently used to wrap the
+// data encryption key
+func (instance *Instance) getTDEPassphrase() string {
+	prefix := apiv1.TDEPassphraseEnvVar + "="
+	for _, entry := range instance.getEnvironment() {
+		if strings.HasPrefix(entry, prefix) {
+			return strings.TrimPrefix(entry, prefix)
+		}
+	}
+	return ""
+}
+
+// getEnvironment gets the environment of the processes started by the
+// instance manager, which is inherited when not explicitly set
+func (instance *Instance) getEnvironment() []string {
+	if instance.Env != nil {
+		return instance.Env
+	}
+	return os.Environ()
+}
+
+// setEnvironmentVariable returns a copy of the passed environment where the
+// variable has the passed value
+func setEnvironmentVariable(env []string, name, value string) []string {
+	prefix := name + "="
+	result := make([]string, 0, len(env)+1)
+	for _, entry := range env {
+		if !strings.HasPrefix(entry, prefix) {
+			result = append(result, entry)
+		}
+	}
+	return append(result, prefix+value)
+}
+
+// expandKeyFilePath replaces the `%p` placeholder in a key wrapping
+// command with the path of the key file
+func expandKeyFilePath(command, keyFile string) string {
+	return strings.ReplaceAll(command, "%p", keyFile)
+}
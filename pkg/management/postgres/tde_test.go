@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("data encryption key rotation", func() {
+	// These commands "wrap" the key by prefixing it with the passphrase,
+	// and refuse to unwrap it if the passphrase doesn't match
+	tde := &apiv1.TDEConfiguration{
+		Enabled:       true,
+		WrapCommand:   `{ printf '%s:' "$PGPASSPHRASE"; cat; } > "%p"`,
+		UnwrapCommand: `grep -q "^$PGPASSPHRASE:" "%p" && cut -d: -f2- "%p"`,
+	}
+
+	var instance *Instance
+	var keyFile string
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		instance = NewInstance()
+		instance.PgData = tempDir
+		instance.Env = []string{"PATH=" + os.Getenv("PATH"), "PGPASSPHRASE=old"}
+
+		keyFile = filepath.Join(tempDir, dataEncryptionKeyFile)
+		Expect(fileutils.EnsureParentDirectoryExist(keyFile)).To(Succeed())
+		Expect(os.WriteFile(keyFile, []byte("old:secret-key\n"), 0o600)).To(Succeed())
+	})
+
+	It("does nothing when the passphrase didn't change", func() {
+		rewrapped, err := instance.RewrapDataEncryptionKey(context.Background(), tde, "1", "old", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rewrapped).To(BeFalse())
+		Expect(instance.GetTDESecretVersion()).To(Equal("1"))
+	})
+
+	It("wraps the key again with the new passphrase", func() {
+		rewrapped, err := instance.RewrapDataEncryptionKey(context.Background(), tde, "2", "new", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rewrapped).To(BeTrue())
+		Expect(os.ReadFile(keyFile)).To(BeEquivalentTo("new:secret-key\n"))
+		Expect(instance.getTDEPassphrase()).To(Equal("new"))
+		Expect(instance.Env).To(ContainElement("PGPASSPHRASE=new"))
+		Expect(instance.Env).ToNot(ContainElement("PGPASSPHRASE=old"))
+		Expect(instance.GetTDESecretVersion()).To(Equal("2"))
+	})
+
+	It("uses the previous passphrases when restarted with the new one", func() {
+		instance.Env = []string{"PATH=" + os.Getenv("PATH"), "PGPASSPHRASE=new"}
+		rewrapped, err := instance.RewrapDataEncryptionKey(
+			context.Background(), tde, "2", "new", []string{"older", "old"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rewrapped).To(BeTrue())
+		Expect(os.ReadFile(keyFile)).To(BeEquivalentTo("new:secret-key\n"))
+		Expect(instance.GetTDESecretVersion()).To(Equal("2"))
+	})
+
+	It("only checks the key when it is already wrapped with the new passphrase", func() {
+		Expect(os.WriteFile(keyFile, []byte("new:secret-key\n"), 0o600)).To(Succeed())
+		rewrapped, err := instance.RewrapDataEncryptionKey(context.Background(), tde, "2", "new", []string{"old"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rewrapped).To(BeFalse())
+		Expect(instance.getTDEPassphrase()).To(Equal("new"))
+		Expect(instance.GetTDESecretVersion()).To(Equal("2"))
+	})
+
+	It("keeps the current key if it can't be unwrapped", func() {
+		instance.Env = []string{"PATH=" + os.Getenv("PATH"), "PGPASSPHRASE=wrong"}
+		rewrapped, err := instance.RewrapDataEncryptionKey(context.Background(), tde, "2", "new", []string{"older"})
+		Expect(err).To(HaveOccurred())
+		Expect(rewrapped).To(BeFalse())
+		Expect(os.ReadFile(keyFile)).To(BeEquivalentTo("old:secret-key\n"))
+		Expect(instance.getTDEPassphrase()).To(Equal("wrong"))
+		Expect(instance.GetTDESecretVersion()).To(BeEmpty())
+	})
+
+	It("does nothing when the data directory is not encrypted", func() {
+		Expect(os.Remove(keyFile)).To(Succeed())
+		rewrapped, err := instance.RewrapDataEncryptionKey(context.Background(), tde, "2", "new", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rewrapped).To(BeFalse())
+	})
+})
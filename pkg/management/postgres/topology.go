@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// GetTopology reads the position of this instance in the replication
+// topology. The values are read one after the other in a single
+// transaction, but they come from shared memory and are not part of
+// its snapshot: they are close in time, not taken at the same instant
+func (instance *Instance) GetTopology(ctx context.Context) (*postgres.InstanceTopology, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return getTopology(ctx, superUserDB, instance.PodName, instance.ClusterName)
+}
+
+// getTopology reads the topology information using the passed connection
+func getTopology(
+	ctx context.Context,
+	db *sql.DB,
+	podName string,
+	clusterName string,
+) (*postgres.InstanceTopology, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result := &postgres.InstanceTopology{Pod: podName}
+	row := tx.QueryRowContext(
+		ctx,
+		"SELECT NOT pg_is_in_recovery(), (SELECT timeline_id FROM pg_control_checkpoint())")
+	if err := row.Scan(&result.IsPrimary, &result.TimeLineID); err != nil {
+		return nil, err
+	}
+
+	if result.IsPrimary {
+		err = fillTopologyFromPrimary(ctx, tx, result, clusterName)
+	} else {
+		err = fillTopologyFromReplica(ctx, tx, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+// fillTopologyFromPrimary reads the current WAL location of the primary
+// and the status of the replicas streaming from it
+func fillTopologyFromPrimary(
+	ctx context.Context,
+	tx *sql.Tx,
+	result *postgres.InstanceTopology,
+	clusterName string,
+) error {
+	row := tx.QueryRowContext(ctx, "SELECT pg_current_wal_lsn()")
+	if err := row.Scan(&result.CurrentLsn); err != nil {
+		return err
+	}
+
+	var err error
+	result.Replicas, err = getReplicationInfo(tx, clusterName)
+	return err
+}
+
+// fillTopologyFromReplica reads the WAL locations received and replayed
+// by a replica, and the upstream it is streaming from
+func fillTopologyFromReplica(
+	ctx context.Context,
+	tx *sql.Tx,
+	result *postgres.InstanceTopology,
+) error {
+	// pg_last_wal_receive_lsn may be NULL when using non-streaming
+	// replicas, and pg_stat_wal_receiver is empty when not streaming
+	row := tx.QueryRowContext(
+		ctx,
+		`
+		SELECT
+			COALESCE(pg_last_wal_receive_lsn()::varchar, ''),
+			COALESCE(pg_last_wal_replay_lsn()::varchar, ''),
+			COALESCE((SELECT sender_host FROM pg_catalog.pg_stat_wal_receiver LIMIT 1), ''),
+			COALESCE((SELECT status FROM pg_catalog.pg_stat_wal_receiver LIMIT 1), '')
+		`)
+	if err := row.Scan(
+		&result.ReceivedLsn,
+		&result.ReplayLsn,
+		&result.Upstream,
+		&result.WalReceiverStatus,
+	); err != nil {
+		return err
+	}
+
+	// The replay location may be evaluated after the receive one,
+	// see fillStatusFromReplica
+	if result.ReceivedLsn.Less(result.ReplayLsn) {
+		result.ReceivedLsn = result.ReplayLsn
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instance topology", func() {
+	const roleQuery = "SELECT NOT pg_is_in_recovery(), (SELECT timeline_id FROM pg_control_checkpoint())"
+
+	It("reports the current location and the replicas of a primary", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(roleQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"is_primary", "timeline_id"}).AddRow(true, 2))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_current_wal_lsn()")).
+			WillReturnRows(sqlmock.NewRows([]string{"lsn"}).AddRow("0/3000060"))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM pg_catalog.pg_stat_replication")).
+			WithArgs("cluster-example-[0-9]+$", "streaming_replica").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"application_name", "state", "sent_lsn", "write_lsn", "flush_lsn", "replay_lsn",
+				"write_lag", "flush_lag", "replay_lag", "sync_state", "sync_priority",
+			}).AddRow("cluster-example-2", "streaming", "0/3000060", "0/3000060", "0/3000060",
+				"0/3000000", "0", "0", "0", "quorum", "1"))
+		mock.ExpectCommit()
+
+		topology, err := getTopology(ctx, db, "cluster-example-1", "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(topology.Pod).To(Equal("cluster-example-1"))
+		Expect(topology.IsPrimary).To(BeTrue())
+		Expect(topology.TimeLineID).To(Equal(2))
+		Expect(topology.CurrentLsn).To(Equal(postgres.LSN("0/3000060")))
+		Expect(topology.Replicas).To(HaveLen(1))
+		Expect(topology.Replicas[0].ApplicationName).To(Equal("cluster-example-2"))
+		Expect(topology.Replicas[0].SyncState).To(Equal("quorum"))
+	})
+
+	It("reports the locations and the upstream of a replica", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(roleQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"is_primary", "timeline_id"}).AddRow(false, 2))
+		mock.ExpectQuery(regexp.QuoteMeta("pg_last_wal_receive_lsn()")).
+			WillReturnRows(sqlmock.NewRows([]string{"received", "replayed", "sender_host", "status"}).
+				AddRow("0/3000000", "0/3000060", "cluster-example-rw", "streaming"))
+		mock.ExpectCommit()
+
+		topology, err := getTopology(ctx, db, "cluster-example-2", "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(topology.IsPrimary).To(BeFalse())
+		Expect(topology.ReceivedLsn).To(Equal(postgres.LSN("0/3000060")))
+		Expect(topology.ReplayLsn).To(Equal(postgres.LSN("0/3000060")))
+		Expect(topology.Upstream).To(Equal("cluster-example-rw"))
+		Expect(topology.WalReceiverStatus).To(Equal("streaming"))
+		Expect(topology.Replicas).To(BeEmpty())
+	})
+})
@@ -179,6 +179,12 @@ func (w *walArchiveBootstrapper) ensureFirstWalArchived(backoff wait.Backoff) er
 }
 
 func (w *walArchiveBootstrapper) shipWalFile(db *sql.DB) error {
+	return checkpointAndSwitchWAL(db)
+}
+
+// checkpointAndSwitchWAL requires a checkpoint and then forces PostgreSQL
+// to switch to a new WAL file, making the current one ready to be archived
+func checkpointAndSwitchWAL(db *sql.DB) error {
 	if _, err := db.Exec("CHECKPOINT"); err != nil {
 		return fmt.Errorf("error while requiring a checkpoint: %w", err)
 	}
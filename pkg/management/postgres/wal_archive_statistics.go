@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// walArchiveStatisticsWindow is the time window used to compute
+// the throughput of the archive command
+const walArchiveStatisticsWindow = 5 * time.Minute
+
+// walArchiveSample is a batch of WAL files archived at a certain time
+type walArchiveSample struct {
+	time  time.Time
+	batch postgres.WALArchiveBatch
+}
+
+// walArchiveStatistics collects the batches reported by the wal-archive
+// process, which is run by PostgreSQL and can't keep any state by itself
+type walArchiveStatistics struct {
+	mu      sync.Mutex
+	samples []walArchiveSample
+}
+
+// record stores a batch archived at the passed time
+func (stats *walArchiveStatistics) record(batch postgres.WALArchiveBatch, now time.Time) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.prune(now)
+	stats.samples = append(stats.samples, walArchiveSample{time: now, batch: batch})
+}
+
+// fill sets the throughput of the archive command, averaged on the
+// statistics window, in the passed WAL archive status
+func (stats *walArchiveStatistics) fill(result *postgres.WALArchiveStatus, now time.Time) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.prune(now)
+	if len(stats.samples) == 0 {
+		return
+	}
+
+	var archived int
+	var uploadSeconds float64
+	for _, sample := range stats.samples {
+		archived += sample.batch.Archived
+		uploadSeconds += sample.batch.UploadSeconds
+	}
+
+	result.SegmentsPerMinute = float64(archived) / walArchiveStatisticsWindow.Minutes()
+	result.AverageUploadSeconds = uploadSeconds / float64(len(stats.samples))
+	result.Parallelism = stats.samples[len(stats.samples)-1].batch.Parallelism
+}
+
+// prune removes the samples that are out of the statistics window
+func (stats *walArchiveStatistics) prune(now time.Time) {
+	threshold := now.Add(-walArchiveStatisticsWindow)
+	idx := 0
+	for idx < len(stats.samples) && stats.samples[idx].time.Before(threshold) {
+		idx++
+	}
+	stats.samples = stats.samples[idx:]
+}
+
+// RecordWALArchiveBatch stores the outcome of an execution of the archive
+// command, to be reported in the WAL archive status
+func (instance *Instance) RecordWALArchiveBatch(batch postgres.WALArchiveBatch) {
+	instance.walArchiveStatistics.record(batch, time.Now())
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archive statistics", func() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	It("doesn't report anything when no batch was archived", func() {
+		var stats walArchiveStatistics
+		var result postgres.WALArchiveStatus
+		stats.fill(&result, now)
+		Expect(result.SegmentsPerMinute).To(BeZero())
+		Expect(result.AverageUploadSeconds).To(BeZero())
+		Expect(result.Parallelism).To(BeZero())
+	})
+
+	It("computes the throughput on the batches inside the window", func() {
+		var stats walArchiveStatistics
+		stats.record(postgres.WALArchiveBatch{Archived: 100, Parallelism: 8, UploadSeconds: 10},
+			now.Add(-10*time.Minute))
+		stats.record(postgres.WALArchiveBatch{Archived: 8, Parallelism: 8, UploadSeconds: 3},
+			now.Add(-2*time.Minute))
+		stats.record(postgres.WALArchiveBatch{Archived: 2, Parallelism: 2, UploadSeconds: 1},
+			now.Add(-time.Minute))
+
+		var result postgres.WALArchiveStatus
+		stats.fill(&result, now)
+		Expect(result.SegmentsPerMinute).To(BeNumerically("~", 2))
+		Expect(result.AverageUploadSeconds).To(BeNumerically("~", 2))
+		Expect(result.Parallelism).To(Equal(2))
+		Expect(stats.samples).To(HaveLen(2))
+	})
+})
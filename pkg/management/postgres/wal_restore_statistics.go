@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// walRestoreStatisticsWindow is the time window used to score the
+	// WAL files fetches from the object store. When the fetches are
+	// skipped in favor of streaming replication, the failures go out
+	// of the window and the object store is tried again
+	walRestoreStatisticsWindow = 5 * time.Minute
+
+	// walRestoreMinimumAttempts is the number of fetches in the window
+	// needed to score the object store
+	walRestoreMinimumAttempts = 3
+
+	// walRestoreFailureThreshold is the failure rate over which
+	// streaming replication is preferred to the object store
+	walRestoreFailureThreshold = 0.5
+)
+
+// walRestoreSample is the outcome of the restore command at a certain time
+type walRestoreSample struct {
+	time   time.Time
+	result postgres.WALRestoreResult
+}
+
+// walRestoreStatistics collects the results reported by the wal-restore
+// process, which is run by PostgreSQL and can't keep any state by itself,
+// and scores the object store to choose where to fetch the WAL files from
+type walRestoreStatistics struct {
+	mu                 sync.Mutex
+	samples            []walRestoreSample
+	totals             postgres.WALRestoreStatus
+	streamingAvailable bool
+	reported           bool
+}
+
+// record stores the result of the restore command at the passed time
+func (stats *walRestoreStatistics) record(result postgres.WALRestoreResult, now time.Time) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	previousMode := stats.mode(now)
+
+	stats.reported = true
+	stats.streamingAvailable = result.StreamingAvailable
+	stats.totals.RestoredCount += int64(result.Restored)
+	stats.totals.FailedCount += int64(result.Failed)
+	stats.totals.NotFoundCount += int64(result.NotFound)
+	if result.Skipped {
+		stats.totals.SkippedCount++
+	}
+	if result.Failed > 0 {
+		stats.totals.LastError = result.Error
+		stats.totals.LastFailureTime = now.Format(time.RFC3339)
+	}
+	if result.Restored > 0 || result.Failed > 0 {
+		stats.samples = append(stats.samples, walRestoreSample{time: now, result: result})
+	}
+
+	if mode := stats.mode(now); mode != previousMode {
+		log.Info("Changing the source of the WAL files restored by this instance",
+			"previousMode", previousMode,
+			"mode", mode,
+			"failureRate", stats.failureRate())
+	}
+}
+
+// status returns the status of the restore command at the passed time,
+// or nil if the restore command never reported any result
+func (stats *walRestoreStatistics) status(now time.Time) *postgres.WALRestoreStatus {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	if !stats.reported {
+		return nil
+	}
+
+	result := stats.totals
+	result.Mode = stats.mode(now)
+	result.FailureRate = stats.failureRate()
+	return &result
+}
+
+// mode chooses where to fetch the WAL files from, preferring streaming
+// replication when most of the fetches from the object store are failing
+func (stats *walRestoreStatistics) mode(now time.Time) postgres.WALRestoreMode {
+	stats.prune(now)
+
+	if !stats.streamingAvailable || stats.attempts() < walRestoreMinimumAttempts ||
+		stats.failureRate() < walRestoreFailureThreshold {
+		return postgres.WALRestoreModeArchive
+	}
+
+	return postgres.WALRestoreModeStreaming
+}
+
+// attempts is the number of WAL files fetches in the window
+func (stats *walRestoreStatistics) attempts() int {
+	var attempts int
+	for _, sample := range stats.samples {
+		attempts += sample.result.Restored + sample.result.Failed
+	}
+	return attempts
+}
+
+// failureRate is the ratio of the failed fetches in the window
+func (stats *walRestoreStatistics) failureRate() float64 {
+	attempts := stats.attempts()
+	if attempts == 0 {
+		return 0
+	}
+
+	var failed int
+	for _, sample := range stats.samples {
+		failed += sample.result.Failed
+	}
+	return float64(failed) / float64(attempts)
+}
+
+// prune removes the samples that are out of the statistics window
+func (stats *walRestoreStatistics) prune(now time.Time) {
+	threshold := now.Add(-walRestoreStatisticsWindow)
+	idx := 0
+	for idx < len(stats.samples) && stats.samples[idx].time.Before(threshold) {
+		idx++
+	}
+	stats.samples = stats.samples[idx:]
+}
+
+// RecordWALRestoreResult stores the outcome of an execution of the
+// restore command, to score the object store
+func (instance *Instance) RecordWALRestoreResult(result postgres.WALRestoreResult) {
+	instance.walRestoreStatistics.record(result, time.Now())
+}
+
+// GetWALRestoreStatus gets the status of the restore command, including
+// the preferred source of the WAL files, or nil if the restore command
+// never reported any result
+func (instance *Instance) GetWALRestoreStatus() *postgres.WALRestoreStatus {
+	return instance.walRestoreStatistics.status(time.Now())
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL restore statistics", func() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	It("doesn't report anything when the restore command never ran", func() {
+		var stats walRestoreStatistics
+		Expect(stats.status(now)).To(BeNil())
+	})
+
+	It("prefers the object store while the fetches succeed", func() {
+		var stats walRestoreStatistics
+		stats.record(postgres.WALRestoreResult{Restored: 8, StreamingAvailable: true}, now.Add(-time.Minute))
+		stats.record(postgres.WALRestoreResult{Restored: 1, NotFound: 7, StreamingAvailable: true}, now)
+
+		status := stats.status(now)
+		Expect(status).ToNot(BeNil())
+		Expect(status.Mode).To(Equal(postgres.WALRestoreModeArchive))
+		Expect(status.FailureRate).To(BeZero())
+		Expect(status.RestoredCount).To(BeEquivalentTo(9))
+		Expect(status.NotFoundCount).To(BeEquivalentTo(7))
+	})
+
+	It("prefers streaming replication when most of the fetches fail", func() {
+		var stats walRestoreStatistics
+		stats.record(postgres.WALRestoreResult{Restored: 1, StreamingAvailable: true}, now.Add(-2*time.Minute))
+		stats.record(postgres.WALRestoreResult{Failed: 4, Error: "timeout", StreamingAvailable: true},
+			now.Add(-time.Minute))
+
+		status := stats.status(now)
+		Expect(status.Mode).To(Equal(postgres.WALRestoreModeStreaming))
+		Expect(status.FailureRate).To(BeNumerically("~", 0.8))
+		Expect(status.FailedCount).To(BeEquivalentTo(4))
+		Expect(status.LastError).To(Equal("timeout"))
+		Expect(status.LastFailureTime).ToNot(BeEmpty())
+
+		By("trying the object store again once the failures are out of the window", func() {
+			stats.record(postgres.WALRestoreResult{Skipped: true, StreamingAvailable: true}, now)
+			Expect(stats.status(now).Mode).To(Equal(postgres.WALRestoreModeStreaming))
+
+			status := stats.status(now.Add(walRestoreStatisticsWindow))
+			Expect(status.Mode).To(Equal(postgres.WALRestoreModeArchive))
+			Expect(status.SkippedCount).To(BeEquivalentTo(1))
+		})
+	})
+
+	It("keeps using the object store when streaming replication is not available", func() {
+		var stats walRestoreStatistics
+		stats.record(postgres.WALRestoreResult{Failed: 8, StreamingAvailable: false}, now)
+		Expect(stats.status(now).Mode).To(Equal(postgres.WALRestoreModeArchive))
+	})
+
+	It("needs a minimum number of fetches to score the object store", func() {
+		var stats walRestoreStatistics
+		stats.record(postgres.WALRestoreResult{Failed: 1, StreamingAvailable: true}, now)
+		Expect(stats.status(now).Mode).To(Equal(postgres.WALRestoreModeArchive))
+	})
+})
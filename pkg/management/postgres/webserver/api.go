@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// apiOperation describes an HTTP method supported by a route of the
+// REST API, and is used to build the OpenAPI document
+type apiOperation struct {
+	// The HTTP method
+	method string
+
+	// A short description of the operation
+	summary string
+
+	// A value of the type of the JSON request body, nil if there's none
+	request any
+
+	// A value of the type of the JSON response payload, nil if there's none.
+	// Use oneOf when the payload can have different types
+	response any
+
+	// True when the payload is wrapped in the `data` field of a Response,
+	// the errors being reported in its `error` field
+	wrapped bool
+
+	// The content type of the response, when it's not JSON
+	contentType string
+}
+
+// oneOf is used as the response of an operation whose payload can be
+// of any of the given types
+type oneOf []any
+
+// apiRoute is a route of the REST API of the instance manager
+type apiRoute struct {
+	// The path of the route, without the version prefix. When the path
+	// ends with a slash, it is followed by a parameter
+	path string
+
+	// The name of the parameter following the path, if any
+	parameter string
+
+	// The operations supported by this route
+	operations []apiOperation
+
+	// The handler serving the route
+	handler http.HandlerFunc
+}
+
+// HandleAPI registers a route of the REST API under the prefix of the
+// current API version. The prefix is removed before invoking the handler,
+// which is also registered under the unversioned path, as served before
+// the API was versioned. Requests using the unversioned path get a
+// deprecation notice in the response headers
+func (mux *instrumentedServeMux) HandleAPI(route apiRoute) {
+	mux.Handle(url.Versioned(route.path), http.StripPrefix(url.APIVersionPrefix, route.handler))
+	mux.Handle(route.path, deprecatedRoute(route.path, route.handler))
+	mux.routes = append(mux.routes, route)
+}
+
+// HandleOpenAPI serves the OpenAPI document describing the routes
+// registered via HandleAPI, which must be called before
+func (mux *instrumentedServeMux) HandleOpenAPI(title string) {
+	document, err := json.Marshal(newOpenAPIDocument(title, mux.routes))
+	if err != nil {
+		// The document is built from the types of the handlers,
+		// this can only be a programming error
+		panic(fmt.Sprintf("while marshalling the OpenAPI document: %v", err))
+	}
+
+	mux.HandleFunc(url.PathOpenAPI, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(document); err != nil {
+			log.Debug("while writing the OpenAPI document", "err", err.Error())
+		}
+	})
+}
+
+// deprecatedRoute adds to the responses of an unversioned route the headers
+// pointing the clients to the versioned one
+func deprecatedRoute(path string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", url.Versioned(path)))
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,138 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("versioned REST API", func() {
+	var mux *instrumentedServeMux
+
+	BeforeEach(func() {
+		mux = newInstrumentedServeMux("test")
+		mux.HandleAPI(apiRoute{
+			path:      "/test/",
+			parameter: "name",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(r.URL.Path))
+			},
+			operations: []apiOperation{
+				{
+					method:   http.MethodGet,
+					summary:  "Get the cluster",
+					response: apiv1.Cluster{},
+				},
+				{
+					method:   http.MethodPost,
+					summary:  "Record a batch",
+					request:  pg.WALArchiveBatch{},
+					response: pg.WALArchiveStatus{},
+					wrapped:  true,
+				},
+			},
+		})
+		mux.HandleOpenAPI("test")
+	})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	It("serves the routes under the version prefix, removing it", func() {
+		recorder := serve(http.MethodGet, url.Versioned("/test/one"))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(Equal("/test/one"))
+		Expect(recorder.Header().Get("Deprecation")).To(BeEmpty())
+	})
+
+	It("keeps serving the unversioned routes, marking them as deprecated", func() {
+		recorder := serve(http.MethodGet, "/test/one")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(Equal("/test/one"))
+		Expect(recorder.Header().Get("Deprecation")).To(Equal("true"))
+		Expect(recorder.Header().Get("Link")).To(Equal(`</v1/test/>; rel="successor-version"`))
+	})
+
+	It("describes the routes in the OpenAPI document", func() {
+		recorder := serve(http.MethodGet, url.PathOpenAPI)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var document struct {
+			OpenAPI string `json:"openapi"`
+			Info    struct {
+				Version string `json:"version"`
+			} `json:"info"`
+			Paths      map[string]map[string]map[string]any `json:"paths"`
+			Components struct {
+				Schemas map[string]map[string]any `json:"schemas"`
+			} `json:"components"`
+		}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &document)).To(Succeed())
+		Expect(document.OpenAPI).To(Equal(openAPIVersion))
+		Expect(document.Info.Version).To(Equal("v1"))
+
+		Expect(document.Paths).To(HaveKey("/v1/test/{name}"))
+		operations := document.Paths["/v1/test/{name}"]
+		Expect(operations).To(HaveKey("get"))
+		Expect(operations).To(HaveKey("post"))
+		Expect(operations["post"]).To(HaveKey("requestBody"))
+		Expect(operations["get"]["parameters"]).To(HaveLen(1))
+
+		Expect(document.Components.Schemas).To(HaveKey("v1.Cluster"))
+		Expect(document.Components.Schemas).To(HaveKey("postgres.WALArchiveBatch"))
+		Expect(document.Components.Schemas).To(HaveKey("postgres.WALArchiveStatus"))
+		Expect(document.Components.Schemas).To(HaveKey("webserver.Error"))
+	})
+
+	It("builds the schemas following the JSON encoding rules", func() {
+		registry := schemaRegistry{schemas: make(map[string]any)}
+		Expect(registry.schemaFor(reflect.TypeOf(Error{}))).To(Equal(map[string]any{
+			"$ref": "#/components/schemas/webserver.Error",
+		}))
+		Expect(registry.schemas["webserver.Error"]).To(Equal(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "string"},
+				"message": map[string]any{"type": "string"},
+				"details": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"$ref": "#/components/schemas/webserver.Error"},
+				},
+			},
+			"required": []string{"code", "message"},
+		}))
+
+		Expect(registry.schemaFor(reflect.TypeOf(BackupResultData{}))).To(HaveKey("$ref"))
+		properties := registry.schemas["webserver.BackupResultData"].(map[string]any)["properties"]
+		Expect(properties).To(HaveKeyWithValue("labelFile", map[string]any{"type": "string", "format": "byte"}))
+		Expect(properties).To(HaveKeyWithValue("beginLSN", map[string]any{"type": "string"}))
+	})
+})
@@ -0,0 +1,413 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// baseBackupIDHeader is the HTTP header containing the ID of the
+	// base backup session being streamed
+	baseBackupIDHeader = "X-Cnpg-Basebackup-Id"
+
+	// baseBackupChunkSize is the size of each chunk written to the client
+	baseBackupChunkSize = 1024 * 1024
+
+	// baseBackupBufferSize is the size of the in-memory buffer between
+	// pg_basebackup and the client. pg_basebackup is blocked when the
+	// buffer is full
+	baseBackupBufferSize = 16 * 1024 * 1024
+
+	// baseBackupResumeWindow is how much of the data already sent to
+	// the client is kept in the buffer, allowing the client to resume
+	// an interrupted transfer
+	baseBackupResumeWindow = 8 * 1024 * 1024
+
+	// baseBackupIdleTimeout is the time after which a base backup
+	// session without a client is discarded
+	baseBackupIdleTimeout = 5 * time.Minute
+
+	// baseBackupReaperInterval is how often we look for idle sessions
+	baseBackupReaperInterval = 10 * time.Second
+)
+
+var (
+	// errBaseBackupRunning is raised when a new base backup is requested
+	// while another one is still being taken
+	errBaseBackupRunning = errors.New("a base backup is already running")
+
+	// errBaseBackupOffsetUnavailable is raised when a client asks to resume
+	// a transfer from an offset which is not in the buffer anymore
+	errBaseBackupOffsetUnavailable = errors.New("the requested offset is not available anymore")
+
+	// errBaseBackupFollowerReplaced is raised to a client when another one
+	// resumed the transfer of the same base backup
+	errBaseBackupFollowerReplaced = errors.New("the transfer has been resumed by another request")
+)
+
+// baseBackupProducer writes a base backup tarball into the passed writer
+type baseBackupProducer func(ctx context.Context, output io.Writer) error
+
+// baseBackupSession is a base backup being streamed to a client.
+// The tarball produced by pg_basebackup goes through a bounded
+// in-memory ring buffer: pg_basebackup is blocked while the client
+// doesn't keep up, and the last part of the stream already sent is
+// retained so that the client can resume an interrupted transfer.
+type baseBackupSession struct {
+	id     string
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// changed is closed and replaced every time the state of the session
+	// changes, waking up the goroutines waiting for it
+	changed chan struct{}
+	buffer  []byte
+	// start and end are the offsets, in the stream, of the first byte
+	// retained in the buffer and of the byte following the last one
+	start int64
+	end   int64
+	// follower is the generation of the request currently streaming
+	// the base backup, zero if there is none
+	follower     int
+	generation   int
+	lastActivity time.Time
+	done         bool
+	err          error
+}
+
+// baseBackupSessions keeps track of the current base backup session.
+// Only one base backup is allowed at a time.
+type baseBackupSessions struct {
+	// ctx is the context of the webserver, terminating the base backups
+	// when the instance manager is shut down
+	ctx context.Context
+
+	mu      sync.Mutex
+	current *baseBackupSession
+}
+
+// start begins a new base backup session, discarding the previous
+// one if it has already terminated
+func (s *baseBackupSessions) start(producer baseBackupProducer) (*baseBackupSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != nil {
+		if !s.current.isDone() {
+			return nil, errBaseBackupRunning
+		}
+		s.current.discard()
+		s.current = nil
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	session := &baseBackupSession{
+		id:           rand.String(12),
+		ctx:          ctx,
+		cancel:       cancel,
+		changed:      make(chan struct{}),
+		buffer:       make([]byte, baseBackupBufferSize),
+		lastActivity: time.Now(),
+	}
+
+	go func() {
+		session.setDone(producer(ctx, session))
+	}()
+	go s.reapWhenIdle(ctx, session)
+
+	s.current = session
+	return session, nil
+}
+
+// reapWhenIdle discards the passed session once no client has been
+// following it for baseBackupIdleTimeout, so that a client that went
+// away doesn't keep pg_basebackup and the buffer around
+func (s *baseBackupSessions) reapWhenIdle(ctx context.Context, session *baseBackupSession) {
+	ticker := time.NewTicker(baseBackupReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if session.idleTime() < baseBackupIdleTimeout {
+			continue
+		}
+
+		if s.remove(session.id) {
+			log.Info("Discarding idle base backup", "id", session.id)
+		}
+		return
+	}
+}
+
+// get returns the session with the passed ID, or nil if it doesn't exist
+func (s *baseBackupSessions) get(id string) *baseBackupSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil || s.current.id != id {
+		return nil
+	}
+	return s.current
+}
+
+// remove stops the session with the passed ID and releases its buffer.
+// It returns false if the session doesn't exist
+func (s *baseBackupSessions) remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil || s.current.id != id {
+		return false
+	}
+
+	s.current.discard()
+	s.current = nil
+	return true
+}
+
+// notify wakes up the goroutines waiting for the session to change.
+// It must be called with the lock held
+func (session *baseBackupSession) notify() {
+	close(session.changed)
+	session.changed = make(chan struct{})
+}
+
+func (session *baseBackupSession) setDone(err error) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	session.done = true
+	session.err = err
+	session.notify()
+}
+
+func (session *baseBackupSession) isDone() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	return session.done
+}
+
+// idleTime is the time elapsed since the last client stopped
+// following the session, zero if a client is following it
+func (session *baseBackupSession) idleTime() time.Duration {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.follower != 0 {
+		return 0
+	}
+	return time.Since(session.lastActivity)
+}
+
+// discard stops the producer and releases the buffer
+func (session *baseBackupSession) discard() {
+	session.cancel()
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if !session.done {
+		session.done = true
+		session.err = context.Canceled
+	}
+	session.buffer = nil
+	session.notify()
+}
+
+// Write implements the io.Writer interface for the producer, blocking
+// while the buffer is full
+func (session *baseBackupSession) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		session.mu.Lock()
+		if session.buffer == nil {
+			session.mu.Unlock()
+			return written, context.Canceled
+		}
+
+		if session.end-session.start == int64(len(session.buffer)) {
+			changed := session.changed
+			session.mu.Unlock()
+
+			select {
+			case <-session.ctx.Done():
+				return written, session.ctx.Err()
+			case <-changed:
+			}
+			continue
+		}
+
+		position := int(session.end % int64(len(session.buffer)))
+		free := int(int64(len(session.buffer)) - (session.end - session.start))
+		n := copy(session.buffer[position:min(position+free, len(session.buffer))], p[written:])
+		session.end += int64(n)
+		written += n
+		session.notify()
+		session.mu.Unlock()
+	}
+
+	return written, nil
+}
+
+// attach registers a new client following the session from the passed
+// offset, replacing the previous one. It returns the generation of the client
+func (session *baseBackupSession) attach(offset int64) (int, error) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.buffer == nil || offset < session.start || offset > session.end {
+		return 0, errBaseBackupOffsetUnavailable
+	}
+
+	session.generation++
+	session.follower = session.generation
+	session.notify()
+	return session.follower, nil
+}
+
+// detach unregisters the passed client, if it is still the one
+// following the session
+func (session *baseBackupSession) detach(generation int) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.follower != generation {
+		return
+	}
+	session.follower = 0
+	session.lastActivity = time.Now()
+}
+
+// read copies into p the data available at the passed offset, waiting
+// for pg_basebackup to produce it. It returns io.EOF once the whole
+// base backup has been read
+func (session *baseBackupSession) read(ctx context.Context, generation int, offset int64, p []byte) (int, error) {
+	for {
+		session.mu.Lock()
+		switch {
+		case session.follower != generation:
+			session.mu.Unlock()
+			return 0, errBaseBackupFollowerReplaced
+
+		case session.buffer == nil || offset < session.start:
+			session.mu.Unlock()
+			return 0, errBaseBackupOffsetUnavailable
+
+		case offset < session.end:
+			position := int(offset % int64(len(session.buffer)))
+			available := int(min(session.end-offset, int64(len(session.buffer)-position)))
+			n := copy(p, session.buffer[position:position+available])
+			session.mu.Unlock()
+			return n, nil
+
+		case session.done:
+			err := session.err
+			session.mu.Unlock()
+			if err == nil {
+				err = io.EOF
+			}
+			return 0, err
+		}
+
+		changed := session.changed
+		session.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// advance records that the passed client has sent the data up to the
+// passed offset, releasing the part of the buffer that is not needed
+// to resume the transfer anymore
+func (session *baseBackupSession) advance(generation int, offset int64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.follower != generation {
+		return
+	}
+
+	if start := offset - baseBackupResumeWindow; start > session.start {
+		session.start = start
+		session.notify()
+	}
+}
+
+// follow streams the base backup to the passed attached client, starting
+// from the passed offset, until pg_basebackup terminates
+func (session *baseBackupSession) follow(ctx context.Context, generation int, offset int64, w io.Writer) error {
+	flusher, _ := w.(http.Flusher)
+	buffer := make([]byte, baseBackupChunkSize)
+	for {
+		n, err := session.read(ctx, generation, offset, buffer)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(buffer[:n]); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		offset += int64(n)
+		session.advance(generation, offset)
+	}
+}
+
+// parseBaseBackupOffset parses the offset from which a client wants
+// to resume the transfer of a base backup
+func parseBaseBackupOffset(r *http.Request) (int64, error) {
+	rawOffset := r.URL.Query().Get("offset")
+	if rawOffset == "" {
+		return 0, nil
+	}
+
+	offset, err := strconv.ParseInt(rawOffset, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid offset: %s", rawOffset)
+	}
+
+	return offset, nil
+}
@@ -0,0 +1,222 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// followSession attaches a client to the passed session and streams
+// the base backup from the passed offset
+func followSession(ctx context.Context, session *baseBackupSession, offset int64, w io.Writer) error {
+	generation, err := session.attach(offset)
+	if err != nil {
+		return err
+	}
+	defer session.detach(generation)
+
+	return session.follow(ctx, generation, offset, w)
+}
+
+var _ = Describe("base backup sessions", func() {
+	var (
+		sessions *baseBackupSessions
+		cancel   context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(func() {
+			cancel()
+		})
+		sessions = &baseBackupSessions{
+			ctx: ctx,
+		}
+	})
+
+	It("streams the whole content written by the producer", func(ctx SpecContext) {
+		release := make(chan struct{})
+		session, err := sessions.start(func(_ context.Context, output io.Writer) error {
+			_, _ = output.Write([]byte("first-"))
+			<-release
+			_, _ = output.Write([]byte("second"))
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		go close(release)
+
+		var buffer bytes.Buffer
+		Expect(followSession(ctx, session, 0, &buffer)).To(Succeed())
+		Expect(buffer.String()).To(Equal("first-second"))
+	})
+
+	It("resumes the transfer from the requested offset", func(ctx SpecContext) {
+		session, err := sessions.start(func(_ context.Context, output io.Writer) error {
+			_, err := output.Write([]byte("0123456789"))
+			return err
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		var buffer bytes.Buffer
+		Expect(followSession(ctx, session, 0, &buffer)).To(Succeed())
+		Expect(buffer.String()).To(Equal("0123456789"))
+
+		buffer.Reset()
+		Expect(followSession(ctx, session, 6, &buffer)).To(Succeed())
+		Expect(buffer.String()).To(Equal("6789"))
+	})
+
+	It("blocks the producer while the buffer is full", func(ctx SpecContext) {
+		produced := make(chan struct{})
+		session, err := sessions.start(func(_ context.Context, output io.Writer) error {
+			defer close(produced)
+			_, err := output.Write(make([]byte, baseBackupBufferSize+1))
+			return err
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Consistently(produced).WithTimeout(200 * time.Millisecond).ShouldNot(BeClosed())
+
+		var buffer bytes.Buffer
+		Expect(followSession(ctx, session, 0, &buffer)).To(Succeed())
+		Expect(buffer.Len()).To(Equal(baseBackupBufferSize + 1))
+		Eventually(produced).Should(BeClosed())
+	})
+
+	It("only keeps the resume window of the data already sent", func(ctx SpecContext) {
+		const size = baseBackupResumeWindow + 1024
+		session, err := sessions.start(func(_ context.Context, output io.Writer) error {
+			_, err := output.Write(make([]byte, size))
+			return err
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(followSession(ctx, session, 0, io.Discard)).To(Succeed())
+
+		_, err = session.attach(0)
+		Expect(err).To(MatchError(errBaseBackupOffsetUnavailable))
+		_, err = session.attach(size + 1)
+		Expect(err).To(MatchError(errBaseBackupOffsetUnavailable))
+
+		var buffer bytes.Buffer
+		Expect(followSession(ctx, session, 1024, &buffer)).To(Succeed())
+		Expect(buffer.Len()).To(Equal(baseBackupResumeWindow))
+	})
+
+	It("stops streaming to a client replaced by another one", func(ctx SpecContext) {
+		release := make(chan struct{})
+		defer close(release)
+		session, err := sessions.start(func(_ context.Context, _ io.Writer) error {
+			<-release
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		first, err := session.attach(0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = session.attach(0)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(session.follow(ctx, first, 0, io.Discard)).To(MatchError(errBaseBackupFollowerReplaced))
+	})
+
+	It("reports the producer error to the followers", func(ctx SpecContext) {
+		producerErr := errors.New("pg_basebackup failed")
+		session, err := sessions.start(func(_ context.Context, _ io.Writer) error {
+			return producerErr
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(followSession(ctx, session, 0, io.Discard)).To(MatchError(producerErr))
+	})
+
+	It("refuses to start a base backup while another one is running", func() {
+		release := make(chan struct{})
+		defer close(release)
+		_, err := sessions.start(func(_ context.Context, _ io.Writer) error {
+			<-release
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = sessions.start(func(_ context.Context, _ io.Writer) error {
+			return nil
+		})
+		Expect(err).To(MatchError(errBaseBackupRunning))
+	})
+
+	It("terminates the producer when the webserver is shut down", func(ctx SpecContext) {
+		session, err := sessions.start(func(_ context.Context, output io.Writer) error {
+			_, err := output.Write(make([]byte, baseBackupBufferSize+1))
+			return err
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		cancel()
+		Eventually(session.isDone).Should(BeTrue())
+		Expect(followSession(ctx, session, 0, io.Discard)).To(MatchError(context.Canceled))
+	})
+
+	It("releases the buffer when the session is removed", func(ctx SpecContext) {
+		session, err := sessions.start(func(_ context.Context, _ io.Writer) error {
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(followSession(ctx, session, 0, io.Discard)).To(Succeed())
+
+		Expect(sessions.get(session.id)).To(Equal(session))
+		Expect(sessions.remove(session.id)).To(BeTrue())
+		Expect(sessions.get(session.id)).To(BeNil())
+		_, err = session.attach(0)
+		Expect(err).To(MatchError(errBaseBackupOffsetUnavailable))
+	})
+
+	It("measures the idle time only when no client is following", func() {
+		release := make(chan struct{})
+		defer close(release)
+		session, err := sessions.start(func(_ context.Context, _ io.Writer) error {
+			<-release
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		generation, err := session.attach(0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.idleTime()).To(BeZero())
+
+		session.detach(generation)
+		Eventually(session.idleTime).Should(BeNumerically(">", 0))
+	})
+
+	It("parses the offset parameter", func() {
+		offset, err := parseBaseBackupOffset(httptest.NewRequest("GET", "/pg/basebackup?offset=42", nil))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(offset).To(BeEquivalentTo(42))
+
+		_, err = parseBaseBackupOffset(httptest.NewRequest("GET", "/pg/basebackup?offset=-1", nil))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,68 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// certificatesRoute is the REST API route reporting the expiration of
+// the certificates used by the instance
+func certificatesRoute(instance *postgres.Instance) apiRoute {
+	return apiRoute{
+		path:    url.PathPgCertificates,
+		handler: serveCertificates(instance),
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the expiration of the certificates used by the instance",
+			response: []pg.CertificateExpiration{},
+			wrapped:  true,
+		}},
+	}
+}
+
+// serveCertificates returns the handler reporting the expiration of the
+// certificates the instance is currently using, which may differ from the
+// ones in the secrets while a renewal is being propagated. It is served by
+// both the local and the remote webservers
+func serveCertificates(instance *postgres.Instance) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+			return
+		}
+
+		expirations, err := instance.GetCertificatesExpiration()
+		if err != nil {
+			log.Debug("Instance certificates endpoint failing", "err", err.Error())
+			sendJSONResponse(w, http.StatusInternalServerError, Response[any]{
+				Error: &Error{
+					Code:    "CERTIFICATES_FAILED",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+
+		sendJSONResponseWithData(w, http.StatusOK, expirations)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// diagnosticQueryRunner executes a diagnostic query
+type diagnosticQueryRunner func(
+	ctx context.Context,
+	request pg.DiagnosticQueryRequest,
+) (*pg.DiagnosticQueryResult, error)
+
+// NewDiagnosticsWebServer returns a webserver running the read-only
+// diagnostic queries of the `sql` command of the kubectl plugin.
+// It is bound to the loopback interface, so it can only be reached
+// through a port-forward, which is authorized by the Kubernetes RBAC.
+// As the other containers of the Pod share the loopback interface, the
+// requests must also carry the token written in the PostgreSQL container
+func NewDiagnosticsWebServer(instance *postgres.Instance) (*Webserver, error) {
+	serveMux := newInstrumentedServeMux("diagnostics")
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgQuery,
+		handler: serveDiagnosticQuery(instance.RunDiagnosticQuery),
+		operations: []apiOperation{{
+			method: http.MethodPost,
+			summary: "Run a read-only diagnostic query, when enabled in the cluster, " +
+				"with the statement timeout and the maximum number of rows configured there",
+			request:  pg.DiagnosticQueryRequest{},
+			response: pg.DiagnosticQueryResult{},
+			wrapped:  true,
+		}},
+	})
+	serveMux.HandleOpenAPI("CloudNativePG instance manager diagnostics API")
+
+	token, err := localauth.GenerateDiagnosticsToken()
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf("localhost:%d", url.DiagnosticsPort),
+		Handler:           localauth.Middleware(token, serveMux),
+		ReadTimeout:       DefaultReadTimeout,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+	}
+
+	return NewWebServer(instance, server), nil
+}
+
+// serveDiagnosticQuery returns the handler running the diagnostic queries
+func serveDiagnosticQuery(runQuery diagnosticQueryRunner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var request pg.DiagnosticQueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			sendBadRequestJSONResponse(w, "FAILED_TO_PARSE_REQUEST", err.Error())
+			return
+		}
+
+		result, err := runQuery(r.Context(), request)
+		switch {
+		case errors.Is(err, postgres.ErrDiagnosticQueriesDisabled):
+			sendJSONResponse(w, http.StatusForbidden, Response[any]{
+				Error: &Error{
+					Code:    "DIAGNOSTIC_QUERIES_DISABLED",
+					Message: err.Error(),
+				},
+			})
+		case errors.Is(err, postgres.ErrEmptyDiagnosticQuery):
+			sendBadRequestJSONResponse(w, "EMPTY_QUERY", err.Error())
+		case err != nil:
+			sendUnprocessableEntityJSONResponse(w, "QUERY_FAILED", err.Error())
+		default:
+			sendJSONResponseWithData(w, http.StatusOK, *result)
+		}
+	}
+}
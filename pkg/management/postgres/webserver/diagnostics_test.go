@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("diagnostic queries", func() {
+	runnerFor := func(result *pg.DiagnosticQueryResult, err error) diagnosticQueryRunner {
+		return func(context.Context, pg.DiagnosticQueryRequest) (*pg.DiagnosticQueryResult, error) {
+			return result, err
+		}
+	}
+
+	serve := func(runQuery diagnosticQueryRunner, method string, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		serveDiagnosticQuery(runQuery).ServeHTTP(rec,
+			httptest.NewRequest(method, "/pg/query", strings.NewReader(body)))
+		return rec
+	}
+
+	value := "1"
+	result := &pg.DiagnosticQueryResult{
+		Columns: []string{"one"},
+		Rows:    [][]*string{{&value}},
+	}
+
+	It("returns the result of the query", func() {
+		rec := serve(runnerFor(result, nil), http.MethodPost, `{"query": "SELECT 1 AS one"}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var response Response[pg.DiagnosticQueryResult]
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Data).To(Equal(result))
+	})
+
+	DescribeTable("failures",
+		func(runQuery diagnosticQueryRunner, method string, body string, expectedCode int) {
+			Expect(serve(runQuery, method, body).Code).To(Equal(expectedCode))
+		},
+		Entry("with the wrong method", runnerFor(result, nil), http.MethodGet, "",
+			http.StatusMethodNotAllowed),
+		Entry("with an invalid request", runnerFor(result, nil), http.MethodPost, "{",
+			http.StatusBadRequest),
+		Entry("when the queries are disabled", runnerFor(nil, postgres.ErrDiagnosticQueriesDisabled),
+			http.MethodPost, `{"query": "SELECT 1"}`, http.StatusForbidden),
+		Entry("with an empty query", runnerFor(nil, postgres.ErrEmptyDiagnosticQuery),
+			http.MethodPost, `{"query": ""}`, http.StatusBadRequest),
+		Entry("when the query fails", runnerFor(nil, errors.New("syntax error")),
+			http.MethodPost, `{"query": "SELEC 1"}`, http.StatusUnprocessableEntity),
+	)
+})
@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// faultsRoute is the REST API route used to inject faults in the
+// instance manager, simulating failures in a test environment
+func faultsRoute(instance *postgres.Instance) apiRoute {
+	return apiRoute{
+		path:    url.PathFaults,
+		handler: serveFaults(instance),
+		operations: []apiOperation{
+			{
+				method:   http.MethodGet,
+				summary:  "Get the faults injected in the instance manager",
+				response: []pg.Fault{},
+				wrapped:  true,
+			},
+			{
+				method:   http.MethodPost,
+				summary:  "Inject a fault, replacing the active one of the same type",
+				request:  pg.Fault{},
+				response: pg.Fault{},
+				wrapped:  true,
+			},
+			{
+				method: http.MethodDelete,
+				summary: "Remove the fault whose type is passed in the `type` query parameter, " +
+					"or every fault if it is not set",
+				wrapped: true,
+			},
+		},
+	}
+}
+
+// serveFaults returns the handler used to inject and remove the faults.
+// It is only served by the local webserver, when the fault injection
+// has been enabled in the PostgreSQL container
+func serveFaults(instance *postgres.Instance) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			sendJSONResponseWithData(w, http.StatusOK, instance.GetInjectedFaults())
+
+		case http.MethodPost:
+			defer func() {
+				if err := r.Body.Close(); err != nil {
+					log.Error(err, "while closing the body")
+				}
+			}()
+
+			var fault pg.Fault
+			if err := json.NewDecoder(r.Body).Decode(&fault); err != nil {
+				sendBadRequestJSONResponse(w, "FAILED_TO_PARSE_REQUEST", "Failed to parse request body")
+				return
+			}
+
+			injected, err := instance.InjectFault(fault)
+			if err != nil {
+				sendUnprocessableEntityJSONResponse(w, "INVALID_FAULT", err.Error())
+				return
+			}
+
+			log.Warning("Fault injected in the instance manager",
+				"type", injected.Type,
+				"delay", injected.Delay,
+				"expiresAt", injected.ExpiresAt)
+			sendJSONResponseWithData(w, http.StatusOK, injected)
+
+		case http.MethodDelete:
+			faultType := pg.FaultType(r.URL.Query().Get("type"))
+			instance.RemoveInjectedFault(faultType)
+
+			log.Info("Fault removed from the instance manager", "type", faultType)
+			sendJSONResponse(w, http.StatusOK, Response[any]{})
+
+		default:
+			http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fault injection endpoint", func() {
+	var instance *postgres.Instance
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		serveFaults(instance).ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	BeforeEach(func() {
+		instance = postgres.NewInstance()
+	})
+
+	It("injects and removes the faults", func() {
+		rec := serve(http.MethodPost, url.PathFaults, `{"type": "readinessProbe", "duration": "5m"}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"expiresAt"`))
+		Expect(instance.CheckInjectedFault(pg.FaultTypeReadinessProbe)).To(MatchError(postgres.ErrInjectedFault))
+
+		rec = serve(http.MethodPost, url.PathFaults, `{"type": "walArchive"}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = serve(http.MethodGet, url.PathFaults, "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"type":"readinessProbe"`))
+		Expect(rec.Body.String()).To(ContainSubstring(`"type":"walArchive"`))
+
+		rec = serve(http.MethodDelete, url.PathFaults+"?type=readinessProbe", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(instance.CheckInjectedFault(pg.FaultTypeReadinessProbe)).To(Succeed())
+		Expect(instance.GetInjectedFaults()).To(HaveLen(1))
+
+		rec = serve(http.MethodDelete, url.PathFaults, "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(instance.GetInjectedFaults()).To(BeEmpty())
+	})
+
+	It("refuses the invalid faults", func() {
+		rec := serve(http.MethodPost, url.PathFaults, `{"type": "diskFull"}`)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(rec.Body.String()).To(ContainSubstring("INVALID_FAULT"))
+
+		rec = serve(http.MethodPost, url.PathFaults, `not json`)
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(instance.GetInjectedFaults()).To(BeEmpty())
+	})
+
+	It("fails the probes having a fault injected", func() {
+		endpoints := remoteWebserverEndpoints{instance: instance}
+		_, err := instance.InjectFault(pg.Fault{Type: pg.FaultTypeLivenessProbe})
+		Expect(err).ToNot(HaveOccurred())
+
+		rec := httptest.NewRecorder()
+		endpoints.isServerHealthy(rec, httptest.NewRequest(http.MethodGet, url.PathHealth, nil))
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).To(ContainSubstring("injected fault: livenessProbe"))
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -30,19 +31,27 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/localauth"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 type localWebserverEndpoints struct {
-	typedClient   client.Client
-	instance      *postgres.Instance
-	eventRecorder record.EventRecorder
+	typedClient        client.Client
+	instance           *postgres.Instance
+	eventRecorder      record.EventRecorder
+	baseBackupSessions *baseBackupSessions
+	refreshCache       func(ctx context.Context) error
 }
 
-// NewLocalWebServer returns a webserver that allows connection only from localhost
-func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
+// NewLocalWebServer returns a webserver that allows connection only from localhost.
+// The passed function is used to refresh the content of the local cache on request
+func NewLocalWebServer(
+	instance *postgres.Instance,
+	refreshCache func(ctx context.Context) error,
+) (*Webserver, error) {
 	typedClient, err := management.NewControllerRuntimeClient()
 	if err != nil {
 		return nil, fmt.Errorf("creating controller-runtine client: %v", err)
@@ -53,21 +62,163 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 	}
 
 	endpoints := localWebserverEndpoints{
-		typedClient:   typedClient,
-		instance:      instance,
-		eventRecorder: eventRecorder,
+		typedClient:        typedClient,
+		instance:           instance,
+		eventRecorder:      eventRecorder,
+		baseBackupSessions: &baseBackupSessions{},
+		refreshCache:       refreshCache,
 	}
 
-	serveMux := http.NewServeMux()
-	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
-	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux := newInstrumentedServeMux("local")
+	serveMux.HandleAPI(apiRoute{
+		path:      url.PathCache,
+		parameter: "object",
+		handler:   endpoints.serveCache,
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the cached cluster or the environment of the WAL archiver and restorer",
+			response: oneOf{apiv1.Cluster{}, []string{}},
+		}},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathCacheRefresh,
+		handler: endpoints.serveCacheRefresh,
+		operations: []apiOperation{{
+			method:  http.MethodPost,
+			summary: "Refresh the cached resources",
+			wrapped: true,
+		}},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgBackup,
+		handler: endpoints.requestBackup,
+		operations: []apiOperation{
+			{
+				method:      http.MethodPost,
+				summary:     "Start the backup whose name is passed in the `name` query parameter",
+				contentType: "text/plain",
+			},
+			{
+				method: http.MethodDelete,
+				summary: "Remove the base backup of the Backup whose name is passed in the `name` " +
+					"query parameter from the object store",
+				contentType: "text/plain",
+			},
+		},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:      url.PathPgBackupStatus,
+		parameter: "backup",
+		handler:   endpoints.serveBackupStatus,
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the progress of a running backup",
+			response: apiv1.BackupProgress{},
+			wrapped:  true,
+		}},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgBaseBackup,
+		handler: endpoints.streamBaseBackup,
+		operations: []apiOperation{
+			{
+				method: http.MethodGet,
+				summary: "Stream a tarball of the data directory, resuming the base backup " +
+					"passed in the `id` query parameter from the `offset` one",
+				contentType: "application/x-tar",
+			},
+			{
+				method:      http.MethodDelete,
+				summary:     "Remove the base backup passed in the `id` query parameter",
+				contentType: "text/plain",
+			},
+		},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgWALArchiveStatus,
+		handler: endpoints.serveWALArchiveStatus,
+		operations: []apiOperation{
+			{
+				method:   http.MethodGet,
+				summary:  "Get the status of the WAL archiving process",
+				response: pg.WALArchiveStatus{},
+				wrapped:  true,
+			},
+			{
+				method:  http.MethodPost,
+				summary: "Record the outcome of a batch of archived WAL files",
+				request: pg.WALArchiveBatch{},
+				wrapped: true,
+			},
+		},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgWALRestoreStatus,
+		handler: endpoints.serveWALRestoreStatus,
+		operations: []apiOperation{
+			{
+				method:   http.MethodGet,
+				summary:  "Get the status of the WAL restore process, including the preferred source of the WAL files",
+				response: pg.WALRestoreStatus{},
+				wrapped:  true,
+			},
+			{
+				method:  http.MethodPost,
+				summary: "Record the outcome of an execution of the restore command",
+				request: pg.WALRestoreResult{},
+				wrapped: true,
+			},
+		},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPGControlData,
+		handler: endpoints.serveControlData,
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the fields reported by pg_controldata",
+			response: map[string]string{},
+			wrapped:  true,
+		}},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgSettings,
+		handler: endpoints.serveSettings,
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the configuration parameters not set to their default value",
+			response: []pg.Setting{},
+			wrapped:  true,
+		}},
+	})
+	serveMux.HandleAPI(topologyRoute(instance))
+	serveMux.HandleAPI(certificatesRoute(instance))
+	if pg.IsFaultInjectionEnabled() {
+		log.Warning("Fault injection enabled, the faults can be injected via the local webserver",
+			"environmentVariable", pg.FaultInjectionEnvironmentVariable)
+		serveMux.HandleAPI(faultsRoute(instance))
+	}
+	serveMux.HandleOpenAPI("CloudNativePG instance manager local API")
+
+	// Only the processes running in the PostgreSQL container can read
+	// the token, preventing the other containers of the Pod from
+	// triggering backups or reading the cached credentials
+	token, err := localauth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// The base backups being streamed are terminated together
+	// with the webserver
+	baseBackupsCtx, cancelBaseBackups := context.WithCancel(context.Background())
+	endpoints.baseBackupSessions.ctx = baseBackupsCtx
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
-		Handler:           serveMux,
+		Handler:           localauth.Middleware(token, serveMux),
 		ReadHeaderTimeout: DefaultReadTimeout,
 		ReadTimeout:       DefaultReadTimeout,
 	}
+	server.RegisterOnShutdown(cancelBaseBackups)
 
 	webserver := NewWebServer(instance, server)
 
@@ -81,9 +232,10 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 	log.Debug("Cached object request received")
 
 	var js []byte
-	switch requestedObject {
-	case cache.ClusterKey:
+	switch {
+	case requestedObject == cache.ClusterKey:
 		response, err := cache.LoadClusterUnsafe()
+		observeCacheRequest(requestedObject, err)
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
 			return
@@ -99,8 +251,9 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-	case cache.WALRestoreKey, cache.WALArchiveKey:
+	case requestedObject == cache.WALRestoreKey, cache.IsWALArchiveKey(requestedObject):
 		response, err := cache.LoadEnv(requestedObject)
+		observeCacheRequest(requestedObject, err)
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
 			return
@@ -126,13 +279,61 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 	_, _ = w.Write(js)
 }
 
+// serveCacheRefresh refreshes the cached cluster and the environment used by
+// the WAL archiver and restorer, reading again the backup credentials. This
+// makes a change in the credentials effective without waiting for the next
+// reconciliation loop
+func (ws *localWebserverEndpoints) serveCacheRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := ws.refreshCache(r.Context()); err != nil {
+		log.Warning("Cache refresh failed", "err", err.Error())
+		sendJSONResponse(w, http.StatusInternalServerError, Response[any]{
+			Error: &Error{
+				Code:    "CACHE_REFRESH_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	log.Info("Cache refreshed on request")
+	sendJSONResponse(w, http.StatusOK, Response[any]{})
+}
+
+// observeCacheRequest counts a request for a cached object
+func observeCacheRequest(object string, err error) {
+	result := cacheHit
+	switch {
+	case errors.Is(err, cache.ErrCacheMiss):
+		result = cacheMiss
+	case err != nil:
+		result = cacheError
+	}
+	cacheRequestsTotal.WithLabelValues(object, result).Inc()
+}
+
 // This function schedule a backup
 func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		ws.deleteBackup(w, r)
+		return
+	}
+
 	var cluster apiv1.Cluster
 	var backup apiv1.Backup
 
 	ctx := context.Background()
 
+	backupMethod := "unknown"
+	outcome := backupRequestFailed
+	defer func() {
+		backupRequestsTotal.WithLabelValues(backupMethod, outcome).Inc()
+	}()
+
 	backupName := r.URL.Query().Get("name")
 	if len(backupName) == 0 {
 		http.Error(w, "Missing backup name parameter", http.StatusBadRequest)
@@ -161,12 +362,20 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 		return
 	}
 
+	backupMethod = string(backup.Spec.Method)
 	switch backup.Spec.Method {
 	case apiv1.BackupMethodBarmanObjectStore:
 		if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
 			http.Error(w, "Barman backup not configured in the cluster", http.StatusConflict)
 			return
 		}
+		if cluster.Spec.Backup.GetBarmanObjectStore(backup.Spec.ObjectStoreName) == nil {
+			http.Error(
+				w,
+				fmt.Sprintf("Object store %s not configured in the cluster", backup.Spec.ObjectStoreName),
+				http.StatusConflict)
+			return
+		}
 
 		if err := ws.startBarmanBackup(ctx, &cluster, &backup); err != nil {
 			http.Error(
@@ -175,6 +384,7 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 				http.StatusInternalServerError)
 			return
 		}
+		outcome = backupRequestSucceeded
 		_, _ = fmt.Fprint(w, "OK")
 
 	case apiv1.BackupMethodPlugin:
@@ -184,6 +394,7 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 		}
 
 		ws.startPluginBackup(ctx, &cluster, &backup)
+		outcome = backupRequestSucceeded
 		_, _ = fmt.Fprint(w, "OK")
 
 	default:
@@ -194,6 +405,88 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 	}
 }
 
+// deleteBackup removes the base backup of a Backup being deleted from the
+// object store where it has been taken
+func (ws *localWebserverEndpoints) deleteBackup(w http.ResponseWriter, r *http.Request) {
+	var cluster apiv1.Cluster
+	var backup apiv1.Backup
+
+	ctx := r.Context()
+
+	backupName := r.URL.Query().Get("name")
+	if len(backupName) == 0 {
+		http.Error(w, "Missing backup name parameter", http.StatusBadRequest)
+		return
+	}
+
+	if err := ws.typedClient.Get(ctx, client.ObjectKey{
+		Namespace: ws.instance.Namespace,
+		Name:      ws.instance.ClusterName,
+	}, &cluster); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting cluster: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if err := ws.typedClient.Get(ctx, client.ObjectKey{
+		Namespace: ws.instance.Namespace,
+		Name:      backupName,
+	}, &backup); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting backup: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if backup.Spec.Method != apiv1.BackupMethodBarmanObjectStore {
+		http.Error(
+			w,
+			fmt.Sprintf("Cannot delete a backup taken with method: %v", backup.Spec.Method),
+			http.StatusBadRequest)
+		return
+	}
+
+	if err := postgres.DeleteBackupFromObjectStore(ctx, ws.typedClient, &cluster, &backup); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while deleting backup: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// serveBackupStatus reports the progress of a backup running on this instance
+func (ws *localWebserverEndpoints) serveBackupStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	backupName := strings.TrimPrefix(r.URL.Path, url.PathPgBackupStatus)
+	if len(backupName) == 0 {
+		http.Error(w, "Missing backup name", http.StatusBadRequest)
+		return
+	}
+
+	progress, ok := postgres.GetBackupProgress(backupName)
+	if !ok {
+		sendJSONResponse(w, http.StatusNotFound, Response[any]{
+			Error: &Error{
+				Code:    "BACKUP_NOT_RUNNING",
+				Message: fmt.Sprintf("backup %s is not running on this instance", backupName),
+			},
+		})
+		return
+	}
+
+	sendJSONResponseWithData(w, http.StatusOK, progress)
+}
+
 func (ws *localWebserverEndpoints) startBarmanBackup(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -230,3 +523,241 @@ func (ws *localWebserverEndpoints) startPluginBackup(
 	cmd := NewPluginBackupCommand(cluster, backup, ws.typedClient, ws.eventRecorder)
 	cmd.Start(ctx)
 }
+
+// serveWALArchiveStatus reports the status of the WAL archiving process,
+// including the archive lag in segments and bytes when running on the primary,
+// with a GET request. A POST request records the outcome of an execution of
+// the archive command, sent by the wal-archive process
+func (ws *localWebserverEndpoints) serveWALArchiveStatus(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		status, err := ws.instance.GetWALArchiveStatus(r.Context())
+		if err != nil {
+			log.Debug("WAL archive status endpoint failing", "err", err.Error())
+			sendJSONResponse(w, http.StatusInternalServerError, Response[any]{
+				Error: &Error{
+					Code:    "WAL_ARCHIVE_STATUS_FAILED",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+
+		sendJSONResponseWithData(w, http.StatusOK, status)
+
+	case http.MethodPost:
+		defer func() {
+			if err := r.Body.Close(); err != nil {
+				log.Error(err, "while closing the body")
+			}
+		}()
+
+		var batch pg.WALArchiveBatch
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			sendBadRequestJSONResponse(w, "FAILED_TO_PARSE_REQUEST", "Failed to parse request body")
+			return
+		}
+
+		ws.instance.RecordWALArchiveBatch(batch)
+		observeWALArchiveBatch(batch)
+		sendJSONResponse(w, http.StatusOK, Response[any]{})
+
+	default:
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveWALRestoreStatus reports the status of the WAL restore process,
+// including where the WAL files should be fetched from, with a GET request.
+// A POST request records the outcome of an execution of the restore
+// command, sent by the wal-restore process
+func (ws *localWebserverEndpoints) serveWALRestoreStatus(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		status := ws.instance.GetWALRestoreStatus()
+		if status == nil {
+			status = &pg.WALRestoreStatus{Mode: pg.WALRestoreModeArchive}
+		}
+
+		sendJSONResponseWithData(w, http.StatusOK, status)
+
+	case http.MethodPost:
+		defer func() {
+			if err := r.Body.Close(); err != nil {
+				log.Error(err, "while closing the body")
+			}
+		}()
+
+		var result pg.WALRestoreResult
+		if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+			sendBadRequestJSONResponse(w, "FAILED_TO_PARSE_REQUEST", "Failed to parse request body")
+			return
+		}
+
+		ws.instance.RecordWALRestoreResult(result)
+		sendJSONResponse(w, http.StatusOK, Response[any]{})
+
+	default:
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+	}
+}
+
+// streamBaseBackup streams a tarball of the data directory taken with
+// pg_basebackup. A GET request without the `id` parameter starts a new
+// base backup, whose ID is returned in the response headers. The tarball
+// is not stored on disk: a client can resume an interrupted transfer
+// issuing a GET request with the `id` and the `offset` parameters as long
+// as the offset is still in the in-memory buffer, and should release the
+// base backup with a DELETE request once done. Base backups without a
+// client for baseBackupIdleTimeout are discarded.
+func (ws *localWebserverEndpoints) streamBaseBackup(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		session, offset, ok := ws.getBaseBackupSession(w, r)
+		if !ok {
+			return
+		}
+
+		generation, err := session.attach(offset)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		defer session.detach(generation)
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Header().Set(baseBackupIDHeader, session.id)
+		w.WriteHeader(http.StatusOK)
+
+		if err := session.follow(r.Context(), generation, offset, w); err != nil {
+			log.Error(err, "while streaming base backup", "id", session.id, "offset", offset)
+			// The response status has already been sent, the only way we
+			// have to tell the client that the tarball is truncated
+			// is aborting the connection
+			panic(http.ErrAbortHandler)
+		}
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if !ws.baseBackupSessions.remove(id) {
+			http.Error(w, "Unknown base backup", http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, "OK")
+
+	default:
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+	}
+}
+
+// getBaseBackupSession gets the base backup session requested by the client,
+// starting a new one if needed. In case of errors, the response is written and
+// false is returned
+func (ws *localWebserverEndpoints) getBaseBackupSession(
+	w http.ResponseWriter,
+	r *http.Request,
+) (*baseBackupSession, int64, bool) {
+	offset, err := parseBaseBackupOffset(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	if id := r.URL.Query().Get("id"); id != "" {
+		session := ws.baseBackupSessions.get(id)
+		if session == nil {
+			http.Error(w, "Unknown base backup", http.StatusNotFound)
+			return nil, 0, false
+		}
+		return session, offset, true
+	}
+
+	if offset != 0 {
+		http.Error(w, "Cannot use an offset when starting a new base backup", http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	var cluster apiv1.Cluster
+	if err := ws.typedClient.Get(r.Context(), client.ObjectKey{
+		Namespace: ws.instance.Namespace,
+		Name:      ws.instance.ClusterName,
+	}, &cluster); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting cluster: %v", err.Error()),
+			http.StatusInternalServerError)
+		return nil, 0, false
+	}
+
+	if cluster.ContainsTablespaces() {
+		http.Error(w, "Streaming base backups are not supported with tablespaces", http.StatusConflict)
+		return nil, 0, false
+	}
+
+	options := postgres.BaseBackupOptions{
+		MaxRate:        r.URL.Query().Get("maxRate"),
+		FastCheckpoint: r.URL.Query().Get("checkpoint") == "fast",
+	}
+	session, err := ws.baseBackupSessions.start(func(ctx context.Context, output io.Writer) error {
+		return ws.instance.TakeBaseBackup(ctx, options, output)
+	})
+	if errors.Is(err, errBaseBackupRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return nil, 0, false
+	}
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while starting base backup: %v", err.Error()),
+			http.StatusInternalServerError)
+		return nil, 0, false
+	}
+
+	return session, 0, true
+}
+
+// serveControlData reports the output of pg_controldata, parsed in
+// its fields
+func (ws *localWebserverEndpoints) serveControlData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	controlData, err := ws.instance.GetParsedControlData()
+	if err != nil {
+		log.Debug("Instance pg_controldata endpoint failing", "err", err.Error())
+		sendJSONResponse(w, http.StatusInternalServerError, Response[any]{
+			Error: &Error{
+				Code:    "CONTROLDATA_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	sendJSONResponseWithData(w, http.StatusOK, controlData)
+}
+
+// serveSettings reports the configuration parameters whose value
+// doesn't come from the built-in defaults
+func (ws *localWebserverEndpoints) serveSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	settings, err := ws.instance.GetNonDefaultSettings(r.Context())
+	if err != nil {
+		log.Debug("Instance settings endpoint failing", "err", err.Error())
+		sendJSONResponse(w, http.StatusInternalServerError, Response[any]{
+			Error: &Error{
+				Code:    "SETTINGS_FAILED",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	sendJSONResponseWithData(w, http.StatusOK, settings)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cache refresh endpoint", func() {
+	var refreshed int
+
+	newEndpoints := func(err error) *localWebserverEndpoints {
+		refreshed = 0
+		return &localWebserverEndpoints{
+			refreshCache: func(context.Context) error {
+				refreshed++
+				return err
+			},
+		}
+	}
+
+	It("refreshes the cache on request", func() {
+		recorder := httptest.NewRecorder()
+		newEndpoints(nil).serveCacheRefresh(recorder,
+			httptest.NewRequest(http.MethodPost, url.PathCacheRefresh, nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(refreshed).To(Equal(1))
+	})
+
+	It("reports the refresh errors", func() {
+		recorder := httptest.NewRecorder()
+		newEndpoints(errors.New("boom")).serveCacheRefresh(recorder,
+			httptest.NewRequest(http.MethodPost, url.PathCacheRefresh, nil))
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		Expect(recorder.Body.String()).To(ContainSubstring("CACHE_REFRESH_FAILED"))
+	})
+
+	It("accepts only POST requests", func() {
+		recorder := httptest.NewRecorder()
+		newEndpoints(nil).serveCacheRefresh(recorder,
+			httptest.NewRequest(http.MethodGet, url.PathCacheRefresh, nil))
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(refreshed).To(BeZero())
+	})
+})
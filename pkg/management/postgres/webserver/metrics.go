@@ -0,0 +1,151 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// metricsNamespace is the namespace of the webserver metrics, the same
+	// used for the other metrics exposed by the instance manager
+	metricsNamespace = "cnpg"
+
+	// metricsSubsystem is the subsystem of the webserver metrics
+	metricsSubsystem = "instance_manager_webserver"
+
+	// walArchiveMetricsSubsystem is the subsystem of the metrics of the
+	// archive command, which are reported to the local webserver
+	walArchiveMetricsSubsystem = "instance_manager_wal_archive"
+)
+
+// Labels used by the webserver metrics
+const (
+	backupRequestSucceeded = "succeeded"
+	backupRequestFailed    = "failed"
+
+	cacheHit   = "hit"
+	cacheMiss  = "miss"
+	cacheError = "error"
+
+	walArchiveSucceeded = "succeeded"
+	walArchiveFailed    = "failed"
+)
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the requests served by the instance manager webservers.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "handler", "method", "code"})
+
+	backupRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "backup_requests_total",
+		Help:      "Total number of backup requests received, by backup method and outcome.",
+	}, []string{"method", "outcome"})
+
+	cacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "cache_requests_total",
+		Help:      "Total number of requests for cached objects, by object and result (hit, miss, error).",
+	}, []string{"object", "result"})
+
+	walArchiveSegmentsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: walArchiveMetricsSubsystem,
+		Name:      "segments_total",
+		Help:      "Total number of WAL files processed by the archive command, by outcome.",
+	}, []string{"outcome"})
+
+	walArchiveUploadDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: walArchiveMetricsSubsystem,
+		Name:      "upload_duration_seconds",
+		Help:      "Time spent by the archive command uploading a batch of WAL files.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	walArchiveParallelism = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: walArchiveMetricsSubsystem,
+		Name:      "parallelism",
+		Help:      "Number of WAL files archived in parallel by the last execution of the archive command.",
+	})
+)
+
+// Collectors returns the collectors of the metrics of the webservers, to be
+// exposed by the metrics server
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		requestDuration,
+		backupRequestsTotal,
+		cacheRequestsTotal,
+		walArchiveSegmentsTotal,
+		walArchiveUploadDuration,
+		walArchiveParallelism,
+	}
+}
+
+// observeWALArchiveBatch updates the metrics of the archive command
+// with the outcome of one of its executions
+func observeWALArchiveBatch(batch pg.WALArchiveBatch) {
+	walArchiveSegmentsTotal.WithLabelValues(walArchiveSucceeded).Add(float64(batch.Archived))
+	walArchiveSegmentsTotal.WithLabelValues(walArchiveFailed).Add(float64(batch.Failed))
+	walArchiveUploadDuration.Observe(batch.UploadSeconds)
+	walArchiveParallelism.Set(float64(batch.Parallelism))
+}
+
+// instrumentedServeMux is an http.ServeMux measuring the duration of the
+// requests served by each registered handler
+type instrumentedServeMux struct {
+	*http.ServeMux
+	serverName string
+
+	// The routes of the REST API registered via HandleAPI
+	routes []apiRoute
+}
+
+func newInstrumentedServeMux(serverName string) *instrumentedServeMux {
+	return &instrumentedServeMux{
+		ServeMux:   http.NewServeMux(),
+		serverName: serverName,
+	}
+}
+
+// Handle registers the handler for the given pattern
+func (mux *instrumentedServeMux) Handle(pattern string, handler http.Handler) {
+	observer := requestDuration.MustCurryWith(prometheus.Labels{
+		"server":  mux.serverName,
+		"handler": pattern,
+	})
+	mux.ServeMux.Handle(pattern, promhttp.InstrumentHandlerDuration(observer, handler))
+}
+
+// HandleFunc registers the handler function for the given pattern
+func (mux *instrumentedServeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	mux.Handle(pattern, http.HandlerFunc(handler))
+}
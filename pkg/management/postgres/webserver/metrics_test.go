@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("webserver metrics", func() {
+	It("measures the requests served by each handler", func() {
+		mux := newInstrumentedServeMux("test")
+		mux.HandleFunc("/test/", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+
+		for _, path := range []string{"/test/one", "/test/two"} {
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			Expect(recorder.Code).To(Equal(http.StatusTeapot))
+		}
+
+		registry := prometheus.NewPedanticRegistry()
+		Expect(registry.Register(requestDuration)).To(Succeed())
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveLen(1))
+
+		var sampleCount uint64
+		for _, metric := range families[0].GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["server"] == "test" && labels["handler"] == "/test/" &&
+				labels["method"] == "get" && labels["code"] == "418" {
+				sampleCount = metric.GetHistogram().GetSampleCount()
+			}
+		}
+		Expect(sampleCount).To(BeEquivalentTo(2))
+	})
+
+	It("counts the cache hits and misses", func() {
+		const object = "test-object"
+		observeCacheRequest(object, nil)
+		observeCacheRequest(object, cache.ErrCacheMiss)
+		observeCacheRequest(object, cache.ErrCacheMiss)
+		observeCacheRequest(object, errors.New("boom"))
+
+		Expect(testutil.ToFloat64(cacheRequestsTotal.WithLabelValues(object, cacheHit))).To(BeEquivalentTo(1))
+		Expect(testutil.ToFloat64(cacheRequestsTotal.WithLabelValues(object, cacheMiss))).To(BeEquivalentTo(2))
+		Expect(testutil.ToFloat64(cacheRequestsTotal.WithLabelValues(object, cacheError))).To(BeEquivalentTo(1))
+	})
+	It("records the outcome of the archive command", func() {
+		before := testutil.ToFloat64(walArchiveSegmentsTotal.WithLabelValues(walArchiveSucceeded))
+		observeWALArchiveBatch(pg.WALArchiveBatch{
+			Archived:      3,
+			Failed:        1,
+			Parallelism:   4,
+			UploadSeconds: 2.5,
+		})
+
+		Expect(testutil.ToFloat64(walArchiveSegmentsTotal.WithLabelValues(walArchiveSucceeded)) - before).
+			To(BeEquivalentTo(3))
+		Expect(testutil.ToFloat64(walArchiveParallelism)).To(BeEquivalentTo(4))
+		Expect(testutil.CollectAndCount(walArchiveUploadDuration)).To(Equal(1))
+	})
+})
@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// certificateLabels are the labels identifying a certificate of the instance
+var certificateLabels = []string{"certificate"}
+
+// getCertificatesExpiration reads the validity of the certificates
+// used by the instance
+var getCertificatesExpiration = func(instance *postgres.Instance) ([]pg.CertificateExpiration, error) {
+	return instance.GetCertificatesExpiration()
+}
+
+// CertificatesMetrics contains the days left before the certificates
+// used by the instance expire
+type CertificatesMetrics struct {
+	DaysToExpiry *prometheus.GaugeVec
+}
+
+func (m CertificatesMetrics) reset() {
+	m.DaysToExpiry.Reset()
+}
+
+// collectCertificates measures the certificates currently used by the instance
+func collectCertificates(e *Exporter) error {
+	expirations, err := getCertificatesExpiration(e.instance)
+	if err != nil {
+		return err
+	}
+
+	e.Metrics.CertificatesMetrics.reset()
+	for _, expiration := range expirations {
+		e.Metrics.CertificatesMetrics.DaysToExpiry.WithLabelValues(expiration.Name).Set(expiration.DaysToExpiry)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("certificates metrics", func() {
+	var (
+		exporter    *Exporter
+		expirations []pg.CertificateExpiration
+		readErr     error
+	)
+
+	BeforeEach(func() {
+		readErr = nil
+		expirations = []pg.CertificateExpiration{
+			{Name: "server", DaysToExpiry: 12.5},
+			{Name: "client-ca", DaysToExpiry: -1},
+		}
+		exporter = NewExporter(postgres.NewInstance())
+
+		DeferCleanup(func(previous func(*postgres.Instance) ([]pg.CertificateExpiration, error)) {
+			getCertificatesExpiration = previous
+		}, getCertificatesExpiration)
+		getCertificatesExpiration = func(*postgres.Instance) ([]pg.CertificateExpiration, error) {
+			return expirations, readErr
+		}
+	})
+
+	It("reports the days left for each certificate", func() {
+		Expect(collectCertificates(exporter)).To(Succeed())
+
+		daysToExpiry := exporter.Metrics.CertificatesMetrics.DaysToExpiry
+		Expect(testutil.ToFloat64(daysToExpiry.WithLabelValues("server"))).To(BeEquivalentTo(12.5))
+		Expect(testutil.ToFloat64(daysToExpiry.WithLabelValues("client-ca"))).To(BeEquivalentTo(-1))
+		Expect(testutil.CollectAndCount(daysToExpiry)).To(Equal(2))
+	})
+
+	It("drops the certificates not used anymore", func() {
+		Expect(collectCertificates(exporter)).To(Succeed())
+
+		expirations = expirations[:1]
+		Expect(collectCertificates(exporter)).To(Succeed())
+		Expect(testutil.CollectAndCount(exporter.Metrics.CertificatesMetrics.DaysToExpiry)).To(Equal(1))
+	})
+
+	It("returns the error reading the certificates", func() {
+		readErr = errors.New("boom")
+		Expect(collectCertificates(exporter)).To(MatchError("boom"))
+	})
+})
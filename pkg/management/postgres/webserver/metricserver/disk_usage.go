@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"path"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+)
+
+// diskUsageLabels are the labels identifying a volume of the instance
+var diskUsageLabels = []string{"volume"}
+
+// Values of the volume label
+const (
+	diskUsageVolumeData = "data"
+	diskUsageVolumeWAL  = "wal"
+)
+
+// diskUsageVolume is a volume of the instance whose usage is measured
+type diskUsageVolume struct {
+	name string
+	path string
+}
+
+// getDiskUsage gets the total and available bytes of the filesystem
+// containing the passed path
+var getDiskUsage = compatibility.GetDiskUsage
+
+// DiskUsageMetrics contains the size and the available space of the
+// volumes of the instance
+type DiskUsageMetrics struct {
+	Total     *prometheus.GaugeVec
+	Available *prometheus.GaugeVec
+}
+
+func (m DiskUsageMetrics) reset() {
+	m.Total.Reset()
+	m.Available.Reset()
+}
+
+// collectDiskUsage measures the data volume and, when the cluster has a
+// dedicated one, the WAL volume
+func collectDiskUsage(e *Exporter) error {
+	volumes := []diskUsageVolume{
+		{name: diskUsageVolumeData, path: e.instance.PgData},
+	}
+	if cluster, err := cache.LoadClusterUnsafe(); err == nil && cluster.ShouldCreateWalArchiveVolume() {
+		volumes = append(volumes, diskUsageVolume{
+			name: diskUsageVolumeWAL,
+			path: path.Join(e.instance.PgData, "pg_wal"),
+		})
+	}
+
+	e.Metrics.DiskUsageMetrics.reset()
+	for _, volume := range volumes {
+		total, available, err := getDiskUsage(volume.path)
+		if err != nil {
+			return err
+		}
+		e.Metrics.DiskUsageMetrics.Total.WithLabelValues(volume.name).Set(float64(total))
+		e.Metrics.DiskUsageMetrics.Available.WithLabelValues(volume.name).Set(float64(available))
+	}
+
+	return nil
+}
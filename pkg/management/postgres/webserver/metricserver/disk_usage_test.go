@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("disk usage metrics", func() {
+	var (
+		exporter *Exporter
+		measured []string
+		diskErr  error
+	)
+
+	BeforeEach(func() {
+		measured = nil
+		diskErr = nil
+		cache.Delete(cache.ClusterKey)
+		DeferCleanup(cache.Delete, cache.ClusterKey)
+
+		instance := postgres.NewInstance()
+		instance.PgData = "/var/lib/postgresql/data/pgdata"
+		exporter = NewExporter(instance)
+
+		DeferCleanup(func(previous func(string) (uint64, uint64, error)) {
+			getDiskUsage = previous
+		}, getDiskUsage)
+		getDiskUsage = func(path string) (uint64, uint64, error) {
+			measured = append(measured, path)
+			return 1000, 400, diskErr
+		}
+	})
+
+	It("only measures the data volume without a dedicated WAL volume", func() {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{})
+
+		Expect(collectDiskUsage(exporter)).To(Succeed())
+		Expect(measured).To(Equal([]string{"/var/lib/postgresql/data/pgdata"}))
+		Expect(testutil.ToFloat64(exporter.Metrics.DiskUsageMetrics.Total.WithLabelValues("data"))).
+			To(BeEquivalentTo(1000))
+		Expect(testutil.ToFloat64(exporter.Metrics.DiskUsageMetrics.Available.WithLabelValues("data"))).
+			To(BeEquivalentTo(400))
+		Expect(testutil.CollectAndCount(exporter.Metrics.DiskUsageMetrics.Total)).To(Equal(1))
+	})
+
+	It("measures the WAL volume when the cluster has a dedicated one", func() {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				WalStorage: &apiv1.StorageConfiguration{Size: "1Gi"},
+			},
+		})
+
+		Expect(collectDiskUsage(exporter)).To(Succeed())
+		Expect(measured).To(Equal([]string{
+			"/var/lib/postgresql/data/pgdata",
+			"/var/lib/postgresql/data/pgdata/pg_wal",
+		}))
+		Expect(testutil.CollectAndCount(exporter.Metrics.DiskUsageMetrics.Total)).To(Equal(2))
+		Expect(testutil.CollectAndCount(exporter.Metrics.DiskUsageMetrics.Available)).To(Equal(2))
+	})
+
+	It("reports the errors measuring the volumes", func() {
+		diskErr = errors.New("no such file or directory")
+
+		Expect(collectDiskUsage(exporter)).To(MatchError(diskErr))
+	})
+})
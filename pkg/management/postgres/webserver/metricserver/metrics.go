@@ -24,6 +24,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/inactive"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/walstorage"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
@@ -49,6 +51,24 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 	if err := registry.Register(collectors.NewGoCollector()); err != nil {
 		return nil, fmt.Errorf("while registering Go exporters: %w", err)
 	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, fmt.Errorf("while registering process exporters: %w", err)
+	}
+	for _, collector := range webserver.Collectors() {
+		if err := registry.Register(collector); err != nil {
+			return nil, fmt.Errorf("while registering webserver exporters: %w", err)
+		}
+	}
+	for _, collector := range inactive.Collectors() {
+		if err := registry.Register(collector); err != nil {
+			return nil, fmt.Errorf("while registering inactive replication slots exporters: %w", err)
+		}
+	}
+	for _, collector := range walstorage.Collectors() {
+		if err := registry.Register(collector); err != nil {
+			return nil, fmt.Errorf("while registering WAL storage exporters: %w", err)
+		}
+	}
 	serveMux := http.NewServeMux()
 	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
@@ -0,0 +1,343 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+var (
+	// objectSizesDatabaseLabels are the labels identifying a database
+	objectSizesDatabaseLabels = []string{"datname"}
+
+	// objectSizesSchemaLabels are the labels identifying a schema
+	objectSizesSchemaLabels = []string{"datname", "schemaname"}
+
+	// objectSizesTableLabels are the labels identifying a table
+	objectSizesTableLabels = []string{"datname", "schemaname", "relname"}
+)
+
+// objectSizesOtherTables is the value of the relname label used for
+// the size of the tables not ranking in the largest ones
+const objectSizesOtherTables = "other"
+
+// objectSizesDatabasesQuery lists the databases accepting connections
+const objectSizesDatabasesQuery = `SELECT datname FROM pg_catalog.pg_database
+WHERE datallowconn AND NOT datistemplate ORDER BY datname`
+
+// objectSizesDatabaseSizeQuery gets the size of the current database
+const objectSizesDatabaseSizeQuery = "SELECT pg_catalog.pg_database_size(pg_catalog.current_database())"
+
+// objectSizesTablesQuery gets the size of the tables and materialized views
+// of the current database, including their indexes and TOAST data. The
+// system schemas are skipped
+const objectSizesTablesQuery = `SELECT n.nspname, c.relname, pg_catalog.pg_total_relation_size(c.oid)
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'm')
+	AND n.nspname <> 'information_schema'
+	AND n.nspname !~ '^pg_'`
+
+// objectSize is the size of a database, a schema or a table
+type objectSize struct {
+	datname    string
+	schemaname string
+	relname    string
+	size       int64
+}
+
+// objectSizes contains the collected size of the objects
+type objectSizes struct {
+	databases []objectSize
+	schemas   []objectSize
+	tables    []objectSize
+}
+
+// objectSizesCache contains the last size of the objects collected in
+// the background, which is exported by every scrape
+type objectSizesCache struct {
+	mu sync.Mutex
+
+	// startedAt is the last time a collection has been started
+	startedAt time.Time
+
+	// collecting is true while a collection is running
+	collecting bool
+
+	// sizes is the result of the last successful collection,
+	// and refreshedAt is the time it has been completed
+	sizes       *objectSizes
+	refreshedAt time.Time
+}
+
+// get gets the result of the last successful collection
+func (c *objectSizesCache) get() (*objectSizes, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sizes, c.refreshedAt
+}
+
+// clear forgets the collected size of the objects
+func (c *objectSizesCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.startedAt = time.Time{}
+	c.sizes = nil
+	c.refreshedAt = time.Time{}
+}
+
+// startCollection checks whether a new collection is due, marking it
+// as running in that case. A collection is not started again before
+// the refresh interval, even when the previous one failed, as it
+// requires a scan of the catalogs of every database
+func (c *objectSizesCache) startCollection(refreshInterval time.Duration, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.collecting || now.Sub(c.startedAt) < refreshInterval {
+		return false
+	}
+	c.collecting = true
+	c.startedAt = now
+	return true
+}
+
+// endCollection stores the result of a collection, keeping the
+// previous one when the collection failed
+func (c *objectSizesCache) endCollection(sizes *objectSizes, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.collecting = false
+	if sizes != nil {
+		c.sizes = sizes
+		c.refreshedAt = now
+	}
+}
+
+func (m ObjectSizesMetrics) reset() {
+	m.DatabaseSize.Reset()
+	m.SchemaSize.Reset()
+	m.TableSize.Reset()
+}
+
+// update replaces the exported sizes with the passed ones, collected at
+// the passed time, to avoid reporting objects that have been dropped or
+// are not collected anymore
+func (m ObjectSizesMetrics) update(sizes *objectSizes, refreshedAt time.Time) {
+	m.reset()
+	for _, database := range sizes.databases {
+		m.DatabaseSize.WithLabelValues(database.datname).Set(float64(database.size))
+	}
+	for _, schema := range sizes.schemas {
+		m.SchemaSize.WithLabelValues(schema.datname, schema.schemaname).Set(float64(schema.size))
+	}
+	for _, table := range sizes.tables {
+		m.TableSize.WithLabelValues(table.datname, table.schemaname, table.relname).Set(float64(table.size))
+	}
+	m.LastRefresh.Set(float64(refreshedAt.UnixNano()) / 1e9)
+}
+
+// collectObjectSizes exports the size of the objects collected by the
+// last successful refresh, starting a new one in the background when due,
+// so that the scrape is not delayed by the scan of every database
+func collectObjectSizes(e *Exporter, db *sql.DB) error {
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !cluster.Spec.Monitoring.IsObjectSizesEnabled() {
+		e.Metrics.ObjectSizesMetrics.reset()
+		e.Metrics.ObjectSizesMetrics.LastRefresh.Set(0)
+		e.objectSizes.clear()
+		return nil
+	}
+
+	configuration := cluster.Spec.Monitoring.ObjectSizes
+	if e.objectSizes.startCollection(configuration.GetRefreshInterval(), time.Now()) {
+		go e.refreshObjectSizes(db, e.instance.ConnectionPool().Connection, configuration)
+	}
+
+	if sizes, refreshedAt := e.objectSizes.get(); sizes != nil {
+		e.Metrics.ObjectSizesMetrics.update(sizes, refreshedAt)
+	}
+	return nil
+}
+
+// refreshObjectSizes collects the size of the objects and stores it in
+// the cache. When the collection fails, the previous sizes are kept
+// and exported until the next refresh
+func (e *Exporter) refreshObjectSizes(
+	db *sql.DB,
+	connect func(dbname string) (*sql.DB, error),
+	configuration *apiv1.ObjectSizesConfiguration,
+) {
+	sizes, err := getObjectSizes(db, connect, configuration)
+	if err != nil {
+		log.Error(err, "while collecting the object sizes")
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.ObjectSizes").Inc()
+	}
+	e.objectSizes.endCollection(sizes, time.Now())
+}
+
+// getObjectSizes gets the size of the databases, schemas and tables selected
+// by the passed configuration. The databases are listed using the passed
+// connection, while the passed function is used to connect to each of them.
+// Only the largest tables are reported individually, while the size of the
+// remaining ones is aggregated in a single entry
+func getObjectSizes(
+	db *sql.DB,
+	connect func(dbname string) (*sql.DB, error),
+	configuration *apiv1.ObjectSizesConfiguration,
+) (*objectSizes, error) {
+	filter, err := configuration.GetFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := getObjectSizesDatabases(db, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objectSizes{}
+	var tables []objectSize
+	for _, datname := range databases {
+		databaseDB, err := connect(datname)
+		if err != nil {
+			return nil, fmt.Errorf("while connecting to database %q: %w", datname, err)
+		}
+
+		database, schemas, databaseTables, err := getDatabaseObjectSizes(databaseDB, datname, filter)
+		if err != nil {
+			return nil, fmt.Errorf("while getting the size of the objects of database %q: %w", datname, err)
+		}
+
+		result.databases = append(result.databases, database)
+		result.schemas = append(result.schemas, schemas...)
+		tables = append(tables, databaseTables...)
+	}
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].size > tables[j].size
+	})
+
+	maxTables := configuration.GetMaxTables()
+	other := objectSize{relname: objectSizesOtherTables}
+	for i, table := range tables {
+		if i < maxTables {
+			result.tables = append(result.tables, table)
+		} else {
+			other.size += table.size
+		}
+	}
+	result.tables = append(result.tables, other)
+
+	return result, nil
+}
+
+// getObjectSizesDatabases gets the names of the databases to be collected
+func getObjectSizesDatabases(db *sql.DB, filter *apiv1.ObjectSizesFilter) ([]string, error) {
+	rows, err := db.Query(objectSizesDatabasesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for the databases")
+		}
+	}()
+
+	var result []string
+	for rows.Next() {
+		var datname string
+		if err := rows.Scan(&datname); err != nil {
+			return nil, err
+		}
+
+		if filter.IsDatabaseIncluded(datname) {
+			result = append(result, datname)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getDatabaseObjectSizes gets the size of a database, of its schemas and
+// of its tables selected by the passed filter. The size of the
+// schemas accounts for all their tables, including the skipped ones
+func getDatabaseObjectSizes(
+	db *sql.DB,
+	datname string,
+	filter *apiv1.ObjectSizesFilter,
+) (database objectSize, schemas []objectSize, tables []objectSize, err error) {
+	database.datname = datname
+	if err = db.QueryRow(objectSizesDatabaseSizeQuery).Scan(&database.size); err != nil {
+		return database, nil, nil, err
+	}
+
+	rows, err := db.Query(objectSizesTablesQuery)
+	if err != nil {
+		return database, nil, nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for the tables")
+		}
+	}()
+
+	schemaSizes := make(map[string]int64)
+	for rows.Next() {
+		table := objectSize{datname: datname}
+		if err = rows.Scan(&table.schemaname, &table.relname, &table.size); err != nil {
+			return database, nil, nil, err
+		}
+		schemaSizes[table.schemaname] += table.size
+
+		if filter.IsTableIncluded(table.schemaname, table.relname) {
+			tables = append(tables, table)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return database, nil, nil, err
+	}
+
+	for schemaname, size := range schemaSizes {
+		schemas = append(schemas, objectSize{datname: datname, schemaname: schemaname, size: size})
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].schemaname < schemas[j].schemaname
+	})
+
+	return database, schemas, tables, nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("object sizes metrics", func() {
+	var (
+		db        *sql.DB
+		mock      sqlmock.Sqlmock
+		appDB     *sql.DB
+		appMock   sqlmock.Sqlmock
+		connected []string
+	)
+
+	newMock := func() (*sql.DB, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+		return db, mock
+	}
+
+	connect := func(dbname string) (*sql.DB, error) {
+		connected = append(connected, dbname)
+		switch dbname {
+		case "app":
+			return appDB, nil
+		case "postgres":
+			return db, nil
+		default:
+			return nil, errors.New("unknown database")
+		}
+	}
+
+	expectTables := func(mock sqlmock.Sqlmock, size int64, tables ...string) {
+		mock.ExpectQuery(objectSizesDatabaseSizeQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(size))
+		rows := sqlmock.NewRows([]string{"nspname", "relname", "size"})
+		for i := 0; i < len(tables); i += 2 {
+			rows.AddRow(tables[i], tables[i+1], int64(len(tables)-i)*100)
+		}
+		mock.ExpectQuery(objectSizesTablesQuery).WillReturnRows(rows)
+	}
+
+	BeforeEach(func() {
+		db, mock = newMock()
+		appDB, appMock = newMock()
+		connected = nil
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(appMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("gets the size of the selected databases, schemas and tables", func() {
+		mock.ExpectQuery(objectSizesDatabasesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app").AddRow("app_test").AddRow("postgres"))
+		expectTables(appMock, 10000,
+			"public", "orders",
+			"public", "tmp_orders",
+			"sales", "invoices")
+		expectTables(mock, 5000)
+
+		sizes, err := getObjectSizes(db, connect, &apiv1.ObjectSizesConfiguration{
+			ExcludeDatabases: []string{"_test$"},
+			ExcludeTables:    []string{`\.tmp_`},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(connected).To(Equal([]string{"app", "postgres"}))
+		Expect(sizes.databases).To(Equal([]objectSize{
+			{datname: "app", size: 10000},
+			{datname: "postgres", size: 5000},
+		}))
+		Expect(sizes.schemas).To(Equal([]objectSize{
+			{datname: "app", schemaname: "public", size: 1000},
+			{datname: "app", schemaname: "sales", size: 200},
+		}))
+		Expect(sizes.tables).To(Equal([]objectSize{
+			{datname: "app", schemaname: "public", relname: "orders", size: 600},
+			{datname: "app", schemaname: "sales", relname: "invoices", size: 200},
+			{relname: objectSizesOtherTables},
+		}))
+	})
+
+	It("aggregates the size of the tables exceeding the cap", func() {
+		mock.ExpectQuery(objectSizesDatabasesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app"))
+		expectTables(appMock, 10000,
+			"public", "orders",
+			"public", "customers",
+			"public", "invoices")
+
+		sizes, err := getObjectSizes(db, connect, &apiv1.ObjectSizesConfiguration{MaxTables: 1})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sizes.tables).To(Equal([]objectSize{
+			{datname: "app", schemaname: "public", relname: "orders", size: 600},
+			{relname: objectSizesOtherTables, size: 600},
+		}))
+
+		metrics := newMetrics().ObjectSizesMetrics
+		metrics.update(sizes, time.Unix(1700000000, 0))
+		Expect(testutil.ToFloat64(metrics.TableSize.WithLabelValues("", "", objectSizesOtherTables))).
+			To(BeEquivalentTo(600))
+		Expect(testutil.ToFloat64(metrics.DatabaseSize.WithLabelValues("app"))).To(BeEquivalentTo(10000))
+		Expect(testutil.ToFloat64(metrics.LastRefresh)).To(BeEquivalentTo(1700000000))
+	})
+
+	It("reports the failures connecting to the databases", func() {
+		mock.ExpectQuery(objectSizesDatabasesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("other"))
+
+		_, err := getObjectSizes(db, connect, &apiv1.ObjectSizesConfiguration{})
+		Expect(err).To(MatchError(ContainSubstring(`while connecting to database "other"`)))
+	})
+	It("keeps the previous sizes when a refresh fails", func() {
+		exporter := &Exporter{Metrics: newMetrics()}
+
+		mock.ExpectQuery(objectSizesDatabasesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app"))
+		expectTables(appMock, 10000, "public", "orders")
+		exporter.refreshObjectSizes(db, connect, &apiv1.ObjectSizesConfiguration{})
+
+		mock.ExpectQuery(objectSizesDatabasesQuery).WillReturnError(errors.New("timeout"))
+		exporter.refreshObjectSizes(db, connect, &apiv1.ObjectSizesConfiguration{})
+
+		sizes, refreshedAt := exporter.objectSizes.get()
+		Expect(refreshedAt).ToNot(BeZero())
+		Expect(sizes.databases).To(Equal([]objectSize{{datname: "app", size: 10000}}))
+		Expect(testutil.ToFloat64(exporter.Metrics.PgCollectionErrors.WithLabelValues("Collect.ObjectSizes"))).
+			To(BeEquivalentTo(1))
+	})
+})
+
+var _ = Describe("object sizes cache", func() {
+	It("starts a collection only when due and not already running", func() {
+		var objectSizesCache objectSizesCache
+		now := time.Now()
+
+		Expect(objectSizesCache.startCollection(time.Hour, now)).To(BeTrue())
+		Expect(objectSizesCache.startCollection(time.Hour, now.Add(2*time.Hour))).To(BeFalse())
+
+		objectSizesCache.endCollection(nil, now)
+		Expect(objectSizesCache.startCollection(time.Hour, now.Add(time.Minute))).To(BeFalse())
+		Expect(objectSizesCache.startCollection(time.Hour, now.Add(time.Hour))).To(BeTrue())
+
+		objectSizesCache.endCollection(&objectSizes{}, now.Add(time.Hour))
+		sizes, refreshedAt := objectSizesCache.get()
+		Expect(sizes).ToNot(BeNil())
+		Expect(refreshedAt).To(Equal(now.Add(time.Hour)))
+
+		objectSizesCache.clear()
+		sizes, _ = objectSizesCache.get()
+		Expect(sizes).To(BeNil())
+	})
+})
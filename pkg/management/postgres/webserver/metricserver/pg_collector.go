@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -46,6 +47,10 @@ type Exporter struct {
 	instance *postgres.Instance
 	Metrics  *metrics
 	queries  *m.QueriesCollector
+
+	// objectSizes contains the size of the objects, which is
+	// collected in the background
+	objectSizes objectSizesCache
 }
 
 // metrics here are related to the exporter itself, which is instrumented to
@@ -66,7 +71,13 @@ type metrics struct {
 	LastAvailableBackupTimestamp prometheus.Gauge
 	LastFailedBackupTimestamp    prometheus.Gauge
 	FencingOn                    prometheus.Gauge
+	PostmasterExits              prometheus.Gauge
+	PostmasterRestartsPaused     prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
+	PgStatStatementsMetrics      PgStatStatementsMetrics
+	ObjectSizesMetrics           ObjectSizesMetrics
+	DiskUsageMetrics             DiskUsageMetrics
+	CertificatesMetrics          CertificatesMetrics
 	NodesUsed                    prometheus.Gauge
 }
 
@@ -82,6 +93,32 @@ type PgStatWalMetrics struct {
 	WalSyncTime    *prometheus.GaugeVec
 }
 
+// PgStatStatementsMetrics contains the statistics of the top queries
+// tracked by pg_stat_statements, available when enabled in the cluster
+type PgStatStatementsMetrics struct {
+	Calls    *prometheus.Desc
+	ExecTime *prometheus.Desc
+	Rows     *prometheus.Desc
+
+	// Protects the statistics, which are updated while collecting
+	mutex sync.Mutex
+
+	// The statistics gathered by the latest collection
+	statements []pgStatStatementsRow
+
+	// The queries whose statistics are exported individually
+	tracked map[pgStatStatementsKey]struct{}
+}
+
+// ObjectSizesMetrics contains the size of the databases, schemas and
+// tables, available when enabled in the cluster
+type ObjectSizesMetrics struct {
+	DatabaseSize *prometheus.GaugeVec
+	SchemaSize   *prometheus.GaugeVec
+	TableSize    *prometheus.GaugeVec
+	LastRefresh  prometheus.Gauge
+}
+
 // NewExporter creates an exporter
 func NewExporter(instance *postgres.Instance) *Exporter {
 	return &Exporter{
@@ -187,6 +224,18 @@ func newMetrics() *metrics {
 			Name:      "fencing_on",
 			Help:      "1 if the instance is fenced, 0 otherwise",
 		}),
+		PostmasterExits: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "postmaster_exits",
+			Help:      "Number of consecutive unexpected exits of the postmaster",
+		}),
+		PostmasterRestartsPaused: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "postmaster_restarts_paused",
+			Help:      "1 if the restarts of PostgreSQL are paused after an unexpected exit, 0 otherwise",
+		}),
 		NodesUsed: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: PrometheusNamespace,
 			Subsystem: subsystem,
@@ -254,6 +303,79 @@ func newMetrics() *metrics {
 					"fsync_writethrough, otherwise zero). Only available on PG 14+",
 			}, []string{"stats_reset"}),
 		},
+		PgStatStatementsMetrics: PgStatStatementsMetrics{
+			Calls: prometheus.NewDesc(
+				prometheus.BuildFQName(PrometheusNamespace, subsystem, "pg_stat_statements_calls_total"),
+				"Number of times the statement was executed. "+
+					"Only available when the query statistics are enabled",
+				pgStatStatementsLabels, nil),
+			ExecTime: prometheus.NewDesc(
+				prometheus.BuildFQName(PrometheusNamespace, subsystem, "pg_stat_statements_exec_time_seconds_total"),
+				"Total time spent executing the statement, in seconds. "+
+					"Only available when the query statistics are enabled",
+				pgStatStatementsLabels, nil),
+			Rows: prometheus.NewDesc(
+				prometheus.BuildFQName(PrometheusNamespace, subsystem, "pg_stat_statements_rows_total"),
+				"Total number of rows retrieved or affected by the statement. "+
+					"Only available when the query statistics are enabled",
+				pgStatStatementsLabels, nil),
+		},
+
+		ObjectSizesMetrics: ObjectSizesMetrics{
+			DatabaseSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "database_size_bytes",
+				Help: "Size of the database, in bytes. " +
+					"Only available when the object sizes are enabled",
+			}, objectSizesDatabaseLabels),
+			SchemaSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "schema_size_bytes",
+				Help: "Total size of the tables of the schema, including indexes and TOAST data, in bytes. " +
+					"Only available when the object sizes are enabled",
+			}, objectSizesSchemaLabels),
+			TableSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "table_size_bytes",
+				Help: "Total size of the table, including indexes and TOAST data, in bytes. " +
+					"Only available when the object sizes are enabled",
+			}, objectSizesTableLabels),
+			LastRefresh: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "object_sizes_last_refresh_timestamp",
+				Help: "The last time the size of the objects has been collected. " +
+					"Only available when the object sizes are enabled",
+			}),
+		},
+		DiskUsageMetrics: DiskUsageMetrics{
+			Total: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "disk_total_bytes",
+				Help: "Size of the volume, in bytes. The WAL volume is only reported " +
+					"when the cluster has a dedicated one",
+			}, diskUsageLabels),
+			Available: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "disk_available_bytes",
+				Help: "Space available in the volume, in bytes. The WAL volume is only reported " +
+					"when the cluster has a dedicated one",
+			}, diskUsageLabels),
+		},
+		CertificatesMetrics: CertificatesMetrics{
+			DaysToExpiry: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "certificate_days_to_expiry",
+				Help: "Days left before the certificate used by the instance expires, " +
+					"negative when it is already expired",
+			}, certificateLabels),
+		},
 	}
 }
 
@@ -272,9 +394,21 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.PgVersion.Describe(ch)
 	e.Metrics.FirstRecoverabilityPoint.Describe(ch)
 	e.Metrics.FencingOn.Describe(ch)
+	ch <- e.Metrics.PostmasterExits.Desc()
+	ch <- e.Metrics.PostmasterRestartsPaused.Desc()
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	ch <- e.Metrics.PgStatStatementsMetrics.Calls
+	ch <- e.Metrics.PgStatStatementsMetrics.ExecTime
+	ch <- e.Metrics.PgStatStatementsMetrics.Rows
+	e.Metrics.ObjectSizesMetrics.DatabaseSize.Describe(ch)
+	e.Metrics.ObjectSizesMetrics.SchemaSize.Describe(ch)
+	e.Metrics.ObjectSizesMetrics.TableSize.Describe(ch)
+	ch <- e.Metrics.ObjectSizesMetrics.LastRefresh.Desc()
+	e.Metrics.DiskUsageMetrics.Total.Describe(ch)
+	e.Metrics.DiskUsageMetrics.Available.Describe(ch)
+	e.Metrics.CertificatesMetrics.DaysToExpiry.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -310,9 +444,19 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.PgVersion.Collect(ch)
 	e.Metrics.FirstRecoverabilityPoint.Collect(ch)
 	e.Metrics.FencingOn.Collect(ch)
+	ch <- e.Metrics.PostmasterExits
+	ch <- e.Metrics.PostmasterRestartsPaused
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.PgStatStatementsMetrics.collect(ch)
+	e.Metrics.ObjectSizesMetrics.DatabaseSize.Collect(ch)
+	e.Metrics.ObjectSizesMetrics.SchemaSize.Collect(ch)
+	e.Metrics.ObjectSizesMetrics.TableSize.Collect(ch)
+	ch <- e.Metrics.ObjectSizesMetrics.LastRefresh
+	e.Metrics.DiskUsageMetrics.Total.Collect(ch)
+	e.Metrics.DiskUsageMetrics.Available.Collect(ch)
+	e.Metrics.CertificatesMetrics.DaysToExpiry.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -329,6 +473,8 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	e.Metrics.CollectionsTotal.Inc()
 	collectionStart := time.Now()
+	e.collectPostmasterRestarts()
+
 	if e.instance.IsFenced() {
 		e.Metrics.FencingOn.Set(1)
 		log.Info("metrics collection skipped due to fencing")
@@ -423,6 +569,33 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWALStat").Inc()
 		}
 	}
+
+	if err := collectPGStatStatements(e, db); err != nil {
+		log.Error(err, "while collecting pg_stat_statements")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGStatStatements").Inc()
+		e.Metrics.PgStatStatementsMetrics.reset()
+	}
+
+	if err := collectObjectSizes(e, db); err != nil {
+		log.Error(err, "while collecting the object sizes")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.ObjectSizes").Inc()
+	}
+
+	if err := collectDiskUsage(e); err != nil {
+		log.Error(err, "while collecting the disk usage")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.DiskUsage").Inc()
+		e.Metrics.DiskUsageMetrics.reset()
+	}
+
+	if err := collectCertificates(e); err != nil {
+		log.Error(err, "while collecting the certificates")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.Certificates").Inc()
+		e.Metrics.CertificatesMetrics.reset()
+	}
 }
 
 func (e *Exporter) setTimestampMetric(
@@ -489,6 +662,24 @@ func (e *Exporter) collectNodesUsed() {
 	e.Metrics.NodesUsed.Set(float64(cluster.Status.Topology.NodesUsed))
 }
 
+// collectPostmasterRestarts reports the unexpected exits of the postmaster,
+// which are known even when PostgreSQL is not running
+func (e *Exporter) collectPostmasterRestarts() {
+	result := e.instance.GetPostmasterRestartResult()
+	if result == nil {
+		e.Metrics.PostmasterExits.Set(0)
+		e.Metrics.PostmasterRestartsPaused.Set(0)
+		return
+	}
+
+	e.Metrics.PostmasterExits.Set(float64(result.Attempts))
+	if result.Paused {
+		e.Metrics.PostmasterRestartsPaused.Set(1)
+	} else {
+		e.Metrics.PostmasterRestartsPaused.Set(0)
+	}
+}
+
 func (e *Exporter) collectFromPrimaryLastFailedBackupTimestamp() {
 	const errorLabel = "Collect.LastFailedBackupTimestamp"
 	e.setTimestampMetric(e.Metrics.LastFailedBackupTimestamp, errorLabel, func(cluster *apiv1.Cluster) string {
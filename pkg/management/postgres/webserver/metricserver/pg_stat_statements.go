@@ -0,0 +1,212 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// pgStatStatementsLabels are the labels identifying a normalized query
+var pgStatStatementsLabels = []string{"queryid", "datname", "usename"}
+
+// pgStatStatementsOtherQueries is the value of the queryid label used
+// for the statistics of the queries not ranking in the top ones
+const pgStatStatementsOtherQueries = "other"
+
+// pgStatStatementsQuery gets the statistics of every normalized query, ranked
+// by total execution time. The rows differing only in the `toplevel` column,
+// available since PostgreSQL 14, are merged. The column containing the total
+// execution time changed name in PostgreSQL 13
+const pgStatStatementsQuery = `
+SELECT COALESCE(s.queryid::text, '') AS queryid, d.datname, r.rolname AS usename,
+	SUM(s.calls)::bigint AS calls, SUM(s.%[1]s)::float8 AS total_time, SUM(s.rows)::bigint AS rows
+FROM pg_stat_statements s
+JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+JOIN pg_catalog.pg_roles r ON r.oid = s.userid
+GROUP BY 1, 2, 3
+ORDER BY total_time DESC`
+
+// pgStatStatementsKey identifies a normalized query
+type pgStatStatementsKey struct {
+	queryID string
+	datname string
+	usename string
+}
+
+// pgStatStatementsRow is the statistics of a normalized query, or
+// of the aggregation of the queries not ranking in the top ones
+type pgStatStatementsRow struct {
+	pgStatStatementsKey
+	calls int64
+	// expressed in milliseconds
+	totalTime float64
+	rows      int64
+}
+
+func (m *PgStatStatementsMetrics) reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.statements = nil
+	m.tracked = nil
+}
+
+// update replaces the exported statistics with the passed ones, which
+// must be ranked by total execution time. The queries already exported
+// individually keep their series as long as pg_stat_statements tracks
+// them, and the free slots go to the most expensive remaining queries.
+// This keeps the series stable across collections, while the ranking
+// changes continuously. The remaining queries are aggregated under a
+// single series
+func (m *PgStatStatementsMetrics) update(statements []pgStatStatementsRow, topQueries int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	tracked := make(map[pgStatStatementsKey]struct{}, topQueries)
+	for _, statement := range statements {
+		if len(tracked) == topQueries {
+			break
+		}
+		if _, ok := m.tracked[statement.pgStatStatementsKey]; ok {
+			tracked[statement.pgStatStatementsKey] = struct{}{}
+		}
+	}
+	for _, statement := range statements {
+		if len(tracked) == topQueries {
+			break
+		}
+		tracked[statement.pgStatStatementsKey] = struct{}{}
+	}
+
+	result := make([]pgStatStatementsRow, 0, len(tracked)+1)
+	other := pgStatStatementsRow{pgStatStatementsKey: pgStatStatementsKey{queryID: pgStatStatementsOtherQueries}}
+	for _, statement := range statements {
+		if _, ok := tracked[statement.pgStatStatementsKey]; ok {
+			result = append(result, statement)
+			continue
+		}
+		other.calls += statement.calls
+		other.totalTime += statement.totalTime
+		other.rows += statement.rows
+	}
+
+	m.statements = append(result, other)
+	m.tracked = tracked
+}
+
+// collect sends the statistics gathered by the latest update. They are
+// reported as counters, as pg_stat_statements only increases them until
+// its statistics are reset
+func (m *PgStatStatementsMetrics) collect(ch chan<- prometheus.Metric) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, statement := range m.statements {
+		labels := []string{statement.queryID, statement.datname, statement.usename}
+		ch <- prometheus.MustNewConstMetric(m.Calls, prometheus.CounterValue, float64(statement.calls), labels...)
+		ch <- prometheus.MustNewConstMetric(m.ExecTime, prometheus.CounterValue, statement.totalTime/1000, labels...)
+		ch <- prometheus.MustNewConstMetric(m.Rows, prometheus.CounterValue, float64(statement.rows), labels...)
+	}
+}
+
+func collectPGStatStatements(e *Exporter, db *sql.DB) error {
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !cluster.Spec.Monitoring.IsQueryStatisticsEnabled() {
+		e.Metrics.PgStatStatementsMetrics.reset()
+		return nil
+	}
+
+	version, err := e.instance.GetPgVersion()
+	if err != nil {
+		return err
+	}
+
+	statements, err := getPgStatStatements(db, version.Major)
+	if err != nil {
+		return err
+	}
+
+	e.Metrics.PgStatStatementsMetrics.update(statements, cluster.Spec.Monitoring.GetQueryStatisticsTopQueries())
+	return nil
+}
+
+// getPgStatStatements gets the statistics of the queries ranked by total execution
+// time. An empty list is returned when the pg_stat_statements extension is not yet
+// available
+func getPgStatStatements(db *sql.DB, majorVersion uint64) ([]pgStatStatementsRow, error) {
+	var isAvailable bool
+	row := db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_extension WHERE extname = 'pg_stat_statements')")
+	if err := row.Scan(&isAvailable); err != nil {
+		return nil, err
+	}
+	if !isAvailable {
+		log.Debug("pg_stat_statements extension not available, skipping query statistics")
+		return nil, nil
+	}
+
+	totalTimeColumn := "total_exec_time"
+	if majorVersion < 13 {
+		totalTimeColumn = "total_time"
+	}
+
+	rows, err := db.Query(fmt.Sprintf(pgStatStatementsQuery, totalTimeColumn))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for pg_stat_statements")
+		}
+	}()
+
+	var result []pgStatStatementsRow
+	for rows.Next() {
+		var statement pgStatStatementsRow
+		if err := rows.Scan(
+			&statement.queryID,
+			&statement.datname,
+			&statement.usename,
+			&statement.calls,
+			&statement.totalTime,
+			&statement.rows,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, statement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
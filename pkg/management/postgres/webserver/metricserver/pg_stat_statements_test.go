@@ -0,0 +1,192 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_stat_statements metrics", func() {
+	const extensionQuery = "SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_extension " +
+		"WHERE extname = 'pg_stat_statements')"
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns no statistics when the extension is not available", func() {
+		mock.ExpectQuery(extensionQuery).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		rows, err := getPgStatStatements(db, 16)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rows).To(BeEmpty())
+	})
+
+	It("gets the queries ranked by total execution time", func() {
+		mock.ExpectQuery(extensionQuery).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(fmt.Sprintf(pgStatStatementsQuery, "total_exec_time")).
+			WillReturnRows(sqlmock.NewRows([]string{"queryid", "datname", "usename", "calls", "total_time", "rows"}).
+				AddRow("-123", "app", "app", 100, 2500.0, 1000).
+				AddRow("456", "app", "postgres", 10, 500.0, 10))
+
+		rows, err := getPgStatStatements(db, 16)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rows).To(HaveLen(2))
+		Expect(rows[0]).To(Equal(pgStatStatementsRow{
+			pgStatStatementsKey: pgStatStatementsKey{queryID: "-123", datname: "app", usename: "app"},
+			calls:               100,
+			totalTime:           2500,
+			rows:                1000,
+		}))
+	})
+
+	It("uses the total_time column before PostgreSQL 13", func() {
+		mock.ExpectQuery(extensionQuery).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(fmt.Sprintf(pgStatStatementsQuery, "total_time")).
+			WillReturnRows(sqlmock.NewRows([]string{"queryid", "datname", "usename", "calls", "total_time", "rows"}))
+
+		rows, err := getPgStatStatements(db, 12)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rows).To(BeEmpty())
+	})
+})
+
+var _ = Describe("pg_stat_statements top queries", func() {
+	statement := func(queryID string, calls int64, totalTime float64) pgStatStatementsRow {
+		return pgStatStatementsRow{
+			pgStatStatementsKey: pgStatStatementsKey{queryID: queryID, datname: "app", usename: "app"},
+			calls:               calls,
+			totalTime:           totalTime,
+			rows:                calls,
+		}
+	}
+
+	exportedQueries := func(metrics *PgStatStatementsMetrics) []string {
+		result := make([]string, 0, len(metrics.statements))
+		for _, statement := range metrics.statements {
+			result = append(result, statement.queryID)
+		}
+		return result
+	}
+
+	It("exports the top queries as counters and aggregates the other ones", func() {
+		metrics := &newMetrics().PgStatStatementsMetrics
+		metrics.update([]pgStatStatementsRow{
+			statement("1", 10, 1500),
+			statement("2", 5, 100),
+			statement("3", 1, 10),
+		}, 2)
+
+		Expect(exportedQueries(metrics)).To(Equal([]string{"1", "2", pgStatStatementsOtherQueries}))
+		Expect(metrics.statements[2].calls).To(BeEquivalentTo(1))
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(pgStatStatementsCollector{metrics: metrics})
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		calls := getMetric(families, "cnpg_collector_pg_stat_statements_calls_total")
+		Expect(calls).ToNot(BeNil())
+		Expect(calls.GetMetric()).To(HaveLen(3))
+		execTime := getMetric(families, "cnpg_collector_pg_stat_statements_exec_time_seconds_total")
+		Expect(execTime).ToNot(BeNil())
+		// The series are sorted by labels, and the one aggregating
+		// the other queries has an empty database name
+		Expect(execTime.GetMetric()[0].GetCounter().GetValue()).To(BeEquivalentTo(0.01))
+		Expect(execTime.GetMetric()[1].GetCounter().GetValue()).To(BeEquivalentTo(1.5))
+	})
+
+	It("keeps exporting the same queries while the ranking changes", func() {
+		metrics := &newMetrics().PgStatStatementsMetrics
+		metrics.update([]pgStatStatementsRow{
+			statement("1", 10, 1500),
+			statement("2", 5, 100),
+			statement("3", 1, 10),
+		}, 2)
+
+		metrics.update([]pgStatStatementsRow{
+			statement("3", 100, 5000),
+			statement("1", 11, 1600),
+			statement("2", 6, 110),
+		}, 2)
+		Expect(exportedQueries(metrics)).To(Equal([]string{"1", "2", pgStatStatementsOtherQueries}))
+		Expect(metrics.statements[2].calls).To(BeEquivalentTo(100))
+	})
+
+	It("gives the slot of a query not tracked anymore to the most expensive one", func() {
+		metrics := &newMetrics().PgStatStatementsMetrics
+		metrics.update([]pgStatStatementsRow{
+			statement("1", 10, 1500),
+			statement("2", 5, 100),
+			statement("3", 1, 10),
+		}, 2)
+
+		metrics.update([]pgStatStatementsRow{
+			statement("4", 1, 20),
+			statement("2", 6, 110),
+			statement("3", 2, 20),
+		}, 2)
+		Expect(exportedQueries(metrics)).To(ConsistOf("4", "2", pgStatStatementsOtherQueries))
+	})
+
+	It("drops every series when reset", func() {
+		metrics := &newMetrics().PgStatStatementsMetrics
+		metrics.update([]pgStatStatementsRow{statement("1", 10, 1500)}, 2)
+		metrics.reset()
+
+		ch := make(chan prometheus.Metric, 3)
+		metrics.collect(ch)
+		Expect(ch).To(BeEmpty())
+	})
+})
+
+// pgStatStatementsCollector exposes the pg_stat_statements metrics
+// to a registry
+type pgStatStatementsCollector struct {
+	metrics *PgStatStatementsMetrics
+}
+
+func (c pgStatStatementsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.metrics.Calls
+	ch <- c.metrics.ExecTime
+	ch <- c.metrics.Rows
+}
+
+func (c pgStatStatementsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.collect(ch)
+}
@@ -0,0 +1,277 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// openAPIVersion is the version of the OpenAPI specification
+// the document complies with
+const openAPIVersion = "3.0.3"
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	metaTimeType    = reflect.TypeOf(metav1.Time{})
+	jsonMarshalType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// newOpenAPIDocument builds the OpenAPI document describing the
+// versioned paths of the passed routes
+func newOpenAPIDocument(title string, routes []apiRoute) map[string]any {
+	registry := schemaRegistry{schemas: make(map[string]any)}
+
+	paths := make(map[string]any, len(routes))
+	for _, route := range routes {
+		routePath := url.Versioned(route.path)
+		var parameters []any
+		if route.parameter != "" {
+			routePath += "{" + route.parameter + "}"
+			parameters = []any{
+				map[string]any{
+					"name":     route.parameter,
+					"in":       "path",
+					"required": true,
+					"schema":   map[string]any{"type": "string"},
+				},
+			}
+		}
+
+		item := make(map[string]any, len(route.operations))
+		for _, operation := range route.operations {
+			item[strings.ToLower(operation.method)] = registry.operation(operation, parameters)
+		}
+		paths[routePath] = item
+	}
+
+	return map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   title,
+			"version": strings.TrimPrefix(url.APIVersionPrefix, "/"),
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": registry.schemas,
+		},
+	}
+}
+
+// schemaRegistry collects the JSON schemas of the named types
+// used by the REST API, to be referenced by the operations
+type schemaRegistry struct {
+	schemas map[string]any
+}
+
+// operation builds the OpenAPI description of an operation
+func (r *schemaRegistry) operation(operation apiOperation, parameters []any) map[string]any {
+	result := map[string]any{
+		"summary": operation.summary,
+		"responses": map[string]any{
+			"200": r.response(operation),
+		},
+	}
+	if parameters != nil {
+		result["parameters"] = parameters
+	}
+	if operation.request != nil {
+		result["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": r.schemaFor(reflect.TypeOf(operation.request)),
+				},
+			},
+		}
+	}
+
+	return result
+}
+
+// response builds the OpenAPI description of the successful
+// response of an operation
+func (r *schemaRegistry) response(operation apiOperation) map[string]any {
+	if operation.contentType != "" {
+		return map[string]any{
+			"description": "OK",
+			"content": map[string]any{
+				operation.contentType: map[string]any{
+					"schema": map[string]any{"type": "string", "format": "binary"},
+				},
+			},
+		}
+	}
+
+	if operation.response == nil && !operation.wrapped {
+		return map[string]any{"description": "OK"}
+	}
+
+	schema := r.payloadSchema(operation.response)
+	if operation.wrapped {
+		schema = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"data":  schema,
+				"error": r.schemaFor(reflect.TypeOf(Error{})),
+			},
+		}
+	}
+
+	return map[string]any{
+		"description": "OK",
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+// payloadSchema builds the schema of the payload of a response
+func (r *schemaRegistry) payloadSchema(payload any) map[string]any {
+	switch typedPayload := payload.(type) {
+	case nil:
+		return map[string]any{"type": "object"}
+	case oneOf:
+		alternatives := make([]any, len(typedPayload))
+		for i := range typedPayload {
+			alternatives[i] = r.schemaFor(reflect.TypeOf(typedPayload[i]))
+		}
+		return map[string]any{"oneOf": alternatives}
+	default:
+		return r.schemaFor(reflect.TypeOf(payload))
+	}
+}
+
+// schemaFor builds the JSON schema of a type, registering the named
+// structs it uses. The Kubernetes objects are described as opaque
+// objects, as their schema is part of the Kubernetes API
+func (r *schemaRegistry) schemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType, t == metaTimeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct && strings.HasPrefix(t.PkgPath(), "k8s.io/"):
+		return map[string]any{"type": "object", "description": "Kubernetes " + t.String()}
+	case t.Implements(jsonMarshalType) || reflect.PointerTo(t).Implements(jsonMarshalType):
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return r.structSchema(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema registers the schema of a named struct, returning a reference
+// to it. Anonymous structs are described inline
+func (r *schemaRegistry) structSchema(t reflect.Type) map[string]any {
+	if t.Name() == "" {
+		return r.objectSchema(t)
+	}
+
+	name := path.Base(t.PkgPath()) + "." + t.Name()
+	if _, ok := r.schemas[name]; !ok {
+		// Register the name before building the schema, to
+		// stop the recursion on self-referencing types
+		r.schemas[name] = nil
+		r.schemas[name] = r.objectSchema(t)
+	}
+
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// objectSchema builds the schema of the fields of a struct, following
+// the rules of encoding/json
+func (r *schemaRegistry) objectSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	r.addFields(t, properties, &required)
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+func (r *schemaRegistry) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				r.addFields(fieldType, properties, required)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = r.schemaFor(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
@@ -82,7 +83,7 @@ func (b *PluginBackupCommand) invokeStart(ctx context.Context) {
 
 	// record the backup beginning
 	backupLog.Info("Plugin backup started")
-	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
+	b.Recorder.Event(b.Backup, "Normal", events.BackupStarted, "Backup started")
 
 	response, err := cli.Backup(
 		ctx,
@@ -96,7 +97,7 @@ func (b *PluginBackupCommand) invokeStart(ctx context.Context) {
 	}
 
 	backupLog.Info("Backup completed")
-	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+	b.Recorder.Event(b.Backup, "Normal", events.BackupCompleted, "Backup completed")
 
 	// Set the status to completed
 	b.Backup.Status.SetAsCompleted()
@@ -137,7 +138,7 @@ func (b *PluginBackupCommand) markBackupAsFailed(ctx context.Context, failure er
 
 	// record the failure
 	b.Log.Error(failure, "Backup failed")
-	b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
+	b.Recorder.Event(b.Backup, "Warning", events.BackupFailed, "Backup failed")
 
 	// update backup status as failed
 	backupStatus.SetAsFailed(failure)
@@ -0,0 +1,227 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// probeType identifies the probe being evaluated
+type probeType string
+
+const (
+	probeTypeStartup   probeType = "startup"
+	probeTypeReadiness probeType = "readiness"
+)
+
+// getProbeConfiguration gets the configuration of the requested probe
+// from the cached cluster, returning nil if it is not available
+func getProbeConfiguration(probe probeType) *apiv1.ProbeWithStrategy {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil || cluster.Spec.Probes == nil {
+		return nil
+	}
+
+	switch probe {
+	case probeTypeStartup:
+		return cluster.Spec.Probes.Startup
+	case probeTypeReadiness:
+		return cluster.Spec.Probes.Readiness
+	default:
+		return nil
+	}
+}
+
+// getProbeStrategy returns the strategy configured for a probe, or
+// the passed default one if none is set
+func getProbeStrategy(
+	config *apiv1.ProbeWithStrategy,
+	defaultStrategy apiv1.ProbeStrategyType,
+) apiv1.ProbeStrategyType {
+	if config == nil || config.Type == "" {
+		return defaultStrategy
+	}
+	return config.Type
+}
+
+// recoveryConflictsTracker keeps track of the number of queries cancelled
+// because of recovery conflicts, to measure how many of them happened between
+// two consecutive probes
+type recoveryConflictsTracker struct {
+	mu       sync.Mutex
+	previous *int64
+}
+
+// update stores the current number of recovery conflicts and returns the
+// number of conflicts happened since the previous sample. The first sample
+// and a reset of the statistics count as zero
+func (tracker *recoveryConflictsTracker) update(current int64) int64 {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	previous := tracker.previous
+	tracker.previous = &current
+
+	if previous == nil || current < *previous {
+		return 0
+	}
+	return current - *previous
+}
+
+// evaluateProbe checks the instance according to the strategy of the passed
+// probe configuration
+func evaluateProbe(
+	instance *postgres.Instance,
+	config *apiv1.ProbeWithStrategy,
+	defaultStrategy apiv1.ProbeStrategyType,
+	tracker *recoveryConflictsTracker,
+) error {
+	switch strategy := getProbeStrategy(config, defaultStrategy); strategy {
+	case apiv1.ProbeStrategyPgIsReady:
+		return instance.IsServerHealthy()
+
+	case apiv1.ProbeStrategyQuery:
+		if err := instance.IsServerReady(); err != nil {
+			return err
+		}
+		return checkReplica(instance, config, tracker, false)
+
+	case apiv1.ProbeStrategyStreaming:
+		if err := instance.IsServerReady(); err != nil {
+			return err
+		}
+		return checkReplica(instance, config, tracker, true)
+
+	default:
+		return fmt.Errorf("unknown probe strategy: %s", strategy)
+	}
+}
+
+// checkReplica runs the checks reserved to replicas, using the connection
+// dedicated to the probes. When requireStreaming is set, the replica must be
+// streaming from its source within the configured lag
+func checkReplica(
+	instance *postgres.Instance,
+	config *apiv1.ProbeWithStrategy,
+	tracker *recoveryConflictsTracker,
+	requireStreaming bool,
+) error {
+	var maximumRecoveryConflicts *int64
+	if config != nil {
+		maximumRecoveryConflicts = config.MaximumRecoveryConflicts
+	}
+	if !requireStreaming && maximumRecoveryConflicts == nil {
+		return nil
+	}
+
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if isPrimary {
+		return nil
+	}
+
+	probeDB, err := instance.GetProbeDB()
+	if err != nil {
+		return err
+	}
+
+	if requireStreaming && !isDesignatedPrimary(instance) {
+		if err := checkStreamingReplica(probeDB, config.MaximumLag); err != nil {
+			return err
+		}
+	}
+
+	if maximumRecoveryConflicts == nil {
+		return nil
+	}
+	return checkRecoveryConflicts(probeDB, tracker, *maximumRecoveryConflicts)
+}
+
+// isDesignatedPrimary checks if this instance is the designated primary of
+// a replica cluster, which is allowed not to stream from its source
+func isDesignatedPrimary(instance *postgres.Instance) bool {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return false
+	}
+
+	return cluster.IsReplica() && cluster.Status.CurrentPrimary == instance.PodName
+}
+
+// checkStreamingReplica checks that a replica is streaming from its source and,
+// if a maximum lag is set, that the amount of WAL received but not yet replayed
+// doesn't exceed it
+func checkStreamingReplica(db *sql.DB, maximumLag *resource.Quantity) error {
+	row := db.QueryRow(
+		`SELECT
+			COALESCE((SELECT status = 'streaming' FROM pg_catalog.pg_stat_wal_receiver LIMIT 1), false),
+			COALESCE(pg_catalog.pg_wal_lsn_diff(
+				pg_catalog.pg_last_wal_receive_lsn(), pg_catalog.pg_last_wal_replay_lsn()), 0)::bigint`)
+
+	var isStreaming bool
+	var lag int64
+	if err := row.Scan(&isStreaming, &lag); err != nil {
+		return err
+	}
+
+	if !isStreaming {
+		return fmt.Errorf("instance is not streaming from its source")
+	}
+
+	if maximumLag != nil && lag > maximumLag.Value() {
+		log.Debug("Replication lag exceeds the configured limit",
+			"lag", lag, "maximumLag", maximumLag.Value())
+		return fmt.Errorf("replication lag (%d bytes) exceeds the maximum lag (%d bytes)",
+			lag, maximumLag.Value())
+	}
+
+	return nil
+}
+
+// checkRecoveryConflicts checks that the number of queries cancelled because
+// of recovery conflicts since the previous probe doesn't exceed the passed limit
+func checkRecoveryConflicts(db *sql.DB, tracker *recoveryConflictsTracker, maximumConflicts int64) error {
+	row := db.QueryRow(
+		`SELECT COALESCE(SUM(confl_tablespace + confl_lock + confl_snapshot +
+			confl_bufferpin + confl_deadlock), 0)::bigint
+		FROM pg_catalog.pg_stat_database_conflicts`)
+
+	var conflicts int64
+	if err := row.Scan(&conflicts); err != nil {
+		return err
+	}
+
+	if delta := tracker.update(conflicts); delta > maximumConflicts {
+		log.Debug("Recovery conflicts exceed the configured limit",
+			"conflicts", delta, "maximumRecoveryConflicts", maximumConflicts)
+		return fmt.Errorf("recovery conflicts (%d) exceed the maximum number of recovery conflicts (%d)",
+			delta, maximumConflicts)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("probe strategy", func() {
+	It("uses the default strategy when nothing is configured", func() {
+		Expect(getProbeStrategy(nil, apiv1.ProbeStrategyQuery)).To(Equal(apiv1.ProbeStrategyQuery))
+		Expect(getProbeStrategy(&apiv1.ProbeWithStrategy{}, apiv1.ProbeStrategyPgIsReady)).
+			To(Equal(apiv1.ProbeStrategyPgIsReady))
+	})
+
+	It("uses the configured strategy", func() {
+		config := &apiv1.ProbeWithStrategy{Type: apiv1.ProbeStrategyStreaming}
+		Expect(getProbeStrategy(config, apiv1.ProbeStrategyQuery)).To(Equal(apiv1.ProbeStrategyStreaming))
+	})
+})
+
+var _ = Describe("streaming replica check", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectReplicationStatus := func(isStreaming bool, lag int64) {
+		mock.ExpectQuery("pg_stat_wal_receiver").
+			WillReturnRows(sqlmock.NewRows([]string{"streaming", "lag"}).AddRow(isStreaming, lag))
+	}
+
+	It("fails when the replica is not streaming", func() {
+		expectReplicationStatus(false, 0)
+		Expect(checkStreamingReplica(db, nil)).To(HaveOccurred())
+	})
+
+	It("succeeds when the replica is streaming and no lag limit is set", func() {
+		expectReplicationStatus(true, 1024*1024*1024)
+		Expect(checkStreamingReplica(db, nil)).To(Succeed())
+	})
+
+	It("checks the replication lag against the configured limit", func() {
+		maximumLag := resource.MustParse("16Mi")
+
+		expectReplicationStatus(true, 1024)
+		Expect(checkStreamingReplica(db, &maximumLag)).To(Succeed())
+
+		expectReplicationStatus(true, 32*1024*1024)
+		Expect(checkStreamingReplica(db, &maximumLag)).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("recovery conflicts tracker", func() {
+	It("counts the first sample as zero", func() {
+		tracker := &recoveryConflictsTracker{}
+		Expect(tracker.update(42)).To(BeZero())
+	})
+
+	It("returns the conflicts happened since the previous sample", func() {
+		tracker := &recoveryConflictsTracker{}
+		tracker.update(10)
+		Expect(tracker.update(15)).To(BeEquivalentTo(5))
+		Expect(tracker.update(15)).To(BeZero())
+	})
+
+	It("counts a reset of the statistics as zero", func() {
+		tracker := &recoveryConflictsTracker{}
+		tracker.update(10)
+		Expect(tracker.update(2)).To(BeZero())
+		Expect(tracker.update(3)).To(BeEquivalentTo(1))
+	})
+})
+
+var _ = Describe("recovery conflicts check", func() {
+	var (
+		db      *sql.DB
+		mock    sqlmock.Sqlmock
+		tracker *recoveryConflictsTracker
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		tracker = &recoveryConflictsTracker{}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectRecoveryConflicts := func(conflicts int64) {
+		mock.ExpectQuery("pg_stat_database_conflicts").
+			WillReturnRows(sqlmock.NewRows([]string{"conflicts"}).AddRow(conflicts))
+	}
+
+	It("checks the recovery conflicts against the configured limit", func() {
+		expectRecoveryConflicts(100)
+		Expect(checkRecoveryConflicts(db, tracker, 5)).To(Succeed())
+
+		expectRecoveryConflicts(103)
+		Expect(checkRecoveryConflicts(db, tracker, 5)).To(Succeed())
+
+		expectRecoveryConflicts(110)
+		Expect(checkRecoveryConflicts(db, tracker, 5)).To(HaveOccurred())
+	})
+})
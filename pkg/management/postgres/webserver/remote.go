@@ -27,18 +27,24 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/upgrade"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 type remoteWebserverEndpoints struct {
 	typedClient   client.Client
 	instance      *postgres.Instance
 	currentBackup *backupConnection
+
+	// The recovery conflicts observed by the startup and readiness probes
+	startupConflicts   *recoveryConflictsTracker
+	readinessConflicts *recoveryConflictsTracker
 }
 
 // StartBackupRequest the required data to execute the pg_start_backup
@@ -71,18 +77,97 @@ func NewRemoteWebServer(
 	}
 
 	endpoints := remoteWebserverEndpoints{
-		typedClient: typedClient,
-		instance:    instance,
+		typedClient:        typedClient,
+		instance:           instance,
+		startupConflicts:   &recoveryConflictsTracker{},
+		readinessConflicts: &recoveryConflictsTracker{},
 	}
 	go endpoints.keepBackupAliveConn()
 
-	serveMux := http.NewServeMux()
-	serveMux.HandleFunc(url.PathPgModeBackup, endpoints.backup)
+	serveMux := newInstrumentedServeMux("remote")
+	// The probes are invoked by the kubelet and are not part of the
+	// versioned REST API
 	serveMux.HandleFunc(url.PathHealth, endpoints.isServerHealthy)
 	serveMux.HandleFunc(url.PathReady, endpoints.isServerReady)
-	serveMux.HandleFunc(url.PathPgStatus, endpoints.pgStatus)
-	serveMux.HandleFunc(url.PathPGControlData, endpoints.pgControlData)
-	serveMux.HandleFunc(url.PathUpdate, endpoints.updateInstanceManager(cancelFunc, exitedConditions))
+	serveMux.HandleFunc(url.PathStartup, endpoints.isServerStartedUp)
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgModeBackup,
+		handler: endpoints.backup,
+		operations: []apiOperation{
+			{
+				method:   http.MethodGet,
+				summary:  "Get the status of the running backup",
+				response: BackupResultData{},
+				wrapped:  true,
+			},
+			{
+				method:  http.MethodPost,
+				summary: "Start a backup with the PostgreSQL low-level API",
+				request: StartBackupRequest{},
+				wrapped: true,
+			},
+			{
+				method:  http.MethodPut,
+				summary: "Stop the running backup",
+				request: StopBackupRequest{},
+				wrapped: true,
+			},
+		},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgStatus,
+		handler: endpoints.pgStatus,
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the status of the instance",
+			response: pg.PostgresqlStatus{},
+		}},
+	})
+	serveMux.HandleAPI(topologyRoute(instance))
+	serveMux.HandleAPI(certificatesRoute(instance))
+	for _, route := range roleCheckRoutes(instance) {
+		serveMux.HandleAPI(route)
+	}
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPGControlData,
+		handler: endpoints.pgControlData,
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the output of pg_controldata",
+			response: pgControlDataResponse{},
+		}},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgConfigurationDiff,
+		handler: endpoints.pgConfigurationDiff,
+		operations: []apiOperation{{
+			method:   http.MethodPost,
+			summary:  "Evaluate the impact of a change of the PostgreSQL configuration",
+			request:  pg.ConfigurationDiffRequest{},
+			response: pg.ConfigurationDiff{},
+			wrapped:  true,
+		}},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathPgShutdownStatus,
+		handler: endpoints.pgShutdownStatus,
+		operations: []apiOperation{{
+			method:   http.MethodGet,
+			summary:  "Get the progress of the shutdown procedure of the instance",
+			response: pg.ShutdownStatus{},
+			wrapped:  true,
+		}},
+	})
+	serveMux.HandleAPI(apiRoute{
+		path:    url.PathUpdate,
+		handler: endpoints.updateInstanceManager(cancelFunc, exitedConditions),
+		operations: []apiOperation{{
+			method:      http.MethodPut,
+			summary:     "Replace the instance manager with the binary in the request body",
+			contentType: "text/plain",
+		}},
+	})
+	serveMux.HandleOpenAPI("CloudNativePG instance manager API")
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", url.StatusPort),
@@ -95,10 +180,19 @@ func NewRemoteWebServer(
 }
 
 func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, _ *http.Request) {
+	if err := ws.instance.CheckInjectedFault(pg.FaultTypeLivenessProbe); err != nil {
+		log.Debug("Liveness probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// If `pg_rewind` is running the Pod is starting up.
 	// We need to report it healthy to avoid being killed by the kubelet.
-	// Same goes for instances with fencing on.
-	if ws.instance.PgRewindIsRunning || ws.instance.MightBeUnavailable() {
+	// Same goes for instances with fencing on, and for the ones waiting
+	// to restart PostgreSQL after an unexpected exit, unless nothing
+	// would restart a primary whose restarts are paused.
+	if ws.instance.PgRewindIsRunning || ws.instance.MightBeUnavailable() ||
+		ws.instance.ShouldSkipProbesForPostmasterRestart() {
 		log.Trace("Liveness probe skipped")
 		_, _ = fmt.Fprint(w, "Skipped")
 		return
@@ -115,9 +209,47 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, _ *ht
 	_, _ = fmt.Fprint(w, "OK")
 }
 
+// This is the startup probe
+func (ws *remoteWebserverEndpoints) isServerStartedUp(w http.ResponseWriter, _ *http.Request) {
+	if err := ws.instance.CheckInjectedFault(pg.FaultTypeStartupProbe); err != nil {
+		log.Debug("Startup probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// If `pg_rewind` is running the Pod is starting up.
+	// We need to report it as started up to avoid being killed by the kubelet.
+	// Same goes for instances with fencing on, and for the ones waiting
+	// to restart PostgreSQL after an unexpected exit, unless nothing
+	// would restart a primary whose restarts are paused.
+	if ws.instance.PgRewindIsRunning || ws.instance.MightBeUnavailable() ||
+		ws.instance.ShouldSkipProbesForPostmasterRestart() {
+		log.Trace("Startup probe skipped")
+		_, _ = fmt.Fprint(w, "Skipped")
+		return
+	}
+
+	config := getProbeConfiguration(probeTypeStartup)
+	if err := evaluateProbe(ws.instance, config, apiv1.ProbeStrategyPgIsReady, ws.startupConflicts); err != nil {
+		log.Debug("Startup probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Trace("Startup probe succeeding")
+	_, _ = fmt.Fprint(w, "OK")
+}
+
 // This is the readiness probe
 func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, _ *http.Request) {
-	if err := ws.instance.IsServerReady(); err != nil {
+	if err := ws.instance.CheckInjectedFault(pg.FaultTypeReadinessProbe); err != nil {
+		log.Debug("Readiness probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	config := getProbeConfiguration(probeTypeReadiness)
+	if err := evaluateProbe(ws.instance, config, apiv1.ProbeStrategyQuery, ws.readinessConflicts); err != nil {
 		log.Debug("Readiness probe failing", "err", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -128,7 +260,9 @@ func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, _ *http
 }
 
 // This probe is for the instance status, including replication
-func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, _ *http.Request) {
+func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, r *http.Request) {
+	ws.instance.WaitInjectedFault(r.Context(), pg.FaultTypeSlowStatus)
+
 	// Extract the status of the current instance
 	status, err := ws.instance.GetStatus()
 	if err != nil {
@@ -154,11 +288,12 @@ func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, _ *http.Requ
 	_, _ = w.Write(js)
 }
 
-func (ws *remoteWebserverEndpoints) pgControlData(w http.ResponseWriter, _ *http.Request) {
-	type Response struct {
-		Data string `json:"data,omitempty"`
-	}
+// pgControlDataResponse is the response of the pg_controldata endpoint
+type pgControlDataResponse struct {
+	Data string `json:"data,omitempty"`
+}
 
+func (ws *remoteWebserverEndpoints) pgControlData(w http.ResponseWriter, _ *http.Request) {
 	out, err := ws.instance.GetPgControldata()
 	if err != nil {
 		log.Debug(
@@ -168,7 +303,7 @@ func (ws *remoteWebserverEndpoints) pgControlData(w http.ResponseWriter, _ *http
 		return
 	}
 
-	res, err := json.Marshal(Response{Data: out})
+	res, err := json.Marshal(pgControlDataResponse{Data: out})
 	if err != nil {
 		log.Warning(
 			"Internal error marshalling pg_controldata response",
@@ -181,6 +316,44 @@ func (ws *remoteWebserverEndpoints) pgControlData(w http.ResponseWriter, _ *http
 	_, _ = w.Write(res)
 }
 
+// pgConfigurationDiff computes which of the requested configuration
+// parameters would change, and whether they need a restart to be applied.
+// Nothing is changed in the instance configuration
+func (ws *remoteWebserverEndpoints) pgConfigurationDiff(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var p pg.ConfigurationDiffRequest
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		sendBadRequestJSONResponse(w, "FAILED_TO_PARSE_REQUEST", "Failed to parse request body")
+		return
+	}
+	defer func() {
+		if err := req.Body.Close(); err != nil {
+			log.Error(err, "while closing the body")
+		}
+	}()
+
+	diff, err := ws.instance.DiffConfiguration(p.Parameters)
+	if err != nil {
+		log.Debug(
+			"Instance configuration diff endpoint failing",
+			"err", err.Error())
+		sendUnprocessableEntityJSONResponse(w, "CANNOT_DIFF_CONFIGURATION", err.Error())
+		return
+	}
+
+	sendJSONResponseWithData(w, http.StatusOK, diff)
+}
+
+// pgShutdownStatus reports the phase of the shutdown procedure of the
+// instance, allowing the operator to follow it during a rollout
+func (ws *remoteWebserverEndpoints) pgShutdownStatus(w http.ResponseWriter, _ *http.Request) {
+	sendJSONResponseWithData(w, http.StatusOK, ws.instance.GetShutdownStatus())
+}
+
 // updateInstanceManager replace the instance with one in the
 // new binary
 func (ws *remoteWebserverEndpoints) updateInstanceManager(
@@ -0,0 +1,136 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// maxLagBytesParameter is the query parameter setting the maximum lag
+// accepted by the replica check
+const maxLagBytesParameter = "maxLagBytes"
+
+// roleCheckUnavailableMessage is the answer of the role checks when the
+// role of the instance can't be read. The checks don't require any
+// authentication, so the details of the error are only logged
+const roleCheckUnavailableMessage = "instance not available"
+
+// roleChecker reads the role of the instance
+type roleChecker func(ctx context.Context) (*postgres.RoleCheck, error)
+
+// roleCheckRoutes are the REST API routes answering whether the instance
+// is the primary or a replica, with plain 200 and 503 status codes that
+// external load balancers like HAProxy can use as health checks
+func roleCheckRoutes(instance *postgres.Instance) []apiRoute {
+	return []apiRoute{
+		{
+			path:    url.PathPgIsPrimary,
+			handler: serveIsPrimary(instance.CheckRole),
+			operations: []apiOperation{{
+				method:      http.MethodGet,
+				summary:     "Succeed with 200 when the instance is the primary, fail with 503 otherwise",
+				contentType: "text/plain",
+			}},
+		},
+		{
+			path:    url.PathPgIsReplica,
+			handler: serveIsReplica(instance.CheckRole),
+			operations: []apiOperation{{
+				method: http.MethodGet,
+				summary: "Succeed with 200 when the instance is a replica, fail with 503 otherwise. " +
+					"When the `maxLagBytes` query parameter is passed, the replica must also be streaming " +
+					"and have at most that amount of WAL to replay",
+				contentType: "text/plain",
+			}},
+		},
+	}
+}
+
+// serveIsPrimary returns the handler of the primary check
+func serveIsPrimary(checkRole roleChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+			return
+		}
+
+		role, err := checkRole(r.Context())
+		if err != nil {
+			log.Debug("Primary check failing", "err", err.Error())
+			http.Error(w, roleCheckUnavailableMessage, http.StatusServiceUnavailable)
+			return
+		}
+
+		if !role.IsPrimary {
+			http.Error(w, "replica", http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, "primary")
+	}
+}
+
+// serveIsReplica returns the handler of the replica check
+func serveIsReplica(checkRole roleChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+			return
+		}
+
+		maxLagBytes := int64(-1)
+		if value := r.URL.Query().Get(maxLagBytesParameter); value != "" {
+			var err error
+			maxLagBytes, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || maxLagBytes < 0 {
+				http.Error(w,
+					fmt.Sprintf("%s must be a non-negative number of bytes", maxLagBytesParameter),
+					http.StatusBadRequest)
+				return
+			}
+		}
+
+		role, err := checkRole(r.Context())
+		if err != nil {
+			log.Debug("Replica check failing", "err", err.Error())
+			http.Error(w, roleCheckUnavailableMessage, http.StatusServiceUnavailable)
+			return
+		}
+
+		switch {
+		case role.IsPrimary:
+			http.Error(w, "primary", http.StatusServiceUnavailable)
+		case maxLagBytes < 0:
+			_, _ = fmt.Fprint(w, "replica")
+		case !role.IsStreaming:
+			http.Error(w, "replica not streaming", http.StatusServiceUnavailable)
+		case role.LagBytes > maxLagBytes:
+			http.Error(w,
+				fmt.Sprintf("replica lagging by %d bytes", role.LagBytes),
+				http.StatusServiceUnavailable)
+		default:
+			_, _ = fmt.Fprintf(w, "replica lagging by %d bytes", role.LagBytes)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("role checks", func() {
+	checkerFor := func(role *postgres.RoleCheck, err error) roleChecker {
+		return func(context.Context) (*postgres.RoleCheck, error) {
+			return role, err
+		}
+	}
+
+	serve := func(handler http.HandlerFunc, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	primary := &postgres.RoleCheck{IsPrimary: true}
+	streamingReplica := &postgres.RoleCheck{IsStreaming: true, LagBytes: 2048}
+	disconnectedReplica := &postgres.RoleCheck{}
+
+	DescribeTable("primary check",
+		func(role *postgres.RoleCheck, err error, expectedCode int) {
+			rec := serve(serveIsPrimary(checkerFor(role, err)), "/pg/is-primary")
+			Expect(rec.Code).To(Equal(expectedCode))
+		},
+		Entry("on the primary", primary, nil, http.StatusOK),
+		Entry("on a replica", streamingReplica, nil, http.StatusServiceUnavailable),
+		Entry("when PostgreSQL is not reachable", nil, errors.New("connection refused"),
+			http.StatusServiceUnavailable),
+	)
+
+	It("doesn't disclose why the role can't be read", func() {
+		checker := checkerFor(nil, errors.New("password authentication failed for user \"postgres\""))
+		for _, handler := range []http.HandlerFunc{serveIsPrimary(checker), serveIsReplica(checker)} {
+			rec := serve(handler, "/pg/is-primary")
+			Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(rec.Body.String()).To(Equal(roleCheckUnavailableMessage + "\n"))
+		}
+	})
+
+	DescribeTable("replica check",
+		func(role *postgres.RoleCheck, target string, expectedCode int) {
+			rec := serve(serveIsReplica(checkerFor(role, nil)), target)
+			Expect(rec.Code).To(Equal(expectedCode))
+		},
+		Entry("on the primary", primary, "/pg/is-replica", http.StatusServiceUnavailable),
+		Entry("on a replica", disconnectedReplica, "/pg/is-replica", http.StatusOK),
+		Entry("on a replica within the lag", streamingReplica, "/pg/is-replica?maxLagBytes=4096",
+			http.StatusOK),
+		Entry("on a replica beyond the lag", streamingReplica, "/pg/is-replica?maxLagBytes=1024",
+			http.StatusServiceUnavailable),
+		Entry("on a replica not streaming with a lag", disconnectedReplica, "/pg/is-replica?maxLagBytes=1024",
+			http.StatusServiceUnavailable),
+		Entry("with an invalid lag", streamingReplica, "/pg/is-replica?maxLagBytes=-1",
+			http.StatusBadRequest),
+	)
+})
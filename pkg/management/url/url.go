@@ -37,6 +37,9 @@ const (
 	// PathReady is the URL oath for Ready State
 	PathReady string = "/readyz"
 
+	// PathStartup is the URL path for the Startup State
+	PathStartup string = "/startupz"
+
 	// PathPGControlData is the URL path for PostgreSQL pg_controldata output
 	PathPGControlData string = "/pg/controldata"
 
@@ -46,6 +49,47 @@ const (
 	// PathPgBackup is the URL path for PostgreSQL Backup
 	PathPgBackup string = "/pg/backup"
 
+	// PathPgBackupStatus is the URL path for the progress of a running backup,
+	// to be followed by the backup name
+	PathPgBackupStatus string = "/pg/backup/status/"
+
+	// PathPgBaseBackup is the URL path to stream a base backup of the data directory
+	PathPgBaseBackup string = "/pg/basebackup"
+
+	// PathPgWALArchiveStatus is the URL path for the status of the WAL archiving process
+	PathPgWALArchiveStatus string = "/pg/wal-archive/status"
+
+	// PathPgWALRestoreStatus is the URL path for the status of the WAL restore process
+	PathPgWALRestoreStatus string = "/pg/wal-restore/status"
+
+	// PathPgConfigurationDiff is the URL path to evaluate the impact of
+	// a change of the PostgreSQL configuration
+	PathPgConfigurationDiff string = "/pg/configuration/diff"
+
+	// PathPgSettings is the URL path for the PostgreSQL configuration
+	// parameters not set to their default value
+	PathPgSettings string = "/pg/settings"
+
+	// PathPgShutdownStatus is the URL path for the progress of the
+	// shutdown procedure of the instance
+	PathPgShutdownStatus string = "/pg/shutdown-status"
+
+	// PathPgTopology is the URL path for the position of the instance
+	// in the replication topology
+	PathPgTopology string = "/pg/topology"
+
+	// PathPgCertificates is the URL path for the expiration of the
+	// certificates used by the instance
+	PathPgCertificates string = "/pg/certificates"
+
+	// PathPgIsPrimary is the URL path of the check succeeding only
+	// on the primary instance
+	PathPgIsPrimary string = "/pg/is-primary"
+
+	// PathPgIsReplica is the URL path of the check succeeding only on
+	// the replicas, optionally within a maximum lag
+	PathPgIsReplica string = "/pg/is-replica"
+
 	// PathPgModeBackup is the URL path to interact with pg_start_backup and pg_stop_backup
 	PathPgModeBackup string = "/pg/mode/backup"
 
@@ -58,10 +102,39 @@ const (
 	// PathCache is the URL path for cached resources
 	PathCache string = "/cache/"
 
+	// PathCacheRefresh is the URL path used to refresh the cached resources
+	// without waiting for the next reconciliation loop
+	PathCacheRefresh string = "/cache/refresh"
+
+	// PathFaults is the URL path used to inject faults in the instance
+	// manager, only served when the fault injection is enabled
+	PathFaults string = "/faults"
+
 	// StatusPort is the port for status HTTP requests
 	StatusPort int = 8000
+
+	// DiagnosticsPort is the port of the webserver running the diagnostic
+	// queries, bound to the loopback interface and reached with a port-forward
+	DiagnosticsPort int = 8020
+
+	// PathPgQuery is the URL path to run a read-only diagnostic query
+	PathPgQuery string = "/pg/query"
+
+	// APIVersionPrefix is the URL path prefix of the current version
+	// of the instance manager REST API
+	APIVersionPrefix string = "/v1"
+
+	// PathOpenAPI is the URL path for the OpenAPI document describing
+	// the instance manager REST API
+	PathOpenAPI string = "/openapi.json"
 )
 
+// Versioned returns the path under the prefix of the current
+// version of the REST API
+func Versioned(path string) string {
+	return APIVersionPrefix + path
+}
+
 // Local builds an http request pointing to localhost
 func Local(path string, port int) string {
 	return Build("localhost", path, port)
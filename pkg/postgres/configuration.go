@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -40,6 +41,37 @@ const ParameterArchiveMode = "archive_mode"
 // ParameterWalLogHints the configuration key containing the wal_log_hints value
 const ParameterWalLogHints = "wal_log_hints"
 
+// ParameterSuperuserReservedConnections the configuration key containing
+// the superuser_reserved_connections value
+const ParameterSuperuserReservedConnections = "superuser_reserved_connections"
+
+// ParameterPgStatStatementsTrack the configuration key containing
+// the pg_stat_statements.track value
+const ParameterPgStatStatementsTrack = "pg_stat_statements.track"
+
+// ParameterPasswordEncryption the configuration key containing the
+// algorithm used to hash the passwords
+const ParameterPasswordEncryption = "password_encryption"
+
+// PasswordEncryptionSCRAM is the value of the password_encryption
+// parameter hashing the passwords with SCRAM-SHA-256
+const PasswordEncryptionSCRAM = "scram-sha-256"
+
+// ParameterKrbServerKeyfile the configuration key containing the location
+// of the keytab used by the GSSAPI authentication
+const ParameterKrbServerKeyfile = "krb_server_keyfile"
+
+// The configuration keys of the pgaudit parameters managed through
+// the `pgaudit` stanza of the cluster
+const (
+	ParameterPgAuditLog              = "pgaudit.log"
+	ParameterPgAuditLogCatalog       = "pgaudit.log_catalog"
+	ParameterPgAuditLogParameter     = "pgaudit.log_parameter"
+	ParameterPgAuditLogRelation      = "pgaudit.log_relation"
+	ParameterPgAuditLogStatementOnce = "pgaudit.log_statement_once"
+	ParameterPgAuditRole             = "pgaudit.role"
+)
+
 // An acceptable wal_level value
 const (
 	WalLevelValueLogical WalLevelValue = "logical"
@@ -83,7 +115,14 @@ local all all peer map=local
 hostssl postgres streaming_replica all cert
 hostssl replication streaming_replica all cert
 hostssl all cnpg_pooler_pgbouncer all cert
-
+{{ if .ClientCertificateRoles }}
+#
+# CLIENT CERTIFICATE RULES
+#
+{{ range $role := .ClientCertificateRoles }}
+hostssl all "{{ $role }}" all cert
+{{- end }}
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -99,10 +138,21 @@ hostssl all cnpg_pooler_pgbouncer all cert
 {{.LDAPConfiguration}}
 {{ end }}
 
+{{ if .GSSAPIConfiguration }}
+#
+# GSSAPI CONFIGURATION (optional)
+#
+{{.GSSAPIConfiguration}}
+{{ end }}
+
 #
 # DEFAULT RULES
 #
+{{ if .RequireChannelBinding -}}
+hostssl all all all scram-sha-256
+{{ else -}}
 host all all all {{.DefaultAuthenticationMethod}}
+{{ end -}}
 `
 
 	// identTemplateString is the template used to generate the pg_ident.conf
@@ -115,6 +165,9 @@ host all all all {{.DefaultAuthenticationMethod}}
 # Grant local access ('local' user map)
 local {{.Username}} postgres
 
+# Grant local access to the role running the diagnostic queries
+local {{.Username}} cnpg_diagnostics
+
 #
 # USER-DEFINED RULES
 #
@@ -212,6 +265,10 @@ local {{.Username}} postgres
 	// `.csv` and `.log` as needed.
 	LogFileName = "postgres"
 
+	// LogFilesPath is the path of the volume where the instance manager
+	// writes a copy of the PostgreSQL logs, when requested
+	LogFilesPath = "/var/log/postgresql"
+
 	// CNPGConfigSha256 is the parameter to be used to inject the sha256 of the
 	// config in the custom.conf file
 	CNPGConfigSha256 = "cnpg.config_sha256"
@@ -279,11 +336,21 @@ type ConfigurationInfo struct {
 	// The list of user-level settings
 	UserSettings map[string]string
 
+	// The list of user-level settings overriding the ones in UserSettings,
+	// to be set only when generating the configuration of a standby
+	ReplicaSettings map[string]string
+
 	// The list of replicas
 	SyncReplicasElectable []string
 
 	// The number of desired number of synchronous replicas
 	SyncReplicas int
+
+	// The method used to choose the synchronous replicas among the
+	// electable ones, either "ANY" (quorum-based, default) or "FIRST"
+	// (priority-based)
+	SyncReplicasMethod string
+
 	// List of additional sharedPreloadLibraries to be loaded
 	AdditionalSharedPreloadLibraries []string
 
@@ -309,6 +376,25 @@ type ConfigurationInfo struct {
 
 	// IsWalArchivingDisabled is true when user requested to disable WAL archiving
 	IsWalArchivingDisabled bool
+
+	// DataEncryptionKeyUnwrapCommand is the command used to unwrap the data
+	// encryption key when TDE is enabled, empty otherwise
+	DataEncryptionKeyUnwrapCommand string
+
+	// KerberosServerKeyFile is the location of the keytab used by the
+	// GSSAPI authentication, empty if it is not enabled
+	KerberosServerKeyFile string
+
+	// PasswordEncryption is the algorithm the passwords are hashed with,
+	// overriding the user settings, empty if it is not managed
+	PasswordEncryption string
+
+	// The workload the parameters are tuned for, empty if no preset is used
+	WorkloadType string
+
+	// The resources available to the instance, used to tune the parameters
+	// for the workload
+	WorkloadResources WorkloadResources
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -366,43 +452,58 @@ var (
 		},
 	}
 
+	// PrimaryDrivenConfigurationParameters contains the parameters whose
+	// value on a standby depends on the one of the primary, and that can't
+	// be set only for the replicas
+	PrimaryDrivenConfigurationParameters = []string{
+		"max_connections",
+		"max_locks_per_transaction",
+		"max_prepared_transactions",
+		"max_wal_senders",
+		"max_worker_processes",
+		"track_commit_timestamp",
+		ParameterWalLevel,
+		ParameterWalLogHints,
+	}
+
 	// FixedConfigurationParameters contains the parameters that can't be
 	// changed by the user
 	FixedConfigurationParameters = map[string]string{
 		// The following parameters need a restart to be applied
-		"allow_system_table_mods":   blockedConfigurationParameter,
-		"archive_mode":              fixedConfigurationParameter,
-		"bonjour":                   blockedConfigurationParameter,
-		"bonjour_name":              blockedConfigurationParameter,
-		"cluster_name":              fixedConfigurationParameter,
-		"config_file":               blockedConfigurationParameter,
-		"data_directory":            blockedConfigurationParameter,
-		"data_sync_retry":           blockedConfigurationParameter,
-		"event_source":              blockedConfigurationParameter,
-		"external_pid_file":         blockedConfigurationParameter,
-		"hba_file":                  blockedConfigurationParameter,
-		"hot_standby":               blockedConfigurationParameter,
-		"ident_file":                blockedConfigurationParameter,
-		"jit_provider":              blockedConfigurationParameter,
-		"listen_addresses":          blockedConfigurationParameter,
-		"logging_collector":         blockedConfigurationParameter,
-		"port":                      fixedConfigurationParameter,
-		"primary_conninfo":          fixedConfigurationParameter,
-		"primary_slot_name":         fixedConfigurationParameter,
-		"recovery_target":           fixedConfigurationParameter,
-		"recovery_target_action":    fixedConfigurationParameter,
-		"recovery_target_inclusive": fixedConfigurationParameter,
-		"recovery_target_lsn":       fixedConfigurationParameter,
-		"recovery_target_name":      fixedConfigurationParameter,
-		"recovery_target_time":      fixedConfigurationParameter,
-		"recovery_target_timeline":  fixedConfigurationParameter,
-		"recovery_target_xid":       fixedConfigurationParameter,
-		"restore_command":           fixedConfigurationParameter,
-		"shared_preload_libraries":  fixedConfigurationParameter,
-		"temp_tablespaces":          fixedConfigurationParameter,
-		"unix_socket_directories":   blockedConfigurationParameter,
-		"unix_socket_group":         blockedConfigurationParameter,
-		"unix_socket_permissions":   blockedConfigurationParameter,
+		"allow_system_table_mods":            blockedConfigurationParameter,
+		"archive_mode":                       fixedConfigurationParameter,
+		"bonjour":                            blockedConfigurationParameter,
+		"bonjour_name":                       blockedConfigurationParameter,
+		"cluster_name":                       fixedConfigurationParameter,
+		"config_file":                        blockedConfigurationParameter,
+		"data_directory":                     blockedConfigurationParameter,
+		"data_encryption_key_unwrap_command": fixedConfigurationParameter,
+		"data_sync_retry":                    blockedConfigurationParameter,
+		"event_source":                       blockedConfigurationParameter,
+		"external_pid_file":                  blockedConfigurationParameter,
+		"hba_file":                           blockedConfigurationParameter,
+		"hot_standby":                        blockedConfigurationParameter,
+		"ident_file":                         blockedConfigurationParameter,
+		"jit_provider":                       blockedConfigurationParameter,
+		"listen_addresses":                   blockedConfigurationParameter,
+		"logging_collector":                  blockedConfigurationParameter,
+		"port":                               fixedConfigurationParameter,
+		"primary_conninfo":                   fixedConfigurationParameter,
+		"primary_slot_name":                  fixedConfigurationParameter,
+		"recovery_target":                    fixedConfigurationParameter,
+		"recovery_target_action":             fixedConfigurationParameter,
+		"recovery_target_inclusive":          fixedConfigurationParameter,
+		"recovery_target_lsn":                fixedConfigurationParameter,
+		"recovery_target_name":               fixedConfigurationParameter,
+		"recovery_target_time":               fixedConfigurationParameter,
+		"recovery_target_timeline":           fixedConfigurationParameter,
+		"recovery_target_xid":                fixedConfigurationParameter,
+		"restore_command":                    fixedConfigurationParameter,
+		"shared_preload_libraries":           fixedConfigurationParameter,
+		"temp_tablespaces":                   fixedConfigurationParameter,
+		"unix_socket_directories":            blockedConfigurationParameter,
+		"unix_socket_group":                  blockedConfigurationParameter,
+		"unix_socket_permissions":            blockedConfigurationParameter,
 
 		// The following parameters need a reload to be applied
 		"archive_cleanup_command":                blockedConfigurationParameter,
@@ -499,20 +600,30 @@ var (
 )
 
 // CreateHBARules will create the content of pg_hba.conf file given
-// the rules set by the cluster spec
-func CreateHBARules(hba []string,
-	defaultAuthenticationMethod, ldapConfigString string,
+// the rules set by the cluster spec and the roles authenticating
+// with a client certificate. When channel binding is required, the
+// password authentication is only accepted through SCRAM-SHA-256
+// over TLS
+func CreateHBARules(hba []string, clientCertificateRoles []string,
+	defaultAuthenticationMethod, ldapConfigString, gssapiConfigString string,
+	requireChannelBinding bool,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
 	templateData := struct {
 		UserRules                   []string
+		ClientCertificateRoles      []string
 		LDAPConfiguration           string
+		GSSAPIConfiguration         string
 		DefaultAuthenticationMethod string
+		RequireChannelBinding       bool
 	}{
 		UserRules:                   hba,
+		ClientCertificateRoles:      clientCertificateRoles,
 		LDAPConfiguration:           ldapConfigString,
+		GSSAPIConfiguration:         gssapiConfigString,
 		DefaultAuthenticationMethod: defaultAuthenticationMethod,
+		RequireChannelBinding:       requireChannelBinding,
 	}
 
 	if err := hbaTemplate.Execute(&hbaContent, templateData); err != nil {
@@ -606,6 +717,9 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 	// Set all the default settings
 	setDefaultConfigurations(info, configuration)
 
+	// Tune the default settings for the workload
+	setWorkloadConfigurations(info, configuration)
+
 	// Apply all the values from the user, overriding defaults,
 	// ignoring those which are fixed if ignoreFixedSettingsFromUser is true
 	for key, value := range info.UserSettings {
@@ -616,6 +730,16 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig(key, value)
 	}
 
+	// Apply the values meant for the standby instances, with the same
+	// rules as the ones above
+	for key, value := range info.ReplicaSettings {
+		_, isFixed := FixedConfigurationParameters[key]
+		if isFixed && ignoreFixedSettingsFromUser {
+			continue
+		}
+		configuration.OverwriteConfig(key, value)
+	}
+
 	// Apply all mandatory settings, on top of defaults and user settings
 	if info.IncludingMandatory {
 		for key, value := range info.Settings.MandatorySettings {
@@ -651,6 +775,21 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig("temp_tablespaces", strings.Join(info.TemporaryTablespaces, ","))
 	}
 
+	// Apply the command to unwrap the data encryption key
+	if info.DataEncryptionKeyUnwrapCommand != "" {
+		configuration.OverwriteConfig("data_encryption_key_unwrap_command", info.DataEncryptionKeyUnwrapCommand)
+	}
+
+	// Apply the location of the keytab used by the GSSAPI authentication
+	if info.KerberosServerKeyFile != "" {
+		configuration.OverwriteConfig(ParameterKrbServerKeyfile, info.KerberosServerKeyFile)
+	}
+
+	// Apply the algorithm used to hash the passwords
+	if info.PasswordEncryption != "" {
+		configuration.OverwriteConfig(ParameterPasswordEncryption, info.PasswordEncryption)
+	}
+
 	return configuration
 }
 
@@ -674,6 +813,19 @@ func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfigura
 	}
 }
 
+// setWorkloadConfigurations sets the configurations tuned for the workload
+// type and the resources of the instance, if a workload type is requested
+func setWorkloadConfigurations(info ConfigurationInfo, configuration *PgConfiguration) {
+	if info.WorkloadType == "" {
+		return
+	}
+
+	maxConnections, _ := strconv.Atoi(info.UserSettings["max_connections"])
+	for key, value := range GetWorkloadSettings(info.WorkloadType, info.WorkloadResources, maxConnections) {
+		configuration.OverwriteConfig(key, value)
+	}
+}
+
 // setManagedSharedPreloadLibraries sets all additional preloaded libraries
 func setManagedSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConfiguration) {
 	for _, extension := range ManagedExtensions {
@@ -715,8 +867,13 @@ func setReplicasListConfigurations(info ConfigurationInfo, configuration *PgConf
 		for idx, name := range info.SyncReplicasElectable {
 			escapedReplicas[idx] = escapePostgresConfLiteral(name)
 		}
+		method := info.SyncReplicasMethod
+		if method == "" {
+			method = "ANY"
+		}
 		configuration.OverwriteConfig(SynchronousStandbyNames, fmt.Sprintf(
-			"ANY %v (%v)",
+			"%s %v (%v)",
+			method,
 			info.SyncReplicas,
 			strings.Join(escapedReplicas, ",")))
 	}
@@ -181,6 +181,25 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			Expect(config.GetConfig("synchronous_standby_names")).
 				To(Equal("ANY 2 (\"one\",\"two\",\"three\")"))
 		})
+
+		It("uses the priority-based method when requested", func() {
+			info := ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				MajorVersion:       130000,
+				UserSettings:       settings,
+				IncludingMandatory: true,
+				SyncReplicasElectable: []string{
+					"one",
+					"two",
+					"three",
+				},
+				SyncReplicas:       1,
+				SyncReplicasMethod: "FIRST",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("synchronous_standby_names")).
+				To(Equal("FIRST 1 (\"one\",\"two\",\"three\")"))
+		})
 	})
 
 	It("checks if PreserveFixedSettingsFromUser works properly", func() {
@@ -235,19 +254,43 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, nil, "md5", "", "", false)).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, nil, "this-one", "", "", false)).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, nil, "defaultAuthenticationMethod", "ldapConfigString", "", false)).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
+
+	It("really uses the gssapiConfigString", func() {
+		Expect(CreateHBARules(specRules, nil, "defaultAuthenticationMethod", "", "gssapiConfigString", false)).To(
+			ContainSubstring("\ngssapiConfigString\n"))
+	})
+
+	It("requires a client certificate for the passed roles before the user-defined rules", func() {
+		rules, err := CreateHBARules(specRules, []string{"app", "all"}, "md5", "", "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring("\nhostssl all \"app\" all cert\nhostssl all \"all\" all cert\n"))
+		Expect(strings.Index(rules, "hostssl all \"all\" all cert")).To(BeNumerically("<", strings.Index(rules, "\ntwo\n")))
+	})
+
+	It("only accepts SCRAM-SHA-256 over TLS when channel binding is required", func() {
+		rules, err := CreateHBARules(specRules, nil, "md5", "", "", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring("\nhostssl all all all scram-sha-256\n"))
+		Expect(rules).ToNot(ContainSubstring("host all all all"))
+	})
+
+	It("has no client certificate section when no role needs it", func() {
+		Expect(CreateHBARules(specRules, nil, "md5", "", "", false)).ToNot(
+			ContainSubstring("CLIENT CERTIFICATE RULES"))
+	})
 })
 
 var _ = Describe("pg_ident.conf generation", func() {
@@ -260,6 +303,11 @@ var _ = Describe("pg_ident.conf generation", func() {
 			ContainSubstring("\nlocal someone postgres\n"))
 	})
 
+	It("contains the map of the role running the diagnostic queries", func() {
+		Expect(CreateIdentRules(make([]string, 0), "someone")).To(
+			ContainSubstring("\nlocal someone cnpg_diagnostics\n"))
+	})
+
 	It("contains the default map and additional mappings when added", func() {
 		rules, _ := CreateIdentRules(specRules, "someone")
 		Expect(rules).To(ContainSubstring("\nlocal someone postgres\n"))
@@ -364,3 +412,53 @@ var _ = Describe("pg_failover_slots", func() {
 		Expect(libraries).To(ContainElements("pg_failover_slots"))
 	})
 })
+
+var _ = Describe("data encryption key unwrap command", func() {
+	info := ConfigurationInfo{
+		Settings:           CnpgConfigurationSettings,
+		MajorVersion:       150000,
+		IncludingMandatory: true,
+	}
+
+	It("is not set when TDE is not enabled", func() {
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("data_encryption_key_unwrap_command")).To(BeEmpty())
+	})
+
+	It("is set when TDE is enabled", func() {
+		tdeInfo := info
+		tdeInfo.DataEncryptionKeyUnwrapCommand = "cat %p"
+		config := CreatePostgresqlConfiguration(tdeInfo)
+		Expect(config.GetConfig("data_encryption_key_unwrap_command")).To(Equal("cat %p"))
+	})
+
+	It("can't be set by the user", func() {
+		userInfo := info
+		userInfo.UserSettings = map[string]string{"data_encryption_key_unwrap_command": "cat %p"}
+		config := CreatePostgresqlConfiguration(userInfo)
+		Expect(config.GetConfig("data_encryption_key_unwrap_command")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replica settings", func() {
+	info := ConfigurationInfo{
+		Settings:           CnpgConfigurationSettings,
+		MajorVersion:       150000,
+		IncludingMandatory: true,
+		UserSettings:       map[string]string{"hot_standby_feedback": "off"},
+	}
+
+	It("override the user settings", func() {
+		replicaInfo := info
+		replicaInfo.ReplicaSettings = map[string]string{"hot_standby_feedback": "on"}
+		config := CreatePostgresqlConfiguration(replicaInfo)
+		Expect(config.GetConfig("hot_standby_feedback")).To(Equal("on"))
+	})
+
+	It("can't override the fixed parameters", func() {
+		replicaInfo := info
+		replicaInfo.ReplicaSettings = map[string]string{"archive_mode": "off"}
+		config := CreatePostgresqlConfiguration(replicaInfo)
+		Expect(config.GetConfig("archive_mode")).To(Equal("on"))
+	})
+})
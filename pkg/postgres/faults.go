@@ -0,0 +1,129 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// FaultInjectionEnvironmentVariable is the environment variable that,
+// when set to true in the PostgreSQL container, lets the faults be
+// injected in the instance manager via the local webserver
+const FaultInjectionEnvironmentVariable = "CNPG_FAULT_INJECTION"
+
+// FaultType is the type of fault injected in the instance manager
+type FaultType string
+
+const (
+	// FaultTypeWALArchive makes the archive command fail
+	FaultTypeWALArchive FaultType = "walArchive"
+
+	// FaultTypeSlowStatus delays the replies of the instance status
+	// endpoint, like it happens when the queries run to build it are slow
+	FaultTypeSlowStatus FaultType = "slowStatus"
+
+	// FaultTypeStartupProbe makes the startup probe fail
+	FaultTypeStartupProbe FaultType = "startupProbe"
+
+	// FaultTypeReadinessProbe makes the readiness probe fail
+	FaultTypeReadinessProbe FaultType = "readinessProbe"
+
+	// FaultTypeLivenessProbe makes the liveness probe fail
+	FaultTypeLivenessProbe FaultType = "livenessProbe"
+)
+
+// Fault is a fault injected in the instance manager, to test how the
+// operator and the monitoring system react to it
+type Fault struct {
+	// The type of the fault
+	Type FaultType `json:"type"`
+
+	// The delay added by the faults slowing down the instance,
+	// as a Go duration, e.g. "30s"
+	Delay string `json:"delay,omitempty"`
+
+	// How long the fault stays active, as a Go duration. The fault
+	// stays active until it is removed when this is empty
+	Duration string `json:"duration,omitempty"`
+
+	// When the fault will be removed, set by the instance manager
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
+// IsFaultInjectionEnabled checks whether the faults can be injected
+// in the instance manager
+func IsFaultInjectionEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(FaultInjectionEnvironmentVariable))
+	return err == nil && enabled
+}
+
+// Validate checks that the fault is supported and that its
+// durations are well-formed
+func (fault Fault) Validate() error {
+	switch fault.Type {
+	case FaultTypeWALArchive, FaultTypeStartupProbe, FaultTypeReadinessProbe, FaultTypeLivenessProbe:
+		if fault.Delay != "" {
+			return fmt.Errorf("the %s fault doesn't support a delay", fault.Type)
+		}
+
+	case FaultTypeSlowStatus:
+		if fault.Delay == "" {
+			return fmt.Errorf("the %s fault requires a delay", fault.Type)
+		}
+
+	default:
+		return fmt.Errorf("unknown fault type: %q", fault.Type)
+	}
+
+	if _, err := fault.GetDelay(); err != nil {
+		return err
+	}
+	if _, err := fault.GetDuration(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDelay gets the delay added by the fault, zero if none is set
+func (fault Fault) GetDelay() (time.Duration, error) {
+	return parsePositiveDuration("delay", fault.Delay)
+}
+
+// GetDuration gets for how long the fault stays active,
+// zero if it stays active until it is removed
+func (fault Fault) GetDuration() (time.Duration, error) {
+	return parsePositiveDuration("duration", fault.Duration)
+}
+
+// parsePositiveDuration parses a Go duration which must be positive, if set
+func parsePositiveDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("the %s must be positive, found %q", name, value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("injected faults", func() {
+	DescribeTable("validation",
+		func(fault Fault, valid bool) {
+			if valid {
+				Expect(fault.Validate()).To(Succeed())
+			} else {
+				Expect(fault.Validate()).ToNot(Succeed())
+			}
+		},
+		Entry("archive failure", Fault{Type: FaultTypeWALArchive}, true),
+		Entry("probe failure lasting a while", Fault{Type: FaultTypeReadinessProbe, Duration: "5m"}, true),
+		Entry("slow status", Fault{Type: FaultTypeSlowStatus, Delay: "30s"}, true),
+		Entry("slow status without a delay", Fault{Type: FaultTypeSlowStatus}, false),
+		Entry("probe failure with a delay", Fault{Type: FaultTypeLivenessProbe, Delay: "30s"}, false),
+		Entry("negative duration", Fault{Type: FaultTypeStartupProbe, Duration: "-1m"}, false),
+		Entry("malformed delay", Fault{Type: FaultTypeSlowStatus, Delay: "soon"}, false),
+		Entry("unknown type", Fault{Type: "diskFull"}, false),
+	)
+
+	It("is enabled by the environment", func() {
+		GinkgoT().Setenv(FaultInjectionEnvironmentVariable, "true")
+		Expect(IsFaultInjectionEnabled()).To(BeTrue())
+		GinkgoT().Setenv(FaultInjectionEnvironmentVariable, "no")
+		Expect(IsFaultInjectionEnabled()).To(BeFalse())
+		GinkgoT().Setenv(FaultInjectionEnvironmentVariable, "")
+		Expect(IsFaultInjectionEnabled()).To(BeFalse())
+	})
+})
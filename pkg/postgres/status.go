@@ -19,6 +19,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -26,23 +27,294 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// WALArchiveStatus is the status of the WAL archiving process
+// of an instance
+type WALArchiveStatus struct {
+	IsPrimary bool `json:"isPrimary"`
+
+	// Archiver status, from pg_stat_archiver
+
+	LastArchivedWAL     string `json:"lastArchivedWAL,omitempty"`
+	LastArchivedWALTime string `json:"lastArchivedWALTime,omitempty"`
+	LastFailedWAL       string `json:"lastFailedWAL,omitempty"`
+	LastFailedWALTime   string `json:"lastFailedWALTime,omitempty"`
+	ArchivedCount       int64  `json:"archivedCount"`
+	FailedCount         int64  `json:"failedCount"`
+	IsArchivingWAL      bool   `json:"isArchivingWAL"`
+
+	// Is the number of '.ready' wal files contained in the wal archive folder
+	ReadyWALFiles int `json:"readyWalFiles"`
+
+	// Throughput of the archive command, computed on the batches reported
+	// by the wal-archive process in the last minutes
+
+	SegmentsPerMinute    float64 `json:"segmentsPerMinute"`
+	AverageUploadSeconds float64 `json:"averageUploadSeconds"`
+	Parallelism          int     `json:"parallelism,omitempty"`
+
+	// Archive lag, only available in the primary once the first
+	// WAL file has been archived
+
+	CurrentWAL         string `json:"currentWAL,omitempty"`
+	CurrentLsn         LSN    `json:"currentLsn,omitempty"`
+	ArchiveLagSegments *int64 `json:"archiveLagSegments,omitempty"`
+	ArchiveLagBytes    *int64 `json:"archiveLagBytes,omitempty"`
+}
+
+// WALArchiveBatch is the outcome of an execution of the archive command,
+// which can archive more than one WAL file in parallel
+type WALArchiveBatch struct {
+	// The number of WAL files that were archived
+	Archived int `json:"archived"`
+
+	// The number of WAL files that failed to be archived
+	Failed int `json:"failed"`
+
+	// The number of WAL files that were archived in parallel
+	Parallelism int `json:"parallelism"`
+
+	// The time spent uploading the WAL files, in seconds
+	UploadSeconds float64 `json:"uploadSeconds"`
+}
+
+// WALRestoreMode is the source a standby prefers to fetch the WAL files from
+type WALRestoreMode string
+
+const (
+	// WALRestoreModeArchive fetches the WAL files from the object store
+	WALRestoreModeArchive WALRestoreMode = "archive"
+
+	// WALRestoreModeStreaming skips the object store, letting PostgreSQL
+	// receive the WAL files via streaming replication
+	WALRestoreModeStreaming WALRestoreMode = "streaming"
+)
+
+// WALRestoreStatus is the status of the restore command, which fetches
+// the WAL files from the object store
+type WALRestoreStatus struct {
+	// The source preferred to fetch the WAL files
+	Mode WALRestoreMode `json:"mode"`
+
+	// The ratio of the failed fetches in the last minutes, deciding the mode
+	FailureRate float64 `json:"failureRate"`
+
+	// Counters since the start of the instance manager
+
+	RestoredCount int64 `json:"restoredCount"`
+	FailedCount   int64 `json:"failedCount"`
+	NotFoundCount int64 `json:"notFoundCount"`
+	SkippedCount  int64 `json:"skippedCount"`
+
+	LastError       string `json:"lastError,omitempty"`
+	LastFailureTime string `json:"lastFailureTime,omitempty"`
+}
+
+// WALRestoreResult is the outcome of an execution of the restore command,
+// which can fetch more than one WAL file in parallel
+type WALRestoreResult struct {
+	// The number of WAL files that were restored
+	Restored int `json:"restored"`
+
+	// The number of WAL files that failed to be restored
+	Failed int `json:"failed"`
+
+	// The number of WAL files that are not in the object store yet
+	NotFound int `json:"notFound"`
+
+	// True when the requested WAL file was left to streaming replication
+	Skipped bool `json:"skipped,omitempty"`
+
+	// True when the instance can receive the WAL files via streaming replication
+	StreamingAvailable bool `json:"streamingAvailable"`
+
+	// The error of the first failed fetch, if any
+	Error string `json:"error,omitempty"`
+}
+
+// ConfigurationDiffRequest is the request body of the configuration diff endpoint
+type ConfigurationDiffRequest struct {
+	// The configuration parameters to be evaluated, with their new value
+	Parameters map[string]string `json:"parameters"`
+}
+
+// ParameterChange describes how the change of a configuration parameter
+// would be applied to a running instance
+type ParameterChange struct {
+	// The name of the parameter
+	Name string `json:"name"`
+
+	// The value the instance is running with
+	CurrentValue string `json:"currentValue,omitempty"`
+
+	// The requested value
+	NewValue string `json:"newValue"`
+
+	// The context of the parameter, as reported by pg_settings. Empty
+	// if the parameter is not known by the instance
+	Context string `json:"context,omitempty"`
+}
+
+// ConfigurationDiff contains the parameters whose value would change,
+// classified by the way they are applied to a running instance
+type ConfigurationDiff struct {
+	// The parameters that need a restart of PostgreSQL
+	Restart []ParameterChange `json:"restart,omitempty"`
+
+	// The parameters that are applied by reloading the configuration
+	Reload []ParameterChange `json:"reload,omitempty"`
+}
+
+// RequiresRestart is true when at least one of the changed parameters
+// needs a restart of PostgreSQL to be applied
+func (diff ConfigurationDiff) RequiresRestart() bool {
+	return len(diff.Restart) > 0
+}
+
+// Setting is a configuration parameter whose value doesn't come from
+// the built-in defaults, as reported by pg_settings
+type Setting struct {
+	Name    string `json:"name"`
+	Setting string `json:"setting"`
+	Unit    string `json:"unit,omitempty"`
+
+	// Where the current value comes from, i.e. "configuration file"
+	Source string `json:"source"`
+
+	// The value assumed at server start when the parameter is not
+	// otherwise set
+	BootValue string `json:"bootValue,omitempty"`
+
+	// True when the parameter has been changed in the configuration
+	// files but needs a restart of PostgreSQL to be applied
+	PendingRestart bool `json:"pendingRestart,omitempty"`
+}
+
+// ConfigurationSnapshot is the parsed output of pg_controldata together
+// with the parameters not set to their default value
+type ConfigurationSnapshot struct {
+	ControlData map[string]string `json:"controlData,omitempty"`
+	Settings    []Setting         `json:"settings,omitempty"`
+}
+
+// ShutdownPhase is the phase of the shutdown procedure of an instance
+type ShutdownPhase string
+
+const (
+	// ShutdownPhaseRunning means that no shutdown has been requested
+	ShutdownPhaseRunning ShutdownPhase = "running"
+
+	// ShutdownPhaseSmart means that PostgreSQL is waiting for the
+	// clients to disconnect
+	ShutdownPhaseSmart ShutdownPhase = "smart"
+
+	// ShutdownPhaseFast means that PostgreSQL is terminating the
+	// client connections and shutting down cleanly
+	ShutdownPhaseFast ShutdownPhase = "fast"
+
+	// ShutdownPhaseImmediate means that PostgreSQL is aborting all its
+	// processes, requiring crash recovery at the next start
+	ShutdownPhaseImmediate ShutdownPhase = "immediate"
+
+	// ShutdownPhaseCompleted means that PostgreSQL has been shut down
+	ShutdownPhaseCompleted ShutdownPhase = "completed"
+)
+
+// ShutdownStatus is the progress of the shutdown procedure of an instance,
+// which escalates from a smart shutdown to a fast and an immediate one
+type ShutdownStatus struct {
+	// The current phase
+	Phase ShutdownPhase `json:"phase"`
+
+	// When the current phase started
+	PhaseStartedAt string `json:"phaseStartedAt,omitempty"`
+
+	// The time in seconds after which the current phase is escalated
+	// to the next one, zero if no escalation will happen
+	PhaseTimeout int32 `json:"phaseTimeout,omitempty"`
+}
+
+// StartupCheckResult is the result of the consistency checks of the data
+// directory executed before starting PostgreSQL after an unclean shutdown
+type StartupCheckResult struct {
+	// When the checks have been executed
+	Time string `json:"time"`
+
+	// Whether the data directory passed the checks
+	Passed bool `json:"passed"`
+
+	// The number of relation files whose data checksums have been verified
+	VerifiedFiles int64 `json:"verifiedFiles,omitempty"`
+
+	// The description of the failure, or of the skipped checks
+	Message string `json:"message,omitempty"`
+
+	// Whether the instance has been quarantined, and PostgreSQL not started
+	Quarantined bool `json:"quarantined,omitempty"`
+}
+
+// PostmasterRestartResult reports the unexpected exits of the postmaster
+// and the restarts executed by the instance manager
+type PostmasterRestartResult struct {
+	// The number of consecutive unexpected exits of the postmaster
+	Attempts int32 `json:"attempts"`
+
+	// Why the postmaster exited the last time
+	LastExitReason string `json:"lastExitReason"`
+
+	// When the postmaster exited the last time
+	LastExitTime string `json:"lastExitTime"`
+
+	// The description of the last exit of the postmaster
+	Message string `json:"message,omitempty"`
+
+	// When PostgreSQL will be restarted
+	NextRestartTime string `json:"nextRestartTime,omitempty"`
+
+	// Whether the restarts of PostgreSQL are paused
+	Paused bool `json:"paused,omitempty"`
+}
+
+// LifecycleHookResult is the result of the last execution of a
+// lifecycle hook on an instance
+type LifecycleHookResult struct {
+	// The name of the hook
+	Name string `json:"name"`
+
+	// The point of the lifecycle where the hook has been executed
+	Point string `json:"point"`
+
+	// When the hook has been started
+	StartedAt string `json:"startedAt"`
+
+	// The time in seconds taken by the hook
+	Duration int32 `json:"duration,omitempty"`
+
+	// Whether the hook completed successfully
+	Succeeded bool `json:"succeeded"`
+
+	// The error raised by the hook, if any
+	Message string `json:"message,omitempty"`
+}
+
 // PostgresqlStatus defines a status for every instance in the cluster
 type PostgresqlStatus struct {
-	CurrentLsn                LSN         `json:"currentLsn,omitempty"`
-	ReceivedLsn               LSN         `json:"receivedLsn,omitempty"`
-	ReplayLsn                 LSN         `json:"replayLsn,omitempty"`
-	SystemID                  string      `json:"systemID"`
-	IsPrimary                 bool        `json:"isPrimary"`
-	ReplayPaused              bool        `json:"replayPaused"`
-	PendingRestart            bool        `json:"pendingRestart"`
-	PendingRestartForDecrease bool        `json:"pendingRestartForDecrease"`
-	IsWalReceiverActive       bool        `json:"isWalReceiverActive"`
-	IsPgRewindRunning         bool        `json:"isPgRewindRunning"`
-	MightBeUnavailable        bool        `json:"mightBeUnavailable"`
-	IsArchivingWAL            bool        `json:"isArchivingWAL,omitempty"`
-	Node                      string      `json:"node"`
-	Pod                       *corev1.Pod `json:"pod"`
-	TotalInstanceSize         string      `json:"totalInstanceSize"`
+	CurrentLsn                LSN    `json:"currentLsn,omitempty"`
+	ReceivedLsn               LSN    `json:"receivedLsn,omitempty"`
+	ReplayLsn                 LSN    `json:"replayLsn,omitempty"`
+	SystemID                  string `json:"systemID"`
+	IsPrimary                 bool   `json:"isPrimary"`
+	ReplayPaused              bool   `json:"replayPaused"`
+	PendingRestart            bool   `json:"pendingRestart"`
+	PendingRestartForDecrease bool   `json:"pendingRestartForDecrease"`
+	IsWalReceiverActive       bool   `json:"isWalReceiverActive"`
+	IsPgRewindRunning         bool   `json:"isPgRewindRunning"`
+	MightBeUnavailable        bool   `json:"mightBeUnavailable"`
+	IsArchivingWAL            bool   `json:"isArchivingWAL,omitempty"`
+	Node                      string `json:"node"`
+	// populated by the operator when the node is being drained or is not healthy
+	NodeUnavailabilityReason string      `json:"nodeUnavailabilityReason,omitempty"`
+	Pod                      *corev1.Pod `json:"pod"`
+	TotalInstanceSize        string      `json:"totalInstanceSize"`
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
 
@@ -53,6 +325,14 @@ type PostgresqlStatus struct {
 	LastFailedWAL       string `json:"lastFailedWAL,omitempty"`
 	LastFailedWALTime   string `json:"lastFailedWALTime,omitempty"`
 
+	// The number of data page checksum failures detected in the
+	// databases of the instance, from pg_stat_database
+	ChecksumFailures int64 `json:"checksumFailures,omitempty"`
+
+	// The roles whose password is still stored as an MD5 hash,
+	// reported by the primary only
+	MD5PasswordRoles []string `json:"md5PasswordRoles,omitempty"`
+
 	// WAL Status
 
 	CurrentWAL string `json:"currentWAL,omitempty"`
@@ -64,6 +344,23 @@ type PostgresqlStatus struct {
 	// SELECT timeline_id FROM pg_control_checkpoint()
 	TimeLineID int `json:"timeLineID,omitempty"`
 
+	// The status of the restore command, reported by the
+	// instances fetching WAL files from the object store
+	WALRestoreStatus *WALRestoreStatus `json:"walRestoreStatus,omitempty"`
+
+	// The results of the last execution of the lifecycle hooks
+	LifecycleHooks []LifecycleHookResult `json:"lifecycleHooks,omitempty"`
+
+	// The result of the last consistency checks of the data directory
+	StartupCheck *StartupCheckResult `json:"startupCheck,omitempty"`
+
+	// The unexpected exits of the postmaster and the restarts that followed
+	PostmasterRestart *PostmasterRestartResult `json:"postmasterRestart,omitempty"`
+
+	// The resource version of the TDE secret whose passphrase is wrapping
+	// the data encryption key of the instance
+	TDESecretVersion string `json:"tdeSecretVersion,omitempty"`
+
 	// This field is set when there is an error while extracting the
 	// status of a Pod
 	Error error `json:"-"`
@@ -92,6 +389,48 @@ type PostgresqlStatus struct {
 	IsPodReady bool `json:"isPodReady"`
 }
 
+// InstanceTopology is the position of an instance in the replication
+// topology, as read in a single request to the instance manager
+type InstanceTopology struct {
+	Pod        string `json:"pod"`
+	IsPrimary  bool   `json:"isPrimary"`
+	TimeLineID int    `json:"timeLineID,omitempty"`
+
+	// The current WAL write location, only reported by the primary
+	CurrentLsn LSN `json:"currentLsn,omitempty"`
+
+	// The WAL locations received and replayed, only reported by replicas
+	ReceivedLsn LSN `json:"receivedLsn,omitempty"`
+	ReplayLsn   LSN `json:"replayLsn,omitempty"`
+
+	// The host the replica is streaming from, and the status of
+	// its WAL receiver
+	Upstream          string `json:"upstream,omitempty"`
+	WalReceiverStatus string `json:"walReceiverStatus,omitempty"`
+
+	// The replicas streaming from the primary, including their
+	// synchronous state
+	Replicas PgStatReplicationList `json:"replicas,omitempty"`
+}
+
+// CertificateExpiration is the validity of one of the certificates
+// used by an instance
+type CertificateExpiration struct {
+	// The role of the certificate, like "server" or "client-ca"
+	Name string `json:"name"`
+
+	// The common name of the subject of the certificate
+	CommonName string `json:"commonName"`
+
+	// The validity period of the certificate
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+
+	// The days left before the certificate expires, negative when
+	// it is already expired
+	DaysToExpiry float64 `json:"daysToExpiry"`
+}
+
 // PgStatReplication contains the replications of replicas as reported by the primary instance
 type PgStatReplication struct {
 	ApplicationName string `json:"applicationName,omitempty"`
@@ -297,6 +636,14 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return !list.Items[i].ReplayLsn.Less(list.Items[j].ReplayLsn)
 	}
 
+	// Between equally updated secondaries, prefer the ones
+	// running on available nodes
+	isNodeAvailableI := list.Items[i].NodeUnavailabilityReason == ""
+	isNodeAvailableJ := list.Items[j].NodeUnavailabilityReason == ""
+	if isNodeAvailableI != isNodeAvailableJ {
+		return isNodeAvailableI
+	}
+
 	return list.Items[i].Pod.Name < list.Items[j].Pod.Name
 }
 
@@ -405,3 +752,31 @@ func (list PostgresqlStatusList) InstancesReportingStatus() int {
 
 	return n
 }
+
+// DiagnosticQueryRequest is a read-only diagnostic query to be executed
+// by an instance
+type DiagnosticQueryRequest struct {
+	// The database where the query is executed, `postgres` if empty
+	Database string `json:"database,omitempty"`
+
+	// The query, made of a single SQL statement
+	Query string `json:"query"`
+
+	// The Kubernetes user running the query, as claimed by the client.
+	// It is not verified and is only logged together with the query
+	Caller string `json:"caller,omitempty"`
+}
+
+// DiagnosticQueryResult is the result of a diagnostic query
+type DiagnosticQueryResult struct {
+	// The names of the columns
+	Columns []string `json:"columns"`
+
+	// The rows, with the values in their text representation.
+	// NULL values are reported as nil
+	Rows [][]*string `json:"rows"`
+
+	// True when the query returned more rows than the maximum
+	// allowed, and the other ones have been discarded
+	Truncated bool `json:"truncated,omitempty"`
+}
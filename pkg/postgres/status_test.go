@@ -204,6 +204,37 @@ var _ = Describe("PostgreSQL status", func() {
 		Expect(podList.Items[1].Pod.Name).To(Equal("p-2"))
 		Expect(podList.Items[2].Pod.Name).To(Equal("p-3"))
 	})
+
+	It("prefers the equally updated servers running on available nodes", func() {
+		podList := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod:                      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p-1"}},
+					ReceivedLsn:              "1/A0",
+					ReplayLsn:                "1/A0",
+					NodeUnavailabilityReason: "Unschedulable",
+				},
+				{
+					Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p-2"}},
+					ReceivedLsn: "1/A0",
+					ReplayLsn:   "1/A0",
+				},
+				{
+					Pod:                      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p-3"}},
+					ReceivedLsn:              "1/B0",
+					ReplayLsn:                "1/B0",
+					NodeUnavailabilityReason: "DiskPressure",
+				},
+			},
+		}
+		sort.Sort(&podList)
+
+		// p-3 is the first entry of the list because it is more advanced,
+		// and it is never skipped to avoid losing data
+		Expect(podList.Items[0].Pod.Name).To(Equal("p-3"))
+		Expect(podList.Items[1].Pod.Name).To(Equal("p-2"))
+		Expect(podList.Items[2].Pod.Name).To(Equal("p-1"))
+	})
 })
 
 var _ = Describe("PostgreSQL status real", func() {
@@ -90,3 +90,14 @@ func GetPostgresMajorVersion(parsedVersion int) int {
 func IsUpgradePossible(fromVersion, toVersion int) bool {
 	return GetPostgresMajorVersion(fromVersion) == GetPostgresMajorVersion(toVersion)
 }
+
+// GetPostgresMajorVersionNumber gets the number identifying the major
+// version of PostgreSQL, as used in the image tags, from a parsed version.
+// Only the versions using the two-part numbering scheme are supported.
+// Example:
+//
+//	GetPostgresMajorVersionNumber(100002) == 10
+//	GetPostgresMajorVersionNumber(160003) == 16
+func GetPostgresMajorVersionNumber(parsedVersion int) int {
+	return parsedVersion / 10000
+}
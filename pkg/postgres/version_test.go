@@ -65,6 +65,11 @@ var _ = Describe("PostgreSQL version handling", func() {
 			Expect(GetPostgresMajorVersion(90504)).To(Equal(90500))
 			Expect(GetPostgresMajorVersion(90400)).To(Equal(90400))
 		})
+
+		It("should extract the major version number as used in the image tags", func() {
+			Expect(GetPostgresMajorVersionNumber(100003)).To(Equal(10))
+			Expect(GetPostgresMajorVersionNumber(160000)).To(Equal(16))
+		})
 	})
 
 	Describe("detect whenever a version upgrade is possible using the numeric version", func() {
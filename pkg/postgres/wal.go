@@ -149,6 +149,45 @@ func (segment Segment) Name() string {
 	return fmt.Sprintf("%08X%08X%08X", segment.Tli, segment.Log, segment.Seg)
 }
 
+// SegmentFromLSN returns the segment, on the passed timeline,
+// containing the passed LSN
+func SegmentFromLSN(tli int32, lsn LSN, walSegmentSize int64) (Segment, error) {
+	position, err := lsn.Parse()
+	if err != nil {
+		return Segment{}, err
+	}
+
+	return Segment{
+		Tli: tli,
+		Log: int32(position >> 32),
+		Seg: int32((position & 0xFFFFFFFF) / walSegmentSize),
+	}, nil
+}
+
+// DetectWALSegmentSize finds the WAL segment size of an instance, given
+// the name of the WAL segment containing the passed LSN, i.e. the begin WAL
+// and LSN of one of its backups
+func DetectWALSegmentSize(walName string, lsn LSN) (int64, error) {
+	segment, err := SegmentFromName(walName)
+	if err != nil {
+		return 0, err
+	}
+
+	// PostgreSQL supports WAL segment sizes that are a power
+	// of two between 1MB and 1GB
+	for walSegmentSize := int64(1 << 20); walSegmentSize <= 1<<30; walSegmentSize <<= 1 {
+		candidate, err := SegmentFromLSN(segment.Tli, lsn, walSegmentSize)
+		if err != nil {
+			return 0, err
+		}
+		if candidate == segment {
+			return walSegmentSize, nil
+		}
+	}
+
+	return 0, fmt.Errorf("LSN %s is not contained in WAL file %s", lsn, walName)
+}
+
 // WalSegmentsPerFile is the number of WAL Segments in a WAL File
 func WalSegmentsPerFile(walSegmentSize int64) int32 {
 	// Given that segment section is represented by 8 hex characters,
@@ -190,3 +229,31 @@ func (segment Segment) NextSegments(size int, postgresVersion *int, segmentSize
 
 	return result
 }
+
+// ArchiveLag computes how far the WAL archive is behind the current WAL
+// write location, given the name of the last archived WAL file. It returns
+// the number of completed WAL segments that still need to be archived and
+// the amount of WAL, in bytes, generated after the last archived segment
+func ArchiveLag(lastArchivedWAL string, currentLSN LSN, walSegmentSize int64) (segments, bytes int64, err error) {
+	if !IsWALFile(lastArchivedWAL) {
+		return 0, 0, ErrorBadWALSegmentName
+	}
+
+	segment, err := SegmentFromName(lastArchivedWAL)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	current, err := currentLSN.Parse()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	archivedUpTo := int64(segment.Log)<<32 + (int64(segment.Seg)+1)*walSegmentSize
+	if current <= archivedUpTo {
+		return 0, 0, nil
+	}
+
+	bytes = current - archivedUpTo
+	return bytes / walSegmentSize, bytes, nil
+}
@@ -244,3 +244,58 @@ var _ = Describe("WAL files checking", func() {
 		}
 	})
 })
+
+var _ = Describe("WAL archive lag", func() {
+	It("computes the lag from the last archived WAL and the current location", func() {
+		segments, bytes, err := ArchiveLag("000000010000000000000003", "0/6000100", DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segments).To(BeEquivalentTo(2))
+		Expect(bytes).To(BeEquivalentTo(2*DefaultWALSegmentSize + 0x100))
+	})
+
+	It("takes into account the log number of the segment", func() {
+		segments, bytes, err := ArchiveLag("0000000100000001000000FF", "2/1000000", DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segments).To(BeEquivalentTo(1))
+		Expect(bytes).To(BeEquivalentTo(DefaultWALSegmentSize))
+	})
+
+	It("reports no lag when nothing has been written after the last archived segment", func() {
+		segments, bytes, err := ArchiveLag("000000010000000000000003", "0/4000000", DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segments).To(BeZero())
+		Expect(bytes).To(BeZero())
+	})
+
+	It("fails when the last archived file is not a WAL segment", func() {
+		_, _, err := ArchiveLag("00000002.history", "0/6000100", DefaultWALSegmentSize)
+		Expect(err).To(MatchError(ErrorBadWALSegmentName))
+	})
+})
+
+var _ = Describe("WAL segments of an LSN", func() {
+	It("computes the segment containing an LSN", func() {
+		segment, err := SegmentFromLSN(2, "1/3000100", DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segment.Name()).To(Equal("000000020000000100000003"))
+
+		segment, err = SegmentFromLSN(1, "0/3000100", 1<<26)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segment.Name()).To(Equal("000000010000000000000000"))
+	})
+
+	It("detects the WAL segment size", func() {
+		walSegmentSize, err := DetectWALSegmentSize("000000010000000000000003", "0/3000028")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walSegmentSize).To(Equal(DefaultWALSegmentSize))
+
+		walSegmentSize, err = DetectWALSegmentSize("000000010000000000000001", "0/4000028")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walSegmentSize).To(BeEquivalentTo(1 << 26))
+	})
+
+	It("fails when the LSN is not contained in the WAL file", func() {
+		_, err := DetectWALSegmentSize("000000010000000000000003", "1/3000028")
+		Expect(err).To(HaveOccurred())
+	})
+})
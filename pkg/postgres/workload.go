@@ -0,0 +1,192 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	// WorkloadTypeOLTP tunes the instances for many short transactions
+	WorkloadTypeOLTP = "oltp"
+
+	// WorkloadTypeAnalytics tunes the instances for few long-running queries
+	// scanning large amounts of data
+	WorkloadTypeAnalytics = "analytics"
+
+	// WorkloadTypeMixed tunes the instances for a mix of short transactions
+	// and reporting queries
+	WorkloadTypeMixed = "mixed"
+
+	// defaultMaxConnections is the default value of max_connections
+	defaultMaxConnections = 100
+
+	megabyte = 1024 * 1024
+	gigabyte = 1024 * megabyte
+)
+
+// WorkloadResources are the resources available to an instance, which
+// are used to compute the parameters of a workload preset
+type WorkloadResources struct {
+	// The memory available to the instance in bytes, zero if unknown
+	Memory int64
+
+	// The number of CPUs available to the instance, zero if unknown
+	CPU int64
+
+	// The size in bytes of the volume containing the WAL files, zero if unknown
+	WALVolumeSize int64
+}
+
+// workloadPreset describes how the parameters are tuned for a workload
+type workloadPreset struct {
+	// The fraction of the memory used as shared_buffers
+	sharedBuffersRatio float64
+
+	// The fraction of the memory that can be used by the
+	// sort and hash operations of all the connections
+	workMemRatio float64
+
+	// The fraction of the memory used by the maintenance operations
+	maintenanceWorkMemRatio float64
+
+	// The number of parallel workers per gather, for each CPU
+	parallelWorkersPerCPU float64
+
+	// The max_wal_size to be used, if the WAL volume is large enough
+	maxWALSize int64
+
+	// The settings that don't depend on the resources
+	settings SettingsCollection
+}
+
+// workloadPresets are the presets of the supported workloads
+var workloadPresets = map[string]workloadPreset{
+	WorkloadTypeOLTP: {
+		sharedBuffersRatio:      0.25,
+		workMemRatio:            0.25,
+		maintenanceWorkMemRatio: 0.05,
+		parallelWorkersPerCPU:   0.25,
+		maxWALSize:              2 * gigabyte,
+		settings: SettingsCollection{
+			"checkpoint_timeout":              "15min",
+			"checkpoint_completion_target":    "0.9",
+			"bgwriter_lru_maxpages":           "400",
+			"bgwriter_lru_multiplier":         "4.0",
+			"autovacuum_naptime":              "30s",
+			"autovacuum_vacuum_scale_factor":  "0.05",
+			"autovacuum_analyze_scale_factor": "0.02",
+			"autovacuum_vacuum_cost_limit":    "1000",
+			"max_standby_streaming_delay":     "30s",
+			"max_standby_archive_delay":       "30s",
+		},
+	},
+	WorkloadTypeMixed: {
+		sharedBuffersRatio:      0.25,
+		workMemRatio:            0.35,
+		maintenanceWorkMemRatio: 0.075,
+		parallelWorkersPerCPU:   0.5,
+		maxWALSize:              4 * gigabyte,
+		settings: SettingsCollection{
+			"checkpoint_timeout":              "15min",
+			"checkpoint_completion_target":    "0.9",
+			"bgwriter_lru_maxpages":           "200",
+			"bgwriter_lru_multiplier":         "3.0",
+			"autovacuum_vacuum_scale_factor":  "0.1",
+			"autovacuum_analyze_scale_factor": "0.05",
+			"autovacuum_vacuum_cost_limit":    "800",
+			"max_standby_streaming_delay":     "2min",
+			"max_standby_archive_delay":       "2min",
+		},
+	},
+	WorkloadTypeAnalytics: {
+		sharedBuffersRatio:      0.2,
+		workMemRatio:            0.5,
+		maintenanceWorkMemRatio: 0.1,
+		parallelWorkersPerCPU:   1,
+		maxWALSize:              8 * gigabyte,
+		settings: SettingsCollection{
+			"checkpoint_timeout":              "30min",
+			"checkpoint_completion_target":    "0.9",
+			"autovacuum_vacuum_scale_factor":  "0.1",
+			"autovacuum_analyze_scale_factor": "0.05",
+			"default_statistics_target":       "500",
+			"max_standby_streaming_delay":     "10min",
+			"max_standby_archive_delay":       "10min",
+		},
+	},
+}
+
+// GetWorkloadSettings returns the parameters tuned for the passed workload
+// type and resources. The parameters depending on a resource are skipped
+// when its amount is not known. The number of connections is needed to
+// divide the memory available for the sort and hash operations
+func GetWorkloadSettings(
+	workloadType string,
+	resources WorkloadResources,
+	maxConnections int,
+) SettingsCollection {
+	preset, ok := workloadPresets[workloadType]
+	if !ok {
+		return nil
+	}
+
+	result := make(SettingsCollection, len(preset.settings)+8)
+	for key, value := range preset.settings {
+		result[key] = value
+	}
+
+	if resources.Memory > 0 {
+		memory := float64(resources.Memory)
+		if maxConnections <= 0 {
+			maxConnections = defaultMaxConnections
+		}
+
+		result["shared_buffers"] = formatMegabytes(memory * preset.sharedBuffersRatio)
+		result["effective_cache_size"] = formatMegabytes(memory * 0.75)
+		result["work_mem"] = formatMegabytes(
+			math.Max(memory*preset.workMemRatio/float64(maxConnections), 4*megabyte))
+		result["maintenance_work_mem"] = formatMegabytes(
+			math.Min(math.Max(memory*preset.maintenanceWorkMemRatio, 64*megabyte), 2*gigabyte))
+	}
+
+	if resources.CPU > 0 {
+		cpu := float64(resources.CPU)
+		result["max_parallel_workers_per_gather"] = strconv.Itoa(
+			int(math.Min(math.Max(math.Floor(cpu*preset.parallelWorkersPerCPU), 1), 8)))
+		result["max_parallel_maintenance_workers"] = strconv.Itoa(
+			int(math.Min(math.Max(math.Floor(cpu/2), 1), 4)))
+		result["autovacuum_max_workers"] = strconv.Itoa(
+			int(math.Min(math.Max(math.Floor(cpu/2), 3), 10)))
+	}
+
+	// The WAL files can exceed max_wal_size, so we keep a large margin
+	// from the size of the volume where they are stored
+	if resources.WALVolumeSize > 0 && preset.maxWALSize <= resources.WALVolumeSize/4 {
+		result["max_wal_size"] = formatMegabytes(float64(preset.maxWALSize))
+	}
+
+	return result
+}
+
+// formatMegabytes formats an amount of bytes as a PostgreSQL
+// memory parameter, rounding it down to the megabyte
+func formatMegabytes(value float64) string {
+	return fmt.Sprintf("%dMB", int64(value/megabyte))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("workload presets", func() {
+	resources := WorkloadResources{
+		Memory:        8 * gigabyte,
+		CPU:           4,
+		WALVolumeSize: 20 * gigabyte,
+	}
+
+	It("tunes the parameters of an OLTP workload", func() {
+		settings := GetWorkloadSettings(WorkloadTypeOLTP, resources, 0)
+		Expect(settings).To(HaveKeyWithValue("shared_buffers", "2048MB"))
+		Expect(settings).To(HaveKeyWithValue("effective_cache_size", "6144MB"))
+		Expect(settings).To(HaveKeyWithValue("work_mem", "20MB"))
+		Expect(settings).To(HaveKeyWithValue("maintenance_work_mem", "409MB"))
+		Expect(settings).To(HaveKeyWithValue("max_parallel_workers_per_gather", "1"))
+		Expect(settings).To(HaveKeyWithValue("max_parallel_maintenance_workers", "2"))
+		Expect(settings).To(HaveKeyWithValue("autovacuum_max_workers", "3"))
+		Expect(settings).To(HaveKeyWithValue("max_wal_size", "2048MB"))
+		Expect(settings).To(HaveKeyWithValue("bgwriter_lru_maxpages", "400"))
+		Expect(settings).To(HaveKeyWithValue("max_standby_streaming_delay", "30s"))
+	})
+
+	It("tunes the parameters of an analytics workload", func() {
+		settings := GetWorkloadSettings(WorkloadTypeAnalytics, resources, 20)
+		Expect(settings).To(HaveKeyWithValue("shared_buffers", "1638MB"))
+		Expect(settings).To(HaveKeyWithValue("work_mem", "204MB"))
+		Expect(settings).To(HaveKeyWithValue("maintenance_work_mem", "819MB"))
+		Expect(settings).To(HaveKeyWithValue("max_parallel_workers_per_gather", "4"))
+		Expect(settings).To(HaveKeyWithValue("default_statistics_target", "500"))
+		Expect(settings).To(HaveKeyWithValue("max_standby_streaming_delay", "10min"))
+		Expect(settings).ToNot(HaveKey("max_wal_size"))
+	})
+
+	It("keeps the computed values within sensible bounds", func() {
+		settings := GetWorkloadSettings(WorkloadTypeMixed, WorkloadResources{
+			Memory: 512 * megabyte,
+			CPU:    64,
+		}, 500)
+		Expect(settings).To(HaveKeyWithValue("work_mem", "4MB"))
+		Expect(settings).To(HaveKeyWithValue("maintenance_work_mem", "64MB"))
+		Expect(settings).To(HaveKeyWithValue("max_parallel_workers_per_gather", "8"))
+		Expect(settings).To(HaveKeyWithValue("max_parallel_maintenance_workers", "4"))
+		Expect(settings).To(HaveKeyWithValue("autovacuum_max_workers", "10"))
+	})
+
+	It("skips the parameters depending on unknown resources", func() {
+		settings := GetWorkloadSettings(WorkloadTypeMixed, WorkloadResources{}, 0)
+		Expect(settings).To(HaveKeyWithValue("checkpoint_timeout", "15min"))
+		Expect(settings).ToNot(HaveKey("shared_buffers"))
+		Expect(settings).ToNot(HaveKey("max_parallel_workers_per_gather"))
+		Expect(settings).ToNot(HaveKey("max_wal_size"))
+	})
+
+	It("doesn't tune anything for an unknown workload", func() {
+		Expect(GetWorkloadSettings("unknown", resources, 0)).To(BeEmpty())
+	})
+
+	It("is overridden by the user settings", func() {
+		config := CreatePostgresqlConfiguration(ConfigurationInfo{
+			Settings:          CnpgConfigurationSettings,
+			MajorVersion:      160000,
+			UserSettings:      map[string]string{"shared_buffers": "1GB", "max_connections": "200"},
+			WorkloadType:      WorkloadTypeOLTP,
+			WorkloadResources: resources,
+		})
+		Expect(config.GetConfig("shared_buffers")).To(Equal("1GB"))
+		Expect(config.GetConfig("work_mem")).To(Equal("10MB"))
+		Expect(config.GetConfig("checkpoint_timeout")).To(Equal("15min"))
+	})
+})
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -133,7 +134,7 @@ func (o *offlineExecutor) ensurePodIsFenced(
 	// The list of fenced instances is empty, so we need to request
 	// fencing for the target pod
 	contextLogger.Info("Fencing Pod", "podName", targetPodName)
-	o.recorder.Eventf(backup, "Normal", "FencePod",
+	o.recorder.Eventf(backup, "Normal", events.FencePod,
 		"Fencing Pod %v", targetPodName)
 
 	return nil
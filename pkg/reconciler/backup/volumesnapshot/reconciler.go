@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
@@ -354,7 +355,7 @@ func EnsurePodIsUnfenced(
 	// The list of fenced instances is empty, so we need to request
 	// fencing for the target pod
 	contextLogger.Info("Unfencing Pod", "podName", targetPod.Name)
-	recorder.Eventf(backup, "Normal", "UnfencePod",
+	recorder.Eventf(backup, "Normal", events.UnfencePod,
 		"Unfencing Pod %v", targetPod.Name)
 
 	return nil
@@ -370,7 +371,7 @@ func (se *Reconciler) createSnapshotPVCGroupStep(
 	targetPod *corev1.Pod,
 ) error {
 	for i := range pvcs {
-		se.recorder.Eventf(backup, "Normal", "CreateSnapshot",
+		se.recorder.Eventf(backup, "Normal", events.CreateSnapshot,
 			"Creating VolumeSnapshot for PVC %v", pvcs[i].Name)
 
 		err := se.createSnapshot(ctx, cluster, backup, targetPod, &pvcs[i])
@@ -0,0 +1,293 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failoverarbiter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// requestTimeout is the maximum time a request to the Kubernetes
+// cluster hosting the lease can take
+const requestTimeout = 5 * time.Second
+
+// LeaseArbiter is a failover arbiter based on a Lease. Only the holder
+// of the lease is allowed to run a primary
+type LeaseArbiter struct {
+	cli      client.Client
+	key      client.ObjectKey
+	identity string
+	duration time.Duration
+
+	// createdAt is the time this arbiter has been created, and
+	// lastAcquired the last time it acquired or renewed the lease
+	createdAt    time.Time
+	lastAcquired time.Time
+}
+
+// NewLeaseArbiter creates a failover arbiter using the passed client
+// to access the lease
+func NewLeaseArbiter(cli client.Client, cluster *apiv1.Cluster) *LeaseArbiter {
+	lease := cluster.Spec.FailoverArbiter.Lease
+	return &LeaseArbiter{
+		cli: cli,
+		key: client.ObjectKey{
+			Namespace: lease.Namespace,
+			Name:      lease.GetName(cluster.Name),
+		},
+		identity:  lease.Identity,
+		duration:  lease.GetLeaseDuration(),
+		createdAt: time.Now(),
+	}
+}
+
+// Registry keeps the failover arbiter of every cluster, so that the client
+// connecting to the Kubernetes cluster hosting the lease is created again
+// only when the configuration or the kubeconfig change, and the last time
+// the lease has been renewed is known across the reconciliation loops
+type Registry struct {
+	mu       sync.Mutex
+	arbiters map[types.NamespacedName]*registryEntry
+}
+
+type registryEntry struct {
+	arbiter *LeaseArbiter
+
+	// version identifies the configuration and the kubeconfig
+	// used to create the arbiter
+	version string
+}
+
+// NewRegistry creates an empty failover arbiter Registry
+func NewRegistry() *Registry {
+	return &Registry{arbiters: make(map[types.NamespacedName]*registryEntry)}
+}
+
+// Get gets the failover arbiter of the passed cluster, connecting to the
+// Kubernetes cluster hosting the lease with the kubeconfig stored in the
+// configured secret. It returns nil if no arbiter is configured. A nil
+// Registry creates a new arbiter every time
+func (r *Registry) Get(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) (*LeaseArbiter, error) {
+	if cluster.Spec.FailoverArbiter == nil || cluster.Spec.FailoverArbiter.Lease == nil {
+		r.Forget(client.ObjectKeyFromObject(cluster))
+		return nil, nil
+	}
+
+	lease := cluster.Spec.FailoverArbiter.Lease
+	var secret corev1.Secret
+	if err := cli.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: lease.KubeconfigSecret.Name},
+		&secret,
+	); err != nil {
+		return nil, fmt.Errorf("while getting the failover arbiter kubeconfig secret: %w", err)
+	}
+
+	version := fmt.Sprintf("%s/%s/%s/%s/%s/%s/%v", secret.UID, secret.ResourceVersion, lease.KubeconfigSecret.Key,
+		lease.Namespace, lease.GetName(cluster.Name), lease.Identity, lease.GetLeaseDuration())
+
+	if r == nil {
+		return newFromSecret(cluster, &secret)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := client.ObjectKeyFromObject(cluster)
+	if entry, ok := r.arbiters[key]; ok && entry.version == version {
+		return entry.arbiter, nil
+	}
+
+	arbiter, err := newFromSecret(cluster, &secret)
+	if err != nil {
+		return nil, err
+	}
+	r.arbiters[key] = &registryEntry{arbiter: arbiter, version: version}
+	return arbiter, nil
+}
+
+// Forget removes the failover arbiter of the passed cluster
+func (r *Registry) Forget(key types.NamespacedName) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.arbiters, key)
+}
+
+// newFromSecret creates the failover arbiter of the passed cluster using
+// the kubeconfig contained in the passed secret
+func newFromSecret(cluster *apiv1.Cluster, secret *corev1.Secret) (*LeaseArbiter, error) {
+	lease := cluster.Spec.FailoverArbiter.Lease
+	kubeconfig, ok := secret.Data[lease.KubeconfigSecret.Key]
+	if !ok {
+		return nil, fmt.Errorf("missing key %s in the failover arbiter kubeconfig secret %s",
+			lease.KubeconfigSecret.Key, lease.KubeconfigSecret.Name)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the failover arbiter kubeconfig: %w", err)
+	}
+
+	// The lease is consulted inside the reconciliation loop, which
+	// must not be blocked for long by an unreachable arbiter
+	restConfig.Timeout = min(requestTimeout, lease.GetLeaseDuration()/3)
+
+	remoteClient, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("while creating the failover arbiter client: %w", err)
+	}
+
+	return NewLeaseArbiter(remoteClient, cluster), nil
+}
+
+// GetLeaseDuration gets the duration of the lease
+func (arbiter *LeaseArbiter) GetLeaseDuration() time.Duration {
+	return arbiter.duration
+}
+
+// GetRenewalDelay gets the time to wait before the lease held by this
+// arbiter needs to be renewed, which is zero when it is already due
+func (arbiter *LeaseArbiter) GetRenewalDelay(now time.Time) time.Duration {
+	if arbiter.lastAcquired.IsZero() {
+		return 0
+	}
+	return max(arbiter.lastAcquired.Add(arbiter.duration/3).Sub(now), 0)
+}
+
+// IsExpired checks if the lease hasn't been renewed by this arbiter
+// within its duration, and can then be acquired by another cluster.
+// When the lease has never been renewed by this arbiter, the duration
+// is counted from its creation
+func (arbiter *LeaseArbiter) IsExpired(now time.Time) bool {
+	reference := arbiter.lastAcquired
+	if reference.IsZero() {
+		reference = arbiter.createdAt
+	}
+	return now.Sub(reference) >= arbiter.duration
+}
+
+// TryAcquire tries to acquire or renew the lease, returning true if this
+// cluster holds it and is then allowed to run a primary. Together with
+// the outcome, the current holder of the lease is returned
+func (arbiter *LeaseArbiter) TryAcquire(ctx context.Context) (bool, string, error) {
+	now := time.Now()
+	acquired, holder, err := arbiter.tryAcquire(ctx, now)
+	if acquired {
+		arbiter.lastAcquired = now
+	} else if err == nil {
+		arbiter.lastAcquired = time.Time{}
+	}
+	return acquired, holder, err
+}
+
+func (arbiter *LeaseArbiter) tryAcquire(ctx context.Context, now time.Time) (bool, string, error) {
+	contextLogger := log.FromContext(ctx).WithValues("lease", arbiter.key, "identity", arbiter.identity)
+
+	var lease coordinationv1.Lease
+	err := arbiter.cli.Get(ctx, arbiter.key, &lease)
+	if apierrs.IsNotFound(err) {
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: arbiter.key.Namespace,
+				Name:      arbiter.key.Name,
+			},
+		}
+		arbiter.setHolder(&lease, now)
+		if err := arbiter.cli.Create(ctx, &lease); err != nil {
+			if apierrs.IsAlreadyExists(err) {
+				// Someone else created the lease in the meantime
+				return false, "", nil
+			}
+			return false, "", err
+		}
+		contextLogger.Info("Failover arbiter lease created")
+		return true, arbiter.identity, nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	switch {
+	case holder == arbiter.identity:
+		// We already hold the lease, let's renew it
+
+	case holder == "" || isExpired(&lease, now):
+		contextLogger.Info("Acquiring the failover arbiter lease", "previousHolder", holder)
+
+	default:
+		return false, holder, nil
+	}
+
+	origLease := lease.DeepCopy()
+	arbiter.setHolder(&lease, now)
+	if err := arbiter.cli.Patch(
+		ctx,
+		&lease,
+		client.MergeFromWithOptions(origLease, client.MergeFromWithOptimisticLock{}),
+	); err != nil {
+		if apierrs.IsConflict(err) {
+			// Someone else updated the lease in the meantime
+			return false, holder, nil
+		}
+		return false, holder, err
+	}
+
+	return true, arbiter.identity, nil
+}
+
+// setHolder sets this cluster as the holder of the lease
+func (arbiter *LeaseArbiter) setHolder(lease *coordinationv1.Lease, now time.Time) {
+	renewTime := metav1.NewMicroTime(now)
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != arbiter.identity {
+		lease.Spec.HolderIdentity = ptr.To(arbiter.identity)
+		lease.Spec.AcquireTime = &renewTime
+		if lease.Spec.LeaseTransitions != nil || lease.Spec.RenewTime != nil {
+			lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+		}
+	}
+	lease.Spec.RenewTime = &renewTime
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(arbiter.duration.Seconds()))
+}
+
+// isExpired checks if the lease has not been renewed within its duration
+func isExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil {
+		return true
+	}
+
+	duration := time.Duration(ptr.Deref(lease.Spec.LeaseDurationSeconds, 0)) * time.Second
+	return now.After(lease.Spec.RenewTime.Add(duration))
+}
@@ -0,0 +1,202 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failoverarbiter
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lease failover arbiter", func() {
+	var (
+		cluster *apiv1.Cluster
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				FailoverArbiter: &apiv1.FailoverArbiterConfiguration{
+					Lease: &apiv1.FailoverArbiterLease{
+						Namespace: "arbiter",
+						Identity:  "site-a",
+					},
+				},
+			},
+		}
+	})
+
+	newLease := func(holder string, renewTime time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "arbiter", Name: "cluster-example"},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(holder),
+				LeaseDurationSeconds: ptr.To(int32(30)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(renewTime)),
+				LeaseTransitions:     ptr.To(int32(0)),
+			},
+		}
+	}
+
+	getLease := func(ctx context.Context, cli client.Client) *coordinationv1.Lease {
+		var lease coordinationv1.Lease
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "arbiter", Name: "cluster-example"}, &lease)).To(Succeed())
+		return &lease
+	}
+
+	It("creates the lease when it doesn't exist", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).Build()
+
+		acquired, holder, err := NewLeaseArbiter(cli, cluster).tryAcquire(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeTrue())
+		Expect(holder).To(Equal("site-a"))
+
+		lease := getLease(ctx, cli)
+		Expect(lease.Spec.HolderIdentity).To(HaveValue(Equal("site-a")))
+		Expect(lease.Spec.LeaseDurationSeconds).To(HaveValue(BeEquivalentTo(30)))
+	})
+
+	It("renews the lease held by this cluster", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newLease("site-a", now.Add(-20*time.Second))).Build()
+
+		acquired, _, err := NewLeaseArbiter(cli, cluster).tryAcquire(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeTrue())
+
+		lease := getLease(ctx, cli)
+		Expect(lease.Spec.RenewTime.Time.Unix()).To(Equal(now.Unix()))
+		Expect(lease.Spec.LeaseTransitions).To(HaveValue(BeEquivalentTo(0)))
+	})
+
+	It("is denied while the lease is held by another cluster", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newLease("site-b", now.Add(-10*time.Second))).Build()
+
+		acquired, holder, err := NewLeaseArbiter(cli, cluster).tryAcquire(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeFalse())
+		Expect(holder).To(Equal("site-b"))
+		Expect(getLease(ctx, cli).Spec.HolderIdentity).To(HaveValue(Equal("site-b")))
+	})
+
+	It("takes over an expired lease held by another cluster", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newLease("site-b", now.Add(-time.Minute))).Build()
+
+		acquired, holder, err := NewLeaseArbiter(cli, cluster).tryAcquire(ctx, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeTrue())
+		Expect(holder).To(Equal("site-a"))
+
+		lease := getLease(ctx, cli)
+		Expect(lease.Spec.HolderIdentity).To(HaveValue(Equal("site-a")))
+		Expect(lease.Spec.LeaseTransitions).To(HaveValue(BeEquivalentTo(1)))
+	})
+
+	It("fails when the kubeconfig secret doesn't contain the requested key", func(ctx SpecContext) {
+		cluster.Spec.FailoverArbiter.Lease.KubeconfigSecret = apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "arbiter-kubeconfig"},
+			Key:                  "kubeconfig",
+		}
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "arbiter-kubeconfig"},
+			}).Build()
+
+		arbiter, err := NewRegistry().Get(ctx, cli, cluster)
+		Expect(err).To(HaveOccurred())
+		Expect(arbiter).To(BeNil())
+	})
+
+	It("returns no arbiter when it isn't configured", func(ctx SpecContext) {
+		cluster.Spec.FailoverArbiter = nil
+		arbiter, err := NewRegistry().Get(ctx, nil, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arbiter).To(BeNil())
+	})
+
+	It("creates the client again only when the kubeconfig changes", func(ctx SpecContext) {
+		cluster.Spec.FailoverArbiter.Lease.KubeconfigSecret = apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "arbiter-kubeconfig"},
+			Key:                  "kubeconfig",
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "arbiter-kubeconfig"},
+			Data:       map[string][]byte{"kubeconfig": newKubeconfig("https://arbiter-a:6443")},
+		}
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).WithObjects(secret).Build()
+		registry := NewRegistry()
+
+		arbiter, err := registry.Get(ctx, cli, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(registry.Get(ctx, cli, cluster)).To(BeIdenticalTo(arbiter))
+
+		secret.Data["kubeconfig"] = newKubeconfig("https://arbiter-b:6443")
+		Expect(cli.Update(ctx, secret)).To(Succeed())
+		Expect(registry.Get(ctx, cli, cluster)).ToNot(BeIdenticalTo(arbiter))
+	})
+
+	It("renews the lease every third of its duration and expires it after the whole duration", func() {
+		arbiter := NewLeaseArbiter(nil, cluster)
+		Expect(arbiter.GetRenewalDelay(now)).To(BeZero())
+		Expect(arbiter.IsExpired(arbiter.createdAt.Add(20 * time.Second))).To(BeFalse())
+		Expect(arbiter.IsExpired(arbiter.createdAt.Add(30 * time.Second))).To(BeTrue())
+
+		arbiter.lastAcquired = now
+		Expect(arbiter.GetRenewalDelay(now.Add(4 * time.Second))).To(Equal(6 * time.Second))
+		Expect(arbiter.GetRenewalDelay(now.Add(15 * time.Second))).To(BeZero())
+		Expect(arbiter.IsExpired(now.Add(29 * time.Second))).To(BeFalse())
+		Expect(arbiter.IsExpired(now.Add(30 * time.Second))).To(BeTrue())
+	})
+})
+
+func newKubeconfig(server string) []byte {
+	return []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: arbiter
+  cluster:
+    server: ` + server + `
+contexts:
+- name: arbiter
+  context:
+    cluster: arbiter
+    user: arbiter
+current-context: arbiter
+users:
+- name: arbiter
+  user:
+    token: secret
+`)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// resource is a resource of the cluster that is a candidate
+// for the garbage collection
+type resource struct {
+	object client.Object
+	kind   string
+}
+
+// detectOrphanedJobs finds the failed jobs. The major upgrade jobs are
+// excluded, as their deletion makes the operator retry the upgrade, and
+// so are the in-place restore jobs, which are removed by the next restore
+func detectOrphanedJobs(jobs []batchv1.Job) []resource {
+	var result []resource
+	for idx := range jobs {
+		job := &jobs[idx]
+		if !job.DeletionTimestamp.IsZero() || specs.IsMajorUpgradeJob(*job) || specs.IsInPlaceRestoreJob(*job) {
+			continue
+		}
+
+		if utils.JobHasFailed(*job) {
+			result = append(result, resource{object: job, kind: "Job"})
+		}
+	}
+	return result
+}
+
+// detectPVCs splits the PVCs of the cluster between the ones whose
+// initialization never completed, and that are not used by any Pod or
+// running Job, and the other ones
+func detectPVCs(
+	cluster *apiv1.Cluster,
+	jobs []batchv1.Job,
+	pvcs []corev1.PersistentVolumeClaim,
+) (orphaned []resource, used []resource) {
+	// The status of the cluster already contains the list of
+	// the PVCs not used by any Pod
+	unusedPVCs := stringset.New()
+	for _, names := range [][]string{
+		cluster.Status.DanglingPVC,
+		cluster.Status.UnusablePVC,
+		cluster.Status.InitializingPVC,
+	} {
+		for _, name := range names {
+			unusedPVCs.Put(name)
+		}
+	}
+
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		if !pvc.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		candidate := resource{object: pvc, kind: "PersistentVolumeClaim"}
+		if unusedPVCs.Has(pvc.Name) &&
+			pvc.Annotations[utils.PVCStatusAnnotationName] != persistentvolumeclaim.StatusReady &&
+			!isUsedByActiveJob(pvc, jobs) {
+			orphaned = append(orphaned, candidate)
+		} else {
+			used = append(used, candidate)
+		}
+	}
+
+	return orphaned, used
+}
+
+// isUsedByActiveJob checks if the PVC is used by a Job that has not failed
+func isUsedByActiveJob(pvc *corev1.PersistentVolumeClaim, jobs []batchv1.Job) bool {
+	for _, job := range jobs {
+		if utils.JobHasFailed(job) {
+			continue
+		}
+
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvc.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// detectSecrets splits the certificate secrets generated by the operator
+// between the ones that have been replaced by user-provided secrets
+// and the ones that are still used
+func detectSecrets(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+) (orphaned []resource, used []resource, err error) {
+	caSecretName := cluster.Name + apiv1.DefaultServerCaSecretSuffix
+	serverSecretName := cluster.Name + apiv1.ServerSecretSuffix
+	replicationSecretName := cluster.Name + apiv1.ReplicationSecretSuffix
+
+	// The generated CA is used both for the server and for the clients
+	generatedSecrets := map[string]bool{
+		caSecretName: cluster.GetServerCASecretName() != caSecretName &&
+			cluster.GetClientCASecretName() != caSecretName,
+		serverSecretName:      cluster.GetServerTLSSecretName() != serverSecretName,
+		replicationSecretName: cluster.GetReplicationSecretName() != replicationSecretName,
+	}
+
+	for _, name := range []string{caSecretName, serverSecretName, replicationSecretName} {
+		var secret corev1.Secret
+		err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &secret)
+		if apierrs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// We only collect the secrets that have been generated
+		// by the operator for this cluster
+		if owner := metav1.GetControllerOf(&secret); owner == nil ||
+			owner.Kind != apiv1.ClusterKind || owner.Name != cluster.Name {
+			continue
+		}
+
+		candidate := resource{object: &secret, kind: "Secret"}
+		if generatedSecrets[name] {
+			orphaned = append(orphaned, candidate)
+		} else {
+			used = append(used, candidate)
+		}
+	}
+
+	return orphaned, used, nil
+}
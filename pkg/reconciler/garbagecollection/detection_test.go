@@ -0,0 +1,165 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newJob(name string, pvcName string, failed bool) batchv1.Job {
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "pgdata",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvcName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if failed {
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+		}
+	}
+	return job
+}
+
+func newPVC(name string, status persistentvolumeclaim.PVCStatus) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{utils.PVCStatusAnnotationName: status},
+		},
+	}
+}
+
+func newSecret(cluster *apiv1.Cluster, name string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+	}
+	cluster.SetInheritedDataAndOwnership(&secret.ObjectMeta)
+	return secret
+}
+
+func getNames(resources []resource) []string {
+	result := make([]string, len(resources))
+	for i := range resources {
+		result[i] = resources[i].object.GetName()
+	}
+	return result
+}
+
+var _ = Describe("orphaned resources detection", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+	})
+
+	It("detects the failed jobs, except the major upgrade ones", func() {
+		upgradeJob := newJob("cluster-example-1-major-upgrade", "cluster-example-1", true)
+		upgradeJob.Spec.Template.Labels = map[string]string{utils.JobRoleLabelName: "major-upgrade"}
+
+		jobs := []batchv1.Job{
+			newJob("cluster-example-2-join", "cluster-example-2", true),
+			newJob("cluster-example-3-join", "cluster-example-3", false),
+			upgradeJob,
+		}
+		Expect(getNames(detectOrphanedJobs(jobs))).To(ConsistOf("cluster-example-2-join"))
+	})
+
+	It("detects the PVCs whose initialization never completed", func() {
+		cluster.Status.DanglingPVC = []string{"cluster-example-1", "cluster-example-2"}
+		cluster.Status.InitializingPVC = []string{"cluster-example-3", "cluster-example-4"}
+		cluster.Status.HealthyPVC = []string{"cluster-example-5"}
+
+		pvcs := []corev1.PersistentVolumeClaim{
+			newPVC("cluster-example-1", persistentvolumeclaim.StatusReady),
+			newPVC("cluster-example-2", persistentvolumeclaim.StatusInitializing),
+			newPVC("cluster-example-3", persistentvolumeclaim.StatusInitializing),
+			newPVC("cluster-example-4", persistentvolumeclaim.StatusInitializing),
+			newPVC("cluster-example-5", persistentvolumeclaim.StatusReady),
+		}
+		jobs := []batchv1.Job{
+			newJob("cluster-example-3-join", "cluster-example-3", true),
+			newJob("cluster-example-4-join", "cluster-example-4", false),
+		}
+
+		orphaned, used := detectPVCs(cluster, jobs, pvcs)
+		Expect(getNames(orphaned)).To(ConsistOf("cluster-example-2", "cluster-example-3"))
+		Expect(getNames(used)).To(ConsistOf("cluster-example-1", "cluster-example-4", "cluster-example-5"))
+	})
+
+	It("detects the generated certificates replaced by user-provided ones", func(ctx SpecContext) {
+		cluster.Spec.Certificates = &apiv1.CertificatesConfiguration{
+			ServerCASecret:  "my-server-ca",
+			ServerTLSSecret: "my-server-tls",
+		}
+
+		foreignSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-server", Namespace: "default"},
+		}
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				newSecret(cluster, "cluster-example-ca"),
+				newSecret(cluster, "cluster-example-replication"),
+				foreignSecret,
+			).Build()
+
+		orphaned, used, err := detectSecrets(ctx, cli, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		// The generated CA is still used for the client certificates, and
+		// the server secret has not been generated by the operator
+		Expect(orphaned).To(BeEmpty())
+		Expect(getNames(used)).To(ConsistOf("cluster-example-ca", "cluster-example-replication"))
+
+		cluster.Spec.Certificates.ClientCASecret = "my-client-ca"
+		cluster.Spec.Certificates.ReplicationTLSSecret = "my-replication-tls"
+		orphaned, used, err = detectSecrets(ctx, cli, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getNames(orphaned)).To(ConsistOf("cluster-example-ca", "cluster-example-replication"))
+		Expect(used).To(BeEmpty())
+	})
+})
@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection
+
+import (
+	"context"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/events"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// Reconcile detects the orphaned resources of the cluster, reporting them
+// with an event, and deletes the ones whose retention period has expired.
+// It returns the time after which the next orphaned resource expires,
+// or zero if there is nothing left to be deleted.
+func Reconcile(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	jobs []batchv1.Job,
+	pvcs []corev1.PersistentVolumeClaim,
+) (time.Duration, error) {
+	if cluster.Spec.GarbageCollection == nil {
+		return 0, nil
+	}
+
+	orphanedPVCs, usedPVCs := detectPVCs(cluster, jobs, pvcs)
+	orphanedSecrets, usedSecrets, err := detectSecrets(ctx, c, cluster)
+	if err != nil {
+		return 0, err
+	}
+
+	// A resource that was orphaned may have been adopted again,
+	// i.e. when a user-provided certificate is removed
+	for _, item := range append(usedPVCs, usedSecrets...) {
+		if err := unmarkOrphaned(ctx, c, item); err != nil {
+			return 0, err
+		}
+	}
+
+	orphaned := detectOrphanedJobs(jobs)
+	orphaned = append(orphaned, orphanedPVCs...)
+	orphaned = append(orphaned, orphanedSecrets...)
+
+	now := time.Now()
+	policy := cluster.Spec.GarbageCollection.GetPolicy()
+	retentionPeriod := cluster.Spec.GarbageCollection.GetRetentionPeriod()
+
+	var nextExpiration time.Duration
+	for _, item := range orphaned {
+		orphanedSince, detected, err := markOrphaned(ctx, c, item, now)
+		if err != nil {
+			return 0, err
+		}
+		if detected {
+			recorder.Eventf(cluster, "Normal", events.OrphanedResource,
+				"%s %s is not used anymore (garbage collection policy: %s)",
+				item.kind, item.object.GetName(), policy)
+		}
+
+		if policy != apiv1.GarbageCollectionPolicyDelete {
+			continue
+		}
+
+		if expiresIn := orphanedSince.Add(retentionPeriod).Sub(now); expiresIn > 0 {
+			if nextExpiration == 0 || expiresIn < nextExpiration {
+				nextExpiration = expiresIn
+			}
+			continue
+		}
+
+		if err := deleteOrphaned(ctx, c, item); err != nil {
+			return 0, err
+		}
+		recorder.Eventf(cluster, "Normal", events.GarbageCollected,
+			"Deleted the orphaned %s %s", item.kind, item.object.GetName())
+	}
+
+	return nextExpiration, nil
+}
+
+// markOrphaned annotates the resource with the time it has been detected
+// as orphaned, unless already done, and returns that time together with
+// a flag telling whether the resource has just been detected
+func markOrphaned(
+	ctx context.Context,
+	c client.Client,
+	item resource,
+	now time.Time,
+) (time.Time, bool, error) {
+	annotations := item.object.GetAnnotations()
+	if orphanedSince, err := time.Parse(time.RFC3339, annotations[utils.OrphanedSinceAnnotationName]); err == nil {
+		return orphanedSince, false, nil
+	}
+
+	log.FromContext(ctx).Info("Detected an orphaned resource",
+		"kind", item.kind, "name", item.object.GetName())
+
+	// The annotation has a resolution of one second, and we
+	// round the time to get the same value when reading it back
+	now = now.Truncate(time.Second)
+
+	origObject := item.object.DeepCopyObject().(client.Object)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[utils.OrphanedSinceAnnotationName] = now.Format(time.RFC3339)
+	item.object.SetAnnotations(annotations)
+
+	if err := c.Patch(ctx, item.object, client.MergeFrom(origObject)); err != nil {
+		return time.Time{}, false, err
+	}
+	return now, true, nil
+}
+
+// unmarkOrphaned removes the annotation set by markOrphaned
+func unmarkOrphaned(ctx context.Context, c client.Client, item resource) error {
+	annotations := item.object.GetAnnotations()
+	if _, ok := annotations[utils.OrphanedSinceAnnotationName]; !ok {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("The resource is not orphaned anymore",
+		"kind", item.kind, "name", item.object.GetName())
+
+	origObject := item.object.DeepCopyObject().(client.Object)
+	delete(annotations, utils.OrphanedSinceAnnotationName)
+	item.object.SetAnnotations(annotations)
+
+	return c.Patch(ctx, item.object, client.MergeFrom(origObject))
+}
+
+// deleteOrphaned removes an orphaned resource, together with its dependents
+func deleteOrphaned(ctx context.Context, c client.Client, item resource) error {
+	log.FromContext(ctx).Info("Deleting an orphaned resource",
+		"kind", item.kind, "name", item.object.GetName())
+
+	err := c.Delete(ctx, item.object, client.PropagationPolicy("Background"))
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection
+
+import (
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("garbage collection", func() {
+	var (
+		cluster  *apiv1.Cluster
+		job      batchv1.Job
+		pvc      corev1.PersistentVolumeClaim
+		cli      client.Client
+		recorder *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				GarbageCollection: &apiv1.GarbageCollectionConfiguration{
+					RetentionPeriod: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				InitializingPVC: []string{"cluster-example-2"},
+			},
+		}
+		job = newJob("cluster-example-2-join", "cluster-example-2", true)
+		pvc = newPVC("cluster-example-2", persistentvolumeclaim.StatusInitializing)
+		cli = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(&job, &pvc).Build()
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	reconcile := func(ctx SpecContext) time.Duration {
+		var jobs batchv1.JobList
+		Expect(cli.List(ctx, &jobs)).To(Succeed())
+		var pvcs corev1.PersistentVolumeClaimList
+		Expect(cli.List(ctx, &pvcs)).To(Succeed())
+
+		requeue, err := Reconcile(ctx, cli, recorder, cluster, jobs.Items, pvcs.Items)
+		Expect(err).ToNot(HaveOccurred())
+		return requeue
+	}
+
+	setOrphanedSince := func(ctx SpecContext, object client.Object, since time.Time) {
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(object), object)).To(Succeed())
+		annotations := object.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[utils.OrphanedSinceAnnotationName] = since.Format(time.RFC3339)
+		object.SetAnnotations(annotations)
+		Expect(cli.Update(ctx, object)).To(Succeed())
+	}
+
+	It("does nothing when not configured", func(ctx SpecContext) {
+		cluster.Spec.GarbageCollection = nil
+		Expect(reconcile(ctx)).To(BeZero())
+		Expect(recorder.Events).To(BeEmpty())
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(&job), &job)).To(Succeed())
+		Expect(job.Annotations).ToNot(HaveKey(utils.OrphanedSinceAnnotationName))
+	})
+
+	It("marks the orphaned resources and waits for the retention period", func(ctx SpecContext) {
+		requeue := reconcile(ctx)
+		Expect(requeue).To(BeNumerically(">", 59*time.Minute))
+		Expect(requeue).To(BeNumerically("<=", time.Hour))
+		Expect(recorder.Events).To(HaveLen(2))
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(&job), &job)).To(Succeed())
+		Expect(job.Annotations).To(HaveKey(utils.OrphanedSinceAnnotationName))
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(&pvc), &pvc)).To(Succeed())
+		Expect(pvc.Annotations).To(HaveKey(utils.OrphanedSinceAnnotationName))
+
+		// The resources are reported only once
+		reconcile(ctx)
+		Expect(recorder.Events).To(HaveLen(2))
+	})
+
+	It("deletes the orphaned resources when the retention period expires", func(ctx SpecContext) {
+		setOrphanedSince(ctx, &job, time.Now().Add(-2*time.Hour))
+		setOrphanedSince(ctx, &pvc, time.Now().Add(-2*time.Hour))
+
+		Expect(reconcile(ctx)).To(BeZero())
+		err := cli.Get(ctx, client.ObjectKeyFromObject(&job), &job)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		err = cli.Get(ctx, client.ObjectKeyFromObject(&pvc), &pvc)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(2))
+	})
+
+	It("retains the orphaned resources when requested", func(ctx SpecContext) {
+		cluster.Spec.GarbageCollection.Policy = apiv1.GarbageCollectionPolicyRetain
+		setOrphanedSince(ctx, &job, time.Now().Add(-2*time.Hour))
+
+		Expect(reconcile(ctx)).To(BeZero())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(&job), &job)).To(Succeed())
+	})
+
+	It("unmarks the resources that are used again", func(ctx SpecContext) {
+		setOrphanedSince(ctx, &pvc, time.Now())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(&pvc), &pvc)).To(Succeed())
+		pvc.Annotations[utils.PVCStatusAnnotationName] = persistentvolumeclaim.StatusReady
+		Expect(cli.Update(ctx, &pvc)).To(Succeed())
+
+		reconcile(ctx)
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(&pvc), &pvc)).To(Succeed())
+		Expect(pvc.Annotations).ToNot(HaveKey(utils.OrphanedSinceAnnotationName))
+	})
+})
@@ -182,6 +182,47 @@ var _ = Describe("Reconcile resource requests", func() {
 		)
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	It("resizes each PVC according to its own storage section", func() {
+		const clusterName = "cluster-resize"
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size:               "2Gi",
+					ResizeInUseVolumes: ptr.To(false),
+				},
+				WalStorage: &apiv1.StorageConfiguration{
+					Size:               "2Gi",
+					ResizeInUseVolumes: ptr.To(true),
+				},
+			},
+		}
+		pvcs := corev1.PersistentVolumeClaimList{
+			Items: []corev1.PersistentVolumeClaim{
+				makePVC(clusterName, "1", "1", NewPgDataCalculator(), false),
+				makePVC(clusterName, "1-wal", "1", NewPgWalCalculator(), false),
+			},
+		}
+		for idx := range pvcs.Items {
+			pvcs.Items[idx].Spec.Resources.Requests = corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("1Gi"),
+			}
+		}
+
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithLists(&pvcs).
+			Build()
+		Expect(reconcileResourceRequests(context.Background(), cli, cluster, pvcs.Items)).To(Succeed())
+
+		var dataPVC, walPVC corev1.PersistentVolumeClaim
+		Expect(cli.Get(context.Background(), types.NamespacedName{Name: clusterName + "-1"}, &dataPVC)).To(Succeed())
+		Expect(cli.Get(context.Background(), types.NamespacedName{Name: clusterName + "-1-wal"}, &walPVC)).To(Succeed())
+		Expect(dataPVC.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+		Expect(walPVC.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
+	})
 })
 
 var _ = Describe("PVC reconciliation", func() {
@@ -28,17 +28,14 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
-// reconcileResourceRequests align the resource requests
+// reconcileResourceRequests align the resource requests. Each PVC is
+// resized according to the storage section it has been created from
 func reconcileResourceRequests(
 	ctx context.Context,
 	c client.Client,
 	cluster *apiv1.Cluster,
 	pvcs []corev1.PersistentVolumeClaim,
 ) error {
-	if !cluster.ShouldResizeInUseVolumes() {
-		return nil
-	}
-
 	for idx := range pvcs {
 		if err := reconcilePVCQuantity(ctx, c, cluster, &pvcs[idx]); err != nil {
 			return err
@@ -74,6 +71,10 @@ func reconcilePVCQuantity(
 		return err
 	}
 
+	if !cluster.ShouldResizeInUseVolumesFor(&storageConfiguration) {
+		return nil
+	}
+
 	parsedSize := storageConfiguration.GetSizeOrNil()
 	if parsedSize == nil {
 		return ErrorInvalidSize
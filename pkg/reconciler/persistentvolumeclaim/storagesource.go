@@ -51,6 +51,9 @@ func GetCandidateStorageSourceForPrimary(
 	if backup.IsCompletedVolumeSnapshot() {
 		return getCandidateSourceFromBackup(backup)
 	}
+	if result := getCandidateSourceFromVolumeSource(cluster); result != nil {
+		return result
+	}
 	return getCandidateSourceFromClusterDefinition(cluster)
 }
 
@@ -182,3 +185,38 @@ func getCandidateSourceFromClusterDefinition(cluster *apiv1.Cluster) *StorageSou
 		TablespaceSource: volumeSnapshots.TablespaceStorage,
 	}
 }
+
+// getCandidateSourceFromVolumeSource gets a candidate storage source from
+// the PVCs the Cluster is adopting. Those PVCs are cloned only when creating
+// the first primary instance, replicas are then cloned from it
+func getCandidateSourceFromVolumeSource(cluster *apiv1.Cluster) *StorageSource {
+	if cluster.Spec.Bootstrap == nil ||
+		cluster.Spec.Bootstrap.Recovery == nil ||
+		cluster.Spec.Bootstrap.Recovery.VolumeSource == nil {
+		return nil
+	}
+
+	volumeSource := cluster.Spec.Bootstrap.Recovery.VolumeSource
+	result := StorageSource{
+		DataSource: newPersistentVolumeClaimReference(volumeSource.Storage.Name),
+	}
+	if volumeSource.WalStorage != nil {
+		walSource := newPersistentVolumeClaimReference(volumeSource.WalStorage.Name)
+		result.WALSource = &walSource
+	}
+	for tablespaceName, tablespaceSource := range volumeSource.TablespaceStorage {
+		if result.TablespaceSource == nil {
+			result.TablespaceSource = map[string]corev1.TypedLocalObjectReference{}
+		}
+		result.TablespaceSource[tablespaceName] = newPersistentVolumeClaimReference(tablespaceSource.Name)
+	}
+
+	return &result
+}
+
+func newPersistentVolumeClaimReference(name string) corev1.TypedLocalObjectReference {
+	return corev1.TypedLocalObjectReference{
+		Kind: "PersistentVolumeClaim",
+		Name: name,
+	}
+}
@@ -307,3 +307,55 @@ var _ = Describe("candidate backups", func() {
 		Expect(source).To(BeNil())
 	})
 })
+
+var _ = Describe("Storage source from existing PVCs", func() {
+	clusterWithVolumeSource := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			StorageConfiguration: apiv1.StorageConfiguration{},
+			WalStorage:           &apiv1.StorageConfiguration{},
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				Recovery: &apiv1.BootstrapRecovery{
+					VolumeSource: &apiv1.VolumeSourceRecovery{
+						Storage:    apiv1.LocalObjectReference{Name: "old-1"},
+						WalStorage: &apiv1.LocalObjectReference{Name: "old-1-wal"},
+						TablespaceStorage: map[string]apiv1.LocalObjectReference{
+							"tbs1": {Name: "old-1-tbs-tbs1"},
+						},
+					},
+				},
+			},
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://test",
+				},
+			},
+		},
+	}
+
+	It("clones the PVCs when creating the primary", func() {
+		source := GetCandidateStorageSourceForPrimary(clusterWithVolumeSource, nil)
+		Expect(source).ToNot(BeNil())
+		Expect(source.DataSource).To(Equal(corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: "old-1",
+		}))
+		Expect(source.WALSource).To(Equal(&corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: "old-1-wal",
+		}))
+		Expect(source.TablespaceSource).To(HaveKeyWithValue("tbs1", corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: "old-1-tbs-tbs1",
+		}))
+	})
+
+	It("doesn't use them when creating a replica", func(ctx context.Context) {
+		source, err := NewPgDataCalculator().GetSource(GetCandidateStorageSourceForReplica(
+			ctx,
+			clusterWithVolumeSource,
+			apiv1.BackupList{},
+		))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(source).To(BeNil())
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	volumesnapshot "github.com/kubernetes-csi/external-snapshotter/client/v7/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -157,6 +158,100 @@ func VerifyDataSourceCoherence(
 	return result, nil
 }
 
+// validateVolumeSource validates a PVC to be adopted, adding the
+// result to the status
+func (status *ValidationStatus) validateVolumeSource(
+	name string,
+	pvc *corev1.PersistentVolumeClaim,
+	expectedMeta Meta,
+) {
+	if pvc == nil {
+		status.addErrorf(name, "PersistentVolumeClaim doesn't exist")
+		return
+	}
+
+	if pvc.Status.Phase != corev1.ClaimBound {
+		status.addErrorf(name, "PersistentVolumeClaim is not bound (phase: '%s')", pvc.Status.Phase)
+	}
+
+	pvcRoleLabel := pvc.GetLabels()[utils.PvcRoleLabelName]
+	if len(pvcRoleLabel) == 0 {
+		status.addWarningf(name, "Empty PVC role label")
+	} else if pvcRoleLabel != expectedMeta.GetRoleName() {
+		status.addErrorf(
+			name,
+			"Expected role '%s', found '%s'",
+			expectedMeta.GetRoleName(),
+			pvcRoleLabel)
+	}
+}
+
+// VerifyVolumeSourceCoherence verifies if the PVCs that we should adopt
+// when creating a new cluster are usable. We check for:
+//
+//   - the PVCs exist and are bound, otherwise they can't be cloned
+//
+//   - the role of the PVCs, when known, is coherent with the requested
+//     section (being storage, walStorage or tablespaceStorage)
+func VerifyVolumeSourceCoherence(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	source *apiv1.VolumeSourceRecovery,
+) (ValidationStatus, error) {
+	var result ValidationStatus
+
+	if source == nil {
+		return result, nil
+	}
+
+	validate := func(name string, expectedMeta Meta) error {
+		pvc, err := getPersistentVolumeClaimOrNil(ctx, c, client.ObjectKey{Namespace: namespace, Name: name})
+		if err != nil {
+			return err
+		}
+		result.validateVolumeSource(name, pvc, expectedMeta)
+		return nil
+	}
+
+	if err := validate(source.Storage.Name, NewPgDataCalculator()); err != nil {
+		return result, err
+	}
+
+	if source.WalStorage != nil {
+		if err := validate(source.WalStorage.Name, NewPgWalCalculator()); err != nil {
+			return result, err
+		}
+	}
+
+	for tablespaceName, tablespaceSource := range source.TablespaceStorage {
+		if err := validate(tablespaceSource.Name, NewPgTablespaceCalculator(tablespaceName)); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// getPersistentVolumeClaimOrNil gets a PVC with a specified name.
+// If the PVC doesn't exist, returns nil
+func getPersistentVolumeClaimOrNil(
+	ctx context.Context,
+	c client.Client,
+	name client.ObjectKey,
+) (*corev1.PersistentVolumeClaim, error) {
+	var result corev1.PersistentVolumeClaim
+	if err := c.Get(ctx, name, &result); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // getVolumeShapshotOrNil gets a volume snapshot with a specified name.
 // If the volume snapshot don't exist, returns nil
 func getVolumeShapshotOrNil(
@@ -274,3 +274,91 @@ var _ = Describe("Volume Snapshot validation", func() {
 		}))
 	})
 })
+
+var _ = Describe("Volume source validation", func() {
+	newPVC := func(name string, role utils.PVCRole, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase: phase,
+			},
+		}
+		if role != "" {
+			pvc.Labels[utils.PvcRoleLabelName] = string(role)
+		}
+		return pvc
+	}
+
+	It("accepts bound PVCs having the expected roles", func(ctx SpecContext) {
+		mockClient := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				newPVC("old-1", utils.PVCRolePgData, corev1.ClaimBound),
+				newPVC("old-1-wal", utils.PVCRolePgWal, corev1.ClaimBound),
+			).
+			Build()
+
+		status, err := VerifyVolumeSourceCoherence(ctx, mockClient, "default", &apiv1.VolumeSourceRecovery{
+			Storage:    apiv1.LocalObjectReference{Name: "old-1"},
+			WalStorage: &apiv1.LocalObjectReference{Name: "old-1-wal"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.ContainsErrors()).To(BeFalse())
+		Expect(status.ContainsWarnings()).To(BeFalse())
+	})
+
+	It("warns when the PVC role label is missing", func(ctx SpecContext) {
+		mockClient := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newPVC("restored", "", corev1.ClaimBound)).
+			Build()
+
+		status, err := VerifyVolumeSourceCoherence(ctx, mockClient, "default", &apiv1.VolumeSourceRecovery{
+			Storage: apiv1.LocalObjectReference{Name: "restored"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(ValidationStatus{
+			Warnings: []ValidationMessage{
+				{
+					ObjectName: "restored",
+					Message:    "Empty PVC role label",
+				},
+			},
+		}))
+	})
+
+	It("complains about missing, unbound and mismatching PVCs", func(ctx SpecContext) {
+		mockClient := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				newPVC("old-1", utils.PVCRolePgWal, corev1.ClaimPending),
+			).
+			Build()
+
+		status, err := VerifyVolumeSourceCoherence(ctx, mockClient, "default", &apiv1.VolumeSourceRecovery{
+			Storage:    apiv1.LocalObjectReference{Name: "old-1"},
+			WalStorage: &apiv1.LocalObjectReference{Name: "old-1-wal"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(ValidationStatus{
+			Errors: []ValidationMessage{
+				{
+					ObjectName: "old-1",
+					Message:    "PersistentVolumeClaim is not bound (phase: 'Pending')",
+				},
+				{
+					ObjectName: "old-1",
+					Message:    "Expected role 'PG_DATA', found 'PG_WAL'",
+				},
+				{
+					ObjectName: "old-1-wal",
+					Message:    "PersistentVolumeClaim doesn't exist",
+				},
+			},
+		}))
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaclusterswitch
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcilePromotionToken stores in the status the demotion token of the
+// former primary cluster, when it lives in the same namespace, so that
+// the designated primary can be promoted without copying the token by hand.
+// The token is removed once the promotion has happened
+func reconcilePromotionToken(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	instances postgres.PostgresqlStatusList,
+) error {
+	var token string
+	if isWaitingForPromotionToken(cluster, instances) {
+		var err error
+		if token, err = findDemotionToken(ctx, cli, cluster); err != nil {
+			return err
+		}
+	}
+
+	if token == cluster.Status.PromotionToken {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Updating the promotion token found in the former primary cluster",
+		"found", token != "")
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PromotionToken = token
+	return cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// isWaitingForPromotionToken checks if the designated primary of a
+// cluster in a distributed topology is waiting to be promoted without
+// a token set by the user
+func isWaitingForPromotionToken(cluster *apiv1.Cluster, instances postgres.PostgresqlStatusList) bool {
+	replicaCluster := cluster.Spec.ReplicaCluster
+	return replicaCluster != nil &&
+		replicaCluster.Primary != "" &&
+		replicaCluster.PromotionToken == "" &&
+		!cluster.IsReplica() &&
+		!containsPrimaryInstance(instances)
+}
+
+// findDemotionToken gets the demotion token of the cluster in the same
+// namespace which is named after the source of the passed cluster, and
+// has been demoted in favor of it
+func findDemotionToken(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) (string, error) {
+	var clusters apiv1.ClusterList
+	if err := cli.List(ctx, &clusters, client.InNamespace(cluster.Namespace)); err != nil {
+		return "", err
+	}
+
+	for _, item := range clusters.Items {
+		if item.UID == cluster.UID ||
+			item.Spec.ReplicaCluster == nil ||
+			item.GetReplicaClusterSelf() != cluster.Spec.ReplicaCluster.Source ||
+			item.Spec.ReplicaCluster.Primary != cluster.GetReplicaClusterSelf() {
+			continue
+		}
+
+		return item.Status.DemotionToken, nil
+	}
+
+	return "", nil
+}
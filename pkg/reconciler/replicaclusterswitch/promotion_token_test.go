@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaclusterswitch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Promotion token", func() {
+	var (
+		formerPrimary *apiv1.Cluster
+		newPrimary    *apiv1.Cluster
+		cli           client.Client
+	)
+
+	BeforeEach(func() {
+		formerPrimary = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-eu-south", Namespace: "default", UID: "south"},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Primary: "cluster-eu-central",
+					Source:  "cluster-eu-central",
+				},
+			},
+			Status: apiv1.ClusterStatus{DemotionToken: "token"},
+		}
+		newPrimary = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-eu-central", Namespace: "default", UID: "central"},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Primary: "cluster-eu-central",
+					Source:  "cluster-eu-south",
+				},
+			},
+		}
+	})
+
+	newClient := func(objects ...client.Object) {
+		cli = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			WithStatusSubresource(objects...).
+			Build()
+	}
+
+	It("takes the demotion token of the former primary cluster", func(ctx SpecContext) {
+		newClient(formerPrimary, newPrimary)
+
+		Expect(reconcilePromotionToken(ctx, cli, newPrimary, postgres.PostgresqlStatusList{})).To(Succeed())
+		Expect(newPrimary.Status.PromotionToken).To(Equal("token"))
+		Expect(newPrimary.GetPromotionToken()).To(Equal("token"))
+	})
+
+	It("ignores the clusters which haven't been demoted in favor of this one", func(ctx SpecContext) {
+		formerPrimary.Spec.ReplicaCluster.Primary = "cluster-eu-west"
+		newClient(formerPrimary, newPrimary)
+
+		Expect(reconcilePromotionToken(ctx, cli, newPrimary, postgres.PostgresqlStatusList{})).To(Succeed())
+		Expect(newPrimary.Status.PromotionToken).To(BeEmpty())
+	})
+
+	It("gives precedence to the token set by the user", func(ctx SpecContext) {
+		newPrimary.Spec.ReplicaCluster.PromotionToken = "user-token"
+		newClient(formerPrimary, newPrimary)
+
+		Expect(reconcilePromotionToken(ctx, cli, newPrimary, postgres.PostgresqlStatusList{})).To(Succeed())
+		Expect(newPrimary.Status.PromotionToken).To(BeEmpty())
+		Expect(newPrimary.GetPromotionToken()).To(Equal("user-token"))
+	})
+
+	It("removes the token once the promotion has happened", func(ctx SpecContext) {
+		newPrimary.Status.PromotionToken = "token"
+		newClient(formerPrimary, newPrimary)
+
+		instances := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{{IsPrimary: true}}}
+		Expect(reconcilePromotionToken(ctx, cli, newPrimary, instances)).To(Succeed())
+		Expect(newPrimary.Status.PromotionToken).To(BeEmpty())
+	})
+})
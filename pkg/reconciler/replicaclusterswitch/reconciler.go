@@ -38,6 +38,10 @@ func Reconcile(
 	cluster *apiv1.Cluster,
 	instances postgres.PostgresqlStatusList,
 ) (*ctrl.Result, error) {
+	if err := reconcilePromotionToken(ctx, cli, cluster, instances); err != nil {
+		return nil, err
+	}
+
 	if !cluster.IsReplica() {
 		return nil, nil
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package instance
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -210,6 +211,108 @@ func (r *StatusClient) GetPgControlDataFromInstance(
 	return result.Data, result.Error
 }
 
+// GetShutdownStatusFromInstance obtains the progress of the shutdown procedure
+// of the instance from its HTTP endpoint
+func (r *StatusClient) GetShutdownStatusFromInstance(
+	ctx context.Context,
+	pod *corev1.Pod,
+) (*postgres.ShutdownStatus, error) {
+	contextLogger := log.FromContext(ctx)
+
+	httpURL := url.Build(pod.Status.PodIP, url.PathPgShutdownStatus, url.StatusPort)
+	req, err := http.NewRequestWithContext(ctx, "GET", httpURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			contextLogger.Error(err, "while closing body")
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result struct {
+		Data *postgres.ShutdownStatus `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("empty shutdown status")
+	}
+
+	return result.Data, nil
+}
+
+// GetConfigurationDiffFromInstance asks the instance which of the passed
+// configuration parameters would change if applied, and whether they need
+// a restart of PostgreSQL. The configuration of the instance is not changed
+func (r *StatusClient) GetConfigurationDiffFromInstance(
+	ctx context.Context,
+	pod *corev1.Pod,
+	parameters map[string]string,
+) (*postgres.ConfigurationDiff, error) {
+	contextLogger := log.FromContext(ctx)
+
+	requestBody, err := json.Marshal(postgres.ConfigurationDiffRequest{Parameters: parameters})
+	if err != nil {
+		return nil, err
+	}
+
+	httpURL := url.Build(pod.Status.PodIP, url.PathPgConfigurationDiff, url.StatusPort)
+	req, err := http.NewRequestWithContext(ctx, "POST", httpURL, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			contextLogger.Error(err, "while closing body")
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result struct {
+		Data *postgres.ConfigurationDiff `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("empty configuration diff")
+	}
+
+	return result.Data, nil
+}
+
 // rawInstanceStatusRequest retrieves the status of PostgreSQL pods via an HTTP request with GET method.
 func (r *StatusClient) rawInstanceStatusRequest(
 	ctx context.Context,
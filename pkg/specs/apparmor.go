@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// getAppArmorAnnotations gets the AppArmor annotations to be applied to a pod
+// having the passed spec: the ones set in the cluster for its containers,
+// and the ones applying the profile of the cluster spec to the other containers
+func getAppArmorAnnotations(cluster *apiv1.Cluster, spec *corev1.PodSpec) map[string]string {
+	annotations := make(map[string]string)
+	for _, key := range getAppArmorAnnotationKeys(spec) {
+		if value, ok := cluster.Annotations[key]; ok {
+			annotations[key] = value
+		} else if cluster.Spec.AppArmorProfile != nil {
+			annotations[key] = cluster.Spec.AppArmorProfile.GetAnnotationValue()
+		}
+	}
+
+	return annotations
+}
+
+// getAppArmorAnnotationKeys gets the names of the AppArmor annotations
+// of the containers, including the init ones, of a pod
+func getAppArmorAnnotationKeys(spec *corev1.PodSpec) []string {
+	keys := make([]string, 0, len(spec.InitContainers)+len(spec.Containers))
+	for _, container := range spec.InitContainers {
+		keys = append(keys, utils.AppArmorAnnotationPrefix+"/"+container.Name)
+	}
+	for _, container := range spec.Containers {
+		keys = append(keys, utils.AppArmorAnnotationPrefix+"/"+container.Name)
+	}
+	return keys
+}
+
+// annotateAppArmor applies the AppArmor annotations of the cluster to a pod
+func annotateAppArmor(cluster *apiv1.Cluster, object *metav1.ObjectMeta, spec *corev1.PodSpec) {
+	annotations := getAppArmorAnnotations(cluster, spec)
+	if len(annotations) == 0 {
+		return
+	}
+
+	if object.Annotations == nil {
+		object.Annotations = make(map[string]string)
+	}
+	for key, value := range annotations {
+		object.Annotations[key] = value
+	}
+}
+
+// IsPodAppArmorProfileOutdated checks whether the AppArmor annotations of the
+// containers of an instance pod differ from the ones the cluster requires. As
+// these annotations cannot be changed, such a pod needs to be recreated
+func IsPodAppArmorProfileOutdated(cluster *apiv1.Cluster, pod *corev1.Pod) bool {
+	expected := getAppArmorAnnotations(cluster, &pod.Spec)
+	for _, key := range getAppArmorAnnotationKeys(&pod.Spec) {
+		currentValue, currentOk := pod.Annotations[key]
+		expectedValue, expectedOk := expected[key]
+		if currentOk != expectedOk || currentValue != expectedValue {
+			return true
+		}
+	}
+
+	return false
+}
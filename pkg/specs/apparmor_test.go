@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppArmor annotations", func() {
+	const (
+		postgresAnnotation  = utils.AppArmorAnnotationPrefix + "/" + PostgresContainerName
+		bootstrapAnnotation = utils.AppArmorAnnotationPrefix + "/" + BootstrapControllerContainerName
+	)
+
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+	})
+
+	It("doesn't annotate the pods when no profile is set", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Annotations).ToNot(HaveKey(postgresAnnotation))
+		Expect(pod.Annotations).ToNot(HaveKey(bootstrapAnnotation))
+	})
+
+	It("applies the profile of the cluster to every container", func() {
+		cluster.Spec.AppArmorProfile = &apiv1.AppArmorProfile{
+			Type:             apiv1.AppArmorProfileTypeLocalhost,
+			LocalhostProfile: "postgres",
+		}
+
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Annotations).To(HaveKeyWithValue(postgresAnnotation, "localhost/postgres"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(bootstrapAnnotation, "localhost/postgres"))
+	})
+
+	It("prefers the annotations of the cluster", func() {
+		cluster.Annotations = map[string]string{postgresAnnotation: "unconfined"}
+		cluster.Spec.AppArmorProfile = &apiv1.AppArmorProfile{Type: apiv1.AppArmorProfileTypeRuntimeDefault}
+
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Annotations).To(HaveKeyWithValue(postgresAnnotation, "unconfined"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(bootstrapAnnotation, "runtime/default"))
+	})
+
+	It("annotates the pods of the jobs", func() {
+		cluster.Spec.AppArmorProfile = &apiv1.AppArmorProfile{Type: apiv1.AppArmorProfileTypeUnconfined}
+		cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{InitDB: &apiv1.BootstrapInitDB{}}
+
+		job := CreatePrimaryJobViaInitdb(cluster, 1)
+		Expect(job.Spec.Template.Annotations).To(HaveKeyWithValue(bootstrapAnnotation, "unconfined"))
+		Expect(job.Spec.Template.Annotations).To(
+			HaveKeyWithValue(utils.AppArmorAnnotationPrefix+"/initdb", "unconfined"))
+	})
+
+	It("detects the pods created with a different profile", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(IsPodAppArmorProfileOutdated(&cluster, pod)).To(BeFalse())
+
+		cluster.Spec.AppArmorProfile = &apiv1.AppArmorProfile{Type: apiv1.AppArmorProfileTypeRuntimeDefault}
+		Expect(IsPodAppArmorProfileOutdated(&cluster, pod)).To(BeTrue())
+
+		pod = PodWithExistingStorage(cluster, 1)
+		Expect(IsPodAppArmorProfileOutdated(&cluster, pod)).To(BeFalse())
+
+		cluster.Spec.AppArmorProfile = nil
+		Expect(IsPodAppArmorProfileOutdated(&cluster, pod)).To(BeTrue())
+	})
+})
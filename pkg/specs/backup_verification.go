@@ -0,0 +1,234 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"slices"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// jobRoleBackupVerification is the role of the jobs verifying a backup.
+// These jobs are owned by the backup, not by the cluster, and are not
+// bound to an instance
+const jobRoleBackupVerification jobRole = "backup-verification"
+
+// backupVerificationBackoffLimit is the number of times the job verifying
+// a backup is retried. Every attempt starts from empty scratch volumes, so
+// a transient failure while downloading the backup doesn't fail it
+const backupVerificationBackoffLimit = 2
+
+// backupVerificationExcludedVolumes are the volumes of the instances that
+// aren't mounted by the job verifying a backup, which doesn't need the
+// credentials of the cluster
+var backupVerificationExcludedVolumes = []string{"superuser-secret", "app-secret"}
+
+// GetBackupVerificationJobName returns the name of the job verifying
+// the passed backup, which is also the name of its service account
+func GetBackupVerificationJobName(backupName string) string {
+	return backupName + "-verification"
+}
+
+// CreateBackupVerificationRole creates the role of the job verifying the
+// passed backup, which can only read the cluster, the backup, and the
+// secrets needed to access the object store where the backup is
+func CreateBackupVerificationRole(cluster apiv1.Cluster, backup *apiv1.Backup) rbacv1.Role {
+	var secrets []string
+	secrets = append(secrets, s3CredentialsSecrets(backup.Status.BarmanCredentials.AWS)...)
+	secrets = append(secrets, azureCredentialsSecrets(backup.Status.BarmanCredentials.Azure)...)
+	secrets = append(secrets, googleCredentialsSecrets(backup.Status.BarmanCredentials.Google)...)
+	if backup.Status.WALClientSideEncryption != nil {
+		secrets = append(secrets, backup.Status.WALClientSideEncryption.KeysSecret.Name)
+	}
+	if backup.Status.EndpointCA != nil {
+		secrets = append(secrets, backup.Status.EndpointCA.Name)
+	}
+
+	role := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: backup.Namespace,
+			Name:      GetBackupVerificationJobName(backup.Name),
+			Labels: map[string]string{
+				utils.ClusterLabelName:    cluster.Name,
+				utils.BackupNameLabelName: backup.Name,
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{"postgresql.cnpg.io"},
+				Resources:     []string{"clusters"},
+				Verbs:         []string{"get"},
+				ResourceNames: []string{cluster.Name},
+			},
+			{
+				APIGroups:     []string{"postgresql.cnpg.io"},
+				Resources:     []string{"backups"},
+				Verbs:         []string{"get"},
+				ResourceNames: []string{backup.Name},
+			},
+		},
+	}
+
+	if secrets = cleanupResourceList(secrets); len(secrets) > 0 {
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			Verbs:         []string{"get"},
+			ResourceNames: secrets,
+		})
+	}
+
+	return role
+}
+
+// CreateBackupVerificationJob creates the job verifying the passed backup
+// by restoring it in scratch volumes, which are discarded together with
+// the job pod
+func CreateBackupVerificationJob(cluster apiv1.Cluster, backup *apiv1.Backup) *batchv1.Job {
+	jobName := GetBackupVerificationJobName(backup.Name)
+
+	command := []string{
+		"/controller/manager",
+		"instance",
+		"verify-backup",
+		"--backup-name", backup.Name,
+	}
+	command = append(command, buildCommonInitJobFlags(cluster)...)
+
+	resources := cluster.Spec.Resources
+	if backup.Spec.Verify != nil && backup.Spec.Verify.Resources != nil {
+		resources = *backup.Spec.Verify.Resources
+	}
+
+	volumes, volumeMounts := createScratchPostgresVolumes(&cluster, jobName)
+	envConfig := CreatePodEnvConfig(cluster, jobName)
+	labels := map[string]string{
+		utils.ClusterLabelName:    cluster.Name,
+		utils.BackupNameLabelName: backup.Name,
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: backup.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](backupVerificationBackoffLimit),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						utils.ClusterLabelName:    cluster.Name,
+						utils.BackupNameLabelName: backup.Name,
+						utils.JobRoleLabelName:    string(jobRoleBackupVerification),
+					},
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						createBootstrapContainer(cluster),
+					},
+					SchedulerName: cluster.Spec.SchedulerName,
+					Containers: []corev1.Container{
+						{
+							Name:            string(jobRoleBackupVerification),
+							Image:           cluster.GetImageName(),
+							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							Env:             envConfig.EnvVars,
+							EnvFrom:         envConfig.EnvFrom,
+							Command:         command,
+							VolumeMounts:    volumeMounts,
+							Resources:       resources,
+							SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+						},
+					},
+					Volumes: volumes,
+					SecurityContext: CreatePodSecurityContext(
+						cluster.GetSeccompProfile(),
+						cluster.GetPostgresUID(),
+						cluster.GetPostgresGID()),
+					Tolerations:        cluster.Spec.Affinity.Tolerations,
+					ServiceAccountName: jobName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
+					PriorityClassName:  cluster.Spec.PriorityClassName,
+				},
+			},
+		},
+	}
+
+	utils.SetAsOwnedBy(&job.ObjectMeta, backup.ObjectMeta, metav1.TypeMeta{
+		Kind:       apiv1.BackupKind,
+		APIVersion: apiv1.GroupVersion.String(),
+	})
+	addManagerLoggingOptions(cluster, &job.Spec.Template.Spec.Containers[0])
+	annotateAppArmor(&cluster, &job.Spec.Template.ObjectMeta, &job.Spec.Template.Spec)
+
+	if backup.Status.EndpointCA != nil && backup.Status.EndpointCA.Name != "" && backup.Status.EndpointCA.Key != "" {
+		AddBarmanEndpointCAToPodSpec(&job.Spec.Template.Spec, backup.Status.EndpointCA, backup.Status.BarmanCredentials)
+	}
+
+	return job
+}
+
+// createScratchPostgresVolumes creates the volumes of a PostgreSQL
+// instance, and their mounts, replacing every persistent volume claim with
+// an empty dir as large as the volume it replaces. The secrets containing
+// the credentials of the cluster are not mounted
+func createScratchPostgresVolumes(
+	cluster *apiv1.Cluster,
+	podName string,
+) ([]corev1.Volume, []corev1.VolumeMount) {
+	sizeLimits := map[string]*resource.Quantity{
+		"pgdata": cluster.Spec.StorageConfiguration.GetSizeOrNil(),
+		"pg-wal": cluster.Spec.WalStorage.GetSizeOrNil(),
+	}
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		sizeLimits[VolumeMountNameForTablespace(tablespace.Name)] = tablespace.Storage.GetSizeOrNil()
+	}
+
+	var volumes []corev1.Volume
+	for _, volume := range createPostgresVolumes(cluster, podName) {
+		if slices.Contains(backupVerificationExcludedVolumes, volume.Name) {
+			continue
+		}
+		if volume.PersistentVolumeClaim != nil {
+			volume.VolumeSource = corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: sizeLimits[volume.Name],
+				},
+			}
+		}
+		volumes = append(volumes, volume)
+	}
+
+	var volumeMounts []corev1.VolumeMount
+	for _, volumeMount := range createPostgresVolumeMounts(*cluster) {
+		if !slices.Contains(backupVerificationExcludedVolumes, volumeMount.Name) {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+	}
+
+	return volumes, volumeMounts
+}
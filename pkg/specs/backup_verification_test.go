@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup verification job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi"},
+			WalStorage:           &apiv1.StorageConfiguration{Size: "1Gi"},
+		},
+	}
+	backup := &apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: "default", UID: "uid"},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+			Verify:  &apiv1.BackupVerificationConfiguration{},
+		},
+	}
+
+	It("restores the backup in scratch volumes", func() {
+		job := CreateBackupVerificationJob(cluster, backup)
+		Expect(job.Name).To(Equal("backup-example-verification"))
+		Expect(job.Labels[utils.BackupNameLabelName]).To(Equal("backup-example"))
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.OwnerReferences[0].Kind).To(Equal(apiv1.BackupKind))
+
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements(
+			"verify-backup", "--backup-name", "backup-example", "--pg-wal"))
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			Expect(volume.PersistentVolumeClaim).To(BeNil())
+		}
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(And(
+			HaveField("Name", "pgdata"),
+			HaveField("EmptyDir.SizeLimit.String()", "1Gi"),
+		)))
+	})
+
+	It("doesn't use the credentials of the cluster", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.EnableSuperuserAccess = ptr.To(true)
+		Expect(createPostgresVolumes(cluster, "pod")).To(ContainElement(HaveField("Name", "superuser-secret")))
+
+		job := CreateBackupVerificationJob(*cluster, backup)
+		Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal("backup-example-verification"))
+		Expect(job.Spec.Template.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "superuser-secret")))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).ToNot(
+			ContainElement(HaveField("Name", "superuser-secret")))
+	})
+
+	It("can only read the resources needed to restore the backup", func() {
+		backup := backup.DeepCopy()
+		backup.Status.BarmanCredentials.AWS = &apiv1.S3Credentials{
+			AccessKeyIDReference:     &apiv1.SecretKeySelector{LocalObjectReference: apiv1.LocalObjectReference{Name: "aws"}},
+			SecretAccessKeyReference: &apiv1.SecretKeySelector{LocalObjectReference: apiv1.LocalObjectReference{Name: "aws"}},
+		}
+
+		role := CreateBackupVerificationRole(cluster, backup)
+		Expect(role.Name).To(Equal("backup-example-verification"))
+		Expect(role.Rules).To(HaveLen(3))
+		Expect(role.Rules[2].Resources).To(Equal([]string{"secrets"}))
+		Expect(role.Rules[2].ResourceNames).To(Equal([]string{"aws"}))
+		for _, rule := range role.Rules {
+			Expect(rule.Verbs).To(Equal([]string{"get"}))
+		}
+	})
+})
@@ -0,0 +1,297 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// DefaultFioImage is the image used to run fio when the
+	// benchmark doesn't specify one
+	DefaultFioImage = "wallnerryan/fiotools-aio:latest"
+
+	// benchmarkResultsPath is where the output of the benchmark tool
+	// is saved before being copied into the termination message
+	benchmarkResultsPath = "/results"
+
+	// benchmarkDataPath is where the volume used by fio is mounted
+	benchmarkDataPath = "/data"
+
+	// benchmarkFioUser is the user running fio
+	benchmarkFioUser = int64(10001)
+
+	// benchmarkScript runs the command passed as arguments, saving the
+	// last 4kB of its output in the termination message of the container,
+	// where the operator collects the results from
+	benchmarkScript = `"$@" > ` + benchmarkResultsPath + `/output
+rc=$?
+cat ` + benchmarkResultsPath + `/output
+tail -c 4096 ` + benchmarkResultsPath + `/output > /dev/termination-log
+exit $rc`
+)
+
+// defaultFioArgs are the arguments passed to fio when the benchmark
+// doesn't specify any
+var defaultFioArgs = []string{
+	"--name=benchmark",
+	"--direct=1",
+	"--ioengine=libaio",
+	"--bs=8k",
+	"--size=1G",
+	"--rw=randrw",
+	"--rwmixread=70",
+	"--iodepth=32",
+	"--time_based",
+	"--runtime=60",
+	"--end_fsync=1",
+	"--group_reporting",
+}
+
+// GetBenchmarkJobName returns the name of the job running the passed
+// benchmark, which is also the name of the volume used by fio
+func GetBenchmarkJobName(benchmarkName string) string {
+	return benchmarkName + "-benchmark"
+}
+
+// CreateBenchmarkPVC creates the volume used by fio in the passed benchmark
+func CreateBenchmarkPVC(benchmark *apiv1.Benchmark) *corev1.PersistentVolumeClaim {
+	size := benchmark.Spec.Fio.Size
+	if size.IsZero() {
+		size = resource.MustParse("2Gi")
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetBenchmarkJobName(benchmark.Name),
+			Namespace: benchmark.Namespace,
+			Labels: map[string]string{
+				utils.BenchmarkNameLabelName: benchmark.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: benchmark.Spec.Fio.StorageClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	setAsOwnedByBenchmark(&pvc.ObjectMeta, benchmark)
+
+	return pvc
+}
+
+// CreatePgbenchJob creates the job running pgbench against the passed
+// cluster, as requested by the passed benchmark
+func CreatePgbenchJob(cluster apiv1.Cluster, benchmark *apiv1.Benchmark) *batchv1.Job {
+	configuration := benchmark.Spec.Pgbench
+	env := createPgbenchEnvVars(cluster, configuration)
+
+	job := createBenchmarkJob(
+		benchmark,
+		cluster.GetImageName(),
+		append([]string{"pgbench"}, configuration.Args...),
+		env,
+	)
+	podSpec := &job.Spec.Template.Spec
+	podSpec.SchedulerName = cluster.Spec.SchedulerName
+	podSpec.SecurityContext = CreatePodSecurityContext(
+		cluster.GetSeccompProfile(),
+		cluster.GetPostgresUID(),
+		cluster.GetPostgresGID())
+	podSpec.Containers[0].ImagePullPolicy = cluster.Spec.ImagePullPolicy
+	podSpec.Containers[0].SecurityContext = CreateContainerSecurityContext(cluster.GetSeccompProfile())
+
+	if configuration.InitializationScale > 0 {
+		podSpec.InitContainers = []corev1.Container{
+			{
+				Name:            "pgbench-init",
+				Image:           cluster.GetImageName(),
+				ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+				Env:             env,
+				Command: []string{
+					"pgbench",
+					"--initialize",
+					"--scale", strconv.Itoa(int(configuration.InitializationScale)),
+				},
+				Resources:       benchmark.Spec.Resources,
+				SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+			},
+		}
+	}
+
+	return job
+}
+
+// CreateFioJob creates the job running fio on the volume provisioned
+// for the passed benchmark
+func CreateFioJob(benchmark *apiv1.Benchmark) *batchv1.Job {
+	configuration := benchmark.Spec.Fio
+
+	image := configuration.Image
+	if image == "" {
+		image = DefaultFioImage
+	}
+
+	args := configuration.Args
+	if len(args) == 0 {
+		args = defaultFioArgs
+	}
+	command := []string{"fio"}
+	command = append(command, args...)
+	command = append(command,
+		"--directory="+benchmarkDataPath,
+		"--output-format=terse",
+		"--terse-version=3",
+	)
+
+	job := createBenchmarkJob(benchmark, image, command, nil)
+	podSpec := &job.Spec.Template.Spec
+	podSpec.SecurityContext = CreatePodSecurityContext(
+		&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		benchmarkFioUser,
+		benchmarkFioUser)
+	podSpec.Containers[0].SecurityContext = CreateContainerSecurityContext(
+		&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      "data",
+		MountPath: benchmarkDataPath,
+	})
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "data",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: GetBenchmarkJobName(benchmark.Name),
+			},
+		},
+	})
+
+	return job
+}
+
+// createBenchmarkJob creates a job running the passed command through
+// the benchmark script
+func createBenchmarkJob(
+	benchmark *apiv1.Benchmark,
+	image string,
+	command []string,
+	env []corev1.EnvVar,
+) *batchv1.Job {
+	labels := map[string]string{
+		utils.BenchmarkNameLabelName: benchmark.Name,
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetBenchmarkJobName(benchmark.Name),
+			Namespace: benchmark.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			// Running the benchmark again would just report the
+			// results of a different workload
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "benchmark",
+							Image: image,
+							Env:   env,
+							Command: append(
+								[]string{"sh", "-c", benchmarkScript, "benchmark"},
+								command...,
+							),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "results",
+									MountPath: benchmarkResultsPath,
+								},
+							},
+							Resources: benchmark.Spec.Resources,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "results",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+					RestartPolicy: corev1.RestartPolicyNever,
+					NodeSelector:  benchmark.Spec.NodeSelector,
+				},
+			},
+		},
+	}
+	setAsOwnedByBenchmark(&job.ObjectMeta, benchmark)
+
+	return job
+}
+
+// createPgbenchEnvVars creates the environment variables pgbench uses to
+// connect to the read-write service of the passed cluster
+func createPgbenchEnvVars(cluster apiv1.Cluster, configuration *apiv1.PgbenchConfiguration) []corev1.EnvVar {
+	database := configuration.Database
+	if database == "" {
+		database = "app"
+	}
+
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: cluster.GetApplicationSecretName(),
+				},
+				Key: key,
+			},
+		}
+	}
+
+	return []corev1.EnvVar{
+		{Name: "PGHOST", Value: cluster.GetServiceReadWriteName()},
+		{Name: "PGPORT", Value: strconv.Itoa(postgres.ServerPort)},
+		{Name: "PGDATABASE", Value: database},
+		{Name: "PGUSER", ValueFrom: secretKeyRef("username")},
+		{Name: "PGPASSWORD", ValueFrom: secretKeyRef("password")},
+	}
+}
+
+// setAsOwnedByBenchmark sets the passed object as owned by the benchmark
+func setAsOwnedByBenchmark(meta *metav1.ObjectMeta, benchmark *apiv1.Benchmark) {
+	utils.SetAsOwnedBy(meta, benchmark.ObjectMeta, metav1.TypeMeta{
+		Kind:       apiv1.BenchmarkKind,
+		APIVersion: apiv1.GroupVersion.String(),
+	})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Benchmark jobs", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+	}
+
+	It("runs pgbench against the read-write service of the cluster", func() {
+		benchmark := &apiv1.Benchmark{
+			ObjectMeta: metav1.ObjectMeta{Name: "tpcb", Namespace: "default", UID: "uid"},
+			Spec: apiv1.BenchmarkSpec{
+				Pgbench: &apiv1.PgbenchConfiguration{
+					Cluster:             apiv1.LocalObjectReference{Name: "cluster-example"},
+					InitializationScale: 10,
+					Args:                []string{"--time", "30"},
+				},
+				NodeSelector: map[string]string{"workload": "benchmark"},
+			},
+		}
+
+		job := CreatePgbenchJob(cluster, benchmark)
+		Expect(job.Name).To(Equal("tpcb-benchmark"))
+		Expect(job.Labels[utils.BenchmarkNameLabelName]).To(Equal("tpcb"))
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.OwnerReferences[0].Kind).To(Equal(apiv1.BenchmarkKind))
+
+		podSpec := job.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("workload", "benchmark"))
+		Expect(podSpec.Containers[0].Command).To(HaveLen(7))
+		Expect(podSpec.Containers[0].Command[4:]).To(Equal([]string{"pgbench", "--time", "30"}))
+		Expect(podSpec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "PGHOST", Value: "cluster-example-rw"},
+			corev1.EnvVar{Name: "PGDATABASE", Value: "app"},
+		))
+		Expect(podSpec.InitContainers).To(HaveLen(1))
+		Expect(podSpec.InitContainers[0].Command).To(Equal([]string{"pgbench", "--initialize", "--scale", "10"}))
+	})
+
+	It("runs fio on a dedicated volume", func() {
+		benchmark := &apiv1.Benchmark{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: "default", UID: "uid"},
+			Spec: apiv1.BenchmarkSpec{
+				Fio: &apiv1.FioConfiguration{
+					StorageClass: ptr.To("fast"),
+				},
+			},
+		}
+
+		pvc := CreateBenchmarkPVC(benchmark)
+		Expect(pvc.Name).To(Equal("storage-benchmark"))
+		Expect(pvc.Spec.StorageClassName).To(Equal(ptr.To("fast")))
+		Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("2Gi")))
+
+		job := CreateFioJob(benchmark)
+		podSpec := job.Spec.Template.Spec
+		Expect(podSpec.Containers[0].Image).To(Equal(DefaultFioImage))
+		Expect(podSpec.Containers[0].Command).To(ContainElements(
+			"fio", "--rw=randrw", "--directory=/data", "--output-format=terse", "--terse-version=3"))
+		Expect(podSpec.Volumes).To(ContainElement(HaveField("PersistentVolumeClaim.ClaimName", "storage-benchmark")))
+	})
+})
@@ -0,0 +1,337 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+const (
+	// changeDataCaptureDataPath is where the bridge stores its offsets
+	// when they are not stored in Kafka. The volume needs to be persistent:
+	// without the stored offsets, the bridge would start again following
+	// the snapshot mode, skipping or publishing again some changes
+	changeDataCaptureDataPath = "/debezium/data"
+
+	// changeDataCaptureKafkaOffsetStore is the Kafka Connect class storing
+	// the offsets of the bridge in a Kafka topic
+	changeDataCaptureKafkaOffsetStore = "org.apache.kafka.connect.storage.KafkaOffsetBackingStore"
+
+	// changeDataCaptureUser is the user running Debezium Server
+	changeDataCaptureUser = int64(185)
+
+	// changeDataCaptureStringSerializer is the serializer used for the
+	// keys and the values of the messages published to Kafka
+	changeDataCaptureStringSerializer = "org.apache.kafka.common.serialization.StringSerializer"
+)
+
+// GetChangeDataCaptureDeploymentName returns the name of the deployment
+// running the bridge of the passed ChangeDataCapture
+func GetChangeDataCaptureDeploymentName(cdcName string) string {
+	return cdcName + "-cdc"
+}
+
+// GetChangeDataCaptureOffsetsPVCName returns the name of the volume where
+// the bridge of the passed ChangeDataCapture stores its offsets
+func GetChangeDataCaptureOffsetsPVCName(cdcName string) string {
+	return cdcName + "-cdc-offsets"
+}
+
+// ChangeDataCaptureNeedsOffsetsPVC checks whether the bridge of the passed
+// ChangeDataCapture stores its offsets in a volume, which happens for
+// every sink but Kafka, where the offsets are stored in a topic
+func ChangeDataCaptureNeedsOffsetsPVC(cdc *apiv1.ChangeDataCapture) bool {
+	return cdc.Spec.Sink.Kafka == nil
+}
+
+// CreateChangeDataCaptureOffsetsPVC creates the volume where the bridge of
+// the passed ChangeDataCapture stores its offsets
+func CreateChangeDataCaptureOffsetsPVC(cdc *apiv1.ChangeDataCapture) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetChangeDataCaptureOffsetsPVCName(cdc.Name),
+			Namespace: cdc.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName:               cdc.Spec.ClusterRef.Name,
+				utils.ChangeDataCaptureNameLabelName: cdc.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: cdc.GetOffsetStorageClass(),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: cdc.GetOffsetStorageSize(),
+				},
+			},
+		},
+	}
+	utils.SetAsOwnedBy(&pvc.ObjectMeta, cdc.ObjectMeta, metav1.TypeMeta{
+		Kind:       apiv1.ChangeDataCaptureKind,
+		APIVersion: apiv1.GroupVersion.String(),
+	})
+
+	return pvc
+}
+
+// CreateChangeDataCaptureDeployment creates the deployment running the bridge
+// that consumes the replication slot of the passed ChangeDataCapture from the
+// primary of the passed cluster, publishing the changes to the configured sink
+func CreateChangeDataCaptureDeployment(
+	cdc *apiv1.ChangeDataCapture,
+	cluster *apiv1.Cluster,
+) (*appsv1.Deployment, error) {
+	labels := map[string]string{
+		utils.ClusterLabelName:               cluster.Name,
+		utils.ChangeDataCaptureNameLabelName: cdc.Name,
+	}
+	seccompProfile := &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "tmp",
+			MountPath: "/tmp",
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "tmp",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	if ChangeDataCaptureNeedsOffsetsPVC(cdc) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "data",
+			MountPath: changeDataCaptureDataPath,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: GetChangeDataCaptureOffsetsPVCName(cdc.Name),
+				},
+			},
+		})
+	}
+
+	podTemplate := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: labels,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:            "bridge",
+					Image:           cdc.GetImage(),
+					Env:             createChangeDataCaptureEnvVars(cdc, cluster),
+					VolumeMounts:    volumeMounts,
+					Resources:       cdc.Spec.Resources,
+					SecurityContext: CreateContainerSecurityContext(seccompProfile),
+				},
+			},
+			Volumes: volumes,
+			SecurityContext: CreatePodSecurityContext(
+				seccompProfile,
+				changeDataCaptureUser,
+				changeDataCaptureUser),
+			NodeSelector: cdc.Spec.NodeSelector,
+		},
+	}
+
+	templateHash, err := hash.ComputeHash(podTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetChangeDataCaptureDeploymentName(cdc.Name),
+			Namespace: cdc.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				utils.CNPGHashAnnotationName: templateHash,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			// The replication slot can be consumed by only one
+			// bridge at a time
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.ChangeDataCaptureNameLabelName: cdc.Name,
+				},
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: podTemplate,
+		},
+	}
+	utils.SetAsOwnedBy(&deployment.ObjectMeta, cdc.ObjectMeta, metav1.TypeMeta{
+		Kind:       apiv1.ChangeDataCaptureKind,
+		APIVersion: apiv1.GroupVersion.String(),
+	})
+
+	return deployment, nil
+}
+
+// createChangeDataCaptureEnvVars creates the environment variables configuring
+// Debezium Server, which maps them to the corresponding properties
+func createChangeDataCaptureEnvVars(cdc *apiv1.ChangeDataCapture, cluster *apiv1.Cluster) []corev1.EnvVar {
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: cdc.Spec.Credentials.Name,
+				},
+				Key: key,
+			},
+		}
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "DEBEZIUM_SOURCE_CONNECTOR_CLASS", Value: "io.debezium.connector.postgresql.PostgresConnector"},
+		{Name: "DEBEZIUM_SOURCE_DATABASE_HOSTNAME", Value: cluster.GetServiceReadWriteName()},
+		{Name: "DEBEZIUM_SOURCE_DATABASE_PORT", Value: strconv.Itoa(postgres.ServerPort)},
+		{Name: "DEBEZIUM_SOURCE_DATABASE_USER", ValueFrom: secretKeyRef(corev1.BasicAuthUsernameKey)},
+		{Name: "DEBEZIUM_SOURCE_DATABASE_PASSWORD", ValueFrom: secretKeyRef(corev1.BasicAuthPasswordKey)},
+		{Name: "DEBEZIUM_SOURCE_DATABASE_DBNAME", Value: cdc.Spec.DBName},
+		{Name: "DEBEZIUM_SOURCE_DATABASE_SSLMODE", Value: "require"},
+		{Name: "DEBEZIUM_SOURCE_PLUGIN_NAME", Value: "pgoutput"},
+		{Name: "DEBEZIUM_SOURCE_SLOT_NAME", Value: cdc.Spec.SlotName},
+		// The replication slot is managed by the instance manager
+		{Name: "DEBEZIUM_SOURCE_SLOT_DROP_ON_STOP", Value: "false"},
+		{Name: "DEBEZIUM_SOURCE_PUBLICATION_NAME", Value: cdc.Spec.PublicationName},
+		{Name: "DEBEZIUM_SOURCE_PUBLICATION_AUTOCREATE_MODE", Value: "disabled"},
+		{Name: "DEBEZIUM_SOURCE_TOPIC_PREFIX", Value: cdc.GetTopicPrefix()},
+		{Name: "DEBEZIUM_SOURCE_SNAPSHOT_MODE", Value: string(cdc.GetSnapshotMode())},
+	}
+
+	switch {
+	case cdc.Spec.Sink.Kafka != nil:
+		kafka := cdc.Spec.Sink.Kafka
+		env = append(env,
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_TYPE", Value: "kafka"},
+			corev1.EnvVar{
+				Name:  "DEBEZIUM_SINK_KAFKA_PRODUCER_BOOTSTRAP_SERVERS",
+				Value: strings.Join(kafka.BootstrapServers, ","),
+			},
+			corev1.EnvVar{
+				Name:  "DEBEZIUM_SINK_KAFKA_PRODUCER_KEY_SERIALIZER",
+				Value: changeDataCaptureStringSerializer,
+			},
+			corev1.EnvVar{
+				Name:  "DEBEZIUM_SINK_KAFKA_PRODUCER_VALUE_SERIALIZER",
+				Value: changeDataCaptureStringSerializer,
+			},
+		)
+
+		// The offsets are stored in a topic of the same Kafka cluster,
+		// letting the replication factor default to the one of the brokers
+		env = append(env,
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_OFFSET_STORAGE", Value: changeDataCaptureKafkaOffsetStore},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_OFFSET_STORAGE_TOPIC", Value: cdc.GetKafkaOffsetsTopic()},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_OFFSET_STORAGE_PARTITIONS", Value: "1"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_OFFSET_STORAGE_REPLICATION_FACTOR", Value: "-1"},
+			corev1.EnvVar{
+				Name:  "DEBEZIUM_SOURCE_BOOTSTRAP_SERVERS",
+				Value: strings.Join(kafka.BootstrapServers, ","),
+			},
+		)
+
+		// Properties are sorted by name to produce a stable output
+		keys := make([]string, 0, len(kafka.Properties))
+		for key := range kafka.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			env = append(env, corev1.EnvVar{
+				Name:  "DEBEZIUM_SINK_KAFKA_PRODUCER_" + toEnvVarName(key),
+				Value: kafka.Properties[key],
+			})
+			// The connection to the topic storing the offsets needs
+			// the same security settings of the producer
+			if isKafkaSecurityProperty(key) {
+				env = append(env, corev1.EnvVar{
+					Name:  "DEBEZIUM_SOURCE_" + toEnvVarName(key),
+					Value: kafka.Properties[key],
+				})
+			}
+		}
+
+	case cdc.Spec.Sink.NATS != nil:
+		nats := cdc.Spec.Sink.NATS
+		env = append(env,
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_TYPE", Value: "nats-jetstream"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_NATS_JETSTREAM_URL", Value: nats.URL},
+			corev1.EnvVar{
+				Name:  "DEBEZIUM_SINK_NATS_JETSTREAM_CREATE_STREAM",
+				Value: strconv.FormatBool(nats.CreateStream),
+			},
+			corev1.EnvVar{
+				Name:  "DEBEZIUM_SINK_NATS_JETSTREAM_SUBJECTS",
+				Value: fmt.Sprintf("%s.>", cdc.GetTopicPrefix()),
+			},
+			corev1.EnvVar{
+				Name:  "DEBEZIUM_SOURCE_OFFSET_STORAGE_FILE_FILENAME",
+				Value: changeDataCaptureDataPath + "/offsets.dat",
+			},
+		)
+	}
+
+	return env
+}
+
+// isKafkaSecurityProperty checks whether the passed Kafka client property
+// configures how the client authenticates and encrypts the connections
+func isKafkaSecurityProperty(property string) bool {
+	return strings.HasPrefix(property, "security.") ||
+		strings.HasPrefix(property, "sasl.") ||
+		strings.HasPrefix(property, "ssl.")
+}
+
+// toEnvVarName converts the name of a property to the name of the environment
+// variable setting it, replacing the characters that are not letters or digits
+// with underscores
+func toEnvVarName(property string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, property)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Change data capture deployment", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+	}
+
+	newChangeDataCapture := func(sink apiv1.ChangeDataCaptureSink) *apiv1.ChangeDataCapture {
+		return &apiv1.ChangeDataCapture{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default", UID: "uid"},
+			Spec: apiv1.ChangeDataCaptureSpec{
+				ClusterRef:      apiv1.LocalObjectReference{Name: "cluster-example"},
+				DBName:          "app",
+				SlotName:        "orders_cdc",
+				PublicationName: "orders_pub",
+				Credentials:     apiv1.LocalObjectReference{Name: "cdc-user"},
+				Sink:            sink,
+			},
+		}
+	}
+
+	It("runs a single bridge consuming the replication slot from the primary", func() {
+		cdc := newChangeDataCapture(apiv1.ChangeDataCaptureSink{
+			Kafka: &apiv1.ChangeDataCaptureKafkaSink{
+				BootstrapServers: []string{"kafka-0:9092", "kafka-1:9092"},
+				Properties: map[string]string{
+					"compression.type":  "zstd",
+					"acks":              "all",
+					"security.protocol": "SSL",
+				},
+			},
+		})
+
+		deployment, err := CreateChangeDataCaptureDeployment(cdc, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Name).To(Equal("orders-cdc"))
+		Expect(deployment.Labels[utils.ChangeDataCaptureNameLabelName]).To(Equal("orders"))
+		Expect(deployment.Annotations).To(HaveKey(utils.CNPGHashAnnotationName))
+		Expect(deployment.OwnerReferences).To(HaveLen(1))
+		Expect(deployment.OwnerReferences[0].Kind).To(Equal(apiv1.ChangeDataCaptureKind))
+		Expect(*deployment.Spec.Replicas).To(BeEquivalentTo(1))
+		Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal(apiv1.DefaultChangeDataCaptureImage))
+		Expect(container.Env).To(ContainElements(
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_DATABASE_HOSTNAME", Value: "cluster-example-rw"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_DATABASE_DBNAME", Value: "app"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_SLOT_NAME", Value: "orders_cdc"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_SLOT_DROP_ON_STOP", Value: "false"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_PUBLICATION_NAME", Value: "orders_pub"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_TOPIC_PREFIX", Value: "cluster-example"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_SNAPSHOT_MODE", Value: "no_data"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_TYPE", Value: "kafka"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_KAFKA_PRODUCER_BOOTSTRAP_SERVERS", Value: "kafka-0:9092,kafka-1:9092"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_KAFKA_PRODUCER_ACKS", Value: "all"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_KAFKA_PRODUCER_COMPRESSION_TYPE", Value: "zstd"},
+		))
+	})
+
+	It("stores the offsets in a Kafka topic when publishing to Kafka", func() {
+		cdc := newChangeDataCapture(apiv1.ChangeDataCaptureSink{
+			Kafka: &apiv1.ChangeDataCaptureKafkaSink{
+				BootstrapServers: []string{"kafka-0:9092"},
+				Properties:       map[string]string{"acks": "all", "security.protocol": "SSL"},
+			},
+		})
+		Expect(ChangeDataCaptureNeedsOffsetsPVC(cdc)).To(BeFalse())
+
+		deployment, err := CreateChangeDataCaptureDeployment(cdc, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElements(
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_OFFSET_STORAGE", Value: changeDataCaptureKafkaOffsetStore},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_OFFSET_STORAGE_TOPIC", Value: "cluster-example-orders-offsets"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_BOOTSTRAP_SERVERS", Value: "kafka-0:9092"},
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_SECURITY_PROTOCOL", Value: "SSL"},
+		))
+		Expect(env).ToNot(ContainElement(corev1.EnvVar{Name: "DEBEZIUM_SOURCE_ACKS", Value: "all"}))
+		Expect(deployment.Spec.Template.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "data")))
+	})
+
+	It("stores the offsets in a persistent volume when publishing to NATS", func() {
+		cdc := newChangeDataCapture(apiv1.ChangeDataCaptureSink{
+			NATS: &apiv1.ChangeDataCaptureNATSSink{
+				URL: "nats://nats:4222",
+				OffsetStorage: &apiv1.ChangeDataCaptureOffsetStorage{
+					StorageClass: ptr.To("fast"),
+				},
+			},
+		})
+		Expect(ChangeDataCaptureNeedsOffsetsPVC(cdc)).To(BeTrue())
+
+		pvc := CreateChangeDataCaptureOffsetsPVC(cdc)
+		Expect(pvc.Name).To(Equal("orders-cdc-offsets"))
+		Expect(pvc.OwnerReferences).To(HaveLen(1))
+		Expect(pvc.Spec.StorageClassName).To(HaveValue(Equal("fast")))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+
+		deployment, err := CreateChangeDataCaptureDeployment(cdc, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "orders-cdc-offsets"},
+			},
+		}))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  "DEBEZIUM_SOURCE_OFFSET_STORAGE_FILE_FILENAME",
+			Value: "/debezium/data/offsets.dat",
+		}))
+	})
+
+	It("publishes the changes to NATS JetStream", func() {
+		cdc := newChangeDataCapture(apiv1.ChangeDataCaptureSink{
+			TopicPrefix: "shop",
+			NATS:        &apiv1.ChangeDataCaptureNATSSink{URL: "nats://nats:4222", CreateStream: true},
+		})
+
+		deployment, err := CreateChangeDataCaptureDeployment(cdc, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "DEBEZIUM_SOURCE_TOPIC_PREFIX", Value: "shop"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_TYPE", Value: "nats-jetstream"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_NATS_JETSTREAM_URL", Value: "nats://nats:4222"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_NATS_JETSTREAM_CREATE_STREAM", Value: "true"},
+			corev1.EnvVar{Name: "DEBEZIUM_SINK_NATS_JETSTREAM_SUBJECTS", Value: "shop.>"},
+		))
+	})
+
+	It("changes the hash when the bridge configuration changes", func() {
+		cdc := newChangeDataCapture(apiv1.ChangeDataCaptureSink{
+			Kafka: &apiv1.ChangeDataCaptureKafkaSink{BootstrapServers: []string{"kafka-0:9092"}},
+		})
+		deployment, err := CreateChangeDataCaptureDeployment(cdc, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		cdc.Spec.SnapshotMode = apiv1.ChangeDataCaptureSnapshotInitial
+		updatedDeployment, err := CreateChangeDataCaptureDeployment(cdc, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedDeployment.Annotations[utils.CNPGHashAnnotationName]).
+			ToNot(Equal(deployment.Annotations[utils.CNPGHashAnnotationName]))
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetInstanceHostName returns the DNS name of an instance, as published
+// by the headless `-any` service
+func GetInstanceHostName(cluster apiv1.Cluster, instanceName string) string {
+	return fmt.Sprintf("%s.%s.%s.svc", instanceName, cluster.GetServiceAnyName(), cluster.Namespace)
+}
+
+// getConnectionHosts returns the list of the instances, in the
+// `host:port` form, starting with the current primary. Listing the
+// primary first makes the drivers find it at their first attempt
+func getConnectionHosts(cluster apiv1.Cluster) []string {
+	instances := slices.Clone(cluster.Status.InstanceNames)
+	slices.Sort(instances)
+	slices.SortStableFunc(instances, func(a, b string) int {
+		switch {
+		case a == cluster.Status.CurrentPrimary:
+			return -1
+		case b == cluster.Status.CurrentPrimary:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	hosts := make([]string, len(instances))
+	for idx, instance := range instances {
+		hosts[idx] = fmt.Sprintf("%s:%d", GetInstanceHostName(cluster, instance), postgres.ServerPort)
+	}
+
+	return hosts
+}
+
+// CreateConnectionConfigMap creates the ConfigMap with the multi-host
+// connection strings of the cluster. The strings don't contain any
+// credential, which can be found in the application secret
+func CreateConnectionConfigMap(cluster apiv1.Cluster) *corev1.ConfigMap {
+	hosts := strings.Join(getConnectionHosts(cluster), ",")
+	dbname := cluster.GetApplicationDatabaseName()
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetConnectionConfigMapName(),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+			},
+		},
+		Data: map[string]string{
+			"hosts":  hosts,
+			"dbname": dbname,
+			"uri": fmt.Sprintf(
+				"postgresql://%s/%s?target_session_attrs=read-write", hosts, dbname),
+			"ro-uri": fmt.Sprintf(
+				"postgresql://%s/%s?target_session_attrs=prefer-standby", hosts, dbname),
+			"jdbc-uri": fmt.Sprintf(
+				"jdbc:postgresql://%s/%s?targetServerType=primary", hosts, dbname),
+			"jdbc-ro-uri": fmt.Sprintf(
+				"jdbc:postgresql://%s/%s?targetServerType=preferSecondary", hosts, dbname),
+		},
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("multi-host connection strings", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Database: "app",
+				},
+			},
+		},
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-3",
+			InstanceNames:  []string{"cluster-example-2", "cluster-example-3", "cluster-example-1"},
+		},
+	}
+
+	It("resolves the instances through the any service", func() {
+		Expect(GetInstanceHostName(cluster, "cluster-example-1")).
+			To(Equal("cluster-example-1.cluster-example-any.default.svc"))
+	})
+
+	It("lists the primary first, followed by the other instances in order", func() {
+		Expect(getConnectionHosts(cluster)).To(Equal([]string{
+			"cluster-example-3.cluster-example-any.default.svc:5432",
+			"cluster-example-1.cluster-example-any.default.svc:5432",
+			"cluster-example-2.cluster-example-any.default.svc:5432",
+		}))
+	})
+
+	It("creates the ConfigMap with the libpq and JDBC connection strings", func() {
+		configMap := CreateConnectionConfigMap(cluster)
+		hosts := "cluster-example-3.cluster-example-any.default.svc:5432," +
+			"cluster-example-1.cluster-example-any.default.svc:5432," +
+			"cluster-example-2.cluster-example-any.default.svc:5432"
+
+		Expect(configMap.Name).To(Equal("cluster-example-connection"))
+		Expect(configMap.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+		Expect(configMap.Data).To(Equal(map[string]string{
+			"hosts":       hosts,
+			"dbname":      "app",
+			"uri":         "postgresql://" + hosts + "/app?target_session_attrs=read-write",
+			"ro-uri":      "postgresql://" + hosts + "/app?target_session_attrs=prefer-standby",
+			"jdbc-uri":    "jdbc:postgresql://" + hosts + "/app?targetServerType=primary",
+			"jdbc-ro-uri": "jdbc:postgresql://" + hosts + "/app?targetServerType=preferSecondary",
+		}))
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetExternalSecretsLabels gets the labels identifying the Role and the
+// RoleBinding allowing the passed cluster to read the secrets granted in
+// another namespace
+func GetExternalSecretsLabels(cluster apiv1.Cluster) map[string]string {
+	return map[string]string{
+		utils.ClusterLabelName:          cluster.Name,
+		utils.ClusterNamespaceLabelName: cluster.Namespace,
+	}
+}
+
+// CreateExternalSecretsRole creates the Role allowing the instance manager
+// of the passed cluster to read the secrets granted to it in another namespace
+func CreateExternalSecretsRole(cluster apiv1.Cluster, namespace string, secretNames []string) rbacv1.Role {
+	return rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      getExternalSecretsRoleName(cluster),
+			Labels:    GetExternalSecretsLabels(cluster),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"secrets",
+				},
+				Verbs: []string{
+					"get",
+				},
+				ResourceNames: cleanupResourceList(secretNames),
+			},
+		},
+	}
+}
+
+// CreateExternalSecretsRoleBinding binds the Role created by
+// CreateExternalSecretsRole to the ServiceAccount used by the Pods
+// of the passed cluster
+func CreateExternalSecretsRoleBinding(cluster apiv1.Cluster, namespace string) rbacv1.RoleBinding {
+	return rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      getExternalSecretsRoleName(cluster),
+			Labels:    GetExternalSecretsLabels(cluster),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				APIGroup:  "",
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     getExternalSecretsRoleName(cluster),
+		},
+	}
+}
+
+// getExternalSecretsRoleName gets the name of the Role and of the
+// RoleBinding created in the namespaces the cluster takes secrets from
+func getExternalSecretsRoleName(cluster apiv1.Cluster) string {
+	return fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+}
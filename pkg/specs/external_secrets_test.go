@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("External secrets access", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "dr",
+		},
+	}
+
+	It("creates a Role reading only the granted secrets", func() {
+		role := CreateExternalSecretsRole(cluster, "production",
+			[]string{"cluster-example-replication", "cluster-example-ca", "cluster-example-ca"})
+		Expect(role.Namespace).To(Equal("production"))
+		Expect(role.Name).To(Equal("dr-cluster-example"))
+		Expect(role.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+		Expect(role.Labels).To(HaveKeyWithValue(utils.ClusterNamespaceLabelName, "dr"))
+		Expect(role.Rules).To(HaveLen(1))
+		Expect(role.Rules[0].Resources).To(ConsistOf("secrets"))
+		Expect(role.Rules[0].Verbs).To(ConsistOf("get"))
+		Expect(role.Rules[0].ResourceNames).To(Equal(
+			[]string{"cluster-example-ca", "cluster-example-replication"}))
+	})
+
+	It("binds the Role to the ServiceAccount of the cluster", func() {
+		roleBinding := CreateExternalSecretsRoleBinding(cluster, "production")
+		Expect(roleBinding.Namespace).To(Equal("production"))
+		Expect(roleBinding.RoleRef.Name).To(Equal("dr-cluster-example"))
+		Expect(roleBinding.Subjects).To(HaveLen(1))
+		Expect(roleBinding.Subjects[0].Kind).To(Equal("ServiceAccount"))
+		Expect(roleBinding.Subjects[0].Name).To(Equal("cluster-example"))
+		Expect(roleBinding.Subjects[0].Namespace).To(Equal("dr"))
+	})
+})
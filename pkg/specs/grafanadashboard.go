@@ -0,0 +1,318 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// grafanaDashboard is the subset of the JSON model of a Grafana
+// dashboard used by the dashboards generated by the operator
+type grafanaDashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Refresh       string            `json:"refresh"`
+	Time          grafanaTimeRange  `json:"time"`
+	Templating    grafanaTemplating `json:"templating"`
+	Panels        []grafanaPanel    `json:"panels"`
+	Editable      bool              `json:"editable"`
+}
+
+type grafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type grafanaTemplating struct {
+	List []grafanaVariable `json:"list"`
+}
+
+type grafanaVariable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type grafanaPanel struct {
+	ID          int                `json:"id"`
+	Title       string             `json:"title"`
+	Type        string             `json:"type"`
+	Datasource  grafanaDatasource  `json:"datasource"`
+	GridPos     grafanaGridPos     `json:"gridPos"`
+	Targets     []grafanaTarget    `json:"targets"`
+	FieldConfig grafanaFieldConfig `json:"fieldConfig"`
+}
+
+type grafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type grafanaGridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type grafanaTarget struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+}
+
+type grafanaFieldConfig struct {
+	Defaults grafanaFieldDefaults `json:"defaults"`
+}
+
+type grafanaFieldDefaults struct {
+	Unit       string             `json:"unit,omitempty"`
+	Min        *float64           `json:"min,omitempty"`
+	Thresholds *grafanaThresholds `json:"thresholds,omitempty"`
+	Custom     map[string]any     `json:"custom,omitempty"`
+}
+
+type grafanaThresholds struct {
+	Mode  string                 `json:"mode"`
+	Steps []grafanaThresholdStep `json:"steps"`
+}
+
+type grafanaThresholdStep struct {
+	Color string   `json:"color"`
+	Value *float64 `json:"value"`
+}
+
+// grafanaDatasourceVariable is the name of the dashboard variable
+// selecting the Prometheus datasource
+const grafanaDatasourceVariable = "datasource"
+
+// getGrafanaDashboardUID gets a stable identifier of the dashboard of a
+// cluster, which must be unique in Grafana and at most 40 characters long
+func getGrafanaDashboardUID(cluster *apiv1.Cluster) string {
+	hash := sha256.Sum256([]byte(cluster.Namespace + "/" + cluster.Name))
+	return "cnpg-" + hex.EncodeToString(hash[:])[:32]
+}
+
+// newGrafanaThresholds creates the thresholds turning a value red when
+// it exceeds the passed limit
+func newGrafanaThresholds(limit float64) *grafanaThresholds {
+	return &grafanaThresholds{
+		Mode: "absolute",
+		Steps: []grafanaThresholdStep{
+			{Color: "green"},
+			{Color: "red", Value: &limit},
+		},
+	}
+}
+
+// createGrafanaDashboard creates the Grafana dashboard of the cluster,
+// whose thresholds match the ones of the alerting rules
+func createGrafanaDashboard(cluster *apiv1.Cluster) grafanaDashboard {
+	var rules *apiv1.PrometheusRuleConfiguration
+	if cluster.Spec.Monitoring != nil {
+		rules = cluster.Spec.Monitoring.PrometheusRule
+	}
+
+	selector := getMetricsSelector(cluster)
+	zero := 0.0
+	instances := float64(cluster.Spec.Instances)
+
+	type panelDefinition struct {
+		title        string
+		panelType    string
+		expr         string
+		legendFormat string
+		unit         string
+		thresholds   *grafanaThresholds
+	}
+	definitions := []panelDefinition{
+		{
+			title:     "Running instances",
+			panelType: "stat",
+			expr:      fmt.Sprintf(`count(cnpg_collector_up{%s} == 1) or vector(0)`, selector),
+			thresholds: &grafanaThresholds{
+				Mode: "absolute",
+				Steps: []grafanaThresholdStep{
+					{Color: "red"},
+					{Color: "green", Value: &instances},
+				},
+			},
+		},
+		{
+			title:        "Replication lag",
+			panelType:    "timeseries",
+			expr:         fmt.Sprintf(`max by (pod) (cnpg_pg_replication_lag{%s})`, selector),
+			legendFormat: "{{pod}}",
+			unit:         "s",
+			thresholds:   newGrafanaThresholds(float64(rules.GetMaxReplicationLag())),
+		},
+		{
+			title:     "Connections usage",
+			panelType: "timeseries",
+			expr: fmt.Sprintf(`sum by (pod) (cnpg_backends_total{%s}) * 100 / `+
+				`max by (pod) (cnpg_pg_settings_setting{%s,name="max_connections"})`, selector, selector),
+			legendFormat: "{{pod}}",
+			unit:         "percent",
+			thresholds:   newGrafanaThresholds(float64(rules.GetMaxConnectionsUsage())),
+		},
+		{
+			title:        "Longest transaction",
+			panelType:    "timeseries",
+			expr:         fmt.Sprintf(`max by (pod) (cnpg_backends_max_tx_duration_seconds{%s})`, selector),
+			legendFormat: "{{pod}}",
+			unit:         "s",
+			thresholds:   newGrafanaThresholds(float64(rules.GetMaxTransactionDuration())),
+		},
+		{
+			title:     "Storage usage",
+			panelType: "timeseries",
+			expr: fmt.Sprintf(`(1 - cnpg_collector_disk_available_bytes{%s} / cnpg_collector_disk_total_bytes{%s}) * 100`,
+				selector, selector),
+			legendFormat: "{{pod}} {{volume}}",
+			unit:         "percent",
+			thresholds:   newGrafanaThresholds(float64(rules.GetMaxStorageUsage())),
+		},
+		{
+			title:        "Transaction ID age",
+			panelType:    "timeseries",
+			expr:         fmt.Sprintf(`max by (pod, datname) (cnpg_pg_database_xid_age{%s})`, selector),
+			legendFormat: "{{pod}} {{datname}}",
+			unit:         "short",
+			thresholds:   newGrafanaThresholds(float64(rules.GetMaxXIDAge())),
+		},
+		{
+			title:        "Database size",
+			panelType:    "timeseries",
+			expr:         fmt.Sprintf(`max by (datname) (cnpg_pg_database_size_bytes{%s})`, selector),
+			legendFormat: "{{datname}}",
+			unit:         "bytes",
+		},
+		{
+			title:        "Archived WAL files",
+			panelType:    "timeseries",
+			expr:         fmt.Sprintf(`sum by (pod) (rate(cnpg_pg_stat_archiver_archived_count{%s}[5m]))`, selector),
+			legendFormat: "{{pod}}",
+			unit:         "ops",
+		},
+	}
+
+	panels := make([]grafanaPanel, len(definitions))
+	for idx, definition := range definitions {
+		defaults := grafanaFieldDefaults{
+			Unit:       definition.unit,
+			Thresholds: definition.thresholds,
+		}
+		if definition.panelType == "timeseries" {
+			defaults.Min = &zero
+			if definition.thresholds != nil {
+				defaults.Custom = map[string]any{
+					"thresholdsStyle": map[string]string{"mode": "line+area"},
+				}
+			}
+		}
+
+		panels[idx] = grafanaPanel{
+			ID:    idx + 1,
+			Title: definition.title,
+			Type:  definition.panelType,
+			Datasource: grafanaDatasource{
+				Type: "prometheus",
+				UID:  "${" + grafanaDatasourceVariable + "}",
+			},
+			GridPos: grafanaGridPos{X: (idx % 2) * 12, Y: (idx / 2) * 8, W: 12, H: 8},
+			Targets: []grafanaTarget{{
+				RefID:        "A",
+				Expr:         definition.expr,
+				LegendFormat: definition.legendFormat,
+			}},
+			FieldConfig: grafanaFieldConfig{Defaults: defaults},
+		}
+	}
+
+	return grafanaDashboard{
+		UID:           getGrafanaDashboardUID(cluster),
+		Title:         fmt.Sprintf("CloudNativePG / %s / %s", cluster.Namespace, cluster.Name),
+		Tags:          []string{"cloudnative-pg"},
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          grafanaTimeRange{From: "now-6h", To: "now"},
+		Templating: grafanaTemplating{
+			List: []grafanaVariable{{
+				Name:  grafanaDatasourceVariable,
+				Label: "Data source",
+				Type:  "datasource",
+				Query: "prometheus",
+			}},
+		},
+		Panels: panels,
+		// The changes made in Grafana would be overwritten by the operator
+		Editable: false,
+	}
+}
+
+// CreateGrafanaDashboardConfigMap creates the ConfigMap with the Grafana
+// dashboard of the cluster, labelled to be discovered by the Grafana sidecar
+func CreateGrafanaDashboardConfigMap(cluster *apiv1.Cluster) (*corev1.ConfigMap, error) {
+	var configuration *apiv1.GrafanaDashboardConfiguration
+	if cluster.Spec.Monitoring != nil {
+		configuration = cluster.Spec.Monitoring.GrafanaDashboard
+	}
+
+	dashboard, err := json.MarshalIndent(createGrafanaDashboard(cluster), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{
+		utils.ClusterLabelName: cluster.Name,
+	}
+	for key, value := range configuration.GetLabels() {
+		labels[key] = value
+	}
+
+	var annotations map[string]string
+	if configuration != nil && len(configuration.Annotations) > 0 {
+		annotations = make(map[string]string, len(configuration.Annotations))
+		for key, value := range configuration.Annotations {
+			annotations[key] = value
+		}
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cluster.GetGrafanaDashboardConfigMapName(),
+			Namespace:   cluster.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Data: map[string]string{
+			fmt.Sprintf("cnpg-%s-%s.json", cluster.Namespace, cluster.Name): string(dashboard),
+		},
+	}, nil
+}
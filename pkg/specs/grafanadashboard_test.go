@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Grafana dashboard", func() {
+	newCluster := func(namespace string, monitoring *apiv1.MonitoringConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "test",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances:  3,
+				Monitoring: monitoring,
+			},
+		}
+	}
+
+	findPanel := func(dashboard grafanaDashboard, title string) *grafanaPanel {
+		for idx := range dashboard.Panels {
+			if dashboard.Panels[idx].Title == title {
+				return &dashboard.Panels[idx]
+			}
+		}
+		return nil
+	}
+
+	It("creates a ConfigMap discovered by the Grafana sidecar", func() {
+		configMap, err := CreateGrafanaDashboardConfigMap(newCluster("default", &apiv1.MonitoringConfiguration{
+			GrafanaDashboard: &apiv1.GrafanaDashboardConfiguration{Enabled: true},
+		}))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(configMap.Name).To(Equal("test-grafana-dashboard"))
+		Expect(configMap.Labels).To(Equal(map[string]string{
+			utils.ClusterLabelName:             "test",
+			apiv1.DefaultGrafanaDashboardLabel: "1",
+		}))
+		Expect(configMap.Data).To(HaveKey("cnpg-default-test.json"))
+
+		var dashboard grafanaDashboard
+		Expect(json.Unmarshal([]byte(configMap.Data["cnpg-default-test.json"]), &dashboard)).To(Succeed())
+		Expect(dashboard.Title).To(Equal("CloudNativePG / default / test"))
+		Expect(dashboard.UID).To(HaveLen(37))
+	})
+
+	It("uses the labels and the annotations of the cluster specification", func() {
+		configMap, err := CreateGrafanaDashboardConfigMap(newCluster("default", &apiv1.MonitoringConfiguration{
+			GrafanaDashboard: &apiv1.GrafanaDashboardConfiguration{
+				Enabled:     true,
+				Labels:      map[string]string{"dashboards": "postgres"},
+				Annotations: map[string]string{"grafana_folder": "PostgreSQL"},
+			},
+		}))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(configMap.Labels).To(HaveKeyWithValue("dashboards", "postgres"))
+		Expect(configMap.Labels).ToNot(HaveKey(apiv1.DefaultGrafanaDashboardLabel))
+		Expect(configMap.Annotations).To(HaveKeyWithValue("grafana_folder", "PostgreSQL"))
+	})
+
+	It("uses the thresholds of the alerting rules", func() {
+		dashboard := createGrafanaDashboard(newCluster("default", &apiv1.MonitoringConfiguration{
+			PrometheusRule: &apiv1.PrometheusRuleConfiguration{MaxReplicationLag: 60},
+		}))
+
+		replicationLag := findPanel(dashboard, "Replication lag")
+		Expect(replicationLag).ToNot(BeNil())
+		Expect(*replicationLag.FieldConfig.Defaults.Thresholds.Steps[1].Value).To(BeEquivalentTo(60))
+		Expect(replicationLag.Targets[0].Expr).To(ContainSubstring(`namespace="default",pod=~"test-[0-9]+"`))
+
+		runningInstances := findPanel(dashboard, "Running instances")
+		Expect(*runningInstances.FieldConfig.Defaults.Thresholds.Steps[1].Value).To(BeEquivalentTo(3))
+	})
+
+	It("generates a different identifier for each cluster", func() {
+		Expect(getGrafanaDashboardUID(newCluster("one", nil))).ToNot(
+			Equal(getGrafanaDashboardUID(newCluster("two", nil))))
+	})
+})
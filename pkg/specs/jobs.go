@@ -24,10 +24,11 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -35,6 +36,27 @@ const (
 	// postInitApplicationSQLRefsFolder points to the folder of
 	// postInitApplicationSQL files in the primary job with initdb.
 	postInitApplicationSQLRefsFolder = "/etc/post-init-application-sql"
+
+	// MajorUpgradeContainerName is the name of the container upgrading
+	// the data directory in the major upgrade job
+	MajorUpgradeContainerName = string(jobRoleMajorUpgrade)
+
+	// MajorUpgradeDataDirectoryUpgradedMessage is the termination message
+	// of a major upgrade job failing after pg_upgrade completed, when the
+	// old data directory can't be restored anymore
+	MajorUpgradeDataDirectoryUpgradedMessage = "the data directory has already been upgraded"
+
+	// MajorUpgradePrepareContainerName is the name of the container copying
+	// the binaries of the old PostgreSQL version in the major upgrade job
+	MajorUpgradePrepareContainerName = "prepare-major-upgrade"
+
+	// majorUpgradeOldBinariesFolder is the folder where the binaries of
+	// the old PostgreSQL version are copied in the major upgrade job
+	majorUpgradeOldBinariesFolder = postgres.ScratchDataDirectory + "/old"
+
+	// inPlaceRestoreBackoffLimit is the number of times the in-place
+	// restore job is retried before the restore is marked as failed
+	inPlaceRestoreBackoffLimit = 3
 )
 
 // CreatePrimaryJobViaInitdb creates a new primary instance in a Pod
@@ -102,6 +124,7 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 			"namespace", cluster.Namespace)
 
 		options = append(options, config.Options...)
+		options = append(options, buildTDEInitDBOptions(cluster)...)
 		initCommand = append(
 			initCommand,
 			"--initdb-flags",
@@ -128,6 +151,7 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if walSegmentSize := config.WalSegmentSize; walSegmentSize != 0 && utils.IsPowerOfTwo(walSegmentSize) {
 		options = append(options, fmt.Sprintf("--wal-segsize=%v", walSegmentSize))
 	}
+	options = append(options, buildTDEInitDBOptions(cluster)...)
 	initCommand = append(
 		initCommand,
 		"--initdb-flags",
@@ -136,6 +160,21 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	return initCommand
 }
 
+// buildTDEInitDBOptions builds the initdb options needed to create an
+// encrypted data directory when TDE is enabled
+func buildTDEInitDBOptions(cluster apiv1.Cluster) []string {
+	if !cluster.IsTDEEnabled() {
+		return nil
+	}
+
+	tde := cluster.Spec.PostgresConfiguration.TDE
+	return []string{
+		"--data-encryption",
+		fmt.Sprintf("--key-wrap-command=%s", tde.GetWrapCommand()),
+		fmt.Sprintf("--key-unwrap-command=%s", tde.GetUnwrapCommand()),
+	}
+}
+
 // CreatePrimaryJobViaRestoreSnapshot creates a new primary instance in a Pod, restoring from a volumeSnapshot
 func CreatePrimaryJobViaRestoreSnapshot(
 	cluster apiv1.Cluster,
@@ -168,6 +207,20 @@ func CreatePrimaryJobViaRestoreSnapshot(
 	return job
 }
 
+// CreatePrimaryJobViaVolumeSource creates a new primary instance in a Pod,
+// adopting the data directory cloned from an existing PVC
+func CreatePrimaryJobViaVolumeSource(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
+	initCommand := []string{
+		"/controller/manager",
+		"instance",
+		"restoresnapshot",
+	}
+
+	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+
+	return createPrimaryJob(cluster, nodeSerial, jobRoleSnapshotRecovery, initCommand)
+}
+
 // CreatePrimaryJobViaRecovery creates a new primary instance in a Pod, restoring from a Backup
 func CreatePrimaryJobViaRecovery(cluster apiv1.Cluster, nodeSerial int, backup *apiv1.Backup) *batchv1.Job {
 	initCommand := []string{
@@ -251,6 +304,98 @@ func RestoreReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job
 	return job
 }
 
+// CreateMajorUpgradeJob creates a job upgrading the data directory of the
+// instance with the passed serial to the PostgreSQL major version of the
+// image selected in the cluster. The binaries of the old major version are
+// taken from the passed image
+func CreateMajorUpgradeJob(cluster apiv1.Cluster, nodeSerial int, oldImage string) *batchv1.Job {
+	upgradeCommand := []string{
+		"/controller/manager",
+		"instance",
+		"upgrade",
+		"execute",
+		"--old-binaries", majorUpgradeOldBinariesFolder,
+	}
+
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+		upgradeCommand = append(upgradeCommand, buildInitDBFlags(cluster)...)
+	}
+
+	upgradeCommand = append(upgradeCommand, buildCommonInitJobFlags(cluster)...)
+
+	job := createPrimaryJob(cluster, nodeSerial, jobRoleMajorUpgrade, upgradeCommand)
+
+	// A failed upgrade restores the old data directory, and retrying
+	// it would just lead to the same error. A failure after pg_upgrade
+	// completed is retried by deleting the job
+	job.Spec.BackoffLimit = ptr.To[int32](0)
+
+	prepareContainer := corev1.Container{
+		Name:            MajorUpgradePrepareContainerName,
+		Image:           oldImage,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/controller/manager",
+			"instance",
+			"upgrade",
+			"prepare",
+			majorUpgradeOldBinariesFolder,
+		},
+		VolumeMounts:    createPostgresVolumeMounts(cluster),
+		Resources:       cluster.Spec.Resources,
+		SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+	}
+	addManagerLoggingOptions(cluster, &prepareContainer)
+
+	job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, prepareContainer)
+
+	return job
+}
+
+// CreateInPlaceRestoreJob creates a job replacing the data directory of
+// the instance with the passed serial with the content of a backup, as
+// requested by the passed Restore object
+func CreateInPlaceRestoreJob(
+	cluster apiv1.Cluster,
+	nodeSerial int,
+	restore *apiv1.Restore,
+	backup *apiv1.Backup,
+) *batchv1.Job {
+	restoreCommand := []string{
+		"/controller/manager",
+		"instance",
+		"restore",
+		"--in-place-restore", restore.Name,
+	}
+
+	restoreCommand = append(restoreCommand, buildCommonInitJobFlags(cluster)...)
+
+	job := createPrimaryJob(cluster, nodeSerial, jobRoleInPlaceRestore, restoreCommand)
+
+	// The data directory is removed only after the backup has been
+	// checked, and again at the beginning of every attempt, so the
+	// restore is retried like the other bootstrap jobs
+	job.Spec.BackoffLimit = ptr.To[int32](inPlaceRestoreBackoffLimit)
+
+	if backup.Status.EndpointCA != nil && backup.Status.EndpointCA.Name != "" && backup.Status.EndpointCA.Key != "" {
+		AddBarmanEndpointCAToPodSpec(&job.Spec.Template.Spec, backup.Status.EndpointCA, backup.Status.BarmanCredentials)
+	}
+
+	return job
+}
+
+// IsInPlaceRestoreJob checks if the passed job is restoring
+// the data directory of an existing cluster
+func IsInPlaceRestoreJob(job batchv1.Job) bool {
+	return job.Spec.Template.Labels[utils.JobRoleLabelName] == string(jobRoleInPlaceRestore)
+}
+
+// IsMajorUpgradeJob checks if the passed job is upgrading
+// a data directory to a new PostgreSQL major version
+func IsMajorUpgradeJob(job batchv1.Job) bool {
+	return job.Spec.Template.Labels[utils.JobRoleLabelName] == string(jobRoleMajorUpgrade)
+}
+
 func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
 	var flags []string
 
@@ -271,9 +416,19 @@ const (
 	jobRoleFullRecovery     jobRole = "full-recovery"
 	jobRoleJoin             jobRole = "join"
 	jobRoleSnapshotRecovery jobRole = "snapshot-recovery"
+	jobRoleMajorUpgrade     jobRole = "major-upgrade"
+	jobRoleInPlaceRestore   jobRole = "inplace-restore"
 )
 
-var jobRoleList = []jobRole{jobRoleImport, jobRoleInitDB, jobRolePGBaseBackup, jobRoleFullRecovery, jobRoleJoin}
+var jobRoleList = []jobRole{
+	jobRoleImport,
+	jobRoleInitDB,
+	jobRolePGBaseBackup,
+	jobRoleFullRecovery,
+	jobRoleJoin,
+	jobRoleMajorUpgrade,
+	jobRoleInPlaceRestore,
+}
 
 // getJobName returns a string indicating the job name
 func (role jobRole) getJobName(instanceName string) string {
@@ -350,15 +505,13 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 		},
 	}
 
-	if configuration.Current.CreateAnyService {
+	if cluster.IsMultiHostConnectionEnabled() {
 		job.Spec.Template.Spec.Subdomain = cluster.GetServiceAnyName()
 	}
 
 	cluster.SetInheritedDataAndOwnership(&job.ObjectMeta)
 	addManagerLoggingOptions(cluster, &job.Spec.Template.Spec.Containers[0])
-	if utils.IsAnnotationAppArmorPresent(&job.Spec.Template.Spec, cluster.Annotations) {
-		utils.AnnotateAppArmor(&job.ObjectMeta, &job.Spec.Template.Spec, cluster.Annotations)
-	}
+	annotateAppArmor(&cluster, &job.Spec.Template.ObjectMeta, &job.Spec.Template.Spec)
 
 	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
 		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
@@ -165,3 +165,132 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
 })
+
+var _ = Describe("Job created via volumeSource", func() {
+	It("restores the adopted data directory without a backup label", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						VolumeSource: &apiv1.VolumeSourceRecovery{
+							Storage: apiv1.LocalObjectReference{Name: "old-1"},
+						},
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaVolumeSource(cluster, 1)
+		Expect(job.Name).To(Equal("cluster-example-1-snapshot-recovery"))
+		command := job.Spec.Template.Spec.Containers[0].Command
+		Expect(command[:3]).To(Equal([]string{"/controller/manager", "instance", "restoresnapshot"}))
+		Expect(command).ToNot(ContainElement(HavePrefix("--backuplabel")))
+	})
+})
+
+var _ = Describe("initdb options for TDE", func() {
+	It("are not added when TDE is disabled", func() {
+		cluster := apiv1.Cluster{}
+		Expect(buildTDEInitDBOptions(cluster)).To(BeEmpty())
+	})
+
+	It("enable the data encryption using the configured commands", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					TDE: &apiv1.TDEConfiguration{
+						Enabled:     true,
+						WrapCommand: "wrap %p",
+					},
+				},
+			},
+		}
+		Expect(buildTDEInitDBOptions(cluster)).To(Equal([]string{
+			"--data-encryption",
+			"--key-wrap-command=wrap %p",
+			"--key-unwrap-command=" + apiv1.DefaultTDEUnwrapCommand,
+		}))
+	})
+})
+
+var _ = Describe("Major upgrade job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:16.1",
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Encoding: "UTF8",
+				},
+			},
+		},
+	}
+
+	It("runs the new image using the binaries of the old one", func() {
+		job := CreateMajorUpgradeJob(cluster, 1, "postgres:15.4")
+		Expect(job.Name).To(Equal("cluster-example-1-major-upgrade"))
+		Expect(IsMajorUpgradeJob(*job)).To(BeTrue())
+		Expect(*job.Spec.BackoffLimit).To(BeZero())
+
+		initContainers := job.Spec.Template.Spec.InitContainers
+		Expect(initContainers).To(HaveLen(2))
+		Expect(initContainers[1].Name).To(Equal(MajorUpgradePrepareContainerName))
+		Expect(initContainers[1].Image).To(Equal("postgres:15.4"))
+		Expect(initContainers[1].Command).To(ContainElement(majorUpgradeOldBinariesFolder))
+
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("postgres:16.1"))
+		Expect(container.Command).To(ContainElements("upgrade", "execute", majorUpgradeOldBinariesFolder))
+		Expect(container.Command).To(ContainElement("--encoding=UTF8"))
+	})
+
+	It("is not confused with the other jobs", func() {
+		Expect(IsMajorUpgradeJob(*CreatePrimaryJobViaInitdb(cluster, 1))).To(BeFalse())
+	})
+})
+
+var _ = Describe("In-place restore job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:16.1",
+		},
+	}
+	restore := &apiv1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Name: "flashback"},
+	}
+	backup := &apiv1.Backup{
+		Status: apiv1.BackupStatus{
+			BarmanCredentials: apiv1.BarmanCredentials{},
+			EndpointCA: &apiv1.SecretKeySelector{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: "barman-ca"},
+				Key:                  "ca.crt",
+			},
+		},
+	}
+
+	It("restores the selected backup on the primary instance", func() {
+		job := CreateInPlaceRestoreJob(cluster, 1, restore, backup)
+		Expect(job.Name).To(Equal("cluster-example-1-inplace-restore"))
+		Expect(IsInPlaceRestoreJob(*job)).To(BeTrue())
+		Expect(*job.Spec.BackoffLimit).To(BeEquivalentTo(3))
+
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Command).To(ContainElements("restore", "--in-place-restore", "flashback"))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(
+			HaveField("Name", "barman-endpoint-ca")))
+	})
+
+	It("is not confused with the other jobs", func() {
+		Expect(IsInPlaceRestoreJob(*CreateMajorUpgradeJob(cluster, 1, "postgres:15.4"))).To(BeFalse())
+	})
+})
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// ClientConnectionsMetricName is the name of the metric, exposed by the
+// pgbouncer instance manager, used to scale the pooler depending on the
+// number of active client connections
+const ClientConnectionsMetricName = "cnpg_pgbouncer_pools_cl_active"
+
+// HorizontalPodAutoscaler creates the autoscaler of the pgbouncer deployment,
+// given the configurations we have in the pooler specifications. It returns
+// nil when the pooler is not configured to be autoscaled
+func HorizontalPodAutoscaler(pooler *apiv1.Pooler) *autoscalingv2.HorizontalPodAutoscaler {
+	autoscaling := pooler.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+
+	var metrics []autoscalingv2.MetricSpec
+	if autoscaling.TargetClientConnections != nil {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: ClientConnectionsMetricName,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(int64(*autoscaling.TargetClientConnections), resource.DecimalSI),
+				},
+			},
+		})
+	}
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: autoscaling.TargetCPUUtilizationPercentage,
+				},
+			},
+		})
+	}
+
+	minReplicas := autoscaling.GetMinInstances()
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pooler.Name,
+			Namespace: pooler.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName:   pooler.Spec.Cluster.Name,
+				utils.PgbouncerNameLabel: pooler.Name,
+				utils.PodRoleLabelName:   string(utils.PodRolePooler),
+			},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       pooler.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: autoscaling.MaxInstances,
+			Metrics:     metrics,
+			Behavior:    autoscaling.Behavior.DeepCopy(),
+		},
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HorizontalPodAutoscaler", func() {
+	var pooler *apiv1.Pooler
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pooler",
+				Namespace: "test-namespace",
+			},
+			Spec: apiv1.PoolerSpec{
+				Cluster:   apiv1.LocalObjectReference{Name: "test-cluster"},
+				Instances: ptr.To(int32(1)),
+			},
+		}
+	})
+
+	It("is not created without an autoscaling configuration", func() {
+		Expect(HorizontalPodAutoscaler(pooler)).To(BeNil())
+	})
+
+	It("targets the pooler deployment", func() {
+		pooler.Spec.Autoscaling = &apiv1.PoolerAutoscaling{
+			MaxInstances:            3,
+			TargetClientConnections: ptr.To(int32(50)),
+		}
+
+		hpa := HorizontalPodAutoscaler(pooler)
+		Expect(hpa).ToNot(BeNil())
+		Expect(hpa.Name).To(Equal(pooler.Name))
+		Expect(hpa.Namespace).To(Equal(pooler.Namespace))
+		Expect(hpa.Spec.ScaleTargetRef).To(Equal(autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       pooler.Name,
+		}))
+		Expect(*hpa.Spec.MinReplicas).To(BeEquivalentTo(1))
+		Expect(hpa.Spec.MaxReplicas).To(BeEquivalentTo(3))
+	})
+
+	It("scales on the client connections and on the CPU utilization", func() {
+		pooler.Spec.Autoscaling = &apiv1.PoolerAutoscaling{
+			MinInstances:                   ptr.To(int32(2)),
+			MaxInstances:                   5,
+			TargetClientConnections:        ptr.To(int32(50)),
+			TargetCPUUtilizationPercentage: ptr.To(int32(70)),
+		}
+
+		hpa := HorizontalPodAutoscaler(pooler)
+		Expect(*hpa.Spec.MinReplicas).To(BeEquivalentTo(2))
+		Expect(hpa.Spec.Metrics).To(HaveLen(2))
+
+		Expect(hpa.Spec.Metrics[0].Type).To(Equal(autoscalingv2.PodsMetricSourceType))
+		Expect(hpa.Spec.Metrics[0].Pods.Metric.Name).To(Equal(ClientConnectionsMetricName))
+		Expect(hpa.Spec.Metrics[0].Pods.Target.AverageValue.Value()).To(BeEquivalentTo(50))
+
+		Expect(hpa.Spec.Metrics[1].Type).To(Equal(autoscalingv2.ResourceMetricSourceType))
+		Expect(hpa.Spec.Metrics[1].Resource.Name).To(Equal(corev1.ResourceCPU))
+		Expect(*hpa.Spec.Metrics[1].Resource.Target.AverageUtilization).To(BeEquivalentTo(70))
+	})
+})
@@ -33,8 +33,21 @@ func BuildReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisru
 	if cluster == nil || cluster.Spec.Instances < 3 {
 		return nil
 	}
-	minAvailableReplicas := cluster.Spec.Instances - 2
-	allReplicasButOne := intstr.FromInt(minAvailableReplicas)
+	return buildReplicasPodDisruptionBudget(cluster, cluster.Spec.Instances-2)
+}
+
+// BuildBlockingReplicasPodDisruptionBudget creates a pod disruption budget
+// preventing the eviction of any replica. It is used outside the maintenance
+// windows of the cluster, to defer the draining of the nodes
+func BuildBlockingReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisruptionBudget {
+	if cluster == nil || cluster.Spec.Instances < 2 {
+		return nil
+	}
+	return buildReplicasPodDisruptionBudget(cluster, cluster.Spec.Instances-1)
+}
+
+func buildReplicasPodDisruptionBudget(cluster *apiv1.Cluster, minAvailableReplicas int) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(minAvailableReplicas)
 
 	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
@@ -48,7 +61,7 @@ func BuildReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisru
 					utils.ClusterRoleLabelName: ClusterRoleLabelReplica,
 				},
 			},
-			MinAvailable: &allReplicasButOne,
+			MinAvailable: &minAvailable,
 		},
 	}
 
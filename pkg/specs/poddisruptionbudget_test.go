@@ -49,6 +49,16 @@ var _ = Describe("POD Disruption Budget specifications", func() {
 		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(minAvailableReplicas)))
 	})
 
+	It("require every replica to be available outside the maintenance windows", func() {
+		result := BuildBlockingReplicasPodDisruptionBudget(cluster)
+		Expect(result.Name).To(Equal(cluster.Name))
+		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(replicas)))
+
+		singleInstance := cluster.DeepCopy()
+		singleInstance.Spec.Instances = 1
+		Expect(BuildBlockingReplicasPodDisruptionBudget(singleInstance)).To(BeNil())
+	})
+
 	It("require at least one primary instance to be available at all times", func() {
 		result := BuildPrimaryPodDisruptionBudget(cluster)
 		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(minAvailablePrimary)))
@@ -31,7 +31,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -64,6 +63,14 @@ const (
 	// ClusterRoleLabelReplica is written in labels to represent replica servers
 	ClusterRoleLabelReplica = "replica"
 
+	// ReadOnlyTrafficEnabled is written in the read-only traffic label of
+	// the replicas that can receive read-only traffic
+	ReadOnlyTrafficEnabled = "enabled"
+
+	// ReadOnlyTrafficDisabled is written in the read-only traffic label of
+	// the replicas lagging too far behind the primary
+	ReadOnlyTrafficDisabled = "disabled"
+
 	// PostgresContainerName is the name of the container executing PostgreSQL
 	// inside one Pod
 	PostgresContainerName = "postgres"
@@ -146,6 +153,21 @@ func CreatePodEnvConfig(cluster apiv1.Cluster, podName string) EnvConfig {
 		},
 		EnvFrom: cluster.Spec.EnvFrom,
 	}
+
+	if secretKeyRef := cluster.GetTDESecretKeyRef(); secretKeyRef != nil {
+		config.EnvVars = append(config.EnvVars, corev1.EnvVar{
+			Name: apiv1.TDEPassphraseEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretKeyRef.Name,
+					},
+					Key: secretKeyRef.Key,
+				},
+			},
+		})
+	}
+
 	config.EnvVars = append(config.EnvVars, cluster.Spec.Env...)
 
 	hashValue, _ := hash.ComputeHash(config)
@@ -193,12 +215,12 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig) []core
 			EnvFrom:         envConfig.EnvFrom,
 			VolumeMounts:    createPostgresVolumeMounts(cluster),
 			StartupProbe: &corev1.Probe{
-				FailureThreshold: getStartupProbeFailureThreshold(cluster.GetMaxStartDelay()),
+				FailureThreshold: getStartupProbeFailureThreshold(cluster.GetMaxStartDelay(), StartupProbePeriod),
 				PeriodSeconds:    StartupProbePeriod,
 				TimeoutSeconds:   5,
 				ProbeHandler: corev1.ProbeHandler{
 					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathHealth,
+						Path: url.PathStartup,
 						Port: intstr.FromInt32(int32(url.StatusPort)),
 					},
 				},
@@ -251,17 +273,47 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig) []core
 	}
 
 	addManagerLoggingOptions(cluster, &containers[0])
+	configureProbes(cluster, &containers[0])
 
 	return containers
 }
 
+// configureProbes applies the probes configuration defined by the user
+// to the PostgreSQL container
+func configureProbes(cluster apiv1.Cluster, container *corev1.Container) {
+	probes := cluster.Spec.Probes
+	if probes == nil {
+		return
+	}
+
+	if probes.Startup != nil {
+		probes.Startup.ApplyInto(container.StartupProbe)
+		// The failure threshold depends on the period, so it
+		// needs to be recomputed if only the latter has been set
+		if probes.Startup.FailureThreshold == 0 {
+			container.StartupProbe.FailureThreshold = getStartupProbeFailureThreshold(
+				cluster.GetMaxStartDelay(),
+				container.StartupProbe.PeriodSeconds,
+			)
+		}
+	}
+
+	if probes.Liveness != nil {
+		probes.Liveness.ApplyInto(container.LivenessProbe)
+	}
+
+	if probes.Readiness != nil {
+		probes.Readiness.ApplyInto(container.ReadinessProbe)
+	}
+}
+
 // getStartupProbeFailureThreshold get the startup probe failure threshold
 // FAILURE_THRESHOLD = ceil(startDelay / periodSeconds) and minimum value is 1
-func getStartupProbeFailureThreshold(startupDelay int32) int32 {
-	if startupDelay <= StartupProbePeriod {
+func getStartupProbeFailureThreshold(startupDelay, periodSeconds int32) int32 {
+	if startupDelay <= periodSeconds {
 		return 1
 	}
-	return int32(math.Ceil(float64(startupDelay) / float64(StartupProbePeriod)))
+	return int32(math.Ceil(float64(startupDelay) / float64(periodSeconds)))
 }
 
 // CreateAffinitySection creates the affinity sections for Pods, given the configuration
@@ -408,13 +460,11 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 		pod.Spec.PriorityClassName = cluster.Spec.PriorityClassName
 	}
 
-	if configuration.Current.CreateAnyService {
+	if cluster.IsMultiHostConnectionEnabled() {
 		pod.Spec.Subdomain = cluster.GetServiceAnyName()
 	}
 
-	if utils.IsAnnotationAppArmorPresent(&pod.Spec, cluster.Annotations) {
-		utils.AnnotateAppArmor(&pod.ObjectMeta, &pod.Spec, cluster.Annotations)
-	}
+	annotateAppArmor(&cluster, &pod.ObjectMeta, &pod.Spec)
 	return pod
 }
 
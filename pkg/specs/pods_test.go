@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -313,6 +314,32 @@ var _ = Describe("Create affinity section", func() {
 })
 
 var _ = Describe("EnvConfig", func() {
+	It("passes the TDE passphrase from the secret", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				PostgresConfiguration: v1.PostgresConfiguration{
+					TDE: &v1.TDEConfiguration{
+						Enabled: true,
+						SecretKeyRef: &v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "tde-secret"},
+							Key:                  "passphrase",
+						},
+					},
+				},
+			},
+		}
+		envConfig := CreatePodEnvConfig(cluster, "test-1")
+		Expect(envConfig.EnvVars).To(ContainElement(corev1.EnvVar{
+			Name: v1.TDEPassphraseEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "tde-secret"},
+					Key:                  "passphrase",
+				},
+			},
+		}))
+	})
+
 	Context("IsEnvEqual function", func() {
 		It("returns true if the Env are equal", func() {
 			cluster := v1.Cluster{
@@ -922,10 +949,80 @@ var _ = Describe("PodSpec drift detection", func() {
 
 var _ = Describe("Compute startup probe failure threshold", func() {
 	It("should take the minimum value 1", func() {
-		Expect(getStartupProbeFailureThreshold(5)).To(BeNumerically("==", 1))
+		Expect(getStartupProbeFailureThreshold(5, StartupProbePeriod)).To(BeNumerically("==", 1))
 	})
 
 	It("should take the value from 'startDelay / periodSeconds'", func() {
-		Expect(getStartupProbeFailureThreshold(109)).To(BeNumerically("==", 11))
+		Expect(getStartupProbeFailureThreshold(109, StartupProbePeriod)).To(BeNumerically("==", 11))
+	})
+})
+
+var _ = Describe("Probes configuration", func() {
+	var cluster v1.Cluster
+
+	BeforeEach(func() {
+		cluster = v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 3600,
+			},
+		}
+	})
+
+	It("keeps the default probes when nothing is configured", func() {
+		containers := createPostgresContainers(cluster, EnvConfig{})
+		Expect(containers[0].StartupProbe.PeriodSeconds).To(BeEquivalentTo(StartupProbePeriod))
+		Expect(containers[0].StartupProbe.FailureThreshold).To(BeEquivalentTo(360))
+		Expect(containers[0].StartupProbe.HTTPGet.Path).To(Equal(url.PathStartup))
+		Expect(containers[0].ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(ReadinessProbePeriod))
+		Expect(containers[0].LivenessProbe.TimeoutSeconds).To(BeEquivalentTo(5))
+	})
+
+	It("doesn't require a rollout of the Pods created with the previous startup probe", func() {
+		containers := createPostgresContainers(cluster, EnvConfig{})
+		previousContainers := createPostgresContainers(cluster, EnvConfig{})
+		previousContainers[0].StartupProbe.HTTPGet.Path = url.PathHealth
+
+		specsMatch, diff := ComparePodSpecs(
+			corev1.PodSpec{Containers: previousContainers},
+			corev1.PodSpec{Containers: containers},
+		)
+		Expect(diff).To(BeEmpty())
+		Expect(specsMatch).To(BeTrue())
+	})
+
+	It("applies the probes configuration set by the user", func() {
+		cluster.Spec.Probes = &v1.ProbesConfiguration{
+			Startup: &v1.ProbeWithStrategy{
+				Probe: v1.Probe{PeriodSeconds: 20},
+			},
+			Liveness: &v1.Probe{
+				TimeoutSeconds:   10,
+				FailureThreshold: 5,
+			},
+			Readiness: &v1.ProbeWithStrategy{
+				Probe: v1.Probe{SuccessThreshold: 2},
+				Type:  v1.ProbeStrategyStreaming,
+			},
+		}
+
+		containers := createPostgresContainers(cluster, EnvConfig{})
+		Expect(containers[0].StartupProbe.PeriodSeconds).To(BeEquivalentTo(20))
+		Expect(containers[0].StartupProbe.FailureThreshold).To(BeEquivalentTo(180))
+		Expect(containers[0].LivenessProbe.TimeoutSeconds).To(BeEquivalentTo(10))
+		Expect(containers[0].LivenessProbe.FailureThreshold).To(BeEquivalentTo(5))
+		Expect(containers[0].LivenessProbe.PeriodSeconds).To(BeEquivalentTo(LivenessProbePeriod))
+		Expect(containers[0].ReadinessProbe.SuccessThreshold).To(BeEquivalentTo(2))
+		Expect(containers[0].ReadinessProbe.HTTPGet.Path).To(Equal(url.PathReady))
+	})
+
+	It("doesn't recompute an explicit startup failure threshold", func() {
+		cluster.Spec.Probes = &v1.ProbesConfiguration{
+			Startup: &v1.ProbeWithStrategy{
+				Probe: v1.Probe{PeriodSeconds: 20, FailureThreshold: 7},
+			},
+		}
+
+		containers := createPostgresContainers(cluster, EnvConfig{})
+		Expect(containers[0].StartupProbe.FailureThreshold).To(BeEquivalentTo(7))
 	})
 })
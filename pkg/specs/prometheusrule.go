@@ -0,0 +1,190 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// alertSeverityWarning is the severity of the alerts requiring
+	// attention, but not an immediate action
+	alertSeverityWarning = "warning"
+
+	// alertSeverityCritical is the severity of the alerts requiring
+	// an immediate action
+	alertSeverityCritical = "critical"
+)
+
+// getMetricsSelector gets the PromQL label matchers selecting the series
+// of the instances of the cluster, as scraped through the PodMonitor
+func getMetricsSelector(cluster *apiv1.Cluster) string {
+	return fmt.Sprintf(`namespace="%s",pod=~"%s-[0-9]+"`, cluster.Namespace, cluster.Name)
+}
+
+// CreatePrometheusRule creates the PrometheusRule with the alerts of the
+// cluster, whose thresholds are taken from the cluster specification
+func CreatePrometheusRule(cluster *apiv1.Cluster) *monitoringv1.PrometheusRule {
+	var configuration *apiv1.PrometheusRuleConfiguration
+	if cluster.Spec.Monitoring != nil {
+		configuration = cluster.Spec.Monitoring.PrometheusRule
+	}
+
+	meta := metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+	cluster.SetInheritedDataAndOwnership(&meta)
+	if configuration != nil && len(configuration.Labels) > 0 {
+		if meta.Labels == nil {
+			meta.Labels = make(map[string]string, len(configuration.Labels))
+		}
+		for key, value := range configuration.Labels {
+			meta.Labels[key] = value
+		}
+	}
+
+	selector := getMetricsSelector(cluster)
+	newRule := func(alert, expr, forDuration, severity, summary, description string) monitoringv1.Rule {
+		return monitoringv1.Rule{
+			Alert: alert,
+			Expr:  intstr.FromString(expr),
+			For:   ptr.To(monitoringv1.Duration(forDuration)),
+			Labels: map[string]string{
+				"severity":  severity,
+				"namespace": cluster.Namespace,
+				"cluster":   cluster.Name,
+			},
+			Annotations: map[string]string{
+				"summary":     summary,
+				"description": description,
+			},
+		}
+	}
+
+	rules := []monitoringv1.Rule{
+		newRule(
+			"CNPGClusterInstancesDown",
+			fmt.Sprintf(`(count(cnpg_collector_up{%s} == 1) or vector(0)) < %d`,
+				selector, cluster.Spec.Instances),
+			"5m",
+			alertSeverityCritical,
+			"Instances of the cluster are not running",
+			fmt.Sprintf("Less than %d instances of the cluster %s/%s are running PostgreSQL.",
+				cluster.Spec.Instances, cluster.Namespace, cluster.Name),
+		),
+		newRule(
+			"CNPGClusterHighReplicationLag",
+			fmt.Sprintf(`max by (pod) (cnpg_pg_replication_lag{%s}) > %d`,
+				selector, configuration.GetMaxReplicationLag()),
+			"1m",
+			alertSeverityWarning,
+			"A replica is lagging behind the primary",
+			fmt.Sprintf("The replica {{ $labels.pod }} is lagging behind the primary by more than %d seconds.",
+				configuration.GetMaxReplicationLag()),
+		),
+		newRule(
+			"CNPGClusterLongRunningTransaction",
+			fmt.Sprintf(`max by (pod) (cnpg_backends_max_tx_duration_seconds{%s}) > %d`,
+				selector, configuration.GetMaxTransactionDuration()),
+			"1m",
+			alertSeverityWarning,
+			"A transaction is running for too long",
+			fmt.Sprintf("A transaction on {{ $labels.pod }} has been running for more than %d seconds.",
+				configuration.GetMaxTransactionDuration()),
+		),
+		newRule(
+			"CNPGClusterHighConnectionsUsage",
+			fmt.Sprintf(`sum by (pod) (cnpg_backends_total{%s}) * 100 / `+
+				`max by (pod) (cnpg_pg_settings_setting{%s,name="max_connections"}) > %d`,
+				selector, selector, configuration.GetMaxConnectionsUsage()),
+			"5m",
+			alertSeverityWarning,
+			"The connections are approaching max_connections",
+			fmt.Sprintf("More than %d%% of max_connections are in use on {{ $labels.pod }}.",
+				configuration.GetMaxConnectionsUsage()),
+		),
+		newRule(
+			"CNPGClusterHighStorageUsage",
+			fmt.Sprintf(`(1 - cnpg_collector_disk_available_bytes{%s} / cnpg_collector_disk_total_bytes{%s}) * 100 > %d`,
+				selector, selector, configuration.GetMaxStorageUsage()),
+			"5m",
+			alertSeverityWarning,
+			"A volume of the cluster is running out of space",
+			fmt.Sprintf("More than %d%% of the {{ $labels.volume }} volume of {{ $labels.pod }} is in use.",
+				configuration.GetMaxStorageUsage()),
+		),
+		newRule(
+			"CNPGClusterXIDWraparound",
+			fmt.Sprintf(`max by (pod, datname) (cnpg_pg_database_xid_age{%s}) > %d`,
+				selector, configuration.GetMaxXIDAge()),
+			"5m",
+			alertSeverityWarning,
+			"A database is approaching the transaction ID wraparound",
+			fmt.Sprintf("The oldest unfrozen transaction ID of the database {{ $labels.datname }} "+
+				"on {{ $labels.pod }} is more than %d transactions old.",
+				configuration.GetMaxXIDAge()),
+		),
+		newRule(
+			"CNPGClusterWALArchivingFailing",
+			fmt.Sprintf(`cnpg_pg_stat_archiver_last_failed_time{%s} > cnpg_pg_stat_archiver_last_archived_time{%s}`,
+				selector, selector),
+			"5m",
+			alertSeverityCritical,
+			"The WAL archiving is failing",
+			"The last attempt to archive a WAL file on {{ $labels.pod }} failed.",
+		),
+		newRule(
+			"CNPGClusterPostmasterRestartsPaused",
+			fmt.Sprintf(`max by (pod) (cnpg_collector_postmaster_restarts_paused{%s}) > 0`, selector),
+			"1m",
+			alertSeverityCritical,
+			"PostgreSQL is not restarted after repeated unexpected exits",
+			"The instance manager of {{ $labels.pod }} stopped restarting PostgreSQL, "+
+				"check the status of the cluster for the reason of the last exit.",
+		),
+	}
+
+	if !cluster.IsReplica() {
+		rules = append(rules, newRule(
+			"CNPGClusterReplicaNotStreaming",
+			fmt.Sprintf(`cnpg_pg_replication_in_recovery{%s} > cnpg_pg_replication_is_wal_receiver_up{%s}`,
+				selector, selector),
+			"5m",
+			alertSeverityWarning,
+			"A replica is not streaming from the primary",
+			"The replica {{ $labels.pod }} is not receiving the WAL stream from the primary.",
+		))
+	}
+
+	return &monitoringv1.PrometheusRule{
+		ObjectMeta: meta,
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{{
+				Name:  fmt.Sprintf("cnpg-%s-%s.rules", cluster.Namespace, cluster.Name),
+				Rules: rules,
+			}},
+		},
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrometheusRule", func() {
+	findRule := func(rule *monitoringv1.PrometheusRule, alert string) *monitoringv1.Rule {
+		for _, group := range rule.Spec.Groups {
+			for idx := range group.Rules {
+				if group.Rules[idx].Alert == alert {
+					return &group.Rules[idx]
+				}
+			}
+		}
+		return nil
+	}
+
+	newCluster := func(configuration *apiv1.PrometheusRuleConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "test",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				Monitoring: &apiv1.MonitoringConfiguration{
+					PrometheusRule: configuration,
+				},
+			},
+		}
+	}
+
+	It("uses the default thresholds", func() {
+		rule := CreatePrometheusRule(newCluster(&apiv1.PrometheusRuleConfiguration{Enabled: true}))
+
+		Expect(rule.Name).To(Equal("test"))
+		Expect(rule.Namespace).To(Equal("test-namespace"))
+		Expect(rule.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "test"))
+		Expect(rule.OwnerReferences).To(HaveLen(1))
+
+		Expect(findRule(rule, "CNPGClusterInstancesDown").Expr.String()).To(Equal(
+			`(count(cnpg_collector_up{namespace="test-namespace",pod=~"test-[0-9]+"} == 1) or vector(0)) < 3`))
+		Expect(findRule(rule, "CNPGClusterHighReplicationLag").Expr.String()).To(HaveSuffix("> 300"))
+		Expect(findRule(rule, "CNPGClusterHighConnectionsUsage").Expr.String()).To(HaveSuffix("> 80"))
+		Expect(findRule(rule, "CNPGClusterXIDWraparound").Expr.String()).To(HaveSuffix("> 150000000"))
+		Expect(findRule(rule, "CNPGClusterReplicaNotStreaming")).ToNot(BeNil())
+		Expect(findRule(rule, "CNPGClusterPostmasterRestartsPaused").Expr.String()).To(Equal(
+			`max by (pod) (cnpg_collector_postmaster_restarts_paused{namespace="test-namespace",pod=~"test-[0-9]+"}) > 0`))
+	})
+
+	It("uses the thresholds and the labels of the cluster specification", func() {
+		rule := CreatePrometheusRule(newCluster(&apiv1.PrometheusRuleConfiguration{
+			Enabled:                true,
+			Labels:                 map[string]string{"release": "prometheus"},
+			MaxReplicationLag:      60,
+			MaxTransactionDuration: 120,
+			MaxConnectionsUsage:    90,
+			MaxStorageUsage:        70,
+			MaxXIDAge:              1000000,
+		}))
+
+		Expect(rule.Labels).To(HaveKeyWithValue("release", "prometheus"))
+		Expect(findRule(rule, "CNPGClusterHighReplicationLag").Expr.String()).To(HaveSuffix("> 60"))
+		Expect(findRule(rule, "CNPGClusterLongRunningTransaction").Expr.String()).To(HaveSuffix("> 120"))
+		Expect(findRule(rule, "CNPGClusterHighConnectionsUsage").Expr.String()).To(HaveSuffix("> 90"))
+		Expect(findRule(rule, "CNPGClusterHighStorageUsage").Expr.String()).To(HaveSuffix("> 70"))
+		Expect(findRule(rule, "CNPGClusterXIDWraparound").Expr.String()).To(HaveSuffix("> 1000000"))
+		Expect(findRule(rule, "CNPGClusterHighReplicationLag").Labels).To(And(
+			HaveKeyWithValue("namespace", "test-namespace"),
+			HaveKeyWithValue("cluster", "test"),
+			HaveKeyWithValue("severity", "warning"),
+		))
+	})
+
+	It("doesn't check the streaming of the replicas in a replica cluster", func() {
+		cluster := newCluster(&apiv1.PrometheusRuleConfiguration{Enabled: true})
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: true, Source: "origin"}
+
+		Expect(findRule(CreatePrometheusRule(cluster), "CNPGClusterReplicaNotStreaming")).To(BeNil())
+	})
+})
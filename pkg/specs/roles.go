@@ -113,6 +113,129 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"update",
 			},
 		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"restores",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"databases",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+				"patch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"databases/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"publications",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+				"patch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"publications/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"changedatacaptures",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+				"patch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"changedatacaptures/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"subscriptions",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+				"patch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"subscriptions/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
 		{
 			APIGroups: []string{
 				"",
@@ -156,6 +279,11 @@ func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) [
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 	involvedSecretNames = append(involvedSecretNames, managedRolesSecrets(cluster)...)
+	involvedSecretNames = append(involvedSecretNames, poolerAuthQuerySecrets(cluster)...)
+
+	if tdeSecret := cluster.GetTDESecretKeyRef(); tdeSecret != nil {
+		involvedSecretNames = append(involvedSecretNames, tdeSecret.Name, cluster.GetTDEPassphrasesSecretName())
+	}
 
 	return cleanupResourceList(involvedSecretNames)
 }
@@ -173,6 +301,15 @@ func getInvolvedConfigMapNames(cluster apiv1.Cluster) []string {
 		}
 	}
 
+	// The instance manager reads the content of the lifecycle hooks
+	for _, point := range apiv1.GetLifecycleHookPoints() {
+		for _, hook := range cluster.Spec.LifecycleHooks.GetHooks(point) {
+			if reference := hook.GetConfigMapKeySelector(); reference != nil {
+				involvedConfigMapNames = append(involvedConfigMapNames, reference.Name)
+			}
+		}
+	}
+
 	return cleanupResourceList(involvedConfigMapNames)
 }
 
@@ -189,6 +326,11 @@ func externalClusterSecrets(cluster apiv1.Cluster) []string {
 	var result []string
 
 	for _, server := range cluster.Spec.ExternalClusters {
+		// The secrets of another namespace are granted by a dedicated
+		// Role living there
+		if server.GetSecretsNamespace(cluster.Namespace) != cluster.Namespace {
+			continue
+		}
 		if server.SSLCert != nil {
 			result = append(result,
 				server.SSLCert.Name)
@@ -215,6 +357,9 @@ func externalClusterSecrets(cluster apiv1.Cluster) []string {
 			result = append(
 				result,
 				googleCredentialsSecrets(barmanObjStore.BarmanCredentials.Google)...)
+			result = append(
+				result,
+				walEncryptionSecrets(barmanObjStore.Wal)...)
 			if barmanObjStore.EndpointCA != nil {
 				result = append(result, barmanObjStore.EndpointCA.Name)
 			}
@@ -238,6 +383,33 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 		result = append(
 			result,
 			googleCredentialsSecrets(cluster.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google)...)
+		result = append(
+			result,
+			walEncryptionSecrets(cluster.Spec.Backup.BarmanObjectStore.Wal)...)
+
+		for _, objectStore := range cluster.Spec.Backup.AdditionalBarmanObjectStores {
+			result = append(
+				result,
+				s3CredentialsSecrets(objectStore.BarmanCredentials.AWS)...)
+			result = append(
+				result,
+				azureCredentialsSecrets(objectStore.BarmanCredentials.Azure)...)
+			result = append(
+				result,
+				googleCredentialsSecrets(objectStore.BarmanCredentials.Google)...)
+			result = append(
+				result,
+				walEncryptionSecrets(objectStore.Wal)...)
+		}
+	}
+
+	// Secrets containing the headers of the backup hooks
+	if hooks := cluster.Spec.Backup.GetHooks(); hooks != nil {
+		for _, hook := range append(hooks.GetPre(), hooks.GetPost()...) {
+			if hook.HTTP != nil && hook.HTTP.HeadersSecret != nil {
+				result = append(result, hook.HTTP.HeadersSecret.Name)
+			}
+		}
 	}
 
 	// Secrets needed by Barman, if set
@@ -257,6 +429,9 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 		result = append(
 			result,
 			googleCredentialsSecrets(backupOrigin.Status.BarmanCredentials.Google)...)
+		if backupOrigin.Status.WALClientSideEncryption != nil {
+			result = append(result, backupOrigin.Status.WALClientSideEncryption.KeysSecret.Name)
+		}
 	}
 
 	return result
@@ -328,6 +503,14 @@ func googleCredentialsSecrets(googleCredentials *apiv1.GoogleCredentials) []stri
 	return secrets
 }
 
+func walEncryptionSecrets(walConfiguration *apiv1.WalBackupConfiguration) []string {
+	if walConfiguration == nil || walConfiguration.ClientSideEncryption == nil {
+		return nil
+	}
+
+	return []string{walConfiguration.ClientSideEncryption.KeysSecret.Name}
+}
+
 func managedRolesSecrets(cluster apiv1.Cluster) []string {
 	if cluster.Spec.Managed == nil {
 		return nil
@@ -349,3 +532,21 @@ func managedRolesSecrets(cluster apiv1.Cluster) []string {
 
 	return secretNames
 }
+
+// poolerAuthQuerySecrets gets the secrets containing the passwords of
+// the auth query users provisioned by the instance manager
+func poolerAuthQuerySecrets(cluster apiv1.Cluster) []string {
+	if cluster.Status.PoolerIntegrations == nil {
+		return nil
+	}
+
+	authQueryUsers := cluster.Status.PoolerIntegrations.PgBouncerIntegration.AuthQueryUsers
+	secretNames := make([]string, 0, len(authQueryUsers))
+	for _, user := range authQueryUsers {
+		if user.PasswordSecret != nil && user.PasswordSecret.Name != "" {
+			secretNames = append(secretNames, user.PasswordSecret.Name)
+		}
+	}
+
+	return secretNames
+}
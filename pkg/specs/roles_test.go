@@ -165,7 +165,7 @@ var _ = Describe("Roles", func() {
 		serviceAccount := CreateRole(cluster, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules).To(HaveLen(7))
+		Expect(serviceAccount.Rules).To(HaveLen(16))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
@@ -195,6 +195,44 @@ var _ = Describe("Roles", func() {
 			"testPassword",
 		))
 	})
+
+	It("should not contain the secrets external clusters take from another namespace", func() {
+		externalCluster := cluster.DeepCopy()
+		externalCluster.Spec.ExternalClusters[0].SecretsNamespace = "production"
+		Expect(externalClusterSecrets(*externalCluster)).To(BeEmpty())
+	})
+
+	It("should contain the ConfigMaps of the lifecycle hooks", func() {
+		hooksCluster := cluster.DeepCopy()
+		hooksCluster.Spec.LifecycleHooks = &apiv1.LifecycleHooksConfiguration{
+			PreStart: []apiv1.LifecycleHook{
+				{
+					Name: "mount",
+					Script: &apiv1.ConfigMapKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: "testHooksScripts"},
+						Key:                  "mount.sh",
+					},
+				},
+			},
+			PostPromotion: []apiv1.LifecycleHook{
+				{
+					Name: "notify",
+					SQL: &apiv1.ConfigMapKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: "testHooksSQL"},
+						Key:                  "notify.sql",
+					},
+				},
+			},
+		}
+
+		serviceAccount := CreateRole(*hooksCluster, nil)
+		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf(
+			"thisTest",
+			"testConfigMapKeySelector",
+			"testHooksScripts",
+			"testHooksSQL",
+		))
+	})
 })
 
 var _ = Describe("Secrets", func() {
@@ -277,6 +315,83 @@ var _ = Describe("Secrets", func() {
 		Expect(secrets).To(ConsistOf("test-secret", "test-access", "test-region", "test-session", "test-endpoint-ca-name"))
 	})
 
+	It("includes the secrets of the additional object stores", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						Google: &apiv1.GoogleCredentials{
+							ApplicationCredentials: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "google-secret"},
+							},
+						},
+					},
+				},
+				AdditionalBarmanObjectStores: []apiv1.NamedBarmanObjectStoreConfiguration{
+					{
+						Name: "dr",
+						BarmanObjectStoreConfiguration: apiv1.BarmanObjectStoreConfiguration{
+							BarmanCredentials: apiv1.BarmanCredentials{
+								Azure: &apiv1.AzureCredentials{
+									StorageKey: &apiv1.SecretKeySelector{
+										LocalObjectReference: apiv1.LocalObjectReference{Name: "azure-dr-secret"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(backupSecrets(cluster, nil)).To(ConsistOf("google-secret", "azure-dr-secret"))
+	})
+
+	It("should contain the secrets with the keys encrypting the WAL files", func() {
+		cluster.Spec.Backup = &apiv1.BackupConfiguration{
+			BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+				BarmanCredentials: apiv1.BarmanCredentials{
+					AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+				},
+				Wal: &apiv1.WalBackupConfiguration{
+					ClientSideEncryption: &apiv1.WALClientSideEncryption{
+						KeysSecret: apiv1.LocalObjectReference{Name: "wal-keys"},
+						ActiveKey:  "current",
+					},
+				},
+			},
+		}
+		backupOrigin := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				WALClientSideEncryption: &apiv1.WALClientSideEncryption{
+					KeysSecret: apiv1.LocalObjectReference{Name: "origin-wal-keys"},
+					ActiveKey:  "current",
+				},
+			},
+		}
+		Expect(backupSecrets(cluster, backupOrigin)).To(ConsistOf("wal-keys", "origin-wal-keys"))
+	})
+
+	It("should contain the secrets with the headers of the backup hooks", func() {
+		cluster.Spec.Backup = &apiv1.BackupConfiguration{
+			Hooks: &apiv1.BackupHooks{
+				Pre: []apiv1.BackupHook{
+					{
+						Name: "quiesce",
+						HTTP: &apiv1.BackupHookHTTPAction{
+							URL:           "http://app/quiesce",
+							HeadersSecret: &apiv1.LocalObjectReference{Name: "app-hooks"},
+						},
+					},
+					{Name: "flush", SQL: "CHECKPOINT"},
+				},
+				Post: []apiv1.BackupHook{
+					{Name: "resume", HTTP: &apiv1.BackupHookHTTPAction{URL: "http://app/resume"}},
+				},
+			},
+		}
+		Expect(backupSecrets(cluster, nil)).To(ConsistOf("app-hooks"))
+	})
+
 	It("should contain default secrets only", func() {
 		Expect(getInvolvedSecretNames(cluster, nil)).To(Equal([]string{
 			"thisTest-app",
@@ -355,3 +470,32 @@ var _ = Describe("Managed Roles", func() {
 		Expect(secretsPolicy.ResourceNames).To(ContainElements("my_secret1", "my_secret3"))
 	})
 })
+
+var _ = Describe("Pooler auth query users", func() {
+	It("gets the list of secrets containing their passwords", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "thisTest",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				PoolerIntegrations: &apiv1.PoolerIntegrations{
+					PgBouncerIntegration: apiv1.PgBouncerIntegrationStatus{
+						AuthQueryUsers: []apiv1.PgBouncerAuthQueryUser{
+							{
+								Name:           "pgbouncer_auth",
+								PasswordSecret: &apiv1.SecretVersion{Name: "pgbouncer-auth", Version: "1"},
+							},
+							{
+								Name: "pgbouncer_cert",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(poolerAuthQuerySecrets(cluster)).To(ConsistOf("pgbouncer-auth"))
+		Expect(getInvolvedSecretNames(cluster, nil)).To(ContainElement("pgbouncer-auth"))
+	})
+})
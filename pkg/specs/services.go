@@ -23,6 +23,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/servicespec"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -37,7 +38,36 @@ func buildInstanceServicePorts() []corev1.ServicePort {
 	}
 }
 
-// CreateClusterAnyService create a service insisting on all the pods
+// buildServiceSelector builds the selector of the services pointing
+// to the instances of the given type
+func buildServiceSelector(cluster apiv1.Cluster, selectorType apiv1.ServiceSelectorType) map[string]string {
+	switch selectorType {
+	case apiv1.ServiceSelectorTypeRW:
+		return map[string]string{
+			utils.ClusterLabelName:     cluster.Name,
+			utils.ClusterRoleLabelName: ClusterRoleLabelPrimary,
+		}
+	case apiv1.ServiceSelectorTypeRO:
+		selector := map[string]string{
+			utils.ClusterLabelName: cluster.Name,
+			// TODO: eventually migrate to the new label
+			utils.ClusterRoleLabelName: ClusterRoleLabelReplica,
+		}
+		if _, enabled := cluster.GetReadOnlyMaxLagBytes(); enabled {
+			selector[utils.ReadOnlyTrafficLabelName] = ReadOnlyTrafficEnabled
+		}
+		return selector
+	default:
+		return map[string]string{
+			utils.ClusterLabelName: cluster.Name,
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		}
+	}
+}
+
+// CreateClusterAnyService create a headless service insisting on all the
+// pods, used by the clusters publishing their multi-host connection strings
+// as the DNS subdomain of the instances
 func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -46,6 +76,7 @@ func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 		},
 		Spec: corev1.ServiceSpec{
 			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
 			PublishNotReadyAddresses: true,
 			Ports:                    buildInstanceServicePorts(),
 			Selector: map[string]string{
@@ -64,12 +95,9 @@ func CreateClusterReadService(cluster apiv1.Cluster) *corev1.Service {
 			Namespace: cluster.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:  corev1.ServiceTypeClusterIP,
-			Ports: buildInstanceServicePorts(),
-			Selector: map[string]string{
-				utils.ClusterLabelName: cluster.Name,
-				utils.PodRoleLabelName: string(utils.PodRoleInstance),
-			},
+			Type:     corev1.ServiceTypeClusterIP,
+			Ports:    buildInstanceServicePorts(),
+			Selector: buildServiceSelector(cluster, apiv1.ServiceSelectorTypeR),
 		},
 	}
 }
@@ -82,13 +110,9 @@ func CreateClusterReadOnlyService(cluster apiv1.Cluster) *corev1.Service {
 			Namespace: cluster.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:  corev1.ServiceTypeClusterIP,
-			Ports: buildInstanceServicePorts(),
-			Selector: map[string]string{
-				utils.ClusterLabelName: cluster.Name,
-				// TODO: eventually migrate to the new label
-				utils.ClusterRoleLabelName: ClusterRoleLabelReplica,
-			},
+			Type:     corev1.ServiceTypeClusterIP,
+			Ports:    buildInstanceServicePorts(),
+			Selector: buildServiceSelector(cluster, apiv1.ServiceSelectorTypeRO),
 		},
 	}
 }
@@ -101,12 +125,49 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 			Namespace: cluster.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:  corev1.ServiceTypeClusterIP,
-			Ports: buildInstanceServicePorts(),
-			Selector: map[string]string{
-				utils.ClusterLabelName:     cluster.Name,
-				utils.ClusterRoleLabelName: ClusterRoleLabelPrimary,
-			},
+			Type:     corev1.ServiceTypeClusterIP,
+			Ports:    buildInstanceServicePorts(),
+			Selector: buildServiceSelector(cluster, apiv1.ServiceSelectorTypeRW),
 		},
 	}
 }
+
+// CreateClusterManagedServices creates the additional services
+// defined by the user in the cluster
+func CreateClusterManagedServices(cluster apiv1.Cluster) []*corev1.Service {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
+	}
+
+	result := make([]*corev1.Service, 0, len(cluster.Spec.Managed.Services.Additional))
+	for _, managedService := range cluster.Spec.Managed.Services.Additional {
+		serviceTemplate := servicespec.NewFrom(managedService.ServiceTemplate.DeepCopy()).
+			WithLabel(utils.ClusterLabelName, cluster.Name).
+			WithLabel(utils.IsManagedLabelName, "true").
+			WithServiceType(corev1.ServiceTypeClusterIP, false).
+			Build()
+
+		if len(serviceTemplate.Spec.Ports) == 0 {
+			serviceTemplate.Spec.Ports = buildInstanceServicePorts()
+		}
+
+		// The user can restrict the selected instances further, but
+		// can't select instances outside the requested type
+		if serviceTemplate.Spec.Selector == nil {
+			serviceTemplate.Spec.Selector = make(map[string]string)
+		}
+		utils.MergeMap(serviceTemplate.Spec.Selector, buildServiceSelector(cluster, managedService.SelectorType))
+
+		result = append(result, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        managedService.Name,
+				Namespace:   cluster.Namespace,
+				Labels:      serviceTemplate.ObjectMeta.Labels,
+				Annotations: serviceTemplate.ObjectMeta.Annotations,
+			},
+			Spec: serviceTemplate.Spec,
+		})
+	}
+
+	return result
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -39,6 +40,7 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
 		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[utils.PodRoleLabelName]).To(Equal(string(utils.PodRoleInstance)))
+		Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
 	})
 
 	It("create a configured -r service", func() {
@@ -57,6 +59,18 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).To(Equal(ClusterRoleLabelReplica))
 	})
 
+	It("selects only the replicas receiving read-only traffic with lag-based routing", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				LagRouting: &apiv1.LagRoutingConfiguration{MaxLagBytes: 1024},
+			},
+		}
+		service := CreateClusterReadOnlyService(*cluster)
+		Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).To(Equal(ClusterRoleLabelReplica))
+		Expect(service.Spec.Selector[utils.ReadOnlyTrafficLabelName]).To(Equal(ReadOnlyTrafficEnabled))
+	})
+
 	It("create a configured -rw service", func() {
 		service := CreateClusterReadWriteService(postgresql)
 		Expect(service.Name).To(Equal("clustername-rw"))
@@ -64,4 +78,63 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
 	})
+
+	It("doesn't create managed services when none are defined", func() {
+		Expect(CreateClusterManagedServices(postgresql)).To(BeEmpty())
+	})
+
+	It("create the additional managed services", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				Additional: []apiv1.ManagedService{
+					{
+						Name:         "clustername-ro-zone-a",
+						SelectorType: apiv1.ServiceSelectorTypeRO,
+						ServiceTemplate: apiv1.ServiceTemplateSpec{
+							ObjectMeta: apiv1.Metadata{
+								Labels: map[string]string{"zone": "a"},
+							},
+							Spec: corev1.ServiceSpec{
+								Type: corev1.ServiceTypeLoadBalancer,
+								Selector: map[string]string{
+									"zone": "a",
+									// The operator must override this one
+									utils.ClusterRoleLabelName: ClusterRoleLabelPrimary,
+								},
+							},
+						},
+					},
+					{
+						Name:         "clustername-rw-internal",
+						SelectorType: apiv1.ServiceSelectorTypeRW,
+					},
+				},
+			},
+		}
+
+		services := CreateClusterManagedServices(*cluster)
+		Expect(services).To(HaveLen(2))
+
+		Expect(services[0].Name).To(Equal("clustername-ro-zone-a"))
+		Expect(services[0].Labels).To(HaveKeyWithValue("zone", "a"))
+		Expect(services[0].Labels).To(HaveKeyWithValue(utils.IsManagedLabelName, "true"))
+		Expect(services[0].Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(services[0].Spec.Ports).To(Equal(buildInstanceServicePorts()))
+		Expect(services[0].Spec.Selector).To(Equal(map[string]string{
+			"zone":                     "a",
+			utils.ClusterLabelName:     "clustername",
+			utils.ClusterRoleLabelName: ClusterRoleLabelReplica,
+		}))
+
+		Expect(services[1].Name).To(Equal("clustername-rw-internal"))
+		Expect(services[1].Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(services[1].Spec.Selector).To(Equal(map[string]string{
+			utils.ClusterLabelName:     "clustername",
+			utils.ClusterRoleLabelName: ClusterRoleLabelPrimary,
+		}))
+
+		// The cluster definition must not be changed
+		Expect(cluster.Spec.Managed.Services.Additional[1].ServiceTemplate.Spec.Selector).To(BeNil())
+	})
 })
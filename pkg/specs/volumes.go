@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -110,7 +111,7 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 		},
 	}
 
-	if cluster.GetEnableSuperuserAccess() {
+	if cluster.IsSuperuserSecretInUse() {
 		result = append(result,
 			corev1.Volume{
 				Name: "superuser-secret",
@@ -123,7 +124,7 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 		)
 	}
 
-	if cluster.ShouldCreateApplicationDatabase() {
+	if cluster.IsApplicationSecretInUse() {
 		result = append(result,
 			corev1.Volume{
 				Name: "app-secret",
@@ -136,6 +137,44 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 		)
 	}
 
+	if gssapi := cluster.Spec.PostgresConfiguration.GSSAPI; gssapi != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: apiv1.GSSAPIKeytabVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: gssapi.Keytab.Name,
+						Items: []corev1.KeyToPath{
+							{
+								Key:  gssapi.Keytab.Key,
+								Path: apiv1.GSSAPIKeytabFileName,
+							},
+						},
+						// The keytab is read by the postgres group
+						DefaultMode: ptr.To[int32](0o640),
+					},
+				},
+			},
+		)
+	}
+
+	if secretsStore := cluster.Spec.SecretsStore; secretsStore != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: apiv1.SecretsStoreVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					CSI: &corev1.CSIVolumeSource{
+						Driver:   apiv1.SecretsStoreCSIDriver,
+						ReadOnly: ptr.To(true),
+						VolumeAttributes: map[string]string{
+							"secretProviderClass": secretsStore.SecretProviderClass,
+						},
+					},
+				},
+			},
+		)
+	}
+
 	if cluster.ShouldCreateWalArchiveVolume() {
 		result = append(result,
 			corev1.Volume{
@@ -170,6 +209,10 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 	if cluster.ShouldCreateProjectedVolume() {
 		result = append(result, createProjectedVolume(cluster))
 	}
+
+	if cluster.IsLogFilesEnabled() {
+		result = append(result, createLogFilesVolume(cluster))
+	}
 	return result
 }
 
@@ -248,13 +291,20 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 			Name:      "scratch-data",
 			MountPath: postgres.ScratchDataDirectory,
 		},
+		{
+			// with a read-only root filesystem, this is where the tools
+			// started by the instance manager write their temporary files
+			Name:      "scratch-data",
+			MountPath: "/tmp",
+			SubPath:   "tmp",
+		},
 		{
 			Name:      "shm",
 			MountPath: "/dev/shm",
 		},
 	}
 
-	if cluster.GetEnableSuperuserAccess() {
+	if cluster.IsSuperuserSecretInUse() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
 				Name:      "superuser-secret",
@@ -263,7 +313,7 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
-	if cluster.ShouldCreateApplicationDatabase() {
+	if cluster.IsApplicationSecretInUse() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
 				Name:      "app-secret",
@@ -272,6 +322,26 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if cluster.Spec.PostgresConfiguration.GSSAPI != nil {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      apiv1.GSSAPIKeytabVolumeName,
+				MountPath: apiv1.GSSAPIKeytabMountPath,
+				ReadOnly:  true,
+			},
+		)
+	}
+
+	if cluster.Spec.SecretsStore != nil {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      apiv1.SecretsStoreVolumeName,
+				MountPath: apiv1.SecretsStoreMountPath,
+				ReadOnly:  true,
+			},
+		)
+	}
+
 	if cluster.ShouldCreateWalArchiveVolume() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
@@ -290,6 +360,15 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if cluster.IsLogFilesEnabled() {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "log-files",
+				MountPath: postgres.LogFilesPath,
+			},
+		)
+	}
+
 	// we should create volumeMounts in fixed sequence as podSpec will store it in annotation and
 	// later it will be  retrieved to do deepEquals
 	if cluster.ContainsTablespaces() {
@@ -333,6 +412,21 @@ func createEphemeralVolume(cluster *apiv1.Cluster) corev1.Volume {
 	}
 }
 
+func createLogFilesVolume(cluster *apiv1.Cluster) corev1.Volume {
+	logFilesVolumeSource := corev1.VolumeSource{}
+	if cluster.Spec.LogFiles.EphemeralVolumeSource != nil {
+		logFilesVolumeSource.Ephemeral = cluster.Spec.LogFiles.EphemeralVolumeSource
+	} else {
+		logFilesVolumeSource.EmptyDir = &corev1.EmptyDirVolumeSource{
+			SizeLimit: cluster.Spec.LogFiles.SizeLimit,
+		}
+	}
+	return corev1.Volume{
+		Name:         "log-files",
+		VolumeSource: logFilesVolumeSource,
+	}
+}
+
 func createProjectedVolume(cluster *apiv1.Cluster) corev1.Volume {
 	return corev1.Volume{
 		Name: "projected",
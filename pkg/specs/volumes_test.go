@@ -22,6 +22,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -518,3 +519,175 @@ var _ = Describe("createEphemeralVolume", func() {
 		Expect(*ephemeralVolume.VolumeSource.EmptyDir.SizeLimit).To(Equal(quantity))
 	})
 })
+
+var _ = Describe("GSSAPI keytab volume", func() {
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				GSSAPI: &apiv1.GSSAPIConfiguration{
+					Keytab: apiv1.SecretKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: "pg-keytab"},
+						Key:                  "postgres.keytab",
+					},
+				},
+			},
+		},
+	}
+
+	It("projects the keytab from the secret", func() {
+		volumes := createPostgresVolumes(&cluster, "pod-1")
+		var keytab *corev1.Volume
+		for i := range volumes {
+			if volumes[i].Name == apiv1.GSSAPIKeytabVolumeName {
+				keytab = &volumes[i]
+			}
+		}
+		Expect(keytab).ToNot(BeNil())
+		Expect(keytab.Secret).ToNot(BeNil())
+		Expect(keytab.Secret.SecretName).To(Equal("pg-keytab"))
+		Expect(keytab.Secret.Items).To(ConsistOf(corev1.KeyToPath{
+			Key:  "postgres.keytab",
+			Path: apiv1.GSSAPIKeytabFileName,
+		}))
+	})
+
+	It("mounts the keytab read-only", func() {
+		Expect(createPostgresVolumeMounts(cluster)).To(ContainElement(corev1.VolumeMount{
+			Name:      apiv1.GSSAPIKeytabVolumeName,
+			MountPath: apiv1.GSSAPIKeytabMountPath,
+			ReadOnly:  true,
+		}))
+	})
+
+	It("does not add the volume when GSSAPI is not configured", func() {
+		volumes := createPostgresVolumes(&apiv1.Cluster{}, "pod-1")
+		for _, volume := range volumes {
+			Expect(volume.Name).ToNot(Equal(apiv1.GSSAPIKeytabVolumeName))
+		}
+	})
+})
+
+var _ = Describe("Log files volume", func() {
+	It("mounts an emptyDir volume with the configured size limit", func() {
+		quantity := resource.MustParse("1Gi")
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				LogFiles: &apiv1.LogFilesConfiguration{Enabled: true, SizeLimit: &quantity},
+			},
+		}
+
+		Expect(createPostgresVolumes(&cluster, "pod-1")).To(ContainElement(corev1.Volume{
+			Name: "log-files",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &quantity},
+			},
+		}))
+		Expect(createPostgresVolumeMounts(cluster)).To(ContainElement(corev1.VolumeMount{
+			Name:      "log-files",
+			MountPath: postgres.LogFilesPath,
+		}))
+	})
+
+	It("uses the ephemeral volume source when specified", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				LogFiles: &apiv1.LogFilesConfiguration{
+					Enabled:               true,
+					EphemeralVolumeSource: &corev1.EphemeralVolumeSource{},
+				},
+			},
+		}
+
+		volume := createLogFilesVolume(&cluster)
+		Expect(volume.EmptyDir).To(BeNil())
+		Expect(volume.Ephemeral).ToNot(BeNil())
+	})
+
+	It("does not add the volume when the log files are disabled", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				LogFiles: &apiv1.LogFilesConfiguration{Enabled: false},
+			},
+		}
+		for _, volume := range createPostgresVolumes(&cluster, "pod-1") {
+			Expect(volume.Name).ToNot(Equal("log-files"))
+		}
+	})
+})
+
+var _ = Describe("Secrets Store volume", func() {
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			SecretsStore: &apiv1.SecretsStoreConfiguration{
+				SecretProviderClass:   "vault-passwords",
+				SuperuserPasswordFile: "superuser-password",
+			},
+		},
+	}
+
+	It("mounts the objects of the SecretProviderClass", func() {
+		volumes := createPostgresVolumes(&cluster, "pod-1")
+		var secretsStore *corev1.Volume
+		for i := range volumes {
+			if volumes[i].Name == apiv1.SecretsStoreVolumeName {
+				secretsStore = &volumes[i]
+			}
+		}
+		Expect(secretsStore).ToNot(BeNil())
+		Expect(secretsStore.CSI).ToNot(BeNil())
+		Expect(secretsStore.CSI.Driver).To(Equal(apiv1.SecretsStoreCSIDriver))
+		Expect(secretsStore.CSI.VolumeAttributes).To(HaveKeyWithValue("secretProviderClass", "vault-passwords"))
+	})
+
+	It("mounts the volume read-only", func() {
+		Expect(createPostgresVolumeMounts(cluster)).To(ContainElement(corev1.VolumeMount{
+			Name:      apiv1.SecretsStoreVolumeName,
+			MountPath: apiv1.SecretsStoreMountPath,
+			ReadOnly:  true,
+		}))
+	})
+
+	It("doesn't mount the secrets of the passwords read from the Secrets Store", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				EnableSuperuserAccess: ptr.To(true),
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				SecretsStore: &apiv1.SecretsStoreConfiguration{
+					SecretProviderClass:   "vault-passwords",
+					SuperuserPasswordFile: "superuser-password",
+				},
+			},
+		}
+		volumeNames := func() []string {
+			var names []string
+			for _, volume := range createPostgresVolumes(&cluster, "pod-1") {
+				names = append(names, volume.Name)
+			}
+			return names
+		}
+		Expect(volumeNames()).ToNot(ContainElement("superuser-secret"))
+		Expect(volumeNames()).To(ContainElement("app-secret"))
+
+		cluster.Spec.SecretsStore.ApplicationPasswordFile = "app-password"
+		Expect(volumeNames()).ToNot(ContainElement("app-secret"))
+	})
+
+	It("does not add the volume when the Secrets Store is not configured", func() {
+		volumes := createPostgresVolumes(&apiv1.Cluster{}, "pod-1")
+		for _, volume := range volumes {
+			Expect(volume.Name).ToNot(Equal(apiv1.SecretsStoreVolumeName))
+		}
+	})
+})
+
+var _ = Describe("Temporary files volume", func() {
+	It("mounts the scratch volume on /tmp, for the root filesystem to be read-only", func() {
+		Expect(createPostgresVolumeMounts(apiv1.Cluster{})).To(ContainElement(corev1.VolumeMount{
+			Name:      "scratch-data",
+			MountPath: "/tmp",
+			SubPath:   "tmp",
+		}))
+	})
+})
@@ -108,6 +108,29 @@ var _ = Describe("Detect resources properly when", func() {
 		Expect(exists).To(BeTrue())
 	})
 
+	It("should not detect the cert-manager Certificate resource", func() {
+		exists, err := CertManagerExist(client.Discovery())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should detect the cert-manager Certificate resource", func() {
+		resources := []*metav1.APIResourceList{
+			{
+				GroupVersion: "cert-manager.io/v1",
+				APIResources: []metav1.APIResource{
+					{
+						Name: "certificates",
+					},
+				},
+			},
+		}
+		fakeDiscovery.Resources = resources
+		exists, err := CertManagerExist(client.Discovery())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
 	It("should not detect SecurityContextConstraints", func() {
 		err := DetectSecurityContextConstraints(client.Discovery())
 		Expect(err).ToNot(HaveOccurred())
@@ -142,6 +142,10 @@ type FencingMetadataExecutor struct {
 	fenceFunc    func(string, metav1.Object) (appliedChange bool, err error)
 	instanceName string
 	cli          client.Client
+
+	// expandWildcard is true when the wildcard, if present, needs to be
+	// replaced by the list of the instances before applying the change
+	expandWildcard bool
 }
 
 // NewFencingMetadataExecutor creates a fluent client for FencingMetadataExecutor
@@ -154,12 +158,14 @@ func NewFencingMetadataExecutor(cli client.Client) *FencingMetadataExecutor {
 // AddFencing instructs the client to execute the logic of adding a instance
 func (fb *FencingMetadataExecutor) AddFencing() *FencingMetadataExecutor {
 	fb.fenceFunc = AddFencedInstance
+	fb.expandWildcard = false
 	return fb
 }
 
 // RemoveFencing instructs the client to execute the logic of removing an instance
 func (fb *FencingMetadataExecutor) RemoveFencing() *FencingMetadataExecutor {
 	fb.fenceFunc = removeFencedInstance
+	fb.expandWildcard = true
 	return fb
 }
 
@@ -193,6 +199,18 @@ func (fb *FencingMetadataExecutor) Execute(ctx context.Context, key types.Namesp
 	}
 
 	fencedObject := obj.DeepCopyObject().(client.Object)
+	if fb.expandWildcard && fb.instanceName != FenceAllInstances {
+		// Lifting the fence from a single instance of a fenced cluster
+		// requires every other instance to be explicitly fenced
+		instanceNames, err := fb.getInstanceNames(ctx, key)
+		if err != nil {
+			return err
+		}
+		if err := expandFencedInstancesWildcard(fencedObject, instanceNames); err != nil {
+			return err
+		}
+	}
+
 	appliedChange, err := fb.fenceFunc(fb.instanceName, fencedObject)
 	if err != nil {
 		return err
@@ -203,3 +221,60 @@ func (fb *FencingMetadataExecutor) Execute(ctx context.Context, key types.Namesp
 
 	return fb.cli.Patch(ctx, fencedObject, client.MergeFrom(obj))
 }
+
+// getInstanceNames gets the names of the instances of a cluster. They are
+// derived from the PGDATA PVCs, so that an instance whose Pod is missing,
+// i.e. because it is being recreated, is not left out
+func (fb *FencingMetadataExecutor) getInstanceNames(ctx context.Context, key types.NamespacedName) ([]string, error) {
+	instanceNames := stringset.New()
+
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := fb.cli.List(
+		ctx,
+		&pvcList,
+		client.InNamespace(key.Namespace),
+		client.MatchingLabels{
+			ClusterLabelName: key.Name,
+			PvcRoleLabelName: string(PVCRolePgData),
+		},
+	); err != nil {
+		return nil, err
+	}
+	for _, pvc := range pvcList.Items {
+		if instanceName := pvc.Labels[InstanceNameLabelName]; instanceName != "" {
+			instanceNames.Put(instanceName)
+		}
+	}
+
+	var podList corev1.PodList
+	if err := fb.cli.List(
+		ctx,
+		&podList,
+		client.InNamespace(key.Namespace),
+		client.MatchingLabels{
+			ClusterLabelName: key.Name,
+			PodRoleLabelName: string(PodRoleInstance),
+		},
+	); err != nil {
+		return nil, err
+	}
+	for _, pod := range podList.Items {
+		instanceNames.Put(pod.Name)
+	}
+
+	return instanceNames.ToList(), nil
+}
+
+// expandFencedInstancesWildcard replaces the wildcard in the fenced instances
+// annotation, if present, with the passed list of instance names
+func expandFencedInstancesWildcard(object metav1.Object, instanceNames []string) error {
+	fencedInstances, err := GetFencedInstances(object.GetAnnotations())
+	if err != nil {
+		return err
+	}
+	if !fencedInstances.Has(FenceAllInstances) {
+		return nil
+	}
+
+	return setFencedInstances(object, stringset.From(instanceNames))
+}
@@ -17,9 +17,13 @@ limitations under the License.
 package utils
 
 import (
+	"context"
 	"encoding/json"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -118,4 +122,68 @@ var _ = Describe("Fencing annotation handling", func() {
 				To(HaveKeyWithValue(FencedInstanceAnnotation, jsonMarshal("cluster-example-1")))
 		})
 	})
+	When("The whole cluster is fenced", func() {
+		It("should expand the wildcard into the list of instances", func() {
+			clusterMeta := metav1.ObjectMeta{
+				Annotations: map[string]string{
+					FencedInstanceAnnotation: jsonMarshal(FenceAllInstances),
+				},
+			}
+			Expect(expandFencedInstancesWildcard(&clusterMeta,
+				[]string{"cluster-example-2", "cluster-example-1"})).To(Succeed())
+			Expect(clusterMeta.Annotations).
+				To(HaveKeyWithValue(FencedInstanceAnnotation, jsonMarshal("cluster-example-1", "cluster-example-2")))
+		})
+
+		It("should allow lifting the fence from a single instance", func(ctx context.Context) {
+			instancePod := func(name string) *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels: map[string]string{
+							ClusterLabelName: "cluster-example",
+							PodRoleLabelName: string(PodRoleInstance),
+						},
+					},
+				}
+			}
+			fencedObject := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster-example",
+					Namespace: "default",
+					Annotations: map[string]string{
+						FencedInstanceAnnotation: jsonMarshal(FenceAllInstances),
+					},
+				},
+			}
+			// The Pod of the third instance is being recreated
+			instancePVC := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster-example-3",
+					Namespace: "default",
+					Labels: map[string]string{
+						ClusterLabelName:      "cluster-example",
+						InstanceNameLabelName: "cluster-example-3",
+						PvcRoleLabelName:      string(PVCRolePgData),
+					},
+				},
+			}
+			cli := fake.NewClientBuilder().
+				WithObjects(instancePod("cluster-example-1"), instancePod("cluster-example-2"),
+					instancePVC, fencedObject).
+				Build()
+
+			key := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+			Expect(NewFencingMetadataExecutor(cli).
+				RemoveFencing().
+				ForInstance("cluster-example-1").
+				Execute(ctx, key, &corev1.ConfigMap{})).To(Succeed())
+
+			var result corev1.ConfigMap
+			Expect(cli.Get(ctx, key, &result)).To(Succeed())
+			Expect(result.Annotations).
+				To(HaveKeyWithValue(FencedInstanceAnnotation, jsonMarshal("cluster-example-2", "cluster-example-3")))
+		})
+	})
 })
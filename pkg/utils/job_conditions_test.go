@@ -18,6 +18,7 @@ package utils
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -47,4 +48,20 @@ var _ = Describe("Job conditions", func() {
 		Expect(CountJobsWithOneCompletion([]batchv1.Job{completeJob})).To(Equal(1))
 		Expect(CountJobsWithOneCompletion([]batchv1.Job{})).To(Equal(0))
 	})
+
+	It("detects if a certain job has failed", func() {
+		failedJob := batchv1.Job{
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:   batchv1.JobFailed,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		}
+		Expect(JobHasFailed(failedJob)).To(BeTrue())
+		Expect(JobHasFailed(nonCompleteJob)).To(BeFalse())
+		Expect(JobHasFailed(completeJob)).To(BeFalse())
+	})
 })
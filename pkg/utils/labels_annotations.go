@@ -17,7 +17,6 @@ limitations under the License.
 package utils
 
 import (
-	"reflect"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,6 +33,14 @@ const (
 	// ClusterLabelName is the name of the label cluster which the backup CR belongs to
 	ClusterLabelName = MetadataNamespace + "/cluster"
 
+	// ClusterNamespaceLabelName is the name of the label containing the
+	// namespace of the cluster an object living in another namespace belongs to
+	ClusterNamespaceLabelName = MetadataNamespace + "/clusterNamespace"
+
+	// ClientCertificateRoleLabelName is the name of the label containing the
+	// managed role whose client certificate is stored in a secret
+	ClientCertificateRoleLabelName = MetadataNamespace + "/clientCertificateRole"
+
 	// JobRoleLabelName is the name of the label containing the purpose of the executed job
 	// the value could be import, initdb, join
 	JobRoleLabelName = MetadataNamespace + "/jobRole"
@@ -53,6 +60,14 @@ const (
 	// BackupNameLabelName is the name of the label containing the backup id, available on backup resources
 	BackupNameLabelName = MetadataNamespace + "/backupName"
 
+	// BenchmarkNameLabelName is the name of the label containing the benchmark name,
+	// available on the resources created to run a benchmark
+	BenchmarkNameLabelName = MetadataNamespace + "/benchmarkName"
+
+	// ChangeDataCaptureNameLabelName is the name of the label containing the name
+	// of the ChangeDataCapture, available on the resources of its bridge
+	ChangeDataCaptureNameLabelName = MetadataNamespace + "/changeDataCaptureName"
+
 	// PgbouncerNameLabel is the name of the label of containing the pooler name
 	PgbouncerNameLabel = MetadataNamespace + "/poolerName"
 
@@ -87,8 +102,17 @@ const (
 	// BackupDateLabelName is the name of the label where the date of a backup in 'YYYYMMDD' format is kept
 	BackupDateLabelName = MetadataNamespace + "/backupDate"
 
+	// IsManagedLabelName is the name of the label added to the additional
+	// services managed by a cluster
+	IsManagedLabelName = MetadataNamespace + "/isManaged"
+
 	// IsOnlineBackupLabelName is the name of the label used to specify whether a backup was online
 	IsOnlineBackupLabelName = MetadataNamespace + "/onlineBackup"
+
+	// ReadOnlyTrafficLabelName is the name of the label telling whether a
+	// replica is close enough to the primary to receive read-only traffic,
+	// used when lag-based routing is enabled
+	ReadOnlyTrafficLabelName = MetadataNamespace + "/readOnlyTraffic"
 )
 
 const (
@@ -158,6 +182,15 @@ const (
 	// PostgreSQL cluster
 	HibernationAnnotationName = MetadataNamespace + "/hibernation"
 
+	// AbandonInPlaceRestoreAnnotationName is the name of the annotation which,
+	// when set on a cluster whose in-place restore failed, promotes one of the
+	// replicas untouched by the restore and clones the primary again from it
+	AbandonInPlaceRestoreAnnotationName = MetadataNamespace + "/abandonInPlaceRestore"
+
+	// UnsafeParametersAnnotationName is the name of the annotation containing the
+	// comma-separated list of PostgreSQL parameters whose unsafe values are accepted
+	UnsafeParametersAnnotationName = MetadataNamespace + "/unsafeParameters"
+
 	// PoolerSpecHashAnnotationName is the name of the annotation added to the deployment to tell
 	// the hash of the Pooler Specification
 	PoolerSpecHashAnnotationName = MetadataNamespace + "/poolerSpecHash"
@@ -199,6 +232,17 @@ const (
 	// SnapshotEndTimeAnnotationName is the name of the annotation where a snapshot's end time is kept
 	SnapshotEndTimeAnnotationName = MetadataNamespace + "/snapshotEndTime"
 
+	// OrphanedSinceAnnotationName is the name of the annotation containing
+	// the time when a resource has been detected as orphaned by the
+	// garbage collection
+	OrphanedSinceAnnotationName = MetadataNamespace + "/orphanedSince"
+
+	// PostgresRestartsBaselineAnnotationName is the name of the annotation
+	// containing the number of restarts of the PostgreSQL container when
+	// the pod was last seen ready, from which the restarts are counted
+	// to detect a damaged replica
+	PostgresRestartsBaselineAnnotationName = MetadataNamespace + "/postgresRestartsBaseline"
+
 	// ClusterRestartAnnotationName is the name of the annotation containing the
 	// latest required restart time
 	ClusterRestartAnnotationName = "kubectl.kubernetes.io/restartedAt"
@@ -359,15 +403,20 @@ func IsAnnotationAppArmorPresent(spec *corev1.PodSpec, annotations map[string]st
 	return len(annotation) != 0
 }
 
-// IsAnnotationAppArmorPresentInObject checks if the AppArmor annotations are present or not in the given Object
+// IsAnnotationAppArmorPresentInObject checks if the AppArmor annotations are present or not in the given Object.
+// The object may have further AppArmor annotations, like the ones derived from the AppArmor profile of the cluster
 func IsAnnotationAppArmorPresentInObject(
 	object *metav1.ObjectMeta,
 	spec *corev1.PodSpec,
 	annotations map[string]string,
 ) bool {
 	objAnnotations := getAnnotationAppArmor(spec, object.Annotations)
-	appArmorAnnotations := getAnnotationAppArmor(spec, annotations)
-	return reflect.DeepEqual(objAnnotations, appArmorAnnotations)
+	for annotation, value := range getAnnotationAppArmor(spec, annotations) {
+		if objValue, ok := objAnnotations[annotation]; !ok || objValue != value {
+			return false
+		}
+	}
+	return true
 }
 
 // AnnotateAppArmor adds an annotation to the pod
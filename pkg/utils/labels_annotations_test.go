@@ -155,4 +155,21 @@ var _ = Describe("Annotate pods management", func() {
 		_, isPresent := pod.ObjectMeta.Annotations[appArmorPostgres]
 		Expect(isPresent).To(BeFalse())
 	})
+
+	It("accepts objects having further AppArmor annotations", func() {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				appArmorPostgres: "unconfined",
+				AppArmorAnnotationPrefix + "/bootstrap-controller": "runtime/default",
+			}},
+			Spec: corev1.PodSpec{
+				Containers:     []corev1.Container{{Name: "postgres"}},
+				InitContainers: []corev1.Container{{Name: "bootstrap-controller"}},
+			},
+		}
+		Expect(IsAnnotationAppArmorPresentInObject(&pod.ObjectMeta, &pod.Spec, annotations)).To(BeTrue())
+
+		pod.Annotations[appArmorPostgres] = "runtime/default"
+		Expect(IsAnnotationAppArmorPresentInObject(&pod.ObjectMeta, &pod.Spec, annotations)).To(BeFalse())
+	})
 })
@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ClusterAutoscalerToBeDeletedTaint is the taint applied by the cluster
+// autoscaler to the nodes it is going to remove
+const ClusterAutoscalerToBeDeletedTaint = "ToBeDeletedByClusterAutoscaler"
+
+// NodeUnavailabilityReason is the reason why the instances running on
+// a node should be moved away from it
+type NodeUnavailabilityReason string
+
+const (
+	// NodeUnschedulable means the node has been cordoned, i.e. it is being drained
+	NodeUnschedulable NodeUnavailabilityReason = "Unschedulable"
+
+	// NodeScalingDown means the cluster autoscaler is going to remove the node
+	NodeScalingDown NodeUnavailabilityReason = "ScalingDown"
+
+	// NodeOutOfService means the node has been marked as out of service
+	NodeOutOfService NodeUnavailabilityReason = "OutOfService"
+
+	// NodeNotReady means the kubelet is not reporting the node as ready
+	NodeNotReady NodeUnavailabilityReason = "NotReady"
+
+	// NodeDiskPressure means the kubelet is going to evict the pods to reclaim disk space
+	NodeDiskPressure NodeUnavailabilityReason = "DiskPressure"
+)
+
+// GetNodeUnavailabilityReason returns the reason why the instances running on
+// the passed node should be moved away from it, or an empty string if the
+// node is healthy
+func GetNodeUnavailabilityReason(node *corev1.Node) NodeUnavailabilityReason {
+	reason, _ := GetSettledNodeUnavailabilityReason(node, 0, time.Now())
+	return reason
+}
+
+// GetSettledNodeUnavailabilityReason is like GetNodeUnavailabilityReason, but
+// takes into account the conditions of the node only once they have been
+// holding for longer than the passed grace period, ignoring the transient
+// ones. While a condition is within its grace period, the time remaining
+// before it settles is returned
+func GetSettledNodeUnavailabilityReason(
+	node *corev1.Node,
+	gracePeriod time.Duration,
+	now time.Time,
+) (NodeUnavailabilityReason, time.Duration) {
+	if node.Spec.Unschedulable {
+		return NodeUnschedulable, 0
+	}
+
+	for _, taint := range node.Spec.Taints {
+		switch taint.Key {
+		case ClusterAutoscalerToBeDeletedTaint:
+			return NodeScalingDown, 0
+		case corev1.TaintNodeOutOfService:
+			return NodeOutOfService, 0
+		}
+	}
+
+	var pending time.Duration
+	for _, condition := range node.Status.Conditions {
+		var reason NodeUnavailabilityReason
+		switch {
+		case condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue:
+			reason = NodeNotReady
+		case condition.Type == corev1.NodeDiskPressure && condition.Status == corev1.ConditionTrue:
+			reason = NodeDiskPressure
+		default:
+			continue
+		}
+
+		remaining := condition.LastTransitionTime.Add(gracePeriod).Sub(now)
+		if remaining <= 0 {
+			return reason, 0
+		}
+		if pending == 0 || remaining < pending {
+			pending = remaining
+		}
+	}
+
+	return "", pending
+}
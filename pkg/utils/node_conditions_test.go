@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node conditions", func() {
+	readyCondition := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+
+	It("reports a ready node as available", func() {
+		node := &corev1.Node{
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					readyCondition,
+					{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+				},
+			},
+		}
+		Expect(GetNodeUnavailabilityReason(node)).To(BeEmpty())
+	})
+
+	It("detects a node being drained", func() {
+		node := &corev1.Node{
+			Spec:   corev1.NodeSpec{Unschedulable: true},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+		}
+		Expect(GetNodeUnavailabilityReason(node)).To(Equal(NodeUnschedulable))
+	})
+
+	It("detects a node being removed by the cluster autoscaler", func() {
+		node := &corev1.Node{
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{{Key: ClusterAutoscalerToBeDeletedTaint, Effect: corev1.TaintEffectNoSchedule}},
+			},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+		}
+		Expect(GetNodeUnavailabilityReason(node)).To(Equal(NodeScalingDown))
+	})
+
+	It("detects a node out of service", func() {
+		node := &corev1.Node{
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{{Key: corev1.TaintNodeOutOfService, Effect: corev1.TaintEffectNoExecute}},
+			},
+		}
+		Expect(GetNodeUnavailabilityReason(node)).To(Equal(NodeOutOfService))
+	})
+
+	It("detects the node conditions", func() {
+		notReadyNode := &corev1.Node{
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}},
+			},
+		}
+		Expect(GetNodeUnavailabilityReason(notReadyNode)).To(Equal(NodeNotReady))
+
+		diskPressureNode := &corev1.Node{
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					readyCondition,
+					{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+		Expect(GetNodeUnavailabilityReason(diskPressureNode)).To(Equal(NodeDiskPressure))
+	})
+
+	It("waits for the node conditions to settle", func() {
+		now := time.Now()
+		node := &corev1.Node{
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.NewTime(now.Add(-20 * time.Second)),
+				}},
+			},
+		}
+
+		reason, pending := GetSettledNodeUnavailabilityReason(node, time.Minute, now)
+		Expect(reason).To(BeEmpty())
+		Expect(pending).To(Equal(40 * time.Second))
+
+		reason, pending = GetSettledNodeUnavailabilityReason(node, time.Minute, now.Add(time.Minute))
+		Expect(reason).To(Equal(NodeNotReady))
+		Expect(pending).To(BeZero())
+
+		node.Spec.Unschedulable = true
+		reason, _ = GetSettledNodeUnavailabilityReason(node, time.Minute, now)
+		Expect(reason).To(Equal(NodeUnschedulable))
+	})
+})
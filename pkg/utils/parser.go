@@ -16,7 +16,47 @@ limitations under the License.
 
 package utils
 
-import "strings"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// PgControlDataKeyDatabaseClusterState is the pg_controldata key
+	// containing the state of the database cluster
+	PgControlDataKeyDatabaseClusterState = "Database cluster state"
+
+	// PgControlDataKeyDatabaseSystemIdentifier is the pg_controldata key
+	// containing the system identifier of the database cluster
+	PgControlDataKeyDatabaseSystemIdentifier = "Database system identifier"
+
+	// PgControlDataKeyLatestCheckpointTimelineID is the pg_controldata key
+	// containing the timeline of the latest checkpoint
+	PgControlDataKeyLatestCheckpointTimelineID = "Latest checkpoint's TimeLineID"
+
+	// PgControlDataKeyLatestCheckpointLocation is the pg_controldata key
+	// containing the location of the latest checkpoint
+	PgControlDataKeyLatestCheckpointLocation = "Latest checkpoint location"
+
+	// PgControlDataKeyREDOWALFile is the pg_controldata key containing
+	// the WAL file holding the REDO location of the latest checkpoint
+	PgControlDataKeyREDOWALFile = "Latest checkpoint's REDO WAL file"
+
+	// PgControlDataKeyDataPageChecksumVersion is the pg_controldata key
+	// containing the version of the data checksums, zero when disabled
+	PgControlDataKeyDataPageChecksumVersion = "Data page checksum version"
+
+	// PgControlDataDatabaseClusterStateShutDown is the state of a database
+	// cluster that has been cleanly shut down while being a primary
+	PgControlDataDatabaseClusterStateShutDown = "shut down"
+
+	// PgControlDataDatabaseClusterStateShutDownInRecovery is the state of a
+	// database cluster that has been cleanly shut down while being a replica
+	PgControlDataDatabaseClusterStateShutDownInRecovery = "shut down in recovery"
+)
 
 // ParsePgControldataOutput parses a pg_controldata output into a map of key-value pairs
 func ParsePgControldataOutput(data string) map[string]string {
@@ -31,3 +71,78 @@ func ParsePgControldataOutput(data string) map[string]string {
 	}
 	return pairs
 }
+
+// PgControldataTokenContent contains the information, extracted from
+// pg_controldata, needed by a replica cluster to be safely promoted after
+// the demotion of the primary cluster
+type PgControldataTokenContent struct {
+	// The system identifier of the demoted database cluster
+	DatabaseSystemIdentifier string `json:"databaseSystemIdentifier"`
+
+	// The timeline of the shutdown checkpoint
+	LatestCheckpointTimelineID string `json:"latestCheckpointTimelineID"`
+
+	// The location of the shutdown checkpoint
+	LatestCheckpointLocation string `json:"latestCheckpointLocation"`
+
+	// The WAL file containing the shutdown checkpoint
+	REDOWALFile string `json:"redoWALFile"`
+}
+
+// NewPgControldataTokenContent builds the content of a token from
+// the parsed output of pg_controldata
+func NewPgControldataTokenContent(pgControlData map[string]string) *PgControldataTokenContent {
+	return &PgControldataTokenContent{
+		DatabaseSystemIdentifier:   pgControlData[PgControlDataKeyDatabaseSystemIdentifier],
+		LatestCheckpointTimelineID: pgControlData[PgControlDataKeyLatestCheckpointTimelineID],
+		LatestCheckpointLocation:   pgControlData[PgControlDataKeyLatestCheckpointLocation],
+		REDOWALFile:                pgControlData[PgControlDataKeyREDOWALFile],
+	}
+}
+
+// IsValid checks if the token contains every required information
+func (content *PgControldataTokenContent) IsValid() error {
+	switch {
+	case content.DatabaseSystemIdentifier == "":
+		return errors.New("missing database system identifier")
+	case content.LatestCheckpointTimelineID == "":
+		return errors.New("missing latest checkpoint timeline")
+	case content.LatestCheckpointLocation == "":
+		return errors.New("missing latest checkpoint location")
+	case content.REDOWALFile == "":
+		return errors.New("missing REDO WAL file")
+	}
+
+	return nil
+}
+
+// Encode encodes the token content into a string which can be
+// transferred between clusters
+func (content *PgControldataTokenContent) Encode() (string, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// ParsePgControldataToken decodes a token created by Encode,
+// checking its validity
+func ParsePgControldataToken(token string) (*PgControldataTokenContent, error) {
+	data, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("while decoding the token: %w", err)
+	}
+
+	var content PgControldataTokenContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("while unmarshalling the token: %w", err)
+	}
+
+	if err := content.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	return &content, nil
+}
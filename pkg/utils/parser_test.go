@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const pgControldataOutput = `pg_control version number:            1300
+Catalog version number:               202307071
+Database system identifier:           7390877990563459097
+Database cluster state:               shut down
+Latest checkpoint location:           0/6000028
+Latest checkpoint's REDO location:    0/6000028
+Latest checkpoint's REDO WAL file:    000000010000000000000006
+Latest checkpoint's TimeLineID:       1
+`
+
+var _ = Describe("pg_controldata token", func() {
+	It("is built from the output of pg_controldata", func() {
+		content := NewPgControldataTokenContent(ParsePgControldataOutput(pgControldataOutput))
+		Expect(content.IsValid()).To(Succeed())
+		Expect(*content).To(Equal(PgControldataTokenContent{
+			DatabaseSystemIdentifier:   "7390877990563459097",
+			LatestCheckpointTimelineID: "1",
+			LatestCheckpointLocation:   "0/6000028",
+			REDOWALFile:                "000000010000000000000006",
+		}))
+	})
+
+	It("can be encoded and parsed back", func() {
+		content := NewPgControldataTokenContent(ParsePgControldataOutput(pgControldataOutput))
+		token, err := content.Encode()
+		Expect(err).ToNot(HaveOccurred())
+
+		parsed, err := ParsePgControldataToken(token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed).To(Equal(content))
+	})
+
+	It("rejects malformed or incomplete tokens", func() {
+		_, err := ParsePgControldataToken("not-base64!")
+		Expect(err).To(HaveOccurred())
+
+		token, err := (&PgControldataTokenContent{DatabaseSystemIdentifier: "7390877990563459097"}).Encode()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = ParsePgControldataToken(token)
+		Expect(err).To(MatchError(ContainSubstring("missing latest checkpoint timeline")))
+	})
+})